@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/concurrency"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extproc"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// startExtAuthz serves the ext_authz evaluator on cfg.ExtAuthzAddress until ctx is cancelled,
+// with the access log service that ends concurrency leases when accessLogs is not nil. It uses
+// the same TLS settings as the HTTP server.
+func startExtAuthz(ctx context.Context, log *logger.Logger, cfg *config.Config, evaluator *extauthz.Server, accessLogs *concurrency.AccessLogServer) error {
+	return startGRPC(ctx, log, cfg, "ext_authz evaluator", cfg.ExtAuthzAddress, func(srv *grpc.Server) {
+		authv3.RegisterAuthorizationServer(srv, evaluator)
+		if accessLogs != nil {
+			accesslogv3.RegisterAccessLogServiceServer(srv, accessLogs)
+		}
+	})
+}
+
+// startExtProc serves the shared-route ext_proc processor on cfg.ExtProcAddress until ctx is
+// cancelled. It uses the same TLS settings as the HTTP server.
+func startExtProc(ctx context.Context, log *logger.Logger, cfg *config.Config, processor *extproc.Server) error {
+	return startGRPC(ctx, log, cfg, "ext_proc processor", cfg.ExtProcAddress, func(srv *grpc.Server) {
+		extprocv3.RegisterExternalProcessorServer(srv, processor)
+	})
+}
+
+func startGRPC(ctx context.Context, log *logger.Logger, cfg *config.Config, name, address string, register func(*grpc.Server)) error {
+	srv, healthSrv, err := newGRPCServer(cfg, register)
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s for %s: %w", address, name, err)
+	}
+
+	go func() {
+		log.Info(name+" starting", "address", address, "secure", cfg.Secure)
+		if err := srv.Serve(lis); err != nil {
+			log.Error(name+" stopped", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		// Report NOT_SERVING first, so Envoy stops sending new checks while in-flight ones finish.
+		healthSrv.Shutdown()
+		srv.GracefulStop()
+	}()
+	return nil
+}
+
+// newGRPCServer creates a gRPC server with the services added by register and the standard
+// grpc.health.v1 service, which Envoy cluster health checks and Kubernetes gRPC probes use.
+func newGRPCServer(cfg *config.Config, register func(*grpc.Server)) (*grpc.Server, *health.Server, error) {
+	var opts []grpc.ServerOption
+	if cfg.Secure {
+		tlsConfig, err := buildTLSConfig(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	srv := grpc.NewServer(opts...)
+	register(srv)
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	return srv, healthSrv, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+func TestExtAuthzOverGRPC(t *testing.T) {
+	evaluator := extauthz.NewServer(logger.Development(), nil, nil, nil)
+	srv, healthSrv, err := newGRPCServer(&config.Config{}, func(srv *grpc.Server) {
+		authv3.RegisterAuthorizationServer(srv, evaluator)
+	})
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	ctx := context.Background()
+
+	status, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status.GetStatus())
+
+	// A request without an API key is denied before any lookup, as Envoy's ext_authz filter sees it.
+	resp, err := authv3.NewAuthorizationClient(conn).Check(ctx, &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{Method: "POST", Path: "/llm/granite/v1/chat/completions"},
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(codes.Unauthenticated), resp.GetStatus().GetCode())
+	assert.Equal(t, typev3.StatusCode_Unauthorized, resp.GetDeniedResponse().GetStatus().GetCode())
+
+	healthSrv.Shutdown()
+	status, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status.GetStatus())
+}
@@ -0,0 +1,17 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+// newClient creates a maas-api client from the flags. Requests are bounded by the --timeout
+// context rather than a client timeout.
+func newClient(opts options) *client.Client {
+	c := client.New(opts.server, opts.token)
+	c.SetHTTPClient(&http.Client{})
+	c.SetInsecureSkipVerify(opts.insecure)
+	c.SetSubscription(opts.subscription)
+	return c
+}
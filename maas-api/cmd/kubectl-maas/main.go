@@ -0,0 +1,150 @@
+// Command kubectl-maas is a kubectl plugin for inspecting MaaS resources through maas-api.
+//
+// Install it anywhere on PATH and invoke it as `kubectl maas <command>`.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/utils/env"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+const usage = `Usage: kubectl maas [flags] <command> [command flags]
+
+Commands:
+  models          List models available to the current user
+  subscriptions   List subscriptions accessible to the current user
+  tiers           List subscriptions ordered by selection priority
+  usage           Show token rate limits and billing rates per model
+
+Flags:
+`
+
+type options struct {
+	server       string
+	token        string
+	subscription string
+	output       string
+	insecure     bool
+	timeout      time.Duration
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	opts := options{}
+	fs := flag.NewFlagSet("kubectl-maas", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.server, "server", env.GetString("MAAS_API_URL", ""), "Base URL of the MaaS API, e.g. https://maas.apps.example.com/maas-api")
+	fs.StringVar(&opts.token, "token", env.GetString("MAAS_TOKEN", ""), "Bearer token or API key; defaults to the token in the current kubeconfig context")
+	fs.StringVar(&opts.subscription, "subscription", "", "Subscription to send in the X-MaaS-Subscription header")
+	fs.StringVar(&opts.output, "o", "table", "Output format: table or json")
+	fs.BoolVar(&opts.insecure, "insecure-skip-tls-verify", false, "Skip TLS certificate verification")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "Request timeout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("command is required")
+	}
+	if opts.output != outputTable && opts.output != outputJSON {
+		return fmt.Errorf("unsupported output format %q", opts.output)
+	}
+	if opts.server == "" {
+		return errors.New("--server (or MAAS_API_URL) is required")
+	}
+	if opts.token == "" {
+		restConfig, err := config.LoadRestConfig()
+		if err != nil || restConfig.BearerToken == "" {
+			return errors.New("no token found: pass --token, set MAAS_TOKEN, or log in with a token-based kubeconfig")
+		}
+		opts.token = restConfig.BearerToken
+	}
+
+	c := newClient(opts)
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	switch cmd {
+	case "models":
+		return runModels(ctx, c, cmdArgs, opts.output, out)
+	case "subscriptions", "subs":
+		return runSubscriptions(ctx, c, opts.output, out, false)
+	case "tiers":
+		return runSubscriptions(ctx, c, opts.output, out, true)
+	case "usage":
+		return runUsage(ctx, c, opts.output, out)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func runModels(ctx context.Context, c *client.Client, args []string, output string, out io.Writer) error {
+	fs := flag.NewFlagSet("models", flag.ContinueOnError)
+	explain := fs.Bool("explain-decision", false, "Explain which subscription the gateway selects for each model")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	models, err := c.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+	if !*explain {
+		return printModels(out, output, models)
+	}
+
+	decisions := make([]decision, 0, len(models))
+	for _, m := range models {
+		// OwnedBy is the MaaSModelRef's namespace/name.
+		namespace, name, _ := strings.Cut(m.OwnedBy, "/")
+		subs, err := c.ListSubscriptionsForModel(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		decisions = append(decisions, explainDecision(m.ID, c.Subscription(), subs))
+	}
+	return printDecisions(out, output, decisions)
+}
+
+func runSubscriptions(ctx context.Context, c *client.Client, output string, out io.Writer, byPriority bool) error {
+	subs, err := c.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	if byPriority {
+		sortByPriority(subs)
+	}
+	return printSubscriptions(out, output, subs)
+}
+
+func runUsage(ctx context.Context, c *client.Client, output string, out io.Writer) error {
+	subs, err := c.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	return printUsage(out, output, subs)
+}
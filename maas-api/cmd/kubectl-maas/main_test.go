@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+func TestExplainDecision(t *testing.T) {
+	premium := client.Subscription{SubscriptionIDHeader: "premium", Priority: 10}
+	free := client.Subscription{SubscriptionIDHeader: "free"}
+
+	tests := []struct {
+		name      string
+		requested string
+		subs      []client.Subscription
+		allowed   bool
+		selected  string
+	}{
+		{name: "no subscriptions", allowed: false},
+		{name: "single subscription auto-selected", subs: []client.Subscription{free}, allowed: true, selected: "free"},
+		{name: "multiple subscriptions need header", subs: []client.Subscription{free, premium}, allowed: false},
+		{name: "requested bare name", requested: "premium", subs: []client.Subscription{free, premium}, allowed: true, selected: "premium"},
+		{name: "requested qualified name", requested: "models-as-a-service/premium", subs: []client.Subscription{free, premium}, allowed: true, selected: "premium"},
+		{name: "requested subscription without model", requested: "enterprise", subs: []client.Subscription{free}, allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := explainDecision("llm", tt.requested, tt.subs)
+			assert.Equal(t, tt.allowed, d.Allowed)
+			assert.Equal(t, tt.selected, d.Subscription)
+			assert.NotEmpty(t, d.Reason)
+		})
+	}
+}
+
+func TestRunTiersOrdersByPriority(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "/v1/subscriptions", r.URL.Path)
+		_ = json.NewEncoder(w).Encode([]client.Subscription{
+			{SubscriptionIDHeader: "free", Priority: 0},
+			{SubscriptionIDHeader: "premium", Priority: 10},
+		})
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	err := run(context.Background(), []string{"--server", srv.URL, "--token", "test-token", "-o", "json", "tiers"}, &out)
+	require.NoError(t, err)
+
+	var got []client.Subscription
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	require.Len(t, got, 2)
+	assert.Equal(t, "premium", got[0].SubscriptionIDHeader)
+	assert.Equal(t, "free", got[1].SubscriptionIDHeader)
+}
+
+func TestRunSurfacesAPIErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":{"message":"Authorization required","type":"authentication_error"}}`))
+	}))
+	defer srv.Close()
+
+	err := run(context.Background(), []string{"--server", srv.URL, "--token", "bad", "models"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Authorization required")
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// decision describes how the gateway resolves a subscription for requests to a single model.
+// It mirrors the auto-selection rules of the /internal/v1/subscriptions/select endpoint.
+type decision struct {
+	Model        string   `json:"model"`
+	Allowed      bool     `json:"allowed"`
+	Subscription string   `json:"subscription,omitempty"`
+	Candidates   []string `json:"candidates,omitempty"`
+	Reason       string   `json:"reason"`
+}
+
+func explainDecision(modelID, requested string, subs []client.Subscription) decision {
+	d := decision{Model: modelID}
+	for _, s := range subs {
+		d.Candidates = append(d.Candidates, s.SubscriptionIDHeader)
+	}
+
+	switch {
+	case requested != "":
+		for _, s := range subs {
+			if s.SubscriptionIDHeader == requested || strings.HasSuffix(requested, "/"+s.SubscriptionIDHeader) {
+				d.Allowed = true
+				d.Subscription = s.SubscriptionIDHeader
+				d.Reason = "requested subscription grants access to this model"
+				return d
+			}
+		}
+		d.Reason = fmt.Sprintf("requested subscription %q does not include this model or is not accessible", requested)
+	case len(subs) == 0:
+		d.Reason = "no accessible subscription includes this model"
+	case len(subs) == 1:
+		d.Allowed = true
+		d.Subscription = subs[0].SubscriptionIDHeader
+		d.Reason = "only accessible subscription for this model, selected automatically"
+	default:
+		d.Reason = "multiple subscriptions include this model; requests must set the X-MaaS-Subscription header"
+	}
+	return d
+}
+
+// sortByPriority orders subscriptions the way the selector ranks them: priority desc, then name asc.
+func sortByPriority(subs []client.Subscription) {
+	sort.SliceStable(subs, func(i, j int) bool {
+		if subs[i].Priority != subs[j].Priority {
+			return subs[i].Priority > subs[j].Priority
+		}
+		return subs[i].SubscriptionIDHeader < subs[j].SubscriptionIDHeader
+	})
+}
+
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printModels(out io.Writer, output string, list []client.Model) error {
+	if output == outputJSON {
+		return printJSON(out, list)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tOWNER\tKIND\tREADY\tURL")
+	for _, m := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", m.ID, m.OwnedBy, orNone(m.Kind), m.Ready, orNone(m.URL))
+	}
+	return w.Flush()
+}
+
+func printDecisions(out io.Writer, output string, list []decision) error {
+	if output == outputJSON {
+		return printJSON(out, list)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tALLOWED\tSUBSCRIPTION\tCANDIDATES\tREASON")
+	for _, d := range list {
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\n", d.Model, d.Allowed, orNone(d.Subscription), orNone(strings.Join(d.Candidates, ",")), d.Reason)
+	}
+	return w.Flush()
+}
+
+func printSubscriptions(out io.Writer, output string, subs []client.Subscription) error {
+	if output == outputJSON {
+		return printJSON(out, subs)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPRIORITY\tMODELS\tORGANIZATION\tDESCRIPTION")
+	for _, s := range subs {
+		names := make([]string, 0, len(s.ModelRefs))
+		for _, ref := range s.ModelRefs {
+			names = append(names, ref.Name)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", s.SubscriptionIDHeader, s.Priority, orNone(strings.Join(names, ",")), orNone(s.OrganizationID), s.SubscriptionDescription)
+	}
+	return w.Flush()
+}
+
+// usageRow is one model entry of a subscription as shown by the usage command.
+type usageRow struct {
+	Subscription string             `json:"subscription"`
+	Model        string             `json:"model"`
+	Limits       []client.RateLimit `json:"tokenRateLimits,omitempty"`
+	PerToken     string             `json:"perToken,omitempty"`
+}
+
+func printUsage(out io.Writer, output string, subs []client.Subscription) error {
+	var rows []usageRow
+	for _, s := range subs {
+		for _, ref := range s.ModelRefs {
+			row := usageRow{Subscription: s.SubscriptionIDHeader, Model: ref.Name, Limits: ref.TokenRateLimits}
+			if ref.BillingRate != nil {
+				row.PerToken = ref.BillingRate.PerToken
+			}
+			rows = append(rows, row)
+		}
+	}
+	if output == outputJSON {
+		if rows == nil {
+			rows = []usageRow{}
+		}
+		return printJSON(out, rows)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SUBSCRIPTION\tMODEL\tTOKEN LIMITS\tPER TOKEN")
+	for _, r := range rows {
+		limits := make([]string, 0, len(r.Limits))
+		for _, l := range r.Limits {
+			limits = append(limits, fmt.Sprintf("%d/%s", l.Limit, l.Window))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Subscription, r.Model, orNone(strings.Join(limits, ",")), orNone(r.PerToken))
+	}
+	return w.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
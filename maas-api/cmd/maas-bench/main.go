@@ -0,0 +1,174 @@
+// Command maas-bench load tests the authorization endpoints of maas-api over a synthetic informer
+// cache and writes the throughput and latency of each scenario as JSON, for regression tracking.
+//
+// Every combination of --models, --shapes, --endpoints and --concurrency is run against the
+// maas-api handlers served in process on a loopback port, so results depend on the machine but not
+// on a cluster. To load test a deployed maas-api, use the k6 scenario in test/bench instead.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/bench"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// report is the JSON document maas-bench writes.
+type report struct {
+	Timestamp  time.Time      `json:"timestamp"`
+	GoVersion  string         `json:"goVersion"`
+	GOMAXPROCS int            `json:"gomaxprocs"`
+	Results    []bench.Result `json:"results"`
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out, progress io.Writer) error {
+	var (
+		modelsList, shapesList, endpointsList, concurrencyList, output string
+		duration, decisionCacheTTL                                     time.Duration
+		unindexed                                                      bool
+	)
+	fs := flag.NewFlagSet("maas-bench", flag.ContinueOnError)
+	fs.StringVar(&modelsList, "models", "100,1000,10000", "Comma-separated cache sizes (MaaSModelRefs)")
+	fs.StringVar(&shapesList, "shapes", "bare,annotated,aliased,routed", "Comma-separated MaaSModelRef shapes: bare, annotated, aliased, routed")
+	fs.StringVar(&endpointsList, "endpoints", "select,access,batch", "Comma-separated endpoints: select, access, batch")
+	fs.StringVar(&concurrencyList, "concurrency", "1,16,64", "Comma-separated numbers of concurrent callers")
+	fs.DurationVar(&duration, "duration", 10*time.Second, "How long each scenario runs")
+	fs.BoolVar(&unindexed, "unindexed", false, "Hide the informer indexes, so lookups scan the cache")
+	fs.DurationVar(&decisionCacheTTL, "decision-cache-ttl", 0, "Enable the selection decision cache with this TTL")
+	fs.StringVar(&output, "o", "", "File to write the JSON report to (default: standard output)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sizes, err := parseInts(modelsList)
+	if err != nil {
+		return fmt.Errorf("--models: %w", err)
+	}
+	concurrency, err := parseInts(concurrencyList)
+	if err != nil {
+		return fmt.Errorf("--concurrency: %w", err)
+	}
+	var shapes []bench.Shape
+	for s := range strings.SplitSeq(shapesList, ",") {
+		shape, err := bench.ParseShape(s)
+		if err != nil {
+			return fmt.Errorf("--shapes: %w", err)
+		}
+		shapes = append(shapes, shape)
+	}
+	var endpoints []bench.Endpoint
+	for s := range strings.SplitSeq(endpointsList, ",") {
+		endpoint, err := bench.ParseEndpoint(s)
+		if err != nil {
+			return fmt.Errorf("--endpoints: %w", err)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if duration <= 0 {
+		return errors.New("--duration must be positive")
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	log := logger.New(false)
+	rep := report{Timestamp: time.Now().UTC(), GoVersion: runtime.Version(), GOMAXPROCS: runtime.GOMAXPROCS(0)}
+	// A failed or interrupted run still reports the scenarios that completed.
+	var runErr error
+runs:
+	for _, size := range sizes {
+		for _, shape := range shapes {
+			var results []bench.Result
+			results, runErr = runCache(ctx, log, bench.Options{
+				Models: size, Shape: shape, Unindexed: unindexed, DecisionCacheTTL: decisionCacheTTL,
+			}, endpoints, concurrency, duration, progress)
+			rep.Results = append(rep.Results, results...)
+			if runErr != nil {
+				break runs
+			}
+		}
+	}
+
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return errors.Join(runErr, err)
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return errors.Join(runErr, enc.Encode(rep))
+}
+
+// runCache serves one synthetic cache and runs every endpoint and concurrency against it.
+func runCache(ctx context.Context, log *logger.Logger, opts bench.Options, endpoints []bench.Endpoint,
+	concurrency []int, duration time.Duration, progress io.Writer,
+) ([]bench.Result, error) {
+	c, err := bench.NewCache(opts)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &http.Server{Handler: c.NewRouter(log), ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: slices.Max(concurrency)}}
+	baseURL := "http://" + listener.Addr().String()
+	var results []bench.Result
+	for _, endpoint := range endpoints {
+		for _, n := range concurrency {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			result, err := bench.Run(ctx, client, baseURL, c, bench.Scenario{Endpoint: endpoint, Concurrency: n, Duration: duration})
+			if err != nil {
+				return results, err
+			}
+			fmt.Fprintf(progress, "models=%d shape=%s endpoint=%s concurrency=%d: %.0f req/s, p50 %.2fms, p99 %.2fms, %d errors, %d denied\n",
+				opts.Models, opts.Shape, endpoint, n, result.RequestsPerSecond, result.Latency.P50, result.Latency.P99, result.Errors, result.Denied)
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func parseInts(list string) ([]int, error) {
+	var out []int
+	for s := range strings.SplitSeq(list, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%q is not a positive number", s)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
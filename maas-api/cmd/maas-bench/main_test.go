@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReportsEveryScenario(t *testing.T) {
+	var out bytes.Buffer
+	err := run(context.Background(), []string{
+		"--models", "50", "--shapes", "bare,aliased", "--endpoints", "select,access", "--concurrency", "1,2", "--duration", "50ms",
+	}, &out, io.Discard)
+	require.NoError(t, err)
+
+	var rep report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &rep))
+	require.Len(t, rep.Results, 8, "2 shapes x 2 endpoints x 2 concurrency levels")
+	for _, r := range rep.Results {
+		assert.Equal(t, 50, r.Models)
+		assert.Positive(t, r.Requests, "%s %s %d", r.Shape, r.Endpoint, r.Concurrency)
+		assert.Zero(t, r.Errors+r.Denied, "%s %s %d", r.Shape, r.Endpoint, r.Concurrency)
+	}
+}
+
+func TestRunRejectsInvalidFlags(t *testing.T) {
+	for name, args := range map[string][]string{
+		"--models":      {"--models", "0"},
+		"--concurrency": {"--concurrency", "many"},
+		"--shapes":      {"--shapes", "fancy"},
+		"--endpoints":   {"--endpoints", "stream"},
+		"--duration":    {"--duration", "0s"},
+	} {
+		err := run(context.Background(), args, io.Discard, io.Discard)
+		assert.ErrorContains(t, err, name)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/utils/env"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// apiOptions are the flags of the commands that call maas-api.
+type apiOptions struct {
+	server   string
+	token    string
+	output   string
+	insecure bool
+	timeout  time.Duration
+}
+
+func (o *apiOptions) addFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&o.server, "server", env.GetString("MAAS_API_URL", ""), "Base URL of the MaaS API, e.g. https://maas.apps.example.com/maas-api")
+	flags.StringVar(&o.token, "token", env.GetString("MAAS_TOKEN", ""), "Bearer token or API key; defaults to the token in the current kubeconfig context")
+	flags.StringVarP(&o.output, "output", "o", outputTable, "Output format: table or json")
+	flags.BoolVar(&o.insecure, "insecure-skip-tls-verify", false, "Skip TLS certificate verification")
+	flags.DurationVar(&o.timeout, "timeout", 30*time.Second, "Request timeout")
+}
+
+// client checks the flags and creates a maas-api client. Without --token, the bearer token of the
+// current kubeconfig context is used, as after "oc login".
+func (o *apiOptions) client() (*client.Client, error) {
+	if o.output != outputTable && o.output != outputJSON {
+		return nil, fmt.Errorf("unsupported output format %q", o.output)
+	}
+	if o.server == "" {
+		return nil, errors.New("--server (or MAAS_API_URL) is required")
+	}
+	token := o.token
+	if token == "" {
+		restConfig, err := config.LoadRestConfig()
+		if err != nil || restConfig.BearerToken == "" {
+			return nil, errors.New("no token found: pass --token, set MAAS_TOKEN, or log in with a token-based kubeconfig")
+		}
+		token = restConfig.BearerToken
+	}
+	c := client.New(o.server, token)
+	c.SetHTTPClient(&http.Client{Timeout: o.timeout})
+	c.SetInsecureSkipVerify(o.insecure)
+	return c, nil
+}
+
+// apiCommand adapts fn, which calls maas-api with c, into the RunE of a command.
+func apiCommand(o *apiOptions, fn func(ctx context.Context, c *client.Client, args []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		c, err := o.client()
+		if err != nil {
+			return err
+		}
+		return fn(cmd.Context(), c, args)
+	}
+}
+
+func newModelsCommand(o *apiOptions, out io.Writer) *cobra.Command {
+	var tier string
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "List the models available to you, or to one of your tiers",
+		Args:  cobra.NoArgs,
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+			c.SetSubscription(tier)
+			list, err := c.ListModels(ctx)
+			if err != nil {
+				return err
+			}
+			return printModels(out, o.output, list)
+		}),
+	}
+	cmd.Flags().StringVar(&tier, "tier", "", "Subscription (tier) to list the models of")
+	return cmd
+}
+
+func newAccessCommand(o *apiOptions, out io.Writer) *cobra.Command {
+	var tier string
+	cmd := &cobra.Command{
+		Use:   "access MODEL",
+		Short: "Check whether you may call a model (name, alias or namespace/name) and, if not, why",
+		Args:  cobra.ExactArgs(1),
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, args []string) error {
+			decision, err := c.CheckAccess(ctx, args[0], tier)
+			if err != nil {
+				return err
+			}
+			return printAccess(out, o.output, decision)
+		}),
+	}
+	cmd.Flags().StringVar(&tier, "tier", "", "Subscription (tier) to check; the one the gateway would select by default")
+	return cmd
+}
+
+func newTokensCommand(o *apiOptions, out io.Writer) *cobra.Command {
+	tokens := &cobra.Command{
+		Use:   "tokens",
+		Short: "Mint, list and revoke short-lived tokens for notebooks and CI jobs",
+	}
+
+	var req client.CreateTokenRequest
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Mint a token; its key is shown only once",
+		Args:  cobra.NoArgs,
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+			created, err := c.CreateToken(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printCreatedToken(out, o.output, created)
+		}),
+	}
+	create.Flags().StringVar(&req.Name, "name", "", "Name of the token; generated when empty")
+	create.Flags().StringVar(&req.Subscription, "tier", "", "Subscription (tier) the token is bound to; your default when empty")
+	create.Flags().StringSliceVar(&req.Models, "model", nil, "Model (namespace/name) the token is limited to; repeat for several")
+	create.Flags().DurationVar(&req.ExpiresIn, "expires-in", 0, "Lifetime of the token, at most 1h (default 1h)")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List your active tokens",
+		Args:  cobra.NoArgs,
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+			list, err := c.ListTokens(ctx)
+			if err != nil {
+				return err
+			}
+			return printTokens(out, o.output, list)
+		}),
+	}
+
+	revoke := &cobra.Command{
+		Use:   "revoke ID",
+		Short: "Revoke a token",
+		Args:  cobra.ExactArgs(1),
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, args []string) error {
+			revoked, err := c.RevokeToken(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			if o.output == outputJSON {
+				return printJSON(out, revoked)
+			}
+			fmt.Fprintf(out, "token %s revoked\n", revoked.ID)
+			return nil
+		}),
+	}
+
+	tokens.AddCommand(create, list, revoke)
+	return tokens
+}
+
+func newUsageCommand(o *apiOptions, out io.Writer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "usage",
+		Short: "Show your rate limits on each model and your requests in progress",
+		Args:  cobra.NoArgs,
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+			limits, err := c.ListLimits(ctx)
+			if err != nil {
+				return err
+			}
+			return printLimits(out, o.output, limits)
+		}),
+	}
+}
+
+func newAdminCommand(o *apiOptions, out io.Writer) *cobra.Command {
+	admin := &cobra.Command{
+		Use:   "admin",
+		Short: "Manage tiers and view the model catalog (admins only)",
+	}
+
+	tiers := &cobra.Command{
+		Use:   "tiers",
+		Short: "Manage tiers (MaaSSubscriptions)",
+	}
+	tiers.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the tiers, highest priority first",
+			Args:  cobra.NoArgs,
+			RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+				list, err := c.ListTiers(ctx)
+				if err != nil {
+					return err
+				}
+				return printTiers(out, o.output, list)
+			}),
+		},
+		&cobra.Command{
+			Use:   "get NAME",
+			Short: "Show a tier",
+			Args:  cobra.ExactArgs(1),
+			RunE: apiCommand(o, func(ctx context.Context, c *client.Client, args []string) error {
+				tier, err := c.GetTier(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return printTier(out, o.output, tier)
+			}),
+		},
+		&cobra.Command{
+			Use:   "delete NAME",
+			Short: "Delete a tier",
+			Args:  cobra.ExactArgs(1),
+			RunE: apiCommand(o, func(ctx context.Context, c *client.Client, args []string) error {
+				if err := c.DeleteTier(ctx, args[0]); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "tier %s deleted\n", args[0])
+				return nil
+			}),
+		},
+	)
+
+	var file string
+	apply := &cobra.Command{
+		Use:   "apply -f FILE",
+		Short: "Create a tier, or replace it when it exists, from a YAML or JSON file",
+		Args:  cobra.NoArgs,
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+			tier, err := readTier(file)
+			if err != nil {
+				return err
+			}
+			var applied *client.Tier
+			verb := "configured"
+			if _, err = c.GetTier(ctx, tier.Name); client.IsNotFound(err) {
+				applied, err = c.CreateTier(ctx, *tier)
+				verb = "created"
+			} else if err == nil {
+				applied, err = c.UpdateTier(ctx, *tier)
+			}
+			if err != nil {
+				return err
+			}
+			if o.output == outputJSON {
+				return printJSON(out, applied)
+			}
+			fmt.Fprintf(out, "tier %s %s\n", applied.Name, verb)
+			return nil
+		}),
+	}
+	apply.Flags().StringVarP(&file, "filename", "f", "", "File holding the tier, or - for stdin")
+	_ = apply.MarkFlagRequired("filename")
+	tiers.AddCommand(apply)
+
+	catalog := &cobra.Command{
+		Use:   "catalog",
+		Short: "Show the model catalog: every model with its phase, endpoint and tiers",
+		Args:  cobra.NoArgs,
+		RunE: apiCommand(o, func(ctx context.Context, c *client.Client, _ []string) error {
+			catalog, err := c.GetCatalog(ctx)
+			if err != nil {
+				return err
+			}
+			return printCatalog(out, o.output, catalog)
+		}),
+	}
+
+	admin.AddCommand(tiers, catalog)
+	return admin
+}
+
+// readTier reads a tier from a YAML or JSON file, or stdin for "-".
+func readTier(file string) (*client.Tier, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tier: %w", err)
+	}
+	var tier client.Tier
+	if err := yaml.UnmarshalStrict(data, &tier); err != nil {
+		return nil, fmt.Errorf("failed to parse tier: %w", err)
+	}
+	if tier.Name == "" {
+		return nil, errors.New("the tier has no name")
+	}
+	return &tier, nil
+}
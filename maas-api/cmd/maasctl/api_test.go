@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+// runAPI runs maasctl against srv and returns its output.
+func runAPI(t *testing.T, srv *httptest.Server, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	err := run(context.Background(), append([]string{"--server", srv.URL, "--token", "test-token"}, args...), &out, nil)
+	return out.String(), err
+}
+
+func TestModelsSendsTier(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "/v1/models", r.URL.Path)
+		assert.Equal(t, "premium", r.Header.Get("X-MaaS-Subscription"))
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"granite","object":"model","owned_by":"llm","ready":true}]}`))
+	}))
+	defer srv.Close()
+
+	out, err := runAPI(t, srv, "models", "--tier", "premium")
+	require.NoError(t, err)
+	assert.Contains(t, out, "granite")
+	assert.Contains(t, out, "llm")
+}
+
+func TestAccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/models/granite/access", r.URL.Path)
+		assert.Equal(t, "llm", r.URL.Query().Get("namespace"))
+		assert.Equal(t, "free", r.URL.Query().Get("tier"))
+		_, _ = w.Write([]byte(`{"object":"model.access","allowed":false,"model":"llm/granite","reason":"tier_not_allowed","allowedTiers":["premium"]}`))
+	}))
+	defer srv.Close()
+
+	out, err := runAPI(t, srv, "access", "llm/granite", "--tier", "free")
+	require.NoError(t, err)
+	assert.Contains(t, out, "tier_not_allowed")
+	assert.Contains(t, out, "tiers that include this model: premium")
+}
+
+func TestTokens(t *testing.T) {
+	var created map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v1/tokens":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"key":"sk-oai-secret","id":"tok-1","name":"ci","subscription":"free","ephemeral":true}`))
+		case "GET /v1/tokens":
+			_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"tok-1","name":"ci","status":"active","creationDate":"2026-03-02T10:00:00Z"}]}`))
+		case "DELETE /v1/tokens/tok-1":
+			_, _ = w.Write([]byte(`{"id":"tok-1","name":"ci","status":"revoked","creationDate":"2026-03-02T10:00:00Z"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	out, err := runAPI(t, srv, "tokens", "create", "--name", "ci", "--tier", "free", "--model", "llm/granite", "--model", "llm/mistral", "--expires-in", "30m")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"name":         "ci",
+		"subscription": "free",
+		"models":       []any{"llm/granite", "llm/mistral"},
+		"expiresIn":    "30m0s",
+	}, created)
+	assert.Contains(t, out, "token (shown only once): sk-oai-secret")
+
+	out, err = runAPI(t, srv, "tokens", "list", "-o", "json")
+	require.NoError(t, err)
+	var list []client.Token
+	require.NoError(t, json.Unmarshal([]byte(out), &list))
+	require.Len(t, list, 1)
+	assert.Equal(t, "tok-1", list[0].ID)
+
+	out, err = runAPI(t, srv, "tokens", "revoke", "tok-1")
+	require.NoError(t, err)
+	assert.Equal(t, "token tok-1 revoked\n", out)
+}
+
+func TestAdminTiersApply(t *testing.T) {
+	var methods []string
+	exists := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case http.MethodGet:
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"code":"NOT_FOUND","message":"tier not found"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"gold"}`))
+		case http.MethodPost, http.MethodPut:
+			var tier client.Tier
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&tier))
+			assert.Equal(t, int32(20), tier.Priority)
+			assert.Equal(t, []string{"gold-users"}, tier.Owner.Groups)
+			_ = json.NewEncoder(w).Encode(tier)
+		}
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "tier.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`name: gold
+priority: 20
+owner:
+  groups: [gold-users]
+models:
+- name: granite
+  namespace: llm
+  tokenRateLimits:
+  - limit: 100000
+    window: 1m
+`), 0o600))
+
+	out, err := runAPI(t, srv, "admin", "tiers", "apply", "-f", file)
+	require.NoError(t, err)
+	assert.Equal(t, "tier gold created\n", out)
+
+	exists = true
+	out, err = runAPI(t, srv, "admin", "tiers", "apply", "-f", file)
+	require.NoError(t, err)
+	assert.Equal(t, "tier gold configured\n", out)
+	assert.Equal(t, []string{"GET", "POST", "GET", "PUT"}, methods)
+
+	require.NoError(t, os.WriteFile(file, []byte("name: gold\npriorty: 20\n"), 0o600))
+	_, err = runAPI(t, srv, "admin", "tiers", "apply", "-f", file)
+	require.ErrorContains(t, err, "failed to parse tier")
+}
+
+func TestAPIErrorsAreSurfaced(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"code":"PERMISSION_DENIED","message":"Admin access required"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := runAPI(t, srv, "admin", "catalog")
+	require.ErrorContains(t, err, "403 PERMISSION_DENIED: Admin access required")
+
+	_, err = runAPI(t, srv, "usage", "-o", "yaml")
+	require.ErrorContains(t, err, `unsupported output format "yaml"`)
+}
@@ -0,0 +1,572 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed manifests/*.yaml
+var manifests embed.FS
+
+// Versions of the dependencies the demo installs, kept in line with scripts/install-dependencies.sh
+// and scripts/installers/install-istio.sh.
+const (
+	gatewayAPIVersion = "v1.3.0"
+	istioVersion      = "1.26.2"
+	istioHub          = "gcr.io/istio-release"
+	kuadrantVersion   = "1.3.1"
+	kuadrantCharts    = "https://kuadrant.io/helm-charts"
+)
+
+type demoModel struct {
+	Name string
+}
+
+type demoTier struct {
+	Name            string
+	User            string
+	Group           string
+	Priority        int
+	TokensPerMinute int64
+	Models          []string
+}
+
+// demoConfig is the demo environment; the manifests are rendered with it.
+type demoConfig struct {
+	ClusterName         string
+	RepoRoot            string
+	MaaSAPIImage        string
+	MaaSControllerImage string
+	OIDCImage           string
+	PostgresImage       string
+	LocalPort           int
+	Timeout             time.Duration
+	DryRun              bool
+
+	GatewayName           string
+	GatewayNamespace      string
+	MaaSNamespace         string
+	SubscriptionNamespace string
+	DemoNamespace         string
+	OIDCIssuerID          string
+	OIDCClientID          string
+
+	Models []demoModel
+	Tiers  []demoTier
+}
+
+func defaultDemoConfig() demoConfig {
+	return demoConfig{
+		GatewayName:           "maas-default-gateway",
+		GatewayNamespace:      "openshift-ingress",
+		MaaSNamespace:         "opendatahub",
+		SubscriptionNamespace: "models-as-a-service",
+		DemoNamespace:         "maas-demo",
+		OIDCIssuerID:          "demo",
+		OIDCClientID:          "maas-demo",
+		Models:                []demoModel{{Name: "granite"}, {Name: "llama"}},
+		Tiers: []demoTier{
+			{Name: "free", User: "alice", Group: "demo-free", TokensPerMinute: 100, Models: []string{"granite"}},
+			{Name: "premium", User: "bob", Group: "demo-premium", Priority: 10, TokensPerMinute: 10000, Models: []string{"granite", "llama"}},
+		},
+	}
+}
+
+// Users lists the demo user of each tier, for the OIDC issuer's token mappings.
+func (c demoConfig) Users() []struct{ Name, Group string } {
+	users := make([]struct{ Name, Group string }, 0, len(c.Tiers))
+	for _, t := range c.Tiers {
+		users = append(users, struct{ Name, Group string }{t.User, t.Group})
+	}
+	return users
+}
+
+// oidcHost is the issuer's in-cluster address. Tokens must carry it as their issuer for
+// Authorino to accept them, so the demo requests them with it as the Host header.
+func (c demoConfig) oidcHost() string {
+	return "mock-oidc." + c.DemoNamespace + ".svc.cluster.local:8080"
+}
+
+func (c demoConfig) oidcIssuer() string {
+	return "http://" + c.oidcHost() + "/" + c.OIDCIssuerID
+}
+
+func (c demoConfig) gatewayService() string {
+	return c.GatewayName + "-istio"
+}
+
+func runDemoUp(ctx context.Context, args []string, out io.Writer, r runner) error {
+	cfg := defaultDemoConfig()
+	fs := flag.NewFlagSet("demo up", flag.ContinueOnError)
+	fs.StringVar(&cfg.ClusterName, "name", "maas-demo", "Name of the kind cluster")
+	fs.StringVar(&cfg.RepoRoot, "repo", ".", "Root of the models-as-a-service checkout the MaaS manifests are taken from")
+	fs.StringVar(&cfg.MaaSAPIImage, "maas-api-image", "quay.io/opendatahub/maas-api:latest", "maas-api image; also runs the mock models")
+	fs.StringVar(&cfg.MaaSControllerImage, "maas-controller-image", "quay.io/opendatahub/maas-controller:latest", "maas-controller image")
+	fs.StringVar(&cfg.OIDCImage, "oidc-image", "ghcr.io/navikt/mock-oauth2-server:2.1.10", "Image of the fake OIDC issuer")
+	fs.StringVar(&cfg.PostgresImage, "postgres-image", "docker.io/library/postgres:16", "PostgreSQL image for API key storage")
+	fs.IntVar(&cfg.LocalPort, "port", 8080, "Local port the gateway is forwarded to; the next port is used for the OIDC issuer while minting keys")
+	fs.DurationVar(&cfg.Timeout, "timeout", 5*time.Minute, "How long to wait for each component to become ready")
+	fs.BoolVar(&cfg.DryRun, "dry-run", false, "Print the commands instead of running them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	repo, err := filepath.Abs(cfg.RepoRoot)
+	if err != nil {
+		return err
+	}
+	cfg.RepoRoot = repo
+	if !cfg.DryRun {
+		if _, err := os.Stat(filepath.Join(repo, "deployment", "overlays", "http-backend")); err != nil {
+			return fmt.Errorf("--repo %s is not a models-as-a-service checkout: %w", repo, err)
+		}
+	}
+	if cfg.DryRun {
+		r = dryRunner{out: out}
+	}
+
+	d := &demo{cfg: cfg, r: r, out: out, http: &http.Client{Timeout: 30 * time.Second}}
+	return d.up(ctx)
+}
+
+func runDemoDown(ctx context.Context, args []string, out io.Writer, r runner) error {
+	fs := flag.NewFlagSet("demo down", flag.ContinueOnError)
+	name := fs.String("name", "maas-demo", "Name of the kind cluster")
+	dryRun := fs.Bool("dry-run", false, "Print the commands instead of running them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *dryRun {
+		r = dryRunner{out: out}
+	}
+	fmt.Fprintf(out, "==> Deleting kind cluster %s\n", *name)
+	_, err := r.run(ctx, nil, "kind", "delete", "cluster", "--name", *name)
+	return err
+}
+
+type demo struct {
+	cfg  demoConfig
+	r    runner
+	out  io.Writer
+	http *http.Client
+}
+
+func (d *demo) up(ctx context.Context) error {
+	cfg := d.cfg
+	wait := "--timeout=" + cfg.Timeout.String()
+	steps := []struct {
+		name string
+		run  func(context.Context) error
+	}{
+		{"Checking for kind, kubectl and helm", d.preflight},
+		{"Creating kind cluster " + cfg.ClusterName, d.createCluster},
+		{"Installing Gateway API " + gatewayAPIVersion, func(ctx context.Context) error {
+			return d.kubectl(ctx, nil, "apply", "--server-side", "-f",
+				"https://github.com/kubernetes-sigs/gateway-api/releases/download/"+gatewayAPIVersion+"/standard-install.yaml")
+		}},
+		{"Installing Istio " + istioVersion, func(ctx context.Context) error {
+			if _, err := d.r.run(ctx, nil, "helm", "upgrade", "-i", "istio-base", "oci://"+istioHub+"/charts/base",
+				"--version", istioVersion, "-n", "istio-system", "--create-namespace"); err != nil {
+				return err
+			}
+			_, err := d.r.run(ctx, nil, "helm", "upgrade", "-i", "istiod", "oci://"+istioHub+"/charts/istiod",
+				"--version", istioVersion, "-n", "istio-system", "--set", "tag="+istioVersion, "--set", "hub="+istioHub, "--wait", wait)
+			return err
+		}},
+		{"Installing Kuadrant " + kuadrantVersion, func(ctx context.Context) error {
+			if _, err := d.r.run(ctx, nil, "helm", "repo", "add", "kuadrant", kuadrantCharts, "--force-update"); err != nil {
+				return err
+			}
+			if _, err := d.r.run(ctx, nil, "helm", "upgrade", "-i", "kuadrant-operator", "kuadrant/kuadrant-operator",
+				"--version", kuadrantVersion, "-n", "kuadrant-system", "--create-namespace", "--wait", wait); err != nil {
+				return err
+			}
+			if err := d.apply(ctx, "kuadrant.yaml"); err != nil {
+				return err
+			}
+			return d.kubectl(ctx, nil, "wait", "--for=condition=Ready", "kuadrant/kuadrant", "-n", "kuadrant-system", wait)
+		}},
+		{"Creating gateway " + cfg.GatewayNamespace + "/" + cfg.GatewayName, func(ctx context.Context) error {
+			if err := d.apply(ctx, "gateway.yaml"); err != nil {
+				return err
+			}
+			return d.kubectl(ctx, nil, "wait", "--for=condition=Programmed", "gateway/"+cfg.GatewayName, "-n", cfg.GatewayNamespace, wait)
+		}},
+		{"Deploying fake OIDC issuer " + cfg.oidcIssuer(), func(ctx context.Context) error {
+			if err := d.apply(ctx, "oidc.yaml"); err != nil {
+				return err
+			}
+			return d.kubectl(ctx, nil, "rollout", "status", "deployment/mock-oidc", "-n", cfg.DemoNamespace, wait)
+		}},
+		{"Deploying PostgreSQL", func(ctx context.Context) error {
+			if err := d.apply(ctx, "postgres.yaml"); err != nil {
+				return err
+			}
+			return d.kubectl(ctx, nil, "rollout", "status", "deployment/postgres", "-n", cfg.MaaSNamespace, wait)
+		}},
+		{"Deploying maas-api and maas-controller", d.deployMaaS},
+		{"Deploying mock models", func(ctx context.Context) error {
+			if err := d.apply(ctx, "models.yaml"); err != nil {
+				return err
+			}
+			for _, m := range cfg.Models {
+				if err := d.kubectl(ctx, nil, "rollout", "status", "deployment/"+m.Name+"-mock", "-n", cfg.DemoNamespace, wait); err != nil {
+					return err
+				}
+			}
+			return d.kubectl(ctx, nil, "wait", "--for=jsonpath={.status.phase}=Ready", "maasmodelref", "--all", "-n", cfg.DemoNamespace, wait)
+		}},
+		{"Creating tiers", func(ctx context.Context) error {
+			if err := d.apply(ctx, "tiers.yaml"); err != nil {
+				return err
+			}
+			return d.kubectl(ctx, nil, "wait", "--for=jsonpath={.status.phase}=Active", "maassubscription", "--all", "-n", cfg.SubscriptionNamespace, wait)
+		}},
+	}
+	for _, step := range steps {
+		fmt.Fprintf(d.out, "==> %s\n", step.name)
+		if err := step.run(ctx); err != nil {
+			return fmt.Errorf("%s: %w", strings.ToLower(step.name[:1])+step.name[1:], err)
+		}
+	}
+
+	fmt.Fprintln(d.out, "==> Minting sample API keys")
+	keys, err := d.mintKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("minting sample API keys: %w", err)
+	}
+	d.printSummary(keys)
+	return nil
+}
+
+func (d *demo) preflight(context.Context) error {
+	if d.cfg.DryRun {
+		return nil
+	}
+	for _, tool := range []string{"kind", "kubectl", "helm"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not found on PATH", tool)
+		}
+	}
+	return nil
+}
+
+func (d *demo) createCluster(ctx context.Context) error {
+	clusters, err := d.r.run(ctx, nil, "kind", "get", "clusters")
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Fields(string(clusters)) {
+		if name == d.cfg.ClusterName {
+			fmt.Fprintf(d.out, "    cluster %s already exists, reusing it\n", name)
+			return d.kubectl(ctx, nil, "config", "use-context", "kind-"+name)
+		}
+	}
+	_, err = d.r.run(ctx, nil, "kind", "create", "cluster", "--name", d.cfg.ClusterName, "--wait", d.cfg.Timeout.String())
+	return err
+}
+
+// deployMaaS applies the http-backend overlay of the checkout with the demo's namespace and
+// images, as scripts/deploy.sh does, then points maas-api's AuthPolicy at the fake issuer.
+func (d *demo) deployMaaS(ctx context.Context) error {
+	cfg := d.cfg
+	dir, err := os.MkdirTemp("", "maasctl-demo-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	apiName, apiTag := splitImage(cfg.MaaSAPIImage)
+	controllerName, controllerTag := splitImage(cfg.MaaSControllerImage)
+	kustomization := fmt.Sprintf(`apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+namespace: %s
+resources:
+  - %s
+images:
+  - name: maas-api
+    newName: %s
+    newTag: %s
+  - name: maas-controller
+    newName: %s
+    newTag: %s
+`, cfg.MaaSNamespace, filepath.Join(cfg.RepoRoot, "deployment", "overlays", "http-backend"), apiName, apiTag, controllerName, controllerTag)
+	if err := os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(kustomization), 0o600); err != nil {
+		return err
+	}
+	rendered, err := d.r.run(ctx, nil, "kubectl", "kustomize", "--load-restrictor", "LoadRestrictionsNone", dir)
+	if err != nil {
+		return err
+	}
+	rendered = bytes.ReplaceAll(rendered, []byte("maas-api.placehold.svc"), []byte("maas-api."+cfg.MaaSNamespace+".svc"))
+	if err := d.kubectl(ctx, rendered, "apply", "--server-side", "--force-conflicts", "-f", "-"); err != nil {
+		return err
+	}
+
+	// Gateway policies stay in the gateway namespace.
+	policies, err := d.r.run(ctx, nil, "kubectl", "kustomize", filepath.Join(cfg.RepoRoot, "deployment", "base", "maas-controller", "policies"))
+	if err != nil {
+		return err
+	}
+	if err := d.kubectl(ctx, policies, "apply", "--server-side", "--force-conflicts", "-f", "-"); err != nil {
+		return err
+	}
+
+	patch, err := os.ReadFile(filepath.Join(cfg.RepoRoot, "scripts", "data", "maas-api-authpolicy-external-oidc-patch.yaml"))
+	if err != nil && !cfg.DryRun {
+		return err
+	}
+	patched := strings.NewReplacer("__OIDC_ISSUER_URL__", cfg.oidcIssuer(), "__OIDC_CLIENT_ID__", cfg.OIDCClientID).Replace(string(patch))
+	if err := d.kubectl(ctx, nil, "patch", "authpolicy", "maas-api-auth-policy", "-n", cfg.MaaSNamespace, "--type", "merge", "-p", patched); err != nil {
+		return err
+	}
+
+	for _, deployment := range []string{"maas-api", "maas-controller"} {
+		if err := d.kubectl(ctx, nil, "rollout", "status", "deployment/"+deployment, "-n", cfg.MaaSNamespace, "--timeout="+cfg.Timeout.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitImage splits an image reference into name and tag, defaulting the tag to latest.
+func splitImage(image string) (string, string) {
+	slash := strings.LastIndex(image, "/")
+	if colon := strings.LastIndex(image, ":"); colon > slash {
+		return image[:colon], image[colon+1:]
+	}
+	return image, "latest"
+}
+
+// apply renders a manifest with the demo config and applies it.
+func (d *demo) apply(ctx context.Context, name string) error {
+	rendered, err := renderManifest(name, d.cfg)
+	if err != nil {
+		return err
+	}
+	return d.kubectl(ctx, rendered, "apply", "--server-side", "--force-conflicts", "-f", "-")
+}
+
+func renderManifest(name string, cfg demoConfig) ([]byte, error) {
+	tmpl, err := template.ParseFS(manifests, "manifests/"+name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, cfg); err != nil {
+		return nil, fmt.Errorf("rendering %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *demo) kubectl(ctx context.Context, stdin []byte, args ...string) error {
+	_, err := d.r.run(ctx, stdin, "kubectl", args...)
+	return err
+}
+
+// mintKeys logs each tier's user in at the fake issuer and creates an API key for the tier,
+// through port-forwards to the issuer and the gateway.
+func (d *demo) mintKeys(ctx context.Context) (map[string]string, error) {
+	cfg := d.cfg
+	keys := make(map[string]string, len(cfg.Tiers))
+	if cfg.DryRun {
+		for _, t := range cfg.Tiers {
+			keys[t.Name] = "<" + t.Name + " key>"
+		}
+		return keys, nil
+	}
+
+	stopGateway, err := d.r.portForward(ctx, cfg.GatewayNamespace, cfg.gatewayService(), cfg.LocalPort, 80)
+	if err != nil {
+		return nil, err
+	}
+	defer stopGateway()
+	stopOIDC, err := d.r.portForward(ctx, cfg.DemoNamespace, "mock-oidc", cfg.LocalPort+1, 8080)
+	if err != nil {
+		return nil, err
+	}
+	defer stopOIDC()
+
+	gatewayURL := "http://127.0.0.1:" + strconv.Itoa(cfg.LocalPort)
+	oidcURL := "http://127.0.0.1:" + strconv.Itoa(cfg.LocalPort+1)
+	deadline := time.Now().Add(cfg.Timeout)
+	for _, t := range cfg.Tiers {
+		token, err := fetchToken(ctx, d.http, oidcURL, cfg.oidcHost(), cfg.OIDCIssuerID, cfg.OIDCClientID, t.User)
+		if err != nil {
+			return nil, fmt.Errorf("getting a token for %s: %w", t.User, err)
+		}
+		// The AuthPolicy patch takes a moment to reach the gateway.
+		for {
+			keys[t.Name], err = createAPIKey(ctx, d.http, gatewayURL, token, "demo-"+t.User, t.Name)
+			if err == nil || time.Now().After(deadline) {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("creating an API key for %s: %w", t.User, err)
+		}
+	}
+	return keys, nil
+}
+
+// fetchToken gets a token for user from the fake issuer with the client credentials grant; the
+// issuer maps the requested scope to the user's claims.
+func fetchToken(ctx context.Context, httpClient *http.Client, baseURL, host, issuerID, clientID, user string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {"demo"},
+		"scope":         {user},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/"+issuerID+"/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Host = host
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := doJSON(httpClient, req, &tok); err != nil {
+		return "", err
+	}
+	return tok.AccessToken, nil
+}
+
+// createAPIKey creates an API key bound to subscription through maas-api.
+func createAPIKey(ctx context.Context, httpClient *http.Client, gatewayURL, token, name, subscription string) (string, error) {
+	body, _ := json.Marshal(map[string]string{"name": name, "subscription": subscription})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gatewayURL+"/maas-api/v1/api-keys", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := doJSON(httpClient, req, &created); err != nil {
+		return "", err
+	}
+	if created.Key == "" {
+		return "", errors.New("maas-api returned no key")
+	}
+	return created.Key, nil
+}
+
+func doJSON(httpClient *http.Client, req *http.Request, out any) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (d *demo) printSummary(keys map[string]string) {
+	cfg := d.cfg
+	base := "http://localhost:" + strconv.Itoa(cfg.LocalPort)
+	fmt.Fprintf(d.out, "\nMaaS demo is up. Forward the gateway in another terminal:\n\n")
+	fmt.Fprintf(d.out, "  kubectl port-forward -n %s svc/%s %d:80\n", cfg.GatewayNamespace, cfg.gatewayService(), cfg.LocalPort)
+	for _, t := range cfg.Tiers {
+		env := strings.ToUpper(t.Name) + "_KEY"
+		fmt.Fprintf(d.out, "\nTier %s (user %s, group %s, %d tokens/min): %s\n\n", t.Name, t.User, t.Group, t.TokensPerMinute, strings.Join(t.Models, ", "))
+		fmt.Fprintf(d.out, "  export %s=%s\n", env, keys[t.Name])
+		fmt.Fprintf(d.out, "  curl -s %s/v1/models -H \"Authorization: Bearer $%s\"\n", base, env)
+		for _, m := range t.Models {
+			fmt.Fprintf(d.out, "  curl -s %s/%s/v1/chat/completions -H \"Authorization: Bearer $%s\" -H \"Content-Type: application/json\" \\\n", base, m, env)
+			fmt.Fprintf(d.out, "    -d '{\"model\": \"%s\", \"messages\": [{\"role\": \"user\", \"content\": \"Hello\"}]}'\n", m)
+		}
+	}
+	fmt.Fprintf(d.out, "\nTear it down with: maasctl demo down --name %s\n", cfg.ClusterName)
+}
+
+// runner runs the external tools the demo drives.
+type runner interface {
+	// run runs name with args, feeding it stdin, and returns its standard output.
+	run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error)
+	// portForward forwards localPort to port of a service until stop is called.
+	portForward(ctx context.Context, namespace, service string, localPort, port int) (stop func(), err error)
+}
+
+// execRunner runs the tools, streaming their standard error to log.
+type execRunner struct {
+	log io.Writer
+}
+
+func (e execRunner) run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	cmd.Stderr = e.log
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+func (e execRunner) portForward(ctx context.Context, namespace, service string, localPort, port int) (func(), error) {
+	cmd := exec.CommandContext(ctx, "kubectl", "port-forward", "-n", namespace, "svc/"+service, fmt.Sprintf("%d:%d", localPort, port))
+	cmd.Stderr = e.log
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	stop := func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort))
+	for range 60 {
+		if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+			_ = conn.Close()
+			return stop, nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	stop()
+	return nil, fmt.Errorf("port-forward to %s/%s did not become ready", namespace, service)
+}
+
+// dryRunner prints the commands instead of running them.
+type dryRunner struct {
+	out io.Writer
+}
+
+func (d dryRunner) run(_ context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	fmt.Fprintf(d.out, "    $ %s %s\n", name, strings.Join(args, " "))
+	if stdin != nil {
+		fmt.Fprintf(d.out, "      (%d bytes on stdin)\n", len(stdin))
+	}
+	return nil, nil
+}
+
+func (d dryRunner) portForward(_ context.Context, namespace, service string, localPort, port int) (func(), error) {
+	fmt.Fprintf(d.out, "    $ kubectl port-forward -n %s svc/%s %d:%d\n", namespace, service, localPort, port)
+	return func() {}, nil
+}
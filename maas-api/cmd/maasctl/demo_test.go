@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// recordingRunner records the commands it is asked to run.
+type recordingRunner struct {
+	commands []string
+	stdin    map[string][]byte
+}
+
+func (r *recordingRunner) run(_ context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := name + " " + strings.Join(args, " ")
+	r.commands = append(r.commands, cmd)
+	if stdin != nil {
+		r.stdin[cmd] = stdin
+	}
+	if cmd == "kind get clusters" {
+		return []byte("other\nmaas-demo\n"), nil
+	}
+	return nil, nil
+}
+
+func (r *recordingRunner) portForward(context.Context, string, string, int, int) (func(), error) {
+	return func() {}, nil
+}
+
+func TestRenderManifests(t *testing.T) {
+	cfg := defaultDemoConfig()
+	cfg.MaaSAPIImage = "quay.io/opendatahub/maas-api:test"
+	cfg.OIDCImage = "mock-oidc:test"
+	cfg.PostgresImage = "postgres:test"
+
+	kinds := map[string]int{}
+	for _, name := range []string{"gateway.yaml", "kuadrant.yaml", "oidc.yaml", "postgres.yaml", "models.yaml", "tiers.yaml"} {
+		rendered, err := renderManifest(name, cfg)
+		require.NoError(t, err, name)
+
+		decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(rendered), 4096)
+		for {
+			var obj map[string]any
+			err := decoder.Decode(&obj)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err, "%s does not render to valid YAML:\n%s", name, rendered)
+			if obj == nil {
+				continue
+			}
+			kinds[obj["kind"].(string)]++
+		}
+	}
+	assert.Equal(t, 2, kinds["ExternalModel"])
+	assert.Equal(t, 2, kinds["MaaSModelRef"])
+	assert.Equal(t, 2, kinds["MaaSSubscription"])
+	assert.Equal(t, 2, kinds["MaaSAuthPolicy"])
+	assert.Equal(t, 1, kinds["Gateway"])
+
+	oidc, err := renderManifest("oidc.yaml", cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(oidc), `"groups": ["demo-premium"]`, "the issuer maps each demo user to the tier's group")
+}
+
+func TestDemoUp_DryRun(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"demo", "up", "--dry-run", "--port", "9090"}, &out, nil))
+
+	got := out.String()
+	assert.Contains(t, got, "$ kind create cluster --name maas-demo")
+	assert.Contains(t, got, "$ helm upgrade -i kuadrant-operator kuadrant/kuadrant-operator")
+	assert.Contains(t, got, "$ kubectl patch authpolicy maas-api-auth-policy -n opendatahub --type merge")
+	assert.Contains(t, got, "export PREMIUM_KEY=<premium key>")
+	assert.Contains(t, got, "curl -s http://localhost:9090/llama/v1/chat/completions")
+	free := got[strings.Index(got, "Tier free"):strings.Index(got, "Tier premium")]
+	assert.NotContains(t, free, "/llama/", "the free tier has no access to llama")
+}
+
+func TestDemoUp_ReusesCluster(t *testing.T) {
+	r := &recordingRunner{stdin: map[string][]byte{}}
+	cfg := defaultDemoConfig()
+	cfg.ClusterName = "maas-demo"
+	cfg.RepoRoot = "../../.."
+	cfg.DryRun = true // skips preflight and key minting
+	d := &demo{cfg: cfg, r: r, out: io.Discard}
+
+	require.NoError(t, d.up(context.Background()))
+	assert.NotContains(t, r.commands, "kind create cluster --name maas-demo --wait 0s")
+	assert.Contains(t, r.commands, "kubectl config use-context kind-maas-demo")
+	assert.Contains(t, r.commands, "kubectl wait --for=jsonpath={.status.phase}=Active maassubscription --all -n models-as-a-service --timeout=0s")
+
+	var patch string
+	for _, cmd := range r.commands {
+		if strings.HasPrefix(cmd, "kubectl patch authpolicy") {
+			patch = cmd
+		}
+	}
+	assert.Contains(t, patch, "http://mock-oidc.maas-demo.svc.cluster.local:8080/demo")
+	assert.NotContains(t, patch, "__OIDC_")
+}
+
+func TestDemoDown(t *testing.T) {
+	r := &recordingRunner{stdin: map[string][]byte{}}
+	require.NoError(t, run(context.Background(), []string{"demo", "down", "--name", "mine"}, io.Discard, r))
+	assert.Equal(t, []string{"kind delete cluster --name mine"}, r.commands)
+}
+
+func TestMintAPIKey(t *testing.T) {
+	oidc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "/demo/token", r.URL.Path)
+		assert.Equal(t, "mock-oidc.maas-demo.svc.cluster.local:8080", r.Host, "tokens must be issued for the in-cluster issuer")
+		assert.Equal(t, "bob", r.PostForm.Get("scope"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "bob-token"})
+	}))
+	defer oidc.Close()
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/maas-api/v1/api-keys", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer bob-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "premium", body["subscription"])
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]string{"key": "sk-oai-premium"})
+	}))
+	defer gateway.Close()
+
+	ctx := context.Background()
+	cfg := defaultDemoConfig()
+	token, err := fetchToken(ctx, http.DefaultClient, oidc.URL, cfg.oidcHost(), cfg.OIDCIssuerID, cfg.OIDCClientID, "bob")
+	require.NoError(t, err)
+	key, err := createAPIKey(ctx, http.DefaultClient, gateway.URL, token, "demo-bob", "premium")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-oai-premium", key)
+
+	_, err = createAPIKey(ctx, http.DefaultClient, gateway.URL, "wrong", "demo-bob", "premium")
+	require.ErrorContains(t, err, "returned 401")
+}
+
+func TestSplitImage(t *testing.T) {
+	for image, want := range map[string][2]string{
+		"quay.io/opendatahub/maas-api:v1": {"quay.io/opendatahub/maas-api", "v1"},
+		"localhost:5000/maas-api":         {"localhost:5000/maas-api", "latest"},
+		"maas-api":                        {"maas-api", "latest"},
+	} {
+		name, tag := splitImage(image)
+		assert.Equal(t, want, [2]string{name, tag}, image)
+	}
+}
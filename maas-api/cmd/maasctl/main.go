@@ -0,0 +1,88 @@
+// Command maasctl manages MaaS environments and talks to maas-api for model consumers and admins.
+//
+//	maasctl demo up                    # kind cluster with the full stack, mock models, tiers and API keys
+//	maasctl demo down                  # delete the demo cluster
+//	maasctl models --tier premium      # models available to a tier
+//	maasctl access llm/granite         # whether you may call a model, and why not
+//	maasctl tokens create --expires-in 30m
+//	maasctl usage                      # your rate limits and requests in progress
+//	maasctl admin tiers apply -f tier.yaml
+//	maasctl admin catalog
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if err := run(ctx, os.Args[1:], os.Stdout, execRunner{log: os.Stderr}); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer, r runner) error {
+	root := newRootCommand(out, r)
+	root.SetArgs(args)
+	return root.ExecuteContext(ctx)
+}
+
+func newRootCommand(out io.Writer, r runner) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "maasctl",
+		Short:         "Manage MaaS environments and use maas-api from the command line",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.SetOut(out)
+	root.SetErr(out)
+
+	opts := &apiOptions{}
+	opts.addFlags(root)
+
+	root.AddCommand(
+		newDemoCommand(out, r),
+		newModelsCommand(opts, out),
+		newAccessCommand(opts, out),
+		newTokensCommand(opts, out),
+		newUsageCommand(opts, out),
+		newAdminCommand(opts, out),
+	)
+	return root
+}
+
+// newDemoCommand wraps the demo commands, which parse their own flags.
+func newDemoCommand(out io.Writer, r runner) *cobra.Command {
+	demo := &cobra.Command{
+		Use:   "demo",
+		Short: "Create or delete a local kind demo environment",
+	}
+	demo.AddCommand(
+		&cobra.Command{
+			Use:                "up [flags]",
+			Short:              "Create a kind cluster running MaaS with mock models, tiers and sample API keys",
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runDemoUp(cmd.Context(), args, out, r)
+			},
+		},
+		&cobra.Command{
+			Use:                "down [flags]",
+			Short:              "Delete the demo cluster",
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runDemoDown(cmd.Context(), args, out, r)
+			},
+		},
+	)
+	return demo
+}
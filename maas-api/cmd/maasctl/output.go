@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func newTable(out io.Writer, header string) *tabwriter.Writer {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, header)
+	return w
+}
+
+func printModels(out io.Writer, output string, list []client.Model) error {
+	if output == outputJSON {
+		return printJSON(out, list)
+	}
+	w := newTable(out, "NAME\tOWNER\tKIND\tREADY\tURL")
+	for _, m := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", m.ID, m.OwnedBy, orNone(m.Kind), m.Ready, orNone(m.URL))
+	}
+	return w.Flush()
+}
+
+func printAccess(out io.Writer, output string, d *client.AccessDecision) error {
+	if output == outputJSON {
+		return printJSON(out, d)
+	}
+	w := newTable(out, "MODEL\tALLOWED\tSUBSCRIPTION\tREASON\tMESSAGE")
+	fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\n", orNone(d.Model), d.Allowed, orNone(d.Subscription), orNone(d.Reason), orNone(d.Message))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !d.Allowed && len(d.AllowedTiers) > 0 {
+		fmt.Fprintf(out, "\ntiers that include this model: %s\n", strings.Join(d.AllowedTiers, ", "))
+	}
+	return nil
+}
+
+func printCreatedToken(out io.Writer, output string, t *client.CreatedToken) error {
+	if output == outputJSON {
+		return printJSON(out, t)
+	}
+	expires := ""
+	if t.ExpiresAt != nil {
+		expires = *t.ExpiresAt
+	}
+	w := newTable(out, "ID\tNAME\tSUBSCRIPTION\tMODELS\tEXPIRES")
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.ID, t.Name, orNone(t.Subscription), orAll(t.Models), orNone(expires))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\ntoken (shown only once): %s\n", t.Key)
+	return nil
+}
+
+func printTokens(out io.Writer, output string, list []client.Token) error {
+	if output == outputJSON {
+		if list == nil {
+			list = []client.Token{}
+		}
+		return printJSON(out, list)
+	}
+	w := newTable(out, "ID\tNAME\tSUBSCRIPTION\tMODELS\tSTATUS\tEXPIRES")
+	for _, t := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Name, orNone(t.Subscription), orAll(t.Models), t.Status, orNone(t.ExpirationDate))
+	}
+	return w.Flush()
+}
+
+func printLimits(out io.Writer, output string, list []client.Limit) error {
+	if output == outputJSON {
+		if list == nil {
+			list = []client.Limit{}
+		}
+		return printJSON(out, list)
+	}
+	w := newTable(out, "SUBSCRIPTION\tMODEL\tTOKEN LIMITS\tREQUEST LIMITS\tIN FLIGHT\tSOURCE")
+	for _, l := range list {
+		tokens := make([]string, 0, len(l.TokenRateLimits))
+		for _, r := range l.TokenRateLimits {
+			tokens = append(tokens, fmt.Sprintf("%d/%s", r.Limit, r.Window))
+		}
+		requests := make([]string, 0, len(l.RequestRateLimits))
+		for _, r := range l.RequestRateLimits {
+			requests = append(requests, fmt.Sprintf("%d/%s", r.Limit, r.Window))
+		}
+		inFlight := ""
+		if l.MaxConcurrentRequests > 0 && l.InFlight != nil {
+			inFlight = fmt.Sprintf("%d/%d", *l.InFlight, l.MaxConcurrentRequests)
+		}
+		source := l.Source
+		if l.Override != "" {
+			source += " (" + l.Override + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", l.Subscription, l.Model, orNone(strings.Join(tokens, ",")),
+			orNone(strings.Join(requests, ",")), orNone(inFlight), source)
+	}
+	return w.Flush()
+}
+
+func printTiers(out io.Writer, output string, list []client.Tier) error {
+	if output == outputJSON {
+		if list == nil {
+			list = []client.Tier{}
+		}
+		return printJSON(out, list)
+	}
+	w := newTable(out, "NAME\tPRIORITY\tGROUPS\tMODELS\tPHASE")
+	for _, t := range list {
+		printTierRow(w, t)
+	}
+	return w.Flush()
+}
+
+func printTier(out io.Writer, output string, t *client.Tier) error {
+	if output == outputJSON {
+		return printJSON(out, t)
+	}
+	w := newTable(out, "NAME\tPRIORITY\tGROUPS\tMODELS\tPHASE")
+	printTierRow(w, *t)
+	return w.Flush()
+}
+
+func printTierRow(w io.Writer, t client.Tier) {
+	names := make([]string, 0, len(t.Models))
+	for _, m := range t.Models {
+		names = append(names, m.Namespace+"/"+m.Name)
+	}
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", t.Name, t.Priority, orNone(strings.Join(t.Owner.Groups, ",")),
+		orNone(strings.Join(names, ",")), orNone(t.Phase))
+}
+
+// printCatalog prints the catalog's models; -o json prints the whole catalog, with the summary and
+// the coverage of each tier.
+func printCatalog(out io.Writer, output string, catalog map[string]any) error {
+	if output == outputJSON {
+		return printJSON(out, catalog)
+	}
+	w := newTable(out, "MODEL\tKIND\tPHASE\tENDPOINT\tTIERS")
+	models, _ := catalog["models"].([]any)
+	for _, item := range models {
+		m, _ := item.(map[string]any)
+		var tiers []string
+		if list, ok := m["tiers"].([]any); ok {
+			for _, t := range list {
+				tiers = append(tiers, fmt.Sprint(t))
+			}
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\t%s\n", str(m["namespace"]), str(m["name"]), orNone(str(m["kind"])),
+			orNone(str(m["phase"])), orNone(str(m["endpoint"])), orNone(strings.Join(tiers, ",")))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if uncovered, ok := catalog["uncoveredModels"].(float64); ok && uncovered > 0 {
+		fmt.Fprintf(out, "\n%s model(s) are in no tier\n", strconv.FormatFloat(uncovered, 'f', 0, 64))
+	}
+	return nil
+}
+
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// orAll shows the models a token is limited to, or that it may call all of its subscription's.
+func orAll(models []string) string {
+	if len(models) == 0 {
+		return "<all>"
+	}
+	return strings.Join(models, ",")
+}
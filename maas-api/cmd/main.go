@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,24 +11,75 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apiversion"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/clientip"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/clock"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/concurrency"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/contextwindow"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/dependency"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extproc"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/federation"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/fips"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/hooks"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/identity"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/janitor"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/kms"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/migration"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/prewarm"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/readonly"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/retention"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signing"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/throttle"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/upstream"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/warmstart"
+)
+
+// Set at build time through -ldflags "-X main.version=... -X main.commit=... -X main.buildTime=...".
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
 )
 
 func main() {
-	if err := serve(); err != nil {
+	run := serve
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			run = func() error { return runMigrate(os.Args[2:]) }
+		case "sandbox":
+			run = func() error { return runSandbox(os.Args[2:]) }
+		}
+	}
+	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
@@ -47,11 +99,24 @@ func serve() error {
 
 	cfg.PrintDeprecationWarnings(log)
 
+	cryptoStatus, err := fips.Verify(cfg.FIPSRequired)
+	if err != nil {
+		return fmt.Errorf("crypto startup check failed: %w", err)
+	}
+	log.Info("Crypto module", "backend", cryptoStatus.Backend, "fipsEnabled", cryptoStatus.Enabled, "fipsRequired", cryptoStatus.Required)
+
 	// Create cluster config early to load database URL from secret
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	cluster, err := config.NewClusterConfig(cfg.Namespace, cfg.MaaSSubscriptionNamespace, constant.DefaultResyncPeriod)
+	modelScope, err := models.ParseScope(cfg.ModelNamespaces, cfg.ModelLabelSelector)
+	if err != nil {
+		return fmt.Errorf("configuration validation failed: MODEL_LABEL_SELECTOR is invalid: %w", err)
+	}
+	modelScope.Shards, modelScope.Shard = cfg.ModelShards, cfg.ModelShard
+	log.Info("Model scope", "scope", modelScope.String())
+
+	cluster, err := config.NewClusterConfig(cfg.Namespace, cfg.MaaSSubscriptionNamespace, constant.DefaultResyncPeriod, modelScope)
 	if err != nil {
 		return fmt.Errorf("failed to create cluster config: %w", err)
 	}
@@ -66,12 +131,45 @@ func serve() error {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	// Federated clusters are merged into the model lister before anything uses it.
+	remotes, _ := federation.ParseRemotes(cfg.FederationKubeconfigs, cfg.FederationEndpoints) // checked by cfg.Validate
+	if len(remotes) > 0 {
+		if err := cluster.Federate(log, cfg.ClusterName, remotes, cfg.FederationToken, cfg.FederationRefreshInterval); err != nil {
+			return fmt.Errorf("failed to federate models: %w", err)
+		}
+		log.Info("Model federation enabled", "cluster", cfg.ClusterName, "remotes", len(remotes), "refreshInterval", cfg.FederationRefreshInterval)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingEndpoint, cfg.TracingSamplingPercentage, version,
+		models.TracingResolver(cluster.MaaSModelRefLister))
+	if err != nil {
+		return fmt.Errorf("failed to configure tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Error("Failed to flush spans", "error", err)
+		}
+	}()
+	if cfg.TracingEndpoint != "" {
+		log.Info("Tracing enabled", "endpoint", cfg.TracingEndpoint, "samplingPercentage", cfg.TracingSamplingPercentage)
+	}
+
 	gin.SetMode(gin.ReleaseMode)
 	if cfg.DebugMode {
 		gin.SetMode(gin.DebugMode)
 	}
 
 	router := gin.Default()
+	router.Use(tracing.Middleware())
+	// Client IPs come from the clientip resolver, which rewrites RemoteAddr; gin itself trusts no proxy.
+	if err := router.SetTrustedProxies(nil); err != nil {
+		return fmt.Errorf("failed to configure trusted proxies: %w", err)
+	}
+	clientIPs, err := clientip.NewResolver(cfg.TrustedProxies, cfg.ForwardedHeader)
+	if err != nil {
+		return fmt.Errorf("failed to configure trusted proxies: %w", err)
+	}
+	router.Use(clientIPs.Middleware())
 	if cfg.DebugMode {
 		log.Warn("Debug CORS policy active: allowing localhost origins only")
 		router.Use(cors.New(debugCORSConfig()))
@@ -79,7 +177,7 @@ func serve() error {
 
 	router.OPTIONS("/*path", func(c *gin.Context) { c.Status(204) })
 
-	store, err := initStore(ctx, log, cfg)
+	store, db, err := initStore(ctx, log, cfg)
 	if err != nil {
 		return fmt.Errorf("failed to initialize token store: %w", err)
 	}
@@ -89,9 +187,27 @@ func serve() error {
 		}
 	}()
 
-	if err = registerHandlers(ctx, log, router, cfg, cluster, store); err != nil {
+	instance, _ := os.Hostname()
+	warm := warmstart.New(log, warmstart.NewPostgresStore(db), instance, cfg.WarmRestartMaxAge)
+	// The metrics listener is not behind the gateway; it also serves the pre-warm endpoints.
+	metricsRouter := gin.New()
+	metricsRouter.Use(gin.Recovery())
+	metricsRouter.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	if err = registerHandlers(ctx, log, router, metricsRouter, cfg, cluster, store, usage.NewPostgresStore(db), audit.NewPostgresStore(db), warm, newBuildInfo(cfg, cryptoStatus)); err != nil {
 		return fmt.Errorf("failed to register handlers: %w", err)
 	}
+	// Restore before serving, so the first requests already see the budgets and nonces of the
+	// replicas that stopped. A failure only costs the warm start.
+	if err := warm.Restore(ctx); err != nil {
+		log.Warn("Starting without warm restart snapshot", "error", err)
+	}
+	go warm.Run(ctx)
+
+	if cfg.MetricsAddress != "" {
+		if err := startMetrics(ctx, log, cfg.MetricsAddress, metricsRouter); err != nil {
+			return err
+		}
+	}
 
 	srv, err := newServer(cfg, router)
 	if err != nil {
@@ -123,30 +239,296 @@ func serve() error {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
+	// Requests have drained, so the snapshot holds all the usage this replica was told about.
+	if err := warm.Save(shutdownCtx); err != nil {
+		log.Error("Failed to save warm restart snapshot", "error", err)
+	}
 
 	log.Info("Server exited gracefully")
 	return nil
 }
 
-// initStore creates the PostgreSQL store for API key management.
+// newSignatureVerifier loads the signing secrets and picks the replay cache: Redis when configured,
+// so replicas share nonces, and this replica's memory otherwise, carried across restarts by warm.
+func newSignatureVerifier(cfg *config.Config, warm *warmstart.Manager) (*signing.Verifier, error) {
+	secrets, err := signing.LoadSecrets(cfg.RequestSigningSecretsFile)
+	if err != nil {
+		return nil, err
+	}
+	var cache signing.ReplayCache
+	if cfg.RequestSigningRedisURL != "" {
+		if cache, err = signing.NewRedisCache(cfg.RequestSigningRedisURL); err != nil {
+			return nil, err
+		}
+	} else {
+		memoryCache := signing.NewMemoryCache(0)
+		warm.Add("nonces", memoryCache)
+		cache = memoryCache
+	}
+	return signing.NewVerifier(secrets, cache, cfg.RequestSigningMaxSkew), nil
+}
+
+// newBudgetTracker creates the token budget tracker, counting in Redis when QUOTA_REDIS_URL is
+// set and in memory otherwise, carried across restarts by warm.
+func newBudgetTracker(log *logger.Logger, cfg *config.Config, selector *subscription.Selector, warm *warmstart.Manager) (*quota.Tracker, error) {
+	var store quota.Store
+	if cfg.QuotaRedisURL != "" {
+		redisStore, err := quota.NewRedisStore(cfg.QuotaRedisURL)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	} else {
+		memoryStore := quota.NewMemoryStore()
+		warm.Add("budgets", memoryStore)
+		store = memoryStore
+	}
+	return quota.NewTracker(log, store, func(subscriptionKey string) (quota.Budget, bool) {
+		budget := selector.TokenBudget(subscriptionKey)
+		if budget == nil {
+			return quota.Budget{}, false
+		}
+		return quota.Budget{Limit: budget.Limit, Period: budget.Period}, true
+	}), nil
+}
+
+// newRateLimiter creates the rate limiter of RATE_LIMIT_BACKEND=redis, counting in QUOTA_REDIS_URL
+// against the effective limits of each subscription.
+func newRateLimiter(cfg *config.Config, selector *subscription.Selector) (*quota.RateLimiter, error) {
+	store, err := quota.NewRedisStore(cfg.QuotaRedisURL)
+	if err != nil {
+		return nil, err
+	}
+	return quota.NewRateLimiter(store, func(subscriptionKey string) (requests, tokens []quota.Rate) {
+		tokenLimits, requestLimits := selector.EffectiveRateLimits(subscriptionKey)
+		for _, l := range requestLimits {
+			requests = append(requests, quota.Rate{Limit: l.Limit, Window: subscription.ParseWindow(l.Window)})
+		}
+		for _, l := range tokenLimits {
+			tokens = append(tokens, quota.Rate{Limit: l.Limit, Window: subscription.ParseWindow(l.Window)})
+		}
+		return requests, tokens
+	}), nil
+}
+
+// newConcurrencyLimiter creates the limiter of ENFORCE_CONCURRENCY_LIMITS, with the organization
+// caps of ORG_MAX_CONCURRENT_REQUESTS, keeping leases in CONCURRENCY_REDIS_URL when set and in
+// memory otherwise.
+func newConcurrencyLimiter(cfg *config.Config, selector *subscription.Selector) (*concurrency.Limiter, error) {
+	var store concurrency.Store = concurrency.NewMemoryStore()
+	if cfg.ConcurrencyRedisURL != "" {
+		redisStore, err := concurrency.NewRedisStore(cfg.ConcurrencyRedisURL)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	}
+	limiter := concurrency.NewLimiter(store, selector.MaxConcurrentRequests, cfg.ConcurrencyLeaseTTL)
+	if cfg.OrgMaxConcurrentRequests != "" {
+		orgLimits, err := concurrency.ParseOrganizationLimits(cfg.OrgMaxConcurrentRequests)
+		if err != nil {
+			return nil, err
+		}
+		limiter.SetOrganizationLimits(selector.OrganizationOf, orgLimits)
+	}
+	return limiter, nil
+}
+
+// newDependencyChecker builds the readiness checks listed in READY_CHECKS, or returns nil for none.
+func newDependencyChecker(cfg *config.Config, cluster *config.ClusterConfig) *dependency.Checker {
+	checks := make(map[string]dependency.Check)
+	for name := range strings.SplitSeq(cfg.ReadyChecks, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case dependency.Gateway:
+			checks[name] = dependency.GatewayCheck(cfg.ReadyCheckGatewayURL)
+		case dependency.Authorino:
+			checks[name] = dependency.AuthorinoCheck(cluster.DynamicClient, cfg.ReadyCheckAuthConfigNamespace)
+		case dependency.Limitador:
+			checks[name] = dependency.LimitadorCheck(cfg.LimitadorURL)
+		}
+	}
+	return dependency.NewChecker(checks)
+}
+
+// initStore creates the PostgreSQL store for API key management and returns it with its
+// connection pool, which other stores share.
 // DBConnectionURL is validated in cfg.Validate() before this is called.
 //
 //nolint:ireturn // Returns MetadataStore interface by design.
-func initStore(ctx context.Context, log *logger.Logger, cfg *config.Config) (api_keys.MetadataStore, error) {
+func initStore(ctx context.Context, log *logger.Logger, cfg *config.Config) (api_keys.MetadataStore, *sql.DB, error) {
 	log.Info("Connecting to PostgreSQL database...")
-	return api_keys.NewPostgresStoreFromURL(ctx, log, cfg.DBConnectionURL)
+	var store *api_keys.PostgresStore
+	if cfg.MigrateOnStartup {
+		var err error
+		if store, err = api_keys.NewPostgresStoreFromURL(ctx, log, cfg.DBConnectionURL); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		db, err := api_keys.OpenPostgres(ctx, cfg.DBConnectionURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := migration.NewRunner(log, api_keys.NewSchemaMigration(db, log)).Verify(ctx); err != nil {
+			db.Close()
+			return nil, nil, err
+		}
+		store = api_keys.NewPostgresStore(db, log)
+	}
+
+	provider, err := kms.NewProvider(cfg.KMS)
+	if err != nil {
+		store.Close()
+		return nil, nil, fmt.Errorf("failed to configure KMS: %w", err)
+	}
+	if provider == nil {
+		return store, store.DB(), nil
+	}
+	log.Info("Encrypting stored API key descriptions", "kms", provider.Name())
+	encrypted := api_keys.NewEncryptedStore(store, kms.NewEnvelope(provider))
+	if cfg.KMSRewrap && cfg.ReadOnly {
+		log.Warn("Skipping rewrap of stored API key descriptions in read-only mode")
+	} else if cfg.KMSRewrap {
+		go func() {
+			count, err := encrypted.Rewrap(ctx)
+			if err != nil {
+				log.Error("Failed to rewrap stored API key descriptions", "rewrapped", count, "error", err)
+				return
+			}
+			log.Info("Rewrapped stored API key descriptions", "count", count)
+		}()
+	}
+	return encrypted, store.DB(), nil
+}
+
+// modelSubscriptionsV1 matches models by name only, so same-named models in different namespaces
+// were indistinguishable; v2 takes the namespace.
+var modelSubscriptionsV1 = apiversion.DeprecatedRoute{
+	Method:    http.MethodGet,
+	Path:      "/v1/model/{model-id}/subscriptions",
+	Since:     time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+	Sunset:    time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+	Successor: "/v2/models/{namespace}/{name}/subscriptions",
+}
+
+// newBuildInfo reports the ldflags build variables, falling back to the VCS revision Go embeds,
+// and the features cfg enables.
+func newBuildInfo(cfg *config.Config, crypto fips.Status) handlers.BuildInfo {
+	info := handlers.BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+		Crypto:    crypto,
+		AuthMode:  cfg.AuthMode(),
+		Features:  cfg.Features(),
+		APICompatibility: handlers.APICompatibility{
+			API:  handlers.APIVersion,
+			CRDs: models.GVR().GroupVersion().String(),
+		},
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = s.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	return info
 }
 
-func registerHandlers(ctx context.Context, log *logger.Logger, router *gin.Engine, cfg *config.Config, cluster *config.ClusterConfig, store api_keys.MetadataStore) error {
+func registerHandlers(
+	ctx context.Context, log *logger.Logger, router, metricsRouter *gin.Engine, cfg *config.Config, cluster *config.ClusterConfig, store api_keys.MetadataStore,
+	usageStore usage.Store, auditStore audit.Store, warm *warmstart.Manager, buildInfo handlers.BuildInfo,
+) error {
 	router.GET("/health", handlers.NewHealthHandler().HealthCheck)
+	readiness := handlers.NewReadinessHandler(cluster.CachesSynced)
+	if checker := newDependencyChecker(cfg, cluster); checker != nil {
+		readiness.SetDependencyChecker(checker)
+		log.Info("Readiness checks dependencies", "checks", cfg.ReadyChecks)
+	}
+	router.GET("/ready", readiness.ReadinessCheck)
+
+	modelNotFound := models.NewNotFoundCache(cfg.ModelNotFoundTTL)
+	if modelNotFound != nil {
+		if err := cluster.AddMaaSModelRefHandler(modelNotFound.EventHandler()); err != nil {
+			return err
+		}
+	}
+
+	tierCatalog := subscription.NewCatalog(log, cluster.MaaSSubscriptionLister)
+	if err := cluster.AddMaaSSubscriptionHandler(tierCatalog.EventHandler()); err != nil {
+		return err
+	}
+
+	decisions := subscription.NewDecisionCache(cfg.DecisionCacheTTL)
+	if decisions != nil {
+		if err := cluster.AddMaaSSubscriptionHandler(decisions.EventHandler()); err != nil {
+			return err
+		}
+		if err := cluster.AddMaaSModelRefHandler(decisions.EventHandler()); err != nil {
+			return err
+		}
+		if err := cluster.AddMaaSRateLimitOverrideHandler(decisions.EventHandler()); err != nil {
+			return err
+		}
+	}
 
 	if !cluster.StartAndWaitForSync(ctx.Done()) {
 		return errors.New("failed to sync informer caches")
 	}
 
+	readOnly := readonly.New(log, cfg.ReadOnly, cfg.ReadOnlyRetryAfter)
+	if cfg.ReadOnly {
+		log.Warn("Read-only mode on: mutations are rejected")
+	}
+	go readOnly.Watch(ctx, cluster.ClientSet, cfg.Namespace, constant.ReadOnlyPollInterval)
+	// mutation guards routes that write to the API key database or to cluster resources.
+	mutation := readOnly.Middleware()
+
 	v1Routes := router.Group("/v1")
+	v1Routes.GET("/buildinfo", handlers.NewBuildInfoHandler(buildInfo).GetBuildInfo)
+	v2Routes := router.Group("/v2")
+	router.GET("/versions", apiversion.NewHandler(apiversion.Discovery{
+		Current: "v1",
+		Versions: []apiversion.Version{
+			{Version: "v1", Path: "/v1", Status: apiversion.StatusStable},
+			{Version: "v2", Path: "/v2", Status: apiversion.StatusPreview},
+		},
+		Deprecated: []apiversion.DeprecatedRoute{modelSubscriptionsV1},
+	}).GetVersions)
+
+	var skew *clock.Skew
+	if cfg.ClockSkewThreshold > 0 {
+		probe, err := clock.APIServerProbe(cluster.RestConfig)
+		if err != nil {
+			return fmt.Errorf("failed to configure clock skew check: %w", err)
+		}
+		skew = clock.NewSkew(log, probe, cfg.ClockSkewThreshold)
+		warm.Add("clock", skew)
+		go skew.Run(ctx, constant.ClockSkewCheckInterval)
+	}
 
 	subscriptionSelector := subscription.NewSelector(log, cluster.MaaSSubscriptionLister)
+	subscriptionSelector.SetClock(skew.Now)
+	subscriptionSelector.SetAllowMultiple(cfg.AllowMultiSubscription)
+	subscriptionSelector.SetTieBreak(cfg.MultiSubscriptionTieBreak)
+	subscriptionSelector.SetLineageResolver(models.LineageResolver(cluster.MaaSModelRefLister))
+	subscriptionSelector.SetSoftDeletedResolver(models.SoftDeletedResolver(cluster.MaaSModelRefLister))
+	subscriptionSelector.SetMaintenanceResolver(models.MaintenanceResolver(cluster.MaaSModelRefLister, cfg.MaintenanceRetryAfter))
+	subscriptionSelector.SetOverrides(cluster.MaaSRateLimitOverrideLister)
+	// Ignored models are left out of the lister like models out of scope, so both are not found.
+	subscriptionSelector.SetServedResolver(models.ServedResolver(cluster.MaaSModelRefLister))
+	if clusters := cluster.Federation(); clusters != nil {
+		subscriptionSelector.SetClusterResolver(clusters.Cluster)
+	}
+	subscriptionSelector.SetDecisionCache(decisions)
+	subscriptionSelector.SetCatalog(tierCatalog)
 
 	modelManager, err := models.NewManager(log)
 	if err != nil {
@@ -154,31 +536,366 @@ func registerHandlers(ctx context.Context, log *logger.Logger, router *gin.Engin
 	}
 
 	tokenHandler := token.NewHandler(log, cfg.Name)
+	// Callers are identified by the gateway's identity headers, or by a verified JWT when
+	// IDENTITY_JWT_ISSUER is set.
+	extractUser := tokenHandler.ExtractUserInfo()
+	var verifier *identity.Verifier
+	if cfg.Identity.Enabled() {
+		verifier = identity.New(log, cfg.Identity)
+		extractUser = verifier.Require()
+		log.Info("JWT identity enabled", "issuer", cfg.Identity.Issuer, "audience", cfg.Identity.Audience,
+			"tierClaim", cfg.Identity.TierClaim)
+	}
 	modelsHandler := handlers.NewModelsHandler(log, modelManager, subscriptionSelector, cluster.MaaSModelRefLister)
+	meter := metering.New(cfg.MeteringPerUser)
+	reasonLabel, _ := reason.ParseLabel(cfg.MeteringReasonLabel) // checked by cfg.Validate
+	meter.SetReasonLabel(reasonLabel)
+	meter.SetSampleRate(models.SampleRateResolver(cluster.MaaSModelRefLister))
+	if cfg.UsageRemoteWriteURL != "" {
+		instance, _ := os.Hostname()
+		remoteWriter := metering.NewRemoteWriter(log, cfg.UsageRemoteWriteURL, instance, cfg.UsageRemoteWriteInterval)
+		remoteWriter.SetBearerToken(cfg.UsageRemoteWriteToken)
+		remoteWriter.SetTenant(cfg.UsageRemoteWriteTenant)
+		remoteWriter.SetOrganizations(subscriptionSelector.Organizations)
+		remoteWriter.SetClock(skew.Now)
+		log.Info("Pushing usage via remote write", "url", cfg.UsageRemoteWriteURL, "interval", cfg.UsageRemoteWriteInterval)
+		go remoteWriter.Run(ctx)
+	}
 	subscriptionHandler := subscription.NewHandler(log, subscriptionSelector)
+	subscriptionHandler.SetMeter(meter)
+	auditSinks, err := audit.NewSinks(cfg.AuditSinks, cluster.ClientSet, cfg.AuditWebhookURL, auditStore)
+	if err != nil {
+		return fmt.Errorf("failed to configure audit sinks: %w", err)
+	}
+	auditor := audit.New(log, auditSinks...)
+	auditor.SetOrganizations(subscriptionSelector.Organizations)
+	if auditor != nil {
+		log.Info("Audit log enabled", "sinks", cfg.AuditSinks)
+		go auditor.Run(ctx)
+	}
+	subscriptionHandler.SetAuditor(auditor)
+	var quotaWarner *quota.Warner
+	if cfg.QuotaWarningThreshold > 0 {
+		log.Info("Soft quota warnings enabled", "threshold", cfg.QuotaWarningThreshold, "limitador", cfg.LimitadorURL)
+		quotaWarner = quota.NewWarner(log, quota.NewLimitadorSource(cfg.LimitadorURL, cfg.LimitadorNamespace), cfg.QuotaWarningThreshold)
+		subscriptionHandler.SetQuotaWarner(quotaWarner)
+	}
+	var tokenReporter *quota.Warner
+	if cfg.QuotaHeaders {
+		log.Info("Quota response headers enabled", "limitador", cfg.LimitadorURL)
+		// Remaining tokens come from the same Limitador counters as warnings, whatever the threshold.
+		tokenReporter = quotaWarner
+		if tokenReporter == nil {
+			tokenReporter = quota.NewWarner(log, quota.NewLimitadorSource(cfg.LimitadorURL, cfg.LimitadorNamespace), 0)
+		}
+		subscriptionHandler.SetTokenReporter(tokenReporter)
+	}
+	var budgetTracker *quota.Tracker
+	if cfg.UsageIngestToken != "" {
+		if budgetTracker, err = newBudgetTracker(log, cfg, subscriptionSelector, warm); err != nil {
+			return fmt.Errorf("failed to configure token budgets: %w", err)
+		}
+		budgetTracker.SetClock(skew.Now)
+		budgetTracker.SetDailyCaps(subscriptionSelector.MaxTokensPerDay, subscription.NewQuotaMarker(log, cluster.DynamicClient).CapReached)
+		subscriptionHandler.SetBudgetChecker(budgetTracker)
+		log.Info("Token budgets enabled", "sharedCounters", cfg.QuotaRedisURL != "")
+	}
+	var rateLimiter *quota.RateLimiter
+	if cfg.RateLimitBackend == quota.RateLimitBackendRedis {
+		if rateLimiter, err = newRateLimiter(cfg, subscriptionSelector); err != nil {
+			return fmt.Errorf("failed to configure rate limits: %w", err)
+		}
+		rateLimiter.SetClock(skew.Now)
+		log.Info("Subscription rate limits enforced by maas-api", "backend", cfg.RateLimitBackend)
+	}
 
 	apiKeyService := api_keys.NewServiceWithLogger(store, cfg, subscriptionSelector, log)
 	apiKeyHandler := api_keys.NewHandler(log, apiKeyService, cluster.AdminChecker)
+	capacityHandler := handlers.NewCapacityHandler(log, cluster.MaaSModelRefLister, subscriptionSelector, cluster.AdminChecker)
+	catalogHandler := handlers.NewCatalogHandler(log, cluster.DynamicClient, cluster.AdminChecker)
+	gatewayTransport, err := handlers.NewGatewayTransport(cfg.GatewayCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to configure gateway TLS: %w", err)
+	}
+	diagnoseHandler := handlers.NewDiagnoseHandler(log, cluster.DynamicClient, cluster.MaaSModelRefLister, cluster.AdminChecker)
+	diagnoseHandler.SetClock(skew.Now)
+	diagnoseHandler.SetTransport(gatewayTransport)
+	namespaceQuotaHandler := handlers.NewNamespaceQuotaHandler(log, cluster.ClientSet, cluster.MaaSModelRefLister, subscriptionSelector, cluster.AdminChecker)
+	fallbackHandler := handlers.NewFallbackHandler(log, cluster.MaaSModelRefLister, subscriptionSelector)
+	fallbackHandler.SetTransport(gatewayTransport)
+	fallbackHandler.SetMeter(meter)
+	fallbackHandler.SetNotFoundCache(modelNotFound)
+	publishHandler := handlers.NewPublishHandler(log, cluster.DynamicClient, cluster.MaaSModelRefLister, cluster.AccessReviewer, cfg.MaaSSubscriptionNamespace)
+	tierHandler := handlers.NewTierHandler(log, cluster.DynamicClient, cluster.AdminChecker, cfg.MaaSSubscriptionNamespace)
+	tierHandler.SetAuditor(auditor)
+
+	authzThrottle, err := throttle.New(log, cfg.AuthzThrottle)
+	if err != nil {
+		return fmt.Errorf("failed to configure authorization throttle: %w", err)
+	}
+	if authzThrottle != nil {
+		log.Info("Authorization throttle enabled", "ratePerSecond", cfg.AuthzThrottle.RatePerSecond,
+			"maxInFlightPerCaller", cfg.AuthzThrottle.MaxInFlightPerCaller, "maxInFlight", cfg.AuthzThrottle.MaxInFlight,
+			"redis", cfg.AuthzThrottle.RedisURL != "")
+	}
+
+	// The evaluator also backs POST /v1/models/authorize/batch, so it is built without ext_authz.
+	evaluator := extauthz.NewServer(log, apiKeyService, subscriptionSelector, cluster.MaaSAuthPolicyLister)
+	evaluator.SetLineageResolver(models.LineageResolver(cluster.MaaSModelRefLister))
+	evaluator.SetModelResolver(modelNotFound.Resolver(models.NamespaceResolver(cluster.MaaSModelRefLister)))
+	evaluator.SetAliasResolver(models.AliasResolver(cluster.MaaSModelRefLister))
+	evaluator.SetRouteResolver(models.NewRouteResolver(cluster.MaaSModelRefLister))
+	evaluator.SetAllowListResolver(models.AllowListResolver(cluster.MaaSModelRefLister))
+	evaluator.SetErrorResponseResolver(models.ErrorResponseResolver(cluster.MaaSModelRefLister))
+	if budgetTracker != nil {
+		evaluator.SetBudgetChecker(budgetTracker)
+	}
+	if cfg.DecisionHooksFile != "" {
+		decisionHooks, err := hooks.Load(log, cfg.DecisionHooksFile)
+		if err != nil {
+			return fmt.Errorf("failed to configure decision hooks: %w", err)
+		}
+		defer decisionHooks.Close()
+		evaluator.SetHooks(decisionHooks)
+		log.Info("Decision hooks enabled", "hooks", decisionHooks.Names())
+	}
+	authorizationRules, err := policy.New(log)
+	if cfg.AuthorizationRulesFile != "" {
+		authorizationRules, err = policy.Load(log, cfg.AuthorizationRulesFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to configure authorization rules: %w", err)
+	}
+	authorizationRules.SetModels(cluster.MaaSModelRefLister)
+	evaluator.SetPolicy(authorizationRules)
+	if cfg.AuthorizationRulesFile != "" {
+		log.Info("Authorization rules enabled", "rules", authorizationRules.Names())
+	}
+	batchAuthzHandler := extauthz.NewHandler(log, evaluator)
+	reasonVerbosity, _ := reason.ParseVerbosity(cfg.AuthzReasonVerbosity) // checked by cfg.Validate
+	batchAuthzHandler.SetReasonVerbosity(reasonVerbosity, subscriptionSelector)
+	batchAuthzHandler.SetAuditor(auditor)
+	if cfg.SignedURLSecret != "" {
+		signer, err := signedurl.NewSigner([]byte(cfg.SignedURLSecret), cfg.SignedURLMaxTTL)
+		if err != nil {
+			return fmt.Errorf("failed to configure signed URLs: %w", err)
+		}
+		signer.SetClock(skew.Now)
+		evaluator.SetSignedURLSigner(signer)
+		apiKeyHandler.SetSignedURLSigner(signer)
+		log.Info("Signed URLs enabled", "maxTTL", cfg.SignedURLMaxTTL.String())
+	}
+
+	if cfg.ExtAuthzAddress != "" {
+		evaluator.SetMeter(meter)
+		evaluator.SetAuditor(auditor)
+		modelSources, err := extauthz.ParseModelSources(cfg.ExtAuthzModelSources)
+		if err != nil {
+			return fmt.Errorf("failed to configure ext_authz model sources: %w", err)
+		}
+		evaluator.SetModelSources(modelSources)
+		apiSuffixes, err := extauthz.ParseAPISuffixes(cfg.APISuffixes)
+		if err != nil {
+			return fmt.Errorf("failed to configure ext_authz API suffixes: %w", err)
+		}
+		evaluator.SetAPISuffixes(apiSuffixes)
+		if cfg.EnforceContextWindow {
+			var tokenizer *contextwindow.Tokenizer
+			if cfg.TokenizerURL != "" {
+				tokenizer = contextwindow.NewTokenizer(cfg.TokenizerURL, cfg.TokenizerTimeout)
+			}
+			evaluator.SetContextWindowChecker(contextwindow.NewChecker(log, tokenizer), models.ContextWindowResolver(cluster.MaaSModelRefLister))
+			log.Info("Context window enforcement enabled", "tokenizer", cfg.TokenizerURL != "")
+		}
+		if authzThrottle != nil {
+			clientIPs, err := clientip.NewResolver(cfg.TrustedProxies, cfg.ForwardedHeader)
+			if err != nil {
+				return fmt.Errorf("failed to configure trusted proxies: %w", err)
+			}
+			evaluator.SetThrottle(authzThrottle, clientIPs)
+		}
+		if quotaWarner != nil {
+			evaluator.SetQuotaWarner(quotaWarner)
+		}
+		if tokenReporter != nil {
+			evaluator.SetTokenReporter(tokenReporter)
+		}
+		if rateLimiter != nil {
+			evaluator.SetRateLimiter(rateLimiter)
+		}
+		if cfg.RequestSigningSecretsFile != "" {
+			verifier, err := newSignatureVerifier(cfg, warm)
+			if err != nil {
+				return fmt.Errorf("failed to configure request signing: %w", err)
+			}
+			verifier.SetClock(skew.Now)
+			evaluator.SetSignatureVerifier(verifier)
+			log.Info("Request signing enabled", "users", verifier.Users(), "maxSkew", cfg.RequestSigningMaxSkew.String(),
+				"sharedNonces", cfg.RequestSigningRedisURL != "")
+		}
+		var accessLogs *concurrency.AccessLogServer
+		if cfg.EnforceConcurrencyLimits {
+			limiter, err := newConcurrencyLimiter(cfg, subscriptionSelector)
+			if err != nil {
+				return fmt.Errorf("failed to configure concurrency limits: %w", err)
+			}
+			evaluator.SetConcurrencyLimiter(limiter)
+			subscriptionHandler.SetConcurrencyCounter(limiter)
+			accessLogs = concurrency.NewAccessLogServer(log, limiter)
+			log.Info("Concurrency limits enabled", "leaseTTL", cfg.ConcurrencyLeaseTTL.String(), "sharedLeases", cfg.ConcurrencyRedisURL != "",
+				"organizationCaps", cfg.OrgMaxConcurrentRequests != "")
+		}
+		if err := startExtAuthz(ctx, log, cfg, evaluator, accessLogs); err != nil {
+			return err
+		}
+	}
 
-	v1Routes.GET("/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+	if cfg.ExtProcAddress != "" {
+		processor := extproc.NewServer(log, cluster.MaaSModelRefLister,
+			modelNotFound.Resolver(models.NamespaceResolver(cluster.MaaSModelRefLister)), cfg.ExtProcPaths)
+		processor.SetAliasResolver(models.AliasResolver(cluster.MaaSModelRefLister))
+		if err := startExtProc(ctx, log, cfg, processor); err != nil {
+			return err
+		}
+	}
+
+	v1Routes.GET("/models", extractUser, modelsHandler.ListLLMs)
+	v1Routes.POST("/models", mutation, extractUser, publishHandler.Publish)
+	v1Routes.GET("/models/:name/examples", extractUser, modelsHandler.GetExamples)
+	// Pre-flight checks for portals, throttled like the other authorization endpoints
+	batchAuthz := []gin.HandlerFunc{extractUser, batchAuthzHandler.AuthorizeBatch}
+	if authzThrottle != nil {
+		batchAuthz = append([]gin.HandlerFunc{authzThrottle.Middleware()}, batchAuthz...)
+	}
+	v1Routes.POST("/models/authorize/batch", batchAuthz...)
+	accessCheck := []gin.HandlerFunc{extractUser, batchAuthzHandler.CheckAccess}
+	if authzThrottle != nil {
+		accessCheck = append([]gin.HandlerFunc{authzThrottle.Middleware()}, accessCheck...)
+	}
+	v1Routes.GET("/models/:name/access", accessCheck...)
+	v1Routes.POST("/models/:name/signed-urls", mutation, extractUser, batchAuthzHandler.MintSignedURL)
+
+	// Token usage reported by the gateway's quota filter, authenticated with USAGE_INGEST_TOKEN,
+	// and the chargeback reports over it
+	if budgetTracker != nil {
+		usageIngest := quota.NewHandler(log, budgetTracker, cfg.UsageIngestToken)
+		usageIngest.SetLedger(usageStore)
+		usageIngest.SetOrganizationResolver(subscriptionSelector.OrganizationOf)
+		if rateLimiter != nil {
+			usageIngest.SetRateLimiter(rateLimiter)
+		}
+		usageIngest.SetClassResolver(models.ClassResolver(cluster.MaaSModelRefLister))
+		v1Routes.POST("/usage", usageIngest.IngestUsage)
+		usageHandler := handlers.NewUsageHandler(log, usageStore, cluster.AdminChecker)
+		usageHandler.SetClock(skew.Now)
+		usageHandler.SetForecastSource(subscriptionSelector)
+		v1Routes.GET("/usage", extractUser, usageHandler.GetUsage)
+		v1Routes.GET("/usage/forecast", extractUser, usageHandler.GetForecast)
+	}
 
 	// Subscription listing routes
-	v1Routes.GET("/subscriptions", tokenHandler.ExtractUserInfo(), subscriptionHandler.ListSubscriptions)
-	v1Routes.GET("/model/:model-id/subscriptions", tokenHandler.ExtractUserInfo(), subscriptionHandler.ListSubscriptionsForModel)
+	v1Routes.GET("/subscriptions", extractUser, subscriptionHandler.ListSubscriptions)
+	v1Routes.GET("/limits", extractUser, subscriptionHandler.ListLimits)
+	v1Routes.GET("/model/:model-id/subscriptions", apiversion.Deprecate(modelSubscriptionsV1), extractUser, subscriptionHandler.ListSubscriptionsForModel)
+	v2Routes.GET("/models/:namespace/:name/subscriptions", extractUser, subscriptionHandler.ListSubscriptionsForModelRef)
+
+	// Inference with fallback to alternate models on capacity errors
+	v1Routes.POST("/fallback/*path", extractUser, fallbackHandler.Proxy)
+
+	// Admin routes
+	v1Routes.GET("/admin/capacity", extractUser, capacityHandler.GetCapacity)
+	v1Routes.GET("/admin/quotas", extractUser, namespaceQuotaHandler.GetQuotas)
+	v1Routes.GET("/catalog", extractUser, catalogHandler.GetCatalog)
+	adminRoutes := router.Group("/admin/v1")
+	// Wildcards, so the model may be namespace/name
+	adminRoutes.GET("/diagnose/*model", extractUser, diagnoseHandler.Diagnose)
+	adminRoutes.POST("/diagnose-streaming/*model", extractUser, diagnoseHandler.DiagnoseStreaming)
+
+	// Audit log queries, when decisions are kept in the database
+	if slices.Contains(auditSinks, audit.Sink(auditStore)) {
+		auditHandler := handlers.NewAuditHandler(log, auditStore, cluster.AdminChecker)
+		auditHandler.SetClock(skew.Now)
+		adminRoutes.GET("/audit", extractUser, auditHandler.ListAuditRecords)
+	}
+
+	// Tier routes - admin CRUD over MaaSSubscriptions
+	tierRoutes := v1Routes.Group("/tiers", extractUser)
+	tierRoutes.GET("", tierHandler.ListTiers)
+	tierRoutes.POST("", mutation, tierHandler.CreateTier)
+	tierRoutes.GET("/:name", tierHandler.GetTier)
+	tierRoutes.PUT("/:name", mutation, tierHandler.UpdateTier)
+	tierRoutes.DELETE("/:name", mutation, tierHandler.DeleteTier)
 
 	// API Key routes - Complete CRUD for hash-based key architecture
-	apiKeyRoutes := v1Routes.Group("/api-keys", tokenHandler.ExtractUserInfo())
-	apiKeyRoutes.POST("", apiKeyHandler.CreateAPIKey)                  // Create hash-based key
-	apiKeyRoutes.POST("/search", apiKeyHandler.SearchAPIKeys)          // Search keys with filtering, sorting, and pagination
-	apiKeyRoutes.POST("/bulk-revoke", apiKeyHandler.BulkRevokeAPIKeys) // Bulk revoke keys
-	apiKeyRoutes.GET("/:id", apiKeyHandler.GetAPIKey)                  // Get specific key
-	apiKeyRoutes.DELETE("/:id", apiKeyHandler.RevokeAPIKey)            // Revoke specific key
+	apiKeyRoutes := v1Routes.Group("/api-keys", extractUser)
+	apiKeyRoutes.POST("", mutation, apiKeyHandler.CreateAPIKey)                  // Create hash-based key
+	apiKeyRoutes.POST("/search", apiKeyHandler.SearchAPIKeys)                    // Search keys with filtering, sorting, and pagination
+	apiKeyRoutes.POST("/bulk-revoke", mutation, apiKeyHandler.BulkRevokeAPIKeys) // Bulk revoke keys
+	apiKeyRoutes.GET("/:id", apiKeyHandler.GetAPIKey)                            // Get specific key
+	apiKeyRoutes.DELETE("/:id", mutation, apiKeyHandler.RevokeAPIKey)            // Revoke specific key
+
+	// Token routes - short-lived API keys, optionally scoped to models, for notebooks and CI jobs
+	tokenRoutes := v1Routes.Group("/tokens", extractUser)
+	tokenRoutes.POST("", mutation, apiKeyHandler.CreateToken)        // Mint an ephemeral key
+	tokenRoutes.GET("", apiKeyHandler.ListTokens)                    // List the caller's active tokens
+	tokenRoutes.DELETE("/:id", mutation, apiKeyHandler.RevokeAPIKey) // Revoke a token
 
 	// Internal routes (no auth required - called by Authorino / CronJob)
 	internalRoutes := router.Group("/internal/v1")
-	internalRoutes.POST("/api-keys/validate", apiKeyHandler.ValidateAPIKeyHandler)
-	internalRoutes.POST("/api-keys/cleanup", apiKeyHandler.CleanupExpiredEphemeralKeys)
-	internalRoutes.POST("/subscriptions/select", subscriptionHandler.SelectSubscription)
+	internalRoutes.POST("/api-keys/cleanup", mutation, apiKeyHandler.CleanupExpiredEphemeralKeys)
+	// Authorization callbacks, throttled per caller when AuthzThrottle is configured
+	authzRoutes := internalRoutes.Group("")
+	if authzThrottle != nil {
+		authzRoutes.Use(authzThrottle.Middleware())
+	}
+	authzRoutes.POST("/api-keys/validate", apiKeyHandler.ValidateAPIKeyHandler)
+	selectSubscription := []gin.HandlerFunc{subscriptionHandler.SelectSubscription}
+	if verifier != nil {
+		selectSubscription = append([]gin.HandlerFunc{verifier.Optional()}, selectSubscription...)
+	}
+	authzRoutes.POST("/subscriptions/select", selectSubscription...)
+	// Cache pre-warm for the gateway and Authorino as they start, authenticated with PREWARM_TOKEN
+	// and served on the metrics listener only, which the gateway does not route to
+	if cfg.PrewarmToken != "" {
+		warmer := prewarm.New(log, cluster.MaaSModelRefLister, subscriptionSelector)
+		warmer.SetModelResolver(modelNotFound.Resolver(models.NamespaceResolver(cluster.MaaSModelRefLister)))
+		warmer.SetPolicy(authorizationRules)
+		warmer.SetClock(skew.Now)
+		prewarmHandler := prewarm.NewHandler(log, warmer, cfg.PrewarmToken)
+		prewarmRoutes := metricsRouter.Group("/internal/v1/authorize/prewarm")
+		if authzThrottle != nil {
+			prewarmRoutes.Use(authzThrottle.Middleware())
+		}
+		prewarmRoutes.POST("", prewarmHandler.Prewarm)
+		prewarmRoutes.GET("/:id", prewarmHandler.GetJob)
+	}
+	// Provider credentials of ExternalModels for the generated AuthPolicies, authenticated with
+	// UPSTREAM_CREDENTIALS_TOKEN and served on the metrics listener only
+	if cfg.UpstreamCredentialsToken != "" {
+		upstreamHandler := upstream.NewHandler(log, cluster.DynamicClient, cluster.ClientSet, cfg.UpstreamCredentialsToken)
+		metricsRouter.GET("/internal/v1/external-models/:namespace/:name/credential", upstreamHandler.GetCredential)
+	}
+	// Models of this cluster for federating maas-api instances, authenticated with FEDERATION_TOKEN
+	if cfg.FederationToken != "" {
+		federationHandler := federation.NewHandler(log, cluster.LocalMaaSModelRefLister, cfg.ClusterName, cfg.FederationToken)
+		internalRoutes.GET("/federation/models", federationHandler.ListModels)
+	}
+
+	keyJanitor := janitor.New(log, store, cluster.MaaSSubscriptionLister, cfg.JanitorRetention, cfg.JanitorDryRun)
+	dataRetention, _ := retention.ParsePolicy(cfg.DataRetention)                                // checked by cfg.Validate
+	legalHolds, _ := retention.ParseLegalHolds(cfg.LegalHoldOrganizations, cfg.LegalHoldModels) // checked by cfg.Validate
+	keyJanitor.SetRetention(dataRetention, legalHolds)
+	keyJanitor.SetAuditStore(auditStore)
+	keyJanitor.SetUsageStore(usageStore)
+	janitorHandler := janitor.NewHandler(log, keyJanitor, cluster.AdminChecker)
+	adminRoutes.POST("/janitor/run", mutation, extractUser, janitorHandler.Run)
+	adminRoutes.GET("/janitor/report", extractUser, janitorHandler.LastReport)
+	if cfg.JanitorInterval > 0 {
+		log.Info("Janitor enabled", "interval", cfg.JanitorInterval, "retention", cfg.JanitorRetention, "dryRun", cfg.JanitorDryRun,
+			"legalHolds", !legalHolds.Empty())
+		keyJanitor.SetPaused(readOnly.Enabled)
+		keyJanitor.Start(ctx, cfg.JanitorInterval)
+	}
 
 	return nil
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/kms"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/migration"
+)
+
+// runMigrate implements `maas-api migrate [--dry-run]`: it checks every configured migration step,
+// prints what each would change with its rollback notes, and applies them unless --dry-run is set.
+// It reads the same configuration as the server, so it can run as an init container or Job.
+func runMigrate(args []string) error {
+	cfg := config.Load()
+	dryRun := flag.Bool("dry-run", false, "Run the pre-flight checks and print the plan without applying it")
+	if err := flag.CommandLine.Parse(args); err != nil {
+		return err
+	}
+
+	log := logger.New(cfg.DebugMode)
+	defer func() { _ = log.Sync() }()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	restConfig, err := config.LoadRestConfig()
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes config: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+	if err := cfg.LoadDatabaseURL(ctx, clientset); err != nil {
+		return fmt.Errorf("failed to load database URL: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuration validation failed: %w", err)
+	}
+
+	db, err := api_keys.OpenPostgres(ctx, cfg.DBConnectionURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	steps := []migration.Step{api_keys.NewSchemaMigration(db, log)}
+	if cfg.KMSRewrap {
+		provider, err := kms.NewProvider(cfg.KMS)
+		if err != nil {
+			return fmt.Errorf("failed to configure KMS: %w", err)
+		}
+		encrypted := api_keys.NewEncryptedStore(api_keys.NewPostgresStore(db, log), kms.NewEnvelope(provider))
+		steps = append(steps, api_keys.NewRewrapMigration(encrypted))
+	}
+
+	plans, err := migration.NewRunner(log, steps...).Run(ctx, *dryRun)
+	printPlans(os.Stdout, plans, *dryRun)
+	return err
+}
+
+func printPlans(w io.Writer, plans []migration.Plan, dryRun bool) {
+	verb := "applied"
+	if dryRun {
+		verb = "would apply"
+	}
+	for _, plan := range plans {
+		if plan.UpToDate() {
+			fmt.Fprintf(w, "%s: up to date (version %s)\n", plan.Step, plan.Current)
+			continue
+		}
+		if plan.Current != "" || plan.Target != "" {
+			fmt.Fprintf(w, "%s: %s %s -> %s\n", plan.Step, verb, plan.Current, plan.Target)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", plan.Step, verb)
+		}
+		for _, change := range plan.Pending {
+			fmt.Fprintf(w, "  change:   %s\n", change)
+		}
+		for _, note := range plan.Rollback {
+			fmt.Fprintf(w, "  rollback: %s\n", note)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/sandbox"
+)
+
+// runSandbox implements `maas-api sandbox [--address=:8080] [--latency=300ms]`: it serves the mock
+// OpenAI backend that sandbox subscriptions are routed to. maas-controller deploys it; the gateway
+// terminates TLS, so it listens on plain HTTP.
+func runSandbox(args []string) error {
+	fs := flag.NewFlagSet("sandbox", flag.ContinueOnError)
+	address := fs.String("address", ":8080", "Address to serve the sandbox backend on")
+	latency := fs.Duration("latency", 300*time.Millisecond, "Simulated time to the first token")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log := logger.New(*debug)
+	defer func() { _ = log.Sync() }()
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	sandbox.NewServer(log, *latency).Register(router)
+
+	srv := &http.Server{
+		Addr:              *address,
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		// No WriteTimeout: streamed responses last as long as the simulated generation.
+		IdleTimeout:    60 * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("Sandbox backend starting", "address", *address, "latency", latency.String())
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("sandbox backend failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancelShutdown()
+	return srv.Shutdown(shutdownCtx)
+}
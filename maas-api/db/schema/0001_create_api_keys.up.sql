@@ -1,5 +1,6 @@
 -- Schema for API Key Management: 0001_create_api_keys.up.sql
 -- Description: Initial schema for API Key Management with group-based authorization
+-- Rollback: DROP TABLE api_keys. This deletes every API key; restore a backup instead if keys must survive.
 -- Includes: hash-only storage, status tracking, usage tracking, user groups
 
 CREATE TABLE IF NOT EXISTS api_keys (
@@ -1,5 +1,6 @@
 -- Schema for API Key Management: 0002_add_ephemeral_column.up.sql
 -- Description: Add ephemeral column for short-lived programmatic keys
+-- Rollback: DROP INDEX idx_api_keys_ephemeral_expired; ALTER TABLE api_keys DROP COLUMN ephemeral. Older maas-api versions ignore the column, so it can be left in place.
 
 -- Add ephemeral column to api_keys table (idempotent)
 ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS ephemeral BOOLEAN NOT NULL DEFAULT FALSE;
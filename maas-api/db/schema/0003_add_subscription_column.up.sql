@@ -1,5 +1,6 @@
 -- Schema for API Key Management: 0003_add_subscription_column.up.sql
 -- Description: Add subscription column — binds each API key to a MaaSSubscription name at mint time
+-- Rollback: ALTER TABLE api_keys DROP COLUMN subscription. The column is NOT NULL, so maas-api versions from before it cannot create keys until it is dropped.
 
 -- Add subscription column (idempotent). Value is MaaSSubscription metadata.name, resolved when the key is created.
 ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS subscription TEXT NOT NULL;
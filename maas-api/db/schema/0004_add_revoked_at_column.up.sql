@@ -0,0 +1,10 @@
+-- Schema for API Key Management: 0004_add_revoked_at_column.up.sql
+-- Description: Record when a key was revoked so the janitor can apply its retention period
+-- Rollback: DROP INDEX idx_api_keys_subscription_active; ALTER TABLE api_keys DROP COLUMN revoked_at. Older maas-api versions ignore both, so they can be left in place.
+
+-- Add revoked_at column (idempotent). NULL for keys revoked before this migration; the janitor
+-- then falls back to the key's last use or creation time.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+-- Index for the janitor: find active keys bound to a subscription
+CREATE INDEX IF NOT EXISTS idx_api_keys_subscription_active ON api_keys(subscription) WHERE status = 'active';
@@ -0,0 +1,7 @@
+-- Schema for API Key Management: 0005_add_models_column.up.sql
+-- Description: Add models column — optionally scopes an API key to a set of models at mint time
+-- Rollback: ALTER TABLE api_keys DROP COLUMN models. Older maas-api versions ignore the column, so it can be left in place, but keys scoped to models then work for every model of their subscription.
+
+-- Add models column (idempotent). Values are MaaSModelRef references (namespace/name); an empty
+-- array means the key may be used for every model its subscription includes.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS models TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,21 @@
+-- Schema for usage reporting: 0006_create_usage_hourly.up.sql
+-- Description: Hourly token usage ledger behind the GET /v1/usage chargeback reports
+-- Rollback: DROP TABLE usage_hourly. Older maas-api versions never read the table, so it can be left in place; dropping it deletes the usage history.
+
+-- One row per user, model and subscription for each hour, incremented with every usage report.
+-- groups holds the user's groups as of the latest report in the hour; reports by team count
+-- each row once for every group.
+CREATE TABLE IF NOT EXISTS usage_hourly (
+    hour TIMESTAMPTZ NOT NULL,
+    username TEXT NOT NULL,
+    groups TEXT[] NOT NULL DEFAULT '{}',
+    model TEXT NOT NULL,
+    subscription TEXT NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    prompt_tokens BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    total_tokens BIGINT NOT NULL DEFAULT 0,
+
+    -- hour leads the key, so the time range every report selects is an index scan
+    PRIMARY KEY (hour, username, model, subscription)
+);
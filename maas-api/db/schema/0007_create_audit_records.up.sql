@@ -0,0 +1,22 @@
+-- Schema for the audit store: 0007_create_audit_records.up.sql
+-- Description: Authorization decisions behind the GET /admin/v1/audit query API
+-- Rollback: DROP TABLE audit_records. Older maas-api versions never read the table, so it can be left in place; dropping it deletes the stored decisions.
+
+-- One row per authorization decision, written by the store audit sink.
+CREATE TABLE IF NOT EXISTS audit_records (
+    id BIGSERIAL PRIMARY KEY,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    endpoint TEXT NOT NULL,
+    username TEXT NOT NULL DEFAULT '',
+    subscription TEXT NOT NULL DEFAULT '',
+    model TEXT NOT NULL DEFAULT '',
+    path TEXT NOT NULL DEFAULT '',
+    decision TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    reason_category TEXT NOT NULL DEFAULT ''
+);
+
+-- Queries select a time range, newest first, optionally for one user or model.
+CREATE INDEX IF NOT EXISTS idx_audit_records_recorded_at ON audit_records (recorded_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_records_username ON audit_records (username, recorded_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_records_model ON audit_records (model, recorded_at DESC);
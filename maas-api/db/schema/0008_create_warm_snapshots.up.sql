@@ -0,0 +1,11 @@
+-- Schema for warm restarts: 0008_create_warm_snapshots.up.sql
+-- Description: Snapshots of in-memory token budget counters and replay nonces, saved by a stopping replica and claimed by a running one
+-- Rollback: DROP TABLE warm_snapshots. Older maas-api versions never read the table, so it can be left in place; snapshots in it expire on their own.
+
+-- One row per stopped replica; a replica claims a row by deleting it.
+CREATE TABLE IF NOT EXISTS warm_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    instance TEXT NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL,
+    data JSONB NOT NULL
+);
@@ -0,0 +1,9 @@
+-- Schema for legal holds: 0009_add_organization_columns.up.sql
+-- Description: Record the organization of the subscription on audit records and usage hours, so legal holds on an organization keep its records
+-- Rollback: ALTER TABLE audit_records DROP COLUMN organization; ALTER TABLE usage_hourly DROP COLUMN organization. Older maas-api versions ignore the columns, so they can be left in place.
+
+-- The organization ID from the subscription's tokenMetadata when the record was written, '' for a
+-- subscription without one. Rows written before this migration are NULL: their organization is
+-- unknown, so the janitor keeps them while any organization is under legal hold.
+ALTER TABLE audit_records ADD COLUMN IF NOT EXISTS organization TEXT;
+ALTER TABLE usage_hourly ADD COLUMN IF NOT EXISTS organization TEXT;
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration describes one embedded schema migration, read from its file name and header comments.
+type Migration struct {
+	Version uint
+	// File is the migration's file name, e.g. 0001_create_api_keys.up.sql.
+	File string
+	// Description is the "-- Description:" header.
+	Description string
+	// Rollback is the "-- Rollback:" header: how an operator undoes the migration by hand.
+	// Migrations are applied up only.
+	Rollback string
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(files))
+	for _, file := range files {
+		prefix, _, ok := strings.Cut(file, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: file name must start with <version>_", file)
+		}
+		version, err := strconv.ParseUint(prefix, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version %q", file, prefix)
+		}
+		m := Migration{Version: uint(version), File: file}
+		if err := readHeader(file, &m); err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// readHeader reads the leading comment block of a migration file.
+func readHeader(file string, m *Migration) error {
+	f, err := FS.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+		text := strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		if v, ok := strings.CutPrefix(text, "Description:"); ok {
+			m.Description = strings.TrimSpace(v)
+		} else if v, ok := strings.CutPrefix(text, "Rollback:"); ok {
+			m.Rollback = strings.TrimSpace(v)
+		}
+	}
+	return scanner.Err()
+}
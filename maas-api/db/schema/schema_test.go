@@ -0,0 +1,22 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/db/schema"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := schema.Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, uint(i+1), m.Version, "%s: versions must be consecutive", m.File)
+		assert.NotEmpty(t, m.Description, "%s: missing -- Description: header", m.File)
+		assert.NotEmpty(t, m.Rollback, "%s: missing -- Rollback: header", m.File)
+	}
+}
@@ -10,7 +10,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 )
@@ -18,8 +20,7 @@ import (
 // API key creation: single client-visible outcome for subscription resolution failures so we do not
 // distinguish not-found, access denied, or no default subscription (enumeration / permission hints).
 const (
-	apiKeySubscriptionResolutionErrCode = "invalid_subscription"
-	apiKeySubscriptionResolutionErrMsg  = "Unable to resolve a subscription for this API key" //nolint:gosec // G101: public JSON error text, not a credential
+	apiKeySubscriptionResolutionErrMsg = "Unable to resolve a subscription for this API key" //nolint:gosec // G101: public JSON error text, not a credential
 )
 
 // AdminChecker is an interface for checking if a user is an admin.
@@ -33,6 +34,7 @@ type Handler struct {
 	service      *Service
 	logger       *logger.Logger
 	adminChecker AdminChecker
+	signedURLs   *signedurl.Signer
 }
 
 func NewHandler(log *logger.Logger, service *Service, adminChecker AdminChecker) *Handler {
@@ -49,18 +51,24 @@ func NewHandler(log *logger.Logger, service *Service, adminChecker AdminChecker)
 	}
 }
 
+// SetSignedURLSigner makes POST /internal/v1/api-keys/validate accept the request paths of signed
+// URLs of signer, which Authorino sends for requests without an Authorization header.
+func (h *Handler) SetSignedURLSigner(signer *signedurl.Signer) {
+	h.signedURLs = signer
+}
+
 // getUserContext extracts and validates the user context from the Gin context.
 // Returns the user context on success, or responds with an error and returns nil.
 func (h *Handler) getUserContext(c *gin.Context) *token.UserContext {
 	userCtx, exists := c.Get("user")
 	if !exists {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User context not found"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "User context not found")
 		return nil
 	}
 
 	user, ok := userCtx.(*token.UserContext)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user context type"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Invalid user context type")
 		return nil
 	}
 
@@ -92,7 +100,7 @@ func (h *Handler) isAuthorizedForKey(ctx context.Context, user *token.UserContex
 func (h *Handler) GetAPIKey(c *gin.Context) {
 	tokenID := c.Param("id")
 	if tokenID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Token ID required"})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidPath, "Token ID required")
 		return
 	}
 
@@ -106,13 +114,13 @@ func (h *Handler) GetAPIKey(c *gin.Context) {
 	tok, err := h.service.GetAPIKey(c.Request.Context(), tokenID)
 	if err != nil {
 		if errors.Is(err, ErrKeyNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "API key not found")
 			return
 		}
 		h.logger.Error("Failed to get API key",
 			"error", err,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve API key"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to retrieve API key")
 		return
 	}
 
@@ -124,7 +132,7 @@ func (h *Handler) GetAPIKey(c *gin.Context) {
 			"keyId", tokenID,
 		)
 		// Return 404 instead of 403 to prevent key enumeration (IDOR protection)
-		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "API key not found")
 		return
 	}
 
@@ -141,6 +149,7 @@ type CreateAPIKeyRequest struct {
 	Subscription string          `json:"subscription,omitempty"` // Optional MaaSSubscription name; when omitted, highest-priority accessible subscription is used
 	ExpiresIn    *token.Duration `json:"expiresIn,omitempty"`    // Optional - defaults to API_KEY_MAX_EXPIRATION_DAYS (1hr for ephemeral)
 	Ephemeral    bool            `json:"ephemeral,omitempty"`    // Short-lived programmatic token (default: false)
+	Models       []string        `json:"models,omitempty"`       // Optional MaaSModelRefs (namespace/name) the key is limited to
 }
 
 // CreateAPIKey handles POST /v1/api-keys
@@ -151,10 +160,78 @@ type CreateAPIKeyRequest struct {
 func (h *Handler) CreateAPIKey(c *gin.Context) {
 	var req CreateAPIKeyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+	h.createAPIKey(c, req)
+}
+
+// CreateTokenRequest is the request body for POST /v1/tokens.
+// Tokens are ephemeral API keys: 1hr default and maximum expiration.
+type CreateTokenRequest struct {
+	Name         string          `json:"name,omitempty"`         // Optional, auto-generated when omitted
+	Subscription string          `json:"subscription,omitempty"` // Optional MaaSSubscription name; defaults as for API keys
+	Models       []string        `json:"models,omitempty"`       // Optional MaaSModelRefs (namespace/name) the token is limited to
+	ExpiresIn    *token.Duration `json:"expiresIn,omitempty"`    // Optional - defaults to 1hr
+}
+
+// CreateToken handles POST /v1/tokens
+// Mints a short-lived API key scoped to a subscription and optionally to models, so users can hand
+// credentials to notebooks and CI jobs without sharing their own.
+func (h *Handler) CreateToken(c *gin.Context) {
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+	h.createAPIKey(c, CreateAPIKeyRequest{
+		Name:         req.Name,
+		Subscription: req.Subscription,
+		ExpiresIn:    req.ExpiresIn,
+		Ephemeral:    true,
+		Models:       req.Models,
+	})
+}
+
+// ListTokens handles GET /v1/tokens
+// Lists the caller's active tokens (ephemeral API keys), newest first.
+func (h *Handler) ListTokens(c *gin.Context) {
+	user := h.getUserContext(c)
+	if user == nil {
+		return
+	}
+
+	onlyEphemeral := true
+	result, err := h.service.Search(c.Request.Context(), user.Username,
+		&SearchFilters{Status: []string{string(StatusActive)}, OnlyEphemeral: &onlyEphemeral},
+		&SortParams{By: DefaultSortBy, Order: DefaultSortOrder},
+		&PaginationParams{Limit: MaxLimit},
+	)
+	if err != nil {
+		h.logger.Error("Failed to list tokens", "error", err, "username", user.Username)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list tokens")
 		return
 	}
 
+	// Expired keys keep status active until they are next validated; leave them out.
+	now := time.Now().UTC()
+	tokens := make([]ApiKey, 0, len(result.Keys))
+	for _, k := range result.Keys {
+		if expiresAt, err := time.Parse(time.RFC3339, k.ExpirationDate); err == nil && !expiresAt.After(now) {
+			continue
+		}
+		tokens = append(tokens, k)
+	}
+
+	c.JSON(http.StatusOK, SearchAPIKeysResponse{
+		Object:  "list",
+		Data:    tokens,
+		HasMore: result.HasMore,
+	})
+}
+
+// createAPIKey creates a key for the authenticated user from req and writes the response.
+func (h *Handler) createAPIKey(c *gin.Context, req CreateAPIKeyRequest) {
 	user := h.getUserContext(c)
 	if user == nil {
 		return
@@ -162,7 +239,7 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 
 	// Validate name requirement for non-ephemeral keys
 	if !req.Ephemeral && req.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required for non-ephemeral keys"})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "name is required for non-ephemeral keys")
 		return
 	}
 
@@ -184,24 +261,26 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 	}
 
 	// Create key for the authenticated user with their groups
-	result, err := h.service.CreateAPIKey(c.Request.Context(), user.Username, user.Groups, name, req.Description, expiresIn, req.Ephemeral, strings.TrimSpace(req.Subscription))
+	result, err := h.service.CreateAPIKey(c.Request.Context(), user.Username, user.Groups, name, req.Description, expiresIn, req.Ephemeral, strings.TrimSpace(req.Subscription), req.Models)
 	if err != nil {
 		h.logger.Error("Failed to create API key", "error", err)
 		if errors.Is(err, ErrExpirationNotPositive) || errors.Is(err, ErrExpirationExceedsMax) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+			return
+		}
+		var notInSubscription *subscription.ModelNotInSubscriptionError
+		if errors.Is(err, ErrInvalidModelScope) || errors.As(err, &notInSubscription) {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidModelScope, err.Error())
 			return
 		}
 		var notFound *subscription.SubscriptionNotFoundError
 		var accessDenied *subscription.AccessDeniedError
 		var noSub *subscription.NoSubscriptionError
 		if errors.As(err, &notFound) || errors.As(err, &accessDenied) || errors.As(err, &noSub) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": apiKeySubscriptionResolutionErrMsg,
-				"code":  apiKeySubscriptionResolutionErrCode,
-			})
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidSubscription, apiKeySubscriptionResolutionErrMsg)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to create API key")
 		return
 	}
 
@@ -217,9 +296,11 @@ func (h *Handler) CreateAPIKey(c *gin.Context) {
 	c.JSON(http.StatusCreated, result)
 }
 
-// ValidateAPIKeyRequest is the request body for validating an API key.
+// ValidateAPIKeyRequest is the request body for validating an API key, or the path of a request
+// made with a signed URL.
 type ValidateAPIKeyRequest struct {
-	Key string `binding:"required" json:"key"`
+	Key       string `json:"key,omitempty"`
+	SignedURL string `json:"signedUrl,omitempty"`
 }
 
 // ValidateAPIKeyHandler handles POST /internal/v1/api-keys/validate
@@ -227,15 +308,23 @@ type ValidateAPIKeyRequest struct {
 // Per Feature Refinement "Gateway Integration (Inference Flow)".
 func (h *Handler) ValidateAPIKeyHandler(c *gin.Context) {
 	var req ValidateAPIKeyRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "key is required"})
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Key == "" && req.SignedURL == "") {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "key is required")
+		return
+	}
+	if req.Key == "" {
+		if h.signedURLs == nil {
+			c.JSON(http.StatusOK, &ValidationResult{Valid: false, Reason: "signed URLs are disabled"})
+			return
+		}
+		c.JSON(http.StatusOK, ValidateSignedURL(h.signedURLs, req.SignedURL))
 		return
 	}
 
 	result, err := h.service.ValidateAPIKey(c.Request.Context(), req.Key)
 	if err != nil {
 		h.logger.Error("API key validation failed", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "validation failed"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "validation failed")
 		return
 	}
 
@@ -255,7 +344,7 @@ func (h *Handler) ValidateAPIKeyHandler(c *gin.Context) {
 func (h *Handler) RevokeAPIKey(c *gin.Context) {
 	keyID := c.Param("id")
 	if keyID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "API key ID required"})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidPath, "API key ID required")
 		return
 	}
 
@@ -269,11 +358,11 @@ func (h *Handler) RevokeAPIKey(c *gin.Context) {
 	keyMetadata, err := h.service.GetAPIKey(c.Request.Context(), keyID)
 	if err != nil {
 		if errors.Is(err, ErrKeyNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "API key not found")
 			return
 		}
 		h.logger.Error("Failed to get API key for authorization check", "error", err, "keyId", keyID)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve API key"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to retrieve API key")
 		return
 	}
 
@@ -285,18 +374,18 @@ func (h *Handler) RevokeAPIKey(c *gin.Context) {
 			"keyId", keyID,
 		)
 		// Return 404 instead of 403 to prevent key enumeration (IDOR protection)
-		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "API key not found")
 		return
 	}
 
 	// Perform the revocation
 	if err := h.service.RevokeAPIKey(c.Request.Context(), keyID); err != nil {
 		if errors.Is(err, ErrKeyNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+			apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "API key not found")
 			return
 		}
 		h.logger.Error("Failed to revoke API key", "error", err, "keyId", keyID)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to revoke API key")
 		return
 	}
 
@@ -306,7 +395,7 @@ func (h *Handler) RevokeAPIKey(c *gin.Context) {
 	revokedKey, err := h.service.GetAPIKey(c.Request.Context(), keyID)
 	if err != nil {
 		h.logger.Error("Failed to retrieve revoked key", "error", err, "keyId", keyID)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Key revoked but failed to retrieve metadata"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Key revoked but failed to retrieve metadata")
 		return
 	}
 
@@ -318,7 +407,7 @@ func (h *Handler) RevokeAPIKey(c *gin.Context) {
 func (h *Handler) SearchAPIKeys(c *gin.Context) {
 	var req SearchAPIKeysRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
 		return
 	}
 
@@ -348,9 +437,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 	for _, status := range req.Filters.Status {
 		trimmed := strings.TrimSpace(status)
 		if !ValidStatuses[trimmed] {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": fmt.Sprintf("invalid status '%s': must be active, revoked, or expired", status),
-			})
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, fmt.Sprintf("invalid status '%s': must be active, revoked, or expired", status))
 			return
 		}
 	}
@@ -362,9 +449,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 	if !isAdmin {
 		// Regular user: can only search own keys
 		if targetUsername != "" && targetUsername != user.Username {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "non-admin users can only search their own API keys",
-			})
+			apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "non-admin users can only search their own API keys")
 			return
 		}
 		// Force filter to user's own keys
@@ -374,9 +459,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 
 	// Validate sort parameters
 	if req.Sort.By != "" && !ValidSortFields[req.Sort.By] {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid sort.by: must be one of: created_at, expires_at, last_used_at, name",
-		})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "invalid sort.by: must be one of: created_at, expires_at, last_used_at, name")
 		return
 	}
 
@@ -384,9 +467,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 	if req.Sort.Order != "" {
 		orderLower := strings.ToLower(strings.TrimSpace(req.Sort.Order))
 		if !ValidSortOrders[orderLower] {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "invalid sort.order: must be asc or desc",
-			})
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "invalid sort.order: must be asc or desc")
 			return
 		}
 		req.Sort.Order = orderLower
@@ -394,9 +475,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 
 	// Validate pagination
 	if req.Pagination.Limit < 1 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "pagination.limit must be at least 1",
-		})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "pagination.limit must be at least 1")
 		return
 	}
 	if req.Pagination.Limit > MaxLimit {
@@ -404,9 +483,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 		req.Pagination.Limit = MaxLimit
 	}
 	if req.Pagination.Offset < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "pagination.offset must be non-negative",
-		})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "pagination.offset must be non-negative")
 		return
 	}
 
@@ -423,7 +500,7 @@ func (h *Handler) SearchAPIKeys(c *gin.Context) {
 			"error", err,
 			"username", targetUsername,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search API keys"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to search API keys")
 		return
 	}
 
@@ -444,7 +521,7 @@ func (h *Handler) CleanupExpiredEphemeralKeys(c *gin.Context) {
 	count, err := h.service.CleanupExpiredEphemeral(c.Request.Context())
 	if err != nil {
 		h.logger.Error("Failed to cleanup expired ephemeral keys", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cleanup expired ephemeral keys"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to cleanup expired ephemeral keys")
 		return
 	}
 
@@ -459,7 +536,7 @@ func (h *Handler) CleanupExpiredEphemeralKeys(c *gin.Context) {
 func (h *Handler) BulkRevokeAPIKeys(c *gin.Context) {
 	var req BulkRevokeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
 		return
 	}
 
@@ -474,9 +551,7 @@ func (h *Handler) BulkRevokeAPIKeys(c *gin.Context) {
 			"requestingUser", user.Username,
 			"targetUser", req.Username,
 		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "Access denied: you can only bulk revoke your own API keys",
-		})
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Access denied: you can only bulk revoke your own API keys")
 		return
 	}
 
@@ -488,7 +563,7 @@ func (h *Handler) BulkRevokeAPIKeys(c *gin.Context) {
 			"targetUser", req.Username,
 			"requestingUser", user.Username,
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API keys"})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to revoke API keys")
 		return
 	}
 
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
@@ -24,6 +25,10 @@ import (
 
 const testSubscriptionName = "test-subscription"
 
+type errorResponse struct {
+	Error apierror.Error `json:"error"`
+}
+
 // fixedSubSelector satisfies SubscriptionSelector for handler tests (no cluster subscriptions).
 type fixedSubSelector struct{}
 
@@ -146,12 +151,12 @@ func TestSearchAPIKeys_EmptyRequest(t *testing.T) {
 
 	// Create test keys
 	ctx := context.Background()
-	err := store.AddKey(ctx, testUser.Username, "key-1", "hash-1", "Key 1", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err := store.AddKey(ctx, testUser.Username, "key-1", "hash-1", "Key 1", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
-	err = store.AddKey(ctx, testUser.Username, "key-2", "hash-2", "Key 2", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err = store.AddKey(ctx, testUser.Username, "key-2", "hash-2", "Key 2", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 	// Create a revoked key
-	err = store.AddKey(ctx, testUser.Username, "key-3", "hash-3", "Key 3", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err = store.AddKey(ctx, testUser.Username, "key-3", "hash-3", "Key 3", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 	err = store.Revoke(ctx, "key-3")
 	require.NoError(t, err)
@@ -196,7 +201,7 @@ func TestSearchAPIKeys_Pagination(t *testing.T) {
 		keyID := fmt.Sprintf("key-%d", i)
 		keyHash := fmt.Sprintf("hash-%d", i)
 		name := fmt.Sprintf("Key %d", i)
-		err := store.AddKey(ctx, testUser.Username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+		err := store.AddKey(ctx, testUser.Username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 		require.NoError(t, err)
 	}
 
@@ -256,10 +261,10 @@ func TestSearchAPIKeys_Pagination(t *testing.T) {
 		handler.SearchAPIKeys(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "limit must be at least 1")
+		assert.Contains(t, response.Error.Message, "limit must be at least 1")
 	})
 
 	t.Run("NegativeOffset", func(t *testing.T) {
@@ -275,10 +280,10 @@ func TestSearchAPIKeys_Pagination(t *testing.T) {
 		handler.SearchAPIKeys(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "offset must be non-negative")
+		assert.Contains(t, response.Error.Message, "offset must be non-negative")
 	})
 }
 
@@ -296,9 +301,9 @@ func TestSearchAPIKeys_StatusFilter(t *testing.T) {
 	}
 
 	// Create active and revoked keys
-	err := store.AddKey(ctx, testUser.Username, "active-key", "active-hash", "Active Key", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err := store.AddKey(ctx, testUser.Username, "active-key", "active-hash", "Active Key", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
-	err = store.AddKey(ctx, testUser.Username, "revoked-key", "revoked-hash", "Revoked Key", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err = store.AddKey(ctx, testUser.Username, "revoked-key", "revoked-hash", "Revoked Key", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 	err = store.Revoke(ctx, "revoked-key")
 	require.NoError(t, err)
@@ -401,10 +406,10 @@ func TestSearchAPIKeys_StatusFilter(t *testing.T) {
 		handler.SearchAPIKeys(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "invalid status")
+		assert.Contains(t, response.Error.Message, "invalid status")
 	})
 }
 
@@ -422,11 +427,11 @@ func TestSearchAPIKeys_Sorting(t *testing.T) {
 	}
 
 	// Create keys with different names
-	err := store.AddKey(ctx, testUser.Username, "key-1", "hash-1", "Charlie", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err := store.AddKey(ctx, testUser.Username, "key-1", "hash-1", "Charlie", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
-	err = store.AddKey(ctx, testUser.Username, "key-2", "hash-2", "Alice", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err = store.AddKey(ctx, testUser.Username, "key-2", "hash-2", "Alice", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
-	err = store.AddKey(ctx, testUser.Username, "key-3", "hash-3", "Bob", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err = store.AddKey(ctx, testUser.Username, "key-3", "hash-3", "Bob", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 
 	t.Run("DefaultSort_CreatedAtDesc", func(t *testing.T) {
@@ -507,10 +512,10 @@ func TestSearchAPIKeys_Sorting(t *testing.T) {
 		handler.SearchAPIKeys(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "invalid sort.by")
+		assert.Contains(t, response.Error.Message, "invalid sort.by")
 	})
 
 	t.Run("InvalidSortOrder", func(t *testing.T) {
@@ -526,10 +531,10 @@ func TestSearchAPIKeys_Sorting(t *testing.T) {
 		handler.SearchAPIKeys(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "invalid sort.order")
+		assert.Contains(t, response.Error.Message, "invalid sort.order")
 	})
 }
 
@@ -549,7 +554,7 @@ func TestSearchAPIKeys_AdminVsRegularUser(t *testing.T) {
 			keyID := fmt.Sprintf("%s-key-%d", username, i)
 			keyHash := fmt.Sprintf("%s-hash-%d", username, i)
 			name := fmt.Sprintf("%s Key %d", username, i)
-			err := store.AddKey(ctx, username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+			err := store.AddKey(ctx, username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 			require.NoError(t, err)
 		}
 	}
@@ -670,14 +675,14 @@ func TestSearchAPIKeys_AdminFiltersByUsernameAndStatus(t *testing.T) {
 			keyID := fmt.Sprintf("%s-active-%d", username, i)
 			keyHash := fmt.Sprintf("%s-hash-active-%d", username, i)
 			name := fmt.Sprintf("%s Active Key %d", username, i)
-			err := store.AddKey(ctx, username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+			err := store.AddKey(ctx, username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 			require.NoError(t, err)
 		}
 		// Create 1 revoked key
 		keyID := fmt.Sprintf("%s-revoked", username)
 		keyHash := fmt.Sprintf("%s-hash-revoked", username)
 		name := fmt.Sprintf("%s Revoked Key", username)
-		err := store.AddKey(ctx, username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+		err := store.AddKey(ctx, username, keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 		require.NoError(t, err)
 		err = store.Revoke(ctx, keyID)
 		require.NoError(t, err)
@@ -750,7 +755,7 @@ func TestBulkRevokeAPIKeys(t *testing.T) {
 		keyID := fmt.Sprintf("alice-key-%d", i)
 		keyHash := fmt.Sprintf("alice-hash-%d", i)
 		name := fmt.Sprintf("Alice Key %d", i)
-		err := store.AddKey(ctx, "alice", keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+		err := store.AddKey(ctx, "alice", keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 		require.NoError(t, err)
 	}
 
@@ -758,7 +763,7 @@ func TestBulkRevokeAPIKeys(t *testing.T) {
 		keyID := fmt.Sprintf("bob-key-%d", i)
 		keyHash := fmt.Sprintf("bob-hash-%d", i)
 		name := fmt.Sprintf("Bob Key %d", i)
-		err := store.AddKey(ctx, "bob", keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+		err := store.AddKey(ctx, "bob", keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 		require.NoError(t, err)
 	}
 
@@ -813,7 +818,7 @@ func TestBulkRevokeAPIKeys(t *testing.T) {
 			keyID := fmt.Sprintf("alice-key-%d", i)
 			keyHash := fmt.Sprintf("alice-hash-%d", i)
 			name := fmt.Sprintf("Alice Key %d", i)
-			err := store.AddKey(ctx, "alice", keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+			err := store.AddKey(ctx, "alice", keyID, keyHash, name, "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 			require.NoError(t, err)
 		}
 
@@ -1006,10 +1011,10 @@ func TestCreateAPIKey_SubscriptionSelectErrors(t *testing.T) {
 			h.CreateAPIKey(c)
 
 			assert.Equal(t, http.StatusBadRequest, w.Code)
-			var resp map[string]string
+			var resp errorResponse
 			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
-			assert.Equal(t, apiKeySubscriptionResolutionErrCode, resp["code"])
-			assert.Equal(t, apiKeySubscriptionResolutionErrMsg, resp["error"])
+			assert.Equal(t, apierror.InvalidSubscription, resp.Error.Code)
+			assert.Equal(t, apiKeySubscriptionResolutionErrMsg, resp.Error.Message)
 
 			res, err := store.Search(context.Background(), user.Username, &SearchFilters{}, &SortParams{By: DefaultSortBy, Order: DefaultSortOrder}, &PaginationParams{Limit: 10, Offset: 0})
 			require.NoError(t, err)
@@ -1048,9 +1053,9 @@ func TestGetAPIKeyHandler(t *testing.T) {
 	}
 
 	// Add keys to store
-	err := store.AddKey(context.Background(), aliceKey.Username, aliceKey.ID, "hash1", aliceKey.Name, "", aliceKey.Groups, testSubscriptionName, nil, false)
+	err := store.AddKey(context.Background(), aliceKey.Username, aliceKey.ID, "hash1", aliceKey.Name, "", aliceKey.Groups, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
-	err = store.AddKey(context.Background(), bobKey.Username, bobKey.ID, "hash2", bobKey.Name, "", bobKey.Groups, testSubscriptionName, nil, false)
+	err = store.AddKey(context.Background(), bobKey.Username, bobKey.ID, "hash2", bobKey.Name, "", bobKey.Groups, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 
 	// Helper function to test successful key retrieval
@@ -1098,10 +1103,10 @@ func TestGetAPIKeyHandler(t *testing.T) {
 
 		// IDOR Protection: Return 404 instead of 403 to prevent key enumeration
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, "API key not found", response["error"])
+		assert.Equal(t, "API key not found", response.Error.Message)
 	})
 
 	t.Run("AdminCanGetAnyKey", func(t *testing.T) {
@@ -1143,7 +1148,7 @@ func testRevokeKeySuccess(t *testing.T, user *token.UserContext) {
 	handler := NewHandler(logger.Development(), service, newMockAdminChecker())
 
 	// Create alice's key
-	err := store.AddKey(context.Background(), "alice", "alice-key-1", "hash1", "Alice's Key", "", []string{"tier-free"}, testSubscriptionName, nil, false)
+	err := store.AddKey(context.Background(), "alice", "alice-key-1", "hash1", "Alice's Key", "", []string{"tier-free"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 
 	w := httptest.NewRecorder()
@@ -1186,7 +1191,7 @@ func TestRevokeAPIKeyHandler(t *testing.T) {
 		handler := NewHandler(logger.Development(), service, newMockAdminChecker())
 
 		// Create alice's key
-		err := store.AddKey(context.Background(), "alice", "alice-key-1", "hash1", "Alice's Key", "", []string{"tier-free"}, testSubscriptionName, nil, false)
+		err := store.AddKey(context.Background(), "alice", "alice-key-1", "hash1", "Alice's Key", "", []string{"tier-free"}, testSubscriptionName, nil, nil, false)
 		require.NoError(t, err)
 
 		// Bob trying to revoke Alice's key
@@ -1205,10 +1210,10 @@ func TestRevokeAPIKeyHandler(t *testing.T) {
 
 		// IDOR Protection: Return 404 instead of 403 to prevent key enumeration
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err = json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Equal(t, "API key not found", response["error"])
+		assert.Equal(t, "API key not found", response.Error.Message)
 
 		// Verify key was NOT revoked
 		key, err := store.Get(context.Background(), "alice-key-1")
@@ -1253,7 +1258,7 @@ func TestRevokeAPIKeyHandler(t *testing.T) {
 		handler := NewHandler(logger.Development(), service, newMockAdminChecker())
 
 		// Create and immediately revoke alice's key
-		err := store.AddKey(context.Background(), "alice", "alice-key-1", "hash1", "Alice's Key", "", []string{"tier-free"}, testSubscriptionName, nil, false)
+		err := store.AddKey(context.Background(), "alice", "alice-key-1", "hash1", "Alice's Key", "", []string{"tier-free"}, testSubscriptionName, nil, nil, false)
 		require.NoError(t, err)
 		err = store.Revoke(context.Background(), "alice-key-1")
 		require.NoError(t, err)
@@ -1383,10 +1388,10 @@ func TestCreateEphemeralAPIKey(t *testing.T) {
 		handler.CreateAPIKey(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "name is required")
+		assert.Contains(t, response.Error.Message, "name is required")
 	})
 
 	t.Run("EphemeralKeyExceedsMaxExpiration", func(t *testing.T) {
@@ -1403,10 +1408,10 @@ func TestCreateEphemeralAPIKey(t *testing.T) {
 		handler.CreateAPIKey(c)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		var response map[string]string
+		var response errorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		require.NoError(t, err)
-		assert.Contains(t, response["error"], "cannot exceed 1 hour")
+		assert.Contains(t, response.Error.Message, "cannot exceed 1 hour")
 	})
 }
 
@@ -1424,27 +1429,27 @@ func TestCleanupExpiredEphemeralKeys(t *testing.T) {
 	ctx := context.Background()
 
 	// Create regular active key (should NOT be deleted)
-	err := store.AddKey(ctx, "alice", "regular-key", "hash-1", "Regular Key", "", []string{"users"}, testSubscriptionName, nil, false)
+	err := store.AddKey(ctx, "alice", "regular-key", "hash-1", "Regular Key", "", []string{"users"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 
 	// Create active ephemeral key with future expiration (should NOT be deleted)
 	futureExpiry := time.Now().Add(30 * time.Minute)
-	err = store.AddKey(ctx, "alice", "active-ephemeral", "hash-2", "Active Ephemeral", "", []string{"users"}, testSubscriptionName, &futureExpiry, true)
+	err = store.AddKey(ctx, "alice", "active-ephemeral", "hash-2", "Active Ephemeral", "", []string{"users"}, testSubscriptionName, nil, &futureExpiry, true)
 	require.NoError(t, err)
 
 	// Create expired ephemeral key (should be deleted)
 	pastExpiry := time.Now().Add(-1 * time.Hour)
-	err = store.AddKey(ctx, "alice", "expired-ephemeral", "hash-3", "Expired Ephemeral", "", []string{"users"}, testSubscriptionName, &pastExpiry, true)
+	err = store.AddKey(ctx, "alice", "expired-ephemeral", "hash-3", "Expired Ephemeral", "", []string{"users"}, testSubscriptionName, nil, &pastExpiry, true)
 	require.NoError(t, err)
 
 	// Create another expired ephemeral key (should be deleted)
 	pastExpiry2 := time.Now().Add(-2 * time.Hour)
-	err = store.AddKey(ctx, "bob", "expired-ephemeral-2", "hash-4", "Expired Ephemeral 2", "", []string{"users"}, testSubscriptionName, &pastExpiry2, true)
+	err = store.AddKey(ctx, "bob", "expired-ephemeral-2", "hash-4", "Expired Ephemeral 2", "", []string{"users"}, testSubscriptionName, nil, &pastExpiry2, true)
 	require.NoError(t, err)
 
 	// Create expired ephemeral key within 30-minute grace period (should NOT be deleted)
 	recentExpiry := time.Now().Add(-10 * time.Minute)
-	err = store.AddKey(ctx, "alice", "recently-expired-ephemeral", "hash-5", "Recently Expired Ephemeral", "", []string{"users"}, testSubscriptionName, &recentExpiry, true)
+	err = store.AddKey(ctx, "alice", "recently-expired-ephemeral", "hash-5", "Recently Expired Ephemeral", "", []string{"users"}, testSubscriptionName, nil, &recentExpiry, true)
 	require.NoError(t, err)
 
 	t.Run("DeletesExpiredEphemeralKeys", func(t *testing.T) {
@@ -1506,16 +1511,16 @@ func TestSearchExcludesEphemeralByDefault(t *testing.T) {
 	}
 
 	// Create regular keys
-	err := store.AddKey(ctx, testUser.Username, "regular-key-1", "hash-1", "Regular Key 1", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err := store.AddKey(ctx, testUser.Username, "regular-key-1", "hash-1", "Regular Key 1", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
-	err = store.AddKey(ctx, testUser.Username, "regular-key-2", "hash-2", "Regular Key 2", "", []string{"system:authenticated"}, testSubscriptionName, nil, false)
+	err = store.AddKey(ctx, testUser.Username, "regular-key-2", "hash-2", "Regular Key 2", "", []string{"system:authenticated"}, testSubscriptionName, nil, nil, false)
 	require.NoError(t, err)
 
 	// Create ephemeral keys
 	futureExpiry := time.Now().Add(1 * time.Hour)
-	err = store.AddKey(ctx, testUser.Username, "ephemeral-key-1", "hash-3", "Ephemeral Key 1", "", []string{"system:authenticated"}, testSubscriptionName, &futureExpiry, true)
+	err = store.AddKey(ctx, testUser.Username, "ephemeral-key-1", "hash-3", "Ephemeral Key 1", "", []string{"system:authenticated"}, testSubscriptionName, nil, &futureExpiry, true)
 	require.NoError(t, err)
-	err = store.AddKey(ctx, testUser.Username, "ephemeral-key-2", "hash-4", "Ephemeral Key 2", "", []string{"system:authenticated"}, testSubscriptionName, &futureExpiry, true)
+	err = store.AddKey(ctx, testUser.Username, "ephemeral-key-2", "hash-4", "Ephemeral Key 2", "", []string{"system:authenticated"}, testSubscriptionName, nil, &futureExpiry, true)
 	require.NoError(t, err)
 
 	t.Run("DefaultSearchExcludesEphemeral", func(t *testing.T) {
@@ -1543,3 +1548,73 @@ func TestSearchExcludesEphemeralByDefault(t *testing.T) {
 		assert.Equal(t, 2, ephemeralCount, "should have 2 ephemeral keys")
 	})
 }
+
+// ============================================================
+// TOKEN API TESTS (POST/GET /v1/tokens)
+// ============================================================
+
+func TestTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := NewMockStore()
+	service := NewServiceWithLogger(store, &config.Config{}, fixedSubSelector{}, logger.Development())
+	handler := NewHandler(logger.Development(), service, newMockAdminChecker())
+	testUser := &token.UserContext{Username: "data-scientist", Groups: []string{"system:authenticated"}}
+
+	createToken := func(t *testing.T, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/v1/tokens", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Set("user", testUser)
+		handler.CreateToken(c)
+		return w
+	}
+
+	t.Run("MintsEphemeralModelScopedKey", func(t *testing.T) {
+		w := createToken(t, `{"name": "notebook", "subscription": "team-a", "models": ["llm/granite"], "expiresIn": "30m"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var response CreateAPIKeyResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Ephemeral)
+		assert.Equal(t, "team-a", response.Subscription)
+		assert.Equal(t, []string{"llm/granite"}, response.Models)
+
+		valResult, err := service.ValidateAPIKey(context.Background(), response.Key)
+		require.NoError(t, err)
+		require.True(t, valResult.Valid)
+		assert.Equal(t, []string{"llm/granite"}, valResult.Models, "validation result should carry the scope for Authorino")
+	})
+
+	t.Run("RejectsLongLivedTokens", func(t *testing.T) {
+		w := createToken(t, `{"expiresIn": "2h"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("RejectsBareModelNames", func(t *testing.T) {
+		w := createToken(t, `{"models": ["granite"]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "namespace/name")
+	})
+
+	t.Run("ListsOnlyActiveTokens", func(t *testing.T) {
+		ctx := context.Background()
+		past := time.Now().Add(-time.Minute)
+		require.NoError(t, store.AddKey(ctx, testUser.Username, "regular", "hash-regular", "regular", "", nil, testSubscriptionName, nil, nil, false))
+		require.NoError(t, store.AddKey(ctx, testUser.Username, "expired", "hash-expired", "expired", "", nil, testSubscriptionName, nil, &past, true))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/v1/tokens", nil)
+		c.Set("user", testUser)
+		handler.ListTokens(c)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var response SearchAPIKeysResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1, "only the token minted above is active")
+		assert.Equal(t, "notebook", response.Data[0].Name)
+		assert.Equal(t, []string{"llm/granite"}, response.Data[0].Models)
+	})
+}
@@ -0,0 +1,180 @@
+package api_keys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/db/schema"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/migration"
+)
+
+// SchemaMigration applies the embedded SQL migrations (db/schema) with golang-migrate.
+type SchemaMigration struct {
+	db     *sql.DB
+	logger *logger.Logger
+}
+
+var _ migration.Step = (*SchemaMigration)(nil)
+
+// NewSchemaMigration returns the schema migration step for db. It does not close db.
+func NewSchemaMigration(db *sql.DB, log *logger.Logger) *SchemaMigration {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &SchemaMigration{db: db, logger: log}
+}
+
+// Name implements migration.Step.
+func (m *SchemaMigration) Name() string {
+	return "postgres-schema"
+}
+
+// Plan implements migration.Step. It fails when the schema is dirty (a migration stopped part-way)
+// or newer than this build, which golang-migrate cannot go up from.
+func (m *SchemaMigration) Plan(ctx context.Context) (migration.Plan, error) {
+	migrations, err := schema.Migrations()
+	if err != nil {
+		return migration.Plan{}, fmt.Errorf("failed to read schema migrations: %w", err)
+	}
+	var target uint
+	if len(migrations) > 0 {
+		target = migrations[len(migrations)-1].Version
+	}
+
+	current, dirty, err := m.version(ctx)
+	if err != nil {
+		return migration.Plan{}, err
+	}
+	plan := migration.Plan{Current: strconv.FormatUint(uint64(current), 10), Target: strconv.FormatUint(uint64(target), 10)}
+	if dirty {
+		return plan, fmt.Errorf("schema is dirty at version %d: the migration failed part-way; repair the database "+
+			"(or restore the pre-upgrade backup) and reset the version in schema_migrations", current)
+	}
+	if current > target {
+		return plan, fmt.Errorf("schema version %d is newer than this maas-api supports (%d); "+
+			"upgrade maas-api, or follow the rollback notes of the newer version", current, target)
+	}
+	for _, mig := range migrations {
+		if mig.Version <= current {
+			continue
+		}
+		plan.Pending = append(plan.Pending, fmt.Sprintf("%s: %s", mig.File, mig.Description))
+		plan.Rollback = append(plan.Rollback, fmt.Sprintf("%s: %s", mig.File, mig.Rollback))
+	}
+	return plan, nil
+}
+
+// Apply implements migration.Step.
+func (m *SchemaMigration) Apply(ctx context.Context) error {
+	migrator, err := m.migrator(ctx)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	// Run schema migrations
+	if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("schema migration failed: %w", err)
+	}
+
+	version, dirty, _ := migrator.Version()
+	if dirty {
+		m.logger.Warn("Database schema is in dirty state", "version", version)
+	} else {
+		m.logger.Info("Database schema applied", "version", version)
+	}
+	return nil
+}
+
+// version returns the applied schema version, 0 when none is.
+func (m *SchemaMigration) version(ctx context.Context) (uint, bool, error) {
+	migrator, err := m.migrator(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer migrator.Close()
+
+	version, dirty, err := migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// migrator builds a golang-migrate instance on a dedicated connection, so closing it leaves db open.
+func (m *SchemaMigration) migrator(ctx context.Context) (*migrate.Migrate, error) {
+	// Create migration source from embedded schema files
+	source, err := iofs.New(schema.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema migration source: %w", err)
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	// Create database driver for schema migrations
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create schema migration driver: %w", err)
+	}
+
+	migrator, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to create schema migrator: %w", err)
+	}
+	return migrator, nil
+}
+
+// RewrapMigration re-encrypts stored descriptions under the current KMS key, encrypting those
+// stored before encryption was enabled. It is the key-format step of `maas-api migrate`.
+type RewrapMigration struct {
+	store *EncryptedStore
+}
+
+var _ migration.Step = (*RewrapMigration)(nil)
+
+// NewRewrapMigration returns the rewrap step for store.
+func NewRewrapMigration(store *EncryptedStore) *RewrapMigration {
+	return &RewrapMigration{store: store}
+}
+
+// Name implements migration.Step.
+func (m *RewrapMigration) Name() string {
+	return "kms-rewrap"
+}
+
+// Plan implements migration.Step. Which descriptions use an older key is only known by decrypting
+// them, so the plan always lists the rewrap; rewrapping current values is harmless.
+func (m *RewrapMigration) Plan(_ context.Context) (migration.Plan, error) {
+	if _, ok := m.store.MetadataStore.(descriptionRewriter); !ok {
+		return migration.Plan{}, fmt.Errorf("store %T does not support rewrapping", m.store.MetadataStore)
+	}
+	return migration.Plan{
+		Pending: []string{"re-encrypt every stored API key description under the current KMS key"},
+		Rollback: []string{"keep the previous KMS key enabled until the rewrap completes; " +
+			"descriptions rewrapped under the new key cannot be read by a maas-api configured with the old key only"},
+	}, nil
+}
+
+// Apply implements migration.Step.
+func (m *RewrapMigration) Apply(ctx context.Context) error {
+	count, err := m.store.Rewrap(ctx)
+	if err != nil {
+		return fmt.Errorf("rewrapped %d descriptions before failing: %w", count, err)
+	}
+	return nil
+}
@@ -48,14 +48,15 @@ func NewServiceWithLogger(store MetadataStore, cfg *config.Config, sub Subscript
 // CreateAPIKeyResponse is returned when creating an API key.
 // Per Feature Refinement "Keys Shown Only Once": plaintext key is ONLY returned at creation time.
 type CreateAPIKeyResponse struct {
-	Key          string  `json:"key"`       // Plaintext key - SHOWN ONCE, NEVER STORED
-	KeyPrefix    string  `json:"keyPrefix"` // Display prefix for UI
-	ID           string  `json:"id"`
-	Name         string  `json:"name"`
-	Subscription string  `json:"subscription"` // MaaSSubscription name bound to this key
-	CreatedAt    string  `json:"createdAt"`
-	ExpiresAt    *string `json:"expiresAt,omitempty"` // RFC3339 timestamp
-	Ephemeral    bool    `json:"ephemeral"`           // Short-lived programmatic key
+	Key          string   `json:"key"`       // Plaintext key - SHOWN ONCE, NEVER STORED
+	KeyPrefix    string   `json:"keyPrefix"` // Display prefix for UI
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Subscription string   `json:"subscription"`     // MaaSSubscription name bound to this key
+	Models       []string `json:"models,omitempty"` // Models (namespace/name) the key is scoped to
+	CreatedAt    string   `json:"createdAt"`
+	ExpiresAt    *string  `json:"expiresAt,omitempty"` // RFC3339 timestamp
+	Ephemeral    bool     `json:"ephemeral"`           // Short-lived programmatic key
 }
 
 // CreateAPIKey creates a new API key (sk-oai-* format).
@@ -66,10 +67,17 @@ type CreateAPIKeyResponse struct {
 // - Returns plaintext ONCE at creation ("show-once" pattern)
 // - Stores user groups for subscription-based authorization.
 // Admins can create keys for other users by specifying a different username.
+// models optionally scopes the key to MaaSModelRefs (namespace/name), each of which must be in
+// the bound subscription; an empty scope allows every model of the subscription.
 func (s *Service) CreateAPIKey(
 	ctx context.Context, username string, userGroups []string, name, description string,
-	expiresIn *time.Duration, ephemeral bool, requestedSubscription string,
+	expiresIn *time.Duration, ephemeral bool, requestedSubscription string, models []string,
 ) (*CreateAPIKeyResponse, error) {
+	models, err := normalizeModelScope(models)
+	if err != nil {
+		return nil, err
+	}
+
 	// Compute max expiration days once from config-or-default (CWE-613 mitigation).
 	maxDays := constant.DefaultAPIKeyMaxExpirationDays
 	if s.config != nil && s.config.APIKeyMaxExpirationDays > 0 {
@@ -136,6 +144,19 @@ func (s *Service) CreateAPIKey(
 	}
 	subscriptionName := subResp.Name
 
+	for _, model := range models {
+		//nolint:unqueryvet,nolintlint // Select is subscription resolution, not a SQL query
+		if _, err := s.subSelector.Select(userGroups, username, subscriptionName, model); err != nil {
+			s.logger.Warn("Model scope not covered by subscription when creating API key",
+				"user", username,
+				"subscription", subscriptionName,
+				"model", model,
+				"error", err,
+			)
+			return nil, err
+		}
+	}
+
 	// Generate unique ID for this key
 	keyID := uuid.New().String()
 
@@ -143,7 +164,7 @@ func (s *Service) CreateAPIKey(
 	// Note: prefix is NOT stored (security - reduces brute-force attack surface)
 	// userGroups stored as PostgreSQL TEXT[] array (no JSON marshaling needed)
 	// Hash is SHA-256(key_id + secret) where key_id is embedded in the API key as per-key salt
-	if err := s.store.AddKey(ctx, username, keyID, hash, name, description, userGroups, subscriptionName, &expiresAt, ephemeral); err != nil {
+	if err := s.store.AddKey(ctx, username, keyID, hash, name, description, userGroups, subscriptionName, models, &expiresAt, ephemeral); err != nil {
 		return nil, fmt.Errorf("failed to store API key: %w", err)
 	}
 
@@ -157,6 +178,7 @@ func (s *Service) CreateAPIKey(
 		ID:           keyID,
 		Name:         name,
 		Subscription: subscriptionName,
+		Models:       models,
 		CreatedAt:    time.Now().UTC().Format(time.RFC3339),
 		ExpiresAt:    &formatted,
 		Ephemeral:    ephemeral,
@@ -259,9 +281,29 @@ func (s *Service) ValidateAPIKey(ctx context.Context, key string) (*ValidationRe
 		KeyID:        metadata.ID,
 		Groups:       groups, // Original user groups for subscription-based authorization
 		Subscription: metadata.Subscription,
+		Models:       metadata.Models,
 	}, nil
 }
 
+// normalizeModelScope trims and de-duplicates a key's model scope, rejecting entries that are
+// not namespace/name.
+func normalizeModelScope(models []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]bool, len(models))
+	for _, model := range models {
+		model = strings.TrimSpace(model)
+		ns, name, ok := strings.Cut(model, "/")
+		if !ok || ns == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidModelScope, model)
+		}
+		if !seen[model] {
+			seen[model] = true
+			out = append(out, model)
+		}
+	}
+	return out, nil
+}
+
 // RevokeAPIKey revokes a specific permanent API key.
 func (s *Service) RevokeAPIKey(ctx context.Context, keyID string) error {
 	return s.store.Revoke(ctx, keyID)
@@ -49,7 +49,7 @@ func TestValidateAPIKey_ValidKey(t *testing.T) {
 	username := "alice"
 	groups := []string{"tier-premium", "system:authenticated"}
 
-	err := store.AddKey(ctx, username, keyID, hash, "Test Key", "", groups, "default-sub", nil, false)
+	err := store.AddKey(ctx, username, keyID, hash, "Test Key", "", groups, "default-sub", nil, nil, false)
 	require.NoError(t, err)
 
 	// Validate the key
@@ -113,7 +113,7 @@ func TestValidateAPIKey_RevokedKey(t *testing.T) {
 	username := "bob"
 	groups := []string{"tier-free"}
 
-	err := store.AddKey(ctx, username, keyID, hash, "Revoked Key", "", groups, "default-sub", nil, false)
+	err := store.AddKey(ctx, username, keyID, hash, "Revoked Key", "", groups, "default-sub", nil, nil, false)
 	require.NoError(t, err)
 
 	// Revoke the key
@@ -140,7 +140,7 @@ func TestValidateAPIKey_ExpiredKey(t *testing.T) {
 	groups := []string{"tier-basic"}
 	expiresAt := time.Now().Add(-24 * time.Hour) // Expired 1 day ago
 
-	err := store.AddKey(ctx, username, keyID, hash, "Expired Key", "", groups, "default-sub", &expiresAt, false)
+	err := store.AddKey(ctx, username, keyID, hash, "Expired Key", "", groups, "default-sub", nil, &expiresAt, false)
 	require.NoError(t, err)
 
 	// Validate the expired key
@@ -161,7 +161,7 @@ func TestValidateAPIKey_EmptyGroups(t *testing.T) {
 	plainKey, hash := createTestAPIKey(t)
 	username := "dave"
 
-	err := store.AddKey(ctx, username, keyID, hash, "No Groups Key", "", nil, "default-sub", nil, false)
+	err := store.AddKey(ctx, username, keyID, hash, "No Groups Key", "", nil, "default-sub", nil, nil, false)
 	require.NoError(t, err)
 
 	// Validate the key
@@ -185,7 +185,7 @@ func TestValidateAPIKey_UpdatesLastUsed(t *testing.T) {
 	username := "eve"
 	groups := []string{"tier-enterprise"}
 
-	err := store.AddKey(ctx, username, keyID, hash, "Last Used Test", "", groups, "default-sub", nil, false)
+	err := store.AddKey(ctx, username, keyID, hash, "Last Used Test", "", groups, "default-sub", nil, nil, false)
 	require.NoError(t, err)
 
 	// Get initial metadata (last_used_at should be empty/nil)
@@ -221,7 +221,7 @@ func TestGetAPIKey(t *testing.T) {
 	username := "alice"
 	keyName := "Alice's Key"
 
-	err := store.AddKey(ctx, username, keyID, hash, keyName, "Test description", nil, "default-sub", nil, false)
+	err := store.AddKey(ctx, username, keyID, hash, keyName, "Test description", nil, "default-sub", nil, nil, false)
 	require.NoError(t, err)
 
 	// Get via service layer
@@ -253,7 +253,7 @@ func TestRevokeAPIKey(t *testing.T) {
 	_, hash := createTestAPIKey(t)
 	username := "bob"
 
-	err := store.AddKey(ctx, username, keyID, hash, "Revoke Test", "", nil, "default-sub", nil, false)
+	err := store.AddKey(ctx, username, keyID, hash, "Revoke Test", "", nil, "default-sub", nil, nil, false)
 	require.NoError(t, err)
 
 	// Verify it's active
@@ -287,7 +287,7 @@ func TestCreateAPIKey_MaxExpirationLimit(t *testing.T) {
 
 		// Request 7 days - should succeed
 		expiresIn := 7 * 24 * time.Hour
-		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "")
+		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "", nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -303,7 +303,7 @@ func TestCreateAPIKey_MaxExpirationLimit(t *testing.T) {
 
 		// Request 60 days - should fail
 		expiresIn := 60 * 24 * time.Hour
-		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "")
+		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "", nil)
 
 		require.Error(t, err)
 		assert.Nil(t, result)
@@ -320,7 +320,7 @@ func TestCreateAPIKey_MaxExpirationLimit(t *testing.T) {
 
 		// Request exactly 30 days - should succeed
 		expiresIn := 30 * 24 * time.Hour
-		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "")
+		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "", nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -334,7 +334,7 @@ func TestCreateAPIKey_MaxExpirationLimit(t *testing.T) {
 		svc := api_keys.NewServiceWithLogger(store, cfg, serviceTestSubSelector{}, logger.Development())
 
 		// No expiration requested - should default to APIKeyMaxExpirationDays (30 days)
-		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", nil, false, "")
+		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", nil, false, "", nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -349,7 +349,7 @@ func TestCreateAPIKey_MaxExpirationLimit(t *testing.T) {
 
 		// Request 365 days - should fail because default max is 90 days
 		expiresIn := 365 * 24 * time.Hour
-		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "")
+		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "", nil)
 
 		require.Error(t, err, "should reject expiration exceeding default max (90 days)")
 		assert.Nil(t, result)
@@ -367,7 +367,7 @@ func TestCreateAPIKey_MaxExpirationLimit(t *testing.T) {
 
 		// Request 365 days - should fail because default max is 90 days
 		expiresIn := 365 * 24 * time.Hour
-		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "")
+		result, err := svc.CreateAPIKey(ctx, "alice", []string{"users"}, "Test Key", "", &expiresIn, false, "", nil)
 
 		require.Error(t, err, "should reject expiration exceeding default max (90 days)")
 		assert.Nil(t, result)
@@ -398,7 +398,7 @@ func TestEphemeralKeyExpiration(t *testing.T) {
 		svc := api_keys.NewServiceWithLogger(api_keys.NewMockStore(), &config.Config{}, serviceTestSubSelector{}, logger.Development())
 		now := time.Now().UTC()
 
-		result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "ephemeral-test", "", nil, true, "")
+		result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "ephemeral-test", "", nil, true, "", nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -412,7 +412,7 @@ func TestEphemeralKeyExpiration(t *testing.T) {
 		expiresIn := 30 * time.Minute
 		now := time.Now().UTC()
 
-		result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "short-lived", "", &expiresIn, true, "")
+		result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "short-lived", "", &expiresIn, true, "", nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -424,7 +424,7 @@ func TestEphemeralKeyExpiration(t *testing.T) {
 		svc := api_keys.NewServiceWithLogger(api_keys.NewMockStore(), &config.Config{}, serviceTestSubSelector{}, logger.Development())
 		expiresIn := 1 * time.Hour
 
-		result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "exactly-one-hour", "", &expiresIn, true, "")
+		result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "exactly-one-hour", "", &expiresIn, true, "", nil)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -463,7 +463,7 @@ func TestEphemeralKeyExpiration(t *testing.T) {
 			svc := api_keys.NewServiceWithLogger(api_keys.NewMockStore(), &config.Config{}, serviceTestSubSelector{}, logger.Development())
 			expiresIn := tt.expiresIn
 
-			result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "test-key", "", &expiresIn, true, "")
+			result, err := svc.CreateAPIKey(ctx, "user", []string{"users"}, "test-key", "", &expiresIn, true, "", nil)
 
 			require.Error(t, err)
 			assert.Nil(t, result)
@@ -479,12 +479,17 @@ type subSelectorStub struct {
 	highestPriorityErr error
 	// highestName is returned by SelectHighestPriority on success; empty defaults to "from-priority".
 	highestName string
+	// excludedModel is reported as not in the requested subscription.
+	excludedModel string
 }
 
-func (s subSelectorStub) Select(_ []string, _ string, requested string, _ string) (*subscription.SelectResponse, error) {
+func (s subSelectorStub) Select(_ []string, _ string, requested string, model string) (*subscription.SelectResponse, error) {
 	if s.selectErr != nil {
 		return nil, s.selectErr
 	}
+	if model != "" && model == s.excludedModel {
+		return nil, &subscription.ModelNotInSubscriptionError{Subscription: requested, Model: model}
+	}
 	return &subscription.SelectResponse{Name: requested}, nil
 }
 
@@ -509,7 +514,7 @@ func TestCreateAPIKey_Subscription(t *testing.T) {
 		store := api_keys.NewMockStore()
 		svc := api_keys.NewServiceWithLogger(store, cfg, subSelectorStub{}, logger.Development())
 
-		result, err := svc.CreateAPIKey(ctx, user, groups, "key", "", nil, false, "team-a")
+		result, err := svc.CreateAPIKey(ctx, user, groups, "key", "", nil, false, "team-a", nil)
 		require.NoError(t, err)
 		require.Equal(t, "team-a", result.Subscription)
 
@@ -522,7 +527,7 @@ func TestCreateAPIKey_Subscription(t *testing.T) {
 		store := api_keys.NewMockStore()
 		svc := api_keys.NewServiceWithLogger(store, cfg, subSelectorStub{}, logger.Development())
 
-		result, err := svc.CreateAPIKey(ctx, user, groups, "key", "", nil, false, "")
+		result, err := svc.CreateAPIKey(ctx, user, groups, "key", "", nil, false, "", nil)
 		require.NoError(t, err)
 		require.Equal(t, "from-priority", result.Subscription)
 	})
@@ -577,7 +582,7 @@ func TestCreateAPIKey_Subscription(t *testing.T) {
 				store := api_keys.NewMockStore()
 				svc := api_keys.NewServiceWithLogger(store, cfg, tt.stub, logger.Development())
 
-				result, err := svc.CreateAPIKey(ctx, user, groups, "key", "", nil, false, tt.requested)
+				result, err := svc.CreateAPIKey(ctx, user, groups, "key", "", nil, false, tt.requested, nil)
 				require.Error(t, err)
 				require.Nil(t, result)
 				tt.assertErr(t, err)
@@ -591,6 +596,40 @@ func TestCreateAPIKey_Subscription(t *testing.T) {
 	})
 }
 
+func TestCreateAPIKey_ModelScope(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	groups := []string{"g"}
+
+	t.Run("scope_is_stored_and_returned_on_validation", func(t *testing.T) {
+		store := api_keys.NewMockStore()
+		svc := api_keys.NewServiceWithLogger(store, cfg, subSelectorStub{}, logger.Development())
+
+		result, err := svc.CreateAPIKey(ctx, "u", groups, "nb", "", nil, true, "team-a",
+			[]string{" llm/granite ", "llm/granite", "llm/mistral"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"llm/granite", "llm/mistral"}, result.Models)
+
+		validation, err := svc.ValidateAPIKey(ctx, result.Key)
+		require.NoError(t, err)
+		require.True(t, validation.Valid)
+		assert.Equal(t, []string{"llm/granite", "llm/mistral"}, validation.Models)
+	})
+
+	t.Run("rejects_bare_model_names", func(t *testing.T) {
+		svc := api_keys.NewServiceWithLogger(api_keys.NewMockStore(), cfg, subSelectorStub{}, logger.Development())
+		_, err := svc.CreateAPIKey(ctx, "u", groups, "nb", "", nil, true, "team-a", []string{"granite"})
+		require.ErrorIs(t, err, api_keys.ErrInvalidModelScope)
+	})
+
+	t.Run("rejects_models_outside_the_subscription", func(t *testing.T) {
+		svc := api_keys.NewServiceWithLogger(api_keys.NewMockStore(), cfg, subSelectorStub{excludedModel: "llm/mistral"}, logger.Development())
+		_, err := svc.CreateAPIKey(ctx, "u", groups, "nb", "", nil, true, "team-a", []string{"llm/granite", "llm/mistral"})
+		var target *subscription.ModelNotInSubscriptionError
+		require.ErrorAs(t, err, &target)
+	})
+}
+
 // ============================================================
 // CLEANUP EXPIRED EPHEMERAL KEYS TESTS
 // ============================================================
@@ -602,12 +641,12 @@ func TestCleanupExpiredEphemeral(t *testing.T) {
 		svc, store := createTestService(t)
 
 		// Add active regular key
-		err := store.AddKey(ctx, "alice", "regular-1", "hash-1", "Regular", "", nil, "default-sub", nil, false)
+		err := store.AddKey(ctx, "alice", "regular-1", "hash-1", "Regular", "", nil, "default-sub", nil, nil, false)
 		require.NoError(t, err)
 
 		// Add expired ephemeral key
 		pastExpiry := time.Now().Add(-1 * time.Hour)
-		err = store.AddKey(ctx, "alice", "ephemeral-1", "hash-2", "Ephemeral", "", nil, "default-sub", &pastExpiry, true)
+		err = store.AddKey(ctx, "alice", "ephemeral-1", "hash-2", "Ephemeral", "", nil, "default-sub", nil, &pastExpiry, true)
 		require.NoError(t, err)
 
 		count, err := svc.CleanupExpiredEphemeral(ctx)
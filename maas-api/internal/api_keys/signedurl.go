@@ -0,0 +1,32 @@
+package api_keys
+
+import (
+	"errors"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+)
+
+// ValidateSignedURL returns the identity of the signed URL in a request path, as the validation of
+// an API key of its issuer scoped to its model would. The key ID is the signed URL's ID.
+func ValidateSignedURL(signer *signedurl.Signer, path string) *ValidationResult {
+	token := signedurl.FromPath(path)
+	if token == "" {
+		return &ValidationResult{Valid: false, Reason: "signed URL not found"}
+	}
+	grant, err := signer.Verify(token)
+	if errors.Is(err, signedurl.ErrExpired) {
+		return &ValidationResult{Valid: false, Reason: "signed URL expired"}
+	}
+	if err != nil {
+		return &ValidationResult{Valid: false, Reason: "invalid signed URL"}
+	}
+	return &ValidationResult{
+		Valid:        true,
+		UserID:       grant.User,
+		Username:     grant.User,
+		KeyID:        grant.ID,
+		Groups:       grant.Groups,
+		Subscription: grant.Subscription,
+		Models:       []string{grant.Model},
+	}
+}
@@ -0,0 +1,117 @@
+package api_keys
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Cipher encrypts the free-text fields of stored keys. It is implemented by kms.Envelope.
+// Values are bound to aad, the ID of the key they are stored with, and only open with it.
+type Cipher interface {
+	Encrypt(ctx context.Context, plaintext, aad string) (string, error)
+	// Decrypt must return values that were never encrypted unchanged.
+	Decrypt(ctx context.Context, value, aad string) (string, error)
+	// Rewrap re-encrypts a value under the current key, encrypting plaintext values.
+	Rewrap(ctx context.Context, value, aad string) (string, error)
+}
+
+// descriptionRewriter is implemented by stores that can rewrite every stored description in place.
+type descriptionRewriter interface {
+	RewriteDescriptions(ctx context.Context, rewrite func(ctx context.Context, id, description string) (string, error)) (int64, error)
+}
+
+// EncryptedStore wraps a MetadataStore and encrypts key descriptions at rest.
+// Key material is never stored (only its hash), so the description is the only user-supplied
+// free text; names stay in plaintext because they are searched and sorted by the database.
+type EncryptedStore struct {
+	MetadataStore
+	cipher Cipher
+}
+
+// NewEncryptedStore wraps store so that descriptions are encrypted with cipher.
+func NewEncryptedStore(store MetadataStore, cipher Cipher) *EncryptedStore {
+	if store == nil {
+		panic("store cannot be nil")
+	}
+	if cipher == nil {
+		panic("cipher cannot be nil")
+	}
+	return &EncryptedStore{MetadataStore: store, cipher: cipher}
+}
+
+// AddKey encrypts the description, bound to keyID, before storing the key.
+func (s *EncryptedStore) AddKey(
+	ctx context.Context, username, keyID, keyHash, name, description string, userGroups []string, subscription string, models []string, expiresAt *time.Time, ephemeral bool,
+) error {
+	encrypted, err := s.cipher.Encrypt(ctx, description, keyID)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt description: %w", err)
+	}
+	return s.MetadataStore.AddKey(ctx, username, keyID, keyHash, name, encrypted, userGroups, subscription, models, expiresAt, ephemeral)
+}
+
+// Search decrypts the descriptions of the returned keys.
+func (s *EncryptedStore) Search(
+	ctx context.Context,
+	username string,
+	filters *SearchFilters,
+	sort *SortParams,
+	pagination *PaginationParams,
+) (*PaginatedResult, error) {
+	result, err := s.MetadataStore.Search(ctx, username, filters, sort, pagination)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Keys {
+		if err := s.decrypt(ctx, &result.Keys[i]); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Get decrypts the description of the returned key.
+func (s *EncryptedStore) Get(ctx context.Context, jti string) (*ApiKey, error) {
+	key, err := s.MetadataStore.Get(ctx, jti)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.decrypt(ctx, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// GetByHash returns the key without decrypting its description: validation does not use it, and
+// skipping it keeps the KMS out of the per-request path.
+func (s *EncryptedStore) GetByHash(ctx context.Context, keyHash string) (*ApiKey, error) {
+	key, err := s.MetadataStore.GetByHash(ctx, keyHash)
+	if err != nil {
+		return nil, err
+	}
+	key.Description = ""
+	return key, nil
+}
+
+// Rewrap re-encrypts every stored description under the cipher's current key and encrypts those
+// stored before encryption was enabled. Run it after rotating the KMS key.
+// Returns the count of descriptions rewritten.
+func (s *EncryptedStore) Rewrap(ctx context.Context) (int64, error) {
+	rewriter, ok := s.MetadataStore.(descriptionRewriter)
+	if !ok {
+		return 0, fmt.Errorf("store %T does not support rewrapping", s.MetadataStore)
+	}
+	return rewriter.RewriteDescriptions(ctx, func(ctx context.Context, id, description string) (string, error) {
+		return s.cipher.Rewrap(ctx, description, id)
+	})
+}
+
+func (s *EncryptedStore) decrypt(ctx context.Context, key *ApiKey) error {
+	description, err := s.cipher.Decrypt(ctx, key.Description, key.ID)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt description of key %s: %w", key.ID, err)
+	}
+	key.Description = description
+	return nil
+}
@@ -0,0 +1,83 @@
+package api_keys_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/kms"
+)
+
+func newTestEnvelope(t *testing.T) *kms.Envelope {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte("k1:"+base64.StdEncoding.EncodeToString(key)), 0o600))
+	provider, err := kms.NewLocalKeyFile(path)
+	require.NoError(t, err)
+	return kms.NewEnvelope(provider)
+}
+
+func TestEncryptedStore(t *testing.T) {
+	ctx := context.Background()
+	inner := api_keys.NewMockStore()
+	store := api_keys.NewEncryptedStore(inner, newTestEnvelope(t))
+
+	require.NoError(t, store.AddKey(ctx, "alice", "key-1", "hash-1", "ci", "deploys prod from ci-runner-7",
+		[]string{"team-a"}, "free", nil, nil, false))
+
+	raw, err := inner.Get(ctx, "key-1")
+	require.NoError(t, err)
+	assert.True(t, kms.IsEncrypted(raw.Description), "the wrapped store only sees ciphertext")
+
+	key, err := store.Get(ctx, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "deploys prod from ci-runner-7", key.Description)
+
+	result, err := store.Search(ctx, "alice", &api_keys.SearchFilters{}, &api_keys.SortParams{By: "created_at", Order: "desc"},
+		&api_keys.PaginationParams{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, result.Keys, 1)
+	assert.Equal(t, "deploys prod from ci-runner-7", result.Keys[0].Description)
+
+	validated, err := store.GetByHash(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Empty(t, validated.Description, "validation does not decrypt")
+
+	// A description copied to another key's row does not decrypt there.
+	require.NoError(t, inner.AddKey(ctx, "alice", "key-2", "hash-2", "copy", raw.Description, nil, "free", nil, nil, false))
+	_, err = store.Get(ctx, "key-2")
+	require.Error(t, err)
+}
+
+func TestEncryptedStoreRewrapBackfillsPlaintext(t *testing.T) {
+	ctx := context.Background()
+	inner := api_keys.NewMockStore()
+	require.NoError(t, inner.AddKey(ctx, "alice", "legacy", "hash-1", "legacy", "stored in plaintext", nil, "free", nil, nil, false))
+	require.NoError(t, inner.AddKey(ctx, "alice", "no-description", "hash-2", "bare", "", nil, "free", nil, nil, false))
+
+	store := api_keys.NewEncryptedStore(inner, newTestEnvelope(t))
+	key, err := store.Get(ctx, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "stored in plaintext", key.Description, "plaintext written before encryption stays readable")
+
+	count, err := store.Rewrap(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	raw, err := inner.Get(ctx, "legacy")
+	require.NoError(t, err)
+	assert.True(t, kms.IsEncrypted(raw.Description))
+	key, err = store.Get(ctx, "legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "stored in plaintext", key.Description)
+}
@@ -16,6 +16,9 @@ var (
 	// Expiration validation errors.
 	ErrExpirationNotPositive = errors.New("expiration must be positive")
 	ErrExpirationExceedsMax  = errors.New("expiration exceeds maximum allowed")
+
+	// ErrInvalidModelScope is returned when a key's model scope is not a list of namespace/name references.
+	ErrInvalidModelScope = errors.New("models must be MaaSModelRef references in namespace/name form")
 )
 
 // Legacy constants for backward compatibility with database operations.
@@ -35,10 +38,11 @@ type MetadataStore interface {
 	//   - keyHash: SHA-256(embedded_key_id + "\x00" + secret), where embedded_key_id is the
 	//     per-key salt encoded in the API key format (sk-oai-{embedded_key_id}_{secret})
 	//   - userGroups: array of user's groups (used for authorization)
+	//   - models: MaaSModelRefs (namespace/name) the key is scoped to; empty for every model of the subscription
 	//   - ephemeral: marks the key as short-lived for programmatic use
 	//
 	// Note: keyPrefix is NOT stored (security - reduces brute-force attack surface).
-	AddKey(ctx context.Context, username string, keyID, keyHash, name, description string, userGroups []string, subscription string, expiresAt *time.Time, ephemeral bool) error
+	AddKey(ctx context.Context, username string, keyID, keyHash, name, description string, userGroups []string, subscription string, models []string, expiresAt *time.Time, ephemeral bool) error
 
 	// Search returns API keys matching the search criteria.
 	// Supports filtering, sorting, and pagination.
@@ -74,5 +78,17 @@ type MetadataStore interface {
 	// Returns the count of deleted keys.
 	DeleteExpiredEphemeral(ctx context.Context) (int64, error)
 
+	// PurgeInactive removes revoked and expired keys whose last activity (creation, last use,
+	// expiry or revocation) is before cutoff. With dryRun nothing is deleted.
+	// Returns the count of keys that were (or would be) deleted.
+	PurgeInactive(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error)
+
+	// ActiveKeysBySubscription returns the number of active keys bound to each subscription name.
+	ActiveKeysBySubscription(ctx context.Context) (map[string]int64, error)
+
+	// RevokeBySubscription revokes every active key bound to the named subscription.
+	// Returns the count of keys that were revoked.
+	RevokeBySubscription(ctx context.Context, subscription string) (int64, error)
+
 	Close() error
 }
@@ -23,6 +23,7 @@ type storedKey struct {
 	keyHash    string
 	expiresAt  time.Time
 	lastUsedAt *time.Time
+	revokedAt  time.Time
 	ephemeral  bool
 }
 
@@ -41,7 +42,7 @@ var _ MetadataStore = (*MockStore)(nil)
 // ephemeral marks the key as short-lived for programmatic use.
 // Note: keyPrefix is NOT stored (security - reduces brute-force attack surface).
 func (m *MockStore) AddKey(
-	ctx context.Context, username, keyID, keyHash, name, description string, userGroups []string, subscription string, expiresAt *time.Time, ephemeral bool,
+	ctx context.Context, username, keyID, keyHash, name, description string, userGroups []string, subscription string, models []string, expiresAt *time.Time, ephemeral bool,
 ) error {
 	if keyID == "" {
 		return ErrEmptyJTI
@@ -68,6 +69,7 @@ func (m *MockStore) AddKey(
 			Name:         name,
 			Description:  description,
 			Subscription: subscription,
+			Models:       models,
 			Groups:       userGroups,
 			Status:       StatusActive,
 			CreationDate: time.Now().UTC().Format(time.RFC3339),
@@ -165,12 +167,15 @@ func (m *MockStore) List(ctx context.Context, username string, params Pagination
 }
 
 // filterKeys applies username, status, and ephemeral filters to API keys.
-func (m *MockStore) filterKeys(username string, statusFilters []string, includeEphemeral bool, now time.Time) []ApiKey {
+func (m *MockStore) filterKeys(username string, statusFilters []string, includeEphemeral, onlyEphemeral bool, now time.Time) []ApiKey {
 	filtered := make([]ApiKey, 0, len(m.keys))
 
 	for _, k := range m.keys {
 		// Filter ephemeral keys unless explicitly included
-		if !includeEphemeral && k.ephemeral {
+		if !includeEphemeral && !onlyEphemeral && k.ephemeral {
+			continue
+		}
+		if onlyEphemeral && !k.ephemeral {
 			continue
 		}
 
@@ -329,10 +334,11 @@ func (m *MockStore) Search(
 
 	// Determine if ephemeral keys should be included
 	includeEphemeral := filters.IncludeEphemeral != nil && *filters.IncludeEphemeral
+	onlyEphemeral := filters.OnlyEphemeral != nil && *filters.OnlyEphemeral
 
 	// Filter keys by username, status, and ephemeral
 	now := time.Now().UTC()
-	allKeys := m.filterKeys(username, filters.Status, includeEphemeral, now)
+	allKeys := m.filterKeys(username, filters.Status, includeEphemeral, onlyEphemeral, now)
 
 	// Sort keys
 	sort.Slice(allKeys, func(i, j int) bool {
@@ -411,9 +417,11 @@ func (m *MockStore) InvalidateAll(ctx context.Context, username string) (int, er
 	defer m.mu.Unlock()
 
 	count := 0
+	now := time.Now().UTC()
 	for _, k := range m.keys {
 		if k.username == username && k.metadata.Status == StatusActive {
 			k.metadata.Status = StatusRevoked
+			k.revokedAt = now
 			count++
 		}
 	}
@@ -436,6 +444,7 @@ func (m *MockStore) Revoke(ctx context.Context, keyID string) error {
 	}
 
 	k.metadata.Status = StatusRevoked
+	k.revokedAt = time.Now().UTC()
 	return nil
 }
 
@@ -482,3 +491,89 @@ func (m *MockStore) DeleteExpiredEphemeral(ctx context.Context) (int64, error) {
 func (m *MockStore) Close() error {
 	return nil
 }
+
+// PurgeInactive removes revoked and expired keys whose last activity is before cutoff.
+// Mirrors PostgresStore: the last activity is the latest of creation, last use, expiry and revocation.
+func (m *MockStore) PurgeInactive(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	var count int64
+	for id, k := range m.keys {
+		expired := !k.expiresAt.IsZero() && k.expiresAt.Before(now)
+		if k.metadata.Status == StatusActive && !expired {
+			continue
+		}
+		last, _ := time.Parse(time.RFC3339, k.metadata.CreationDate)
+		for _, t := range []time.Time{k.expiresAt, k.revokedAt} {
+			if t.After(last) {
+				last = t
+			}
+		}
+		if k.lastUsedAt != nil && k.lastUsedAt.After(last) {
+			last = *k.lastUsedAt
+		}
+		if !last.Before(cutoff) {
+			continue
+		}
+		count++
+		if !dryRun {
+			delete(m.keys, id)
+		}
+	}
+	return count, nil
+}
+
+// ActiveKeysBySubscription returns the number of active keys bound to each subscription.
+func (m *MockStore) ActiveKeysBySubscription(ctx context.Context) (map[string]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := map[string]int64{}
+	for _, k := range m.keys {
+		if k.metadata.Status == StatusActive {
+			counts[k.metadata.Subscription]++
+		}
+	}
+	return counts, nil
+}
+
+// RevokeBySubscription revokes every active key bound to the subscription.
+func (m *MockStore) RevokeBySubscription(ctx context.Context, subscription string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now().UTC()
+	var count int64
+	for _, k := range m.keys {
+		if k.metadata.Status == StatusActive && k.metadata.Subscription == subscription {
+			k.metadata.Status = StatusRevoked
+			k.revokedAt = now
+			count++
+		}
+	}
+	return count, nil
+}
+
+// RewriteDescriptions replaces every non-empty description with rewrite(id, description).
+func (m *MockStore) RewriteDescriptions(
+	ctx context.Context, rewrite func(ctx context.Context, id, description string) (string, error),
+) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int64
+	for _, k := range m.keys {
+		if k.metadata.Description == "" {
+			continue
+		}
+		rewritten, err := rewrite(ctx, k.metadata.ID, k.metadata.Description)
+		if err != nil {
+			return count, err
+		}
+		k.metadata.Description = rewritten
+		count++
+	}
+	return count, nil
+}
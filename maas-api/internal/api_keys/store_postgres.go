@@ -35,6 +35,11 @@ func NewPostgresStore(db *sql.DB, log *logger.Logger) *PostgresStore {
 	}
 }
 
+// DB returns the connection pool, which the usage ledger shares.
+func (s *PostgresStore) DB() *sql.DB {
+	return s.db
+}
+
 // AddKey stores an API key with hash-only storage (no plaintext).
 // Keys can be permanent (expiresAt=nil) or expiring (expiresAt set).
 // ephemeral marks the key as short-lived for programmatic use.
@@ -46,7 +51,7 @@ func NewPostgresStore(db *sql.DB, log *logger.Logger) *PostgresStore {
 //
 // Note: keyPrefix is NOT stored (security - reduces brute-force attack surface).
 func (s *PostgresStore) AddKey(
-	ctx context.Context, username, keyID, keyHash, name, description string, userGroups []string, subscription string, expiresAt *time.Time, ephemeral bool,
+	ctx context.Context, username, keyID, keyHash, name, description string, userGroups []string, subscription string, models []string, expiresAt *time.Time, ephemeral bool,
 ) error {
 	if keyID == "" {
 		return ErrEmptyJTI
@@ -66,13 +71,17 @@ func (s *PostgresStore) AddKey(
 	if userGroups == nil {
 		userGroups = []string{}
 	}
+	if models == nil {
+		models = []string{}
+	}
 
 	query := `
-		INSERT INTO api_keys (id, username, name, description, key_hash, user_groups, subscription, status, created_at, expires_at, ephemeral)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, 'active', $8, $9, $10)
+		INSERT INTO api_keys (id, username, name, description, key_hash, user_groups, subscription, models, status, created_at, expires_at, ephemeral)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 'active', $9, $10, $11)
 	`
 	// Use pq.Array to handle PostgreSQL TEXT[] type
-	_, err := s.db.ExecContext(ctx, query, keyID, username, name, description, keyHash, pq.Array(userGroups), subscription, time.Now().UTC(), expiresAt, ephemeral)
+	_, err := s.db.ExecContext(ctx, query, keyID, username, name, description, keyHash, pq.Array(userGroups), subscription, pq.Array(models),
+		time.Now().UTC(), expiresAt, ephemeral)
 	if err != nil {
 		return fmt.Errorf("failed to insert API key: %w", err)
 	}
@@ -210,7 +219,10 @@ func (s *PostgresStore) Search(
 	argPos := 1
 
 	// Exclude ephemeral keys by default
-	if filters.IncludeEphemeral == nil || !*filters.IncludeEphemeral {
+	switch {
+	case filters.OnlyEphemeral != nil && *filters.OnlyEphemeral:
+		whereClauses = append(whereClauses, "ephemeral = TRUE")
+	case filters.IncludeEphemeral == nil || !*filters.IncludeEphemeral:
 		whereClauses = append(whereClauses, "ephemeral = FALSE")
 	}
 
@@ -255,7 +267,7 @@ func (s *PostgresStore) Search(
 
 	//nolint:gosec // Dynamic ORDER BY is safe - sort.By/Order validated against allowlist in handler
 	query := fmt.Sprintf(`
-		SELECT id, name, description, subscription, username, created_at, expires_at, status, last_used_at, ephemeral
+		SELECT id, name, description, subscription, models, username, created_at, expires_at, status, last_used_at, ephemeral
 		FROM api_keys
 		%s
 		%s
@@ -282,6 +294,7 @@ func (s *PostgresStore) Search(
 			&key.Name,
 			&description,
 			&key.Subscription,
+			pq.Array(&key.Models),
 			&key.Username,
 			&createdAt,
 			&expiresAt,
@@ -329,7 +342,7 @@ func (s *PostgresStore) Search(
 // Get retrieves a single API key by ID.
 func (s *PostgresStore) Get(ctx context.Context, keyID string) (*ApiKey, error) {
 	query := `
-		SELECT id, name, description, username, subscription, created_at, expires_at, status, last_used_at, ephemeral
+		SELECT id, name, description, username, subscription, models, created_at, expires_at, status, last_used_at, ephemeral
 		FROM api_keys
 		WHERE id = $1
 	`
@@ -340,7 +353,7 @@ func (s *PostgresStore) Get(ctx context.Context, keyID string) (*ApiKey, error)
 	var expiresAt, lastUsedAt sql.NullTime
 	var description sql.NullString
 
-	if err := row.Scan(&k.ID, &k.Name, &description, &k.Username, &k.Subscription, &createdAt, &expiresAt, &k.Status, &lastUsedAt, &k.Ephemeral); err != nil {
+	if err := row.Scan(&k.ID, &k.Name, &description, &k.Username, &k.Subscription, pq.Array(&k.Models), &createdAt, &expiresAt, &k.Status, &lastUsedAt, &k.Ephemeral); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrKeyNotFound
 		}
@@ -364,7 +377,7 @@ func (s *PostgresStore) Get(ctx context.Context, keyID string) (*ApiKey, error)
 // GetByHash looks up an API key by its SHA-256 hash (critical path for validation).
 func (s *PostgresStore) GetByHash(ctx context.Context, keyHash string) (*ApiKey, error) {
 	query := `
-		SELECT id, username, name, description, user_groups, subscription, status, expires_at, last_used_at, ephemeral
+		SELECT id, username, name, description, user_groups, subscription, models, status, expires_at, last_used_at, ephemeral
 		FROM api_keys
 		WHERE key_hash = $1
 	`
@@ -376,7 +389,7 @@ func (s *PostgresStore) GetByHash(ctx context.Context, keyHash string) (*ApiKey,
 	var userGroups []string
 
 	// Use pq.Array to scan PostgreSQL TEXT[] into []string
-	if err := row.Scan(&k.ID, &k.Username, &k.Name, &description, pq.Array(&userGroups), &k.Subscription, &k.Status, &expiresAt, &lastUsedAt, &k.Ephemeral); err != nil {
+	if err := row.Scan(&k.ID, &k.Username, &k.Name, &description, pq.Array(&userGroups), &k.Subscription, pq.Array(&k.Models), &k.Status, &expiresAt, &lastUsedAt, &k.Ephemeral); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrKeyNotFound
 		}
@@ -416,7 +429,7 @@ func (s *PostgresStore) GetByHash(ctx context.Context, keyHash string) (*ApiKey,
 // InvalidateAll revokes all active keys for a user.
 // Returns the count of keys that were revoked.
 func (s *PostgresStore) InvalidateAll(ctx context.Context, username string) (int, error) {
-	query := `UPDATE api_keys SET status = 'revoked' WHERE username = $1 AND status = 'active'`
+	query := `UPDATE api_keys SET status = 'revoked', revoked_at = NOW() WHERE username = $1 AND status = 'active'`
 
 	result, err := s.db.ExecContext(ctx, query, username)
 	if err != nil {
@@ -435,7 +448,7 @@ func (s *PostgresStore) InvalidateAll(ctx context.Context, username string) (int
 
 // Revoke marks a specific API key as revoked.
 func (s *PostgresStore) Revoke(ctx context.Context, keyID string) error {
-	query := `UPDATE api_keys SET status = 'revoked' WHERE id = $1 AND status = 'active'`
+	query := `UPDATE api_keys SET status = 'revoked', revoked_at = NOW() WHERE id = $1 AND status = 'active'`
 	result, err := s.db.ExecContext(ctx, query, keyID)
 	if err != nil {
 		return fmt.Errorf("failed to revoke key: %w", err)
@@ -486,6 +499,115 @@ func (s *PostgresStore) DeleteExpiredEphemeral(ctx context.Context) (int64, erro
 	return rows, nil
 }
 
+// inactiveKeysWhere matches keys that can no longer be used and whose last activity (creation,
+// last use, expiry or revocation) is before $1. GREATEST ignores NULLs.
+const inactiveKeysWhere = `(status <> 'active' OR (expires_at IS NOT NULL AND expires_at < NOW()))
+	AND GREATEST(created_at, last_used_at, expires_at, revoked_at) < $1`
+
+// PurgeInactive deletes revoked and expired keys whose last activity is before cutoff.
+// With dryRun it only counts them.
+func (s *PostgresStore) PurgeInactive(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	if dryRun {
+		var count int64
+		if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM api_keys WHERE `+inactiveKeysWhere, cutoff).Scan(&count); err != nil {
+			return 0, fmt.Errorf("failed to count inactive keys: %w", err)
+		}
+		return count, nil
+	}
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM api_keys WHERE `+inactiveKeysWhere, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete inactive keys: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return rows, nil
+}
+
+// ActiveKeysBySubscription returns the number of active keys bound to each subscription.
+func (s *PostgresStore) ActiveKeysBySubscription(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT subscription, COUNT(*) FROM api_keys WHERE status = 'active' GROUP BY subscription`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count keys by subscription: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[string]int64{}
+	for rows.Next() {
+		var subscription string
+		var count int64
+		if err := rows.Scan(&subscription, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription count: %w", err)
+		}
+		counts[subscription] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate subscription counts: %w", err)
+	}
+	return counts, nil
+}
+
+// RevokeBySubscription revokes every active key bound to the subscription.
+func (s *PostgresStore) RevokeBySubscription(ctx context.Context, subscription string) (int64, error) {
+	query := `UPDATE api_keys SET status = 'revoked', revoked_at = NOW() WHERE subscription = $1 AND status = 'active'`
+	result, err := s.db.ExecContext(ctx, query, subscription)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke keys for subscription: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows > 0 {
+		s.logger.Info("Revoked keys for subscription", "count", rows, "subscription", subscription)
+	}
+	return rows, nil
+}
+
+// RewriteDescriptions replaces every non-empty description with rewrite(id, description).
+// A row whose description changed concurrently is left alone.
+func (s *PostgresStore) RewriteDescriptions(
+	ctx context.Context, rewrite func(ctx context.Context, id, description string) (string, error),
+) (int64, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, description FROM api_keys WHERE description IS NOT NULL AND description <> ''`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list descriptions: %w", err)
+	}
+	type row struct{ id, description string }
+	var all []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.description); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan description: %w", err)
+		}
+		all = append(all, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to iterate descriptions: %w", err)
+	}
+
+	var count int64
+	for _, r := range all {
+		rewritten, err := rewrite(ctx, r.id, r.description)
+		if err != nil {
+			return count, fmt.Errorf("failed to rewrite description of key %s: %w", r.id, err)
+		}
+		result, err := s.db.ExecContext(ctx,
+			`UPDATE api_keys SET description = $2 WHERE id = $1 AND description = $3`, r.id, rewritten, r.description)
+		if err != nil {
+			return count, fmt.Errorf("failed to update description: %w", err)
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			count += n
+		}
+	}
+	return count, nil
+}
+
 // Close closes the database connection.
 // This should be called during graceful shutdown to prevent connection leaks.
 func (s *PostgresStore) Close() error {
@@ -25,6 +25,7 @@ type ApiKey struct {
 	Description    string   `json:"description,omitempty"`
 	Username       string   `json:"username,omitempty"`
 	Subscription   string   `json:"subscription,omitempty"`   // MaaSSubscription name bound at mint time
+	Models         []string `json:"models,omitempty"`         // Models (namespace/name) the key is scoped to; empty for all of the subscription's
 	Groups         []string `json:"groups,omitempty"`         // User's groups at creation (immutable snapshot for authorization)
 	CreationDate   string   `json:"creationDate"`
 	ExpirationDate string   `json:"expirationDate,omitempty"` // Empty for permanent keys
@@ -41,6 +42,7 @@ type ValidationResult struct {
 	KeyID        string   `json:"keyId,omitempty"`
 	Groups       []string `json:"groups,omitempty"`       // User groups for subscription-based authorization
 	Subscription string   `json:"subscription,omitempty"` // MaaSSubscription name from DB (Authorino → subscription-info)
+	Models       []string `json:"models,omitempty"`       // Models (namespace/name) the key is scoped to; empty for all
 	Reason       string   `json:"reason,omitempty"`       // If invalid: "key not found", "revoked", etc.
 }
 
@@ -88,8 +90,9 @@ type SearchFilters struct {
 	HasExpiration *bool `json:"hasExpiration,omitempty"` // true = expiring, false = permanent
 	HasBeenUsed   *bool `json:"hasBeenUsed,omitempty"`   // true = used, false = never used
 
-	// Ephemeral key filter
+	// Ephemeral key filters
 	IncludeEphemeral *bool `json:"includeEphemeral,omitempty"` // Include ephemeral keys in results (default: false)
+	OnlyEphemeral    *bool `json:"onlyEphemeral,omitempty"`    // Return only ephemeral keys, e.g. for GET /v1/tokens (default: false)
 }
 
 // SortParams specifies sorting criteria.
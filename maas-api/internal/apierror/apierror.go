@@ -0,0 +1,210 @@
+// Package apierror defines the error envelope of maas-api's HTTP API. Every error response has
+// the body
+//
+//	{"error": {"code": "TIER_DENIED", "message": "...", "type": "permission_error", "details": {...}, "requestID": "..."}}
+//
+// Code is machine-readable, so gateway policies and clients can tell causes apart without
+// matching the message. Type is the OpenAI error type, kept so OpenAI clients still parse the
+// error. Details and requestID are omitted when empty.
+//
+// Codes are stable: once released a code keeps its meaning and is never removed.
+package apierror
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// Code is a machine-readable error code.
+type Code string
+
+// Error codes.
+const (
+	// InvalidRequest: the query or body is malformed or fails validation.
+	InvalidRequest Code = "INVALID_REQUEST"
+	// InvalidPath: a path parameter is missing or malformed.
+	InvalidPath Code = "INVALID_PATH"
+	// Unauthenticated: the request carries no valid credentials.
+	Unauthenticated Code = "UNAUTHENTICATED"
+	// PermissionDenied: the caller may not perform the operation, e.g. an admin-only endpoint.
+	PermissionDenied Code = "PERMISSION_DENIED"
+	// TierDenied: none of the caller's subscriptions (tiers) grants access, or the caller may not
+	// use the requested one.
+	TierDenied Code = "TIER_DENIED"
+	// SubscriptionNotFound: the caller has no subscription, or the requested one does not exist.
+	SubscriptionNotFound Code = "SUBSCRIPTION_NOT_FOUND"
+	// SubscriptionRequired: the caller has several subscriptions and must pick one.
+	SubscriptionRequired Code = "SUBSCRIPTION_REQUIRED"
+	// ModelNotFound: the model does not exist or is not served.
+	ModelNotFound Code = "MODEL_NOT_FOUND"
+	// NotFound: another resource, such as an API key, does not exist.
+	NotFound Code = "NOT_FOUND"
+	// UnknownEndpoint: the path after the model is not an API suffix maas-api allows.
+	UnknownEndpoint Code = "UNKNOWN_ENDPOINT"
+	// InvalidSubscription: the subscription for a new API key cannot be resolved. It does not say
+	// whether the subscription is missing or denied, so it cannot be used to enumerate them.
+	InvalidSubscription Code = "INVALID_SUBSCRIPTION"
+	// InvalidModelScope: an API key is scoped to a model that is malformed or outside its
+	// subscription.
+	InvalidModelScope Code = "INVALID_MODEL_SCOPE"
+	// ContextLengthExceeded: the prompt, plus the completion tokens requested, does not fit the
+	// model's context window.
+	ContextLengthExceeded Code = "CONTEXT_LENGTH_EXCEEDED"
+	// Conflict: the resource already exists or was changed concurrently.
+	Conflict Code = "CONFLICT"
+	// QuotaExhausted: the caller's token budget is spent.
+	QuotaExhausted Code = "QUOTA_EXHAUSTED"
+	// RateLimited: the caller sent too many requests.
+	RateLimited Code = "RATE_LIMITED"
+	// ReadOnly: maas-api is in read-only mode for maintenance.
+	ReadOnly Code = "READ_ONLY"
+	// Unavailable: a backend or dependency is unreachable.
+	Unavailable Code = "UNAVAILABLE"
+	// InternalError: maas-api failed to handle the request.
+	InternalError Code = "INTERNAL_ERROR"
+)
+
+// openAITypes maps codes to the OpenAI error type.
+var openAITypes = map[Code]string{
+	InvalidRequest:        "invalid_request_error",
+	InvalidPath:           "invalid_request_error",
+	Unauthenticated:       "authentication_error",
+	PermissionDenied:      "permission_error",
+	TierDenied:            "permission_error",
+	SubscriptionNotFound:  "permission_error",
+	SubscriptionRequired:  "permission_error",
+	ModelNotFound:         "invalid_request_error",
+	NotFound:              "invalid_request_error",
+	UnknownEndpoint:       "invalid_request_error",
+	InvalidSubscription:   "invalid_request_error",
+	InvalidModelScope:     "invalid_request_error",
+	ContextLengthExceeded: "invalid_request_error",
+	Conflict:              "invalid_request_error",
+	QuotaExhausted:        "rate_limit_error",
+	RateLimited:           "rate_limit_error",
+	ReadOnly:              "read_only",
+	Unavailable:           "server_error",
+	InternalError:         "server_error",
+}
+
+// Type returns the OpenAI error type of the code.
+func (c Code) Type() string {
+	if t, ok := openAITypes[c]; ok {
+		return t
+	}
+	return "server_error"
+}
+
+// reasonCodes maps the denial reasons of package reason to error codes.
+var reasonCodes = map[string]Code{
+	reason.Unauthenticated:           Unauthenticated,
+	reason.SignatureRequired:         Unauthenticated,
+	reason.InvalidSignature:          Unauthenticated,
+	reason.StaleSignature:            Unauthenticated,
+	reason.ReplayedRequest:           Unauthenticated,
+	reason.Unauthorized:              TierDenied,
+	reason.AccessDenied:              TierDenied,
+	reason.ModelNotInKeyScope:        PermissionDenied,
+	reason.HostMismatch:              TierDenied,
+	reason.HookDenied:                PermissionDenied,
+	reason.PolicyDenied:              PermissionDenied,
+	reason.EndpointNotAllowed:        TierDenied,
+	reason.NotFound:                  SubscriptionNotFound,
+	reason.MultipleSubscriptions:     SubscriptionRequired,
+	reason.ModelNotInSubscription:    TierDenied,
+	reason.QuotaExhausted:            QuotaExhausted,
+	reason.RateLimited:               RateLimited,
+	reason.TooManyInFlight:           RateLimited,
+	reason.TooManyConcurrentRequests: RateLimited,
+	reason.ModelNotFound:             ModelNotFound,
+	reason.ModelDeleted:              ModelNotFound,
+	reason.ModelMaintenance:          Unavailable,
+	reason.ClusterUnavailable:        Unavailable,
+	reason.ModelAmbiguous:            InvalidRequest,
+	reason.MissingModel:              InvalidRequest,
+	reason.BadRequest:                InvalidRequest,
+	reason.UnsupportedEndpoint:       InvalidRequest,
+	reason.UnknownEndpoint:           UnknownEndpoint,
+	reason.TooManyAttachments:        InvalidRequest,
+	reason.AttachmentTooLarge:        InvalidRequest,
+	reason.ImageTooLarge:             InvalidRequest,
+	reason.AttachmentTypeNotAllowed:  InvalidRequest,
+	reason.ContextLengthExceeded:     ContextLengthExceeded,
+	reason.InternalError:             InternalError,
+	reason.HookFailed:                Unavailable,
+	reason.PolicyFailed:              Unavailable,
+}
+
+// FromReason returns the error code of a denial reason, or InternalError for an unknown one.
+func FromReason(r string) Code {
+	if code, ok := reasonCodes[r]; ok {
+		return code
+	}
+	return InternalError
+}
+
+// Error is the body of an error response, under "error".
+type Error struct {
+	Code      Code           `json:"code"`
+	Message   string         `json:"message"`
+	Type      string         `json:"type"`
+	Details   map[string]any `json:"details,omitempty"`
+	RequestID string         `json:"requestID,omitempty"`
+}
+
+// New creates an error with the OpenAI type of code.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Type: code.Type()}
+}
+
+// WithDetails adds machine-readable context, such as the offending field or model.
+func (e *Error) WithDetails(details map[string]any) *Error {
+	e.Details = details
+	return e
+}
+
+// WithType replaces the OpenAI type, for endpoints whose clients already rely on a specific one.
+func (e *Error) WithType(errType string) *Error {
+	e.Type = errType
+	return e
+}
+
+// WithRequestID sets the ID clients quote when reporting the error.
+func (e *Error) WithRequestID(id string) *Error {
+	e.RequestID = id
+	return e
+}
+
+// JSON returns the envelope, for responses written outside gin.
+func (e *Error) JSON() []byte {
+	body, _ := json.Marshal(gin.H{"error": e})
+	return body
+}
+
+// Respond writes e as the response with status. The request ID comes from the X-Request-Id
+// header the gateway sets.
+func (e *Error) Respond(c *gin.Context, status int) {
+	if e.RequestID == "" {
+		e.RequestID = c.GetHeader("X-Request-Id")
+	}
+	c.JSON(status, gin.H{"error": e})
+}
+
+// Abort is Respond for middleware: it also stops the remaining handlers.
+func (e *Error) Abort(c *gin.Context, status int) {
+	e.Respond(c, status)
+	c.Abort()
+}
+
+// Respond writes an error response with status, code and message.
+func Respond(c *gin.Context, status int, code Code, message string) {
+	New(code, message).Respond(c, status)
+}
+
+// Abort writes an error response with status, code and message, and stops the remaining handlers.
+func Abort(c *gin.Context, status int, code Code, message string) {
+	New(code, message).Abort(c, status)
+}
@@ -0,0 +1,59 @@
+package apierror_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/models/:name", func(c *gin.Context) {
+		apierror.New(apierror.ModelNotFound, "model granite not found").
+			WithDetails(map[string]any{"model": c.Param("name")}).
+			Respond(c, http.StatusNotFound)
+	})
+	router.GET("/admin", func(c *gin.Context) {
+		apierror.Abort(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models/granite", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"MODEL_NOT_FOUND","message":"model granite not found","type":"invalid_request_error",`+
+		`"details":{"model":"granite"},"requestID":"req-123"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":{"code":"PERMISSION_DENIED","message":"Admin access required","type":"permission_error"}}`, w.Body.String())
+}
+
+func TestFromReason(t *testing.T) {
+	assert.Equal(t, apierror.TierDenied, apierror.FromReason(reason.ModelNotInSubscription))
+	assert.Equal(t, apierror.TierDenied, apierror.FromReason(reason.AccessDenied))
+	assert.Equal(t, apierror.ModelNotFound, apierror.FromReason(reason.ModelDeleted))
+	assert.Equal(t, apierror.InternalError, apierror.FromReason("something_new"))
+	assert.Equal(t, apierror.PermissionDenied, apierror.FromReason(reason.PolicyDenied))
+	assert.Equal(t, apierror.Unavailable, apierror.FromReason(reason.PolicyFailed))
+}
+
+// TestEveryReasonHasCode fails when a denial reason is added without an error code, which would
+// answer it as INTERNAL_ERROR.
+func TestEveryReasonHasCode(t *testing.T) {
+	for _, code := range reason.Codes() {
+		if code == reason.InternalError {
+			continue
+		}
+		assert.NotEqual(t, apierror.InternalError, apierror.FromReason(code), "%s has no error code", code)
+	}
+}
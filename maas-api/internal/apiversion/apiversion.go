@@ -0,0 +1,75 @@
+// Package apiversion describes the versioned maas-api route groups and marks deprecated routes, so
+// breaking schema changes ship under a new version while clients migrate off the old one.
+package apiversion
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version statuses reported by GET /versions.
+const (
+	StatusStable     = "stable"
+	StatusPreview    = "preview"
+	StatusDeprecated = "deprecated"
+)
+
+// Version is one entry of the GET /versions discovery document.
+type Version struct {
+	Version string `json:"version"`
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+}
+
+// Discovery is the GET /versions response.
+type Discovery struct {
+	// Current is the version new clients should use.
+	Current  string    `json:"current"`
+	Versions []Version `json:"versions"`
+	// Deprecated lists the routes that send Deprecation headers and when they will be removed.
+	Deprecated []DeprecatedRoute `json:"deprecated"`
+}
+
+// DeprecatedRoute describes a route kept for compatibility after its replacement shipped.
+type DeprecatedRoute struct {
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Since     time.Time `json:"since"`
+	Sunset    time.Time `json:"sunset"`
+	Successor string    `json:"successor"`
+}
+
+// Deprecate returns middleware that announces route's deprecation on every response: Deprecation
+// (RFC 9745), Sunset (RFC 8594) and a Link to the successor-version route.
+func Deprecate(route DeprecatedRoute) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(route.Since.Unix(), 10)
+	sunset := route.Sunset.UTC().Format(http.TimeFormat)
+	link := "<" + route.Successor + `>; rel="successor-version"`
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunset)
+		c.Header("Link", link)
+		c.Next()
+	}
+}
+
+// Handler serves GET /versions.
+type Handler struct {
+	discovery Discovery
+}
+
+// NewHandler creates a handler that serves discovery.
+func NewHandler(discovery Discovery) *Handler {
+	if discovery.Deprecated == nil {
+		discovery.Deprecated = []DeprecatedRoute{}
+	}
+	return &Handler{discovery: discovery}
+}
+
+// GetVersions handles GET /versions.
+func (h *Handler) GetVersions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.discovery)
+}
@@ -0,0 +1,63 @@
+package apiversion_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apiversion"
+)
+
+var oldRoute = apiversion.DeprecatedRoute{
+	Method:    http.MethodGet,
+	Path:      "/v1/things/{id}",
+	Since:     time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+	Sunset:    time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+	Successor: "/v2/things/{namespace}/{id}",
+}
+
+func TestDeprecate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/things/:id", apiversion.Deprecate(oldRoute), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/things/a", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "@1792108800", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/things/{namespace}/{id}>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestGetVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/versions", apiversion.NewHandler(apiversion.Discovery{
+		Current: "v1",
+		Versions: []apiversion.Version{
+			{Version: "v1", Path: "/v1", Status: apiversion.StatusStable},
+			{Version: "v2", Path: "/v2", Status: apiversion.StatusPreview},
+		},
+		Deprecated: []apiversion.DeprecatedRoute{oldRoute},
+	}).GetVersions)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/versions", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var got apiversion.Discovery
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "v1", got.Current)
+	require.Len(t, got.Versions, 2)
+	assert.Equal(t, apiversion.StatusPreview, got.Versions[1].Status)
+	require.Len(t, got.Deprecated, 1)
+	assert.Equal(t, oldRoute.Successor, got.Deprecated[0].Successor)
+	assert.True(t, oldRoute.Sunset.Equal(got.Deprecated[0].Sunset))
+}
@@ -0,0 +1,219 @@
+// Package attachments finds the images, audio and files of OpenAI-style request bodies and checks
+// them against a subscription's attachment policy.
+package attachments
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"  // register the GIF decoder for image.DecodeConfig
+	_ "image/jpeg" // register the JPEG decoder for image.DecodeConfig
+	_ "image/png"  // register the PNG decoder for image.DecodeConfig
+	"maps"
+	"mime"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+// Attachment is one image, audio or file content part of a request.
+type Attachment struct {
+	// Type is the MIME type, or "" when it cannot be determined, as for uploaded file IDs.
+	Type string
+	// Size is the decoded size in bytes of inline data, or -1 for attachments referenced by URL
+	// or file ID.
+	Size int64
+	// Width and Height are the dimensions of an inline PNG, JPEG or GIF image, or 0.
+	Width, Height int
+}
+
+// audioTypes maps the formats of input_audio parts to MIME types.
+var audioTypes = map[string]string{
+	"wav":  "audio/wav",
+	"mp3":  "audio/mpeg",
+	"flac": "audio/flac",
+	"ogg":  "audio/ogg",
+	"webm": "audio/webm",
+}
+
+// Find returns the attachments of a JSON request body: the image_url, input_audio and file
+// content parts of chat messages, and the input_image and input_file parts of Responses API
+// input, wherever they are nested. It returns an error when body is not JSON.
+func Find(body []byte) ([]Attachment, error) {
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	var found []Attachment
+	walk(payload, func(part map[string]any) {
+		if a, ok := fromPart(part); ok {
+			found = append(found, a)
+		}
+	})
+	return found, nil
+}
+
+func walk(v any, visit func(map[string]any)) {
+	switch v := v.(type) {
+	case map[string]any:
+		visit(v)
+		// Sorted, so attachments are numbered the same way for every check of a body.
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			walk(v[key], visit)
+		}
+	case []any:
+		for _, child := range v {
+			walk(child, visit)
+		}
+	}
+}
+
+// fromPart returns the attachment of a content part, if it is one.
+func fromPart(part map[string]any) (Attachment, bool) {
+	partType, _ := part["type"].(string)
+	switch partType {
+	case "image_url":
+		// {"type": "image_url", "image_url": {"url": "..."}}, or a bare URL string.
+		ref := part["image_url"]
+		if m, ok := ref.(map[string]any); ok {
+			ref = m["url"]
+		}
+		s, _ := ref.(string)
+		return fromReference(s, ""), true
+	case "input_image":
+		if s, _ := part["image_url"].(string); s != "" {
+			return fromReference(s, ""), true
+		}
+		return Attachment{Size: -1}, true
+	case "input_audio":
+		audio, _ := part["input_audio"].(map[string]any)
+		data, _ := audio["data"].(string)
+		format, _ := audio["format"].(string)
+		return fromBase64(audioTypes[strings.ToLower(format)], data), true
+	case "file", "input_file":
+		// Chat file parts nest their fields under "file"; Responses input_file parts do not.
+		file := part
+		if m, ok := part["file"].(map[string]any); ok {
+			file = m
+		}
+		filename, _ := file["filename"].(string)
+		if data, _ := file["file_data"].(string); data != "" {
+			return fromReference(data, filename), true
+		}
+		if u, _ := file["file_url"].(string); u != "" {
+			return fromReference(u, filename), true
+		}
+		return Attachment{Type: typeByExtension(filename), Size: -1}, true
+	}
+	return Attachment{}, false
+}
+
+// fromReference returns the attachment of a data URL or of a URL, whose type comes from the
+// extension of filename or of the URL path.
+func fromReference(ref, filename string) Attachment {
+	if rest, ok := strings.CutPrefix(ref, "data:"); ok {
+		meta, data, _ := strings.Cut(rest, ",")
+		mediaType, _, _ := strings.Cut(meta, ";")
+		if mediaType == "" {
+			mediaType = typeByExtension(filename)
+		}
+		if !strings.HasSuffix(meta, ";base64") {
+			return Attachment{Type: strings.ToLower(mediaType), Size: int64(len(data))}
+		}
+		return fromBase64(strings.ToLower(mediaType), data)
+	}
+	if filename == "" {
+		if u, err := url.Parse(ref); err == nil {
+			filename = u.Path
+		}
+	}
+	return Attachment{Type: typeByExtension(filename), Size: -1}
+}
+
+// fromBase64 returns the attachment of base64 data. Image dimensions are read from the header
+// without decoding the image.
+func fromBase64(mediaType, data string) Attachment {
+	data = strings.TrimRight(data, "=")
+	a := Attachment{Type: mediaType, Size: int64(base64.RawStdEncoding.DecodedLen(len(data)))}
+	if strings.HasPrefix(mediaType, "image/") {
+		if config, _, err := image.DecodeConfig(base64.NewDecoder(base64.RawStdEncoding, strings.NewReader(data))); err == nil {
+			a.Width, a.Height = config.Width, config.Height
+		}
+	}
+	return a
+}
+
+func typeByExtension(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ext == "" {
+		return ""
+	}
+	mediaType, _, _ := strings.Cut(mime.TypeByExtension(ext), ";")
+	return mediaType
+}
+
+// Violation is why a request breaks an attachment policy.
+type Violation struct {
+	// Reason is one of reason.TooManyAttachments, reason.AttachmentTooLarge, reason.ImageTooLarge
+	// and reason.AttachmentTypeNotAllowed.
+	Reason  string
+	Message string
+}
+
+// Check returns how a request body breaks policy, or nil when it does not or policy is nil.
+// partial is true when the gateway forwarded the body truncated, because it is larger than the
+// most generous policy of the model allows. Bodies that are not JSON are left to the model.
+func Check(policy *subscription.AttachmentPolicy, body []byte, partial bool) *Violation {
+	if policy == nil {
+		return nil
+	}
+	if partial {
+		return &Violation{reason.AttachmentTooLarge, "Request body is larger than the subscription allows for attachments"}
+	}
+	found, err := Find(body)
+	if err != nil {
+		return nil
+	}
+	if policy.MaxCount != nil && int64(len(found)) > *policy.MaxCount {
+		return &Violation{reason.TooManyAttachments, fmt.Sprintf("Request carries %d attachments, the subscription allows %d", len(found), *policy.MaxCount)}
+	}
+	for i, a := range found {
+		if len(policy.AllowedTypes) > 0 && !typeAllowed(policy.AllowedTypes, a.Type) {
+			if a.Type == "" {
+				return &Violation{reason.AttachmentTypeNotAllowed, fmt.Sprintf("Type of attachment %d cannot be determined, the subscription allows %s", i+1, strings.Join(policy.AllowedTypes, ", "))}
+			}
+			return &Violation{reason.AttachmentTypeNotAllowed, fmt.Sprintf("Attachment %d has type %s, the subscription allows %s", i+1, a.Type, strings.Join(policy.AllowedTypes, ", "))}
+		}
+		if policy.MaxSize != nil && a.Size > *policy.MaxSize {
+			return &Violation{reason.AttachmentTooLarge, fmt.Sprintf("Attachment %d is %d bytes, the subscription allows %d", i+1, a.Size, *policy.MaxSize)}
+		}
+		if policy.MaxImageDimension != nil && int64(max(a.Width, a.Height)) > *policy.MaxImageDimension {
+			return &Violation{reason.ImageTooLarge, fmt.Sprintf("Image %d is %dx%d pixels, the subscription allows %d on its longest side", i+1, a.Width, a.Height, *policy.MaxImageDimension)}
+		}
+	}
+	return nil
+}
+
+// typeAllowed reports whether mediaType matches one of allowed, where "type/*" matches every
+// subtype.
+func typeAllowed(allowed []string, mediaType string) bool {
+	if mediaType == "" {
+		return false
+	}
+	for _, t := range allowed {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if t == mediaType {
+			return true
+		}
+	}
+	return false
+}
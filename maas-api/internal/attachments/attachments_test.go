@@ -0,0 +1,107 @@
+package attachments_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/attachments"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+func pngDataURL(t *testing.T, width, height int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))))
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestFind(t *testing.T) {
+	img := pngDataURL(t, 640, 480)
+	body := []byte(`{
+		"model": "llava",
+		"messages": [
+			{"role": "system", "content": "Describe images."},
+			{"role": "user", "content": [
+				{"type": "text", "text": "What is this?"},
+				{"type": "image_url", "image_url": {"url": "` + img + `"}},
+				{"type": "image_url", "image_url": {"url": "https://example.com/photos/cat.JPG?size=large"}},
+				{"type": "input_audio", "input_audio": {"data": "` + base64.StdEncoding.EncodeToString(make([]byte, 30)) + `", "format": "mp3"}},
+				{"type": "file", "file": {"file_id": "file-123"}}
+			]}
+		],
+		"input": [{"role": "user", "content": [
+			{"type": "input_image", "image_url": "https://example.com/dog.webp"},
+			{"type": "input_file", "filename": "report.pdf", "file_data": "data:;base64,AAAA"}
+		]}]
+	}`)
+
+	found, err := attachments.Find(body)
+	require.NoError(t, err)
+	require.Len(t, found, 6)
+	// Keys are walked in sorted order, so "input" comes before "messages".
+	assert.Equal(t, attachments.Attachment{Type: "image/webp", Size: -1}, found[0])
+	assert.Equal(t, attachments.Attachment{Type: "application/pdf", Size: 3}, found[1], "data URLs without a type are typed by filename")
+	assert.Equal(t, "image/png", found[2].Type)
+	assert.Equal(t, 640, found[2].Width)
+	assert.Equal(t, 480, found[2].Height)
+	assert.Positive(t, found[2].Size)
+	assert.Equal(t, attachments.Attachment{Type: "image/jpeg", Size: -1}, found[3], "remote URLs are typed by extension and not sized")
+	assert.Equal(t, attachments.Attachment{Type: "audio/mpeg", Size: 30}, found[4])
+	assert.Equal(t, attachments.Attachment{Size: -1}, found[5], "uploaded files have no known type")
+
+	_, err = attachments.Find([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	one, kib, side := int64(1), int64(1024), int64(512)
+	body := func(parts ...string) []byte {
+		content := ""
+		for i, p := range parts {
+			if i > 0 {
+				content += ","
+			}
+			content += `{"type": "image_url", "image_url": {"url": "` + p + `"}}`
+		}
+		return []byte(`{"messages": [{"role": "user", "content": [` + content + `]}]}`)
+	}
+	small := pngDataURL(t, 64, 64)
+
+	tests := []struct {
+		name    string
+		policy  *subscription.AttachmentPolicy
+		body    []byte
+		partial bool
+		want    string
+	}{
+		{name: "no policy", body: body(small, small), partial: true},
+		{name: "within policy", policy: &subscription.AttachmentPolicy{MaxCount: &one, MaxSize: &kib, MaxImageDimension: &side, AllowedTypes: []string{"image/png"}}, body: body(small)},
+		{name: "too many", policy: &subscription.AttachmentPolicy{MaxCount: &one}, body: body(small, small), want: reason.TooManyAttachments},
+		{name: "too large", policy: &subscription.AttachmentPolicy{MaxSize: &kib}, body: body("data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, 2048))), want: reason.AttachmentTooLarge},
+		{name: "image too large", policy: &subscription.AttachmentPolicy{MaxImageDimension: &side}, body: body(pngDataURL(t, 1024, 16)), want: reason.ImageTooLarge},
+		{name: "wildcard type", policy: &subscription.AttachmentPolicy{AllowedTypes: []string{"image/*"}}, body: body(small, "https://example.com/a.gif")},
+		{name: "type not allowed", policy: &subscription.AttachmentPolicy{AllowedTypes: []string{"image/jpeg"}}, body: body(small), want: reason.AttachmentTypeNotAllowed},
+		{name: "unknown type", policy: &subscription.AttachmentPolicy{AllowedTypes: []string{"image/*"}}, body: body("https://example.com/image"), want: reason.AttachmentTypeNotAllowed},
+		{name: "truncated body", policy: &subscription.AttachmentPolicy{}, body: body(small), partial: true, want: reason.AttachmentTooLarge},
+		{name: "not json", policy: &subscription.AttachmentPolicy{MaxCount: &one}, body: []byte("plain text")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := attachments.Check(tt.policy, tt.body, tt.partial)
+			if tt.want == "" {
+				assert.Nil(t, v)
+				return
+			}
+			require.NotNil(t, v)
+			assert.Equal(t, tt.want, v.Reason)
+			assert.NotEmpty(t, v.Message)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// Package audit emits a structured record of every authorization decision to configurable sinks:
+// JSON lines on stdout, Kubernetes Events on the MaaSModelRef, an HTTP webhook, or a Store that
+// GET /admin/v1/audit queries. Records are written in the background so a slow sink never delays a
+// decision; when the queue is full, records are dropped and counted in maas_audit_dropped_total.
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// Decision values of Record.Decision.
+const (
+	DecisionAllowed = "allowed"
+	DecisionDenied  = "denied"
+)
+
+// EndpointAdmin is the Record.Endpoint of admin actions, which are kept apart from authorization
+// decisions for retention.
+const EndpointAdmin = "admin"
+
+// Sink names accepted by AUDIT_SINKS.
+const (
+	SinkStdout  = "stdout"
+	SinkEvent   = "event"
+	SinkWebhook = "webhook"
+	SinkStore   = "store"
+)
+
+// queueSize bounds the records waiting for the sinks.
+const queueSize = 1024
+
+var droppedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "maas_audit_dropped_total",
+	Help: "Audit records dropped because the sinks could not keep up.",
+})
+
+func init() {
+	prometheus.MustRegister(droppedTotal)
+}
+
+// Record is one authorization decision, or an admin action (see AdminAction).
+type Record struct {
+	Time           time.Time `json:"timestamp"`
+	Endpoint       string    `json:"endpoint"` // select, ext_authz, signed_url (a signed URL minted) or admin
+	User           string    `json:"user,omitempty"`
+	Subscription   string    `json:"subscription,omitempty"` // name of the selected subscription
+	Organization   string    `json:"organization,omitempty"` // tokenMetadata organization ID of the subscription
+	Model          string    `json:"model,omitempty"`        // namespace/name as requested
+	Path           string    `json:"path,omitempty"`
+	Decision       string    `json:"decision"`
+	Reason         string    `json:"reason,omitempty"`         // denial reason code, empty when allowed
+	ReasonCategory string    `json:"reasonCategory,omitempty"` // category of the reason code
+}
+
+// Sink writes audit records. Write is called from a single goroutine.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+}
+
+// Auditor queues records for its sinks. A nil Auditor records nothing.
+type Auditor struct {
+	logger        *logger.Logger
+	sinks         []Sink
+	queue         chan Record
+	organizations func() map[string]string
+}
+
+// New creates an Auditor writing to sinks. It returns nil when there are none. Call Run to start
+// delivering records.
+func New(log *logger.Logger, sinks ...Sink) *Auditor {
+	if len(sinks) == 0 {
+		return nil
+	}
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Auditor{logger: log, sinks: sinks, queue: make(chan Record, queueSize)}
+}
+
+// SetOrganizations fills in the organization of each record's subscription (by name) as it is
+// written, so legal holds on an organization keep its records even after its subscriptions change.
+func (a *Auditor) SetOrganizations(organizations func() map[string]string) {
+	if a == nil {
+		return
+	}
+	a.organizations = organizations
+}
+
+// Decision records an authorization decision. An empty code means the request was allowed.
+func (a *Auditor) Decision(endpoint, model, subscription, user, path, code string) {
+	if a == nil {
+		return
+	}
+	r := Record{
+		Time:           time.Now().UTC(),
+		Endpoint:       endpoint,
+		User:           user,
+		Subscription:   subscription,
+		Model:          model,
+		Path:           path,
+		Decision:       DecisionAllowed,
+		Reason:         code,
+		ReasonCategory: string(reason.CategoryOf(code)),
+	}
+	if code != "" {
+		r.Decision = DecisionDenied
+	}
+	a.enqueue(r)
+}
+
+// AdminAction records a change an admin made, e.g. action "tier.update" of subscription. Admin
+// actions are recorded as allowed, with the action as the path.
+func (a *Auditor) AdminAction(action, subscription, model, user string) {
+	if a == nil {
+		return
+	}
+	a.enqueue(Record{
+		Time:         time.Now().UTC(),
+		Endpoint:     EndpointAdmin,
+		User:         user,
+		Subscription: subscription,
+		Model:        model,
+		Path:         action,
+		Decision:     DecisionAllowed,
+	})
+}
+
+func (a *Auditor) enqueue(r Record) {
+	select {
+	case a.queue <- r:
+	default:
+		droppedTotal.Inc()
+	}
+}
+
+// Run delivers queued records until ctx is done, then flushes what is left.
+func (a *Auditor) Run(ctx context.Context) {
+	if a == nil {
+		return
+	}
+	for {
+		select {
+		case r := <-a.queue:
+			a.write(ctx, r)
+		case <-ctx.Done():
+			flush := context.WithoutCancel(ctx)
+			for {
+				select {
+				case r := <-a.queue:
+					a.write(flush, r)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *Auditor) write(ctx context.Context, r Record) {
+	if a.organizations != nil && r.Subscription != "" && r.Organization == "" {
+		r.Organization = a.organizations()[r.Subscription]
+	}
+	for _, sink := range a.sinks {
+		if err := sink.Write(ctx, r); err != nil {
+			a.logger.Warn("Failed to write audit record", "sink", sinkName(sink), "error", err)
+		}
+	}
+}
+
+func sinkName(s Sink) string {
+	switch s.(type) {
+	case *StdoutSink:
+		return SinkStdout
+	case *EventSink:
+		return SinkEvent
+	case *WebhookSink:
+		return SinkWebhook
+	case Store:
+		return SinkStore
+	default:
+		return "custom"
+	}
+}
@@ -0,0 +1,194 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(_ context.Context, r audit.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *recordingSink) all() []audit.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]audit.Record(nil), s.records...)
+}
+
+func TestAuditorDeliversDecisions(t *testing.T) {
+	sink := &recordingSink{}
+	auditor := audit.New(logger.Development(), sink)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		auditor.Run(ctx)
+		close(done)
+	}()
+
+	auditor.Decision("ext_authz", "llm/granite", "premium", "alice", "/llm/granite/v1/chat/completions", "")
+	auditor.Decision("select", "llm/granite", "", "bob", "", "access_denied")
+	cancel()
+	<-done
+
+	records := sink.all()
+	require.Len(t, records, 2)
+	assert.Equal(t, audit.DecisionAllowed, records[0].Decision)
+	assert.Equal(t, "premium", records[0].Subscription)
+	assert.Equal(t, "/llm/granite/v1/chat/completions", records[0].Path)
+	assert.False(t, records[0].Time.IsZero())
+	assert.Equal(t, audit.DecisionDenied, records[1].Decision)
+	assert.Equal(t, "access_denied", records[1].Reason)
+	assert.Equal(t, "authorization", records[1].ReasonCategory)
+	assert.Empty(t, records[0].ReasonCategory)
+}
+
+func TestNilAuditor(t *testing.T) {
+	assert.Nil(t, audit.New(nil))
+
+	var auditor *audit.Auditor
+	auditor.Decision("select", "llm/granite", "", "alice", "", "")
+	auditor.Run(context.Background())
+}
+
+func TestStdoutSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := audit.NewStdoutSink(&buf)
+	require.NoError(t, sink.Write(context.Background(), audit.Record{Endpoint: "select", User: "alice", Decision: audit.DecisionAllowed}))
+	require.NoError(t, sink.Write(context.Background(), audit.Record{Endpoint: "select", User: "bob", Decision: audit.DecisionDenied}))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(lines[1], &got))
+	assert.Equal(t, "bob", got["user"])
+	assert.Equal(t, "denied", got["decision"])
+	assert.Contains(t, got, "timestamp")
+	assert.NotContains(t, got, "reason")
+}
+
+func TestWebhookSink(t *testing.T) {
+	var received audit.Record
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink := audit.NewWebhookSink(srv.URL)
+	record := audit.Record{Endpoint: "ext_authz", Model: "llm/granite", Decision: audit.DecisionDenied, Reason: "invalid_key"}
+	require.NoError(t, sink.Write(context.Background(), record))
+	assert.Equal(t, "invalid_key", received.Reason)
+
+	status = http.StatusInternalServerError
+	assert.ErrorContains(t, sink.Write(context.Background(), record), "500")
+}
+
+func TestEventSink(t *testing.T) {
+	clientset := fake.NewClientset()
+	sink := audit.NewEventSink(clientset)
+
+	require.NoError(t, sink.Write(context.Background(), audit.Record{Endpoint: "select", Decision: audit.DecisionDenied, Reason: "missing_model"}))
+	require.NoError(t, sink.Write(context.Background(), audit.Record{
+		Endpoint: "ext_authz", Model: "llm/granite", User: "alice", Decision: audit.DecisionDenied, Reason: "access_denied",
+	}))
+
+	var events []corev1.Event
+	require.Eventually(t, func() bool {
+		list, err := clientset.CoreV1().Events("llm").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return false
+		}
+		events = list.Items
+		return len(events) > 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.Len(t, events, 1, "records without a model must not create events")
+	assert.Equal(t, audit.EventReasonDenied, events[0].Reason)
+	assert.Equal(t, corev1.EventTypeWarning, events[0].Type)
+	assert.Equal(t, "MaaSModelRef", events[0].InvolvedObject.Kind)
+	assert.Equal(t, "granite", events[0].InvolvedObject.Name)
+	assert.Contains(t, events[0].Message, "alice")
+}
+
+func TestNewSinks(t *testing.T) {
+	store := audit.NewMemoryStore()
+	sinks, err := audit.NewSinks("stdout, webhook, store", nil, "http://audit.example.com", store)
+	require.NoError(t, err)
+	require.Len(t, sinks, 3)
+	assert.Same(t, store, sinks[2])
+
+	_, err = audit.NewSinks("webhook", nil, "", nil)
+	require.Error(t, err)
+	_, err = audit.NewSinks("store", nil, "", nil)
+	require.Error(t, err)
+	_, err = audit.NewSinks("syslog", nil, "", nil)
+	require.Error(t, err)
+
+	sinks, err = audit.NewSinks("", nil, "", nil)
+	require.NoError(t, err)
+	assert.Empty(t, sinks)
+}
+
+func TestMemoryStoreQuery(t *testing.T) {
+	ctx := context.Background()
+	store := audit.NewMemoryStore()
+	at := func(h int) time.Time { return time.Date(2026, 10, 1, h, 0, 0, 0, time.UTC) }
+	for _, r := range []audit.Record{
+		{Time: at(9), User: "alice", Model: "llm/granite", Decision: audit.DecisionAllowed},
+		{Time: at(10), User: "bob", Model: "llm/granite", Decision: audit.DecisionDenied, Reason: "model_not_found"},
+		{Time: at(11), User: "alice", Model: "llm/llama", Decision: audit.DecisionDenied, Reason: "rate_limited"},
+		{Time: at(12), User: "alice", Model: "llm/granite", Decision: audit.DecisionAllowed},
+	} {
+		require.NoError(t, store.Write(ctx, r))
+	}
+
+	records, more, err := store.Query(ctx, audit.Query{User: "alice", Limit: 2})
+	require.NoError(t, err)
+	assert.True(t, more)
+	require.Len(t, records, 2)
+	assert.Equal(t, at(12), records[0].Time, "newest first")
+	assert.Equal(t, at(11), records[1].Time)
+
+	records, more, err = store.Query(ctx, audit.Query{User: "alice", Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.False(t, more)
+	require.Len(t, records, 1)
+	assert.Equal(t, at(9), records[0].Time)
+
+	records, _, err = store.Query(ctx, audit.Query{From: at(10), To: at(12), Decision: audit.DecisionDenied, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, records, 2, "from is inclusive and to exclusive")
+
+	records, _, err = store.Query(ctx, audit.Query{Model: "llm/granite", Reason: "model_not_found", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "bob", records[0].User)
+}
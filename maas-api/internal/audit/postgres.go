@@ -0,0 +1,127 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// PostgresStore keeps records in the audit_records table (see db/schema), so every replica writes
+// to and queries the same log.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a store in db, whose schema the api_keys migrations manage.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Write implements Sink.
+func (s *PostgresStore) Write(ctx context.Context, r Record) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_records (recorded_at, endpoint, username, subscription, organization, model, path, decision, reason, reason_category)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		r.Time, r.Endpoint, r.User, r.Subscription, r.Organization, r.Model, r.Path, r.Decision, r.Reason, r.ReasonCategory)
+	if err != nil {
+		return fmt.Errorf("failed to store audit record: %w", err)
+	}
+	return nil
+}
+
+// Query implements Store.
+func (s *PostgresStore) Query(ctx context.Context, q Query) ([]Record, bool, error) {
+	query, args := buildQuery(q)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query audit records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.Time, &r.Endpoint, &r.User, &r.Subscription, &r.Organization, &r.Model, &r.Path, &r.Decision, &r.Reason, &r.ReasonCategory); err != nil {
+			return nil, false, fmt.Errorf("failed to scan audit record: %w", err)
+		}
+		r.Time = r.Time.UTC()
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to query audit records: %w", err)
+	}
+	if len(records) > q.Limit {
+		return records[:q.Limit], true, nil
+	}
+	return records, false, nil
+}
+
+// buildQuery builds the select for q and its arguments. One record more than the limit is fetched
+// to tell whether another page follows.
+func buildQuery(q Query) (string, []any) {
+	var b strings.Builder
+	b.WriteString("SELECT recorded_at, endpoint, username, subscription, COALESCE(organization, ''), model, path, decision, reason, reason_category FROM audit_records WHERE TRUE")
+	var args []any
+	filter := func(condition string, value any) {
+		args = append(args, value)
+		fmt.Fprintf(&b, " AND "+condition, len(args))
+	}
+	if !q.From.IsZero() {
+		filter("recorded_at >= $%d", q.From)
+	}
+	if !q.To.IsZero() {
+		filter("recorded_at < $%d", q.To)
+	}
+	if q.User != "" {
+		filter("username = $%d", q.User)
+	}
+	if q.Model != "" {
+		filter("model = $%d", q.Model)
+	}
+	if q.Decision != "" {
+		filter("decision = $%d", q.Decision)
+	}
+	if q.Reason != "" {
+		filter("reason = $%d", q.Reason)
+	}
+	args = append(args, q.Limit+1, q.Offset)
+	fmt.Fprintf(&b, " ORDER BY recorded_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	return b.String(), args
+}
+
+// Prune implements Store. Records written before the organization column existed have none; they
+// are kept while any organization is under legal hold, as they may be one of its.
+func (s *PostgresStore) Prune(ctx context.Context, p Prune) (int64, error) {
+	endpoint := "endpoint <> $2"
+	if p.AdminActions {
+		endpoint = "endpoint = $2"
+	}
+	where := "recorded_at < $1 AND " + endpoint + " AND NOT (model = ANY($4)) AND " +
+		"(cardinality($3::text[]) = 0 OR (organization IS NOT NULL AND NOT (organization = ANY($3))))"
+	args := []any{p.Before, EndpointAdmin, pq.Array(nonNil(p.Organizations)), pq.Array(nonNil(p.Models))}
+	if p.DryRun {
+		var n int64
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_records WHERE "+where, args...).Scan(&n); err != nil {
+			return 0, fmt.Errorf("failed to count prunable audit records: %w", err)
+		}
+		return n, nil
+	}
+	res, err := s.db.ExecContext(ctx, "DELETE FROM audit_records WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit records: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// nonNil returns s, or an empty slice for nil, which pq sends as NULL rather than an empty array.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+// NewSinks builds the sinks named in a comma-separated list (AUDIT_SINKS). clientset is used by the
+// event sink, webhookURL by the webhook sink and store by the store sink.
+func NewSinks(list string, clientset kubernetes.Interface, webhookURL string, store Store) ([]Sink, error) {
+	var sinks []Sink
+	for name := range strings.SplitSeq(list, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case SinkStdout:
+			sinks = append(sinks, NewStdoutSink(os.Stdout))
+		case SinkEvent:
+			sinks = append(sinks, NewEventSink(clientset))
+		case SinkWebhook:
+			if webhookURL == "" {
+				return nil, errors.New("the webhook audit sink requires a URL")
+			}
+			sinks = append(sinks, NewWebhookSink(webhookURL))
+		case SinkStore:
+			if store == nil {
+				return nil, errors.New("the store audit sink requires a store")
+			}
+			sinks = append(sinks, store)
+		default:
+			return nil, fmt.Errorf("unknown audit sink %q", name)
+		}
+	}
+	return sinks, nil
+}
+
+// StdoutSink writes each record as one JSON line.
+type StdoutSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewStdoutSink creates a sink writing JSON lines to w, usually os.Stdout.
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{enc: json.NewEncoder(w)}
+}
+
+// Write implements Sink.
+func (s *StdoutSink) Write(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(r)
+}
+
+// Event reasons set by EventSink.
+const (
+	EventReasonAllowed = "AccessAllowed"
+	EventReasonDenied  = "AccessDenied"
+)
+
+// EventSink records decisions as Kubernetes Events on the model's MaaSModelRef, so
+// `kubectl describe maasmodelref` shows who was allowed or denied. The event recorder aggregates
+// repeated events, so a busy model does not create one Event per request. Records without a
+// qualified model are skipped.
+type EventSink struct {
+	recorder record.EventRecorder
+}
+
+// NewEventSink creates a sink that emits Events through clientset as component "maas-api".
+func NewEventSink(clientset kubernetes.Interface) *EventSink {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+	return &EventSink{recorder: broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "maas-api"})}
+}
+
+// Write implements Sink.
+func (s *EventSink) Write(_ context.Context, r Record) error {
+	ns, name, ok := strings.Cut(r.Model, "/")
+	if !ok || ns == "" || name == "" {
+		return nil
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion: models.GVR().GroupVersion().String(),
+		Kind:       "MaaSModelRef",
+		Namespace:  ns,
+		Name:       name,
+	}
+	user := r.User
+	if user == "" {
+		user = "unauthenticated caller"
+	}
+	if r.Decision == DecisionAllowed {
+		s.recorder.Eventf(ref, corev1.EventTypeNormal, EventReasonAllowed, "%s allowed via %s subscription %s", user, r.Endpoint, r.Subscription)
+		return nil
+	}
+	s.recorder.Eventf(ref, corev1.EventTypeWarning, EventReasonDenied, "%s denied via %s: %s", user, r.Endpoint, r.Reason)
+	return nil
+}
+
+// WebhookSink POSTs each record as JSON to a URL.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink posting to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Write implements Sink.
+func (s *WebhookSink) Write(ctx context.Context, r Record) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("audit webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Query selects stored records, newest first.
+type Query struct {
+	From time.Time // inclusive; zero for no lower bound
+	To   time.Time // exclusive; zero for no upper bound
+	// User, Model, Decision and Reason, when set, keep only the records with that value.
+	User     string
+	Model    string
+	Decision string
+	Reason   string
+	// Limit is the most records returned, after skipping Offset.
+	Limit  int
+	Offset int
+}
+
+func (q *Query) matches(r *Record) bool {
+	return (q.From.IsZero() || !r.Time.Before(q.From)) &&
+		(q.To.IsZero() || r.Time.Before(q.To)) &&
+		(q.User == "" || r.User == q.User) &&
+		(q.Model == "" || r.Model == q.Model) &&
+		(q.Decision == "" || r.Decision == q.Decision) &&
+		(q.Reason == "" || r.Reason == q.Reason)
+}
+
+// Store keeps audit records for querying. It is a Sink, named "store" in AUDIT_SINKS.
+type Store interface {
+	Sink
+	// Query returns the records matching q, newest first, and whether more follow the page.
+	Query(ctx context.Context, q Query) ([]Record, bool, error)
+	// Prune deletes the records p selects, or only counts them in a dry run, and returns how many.
+	Prune(ctx context.Context, p Prune) (int64, error)
+}
+
+// Prune selects the records a retention run deletes: the decisions, or the admin actions, recorded
+// before Before, except those of organizations and models under legal hold.
+type Prune struct {
+	Before time.Time
+	// AdminActions selects admin actions rather than decisions.
+	AdminActions bool
+	// Organizations (as recorded) and Models (namespace/name) whose records are kept.
+	Organizations []string
+	Models        []string
+	DryRun        bool
+}
+
+func (p *Prune) matches(r *Record) bool {
+	return r.Time.Before(p.Before) && (r.Endpoint == EndpointAdmin) == p.AdminActions &&
+		!slices.Contains(p.Organizations, r.Organization) && !slices.Contains(p.Models, r.Model)
+}
+
+// MemoryStore keeps records in this replica only and loses them on restart. It is meant for tests
+// and development; use PostgresStore in production.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Write implements Sink.
+func (s *MemoryStore) Write(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, r)
+	return nil
+}
+
+// Query implements Store.
+func (s *MemoryStore) Query(_ context.Context, q Query) ([]Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []Record
+	for _, r := range slices.Backward(s.records) {
+		if q.matches(&r) {
+			matched = append(matched, r)
+		}
+	}
+	// Records arrive nearly in time order; sort stably so ties keep the newest written first.
+	slices.SortStableFunc(matched, func(a, b Record) int { return b.Time.Compare(a.Time) })
+	if q.Offset >= len(matched) {
+		return nil, false, nil
+	}
+	matched = matched[q.Offset:]
+	if len(matched) > q.Limit {
+		return matched[:q.Limit], true, nil
+	}
+	return matched, false, nil
+}
+
+// Prune implements Store.
+func (s *MemoryStore) Prune(_ context.Context, p Prune) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.records[:0:0]
+	var n int64
+	for _, r := range s.records {
+		if p.matches(&r) {
+			n++
+			if !p.DryRun {
+				continue
+			}
+		}
+		kept = append(kept, r)
+	}
+	s.records = kept
+	return n, nil
+}
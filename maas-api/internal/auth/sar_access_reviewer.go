@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// SARAccessReviewer checks whether a user may act on a specific resource via Kubernetes
+// SubjectAccessReview, so maas-api can act on a user's behalf only where their own RBAC allows.
+type SARAccessReviewer struct {
+	client kubernetes.Interface
+}
+
+// NewSARAccessReviewer creates a SAR-based access reviewer.
+func NewSARAccessReviewer(client kubernetes.Interface) *SARAccessReviewer {
+	if client == nil {
+		panic("client cannot be nil for SARAccessReviewer")
+	}
+	return &SARAccessReviewer{client: client}
+}
+
+// CanI reports whether the user is allowed the given resource attributes.
+// Returns false (fail-closed) if the check cannot be performed.
+func (s *SARAccessReviewer) CanI(ctx context.Context, user *token.UserContext, attrs authv1.ResourceAttributes) bool {
+	if s == nil || s.client == nil || user == nil || user.Username == "" {
+		return false
+	}
+
+	sar := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:               user.Username,
+			Groups:             user.Groups,
+			ResourceAttributes: &attrs,
+		},
+	}
+
+	result, err := s.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		slog.Warn("SAR access check failed", "error", err.Error(), "verb", attrs.Verb, "resource", attrs.Resource)
+		return false
+	}
+
+	return result.Status.Allowed
+}
@@ -0,0 +1,123 @@
+// Package authpolicy evaluates MaaSAuthPolicy access grants outside of Authorino.
+package authpolicy
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ModelIndex is the informer index of MaaSAuthPolicies by the "namespace/name" of each model
+// they reference.
+const ModelIndex = "model"
+
+// Lister provides access to MaaSAuthPolicy resources from an informer cache.
+type Lister interface {
+	List() ([]*unstructured.Unstructured, error)
+}
+
+// IndexedLister is implemented by listers backed by an informer with ModelIndex, so the policies
+// covering one model are found without scanning every policy.
+type IndexedLister interface {
+	Lister
+	ByModel(modelNamespace, modelName string) ([]*unstructured.Unstructured, error)
+}
+
+// ModelIndexFunc is a cache.IndexFunc returning the "namespace/name" of every model a
+// MaaSAuthPolicy references.
+func ModelIndexFunc(obj any) ([]string, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	refs, _, _ := unstructured.NestedSlice(u.Object, "spec", "modelRefs")
+	keys := make([]string, 0, len(refs))
+	for _, r := range refs {
+		rm, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		ns, _ := rm["namespace"].(string)
+		name, _ := rm["name"].(string)
+		if ns != "" && name != "" {
+			keys = append(keys, ns+"/"+name)
+		}
+	}
+	return keys, nil
+}
+
+// PoliciesForModel returns the policies that may reference the model: an index lookup when lister
+// implements IndexedLister, every policy otherwise. SubjectsForModel filters the result either way.
+func PoliciesForModel(lister Lister, modelNamespace, modelName string) ([]*unstructured.Unstructured, error) {
+	if indexed, ok := lister.(IndexedLister); ok {
+		return indexed.ByModel(modelNamespace, modelName)
+	}
+	return lister.List()
+}
+
+// Subjects is the aggregated set of users and groups granted access to one model.
+type Subjects struct {
+	groups []string
+	users  []string
+}
+
+// SubjectsForModel aggregates subjects from every MaaSAuthPolicy that references the model,
+// mirroring the require-group-membership rule maas-controller writes into each AuthPolicy.
+// found is false when no policy covers the model.
+func SubjectsForModel(policies []*unstructured.Unstructured, modelNamespace, modelName string) (Subjects, bool) {
+	var out Subjects
+	found := false
+	for _, p := range policies {
+		if p.GetDeletionTimestamp() != nil {
+			continue
+		}
+		refs, _, _ := unstructured.NestedSlice(p.Object, "spec", "modelRefs")
+		if !referencesModel(refs, modelNamespace, modelName) {
+			continue
+		}
+		found = true
+		groups, _, _ := unstructured.NestedSlice(p.Object, "spec", "subjects", "groups")
+		for _, g := range groups {
+			if gm, ok := g.(map[string]any); ok {
+				if name, ok := gm["name"].(string); ok {
+					out.groups = append(out.groups, name)
+				}
+			}
+		}
+		users, _, _ := unstructured.NestedStringSlice(p.Object, "spec", "subjects", "users")
+		out.users = append(out.users, users...)
+	}
+	return out, found
+}
+
+func referencesModel(refs []any, modelNamespace, modelName string) bool {
+	for _, r := range refs {
+		rm, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		if rm["namespace"] == modelNamespace && rm["name"] == modelName {
+			return true
+		}
+	}
+	return false
+}
+
+// Add grants access to more groups and users, e.g. from a model's allow-list annotations.
+func (s *Subjects) Add(groups, users []string) {
+	s.groups = append(s.groups, groups...)
+	s.users = append(s.users, users...)
+}
+
+// Allows reports whether the user, or any of their groups, is granted access.
+func (s Subjects) Allows(username string, groups []string) bool {
+	if slices.Contains(s.users, username) {
+		return true
+	}
+	for _, g := range groups {
+		if slices.Contains(s.groups, g) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package bench_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/bench"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// Cache sizes and MaaSModelRef shapes the benchmarks run over. Concurrency is set with -cpu, e.g.
+// go test -bench . -cpu 1,4,16.
+var sizes = []int{100, 1000, 10000}
+
+const apiKey = "sk-oai-bench"
+
+// keys accepts apiKey as bench.User, with the groups of every synthetic subscription.
+type keys struct{ groups []string }
+
+func (k keys) ValidateAPIKey(_ context.Context, key string) (*api_keys.ValidationResult, error) {
+	if key != apiKey {
+		return &api_keys.ValidationResult{Valid: false, Reason: "key not found"}, nil
+	}
+	return &api_keys.ValidationResult{Valid: true, Username: bench.User, KeyID: "bench", Groups: k.groups}, nil
+}
+
+func newCache(tb testing.TB, opts bench.Options) *bench.Cache {
+	tb.Helper()
+	c, err := bench.NewCache(opts)
+	require.NoError(tb, err)
+	return c
+}
+
+func checkRequest(path string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{
+					Path:    path,
+					Headers: map[string]string{"authorization": "Bearer " + apiKey},
+				},
+			},
+		},
+	}
+}
+
+func TestCacheAllowsEveryModel(t *testing.T) {
+	ctx := context.Background()
+	for _, shape := range bench.Shapes {
+		for _, unindexed := range []bool{false, true} {
+			c := newCache(t, bench.Options{Models: 250, Shape: shape, Unindexed: unindexed})
+			server := c.NewServer(logger.New(false), keys{c.Groups()})
+			for i := range 250 {
+				decision := server.CheckAccess(ctx, bench.User, c.Groups(), c.Model(i), "")
+				require.True(t, decision.Allowed, "%s unindexed=%t %s: %s", shape, unindexed, c.Model(i), decision.Reason)
+				assert.Equal(t, c.Tier(i), decision.Subscription)
+
+				resp, err := server.Check(ctx, checkRequest(c.Path(i)))
+				require.NoError(t, err)
+				require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), "%s %s: %s", shape, c.Path(i), resp.GetDeniedResponse().GetBody())
+			}
+		}
+	}
+
+	_, err := bench.NewCache(bench.Options{Models: 10, Shape: "fancy"})
+	assert.ErrorContains(t, err, "unknown shape")
+}
+
+func TestRun(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := newCache(t, bench.Options{Models: 100, Shape: bench.ShapeAliased, DecisionCacheTTL: time.Minute})
+	server := httptest.NewServer(c.NewRouter(logger.New(false)))
+	t.Cleanup(server.Close)
+
+	for _, endpoint := range bench.Endpoints {
+		result, err := bench.Run(context.Background(), server.Client(), server.URL, c,
+			bench.Scenario{Endpoint: endpoint, Concurrency: 4, Duration: 100 * time.Millisecond})
+		require.NoError(t, err)
+		assert.Positive(t, result.Requests, endpoint)
+		assert.Zero(t, result.Errors, endpoint)
+		assert.Zero(t, result.Denied, endpoint)
+		assert.Equal(t, bench.ShapeAliased, result.Shape)
+		assert.True(t, result.Indexed)
+		assert.True(t, result.DecisionCache)
+		assert.Positive(t, result.RequestsPerSecond)
+		assert.LessOrEqual(t, result.Latency.P50, result.Latency.P99)
+	}
+
+	_, err := bench.Run(context.Background(), server.Client(), server.URL, c, bench.Scenario{Endpoint: "stream", Concurrency: 1, Duration: time.Second})
+	assert.ErrorContains(t, err, "unknown endpoint")
+}
+
+// BenchmarkAuthorize decides the pre-flight authorization of POST /v1/models/authorize/batch for
+// one model at a time, across cache sizes, shapes and with and without the informer indexes.
+func BenchmarkAuthorize(b *testing.B) {
+	for _, size := range sizes {
+		for _, shape := range bench.Shapes {
+			for _, unindexed := range []bool{false, true} {
+				if unindexed && size > 1000 {
+					continue // scans of 10000 models only show what the indexes are for
+				}
+				name := "models=" + strconv.Itoa(size) + "/shape=" + string(shape) + "/indexed=" + strconv.FormatBool(!unindexed)
+				b.Run(name, func(b *testing.B) {
+					c := newCache(b, bench.Options{Models: size, Shape: shape, Unindexed: unindexed})
+					server := c.NewServer(logger.New(false), nil)
+					groups := c.Groups()
+					b.ReportAllocs()
+					b.ResetTimer()
+					b.RunParallel(func(pb *testing.PB) {
+						ctx := context.Background()
+						for i := 0; pb.Next(); i++ {
+							entry := extauthz.BatchEntry{Path: c.Path(i)}
+							if d := server.Authorize(ctx, bench.User, groups, entry); !d.Allowed {
+								b.Errorf("%s denied: %s", entry.Path, d.Reason)
+								return
+							}
+						}
+					})
+				})
+			}
+		}
+	}
+}
+
+// BenchmarkCheck decides ext_authz Check requests with an API key, as the gateway sends them for
+// every inference request, with and without the decision cache.
+func BenchmarkCheck(b *testing.B) {
+	for _, size := range sizes {
+		for _, ttl := range []time.Duration{0, time.Minute} {
+			name := "models=" + strconv.Itoa(size) + "/decisionCache=" + strconv.FormatBool(ttl > 0)
+			b.Run(name, func(b *testing.B) {
+				c := newCache(b, bench.Options{Models: size, DecisionCacheTTL: ttl})
+				server := c.NewServer(logger.New(false), keys{c.Groups()})
+				requests := make([]*authv3.CheckRequest, min(size, 1000))
+				for i := range requests {
+					requests[i] = checkRequest(c.Path(i))
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					ctx := context.Background()
+					for i := 0; pb.Next(); i++ {
+						resp, err := server.Check(ctx, requests[i%len(requests)])
+						if err != nil || resp.GetStatus().GetCode() != int32(codes.OK) {
+							b.Errorf("request denied: %v %s", err, resp.GetDeniedResponse().GetBody())
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
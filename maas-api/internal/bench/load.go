@@ -0,0 +1,245 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// Endpoint is an authorization endpoint of maas-api the load runner calls.
+type Endpoint string
+
+const (
+	// EndpointSelect is POST /internal/v1/subscriptions/select, which Authorino calls for every
+	// inference request.
+	EndpointSelect Endpoint = "select"
+	// EndpointAccess is GET /v1/models/{name}/access.
+	EndpointAccess Endpoint = "access"
+	// EndpointBatch is POST /v1/models/authorize/batch, with BatchSize entries per call.
+	EndpointBatch Endpoint = "batch"
+)
+
+// Endpoints lists every endpoint, in the order reports use.
+var Endpoints = []Endpoint{EndpointSelect, EndpointAccess, EndpointBatch}
+
+// ParseEndpoint parses an endpoint name.
+func ParseEndpoint(s string) (Endpoint, error) {
+	endpoint := Endpoint(strings.TrimSpace(s))
+	if !slices.Contains(Endpoints, endpoint) {
+		return "", fmt.Errorf("unknown endpoint %q: want one of select, access, batch", s)
+	}
+	return endpoint, nil
+}
+
+// BatchSize is the number of entries of each batch authorization call.
+const BatchSize = 20
+
+// NewRouter returns a router serving the authorization endpoints over the cache with the
+// handlers and middleware maas-api routes them through. Callers are identified by the identity
+// headers, as behind the gateway.
+func (c *Cache) NewRouter(log *logger.Logger) *gin.Engine {
+	router := gin.New()
+	extractUser := token.NewHandler(log, "bench").ExtractUserInfo()
+	authz := extauthz.NewHandler(log, c.NewServer(log, nil))
+	router.POST("/v1/models/authorize/batch", extractUser, authz.AuthorizeBatch)
+	router.GET("/v1/models/:name/access", extractUser, authz.CheckAccess)
+	router.POST("/internal/v1/subscriptions/select", subscription.NewHandler(log, c.NewSelector(log)).SelectSubscription)
+	return router
+}
+
+// Scenario is a load to put on one endpoint.
+type Scenario struct {
+	Endpoint Endpoint
+	// Concurrency is the number of callers, each making one request at a time.
+	Concurrency int
+	Duration    time.Duration
+}
+
+// Result is the outcome of a scenario, as reported in JSON.
+type Result struct {
+	Endpoint      Endpoint `json:"endpoint"`
+	Models        int      `json:"models"`
+	Shape         Shape    `json:"shape"`
+	Indexed       bool     `json:"indexed"`
+	DecisionCache bool     `json:"decisionCache"`
+	Concurrency   int      `json:"concurrency"`
+	// Requests counts the calls made; a batch call counts once.
+	Requests int64 `json:"requests"`
+	// Errors counts calls that failed or answered other than 200.
+	Errors int64 `json:"errors"`
+	// Denied counts calls answered 200 with a denial, which means the synthetic cache is wrong.
+	Denied            int64   `json:"denied"`
+	DurationSeconds   float64 `json:"durationSeconds"`
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Latency           Latency `json:"latencyMs"`
+}
+
+// Latency summarizes call latencies in milliseconds.
+type Latency struct {
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// Run calls the scenario's endpoint of the maas-api at baseURL, serving the cache, with
+// Concurrency callers for Duration, each asking for the models in turn.
+func Run(ctx context.Context, client *http.Client, baseURL string, c *Cache, s Scenario) (Result, error) {
+	if _, err := ParseEndpoint(string(s.Endpoint)); err != nil {
+		return Result{}, err
+	}
+	if s.Concurrency <= 0 || s.Duration <= 0 {
+		return Result{}, fmt.Errorf("concurrency and duration must be positive, got %d and %s", s.Concurrency, s.Duration)
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	ctx, cancel := context.WithTimeout(ctx, s.Duration)
+	defer cancel()
+	var (
+		errs, denied atomic.Int64
+		wg           sync.WaitGroup
+	)
+	latencies := make([][]time.Duration, s.Concurrency)
+	start := time.Now()
+	for w := range s.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; ctx.Err() == nil; i += s.Concurrency {
+				began := time.Now()
+				allowed, err := c.call(ctx, client, baseURL, s.Endpoint, i)
+				if ctx.Err() != nil {
+					return // cut short by the end of the scenario
+				}
+				latencies[w] = append(latencies[w], time.Since(began))
+				switch {
+				case err != nil:
+					errs.Add(1)
+				case !allowed:
+					denied.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	all := slices.Concat(latencies...)
+	result := Result{
+		Endpoint:          s.Endpoint,
+		Models:            c.opts.Models,
+		Shape:             c.opts.Shape,
+		Indexed:           !c.opts.Unindexed,
+		DecisionCache:     c.opts.DecisionCacheTTL > 0,
+		Concurrency:       s.Concurrency,
+		Requests:          int64(len(all)),
+		Errors:            errs.Load(),
+		Denied:            denied.Load(),
+		DurationSeconds:   elapsed.Seconds(),
+		RequestsPerSecond: float64(len(all)) / elapsed.Seconds(),
+		Latency:           summarize(all),
+	}
+	return result, nil
+}
+
+// call makes one call for model i and reports whether it was allowed.
+func (c *Cache) call(ctx context.Context, client *http.Client, baseURL string, endpoint Endpoint, i int) (bool, error) {
+	var (
+		req *http.Request
+		err error
+	)
+	switch endpoint {
+	case EndpointSelect:
+		body, _ := json.Marshal(subscription.SelectRequest{Username: User, Groups: c.Groups(), RequestedModel: c.Model(i)})
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/internal/v1/subscriptions/select", bytes.NewReader(body))
+	case EndpointAccess:
+		namespace, name, _ := strings.Cut(c.Model(i), "/")
+		if c.opts.Shape == ShapeAliased {
+			name += "-latest"
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet,
+			baseURL+"/v1/models/"+url.PathEscape(name)+"/access?namespace="+url.QueryEscape(namespace), nil)
+	case EndpointBatch:
+		batch := extauthz.BatchRequest{Requests: make([]extauthz.BatchEntry, BatchSize)}
+		for j := range batch.Requests {
+			batch.Requests[j] = extauthz.BatchEntry{Path: c.Path(i*BatchSize + j)}
+		}
+		body, _ := json.Marshal(batch)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/v1/models/authorize/batch", bytes.NewReader(body))
+	}
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(constant.HeaderUsername, User)
+	req.Header.Set(constant.HeaderGroup, c.GroupsHeader())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+
+	switch endpoint {
+	case EndpointSelect:
+		var selected subscription.SelectResponse
+		if err := json.NewDecoder(resp.Body).Decode(&selected); err != nil {
+			return false, err
+		}
+		return selected.Error == "", nil
+	case EndpointAccess:
+		var access extauthz.AccessResponse
+		if err := json.NewDecoder(resp.Body).Decode(&access); err != nil {
+			return false, err
+		}
+		return access.Allowed, nil
+	default:
+		var batch extauthz.BatchResponse
+		if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+			return false, err
+		}
+		return !slices.ContainsFunc(batch.Data, func(d extauthz.Decision) bool { return !d.Allowed }), nil
+	}
+}
+
+func summarize(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	slices.Sort(latencies)
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	at := func(q float64) float64 { return ms(latencies[int(q*float64(len(latencies)-1))]) }
+	var total time.Duration
+	for _, d := range latencies {
+		total += d
+	}
+	return Latency{
+		Mean: ms(total / time.Duration(len(latencies))),
+		P50:  at(0.50),
+		P90:  at(0.90),
+		P99:  at(0.99),
+		Max:  ms(latencies[len(latencies)-1]),
+	}
+}
@@ -0,0 +1,374 @@
+// Package bench measures the authorization hot path of maas-api against a synthetic informer
+// cache, so throughput and latency can be compared across cache sizes, MaaSModelRef shapes and
+// concurrency levels before and after a change. It backs the benchmarks of this package and the
+// maas-bench load runner.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/authpolicy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+// Shape is how the synthetic MaaSModelRefs look, and so which lookups a request takes.
+type Shape string
+
+const (
+	// ShapeBare models have no annotations and are requested by /<namespace>/<name>.
+	ShapeBare Shape = "bare"
+	// ShapeAnnotated models carry display, allow-list, sampling and context window annotations.
+	ShapeAnnotated Shape = "annotated"
+	// ShapeAliased models have two spec.aliases and are requested by /<namespace>/<alias>.
+	ShapeAliased Shape = "aliased"
+	// ShapeRouted models are ExternalModels with a custom path prefix, requested by it. The
+	// prefixes share their first segment, as a provider's often do, so they share a route index key.
+	ShapeRouted Shape = "routed"
+)
+
+// Shapes lists every shape, in the order reports use.
+var Shapes = []Shape{ShapeBare, ShapeAnnotated, ShapeAliased, ShapeRouted}
+
+// ParseShape parses a shape name.
+func ParseShape(s string) (Shape, error) {
+	shape := Shape(strings.TrimSpace(s))
+	if !slices.Contains(Shapes, shape) {
+		return "", fmt.Errorf("unknown shape %q: want one of bare, annotated, aliased, routed", s)
+	}
+	return shape, nil
+}
+
+// Namespace of the synthetic subscriptions and MaaSAuthPolicies.
+const subscriptionNamespace = "models-as-a-service"
+
+// Options describes a synthetic cache.
+type Options struct {
+	// Models is the number of MaaSModelRefs.
+	Models int
+	// Namespaces the models are spread over. Defaults to one per 100 models.
+	Namespaces int
+	// Subscriptions the models are spread over, each with a MaaSAuthPolicy for the same group.
+	// The caller belongs to all of them. Defaults to 10.
+	Subscriptions int
+	Shape         Shape
+	// Unindexed hides the informer indexes from the resolvers, which then scan every
+	// MaaSModelRef and MaaSAuthPolicy, as before the indexed lookups.
+	Unindexed bool
+	// DecisionCacheTTL enables the selection decision cache (see subscription.DecisionCache).
+	DecisionCacheTTL time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Namespaces <= 0 {
+		o.Namespaces = max(1, o.Models/100)
+	}
+	if o.Subscriptions <= 0 {
+		o.Subscriptions = 10
+	}
+	if o.Shape == "" {
+		o.Shape = ShapeBare
+	}
+	return o
+}
+
+// User is the caller every synthetic request is made as.
+const User = "bench-user"
+
+// Cache is a synthetic informer cache of MaaSModelRefs, MaaSSubscriptions and MaaSAuthPolicies,
+// indexed as maas-api indexes its informers.
+type Cache struct {
+	opts          Options
+	models        cache.Indexer
+	subscriptions cache.Indexer
+	policies      cache.Indexer
+}
+
+// NewCache fills a cache as opts describes. Model i is in namespace i%Namespaces and included
+// in subscription i%Subscriptions, so every model is reachable through exactly one tier.
+func NewCache(opts Options) (*Cache, error) {
+	opts = opts.withDefaults()
+	if opts.Models <= 0 {
+		return nil, fmt.Errorf("models must be positive, got %d", opts.Models)
+	}
+	if _, err := ParseShape(string(opts.Shape)); err != nil {
+		return nil, err
+	}
+	c := &Cache{
+		opts: opts,
+		models: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			models.NameIndex:     models.NameIndexFunc,
+			models.RouteIndex:    models.RouteIndexFunc,
+			models.AliasIndex:    models.AliasIndexFunc,
+		}),
+		subscriptions: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}),
+		policies:      cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{authpolicy.ModelIndex: authpolicy.ModelIndexFunc}),
+	}
+
+	refs := make([][]any, opts.Subscriptions)
+	for i := range opts.Models {
+		u := c.model(i)
+		if err := c.models.Add(u); err != nil {
+			return nil, err
+		}
+		tier := i % opts.Subscriptions
+		refs[tier] = append(refs[tier], map[string]any{
+			"namespace":       u.GetNamespace(),
+			"name":            u.GetName(),
+			"tokenRateLimits": []any{map[string]any{"limit": int64(100000), "window": "1m"}},
+		})
+	}
+	for tier := range opts.Subscriptions {
+		if err := c.subscriptions.Add(subscriptionObject(tier, refs[tier])); err != nil {
+			return nil, err
+		}
+		if err := c.policies.Add(policyObject(tier, refs[tier])); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Options returns the options the cache was filled with, defaults applied.
+func (c *Cache) Options() Options {
+	return c.opts
+}
+
+// Groups returns the groups of User: one per subscription.
+func (c *Cache) Groups() []string {
+	groups := make([]string, c.opts.Subscriptions)
+	for tier := range groups {
+		groups[tier] = tierName(tier)
+	}
+	return groups
+}
+
+// Model returns the "namespace/name" of model i.
+func (c *Cache) Model(i int) string {
+	i %= c.opts.Models
+	return c.namespace(i) + "/" + modelName(i)
+}
+
+// Path returns the gateway path of a chat completion to model i, as a client of the shape
+// addresses it.
+func (c *Cache) Path(i int) string {
+	i %= c.opts.Models
+	switch c.opts.Shape {
+	case ShapeAliased:
+		return "/" + c.namespace(i) + "/" + modelName(i) + "-latest/v1/chat/completions"
+	case ShapeRouted:
+		return "/routed/" + modelName(i) + "/v1/chat/completions"
+	default:
+		return "/" + c.Model(i) + "/v1/chat/completions"
+	}
+}
+
+// Tier returns the subscription that includes model i.
+func (c *Cache) Tier(i int) string {
+	return tierName(i % c.opts.Models % c.opts.Subscriptions)
+}
+
+func (c *Cache) namespace(i int) string {
+	return "bench-" + strconv.Itoa(i%c.opts.Namespaces)
+}
+
+func modelName(i int) string { return "model-" + strconv.Itoa(i) }
+
+func tierName(tier int) string { return "tier-" + strconv.Itoa(tier) }
+
+func (c *Cache) model(i int) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+	u.SetKind("MaaSModelRef")
+	u.SetNamespace(c.namespace(i))
+	u.SetName(modelName(i))
+	u.SetResourceVersion("1")
+	kind := "LLMInferenceService"
+	switch c.opts.Shape {
+	case ShapeAnnotated:
+		u.SetAnnotations(map[string]string{
+			constant.AnnotationDisplayName:        "Model " + strconv.Itoa(i),
+			constant.AnnotationDescription:        "Synthetic model for benchmarks",
+			constant.AnnotationGenAIUseCase:       "chat",
+			constant.AnnotationContextWindow:      "32768",
+			constant.AnnotationMeteringSampleRate: "0.5",
+			constant.AnnotationAllowedGroups:      strings.Join(c.Groups(), ","),
+		})
+	case ShapeAliased:
+		_ = unstructured.SetNestedStringSlice(u.Object, []string{modelName(i) + "-latest", modelName(i) + "-stable"}, "spec", "aliases")
+	case ShapeRouted:
+		kind = "ExternalModel"
+		_ = unstructured.SetNestedField(u.Object, "/routed/"+modelName(i), "spec", "routing", "pathPrefix")
+	}
+	_ = unstructured.SetNestedMap(u.Object, map[string]any{"kind": kind, "name": modelName(i)}, "spec", "modelRef")
+	return u
+}
+
+func subscriptionObject(tier int, refs []any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+	u.SetKind("MaaSSubscription")
+	u.SetNamespace(subscriptionNamespace)
+	u.SetName(tierName(tier))
+	u.SetResourceVersion("1")
+	_ = unstructured.SetNestedSlice(u.Object, []any{map[string]any{"name": tierName(tier)}}, "spec", "owner", "groups")
+	_ = unstructured.SetNestedField(u.Object, int64(tier), "spec", "priority")
+	_ = unstructured.SetNestedSlice(u.Object, refs, "spec", "modelRefs")
+	return u
+}
+
+func policyObject(tier int, refs []any) *unstructured.Unstructured {
+	modelRefs := make([]any, len(refs))
+	for i, ref := range refs {
+		r, _ := ref.(map[string]any)
+		modelRefs[i] = map[string]any{"namespace": r["namespace"], "name": r["name"]}
+	}
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+	u.SetKind("MaaSAuthPolicy")
+	u.SetNamespace(subscriptionNamespace)
+	u.SetName(tierName(tier))
+	u.SetResourceVersion("1")
+	_ = unstructured.SetNestedSlice(u.Object, modelRefs, "spec", "modelRefs")
+	_ = unstructured.SetNestedSlice(u.Object, []any{map[string]any{"name": tierName(tier)}}, "spec", "subjects", "groups")
+	return u
+}
+
+// ModelLister returns the MaaSModelRef lister of the cache: indexed like the informer lister of
+// maas-api, or only able to list when Unindexed is set.
+//
+//nolint:ireturn // the lister's dynamic type decides which lookups resolvers use
+func (c *Cache) ModelLister() models.MaaSModelRefLister {
+	l := &indexLister{indexer: c.models}
+	if c.opts.Unindexed {
+		return listOnly{l}
+	}
+	return l
+}
+
+// SubscriptionLister returns the MaaSSubscription lister of the cache.
+//
+//nolint:ireturn // mirrors config.ClusterConfig
+func (c *Cache) SubscriptionLister() subscription.Lister {
+	return &indexLister{indexer: c.subscriptions}
+}
+
+// AuthPolicyLister returns the MaaSAuthPolicy lister of the cache, indexed by model unless
+// Unindexed is set.
+//
+//nolint:ireturn // the lister's dynamic type decides whether policies are scanned
+func (c *Cache) AuthPolicyLister() authpolicy.Lister {
+	l := &indexLister{indexer: c.policies}
+	if c.opts.Unindexed {
+		return listOnly{l}
+	}
+	return l
+}
+
+// NewSelector returns a subscription selector over the cache, set up as maas-api sets it up.
+func (c *Cache) NewSelector(log *logger.Logger) *subscription.Selector {
+	modelLister := c.ModelLister()
+	subscriptions := c.SubscriptionLister()
+	selector := subscription.NewSelector(log, subscriptions)
+	selector.SetLineageResolver(models.LineageResolver(modelLister))
+	selector.SetSoftDeletedResolver(models.SoftDeletedResolver(modelLister))
+	selector.SetMaintenanceResolver(models.MaintenanceResolver(modelLister, constant.DefaultMaintenanceRetryAfter))
+	selector.SetServedResolver(models.ServedResolver(modelLister))
+	selector.SetDecisionCache(subscription.NewDecisionCache(c.opts.DecisionCacheTTL))
+	selector.SetCatalog(subscription.NewCatalog(log, subscriptions))
+	return selector
+}
+
+// NewServer returns an ext_authz evaluator over the cache, with the resolvers maas-api sets, that
+// accepts every API key in keys.
+func (c *Cache) NewServer(log *logger.Logger, keys extauthz.KeyValidator) *extauthz.Server {
+	modelLister := c.ModelLister()
+	s := extauthz.NewServer(log, keys, c.NewSelector(log), c.AuthPolicyLister())
+	s.SetLineageResolver(models.LineageResolver(modelLister))
+	s.SetModelResolver(models.NamespaceResolver(modelLister))
+	s.SetAliasResolver(models.AliasResolver(modelLister))
+	s.SetRouteResolver(models.NewRouteResolver(modelLister))
+	s.SetAllowListResolver(models.AllowListResolver(modelLister))
+	s.SetErrorResponseResolver(models.ErrorResponseResolver(modelLister))
+	return s
+}
+
+// GroupsHeader returns the X-MaaS-Group value of User.
+func (c *Cache) GroupsHeader() string {
+	b, _ := json.Marshal(c.Groups())
+	return string(b)
+}
+
+// indexLister lists the objects of an indexer, with every lookup of the informer listers of
+// maas-api.
+type indexLister struct {
+	indexer cache.Indexer
+}
+
+func (l *indexLister) List() ([]*unstructured.Unstructured, error) {
+	return unstructuredItems(l.indexer.List()), nil
+}
+
+func (l *indexLister) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	obj, exists, err := l.indexer.GetByKey(namespace + "/" + name)
+	if err != nil || !exists {
+		return nil, err
+	}
+	u, _ := obj.(*unstructured.Unstructured)
+	return u, nil
+}
+
+func (l *indexLister) ByNamespace(namespace string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(cache.NamespaceIndex, namespace)
+}
+
+func (l *indexLister) ByName(name string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(models.NameIndex, name)
+}
+
+func (l *indexLister) ByAlias(alias string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(models.AliasIndex, alias)
+}
+
+func (l *indexLister) ByRoute(key string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(models.RouteIndex, key)
+}
+
+func (l *indexLister) ByModel(modelNamespace, modelName string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(authpolicy.ModelIndex, modelNamespace+"/"+modelName)
+}
+
+func (l *indexLister) byIndex(index, key string) ([]*unstructured.Unstructured, error) {
+	objs, err := l.indexer.ByIndex(index, key)
+	if err != nil {
+		return nil, err
+	}
+	return unstructuredItems(objs), nil
+}
+
+func unstructuredItems(objs []any) []*unstructured.Unstructured {
+	out := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		if u, ok := o.(*unstructured.Unstructured); ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// listOnly hides every lookup of a lister but List.
+type listOnly struct{ l *indexLister }
+
+func (o listOnly) List() ([]*unstructured.Unstructured, error) { return o.l.List() }
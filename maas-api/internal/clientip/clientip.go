@@ -0,0 +1,169 @@
+// Package clientip works out the address of the client behind the gateway from the proxy headers,
+// believing only the hops it is configured to trust so a client cannot choose the IP it is logged with.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Forwarding headers a Resolver can read. Only one is read: proxies append to their own header and
+// pass any other through unchanged, so a client could set the other one to any address.
+const (
+	HeaderXForwardedFor = "xff"
+	HeaderForwarded     = "forwarded"
+)
+
+// Resolver resolves client IPs, trusting forwarding headers only when they were added by a trusted proxy.
+type Resolver struct {
+	trusted []netip.Prefix
+	header  string
+}
+
+// ParsePrefixes parses a comma-separated list of CIDRs and bare IPs.
+func ParsePrefixes(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for entry := range strings.SplitSeq(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ValidateHeader checks that header is HeaderXForwardedFor, HeaderForwarded or empty, which means
+// HeaderXForwardedFor.
+func ValidateHeader(header string) error {
+	switch header {
+	case "", HeaderXForwardedFor, HeaderForwarded:
+		return nil
+	default:
+		return fmt.Errorf("invalid forwarded header %q: must be %s or %s", header, HeaderXForwardedFor, HeaderForwarded)
+	}
+}
+
+// NewResolver creates a Resolver trusting the proxies in trustedProxies, a comma-separated list of
+// CIDRs and IPs, and reading the client chain from header, the one those proxies append to (see
+// ValidateHeader). With no trusted proxies, the peer address is always the client.
+func NewResolver(trustedProxies, header string) (*Resolver, error) {
+	if err := ValidateHeader(header); err != nil {
+		return nil, err
+	}
+	prefixes, err := ParsePrefixes(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{trusted: prefixes, header: header}, nil
+}
+
+func (r *Resolver) isTrusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range r.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the client address for a request that arrived from remoteAddr (host:port or a
+// bare IP). The forwarding chain, taken from the configured header only, is walked from the
+// nearest hop outwards while hops are trusted. The first untrusted address is the client, so
+// entries a client prepends itself are never reached. An unparseable entry (e.g. "unknown" or an
+// obfuscated identifier) stops the walk at the last trusted hop.
+func (r *Resolver) ClientIP(remoteAddr string, header http.Header) netip.Addr {
+	client, ok := parseAddr(remoteAddr)
+	if !ok || !r.isTrusted(client) {
+		return client
+	}
+	hops := r.forwardedFor(header)
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(hops[i])
+		if !ok {
+			return client
+		}
+		client = hop
+		if !r.isTrusted(hop) {
+			return client
+		}
+	}
+	return client
+}
+
+// forwardedFor returns the client chain from the RFC 7239 Forwarded header or from X-Forwarded-For,
+// whichever the resolver reads, ordered from the original client to the nearest proxy.
+func (r *Resolver) forwardedFor(header http.Header) []string {
+	var hops []string
+	if r.header == HeaderForwarded {
+		for _, value := range header.Values("Forwarded") {
+			for element := range strings.SplitSeq(value, ",") {
+				hop := "unknown"
+				for pair := range strings.SplitSeq(element, ";") {
+					key, val, found := strings.Cut(strings.TrimSpace(pair), "=")
+					if found && strings.EqualFold(key, "for") {
+						hop = strings.Trim(val, `"`)
+					}
+				}
+				hops = append(hops, hop)
+			}
+		}
+		return hops
+	}
+	for _, value := range header.Values("X-Forwarded-For") {
+		for hop := range strings.SplitSeq(value, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// parseAddr accepts an IP, host:port, or a Forwarded node such as [2001:db8::1]:443.
+func parseAddr(s string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(strings.Trim(s, "[]")); err == nil {
+		return addr.Unmap(), true
+	}
+	host, _, err := net.SplitHostPort(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// Middleware rewrites the request's RemoteAddr to the resolved client, so gin's ClientIP (and with
+// it the access log) reports the real client. The router must not trust any proxies itself, see
+// gin.Engine.SetTrustedProxies.
+func (r *Resolver) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if client := r.ClientIP(c.Request.RemoteAddr, c.Request.Header); client.IsValid() {
+			port := "0"
+			if _, p, err := net.SplitHostPort(c.Request.RemoteAddr); err == nil {
+				port = p
+			}
+			c.Request.RemoteAddr = net.JoinHostPort(client.String(), port)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,135 @@
+package clientip_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/clientip"
+)
+
+func TestClientIP(t *testing.T) {
+	resolver, err := clientip.NewResolver("10.0.0.0/8, 192.168.1.1, fd00::/8", clientip.HeaderXForwardedFor)
+	require.NoError(t, err)
+	forwarded, err := clientip.NewResolver("10.0.0.0/8, 192.168.1.1, fd00::/8", clientip.HeaderForwarded)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		resolver   *clientip.Resolver
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "untrusted peer ignores headers",
+			remoteAddr: "203.0.113.9:4711",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:       "203.0.113.9",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.1.2.3:4711",
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "nearest untrusted hop wins over a spoofed entry",
+			remoteAddr: "10.1.2.3:4711",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.7, 192.168.1.1"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "all hops trusted returns the original client",
+			remoteAddr: "10.1.2.3:4711",
+			headers:    map[string]string{"X-Forwarded-For": "10.9.9.9, 10.0.0.1"},
+			want:       "10.9.9.9",
+		},
+		{
+			name:       "garbage entry stops at the last trusted hop",
+			remoteAddr: "10.1.2.3:4711",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7, unknown"},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "client-supplied Forwarded is ignored when reading X-Forwarded-For",
+			remoteAddr: "10.1.2.3:4711",
+			headers: map[string]string{
+				"Forwarded":       "for=1.2.3.4",
+				"X-Forwarded-For": "198.51.100.7, 10.0.0.5",
+			},
+			want: "198.51.100.7",
+		},
+		{
+			name:       "Forwarded chain when configured",
+			resolver:   forwarded,
+			remoteAddr: "10.1.2.3:4711",
+			headers: map[string]string{
+				"Forwarded":       `for=1.2.3.4, for="[2001:db8::1]:443";proto=https, for=10.0.0.5`,
+				"X-Forwarded-For": "198.51.100.7",
+			},
+			want: "2001:db8::1",
+		},
+		{
+			name:       "client-supplied X-Forwarded-For is ignored when reading Forwarded",
+			resolver:   forwarded,
+			remoteAddr: "10.1.2.3:4711",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "obfuscated Forwarded node is not trusted as an address",
+			resolver:   forwarded,
+			remoteAddr: "10.1.2.3:4711",
+			headers:    map[string]string{"Forwarded": "for=_hidden;proto=https"},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "IPv4-mapped peer matches IPv4 CIDR",
+			remoteAddr: "[::ffff:10.1.2.3]:4711",
+			headers:    map[string]string{"X-Forwarded-For": "198.51.100.7"},
+			want:       "198.51.100.7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.headers {
+				header.Set(k, v)
+			}
+			r := resolver
+			if tt.resolver != nil {
+				r = tt.resolver
+			}
+			assert.Equal(t, tt.want, r.ClientIP(tt.remoteAddr, header).String())
+		})
+	}
+}
+
+func TestNewResolverRejectsInvalidEntries(t *testing.T) {
+	_, err := clientip.NewResolver("10.0.0.0/33", clientip.HeaderXForwardedFor)
+	require.ErrorContains(t, err, `invalid trusted proxy "10.0.0.0/33"`)
+	_, err = clientip.NewResolver("10.0.0.0/8", "x-real-ip")
+	require.ErrorContains(t, err, `invalid forwarded header "x-real-ip"`)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	resolver, err := clientip.NewResolver("10.0.0.0/8", clientip.HeaderXForwardedFor)
+	require.NoError(t, err)
+
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies(nil))
+	router.Use(resolver.Middleware())
+	router.GET("/ip", func(c *gin.Context) { c.String(http.StatusOK, c.ClientIP()) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+	req.RemoteAddr = "10.1.2.3:4711"
+	req.Header.Set("X-Forwarded-For", "6.6.6.6, 198.51.100.7")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "198.51.100.7", w.Body.String())
+}
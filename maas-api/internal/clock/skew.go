@@ -0,0 +1,191 @@
+// Package clock measures how far this pod's clock is from the Kubernetes API server's, which
+// maas-controller and every other replica also see. Subscription expiry and other schedules are
+// evaluated against the API server's time, so a pod on a node with a drifting clock neither keeps
+// a subscription alive after maas-controller has dropped its rate limits nor ends it early.
+//
+// Time read through a Skew never goes back, even when a new measurement lowers the offset, and the
+// latest time read is carried across restarts as a warm restart anchor. Budget windows therefore
+// never return to one that has already ended, which would count usage twice or reset a budget.
+package clock
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/rest"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/warmstart"
+)
+
+// dateResolution is the precision of the HTTP Date header.
+const dateResolution = time.Second
+
+// maxAnchorLead bounds how far ahead of this pod's clock a restored anchor may be. A later one
+// comes from a replica whose clock was wrong, and would hold Now still for too long.
+const maxAnchorLead = time.Hour
+
+// anchorKey is the warm restart entry holding the latest time read.
+const anchorKey = "latest"
+
+var skewSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "maas_clock_skew_seconds",
+	Help: "Measured offset of the Kubernetes API server's clock from this pod's; positive when the pod is behind.",
+})
+
+func init() {
+	prometheus.MustRegister(skewSeconds)
+}
+
+// Probe returns the server's current time, truncated to the second.
+type Probe func(ctx context.Context) (time.Time, error)
+
+// APIServerProbe reads the Date header of a GET /version on the API server.
+func APIServerProbe(cfg *rest.Config) (Probe, error) {
+	client, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API server client: %w", err)
+	}
+	endpoint := strings.TrimSuffix(cfg.Host, "/") + "/version"
+	return func(ctx context.Context) (time.Time, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return time.Time{}, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return time.Time{}, err
+		}
+		defer resp.Body.Close()
+		date, err := http.ParseTime(resp.Header.Get("Date"))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("API server response has no usable Date header: %w", err)
+		}
+		return date, nil
+	}, nil
+}
+
+// Skew tracks the offset of the API server's clock. Offsets below the threshold are treated as
+// noise, since the Date header only has one-second precision; above it, Now is corrected and a
+// warning is logged on every measurement. A nil Skew is the local clock.
+type Skew struct {
+	probe     Probe
+	threshold time.Duration
+	logger    *logger.Logger
+
+	offset atomic.Int64
+	// latest is the latest time Now returned, in Unix nanoseconds.
+	latest atomic.Int64
+}
+
+// NewSkew creates a Skew that corrects and warns about offsets of at least threshold.
+func NewSkew(log *logger.Logger, probe Probe, threshold time.Duration) *Skew {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Skew{probe: probe, threshold: threshold, logger: log}
+}
+
+// Now returns the current time on the API server's clock when this pod's is off by at least the
+// threshold, and the local time otherwise. It never returns a time before one it returned earlier,
+// or before a restored anchor; until the clock catches up it returns that time again.
+func (s *Skew) Now() time.Time {
+	now := time.Now()
+	if s == nil {
+		return now
+	}
+	if offset := s.Offset(); offset >= s.threshold || -offset >= s.threshold {
+		now = now.Add(offset)
+	}
+	return s.advance(now.UnixNano())
+}
+
+// advance raises latest to t and returns the new latest.
+func (s *Skew) advance(t int64) time.Time {
+	for {
+		latest := s.latest.Load()
+		if t <= latest {
+			return time.Unix(0, latest)
+		}
+		if s.latest.CompareAndSwap(latest, t) {
+			return time.Unix(0, t)
+		}
+	}
+}
+
+var _ warmstart.Part = (*Skew)(nil)
+
+// Entries implements warmstart.Part, returning the latest time read as the anchor.
+func (s *Skew) Entries() []warmstart.Entry {
+	latest := s.latest.Load()
+	if latest == 0 {
+		return nil
+	}
+	anchor := time.Unix(0, latest)
+	return []warmstart.Entry{{Key: anchorKey, Value: latest, Expiry: anchor.Add(maxAnchorLead)}}
+}
+
+// Merge implements warmstart.Part, keeping Now from going back before the anchors of stopped
+// replicas. Anchors more than maxAnchorLead ahead of this pod's clock are skipped.
+func (s *Skew) Merge(entries []warmstart.Entry) int {
+	limit := time.Now().Add(s.Offset()).Add(maxAnchorLead).UnixNano()
+	merged := 0
+	for _, e := range entries {
+		if e.Key != anchorKey || e.Value > limit || !time.Now().Before(e.Expiry) {
+			continue
+		}
+		s.advance(e.Value)
+		merged++
+	}
+	return merged
+}
+
+// Offset returns the last measured offset.
+func (s *Skew) Offset() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(s.offset.Load())
+}
+
+// Measure probes the API server once and records the offset. The server's time is taken as the
+// middle of its truncated second, and the local time as the middle of the round trip.
+func (s *Skew) Measure(ctx context.Context) error {
+	sent := time.Now()
+	server, err := s.probe(ctx)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(sent)
+	local := sent.Add(elapsed / 2)
+	offset := server.Add(dateResolution / 2).Sub(local).Round(time.Millisecond)
+
+	s.offset.Store(int64(offset))
+	skewSeconds.Set(offset.Seconds())
+	if offset >= s.threshold || -offset >= s.threshold {
+		s.logger.Warn("Clock skew against the Kubernetes API server detected; schedules follow the API server's clock",
+			"offset", offset.String(), "threshold", s.threshold.String())
+	}
+	return nil
+}
+
+// Run measures the offset now and then every interval until ctx is done.
+func (s *Skew) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Measure(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Warn("Failed to measure clock skew", "error", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package clock_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/clock"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// apiServer answers /version with a Date header offset from the local clock.
+func apiServer(t *testing.T, offset time.Duration) clock.Probe {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/version", r.URL.Path)
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	probe, err := clock.APIServerProbe(&rest.Config{Host: srv.URL})
+	require.NoError(t, err)
+	return probe
+}
+
+func TestSkewFollowsAPIServerBeyondThreshold(t *testing.T) {
+	skew := clock.NewSkew(logger.Development(), apiServer(t, 30*time.Second), 2*time.Second)
+	require.NoError(t, skew.Measure(context.Background()))
+
+	assert.InDelta(t, 30, skew.Offset().Seconds(), 1)
+	assert.InDelta(t, 30, time.Until(skew.Now()).Seconds(), 1)
+}
+
+func TestSkewIgnoresSmallOffsets(t *testing.T) {
+	skew := clock.NewSkew(logger.Development(), apiServer(t, 0), 2*time.Second)
+	require.NoError(t, skew.Measure(context.Background()))
+
+	assert.Less(t, skew.Offset().Abs(), time.Second)
+	assert.Less(t, time.Until(skew.Now()).Abs(), 10*time.Millisecond, "offsets below the threshold are not applied")
+}
+
+func TestSkewProbeFailureKeepsLastOffset(t *testing.T) {
+	calls := 0
+	probe := func(context.Context) (time.Time, error) {
+		calls++
+		if calls > 1 {
+			return time.Time{}, assert.AnError
+		}
+		return time.Now().Add(-time.Minute), nil
+	}
+	skew := clock.NewSkew(nil, probe, time.Second)
+	require.NoError(t, skew.Measure(context.Background()))
+	require.Error(t, skew.Measure(context.Background()))
+	assert.InDelta(t, -60, skew.Offset().Seconds(), 1)
+
+	var local *clock.Skew
+	assert.Zero(t, local.Offset())
+	assert.WithinDuration(t, time.Now(), local.Now(), time.Second)
+}
+
+func TestSkewNeverGoesBack(t *testing.T) {
+	offset := time.Minute
+	probe := func(context.Context) (time.Time, error) {
+		return time.Now().Add(offset), nil
+	}
+	skew := clock.NewSkew(nil, probe, time.Second)
+	require.NoError(t, skew.Measure(context.Background()))
+	ahead := skew.Now()
+
+	// The API server's clock was corrected back; Now holds until the local clock catches up.
+	offset = 0
+	require.NoError(t, skew.Measure(context.Background()))
+	assert.False(t, skew.Now().Before(ahead))
+}
+
+func TestSkewRestoresAnchor(t *testing.T) {
+	stopped := clock.NewSkew(nil, func(context.Context) (time.Time, error) {
+		return time.Now().Add(10 * time.Minute), nil
+	}, time.Second)
+	require.NoError(t, stopped.Measure(context.Background()))
+	anchor := stopped.Now()
+
+	// A replica starting on a node whose clock is behind, before its first measurement.
+	started := clock.NewSkew(nil, nil, time.Second)
+	assert.Equal(t, 1, started.Merge(stopped.Entries()))
+	assert.False(t, started.Now().Before(anchor))
+
+	far := clock.NewSkew(nil, nil, time.Second)
+	entries := stopped.Entries()
+	entries[0].Value = time.Now().Add(48 * time.Hour).UnixNano()
+	entries[0].Expiry = time.Now().Add(49 * time.Hour)
+	assert.Zero(t, far.Merge(entries), "anchors far ahead of the local clock are skipped")
+	assert.WithinDuration(t, time.Now(), far.Now(), time.Second)
+}
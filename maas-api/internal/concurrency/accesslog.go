@@ -0,0 +1,61 @@
+package concurrency
+
+import (
+	"errors"
+	"io"
+
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// Request headers the gateway must log for a request's lease to be released. ext_authz injects
+// both into admitted requests.
+const (
+	UsernameHeader        = "x-maas-username"
+	SubscriptionKeyHeader = "x-maas-subscription-key"
+)
+
+// AccessLogServer is an Envoy access log service (envoy.service.accesslog.v3) that releases the
+// lease of every request the gateway logs as ended. The gateway's http_grpc access logger must
+// log UsernameHeader and SubscriptionKeyHeader.
+type AccessLogServer struct {
+	accesslogv3.UnimplementedAccessLogServiceServer
+
+	limiter *Limiter
+	logger  *logger.Logger
+}
+
+// NewAccessLogServer creates an access log service releasing leases of limiter.
+func NewAccessLogServer(log *logger.Logger, limiter *Limiter) *AccessLogServer {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &AccessLogServer{limiter: limiter, logger: log}
+}
+
+// StreamAccessLogs implements accesslogv3.AccessLogServiceServer.
+func (s *AccessLogServer) StreamAccessLogs(stream accesslogv3.AccessLogService_StreamAccessLogsServer) error {
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&accesslogv3.StreamAccessLogsResponse{})
+		}
+		if err != nil {
+			return err
+		}
+		for _, entry := range msg.GetHttpLogs().GetLogEntry() {
+			req := entry.GetRequest()
+			headers := req.GetRequestHeaders()
+			username, subscriptionKey, requestID := headers[UsernameHeader], headers[SubscriptionKeyHeader], req.GetRequestId()
+			if username == "" || subscriptionKey == "" || requestID == "" {
+				// Requests ext_authz denied, or routes it does not guard.
+				continue
+			}
+			if err := s.limiter.Release(stream.Context(), username, subscriptionKey, requestID); err != nil {
+				// The lease expires on its own.
+				s.logger.Warn("Failed to release concurrency lease", "error", err, "subscription", subscriptionKey)
+			}
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Package concurrency caps the requests each user may have in progress on a model under a
+// subscription. LLM requests are long-lived, so rate limits alone do not bound the GPU capacity a
+// user holds. A request takes a lease when the ext_authz evaluator admits it and gives it back
+// when the gateway's access log reports that it ended; leases of requests the gateway never
+// reports expire on their own. Organizations can also be capped across all their models, so one
+// cannot saturate a shared GPU pool.
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/redis"
+)
+
+var limitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_concurrency_limited_total",
+	Help: "Requests denied because the user had as many requests in progress on the model as the subscription allows, by subscription.",
+}, []string{"subscription"})
+
+var organizationLimitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_organization_concurrency_limited_total",
+	Help: "Requests denied because the organization had as many requests in progress, across all models, as ORG_MAX_CONCURRENT_REQUESTS allows, by organization.",
+}, []string{"organization"})
+
+func init() {
+	prometheus.MustRegister(limitedTotal, organizationLimitedTotal)
+}
+
+// Store keeps the leases of requests in progress, one set per user and subscription key and one
+// per organization.
+type Store interface {
+	// Acquire adds lease to key, kept for ttl, unless key already holds limit live leases. It
+	// returns true when key holds lease afterwards, so acquiring a lease twice is admitted.
+	Acquire(ctx context.Context, key, lease string, limit int64, ttl time.Duration) (bool, error)
+	// Release removes lease from key.
+	Release(ctx context.Context, key, lease string) error
+	// Count returns the live leases of key.
+	Count(ctx context.Context, key string) (int64, error)
+}
+
+// MemoryStore keeps leases in this replica only. With several replicas the gateway's checks and
+// access logs reach different replicas; use RedisStore there.
+type MemoryStore struct {
+	mu     sync.Mutex
+	leases map[string]map[string]time.Time
+	now    func() time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{leases: map[string]map[string]time.Time{}, now: time.Now}
+}
+
+// live drops the expired leases of key and returns the rest. Callers hold mu.
+func (s *MemoryStore) live(key string, now time.Time) map[string]time.Time {
+	leases := s.leases[key]
+	for lease, expiry := range leases {
+		if !now.Before(expiry) {
+			delete(leases, lease)
+		}
+	}
+	if len(leases) == 0 {
+		delete(s.leases, key)
+		return nil
+	}
+	return leases
+}
+
+// Acquire implements Store.
+func (s *MemoryStore) Acquire(_ context.Context, key, lease string, limit int64, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	leases := s.live(key, now)
+	if _, ok := leases[lease]; !ok && int64(len(leases)) >= limit {
+		return false, nil
+	}
+	if leases == nil {
+		leases = map[string]time.Time{}
+		s.leases[key] = leases
+	}
+	leases[lease] = now.Add(ttl)
+	return true, nil
+}
+
+// Release implements Store.
+func (s *MemoryStore) Release(_ context.Context, key, lease string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.leases[key], lease)
+	s.live(key, s.now())
+	return nil
+}
+
+// Count implements Store.
+func (s *MemoryStore) Count(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.live(key, s.now()))), nil
+}
+
+const redisKeyPrefix = "maas:concurrency:"
+
+// acquireScript keeps the leases of a key in a sorted set scored by their expiry on Redis' own
+// clock, so replicas with skewed clocks share one view. It drops expired leases and adds the new
+// one if there is room, returning 1 when the set holds it.
+const acquireScript = `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local ttl = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+if not redis.call('ZSCORE', KEYS[1], ARGV[1]) and redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[2]) then
+  return 0
+end
+redis.call('ZADD', KEYS[1], now + ttl, ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ttl)
+return 1
+`
+
+// countScript drops expired leases and returns the rest.
+const countScript = `
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now)
+return redis.call('ZCARD', KEYS[1])
+`
+
+// RedisStore keeps leases in Redis, so a request admitted by one replica is released by whichever
+// replica receives its access log.
+type RedisStore struct {
+	client *redis.Client
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a store in the Redis at a redis:// or rediss:// URL.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	client, err := redis.NewClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: client}, nil
+}
+
+// Acquire implements Store.
+func (s *RedisStore) Acquire(ctx context.Context, key, lease string, limit int64, ttl time.Duration) (bool, error) {
+	reply, err := s.client.Do(ctx, "EVAL", acquireScript, "1", redisKeyPrefix+key,
+		lease, strconv.FormatInt(limit, 10), strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	if err != nil {
+		return false, err
+	}
+	acquired, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("redis: unexpected reply %v to concurrency script", reply)
+	}
+	return acquired == 1, nil
+}
+
+// Release implements Store.
+func (s *RedisStore) Release(ctx context.Context, key, lease string) error {
+	_, err := s.client.Do(ctx, "ZREM", redisKeyPrefix+key, lease)
+	return err
+}
+
+// Count implements Store.
+func (s *RedisStore) Count(ctx context.Context, key string) (int64, error) {
+	reply, err := s.client.Do(ctx, "EVAL", countScript, "1", redisKeyPrefix+key)
+	if err != nil {
+		return 0, err
+	}
+	count, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected reply %v to concurrency count script", reply)
+	}
+	return count, nil
+}
+
+// LimitResolver returns the cap on the requests each user may have in progress under a
+// model-scoped subscription key (namespace/name@modelNamespace/modelName), or 0 for none.
+type LimitResolver func(subscriptionKey string) int64
+
+// OrganizationResolver returns the organization ID of the subscription of a model-scoped
+// subscription key, or "" for none.
+type OrganizationResolver func(subscriptionKey string) string
+
+// ParseOrganizationLimits parses ORG_MAX_CONCURRENT_REQUESTS, a comma-separated list of
+// organizationId=limit pairs.
+func ParseOrganizationLimits(s string) (map[string]int64, error) {
+	limits := map[string]int64{}
+	for pair := range strings.SplitSeq(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		org, value, ok := strings.Cut(pair, "=")
+		org = strings.TrimSpace(org)
+		if !ok || org == "" {
+			return nil, fmt.Errorf("%q is not organizationId=limit", pair)
+		}
+		limit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("limit of organization %q must be a positive integer", org)
+		}
+		if _, dup := limits[org]; dup {
+			return nil, fmt.Errorf("organization %q is listed twice", org)
+		}
+		limits[org] = limit
+	}
+	return limits, nil
+}
+
+// Denial is the cap a request was denied by.
+type Denial struct {
+	Limit int64
+	// Organization is set when the cap is its organization's, across all models, rather than the
+	// user's on the model.
+	Organization string
+}
+
+// Limiter enforces the concurrency caps of subscriptions per user, and of organizations.
+type Limiter struct {
+	store         Store
+	limits        LimitResolver
+	ttl           time.Duration
+	organizations OrganizationResolver
+	orgLimits     map[string]int64
+}
+
+// NewLimiter creates a Limiter keeping leases in store for at most ttl, which should exceed the
+// longest request the gateway lets run.
+func NewLimiter(store Store, limits LimitResolver, ttl time.Duration) *Limiter {
+	return &Limiter{store: store, limits: limits, ttl: ttl}
+}
+
+// SetOrganizationLimits caps the requests all users of each organization in limits may have in
+// progress together, across models and subscriptions. organizations resolves the organization of
+// a subscription key.
+func (l *Limiter) SetOrganizationLimits(organizations OrganizationResolver, limits map[string]int64) {
+	l.organizations = organizations
+	l.orgLimits = limits
+}
+
+// leaseKey is the set of leases of a user under a subscription key.
+func leaseKey(username, subscriptionKey string) string {
+	return username + "|" + subscriptionKey
+}
+
+// organizationLeaseKey is the set of leases of an organization. It has no "|", unlike leaseKey.
+func organizationLeaseKey(organization string) string {
+	return "org:" + organization
+}
+
+// organization returns the organization of subscriptionKey when it has a cap.
+func (l *Limiter) organization(subscriptionKey string) (string, int64) {
+	if l.organizations == nil || len(l.orgLimits) == 0 {
+		return "", 0
+	}
+	org := l.organizations(subscriptionKey)
+	return org, l.orgLimits[org]
+}
+
+// Acquire takes the leases of request requestID of username under subscriptionKey: one against
+// the subscription's cap on the model and one against its organization's cap, for those that are
+// set. It returns the cap the request would exceed, or nil when it is admitted.
+func (l *Limiter) Acquire(ctx context.Context, username, subscriptionKey, requestID string) (*Denial, error) {
+	limit := l.limits(subscriptionKey)
+	if limit > 0 {
+		acquired, err := l.store.Acquire(ctx, leaseKey(username, subscriptionKey), requestID, limit, l.ttl)
+		if err != nil {
+			return nil, err
+		}
+		if !acquired {
+			subscription, _, _ := strings.Cut(subscriptionKey, "@")
+			limitedTotal.WithLabelValues(subscription).Inc()
+			return &Denial{Limit: limit}, nil
+		}
+	}
+	org, orgLimit := l.organization(subscriptionKey)
+	if orgLimit <= 0 {
+		return nil, nil
+	}
+	acquired, err := l.store.Acquire(ctx, organizationLeaseKey(org), requestID, orgLimit, l.ttl)
+	if err == nil && acquired {
+		return nil, nil
+	}
+	// The request does not run, so it must not hold the user's lease either.
+	if limit > 0 {
+		if releaseErr := l.store.Release(ctx, leaseKey(username, subscriptionKey), requestID); releaseErr != nil && err == nil {
+			err = releaseErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	organizationLimitedTotal.WithLabelValues(org).Inc()
+	return &Denial{Limit: orgLimit, Organization: org}, nil
+}
+
+// Release gives back the leases of request requestID, if it holds any.
+func (l *Limiter) Release(ctx context.Context, username, subscriptionKey, requestID string) error {
+	if err := l.store.Release(ctx, leaseKey(username, subscriptionKey), requestID); err != nil {
+		return err
+	}
+	if org, orgLimit := l.organization(subscriptionKey); orgLimit > 0 {
+		return l.store.Release(ctx, organizationLeaseKey(org), requestID)
+	}
+	return nil
+}
+
+// InFlight returns the requests username has in progress under subscriptionKey.
+func (l *Limiter) InFlight(ctx context.Context, username, subscriptionKey string) (int64, error) {
+	return l.store.Count(ctx, leaseKey(username, subscriptionKey))
+}
@@ -0,0 +1,148 @@
+package concurrency_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	accesslogdatav3 "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/concurrency"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+const key = "maas/gold@llm/granite"
+
+func newLimiter(ttl time.Duration) *concurrency.Limiter {
+	return concurrency.NewLimiter(concurrency.NewMemoryStore(), func(subscriptionKey string) int64 {
+		if subscriptionKey == key {
+			return 2
+		}
+		return 0
+	}, ttl)
+}
+
+func TestLimiter(t *testing.T) {
+	ctx := t.Context()
+	limiter := newLimiter(time.Minute)
+
+	for _, id := range []string{"r1", "r2", "r2"} {
+		denial, err := limiter.Acquire(ctx, "alice", key, id)
+		require.NoError(t, err)
+		assert.Nil(t, denial, "request %s, a retried check keeps its lease", id)
+	}
+	denial, err := limiter.Acquire(ctx, "alice", key, "r3")
+	require.NoError(t, err)
+	assert.Equal(t, &concurrency.Denial{Limit: 2}, denial)
+
+	denial, _ = limiter.Acquire(ctx, "bob", key, "r4")
+	assert.Nil(t, denial, "caps are per user")
+	denial, _ = limiter.Acquire(ctx, "alice", "maas/free@llm/granite", "r5")
+	assert.Nil(t, denial, "keys without a cap are not limited")
+
+	require.NoError(t, limiter.Release(ctx, "alice", key, "r1"))
+	n, err := limiter.InFlight(ctx, "alice", key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	denial, _ = limiter.Acquire(ctx, "alice", key, "r3")
+	assert.Nil(t, denial, "a released lease frees a slot")
+}
+
+func TestLimiterOrganizationLimits(t *testing.T) {
+	ctx := t.Context()
+	limiter := newLimiter(time.Minute)
+	limiter.SetOrganizationLimits(func(subscriptionKey string) string {
+		if strings.HasPrefix(subscriptionKey, "maas/") {
+			return "acme"
+		}
+		return ""
+	}, map[string]int64{"acme": 2})
+
+	denial, err := limiter.Acquire(ctx, "alice", key, "r1")
+	require.NoError(t, err)
+	assert.Nil(t, denial)
+	denial, _ = limiter.Acquire(ctx, "bob", "maas/free@llm/other", "r2")
+	assert.Nil(t, denial, "the organization's cap spans users and models")
+	denial, err = limiter.Acquire(ctx, "carol", key, "r3")
+	require.NoError(t, err)
+	assert.Equal(t, &concurrency.Denial{Limit: 2, Organization: "acme"}, denial)
+	n, _ := limiter.InFlight(ctx, "carol", key)
+	assert.Zero(t, n, "a request denied by its organization's cap gives back the user's lease")
+	denial, _ = limiter.Acquire(ctx, "carol", "other/gold@llm/granite", "r4")
+	assert.Nil(t, denial, "subscriptions of other organizations are not limited by it")
+
+	require.NoError(t, limiter.Release(ctx, "bob", "maas/free@llm/other", "r2"))
+	denial, _ = limiter.Acquire(ctx, "carol", key, "r3")
+	assert.Nil(t, denial, "a released lease frees a slot of the organization")
+}
+
+func TestParseOrganizationLimits(t *testing.T) {
+	limits, err := concurrency.ParseOrganizationLimits(" acme=200, globex = 50 ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"acme": 200, "globex": 50}, limits)
+
+	for _, invalid := range []string{"acme", "=5", "acme=0", "acme=x", "acme=1,acme=2"} {
+		_, err := concurrency.ParseOrganizationLimits(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestLimiterLeasesExpire(t *testing.T) {
+	ctx := t.Context()
+	limiter := newLimiter(10 * time.Millisecond)
+	for _, id := range []string{"r1", "r2"} {
+		denial, _ := limiter.Acquire(ctx, "alice", key, id)
+		require.Nil(t, denial)
+	}
+	time.Sleep(20 * time.Millisecond)
+	n, err := limiter.InFlight(ctx, "alice", key)
+	require.NoError(t, err)
+	assert.Zero(t, n, "leases of requests never logged expire")
+	denial, _ := limiter.Acquire(ctx, "alice", key, "r3")
+	assert.Nil(t, denial)
+}
+
+func TestAccessLogServerReleasesLeases(t *testing.T) {
+	ctx := t.Context()
+	limiter := newLimiter(time.Minute)
+	for _, id := range []string{"r1", "r2"} {
+		denial, _ := limiter.Acquire(ctx, "alice", key, id)
+		require.Nil(t, denial)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	accesslogv3.RegisterAccessLogServiceServer(srv, concurrency.NewAccessLogServer(logger.Development(), limiter))
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := accesslogv3.NewAccessLogServiceClient(conn).StreamAccessLogs(ctx)
+	require.NoError(t, err)
+	entry := func(id string, headers map[string]string) *accesslogdatav3.HTTPAccessLogEntry {
+		return &accesslogdatav3.HTTPAccessLogEntry{Request: &accesslogdatav3.HTTPRequestProperties{RequestId: id, RequestHeaders: headers}}
+	}
+	require.NoError(t, stream.Send(&accesslogv3.StreamAccessLogsMessage{
+		LogEntries: &accesslogv3.StreamAccessLogsMessage_HttpLogs{HttpLogs: &accesslogv3.StreamAccessLogsMessage_HTTPAccessLogEntries{
+			LogEntry: []*accesslogdatav3.HTTPAccessLogEntry{
+				entry("r1", map[string]string{concurrency.UsernameHeader: "alice", concurrency.SubscriptionKeyHeader: key}),
+				entry("r2", nil), // denied by ext_authz, no identity headers
+			},
+		}},
+	}))
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	n, err := limiter.InFlight(ctx, "alice", key)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
@@ -4,16 +4,21 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/auth"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/authpolicy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/federation"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
 )
@@ -21,23 +26,59 @@ import (
 type ClusterConfig struct {
 	ClientSet *kubernetes.Clientset
 
-	// MaaSModelRefLister lists MaaSModelRef CRs from the informer cache for GET /v1/models.
+	// RestConfig is the API server connection the clients were created from.
+	RestConfig *rest.Config
+
+	// DynamicClient writes MaaS custom resources, e.g. models published through POST /v1/models.
+	DynamicClient dynamic.Interface
+
+	// MaaSModelRefLister lists MaaSModelRef CRs from the informer cache for GET /v1/models. With
+	// federation (see Federate), it also lists those of the remote clusters.
 	MaaSModelRefLister models.MaaSModelRefLister
 
+	// LocalMaaSModelRefLister lists only the MaaSModelRefs of this cluster.
+	LocalMaaSModelRefLister models.MaaSModelRefLister
+
 	// MaaSSubscriptionLister lists MaaSSubscription CRs from the informer cache for subscription selection.
 	MaaSSubscriptionLister subscription.Lister
 
+	// MaaSRateLimitOverrideLister lists MaaSRateLimitOverride CRs from the informer cache, applied on
+	// top of the subscriptions' limits.
+	MaaSRateLimitOverrideLister subscription.Lister
+
+	// MaaSAuthPolicyLister lists MaaSAuthPolicy CRs from the informer cache for the ext_authz evaluator.
+	MaaSAuthPolicyLister authpolicy.Lister
+
 	// AdminChecker uses SubjectAccessReview to check if a user is an admin.
 	// Admin is determined by RBAC: can user create maasauthpolicies in the configured MaaS namespace?
 	AdminChecker *auth.SARAdminChecker
 
-	informersSynced []cache.InformerSynced
-	startFuncs      []func(<-chan struct{})
+	// AccessReviewer uses SubjectAccessReview to check a user's own RBAC before maas-api acts on their behalf.
+	AccessReviewer *auth.SARAccessReviewer
+
+	// maasModelRefInformer backs MaaSModelRefLister; see AddMaaSModelRefHandler.
+	maasModelRefInformer cache.SharedIndexInformer
+	// modelScope selects the MaaSModelRefs MaaSModelRefLister returns and handlers are told about.
+	modelScope models.Scope
+	// federation merges the remote clusters' MaaSModelRefs into MaaSModelRefLister; nil without
+	// federation.
+	federation *federation.Index
+	// subscriptionInformer backs MaaSSubscriptionLister; see AddMaaSSubscriptionHandler.
+	subscriptionInformer cache.SharedIndexInformer
+	// overrideInformer backs MaaSRateLimitOverrideLister; see AddMaaSRateLimitOverrideHandler.
+	overrideInformer cache.SharedIndexInformer
+
+	// informers are keyed by resource for the GET /ready report and the informer metrics.
+	informers informerCollector
 }
 
-// maasModelRefLister implements models.MaaSModelRefLister from a cache.GenericLister (informer-backed).
+// maasModelRefLister implements models.MaaSModelRefLister from a cache.GenericLister (informer-backed),
+// indexed by models.NameIndex, models.RouteIndex and models.AliasIndex. It only returns MaaSModelRefs in scope
+// and leaves out ignored ones, so every lookup treats them as not found.
 type maasModelRefLister struct {
-	lister cache.GenericLister
+	lister  cache.GenericLister
+	indexer cache.Indexer
+	scope   models.Scope
 }
 
 func (m *maasModelRefLister) List() ([]*unstructured.Unstructured, error) {
@@ -48,16 +89,85 @@ func (m *maasModelRefLister) List() ([]*unstructured.Unstructured, error) {
 	out := make([]*unstructured.Unstructured, 0, len(objs))
 	for _, o := range objs {
 		u, ok := o.(*unstructured.Unstructured)
-		if !ok {
+		if !ok || !m.scope.Serves(u) {
 			continue
 		}
-		// Return all MaaSModelRefs from all namespaces (no filtering)
 		out = append(out, u)
 	}
 	return out, nil
 }
 
-// subscriptionLister implements subscription.Lister from a cache.GenericLister (informer-backed).
+// Get looks up one MaaSModelRef by key, implementing models.MaaSModelRefGetter.
+func (m *maasModelRefLister) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	obj, err := m.lister.ByNamespace(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || !m.scope.Serves(u) {
+		return nil, nil
+	}
+	return u, nil
+}
+
+// ByNamespace returns the MaaSModelRefs of one namespace, implementing models.MaaSModelRefNamespaceLister.
+func (m *maasModelRefLister) ByNamespace(namespace string) ([]*unstructured.Unstructured, error) {
+	return m.byIndex(cache.NamespaceIndex, namespace)
+}
+
+// ByName returns the MaaSModelRefs named name in any namespace, implementing models.MaaSModelRefNameLister.
+func (m *maasModelRefLister) ByName(name string) ([]*unstructured.Unstructured, error) {
+	return m.byIndex(models.NameIndex, name)
+}
+
+// ByAlias returns the MaaSModelRefs with alias in spec.aliases, implementing models.MaaSModelRefAliasLister.
+func (m *maasModelRefLister) ByAlias(alias string) ([]*unstructured.Unstructured, error) {
+	return m.byIndex(models.AliasIndex, alias)
+}
+
+// ByRoute returns the MaaSModelRefs under a models.RouteIndex key, implementing models.MaaSModelRefRouteLister.
+func (m *maasModelRefLister) ByRoute(key string) ([]*unstructured.Unstructured, error) {
+	return m.byIndex(models.RouteIndex, key)
+}
+
+func (m *maasModelRefLister) byIndex(index, key string) ([]*unstructured.Unstructured, error) {
+	objs, err := m.indexer.ByIndex(index, key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		if u, ok := o.(*unstructured.Unstructured); ok && m.scope.Serves(u) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// authPolicyLister implements authpolicy.IndexedLister from an informer indexed by authpolicy.ModelIndex.
+type authPolicyLister struct {
+	subscriptionLister
+	indexer cache.Indexer
+}
+
+func (a *authPolicyLister) ByModel(modelNamespace, modelName string) ([]*unstructured.Unstructured, error) {
+	objs, err := a.indexer.ByIndex(authpolicy.ModelIndex, modelNamespace+"/"+modelName)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		if u, ok := o.(*unstructured.Unstructured); ok {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// subscriptionLister implements subscription.Lister (and authpolicy.Lister) from a cache.GenericLister (informer-backed).
 type subscriptionLister struct {
 	lister cache.GenericLister
 }
@@ -78,7 +188,9 @@ func (s *subscriptionLister) List() ([]*unstructured.Unstructured, error) {
 	return out, nil
 }
 
-func NewClusterConfig(_ string, subscriptionNamespace string, resyncPeriod time.Duration) (*ClusterConfig, error) {
+// NewClusterConfig connects to the cluster and sets up the informers. Only MaaSModelRefs in
+// modelScope are listed and reported to handlers.
+func NewClusterConfig(_ string, subscriptionNamespace string, resyncPeriod time.Duration, modelScope models.Scope) (*ClusterConfig, error) {
 	restConfig, err := LoadRestConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes config: %w", err)
@@ -94,17 +206,67 @@ func NewClusterConfig(_ string, subscriptionNamespace string, resyncPeriod time.
 		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	// MaaSModelRef informer (cached); watches all namespaces so we can list any namespace from cache.
-	maasDynamicFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, resyncPeriod)
+	// MaaSModelRef informer (cached); watches all namespaces so we can list any namespace from cache,
+	// or only the scope's namespace when it allows just one. The API server applies the scope's
+	// label selector; the listers check the namespace allow-list. A sharded scope drops the other
+	// shards' namespaces before they reach the cache.
+	modelNamespace := metav1.NamespaceAll
+	if len(modelScope.Namespaces) == 1 {
+		modelNamespace = modelScope.Namespaces[0]
+	}
+	var selectLabels func(*metav1.ListOptions)
+	if modelScope.Selector != nil && !modelScope.Selector.Empty() {
+		selectLabels = func(opts *metav1.ListOptions) { opts.LabelSelector = modelScope.Selector.String() }
+	}
+	var inShard func(string) bool
+	if modelScope.Sharded() {
+		inShard = modelScope.InShard
+	}
 	maasGVR := models.GVR()
-	maasInformer := maasDynamicFactory.ForResource(maasGVR)
-	maasModelRefListerVal := &maasModelRefLister{lister: maasInformer.Lister()}
+	maasInformer := newInformer(dynamicClient, maasGVR, modelNamespace, resyncPeriod, selectLabels, inShard)
+	if err := maasInformer.AddIndexers(cache.Indexers{
+		models.NameIndex:  models.NameIndexFunc,
+		models.RouteIndex: models.RouteIndexFunc,
+		models.AliasIndex: models.AliasIndexFunc,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to index MaaSModelRefs: %w", err)
+	}
+	maasModelRefListerVal := &maasModelRefLister{
+		lister:  cache.NewGenericLister(maasInformer.GetIndexer(), maasGVR.GroupResource()),
+		indexer: maasInformer.GetIndexer(),
+		scope:   modelScope,
+	}
 
 	// MaaSSubscription informer (cached); watches only the configured namespace for subscription selection.
-	subscriptionDynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, resyncPeriod, subscriptionNamespace, nil)
 	subscriptionGVR := subscription.GVR()
-	subscriptionInformer := subscriptionDynamicFactory.ForResource(subscriptionGVR)
-	maasSubscriptionListerVal := &subscriptionLister{lister: subscriptionInformer.Lister()}
+	subscriptionInformer := newInformer(dynamicClient, subscriptionGVR, subscriptionNamespace, resyncPeriod, nil, nil)
+	maasSubscriptionListerVal := &subscriptionLister{lister: cache.NewGenericLister(subscriptionInformer.GetIndexer(), subscriptionGVR.GroupResource())}
+
+	// MaaSRateLimitOverride informer (cached); overrides apply to the subscriptions of their namespace.
+	overrideGVR := subscription.OverrideGVR()
+	overrideInformer := newInformer(dynamicClient, overrideGVR, subscriptionNamespace, resyncPeriod, nil, nil)
+
+	// MaaSAuthPolicy informer (cached); policies live alongside subscriptions in the MaaS namespace.
+	// Indexed by referenced model so ext_authz finds a model's policies without scanning them all.
+	authPolicyGVR := authpolicy.GVR()
+	authPolicyInformer := newInformer(dynamicClient, authPolicyGVR, subscriptionNamespace, resyncPeriod, nil, nil)
+	if err := authPolicyInformer.AddIndexers(cache.Indexers{authpolicy.ModelIndex: authpolicy.ModelIndexFunc}); err != nil {
+		return nil, fmt.Errorf("failed to index MaaSAuthPolicies: %w", err)
+	}
+	maasAuthPolicyListerVal := &authPolicyLister{
+		subscriptionLister: subscriptionLister{lister: cache.NewGenericLister(authPolicyInformer.GetIndexer(), authPolicyGVR.GroupResource())},
+		indexer:            authPolicyInformer.GetIndexer(),
+	}
+
+	informers := informerCollector{
+		maasGVR.Resource:         maasInformer,
+		subscriptionGVR.Resource: subscriptionInformer,
+		overrideGVR.Resource:     overrideInformer,
+		authPolicyGVR.Resource:   authPolicyInformer,
+	}
+	if err := prometheus.Register(informers); err != nil {
+		return nil, fmt.Errorf("failed to register informer metrics: %w", err)
+	}
 
 	// SAR-based admin checker: uses SubjectAccessReview to check RBAC permissions.
 	// Admin is determined by: can user create maasauthpolicies in the MaaS namespace?
@@ -112,28 +274,128 @@ func NewClusterConfig(_ string, subscriptionNamespace string, resyncPeriod time.
 	adminCheckerVal := auth.NewSARAdminChecker(clientset, subscriptionNamespace)
 
 	return &ClusterConfig{
-		ClientSet: clientset,
+		ClientSet:     clientset,
+		RestConfig:    restConfig,
+		DynamicClient: dynamicClient,
 
-		MaaSModelRefLister:     maasModelRefListerVal,
-		MaaSSubscriptionLister: maasSubscriptionListerVal,
-		AdminChecker:           adminCheckerVal,
+		MaaSModelRefLister:          maasModelRefListerVal,
+		LocalMaaSModelRefLister:     maasModelRefListerVal,
+		MaaSSubscriptionLister:      maasSubscriptionListerVal,
+		MaaSRateLimitOverrideLister: &subscriptionLister{lister: cache.NewGenericLister(overrideInformer.GetIndexer(), overrideGVR.GroupResource())},
+		MaaSAuthPolicyLister:        maasAuthPolicyListerVal,
+		AdminChecker:                adminCheckerVal,
+		AccessReviewer:              auth.NewSARAccessReviewer(clientset),
 
-		informersSynced: []cache.InformerSynced{
-			maasInformer.Informer().HasSynced,
-			subscriptionInformer.Informer().HasSynced,
-		},
-		startFuncs: []func(<-chan struct{}){
-			maasDynamicFactory.Start,
-			subscriptionDynamicFactory.Start,
-		},
+		maasModelRefInformer: maasInformer,
+		modelScope:           modelScope,
+		subscriptionInformer: subscriptionInformer,
+		overrideInformer:     overrideInformer,
+		informers:            informers,
 	}, nil
 }
 
+// StartAndWaitForSync starts the informers and waits until their caches have synced, recording
+// how long each took. It returns false when stopCh closes first.
 func (c *ClusterConfig) StartAndWaitForSync(stopCh <-chan struct{}) bool {
-	for _, start := range c.startFuncs {
-		start(stopCh)
+	start := time.Now()
+	synced := make([]cache.InformerSynced, 0, len(c.informers))
+	for resource, informer := range c.informers {
+		go informer.Run(stopCh)
+		go func() {
+			if cache.WaitForNamedCacheSync(resource, stopCh, informer.HasSynced) {
+				syncDuration.WithLabelValues(resource).Set(time.Since(start).Seconds())
+			}
+		}()
+		synced = append(synced, informer.HasSynced)
+	}
+	// Remote clusters do not hold up readiness: their models are unknown until they sync.
+	if c.federation != nil {
+		c.federation.Start(stopCh)
+	}
+	return cache.WaitForCacheSync(stopCh, synced...)
+}
+
+// Federate makes MaaSModelRefLister also return the MaaSModelRefs of remotes, and records
+// clusterName as the origin of the local ones. It must be called before the listers are used and
+// the informers started.
+func (c *ClusterConfig) Federate(log *logger.Logger, clusterName string, remotes []federation.Remote, token string,
+	refresh time.Duration,
+) error {
+	local, ok := c.LocalMaaSModelRefLister.(federation.Lister)
+	if !ok {
+		return fmt.Errorf("MaaSModelRef lister %T cannot be federated", c.LocalMaaSModelRefLister)
+	}
+	if err := c.maasModelRefInformer.SetTransform(federation.OriginTransform(clusterName)); err != nil {
+		return fmt.Errorf("failed to record the origin of MaaSModelRefs: %w", err)
+	}
+	index, err := federation.NewIndex(log, clusterName, local, remotes, c.modelScope, token, refresh)
+	if err != nil {
+		return err
+	}
+	if err := prometheus.Register(index); err != nil {
+		return fmt.Errorf("failed to register federation metrics: %w", err)
+	}
+	c.federation = index
+	c.MaaSModelRefLister = index
+	return nil
+}
+
+// Federation returns the index of federated clusters, or nil without federation.
+func (c *ClusterConfig) Federation() *federation.Index {
+	return c.federation
+}
+
+// ModelScope returns the MaaSModelRefs this instance serves.
+func (c *ClusterConfig) ModelScope() models.Scope {
+	return c.modelScope
+}
+
+// AddMaaSModelRefHandler notifies handler of changes to the MaaSModelRefs in scope, including
+// those of federated clusters. Ignoring a model is seen as its deletion.
+func (c *ClusterConfig) AddMaaSModelRefHandler(handler cache.ResourceEventHandler) error {
+	handler = cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj any) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			u, ok := obj.(*unstructured.Unstructured)
+			return ok && c.modelScope.Serves(u)
+		},
+		Handler: handler,
+	}
+	if _, err := c.maasModelRefInformer.AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to watch MaaSModelRefs: %w", err)
+	}
+	if c.federation != nil {
+		return c.federation.AddEventHandler(handler)
+	}
+	return nil
+}
+
+// AddMaaSSubscriptionHandler notifies handler of changes to the MaaSSubscription informer cache.
+func (c *ClusterConfig) AddMaaSSubscriptionHandler(handler cache.ResourceEventHandler) error {
+	if _, err := c.subscriptionInformer.AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to watch MaaSSubscriptions: %w", err)
+	}
+	return nil
+}
+
+// AddMaaSRateLimitOverrideHandler notifies handler of changes to the MaaSRateLimitOverride
+// informer cache.
+func (c *ClusterConfig) AddMaaSRateLimitOverrideHandler(handler cache.ResourceEventHandler) error {
+	if _, err := c.overrideInformer.AddEventHandler(handler); err != nil {
+		return fmt.Errorf("failed to watch MaaSRateLimitOverrides: %w", err)
+	}
+	return nil
+}
+
+// CachesSynced reports, per resource, whether its informer cache and indexes are populated.
+func (c *ClusterConfig) CachesSynced() map[string]bool {
+	out := make(map[string]bool, len(c.informers))
+	for resource, informer := range c.informers {
+		out[resource] = informer.HasSynced()
 	}
-	return cache.WaitForCacheSync(stopCh, c.informersSynced...)
+	return out
 }
 
 // LoadRestConfig creates a *rest.Config using client-go loading rules.
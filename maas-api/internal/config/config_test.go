@@ -6,6 +6,11 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/identity"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/kms"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/throttle"
 )
 
 const testGatewayName = "my-gateway"
@@ -183,6 +188,535 @@ func TestValidate(t *testing.T) {
 			},
 			expectError: "must be at least 1",
 		},
+		{
+			name: "QuotaWarningThreshold above 99 returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				QuotaWarningThreshold:     100,
+				LimitadorURL:              "http://limitador:8080",
+			},
+			expectError: "QUOTA_WARNING_THRESHOLD must be between 0 and 99",
+		},
+		{
+			name: "invalid MeteringReasonLabel returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				MeteringReasonLabel:       "message",
+			},
+			expectError: "METERING_REASON_LABEL",
+		},
+		{
+			name: "invalid AuthzReasonVerbosity returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				AuthzReasonVerbosity:      "verbose",
+			},
+			expectError: "AUTHZ_REASON_VERBOSITY",
+		},
+		{
+			name: "invalid ModelNamespaces returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ModelNamespaces:           "llm,Partner_Models",
+			},
+			expectError: "MODEL_NAMESPACES",
+		},
+		{
+			name: "invalid ModelLabelSelector returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ModelLabelSelector:        "gateway in (partner",
+			},
+			expectError: "MODEL_LABEL_SELECTOR",
+		},
+		{
+			name: "ModelShard out of range returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ModelShards:               3,
+				ModelShard:                3,
+			},
+			expectError: "MODEL_SHARD must be between 0 and 2",
+		},
+		{
+			name: "PrewarmToken without metrics listener returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				PrewarmToken:              "secret",
+			},
+			expectError: "PREWARM_TOKEN requires METRICS_ADDRESS",
+		},
+		{
+			name: "UpstreamCredentialsToken without metrics listener returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				UpstreamCredentialsToken:  "secret",
+			},
+			expectError: "UPSTREAM_CREDENTIALS_TOKEN requires METRICS_ADDRESS",
+		},
+		{
+			name: "FederationEndpoints without token returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ClusterName:               "hub",
+				FederationEndpoints:       "gpu-east=https://maas-api.gpu-east.example.com",
+				FederationRefreshInterval: 30 * time.Second,
+			},
+			expectError: "FEDERATION_ENDPOINTS requires FEDERATION_TOKEN",
+		},
+		{
+			name: "Remote cluster named like the local one returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ClusterName:               "hub",
+				FederationKubeconfigs:     "hub=/etc/federation/hub",
+				FederationRefreshInterval: 30 * time.Second,
+			},
+			expectError: "has the name of this cluster",
+		},
+		{
+			name: "EnforceContextWindow without ext_authz returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				EnforceContextWindow:      true,
+			},
+			expectError: "ENFORCE_CONTEXT_WINDOW requires EXT_AUTHZ_ADDRESS",
+		},
+		{
+			name: "ConcurrencyRedisURL without concurrency limits returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ConcurrencyRedisURL:       "redis://redis:6379",
+			},
+			expectError: "CONCURRENCY_REDIS_URL requires ENFORCE_CONCURRENCY_LIMITS",
+		},
+		{
+			name: "invalid OrgMaxConcurrentRequests returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtAuthzAddress:           ":9001",
+				EnforceConcurrencyLimits:  true,
+				ConcurrencyLeaseTTL:       time.Minute,
+				OrgMaxConcurrentRequests:  "acme=200,globex",
+			},
+			expectError: `ORG_MAX_CONCURRENT_REQUESTS: "globex" is not organizationId=limit`,
+		},
+		{
+			name: "QuotaWarningThreshold without Limitador returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				QuotaWarningThreshold:     80,
+			},
+			expectError: "requires LIMITADOR_URL",
+		},
+		{
+			name: "QuotaHeaders without Limitador returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				QuotaHeaders:              true,
+			},
+			expectError: "QUOTA_HEADERS requires LIMITADOR_URL",
+		},
+		{
+			name: "invalid TrustedProxies returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				TrustedProxies:            "10.0.0.0/8,not-an-ip",
+			},
+			expectError: `invalid trusted proxy "not-an-ip"`,
+		},
+		{
+			name: "invalid ForwardedHeader returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ForwardedHeader:           "both",
+			},
+			expectError: `FORWARDED_HEADER: invalid forwarded header "both"`,
+		},
+		{
+			name: "AuthzThrottle Redis URL without a rate returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				AuthzThrottle:             throttle.Options{MaxInFlight: 100, RedisURL: "redis://redis:6379"},
+			},
+			expectError: "AUTHZ_RATE_LIMIT_REDIS_URL requires AUTHZ_RATE_LIMIT",
+		},
+		{
+			name: "Identity issuer without audience returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				Identity:                  identity.Options{Issuer: "https://sso.example.com/realms/maas", UsernameClaim: "sub"},
+			},
+			expectError: "IDENTITY_JWT_ISSUER requires IDENTITY_JWT_AUDIENCE",
+		},
+		{
+			name: "negative ModelNotFoundTTL returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ModelNotFoundTTL:          -time.Second,
+			},
+			expectError: "MODEL_NOT_FOUND_TTL must not be negative",
+		},
+		{
+			name: "negative MaintenanceRetryAfter returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				MaintenanceRetryAfter:     -time.Second,
+			},
+			expectError: "MAINTENANCE_RETRY_AFTER must not be negative",
+		},
+		{
+			name: "negative DecisionCacheTTL returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				DecisionCacheTTL:          -time.Second,
+			},
+			expectError: "DECISION_CACHE_TTL must not be negative",
+		},
+		{
+			name: "unknown ExtAuthzModelSources returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtAuthzModelSources:      "header,query",
+			},
+			expectError: "EXT_AUTHZ_MODEL_SOURCES",
+		},
+		{
+			name: "RequestSigningSecretsFile without ext_authz returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				RequestSigningSecretsFile: "/etc/maas/signing-secrets",
+				RequestSigningMaxSkew:     time.Minute,
+			},
+			expectError: "REQUEST_SIGNING_SECRETS_FILE requires EXT_AUTHZ_ADDRESS",
+		},
+		{
+			name: "RequestSigningRedisURL with a bad scheme returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtAuthzAddress:           ":9001",
+				RequestSigningSecretsFile: "/etc/maas/signing-secrets",
+				RequestSigningMaxSkew:     time.Minute,
+				RequestSigningRedisURL:    "http://redis",
+			},
+			expectError: "REQUEST_SIGNING_REDIS_URL",
+		},
+		{
+			name: "Short SignedURLSecret returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				SignedURLSecret:           "too-short",
+				SignedURLMaxTTL:           time.Hour,
+			},
+			expectError: "SIGNED_URL_SECRET",
+		},
+		{
+			name: "QuotaRedisURL without UsageIngestToken returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				QuotaRedisURL:             "redis://redis:6379/0",
+			},
+			expectError: "QUOTA_REDIS_URL requires USAGE_INGEST_TOKEN",
+		},
+		{
+			name: "QuotaRedisURL with a bad scheme returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				UsageIngestToken:          "gateway-token",
+				QuotaRedisURL:             "http://redis",
+			},
+			expectError: "QUOTA_REDIS_URL",
+		},
+		{
+			name: "unknown RateLimitBackend returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				RateLimitBackend:          "memcached",
+			},
+			expectError: "RATE_LIMIT_BACKEND",
+		},
+		{
+			name: "redis RateLimitBackend without QuotaRedisURL returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtAuthzAddress:           ":9001",
+				RateLimitBackend:          "redis",
+			},
+			expectError: "RATE_LIMIT_BACKEND=redis requires EXT_AUTHZ_ADDRESS and QUOTA_REDIS_URL",
+		},
+		{
+			name: "redis RateLimitBackend is valid",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtAuthzAddress:           ":9001",
+				UsageIngestToken:          "gateway-token",
+				QuotaRedisURL:             "redis://redis:6379/0",
+				RateLimitBackend:          "redis",
+			},
+		},
+		{
+			name: "host ExtAuthzModelSources is valid",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtAuthzModelSources:      "host,path",
+			},
+		},
+		{
+			name: "relative APISuffixes returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				APISuffixes:               "/v1/embeddings,v1/models",
+			},
+			expectError: "API_SUFFIXES",
+		},
+		{
+			name: "wildcard APISuffixes is valid",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				APISuffixes:               "*",
+			},
+		},
+		{
+			name: "relative ExtProcPaths returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ExtProcPaths:              "/v1/chat/completions,v1/completions",
+			},
+			expectError: "EXT_PROC_PATHS",
+		},
+		{
+			name: "negative ClockSkewThreshold returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ClockSkewThreshold:        -time.Second,
+			},
+			expectError: "CLOCK_SKEW_THRESHOLD must not be negative",
+		},
+		{
+			name: "TracingSamplingPercentage above 100 returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				TracingSamplingPercentage: 150,
+			},
+			expectError: "TRACING_SAMPLING_PERCENTAGE",
+		},
+		{
+			name: "negative ReadOnlyRetryAfter returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ReadOnlyRetryAfter:        -time.Second,
+			},
+			expectError: "READ_ONLY_RETRY_AFTER must not be negative",
+		},
+		{
+			name: "UsageRemoteWriteInterval below one second returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				UsageRemoteWriteURL:       "http://mimir.example.com/api/v1/push",
+				UsageRemoteWriteInterval:  100 * time.Millisecond,
+			},
+			expectError: "USAGE_REMOTE_WRITE_INTERVAL must be at least 1s",
+		},
+		{
+			name: "unknown AuditSinks returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				AuditSinks:                "stdout,syslog",
+			},
+			expectError: "AUDIT_SINKS",
+		},
+		{
+			name: "webhook AuditSinks without URL returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				AuditSinks:                "stdout,webhook",
+			},
+			expectError: "AUDIT_WEBHOOK_URL is required",
+		},
+		{
+			name: "store AuditSinks is valid",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				AuditSinks:                "stdout,store",
+			},
+		},
+		{
+			name: "unknown ReadyChecks returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				ReadyChecks:               "gateway,database",
+				ReadyCheckGatewayURL:      "https://gateway.example.com",
+			},
+			expectError: "READY_CHECKS",
+		},
+		{
+			name: "limitador ReadyChecks without LimitadorURL returns error",
+			cfg: Config{
+				DBConnectionURL:               "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:       30,
+				MaaSSubscriptionNamespace:     "models-as-a-service",
+				ReadyChecks:                   "authorino,limitador",
+				ReadyCheckAuthConfigNamespace: "kuadrant-system",
+			},
+			expectError: "LIMITADOR_URL is required",
+		},
+		{
+			name: "unknown MultiSubscriptionTieBreak returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				MultiSubscriptionTieBreak: "random",
+			},
+			expectError: "MULTI_SUBSCRIPTION_TIE_BREAK",
+		},
+		{
+			name: "JanitorInterval below one minute returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				JanitorInterval:           30 * time.Second,
+			},
+			expectError: "JANITOR_INTERVAL must be 0 (disabled) or at least 1m",
+		},
+		{
+			name: "negative WarmRestartMaxAge returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				WarmRestartMaxAge:         -time.Minute,
+			},
+			expectError: "WARM_RESTART_MAX_AGE",
+		},
+		{
+			name: "negative JanitorRetention returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				JanitorInterval:           time.Hour,
+				JanitorRetention:          -time.Hour,
+			},
+			expectError: "JANITOR_RETENTION must be positive",
+		},
+		{
+			name: "unknown KMS provider returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				KMS:                       kms.Options{Provider: "gcpkms"},
+			},
+			expectError: "unknown KMS provider",
+		},
+		{
+			name: "vault KMS provider without key returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				KMS:                       kms.Options{Provider: kms.ProviderVault, VaultAddress: "https://vault:8200"},
+			},
+			expectError: "requires a Vault address and a transit key name",
+		},
+		{
+			name: "KMS rewrap without provider returns error",
+			cfg: Config{
+				DBConnectionURL:           "postgresql://localhost/test",
+				APIKeyMaxExpirationDays:   30,
+				MaaSSubscriptionNamespace: "models-as-a-service",
+				KMSRewrap:                 true,
+			},
+			expectError: "KMS_REWRAP requires KMS_PROVIDER",
+		},
 	}
 
 	for _, tt := range tests {
@@ -264,3 +798,31 @@ func TestHandleDeprecatedFlags(t *testing.T) {
 		}
 	})
 }
+
+func TestAuthModeAndFeatures(t *testing.T) {
+	cfg := &Config{KMS: kms.Options{Provider: kms.ProviderNone}}
+	if got := cfg.AuthMode(); got != AuthModeAuthorino {
+		t.Errorf("expected auth mode %q, got %q", AuthModeAuthorino, got)
+	}
+	for name, enabled := range cfg.Features() {
+		if enabled {
+			t.Errorf("expected feature %q to be disabled by default", name)
+		}
+	}
+
+	cfg.ExtAuthzAddress = ":9001"
+	cfg.JanitorInterval = time.Hour
+	cfg.KMS.Provider = "local"
+	if got := cfg.AuthMode(); got != AuthModeExtAuthz {
+		t.Errorf("expected auth mode %q, got %q", AuthModeExtAuthz, got)
+	}
+	features := cfg.Features()
+	for _, name := range []string{"extAuthz", "janitor", "descriptionEncryption"} {
+		if !features[name] {
+			t.Errorf("expected feature %q to be enabled", name)
+		}
+	}
+	if features["quotaWarnings"] {
+		t.Error("expected quotaWarnings to stay disabled")
+	}
+}
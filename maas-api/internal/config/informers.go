@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	listDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maas_informer_list_duration_seconds",
+		Help:    "Duration of the full lists an informer makes at startup and whenever its watch has to be re-established, by resource.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"resource"})
+	syncDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "maas_informer_sync_duration_seconds",
+		Help: "Time from startup until an informer cache first synced, by resource.",
+	}, []string{"resource"})
+
+	cacheObjectsDesc = prometheus.NewDesc("maas_informer_cache_objects",
+		"Objects in an informer cache, by resource.", []string{"resource"}, nil)
+	cacheSyncedDesc = prometheus.NewDesc("maas_informer_synced",
+		"Whether an informer cache has synced (1) or not (0), by resource.", []string{"resource"}, nil)
+)
+
+func init() {
+	prometheus.MustRegister(listDuration, syncDuration)
+}
+
+// newInformer returns an informer of gvr in namespace (all when empty) whose lists are timed.
+// tweak adjusts the list and watch options, e.g. to select labels. When keep is set, objects whose
+// namespace it rejects are dropped from lists and watch events, so they are never cached.
+func newInformer(client dynamic.Interface, gvr schema.GroupVersionResource, namespace string, resync time.Duration,
+	tweak func(*metav1.ListOptions), keep func(namespace string) bool,
+) cache.SharedIndexInformer {
+	resource := client.Resource(gvr).Namespace(namespace)
+	lw := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			if tweak != nil {
+				tweak(&opts)
+			}
+			start := time.Now()
+			list, err := resource.List(ctx, opts)
+			listDuration.WithLabelValues(gvr.Resource).Observe(time.Since(start).Seconds())
+			if err != nil {
+				return nil, err
+			}
+			if keep != nil {
+				list.Items = slices.DeleteFunc(list.Items, func(u unstructured.Unstructured) bool { return !keep(u.GetNamespace()) })
+			}
+			return list, nil
+		},
+		WatchFuncWithContext: func(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+			if tweak != nil {
+				tweak(&opts)
+			}
+			w, err := resource.Watch(ctx, opts)
+			if err != nil || keep == nil {
+				return w, err
+			}
+			return watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+				// Bookmarks and errors carry no namespaced object and must reach the reflector.
+				if e.Type == watch.Bookmark || e.Type == watch.Error {
+					return e, true
+				}
+				obj, err := meta.Accessor(e.Object)
+				return e, err == nil && keep(obj.GetNamespace())
+			}), nil
+		},
+	}
+	return cache.NewSharedIndexInformer(lw, &unstructured.Unstructured{}, resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+}
+
+// informerCollector reports the size and sync state of the informer caches.
+type informerCollector map[string]cache.SharedIndexInformer
+
+func (c informerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheObjectsDesc
+	ch <- cacheSyncedDesc
+}
+
+func (c informerCollector) Collect(ch chan<- prometheus.Metric) {
+	for resource, informer := range c {
+		ch <- prometheus.MustNewConstMetric(cacheObjectsDesc, prometheus.GaugeValue, float64(len(informer.GetStore().ListKeys())), resource)
+		synced := 0.0
+		if informer.HasSynced() {
+			synced = 1
+		}
+		ch <- prometheus.MustNewConstMetric(cacheSyncedDesc, prometheus.GaugeValue, synced, resource)
+	}
+}
@@ -0,0 +1,51 @@
+package config //nolint:testpackage // tests use unexported newInformer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func modelRef(namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(models.GVR().GroupVersion().String())
+	u.SetKind("MaaSModelRef")
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func TestNewInformerShard(t *testing.T) {
+	gvr := models.GVR()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "MaaSModelRefList"},
+		modelRef("llm", "granite"), modelRef("llm-shared", "llama"))
+
+	// "llm" is in shard 2 of 3 and "llm-shared" in shard 1.
+	scope := models.Scope{Shards: 3, Shard: 2}
+	informer := newInformer(client, gvr, metav1.NamespaceAll, 0, nil, scope.InShard)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informer.Run(ctx.Done())
+	require.True(t, cache.WaitForCacheSync(ctx.Done(), informer.HasSynced))
+	assert.Equal(t, []string{"llm/granite"}, informer.GetStore().ListKeys(), "lists only keep the shard")
+
+	resource := client.Resource(gvr)
+	_, err := resource.Namespace("llm-shared").Create(ctx, modelRef("llm-shared", "phi"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = resource.Namespace("llm").Create(ctx, modelRef("llm", "mistral"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(informer.GetStore().ListKeys()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"llm/granite", "llm/mistral"}, informer.GetStore().ListKeys(), "watches only keep the shard")
+}
@@ -18,9 +18,128 @@ const (
 	// DefaultAPIKeyMaxExpirationDays is the default maximum allowed expiration for API keys.
 	DefaultAPIKeyMaxExpirationDays = 90
 
+	// DefaultJanitorRetention is how long revoked and expired API keys are kept before the janitor deletes them.
+	DefaultJanitorRetention = 30 * 24 * time.Hour
+
+	// DefaultModelNotFoundTTL is how long a model name that matched no MaaSModelRef is remembered.
+	DefaultModelNotFoundTTL = 10 * time.Second
+
+	// DefaultDecisionCacheTTL is how long a subscription selection result is reused.
+	DefaultDecisionCacheTTL = 5 * time.Second
+
+	// DefaultClockSkewThreshold is the clock offset from the API server above which maas-api
+	// warns and follows the API server's clock.
+	DefaultClockSkewThreshold = 2 * time.Second
+	// ClockSkewCheckInterval is how often the clock offset is measured.
+	ClockSkewCheckInterval = time.Minute
+
+	// DefaultUsageRemoteWriteInterval is how often usage is pushed via Prometheus remote write.
+	DefaultUsageRemoteWriteInterval = 30 * time.Second
+
+	// DefaultClusterName names the cluster maas-api runs in when it federates models.
+	DefaultClusterName = "local"
+	// DefaultFederationRefreshInterval is how often remote clusters are contacted, and remote
+	// endpoints polled, when maas-api federates models.
+	DefaultFederationRefreshInterval = 30 * time.Second
+
+	// DefaultReadOnlyRetryAfter is the Retry-After sent with mutations rejected in read-only mode.
+	DefaultReadOnlyRetryAfter = time.Minute
+	// DefaultMaintenanceRetryAfter is the Retry-After sent for models in maintenance without a
+	// planned end.
+	DefaultMaintenanceRetryAfter = 5 * time.Minute
+	// DefaultTokenizerTimeout bounds a call to the tokenizer service before the context window
+	// check falls back to estimating the prompt tokens.
+	DefaultTokenizerTimeout = 500 * time.Millisecond
+	// DefaultConcurrencyLeaseTTL is how long a request counts against its concurrency cap when the
+	// gateway never reports that it ended.
+	DefaultConcurrencyLeaseTTL = 10 * time.Minute
+	// ReadOnlyPollInterval is how often the read-only annotation on the maas-api namespace is read.
+	ReadOnlyPollInterval = 15 * time.Second
+	// AnnotationReadOnly set to "true" on the maas-api namespace switches maas-api to read-only mode.
+	AnnotationReadOnly = "maas.opendatahub.io/read-only"
+
+	// DefaultAuthConfigNamespace is where Kuadrant creates the Authorino AuthConfigs of AuthPolicies.
+	DefaultAuthConfigNamespace = "kuadrant-system"
+
+	// DefaultExtProcPaths are the OpenAI endpoints served through the shared model route.
+	DefaultExtProcPaths = "/v1/chat/completions,/v1/completions,/v1/embeddings,/v1/responses"
+
+	// DefaultAPISuffixes are the upstream API paths ext_authz allows after a model: the OpenAI
+	// endpoints and those of the model classes.
+	DefaultAPISuffixes = "/v1/chat/completions,/v1/completions,/v1/embeddings,/v1/responses," +
+		"/v1/audio/transcriptions,/v1/audio/translations,/v1/audio/speech,/v1/rerank,/v2/rerank,/rerank,/v1/models"
+
 	// LLMInferenceService annotation keys for model metadata.
 	AnnotationGenAIUseCase  = "opendatahub.io/genai-use-case"
 	AnnotationDescription   = "openshift.io/description"
 	AnnotationDisplayName   = "openshift.io/display-name"
 	AnnotationContextWindow = "opendatahub.io/context-window"
+
+	// AnnotationThroughputPerReplica is set on a MaaSModelRef by admins to declare how many tokens
+	// per minute one backend replica sustains. Used by the admin capacity view.
+	AnnotationThroughputPerReplica = "maas.opendatahub.io/throughput-tokens-per-minute"
+
+	// AnnotationFallbackModels is an ordered, comma-separated list of MaaSModelRefs ("name" in the
+	// same namespace or "namespace/name") that POST /v1/fallback tries when this model is at capacity.
+	AnnotationFallbackModels = "maas.opendatahub.io/fallback-models"
+
+	// Self-serve publication (POST /v1/models). A published model starts with approval "pending"
+	// and is reachable only by its publisher until an admin approves it.
+	AnnotationApproval    = "maas.opendatahub.io/approval"
+	AnnotationPublishedBy = "maas.opendatahub.io/published-by"
+	LabelPublished        = "maas.opendatahub.io/published"
+	ApprovalPending       = "pending"
+
+	// DefaultPrivateTierTokenLimit and DefaultPrivateTierWindow bound the private subscription
+	// created for a self-published model, so an unapproved model cannot take production capacity.
+	DefaultPrivateTierTokenLimit = 10000
+	DefaultPrivateTierWindow     = "1h"
+
+	// Namespace quotas, set by admins on a namespace and enforced by the maas-controller webhook.
+	// AnnotationMaxModels caps the MaaSModelRefs in the namespace; AnnotationMaxTokensPerMinute caps
+	// the throughput all subscriptions together may commit to its models.
+	AnnotationMaxModels          = "maas.opendatahub.io/max-models"
+	AnnotationMaxTokensPerMinute = "maas.opendatahub.io/max-tokens-per-minute"
+
+	// AnnotationResolutionPriority breaks ties when a bare model name matches MaaSModelRefs in
+	// several namespaces: the highest integer wins, and models without it count as 0.
+	AnnotationResolutionPriority = "maas.opendatahub.io/resolution-priority"
+
+	// Per-model allow-lists on a MaaSModelRef: comma-separated groups and users granted access in
+	// addition to the subjects of the MaaSAuthPolicies covering the model.
+	AnnotationAllowedGroups = "maas.opendatahub.io/allowed-groups"
+	AnnotationAllowedUsers  = "maas.opendatahub.io/allowed-users"
+
+	// AnnotationIncludes on a MaaSSubscription lists, comma-separated, the other MaaSSubscriptions
+	// in its namespace that its owners may also use, e.g. enterprise including premium. "*" includes
+	// every subscription in the namespace. Inclusion is transitive.
+	AnnotationIncludes = "maas.opendatahub.io/includes"
+
+	// AnnotationMeteringSampleRate on a MaaSModelRef meters only this fraction of its requests,
+	// e.g. "0.1", extrapolating the usage metrics. Unset or invalid values meter every request.
+	AnnotationMeteringSampleRate = "maas.opendatahub.io/metering-sample-rate"
+
+	// AnnotationSoftDeleted set to "true" on a MaaSModelRef unpublishes it from the catalog and
+	// denies requests to it, while maas-controller keeps its generated resources until the grace
+	// period passes. Removing the annotation restores the model.
+	AnnotationSoftDeleted = "maas.opendatahub.io/soft-deleted"
+
+	// AnnotationIgnore set to "true" on a MaaSModelRef or an LLMInferenceService puts it outside
+	// MaaS governance: maas-api treats the model as not found and maas-controller does not discover
+	// the service.
+	AnnotationIgnore = "maas.opendatahub.io/ignore"
+
+	// AnnotationMaintenance set to "true" on a MaaSModelRef puts it in maintenance: requests to it
+	// are denied with model_maintenance until the annotation is removed. maas-controller also puts
+	// models in maintenance during their spec.maintenanceWindows.
+	AnnotationMaintenance = "maas.opendatahub.io/maintenance"
+
+	// AnnotationQuotaExceededUntil is set by maas-api on a MaaSSubscription whose users have reached
+	// its spec.maxTokensPerDay, to the RFC 3339 time the cap resets. maas-controller reports it as
+	// the QuotaExceeded condition.
+	AnnotationQuotaExceededUntil = "maas.opendatahub.io/quota-exceeded-until"
+
+	// AnnotationOriginCluster is set by maas-api on the MaaSModelRefs it caches to the name of the
+	// cluster hosting the model, when it federates models from remote clusters.
+	AnnotationOriginCluster = "maas.opendatahub.io/origin-cluster"
 )
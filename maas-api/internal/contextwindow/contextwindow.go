@@ -0,0 +1,228 @@
+// Package contextwindow counts the prompt tokens of OpenAI-style request bodies and checks them
+// against a model's context window, so requests that cannot fit are denied before the model
+// server spends time on them.
+package contextwindow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+var tokenizerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_tokenizer_requests_total",
+	Help: "Prompt token counts requested from the tokenizer service, by result (success, error). Errors fall back to an estimate.",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(tokenizerRequests)
+}
+
+const (
+	// bytesPerToken is the average length of a token of English text, used to estimate prompts.
+	bytesPerToken = 4
+	// tokensPerMessage is the overhead a chat template adds around each message.
+	tokensPerMessage = 4
+)
+
+// textKeys are the fields whose strings are prompt text, wherever they are nested under messages,
+// prompt, input or instructions. Other strings, such as roles, types and attachment data, are not.
+var textKeys = []string{"content", "text", "prompt", "input", "instructions", "arguments", "output"}
+
+// Prompt is what a check reads from a request body.
+type Prompt struct {
+	// Model is the "model" field, if any.
+	Model string
+	// Text is the text of the messages, prompt, input and instructions, one part per line.
+	Text string
+	// Messages are the chat messages as sent, or nil.
+	Messages json.RawMessage
+	// MaxTokens is the completion length requested through max_tokens, max_completion_tokens or
+	// max_output_tokens, or 0.
+	MaxTokens int64
+}
+
+// Parse reads the prompt of a JSON request body.
+func Parse(body []byte) (*Prompt, error) {
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+	p := &Prompt{}
+	p.Model, _ = payload["model"].(string)
+	var parts []string
+	for _, key := range []string{"instructions", "messages", "prompt", "input"} {
+		collect(payload[key], key, &parts)
+	}
+	p.Text = strings.Join(parts, "\n")
+	if _, ok := payload["messages"].([]any); ok {
+		var raw struct {
+			Messages json.RawMessage `json:"messages"`
+		}
+		_ = json.Unmarshal(body, &raw)
+		p.Messages = raw.Messages
+	}
+	for _, key := range []string{"max_completion_tokens", "max_tokens", "max_output_tokens"} {
+		if n, ok := payload[key].(float64); ok && n > 0 {
+			p.MaxTokens = int64(n)
+			break
+		}
+	}
+	return p, nil
+}
+
+// collect appends the prompt text of v, found under key, to parts.
+func collect(v any, key string, parts *[]string) {
+	switch v := v.(type) {
+	case string:
+		if slices.Contains(textKeys, key) && v != "" {
+			*parts = append(*parts, v)
+		}
+	case map[string]any:
+		// Sorted, so the text is the same for every check of a body.
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			collect(v[k], k, parts)
+		}
+	case []any:
+		for _, child := range v {
+			collect(child, key, parts)
+		}
+	}
+}
+
+// messageCount returns the number of chat messages, or 0.
+func (p *Prompt) messageCount() int {
+	var messages []json.RawMessage
+	_ = json.Unmarshal(p.Messages, &messages)
+	return len(messages)
+}
+
+// Estimate approximates the prompt tokens from the length of the text and the number of messages.
+func (p *Prompt) Estimate() int64 {
+	return int64((len(p.Text)+bytesPerToken-1)/bytesPerToken + tokensPerMessage*p.messageCount())
+}
+
+// Tokenizer counts prompt tokens with a tokenizer service compatible with vLLM's POST /tokenize,
+// which takes {"model", "messages"} or {"model", "prompt"} and answers {"count": n}.
+type Tokenizer struct {
+	url    string
+	client *http.Client
+}
+
+// NewTokenizer creates a client of the tokenizer service at url.
+func NewTokenizer(url string, timeout time.Duration) *Tokenizer {
+	return &Tokenizer{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Count returns the prompt tokens of p for model. Chat messages are sent as they are, so the
+// service applies the model's chat template.
+func (t *Tokenizer) Count(ctx context.Context, model string, p *Prompt) (int64, error) {
+	req := map[string]any{"model": model}
+	if p.Messages != nil {
+		req["messages"] = p.Messages
+	} else {
+		req["prompt"] = p.Text
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return 0, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, fmt.Errorf("tokenizer returned %s", resp.Status)
+	}
+	var out struct {
+		Count *int64 `json:"count"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&out); err != nil {
+		return 0, fmt.Errorf("invalid tokenizer response: %w", err)
+	}
+	if out.Count == nil {
+		return 0, errors.New("invalid tokenizer response: no count")
+	}
+	return *out.Count, nil
+}
+
+// Violation is a request that does not fit its model's context window.
+type Violation struct {
+	ContextWindow int64
+	PromptTokens  int64
+	MaxTokens     int64
+	// Estimated is set when PromptTokens was estimated rather than counted by the tokenizer.
+	Estimated bool
+}
+
+// Message explains the violation in the words OpenAI uses for context_length_exceeded.
+func (v *Violation) Message() string {
+	return fmt.Sprintf("This model's maximum context length is %d tokens. However, you requested %d tokens (%d in the prompt, %d for the completion). Please reduce the length of the prompt or completion.",
+		v.ContextWindow, v.PromptTokens+v.MaxTokens, v.PromptTokens, v.MaxTokens)
+}
+
+// Checker checks requests against context windows.
+type Checker struct {
+	tokenizer *Tokenizer
+	logger    *logger.Logger
+}
+
+// NewChecker creates a checker that counts prompt tokens with tokenizer, or estimates them when
+// tokenizer is nil or fails.
+func NewChecker(log *logger.Logger, tokenizer *Tokenizer) *Checker {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Checker{tokenizer: tokenizer, logger: log}
+}
+
+// Check returns the violation of a request body for model ("namespace/name") with a context
+// window of window tokens, or nil when it fits. Bodies that are not JSON are left for the model
+// server to reject, and so is every body when window is not positive.
+func (c *Checker) Check(ctx context.Context, model string, window int64, body []byte) *Violation {
+	if window <= 0 || len(body) == 0 {
+		return nil
+	}
+	p, err := Parse(body)
+	if err != nil {
+		return nil
+	}
+	v := &Violation{ContextWindow: window, MaxTokens: p.MaxTokens, PromptTokens: p.Estimate(), Estimated: true}
+	if c.tokenizer != nil {
+		name := p.Model
+		if name == "" {
+			_, name, _ = strings.Cut(model, "/")
+		}
+		count, err := c.tokenizer.Count(ctx, name, p)
+		if err != nil {
+			tokenizerRequests.WithLabelValues("error").Inc()
+			c.logger.Warn("Tokenizer failed, estimating prompt tokens", "error", err, "model", model)
+		} else {
+			tokenizerRequests.WithLabelValues("success").Inc()
+			v.PromptTokens, v.Estimated = count, false
+		}
+	}
+	if v.PromptTokens+v.MaxTokens <= window {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+package contextwindow_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/contextwindow"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+func TestParse(t *testing.T) {
+	p, err := contextwindow.Parse([]byte(`{
+		"model": "granite",
+		"max_completion_tokens": 256,
+		"messages": [
+			{"role": "system", "content": "Be brief."},
+			{"role": "user", "content": [
+				{"type": "text", "text": "What is in this image?"},
+				{"type": "image_url", "image_url": {"url": "data:image/png;base64,AAAA"}}
+			]}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, "granite", p.Model)
+	assert.Equal(t, int64(256), p.MaxTokens)
+	assert.Equal(t, "Be brief.\nWhat is in this image?", p.Text, "roles, types and attachment data are not prompt text")
+	assert.NotNil(t, p.Messages)
+	assert.Equal(t, int64(16), p.Estimate(), "ceil(31/4) plus 4 per message")
+
+	p, err = contextwindow.Parse([]byte(`{"prompt": ["a", "b"], "max_tokens": 10}`))
+	require.NoError(t, err)
+	assert.Equal(t, "a\nb", p.Text)
+	assert.Nil(t, p.Messages)
+
+	p, err = contextwindow.Parse([]byte(`{"instructions": "Be brief.", "input": [{"role": "user", "content": [{"type": "input_text", "text": "Hi"}]}], "max_output_tokens": 5}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Be brief.\nHi", p.Text)
+	assert.Equal(t, int64(5), p.MaxTokens)
+
+	_, err = contextwindow.Parse([]byte("not json"))
+	require.Error(t, err)
+}
+
+func TestCheckWithTokenizer(t *testing.T) {
+	var got map[string]any
+	tokenizer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got["model"] == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"count": 90, "max_model_len": 100}`))
+	}))
+	defer tokenizer.Close()
+	checker := contextwindow.NewChecker(logger.Development(), contextwindow.NewTokenizer(tokenizer.URL, time.Second))
+	body := []byte(`{"messages": [{"role": "user", "content": "hello"}], "max_tokens": 20}`)
+
+	v := checker.Check(t.Context(), "llm/granite", 100, body)
+	require.NotNil(t, v)
+	assert.Equal(t, "granite", got["model"], "the model name without its namespace, when the body names none")
+	assert.NotNil(t, got["messages"], "chat messages are sent for the chat template to apply")
+	assert.Equal(t, int64(90), v.PromptTokens)
+	assert.False(t, v.Estimated)
+	assert.Contains(t, v.Message(), "maximum context length is 100 tokens")
+
+	assert.Nil(t, checker.Check(t.Context(), "llm/granite", 110, body))
+	assert.Nil(t, checker.Check(t.Context(), "llm/granite", 0, body), "models without a context window are not checked")
+
+	v = checker.Check(t.Context(), "llm/broken", 5, body)
+	require.NotNil(t, v, "a failing tokenizer falls back to the estimate")
+	assert.True(t, v.Estimated)
+	assert.Equal(t, int64(6), v.PromptTokens)
+}
+
+func TestCheckEstimate(t *testing.T) {
+	checker := contextwindow.NewChecker(logger.Development(), nil)
+	long := `{"prompt": "` + strings.Repeat("x", 400) + `"}`
+	assert.Nil(t, checker.Check(t.Context(), "llm/granite", 100, []byte(long)))
+	v := checker.Check(t.Context(), "llm/granite", 99, []byte(long))
+	require.NotNil(t, v)
+	assert.Equal(t, int64(100), v.PromptTokens)
+	assert.True(t, v.Estimated)
+	assert.Nil(t, checker.Check(t.Context(), "llm/granite", 1, []byte("not json")), "malformed bodies are left to the model server")
+}
@@ -0,0 +1,181 @@
+// Package dependency checks the services inference requests pass through besides maas-api — the
+// gateway, Authorino and Limitador — so readiness can report a broken policy chain while
+// maas-api itself is healthy.
+package dependency
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Names of the checks.
+const (
+	Gateway   = "gateway"
+	Authorino = "authorino"
+	Limitador = "limitador"
+)
+
+const (
+	// Timeout bounds a single check.
+	Timeout = 2 * time.Second
+	// CacheTTL is how long results are reused, so frequent readiness probes do not each call the
+	// dependencies.
+	CacheTTL = 10 * time.Second
+)
+
+// Status is the outcome of one check.
+type Status struct {
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// Check probes a dependency and returns nil when it is healthy.
+type Check func(ctx context.Context) error
+
+// Checker runs a set of checks concurrently and caches their results for CacheTTL.
+type Checker struct {
+	checks map[string]Check
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	last      map[string]Status
+}
+
+// NewChecker creates a Checker for checks, keyed by name. It returns nil when checks is empty.
+func NewChecker(checks map[string]Check) *Checker {
+	if len(checks) == 0 {
+		return nil
+	}
+	return &Checker{checks: checks}
+}
+
+// Check returns the status of every check.
+func (c *Checker) Check(ctx context.Context) map[string]Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last != nil && time.Since(c.checkedAt) < CacheTTL {
+		return c.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	statuses := make(map[string]Status, len(c.checks))
+	var (
+		wg       sync.WaitGroup
+		resultMu sync.Mutex
+	)
+	for name, check := range c.checks {
+		wg.Go(func() {
+			status := Status{Healthy: true}
+			if err := check(ctx); err != nil {
+				status = Status{Message: err.Error()}
+			}
+			resultMu.Lock()
+			statuses[name] = status
+			resultMu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	c.last, c.checkedAt = statuses, time.Now()
+	return statuses
+}
+
+// GatewayCheck reports whether the gateway at url answers. Any HTTP response counts, as the
+// gateway denies or rejects an unauthenticated request to its root by design.
+func GatewayCheck(url string) Check {
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // reachability only, nothing is sent
+	}}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("invalid gateway URL: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("gateway unreachable: %w", err)
+		}
+		resp.Body.Close()
+		return nil
+	}
+}
+
+// LimitadorCheck reports whether the Limitador HTTP API at baseURL answers its status endpoint.
+func LimitadorCheck(baseURL string) Check {
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/status"
+	client := &http.Client{}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("invalid limitador URL: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("limitador unreachable: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("limitador returned status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// AuthConfigGVR is the Authorino AuthConfig resource Kuadrant translates AuthPolicies into.
+var AuthConfigGVR = schema.GroupVersionResource{Group: "authorino.kuadrant.io", Version: "v1beta3", Resource: "authconfigs"}
+
+// AuthorinoCheck reports whether Authorino accepted the AuthConfigs in namespace: there must be
+// at least one, and each must be Ready. A policy chain without them lets the gateway's default
+// deny answer every request.
+func AuthorinoCheck(client dynamic.Interface, namespace string) Check {
+	return func(ctx context.Context) error {
+		list, err := client.Resource(AuthConfigGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list AuthConfigs: %w", err)
+		}
+		if len(list.Items) == 0 {
+			return fmt.Errorf("no AuthConfigs in namespace %s", namespace)
+		}
+		var notReady []string
+		for _, item := range list.Items {
+			if err := authConfigReady(item); err != nil {
+				notReady = append(notReady, item.GetName()+": "+err.Error())
+			}
+		}
+		if len(notReady) > 0 {
+			return fmt.Errorf("%d of %d AuthConfigs not ready (%s)", len(notReady), len(list.Items), strings.Join(notReady, "; "))
+		}
+		return nil
+	}
+}
+
+func authConfigReady(item unstructured.Unstructured) error {
+	conditions, _, _ := unstructured.NestedSlice(item.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		if cond["status"] == string(metav1.ConditionTrue) {
+			return nil
+		}
+		if msg, _ := cond["message"].(string); msg != "" {
+			return errors.New(msg)
+		}
+		reason, _ := cond["reason"].(string)
+		return fmt.Errorf("not ready: %s", reason)
+	}
+	return errors.New("not reconciled by Authorino")
+}
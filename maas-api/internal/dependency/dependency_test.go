@@ -0,0 +1,119 @@
+package dependency_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/dependency"
+)
+
+func TestChecker(t *testing.T) {
+	calls := 0
+	checker := dependency.NewChecker(map[string]dependency.Check{
+		dependency.Gateway: func(context.Context) error { calls++; return nil },
+		dependency.Limitador: func(context.Context) error {
+			return errors.New("limitador unreachable")
+		},
+	})
+
+	want := map[string]dependency.Status{
+		dependency.Gateway:   {Healthy: true},
+		dependency.Limitador: {Message: "limitador unreachable"},
+	}
+	assert.Equal(t, want, checker.Check(t.Context()))
+	assert.Equal(t, want, checker.Check(t.Context()))
+	assert.Equal(t, 1, calls, "results are reused within the cache TTL")
+
+	assert.Nil(t, dependency.NewChecker(nil), "no checks, no checker")
+}
+
+func TestGatewayCheck(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer gateway.Close()
+	require.NoError(t, dependency.GatewayCheck(gateway.URL)(t.Context()), "a denial still shows the gateway is reachable")
+
+	gateway.Close()
+	assert.ErrorContains(t, dependency.GatewayCheck(gateway.URL)(t.Context()), "gateway unreachable")
+}
+
+func TestLimitadorCheck(t *testing.T) {
+	status := http.StatusOK
+	limitador := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer limitador.Close()
+	check := dependency.LimitadorCheck(limitador.URL + "/")
+
+	require.NoError(t, check(t.Context()))
+	status = http.StatusServiceUnavailable
+	assert.ErrorContains(t, check(t.Context()), "status 503")
+}
+
+func authConfig(name, readyStatus, message string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "authorino.kuadrant.io/v1beta3",
+		"kind":       "AuthConfig",
+		"metadata":   map[string]any{"name": name, "namespace": "kuadrant-system"},
+	}}
+	if readyStatus != "" {
+		obj.Object["status"] = map[string]any{"conditions": []any{
+			map[string]any{"type": "Available", "status": "True"},
+			map[string]any{"type": "Ready", "status": readyStatus, "reason": "Unknown", "message": message},
+		}}
+	}
+	return obj
+}
+
+func TestAuthorinoCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		authConfigs []runtime.Object
+		wantErr     string
+	}{
+		{
+			name:        "all_ready",
+			authConfigs: []runtime.Object{authConfig("a", "True", ""), authConfig("b", "True", "")},
+		},
+		{
+			name:    "none",
+			wantErr: "no AuthConfigs in namespace kuadrant-system",
+		},
+		{
+			name:        "not_ready",
+			authConfigs: []runtime.Object{authConfig("a", "True", ""), authConfig("b", "False", "invalid OPA policy")},
+			wantErr:     "1 of 2 AuthConfigs not ready (b: invalid OPA policy)",
+		},
+		{
+			name:        "not_reconciled",
+			authConfigs: []runtime.Object{authConfig("a", "", "")},
+			wantErr:     "a: not reconciled by Authorino",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				dependency.AuthConfigGVR: "AuthConfigList",
+			}, tt.authConfigs...)
+
+			err := dependency.AuthorinoCheck(client, "kuadrant-system")(t.Context())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package extauthz
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// AccessResponse is the body of GET /v1/models/{name}/access.
+type AccessResponse struct {
+	Object string `json:"object"`
+	Decision
+}
+
+// CheckAccess handles GET /v1/models/{name}/access: whether the calling user may reach the model
+// and, if not, why. It is a dry run of the checks ext_authz makes for a request with a valid API
+// key of the caller, for portals to show before the first inference call. The model is looked up
+// in the namespace query parameter, or resolved from its name as for a bare model name; tier
+// selects the subscription to check, and verbosity lowers how much a denial explains. Nothing is
+// metered, audited or charged.
+func (h *Handler) CheckAccess(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	userContext, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	verbosity, ok := h.requestedVerbosity(c)
+	if !ok {
+		return
+	}
+
+	model := c.Param("name")
+	if namespace := c.Query("namespace"); namespace != "" {
+		model = namespace + "/" + model
+	}
+	decision := h.explain(h.server.CheckAccess(c.Request.Context(), userContext.Username, userContext.Groups, model, requestedTier(c, c.Query("tier"))), verbosity)
+	h.logger.Debug("Access check", "username", userContext.Username, "model", model, "allowed", decision.Allowed, "reason", decision.Reason)
+	c.JSON(http.StatusOK, AccessResponse{Object: "model.access", Decision: decision})
+}
@@ -0,0 +1,77 @@
+package extauthz_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/hooks"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func getAccess(t *testing.T, s *extauthz.Server, query string) extauthz.AccessResponse {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/models/:name/access", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"premium-users"}})
+	}, extauthz.NewHandler(logger.Development(), s).CheckAccess)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/models/"+query, nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp extauthz.AccessResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "model.access", resp.Object)
+	return resp
+}
+
+func TestCheckAccess(t *testing.T) {
+	var seen []hooks.Input
+	s := newServer()
+	s.SetHooks(hooks.NewChain(logger.Development(),
+		&hooks.Hook{Name: "audit", Phase: hooks.PhasePre, Extension: hookFunc(func(in hooks.Input) hooks.Output {
+			seen = append(seen, in)
+			return hooks.Output{}
+		})},
+	))
+
+	allowed := getAccess(t, s, "granite/access?namespace=llm")
+	assert.True(t, allowed.Allowed)
+	assert.Equal(t, "llm/granite", allowed.Model)
+	assert.Equal(t, "premium", allowed.Subscription)
+	assert.Empty(t, allowed.Path)
+
+	require.Len(t, seen, 1)
+	assert.Equal(t, "access", seen[0].Endpoint)
+	assert.True(t, seen[0].DryRun, "hooks are told that no request is authorized")
+
+	denied := getAccess(t, s, "llama/access?namespace=llm")
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, "unauthorized", denied.Reason)
+
+	wrongTier := getAccess(t, s, "granite/access?namespace=llm&tier=free")
+	assert.False(t, wrongTier.Allowed)
+	assert.Equal(t, "free", wrongTier.Tier)
+	assert.Equal(t, "not_found", wrongTier.Reason)
+
+	unknown := getAccess(t, s, "granite/access")
+	assert.False(t, unknown.Allowed)
+	assert.Equal(t, "model_not_found", unknown.Reason, "bare names need a model resolver")
+}
+
+func TestCheckAccessTokenBudget(t *testing.T) {
+	s := newServer()
+	s.SetBudgetChecker(spentBudget("models-as-a-service/premium@llm/granite"))
+	decision := s.CheckAccess(context.Background(), "alice", []string{"premium-users"}, "llm/granite", "")
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "quota_exhausted", decision.Reason)
+}
@@ -0,0 +1,281 @@
+package extauthz
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/hooks"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/identity"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+// MaxBatchSize is the most entries one batch authorization call may check.
+const MaxBatchSize = 500
+
+// BatchEntry is one request to authorize: the gateway path of a model and, optionally, the
+// subscription (tier) the caller would use.
+type BatchEntry struct {
+	Path string `json:"path"`
+	Tier string `json:"tier,omitempty"`
+}
+
+// BatchRequest is the body of POST /v1/models/authorize/batch.
+type BatchRequest struct {
+	Requests []BatchEntry `json:"requests"`
+}
+
+// Decision is whether the caller may reach a model, as ext_authz would decide a request to it
+// with a valid API key of the caller.
+type Decision struct {
+	Path    string `json:"path,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Allowed bool   `json:"allowed"`
+	// Model is the resolved model as namespace/name; empty when the path names none.
+	Model string `json:"model,omitempty"`
+	// Subscription is the subscription the request would be metered against.
+	Subscription string `json:"subscription,omitempty"`
+	// Reason is the ext_authz denial reason (x-ext-auth-reason), e.g. "unauthorized".
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// AllowedTiers lists the subscriptions that include the model, for denials at detailed
+	// verbosity only.
+	AllowedTiers []string `json:"allowedTiers,omitempty"`
+}
+
+// BatchResponse lists the decisions in request order.
+type BatchResponse struct {
+	Object string     `json:"object"`
+	Data   []Decision `json:"data"`
+}
+
+// Authorize decides one entry for a user without an API key: the model is taken from the path as
+// the path model source does, then checked against MaaSAuthPolicies and subscription selection.
+func (s *Server) Authorize(ctx context.Context, username string, groups []string, entry BatchEntry) Decision {
+	decision := Decision{Path: entry.Path, Tier: entry.Tier}
+	ref := s.modelFromPath(entry.Path)
+	if ref == "" {
+		decision.Reason, decision.Message = reason.ModelNotFound, "request does not target a MaaS model"
+		return decision
+	}
+	return s.decide(ctx, "batch", username, groups, ref, decision)
+}
+
+// CheckAccess decides whether a user may reach model, as namespace/name or a bare name, through
+// tier (empty to let selection pick one), as Authorize does for a path.
+func (s *Server) CheckAccess(ctx context.Context, username string, groups []string, model, tier string) Decision {
+	return s.decide(ctx, "access", username, groups, model, Decision{Tier: tier})
+}
+
+// decide runs the checks of ext_authz that do not need an API key for ref, filling in decision.
+// Nothing is metered, audited or charged: the decision authorizes no request.
+func (s *Server) decide(ctx context.Context, endpoint, username string, groups []string, ref string, decision Decision) Decision {
+	modelNS, modelName, ok := splitModelRef(ref)
+	if !ok {
+		decision.Reason, decision.Message = reason.ModelNotFound, "request does not target a MaaS model"
+		return decision
+	}
+	modelNS, modelName = s.resolveAlias(modelNS, modelName)
+	if modelNS == "" {
+		var reason, message string
+		if modelNS, reason, message = s.resolveNamespace(modelName); reason != "" {
+			decision.Reason, decision.Message = reason, message
+			return decision
+		}
+	}
+	decision.Model = modelNS + "/" + modelName
+
+	hookInput := hooks.Input{
+		Phase:        hooks.PhasePre,
+		Endpoint:     endpoint,
+		DryRun:       true,
+		Model:        decision.Model,
+		User:         username,
+		Groups:       groups,
+		Subscription: decision.Tier,
+		Path:         decision.Path,
+		RequestID:    tracing.RequestID(ctx),
+	}
+	if pre := s.hooks.Run(ctx, hookInput); pre.Denied() {
+		decision.Reason, decision.Message = pre.Reason, pre.Message
+		return decision
+	}
+
+	allowed, err := s.allows(ctx, decision.Model, username, groups)
+	if err != nil {
+		s.logger.Error("Failed to list MaaSAuthPolicies", "error", err)
+		decision.Reason, decision.Message = reason.InternalError, "authorization failed"
+		return decision
+	}
+	if !allowed {
+		decision.Reason, decision.Message = reason.Unauthorized, "Access denied"
+		return decision
+	}
+
+	sub, err := s.selectSubscription(ctx, groups, username, decision.Tier, decision.Model, "")
+	if err != nil {
+		decision.Reason, decision.Message = subscription.ErrorCode(err), err.Error()
+		if decision.Reason == reason.InternalError {
+			s.logger.Error("Subscription selection failed", "error", err, "username", username)
+		}
+		return decision
+	}
+	decision.Subscription = sub.Name
+	if v := s.policy.Evaluate(policy.Input{
+		Model: decision.Model, User: username, Groups: groups, Tier: sub.Name, Path: decision.Path, Time: time.Now(),
+	}); v.Denied() {
+		decision.Reason, decision.Message = v.Reason, v.Message
+		return decision
+	}
+	if s.budgets != nil {
+		subscriptionKey := sub.Namespace + "/" + sub.Name + "@" + decision.Model
+		if message, _ := s.budgets.BudgetExhausted(ctx, username, subscriptionKey); message != "" {
+			decision.Reason, decision.Message = reason.QuotaExhausted, message
+			return decision
+		}
+	}
+	hookInput.Phase, hookInput.Subscription = hooks.PhasePost, sub.Name
+	if post := s.hooks.Run(ctx, hookInput); post.Denied() {
+		decision.Reason, decision.Message = post.Reason, post.Message
+		return decision
+	}
+	decision.Allowed = true
+	return decision
+}
+
+// TierLister lists the subscriptions (namespace/name) that include a model. It is implemented by
+// subscription.Selector.
+type TierLister interface {
+	TiersForModel(model string) ([]string, error)
+}
+
+// Handler serves batch authorization over HTTP.
+type Handler struct {
+	server    *Server
+	tiers     TierLister
+	verbosity reason.Verbosity
+	auditor   *audit.Auditor
+	logger    *logger.Logger
+}
+
+// NewHandler creates a handler for POST /v1/models/authorize/batch. Denials are explained at
+// minimal verbosity until SetReasonVerbosity raises it.
+func NewHandler(log *logger.Logger, server *Server) *Handler {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Handler{server: server, verbosity: reason.VerbosityMinimal, logger: log}
+}
+
+// SetReasonVerbosity sets how much denials tell the caller. Callers can lower it per call with
+// the verbosity query parameter, but never raise it. At detailed verbosity, denials list the
+// tiers from tiers.
+func (h *Handler) SetReasonVerbosity(v reason.Verbosity, tiers TierLister) {
+	h.verbosity = v
+	h.tiers = tiers
+}
+
+// SetAuditor records each signed URL minted by MintSignedURL in the audit log.
+func (h *Handler) SetAuditor(a *audit.Auditor) {
+	h.auditor = a
+}
+
+// AuthorizeBatch handles POST /v1/models/authorize/batch. It decides every entry for the calling
+// user in one round trip, so a portal can show which models a user can reach without a call per
+// model. Entries with the same path and tier are decided once.
+func (h *Handler) AuthorizeBatch(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	userContext, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+
+	var req BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "invalid request body: "+err.Error())
+		return
+	}
+	verbosity, ok := h.requestedVerbosity(c)
+	if !ok {
+		return
+	}
+	if len(req.Requests) > MaxBatchSize {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, fmt.Sprintf("at most %d requests can be authorized at once, got %d", MaxBatchSize, len(req.Requests)))
+		return
+	}
+
+	decided := make(map[BatchEntry]Decision, len(req.Requests))
+	data := make([]Decision, len(req.Requests))
+	for i, entry := range req.Requests {
+		entry.Tier = requestedTier(c, entry.Tier)
+		decision, ok := decided[entry]
+		if !ok {
+			decision = h.explain(h.server.Authorize(c.Request.Context(), userContext.Username, userContext.Groups, entry), verbosity)
+			decided[entry] = decision
+		}
+		data[i] = decision
+	}
+	h.logger.Debug("Batch authorization", "username", userContext.Username, "requests", len(data), "distinct", len(decided))
+	c.JSON(http.StatusOK, BatchResponse{Object: "list", Data: data})
+}
+
+// requestedTier returns the tier a call asks for, or the tier the caller's verified JWT binds
+// them to (see identity.Verifier), which overrides it.
+func requestedTier(c *gin.Context, requested string) string {
+	if id, ok := identity.FromContext(c); ok && id.Tier != "" {
+		return id.Tier
+	}
+	return requested
+}
+
+// requestedVerbosity returns the verbosity for a call: the handler's, or minimal when the
+// verbosity query parameter lowers it. It answers 400 and returns false for an invalid value.
+func (h *Handler) requestedVerbosity(c *gin.Context) (reason.Verbosity, bool) {
+	requested := c.Query("verbosity")
+	if requested == "" {
+		return h.verbosity, true
+	}
+	v, ok := reason.ParseVerbosity(requested)
+	if !ok {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "verbosity must be minimal or detailed")
+		return "", false
+	}
+	if v == reason.VerbosityMinimal {
+		return v, true
+	}
+	return h.verbosity, true
+}
+
+// explain sets the denial message for verbosity: the reason's fixed message at minimal verbosity,
+// and the failed check's own message plus the tiers that include the model at detailed verbosity.
+func (h *Handler) explain(decision Decision, verbosity reason.Verbosity) Decision {
+	if decision.Allowed {
+		return decision
+	}
+	if verbosity != reason.VerbosityDetailed {
+		decision.Message = reason.Message(decision.Reason)
+		return decision
+	}
+	if h.tiers == nil || decision.Model == "" || decision.Reason == reason.InternalError {
+		return decision
+	}
+	tiers, err := h.tiers.TiersForModel(decision.Model)
+	if err != nil {
+		h.logger.Warn("Failed to list tiers for denial details", "error", err, "model", decision.Model)
+		return decision
+	}
+	decision.AllowedTiers = tiers
+	return decision
+}
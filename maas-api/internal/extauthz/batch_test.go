@@ -0,0 +1,153 @@
+package extauthz_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func postBatch(t *testing.T, s *extauthz.Server, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/models/authorize/batch", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"premium-users"}})
+	}, extauthz.NewHandler(logger.Development(), s).AuthorizeBatch)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/models/authorize/batch", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthorizeBatch(t *testing.T) {
+	w := postBatch(t, newServer(), `{"requests": [
+		{"path": "/llm/granite/v1/chat/completions"},
+		{"path": "/llm/llama/v1/chat/completions"},
+		{"path": "/llm/granite/v1/chat/completions", "tier": "free"},
+		{"path": "/healthz"},
+		{"path": "/llm/granite/v1/chat/completions"}
+	]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp extauthz.BatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 5)
+
+	assert.True(t, resp.Data[0].Allowed)
+	assert.Equal(t, "llm/granite", resp.Data[0].Model)
+	assert.Equal(t, "premium", resp.Data[0].Subscription)
+
+	assert.False(t, resp.Data[1].Allowed)
+	assert.Equal(t, "llm/llama", resp.Data[1].Model)
+	assert.Equal(t, "unauthorized", resp.Data[1].Reason)
+
+	assert.False(t, resp.Data[2].Allowed)
+	assert.Equal(t, "free", resp.Data[2].Tier)
+	assert.Equal(t, "not_found", resp.Data[2].Reason)
+
+	assert.False(t, resp.Data[3].Allowed)
+	assert.Equal(t, "model_not_found", resp.Data[3].Reason)
+
+	assert.Equal(t, resp.Data[0], resp.Data[4])
+}
+
+func TestAuthorizeBatchTokenBudget(t *testing.T) {
+	s := newServer()
+	s.SetBudgetChecker(spentBudget("models-as-a-service/premium@llm/granite"))
+	w := postBatch(t, s, `{"requests": [{"path": "/llm/granite/v1/chat/completions"}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp extauthz.BatchResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.False(t, resp.Data[0].Allowed)
+	assert.Equal(t, "premium", resp.Data[0].Subscription)
+	assert.Equal(t, "quota_exhausted", resp.Data[0].Reason)
+}
+
+func TestAuthorizeBatchVerbosity(t *testing.T) {
+	body := `{"requests": [
+		{"path": "/llm/granite/v1/chat/completions", "tier": "free"},
+		{"path": "/llm/granite/v1/chat/completions"}
+	]}`
+	decide := func(t *testing.T, verbosity reason.Verbosity, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		server := newServer()
+		handler := extauthz.NewHandler(logger.Development(), server)
+		if verbosity != "" {
+			handler.SetReasonVerbosity(verbosity, subscription.NewSelector(logger.Development(), staticLister{premiumSubscription()}))
+		}
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.POST("/v1/models/authorize/batch", func(c *gin.Context) {
+			c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"premium-users"}})
+		}, handler.AuthorizeBatch)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/models/authorize/batch"+query, bytes.NewBufferString(body))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decisions := func(t *testing.T, w *httptest.ResponseRecorder) []extauthz.Decision {
+		t.Helper()
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp extauthz.BatchResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 2)
+		return resp.Data
+	}
+
+	t.Run("minimal by default", func(t *testing.T) {
+		data := decisions(t, decide(t, "", "?verbosity=detailed"))
+		assert.Equal(t, "not_found", data[0].Reason)
+		assert.Equal(t, reason.Message(reason.NotFound), data[0].Message)
+		assert.Empty(t, data[0].AllowedTiers, "callers cannot raise the verbosity")
+		assert.True(t, data[1].Allowed)
+		assert.Empty(t, data[1].Message)
+	})
+
+	t.Run("detailed", func(t *testing.T) {
+		data := decisions(t, decide(t, reason.VerbosityDetailed, ""))
+		assert.Equal(t, "not_found", data[0].Reason)
+		assert.Equal(t, "requested subscription not found", data[0].Message)
+		assert.Equal(t, []string{"models-as-a-service/premium"}, data[0].AllowedTiers)
+		assert.Empty(t, data[1].AllowedTiers)
+	})
+
+	t.Run("lowered by the caller", func(t *testing.T) {
+		data := decisions(t, decide(t, reason.VerbosityDetailed, "?verbosity=minimal"))
+		assert.Equal(t, reason.Message(reason.NotFound), data[0].Message)
+		assert.Empty(t, data[0].AllowedTiers)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, decide(t, reason.VerbosityDetailed, "?verbosity=all").Code)
+	})
+}
+
+func TestAuthorizeBatchRejectsOversizedBatch(t *testing.T) {
+	entries := make([]string, extauthz.MaxBatchSize+1)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"path": "/llm/model-%d/v1/chat/completions"}`, i)
+	}
+	w := postBatch(t, newServer(), `{"requests": [`+strings.Join(entries, ",")+`]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postBatch(t, newServer(), `{"requests": "all"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,1072 @@
+// Package extauthz implements Envoy's external authorization gRPC contract (the same
+// envoy.service.auth.v3.Authorization service Authorino serves), so a gateway can ask maas-api
+// for the whole inference decision in one call instead of going through Authorino's metadata
+// callbacks. The decision mirrors the AuthPolicy maas-controller generates for each model:
+// API key validation, MaaSAuthPolicy membership, subscription selection, and the same headers
+// and identity metadata on success.
+package extauthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/attachments"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/authpolicy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/clientip"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/concurrency"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/contextwindow"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/hooks"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signing"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/throttle"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+// ModelContextExtension is the per-route context extension that names the model being called,
+// either "namespace/name" or a bare name resolved with the ModelResolver. When absent, the first
+// two path segments are used, matching the /<namespace>/<name>/... routes LLMInferenceServices
+// publish on the gateway.
+const ModelContextExtension = "maas-model"
+
+// ModelHeader names the model for gateways that route every model through one path.
+const ModelHeader = "x-maas-model"
+
+// Where the model of a request is taken from when the route has no ModelContextExtension.
+const (
+	// ModelSourcePath uses the first two path segments, /<namespace>/<name>/..., or the custom
+	// path prefix of a model (spec.routing.pathPrefix) when they do not name a model.
+	ModelSourcePath = "path"
+	// ModelSourceHost uses the Host header, for models with a dedicated hostname
+	// (spec.routing.hostname).
+	ModelSourceHost = "host"
+	// ModelSourceHeader uses the X-MaaS-Model header.
+	ModelSourceHeader = "header"
+	// ModelSourceBody uses the "model" field of the JSON request body, as sent in OpenAI-style
+	// requests. Envoy only forwards the body when the ext_authz filter sets with_request_body.
+	ModelSourceBody = "body"
+)
+
+// ParseModelSources parses a comma-separated list of model sources, tried in order.
+// An empty list means path only.
+func ParseModelSources(list string) ([]string, error) {
+	var sources []string
+	for source := range strings.SplitSeq(list, ",") {
+		source = strings.TrimSpace(source)
+		if source == "" {
+			continue
+		}
+		if source != ModelSourcePath && source != ModelSourceHost && source != ModelSourceHeader && source != ModelSourceBody {
+			return nil, fmt.Errorf("invalid model source %q: must be path, host, header or body", source)
+		}
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	if len(sources) == 0 {
+		sources = []string{ModelSourcePath}
+	}
+	return sources, nil
+}
+
+// ParseAPISuffixes parses a comma-separated list of API suffixes, each starting with "/". "*"
+// allows every path, as does an empty list.
+func ParseAPISuffixes(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "*" {
+		return nil, nil
+	}
+	var suffixes []string
+	for suffix := range strings.SplitSeq(list, ",") {
+		suffix = strings.TrimSpace(suffix)
+		if suffix == "" {
+			continue
+		}
+		if !strings.HasPrefix(suffix, "/") {
+			return nil, fmt.Errorf("invalid API suffix %q: must start with /", suffix)
+		}
+		if !slices.Contains(suffixes, suffix) {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	return suffixes, nil
+}
+
+// AllowListResolver returns the groups and users a model ("namespace/name") grants access to
+// through its own allow-list annotations.
+type AllowListResolver func(model string) (groups, users []string)
+
+// ContextWindowResolver returns the context window of a model ("namespace/name") in tokens, or 0
+// when it declares none.
+type ContextWindowResolver func(model string) int64
+
+// ErrorResponseResolver returns the custom denial bodies of a model ("namespace/name"), or nil.
+type ErrorResponseResolver func(model string) *models.ErrorResponses
+
+// ModelResolver resolves a bare model name to the namespace of its MaaSModelRef. It returns
+// models.ErrModelNotFound or a *models.AmbiguousModelError when there is no single match.
+type ModelResolver func(name string) (string, error)
+
+// AliasResolver resolves a model named by one of its aliases, with or without a namespace, to the
+// namespace and name of its MaaSModelRef. It is implemented by models.AliasResolver.
+type AliasResolver func(namespace, name string) (string, string, bool)
+
+// RouteResolver resolves the model ("namespace/name") of a request from the custom routes of
+// MaaSModelRefs. It is implemented by models.RouteResolver.
+type RouteResolver interface {
+	ByHost(host, path string) (string, bool)
+	ByPath(path string) (string, bool)
+}
+
+// KeyValidator validates API keys.
+type KeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*api_keys.ValidationResult, error)
+}
+
+// SubscriptionSelector resolves the subscription a request is metered against. An empty host
+// skips the check of the subscriptions' gateway listeners.
+type SubscriptionSelector interface {
+	SelectForHost(groups []string, username string, requestedSubscription string, requestedModel string, host string) (*subscription.SelectResponse, error)
+}
+
+// RateLimiter enforces the request and token rate limits of subscriptions in maas-api itself
+// (RATE_LIMIT_BACKEND=redis). It is implemented by quota.RateLimiter.
+type RateLimiter interface {
+	Admit(ctx context.Context, username, subscriptionKey string) (time.Duration, error)
+}
+
+// ConcurrencyLimiter caps the requests a user may have in progress under a model-scoped
+// subscription key, and those of the subscription's organization. It is implemented by
+// concurrency.Limiter.
+type ConcurrencyLimiter interface {
+	Acquire(ctx context.Context, username, subscriptionKey, requestID string) (*concurrency.Denial, error)
+}
+
+// Server answers ext_authz Check calls for model inference routes.
+type Server struct {
+	authv3.UnimplementedAuthorizationServer
+
+	keys        KeyValidator
+	selector    SubscriptionSelector
+	policies    authpolicy.Lister
+	quotaWarner subscription.QuotaWarner
+	tokens      subscription.TokenReporter
+	budgets     subscription.BudgetChecker
+	rateLimits  RateLimiter
+	concurrency ConcurrencyLimiter
+	lineage     subscription.LineageResolver
+	resolve     ModelResolver
+	aliases     AliasResolver
+	routes      RouteResolver
+	allowList   AllowListResolver
+	errorBodies ErrorResponseResolver
+	windows     ContextWindowResolver
+	contexts    *contextwindow.Checker
+	throttle    *throttle.Throttler
+	clientIPs   *clientip.Resolver
+	meter       *metering.Meter
+	auditor     *audit.Auditor
+	signatures  *signing.Verifier
+	signedURLs  *signedurl.Signer
+	hooks       *hooks.Chain
+	policy      *policy.Engine
+	sources     []string
+	suffixes    []string
+	logger      *logger.Logger
+}
+
+// NewServer creates an ext_authz server.
+func NewServer(log *logger.Logger, keys KeyValidator, selector SubscriptionSelector, policies authpolicy.Lister) *Server {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Server{keys: keys, selector: selector, policies: policies, sources: []string{ModelSourcePath}, logger: log}
+}
+
+// SetModelSources sets where the model is taken from on routes without a ModelContextExtension,
+// trying each source in order. Header and body sources let a gateway send every model through a
+// single /v1/chat/completions route.
+func (s *Server) SetModelSources(sources []string) {
+	if len(sources) > 0 {
+		s.sources = sources
+	}
+}
+
+// SetAPISuffixes denies requests whose path does not end in one of suffixes with 404 and reason
+// unknown_endpoint, and lets subscriptions restrict their requests to some of them with
+// spec.endpoints. Without suffixes every path is allowed.
+func (s *Server) SetAPISuffixes(suffixes []string) {
+	s.suffixes = suffixes
+}
+
+// SetQuotaWarner enables the X-MaaS-Quota-Warning header on allowed requests.
+func (s *Server) SetQuotaWarner(w subscription.QuotaWarner) {
+	s.quotaWarner = w
+}
+
+// SetTokenReporter enables the X-MaaS-Remaining-Tokens response header on allowed requests.
+func (s *Server) SetTokenReporter(r subscription.TokenReporter) {
+	s.tokens = r
+}
+
+// SetBudgetChecker denies requests with 429 and reason quota_exhausted once the caller has
+// consumed the token budget of the model under the selected subscription.
+func (s *Server) SetBudgetChecker(b subscription.BudgetChecker) {
+	s.budgets = b
+}
+
+// SetRateLimiter denies requests with 429 and reason rate_limited once the caller has used up a
+// request or token rate limit of the selected subscription, for deployments that enforce rate
+// limits in maas-api rather than at the gateway.
+func (s *Server) SetRateLimiter(l RateLimiter) {
+	s.rateLimits = l
+}
+
+// SetConcurrencyLimiter denies requests with 429 and reason too_many_concurrent_requests once the
+// caller has as many requests in progress on the model as the subscription allows. Each admitted
+// request holds a lease under its x-request-id until the gateway reports that it ended.
+func (s *Server) SetConcurrencyLimiter(l ConcurrencyLimiter) {
+	s.concurrency = l
+}
+
+// SetLineageResolver lets a variant (fine-tune) without MaaSAuthPolicies of its own use its
+// nearest ancestor's, as the AuthPolicy maas-controller generates for it does.
+func (s *Server) SetLineageResolver(resolve subscription.LineageResolver) {
+	s.lineage = resolve
+}
+
+// SetModelResolver lets routes name their model without a namespace. Without a resolver such
+// requests are denied with model_not_found.
+func (s *Server) SetModelResolver(resolve ModelResolver) {
+	s.resolve = resolve
+}
+
+// SetAliasResolver lets requests name models by their aliases, which are resolved before the
+// model's policies and subscriptions are looked up.
+func (s *Server) SetAliasResolver(aliases AliasResolver) {
+	s.aliases = aliases
+}
+
+// SetRouteResolver lets the path and host model sources resolve models by their custom path
+// prefix or dedicated hostname. Without it, the path source only reads /<namespace>/<name>/...
+// and the host source matches nothing.
+func (s *Server) SetRouteResolver(routes RouteResolver) {
+	s.routes = routes
+}
+
+// SetAllowListResolver adds each model's allow-list annotations to the subjects of the
+// MaaSAuthPolicies covering it, as the AuthPolicy maas-controller generates does.
+func (s *Server) SetAllowListResolver(resolve AllowListResolver) {
+	s.allowList = resolve
+}
+
+// SetErrorResponseResolver replaces the body of access and not-found denials with the model's
+// spec.errorResponses, as the AuthPolicy maas-controller generates does.
+func (s *Server) SetErrorResponseResolver(resolve ErrorResponseResolver) {
+	s.errorBodies = resolve
+}
+
+// SetContextWindowChecker denies requests whose prompt, plus the completion tokens they ask for,
+// exceeds the context window resolve returns for their model. Only bodies the gateway forwards
+// are checked.
+func (s *Server) SetContextWindowChecker(checker *contextwindow.Checker, resolve ContextWindowResolver) {
+	s.contexts = checker
+	s.windows = resolve
+}
+
+// SetThrottle rate-limits and caps Check calls per downstream client, resolved from the source
+// address and, through trusted proxies, the forwarding headers. Throttled calls are denied with
+// 429 and reason rate_limited or too_many_in_flight.
+func (s *Server) SetThrottle(t *throttle.Throttler, clientIPs *clientip.Resolver) {
+	s.throttle = t
+	s.clientIPs = clientIPs
+}
+
+// SetMeter records each decision in the usage metrics.
+func (s *Server) SetMeter(m *metering.Meter) {
+	s.meter = m
+}
+
+// SetAuditor records each decision in the audit log.
+func (s *Server) SetAuditor(a *audit.Auditor) {
+	s.auditor = a
+}
+
+// SetHooks runs the decision hooks of chain before each decision and after each allowed one.
+func (s *Server) SetHooks(chain *hooks.Chain) {
+	s.hooks = chain
+}
+
+// SetPolicy denies the requests that the authorization rules of engine do not allow, once a
+// subscription is selected.
+func (s *Server) SetPolicy(engine *policy.Engine) {
+	s.policy = engine
+}
+
+// SetSignatureVerifier requires the users with a signing secret to sign their requests. Unsigned,
+// stale and replayed requests of those users are denied with 401.
+func (s *Server) SetSignatureVerifier(v *signing.Verifier) {
+	s.signatures = v
+}
+
+// SetSignedURLSigner accepts requests without an Authorization header that carry a signed URL of
+// signer, as the user who minted it, and lets the batch handler mint them.
+func (s *Server) SetSignedURLSigner(signer *signedurl.Signer) {
+	s.signedURLs = signer
+}
+
+// Check implements authv3.AuthorizationServer. The decision is traced in a span continuing the
+// gateway's trace, under the tracing policy of the requested model.
+func (s *Server) Check(ctx context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	start := time.Now()
+	httpReq := req.GetAttributes().GetRequest().GetHttp()
+	target := s.target(req.GetAttributes())
+	ctx, span := tracing.StartModelSpan(tracing.Extract(ctx, httpReq.GetHeaders()), "ext_authz.Check", target.model(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attribute.String(tracing.RequestIDAttribute, httpReq.GetHeaders()["x-request-id"])))
+	defer span.End()
+
+	resp, err := s.check(ctx, req, target)
+	model, subscription, user, code := s.decisionLabels(req, resp)
+	if err != nil {
+		code = reason.InternalError
+		tracing.Fail(span, err)
+	}
+	span.SetAttributes(
+		attribute.String(tracing.ReasonAttribute, code),
+		attribute.String("maas.user", user),
+		attribute.String("maas.subscription", subscription),
+	)
+	s.meter.Decision("ext_authz", model, subscription, user, code, time.Since(start))
+	s.auditor.Decision("ext_authz", model, subscription, user, signedurl.Redact(httpReq.GetPath()), code)
+	return resp, err
+}
+
+// checkTarget is the model a request is for, or the denial reason and message when it names none.
+type checkTarget struct {
+	namespace, name string
+	code, message   string
+}
+
+func (t checkTarget) model() string {
+	if t.code != "" {
+		return ""
+	}
+	return t.namespace + "/" + t.name
+}
+
+// target resolves the model a request is for.
+func (s *Server) target(attrs *authv3.AttributeContext) checkTarget {
+	if message := s.modelConflict(attrs.GetContextExtensions(), attrs.GetRequest().GetHttp()); message != "" {
+		return checkTarget{code: reason.BadRequest, message: message}
+	}
+	modelNS, modelName, ok := s.modelFromRequest(attrs.GetContextExtensions(), attrs.GetRequest().GetHttp())
+	if !ok {
+		return checkTarget{code: reason.ModelNotFound, message: "request does not target a MaaS model"}
+	}
+	modelNS, modelName = s.resolveAlias(modelNS, modelName)
+	if modelNS == "" {
+		var code, message string
+		if modelNS, code, message = s.resolveNamespace(modelName); code != "" {
+			return checkTarget{name: modelName, code: code, message: message}
+		}
+	}
+	return checkTarget{namespace: modelNS, name: modelName}
+}
+
+func (s *Server) check(ctx context.Context, req *authv3.CheckRequest, target checkTarget) (*authv3.CheckResponse, error) {
+	attrs := req.GetAttributes()
+	httpReq := attrs.GetRequest().GetHttp()
+
+	if s.throttle != nil {
+		release, reason := s.throttle.Admit(ctx, s.caller(attrs))
+		if reason != "" {
+			throttle.Rejected("ext_authz", reason)
+			resp := denied(codes.ResourceExhausted, reason, "Too many requests")
+			resp.GetDeniedResponse().Headers = append(resp.GetDeniedResponse().Headers, header("retry-after", "1"))
+			return resp, nil
+		}
+		defer release()
+	}
+
+	if target.code == reason.BadRequest {
+		return denied(codes.InvalidArgument, target.code, target.message), nil
+	}
+	if target.code != "" {
+		return denied(codes.PermissionDenied, target.code, target.message), nil
+	}
+	modelNS, modelName, model := target.namespace, target.name, target.model()
+	suffix := s.apiSuffix(httpReq.GetPath())
+	if len(s.suffixes) > 0 && suffix == "" {
+		return denied(codes.NotFound, reason.UnknownEndpoint, "unknown endpoint, must be one of "+strings.Join(s.suffixes, ", ")), nil
+	}
+
+	var identity *api_keys.ValidationResult
+	signedURL := s.signedURLs != nil && httpReq.GetHeaders()["authorization"] == "" && signedurl.FromPath(httpReq.GetPath()) != ""
+	if signedURL {
+		// The grant is scoped to its model like a model-scoped API key.
+		identity = api_keys.ValidateSignedURL(s.signedURLs, httpReq.GetPath())
+	} else {
+		key, ok := strings.CutPrefix(httpReq.GetHeaders()["authorization"], "Bearer ")
+		if !ok || !strings.HasPrefix(key, "sk-oai-") {
+			return denied(codes.Unauthenticated, reason.Unauthenticated, "Authentication required"), nil
+		}
+		var err error
+		if identity, err = s.validateAPIKey(ctx, key); err != nil {
+			s.logger.Error("API key validation failed", "error", err, "model", model)
+			return nil, err
+		}
+	}
+	if !identity.Valid {
+		s.logger.Debug("Rejected invalid API key", "reason", identity.Reason, "model", model)
+		return denied(codes.Unauthenticated, reason.Unauthenticated, "Authentication required"), nil
+	}
+	if s.signatures != nil {
+		reason, message, err := s.signatures.Verify(ctx, identity.Username, httpReq.GetMethod(), httpReq.GetPath(), httpReq.GetHeaders())
+		if err != nil {
+			s.logger.Error("Request signature check failed", "error", err, "username", identity.Username)
+			return nil, err
+		}
+		if reason != "" {
+			s.logger.Debug("Rejected signed request", "reason", reason, "keyId", identity.KeyID, "model", model)
+			return denied(codes.Unauthenticated, reason, message), nil
+		}
+	}
+	if len(identity.Models) > 0 && !slices.Contains(identity.Models, model) {
+		s.logger.Debug("Denied API key outside its model scope", "keyId", identity.KeyID, "model", model)
+		return s.modelDenied(model, reason.ModelNotInKeyScope, "API key is not valid for this model"), nil
+	}
+
+	hookInput := hooks.Input{
+		Phase:        hooks.PhasePre,
+		Endpoint:     "ext_authz",
+		Model:        model,
+		User:         identity.Username,
+		Groups:       identity.Groups,
+		KeyID:        identity.KeyID,
+		Subscription: identity.Subscription,
+		Path:         httpReq.GetPath(),
+		Host:         httpReq.GetHost(),
+		RequestID:    httpReq.GetHeaders()["x-request-id"],
+	}
+	pre := s.hooks.Run(ctx, hookInput)
+	if pre.Denied() {
+		return s.modelDenied(model, pre.Reason, pre.Message), nil
+	}
+
+	allowed, err := s.allows(ctx, model, identity.Username, identity.Groups)
+	if err != nil {
+		s.logger.Error("Failed to list MaaSAuthPolicies", "error", err)
+		return nil, err
+	}
+	if !allowed {
+		return s.modelDenied(model, reason.Unauthorized, "Access denied"), nil
+	}
+
+	sub, err := s.selectSubscription(ctx, identity.Groups, identity.Username, identity.Subscription, model, httpReq.GetHost())
+	if err != nil {
+		var maintenanceErr *subscription.ModelMaintenanceError
+		if errors.As(err, &maintenanceErr) {
+			resp := denied(codes.Unavailable, reason.ModelMaintenance, err.Error())
+			retryAfter := subscription.RetryAfterSeconds(maintenanceErr.RetryAfter)
+			resp.GetDeniedResponse().Headers = append(resp.GetDeniedResponse().Headers, header("retry-after", strconv.FormatInt(retryAfter, 10)))
+			return resp, nil
+		}
+		var clusterErr *subscription.ModelClusterUnavailableError
+		if errors.As(err, &clusterErr) {
+			return denied(codes.Unavailable, reason.ClusterUnavailable, err.Error()), nil
+		}
+		code := subscription.ErrorCode(err)
+		if code == reason.InternalError {
+			s.logger.Error("Subscription selection failed", "error", err, "username", identity.Username)
+		}
+		return s.modelDenied(model, code, err.Error()), nil
+	}
+	if v := s.policy.Evaluate(policy.Input{
+		Model: model, User: identity.Username, Groups: identity.Groups, Tier: sub.Name, Path: httpReq.GetPath(), Time: time.Now(),
+	}); v.Denied() {
+		return s.modelDenied(model, v.Reason, v.Message), nil
+	}
+
+	if suffix != "" && !endpointAllowed(sub.Endpoints, suffix) {
+		s.logger.Debug("Denied endpoint outside the subscription", "endpoint", suffix, "subscription", sub.Name, "model", model)
+		return s.modelDenied(model, reason.EndpointNotAllowed, "subscription "+sub.Name+" does not allow "+suffix), nil
+	}
+
+	if v := attachments.Check(sub.Attachments, requestBody(httpReq), httpReq.GetHeaders()[partialBodyHeader] == "true"); v != nil {
+		s.logger.Debug("Denied request attachments", "reason", v.Reason, "subscription", sub.Name, "model", model)
+		return attachmentDenied(v), nil
+	}
+
+	// A truncated body cannot be parsed; the model server rejects it if it is too long.
+	if s.contexts != nil && httpReq.GetHeaders()[partialBodyHeader] != "true" {
+		if v := s.contexts.Check(ctx, model, s.windows(model), requestBody(httpReq)); v != nil {
+			s.logger.Debug("Denied request over the context window", "promptTokens", v.PromptTokens, "maxTokens", v.MaxTokens,
+				"contextWindow", v.ContextWindow, "estimated", v.Estimated, "model", model)
+			return contextLengthDenied(v), nil
+		}
+	}
+
+	subscriptionKey := sub.Namespace + "/" + sub.Name + "@" + model
+	if s.budgets != nil {
+		if message, resetsIn := s.budgets.BudgetExhausted(ctx, identity.Username, subscriptionKey); message != "" {
+			s.logger.Debug("Token budget exhausted", "username", identity.Username, "subscription", sub.Name, "model", model)
+			resp := denied(codes.ResourceExhausted, reason.QuotaExhausted, message)
+			retryAfter := max(int64(math.Ceil(resetsIn.Seconds())), 1)
+			resp.GetDeniedResponse().Headers = append(resp.GetDeniedResponse().Headers, header("retry-after", strconv.FormatInt(retryAfter, 10)))
+			return resp, nil
+		}
+	}
+	if s.rateLimits != nil {
+		retryIn, err := s.rateLimits.Admit(ctx, identity.Username, subscriptionKey)
+		if err != nil {
+			// An unreachable Redis must not take authorization down with it.
+			s.logger.Error("Rate limit check failed, admitting request", "error", err, "subscription", sub.Name, "model", model)
+		} else if retryIn > 0 {
+			s.logger.Debug("Rate limit exceeded", "username", identity.Username, "subscription", sub.Name, "model", model)
+			resp := denied(codes.ResourceExhausted, reason.RateLimited, "Rate limit exceeded")
+			retryAfter := max(int64(math.Ceil(retryIn.Seconds())), 1)
+			resp.GetDeniedResponse().Headers = append(resp.GetDeniedResponse().Headers, header("retry-after", strconv.FormatInt(retryAfter, 10)))
+			return resp, nil
+		}
+	}
+	var quotaWarning string
+	if s.quotaWarner != nil {
+		quotaWarning = s.quotaWarner.QuotaWarning(ctx, identity.Username, subscriptionKey)
+	}
+	var remainingTokens *int64
+	if s.tokens != nil {
+		remainingTokens = subscription.RemainingTokens(ctx, s.tokens, identity.Username, subscriptionKey, sub.RateLimits)
+	}
+	hookInput.Phase, hookInput.Subscription = hooks.PhasePost, sub.Name
+	post := s.hooks.Run(ctx, hookInput)
+	if post.Denied() {
+		return s.modelDenied(model, post.Reason, post.Message), nil
+	}
+	// Taken last, so no later denial leaves a lease behind.
+	if s.concurrency != nil {
+		if resp := s.acquireLease(ctx, httpReq, identity.Username, sub.Name, model, subscriptionKey); resp != nil {
+			return resp, nil
+		}
+	}
+
+	s.logger.Debug("Request allowed",
+		"username", identity.Username,
+		"model", model,
+		"subscription", sub.Name,
+		"selectedBy", sub.SelectedBy,
+	)
+	resp, err := allowedResponse(identity, sub, modelNS, modelName, subscriptionKey, quotaWarning, remainingTokens)
+	if err != nil {
+		return nil, err
+	}
+	if signedURL {
+		// The token authorizes the request at the gateway only; the model never sees it.
+		resp.GetOkResponse().QueryParametersToRemove = []string{signedurl.QueryParameter}
+	}
+	// Headers from hooks, post hooks overriding pre hooks
+	hookHeaders := map[string]string{}
+	maps.Copy(hookHeaders, pre.Headers)
+	maps.Copy(hookHeaders, post.Headers)
+	okResp := resp.GetOkResponse()
+	for _, name := range slices.Sorted(maps.Keys(hookHeaders)) {
+		okResp.Headers = append(okResp.Headers, header(name, hookHeaders[name]))
+	}
+	return resp, nil
+}
+
+// acquireLease takes the request's concurrency lease, returning a denial when the caller has no
+// request to spare.
+func (s *Server) acquireLease(ctx context.Context, httpReq *authv3.AttributeContext_HttpRequest, username, subscription, model, subscriptionKey string) *authv3.CheckResponse {
+	requestID := httpReq.GetId()
+	if requestID == "" {
+		requestID = httpReq.GetHeaders()["x-request-id"]
+	}
+	if requestID == "" {
+		// Without an ID the gateway's access log cannot release a lease.
+		s.logger.Debug("Request without an ID, skipping the concurrency limit", "subscription", subscription, "model", model)
+		return nil
+	}
+	denial, err := s.concurrency.Acquire(ctx, username, subscriptionKey, requestID)
+	if err != nil {
+		// An unreachable Redis must not take authorization down with it.
+		s.logger.Error("Concurrency limit check failed, admitting request", "error", err, "subscription", subscription, "model", model)
+		return nil
+	}
+	if denial == nil {
+		return nil
+	}
+	s.logger.Debug("Concurrency limit reached", "username", username, "subscription", subscription, "model", model,
+		"limit", denial.Limit, "organization", denial.Organization)
+	message := fmt.Sprintf("At most %d requests may be in progress on this model under subscription %s", denial.Limit, subscription)
+	if denial.Organization != "" {
+		message = fmt.Sprintf("At most %d requests may be in progress across all models for organization %s", denial.Limit, denial.Organization)
+	}
+	resp := denied(codes.ResourceExhausted, reason.TooManyConcurrentRequests, message)
+	resp.GetDeniedResponse().Headers = append(resp.GetDeniedResponse().Headers, header("retry-after", "1"))
+	return resp
+}
+
+// decisionLabels returns the metering labels of a decision. Allowed responses carry the model,
+// subscription and user in their metadata; denials only the reason, and the model as requested.
+func (s *Server) decisionLabels(req *authv3.CheckRequest, resp *authv3.CheckResponse) (model, subscription, user, reason string) {
+	if resp.GetStatus().GetCode() == int32(codes.OK) {
+		fields := resp.GetDynamicMetadata().GetFields()
+		identity := fields["identity"].GetStructValue().GetFields()
+		modelFields := fields["model"].GetStructValue().GetFields()
+		model = modelFields["namespace"].GetStringValue() + "/" + modelFields["name"].GetStringValue()
+		return model, identity["selected_subscription"].GetStringValue(), identity["userid"].GetStringValue(), ""
+	}
+	attrs := req.GetAttributes()
+	if ns, name, ok := s.modelFromRequest(attrs.GetContextExtensions(), attrs.GetRequest().GetHttp()); ok {
+		model = strings.TrimPrefix(ns+"/"+name, "/")
+	}
+	for _, h := range resp.GetDeniedResponse().GetHeaders() {
+		if h.GetHeader().GetKey() == "x-ext-auth-reason" {
+			reason = h.GetHeader().GetValue()
+		}
+	}
+	return model, "", "", reason
+}
+
+// modelDenied is a 403 denial for a known model, with the model's custom body for the reason if
+// it defines one.
+func (s *Server) modelDenied(model, reason, message string) *authv3.CheckResponse {
+	resp := denied(codes.PermissionDenied, reason, message)
+	if s.errorBodies == nil {
+		return resp
+	}
+	custom := s.errorBodies(model).For(reason)
+	if custom == nil {
+		return resp
+	}
+	deniedResp := resp.GetDeniedResponse()
+	deniedResp.Body = custom.Body(reason)
+	deniedResp.Headers[1] = header("content-type", "application/json")
+	return resp
+}
+
+// resolveAlias returns the model named by an alias, or namespace and name as they are.
+func (s *Server) resolveAlias(namespace, name string) (string, string) {
+	if s.aliases == nil {
+		return namespace, name
+	}
+	if aliasNS, aliasName, ok := s.aliases(namespace, name); ok {
+		s.logger.Debug("Resolved model alias", "alias", strings.TrimPrefix(namespace+"/"+name, "/"), "model", aliasNS+"/"+aliasName)
+		return aliasNS, aliasName
+	}
+	return namespace, name
+}
+
+// resolveNamespace returns the namespace for a bare model name, or the denial reason and message
+// when the name matches no model or several equally ranked ones.
+func (s *Server) resolveNamespace(name string) (namespace, code, message string) {
+	if s.resolve == nil {
+		return "", reason.ModelNotFound, "request does not target a MaaS model"
+	}
+	ns, err := s.resolve(name)
+	var ambiguous *models.AmbiguousModelError
+	switch {
+	case err == nil:
+		return ns, "", ""
+	case errors.Is(err, models.ErrModelNotFound):
+		return "", reason.ModelNotFound, "request does not target a MaaS model"
+	case errors.As(err, &ambiguous):
+		s.logger.Debug("Denied ambiguous model name", "model", name, "namespaces", ambiguous.Namespaces)
+		return "", reason.ModelAmbiguous, err.Error()
+	default:
+		s.logger.Error("Model resolution failed", "error", err, "model", name)
+		return "", reason.InternalError, "model resolution failed"
+	}
+}
+
+// validateAPIKey validates the API key in a span.
+func (s *Server) validateAPIKey(ctx context.Context, key string) (*api_keys.ValidationResult, error) {
+	ctx, span := tracing.Start(ctx, "maas.api_key.validate")
+	defer span.End()
+	identity, err := s.keys.ValidateAPIKey(ctx, key)
+	if err != nil {
+		tracing.Fail(span, err)
+	}
+	return identity, err
+}
+
+// selectSubscription selects the subscription (tier) the request is metered against in a span.
+func (s *Server) selectSubscription(ctx context.Context, groups []string, username, requested, model, host string) (*subscription.SelectResponse, error) {
+	_, span := tracing.Start(ctx, "maas.subscription.select")
+	defer span.End()
+	//nolint:unqueryvet,nolintlint // Select is a method, not a SQL query
+	sub, err := s.selector.SelectForHost(groups, username, requested, model, host)
+	if err != nil {
+		span.SetAttributes(attribute.String(tracing.ReasonAttribute, subscription.ErrorCode(err)))
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("maas.selected_by", sub.SelectedBy))
+	return sub, nil
+}
+
+// allows reports whether a MaaSAuthPolicy of the model ("namespace/name"), or of its nearest
+// ancestor with one, or the model's own allow-list grants the user access. The policy lookups
+// are traced in a span.
+func (s *Server) allows(ctx context.Context, model, username string, groups []string) (bool, error) {
+	_, span := tracing.Start(ctx, "maas.authpolicy.lookup")
+	defer span.End()
+	modelNS, modelName, _ := strings.Cut(model, "/")
+	allowed, found, err := s.subjectsForModel(modelNS, modelName)
+	if err == nil && !found && s.lineage != nil {
+		for _, ancestor := range s.lineage(model) {
+			ns, name, _ := strings.Cut(ancestor, "/")
+			if allowed, found, err = s.subjectsForModel(ns, name); err != nil || found {
+				break
+			}
+		}
+	}
+	if err != nil {
+		tracing.Fail(span, err)
+		return false, err
+	}
+	if found && s.allowList != nil {
+		allowed.Add(s.allowList(model))
+	}
+	span.SetAttributes(attribute.Bool("maas.policy_found", found))
+	return found && allowed.Allows(username, groups), nil
+}
+
+func (s *Server) subjectsForModel(modelNamespace, modelName string) (authpolicy.Subjects, bool, error) {
+	policies, err := authpolicy.PoliciesForModel(s.policies, modelNamespace, modelName)
+	if err != nil {
+		return authpolicy.Subjects{}, false, err
+	}
+	allowed, found := authpolicy.SubjectsForModel(policies, modelNamespace, modelName)
+	return allowed, found, nil
+}
+
+// modelFromRequest returns the model namespace and name from the route's context extension or,
+// without one, from the first of the configured sources that names a model. The namespace is
+// empty when only a bare name is given.
+func (s *Server) modelFromRequest(extensions map[string]string, httpReq *authv3.AttributeContext_HttpRequest) (string, string, bool) {
+	if ref := extensions[ModelContextExtension]; ref != "" {
+		return splitModelRef(ref)
+	}
+	for _, source := range s.sources {
+		var ref string
+		switch source {
+		case ModelSourcePath:
+			ref = s.modelFromPath(httpReq.GetPath())
+		case ModelSourceHost:
+			if s.routes != nil {
+				ref, _ = s.routes.ByHost(httpReq.GetHost(), httpReq.GetPath())
+			}
+		case ModelSourceHeader:
+			ref = strings.TrimSpace(httpReq.GetHeaders()[ModelHeader])
+		case ModelSourceBody:
+			ref = modelFromBody(httpReq)
+		}
+		if ref != "" {
+			return splitModelRef(ref)
+		}
+	}
+	return "", "", false
+}
+
+// modelConflict returns why a request is refused when the model source in use is the X-MaaS-Model
+// header and the JSON body names a different model, or "". The gateway routes on the body, so a
+// client could otherwise be authorized for the model in the header and served the one in the body.
+func (s *Server) modelConflict(extensions map[string]string, httpReq *authv3.AttributeContext_HttpRequest) string {
+	if extensions[ModelContextExtension] != "" || !slices.Contains(s.sources, ModelSourceHeader) {
+		return ""
+	}
+	fromHeader := strings.TrimSpace(httpReq.GetHeaders()[ModelHeader])
+	fromBody := modelFromBody(httpReq)
+	if fromHeader == "" || fromBody == "" || sameModel(fromHeader, fromBody) {
+		return ""
+	}
+	return fmt.Sprintf("the X-MaaS-Model header names %s but the request body names %s", fromHeader, fromBody)
+}
+
+// sameModel reports whether two model references name the same model. A bare name matches any
+// namespace.
+func sameModel(a, b string) bool {
+	nsA, nameA, _ := splitModelRef(a)
+	nsB, nameB, _ := splitModelRef(b)
+	return nameA == nameB && (nsA == "" || nsB == "" || nsA == nsB)
+}
+
+// modelFromPath returns the model ("namespace/name") a path is routed to: the model whose custom
+// path prefix matches, or the first two path segments. It returns "" when the path names no model.
+func (s *Server) modelFromPath(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	if s.routes != nil {
+		if routed, ok := s.routes.ByPath(path); ok {
+			return routed
+		}
+	}
+	segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" {
+		return ""
+	}
+	return segments[0] + "/" + segments[1]
+}
+
+// apiSuffix returns the longest configured API suffix path ends with, or "" when none matches or
+// none are configured.
+func (s *Server) apiSuffix(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	var best string
+	for _, suffix := range s.suffixes {
+		if len(suffix) > len(best) && strings.HasSuffix(path, suffix) {
+			best = suffix
+		}
+	}
+	return best
+}
+
+// endpointAllowed reports whether a subscription restricted to endpoints serves suffix. An
+// unrestricted subscription serves every suffix, and every subscription may list models.
+func endpointAllowed(endpoints []string, suffix string) bool {
+	return len(endpoints) == 0 || suffix == "/v1/models" || slices.Contains(endpoints, suffix)
+}
+
+// partialBodyHeader is set by Envoy when the request body it forwards is truncated to the
+// ext_authz filter's max_request_bytes.
+const partialBodyHeader = "x-envoy-auth-partial-body"
+
+// requestBody returns the request body Envoy forwards when the ext_authz filter sets
+// with_request_body, or nil.
+func requestBody(httpReq *authv3.AttributeContext_HttpRequest) []byte {
+	if body := httpReq.GetRawBody(); len(body) > 0 {
+		return body
+	}
+	if body := httpReq.GetBody(); body != "" {
+		return []byte(body)
+	}
+	return nil
+}
+
+// attachmentStatuses are the HTTP statuses of attachment policy denials.
+var attachmentStatuses = map[string]typev3.StatusCode{
+	reason.TooManyAttachments:       typev3.StatusCode_BadRequest,
+	reason.AttachmentTooLarge:       typev3.StatusCode_PayloadTooLarge,
+	reason.ImageTooLarge:            typev3.StatusCode_BadRequest,
+	reason.AttachmentTypeNotAllowed: typev3.StatusCode_UnsupportedMediaType,
+}
+
+// attachmentDenied is the denial of a request that breaks its subscription's attachment policy.
+func attachmentDenied(v *attachments.Violation) *authv3.CheckResponse {
+	resp := denied(codes.InvalidArgument, v.Reason, v.Message)
+	resp.GetDeniedResponse().Status.Code = attachmentStatuses[v.Reason]
+	return resp
+}
+
+// contextLengthDenied is the 400 denial of a request that does not fit its model's context window.
+// The body is a JSON error envelope, so clients can read the token counts.
+func contextLengthDenied(v *contextwindow.Violation) *authv3.CheckResponse {
+	resp := denied(codes.InvalidArgument, reason.ContextLengthExceeded, v.Message())
+	deniedResp := resp.GetDeniedResponse()
+	deniedResp.Body = string(apierror.New(apierror.ContextLengthExceeded, v.Message()).WithDetails(map[string]any{
+		"reason":        reason.ContextLengthExceeded,
+		"contextWindow": v.ContextWindow,
+		"promptTokens":  v.PromptTokens,
+		"maxTokens":     v.MaxTokens,
+		"estimated":     v.Estimated,
+	}).JSON())
+	deniedResp.Headers[1] = header("content-type", "application/json")
+	return resp
+}
+
+// modelFromBody returns the "model" field of a JSON request body, or "".
+func modelFromBody(httpReq *authv3.AttributeContext_HttpRequest) string {
+	body := requestBody(httpReq)
+	if len(body) == 0 {
+		return ""
+	}
+	var payload struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(payload.Model)
+}
+
+// splitModelRef splits "namespace/name" or a bare name.
+func splitModelRef(ref string) (string, string, bool) {
+	if !strings.Contains(ref, "/") {
+		return "", ref, true
+	}
+	ns, name, ok := strings.Cut(ref, "/")
+	if !ok || ns == "" || name == "" {
+		return "", "", false
+	}
+	return ns, name, true
+}
+
+// sandboxHeader marks requests of sandbox subscriptions; the gateway routes them to the mock
+// backend the controller deploys instead of the model.
+const sandboxHeader = "X-MaaS-Sandbox"
+
+func sandboxValue(sandbox bool) string {
+	if sandbox {
+		return "true"
+	}
+	return ""
+}
+
+func allowedResponse(identity *api_keys.ValidationResult, sub *subscription.SelectResponse, modelNS, modelName, subscriptionKey, quotaWarning string,
+	remainingTokens *int64,
+) (*authv3.CheckResponse, error) {
+	labels := make(map[string]any, len(sub.Labels))
+	for k, v := range sub.Labels {
+		labels[k] = v
+	}
+	groups := make([]any, len(identity.Groups))
+	for i, g := range identity.Groups {
+		groups[i] = g
+	}
+	var rpm, tpm int64
+	if sub.RateLimits != nil {
+		rpm, tpm = sub.RateLimits.RequestsPerMinute, sub.RateLimits.TokensPerMinute
+	}
+	metadata, err := structpb.NewStruct(map[string]any{
+		"identity": map[string]any{
+			"userid":                    identity.Username,
+			"keyId":                     identity.KeyID,
+			"groups":                    groups,
+			"groups_str":                strings.Join(identity.Groups, ","),
+			"selected_subscription":     sub.Name,
+			"selected_subscription_key": subscriptionKey,
+			"subscription_selected_by":  sub.SelectedBy,
+			"organizationId":            sub.OrganizationID,
+			"costCenter":                sub.CostCenter,
+			"subscription_labels":       labels,
+			"rate_limit_rpm":            rpm,
+			"rate_limit_tpm":            tpm,
+		},
+		"model": map[string]any{
+			"namespace": modelNS,
+			"name":      modelName,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	headers := []*corev3.HeaderValueOption{
+		header("X-MaaS-Username", identity.Username),
+		header("X-MaaS-Group", `["`+strings.Join(identity.Groups, `","`)+`"]`),
+		header("X-MaaS-Key-Id", identity.KeyID),
+		header("X-MaaS-Subscription", identity.Subscription),
+		header("X-MaaS-Model-Namespace", modelNS),
+		// The gateway's quota filter reports the response's token usage against this key.
+		header("X-MaaS-Subscription-Key", subscriptionKey),
+		// Always set, so a client cannot route itself to the sandbox by sending the header.
+		header(sandboxHeader, sandboxValue(sub.Sandbox)),
+	}
+	if quotaWarning != "" {
+		headers = append(headers, header("X-MaaS-Quota-Warning", quotaWarning))
+	}
+	if sub.Warning != "" {
+		headers = append(headers, header("X-MaaS-Subscription-Warning", sub.Warning))
+	}
+	// Federated models: the gateway routes the request to the cluster hosting the model.
+	if sub.Cluster != "" {
+		headers = append(headers, header("X-MaaS-Cluster", sub.Cluster))
+	}
+	// The gateway moves these onto the response as X-MaaS-Tier, X-MaaS-Model and
+	// X-MaaS-Remaining-Tokens, as with the AuthPolicy.
+	headers = append(headers,
+		header("X-MaaS-Response-Tier", sub.Name),
+		header("X-MaaS-Response-Model", modelNS+"/"+modelName),
+	)
+	if remainingTokens != nil {
+		headers = append(headers, header("X-MaaS-Response-Remaining-Tokens", strconv.FormatInt(*remainingTokens, 10)))
+	}
+
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{
+			OkResponse: &authv3.OkHttpResponse{Headers: headers},
+		},
+		DynamicMetadata: metadata,
+	}, nil
+}
+
+// caller returns the downstream client a Check call is made for.
+func (s *Server) caller(attrs *authv3.AttributeContext) string {
+	source := attrs.GetSource().GetAddress().GetSocketAddress().GetAddress()
+	if s.clientIPs == nil {
+		return source
+	}
+	headers := http.Header{}
+	for key, value := range attrs.GetRequest().GetHttp().GetHeaders() {
+		headers.Set(key, value)
+	}
+	if client := s.clientIPs.ClientIP(source, headers); client.IsValid() {
+		return client.String()
+	}
+	return source
+}
+
+// denied builds a denial matching the AuthPolicy's custom responses: 401 with a fixed message for
+// authentication failures, 429 when throttled, 503 for models in maintenance, 400 for invalid
+// requests, 404 for unknown endpoints, 403 otherwise, with the reason in x-ext-auth-reason.
+func denied(code codes.Code, reason, message string) *authv3.CheckResponse {
+	httpStatus := typev3.StatusCode_Forbidden
+	switch code {
+	case codes.Unauthenticated:
+		httpStatus = typev3.StatusCode_Unauthorized
+	case codes.ResourceExhausted:
+		httpStatus = typev3.StatusCode_TooManyRequests
+	case codes.Unavailable:
+		httpStatus = typev3.StatusCode_ServiceUnavailable
+	case codes.InvalidArgument:
+		httpStatus = typev3.StatusCode_BadRequest
+	case codes.NotFound:
+		httpStatus = typev3.StatusCode_NotFound
+	}
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(code), Message: message},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{
+			DeniedResponse: &authv3.DeniedHttpResponse{
+				Status: &typev3.HttpStatus{Code: httpStatus},
+				Headers: []*corev3.HeaderValueOption{
+					header("x-ext-auth-reason", reason),
+					header("content-type", "text/plain"),
+				},
+				Body: message,
+			},
+		},
+	}
+}
+
+func header(key, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header:       &corev3.HeaderValue{Key: key, Value: value},
+		AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
@@ -0,0 +1,1150 @@
+package extauthz_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/authpolicy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/concurrency"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/contextwindow"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/hooks"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signing"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/throttle"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+const (
+	validKey  = "sk-oai-valid"
+	scopedKey = "sk-oai-scoped" // valid, but only for llm/llama
+)
+
+type fakeKeys struct{}
+
+func (fakeKeys) ValidateAPIKey(_ context.Context, key string) (*api_keys.ValidationResult, error) {
+	if key != validKey && key != scopedKey {
+		return &api_keys.ValidationResult{Valid: false, Reason: "key not found"}, nil
+	}
+	result := &api_keys.ValidationResult{
+		Valid: true, Username: "alice", KeyID: "key-1",
+		Groups: []string{"premium-users"}, Subscription: "premium",
+	}
+	if key == scopedKey {
+		result.Models = []string{"llm/llama"}
+	}
+	return result, nil
+}
+
+type staticLister []*unstructured.Unstructured
+
+func (s staticLister) List() ([]*unstructured.Unstructured, error) { return s, nil }
+
+func authPolicy(group, modelNS, modelName string) *unstructured.Unstructured {
+	p := &unstructured.Unstructured{}
+	p.SetName("policy-" + group)
+	_ = unstructured.SetNestedSlice(p.Object, []any{map[string]any{"name": modelName, "namespace": modelNS}}, "spec", "modelRefs")
+	_ = unstructured.SetNestedSlice(p.Object, []any{map[string]any{"name": group}}, "spec", "subjects", "groups")
+	return p
+}
+
+func premiumSubscription() *unstructured.Unstructured {
+	sub := &unstructured.Unstructured{}
+	sub.SetName("premium")
+	sub.SetNamespace("models-as-a-service")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{map[string]any{"name": "premium-users"}}, "spec", "owner", "groups")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{map[string]any{
+		"name":              "granite",
+		"namespace":         "llm",
+		"tokenRateLimits":   []any{map[string]any{"limit": int64(5000), "window": "1m"}},
+		"requestRateLimits": []any{map[string]any{"limit": int64(60), "window": "1m"}},
+	}}, "spec", "modelRefs")
+	_ = unstructured.SetNestedField(sub.Object, "org-1", "spec", "tokenMetadata", "organizationId")
+	return sub
+}
+
+func newServer() *extauthz.Server {
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	return extauthz.NewServer(log, fakeKeys{}, selector,
+		staticLister{authPolicy("premium-users", "llm", "granite"), authPolicy("other-users", "llm", "llama")})
+}
+
+func check(t *testing.T, s *extauthz.Server, path, authorization string) *authv3.CheckResponse {
+	t.Helper()
+	headers := map[string]string{}
+	if authorization != "" {
+		headers["authorization"] = authorization
+	}
+	resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{Path: path, Headers: headers},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestCheckAllowed(t *testing.T) {
+	resp := check(t, newServer(), "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+
+	headers := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "alice", headers["X-MaaS-Username"])
+	assert.Equal(t, `["premium-users"]`, headers["X-MaaS-Group"])
+	assert.Equal(t, "key-1", headers["X-MaaS-Key-Id"])
+	assert.Equal(t, "premium", headers["X-MaaS-Subscription"])
+	assert.Equal(t, "models-as-a-service/premium@llm/granite", headers["X-MaaS-Subscription-Key"])
+	assert.Contains(t, headers, "X-MaaS-Sandbox", "the sandbox header must be overwritten even when empty")
+	assert.Empty(t, headers["X-MaaS-Sandbox"])
+
+	identity := resp.GetDynamicMetadata().GetFields()["identity"].GetStructValue().GetFields()
+	assert.Equal(t, "alice", identity["userid"].GetStringValue())
+	assert.Equal(t, "models-as-a-service/premium@llm/granite", identity["selected_subscription_key"].GetStringValue())
+	assert.Equal(t, "org-1", identity["organizationId"].GetStringValue())
+	assert.InDelta(t, 60, identity["rate_limit_rpm"].GetNumberValue(), 0)
+	assert.InDelta(t, 5000, identity["rate_limit_tpm"].GetNumberValue(), 0)
+}
+
+// remainingTokens reports the same remaining tokens for every caller.
+type remainingTokens int64
+
+func (r remainingTokens) RemainingTokens(context.Context, string, string, int64) (int64, bool) {
+	return int64(r), true
+}
+
+func TestCheckQuotaHeaders(t *testing.T) {
+	s := newServer()
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+	headers := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "premium", headers["X-MaaS-Response-Tier"])
+	assert.Equal(t, "llm/granite", headers["X-MaaS-Response-Model"])
+	assert.NotContains(t, headers, "X-MaaS-Response-Remaining-Tokens", "remaining tokens are only reported when enabled")
+
+	s.SetTokenReporter(remainingTokens(1200))
+	resp = check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "1200", headers["X-MaaS-Response-Remaining-Tokens"])
+}
+
+func TestCheckSandboxSubscription(t *testing.T) {
+	log := logger.Development()
+	sub := premiumSubscription()
+	_ = unstructured.SetNestedField(sub.Object, true, "spec", "sandbox")
+	s := extauthz.NewServer(log, fakeKeys{}, subscription.NewSelector(log, staticLister{sub}),
+		staticLister{authPolicy("premium-users", "llm", "granite")})
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		if h.GetHeader().GetKey() == "X-MaaS-Sandbox" {
+			assert.Equal(t, "true", h.GetHeader().GetValue())
+			return
+		}
+	}
+	t.Fatal("X-MaaS-Sandbox header not set")
+}
+
+func TestCheckAttachmentPolicy(t *testing.T) {
+	log := logger.Development()
+	sub := premiumSubscription()
+	_ = unstructured.SetNestedMap(sub.Object, map[string]any{
+		"maxCount":     int64(1),
+		"maxSize":      "1Ki",
+		"allowedTypes": []any{"image/*"},
+	}, "spec", "attachments")
+	s := extauthz.NewServer(log, fakeKeys{}, subscription.NewSelector(log, staticLister{sub}),
+		staticLister{authPolicy("premium-users", "llm", "granite")})
+
+	image := func(url string) map[string]any {
+		return map[string]any{"type": "image_url", "image_url": map[string]any{"url": url}}
+	}
+	checkBody := func(t *testing.T, content []any, partial bool) *authv3.CheckResponse {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{"messages": []any{map[string]any{"role": "user", "content": content}}})
+		require.NoError(t, err)
+		headers := map[string]string{"authorization": "Bearer " + validKey}
+		if partial {
+			headers["x-envoy-auth-partial-body"] = "true"
+		}
+		resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+			Attributes: &authv3.AttributeContext{
+				Request: &authv3.AttributeContext_Request{
+					Http: &authv3.AttributeContext_HttpRequest{Path: "/llm/granite/v1/chat/completions", Headers: headers, RawBody: body},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	small := "data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, 100))
+	large := "data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, 2048))
+
+	tests := []struct {
+		name    string
+		content []any
+		partial bool
+		status  typev3.StatusCode
+		reason  string
+	}{
+		{name: "within policy", content: []any{map[string]any{"type": "text", "text": "hi"}, image(small)}},
+		{name: "too many", content: []any{image(small), image("https://example.com/cat.png")}, status: typev3.StatusCode_BadRequest, reason: "too_many_attachments"},
+		{name: "too large", content: []any{image(large)}, status: typev3.StatusCode_PayloadTooLarge, reason: "attachment_too_large"},
+		{name: "type not allowed", content: []any{map[string]any{"type": "file", "file": map[string]any{"file_data": "data:application/pdf;base64,AAAA"}}}, status: typev3.StatusCode_UnsupportedMediaType, reason: "attachment_type_not_allowed"},
+		{name: "truncated body", content: []any{image(small)}, partial: true, status: typev3.StatusCode_PayloadTooLarge, reason: "attachment_too_large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := checkBody(t, tt.content, tt.partial)
+			if tt.reason == "" {
+				assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+				return
+			}
+			require.Equal(t, int32(codes.InvalidArgument), resp.GetStatus().GetCode())
+			assert.Equal(t, tt.status, resp.GetDeniedResponse().GetStatus().GetCode())
+			assert.Equal(t, tt.reason, resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+		})
+	}
+}
+
+func TestCheckContextWindow(t *testing.T) {
+	s := newServer()
+	s.SetContextWindowChecker(contextwindow.NewChecker(logger.Development(), nil), func(model string) int64 {
+		if model == "llm/granite" {
+			return 100
+		}
+		return 0
+	})
+	checkBody := func(t *testing.T, prompt string, maxTokens int, partial bool) *authv3.CheckResponse {
+		t.Helper()
+		body, err := json.Marshal(map[string]any{"messages": []any{map[string]any{"role": "user", "content": prompt}}, "max_tokens": maxTokens})
+		require.NoError(t, err)
+		headers := map[string]string{"authorization": "Bearer " + validKey}
+		if partial {
+			headers["x-envoy-auth-partial-body"] = "true"
+		}
+		resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+			Attributes: &authv3.AttributeContext{
+				Request: &authv3.AttributeContext_Request{
+					Http: &authv3.AttributeContext_HttpRequest{Path: "/llm/granite/v1/chat/completions", Headers: headers, RawBody: body},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := checkBody(t, strings.Repeat("word ", 40), 40, false)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+
+	resp = checkBody(t, strings.Repeat("word ", 40), 80, false)
+	require.Equal(t, int32(codes.InvalidArgument), resp.GetStatus().GetCode())
+	denied := resp.GetDeniedResponse()
+	assert.Equal(t, typev3.StatusCode_BadRequest, denied.GetStatus().GetCode())
+	assert.Equal(t, "context_length_exceeded", denied.GetHeaders()[0].GetHeader().GetValue())
+	assert.Equal(t, "application/json", denied.GetHeaders()[1].GetHeader().GetValue())
+	var envelope struct {
+		Error apierror.Error `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(denied.GetBody()), &envelope))
+	assert.Equal(t, apierror.ContextLengthExceeded, envelope.Error.Code)
+	assert.EqualValues(t, 100, envelope.Error.Details["contextWindow"])
+	assert.EqualValues(t, 54, envelope.Error.Details["promptTokens"])
+	assert.EqualValues(t, 80, envelope.Error.Details["maxTokens"])
+
+	resp = checkBody(t, strings.Repeat("word ", 40), 80, true)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), "truncated bodies are left to the model server")
+}
+
+// spentBudget reports the budget of one subscription key as spent for an hour.
+type spentBudget string
+
+func (b spentBudget) BudgetExhausted(_ context.Context, _, subscriptionKey string) (string, time.Duration) {
+	if subscriptionKey == string(b) {
+		return "token budget of 1000 tokens per 1d is exhausted", time.Hour
+	}
+	return "", 0
+}
+
+func TestCheckTokenBudget(t *testing.T) {
+	s := newServer()
+	s.SetBudgetChecker(spentBudget("models-as-a-service/premium@llm/granite"))
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.ResourceExhausted), resp.GetStatus().GetCode())
+	denied := resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, typev3.StatusCode_TooManyRequests, denied.GetStatus().GetCode())
+	headers := map[string]string{}
+	for _, h := range denied.GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "quota_exhausted", headers["x-ext-auth-reason"])
+	assert.Equal(t, "3600", headers["retry-after"])
+	assert.Equal(t, "token budget of 1000 tokens per 1d is exhausted", denied.GetBody())
+
+	s.SetBudgetChecker(spentBudget("models-as-a-service/premium@llm/other"))
+	resp = check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+}
+
+// limitedFor denies one subscription key for a minute.
+type limitedFor string
+
+func (l limitedFor) Admit(_ context.Context, _, subscriptionKey string) (time.Duration, error) {
+	if subscriptionKey == string(l) {
+		return time.Minute, nil
+	}
+	return 0, nil
+}
+
+func TestCheckRateLimited(t *testing.T) {
+	s := newServer()
+	s.SetRateLimiter(limitedFor("models-as-a-service/premium@llm/granite"))
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.ResourceExhausted), resp.GetStatus().GetCode())
+	denied := resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, typev3.StatusCode_TooManyRequests, denied.GetStatus().GetCode())
+	headers := map[string]string{}
+	for _, h := range denied.GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "rate_limited", headers["x-ext-auth-reason"])
+	assert.Equal(t, "60", headers["retry-after"])
+
+	s.SetRateLimiter(limitedFor("models-as-a-service/premium@llm/other"))
+	resp = check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+}
+
+func TestCheckConcurrencyLimit(t *testing.T) {
+	const key = "models-as-a-service/premium@llm/granite"
+	s := newServer()
+	limiter := concurrency.NewLimiter(concurrency.NewMemoryStore(), func(subscriptionKey string) int64 {
+		if subscriptionKey == key {
+			return 1
+		}
+		return 0
+	}, time.Minute)
+	s.SetConcurrencyLimiter(limiter)
+	checkID := func(id string) *authv3.CheckResponse {
+		t.Helper()
+		resp, err := s.Check(t.Context(), &authv3.CheckRequest{
+			Attributes: &authv3.AttributeContext{
+				Request: &authv3.AttributeContext_Request{
+					Http: &authv3.AttributeContext_HttpRequest{Id: id, Path: "/llm/granite/v1/chat/completions",
+						Headers: map[string]string{"authorization": "Bearer " + validKey}},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := checkID("r1")
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+	resp = checkID("r2")
+	assert.Equal(t, int32(codes.ResourceExhausted), resp.GetStatus().GetCode())
+	denied := resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, typev3.StatusCode_TooManyRequests, denied.GetStatus().GetCode())
+	headers := map[string]string{}
+	for _, h := range denied.GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "too_many_concurrent_requests", headers["x-ext-auth-reason"])
+	assert.Equal(t, "1", headers["retry-after"])
+
+	require.NoError(t, limiter.Release(t.Context(), "alice", key, "r1"))
+	resp = checkID("r2")
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), "the ended request's lease was released")
+
+	limiter.SetOrganizationLimits(func(string) string { return "acme" }, map[string]int64{"acme": 1})
+	require.NoError(t, limiter.Release(t.Context(), "alice", key, "r2"))
+	denial, err := limiter.Acquire(t.Context(), "bob", key, "r9")
+	require.NoError(t, err)
+	require.Nil(t, denial)
+	resp = checkID("r3")
+	assert.Equal(t, int32(codes.ResourceExhausted), resp.GetStatus().GetCode(), "another user holds the organization's only slot")
+	assert.Contains(t, resp.GetDeniedResponse().GetBody(), "organization acme")
+}
+
+func TestCheckAPISuffixes(t *testing.T) {
+	suffixes, err := extauthz.ParseAPISuffixes(constant.DefaultAPISuffixes)
+	require.NoError(t, err)
+	reasonOf := func(resp *authv3.CheckResponse) string {
+		for _, h := range resp.GetDeniedResponse().GetHeaders() {
+			if h.GetHeader().GetKey() == "x-ext-auth-reason" {
+				return h.GetHeader().GetValue()
+			}
+		}
+		return ""
+	}
+
+	s := newServer()
+	s.SetAPISuffixes(suffixes)
+	for _, path := range []string{"/llm/granite/v1/chat/completions", "/llm/granite/v1/embeddings?x=1", "/llm/granite/v1/models"} {
+		resp := check(t, s, path, "Bearer "+validKey)
+		assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), "%s: %s", path, resp.GetDeniedResponse().GetBody())
+	}
+	for _, path := range []string{"/llm/granite/v1/fine_tuning/jobs", "/llm/granite", "/llm/granite/v1/chat/completions/extra"} {
+		resp := check(t, s, path, "Bearer "+validKey)
+		assert.Equal(t, typev3.StatusCode_NotFound, resp.GetDeniedResponse().GetStatus().GetCode(), path)
+		assert.Equal(t, "unknown_endpoint", reasonOf(resp), path)
+	}
+
+	// An embeddings-only tier
+	sub := premiumSubscription()
+	_ = unstructured.SetNestedStringSlice(sub.Object, []string{"/v1/embeddings"}, "spec", "endpoints")
+	log := logger.Development()
+	s = extauthz.NewServer(log, fakeKeys{}, subscription.NewSelector(log, staticLister{sub}),
+		staticLister{authPolicy("premium-users", "llm", "granite")})
+	s.SetAPISuffixes(suffixes)
+	for _, path := range []string{"/llm/granite/v1/embeddings", "/llm/granite/v1/models"} {
+		resp := check(t, s, path, "Bearer "+validKey)
+		assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), "%s: %s", path, resp.GetDeniedResponse().GetBody())
+	}
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, typev3.StatusCode_Forbidden, resp.GetDeniedResponse().GetStatus().GetCode())
+	assert.Equal(t, "endpoint_not_allowed", reasonOf(resp))
+
+	// Without suffixes every path is allowed, and subscriptions are not restricted
+	s.SetAPISuffixes(nil)
+	resp = check(t, s, "/llm/granite/v1/fine_tuning/jobs", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+}
+
+func TestParseAPISuffixes(t *testing.T) {
+	suffixes, err := extauthz.ParseAPISuffixes(" /v1/embeddings, /v1/models,,/v1/embeddings")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/v1/embeddings", "/v1/models"}, suffixes)
+
+	suffixes, err = extauthz.ParseAPISuffixes("*")
+	require.NoError(t, err)
+	assert.Nil(t, suffixes)
+
+	_, err = extauthz.ParseAPISuffixes("v1/embeddings")
+	assert.Error(t, err)
+}
+
+// hookFunc is a decision hook extension.
+type hookFunc func(in hooks.Input) hooks.Output
+
+func (f hookFunc) Call(_ context.Context, in hooks.Input) (hooks.Output, error) {
+	return f(in), nil
+}
+
+func TestCheckHooks(t *testing.T) {
+	var seen []hooks.Input
+	s := newServer()
+	s.SetHooks(hooks.NewChain(logger.Development(),
+		&hooks.Hook{Name: "entitlements", Phase: hooks.PhasePre, Extension: hookFunc(func(in hooks.Input) hooks.Output {
+			seen = append(seen, in)
+			return hooks.Output{Deny: in.Model == "llm/llama", Headers: map[string]string{"X-Entitlement": "gold"}}
+		})},
+		&hooks.Hook{Name: "fraud", Phase: hooks.PhasePost, Extension: hookFunc(func(in hooks.Input) hooks.Output {
+			seen = append(seen, in)
+			return hooks.Output{Headers: map[string]string{"X-Risk-Score": "low"}}
+		})},
+	))
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+	headers := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "gold", headers["X-Entitlement"])
+	assert.Equal(t, "low", headers["X-Risk-Score"])
+	require.Len(t, seen, 2)
+	assert.Equal(t, hooks.Input{Phase: hooks.PhasePre, Endpoint: "ext_authz", Model: "llm/granite", User: "alice",
+		Groups: []string{"premium-users"}, KeyID: "key-1", Subscription: "premium", Path: "/llm/granite/v1/chat/completions"}, seen[0])
+	assert.Equal(t, hooks.PhasePost, seen[1].Phase)
+	assert.Equal(t, "premium", seen[1].Subscription)
+
+	// A pre hook vetoes llama before its MaaSAuthPolicy is checked.
+	resp = check(t, s, "/llm/llama/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.PermissionDenied), resp.GetStatus().GetCode())
+	assert.Equal(t, "hook_denied", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+
+	// Batch authorization runs the same hooks.
+	decision := s.Authorize(context.Background(), "alice", []string{"premium-users"}, extauthz.BatchEntry{Path: "/llm/llama/v1/chat/completions"})
+	assert.Equal(t, "hook_denied", decision.Reason)
+	assert.Equal(t, "batch", seen[len(seen)-1].Endpoint)
+}
+
+func TestCheckAuthorizationRules(t *testing.T) {
+	s := newServer()
+	engine, err := policy.New(logger.Development(), policy.Rule{
+		Name:       "chat-only",
+		Expression: `tier != "premium" || path.endsWith("/chat/completions")`,
+		Message:    "Premium is limited to chat completions",
+	})
+	require.NoError(t, err)
+	s.SetPolicy(engine)
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+
+	resp = check(t, s, "/llm/granite/v1/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.PermissionDenied), resp.GetStatus().GetCode())
+	assert.Equal(t, "policy_denied", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+	assert.Contains(t, resp.GetDeniedResponse().GetBody(), "Premium is limited to chat completions")
+
+	// Batch authorization evaluates the same rules.
+	decision := s.Authorize(context.Background(), "alice", []string{"premium-users"}, extauthz.BatchEntry{Path: "/llm/granite/v1/embeddings"})
+	assert.Equal(t, "policy_denied", decision.Reason)
+}
+
+func TestCheckSignedRequests(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	s := newServer()
+	s.SetSignatureVerifier(signing.NewVerifier(map[string][]byte{"alice": secret}, signing.NewMemoryCache(0), time.Minute))
+
+	const path = "/llm/granite/v1/chat/completions"
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	signed := map[string]string{
+		"authorization":    "Bearer " + validKey,
+		"x-maas-timestamp": ts,
+		"x-maas-nonce":     "0f8e7d6c5b4a39281706",
+		"x-maas-signature": signing.Sign(secret, "POST", path, ts, "0f8e7d6c5b4a39281706"),
+	}
+	checkWith := func(headers map[string]string) *authv3.CheckResponse {
+		resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+			Attributes: &authv3.AttributeContext{
+				Request: &authv3.AttributeContext_Request{
+					Http: &authv3.AttributeContext_HttpRequest{Method: "POST", Path: path, Headers: headers},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	reason := func(resp *authv3.CheckResponse) string {
+		return resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue()
+	}
+
+	resp := checkWith(map[string]string{"authorization": "Bearer " + validKey})
+	assert.Equal(t, int32(codes.Unauthenticated), resp.GetStatus().GetCode())
+	assert.Equal(t, signing.ReasonRequired, reason(resp))
+
+	resp = checkWith(signed)
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+
+	resp = checkWith(signed)
+	assert.Equal(t, int32(codes.Unauthenticated), resp.GetStatus().GetCode())
+	assert.Equal(t, typev3.StatusCode_Unauthorized, resp.GetDeniedResponse().GetStatus().GetCode())
+	assert.Equal(t, signing.ReasonReplayed, reason(resp))
+}
+
+func TestCheckDenied(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		grpcCode      codes.Code
+		httpCode      typev3.StatusCode
+		reason        string
+	}{
+		{name: "missing credentials", path: "/llm/granite/v1/chat/completions", grpcCode: codes.Unauthenticated, httpCode: typev3.StatusCode_Unauthorized, reason: "unauthenticated"},
+		{name: "invalid key", path: "/llm/granite/v1/chat/completions", authorization: "Bearer sk-oai-bogus", grpcCode: codes.Unauthenticated, httpCode: typev3.StatusCode_Unauthorized, reason: "unauthenticated"},
+		{name: "not in auth policy", path: "/llm/llama/v1/chat/completions", authorization: "Bearer " + validKey, grpcCode: codes.PermissionDenied, httpCode: typev3.StatusCode_Forbidden, reason: "unauthorized"},
+		{name: "no auth policy for model", path: "/llm/mistral/v1/chat/completions", authorization: "Bearer " + validKey, grpcCode: codes.PermissionDenied, httpCode: typev3.StatusCode_Forbidden, reason: "unauthorized"},
+		{name: "not a model route", path: "/", authorization: "Bearer " + validKey, grpcCode: codes.PermissionDenied, httpCode: typev3.StatusCode_Forbidden, reason: "model_not_found"},
+		{name: "outside key model scope", path: "/llm/granite/v1/chat/completions", authorization: "Bearer " + scopedKey, grpcCode: codes.PermissionDenied, httpCode: typev3.StatusCode_Forbidden, reason: "model_not_in_key_scope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := check(t, newServer(), tt.path, tt.authorization)
+			assert.Equal(t, int32(tt.grpcCode), resp.GetStatus().GetCode())
+			denied := resp.GetDeniedResponse()
+			require.NotNil(t, denied)
+			assert.Equal(t, tt.httpCode, denied.GetStatus().GetCode())
+			assert.Equal(t, tt.reason, denied.GetHeaders()[0].GetHeader().GetValue())
+		})
+	}
+}
+
+func TestCheckModelMaintenance(t *testing.T) {
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	selector.SetMaintenanceResolver(func(model string, _ time.Time) (bool, time.Duration) {
+		return model == "llm/granite", 10 * time.Minute
+	})
+	s := extauthz.NewServer(log, fakeKeys{}, selector, staticLister{authPolicy("premium-users", "llm", "granite")})
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.Unavailable), resp.GetStatus().GetCode())
+	denied := resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, typev3.StatusCode_ServiceUnavailable, denied.GetStatus().GetCode())
+	headers := map[string]string{}
+	for _, h := range denied.GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "model_maintenance", headers["x-ext-auth-reason"])
+	assert.Equal(t, "600", headers["retry-after"])
+}
+
+func TestCheckModelCluster(t *testing.T) {
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	available := true
+	selector.SetClusterResolver(func(string) (string, bool) { return "gpu-east", available })
+	s := extauthz.NewServer(log, fakeKeys{}, selector, staticLister{authPolicy("premium-users", "llm", "granite")})
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.NotNil(t, resp.GetOkResponse())
+	headers := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "gpu-east", headers["X-MaaS-Cluster"])
+
+	available = false
+	resp = check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.Unavailable), resp.GetStatus().GetCode())
+	denied := resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, typev3.StatusCode_ServiceUnavailable, denied.GetStatus().GetCode())
+	assert.Equal(t, "cluster_unavailable", denied.GetHeaders()[0].GetHeader().GetValue())
+}
+
+func TestCheckModelNotInSubscription(t *testing.T) {
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	s := extauthz.NewServer(log, fakeKeys{}, selector, staticLister{authPolicy("premium-users", "llm", "llama")})
+
+	resp := check(t, s, "/llm/llama/v1/completions", "Bearer "+validKey)
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, "model_not_in_subscription", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+}
+
+func TestCheckTierListener(t *testing.T) {
+	log := logger.Development()
+	sub := premiumSubscription()
+	_ = unstructured.SetNestedMap(sub.Object, map[string]any{
+		"name":      "premium",
+		"hostnames": []any{"premium.models.example.com"},
+	}, "spec", "listener")
+	selector := subscription.NewSelector(log, staticLister{sub})
+	s := extauthz.NewServer(log, fakeKeys{}, selector, staticLister{authPolicy("premium-users", "llm", "granite")})
+
+	checkHost := func(host string) *authv3.CheckResponse {
+		resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+			Attributes: &authv3.AttributeContext{
+				Request: &authv3.AttributeContext_Request{
+					Http: &authv3.AttributeContext_HttpRequest{
+						Host: host, Path: "/llm/granite/v1/chat/completions",
+						Headers: map[string]string{"authorization": "Bearer " + validKey},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := checkHost("premium.models.example.com")
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+
+	resp = checkHost("free.models.example.com")
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, "host_mismatch", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+}
+
+// indexedLister serves policies only through the model index; a full scan fails the test.
+type indexedLister struct {
+	t       *testing.T
+	indexer cache.Indexer
+}
+
+func (l indexedLister) List() ([]*unstructured.Unstructured, error) {
+	l.t.Error("List called; the model index should be used")
+	return nil, errors.New("unexpected scan")
+}
+
+func (l indexedLister) ByModel(modelNamespace, modelName string) ([]*unstructured.Unstructured, error) {
+	objs, err := l.indexer.ByIndex(authpolicy.ModelIndex, modelNamespace+"/"+modelName)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		if u, ok := o.(*unstructured.Unstructured); ok {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func TestCheckUsesModelIndex(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{authpolicy.ModelIndex: authpolicy.ModelIndexFunc})
+	require.NoError(t, indexer.Add(authPolicy("premium-users", "llm", "granite")))
+	require.NoError(t, indexer.Add(authPolicy("other-users", "llm", "llama")))
+
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	s := extauthz.NewServer(log, fakeKeys{}, selector, indexedLister{t: t, indexer: indexer})
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode())
+
+	resp = check(t, s, "/llm/llama/v1/chat/completions", "Bearer "+validKey)
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, "unauthorized", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+}
+
+func modelRef(namespace, name, priority string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if priority != "" {
+		u.SetAnnotations(map[string]string{constant.AnnotationResolutionPriority: priority})
+	}
+	return u
+}
+
+func checkExtension(t *testing.T, s *extauthz.Server, model string) *authv3.CheckResponse {
+	t.Helper()
+	resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			ContextExtensions: map[string]string{extauthz.ModelContextExtension: model},
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{
+					Path:    "/v1/chat/completions",
+					Headers: map[string]string{"authorization": "Bearer " + validKey},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return resp
+}
+
+func TestCheckResolvesBareModelName(t *testing.T) {
+	tests := []struct {
+		name          string
+		refs          staticLister
+		model         string
+		wantNamespace string
+		wantReason    string
+	}{
+		{name: "single match", refs: staticLister{modelRef("llm", "granite", "")}, model: "granite", wantNamespace: "llm"},
+		{name: "priority wins", refs: staticLister{modelRef("team-a", "granite", ""), modelRef("llm", "granite", "10")}, model: "granite", wantNamespace: "llm"},
+		{name: "qualified name skips resolution", refs: staticLister{modelRef("team-a", "granite", ""), modelRef("llm", "granite", "")}, model: "llm/granite", wantNamespace: "llm"},
+		{name: "ambiguous", refs: staticLister{modelRef("team-a", "granite", "5"), modelRef("llm", "granite", "5")}, model: "granite", wantReason: "model_ambiguous"},
+		{name: "unknown", refs: staticLister{modelRef("llm", "llama", "")}, model: "granite", wantReason: "model_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer()
+			s.SetModelResolver(models.NamespaceResolver(tt.refs))
+
+			resp := checkExtension(t, s, tt.model)
+			if tt.wantReason != "" {
+				require.NotNil(t, resp.GetDeniedResponse())
+				assert.Equal(t, tt.wantReason, resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+				return
+			}
+			require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+			headers := map[string]string{}
+			for _, h := range resp.GetOkResponse().GetHeaders() {
+				headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+			}
+			assert.Equal(t, tt.wantNamespace, headers["X-MaaS-Model-Namespace"])
+			model := resp.GetDynamicMetadata().GetFields()["model"].GetStructValue().GetFields()
+			assert.Equal(t, tt.wantNamespace, model["namespace"].GetStringValue())
+			assert.Equal(t, "granite", model["name"].GetStringValue())
+		})
+	}
+}
+
+func TestCheckResolvesModelAliases(t *testing.T) {
+	granite := modelRef("llm", "granite", "")
+	_ = unstructured.SetNestedStringSlice(granite.Object, []string{"granite-latest", "llama"}, "spec", "aliases")
+	refs := getterLister{granite, modelRef("llm", "llama", ""), modelRef("team-a", "llama", "")}
+	s := newServer()
+	s.SetModelResolver(models.NamespaceResolver(refs))
+	s.SetAliasResolver(models.AliasResolver(refs))
+
+	for _, ref := range []string{"granite-latest", "llm/granite-latest"} {
+		resp := checkExtension(t, s, ref)
+		require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+		model := resp.GetDynamicMetadata().GetFields()["model"].GetStructValue().GetFields()
+		assert.Equal(t, "llm", model["namespace"].GetStringValue(), ref)
+		assert.Equal(t, "granite", model["name"].GetStringValue(), ref)
+	}
+
+	resp := checkExtension(t, s, "llm/llama")
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, "unauthorized", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue(), "a model's own name takes precedence over aliases")
+
+	decision := s.CheckAccess(t.Context(), "alice", []string{"premium-users"}, "granite-latest", "")
+	assert.Empty(t, decision.Reason, decision.Message)
+	assert.Equal(t, "llm/granite", decision.Model)
+}
+
+func TestCheckBareModelNameWithoutResolver(t *testing.T) {
+	resp := checkExtension(t, newServer(), "granite")
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, "model_not_found", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+}
+
+func TestCheckModelSources(t *testing.T) {
+	tests := []struct {
+		name       string
+		sources    string
+		path       string
+		header     string
+		body       string
+		wantReason string
+	}{
+		{name: "path by default", path: "/llm/granite/v1/chat/completions"},
+		{name: "path ignores header", path: "/v1/chat/completions", header: "llm/granite", wantReason: "unauthorized"}, // v1/chat is taken as the model
+		{name: "header", sources: "header", path: "/v1/chat/completions", header: "llm/granite"},
+		{name: "bare name in header", sources: "header", path: "/v1/chat/completions", header: "granite"},
+		{name: "body", sources: "body", path: "/v1/chat/completions", body: `{"model": "llm/granite", "messages": []}`},
+		{name: "header agreeing with body", sources: "header,body", path: "/v1/chat/completions", header: "llm/granite", body: `{"model": "granite"}`},
+		{name: "header naming another model than body", sources: "header,body", path: "/v1/chat/completions", header: "llm/granite", body: `{"model": "llm/llama"}`, wantReason: "bad_request"},
+		{name: "header only source checked against body", sources: "header", path: "/v1/chat/completions", header: "llm/granite", body: `{"model": "llm/llama"}`, wantReason: "bad_request"},
+		{name: "body when no header", sources: "header,body", path: "/v1/chat/completions", body: `{"model": "granite"}`},
+		{name: "path as fallback", sources: "header,path", path: "/llm/granite/v1/chat/completions"},
+		{name: "body that is not JSON", sources: "body", path: "/v1/chat/completions", body: "model=granite", wantReason: "model_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer()
+			s.SetModelResolver(models.NamespaceResolver(staticLister{modelRef("llm", "granite", "")}))
+			sources, err := extauthz.ParseModelSources(tt.sources)
+			require.NoError(t, err)
+			s.SetModelSources(sources)
+
+			headers := map[string]string{"authorization": "Bearer " + validKey}
+			if tt.header != "" {
+				headers[extauthz.ModelHeader] = tt.header
+			}
+			resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+				Attributes: &authv3.AttributeContext{
+					Request: &authv3.AttributeContext_Request{
+						Http: &authv3.AttributeContext_HttpRequest{Path: tt.path, Headers: headers, Body: tt.body},
+					},
+				},
+			})
+			require.NoError(t, err)
+			if tt.wantReason != "" {
+				require.NotNil(t, resp.GetDeniedResponse())
+				assert.Equal(t, tt.wantReason, resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+				return
+			}
+			require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+			model := resp.GetDynamicMetadata().GetFields()["model"].GetStructValue().GetFields()
+			assert.Equal(t, "llm", model["namespace"].GetStringValue())
+			assert.Equal(t, "granite", model["name"].GetStringValue())
+		})
+	}
+}
+
+func TestParseModelSources(t *testing.T) {
+	sources, err := extauthz.ParseModelSources("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{extauthz.ModelSourcePath}, sources)
+
+	sources, err = extauthz.ParseModelSources(" header , body,header ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{extauthz.ModelSourceHeader, extauthz.ModelSourceBody}, sources)
+
+	sources, err = extauthz.ParseModelSources("host,path")
+	require.NoError(t, err)
+	assert.Equal(t, []string{extauthz.ModelSourceHost, extauthz.ModelSourcePath}, sources)
+
+	_, err = extauthz.ParseModelSources("query")
+	require.Error(t, err)
+}
+
+func routedModel(namespace, name, pathPrefix, hostname string) *unstructured.Unstructured {
+	u := modelRef(namespace, name, "")
+	_ = unstructured.SetNestedField(u.Object, "ExternalModel", "spec", "modelRef", "kind")
+	if pathPrefix != "" {
+		_ = unstructured.SetNestedField(u.Object, pathPrefix, "spec", "routing", "pathPrefix")
+	}
+	if hostname != "" {
+		_ = unstructured.SetNestedField(u.Object, hostname, "spec", "routing", "hostname")
+	}
+	return u
+}
+
+func TestCheckCustomRoutes(t *testing.T) {
+	aliased := routedModel("llm", "granite", "/openai/granite", "")
+	_ = unstructured.SetNestedStringSlice(aliased.Object, []string{"granite-latest"}, "spec", "aliases")
+	refs := getterLister{
+		modelRef("llm", "llama", ""),
+		aliased,
+		routedModel("team-a", "granite", "/openai", ""),
+		routedModel("llm", "granite-host", "", "granite.example.com"),
+	}
+	tests := []struct {
+		name          string
+		sources       string
+		host          string
+		path          string
+		wantNamespace string
+		wantName      string
+		wantReason    string
+	}{
+		{name: "custom prefix", path: "/openai/granite/v1/chat/completions", wantNamespace: "llm", wantName: "granite"},
+		{name: "longest prefix wins", path: "/openai/v1/chat/completions", wantNamespace: "team-a", wantName: "granite"},
+		{name: "prefix matches on segment boundaries", path: "/openai/granite-2/v1/chat/completions", wantNamespace: "team-a", wantName: "granite"},
+		{name: "alias prefix", path: "/granite-latest/v1/chat/completions", wantNamespace: "llm", wantName: "granite"},
+		{name: "namespace and name path still wins", path: "/llm/llama/v1/chat/completions", wantReason: "unauthorized"},
+		{name: "dedicated hostname", sources: "host,path", host: "Granite.example.com:443", path: "/v1/chat/completions", wantNamespace: "llm", wantName: "granite-host"},
+		{name: "unknown hostname falls back to path", sources: "host,path", host: "other.example.com", path: "/llm/granite/v1/chat/completions", wantNamespace: "llm", wantName: "granite"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.Development()
+			sub := premiumSubscription()
+			_ = unstructured.SetNestedSlice(sub.Object, []any{
+				map[string]any{"name": "granite", "namespace": "llm"},
+				map[string]any{"name": "granite", "namespace": "team-a"},
+				map[string]any{"name": "granite-host", "namespace": "llm"},
+			}, "spec", "modelRefs")
+			selector := subscription.NewSelector(log, staticLister{sub})
+			s := extauthz.NewServer(log, fakeKeys{}, selector, staticLister{authPolicy("premium-users", "llm", "granite"),
+				authPolicy("premium-users", "team-a", "granite"), authPolicy("premium-users", "llm", "granite-host")})
+			s.SetRouteResolver(models.NewRouteResolver(refs))
+			sources, err := extauthz.ParseModelSources(tt.sources)
+			require.NoError(t, err)
+			s.SetModelSources(sources)
+
+			resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+				Attributes: &authv3.AttributeContext{
+					Request: &authv3.AttributeContext_Request{
+						Http: &authv3.AttributeContext_HttpRequest{
+							Host: tt.host, Path: tt.path,
+							Headers: map[string]string{"authorization": "Bearer " + validKey},
+						},
+					},
+				},
+			})
+			require.NoError(t, err)
+			if tt.wantReason != "" {
+				require.NotNil(t, resp.GetDeniedResponse())
+				assert.Equal(t, tt.wantReason, resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+				return
+			}
+			require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+			model := resp.GetDynamicMetadata().GetFields()["model"].GetStructValue().GetFields()
+			assert.Equal(t, tt.wantNamespace, model["namespace"].GetStringValue())
+			assert.Equal(t, tt.wantName, model["name"].GetStringValue())
+		})
+	}
+}
+
+func TestCheckModelAllowList(t *testing.T) {
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	s := extauthz.NewServer(log, fakeKeys{}, selector, staticLister{authPolicy("other-users", "llm", "granite")})
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	require.NotNil(t, resp.GetDeniedResponse())
+	assert.Equal(t, "unauthorized", resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+
+	granite := modelRef("llm", "granite", "")
+	granite.SetAnnotations(map[string]string{constant.AnnotationAllowedGroups: "qa, premium-users"})
+	s.SetAllowListResolver(models.AllowListResolver(getterLister{granite}))
+
+	resp = check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	assert.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+}
+
+// getterLister adds lookups by key to staticLister, like the informer-backed MaaSModelRef lister.
+type getterLister staticLister
+
+func (g getterLister) List() ([]*unstructured.Unstructured, error) { return g, nil }
+
+func (g getterLister) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	for _, u := range g {
+		if u.GetNamespace() == namespace && u.GetName() == name {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestCheckModelErrorResponses(t *testing.T) {
+	log := logger.Development()
+	selector := subscription.NewSelector(log, staticLister{premiumSubscription()})
+	s := extauthz.NewServer(log, fakeKeys{}, selector,
+		staticLister{authPolicy("other-users", "llm", "granite"), authPolicy("premium-users", "llm", "llama")})
+
+	granite := modelRef("llm", "granite", "")
+	_ = unstructured.SetNestedField(granite.Object, "Upgrade your plan to use granite", "spec", "errorResponses", "forbidden", "message")
+	_ = unstructured.SetNestedField(granite.Object, "https://example.com/pricing", "spec", "errorResponses", "forbidden", "url")
+	llama := modelRef("llm", "llama", "")
+	_ = unstructured.SetNestedField(llama.Object, "llama is not in your plan", "spec", "errorResponses", "notFound", "message")
+	s.SetErrorResponseResolver(models.ErrorResponseResolver(getterLister{granite, llama}))
+
+	resp := check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	denied := resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, "unauthorized", denied.GetHeaders()[0].GetHeader().GetValue())
+	assert.Equal(t, "application/json", denied.GetHeaders()[1].GetHeader().GetValue())
+	assert.JSONEq(t, `{"error":{"message":"Upgrade your plan to use granite","type":"unauthorized","url":"https://example.com/pricing"}}`, denied.GetBody())
+
+	resp = check(t, s, "/llm/llama/v1/chat/completions", "Bearer "+validKey)
+	denied = resp.GetDeniedResponse()
+	require.NotNil(t, denied)
+	assert.Equal(t, "model_not_in_subscription", denied.GetHeaders()[0].GetHeader().GetValue())
+	assert.JSONEq(t, `{"error":{"message":"llama is not in your plan","type":"model_not_in_subscription"}}`, denied.GetBody())
+
+	// Authentication failures keep the fixed 401 body.
+	resp = check(t, s, "/llm/granite/v1/chat/completions", "")
+	assert.Equal(t, "Authentication required", resp.GetDeniedResponse().GetBody())
+}
+
+func TestCheckThrottled(t *testing.T) {
+	s := newServer()
+	s.SetThrottle(throttle.NewThrottler(logger.Development(), throttle.NewLocal(1, 1), 0, 0), nil)
+	checkFrom := func(source string) *authv3.CheckResponse {
+		resp, err := s.Check(context.Background(), &authv3.CheckRequest{
+			Attributes: &authv3.AttributeContext{
+				Source: &authv3.AttributeContext_Peer{Address: &corev3.Address{Address: &corev3.Address_SocketAddress{
+					SocketAddress: &corev3.SocketAddress{Address: source},
+				}}},
+				Request: &authv3.AttributeContext_Request{Http: &authv3.AttributeContext_HttpRequest{
+					Path:    "/llm/granite/v1/chat/completions",
+					Headers: map[string]string{"authorization": "Bearer " + validKey},
+				}},
+			},
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	require.Equal(t, int32(codes.OK), checkFrom("198.51.100.7").GetStatus().GetCode())
+	resp := checkFrom("198.51.100.7")
+	assert.Equal(t, int32(codes.ResourceExhausted), resp.GetStatus().GetCode())
+	assert.Equal(t, typev3.StatusCode_TooManyRequests, resp.GetDeniedResponse().GetStatus().GetCode())
+	assert.Equal(t, throttle.ReasonRateLimited, resp.GetDeniedResponse().GetHeaders()[0].GetHeader().GetValue())
+
+	// Another client has its own bucket.
+	assert.Equal(t, int32(codes.OK), checkFrom("198.51.100.8").GetStatus().GetCode())
+}
+
+func decisionCount(t *testing.T, labels map[string]string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	var total float64
+	for _, f := range families {
+		if f.GetName() != "maas_authorization_decisions_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			match := true
+			for _, l := range m.GetLabel() {
+				if want, ok := labels[l.GetName()]; ok && want != l.GetValue() {
+					match = false
+				}
+			}
+			if match {
+				total += m.GetCounter().GetValue()
+			}
+		}
+	}
+	return total
+}
+
+func TestCheckMetersDecisions(t *testing.T) {
+	s := newServer()
+	s.SetMeter(metering.New(true))
+
+	check(t, s, "/llm/granite/v1/chat/completions", "Bearer "+validKey)
+	check(t, s, "/llm/llama/v1/chat/completions", "Bearer "+validKey)
+
+	assert.InDelta(t, 1, decisionCount(t, map[string]string{
+		"endpoint": "ext_authz", "decision": "allowed", "model": "llm/granite", "subscription": "premium", "user": "alice",
+	}), 0)
+	assert.InDelta(t, 1, decisionCount(t, map[string]string{
+		"endpoint": "ext_authz", "decision": "denied", "reason": "unauthorized", "model": "llm/llama",
+	}), 0)
+}
+
+func TestCheckContinuesGatewayTrace(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), "", 100, "test", nil)
+	require.NoError(t, err)
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(tracing.Sampler(100))))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	const gatewayTrace = "4bf92f3577b34da6a3ce929d0e0e4736"
+	_, err = newServer().Check(context.Background(), &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{Path: "/llm/llama/v1/chat/completions", Headers: map[string]string{
+					"authorization": "Bearer " + validKey,
+					"traceparent":   "00-" + gatewayTrace + "-00f067aa0ba902b7-01",
+					"x-request-id":  "req-1",
+				}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+		assert.Equal(t, gatewayTrace, span.SpanContext().TraceID().String(), span.Name())
+	}
+	require.Contains(t, spans, "ext_authz.Check")
+	require.Contains(t, spans, "maas.authpolicy.lookup")
+	assert.Equal(t, spans["ext_authz.Check"].SpanContext().SpanID(), spans["maas.authpolicy.lookup"].Parent().SpanID())
+	assert.Subset(t, spans["ext_authz.Check"].Attributes(), []attribute.KeyValue{
+		attribute.String(tracing.ModelAttribute, "llm/llama"),
+		attribute.String(tracing.RequestIDAttribute, "req-1"),
+		attribute.String(tracing.ReasonAttribute, "unauthorized"),
+	})
+}
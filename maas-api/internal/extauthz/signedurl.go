@@ -0,0 +1,108 @@
+package extauthz
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// SignedURLRequest is the body of POST /v1/models/{name}/signed-urls.
+type SignedURLRequest struct {
+	// ExpiresIn is how long the signed URL is valid; default 15m, at most SIGNED_URL_MAX_TTL.
+	ExpiresIn *token.Duration `json:"expiresIn,omitempty"`
+	// Subscription to meter the requests against; empty lets selection pick one, as for an API
+	// key minted without one.
+	Subscription string `json:"subscription,omitempty"`
+}
+
+// SignedURLResponse is a minted signed URL. Token is only shown once.
+type SignedURLResponse struct {
+	Object       string    `json:"object"`
+	ID           string    `json:"id"`
+	Model        string    `json:"model"`
+	Subscription string    `json:"subscription"`
+	Parameter    string    `json:"parameter"`
+	Token        string    `json:"token"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// MintSignedURL handles POST /v1/models/{name}/signed-urls: a token that gives direct access to
+// the model as the calling user until it expires, appended to model URLs as the maas-signature
+// query parameter. The model is looked up as for GET /v1/models/{name}/access, and the token is
+// only minted when the caller could reach it; the subscription selected now is bound to it. Each
+// mint is audited with the caller as user and endpoint signed_url.
+func (h *Handler) MintSignedURL(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	userContext, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	signer := h.server.signedURLs
+	if signer == nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "signed URLs are disabled")
+		return
+	}
+
+	var req SignedURLRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "invalid request body: "+err.Error())
+			return
+		}
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != nil {
+		if ttl = req.ExpiresIn.Duration; ttl <= 0 || ttl > signer.MaxTTL() {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "expiresIn must be positive and at most "+signer.MaxTTL().String())
+			return
+		}
+	}
+
+	model := c.Param("name")
+	if namespace := c.Query("namespace"); namespace != "" {
+		model = namespace + "/" + model
+	}
+	decision := h.server.CheckAccess(c.Request.Context(), userContext.Username, userContext.Groups, model, requestedTier(c, req.Subscription))
+	if !decision.Allowed {
+		decision = h.explain(decision, h.verbosity)
+		h.logger.Debug("Signed URL denied", "username", userContext.Username, "model", model, "reason", decision.Reason)
+		status := http.StatusForbidden
+		if decision.Reason == reason.ModelNotFound {
+			status = http.StatusNotFound
+		}
+		apierror.Respond(c, status, apierror.FromReason(decision.Reason), decision.Message)
+		return
+	}
+
+	tokenValue, grant, err := signer.Mint(signedurl.Grant{
+		User:         userContext.Username,
+		Groups:       userContext.Groups,
+		Subscription: decision.Subscription,
+		Model:        decision.Model,
+	}, ttl)
+	if err != nil {
+		h.logger.Error("Failed to mint signed URL", "error", err, "username", userContext.Username)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to mint signed URL")
+		return
+	}
+	h.auditor.Decision("signed_url", decision.Model, decision.Subscription, userContext.Username, "", "")
+	h.logger.Info("Signed URL minted", "id", grant.ID, "username", userContext.Username, "model", decision.Model,
+		"expiresAt", time.Unix(grant.ExpiresAt, 0).UTC())
+	c.JSON(http.StatusCreated, SignedURLResponse{
+		Object:       "model.signed_url",
+		ID:           grant.ID,
+		Model:        decision.Model,
+		Subscription: decision.Subscription,
+		Parameter:    signedurl.QueryParameter,
+		Token:        tokenValue,
+		ExpiresAt:    time.Unix(grant.ExpiresAt, 0).UTC(),
+	})
+}
@@ -0,0 +1,94 @@
+package extauthz_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func newSigner(t *testing.T) *signedurl.Signer {
+	t.Helper()
+	signer, err := signedurl.NewSigner(bytes.Repeat([]byte("s"), signedurl.MinSecretLength), time.Hour)
+	require.NoError(t, err)
+	return signer
+}
+
+func mintSignedURL(t *testing.T, s *extauthz.Server, query, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/models/:name/signed-urls", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"premium-users"}})
+	}, extauthz.NewHandler(logger.Development(), s).MintSignedURL)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/models/"+query, bytes.NewBufferString(body)))
+	return w
+}
+
+func TestMintSignedURL(t *testing.T) {
+	s := newServer()
+	assert.Equal(t, http.StatusNotFound, mintSignedURL(t, s, "granite/signed-urls?namespace=llm", "").Code, "disabled without a signer")
+
+	s.SetSignedURLSigner(newSigner(t))
+	w := mintSignedURL(t, s, "granite/signed-urls?namespace=llm", `{"expiresIn": "10m"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp extauthz.SignedURLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "model.signed_url", resp.Object)
+	assert.Equal(t, "llm/granite", resp.Model)
+	assert.Equal(t, "premium", resp.Subscription)
+	assert.Equal(t, signedurl.QueryParameter, resp.Parameter)
+	assert.True(t, resp.ExpiresAt.After(time.Now().Add(9*time.Minute)))
+	assert.True(t, resp.ExpiresAt.Before(time.Now().Add(11*time.Minute)))
+
+	assert.Equal(t, http.StatusForbidden, mintSignedURL(t, s, "llama/signed-urls?namespace=llm", "").Code,
+		"only models the caller can reach")
+	assert.Equal(t, http.StatusBadRequest, mintSignedURL(t, s, "granite/signed-urls?namespace=llm", `{"expiresIn": "2h"}`).Code,
+		"at most the maximum validity")
+}
+
+func TestCheckSignedURL(t *testing.T) {
+	s := newServer()
+	signer := newSigner(t)
+	s.SetSignedURLSigner(signer)
+	mint := func(model string, ttl time.Duration) string {
+		tokenValue, _, err := signer.Mint(signedurl.Grant{User: "alice", Groups: []string{"premium-users"}, Subscription: "premium", Model: model}, ttl)
+		require.NoError(t, err)
+		return "?" + signedurl.QueryParameter + "=" + url.QueryEscape(tokenValue)
+	}
+
+	query := mint("llm/granite", time.Minute)
+	resp := check(t, s, "/llm/granite/v1/chat/completions"+query, "")
+	require.Equal(t, int32(codes.OK), resp.GetStatus().GetCode(), resp.GetDeniedResponse().GetBody())
+	headers := map[string]string{}
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	assert.Equal(t, "alice", headers["X-MaaS-Username"], "requests are attributed to the issuer")
+	assert.Contains(t, headers["X-MaaS-Key-Id"], signedurl.IDPrefix)
+	assert.Equal(t, []string{signedurl.QueryParameter}, resp.GetOkResponse().GetQueryParametersToRemove())
+
+	other := check(t, s, "/llm/llama/v1/chat/completions"+query, "")
+	assert.Equal(t, int32(codes.PermissionDenied), other.GetStatus().GetCode(), "a signed URL only reaches its model")
+
+	expired := check(t, s, "/llm/granite/v1/chat/completions"+mint("llm/granite", time.Nanosecond), "")
+	assert.Equal(t, int32(codes.Unauthenticated), expired.GetStatus().GetCode())
+
+	tampered := check(t, s, "/llm/granite/v1/chat/completions"+query+"x", "")
+	assert.Equal(t, int32(codes.Unauthenticated), tampered.GetStatus().GetCode())
+}
@@ -0,0 +1,289 @@
+// Package extproc implements Envoy's external processing gRPC contract for the shared
+// OpenAI-style route. Clients send every model to one path, such as /v1/chat/completions, with
+// the model named in the JSON body. The processor resolves that name to a MaaSModelRef and
+// rewrites the request onto the model's own route (the path of its status.endpoint, e.g.
+// /<namespace>/<name>/v1/...), so the model's AuthPolicy, TokenRateLimitPolicy and backend apply
+// exactly as for path-style requests.
+package extproc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	filterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_proc/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// ModelHeader carries the resolved model ("namespace/name") to the filters after the processor.
+// Any value sent by the client is removed first.
+const ModelHeader = "x-maas-model"
+
+// ModelResolver resolves a bare model name to the namespace of its MaaSModelRef. It returns
+// models.ErrModelNotFound or a *models.AmbiguousModelError when there is no single match.
+type ModelResolver func(name string) (string, error)
+
+// AliasResolver resolves a model named by one of its aliases, with or without a namespace, to the
+// namespace and name of its MaaSModelRef. It is implemented by models.AliasResolver.
+type AliasResolver func(namespace, name string) (string, string, bool)
+
+// Server answers ext_proc streams for the shared route.
+type Server struct {
+	extprocv3.UnimplementedExternalProcessorServer
+
+	lister  models.MaaSModelRefLister
+	resolve ModelResolver
+	aliases AliasResolver
+	paths   []string
+	logger  *logger.Logger
+}
+
+// NewServer creates an ext_proc server that routes requests to the MaaSModelRefs in lister.
+// paths is a comma-separated list of the shared route's paths (constant.DefaultExtProcPaths when
+// empty); requests for other paths pass through unchanged.
+func NewServer(log *logger.Logger, lister models.MaaSModelRefLister, resolve ModelResolver, paths string) *Server {
+	if log == nil {
+		log = logger.Production()
+	}
+	if strings.TrimSpace(paths) == "" {
+		paths = constant.DefaultExtProcPaths
+	}
+	s := &Server{lister: lister, resolve: resolve, logger: log}
+	for path := range strings.SplitSeq(paths, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			s.paths = append(s.paths, path)
+		}
+	}
+	return s
+}
+
+// SetAliasResolver lets request bodies name models by their aliases.
+func (s *Server) SetAliasResolver(aliases AliasResolver) {
+	s.aliases = aliases
+}
+
+// Process implements extprocv3.ExternalProcessorServer. The filter must send request headers and
+// the buffered request body; the processor turns off body buffering for other paths through a
+// mode override, so the filter must also allow_mode_override.
+func (s *Server) Process(stream extprocv3.ExternalProcessor_ProcessServer) error {
+	var path, requestID string
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var resp *extprocv3.ProcessingResponse
+		switch r := req.GetRequest().(type) {
+		case *extprocv3.ProcessingRequest_RequestHeaders:
+			path = headerValue(r.RequestHeaders.GetHeaders(), ":path")
+			requestID = headerValue(r.RequestHeaders.GetHeaders(), "x-request-id")
+			resp = s.onRequestHeaders(path)
+		case *extprocv3.ProcessingRequest_RequestBody:
+			resp = s.onRequestBody(path, requestID, r.RequestBody.GetBody())
+		case *extprocv3.ProcessingRequest_RequestTrailers:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestTrailers{RequestTrailers: &extprocv3.TrailersResponse{}}}
+		case *extprocv3.ProcessingRequest_ResponseHeaders:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseHeaders{ResponseHeaders: &extprocv3.HeadersResponse{}}}
+		case *extprocv3.ProcessingRequest_ResponseBody:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseBody{ResponseBody: &extprocv3.BodyResponse{}}}
+		case *extprocv3.ProcessingRequest_ResponseTrailers:
+			resp = &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_ResponseTrailers{ResponseTrailers: &extprocv3.TrailersResponse{}}}
+		default:
+			return fmt.Errorf("unexpected ext_proc request %T", r)
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// onRequestHeaders strips a client-supplied model header and, for paths outside the shared
+// route, asks Envoy not to send the body.
+func (s *Server) onRequestHeaders(path string) *extprocv3.ProcessingResponse {
+	resp := &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_RequestHeaders{RequestHeaders: &extprocv3.HeadersResponse{
+			Response: &extprocv3.CommonResponse{
+				HeaderMutation: &extprocv3.HeaderMutation{RemoveHeaders: []string{ModelHeader}},
+			},
+		}},
+	}
+	if !s.shared(path) {
+		resp.ModeOverride = &filterv3.ProcessingMode{
+			RequestBodyMode:    filterv3.ProcessingMode_NONE,
+			ResponseHeaderMode: filterv3.ProcessingMode_SKIP,
+		}
+	}
+	return resp
+}
+
+// onRequestBody rewrites a shared-route request onto the route of the model named in its body,
+// after checking that the model's class serves the path and that the body fits the endpoint.
+func (s *Server) onRequestBody(path, requestID string, body []byte) *extprocv3.ProcessingResponse {
+	if !s.shared(path) {
+		return &extprocv3.ProcessingResponse{Response: &extprocv3.ProcessingResponse_RequestBody{RequestBody: &extprocv3.BodyResponse{}}}
+	}
+
+	var payload struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || strings.TrimSpace(payload.Model) == "" {
+		return immediate(requestID, typev3.StatusCode_BadRequest, reason.MissingModel, "request body must be JSON with a model field")
+	}
+	ref, err := s.model(strings.TrimSpace(payload.Model))
+	var ambiguous *models.AmbiguousModelError
+	switch {
+	case errors.Is(err, models.ErrModelNotFound):
+		return immediate(requestID, typev3.StatusCode_NotFound, reason.ModelNotFound,
+			fmt.Sprintf("The model %q does not exist", payload.Model))
+	case errors.As(err, &ambiguous):
+		return immediate(requestID, typev3.StatusCode_BadRequest, reason.ModelAmbiguous, err.Error())
+	case err != nil:
+		s.logger.Error("Model resolution failed", "error", err, "model", payload.Model)
+		return immediate(requestID, typev3.StatusCode_InternalServerError, reason.InternalError, "model resolution failed")
+	}
+
+	model := ref.GetNamespace() + "/" + ref.GetName()
+	class := models.ClassOf(ref)
+	if !models.ServesPath(class, path) {
+		return immediate(requestID, typev3.StatusCode_NotFound, reason.UnsupportedEndpoint,
+			fmt.Sprintf("The model %q is a %s model and does not serve %s", payload.Model, class, path))
+	}
+	if err := models.ValidateRequest(class, path, body); err != nil {
+		return immediate(requestID, typev3.StatusCode_BadRequest, reason.BadRequest, err.Error())
+	}
+	target := routePrefix(ref) + path
+	s.logger.Debug("Routing shared request to model", "model", model, "path", target)
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_RequestBody{RequestBody: &extprocv3.BodyResponse{
+			Response: &extprocv3.CommonResponse{
+				HeaderMutation: &extprocv3.HeaderMutation{SetHeaders: []*corev3.HeaderValueOption{
+					setHeader(":path", target),
+					setHeader(ModelHeader, model),
+				}},
+				ClearRouteCache: true,
+			},
+		}},
+	}
+}
+
+// model returns the MaaSModelRef for a model reference, which may be qualified or a bare name, and
+// may name the model by one of its aliases.
+func (s *Server) model(ref string) (*unstructured.Unstructured, error) {
+	ns, name, qualified := strings.Cut(ref, "/")
+	if !qualified {
+		ns, name = "", ref
+	}
+	if s.aliases != nil {
+		if aliasNS, aliasName, ok := s.aliases(ns, name); ok {
+			ns, name = aliasNS, aliasName
+		}
+	}
+	if ns == "" {
+		if s.resolve == nil {
+			return nil, models.ErrModelNotFound
+		}
+		var err error
+		if ns, err = s.resolve(name); err != nil {
+			return nil, err
+		}
+	}
+	if ns == "" || name == "" || strings.Contains(name, "/") || s.lister == nil {
+		return nil, models.ErrModelNotFound
+	}
+	if getter, ok := s.lister.(models.MaaSModelRefGetter); ok {
+		u, err := getter.Get(ns, name)
+		if err != nil {
+			return nil, err
+		}
+		if u == nil {
+			return nil, models.ErrModelNotFound
+		}
+		return u, nil
+	}
+	items, err := s.lister.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range items {
+		if u.GetNamespace() == ns && u.GetName() == name {
+			return u, nil
+		}
+	}
+	return nil, models.ErrModelNotFound
+}
+
+// routePrefix is the path the model's own route matches: the path of its status.endpoint, or
+// /<namespace>/<name> before the controller has reported one.
+func routePrefix(ref *unstructured.Unstructured) string {
+	endpoint, _, _ := unstructured.NestedString(ref.Object, "status", "endpoint")
+	if u, err := url.Parse(endpoint); err == nil && strings.Trim(u.Path, "/") != "" {
+		return "/" + strings.Trim(u.Path, "/")
+	}
+	return "/" + ref.GetNamespace() + "/" + ref.GetName()
+}
+
+// shared reports whether path, without its query, is served through the shared route.
+func (s *Server) shared(path string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	for _, p := range s.paths {
+		if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+// immediate answers the client directly with the maas-api error envelope. The denial reason is
+// also in its details and in x-ext-proc-reason.
+func immediate(requestID string, status typev3.StatusCode, code, message string) *extprocv3.ProcessingResponse {
+	body := apierror.New(apierror.FromReason(code), message).
+		WithDetails(map[string]any{"reason": code}).
+		WithRequestID(requestID).
+		JSON()
+	return &extprocv3.ProcessingResponse{
+		Response: &extprocv3.ProcessingResponse_ImmediateResponse{ImmediateResponse: &extprocv3.ImmediateResponse{
+			Status: &typev3.HttpStatus{Code: status},
+			Headers: &extprocv3.HeaderMutation{SetHeaders: []*corev3.HeaderValueOption{
+				setHeader("content-type", "application/json"),
+				setHeader("x-ext-proc-reason", code),
+			}},
+			Body:    body,
+			Details: "maas_" + code,
+		}},
+	}
+}
+
+func headerValue(headers *corev3.HeaderMap, key string) string {
+	for _, h := range headers.GetHeaders() {
+		if h.GetKey() == key {
+			if raw := h.GetRawValue(); len(raw) > 0 {
+				return string(raw)
+			}
+			return h.GetValue()
+		}
+	}
+	return ""
+}
+
+func setHeader(key, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header:       &corev3.HeaderValue{Key: key, RawValue: []byte(value)},
+		AppendAction: corev3.HeaderValueOption_OVERWRITE_IF_EXISTS_OR_ADD,
+	}
+}
@@ -0,0 +1,203 @@
+package extproc_test
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	filterv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/ext_proc/v3"
+	extprocv3 "github.com/envoyproxy/go-control-plane/envoy/service/ext_proc/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extproc"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+type staticLister []*unstructured.Unstructured
+
+func (s staticLister) List() ([]*unstructured.Unstructured, error) { return s, nil }
+
+func modelRef(namespace, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+// fakeStream replays requests and records the responses.
+type fakeStream struct {
+	grpc.ServerStream
+
+	requests  []*extprocv3.ProcessingRequest
+	responses []*extprocv3.ProcessingResponse
+}
+
+func (f *fakeStream) Recv() (*extprocv3.ProcessingRequest, error) {
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeStream) Send(resp *extprocv3.ProcessingResponse) error {
+	f.responses = append(f.responses, resp)
+	return nil
+}
+
+func process(t *testing.T, s *extproc.Server, path, body string) []*extprocv3.ProcessingResponse {
+	t.Helper()
+	stream := &fakeStream{requests: []*extprocv3.ProcessingRequest{
+		{Request: &extprocv3.ProcessingRequest_RequestHeaders{RequestHeaders: &extprocv3.HttpHeaders{
+			Headers: &corev3.HeaderMap{Headers: []*corev3.HeaderValue{
+				{Key: ":path", RawValue: []byte(path)},
+				{Key: extproc.ModelHeader, RawValue: []byte("spoofed/model")},
+			}},
+		}}},
+		{Request: &extprocv3.ProcessingRequest_RequestBody{RequestBody: &extprocv3.HttpBody{Body: []byte(body), EndOfStream: true}}},
+	}}
+	require.NoError(t, s.Process(stream))
+	require.Len(t, stream.responses, 2)
+	return stream.responses
+}
+
+func newServer() *extproc.Server {
+	external := modelRef("llm", "gpt-4o")
+	_ = unstructured.SetNestedField(external.Object, "https://maas.example.com/gpt-4o", "status", "endpoint")
+	granite := modelRef("llm", "granite")
+	_ = unstructured.SetNestedStringSlice(granite.Object, []string{"granite-latest"}, "spec", "aliases")
+	lister := staticLister{granite, modelRef("llm", "llama"), modelRef("team-a", "llama"), external}
+	s := extproc.NewServer(logger.Development(), lister, models.NamespaceResolver(lister), "")
+	s.SetAliasResolver(models.AliasResolver(lister))
+	return s
+}
+
+func headerMutations(resp *extprocv3.ProcessingResponse) map[string]string {
+	set := map[string]string{}
+	for _, h := range resp.GetRequestBody().GetResponse().GetHeaderMutation().GetSetHeaders() {
+		set[h.GetHeader().GetKey()] = string(h.GetHeader().GetRawValue())
+	}
+	return set
+}
+
+func TestProcessRoutesByBodyModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		model     string
+		wantPath  string
+		wantModel string
+	}{
+		{name: "qualified", path: "/v1/chat/completions", model: "llm/granite", wantPath: "/llm/granite/v1/chat/completions", wantModel: "llm/granite"},
+		{name: "bare name", path: "/v1/completions", model: "granite", wantPath: "/llm/granite/v1/completions", wantModel: "llm/granite"},
+		{name: "endpoint path", path: "/v1/chat/completions", model: "gpt-4o", wantPath: "/gpt-4o/v1/chat/completions", wantModel: "llm/gpt-4o"},
+		{name: "alias", path: "/v1/chat/completions", model: "granite-latest", wantPath: "/llm/granite/v1/chat/completions", wantModel: "llm/granite"},
+		{name: "qualified alias", path: "/v1/chat/completions", model: "llm/granite-latest", wantPath: "/llm/granite/v1/chat/completions", wantModel: "llm/granite"},
+		{name: "query kept", path: "/v1/embeddings?x=1", model: "llm/granite", wantPath: "/llm/granite/v1/embeddings?x=1", wantModel: "llm/granite"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := process(t, newServer(), tt.path, `{"model": "`+tt.model+`", "messages": []}`)
+
+			headers := responses[0].GetRequestHeaders().GetResponse().GetHeaderMutation()
+			assert.Equal(t, []string{extproc.ModelHeader}, headers.GetRemoveHeaders(), "client-supplied model header must be dropped")
+			assert.Nil(t, responses[0].GetModeOverride())
+
+			set := headerMutations(responses[1])
+			assert.Equal(t, tt.wantPath, set[":path"])
+			assert.Equal(t, tt.wantModel, set[extproc.ModelHeader])
+			assert.True(t, responses[1].GetRequestBody().GetResponse().GetClearRouteCache())
+		})
+	}
+}
+
+func TestProcessRejectsUnroutableModels(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus typev3.StatusCode
+		wantCode   string
+		wantReason string
+	}{
+		{name: "unknown", body: `{"model": "llm/mistral"}`, wantStatus: typev3.StatusCode_NotFound, wantCode: "MODEL_NOT_FOUND", wantReason: "model_not_found"},
+		{name: "unknown bare name", body: `{"model": "mistral"}`, wantStatus: typev3.StatusCode_NotFound, wantCode: "MODEL_NOT_FOUND", wantReason: "model_not_found"},
+		{name: "ambiguous", body: `{"model": "llama"}`, wantStatus: typev3.StatusCode_BadRequest, wantCode: "INVALID_REQUEST", wantReason: "model_ambiguous"},
+		{name: "no model", body: `{"messages": []}`, wantStatus: typev3.StatusCode_BadRequest, wantCode: "INVALID_REQUEST", wantReason: "missing_model"},
+		{name: "not JSON", body: `model=granite`, wantStatus: typev3.StatusCode_BadRequest, wantCode: "INVALID_REQUEST", wantReason: "missing_model"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := process(t, newServer(), "/v1/chat/completions", tt.body)
+			immediate := responses[1].GetImmediateResponse()
+			require.NotNil(t, immediate)
+			assert.Equal(t, tt.wantStatus, immediate.GetStatus().GetCode())
+
+			var body struct {
+				Error struct {
+					Code    string `json:"code"`
+					Details struct {
+						Reason string `json:"reason"`
+					} `json:"details"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(immediate.GetBody(), &body))
+			assert.Equal(t, tt.wantCode, body.Error.Code)
+			assert.Equal(t, tt.wantReason, body.Error.Details.Reason)
+		})
+	}
+}
+
+func TestProcessPassesThroughOtherPaths(t *testing.T) {
+	responses := process(t, newServer(), "/llm/granite/v1/chat/completions", `{"model": "llm/llama"}`)
+
+	override := responses[0].GetModeOverride()
+	require.NotNil(t, override)
+	assert.Equal(t, filterv3.ProcessingMode_NONE, override.GetRequestBodyMode())
+	assert.Empty(t, headerMutations(responses[1]))
+	assert.Nil(t, responses[1].GetImmediateResponse())
+}
+
+func TestProcessEnforcesModelClass(t *testing.T) {
+	embed := modelRef("llm", "embed")
+	_ = unstructured.SetNestedField(embed.Object, models.ClassEmbedding, "spec", "class")
+	rerank := modelRef("llm", "rerank")
+	_ = unstructured.SetNestedField(rerank.Object, models.ClassReranker, "spec", "class")
+	lister := staticLister{embed, rerank, modelRef("llm", "granite")}
+	s := extproc.NewServer(logger.Development(), lister, models.NamespaceResolver(lister), "/v1/chat/completions,/v1/embeddings,/v1/rerank")
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus typev3.StatusCode
+		wantReason string
+	}{
+		{name: "embedding", path: "/v1/embeddings", body: `{"model": "embed", "input": ["hello"]}`},
+		{name: "rerank", path: "/v1/rerank", body: `{"model": "rerank", "query": "q", "documents": ["a", "b"]}`},
+		{name: "unclassified model is not validated", path: "/v1/embeddings", body: `{"model": "granite"}`},
+		{name: "chat on an embedding model", path: "/v1/chat/completions", body: `{"model": "embed", "messages": [{"role": "user", "content": "hi"}]}`, wantStatus: typev3.StatusCode_NotFound, wantReason: "unsupported_endpoint"},
+		{name: "embedding without input", path: "/v1/embeddings", body: `{"model": "embed", "input": ""}`, wantStatus: typev3.StatusCode_BadRequest, wantReason: "bad_request"},
+		{name: "rerank without documents", path: "/v1/rerank", body: `{"model": "rerank", "query": "q"}`, wantStatus: typev3.StatusCode_BadRequest, wantReason: "bad_request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := process(t, s, tt.path, tt.body)
+			immediate := responses[1].GetImmediateResponse()
+			if tt.wantReason == "" {
+				require.Nil(t, immediate, "request must be routed")
+				assert.NotEmpty(t, headerMutations(responses[1])[":path"])
+				return
+			}
+			require.NotNil(t, immediate)
+			assert.Equal(t, tt.wantStatus, immediate.GetStatus().GetCode())
+			assert.Equal(t, "maas_"+tt.wantReason, immediate.GetDetails())
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Package federation merges the MaaSModelRefs of remote clusters into the local model index, so
+// that one maas-api authorizes requests to models hosted on several clusters. Remote clusters are
+// read either through a kubeconfig or through the /internal/v1/federation/models endpoint of the
+// maas-api running there.
+package federation
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+var (
+	clusterAvailableDesc = prometheus.NewDesc("maas_federation_cluster_available",
+		"Whether a federated cluster's models are synced and fresh (1) or not (0), by cluster.", []string{"cluster"}, nil)
+	clusterModelsDesc = prometheus.NewDesc("maas_federation_models",
+		"MaaSModelRefs cached from a federated cluster, by cluster.", []string{"cluster"}, nil)
+)
+
+// Lister is a MaaSModelRef lister with every lookup the rest of maas-api uses.
+type Lister interface {
+	models.MaaSModelRefLister
+	models.MaaSModelRefGetter
+	models.MaaSModelRefNamespaceLister
+	models.MaaSModelRefNameLister
+	models.MaaSModelRefAliasLister
+	models.MaaSModelRefRouteLister
+}
+
+// Remote is a cluster whose models are federated, read through exactly one of a kubeconfig or the
+// base URL of its maas-api.
+type Remote struct {
+	Name       string
+	Kubeconfig string
+	Endpoint   string
+}
+
+// ParseRemotes parses comma-separated "name=kubeconfig path" and "name=maas-api URL" lists, e.g.
+// "gpu-east=/etc/federation/gpu-east" and "gpu-west=https://maas-api.gpu-west.example.com". The
+// remotes keep their order, kubeconfig remotes first; it is the order in which a model present in
+// several clusters is resolved.
+func ParseRemotes(kubeconfigs, endpoints string) ([]Remote, error) {
+	var remotes []Remote
+	add := func(list string, set func(r *Remote, value string)) error {
+		for entry := range strings.SplitSeq(list, ",") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			name, value, ok := strings.Cut(entry, "=")
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			if !ok || value == "" {
+				return fmt.Errorf("invalid remote cluster %q: want name=value", entry)
+			}
+			if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+				return fmt.Errorf("invalid remote cluster name %q: %s", name, strings.Join(errs, "; "))
+			}
+			if slices.ContainsFunc(remotes, func(r Remote) bool { return r.Name == name }) {
+				return fmt.Errorf("remote cluster %q is configured twice", name)
+			}
+			r := Remote{Name: name}
+			set(&r, value)
+			remotes = append(remotes, r)
+		}
+		return nil
+	}
+	if err := add(kubeconfigs, func(r *Remote, path string) { r.Kubeconfig = path }); err != nil {
+		return nil, err
+	}
+	if err := add(endpoints, func(r *Remote, endpoint string) { r.Endpoint = strings.TrimSuffix(endpoint, "/") }); err != nil {
+		return nil, err
+	}
+	for _, r := range remotes {
+		if r.Endpoint == "" {
+			continue
+		}
+		if u, err := url.Parse(r.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("remote cluster %s: %q is not an http(s) URL", r.Name, r.Endpoint)
+		}
+	}
+	return remotes, nil
+}
+
+// OriginTransform returns an informer transform that records cluster as the origin of each
+// MaaSModelRef (see models.OriginCluster).
+func OriginTransform(cluster string) cache.TransformFunc {
+	return func(obj any) (any, error) {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			setOrigin(u, cluster)
+		}
+		return obj, nil
+	}
+}
+
+func setOrigin(u *unstructured.Unstructured, cluster string) {
+	annotations := u.GetAnnotations()
+	if annotations[constant.AnnotationOriginCluster] == cluster {
+		return
+	}
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[constant.AnnotationOriginCluster] = cluster
+	u.SetAnnotations(annotations)
+}
+
+// Index merges the local MaaSModelRefs with those of the remote clusters. Lookups see the local
+// cluster first, then the available remotes in their configured order, then the unavailable ones;
+// a model (namespace/name) present in several clusters resolves to the first of them, so that it
+// fails over to another cluster hosting it.
+type Index struct {
+	localName string
+	local     Lister
+	remotes   []*remote
+	scope     models.Scope
+	logger    *logger.Logger
+}
+
+// NewIndex returns an index of the local cluster named localName, listed by local, and of remotes.
+// Only the MaaSModelRefs scope serves are taken from the remotes. Remote models older than three
+// refresh intervals are stale: their cluster is reported unavailable.
+func NewIndex(log *logger.Logger, localName string, local Lister, remotes []Remote, scope models.Scope,
+	token string, refresh time.Duration,
+) (*Index, error) {
+	if log == nil {
+		log = logger.Production()
+	}
+	idx := &Index{localName: localName, local: local, scope: scope, logger: log}
+	for _, r := range remotes {
+		var (
+			src *remote
+			err error
+		)
+		if r.Kubeconfig != "" {
+			src, err = newKubeconfigRemote(log, r, scope, refresh)
+		} else {
+			src = newEndpointRemote(log, r, scope, token, refresh)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("remote cluster %s: %w", r.Name, err)
+		}
+		idx.remotes = append(idx.remotes, src)
+	}
+	return idx, nil
+}
+
+// Start reads the remote clusters until stopCh closes. It does not wait for them: a remote that
+// has not synced is unavailable, and its models are unknown until it does.
+func (i *Index) Start(stopCh <-chan struct{}) {
+	for _, r := range i.remotes {
+		go r.run(stopCh)
+	}
+}
+
+// AddEventHandler notifies handler of changes to the remote clusters' MaaSModelRefs the scope
+// serves.
+func (i *Index) AddEventHandler(handler cache.ResourceEventHandler) error {
+	handler = cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj any) bool {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			u, ok := obj.(*unstructured.Unstructured)
+			return ok && i.scope.Serves(u)
+		},
+		Handler: handler,
+	}
+	for _, r := range i.remotes {
+		if err := r.addEventHandler(handler); err != nil {
+			return fmt.Errorf("failed to watch MaaSModelRefs of cluster %s: %w", r.name, err)
+		}
+	}
+	return nil
+}
+
+// Available reports, per remote cluster, whether its models are synced and fresh.
+func (i *Index) Available() map[string]bool {
+	now := time.Now()
+	out := make(map[string]bool, len(i.remotes))
+	for _, r := range i.remotes {
+		out[r.name] = r.available(now)
+	}
+	return out
+}
+
+// Cluster returns the cluster hosting a model ("namespace/name") and whether it is available.
+// Unknown models are reported available, so that they are denied as not found.
+func (i *Index) Cluster(model string) (string, bool) {
+	ns, name, _ := strings.Cut(model, "/")
+	u, err := i.Get(ns, name)
+	if err != nil || u == nil {
+		return "", true
+	}
+	cluster := models.OriginCluster(u)
+	if cluster == "" || cluster == i.localName {
+		return i.localName, true
+	}
+	for _, r := range i.remotes {
+		if r.name == cluster {
+			return cluster, r.available(time.Now())
+		}
+	}
+	return cluster, false
+}
+
+// sources returns the listers in lookup order.
+func (i *Index) sources() []Lister {
+	now := time.Now()
+	out := make([]Lister, 0, len(i.remotes)+1)
+	out = append(out, i.local)
+	var unavailable []Lister
+	for _, r := range i.remotes {
+		if r.available(now) {
+			out = append(out, r.lister)
+		} else {
+			unavailable = append(unavailable, r.lister)
+		}
+	}
+	return append(out, unavailable...)
+}
+
+// List returns the MaaSModelRefs of every cluster, implementing models.MaaSModelRefLister.
+func (i *Index) List() ([]*unstructured.Unstructured, error) {
+	return i.merge(Lister.List)
+}
+
+// Get looks up one MaaSModelRef by key, implementing models.MaaSModelRefGetter.
+func (i *Index) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	for _, l := range i.sources() {
+		u, err := l.Get(namespace, name)
+		if err != nil || u != nil {
+			return u, err
+		}
+	}
+	return nil, nil
+}
+
+// ByNamespace implements models.MaaSModelRefNamespaceLister.
+func (i *Index) ByNamespace(namespace string) ([]*unstructured.Unstructured, error) {
+	return i.merge(func(l Lister) ([]*unstructured.Unstructured, error) { return l.ByNamespace(namespace) })
+}
+
+// ByName implements models.MaaSModelRefNameLister.
+func (i *Index) ByName(name string) ([]*unstructured.Unstructured, error) {
+	return i.merge(func(l Lister) ([]*unstructured.Unstructured, error) { return l.ByName(name) })
+}
+
+// ByAlias implements models.MaaSModelRefAliasLister.
+func (i *Index) ByAlias(alias string) ([]*unstructured.Unstructured, error) {
+	return i.merge(func(l Lister) ([]*unstructured.Unstructured, error) { return l.ByAlias(alias) })
+}
+
+// ByRoute implements models.MaaSModelRefRouteLister.
+func (i *Index) ByRoute(key string) ([]*unstructured.Unstructured, error) {
+	return i.merge(func(l Lister) ([]*unstructured.Unstructured, error) { return l.ByRoute(key) })
+}
+
+// merge runs list on every source and keeps the first MaaSModelRef of each namespace/name.
+func (i *Index) merge(list func(Lister) ([]*unstructured.Unstructured, error)) ([]*unstructured.Unstructured, error) {
+	var out []*unstructured.Unstructured
+	seen := make(map[string]bool)
+	for _, l := range i.sources() {
+		items, err := list(l)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range items {
+			key := u.GetNamespace() + "/" + u.GetName()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// Describe implements prometheus.Collector.
+func (i *Index) Describe(ch chan<- *prometheus.Desc) {
+	ch <- clusterAvailableDesc
+	ch <- clusterModelsDesc
+}
+
+// Collect implements prometheus.Collector, reporting each remote cluster.
+func (i *Index) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	for _, r := range i.remotes {
+		available := 0.0
+		if r.available(now) {
+			available = 1
+		}
+		ch <- prometheus.MustNewConstMetric(clusterAvailableDesc, prometheus.GaugeValue, available, r.name)
+		ch <- prometheus.MustNewConstMetric(clusterModelsDesc, prometheus.GaugeValue, float64(len(r.indexer.ListKeys())), r.name)
+	}
+}
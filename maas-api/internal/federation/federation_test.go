@@ -0,0 +1,199 @@
+package federation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func modelRef(namespace, name, resourceVersion string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+	u.SetKind("MaaSModelRef")
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetResourceVersion(resourceVersion)
+	return u
+}
+
+// newLister returns a lister over an indexer holding objs.
+func newLister(t *testing.T, objs ...*unstructured.Unstructured) *indexLister {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, modelIndexers())
+	for _, u := range objs {
+		require.NoError(t, indexer.Add(u))
+	}
+	return &indexLister{indexer: indexer}
+}
+
+// serveModels runs a maas-api serving the models of lister as cluster, answering token.
+func serveModels(t *testing.T, lister models.MaaSModelRefLister, cluster, token string) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET(ModelsPath, NewHandler(nil, lister, cluster, token).ListModels)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+type recorder struct{ events []string }
+
+func (r *recorder) OnAdd(obj any, _ bool) {
+	r.events = append(r.events, "add "+obj.(*unstructured.Unstructured).GetName())
+}
+
+func (r *recorder) OnUpdate(_, obj any) {
+	r.events = append(r.events, "update "+obj.(*unstructured.Unstructured).GetName())
+}
+
+func (r *recorder) OnDelete(obj any) {
+	r.events = append(r.events, "delete "+obj.(*unstructured.Unstructured).GetName())
+}
+
+func TestParseRemotes(t *testing.T) {
+	remotes, err := ParseRemotes(" gpu-east=/etc/federation/gpu-east ,", "gpu-west=https://maas-api.gpu-west.example.com/")
+	require.NoError(t, err)
+	assert.Equal(t, []Remote{
+		{Name: "gpu-east", Kubeconfig: "/etc/federation/gpu-east"},
+		{Name: "gpu-west", Endpoint: "https://maas-api.gpu-west.example.com"},
+	}, remotes)
+
+	for _, tc := range []struct{ kubeconfigs, endpoints, err string }{
+		{"gpu-east", "", "want name=value"},
+		{"gpu-east=", "", "want name=value"},
+		{"GPU_East=/kubeconfig", "", "invalid remote cluster name"},
+		{"gpu-east=/kubeconfig", "gpu-east=https://maas-api.example.com", "configured twice"},
+		{"", "gpu-west=maas-api.example.com", "not an http(s) URL"},
+	} {
+		_, err := ParseRemotes(tc.kubeconfigs, tc.endpoints)
+		assert.ErrorContains(t, err, tc.err, "%q %q", tc.kubeconfigs, tc.endpoints)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	const token = "federation-token"
+	local := newLister(t, modelRef("llm", "granite", "1"))
+	eastModels := newLister(t, modelRef("llm", "granite", "1"), modelRef("llm", "llama", "1"))
+	westModels := newLister(t, modelRef("llm", "llama", "1"), modelRef("gpu", "phi", "1"))
+	east := serveModels(t, eastModels, "gpu-east", token)
+	west := serveModels(t, westModels, "gpu-west", token)
+
+	idx, err := NewIndex(nil, "hub", local, []Remote{
+		{Name: "gpu-east", Endpoint: east.URL},
+		{Name: "gpu-west", Endpoint: west.URL},
+	}, models.Scope{}, token, time.Minute)
+	require.NoError(t, err)
+	events := &recorder{}
+	require.NoError(t, idx.AddEventHandler(events))
+
+	assert.Equal(t, map[string]bool{"gpu-east": false, "gpu-west": false}, idx.Available())
+	cluster, available := idx.Cluster("llm/llama")
+	assert.Empty(t, cluster, "models of remotes that have not synced are unknown")
+	assert.True(t, available)
+
+	ctx := context.Background()
+	for _, r := range idx.remotes {
+		r.poll(ctx)
+	}
+	assert.Equal(t, map[string]bool{"gpu-east": true, "gpu-west": true}, idx.Available())
+	assert.ElementsMatch(t, []string{"add llama", "add granite", "add llama", "add phi"}, events.events)
+
+	all, err := idx.List()
+	require.NoError(t, err)
+	var listed []string
+	for _, u := range all {
+		listed = append(listed, models.OriginCluster(u)+":"+u.GetNamespace()+"/"+u.GetName())
+	}
+	assert.Equal(t, []string{":llm/granite", "gpu-east:llm/llama", "gpu-west:gpu/phi"}, listed,
+		"each model comes from the first cluster hosting it")
+
+	byName, err := idx.ByName("llama")
+	require.NoError(t, err)
+	require.Len(t, byName, 1)
+	assert.Equal(t, "gpu-east", models.OriginCluster(byName[0]))
+	assert.Equal(t, "gpu-east", models.FromMaaSModelRef(byName[0]).Cluster)
+
+	for model, want := range map[string]string{"llm/granite": "hub", "llm/llama": "gpu-east", "gpu/phi": "gpu-west", "llm/missing": ""} {
+		cluster, available := idx.Cluster(model)
+		assert.Equal(t, want, cluster, model)
+		assert.True(t, available, model)
+	}
+
+	// gpu-east goes stale: llama fails over to gpu-west.
+	idx.remotes[0].lastContact.Store(time.Now().Add(-4 * time.Minute).UnixNano())
+	cluster, available = idx.Cluster("llm/llama")
+	assert.Equal(t, "gpu-west", cluster)
+	assert.True(t, available)
+
+	// phi is only on gpu-west: it stays listed but its cluster is unavailable.
+	idx.remotes[1].lastContact.Store(time.Now().Add(-4 * time.Minute).UnixNano())
+	cluster, available = idx.Cluster("gpu/phi")
+	assert.Equal(t, "gpu-west", cluster)
+	assert.False(t, available)
+
+	// Refreshes report the changes.
+	events.events = nil
+	require.NoError(t, westModels.indexer.Update(modelRef("gpu", "phi", "2")))
+	require.NoError(t, westModels.indexer.Delete(modelRef("llm", "llama", "1")))
+	idx.remotes[1].poll(ctx)
+	assert.ElementsMatch(t, []string{"update phi", "delete llama"}, events.events)
+	assert.True(t, idx.Available()["gpu-west"])
+}
+
+func TestIndexScope(t *testing.T) {
+	const token = "federation-token"
+	ignored := modelRef("llm", "mistral", "1")
+	ignored.SetAnnotations(map[string]string{"maas.opendatahub.io/ignore": "true"})
+	east := serveModels(t, newLister(t, modelRef("llm", "llama", "1"), modelRef("sandbox", "phi", "1"), ignored), "gpu-east", token)
+
+	scope, err := models.ParseScope("llm", "")
+	require.NoError(t, err)
+	idx, err := NewIndex(nil, "hub", newLister(t), []Remote{{Name: "gpu-east", Endpoint: east.URL}}, scope, token, time.Minute)
+	require.NoError(t, err)
+	idx.remotes[0].poll(context.Background())
+
+	all, err := idx.List()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "llama", all[0].GetName())
+	u, err := idx.Get("sandbox", "phi")
+	require.NoError(t, err)
+	assert.Nil(t, u, "models outside the scope are not federated")
+}
+
+func TestHandler(t *testing.T) {
+	remote := modelRef("llm", "llama", "1")
+	remote.SetAnnotations(map[string]string{"maas.opendatahub.io/origin-cluster": "gpu-east"})
+	local := modelRef("llm", "granite", "1")
+	local.SetAnnotations(map[string]string{"maas.opendatahub.io/origin-cluster": "hub"})
+	server := serveModels(t, newLister(t, local, remote), "hub", "federation-token")
+
+	resp, err := http.Get(server.URL + ModelsPath)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	idx, err := NewIndex(nil, "gpu-west", newLister(t), []Remote{{Name: "hub", Endpoint: server.URL}}, models.Scope{}, "federation-token", time.Minute)
+	require.NoError(t, err)
+	idx.remotes[0].poll(context.Background())
+	all, err := idx.List()
+	require.NoError(t, err)
+	require.Len(t, all, 1, "models the instance federates from other clusters are not served")
+	assert.Equal(t, "granite", all[0].GetName())
+
+	idx, err = NewIndex(nil, "gpu-west", newLister(t), []Remote{{Name: "hub", Endpoint: server.URL}}, models.Scope{}, "wrong", time.Minute)
+	require.NoError(t, err)
+	idx.remotes[0].poll(context.Background())
+	assert.False(t, idx.Available()["hub"])
+}
@@ -0,0 +1,56 @@
+package federation
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+// Handler serves the models of the local cluster to federating maas-api instances.
+type Handler struct {
+	lister  models.MaaSModelRefLister
+	cluster string
+	token   string
+	logger  *logger.Logger
+}
+
+// NewHandler creates a handler for GET /internal/v1/federation/models that lists the models of
+// lister, the local cluster named cluster, to callers bearing token.
+func NewHandler(log *logger.Logger, lister models.MaaSModelRefLister, cluster, token string) *Handler {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Handler{lister: lister, cluster: cluster, token: token, logger: log}
+}
+
+// ListModels handles GET /internal/v1/federation/models. Models of other clusters are left out,
+// so that federating instances never read them back from each other.
+func (h *Handler) ListModels(c *gin.Context) {
+	bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(h.token)) != 1 {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authentication required")
+		return
+	}
+
+	items, err := h.lister.List()
+	if err != nil {
+		h.logger.Error("Failed to list models for federation", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list models")
+		return
+	}
+	list := ModelList{Cluster: h.cluster, Items: make([]unstructured.Unstructured, 0, len(items))}
+	for _, u := range items {
+		if cluster := models.OriginCluster(u); cluster != "" && cluster != h.cluster {
+			continue
+		}
+		list.Items = append(list.Items, *u)
+	}
+	c.JSON(http.StatusOK, list)
+}
@@ -0,0 +1,291 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+// staleAfter is how many refresh intervals a remote cluster may go without contact before its
+// models are stale.
+const staleAfter = 3
+
+// remote caches the MaaSModelRefs of one remote cluster and tracks whether they are fresh.
+type remote struct {
+	name    string
+	indexer cache.Indexer
+	lister  *indexLister
+	refresh time.Duration
+	logger  *logger.Logger
+
+	// contact reaches the cluster, and for endpoint remotes refreshes the cache, every refresh.
+	contact func(ctx context.Context) error
+	// hasSynced reports whether the cache has been filled once.
+	hasSynced func() bool
+	// informer reads kubeconfig remotes; nil for endpoint remotes.
+	informer cache.SharedIndexInformer
+
+	// handlers are notified of the changes an endpoint remote's refreshes make.
+	mu       sync.Mutex
+	handlers []cache.ResourceEventHandler
+
+	lastContact atomic.Int64 // unix nanoseconds
+	failing     atomic.Bool
+}
+
+func modelIndexers() cache.Indexers {
+	return cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		models.NameIndex:     models.NameIndexFunc,
+		models.RouteIndex:    models.RouteIndexFunc,
+		models.AliasIndex:    models.AliasIndexFunc,
+	}
+}
+
+// newKubeconfigRemote watches the MaaSModelRefs of a cluster through its kubeconfig, and probes
+// the API server's /readyz every refresh.
+func newKubeconfigRemote(log *logger.Logger, r Remote, scope models.Scope, refresh time.Duration) (*remote, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", r.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes clientset: %w", err)
+	}
+
+	namespace := metav1.NamespaceAll
+	if len(scope.Namespaces) == 1 {
+		namespace = scope.Namespaces[0]
+	}
+	var selectLabels dynamicinformer.TweakListOptionsFunc
+	if scope.Selector != nil && !scope.Selector.Empty() {
+		selectLabels = func(opts *metav1.ListOptions) { opts.LabelSelector = scope.Selector.String() }
+	}
+	informer := dynamicinformer.NewFilteredDynamicInformer(dynamicClient, models.GVR(), namespace, 0, modelIndexers(), selectLabels).Informer()
+	if err := informer.SetTransform(OriginTransform(r.Name)); err != nil {
+		return nil, fmt.Errorf("failed to set transform: %w", err)
+	}
+
+	rem := newRemote(log, r.Name, informer.GetIndexer(), scope, refresh)
+	rem.informer = informer
+	rem.hasSynced = informer.HasSynced
+	readyz := clientset.Discovery().RESTClient()
+	rem.contact = func(ctx context.Context) error {
+		return readyz.Get().AbsPath("/readyz").Do(ctx).Error()
+	}
+	return rem, nil
+}
+
+// ModelList is the body of GET /internal/v1/federation/models: the MaaSModelRefs a maas-api
+// instance serves from its own cluster.
+type ModelList struct {
+	Cluster string                      `json:"cluster"`
+	Items   []unstructured.Unstructured `json:"items"`
+}
+
+// ModelsPath is the path of the endpoint a maas-api serves its local models on to federating
+// instances.
+const ModelsPath = "/internal/v1/federation/models"
+
+// newEndpointRemote polls the models the maas-api of a cluster serves every refresh, presenting
+// token.
+func newEndpointRemote(log *logger.Logger, r Remote, scope models.Scope, token string, refresh time.Duration) *remote {
+	rem := newRemote(log, r.Name, cache.NewIndexer(cache.MetaNamespaceKeyFunc, modelIndexers()), scope, refresh)
+	var synced atomic.Bool
+	rem.hasSynced = synced.Load
+	httpClient := &http.Client{Timeout: refresh}
+	rem.contact = func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.Endpoint+ModelsPath, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("GET %s: %s", req.URL, resp.Status)
+		}
+		var list ModelList
+		if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+			return fmt.Errorf("failed to decode models of %s: %w", req.URL, err)
+		}
+		rem.replace(list.Items)
+		synced.Store(true)
+		return nil
+	}
+	return rem
+}
+
+func newRemote(log *logger.Logger, name string, indexer cache.Indexer, scope models.Scope, refresh time.Duration) *remote {
+	return &remote{
+		name:    name,
+		indexer: indexer,
+		lister:  &indexLister{indexer: indexer, scope: scope},
+		refresh: refresh,
+		logger:  log.WithFields("cluster", name),
+	}
+}
+
+// run reads the cluster until stopCh closes.
+func (r *remote) run(stopCh <-chan struct{}) {
+	if r.informer != nil {
+		go r.informer.Run(stopCh)
+	}
+	wait.UntilWithContext(wait.ContextForChannel(stopCh), r.poll, r.refresh)
+}
+
+// poll contacts the cluster once, logging when it becomes unreachable and reachable again.
+func (r *remote) poll(ctx context.Context) {
+	if err := r.contact(ctx); err != nil {
+		if !r.failing.Swap(true) {
+			r.logger.Warn("Remote cluster unreachable", "error", err)
+		}
+		return
+	}
+	r.lastContact.Store(time.Now().UnixNano())
+	if r.failing.Swap(false) {
+		r.logger.Info("Remote cluster reachable again")
+	}
+}
+
+// available reports whether the cache has synced and the cluster was reached recently.
+func (r *remote) available(now time.Time) bool {
+	if !r.hasSynced() {
+		return false
+	}
+	return now.Sub(time.Unix(0, r.lastContact.Load())) <= staleAfter*r.refresh
+}
+
+func (r *remote) addEventHandler(handler cache.ResourceEventHandler) error {
+	if r.informer != nil {
+		_, err := r.informer.AddEventHandler(handler)
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, handler)
+	return nil
+}
+
+// replace swaps the cached MaaSModelRefs of an endpoint remote for items, and notifies the
+// handlers of the models added, changed and removed.
+func (r *remote) replace(items []unstructured.Unstructured) {
+	previous := make(map[string]*unstructured.Unstructured)
+	for _, obj := range r.indexer.List() {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			previous[u.GetNamespace()+"/"+u.GetName()] = u
+		}
+	}
+	objs := make([]any, 0, len(items))
+	for i := range items {
+		setOrigin(&items[i], r.name)
+		objs = append(objs, &items[i])
+	}
+	if err := r.indexer.Replace(objs, ""); err != nil {
+		r.logger.Error("Failed to cache models of remote cluster", "error", err)
+		return
+	}
+
+	r.mu.Lock()
+	handlers := r.handlers
+	r.mu.Unlock()
+	for i := range items {
+		u := &items[i]
+		key := u.GetNamespace() + "/" + u.GetName()
+		old, existed := previous[key]
+		delete(previous, key)
+		for _, h := range handlers {
+			switch {
+			case !existed:
+				h.OnAdd(u, false)
+			case old.GetResourceVersion() != u.GetResourceVersion():
+				h.OnUpdate(old, u)
+			}
+		}
+	}
+	for _, old := range previous {
+		for _, h := range handlers {
+			h.OnDelete(old)
+		}
+	}
+}
+
+// indexLister implements Lister from an indexer built with modelIndexers, returning only the
+// MaaSModelRefs scope serves.
+type indexLister struct {
+	indexer cache.Indexer
+	scope   models.Scope
+}
+
+func (l *indexLister) List() ([]*unstructured.Unstructured, error) {
+	return l.filter(l.indexer.List()), nil
+}
+
+func (l *indexLister) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	obj, exists, err := l.indexer.GetByKey(namespace + "/" + name)
+	if err != nil || !exists {
+		return nil, err
+	}
+	if u, ok := obj.(*unstructured.Unstructured); ok && l.scope.Serves(u) {
+		return u, nil
+	}
+	return nil, nil
+}
+
+func (l *indexLister) ByNamespace(namespace string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(cache.NamespaceIndex, namespace)
+}
+
+func (l *indexLister) ByName(name string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(models.NameIndex, name)
+}
+
+func (l *indexLister) ByAlias(alias string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(models.AliasIndex, alias)
+}
+
+func (l *indexLister) ByRoute(key string) ([]*unstructured.Unstructured, error) {
+	return l.byIndex(models.RouteIndex, key)
+}
+
+func (l *indexLister) byIndex(index, key string) ([]*unstructured.Unstructured, error) {
+	objs, err := l.indexer.ByIndex(index, key)
+	if err != nil {
+		return nil, err
+	}
+	return l.filter(objs), nil
+}
+
+func (l *indexLister) filter(objs []any) []*unstructured.Unstructured {
+	out := make([]*unstructured.Unstructured, 0, len(objs))
+	for _, o := range objs {
+		if u, ok := o.(*unstructured.Unstructured); ok && l.scope.Serves(u) {
+			out = append(out, u)
+		}
+	}
+	return out
+}
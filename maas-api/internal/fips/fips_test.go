@@ -0,0 +1,30 @@
+package fips_test
+
+import (
+	"crypto/fips140"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/fips"
+)
+
+func TestSelfCheck(t *testing.T) {
+	require.NoError(t, fips.SelfCheck())
+}
+
+func TestVerify(t *testing.T) {
+	status, err := fips.Verify(false)
+	require.NoError(t, err)
+	assert.Equal(t, "passed", status.SelfCheck)
+	assert.Equal(t, fips140.Enabled(), status.Enabled)
+	assert.NotEmpty(t, status.Backend)
+
+	_, err = fips.Verify(true)
+	if fips140.Enabled() {
+		require.NoError(t, err)
+	} else {
+		require.ErrorContains(t, err, "FIPS mode required")
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 100
+	// defaultAuditRange is how far back from to a query without from reaches.
+	defaultAuditRange = 24 * time.Hour
+)
+
+// AuditHandler serves the audit log kept by the store sink.
+type AuditHandler struct {
+	logger       *logger.Logger
+	store        audit.Store
+	adminChecker AdminChecker
+	now          func() time.Time
+}
+
+// NewAuditHandler creates a handler for GET /admin/v1/audit.
+func NewAuditHandler(log *logger.Logger, store audit.Store, adminChecker AdminChecker) *AuditHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &AuditHandler{logger: log, store: store, adminChecker: adminChecker, now: time.Now}
+}
+
+// SetClock resolves default time ranges by now instead of the local clock.
+func (h *AuditHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// ListAuditRecords handles GET /admin/v1/audit, returning authorization decisions newest first.
+// Query parameters:
+//   - from, to: the range, as RFC 3339 times or YYYY-MM-DD dates in UTC. to is exclusive.
+//     They default to 24 hours before to and now.
+//   - user, model, reason: keep only the decisions with that username, namespace/name model or
+//     denial reason code.
+//   - decision: allowed or denied.
+//   - limit (default 50, at most 100) and offset page through the results.
+func (h *AuditHandler) ListAuditRecords(c *gin.Context) {
+	user := userFrom(c)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return
+	}
+
+	q, err := h.parseQuery(c)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+	records, hasMore, err := h.store.Query(c.Request.Context(), q)
+	if err != nil {
+		h.logger.Error("Failed to query audit records", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to query audit records")
+		return
+	}
+	if records == nil {
+		records = []audit.Record{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"object":   "list",
+		"from":     q.From.Format(time.RFC3339),
+		"to":       q.To.Format(time.RFC3339),
+		"data":     records,
+		"has_more": hasMore,
+	})
+}
+
+func (h *AuditHandler) parseQuery(c *gin.Context) (audit.Query, error) {
+	q := audit.Query{
+		To:    h.now().UTC(),
+		User:  c.Query("user"),
+		Model: c.Query("model"),
+		Limit: defaultAuditLimit,
+	}
+	var err error
+	if to := c.Query("to"); to != "" {
+		if q.To, err = parseUsageTime(to); err != nil {
+			return audit.Query{}, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	q.From = q.To.Add(-defaultAuditRange)
+	if from := c.Query("from"); from != "" {
+		if q.From, err = parseUsageTime(from); err != nil {
+			return audit.Query{}, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if !q.From.Before(q.To) {
+		return audit.Query{}, errors.New("from must be before to")
+	}
+
+	switch q.Decision = c.Query("decision"); q.Decision {
+	case "", audit.DecisionAllowed, audit.DecisionDenied:
+	default:
+		return audit.Query{}, fmt.Errorf("invalid decision %q: must be allowed or denied", q.Decision)
+	}
+	if q.Reason = c.Query("reason"); q.Reason != "" && !slices.Contains(reason.Codes(), q.Reason) {
+		return audit.Query{}, fmt.Errorf("invalid reason %q", q.Reason)
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit < 1 {
+			return audit.Query{}, errors.New("limit must be a positive integer")
+		}
+		// Silently cap at maximum, as API key search does.
+		q.Limit = min(q.Limit, maxAuditLimit)
+	}
+	if offset := c.Query("offset"); offset != "" {
+		if q.Offset, err = strconv.Atoi(offset); err != nil || q.Offset < 0 {
+			return audit.Query{}, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+type auditPage struct {
+	Object  string         `json:"object"`
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Data    []audit.Record `json:"data"`
+	HasMore bool           `json:"has_more"`
+}
+
+func listAudit(t *testing.T, admin bool, query string) (*httptest.ResponseRecorder, auditPage) {
+	t.Helper()
+	store := audit.NewMemoryStore()
+	at := func(d, h int) time.Time { return time.Date(2026, 10, d, h, 0, 0, 0, time.UTC) }
+	for _, r := range []audit.Record{
+		{Time: at(14, 9), Endpoint: "ext_authz", User: "alice", Model: "llm/granite", Decision: audit.DecisionAllowed},
+		{Time: at(16, 8), Endpoint: "ext_authz", User: "alice", Model: "llm/granite", Decision: audit.DecisionAllowed},
+		{Time: at(16, 9), Endpoint: "select", User: "bob", Model: "llm/granite", Decision: audit.DecisionDenied, Reason: "access_denied"},
+		{Time: at(16, 10), Endpoint: "ext_authz", User: "alice", Model: "llm/llama", Decision: audit.DecisionDenied, Reason: "rate_limited"},
+	} {
+		require.NoError(t, store.Write(context.Background(), r))
+	}
+	h := handlers.NewAuditHandler(logger.Development(), store, fakeAdminChecker(admin))
+	h.SetClock(func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) })
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/v1/audit", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	}, h.ListAuditRecords)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/v1/audit"+query, nil))
+
+	var page auditPage
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	}
+	return w, page
+}
+
+func TestListAuditRecords(t *testing.T) {
+	t.Run("defaults to the last 24 hours", func(t *testing.T) {
+		w, page := listAudit(t, true, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "list", page.Object)
+		assert.Equal(t, "2026-10-15T12:00:00Z", page.From)
+		assert.Equal(t, "2026-10-16T12:00:00Z", page.To)
+		require.Len(t, page.Data, 3)
+		assert.Equal(t, "llm/llama", page.Data[0].Model, "newest first")
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		w, page := listAudit(t, true, "?from=2026-10-01&user=alice&model=llm/granite&decision=allowed")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, page.Data, 2)
+		for _, r := range page.Data {
+			assert.Equal(t, "alice", r.User)
+			assert.Equal(t, "llm/granite", r.Model)
+		}
+
+		w, page = listAudit(t, true, "?reason=access_denied")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, "bob", page.Data[0].User)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		w, page := listAudit(t, true, "?limit=2")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Len(t, page.Data, 2)
+		assert.True(t, page.HasMore)
+
+		w, page = listAudit(t, true, "?limit=2&offset=2")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.Len(t, page.Data, 1)
+		assert.Equal(t, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), page.Data[0].Time)
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("empty result is an empty list", func(t *testing.T) {
+		w, _ := listAudit(t, true, "?user=nobody")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.JSONEq(t, `[]`, mustField(t, w.Body.Bytes(), "data"))
+	})
+
+	for _, query := range []string{
+		"?from=yesterday",
+		"?from=2026-10-16&to=2026-10-15",
+		"?decision=maybe",
+		"?reason=no_such_reason",
+		"?limit=0",
+		"?offset=-1",
+	} {
+		t.Run("rejects "+query, func(t *testing.T) {
+			w, _ := listAudit(t, true, query)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+
+	t.Run("requires admin", func(t *testing.T) {
+		w, _ := listAudit(t, false, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func mustField(t *testing.T, body []byte, field string) string {
+	t.Helper()
+	var m map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &m))
+	return string(m[field])
+}
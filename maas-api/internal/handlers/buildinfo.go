@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/fips"
+)
+
+// APIVersion is the maas-api REST API version this build serves.
+const APIVersion = "v1"
+
+// BuildInfo identifies the running maas-api build and what it is configured to do, so support and
+// automated health checks can verify exactly what is deployed.
+type BuildInfo struct {
+	Version   string      `json:"version"`
+	Commit    string      `json:"commit"`
+	BuildTime string      `json:"buildTime,omitempty"`
+	GoVersion string      `json:"goVersion"`
+	Crypto    fips.Status `json:"crypto"`
+
+	// AuthMode is how gateways authorize inference requests: "authorino" or "ext_authz".
+	AuthMode string `json:"authMode"`
+	// Features lists the optional features by name and whether this deployment enables them.
+	Features map[string]bool `json:"features"`
+	// APICompatibility is the API surface this build serves.
+	APICompatibility APICompatibility `json:"apiCompatibility"`
+}
+
+// APICompatibility names the API versions a build serves and reads.
+type APICompatibility struct {
+	// API is the REST API version, the prefix of the versioned routes.
+	API string `json:"api"`
+	// CRDs is the MaaS custom resource group/version read from the cluster.
+	CRDs string `json:"crds"`
+}
+
+// BuildInfoHandler serves build information.
+type BuildInfoHandler struct {
+	info BuildInfo
+}
+
+// NewBuildInfoHandler creates a handler that reports info.
+func NewBuildInfoHandler(info BuildInfo) *BuildInfoHandler {
+	return &BuildInfoHandler{info: info}
+}
+
+// GetBuildInfo handles GET /v1/buildinfo.
+func (h *BuildInfoHandler) GetBuildInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, h.info)
+}
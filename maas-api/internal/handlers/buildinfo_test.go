@@ -0,0 +1,42 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/fips"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/buildinfo", handlers.NewBuildInfoHandler(handlers.BuildInfo{
+		Version:          "v0.1.0",
+		Commit:           "9f112dc",
+		GoVersion:        "go1.25.1",
+		Crypto:           fips.Current(),
+		AuthMode:         "ext_authz",
+		Features:         map[string]bool{"extAuthz": true, "janitor": false},
+		APICompatibility: handlers.APICompatibility{API: handlers.APIVersion, CRDs: "maas.opendatahub.io/v1alpha1"},
+	}).GetBuildInfo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/buildinfo", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "9f112dc", body["commit"])
+	assert.Equal(t, "ext_authz", body["authMode"])
+	assert.Equal(t, map[string]any{"extAuthz": true, "janitor": false}, body["features"])
+	assert.Equal(t, map[string]any{"api": "v1", "crds": "maas.opendatahub.io/v1alpha1"}, body["apiCompatibility"])
+	assert.NotContains(t, body, "buildTime", "omitted when unknown")
+	assert.Contains(t, body["crypto"], "fipsEnabled")
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// AdminChecker reports whether a user may call admin-only endpoints.
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, user *token.UserContext) bool
+}
+
+// ModelCapacity compares the token throughput committed by subscriptions for one model
+// against the throughput its backend can sustain.
+type ModelCapacity struct {
+	Model     string            `json:"model"` // namespace/name of the MaaSModelRef
+	Ready     bool              `json:"ready"`
+	Resources *models.Resources `json:"resources,omitempty"`
+	// CapacityTokensPerMinute is the declared per-replica throughput times the replica count.
+	// Zero when the model has no throughput annotation, in which case it is never flagged.
+	CapacityTokensPerMinute int64 `json:"capacity_tokens_per_minute"`
+	// CommittedTokensPerMinute sums each subscription's per-user limit, normalized to one minute:
+	// the load when a single user of every subscription runs at its limit.
+	CommittedTokensPerMinute int64    `json:"committed_tokens_per_minute"`
+	Subscriptions            []string `json:"subscriptions"`
+	Oversubscribed           bool     `json:"oversubscribed"`
+}
+
+// CapacityHandler serves the admin capacity view.
+type CapacityHandler struct {
+	logger               *logger.Logger
+	maasModelRefLister   models.MaaSModelRefLister
+	subscriptionSelector *subscription.Selector
+	adminChecker         AdminChecker
+}
+
+// NewCapacityHandler creates a handler for GET /v1/admin/capacity.
+func NewCapacityHandler(
+	log *logger.Logger,
+	maasModelRefLister models.MaaSModelRefLister,
+	subscriptionSelector *subscription.Selector,
+	adminChecker AdminChecker,
+) *CapacityHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &CapacityHandler{
+		logger:               log,
+		maasModelRefLister:   maasModelRefLister,
+		subscriptionSelector: subscriptionSelector,
+		adminChecker:         adminChecker,
+	}
+}
+
+// GetCapacity handles GET /v1/admin/capacity.
+func (h *CapacityHandler) GetCapacity(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	user, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return
+	}
+
+	var items []*unstructured.Unstructured
+	if h.maasModelRefLister != nil {
+		var err error
+		if items, err = h.maasModelRefLister.List(); err != nil {
+			h.logger.Error("Failed to list MaaSModelRefs", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list models")
+			return
+		}
+	}
+	var subs []*subscription.SelectResponse
+	if h.subscriptionSelector != nil {
+		var err error
+		if subs, err = h.subscriptionSelector.ListAll(); err != nil {
+			h.logger.Error("Failed to list subscriptions", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to get subscriptions")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   computeCapacity(items, subs),
+	})
+}
+
+// computeCapacity builds one ModelCapacity per MaaSModelRef, sorted by namespace/name.
+func computeCapacity(items []*unstructured.Unstructured, subs []*subscription.SelectResponse) []ModelCapacity {
+	out := make([]ModelCapacity, 0, len(items))
+	for _, u := range items {
+		m := models.FromMaaSModelRef(u)
+		if m == nil {
+			continue
+		}
+		mc := ModelCapacity{
+			Model:         u.GetNamespace() + "/" + u.GetName(),
+			Ready:         m.Ready,
+			Resources:     m.Resources,
+			Subscriptions: []string{},
+		}
+
+		if perReplica, err := strconv.ParseInt(u.GetAnnotations()[constant.AnnotationThroughputPerReplica], 10, 64); err == nil && perReplica > 0 {
+			replicas := int64(1)
+			if mc.Resources != nil && mc.Resources.Replicas > 0 {
+				replicas = int64(mc.Resources.Replicas)
+			}
+			mc.CapacityTokensPerMinute = perReplica * replicas
+		}
+
+		for _, sub := range subs {
+			for _, ref := range sub.ModelRefs {
+				if ref.Name != u.GetName() || (ref.Namespace != "" && ref.Namespace != u.GetNamespace()) {
+					continue
+				}
+				mc.Subscriptions = append(mc.Subscriptions, sub.Namespace+"/"+sub.Name)
+				mc.CommittedTokensPerMinute += subscription.SustainedPerMinute(ref.TokenRateLimits)
+			}
+		}
+		mc.Oversubscribed = mc.CapacityTokensPerMinute > 0 && mc.CommittedTokensPerMinute > mc.CapacityTokensPerMinute
+		out = append(out, mc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Model < out[j].Model })
+	return out
+}
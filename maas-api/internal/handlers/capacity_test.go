@@ -0,0 +1,105 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+type fakeAdminChecker bool
+
+func (f fakeAdminChecker) IsAdmin(_ context.Context, _ *token.UserContext) bool { return bool(f) }
+
+// staticSubscriptionLister returns the given MaaSSubscriptions as-is.
+type staticSubscriptionLister []*unstructured.Unstructured
+
+func (s staticSubscriptionLister) List() ([]*unstructured.Unstructured, error) { return s, nil }
+
+func subscriptionWithLimit(name, modelNS, modelName string, limit int64, window string) *unstructured.Unstructured {
+	sub := &unstructured.Unstructured{}
+	sub.SetName(name)
+	sub.SetNamespace("models-as-a-service")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{
+		map[string]any{
+			"name":      modelName,
+			"namespace": modelNS,
+			"tokenRateLimits": []any{
+				map[string]any{"limit": limit, "window": window},
+			},
+		},
+	}, "spec", "modelRefs")
+	return sub
+}
+
+func getCapacity(t *testing.T, h *handlers.CapacityHandler) (*httptest.ResponseRecorder, []handlers.ModelCapacity) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/admin/capacity", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	}, h.GetCapacity)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/capacity", nil))
+
+	var body struct {
+		Data []handlers.ModelCapacity `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	}
+	return w, body.Data
+}
+
+func TestGetCapacity(t *testing.T) {
+	busy := maasModelRefUnstructured("busy", "llm", "http://busy.llm.svc", true,
+		map[string]string{constant.AnnotationThroughputPerReplica: "1000"})
+	_ = unstructured.SetNestedMap(busy.Object, map[string]any{
+		"gpuType": "NVIDIA-A100-SXM4-80GB", "gpuCount": int64(2), "memory": "64Gi", "replicas": int64(2),
+	}, "status", "resources")
+	unknown := maasModelRefUnstructured("unknown", "llm", "http://unknown.llm.svc", true, nil)
+
+	lister := fakeMaaSModelRefLister{"llm": {busy, unknown}}
+	selector := subscription.NewSelector(logger.Development(), staticSubscriptionLister{
+		subscriptionWithLimit("free", "llm", "busy", 500, "1m"),
+		subscriptionWithLimit("premium", "llm", "busy", 120000, "1h"), // 2000/min
+		subscriptionWithLimit("other", "llm", "unknown", 1000000, "1m"),
+	})
+
+	h := handlers.NewCapacityHandler(logger.Development(), lister, selector, fakeAdminChecker(true))
+	w, data := getCapacity(t, h)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, data, 2)
+
+	assert.Equal(t, "llm/busy", data[0].Model)
+	require.NotNil(t, data[0].Resources)
+	assert.Equal(t, "NVIDIA-A100-SXM4-80GB", data[0].Resources.GPUType)
+	assert.Equal(t, int64(2), data[0].Resources.GPUCount)
+	assert.Equal(t, int64(2000), data[0].CapacityTokensPerMinute, "1000 per replica x 2 replicas")
+	assert.Equal(t, int64(2500), data[0].CommittedTokensPerMinute)
+	assert.ElementsMatch(t, []string{"models-as-a-service/free", "models-as-a-service/premium"}, data[0].Subscriptions)
+	assert.True(t, data[0].Oversubscribed)
+
+	assert.Equal(t, "llm/unknown", data[1].Model)
+	assert.Zero(t, data[1].CapacityTokensPerMinute)
+	assert.False(t, data[1].Oversubscribed, "models without declared throughput are never flagged")
+}
+
+func TestGetCapacityRequiresAdmin(t *testing.T) {
+	h := handlers.NewCapacityHandler(logger.Development(), fakeMaaSModelRefLister{}, nil, fakeAdminChecker(false))
+	w, _ := getCapacity(t, h)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
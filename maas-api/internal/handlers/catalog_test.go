@@ -0,0 +1,75 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func getCatalog(t *testing.T, admin bool, objects ...runtime.Object) *httptest.ResponseRecorder {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	h := handlers.NewCatalogHandler(logger.Development(), client, fakeAdminChecker(admin))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/catalog", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "dashboard"})
+	}, h.GetCatalog)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/catalog", nil))
+	return w
+}
+
+func TestGetCatalog(t *testing.T) {
+	catalog := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "maas.opendatahub.io/v1alpha1",
+		"kind":       "MaaSModelCatalog",
+		"metadata":   map[string]any{"name": "default"},
+		"status": map[string]any{
+			"summary":     map[string]any{"total": int64(1), "ready": int64(1), "pending": int64(0), "unhealthy": int64(0), "failed": int64(0)},
+			"lastUpdated": "2026-10-16T12:00:00Z",
+			"models": []any{map[string]any{
+				"name": "granite", "namespace": "llm", "phase": "Ready", "endpoint": "https://maas.example.com/llm/granite",
+				"tiers": []any{"models-as-a-service/free"},
+			}},
+			"tiers": []any{map[string]any{"name": "free", "namespace": "models-as-a-service", "models": int64(1), "readyModels": int64(1)}},
+		},
+	}}
+
+	t.Run("returns the catalog status", func(t *testing.T) {
+		w := getCatalog(t, true, catalog)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "catalog", body["object"])
+		assert.Equal(t, "2026-10-16T12:00:00Z", body["lastUpdated"])
+		assert.Equal(t, float64(0), body["uncoveredModels"])
+		assert.Equal(t, float64(1), body["summary"].(map[string]any)["ready"])
+		require.Len(t, body["models"], 1)
+		assert.Equal(t, "https://maas.example.com/llm/granite", body["models"].([]any)[0].(map[string]any)["endpoint"])
+		require.Len(t, body["tiers"], 1)
+	})
+
+	t.Run("unavailable before the controller creates it", func(t *testing.T) {
+		w := getCatalog(t, true)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+	})
+
+	t.Run("requires admin", func(t *testing.T) {
+		w := getCatalog(t, false, catalog)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
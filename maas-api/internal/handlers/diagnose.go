@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+var (
+	httpRouteGVR            = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	authPolicyGVR           = schema.GroupVersionResource{Group: "kuadrant.io", Version: "v1", Resource: "authpolicies"}
+	rateLimitPolicyGVR      = schema.GroupVersionResource{Group: "kuadrant.io", Version: "v1", Resource: "ratelimitpolicies"}
+	tokenRateLimitPolicyGVR = schema.GroupVersionResource{Group: "kuadrant.io", Version: "v1alpha1", Resource: "tokenratelimitpolicies"}
+)
+
+// CheckStatus is the outcome of one diagnostic check.
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+	// CheckSkipped marks a check that could not run because an earlier link of the chain is missing.
+	CheckSkipped CheckStatus = "skipped"
+)
+
+// DiagnosticCheck is one link of a model's policy chain.
+type DiagnosticCheck struct {
+	Name        string      `json:"name"`
+	Status      CheckStatus `json:"status"`
+	Message     string      `json:"message"`
+	Remediation string      `json:"remediation,omitempty"`
+}
+
+// Diagnosis is the scored checklist of a model's policy chain.
+type Diagnosis struct {
+	Object string `json:"object"`
+	Model  string `json:"model"` // namespace/name of the MaaSModelRef
+	// Score is the percentage of checks that pass; a warning counts half.
+	Score int `json:"score"`
+	// Status is healthy when every check passes, unhealthy when any fails or is skipped, and
+	// degraded otherwise.
+	Status string            `json:"status"`
+	Checks []DiagnosticCheck `json:"checks"`
+}
+
+// DiagnoseHandler serves the admin diagnosis of a model's policy chain.
+type DiagnoseHandler struct {
+	logger             *logger.Logger
+	client             dynamic.Interface
+	maasModelRefLister models.MaaSModelRefLister
+	adminChecker       AdminChecker
+	streamClient       *http.Client
+	now                func() time.Time
+}
+
+// NewDiagnoseHandler creates a handler for GET /admin/v1/diagnose/{model} and
+// POST /admin/v1/diagnose-streaming/{model}.
+func NewDiagnoseHandler(
+	log *logger.Logger,
+	client dynamic.Interface,
+	maasModelRefLister models.MaaSModelRefLister,
+	adminChecker AdminChecker,
+) *DiagnoseHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &DiagnoseHandler{
+		logger:             log,
+		client:             client,
+		maasModelRefLister: maasModelRefLister,
+		adminChecker:       adminChecker,
+		streamClient: &http.Client{
+			Transport: gatewayTransport(&tls.Config{MinVersion: tls.VersionTLS12}),
+		},
+		now: time.Now,
+	}
+}
+
+// SetTransport sends streaming diagnoses with rt, the transport shared with the fallback proxy
+// that verifies the gateway's certificate, instead of a transport trusting the system roots.
+func (h *DiagnoseHandler) SetTransport(rt http.RoundTripper) {
+	h.streamClient.Transport = rt
+}
+
+// SetClock evaluates maintenance windows at now instead of the local clock.
+func (h *DiagnoseHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// Diagnose handles GET /admin/v1/diagnose/{model}, where model is namespace/name, a bare name or
+// an alias. It walks the model's MaaSModelRef, HTTPRoute, AuthPolicy, rate limit policies and
+// backend, and returns a scored checklist with a remediation hint for each check that does not
+// pass.
+func (h *DiagnoseHandler) Diagnose(c *gin.Context) {
+	user := userFrom(c)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return
+	}
+
+	model := strings.Trim(c.Param("model"), "/")
+	if model == "" {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "Model is required")
+		return
+	}
+	u, err := h.lookup(model)
+	if err != nil {
+		h.respondLookupError(c, model, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.diagnose(c.Request.Context(), u))
+}
+
+// lookup returns the MaaSModelRef of a namespace/name, bare or alias model.
+func (h *DiagnoseHandler) lookup(model string) (*unstructured.Unstructured, error) {
+	getter, ok := h.maasModelRefLister.(models.MaaSModelRefGetter)
+	if !ok {
+		return nil, models.ErrModelNotFound
+	}
+	namespace, name, qualified := strings.Cut(model, "/")
+	if !qualified {
+		name, namespace = namespace, ""
+		resolved, err := models.NamespaceResolver(h.maasModelRefLister)(name)
+		if err != nil && !errors.Is(err, models.ErrModelNotFound) {
+			return nil, err
+		}
+		namespace = resolved
+	}
+	if namespace != "" {
+		u, err := getter.Get(namespace, name)
+		if err == nil && u != nil {
+			return u, nil
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	if ns, n, ok := models.AliasResolver(h.maasModelRefLister)(namespace, name); ok {
+		if u, err := getter.Get(ns, n); err == nil && u != nil {
+			return u, nil
+		}
+	}
+	return nil, models.ErrModelNotFound
+}
+
+// diagnose walks the policy chain of a MaaSModelRef. Each check runs even when an earlier one
+// fails, unless it needs the HTTPRoute and there is none.
+func (h *DiagnoseHandler) diagnose(ctx context.Context, u *unstructured.Unstructured) *Diagnosis {
+	d := &Diagnosis{Object: "diagnosis", Model: u.GetNamespace() + "/" + u.GetName()}
+	d.Checks = append(d.Checks, h.checkModel(u))
+
+	route, check := h.checkHTTPRoute(ctx, u)
+	d.Checks = append(d.Checks, check)
+	if route == nil {
+		for _, name := range []string{"authpolicy", "ratelimitpolicy"} {
+			d.Checks = append(d.Checks, DiagnosticCheck{
+				Name:        name,
+				Status:      CheckSkipped,
+				Message:     "Not checked: the model has no HTTPRoute",
+				Remediation: "Fix the httproute check first",
+			})
+		}
+	} else {
+		d.Checks = append(d.Checks, h.checkAuthPolicy(ctx, route), h.checkRateLimitPolicies(ctx, route))
+	}
+	d.Checks = append(d.Checks, checkBackend(u))
+
+	d.Score, d.Status = score(d.Checks)
+	return d
+}
+
+func (h *DiagnoseHandler) checkModel(u *unstructured.Unstructured) DiagnosticCheck {
+	check := DiagnosticCheck{Name: "model"}
+	if models.IsSoftDeleted(u) {
+		check.Status = CheckFail
+		check.Message = "The MaaSModelRef is soft-deleted, so it is hidden from the catalog and denied"
+		check.Remediation = "Restore it by removing the soft-deleted annotation, or delete it if it was retired on purpose"
+		return check
+	}
+	if in, until := models.InMaintenance(u, h.now()); in {
+		check.Status = CheckWarn
+		check.Message = "The model is in maintenance and requests are answered with 503"
+		if !until.IsZero() {
+			check.Message += " until " + until.UTC().Format(time.RFC3339)
+		}
+		check.Remediation = "Wait for the maintenance window to end, or remove the maintenance annotation"
+		return check
+	}
+	check.Status = CheckPass
+	check.Message = "The MaaSModelRef exists and is published"
+	return check
+}
+
+func (h *DiagnoseHandler) checkHTTPRoute(ctx context.Context, u *unstructured.Unstructured) (*unstructured.Unstructured, DiagnosticCheck) {
+	check := DiagnosticCheck{Name: "httproute"}
+	name, _, _ := unstructured.NestedString(u.Object, "status", "httpRouteName")
+	namespace, _, _ := unstructured.NestedString(u.Object, "status", "httpRouteNamespace")
+	if name == "" {
+		check.Status = CheckFail
+		check.Message = "maas-controller has not recorded an HTTPRoute for the model"
+		check.Remediation = "Check the MaaSModelRef's conditions and the maas-controller logs; the model's backend must be deployed and serve an HTTPRoute"
+		return nil, check
+	}
+	if namespace == "" {
+		namespace = u.GetNamespace()
+	}
+	ref := namespace + "/" + name
+
+	route, err := h.client.Resource(httpRouteGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("HTTPRoute %s does not exist", ref)
+		check.Remediation = "Check that the model's backend is deployed; maas-controller recreates generated routes on its next reconcile"
+		return nil, check
+	}
+	if err != nil {
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("Failed to get HTTPRoute %s: %v", ref, err)
+		check.Remediation = "Check that maas-api may read HTTPRoutes"
+		return nil, check
+	}
+
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	if len(parents) == 0 {
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("HTTPRoute %s is not attached to any Gateway", ref)
+		check.Remediation = "Check that the route's parentRefs name the MaaS Gateway and that the Gateway allows routes from its namespace"
+		return route, check
+	}
+	for _, p := range parents {
+		parent, _ := p.(map[string]any)
+		gateway, _, _ := unstructured.NestedString(parent, "parentRef", "name")
+		for _, condType := range []string{"Accepted", "ResolvedRefs"} {
+			if msg, ok := conditionTrue(parent, condType); !ok {
+				check.Status = CheckFail
+				check.Message = fmt.Sprintf("HTTPRoute %s is not %s by Gateway %s: %s", ref, conditionVerb(condType), gateway, msg)
+				check.Remediation = "Check the route's parentRefs and backendRefs; a backend Service in another namespace needs a ReferenceGrant"
+				return route, check
+			}
+		}
+	}
+	check.Status = CheckPass
+	check.Message = fmt.Sprintf("HTTPRoute %s is accepted by its Gateway", ref)
+	return route, check
+}
+
+func (h *DiagnoseHandler) checkAuthPolicy(ctx context.Context, route *unstructured.Unstructured) DiagnosticCheck {
+	check := DiagnosticCheck{Name: "authpolicy"}
+	policies, err := h.policiesTargeting(ctx, authPolicyGVR, route)
+	if err != nil {
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("Failed to list AuthPolicies: %v", err)
+		check.Remediation = "Check that Kuadrant is installed and that maas-api may read AuthPolicies"
+		return check
+	}
+	if len(policies) == 0 {
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("No AuthPolicy targets HTTPRoute %s/%s", route.GetNamespace(), route.GetName())
+		check.Remediation = "Create a MaaSAuthPolicy whose spec.modelRefs include the model; maas-controller generates its AuthPolicy"
+		return check
+	}
+	return enforcedCheck(check, "AuthPolicy", policies,
+		"Check the AuthPolicy's conditions and that Authorino is running; a policy may be overridden by a Gateway-level one")
+}
+
+func (h *DiagnoseHandler) checkRateLimitPolicies(ctx context.Context, route *unstructured.Unstructured) DiagnosticCheck {
+	check := DiagnosticCheck{Name: "ratelimitpolicy"}
+	var policies []unstructured.Unstructured
+	var failed []string
+	for _, gvr := range []schema.GroupVersionResource{tokenRateLimitPolicyGVR, rateLimitPolicyGVR} {
+		found, err := h.policiesTargeting(ctx, gvr, route)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", gvr.Resource, err))
+			continue
+		}
+		policies = append(policies, found...)
+	}
+	if len(policies) == 0 {
+		if len(failed) > 0 {
+			check.Status = CheckFail
+			check.Message = "Failed to list rate limit policies: " + strings.Join(failed, "; ")
+			check.Remediation = "Check that Kuadrant is installed and that maas-api may read rate limit policies"
+			return check
+		}
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("No TokenRateLimitPolicy or RateLimitPolicy targets HTTPRoute %s/%s, so no subscription grants access", route.GetNamespace(), route.GetName())
+		check.Remediation = "Create a MaaSSubscription whose spec.modelRefs include the model; maas-controller generates its TokenRateLimitPolicy"
+		return check
+	}
+	return enforcedCheck(check, "rate limit policy", policies,
+		"Check the policy's conditions and that Limitador is running")
+}
+
+// policiesTargeting lists the Kuadrant policies of gvr in the route's namespace whose targetRef
+// is the route.
+func (h *DiagnoseHandler) policiesTargeting(ctx context.Context, gvr schema.GroupVersionResource, route *unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	list, err := h.client.Resource(gvr).Namespace(route.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var out []unstructured.Unstructured
+	for _, p := range list.Items {
+		kind, _, _ := unstructured.NestedString(p.Object, "spec", "targetRef", "kind")
+		name, _, _ := unstructured.NestedString(p.Object, "spec", "targetRef", "name")
+		if kind == "HTTPRoute" && name == route.GetName() {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// enforcedCheck passes when every policy is Accepted and Enforced by Kuadrant. A policy that is
+// accepted but not enforced, for example because another policy overrides it, is a warning.
+func enforcedCheck(check DiagnosticCheck, kind string, policies []unstructured.Unstructured, remediation string) DiagnosticCheck {
+	var names []string
+	for _, p := range policies {
+		names = append(names, p.GetName())
+		status, _, _ := unstructured.NestedMap(p.Object, "status")
+		if msg, ok := conditionTrue(status, "Accepted"); !ok {
+			check.Status = CheckFail
+			check.Message = fmt.Sprintf("The %s %s is not accepted: %s", kind, p.GetName(), msg)
+			check.Remediation = remediation
+			return check
+		}
+		if msg, ok := conditionTrue(status, "Enforced"); !ok {
+			check.Status = CheckWarn
+			check.Message = fmt.Sprintf("The %s %s is accepted but not enforced: %s", kind, p.GetName(), msg)
+			check.Remediation = remediation
+			return check
+		}
+	}
+	check.Status = CheckPass
+	check.Message = fmt.Sprintf("Enforced: %s", strings.Join(names, ", "))
+	return check
+}
+
+func checkBackend(u *unstructured.Unstructured) DiagnosticCheck {
+	check := DiagnosticCheck{Name: "backend"}
+	phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+	status, _, _ := unstructured.NestedMap(u.Object, "status")
+	msg, _ := conditionTrue(status, "Ready")
+	switch phase {
+	case "Ready":
+		check.Status = CheckPass
+		check.Message = "The model's backend is ready"
+	case "Pending", "":
+		check.Status = CheckWarn
+		check.Message = "The model's backend is not ready yet: " + msg
+		check.Remediation = "Wait for the backend to start, and check its pods and events if it does not"
+	default:
+		check.Status = CheckFail
+		check.Message = fmt.Sprintf("The model's backend is %s: %s", phase, msg)
+		check.Remediation = "Check the backend referenced by spec.modelRef, its pods and events"
+	}
+	return check
+}
+
+// conditionTrue reports whether the condition of type condType in obj's conditions is True, and
+// otherwise why not.
+func conditionTrue(obj map[string]any, condType string) (string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj, "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != condType {
+			continue
+		}
+		if cond["status"] == string(metav1.ConditionTrue) {
+			return "", true
+		}
+		if msg, _ := cond["message"].(string); msg != "" {
+			return msg, false
+		}
+		reason, _ := cond["reason"].(string)
+		return "reason " + reason, false
+	}
+	return "no " + condType + " condition reported", false
+}
+
+func conditionVerb(condType string) string {
+	if condType == "ResolvedRefs" {
+		return "resolved"
+	}
+	return strings.ToLower(condType)
+}
+
+// score returns the percentage of checks that pass, counting warnings as half, and the overall
+// status of the checks.
+func score(checks []DiagnosticCheck) (int, string) {
+	if len(checks) == 0 {
+		return 0, "unhealthy"
+	}
+	points, status := 0, "healthy"
+	for _, c := range checks {
+		switch c.Status {
+		case CheckPass:
+			points += 2
+		case CheckWarn:
+			points++
+			if status == "healthy" {
+				status = "degraded"
+			}
+		default:
+			status = "unhealthy"
+		}
+	}
+	return points * 100 / (2 * len(checks)), status
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+const (
+	// streamDiagnosisTimeout bounds a streaming round trip.
+	streamDiagnosisTimeout = time.Minute
+	// streamBatchGap is how far apart two events must arrive to count as separate deliveries.
+	// Events a buffering proxy releases together arrive within it.
+	streamBatchGap = 5 * time.Millisecond
+	// streamDiagnosisTokens is the completion length requested, enough for several events.
+	streamDiagnosisTokens = 32
+)
+
+// StreamDiagnosis is the result of a streaming round trip through the gateway to a model.
+type StreamDiagnosis struct {
+	Object string `json:"object"`
+	Model  string `json:"model"` // namespace/name of the MaaSModelRef
+	DiagnosticCheck
+	URL         string `json:"url,omitempty"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	// Events is the number of server-sent events received, and Deliveries how many separate
+	// times they arrived. A stream buffered along the way arrives in one delivery.
+	Events     int `json:"events"`
+	Deliveries int `json:"deliveries"`
+	// TimeToFirstEventMs and DurationMs are measured from sending the request.
+	TimeToFirstEventMs int64 `json:"time_to_first_event_ms"`
+	DurationMs         int64 `json:"duration_ms"`
+}
+
+// DiagnoseStreaming handles POST /admin/v1/diagnose-streaming/{model}. It sends a short streamed
+// completion to the model through the gateway with the caller's credentials and
+// X-MaaS-Subscription, and checks that the server-sent events arrive as they are generated rather
+// than buffered by a proxy on the way. The completion is billed to the caller like any other.
+func (h *DiagnoseHandler) DiagnoseStreaming(c *gin.Context) {
+	user := userFrom(c)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return
+	}
+	model := strings.Trim(c.Param("model"), "/")
+	if model == "" {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "Model is required")
+		return
+	}
+	u, err := h.lookup(model)
+	if err != nil {
+		h.respondLookupError(c, model, err)
+		return
+	}
+
+	path, body, ok := streamRequest(u)
+	if !ok {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest,
+			fmt.Sprintf("Model %s/%s of class %s does not stream; only chat and completion models do", u.GetNamespace(), u.GetName(), models.ClassOf(u)))
+		return
+	}
+	m := models.FromMaaSModelRef(u)
+	if m == nil || m.URL == nil {
+		apierror.Respond(c, http.StatusConflict, apierror.Unavailable,
+			fmt.Sprintf("Model %s/%s has no endpoint yet; see GET /admin/v1/diagnose/%s/%s", u.GetNamespace(), u.GetName(), u.GetNamespace(), u.GetName()))
+		return
+	}
+
+	header := http.Header{}
+	for _, name := range []string{"Authorization", "X-MaaS-Subscription"} {
+		if v := c.GetHeader(name); v != "" {
+			header.Set(name, v)
+		}
+	}
+	url := strings.TrimSuffix(m.URL.String(), "/") + path
+	d := h.streamRoundTrip(c.Request.Context(), url, header, body)
+	d.Model = u.GetNamespace() + "/" + u.GetName()
+	c.JSON(http.StatusOK, d)
+}
+
+// respondLookupError answers a request whose model lookup failed.
+func (h *DiagnoseHandler) respondLookupError(c *gin.Context, model string, err error) {
+	var ambiguous *models.AmbiguousModelError
+	switch {
+	case errors.As(err, &ambiguous):
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+	case errors.Is(err, models.ErrModelNotFound):
+		apierror.Respond(c, http.StatusNotFound, apierror.ModelNotFound,
+			fmt.Sprintf("No MaaSModelRef named %s; create one for the model's backend", model))
+	default:
+		h.logger.Error("Failed to get MaaSModelRef", "error", err, "model", model)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to get model")
+	}
+}
+
+// streamRequest returns the path and body of a short streamed completion for a model, or false
+// for models of a class that does not stream.
+func streamRequest(u *unstructured.Unstructured) (string, []byte, bool) {
+	req := map[string]any{
+		"model":      u.GetName(),
+		"stream":     true,
+		"max_tokens": streamDiagnosisTokens,
+	}
+	var path string
+	switch models.ClassOf(u) {
+	case "", models.ClassChat:
+		path = "/v1/chat/completions"
+		req["messages"] = []any{map[string]any{"role": "user", "content": "Count from 1 to 20."}}
+	case models.ClassCompletion:
+		path = "/v1/completions"
+		req["prompt"] = "1, 2, 3,"
+	default:
+		return "", nil, false
+	}
+	body, _ := json.Marshal(req)
+	return path, body, true
+}
+
+// streamRoundTrip sends body to url and times the server-sent events of the response.
+func (h *DiagnoseHandler) streamRoundTrip(ctx context.Context, url string, header http.Header, body []byte) *StreamDiagnosis {
+	d := &StreamDiagnosis{Object: "streaming_diagnosis", URL: url}
+	d.Name = "streaming"
+	ctx, cancel := context.WithTimeout(ctx, streamDiagnosisTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		d.Status, d.Message = CheckFail, err.Error()
+		return d
+	}
+	req.Header = header
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+
+	start := time.Now()
+	resp, err := h.streamClient.Do(req)
+	if err != nil {
+		d.Status = CheckFail
+		d.Message = fmt.Sprintf("Request to %s failed: %v", url, err)
+		d.Remediation = "Check that maas-api can reach the gateway at the model's endpoint"
+		return d
+	}
+	defer resp.Body.Close()
+	d.StatusCode = resp.StatusCode
+	d.ContentType = resp.Header.Get("Content-Type")
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		d.Status = CheckFail
+		d.Message = fmt.Sprintf("The model answered %s: %s", resp.Status, strings.TrimSpace(string(snippet)))
+		d.Remediation = "Send your credentials in Authorization, and X-MaaS-Subscription if you have several subscriptions; see GET /admin/v1/diagnose for the policy chain"
+		return d
+	}
+
+	var first, last time.Time
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "data:") {
+			continue
+		}
+		now := time.Now()
+		if d.Events == 0 {
+			first = now
+			d.Deliveries = 1
+		} else if now.Sub(last) > streamBatchGap {
+			d.Deliveries++
+		}
+		last = now
+		d.Events++
+	}
+	d.DurationMs = time.Since(start).Milliseconds()
+	if d.Events > 0 {
+		d.TimeToFirstEventMs = first.Sub(start).Milliseconds()
+	}
+	if err := scanner.Err(); err != nil {
+		d.Status = CheckFail
+		d.Message = fmt.Sprintf("The stream broke after %d events: %v", d.Events, err)
+		d.Remediation = "Raise the model's spec.requestTimeout or spec.streamIdleTimeout if the stream was cut off"
+		return d
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(d.ContentType)
+	switch {
+	case mediaType != "text/event-stream":
+		d.Status = CheckFail
+		d.Message = fmt.Sprintf("The response is %q, not text/event-stream", d.ContentType)
+		d.Remediation = "Check that the model server supports stream: true and that no proxy rewrites the response"
+	case d.Events < 2:
+		d.Status = CheckWarn
+		d.Message = fmt.Sprintf("Only %d events arrived, too few to tell whether the stream is buffered", d.Events)
+		d.Remediation = "Try a model that generates a longer completion"
+	case d.Deliveries == 1:
+		d.Status = CheckFail
+		d.Message = fmt.Sprintf("All %d events arrived at once after %d ms: a proxy buffers the stream", d.Events, d.TimeToFirstEventMs)
+		d.Remediation = "Disable response buffering on proxies between clients and the model, e.g. compression or body-inspecting filters on the gateway"
+	default:
+		d.Status = CheckPass
+		d.Message = fmt.Sprintf("%d events arrived in %d deliveries over %d ms", d.Events, d.Deliveries, d.DurationMs)
+	}
+	return d
+}
@@ -0,0 +1,287 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// gettableModelRefs adds Get to fakeMaaSModelRefLister.
+type gettableModelRefs struct{ fakeMaaSModelRefLister }
+
+func (g gettableModelRefs) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	for _, u := range g.fakeMaaSModelRefLister[namespace] {
+		if u.GetName() == name {
+			return u, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "maasmodelrefs"}, name)
+}
+
+var diagnoseListKinds = map[schema.GroupVersionResource]string{
+	{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}:     "HTTPRouteList",
+	{Group: "kuadrant.io", Version: "v1", Resource: "authpolicies"}:                 "AuthPolicyList",
+	{Group: "kuadrant.io", Version: "v1", Resource: "ratelimitpolicies"}:            "RateLimitPolicyList",
+	{Group: "kuadrant.io", Version: "v1alpha1", Resource: "tokenratelimitpolicies"}: "TokenRateLimitPolicyList",
+}
+
+func kuadrantPolicy(apiVersion, kind, name, route string, enforced bool) *unstructured.Unstructured {
+	status := "True"
+	if !enforced {
+		status = "False"
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name, "namespace": "llm"},
+		"spec": map[string]any{
+			"targetRef": map[string]any{"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": route},
+		},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Accepted", "status": "True"},
+			map[string]any{"type": "Enforced", "status": status, "message": "overridden by gateway policy"},
+		}},
+	}}
+}
+
+func httpRoute(name string, accepted bool) *unstructured.Unstructured {
+	status := "True"
+	if !accepted {
+		status = "False"
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]any{"name": name, "namespace": "llm"},
+		"status": map[string]any{"parents": []any{map[string]any{
+			"parentRef": map[string]any{"name": "maas-default-gateway"},
+			"conditions": []any{
+				map[string]any{"type": "Accepted", "status": status, "message": "hostname mismatch"},
+				map[string]any{"type": "ResolvedRefs", "status": "True"},
+			},
+		}}},
+	}}
+}
+
+func diagnose(t *testing.T, lister gettableModelRefs, model string, objects ...runtime.Object) (*httptest.ResponseRecorder, *handlers.Diagnosis) {
+	t.Helper()
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), diagnoseListKinds, objects...)
+	h := handlers.NewDiagnoseHandler(logger.Development(), client, lister, fakeAdminChecker(true))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/v1/diagnose/*model", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	}, h.Diagnose)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/v1/diagnose/"+model, nil))
+
+	if w.Code != http.StatusOK {
+		return w, nil
+	}
+	var d handlers.Diagnosis
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	return w, &d
+}
+
+func checkStatuses(d *handlers.Diagnosis) map[string]handlers.CheckStatus {
+	out := map[string]handlers.CheckStatus{}
+	for _, c := range d.Checks {
+		out[c.Name] = c.Status
+	}
+	return out
+}
+
+func TestDiagnose(t *testing.T) {
+	granite := maasModelRefUnstructured("granite", "llm", "http://granite.llm.svc", true, nil)
+	_ = unstructured.SetNestedField(granite.Object, "granite-route", "status", "httpRouteName")
+	_ = unstructured.SetNestedField(granite.Object, "llm", "status", "httpRouteNamespace")
+	_ = unstructured.SetNestedStringSlice(granite.Object, []string{"granite-latest"}, "spec", "aliases")
+	pending := maasModelRefUnstructured("pending", "llm", "", false, nil)
+	lister := gettableModelRefs{fakeMaaSModelRefLister{"llm": {granite, pending}}}
+
+	t.Run("a healthy chain scores 100", func(t *testing.T) {
+		w, d := diagnose(t, lister, "llm/granite",
+			httpRoute("granite-route", true),
+			kuadrantPolicy("kuadrant.io/v1", "AuthPolicy", "maas-auth-granite", "granite-route", true),
+			kuadrantPolicy("kuadrant.io/v1alpha1", "TokenRateLimitPolicy", "maas-trlp-granite", "granite-route", true),
+		)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "llm/granite", d.Model)
+		assert.Equal(t, 100, d.Score)
+		assert.Equal(t, "healthy", d.Status)
+		assert.Equal(t, map[string]handlers.CheckStatus{
+			"model": handlers.CheckPass, "httproute": handlers.CheckPass, "authpolicy": handlers.CheckPass,
+			"ratelimitpolicy": handlers.CheckPass, "backend": handlers.CheckPass,
+		}, checkStatuses(d))
+	})
+
+	t.Run("bare names and aliases resolve", func(t *testing.T) {
+		for _, model := range []string{"granite", "granite-latest"} {
+			_, d := diagnose(t, lister, model, httpRoute("granite-route", true))
+			require.NotNil(t, d, model)
+			assert.Equal(t, "llm/granite", d.Model)
+		}
+	})
+
+	t.Run("missing and unenforced policies have remediation hints", func(t *testing.T) {
+		_, d := diagnose(t, lister, "llm/granite",
+			httpRoute("granite-route", true),
+			kuadrantPolicy("kuadrant.io/v1", "AuthPolicy", "maas-auth-granite", "granite-route", false),
+			kuadrantPolicy("kuadrant.io/v1alpha1", "TokenRateLimitPolicy", "other", "other-route", true),
+		)
+		require.NotNil(t, d)
+		assert.Equal(t, "unhealthy", d.Status)
+		assert.Equal(t, 70, d.Score, "three passes, a warning and a failure")
+		for _, c := range d.Checks {
+			switch c.Name {
+			case "authpolicy":
+				assert.Equal(t, handlers.CheckWarn, c.Status)
+				assert.Contains(t, c.Message, "overridden by gateway policy")
+				assert.NotEmpty(t, c.Remediation)
+			case "ratelimitpolicy":
+				assert.Equal(t, handlers.CheckFail, c.Status)
+				assert.Contains(t, c.Remediation, "MaaSSubscription")
+			}
+		}
+	})
+
+	t.Run("a rejected route fails its check", func(t *testing.T) {
+		_, d := diagnose(t, lister, "llm/granite", httpRoute("granite-route", false))
+		require.NotNil(t, d)
+		assert.Equal(t, handlers.CheckFail, checkStatuses(d)["httproute"])
+		assert.Contains(t, d.Checks[1].Message, "hostname mismatch")
+	})
+
+	t.Run("policy checks are skipped without a route", func(t *testing.T) {
+		_, d := diagnose(t, lister, "llm/pending")
+		require.NotNil(t, d)
+		assert.Equal(t, map[string]handlers.CheckStatus{
+			"model": handlers.CheckPass, "httproute": handlers.CheckFail, "authpolicy": handlers.CheckSkipped,
+			"ratelimitpolicy": handlers.CheckSkipped, "backend": handlers.CheckWarn,
+		}, checkStatuses(d))
+		assert.Equal(t, 30, d.Score)
+	})
+
+	t.Run("unknown models are not found", func(t *testing.T) {
+		w, _ := diagnose(t, lister, "llm/missing")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// diagnoseStreaming runs a streaming diagnosis of a model at endpoint with transport, or
+// http.DefaultTransport when it is nil.
+func diagnoseStreaming(t *testing.T, endpoint string, transport http.RoundTripper) *handlers.StreamDiagnosis {
+	t.Helper()
+	model := maasModelRefUnstructured("granite", "llm", endpoint, true, nil)
+	lister := gettableModelRefs{fakeMaaSModelRefLister{"llm": {model}}}
+	h := handlers.NewDiagnoseHandler(logger.Development(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), lister, fakeAdminChecker(true))
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	h.SetTransport(transport)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/admin/v1/diagnose-streaming/*model", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	}, h.DiagnoseStreaming)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/v1/diagnose-streaming/llm/granite", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var d handlers.StreamDiagnosis
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &d))
+	assert.Equal(t, "llm/granite", d.Model)
+	return &d
+}
+
+func TestDiagnoseStreaming(t *testing.T) {
+	events := []string{`{"choices":[{"delta":{"content":"1"}}]}`, `{"choices":[{"delta":{"content":"2"}}]}`, "[DONE]"}
+	var gotAuth string
+	var gotBody map[string]any
+	model := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, e := range events {
+			_, _ = w.Write([]byte("data: " + e + "\n\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer model.Close()
+
+	t.Run("a stream that flows passes", func(t *testing.T) {
+		d := diagnoseStreaming(t, model.URL, nil)
+		assert.Equal(t, handlers.CheckPass, d.Status, d.Message)
+		assert.Equal(t, 3, d.Events)
+		assert.Equal(t, 3, d.Deliveries)
+		assert.Equal(t, "Bearer admin-token", gotAuth, "the caller's credentials go through the gateway")
+		assert.Equal(t, true, gotBody["stream"])
+		assert.Equal(t, "granite", gotBody["model"])
+	})
+
+	t.Run("a buffered stream fails", func(t *testing.T) {
+		buffered := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(20 * time.Millisecond)
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, e := range events {
+				_, _ = w.Write([]byte("data: " + e + "\n\n"))
+			}
+		}))
+		defer buffered.Close()
+		d := diagnoseStreaming(t, buffered.URL, nil)
+		assert.Equal(t, handlers.CheckFail, d.Status)
+		assert.Equal(t, 3, d.Events)
+		assert.Equal(t, 1, d.Deliveries)
+		assert.NotEmpty(t, d.Remediation)
+	})
+
+	t.Run("errors from the gateway fail", func(t *testing.T) {
+		denied := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "no subscription", http.StatusForbidden)
+		}))
+		defer denied.Close()
+		d := diagnoseStreaming(t, denied.URL, nil)
+		assert.Equal(t, handlers.CheckFail, d.Status)
+		assert.Equal(t, http.StatusForbidden, d.StatusCode)
+		assert.Contains(t, d.Message, "no subscription")
+	})
+	t.Run("the gateway certificate is verified", func(t *testing.T) {
+		tlsModel := httptest.NewTLSServer(model.Config.Handler)
+		defer tlsModel.Close()
+		untrusted, err := handlers.NewGatewayTransport("")
+		require.NoError(t, err)
+		d := diagnoseStreaming(t, tlsModel.URL, untrusted)
+		assert.Equal(t, handlers.CheckFail, d.Status)
+		assert.Contains(t, d.Message, "certificate")
+
+		caFile := filepath.Join(t.TempDir(), "ca.crt")
+		require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsModel.Certificate().Raw}), 0o600))
+		trusted, err := handlers.NewGatewayTransport(caFile)
+		require.NoError(t, err)
+		d = diagnoseStreaming(t, tlsModel.URL, trusted)
+		assert.Equal(t, handlers.CheckPass, d.Status, d.Message)
+	})
+}
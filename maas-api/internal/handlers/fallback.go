@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+const (
+	// HeaderModelUsed names the model (namespace/name) that actually served a fallback request.
+	HeaderModelUsed = "X-MaaS-Model-Used"
+	// HeaderFallbackFrom names the requested model when a fallback served the request instead.
+	HeaderFallbackFrom = "X-MaaS-Fallback-From"
+
+	maxFallbackBodyBytes = 10 << 20
+)
+
+// forwardedRequestHeaders are copied from the caller to each model in the chain. The caller's
+// credentials go through the gateway again, so each hop is authorized and rate limited on its own.
+var forwardedRequestHeaders = []string{"Authorization", "Content-Type", "Accept", "X-MaaS-Subscription"}
+
+// hopByHopHeaders are not copied from the model response.
+var hopByHopHeaders = map[string]bool{
+	"Connection": true, "Keep-Alive": true, "Transfer-Encoding": true, "Upgrade": true,
+	"Proxy-Authenticate": true, "Proxy-Authorization": true, "Te": true, "Trailer": true,
+}
+
+// FallbackHandler proxies OpenAI-style inference requests along a model's fallback chain,
+// moving to the next model the caller is entitled to when one answers with a capacity error.
+type FallbackHandler struct {
+	logger               *logger.Logger
+	maasModelRefLister   models.MaaSModelRefLister
+	subscriptionSelector *subscription.Selector
+	httpClient           *http.Client
+	meter                *metering.Meter
+	notFound             *models.NotFoundCache
+}
+
+// NewFallbackHandler creates a handler for POST /v1/fallback/*path.
+func NewFallbackHandler(
+	log *logger.Logger,
+	maasModelRefLister models.MaaSModelRefLister,
+	subscriptionSelector *subscription.Selector,
+) *FallbackHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &FallbackHandler{
+		logger:               log,
+		maasModelRefLister:   maasModelRefLister,
+		subscriptionSelector: subscriptionSelector,
+		httpClient: &http.Client{
+			// No overall timeout: streamed completions can legitimately run for minutes.
+			Transport: gatewayTransport(&tls.Config{MinVersion: tls.VersionTLS12}),
+		},
+	}
+}
+
+// SetTransport sends the model requests with rt, which verifies the gateway's certificate,
+// instead of a transport trusting the system roots.
+func (h *FallbackHandler) SetTransport(rt http.RoundTripper) {
+	h.httpClient.Transport = rt
+}
+
+// SetMeter records the token counts the gateway reports on proxied responses.
+func (h *FallbackHandler) SetMeter(m *metering.Meter) {
+	h.meter = m
+}
+
+// fallbackTarget is one model in a resolved chain.
+type fallbackTarget struct {
+	namespace string
+	name      string
+	url       string
+}
+
+func (t fallbackTarget) ref() string { return t.namespace + "/" + t.name }
+
+// SetNotFoundCache answers repeated requests for an unknown model without scanning the model cache.
+func (h *FallbackHandler) SetNotFoundCache(c *models.NotFoundCache) {
+	h.notFound = c
+}
+
+// Proxy handles POST /v1/fallback/*path. The JSON body's "model" names the head of the chain;
+// *path is the inference path on the model, e.g. /v1/chat/completions.
+func (h *FallbackHandler) Proxy(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	user, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+
+	raw, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxFallbackBodyBytes))
+	if err != nil {
+		h.invalidRequest(c, "failed to read request body")
+		return
+	}
+	var body map[string]any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		h.invalidRequest(c, "request body must be a JSON object")
+		return
+	}
+	requested, _ := body["model"].(string)
+	if requested == "" {
+		h.invalidRequest(c, "model is required")
+		return
+	}
+
+	chain, err := h.resolveChain(requested, user, c.GetHeader("X-MaaS-Subscription"))
+	if err != nil {
+		h.logger.Error("Failed to resolve fallback chain", "error", err, "model", requested)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to resolve models")
+		return
+	}
+	if len(chain) == 0 {
+		apierror.New(apierror.ModelNotFound, "model "+requested+" not found or not available to you").
+			WithDetails(map[string]any{"model": requested}).
+			Respond(c, http.StatusNotFound)
+		return
+	}
+
+	path := c.Param("path")
+	for i, target := range chain {
+		body["model"] = target.name
+		payload, err := json.Marshal(body)
+		if err != nil {
+			h.invalidRequest(c, "failed to encode request body")
+			return
+		}
+
+		resp, err := h.forward(c, target.url+path, payload)
+		last := i == len(chain)-1
+		if err != nil {
+			h.logger.Warn("Fallback target unreachable", "model", target.ref(), "error", err)
+			if last {
+				apierror.Respond(c, http.StatusBadGateway, apierror.Unavailable, "no model in the fallback chain could be reached")
+				return
+			}
+			continue
+		}
+		if !last && isCapacityError(resp) {
+			h.logger.Debug("Capacity error, trying next model in chain",
+				"model", target.ref(), "status", resp.StatusCode, "username", user.Username)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+
+		c.Header(HeaderModelUsed, target.ref())
+		if i > 0 {
+			c.Header(HeaderFallbackFrom, chain[0].ref())
+		}
+		h.stream(c, resp)
+		h.meter.TokensFromHeaders(target.ref(), c.GetHeader("X-MaaS-Subscription"), user.Username, resp.Header, resp.Trailer)
+		return
+	}
+}
+
+// resolveChain returns the requested model followed by its fallback-models annotation (inherited
+// from the nearest ancestor for a variant without one), keeping only ready models with an endpoint that the caller is entitled to. requested may be a bare
+// MaaSModelRef name or namespace/name.
+func (h *FallbackHandler) resolveChain(requested string, user *token.UserContext, requestedSubscription string) ([]fallbackTarget, error) {
+	if h.notFound.Has(requested) {
+		return nil, nil
+	}
+	items, err := h.maasModelRefLister.List()
+	if err != nil {
+		return nil, err
+	}
+	byRef := make(map[string]*unstructured.Unstructured, len(items))
+	var head *unstructured.Unstructured
+	for _, u := range items {
+		byRef[u.GetNamespace()+"/"+u.GetName()] = u
+		if u.GetName() == requested && head == nil {
+			head = u
+		}
+	}
+	if strings.Contains(requested, "/") {
+		head = byRef[requested]
+	}
+	if head == nil {
+		h.notFound.Add(requested)
+		return nil, nil
+	}
+
+	refs := []string{head.GetNamespace() + "/" + head.GetName()}
+	source := fallbackSource(head, byRef)
+	for _, entry := range strings.Split(source.GetAnnotations()[constant.AnnotationFallbackModels], ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			entry = source.GetNamespace() + "/" + entry
+		}
+		refs = append(refs, entry)
+	}
+
+	var chain []fallbackTarget
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		u := byRef[ref]
+		if u == nil || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		m := models.FromMaaSModelRef(u)
+		if !m.Ready || m.URL == nil || !h.entitled(user, requestedSubscription, ref) {
+			continue
+		}
+		chain = append(chain, fallbackTarget{
+			namespace: u.GetNamespace(),
+			name:      u.GetName(),
+			url:       strings.TrimSuffix(m.URL.String(), "/"),
+		})
+	}
+	return chain, nil
+}
+
+// fallbackSource returns the model whose fallback-models annotation applies to head: head itself,
+// or for a variant without one, the nearest ancestor (status.lineage) that has it.
+func fallbackSource(head *unstructured.Unstructured, byRef map[string]*unstructured.Unstructured) *unstructured.Unstructured {
+	if _, ok := head.GetAnnotations()[constant.AnnotationFallbackModels]; ok {
+		return head
+	}
+	lineage, _, _ := unstructured.NestedStringSlice(head.Object, "status", "lineage")
+	for _, ref := range lineage {
+		if ancestor := byRef[ref]; ancestor != nil {
+			if _, ok := ancestor.GetAnnotations()[constant.AnnotationFallbackModels]; ok {
+				return ancestor
+			}
+		}
+	}
+	return head
+}
+
+// entitled reports whether any subscription the caller can use includes the model. A caller
+// who matches several subscriptions is entitled even if the gateway will ask them to choose.
+func (h *FallbackHandler) entitled(user *token.UserContext, requestedSubscription, modelRef string) bool {
+	if h.subscriptionSelector == nil {
+		return true
+	}
+	//nolint:unqueryvet,nolintlint // Select is a method, not a SQL query
+	_, err := h.subscriptionSelector.Select(user.Groups, user.Username, requestedSubscription, modelRef)
+	var multipleSubsErr *subscription.MultipleSubscriptionsError
+	return err == nil || errors.As(err, &multipleSubsErr)
+}
+
+func (h *FallbackHandler) forward(c *gin.Context, url string, payload []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range forwardedRequestHeaders {
+		if v := c.GetHeader(name); v != "" {
+			req.Header.Set(name, v)
+		}
+	}
+	return h.httpClient.Do(req)
+}
+
+// stream copies the model response to the caller, flushing as it goes so SSE streams stay live.
+func (h *FallbackHandler) stream(c *gin.Context, resp *http.Response) {
+	defer resp.Body.Close()
+	for name, values := range resp.Header {
+		if hopByHopHeaders[name] {
+			continue
+		}
+		for _, v := range values {
+			c.Writer.Header().Add(name, v)
+		}
+	}
+	c.Status(resp.StatusCode)
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				h.logger.Warn("Model response interrupted", "error", err)
+			}
+			return
+		}
+	}
+}
+
+func (h *FallbackHandler) invalidRequest(c *gin.Context, message string) {
+	apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, message)
+}
+
+// isCapacityError reports whether a model response means "try elsewhere": a 429 or 503 from the
+// model server. The gateway's own denials, such as the caller's rate limit or token budget, carry
+// x-ext-auth-reason or x-envoy-ratelimited and are passed on, since the next model would be
+// denied for the same caller. A 429 must also carry x-envoy-upstream-service-time, which Envoy
+// only sets on responses it received from the model server.
+func isCapacityError(resp *http.Response) bool {
+	if resp.Header.Get("X-Ext-Auth-Reason") != "" || resp.Header.Get("X-Envoy-Ratelimited") != "" {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return resp.Header.Get("X-Envoy-Upstream-Service-Time") != ""
+	case http.StatusServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
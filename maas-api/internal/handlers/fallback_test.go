@@ -0,0 +1,244 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// modelBackend answers every request with status and records the model it was asked for. Like
+// responses the gateway received from a model server, they carry x-envoy-upstream-service-time.
+func modelBackend(t *testing.T, status int, gotModel *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(modelHandler(t, status, gotModel, "X-Envoy-Upstream-Service-Time", "12"))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// modelHandler answers with status and the header pairs in headers, and records the model it
+// was asked for.
+func modelHandler(t *testing.T, status int, gotModel *string, headers ...string) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-oai-test", r.Header.Get("Authorization"))
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*gotModel, _ = body["model"].(string)
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"served_by":"`+*gotModel+`"}`)
+	})
+}
+
+func entitlingSubscription(models ...string) *unstructured.Unstructured {
+	refs := make([]any, 0, len(models))
+	for _, m := range models {
+		refs = append(refs, map[string]any{"name": m, "namespace": "llm"})
+	}
+	sub := &unstructured.Unstructured{}
+	sub.SetName("team")
+	sub.SetNamespace("models-as-a-service")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{map[string]any{"name": "team-a"}}, "spec", "owner", "groups")
+	_ = unstructured.SetNestedSlice(sub.Object, refs, "spec", "modelRefs")
+	return sub
+}
+
+func postFallback(h *handlers.FallbackHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/fallback/*path", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"team-a"}})
+	}, h.Proxy)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/fallback/v1/chat/completions", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer sk-oai-test")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestFallbackRetriesOnCapacityError(t *testing.T) {
+	var largeGot, mediumGot, smallGot string
+	large := modelBackend(t, http.StatusServiceUnavailable, &largeGot)
+	medium := modelBackend(t, http.StatusOK, &mediumGot)
+	small := modelBackend(t, http.StatusOK, &smallGot)
+
+	lister := fakeMaaSModelRefLister{"llm": {
+		maasModelRefUnstructured("gpt-large", "llm", large.URL, true,
+			map[string]string{constant.AnnotationFallbackModels: "gpt-restricted, gpt-medium, llm/gpt-small"}),
+		maasModelRefUnstructured("gpt-restricted", "llm", large.URL, true, nil),
+		maasModelRefUnstructured("gpt-medium", "llm", medium.URL, true, nil),
+		maasModelRefUnstructured("gpt-small", "llm", small.URL, true, nil),
+	}}
+	selector := subscription.NewSelector(logger.Development(), staticSubscriptionLister{
+		entitlingSubscription("gpt-large", "gpt-medium", "gpt-small"),
+	})
+	h := handlers.NewFallbackHandler(logger.Development(), lister, selector)
+
+	w := postFallback(h, `{"model":"gpt-large","messages":[]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "gpt-large", largeGot)
+	assert.Equal(t, "gpt-medium", mediumGot, "not-entitled gpt-restricted is skipped")
+	assert.Empty(t, smallGot, "chain stops at the first success")
+	assert.Equal(t, "llm/gpt-medium", w.Header().Get(handlers.HeaderModelUsed))
+	assert.Equal(t, "llm/gpt-large", w.Header().Get(handlers.HeaderFallbackFrom))
+	assert.JSONEq(t, `{"served_by":"gpt-medium"}`, w.Body.String())
+}
+
+func TestFallbackReturnsLastResponseWhenChainExhausted(t *testing.T) {
+	var largeGot, mediumGot string
+	large := modelBackend(t, http.StatusTooManyRequests, &largeGot)
+	medium := modelBackend(t, http.StatusTooManyRequests, &mediumGot)
+
+	lister := fakeMaaSModelRefLister{"llm": {
+		maasModelRefUnstructured("gpt-large", "llm", large.URL, true,
+			map[string]string{constant.AnnotationFallbackModels: "gpt-medium"}),
+		maasModelRefUnstructured("gpt-medium", "llm", medium.URL, true, nil),
+	}}
+	selector := subscription.NewSelector(logger.Development(), staticSubscriptionLister{
+		entitlingSubscription("gpt-large", "gpt-medium"),
+	})
+	h := handlers.NewFallbackHandler(logger.Development(), lister, selector)
+
+	w := postFallback(h, `{"model":"gpt-large"}`)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "llm/gpt-medium", w.Header().Get(handlers.HeaderModelUsed))
+}
+
+func TestFallbackPassesGatewayDenialsThrough(t *testing.T) {
+	denials := map[string][]string{
+		"rate limited by the AuthPolicy": {"X-Ext-Auth-Reason", "rate_limited"},
+		"rate limited by Limitador":      {"X-Envoy-Ratelimited", "true"},
+		"local reply":                    nil,
+	}
+	for name, headers := range denials {
+		t.Run(name, func(t *testing.T) {
+			var largeGot, mediumGot string
+			large := httptest.NewServer(modelHandler(t, http.StatusTooManyRequests, &largeGot, headers...))
+			t.Cleanup(large.Close)
+			medium := modelBackend(t, http.StatusOK, &mediumGot)
+
+			lister := fakeMaaSModelRefLister{"llm": {
+				maasModelRefUnstructured("gpt-large", "llm", large.URL, true,
+					map[string]string{constant.AnnotationFallbackModels: "gpt-medium"}),
+				maasModelRefUnstructured("gpt-medium", "llm", medium.URL, true, nil),
+			}}
+			selector := subscription.NewSelector(logger.Development(), staticSubscriptionLister{
+				entitlingSubscription("gpt-large", "gpt-medium"),
+			})
+			h := handlers.NewFallbackHandler(logger.Development(), lister, selector)
+
+			w := postFallback(h, `{"model":"gpt-large"}`)
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			assert.Empty(t, mediumGot, "the caller's own limit applies to every model in the chain")
+			assert.Equal(t, "llm/gpt-large", w.Header().Get(handlers.HeaderModelUsed))
+		})
+	}
+}
+
+func TestFallbackVerifiesGatewayCertificate(t *testing.T) {
+	var gotModel string
+	backend := httptest.NewTLSServer(modelHandler(t, http.StatusOK, &gotModel))
+	t.Cleanup(backend.Close)
+	lister := fakeMaaSModelRefLister{"llm": {maasModelRefUnstructured("granite", "llm", backend.URL, true, nil)}}
+	h := handlers.NewFallbackHandler(logger.Development(), lister, nil)
+
+	assert.Equal(t, http.StatusBadGateway, postFallback(h, `{"model":"granite"}`).Code, "an untrusted certificate is refused")
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: backend.Certificate().Raw}), 0o600))
+	transport, err := handlers.NewGatewayTransport(caFile)
+	require.NoError(t, err)
+	h.SetTransport(transport)
+	assert.Equal(t, http.StatusOK, postFallback(h, `{"model":"granite"}`).Code)
+	assert.Equal(t, "granite", gotModel)
+
+	_, err = handlers.NewGatewayTransport(filepath.Join(t.TempDir(), "missing.crt"))
+	assert.Error(t, err)
+}
+
+func TestFallbackRejectsUnknownModel(t *testing.T) {
+	h := handlers.NewFallbackHandler(logger.Development(), fakeMaaSModelRefLister{}, nil)
+
+	assert.Equal(t, http.StatusNotFound, postFallback(h, `{"model":"missing"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postFallback(h, `{"messages":[]}`).Code)
+}
+
+// countingLister counts the full scans of the model cache.
+type countingLister struct {
+	fakeMaaSModelRefLister
+	lists int
+}
+
+func (c *countingLister) List() ([]*unstructured.Unstructured, error) {
+	c.lists++
+	return c.fakeMaaSModelRefLister.List()
+}
+
+func TestFallbackCachesUnknownModel(t *testing.T) {
+	var gotModel string
+	backend := modelBackend(t, http.StatusOK, &gotModel)
+	lister := &countingLister{fakeMaaSModelRefLister: fakeMaaSModelRefLister{}}
+	notFound := models.NewNotFoundCache(time.Minute)
+	h := handlers.NewFallbackHandler(logger.Development(), lister, nil)
+	h.SetNotFoundCache(notFound)
+
+	assert.Equal(t, http.StatusNotFound, postFallback(h, `{"model":"granite"}`).Code)
+	assert.Equal(t, http.StatusNotFound, postFallback(h, `{"model":"granite"}`).Code)
+	assert.Equal(t, 1, lister.lists, "the second lookup should be answered from the negative cache")
+
+	granite := maasModelRefUnstructured("granite", "llm", backend.URL, true, nil)
+	lister.fakeMaaSModelRefLister["llm"] = []*unstructured.Unstructured{granite}
+	notFound.EventHandler().OnAdd(granite, false)
+
+	assert.Equal(t, http.StatusOK, postFallback(h, `{"model":"granite"}`).Code, "adding the model invalidates the entry")
+	assert.Equal(t, "granite", gotModel)
+}
+
+func TestFallbackVariantInheritsParentChain(t *testing.T) {
+	var tunedGot, smallGot string
+	tuned := modelBackend(t, http.StatusServiceUnavailable, &tunedGot)
+	small := modelBackend(t, http.StatusOK, &smallGot)
+
+	variant := maasModelRefUnstructured("gpt-large-legal", "llm", tuned.URL, true, nil)
+	_ = unstructured.SetNestedStringSlice(variant.Object, []string{"llm/gpt-large"}, "status", "lineage")
+	lister := fakeMaaSModelRefLister{"llm": {
+		maasModelRefUnstructured("gpt-large", "llm", tuned.URL, true,
+			map[string]string{constant.AnnotationFallbackModels: "gpt-small"}),
+		variant,
+		maasModelRefUnstructured("gpt-small", "llm", small.URL, true, nil),
+	}}
+	selector := subscription.NewSelector(logger.Development(), staticSubscriptionLister{
+		entitlingSubscription("gpt-large-legal", "gpt-small"),
+	})
+	h := handlers.NewFallbackHandler(logger.Development(), lister, selector)
+
+	w := postFallback(h, `{"model":"gpt-large-legal"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "gpt-large-legal", tunedGot)
+	assert.Equal(t, "gpt-small", smallGot, "variant uses the parent's fallback-models")
+	assert.Equal(t, "llm/gpt-small", w.Header().Get(handlers.HeaderModelUsed))
+}
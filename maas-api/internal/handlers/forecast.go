@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+const (
+	defaultForecastLookback = 7 * 24 * time.Hour
+	maxForecastLookback     = 90 * 24 * time.Hour
+)
+
+// ForecastSource resolves the token budgets and organizations of the subscriptions in the ledger.
+type ForecastSource interface {
+	// TokenBudget returns the budget under a model-scoped subscription key, or nil.
+	TokenBudget(subscriptionKey string) *subscription.TokenBudget
+	// Organizations returns the organization ID of each subscription, by subscription name.
+	Organizations() map[string]string
+}
+
+// UsageForecast projects the token consumption of one subscription and model to the end of the
+// current period: the subscription's budget window for the model, or the calendar month.
+type UsageForecast struct {
+	Organization    string          `json:"organization,omitempty"`
+	Subscription    string          `json:"subscription"`
+	Model           string          `json:"model"`
+	PeriodStart     time.Time       `json:"periodStart"`
+	PeriodEnd       time.Time       `json:"periodEnd"`
+	UsedTokens      int64           `json:"usedTokens"`
+	ProjectedTokens int64           `json:"projectedTokens"`
+	TokenBudget     *ForecastBudget `json:"tokenBudget,omitempty"`
+	// OnTrackToExceed is true when at least one user is projected to spend their budget.
+	OnTrackToExceed bool `json:"onTrackToExceed"`
+}
+
+// ForecastBudget is the per-user budget of a forecast and the users projected to exceed it.
+type ForecastBudget struct {
+	Limit  int64              `json:"limit"`
+	Period string             `json:"period"`
+	AtRisk []UserBudgetReport `json:"atRisk,omitempty"`
+}
+
+// UserBudgetReport is one user's consumption of a budget.
+type UserBudgetReport struct {
+	User            string `json:"user"`
+	UsedTokens      int64  `json:"usedTokens"`
+	ProjectedTokens int64  `json:"projectedTokens"`
+}
+
+// SetForecastSource makes forecasts use subscription budgets and organizations. Without it every
+// forecast covers the calendar month and has no budget.
+func (h *UsageHandler) SetForecastSource(source ForecastSource) {
+	h.forecastSource = source
+}
+
+// GetForecast handles GET /v1/usage/forecast. Admins see every user's usage; other users only
+// their own. Query parameters:
+//   - model: namespace/name of one model.
+//   - user: one user's usage (admins only).
+//   - lookback: the recent history the trend is taken from, in hours or days. Defaults to 7d.
+//   - method: linear (default) or seasonal.
+func (h *UsageHandler) GetForecast(c *gin.Context) {
+	user := userFrom(c)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	admin := h.adminChecker.IsAdmin(c.Request.Context(), user)
+
+	now := h.now().UTC()
+	q := usage.Query{
+		To:       now,
+		GroupBy:  []usage.Dimension{usage.DimensionUser, usage.DimensionTier, usage.DimensionModel},
+		Interval: usage.IntervalHour,
+		User:     c.Query("user"),
+		Model:    c.Query("model"),
+	}
+	if !admin {
+		if q.User != "" && q.User != user.Username {
+			apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required to forecast other users' usage")
+			return
+		}
+		q.User = user.Username
+	}
+	if q.Model != "" {
+		if ns, name, ok := strings.Cut(q.Model, "/"); !ok || ns == "" || name == "" {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, fmt.Sprintf("invalid model %q: must be namespace/name", q.Model))
+			return
+		}
+	}
+	lookback := defaultForecastLookback
+	if s := c.Query("lookback"); s != "" {
+		d, err := quota.ParsePeriod(s)
+		if err != nil || d > maxForecastLookback {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, fmt.Sprintf("invalid lookback %q: must be hours or days up to 90d", s))
+			return
+		}
+		lookback = d
+	}
+	method, err := usage.ParseMethod(c.Query("method"))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+
+	// The ledger is read from the start of the month or of the lookback, whichever is earlier.
+	// Budget windows that start before that are topped up below.
+	historyEnd := now.Truncate(time.Hour)
+	q.From = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if start := historyEnd.Add(-lookback); start.Before(q.From) {
+		q.From = start
+	}
+	rows, err := h.store.Query(c.Request.Context(), q)
+	if err != nil {
+		h.logger.Error("Failed to query usage", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to query usage")
+		return
+	}
+
+	type userKey struct{ user, subscription, model string }
+	hourly := map[userKey]map[time.Time]int64{}
+	for _, row := range rows {
+		key := userKey{row.User, row.Tier, row.Model}
+		if hourly[key] == nil {
+			hourly[key] = map[time.Time]int64{}
+		}
+		hourly[key][row.Period] = row.TotalTokens
+	}
+
+	var orgs map[string]string
+	if h.forecastSource != nil {
+		orgs = h.forecastSource.Organizations()
+	}
+	forecasts := map[[2]string]*UsageForecast{}
+	for key, hours := range hourly {
+		f, ok := forecasts[[2]string{key.subscription, key.model}]
+		if !ok {
+			f = h.newForecast(key.subscription, key.model, now, orgs)
+			forecasts[[2]string{key.subscription, key.model}] = f
+		}
+
+		var used int64
+		for hour, tokens := range hours {
+			if !hour.Before(f.PeriodStart) {
+				used += tokens
+			}
+		}
+		if f.PeriodStart.Before(q.From) {
+			earlier, err := h.store.Query(c.Request.Context(), usage.Query{From: f.PeriodStart, To: q.From, User: key.user, Model: key.model})
+			if err != nil {
+				h.logger.Error("Failed to query usage", "error", err)
+				apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to query usage")
+				return
+			}
+			for _, row := range earlier {
+				used += row.TotalTokens
+			}
+		}
+		history := make([]int64, 0, int(lookback/time.Hour))
+		for hour := historyEnd.Add(-lookback); hour.Before(historyEnd); hour = hour.Add(time.Hour) {
+			history = append(history, hours[hour])
+		}
+		projected := used + usage.Project(history, now, f.PeriodEnd, method)
+
+		f.UsedTokens += used
+		f.ProjectedTokens += projected
+		if f.TokenBudget != nil && projected > f.TokenBudget.Limit {
+			f.TokenBudget.AtRisk = append(f.TokenBudget.AtRisk, UserBudgetReport{User: key.user, UsedTokens: used, ProjectedTokens: projected})
+			f.OnTrackToExceed = true
+		}
+	}
+
+	data := make([]UsageForecast, 0, len(forecasts))
+	for _, f := range forecasts {
+		if f.TokenBudget != nil {
+			slices.SortFunc(f.TokenBudget.AtRisk, func(a, b UserBudgetReport) int { return cmp.Compare(a.User, b.User) })
+		}
+		data = append(data, *f)
+	}
+	slices.SortFunc(data, func(a, b UsageForecast) int {
+		return cmp.Or(cmp.Compare(a.Subscription, b.Subscription), cmp.Compare(a.Model, b.Model))
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"object":      "list",
+		"generatedAt": now.Format(time.RFC3339),
+		"lookback":    lookback.String(),
+		"method":      method,
+		"data":        data,
+	})
+}
+
+// newForecast starts the forecast of a subscription and model at now, over the subscription's
+// budget window when it has a valid budget for the model and the calendar month otherwise.
+func (h *UsageHandler) newForecast(sub, model string, now time.Time, orgs map[string]string) *UsageForecast {
+	_, subName, _ := strings.Cut(sub, "/")
+	f := &UsageForecast{
+		Organization: orgs[subName],
+		Subscription: sub,
+		Model:        model,
+		PeriodStart:  time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if h.forecastSource == nil {
+		return f
+	}
+	budget := h.forecastSource.TokenBudget(sub + "@" + model)
+	if budget == nil {
+		return f
+	}
+	period, err := quota.ParsePeriod(budget.Period)
+	if err != nil {
+		return f
+	}
+	f.PeriodStart, f.PeriodEnd = quota.Window(now, period)
+	f.TokenBudget = &ForecastBudget{Limit: budget.Limit, Period: budget.Period}
+	return f
+}
@@ -0,0 +1,127 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+type fakeForecastSource struct{}
+
+func (fakeForecastSource) TokenBudget(key string) *subscription.TokenBudget {
+	if key == "maas/premium@llm/granite" {
+		return &subscription.TokenBudget{Limit: 2000, Period: "1d"}
+	}
+	return nil
+}
+
+func (fakeForecastSource) Organizations() map[string]string {
+	return map[string]string{"premium": "acme"}
+}
+
+func forecastHandler(t *testing.T, admin bool) *handlers.UsageHandler {
+	t.Helper()
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	store := usage.NewMemoryStore()
+	// alice has used 100 tokens every hour for the last day.
+	for h := 1; h <= 24; h++ {
+		require.NoError(t, store.Add(context.Background(), usage.Record{
+			Time: now.Add(-time.Duration(h) * time.Hour), User: "alice", Model: "llm/granite", Subscription: "maas/premium", TotalTokens: 100,
+		}))
+	}
+	require.NoError(t, store.Add(context.Background(), usage.Record{
+		Time: now.Add(-5 * time.Hour), User: "bob", Model: "llm/granite", Subscription: "maas/premium", TotalTokens: 10,
+	}))
+	require.NoError(t, store.Add(context.Background(), usage.Record{
+		Time: time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC), User: "bob", Model: "llm/llama", Subscription: "maas/free", TotalTokens: 50,
+	}))
+	h := handlers.NewUsageHandler(logger.Development(), store, fakeAdminChecker(admin))
+	h.SetClock(func() time.Time { return now })
+	h.SetForecastSource(fakeForecastSource{})
+	return h
+}
+
+func getForecast(t *testing.T, h *handlers.UsageHandler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/usage/forecast", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice"})
+	}, h.GetForecast)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/usage/forecast"+query, nil))
+	return w
+}
+
+func decodeForecasts(t *testing.T, w *httptest.ResponseRecorder) []handlers.UsageForecast {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var body struct {
+		Data []handlers.UsageForecast `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Data
+}
+
+func TestGetForecast(t *testing.T) {
+	t.Run("admin", func(t *testing.T) {
+		data := decodeForecasts(t, getForecast(t, forecastHandler(t, true), "?lookback=24h"))
+		require.Len(t, data, 2)
+
+		free := data[0]
+		assert.Equal(t, "maas/free", free.Subscription)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), free.PeriodStart, "without a budget the period is the month")
+		assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), free.PeriodEnd)
+		assert.Equal(t, int64(50), free.UsedTokens)
+		assert.Equal(t, int64(50), free.ProjectedTokens, "no usage in the lookback projects none")
+		assert.Nil(t, free.TokenBudget)
+
+		premium := data[1]
+		assert.Equal(t, "acme", premium.Organization)
+		assert.Equal(t, "llm/granite", premium.Model)
+		assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), premium.PeriodStart, "the budget window")
+		assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), premium.PeriodEnd)
+		assert.Equal(t, int64(1210), premium.UsedTokens)
+		assert.True(t, premium.OnTrackToExceed)
+		require.NotNil(t, premium.TokenBudget)
+		assert.Equal(t, []handlers.UserBudgetReport{{User: "alice", UsedTokens: 1200, ProjectedTokens: 2400}}, premium.TokenBudget.AtRisk,
+			"alice keeps spending 100 tokens an hour; bob's single request is well within the budget")
+	})
+
+	t.Run("users see their own usage", func(t *testing.T) {
+		h := forecastHandler(t, false)
+		data := decodeForecasts(t, getForecast(t, h, "?method=seasonal&lookback=24h"))
+		require.Len(t, data, 1)
+		assert.Equal(t, int64(1200), data[0].UsedTokens)
+		assert.Equal(t, int64(2400), data[0].ProjectedTokens)
+
+		assert.Equal(t, http.StatusForbidden, getForecast(t, h, "?user=bob").Code)
+	})
+
+	t.Run("one model", func(t *testing.T) {
+		data := decodeForecasts(t, getForecast(t, forecastHandler(t, true), "?model=llm/llama"))
+		require.Len(t, data, 1)
+		assert.Equal(t, "maas/free", data[0].Subscription)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		h := forecastHandler(t, true)
+		for _, query := range []string{"?model=granite", "?lookback=1w", "?lookback=91d", "?method=arima"} {
+			assert.Equal(t, http.StatusBadRequest, getForecast(t, h, query).Code, query)
+		}
+	})
+}
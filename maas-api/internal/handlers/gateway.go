@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+// NewGatewayTransport returns the transport of the model requests maas-api sends through the
+// gateway. It verifies the gateway's certificate against the CAs in caFile, or the system roots
+// when caFile is empty.
+func NewGatewayTransport(caFile string) (http.RoundTripper, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gateway CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in gateway CA bundle %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return gatewayTransport(tlsConfig), nil
+}
+
+func gatewayTransport(tlsConfig *tls.Config) http.RoundTripper {
+	return tracing.Transport(&http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		TLSClientConfig:       tlsConfig,
+		ResponseHeaderTimeout: 2 * time.Minute,
+	})
+}
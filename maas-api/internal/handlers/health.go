@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/dependency"
 )
 
 // HealthHandler handles health check endpoints.
@@ -18,3 +21,56 @@ func NewHealthHandler() *HealthHandler {
 func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 }
+
+// ReadinessHandler reports whether the informer caches ext_authz and model lookups read from are
+// populated, so a gateway or readiness probe can hold traffic until they are.
+type ReadinessHandler struct {
+	cachesSynced func() map[string]bool
+	dependencies DependencyChecker
+}
+
+// DependencyChecker reports the health of the services inference requests pass through besides
+// maas-api, keyed by name.
+type DependencyChecker interface {
+	Check(ctx context.Context) map[string]dependency.Status
+}
+
+// NewReadinessHandler creates a readiness handler. cachesSynced reports sync state per resource.
+func NewReadinessHandler(cachesSynced func() map[string]bool) *ReadinessHandler {
+	if cachesSynced == nil {
+		panic("cachesSynced cannot be nil")
+	}
+	return &ReadinessHandler{cachesSynced: cachesSynced}
+}
+
+// SetDependencyChecker makes readiness also require the checked dependencies to be healthy, so
+// orchestration notices a broken gateway, Authorino or Limitador. Nil checks only the caches.
+func (h *ReadinessHandler) SetDependencyChecker(checker DependencyChecker) {
+	h.dependencies = checker
+}
+
+// ReadinessCheck handles GET /ready. It returns 503 until every cache has synced and while any
+// checked dependency is unhealthy.
+func (h *ReadinessHandler) ReadinessCheck(c *gin.Context) {
+	caches := h.cachesSynced()
+	ready := true
+	for _, synced := range caches {
+		ready = ready && synced
+	}
+	body := gin.H{"caches": caches}
+	if h.dependencies != nil {
+		dependencies := h.dependencies.Check(c.Request.Context())
+		for _, status := range dependencies {
+			ready = ready && status.Healthy
+		}
+		body["dependencies"] = dependencies
+	}
+
+	if !ready {
+		body["status"] = "not_ready"
+		c.JSON(http.StatusServiceUnavailable, body)
+		return
+	}
+	body["status"] = "ready"
+	c.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,83 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/dependency"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+)
+
+type staticDependencies map[string]dependency.Status
+
+func (s staticDependencies) Check(context.Context) map[string]dependency.Status { return s }
+
+func TestReadinessCheck(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name         string
+		caches       map[string]bool
+		dependencies staticDependencies
+		wantCode     int
+		wantStatus   string
+	}{
+		{
+			name:       "caches_synced",
+			caches:     map[string]bool{"maasmodelrefs": true},
+			wantCode:   http.StatusOK,
+			wantStatus: "ready",
+		},
+		{
+			name:       "cache_not_synced",
+			caches:     map[string]bool{"maasmodelrefs": false},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "not_ready",
+		},
+		{
+			name:         "dependencies_healthy",
+			caches:       map[string]bool{"maasmodelrefs": true},
+			dependencies: staticDependencies{dependency.Gateway: {Healthy: true}, dependency.Limitador: {Healthy: true}},
+			wantCode:     http.StatusOK,
+			wantStatus:   "ready",
+		},
+		{
+			name:   "dependency_unhealthy",
+			caches: map[string]bool{"maasmodelrefs": true},
+			dependencies: staticDependencies{
+				dependency.Gateway:   {Healthy: true},
+				dependency.Authorino: {Message: "no AuthConfigs in namespace kuadrant-system"},
+			},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: "not_ready",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness := handlers.NewReadinessHandler(func() map[string]bool { return tt.caches })
+			if tt.dependencies != nil {
+				readiness.SetDependencyChecker(tt.dependencies)
+			}
+			router := gin.New()
+			router.GET("/ready", readiness.ReadinessCheck)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			require.Equal(t, tt.wantCode, w.Code)
+
+			var body struct {
+				Status       string                       `json:"status"`
+				Dependencies map[string]dependency.Status `json:"dependencies"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantStatus, body.Status)
+			assert.Equal(t, map[string]dependency.Status(tt.dependencies), body.Dependencies)
+		})
+	}
+}
@@ -2,19 +2,25 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
-	"sort"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/openai/openai-go/v2/packages/pagination"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
 )
 
+// maxModelListLimit caps the page size of GET /v1/models.
+const maxModelListLimit = 500
+
 // ModelsHandler handles model-related endpoints.
 type ModelsHandler struct {
 	modelMgr             *models.Manager
@@ -56,11 +62,7 @@ func (h *ModelsHandler) selectSubscriptionsForListing(
 			allSubs, err := h.subscriptionSelector.GetAllAccessible(userContext.Groups, userContext.Username)
 			if err != nil {
 				h.logger.Error("Failed to get all accessible subscriptions", "error", err)
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": gin.H{
-						"message": "Failed to get subscriptions",
-						"type":    "server_error",
-					}})
+				apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to get subscriptions")
 				return nil, true
 			}
 			h.logger.Debug("User token - returning models from all accessible subscriptions", "subscriptionCount", len(allSubs))
@@ -68,11 +70,7 @@ func (h *ModelsHandler) selectSubscriptionsForListing(
 		}
 		// No selector configured - cannot return all models
 		h.logger.Debug("Subscription selector not configured")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Subscription system not configured",
-				"type":    "server_error",
-			}})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Subscription system not configured")
 		return nil, true
 	}
 
@@ -113,51 +111,31 @@ func (h *ModelsHandler) handleSubscriptionSelectionError(c *gin.Context, err err
 		h.logger.Debug("API key has no subscription bound - invalid state",
 			"subscriptionCount", len(multipleSubsErr.Subscriptions),
 		)
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"message": "API key has no subscription bound",
-				"type":    "permission_error",
-			}})
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "API key has no subscription bound")
 		return
 	}
 
 	if errors.As(err, &accessDeniedErr) {
 		h.logger.Debug("Access denied to subscription")
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"message": err.Error(),
-				"type":    "permission_error",
-			}})
+		apierror.Respond(c, http.StatusForbidden, apierror.TierDenied, err.Error())
 		return
 	}
 
 	if errors.As(err, &notFoundErr) {
 		h.logger.Debug("Subscription not found")
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"message": err.Error(),
-				"type":    "permission_error",
-			}})
+		apierror.Respond(c, http.StatusForbidden, apierror.SubscriptionNotFound, err.Error())
 		return
 	}
 
 	if errors.As(err, &noSubErr) {
 		h.logger.Debug("No subscription found for user")
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"message": err.Error(),
-				"type":    "permission_error",
-			}})
+		apierror.Respond(c, http.StatusForbidden, apierror.SubscriptionNotFound, err.Error())
 		return
 	}
 
 	// Other errors are internal server errors
 	h.logger.Error("Subscription selection failed", "error", err)
-	c.JSON(http.StatusInternalServerError, gin.H{
-		"error": gin.H{
-			"message": "Failed to select subscription",
-			"type":    "server_error",
-		}})
+	apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to select subscription")
 }
 
 // addSubscriptionIfNew adds a subscription to the model's subscriptions array if not already present.
@@ -170,19 +148,37 @@ func (h *ModelsHandler) addSubscriptionIfNew(model *models.Model, subInfo models
 	model.Subscriptions = append(model.Subscriptions, subInfo)
 }
 
-// ListLLMs handles GET /v1/models.
+// modelListResponse is the GET /v1/models response: an OpenAI model list, with a continue token while
+// more pages remain.
+type modelListResponse struct {
+	Object   string         `json:"object"`
+	Data     []models.Model `json:"data"`
+	Continue string         `json:"continue,omitempty"`
+}
+
+// ListLLMs handles GET /v1/models. Query parameters, all optional:
+//   - namespace: keep only the models of one namespace.
+//   - tier: keep only the models of one of the caller's subscriptions.
+//   - ready: true or false keeps only models that are or are not Ready.
+//   - sort (id, created or namespace; default id) and order (asc or desc; default asc) order the
+//     MaaSModelRefs; id is the MaaSModelRef name.
+//   - limit (at most 500) and continue page through the models. A page holds at most limit
+//     MaaSModelRefs, before access is checked, so it can hold fewer accessible models; follow
+//     continue until the response has none.
 func (h *ModelsHandler) ListLLMs(c *gin.Context) {
 	// Require Authorization header and pass it through as-is to list and access validation.
 	authHeader := strings.TrimSpace(c.GetHeader("Authorization"))
 	if authHeader == "" {
 		h.logger.Error("Authorization header missing")
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"message": "Authorization required",
-				"type":    "authentication_error",
-			}})
+		apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authorization required")
 		return
 	}
+	listOpts, err := parseModelListOptions(c)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+	tier := c.Query("tier")
 
 	// Extract x-maas-subscription header.
 	// For API keys: Authorino injects this from auth.metadata.apiKeyValidation.subscription
@@ -193,11 +189,7 @@ func (h *ModelsHandler) ListLLMs(c *gin.Context) {
 	// Fail closed: API keys without a bound subscription must be rejected
 	if isAPIKeyRequest && requestedSubscription == "" {
 		h.logger.Debug("API key request missing bound subscription header")
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"message": "API key has no subscription bound",
-				"type":    "permission_error",
-			}})
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "API key has no subscription bound")
 		return
 	}
 
@@ -213,22 +205,14 @@ func (h *ModelsHandler) ListLLMs(c *gin.Context) {
 		userContextVal, exists := c.Get("user")
 		if !exists {
 			h.logger.Error("User context not found - ExtractUserInfo middleware not called")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"message": "Internal server error",
-					"type":    "server_error",
-				}})
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
 			return
 		}
 		var ok bool
 		userContext, ok = userContextVal.(*token.UserContext)
 		if !ok {
 			h.logger.Error("Invalid user context type")
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"message": "Internal server error",
-					"type":    "server_error",
-				}})
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
 			return
 		}
 	}
@@ -247,21 +231,28 @@ func (h *ModelsHandler) ListLLMs(c *gin.Context) {
 	if shouldReturn {
 		return
 	}
+	if tier != "" {
+		subscriptionsToUse = slices.DeleteFunc(subscriptionsToUse, func(sub *subscription.SelectResponse) bool {
+			return sub.Name != tier
+		})
+	}
 
 	// Initialize to empty slice (not nil) so JSON marshals as [] instead of null
 	modelList := []models.Model{}
-	if h.maasModelRefLister != nil {
-		h.logger.Debug("Listing models from MaaSModelRef cache (all namespaces)")
-		list, err := models.ListFromMaaSModelRefLister(h.maasModelRefLister)
+	next := ""
+	if h.maasModelRefLister != nil && (tier == "" || len(subscriptionsToUse) > 0) {
+		h.logger.Debug("Listing models from MaaSModelRef cache", "namespace", listOpts.Namespace)
+		list, continueToken, err := models.ListPage(h.maasModelRefLister, listOpts)
+		if errors.Is(err, models.ErrInvalidContinue) {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "Invalid continue token; it must come from a listing with the same sort and order")
+			return
+		}
 		if err != nil {
 			h.logger.Error("Listing from MaaSModelRef failed", "error", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"message": "Failed to list models",
-					"type":    "server_error",
-				}})
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list models")
 			return
 		}
+		next = continueToken
 
 		// Distinguish between "no subscription system" and "user has zero subscriptions"
 		if len(subscriptionsToUse) == 0 {
@@ -332,37 +323,64 @@ func (h *ModelsHandler) ListLLMs(c *gin.Context) {
 				}
 			}
 
-			// Convert map to slice with deterministic ordering
-			keys := make([]modelKey, 0, len(modelsByKey))
-			for k := range modelsByKey {
-				keys = append(keys, k)
-			}
-			sort.Slice(keys, func(i, j int) bool {
-				if keys[i].id != keys[j].id {
-					return keys[i].id < keys[j].id
-				}
-				if keys[i].url != keys[j].url {
-					return keys[i].url < keys[j].url
-				}
-				return keys[i].ownedBy < keys[j].ownedBy
-			})
-			for _, k := range keys {
-				modelList = append(modelList, *modelsByKey[k])
+			for _, model := range modelsByKey {
+				modelList = append(modelList, *model)
 			}
 		}
+		// Deterministic ordering, as the MaaSModelRefs were paged
+		models.SortByPage(modelList, list)
 
 		h.logger.Debug("Access validation complete", "listed", len(list), "accessible", len(modelList), "subscriptions", len(subscriptionsToUse))
-	} else {
+	} else if h.maasModelRefLister == nil {
 		h.logger.Debug("MaaSModelRef lister not configured, returning empty model list")
 	}
 
-	h.logger.Debug("GET /v1/models returning models", "count", len(modelList))
-	c.JSON(http.StatusOK, pagination.Page[models.Model]{
-		Object: "list",
-		Data:   modelList,
+	h.logger.Debug("GET /v1/models returning models", "count", len(modelList), "more", next != "")
+	c.JSON(http.StatusOK, modelListResponse{
+		Object:   "list",
+		Data:     modelList,
+		Continue: next,
 	})
 }
 
+// parseModelListOptions reads the namespace, ready, sort, order, limit and continue parameters of
+// GET /v1/models.
+func parseModelListOptions(c *gin.Context) (models.ListOptions, error) {
+	opts := models.ListOptions{
+		Namespace: c.Query("namespace"),
+		Sort:      c.DefaultQuery("sort", models.SortByID),
+		Continue:  c.Query("continue"),
+	}
+	switch opts.Sort {
+	case models.SortByID, models.SortByCreated, models.SortByNamespace:
+	default:
+		return models.ListOptions{}, fmt.Errorf("invalid sort %q: must be id, created or namespace", opts.Sort)
+	}
+	switch order := c.DefaultQuery("order", "asc"); order {
+	case "asc":
+	case "desc":
+		opts.Descending = true
+	default:
+		return models.ListOptions{}, fmt.Errorf("invalid order %q: must be asc or desc", order)
+	}
+	if ready := c.Query("ready"); ready != "" {
+		value, err := strconv.ParseBool(ready)
+		if err != nil {
+			return models.ListOptions{}, fmt.Errorf("invalid ready %q: must be true or false", ready)
+		}
+		opts.Ready = &value
+	}
+	if limit := c.Query("limit"); limit != "" {
+		var err error
+		if opts.Limit, err = strconv.Atoi(limit); err != nil || opts.Limit < 1 {
+			return models.ListOptions{}, errors.New("limit must be a positive integer")
+		}
+		// Silently cap at maximum, as API key search does.
+		opts.Limit = min(opts.Limit, maxModelListLimit)
+	}
+	return opts, nil
+}
+
 // filterModelsBySubscription filters models to only those matching the subscription's modelRefs.
 func filterModelsBySubscription(modelList []models.Model, modelRefs []subscription.ModelRefInfo) []models.Model {
 	if len(modelRefs) == 0 {
@@ -388,3 +406,91 @@ func filterModelsBySubscription(modelList []models.Model, modelRefs []subscripti
 
 	return filtered
 }
+
+// GetExamples handles GET /v1/models/:name/examples. It returns the model's documentation link
+// and example requests as curl commands against the model's endpoint, with a placeholder for the
+// API key. A bare name is resolved as for inference; ?namespace= selects the model directly.
+func (h *ModelsHandler) GetExamples(c *gin.Context) {
+	u, status, err := h.lookupModel(c.Param("name"), c.Query("namespace"))
+	if err != nil {
+		apiErr := apierror.New(apierror.InvalidRequest, err.Error())
+		if status == http.StatusNotFound {
+			// Keep the not_found_error type this endpoint has always returned.
+			apiErr = apierror.New(apierror.ModelNotFound, err.Error()).WithType("not_found_error")
+		} else if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to look up model", "error", err, "model", c.Param("name"))
+			apiErr = apierror.New(apierror.InternalError, err.Error())
+		}
+		apiErr.WithDetails(map[string]any{"model": c.Param("name")}).Respond(c, status)
+		return
+	}
+	c.JSON(http.StatusOK, models.ExamplesFor(u, modelBaseURL(c, u)))
+}
+
+// lookupModel returns the MaaSModelRef named name, in namespace when set, with the HTTP status of a failure.
+func (h *ModelsHandler) lookupModel(name, namespace string) (*unstructured.Unstructured, int, error) {
+	if h.maasModelRefLister == nil {
+		return nil, http.StatusNotFound, models.ErrModelNotFound
+	}
+	if namespace == "" {
+		resolved, err := models.NamespaceResolver(h.maasModelRefLister)(name)
+		var ambiguous *models.AmbiguousModelError
+		switch {
+		case errors.Is(err, models.ErrModelNotFound):
+			return nil, http.StatusNotFound, fmt.Errorf("model %q not found", name)
+		case errors.As(err, &ambiguous):
+			return nil, http.StatusBadRequest, fmt.Errorf("model %q exists in namespaces %s; set ?namespace=",
+				name, strings.Join(ambiguous.Namespaces, ", "))
+		case err != nil:
+			return nil, http.StatusInternalServerError, errors.New("failed to resolve model")
+		}
+		namespace = resolved
+	}
+	if getter, ok := h.maasModelRefLister.(models.MaaSModelRefGetter); ok {
+		u, err := getter.Get(namespace, name)
+		if err != nil {
+			return nil, http.StatusInternalServerError, errors.New("failed to get model")
+		}
+		if u == nil {
+			return nil, http.StatusNotFound, fmt.Errorf("model %s/%s not found", namespace, name)
+		}
+		return u, 0, nil
+	}
+	items, err := h.maasModelRefLister.List()
+	if err != nil {
+		return nil, http.StatusInternalServerError, errors.New("failed to list models")
+	}
+	for _, u := range items {
+		if u.GetNamespace() == namespace && u.GetName() == name {
+			return u, 0, nil
+		}
+	}
+	return nil, http.StatusNotFound, fmt.Errorf("model %s/%s not found", namespace, name)
+}
+
+// modelBaseURL returns the model's endpoint from status.endpoint. Before the controller has set
+// it, the endpoint is derived from the gateway host the caller used and the model's route.
+func modelBaseURL(c *gin.Context, u *unstructured.Unstructured) string {
+	if endpoint, _, _ := unstructured.NestedString(u.Object, "status", "endpoint"); endpoint != "" {
+		return endpoint
+	}
+	scheme := c.GetHeader("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+	}
+	host := c.GetHeader("X-Forwarded-Host")
+	if host == "" {
+		host = c.Request.Host
+	}
+	path := "/" + u.GetNamespace() + "/" + u.GetName()
+	if prefix, hostname, ok := models.Route(u); ok {
+		path = strings.TrimRight(prefix, "/")
+		if hostname != "" {
+			host = hostname
+		}
+	}
+	return scheme + "://" + host + path
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/openai/openai-go/v2/packages/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -547,16 +548,19 @@ func TestListingModelsWithSubscriptionHeader(t *testing.T) {
 		name         string
 		subscription string
 		userGroups   string
+		wantCode     string
 	}{
 		{
 			name:         "API key - unknown subscription - returns 403",
 			subscription: "nonexistent-subscription",
 			userGroups:   `["free-users"]`,
+			wantCode:     "SUBSCRIPTION_NOT_FOUND",
 		},
 		{
 			name:         "API key - no access to subscription - returns 403",
 			subscription: "premium",
 			userGroups:   `["free-users"]`,
+			wantCode:     "TIER_DENIED",
 		},
 	}
 
@@ -581,6 +585,7 @@ func TestListingModelsWithSubscriptionHeader(t *testing.T) {
 			errorObj, ok := errorResponse["error"].(map[string]any)
 			require.True(t, ok, "Expected error object")
 			assert.Equal(t, "permission_error", errorObj["type"])
+			assert.Equal(t, tt.wantCode, errorObj["code"])
 		})
 	}
 }
@@ -1195,3 +1200,137 @@ func TestListModels_DifferentModelRefsWithSameModelIDAndDifferentSubscriptions(t
 		assert.True(t, subscriptionNames["sub-b"], "Should have model with sub-b")
 	})
 }
+
+func TestGetExamples(t *testing.T) {
+	documented := maasModelRefUnstructured("gpt", "team-a", "https://maas.example.com/team-a/gpt", true, nil)
+	_ = unstructured.SetNestedField(documented.Object, "ExternalModel", "spec", "modelRef", "kind")
+	_ = unstructured.SetNestedField(documented.Object, "https://platform.openai.com/docs/models", "spec", "documentation", "url")
+	_ = unstructured.SetNestedSlice(documented.Object, []any{
+		map[string]any{"name": "embeddings", "path": "/v1/embeddings", "body": `{"model": "gpt", "input": "it's"}`},
+		map[string]any{"name": "models", "path": "/v1/models"},
+	}, "spec", "documentation", "examples")
+	pending := maasModelRefUnstructured("granite", "llm", "", false, nil)
+	duplicate := maasModelRefUnstructured("granite", "team-a", "https://maas.example.com/team-a/granite", true, nil)
+	lister := fakeMaaSModelRefLister{"team-a": {documented, duplicate}, "llm": {pending}}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/models/:name/examples", handlers.NewModelsHandler(logger.Development(), nil, nil, lister).GetExamples)
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, path, nil)
+		req.Host = "gateway.example.com"
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/v1/models/gpt/examples")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var examples models.Examples
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &examples))
+	assert.Equal(t, "team-a/gpt", examples.Model)
+	assert.Equal(t, "https://platform.openai.com/docs/models", examples.DocsURL)
+	require.Len(t, examples.Data, 2)
+	assert.Equal(t, http.MethodPost, examples.Data[0].Method)
+	assert.Equal(t, "https://maas.example.com/team-a/gpt/v1/embeddings", examples.Data[0].URL)
+	assert.Equal(t, "curl -sS 'https://maas.example.com/team-a/gpt/v1/embeddings' \\\n"+
+		"  -H \"Authorization: Bearer $MAAS_API_KEY\" \\\n"+
+		"  -H \"Content-Type: application/json\" \\\n"+
+		`  -d '{"model": "gpt", "input": "it'\''s"}'`, examples.Data[0].Curl)
+	assert.Equal(t, http.MethodGet, examples.Data[1].Method)
+	assert.NotContains(t, examples.Data[1].Curl, "-d ")
+
+	// Without examples a chat completion is offered, against the gateway the caller used until the
+	// controller sets status.endpoint.
+	w = get("/v1/models/granite/examples?namespace=llm")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &examples))
+	require.Len(t, examples.Data, 1)
+	assert.Equal(t, "chat", examples.Data[0].Name)
+	assert.Equal(t, "http://gateway.example.com/llm/granite/v1/chat/completions", examples.Data[0].URL)
+	assert.JSONEq(t, `{"model": "granite", "messages": [{"role": "user", "content": "Hello!"}]}`, string(examples.Data[0].Body))
+
+	assert.Equal(t, http.StatusBadRequest, get("/v1/models/granite/examples").Code)
+	assert.Equal(t, http.StatusNotFound, get("/v1/models/llama/examples").Code)
+	assert.Equal(t, http.StatusNotFound, get("/v1/models/gpt/examples?namespace=llm").Code)
+}
+
+func TestListModels_FilteringAndPaging(t *testing.T) {
+	testLogger := logger.Development()
+
+	lister := fakeMaaSModelRefLister{
+		"team-a": {
+			maasModelRefUnstructured("alpha", "team-a", createMockModelServerWithSubscriptionCheck(t, "alpha", "sub-a").URL, true, nil),
+			maasModelRefUnstructured("gamma", "team-a", createMockModelServerWithSubscriptionCheck(t, "gamma", "sub-b").URL, false, nil),
+		},
+		"team-b": {
+			maasModelRefUnstructured("beta", "team-b", createMockModelServerWithSubscriptionCheck(t, "beta", "sub-a").URL, true, nil),
+		},
+	}
+	modelMgr, err := models.NewManager(testLogger)
+	require.NoError(t, err)
+	selector := subscription.NewSelector(testLogger, fakeMultiSubscriptionLister{"sub-a": {"group-a"}, "sub-b": {"group-b"}})
+	modelsHandler := handlers.NewModelsHandler(testLogger, modelMgr, selector, lister)
+
+	router, _ := fixtures.SetupTestServer(t, fixtures.TestServerConfig{Objects: []runtime.Object{}})
+	_, cleanup := fixtures.StubTokenProviderAPIs(t)
+	defer cleanup()
+	tokenHandler := token.NewHandler(testLogger, fixtures.TestTenant)
+	router.Group("/v1").GET("/models", tokenHandler.ExtractUserInfo(), modelsHandler.ListLLMs)
+
+	list := func(t *testing.T, query string) (int, []string, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/models"+query, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer valid-token")
+		req.Header.Set(constant.HeaderUsername, "test-user@example.com")
+		req.Header.Set(constant.HeaderGroup, `["group-a", "group-b"]`)
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Data     []models.Model `json:"data"`
+			Continue string         `json:"continue"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		ids := make([]string, 0, len(response.Data))
+		for _, m := range response.Data {
+			ids = append(ids, m.ID)
+		}
+		return w.Code, ids, response.Continue
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		code, ids, next := list(t, "")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"alpha", "beta", "gamma"}, ids)
+		assert.Empty(t, next)
+
+		_, ids, _ = list(t, "?namespace=team-a")
+		assert.Equal(t, []string{"alpha", "gamma"}, ids)
+		_, ids, _ = list(t, "?ready=true&order=desc")
+		assert.Equal(t, []string{"beta", "alpha"}, ids)
+		_, ids, _ = list(t, "?tier=sub-b")
+		assert.Equal(t, []string{"gamma"}, ids)
+		_, ids, _ = list(t, "?tier=sub-unknown")
+		assert.Empty(t, ids)
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		code, ids, next := list(t, "?sort=namespace&limit=2")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"alpha", "gamma"}, ids)
+		require.NotEmpty(t, next)
+
+		_, ids, next = list(t, "?sort=namespace&limit=2&continue="+next)
+		assert.Equal(t, []string{"beta"}, ids)
+		assert.Empty(t, next)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"?sort=size", "?order=up", "?ready=maybe", "?limit=0", "?continue=bogus"} {
+			code, _, _ := list(t, query)
+			assert.Equal(t, http.StatusBadRequest, code, query)
+		}
+	})
+}
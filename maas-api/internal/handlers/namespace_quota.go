@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// NamespaceQuota compares what a namespace exposes through MaaS against its quota annotations.
+// A nil maximum means the namespace has no quota for it.
+type NamespaceQuota struct {
+	Namespace                string `json:"namespace"`
+	Models                   int64  `json:"models"`
+	MaxModels                *int64 `json:"max_models"`
+	CommittedTokensPerMinute int64  `json:"committed_tokens_per_minute"`
+	MaxTokensPerMinute       *int64 `json:"max_tokens_per_minute"`
+	// OverQuota is set when usage exceeds a maximum, e.g. after an admin lowered the quota.
+	OverQuota bool `json:"over_quota"`
+}
+
+// NamespaceQuotaHandler serves the admin quota view.
+type NamespaceQuotaHandler struct {
+	logger               *logger.Logger
+	clientset            kubernetes.Interface
+	maasModelRefLister   models.MaaSModelRefLister
+	subscriptionSelector *subscription.Selector
+	adminChecker         AdminChecker
+}
+
+// NewNamespaceQuotaHandler creates a handler for GET /v1/admin/quotas.
+func NewNamespaceQuotaHandler(
+	log *logger.Logger,
+	clientset kubernetes.Interface,
+	maasModelRefLister models.MaaSModelRefLister,
+	subscriptionSelector *subscription.Selector,
+	adminChecker AdminChecker,
+) *NamespaceQuotaHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if clientset == nil {
+		panic("clientset cannot be nil")
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &NamespaceQuotaHandler{
+		logger:               log,
+		clientset:            clientset,
+		maasModelRefLister:   maasModelRefLister,
+		subscriptionSelector: subscriptionSelector,
+		adminChecker:         adminChecker,
+	}
+}
+
+// GetQuotas handles GET /v1/admin/quotas. It lists every namespace that has a quota or exposes models.
+func (h *NamespaceQuotaHandler) GetQuotas(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	user, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return
+	}
+
+	byNamespace := map[string]*NamespaceQuota{}
+	entry := func(ns string) *NamespaceQuota {
+		if byNamespace[ns] == nil {
+			byNamespace[ns] = &NamespaceQuota{Namespace: ns}
+		}
+		return byNamespace[ns]
+	}
+
+	namespaces, err := h.clientset.CoreV1().Namespaces().List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		h.logger.Error("Failed to list namespaces", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list namespaces")
+		return
+	}
+	for _, ns := range namespaces.Items {
+		maxModels := quotaAnnotation(ns.Annotations, constant.AnnotationMaxModels)
+		maxTokens := quotaAnnotation(ns.Annotations, constant.AnnotationMaxTokensPerMinute)
+		if maxModels == nil && maxTokens == nil {
+			continue
+		}
+		q := entry(ns.Name)
+		q.MaxModels = maxModels
+		q.MaxTokensPerMinute = maxTokens
+	}
+
+	if h.maasModelRefLister != nil {
+		items, err := h.maasModelRefLister.List()
+		if err != nil {
+			h.logger.Error("Failed to list MaaSModelRefs", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list models")
+			return
+		}
+		for _, u := range items {
+			entry(u.GetNamespace()).Models++
+		}
+	}
+
+	if h.subscriptionSelector != nil {
+		subs, err := h.subscriptionSelector.ListAll()
+		if err != nil {
+			h.logger.Error("Failed to list subscriptions", "error", err)
+			apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to get subscriptions")
+			return
+		}
+		for _, sub := range subs {
+			for _, ref := range sub.ModelRefs {
+				limits := ref.TokenRateLimits
+				if len(limits) == 0 {
+					// maas-controller applies 100 tokens per minute to entries without limits.
+					limits = []subscription.TokenRateLimit{{Limit: 100, Window: "1m"}}
+				}
+				entry(ref.Namespace).CommittedTokensPerMinute += subscription.SustainedPerMinute(limits)
+			}
+		}
+	}
+
+	out := make([]NamespaceQuota, 0, len(byNamespace))
+	for _, q := range byNamespace {
+		q.OverQuota = (q.MaxModels != nil && q.Models > *q.MaxModels) ||
+			(q.MaxTokensPerMinute != nil && q.CommittedTokensPerMinute > *q.MaxTokensPerMinute)
+		out = append(out, *q)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   out,
+	})
+}
+
+// quotaAnnotation parses a non-negative quota annotation; missing or invalid values mean no quota.
+func quotaAnnotation(annotations map[string]string, key string) *int64 {
+	n, err := strconv.ParseInt(annotations[key], 10, 64)
+	if err != nil || n < 0 {
+		return nil
+	}
+	return &n
+}
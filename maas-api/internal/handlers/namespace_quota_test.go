@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func getQuotas(t *testing.T, h *handlers.NamespaceQuotaHandler) (*httptest.ResponseRecorder, []handlers.NamespaceQuota) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/admin/quotas", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	}, h.GetQuotas)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/admin/quotas", nil))
+
+	var body struct {
+		Data []handlers.NamespaceQuota `json:"data"`
+	}
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	}
+	return w, body.Data
+}
+
+func TestGetQuotas(t *testing.T) {
+	clientset := fake.NewClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: map[string]string{
+			constant.AnnotationMaxModels:          "1",
+			constant.AnnotationMaxTokensPerMinute: "5000",
+		}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Annotations: map[string]string{
+			constant.AnnotationMaxModels: "10",
+		}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}},
+	)
+	lister := fakeMaaSModelRefLister{
+		"team-a": {
+			maasModelRefUnstructured("one", "team-a", "", true, nil),
+			maasModelRefUnstructured("two", "team-a", "", true, nil),
+		},
+		"llm": {maasModelRefUnstructured("granite", "llm", "", true, nil)},
+	}
+	selector := subscription.NewSelector(logger.Development(), staticSubscriptionLister{
+		subscriptionWithLimit("free", "team-a", "one", 1000, "1m"),
+		subscriptionWithLimit("premium", "team-a", "two", 120000, "1h"), // 2000/min
+	})
+	h := handlers.NewNamespaceQuotaHandler(logger.Development(), clientset, lister, selector, fakeAdminChecker(true))
+
+	w, data := getQuotas(t, h)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, data, 3)
+
+	assert.Equal(t, "llm", data[0].Namespace)
+	assert.Equal(t, int64(1), data[0].Models)
+	assert.Nil(t, data[0].MaxModels)
+	assert.False(t, data[0].OverQuota)
+
+	teamA := data[1]
+	assert.Equal(t, "team-a", teamA.Namespace)
+	assert.Equal(t, int64(2), teamA.Models)
+	assert.Equal(t, int64(3000), teamA.CommittedTokensPerMinute)
+	require.NotNil(t, teamA.MaxTokensPerMinute)
+	assert.Equal(t, int64(5000), *teamA.MaxTokensPerMinute)
+	assert.True(t, teamA.OverQuota, "two models against a quota of one")
+
+	assert.Equal(t, "team-b", data[2].Namespace)
+	assert.Equal(t, int64(0), data[2].Models)
+}
+
+func TestGetQuotasRequiresAdmin(t *testing.T) {
+	h := handlers.NewNamespaceQuotaHandler(logger.Development(), fake.NewClientset(), fakeMaaSModelRefLister{}, nil, fakeAdminChecker(false))
+
+	w, _ := getQuotas(t, h)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	authv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/authpolicy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// llmInferenceServiceGVR is the KServe resource a published model must point at.
+var llmInferenceServiceGVR = schema.GroupVersionResource{
+	Group:    "serving.kserve.io",
+	Version:  "v1alpha1",
+	Resource: "llminferenceservices",
+}
+
+// AccessReviewer reports whether a user's own RBAC allows an action on a resource.
+type AccessReviewer interface {
+	CanI(ctx context.Context, user *token.UserContext, attrs authv1.ResourceAttributes) bool
+}
+
+// PublishModelRequest is the body of POST /v1/models.
+type PublishModelRequest struct {
+	// Name of the MaaSModelRef to create; must be a DNS-1123 label.
+	Name string `json:"name"`
+	// Namespace holding the LLMInferenceService; the MaaSModelRef is created alongside it.
+	Namespace string `json:"namespace"`
+	// LLMInferenceService to expose. Defaults to Name.
+	LLMInferenceService string `json:"llmInferenceService,omitempty"`
+	// Parent is the base model this variant is fine-tuned from ("name" in Namespace or "namespace/name").
+	Parent      string `json:"parent,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PublishModelResponse describes the objects created for a published model.
+type PublishModelResponse struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	Approval     string `json:"approval"`
+	AuthPolicy   string `json:"authPolicy"`
+	Subscription string `json:"subscription"`
+}
+
+// PublishHandler lets teams publish their own model variants without a platform admin.
+type PublishHandler struct {
+	logger             *logger.Logger
+	client             dynamic.Interface
+	maasModelRefLister models.MaaSModelRefLister
+	accessReviewer     AccessReviewer
+	maasNamespace      string
+}
+
+// NewPublishHandler creates a handler for POST /v1/models. The private auth policy and
+// subscription of each published model are created in maasNamespace.
+func NewPublishHandler(
+	log *logger.Logger,
+	client dynamic.Interface,
+	maasModelRefLister models.MaaSModelRefLister,
+	accessReviewer AccessReviewer,
+	maasNamespace string,
+) *PublishHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if client == nil {
+		panic("client cannot be nil")
+	}
+	if accessReviewer == nil {
+		panic("accessReviewer cannot be nil")
+	}
+	return &PublishHandler{
+		logger:             log,
+		client:             client,
+		maasModelRefLister: maasModelRefLister,
+		accessReviewer:     accessReviewer,
+		maasNamespace:      maasNamespace,
+	}
+}
+
+// Publish handles POST /v1/models. The caller must be allowed to update the LLMInferenceService
+// they reference; the MaaSModelRef is created pending approval and served only to the caller
+// through a private auth policy and subscription until an admin approves it.
+func (h *PublishHandler) Publish(c *gin.Context) {
+	userContextVal, exists := c.Get("user")
+	user, ok := userContextVal.(*token.UserContext)
+	if !exists || !ok {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+
+	var req PublishModelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.invalidRequest(c, "invalid request body: "+err.Error())
+		return
+	}
+	if req.LLMInferenceService == "" {
+		req.LLMInferenceService = req.Name
+	}
+	for _, field := range []struct{ name, value string }{
+		{"name", req.Name}, {"namespace", req.Namespace}, {"llmInferenceService", req.LLMInferenceService},
+	} {
+		if errs := validation.IsDNS1123Label(field.value); len(errs) > 0 {
+			h.invalidRequest(c, fmt.Sprintf("%s %q is invalid: %s", field.name, field.value, strings.Join(errs, "; ")))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	if !h.accessReviewer.CanI(ctx, user, authv1.ResourceAttributes{
+		Namespace: req.Namespace,
+		Verb:      "update",
+		Group:     llmInferenceServiceGVR.Group,
+		Resource:  llmInferenceServiceGVR.Resource,
+		Name:      req.LLMInferenceService,
+	}) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, fmt.Sprintf("you are not allowed to publish LLMInferenceService %s/%s", req.Namespace, req.LLMInferenceService))
+		return
+	}
+
+	if _, err := h.client.Resource(llmInferenceServiceGVR).Namespace(req.Namespace).Get(ctx, req.LLMInferenceService, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			apierror.Respond(c, http.StatusNotFound, apierror.NotFound, fmt.Sprintf("LLMInferenceService %s/%s not found", req.Namespace, req.LLMInferenceService))
+			return
+		}
+		h.serverError(c, "Failed to get LLMInferenceService", err)
+		return
+	}
+
+	parentNamespace, parentName, err := h.resolveParent(req)
+	if err != nil {
+		h.invalidRequest(c, err.Error())
+		return
+	}
+
+	model := h.buildModel(req, user, parentNamespace, parentName)
+	if _, err := h.client.Resource(models.GVR()).Namespace(req.Namespace).Create(ctx, model, metav1.CreateOptions{}); err != nil {
+		h.createError(c, "MaaSModelRef", err)
+		return
+	}
+
+	privateName := PrivateTierName(req.Namespace, req.Name)
+	if err := h.createPrivateAccess(ctx, privateName, req, user); err != nil {
+		if delErr := h.client.Resource(models.GVR()).Namespace(req.Namespace).Delete(ctx, req.Name, metav1.DeleteOptions{}); delErr != nil {
+			h.logger.Error("Failed to roll back published MaaSModelRef", "error", delErr, "model", req.Namespace+"/"+req.Name)
+		}
+		h.createError(c, "private access for the model", err)
+		return
+	}
+
+	h.logger.Info("Model published pending approval",
+		"model", req.Namespace+"/"+req.Name, "llmInferenceService", req.LLMInferenceService, "username", user.Username)
+	c.JSON(http.StatusCreated, PublishModelResponse{
+		Name:         req.Name,
+		Namespace:    req.Namespace,
+		Approval:     constant.ApprovalPending,
+		AuthPolicy:   h.maasNamespace + "/" + privateName,
+		Subscription: h.maasNamespace + "/" + privateName,
+	})
+}
+
+// PrivateTierName returns the name of the MaaSAuthPolicy and MaaSSubscription giving only the
+// publisher access to the published model namespace/name. It is a hash, as joining the two with
+// dashes names "a-b"/"c" and "a"/"b-c" alike.
+func PrivateTierName(namespace, name string) string {
+	sum := sha256.Sum256([]byte(namespace + "/" + name))
+	return "private-" + hex.EncodeToString(sum[:10])
+}
+
+// resolveParent returns the parent model of the request, checking that it exists.
+func (h *PublishHandler) resolveParent(req PublishModelRequest) (string, string, error) {
+	if req.Parent == "" {
+		return "", "", nil
+	}
+	namespace, name := req.Namespace, req.Parent
+	if ns, n, found := strings.Cut(req.Parent, "/"); found {
+		namespace, name = ns, n
+	}
+	items, err := h.maasModelRefLister.List()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to look up parent model: %w", err)
+	}
+	for _, u := range items {
+		if u.GetNamespace() == namespace && u.GetName() == name {
+			return namespace, name, nil
+		}
+	}
+	return "", "", fmt.Errorf("parent model %s/%s not found", namespace, name)
+}
+
+// buildModel returns the MaaSModelRef for a publish request, marked pending approval.
+func (h *PublishHandler) buildModel(req PublishModelRequest, user *token.UserContext, parentNamespace, parentName string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(models.GVR().GroupVersion().String())
+	u.SetKind("MaaSModelRef")
+	u.SetName(req.Name)
+	u.SetNamespace(req.Namespace)
+	u.SetLabels(map[string]string{constant.LabelPublished: "true"})
+
+	annotations := map[string]string{
+		constant.AnnotationApproval:    constant.ApprovalPending,
+		constant.AnnotationPublishedBy: user.Username,
+	}
+	if req.DisplayName != "" {
+		annotations[constant.AnnotationDisplayName] = req.DisplayName
+	}
+	if req.Description != "" {
+		annotations[constant.AnnotationDescription] = req.Description
+	}
+	u.SetAnnotations(annotations)
+
+	_ = unstructured.SetNestedMap(u.Object, map[string]any{
+		"kind": "LLMInferenceService",
+		"name": req.LLMInferenceService,
+	}, "spec", "modelRef")
+	if parentName != "" {
+		_ = unstructured.SetNestedMap(u.Object, map[string]any{
+			"name":      parentName,
+			"namespace": parentNamespace,
+		}, "spec", "parentRef")
+	}
+	return u
+}
+
+// createPrivateAccess creates the auth policy and subscription that give only the publisher access
+// to the model. A direct auth policy overrides any inherited from the parent, so the variant stays
+// private even when its base model is widely available.
+func (h *PublishHandler) createPrivateAccess(ctx context.Context, name string, req PublishModelRequest, user *token.UserContext) error {
+	modelRef := map[string]any{"name": req.Name, "namespace": req.Namespace}
+	labels := map[string]string{constant.LabelPublished: "true"}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetAPIVersion(authpolicy.GVR().GroupVersion().String())
+	policy.SetKind("MaaSAuthPolicy")
+	policy.SetName(name)
+	policy.SetNamespace(h.maasNamespace)
+	policy.SetLabels(labels)
+	_ = unstructured.SetNestedSlice(policy.Object, []any{modelRef}, "spec", "modelRefs")
+	_ = unstructured.SetNestedStringSlice(policy.Object, []string{user.Username}, "spec", "subjects", "users")
+	if _, err := h.client.Resource(authpolicy.GVR()).Namespace(h.maasNamespace).Create(ctx, policy, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	sub := &unstructured.Unstructured{}
+	sub.SetAPIVersion(subscription.GVR().GroupVersion().String())
+	sub.SetKind("MaaSSubscription")
+	sub.SetName(name)
+	sub.SetNamespace(h.maasNamespace)
+	sub.SetLabels(labels)
+	_ = unstructured.SetNestedStringSlice(sub.Object, []string{user.Username}, "spec", "owner", "users")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{map[string]any{
+		"name":      req.Name,
+		"namespace": req.Namespace,
+		"tokenRateLimits": []any{map[string]any{
+			"limit":  int64(constant.DefaultPrivateTierTokenLimit),
+			"window": constant.DefaultPrivateTierWindow,
+		}},
+	}}, "spec", "modelRefs")
+	if _, err := h.client.Resource(subscription.GVR()).Namespace(h.maasNamespace).Create(ctx, sub, metav1.CreateOptions{}); err != nil {
+		if delErr := h.client.Resource(authpolicy.GVR()).Namespace(h.maasNamespace).Delete(ctx, name, metav1.DeleteOptions{}); delErr != nil {
+			h.logger.Error("Failed to roll back private MaaSAuthPolicy", "error", delErr, "name", name)
+		}
+		return err
+	}
+	return nil
+}
+
+// createError maps a Kubernetes create error to a response. A ResourceQuota on the namespace
+// surfaces as Forbidden and is reported to the caller as such.
+func (h *PublishHandler) createError(c *gin.Context, what string, err error) {
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		apierror.Respond(c, http.StatusConflict, apierror.Conflict, fmt.Sprintf("%s already exists", what))
+	case apierrors.IsForbidden(err):
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, fmt.Sprintf("cannot create %s: %v", what, err))
+	default:
+		h.serverError(c, "Failed to create "+what, err)
+	}
+}
+
+func (h *PublishHandler) serverError(c *gin.Context, message string, err error) {
+	h.logger.Error(message, "error", err)
+	apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, message)
+}
+
+func (h *PublishHandler) invalidRequest(c *gin.Context, message string) {
+	apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, message)
+}
@@ -0,0 +1,159 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/authpolicy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+var llmisvcGVR = schema.GroupVersionResource{Group: "serving.kserve.io", Version: "v1alpha1", Resource: "llminferenceservices"}
+
+// fakeAccessReviewer allows "update" on the listed namespace/name pairs.
+type fakeAccessReviewer map[string]bool
+
+func (f fakeAccessReviewer) CanI(_ context.Context, _ *token.UserContext, attrs authv1.ResourceAttributes) bool {
+	return attrs.Verb == "update" && f[attrs.Namespace+"/"+attrs.Name]
+}
+
+func llmInferenceService(name, namespace string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion("serving.kserve.io/v1alpha1")
+	u.SetKind("LLMInferenceService")
+	u.SetName(name)
+	u.SetNamespace(namespace)
+	return u
+}
+
+func newPublishClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		llmisvcGVR:         "LLMInferenceServiceList",
+		models.GVR():       "MaaSModelRefList",
+		subscription.GVR(): "MaaSSubscriptionList",
+		authpolicy.GVR():   "MaaSAuthPolicyList",
+	}, objects...)
+}
+
+func postPublish(h *handlers.PublishHandler, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/models", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"team-a"}})
+	}, h.Publish)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/models", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPublishCreatesPrivateModel(t *testing.T) {
+	client := newPublishClient(llmInferenceService("granite-legal", "team-a"))
+	lister := fakeMaaSModelRefLister{"llm": {maasModelRefUnstructured("granite", "llm", "https://granite", true, nil)}}
+	h := handlers.NewPublishHandler(logger.Development(), client, lister,
+		fakeAccessReviewer{"team-a/granite-legal": true}, "models-as-a-service")
+
+	w := postPublish(h, `{"name":"granite-legal","namespace":"team-a","parent":"llm/granite","displayName":"Granite (legal)"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var resp handlers.PublishModelResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, constant.ApprovalPending, resp.Approval)
+	privateName := handlers.PrivateTierName("team-a", "granite-legal")
+	assert.Equal(t, "models-as-a-service/"+privateName, resp.Subscription)
+
+	ctx := context.Background()
+	model, err := client.Resource(models.GVR()).Namespace("team-a").Get(ctx, "granite-legal", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, constant.ApprovalPending, model.GetAnnotations()[constant.AnnotationApproval])
+	assert.Equal(t, "alice", model.GetAnnotations()[constant.AnnotationPublishedBy])
+	assert.Equal(t, "Granite (legal)", model.GetAnnotations()[constant.AnnotationDisplayName])
+	kind, _, _ := unstructured.NestedString(model.Object, "spec", "modelRef", "kind")
+	assert.Equal(t, "LLMInferenceService", kind)
+	parent, _, _ := unstructured.NestedString(model.Object, "spec", "parentRef", "namespace")
+	assert.Equal(t, "llm", parent)
+
+	policy, err := client.Resource(authpolicy.GVR()).Namespace("models-as-a-service").Get(ctx, privateName, metav1.GetOptions{})
+	require.NoError(t, err)
+	users, _, _ := unstructured.NestedStringSlice(policy.Object, "spec", "subjects", "users")
+	assert.Equal(t, []string{"alice"}, users)
+
+	sub, err := client.Resource(subscription.GVR()).Namespace("models-as-a-service").Get(ctx, privateName, metav1.GetOptions{})
+	require.NoError(t, err)
+	owners, _, _ := unstructured.NestedStringSlice(sub.Object, "spec", "owner", "users")
+	assert.Equal(t, []string{"alice"}, owners)
+}
+
+func TestPublishRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "invalid name", body: `{"name":"Granite_Legal","namespace":"team-a"}`, wantCode: http.StatusBadRequest},
+		{name: "not allowed on service", body: `{"name":"granite-legal","namespace":"team-b"}`, wantCode: http.StatusForbidden},
+		{name: "service missing", body: `{"name":"missing","namespace":"team-a"}`, wantCode: http.StatusNotFound},
+		{name: "parent missing", body: `{"name":"granite-legal","namespace":"team-a","parent":"nope"}`, wantCode: http.StatusBadRequest},
+		{name: "already published", body: `{"name":"existing","namespace":"team-a"}`, wantCode: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := maasModelRefUnstructured("existing", "team-a", "", false, nil)
+			client := newPublishClient(llmInferenceService("granite-legal", "team-a"), llmInferenceService("granite-legal", "team-b"),
+				llmInferenceService("existing", "team-a"), existing)
+			h := handlers.NewPublishHandler(logger.Development(), client, fakeMaaSModelRefLister{},
+				fakeAccessReviewer{"team-a/granite-legal": true, "team-a/missing": true, "team-a/existing": true}, "models-as-a-service")
+
+			w := postPublish(h, tt.body)
+			assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestPublishRollsBackOnQuota(t *testing.T) {
+	client := newPublishClient(llmInferenceService("granite-legal", "team-a"))
+	client.PrependReactor("create", "maassubscriptions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(subscription.GVR().GroupResource(), handlers.PrivateTierName("team-a", "granite-legal"),
+			assert.AnError)
+	})
+	h := handlers.NewPublishHandler(logger.Development(), client, fakeMaaSModelRefLister{},
+		fakeAccessReviewer{"team-a/granite-legal": true}, "models-as-a-service")
+
+	w := postPublish(h, `{"name":"granite-legal","namespace":"team-a"}`)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	ctx := context.Background()
+	_, err := client.Resource(models.GVR()).Namespace("team-a").Get(ctx, "granite-legal", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "model is removed when private access cannot be created")
+	_, err = client.Resource(authpolicy.GVR()).Namespace("models-as-a-service").Get(ctx, handlers.PrivateTierName("team-a", "granite-legal"), metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "auth policy is removed when the subscription cannot be created")
+}
+
+func TestPrivateTierNameDoesNotCollide(t *testing.T) {
+	assert.NotEqual(t, handlers.PrivateTierName("team-a", "granite"), handlers.PrivateTierName("team", "a-granite"))
+	assert.Equal(t, handlers.PrivateTierName("team-a", "granite"), handlers.PrivateTierName("team-a", "granite"))
+	assert.LessOrEqual(t, len(handlers.PrivateTierName("team-a", "granite")), 63)
+}
@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// rateLimitWindow matches the window pattern enforced by the MaaSSubscription CRD.
+var rateLimitWindow = regexp.MustCompile(`^(\d+)(s|m|h|d)$`)
+
+// TierRateLimit is a limit per window, e.g. 1000 tokens per "1m".
+type TierRateLimit struct {
+	Limit  int64  `json:"limit"`
+	Window string `json:"window"`
+}
+
+// TierModel is a model in a tier with its per-user limits.
+type TierModel struct {
+	Name              string          `json:"name"`
+	Namespace         string          `json:"namespace"`
+	TokenRateLimits   []TierRateLimit `json:"tokenRateLimits,omitempty"`
+	RequestRateLimits []TierRateLimit `json:"requestRateLimits,omitempty"`
+	TokenBudget       *TierBudget     `json:"tokenBudget,omitempty"`
+}
+
+// TierBudget is the number of tokens each user may consume of a model per period ("24h", "30d").
+type TierBudget struct {
+	Limit  int64  `json:"limit"`
+	Period string `json:"period"`
+}
+
+// TierOwner lists the groups and users a tier applies to.
+type TierOwner struct {
+	Groups []string `json:"groups,omitempty"`
+	Users  []string `json:"users,omitempty"`
+}
+
+// Tier is the admin view of a MaaSSubscription. MaaSSubscriptions are the source of truth for
+// tiers: subscription selection only accepts names that exist as one.
+type Tier struct {
+	Name      string      `json:"name"`
+	Priority  int32       `json:"priority"`
+	Owner     TierOwner   `json:"owner"`
+	Models    []TierModel `json:"models"`
+	ExpiresAt *time.Time  `json:"expiresAt,omitempty"`
+	// Includes names lower tiers whose models this tier's owners may also use, or "*" for all.
+	Includes []string `json:"includes,omitempty"`
+	// Listener binds the tier to a gateway listener; requests must then use its hostnames.
+	Listener *TierListener `json:"listener,omitempty"`
+	// Phase is reported by maas-controller and ignored on create and update.
+	Phase string `json:"phase,omitempty"`
+}
+
+// TierListener is the gateway listener a tier's traffic is separated onto.
+type TierListener struct {
+	Name      string   `json:"name"`
+	Hostnames []string `json:"hostnames"`
+}
+
+// TierHandler lets admins manage tiers through the API instead of editing MaaSSubscriptions by hand.
+type TierHandler struct {
+	logger       *logger.Logger
+	client       dynamic.Interface
+	adminChecker AdminChecker
+	namespace    string
+	auditor      *audit.Auditor
+}
+
+// NewTierHandler creates a handler for /v1/tiers. Tiers are MaaSSubscriptions in namespace.
+func NewTierHandler(log *logger.Logger, client dynamic.Interface, adminChecker AdminChecker, namespace string) *TierHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if client == nil {
+		panic("client cannot be nil")
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &TierHandler{
+		logger:       log,
+		client:       client,
+		adminChecker: adminChecker,
+		namespace:    namespace,
+	}
+}
+
+// SetAuditor records each tier change in the audit log as an admin action.
+func (h *TierHandler) SetAuditor(a *audit.Auditor) {
+	h.auditor = a
+}
+
+// ListTiers handles GET /v1/tiers, highest priority first.
+func (h *TierHandler) ListTiers(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	list, err := h.client.Resource(subscription.GVR()).Namespace(h.namespace).List(c.Request.Context(), metav1.ListOptions{})
+	if err != nil {
+		h.serverError(c, "Failed to list tiers", err)
+		return
+	}
+	tiers := make([]Tier, 0, len(list.Items))
+	for i := range list.Items {
+		tiers = append(tiers, tierFromSubscription(&list.Items[i]))
+	}
+	slices.SortStableFunc(tiers, func(a, b Tier) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	c.JSON(http.StatusOK, gin.H{"object": "list", "data": tiers})
+}
+
+// GetTier handles GET /v1/tiers/:name.
+func (h *TierHandler) GetTier(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	obj, err := h.client.Resource(subscription.GVR()).Namespace(h.namespace).Get(c.Request.Context(), c.Param("name"), metav1.GetOptions{})
+	if err != nil {
+		h.clientError(c, c.Param("name"), err)
+		return
+	}
+	c.JSON(http.StatusOK, tierFromSubscription(obj))
+}
+
+// CreateTier handles POST /v1/tiers.
+func (h *TierHandler) CreateTier(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	var tier Tier
+	if err := c.ShouldBindJSON(&tier); err != nil {
+		h.invalidRequest(c, "invalid request body: "+err.Error())
+		return
+	}
+	if err := validateTier(tier); err != nil {
+		h.invalidRequest(c, err.Error())
+		return
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(subscription.GVR().GroupVersion().String())
+	obj.SetKind("MaaSSubscription")
+	obj.SetName(tier.Name)
+	obj.SetNamespace(h.namespace)
+	setTierSpec(obj, tier)
+	created, err := h.client.Resource(subscription.GVR()).Namespace(h.namespace).Create(c.Request.Context(), obj, metav1.CreateOptions{})
+	if err != nil {
+		h.clientError(c, tier.Name, err)
+		return
+	}
+	h.logger.Info("Tier created", "tier", tier.Name, "username", userFrom(c).Username)
+	h.auditor.AdminAction("tier.create", tier.Name, "", userFrom(c).Username)
+	c.JSON(http.StatusCreated, tierFromSubscription(created))
+}
+
+// UpdateTier handles PUT /v1/tiers/:name, replacing the tier's owner, models, priority, expiry and
+// includes.
+// Other fields of the MaaSSubscription spec, such as token metadata, are kept.
+func (h *TierHandler) UpdateTier(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	var tier Tier
+	if err := c.ShouldBindJSON(&tier); err != nil {
+		h.invalidRequest(c, "invalid request body: "+err.Error())
+		return
+	}
+	name := c.Param("name")
+	if tier.Name == "" {
+		tier.Name = name
+	}
+	if tier.Name != name {
+		h.invalidRequest(c, "tier name cannot be changed")
+		return
+	}
+	if err := validateTier(tier); err != nil {
+		h.invalidRequest(c, err.Error())
+		return
+	}
+
+	ctx := c.Request.Context()
+	obj, err := h.client.Resource(subscription.GVR()).Namespace(h.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		h.clientError(c, name, err)
+		return
+	}
+	setTierSpec(obj, tier)
+	updated, err := h.client.Resource(subscription.GVR()).Namespace(h.namespace).Update(ctx, obj, metav1.UpdateOptions{})
+	if err != nil {
+		h.clientError(c, name, err)
+		return
+	}
+	h.logger.Info("Tier updated", "tier", name, "username", userFrom(c).Username)
+	h.auditor.AdminAction("tier.update", name, "", userFrom(c).Username)
+	c.JSON(http.StatusOK, tierFromSubscription(updated))
+}
+
+// DeleteTier handles DELETE /v1/tiers/:name. API keys bound to the tier stop working, since
+// subscription selection no longer finds it.
+func (h *TierHandler) DeleteTier(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	name := c.Param("name")
+	if err := h.client.Resource(subscription.GVR()).Namespace(h.namespace).Delete(c.Request.Context(), name, metav1.DeleteOptions{}); err != nil {
+		h.clientError(c, name, err)
+		return
+	}
+	h.logger.Info("Tier deleted", "tier", name, "username", userFrom(c).Username)
+	h.auditor.AdminAction("tier.delete", name, "", userFrom(c).Username)
+	c.Status(http.StatusNoContent)
+}
+
+// validateTier checks a tier against the rules of the MaaSSubscription CRD, so mistakes are
+// reported to the admin instead of as an opaque admission error.
+func validateTier(tier Tier) error {
+	if errs := validation.IsDNS1123Subdomain(tier.Name); len(errs) > 0 {
+		return fmt.Errorf("name %q is invalid: %s", tier.Name, strings.Join(errs, "; "))
+	}
+	if len(tier.Owner.Groups) == 0 && len(tier.Owner.Users) == 0 {
+		return fmt.Errorf("owner must list at least one group or user")
+	}
+	if len(tier.Models) == 0 {
+		return fmt.Errorf("models must list at least one model")
+	}
+	for _, name := range tier.Includes {
+		if name == tier.Name {
+			return fmt.Errorf("a tier cannot include itself")
+		}
+		if errs := validation.IsDNS1123Subdomain(name); name != "*" && len(errs) > 0 {
+			return fmt.Errorf("includes %q is invalid: %s", name, strings.Join(errs, "; "))
+		}
+	}
+	if l := tier.Listener; l != nil {
+		if l.Name == "" || len(l.Hostnames) == 0 {
+			return fmt.Errorf("listener needs a name and at least one hostname")
+		}
+		for _, host := range l.Hostnames {
+			valid := validation.IsDNS1123Subdomain
+			if strings.HasPrefix(host, "*.") {
+				valid = validation.IsWildcardDNS1123Subdomain
+			}
+			if errs := valid(host); len(errs) > 0 {
+				return fmt.Errorf("listener hostname %q is invalid: %s", host, strings.Join(errs, "; "))
+			}
+		}
+	}
+	for _, m := range tier.Models {
+		if m.Name == "" || m.Namespace == "" {
+			return fmt.Errorf("models entries need a name and namespace")
+		}
+		for _, l := range slices.Concat(m.TokenRateLimits, m.RequestRateLimits) {
+			if l.Limit <= 0 {
+				return fmt.Errorf("model %s/%s: limit must be positive", m.Namespace, m.Name)
+			}
+			if !rateLimitWindow.MatchString(l.Window) {
+				return fmt.Errorf("model %s/%s: window %q must be a number followed by s, m, h or d", m.Namespace, m.Name, l.Window)
+			}
+		}
+		if b := m.TokenBudget; b != nil {
+			if b.Limit <= 0 {
+				return fmt.Errorf("model %s/%s: token budget limit must be positive", m.Namespace, m.Name)
+			}
+			if _, err := quota.ParsePeriod(b.Period); err != nil {
+				return fmt.Errorf("model %s/%s: %w", m.Namespace, m.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setTierSpec writes the tier into the MaaSSubscription spec.
+func setTierSpec(obj *unstructured.Unstructured, tier Tier) {
+	owner := map[string]any{}
+	if len(tier.Owner.Groups) > 0 {
+		groups := make([]any, 0, len(tier.Owner.Groups))
+		for _, g := range tier.Owner.Groups {
+			groups = append(groups, map[string]any{"name": g})
+		}
+		owner["groups"] = groups
+	}
+	if len(tier.Owner.Users) > 0 {
+		users := make([]any, 0, len(tier.Owner.Users))
+		for _, u := range tier.Owner.Users {
+			users = append(users, u)
+		}
+		owner["users"] = users
+	}
+	_ = unstructured.SetNestedMap(obj.Object, owner, "spec", "owner")
+
+	refs := make([]any, 0, len(tier.Models))
+	for _, m := range tier.Models {
+		ref := map[string]any{"name": m.Name, "namespace": m.Namespace}
+		if len(m.TokenRateLimits) > 0 {
+			ref["tokenRateLimits"] = rateLimitsToSpec(m.TokenRateLimits)
+		}
+		if len(m.RequestRateLimits) > 0 {
+			ref["requestRateLimits"] = rateLimitsToSpec(m.RequestRateLimits)
+		}
+		if m.TokenBudget != nil {
+			ref["tokenBudget"] = map[string]any{"limit": m.TokenBudget.Limit, "period": m.TokenBudget.Period}
+		}
+		refs = append(refs, ref)
+	}
+	_ = unstructured.SetNestedSlice(obj.Object, refs, "spec", "modelRefs")
+	_ = unstructured.SetNestedField(obj.Object, int64(tier.Priority), "spec", "priority")
+	if tier.ExpiresAt != nil {
+		_ = unstructured.SetNestedField(obj.Object, tier.ExpiresAt.UTC().Format(time.RFC3339), "spec", "expiresAt")
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "spec", "expiresAt")
+	}
+	if tier.Listener != nil {
+		hostnames := make([]any, 0, len(tier.Listener.Hostnames))
+		for _, h := range tier.Listener.Hostnames {
+			hostnames = append(hostnames, h)
+		}
+		_ = unstructured.SetNestedMap(obj.Object, map[string]any{"name": tier.Listener.Name, "hostnames": hostnames}, "spec", "listener")
+	} else {
+		unstructured.RemoveNestedField(obj.Object, "spec", "listener")
+	}
+
+	annotations := obj.GetAnnotations()
+	if len(tier.Includes) > 0 {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[constant.AnnotationIncludes] = strings.Join(tier.Includes, ",")
+	} else {
+		delete(annotations, constant.AnnotationIncludes)
+	}
+	obj.SetAnnotations(annotations)
+}
+
+func rateLimitsToSpec(limits []TierRateLimit) []any {
+	out := make([]any, 0, len(limits))
+	for _, l := range limits {
+		out = append(out, map[string]any{"limit": l.Limit, "window": l.Window})
+	}
+	return out
+}
+
+// tierFromSubscription reads a MaaSSubscription as a tier.
+func tierFromSubscription(obj *unstructured.Unstructured) Tier {
+	tier := Tier{Name: obj.GetName(), Models: []TierModel{}}
+	if priority, found, _ := unstructured.NestedInt64(obj.Object, "spec", "priority"); found {
+		tier.Priority = int32(priority) //nolint:gosec // priority is an int32 in the CRD
+	}
+	groups, _, _ := unstructured.NestedSlice(obj.Object, "spec", "owner", "groups")
+	for _, g := range groups {
+		if gm, ok := g.(map[string]any); ok {
+			if name, ok := gm["name"].(string); ok {
+				tier.Owner.Groups = append(tier.Owner.Groups, name)
+			}
+		}
+	}
+	tier.Owner.Users, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "owner", "users")
+
+	refs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "modelRefs")
+	for _, r := range refs {
+		rm, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		m := TierModel{}
+		m.Name, _, _ = unstructured.NestedString(rm, "name")
+		m.Namespace, _, _ = unstructured.NestedString(rm, "namespace")
+		m.TokenRateLimits = rateLimitsFromSpec(rm, "tokenRateLimits")
+		m.RequestRateLimits = rateLimitsFromSpec(rm, "requestRateLimits")
+		if budget, found, _ := unstructured.NestedMap(rm, "tokenBudget"); found {
+			b := &TierBudget{}
+			b.Limit, _, _ = unstructured.NestedInt64(budget, "limit")
+			b.Period, _, _ = unstructured.NestedString(budget, "period")
+			m.TokenBudget = b
+		}
+		tier.Models = append(tier.Models, m)
+	}
+
+	if s, found, _ := unstructured.NestedString(obj.Object, "spec", "expiresAt"); found {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			tier.ExpiresAt = &t
+		}
+	}
+	for name := range strings.SplitSeq(obj.GetAnnotations()[constant.AnnotationIncludes], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tier.Includes = append(tier.Includes, name)
+		}
+	}
+	if name, found, _ := unstructured.NestedString(obj.Object, "spec", "listener", "name"); found {
+		tier.Listener = &TierListener{Name: name}
+		tier.Listener.Hostnames, _, _ = unstructured.NestedStringSlice(obj.Object, "spec", "listener", "hostnames")
+	}
+	tier.Phase, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	return tier
+}
+
+func rateLimitsFromSpec(ref map[string]any, field string) []TierRateLimit {
+	items, _, _ := unstructured.NestedSlice(ref, field)
+	var out []TierRateLimit
+	for _, item := range items {
+		im, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		l := TierRateLimit{}
+		l.Limit, _, _ = unstructured.NestedInt64(im, "limit")
+		l.Window, _, _ = unstructured.NestedString(im, "window")
+		out = append(out, l)
+	}
+	return out
+}
+
+// requireAdmin writes an error response and returns false unless the caller is an admin.
+func (h *TierHandler) requireAdmin(c *gin.Context) bool {
+	user := userFrom(c)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return false
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return false
+	}
+	return true
+}
+
+// clientError maps a Kubernetes API error for the named tier to a response.
+func (h *TierHandler) clientError(c *gin.Context, name string, err error) {
+	switch {
+	case apierrors.IsNotFound(err):
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, fmt.Sprintf("tier %q not found", name))
+	case apierrors.IsAlreadyExists(err):
+		apierror.Respond(c, http.StatusConflict, apierror.Conflict, fmt.Sprintf("tier %q already exists", name))
+	case apierrors.IsConflict(err):
+		apierror.Respond(c, http.StatusConflict, apierror.Conflict, fmt.Sprintf("tier %q was modified concurrently, retry the request", name))
+	case apierrors.IsInvalid(err):
+		h.invalidRequest(c, err.Error())
+	default:
+		h.serverError(c, "Failed to manage tier", err)
+	}
+}
+
+func (h *TierHandler) serverError(c *gin.Context, message string, err error) {
+	h.logger.Error(message, "error", err)
+	apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, message)
+}
+
+func (h *TierHandler) invalidRequest(c *gin.Context, message string) {
+	apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, message)
+}
+
+// userFrom returns the user set by the ExtractUserInfo middleware, or nil.
+func userFrom(c *gin.Context) *token.UserContext {
+	user, _ := c.Get("user")
+	u, _ := user.(*token.UserContext)
+	return u
+}
@@ -0,0 +1,228 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func tierRouter(h *handlers.TierHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	tiers := router.Group("/v1/tiers", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	})
+	tiers.GET("", h.ListTiers)
+	tiers.POST("", h.CreateTier)
+	tiers.GET("/:name", h.GetTier)
+	tiers.PUT("/:name", h.UpdateTier)
+	tiers.DELETE("/:name", h.DeleteTier)
+	return router
+}
+
+func callTiers(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTierCRUD(t *testing.T) {
+	existing := subscriptionWithLimit("free", "llm", "granite", 1000, "1m")
+	existing.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+	existing.SetKind("MaaSSubscription")
+	_ = unstructured.SetNestedStringSlice(existing.Object, []string{"alice"}, "spec", "owner", "users")
+	_ = unstructured.SetNestedField(existing.Object, "ACME", "spec", "tokenMetadata", "organizationId")
+	client := newPublishClient(existing)
+	router := tierRouter(handlers.NewTierHandler(logger.Development(), client, fakeAdminChecker(true), "models-as-a-service"))
+
+	t.Run("create", func(t *testing.T) {
+		w := callTiers(router, http.MethodPost, "/v1/tiers", `{
+			"name": "premium", "priority": 10,
+			"owner": {"groups": ["premium-users"]},
+			"listener": {"name": "premium", "hostnames": ["premium.models.example.com"]},
+			"models": [{"name": "granite", "namespace": "llm", "tokenRateLimits": [{"limit": 50000, "window": "1m"}]}]
+		}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var tier handlers.Tier
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tier))
+		assert.Equal(t, &handlers.TierListener{Name: "premium", Hostnames: []string{"premium.models.example.com"}}, tier.Listener)
+
+		sub, err := client.Resource(subscription.GVR()).Namespace("models-as-a-service").Get(t.Context(), "premium", metav1.GetOptions{})
+		require.NoError(t, err)
+		priority, _, _ := unstructured.NestedInt64(sub.Object, "spec", "priority")
+		assert.Equal(t, int64(10), priority)
+		groups, _, _ := unstructured.NestedSlice(sub.Object, "spec", "owner", "groups")
+		assert.Equal(t, []any{map[string]any{"name": "premium-users"}}, groups)
+	})
+
+	t.Run("create with includes", func(t *testing.T) {
+		w := callTiers(router, http.MethodPost, "/v1/tiers", `{
+			"name": "enterprise", "priority": 20, "includes": ["premium", "free"],
+			"owner": {"groups": ["enterprise-users"]},
+			"models": [{"name": "llama", "namespace": "llm"}]
+		}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var tier handlers.Tier
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tier))
+		assert.Equal(t, []string{"premium", "free"}, tier.Includes)
+
+		sub, err := client.Resource(subscription.GVR()).Namespace("models-as-a-service").Get(t.Context(), "enterprise", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "premium,free", sub.GetAnnotations()["maas.opendatahub.io/includes"])
+
+		assert.Equal(t, http.StatusNoContent, callTiers(router, http.MethodDelete, "/v1/tiers/enterprise", "").Code)
+	})
+
+	t.Run("create duplicate", func(t *testing.T) {
+		w := callTiers(router, http.MethodPost, "/v1/tiers",
+			`{"name": "free", "owner": {"users": ["bob"]}, "models": [{"name": "granite", "namespace": "llm"}]}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("list by priority", func(t *testing.T) {
+		w := callTiers(router, http.MethodGet, "/v1/tiers", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Data []handlers.Tier `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Data, 2)
+		assert.Equal(t, "premium", body.Data[0].Name)
+		assert.Equal(t, "free", body.Data[1].Name)
+		assert.Equal(t, []handlers.TierRateLimit{{Limit: 1000, Window: "1m"}}, body.Data[1].Models[0].TokenRateLimits)
+	})
+
+	t.Run("update keeps token metadata", func(t *testing.T) {
+		w := callTiers(router, http.MethodPut, "/v1/tiers/free",
+			`{"priority": 1, "owner": {"users": ["alice", "bob"]}, "models": [{"name": "granite", "namespace": "llm", "tokenRateLimits": [{"limit": 2000, "window": "1m"}], "tokenBudget": {"limit": 1000000, "period": "30d"}}]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var tier handlers.Tier
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tier))
+		assert.Equal(t, []string{"alice", "bob"}, tier.Owner.Users)
+		assert.Equal(t, int64(2000), tier.Models[0].TokenRateLimits[0].Limit)
+		assert.Equal(t, &handlers.TierBudget{Limit: 1000000, Period: "30d"}, tier.Models[0].TokenBudget)
+		assert.Nil(t, tier.Listener)
+
+		sub, err := client.Resource(subscription.GVR()).Namespace("models-as-a-service").Get(t.Context(), "free", metav1.GetOptions{})
+		require.NoError(t, err)
+		org, _, _ := unstructured.NestedString(sub.Object, "spec", "tokenMetadata", "organizationId")
+		assert.Equal(t, "ACME", org)
+	})
+
+	t.Run("rename rejected", func(t *testing.T) {
+		w := callTiers(router, http.MethodPut, "/v1/tiers/free",
+			`{"name": "gold", "owner": {"users": ["alice"]}, "models": [{"name": "granite", "namespace": "llm"}]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, callTiers(router, http.MethodDelete, "/v1/tiers/free", "").Code)
+		assert.Equal(t, http.StatusNotFound, callTiers(router, http.MethodGet, "/v1/tiers/free", "").Code)
+		assert.Equal(t, http.StatusNotFound, callTiers(router, http.MethodDelete, "/v1/tiers/free", "").Code)
+	})
+}
+
+func TestListTiersOrdersExtremePriorities(t *testing.T) {
+	var objects []runtime.Object
+	for name, priority := range map[string]int64{"top": math.MaxInt32, "bottom": math.MinInt32, "middle": 0} {
+		sub := subscriptionWithLimit(name, "llm", "granite", 1000, "1m")
+		sub.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+		sub.SetKind("MaaSSubscription")
+		_ = unstructured.SetNestedField(sub.Object, priority, "spec", "priority")
+		objects = append(objects, sub)
+	}
+	router := tierRouter(handlers.NewTierHandler(logger.Development(), newPublishClient(objects...), fakeAdminChecker(true), "models-as-a-service"))
+
+	w := callTiers(router, http.MethodGet, "/v1/tiers", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data []handlers.Tier `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	var names []string
+	for _, tier := range body.Data {
+		names = append(names, tier.Name)
+	}
+	assert.Equal(t, []string{"top", "middle", "bottom"}, names)
+}
+
+func TestTierChangesAreAudited(t *testing.T) {
+	store := audit.NewMemoryStore()
+	auditor := audit.New(logger.Development(), store)
+	h := handlers.NewTierHandler(logger.Development(), newPublishClient(), fakeAdminChecker(true), "models-as-a-service")
+	h.SetAuditor(auditor)
+	router := tierRouter(h)
+
+	body := `{"name": "gold", "owner": {"users": ["alice"]}, "models": [{"name": "granite", "namespace": "llm"}]}`
+	require.Equal(t, http.StatusCreated, callTiers(router, http.MethodPost, "/v1/tiers", body).Code)
+	require.Equal(t, http.StatusOK, callTiers(router, http.MethodPut, "/v1/tiers/gold", body).Code)
+	require.Equal(t, http.StatusConflict, callTiers(router, http.MethodPost, "/v1/tiers", body).Code)
+	require.Equal(t, http.StatusNoContent, callTiers(router, http.MethodDelete, "/v1/tiers/gold", "").Code)
+
+	// Run flushes the queued records once its context is done.
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	auditor.Run(ctx)
+	records, _, err := store.Query(t.Context(), audit.Query{Limit: 10})
+	require.NoError(t, err)
+	var actions []string
+	for _, r := range records {
+		assert.Equal(t, audit.EndpointAdmin, r.Endpoint)
+		assert.Equal(t, "admin", r.User)
+		assert.Equal(t, "gold", r.Subscription)
+		actions = append(actions, r.Path)
+	}
+	assert.ElementsMatch(t, []string{"tier.create", "tier.update", "tier.delete"}, actions, "failed changes are not audited")
+}
+
+func TestTierValidation(t *testing.T) {
+	router := tierRouter(handlers.NewTierHandler(logger.Development(), newPublishClient(), fakeAdminChecker(true), "models-as-a-service"))
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid name", `{"name": "Gold Tier", "owner": {"users": ["a"]}, "models": [{"name": "m", "namespace": "llm"}]}`},
+		{"no owner", `{"name": "gold", "models": [{"name": "m", "namespace": "llm"}]}`},
+		{"no models", `{"name": "gold", "owner": {"users": ["a"]}, "models": []}`},
+		{"model without namespace", `{"name": "gold", "owner": {"users": ["a"]}, "models": [{"name": "m"}]}`},
+		{"bad window", `{"name": "gold", "owner": {"users": ["a"]}, "models": [{"name": "m", "namespace": "llm", "tokenRateLimits": [{"limit": 1, "window": "1 minute"}]}]}`},
+		{"includes itself", `{"name": "gold", "includes": ["gold"], "owner": {"users": ["a"]}, "models": [{"name": "m", "namespace": "llm"}]}`},
+		{"bad budget period", `{"name": "gold", "owner": {"users": ["a"]}, "models": [{"name": "m", "namespace": "llm", "tokenBudget": {"limit": 1, "period": "30m"}}]}`},
+		{"listener without hostnames", `{"name": "gold", "owner": {"users": ["a"]}, "listener": {"name": "gold"}, "models": [{"name": "m", "namespace": "llm"}]}`},
+		{"bad listener hostname", `{"name": "gold", "owner": {"users": ["a"]}, "listener": {"name": "gold", "hostnames": ["gold models"]}, "models": [{"name": "m", "namespace": "llm"}]}`},
+		{"zero limit", `{"name": "gold", "owner": {"users": ["a"]}, "models": [{"name": "m", "namespace": "llm", "requestRateLimits": [{"limit": 0, "window": "1m"}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := callTiers(router, http.MethodPost, "/v1/tiers", tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestTiersRequireAdmin(t *testing.T) {
+	router := tierRouter(handlers.NewTierHandler(logger.Development(), newPublishClient(), fakeAdminChecker(false), "models-as-a-service"))
+
+	assert.Equal(t, http.StatusForbidden, callTiers(router, http.MethodGet, "/v1/tiers", "").Code)
+	assert.Equal(t, http.StatusForbidden, callTiers(router, http.MethodDelete, "/v1/tiers/free", "").Code)
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+// UsageHandler serves chargeback reports from the usage ledger.
+type UsageHandler struct {
+	logger       *logger.Logger
+	store        usage.Store
+	adminChecker AdminChecker
+	now          func() time.Time
+
+	forecastSource ForecastSource
+}
+
+// NewUsageHandler creates a handler for GET /v1/usage and GET /v1/usage/forecast.
+func NewUsageHandler(log *logger.Logger, store usage.Store, adminChecker AdminChecker) *UsageHandler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &UsageHandler{logger: log, store: store, adminChecker: adminChecker, now: time.Now}
+}
+
+// SetClock resolves default time ranges by now instead of the local clock.
+func (h *UsageHandler) SetClock(now func() time.Time) {
+	h.now = now
+}
+
+// GetUsage handles GET /v1/usage. Query parameters:
+//   - from, to: the range, as RFC 3339 times or YYYY-MM-DD dates in UTC. to is exclusive.
+//     They default to the start of the current month and now.
+//   - groupBy: comma-separated columns among user, team, model and tier.
+//   - interval: hour, day or month to split the range into periods.
+//   - format: json (default) or csv.
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	user := userFrom(c)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return
+	}
+
+	q, format, err := h.parseQuery(c)
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+	rows, err := h.store.Query(c.Request.Context(), q)
+	if err != nil {
+		h.logger.Error("Failed to query usage", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to query usage")
+		return
+	}
+	if rows == nil {
+		rows = []usage.Row{}
+	}
+
+	if format == "csv" {
+		writeUsageCSV(c, q, rows)
+		return
+	}
+	groupBy := make([]string, len(q.GroupBy))
+	for i, d := range q.GroupBy {
+		groupBy[i] = string(d)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"object":   "list",
+		"from":     q.From.Format(time.RFC3339),
+		"to":       q.To.Format(time.RFC3339),
+		"groupBy":  groupBy,
+		"interval": q.Interval,
+		"data":     rows,
+	})
+}
+
+func (h *UsageHandler) parseQuery(c *gin.Context) (usage.Query, string, error) {
+	now := h.now().UTC()
+	q := usage.Query{
+		From: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		To:   now,
+	}
+	var err error
+	if from := c.Query("from"); from != "" {
+		if q.From, err = parseUsageTime(from); err != nil {
+			return usage.Query{}, "", fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if q.To, err = parseUsageTime(to); err != nil {
+			return usage.Query{}, "", fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if !q.From.Before(q.To) {
+		return usage.Query{}, "", errors.New("from must be before to")
+	}
+
+	if groupBy := c.Query("groupBy"); groupBy != "" {
+		for _, s := range strings.Split(groupBy, ",") {
+			d, err := usage.ParseDimension(strings.TrimSpace(s))
+			if err != nil {
+				return usage.Query{}, "", err
+			}
+			if slices.Contains(q.GroupBy, d) {
+				return usage.Query{}, "", fmt.Errorf("duplicate groupBy %q", d)
+			}
+			q.GroupBy = append(q.GroupBy, d)
+		}
+	}
+	if q.Interval, err = usage.ParseInterval(c.Query("interval")); err != nil {
+		return usage.Query{}, "", err
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		return usage.Query{}, "", fmt.Errorf("invalid format %q: must be json or csv", format)
+	}
+	return q, format, nil
+}
+
+// parseUsageTime parses an RFC 3339 time or a YYYY-MM-DD date, which is midnight UTC.
+func parseUsageTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor a YYYY-MM-DD date", s)
+	}
+	return t.UTC(), nil
+}
+
+// writeUsageCSV writes rows as a CSV attachment with a header row. The period column is only
+// present when the report has an interval.
+func writeUsageCSV(c *gin.Context, q usage.Query, rows []usage.Row) {
+	header := []string{}
+	if q.Interval != usage.IntervalNone {
+		header = append(header, "period")
+	}
+	for _, d := range q.GroupBy {
+		header = append(header, string(d))
+	}
+	header = append(header, "requests", "prompt_tokens", "completion_tokens", "total_tokens")
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="usage-%s-%s.csv"`,
+		q.From.Format("20060102T150405Z"), q.To.Format("20060102T150405Z")))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	for _, row := range rows {
+		record := make([]string, 0, len(header))
+		if q.Interval != usage.IntervalNone {
+			record = append(record, row.Period.Format(time.RFC3339))
+		}
+		for _, d := range q.GroupBy {
+			record = append(record, csvText(row.Get(d)))
+		}
+		record = append(record,
+			strconv.FormatInt(row.Requests, 10),
+			strconv.FormatInt(row.PromptTokens, 10),
+			strconv.FormatInt(row.CompletionTokens, 10),
+			strconv.FormatInt(row.TotalTokens, 10))
+		_ = w.Write(record)
+	}
+	w.Flush()
+}
+
+// csvText keeps spreadsheets from evaluating a name as a formula, prefixing values that start
+// with a formula character with a quote.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+func getUsage(t *testing.T, h *handlers.UsageHandler, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/usage", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "admin"})
+	}, h.GetUsage)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/usage"+query, nil))
+	return w
+}
+
+func usageHandler(t *testing.T, admin bool) *handlers.UsageHandler {
+	t.Helper()
+	store := usage.NewMemoryStore()
+	for _, r := range []usage.Record{
+		{Time: time.Date(2026, 9, 3, 10, 0, 0, 0, time.UTC), User: "alice", Groups: []string{"finance"}, Model: "llm/granite", Subscription: "maas/premium", PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30},
+		{Time: time.Date(2026, 9, 4, 10, 0, 0, 0, time.UTC), User: "=bob", Groups: []string{"data-science"}, Model: "llm/granite", Subscription: "maas/free", TotalTokens: 12},
+		{Time: time.Date(2026, 10, 2, 10, 0, 0, 0, time.UTC), User: "alice", Groups: []string{"finance"}, Model: "llm/granite", Subscription: "maas/premium", TotalTokens: 500},
+	} {
+		require.NoError(t, store.Add(context.Background(), r))
+	}
+	h := handlers.NewUsageHandler(logger.Development(), store, fakeAdminChecker(admin))
+	h.SetClock(func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) })
+	return h
+}
+
+func TestGetUsage(t *testing.T) {
+	h := usageHandler(t, true)
+
+	t.Run("defaults to the current month", func(t *testing.T) {
+		w := getUsage(t, h, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "2026-10-01T00:00:00Z", body["from"])
+		assert.Equal(t, "2026-10-16T12:00:00Z", body["to"])
+		assert.Equal(t, []any{map[string]any{
+			"requests": float64(1), "prompt_tokens": float64(0), "completion_tokens": float64(0), "total_tokens": float64(500),
+		}}, body["data"])
+	})
+
+	t.Run("monthly per team", func(t *testing.T) {
+		w := getUsage(t, h, "?from=2026-09-01&to=2026-10-01&groupBy=team&interval=month")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body struct {
+			GroupBy []string    `json:"groupBy"`
+			Data    []usage.Row `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, []string{"team"}, body.GroupBy)
+		september := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, []usage.Row{
+			{Period: september, Team: "data-science", Requests: 1, TotalTokens: 12},
+			{Period: september, Team: "finance", Requests: 1, PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30},
+		}, body.Data)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		w := getUsage(t, h, "?from=2026-09-01T00:00:00Z&to=2026-10-01T00:00:00Z&groupBy=user,tier&format=csv")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="usage-20260901T000000Z-20261001T000000Z.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "user,tier,requests,prompt_tokens,completion_tokens,total_tokens\n"+
+			"'=bob,maas/free,1,0,0,12\n"+
+			"alice,maas/premium,1,10,20,30\n", w.Body.String())
+	})
+}
+
+func TestGetUsageInvalidQuery(t *testing.T) {
+	h := usageHandler(t, true)
+	for _, query := range []string{
+		"?from=yesterday",
+		"?from=2026-10-01&to=2026-09-01",
+		"?groupBy=org",
+		"?groupBy=user,user",
+		"?interval=week",
+		"?format=xml",
+	} {
+		t.Run(query, func(t *testing.T) {
+			w := getUsage(t, h, query)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestGetUsageRequiresAdmin(t *testing.T) {
+	w := getUsage(t, usageHandler(t, false), "")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
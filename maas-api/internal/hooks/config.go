@@ -0,0 +1,131 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+// file is the hooks file, typically a mounted ConfigMap:
+//
+//	hooks:
+//	- name: entitlements
+//	  phase: pre
+//	  url: http://entitlements.example.svc:8080/decide
+//	  timeout: 200ms
+//	  failMode: closed
+//	- name: fraud
+//	  phase: post
+//	  grpc: fraud.example.svc:9001
+//	  failMode: open
+type file struct {
+	Hooks []hookSpec `json:"hooks"`
+}
+
+type hookSpec struct {
+	Name     string   `json:"name"`
+	Phase    Phase    `json:"phase"`
+	URL      string   `json:"url"`
+	GRPC     string   `json:"grpc"`
+	Timeout  string   `json:"timeout"`
+	FailMode FailMode `json:"failMode"`
+}
+
+// Load reads the hooks file at path and connects to its extensions. Hooks run in file order.
+// gRPC extensions are called without TLS. Close the chain to release their connections.
+func Load(log *logger.Logger, path string) (*Chain, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hooks file: %w", err)
+	}
+	var f file
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse hooks file: %w", err)
+	}
+
+	chain := NewChain(log)
+	client := &http.Client{Transport: tracing.Transport(nil)}
+	names := map[string]bool{}
+	for i, spec := range f.Hooks {
+		if names[spec.Name] {
+			chain.Close()
+			return nil, fmt.Errorf("hook %d: duplicate name %q", i+1, spec.Name)
+		}
+		names[spec.Name] = true
+		h, conn, err := spec.hook(client)
+		if err != nil {
+			chain.Close()
+			return nil, fmt.Errorf("hook %d: %w", i+1, err)
+		}
+		if conn != nil {
+			chain.conns = append(chain.conns, conn)
+		}
+		chain.hooks = append(chain.hooks, h)
+	}
+	return chain, nil
+}
+
+// hook validates the spec and creates its hook, with the gRPC connection it opened if any.
+func (spec hookSpec) hook(client *http.Client) (*Hook, *grpc.ClientConn, error) {
+	h := &Hook{Name: spec.Name, Phase: spec.Phase, Timeout: DefaultTimeout, FailMode: spec.FailMode}
+	if spec.Name == "" {
+		return nil, nil, errors.New("name is required")
+	}
+	if spec.Phase != PhasePre && spec.Phase != PhasePost {
+		return nil, nil, fmt.Errorf("phase %q must be pre or post", spec.Phase)
+	}
+	switch spec.FailMode {
+	case "":
+		h.FailMode = FailClosed
+	case FailClosed, FailOpen:
+	default:
+		return nil, nil, fmt.Errorf("failMode %q must be closed or open", spec.FailMode)
+	}
+	if spec.Timeout != "" {
+		timeout, err := time.ParseDuration(spec.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, nil, fmt.Errorf("timeout %q must be a positive duration", spec.Timeout)
+		}
+		h.Timeout = timeout
+	}
+
+	switch {
+	case (spec.URL == "") == (spec.GRPC == ""):
+		return nil, nil, errors.New("exactly one of url and grpc is required")
+	case spec.URL != "":
+		u, err := url.Parse(spec.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, nil, fmt.Errorf("url %q must be an http or https URL", spec.URL)
+		}
+		h.Extension = HTTP(spec.URL, client)
+		return h, nil, nil
+	default:
+		conn, err := grpc.NewClient(spec.GRPC, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to %s: %w", spec.GRPC, err)
+		}
+		h.Extension = GRPC(conn)
+		return h, conn, nil
+	}
+}
+
+// Close releases the gRPC connections of a loaded chain.
+func (c *Chain) Close() {
+	if c == nil {
+		return
+	}
+	for _, conn := range c.conns {
+		_ = conn.Close()
+	}
+	c.conns = nil
+}
@@ -0,0 +1,117 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// maxResponseBytes caps how much of an HTTP extension's answer is read.
+const maxResponseBytes = 64 << 10
+
+// HTTP returns an extension that POSTs the Input as JSON to url and reads an Output from a 200
+// response. Other statuses count as failures.
+func HTTP(url string, client *http.Client) Extension {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpExtension{url: url, client: client}
+}
+
+type httpExtension struct {
+	url    string
+	client *http.Client
+}
+
+func (e *httpExtension) Call(ctx context.Context, in Input) (Output, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return Output{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return Output{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return Output{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Output{}, fmt.Errorf("%s returned %s", e.url, resp.Status)
+	}
+	var out Output
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&out); err != nil {
+		return Output{}, fmt.Errorf("malformed answer from %s: %w", e.url, err)
+	}
+	return out, nil
+}
+
+// Context extension keys a gRPC extension receives the Input under.
+const (
+	ExtensionPhase        = "maas.phase"
+	ExtensionEndpoint     = "maas.endpoint"
+	ExtensionModel        = "maas.model"
+	ExtensionUser         = "maas.user"
+	ExtensionGroups       = "maas.groups" // comma-separated
+	ExtensionKeyID        = "maas.key_id"
+	ExtensionSubscription = "maas.subscription"
+)
+
+// GRPC returns an extension that calls an Envoy ext_authz service (envoy.service.auth.v3) over
+// conn, so existing ext_authz servers can serve as hooks. The request carries the path, host and
+// request ID, and the rest of the Input as context extensions. An OK answer continues with the
+// answer's headers; any other status denies with the denial's body as message.
+func GRPC(conn grpc.ClientConnInterface) Extension {
+	return &grpcExtension{client: authv3.NewAuthorizationClient(conn)}
+}
+
+type grpcExtension struct {
+	client authv3.AuthorizationClient
+}
+
+func (e *grpcExtension) Call(ctx context.Context, in Input) (Output, error) {
+	resp, err := e.client.Check(ctx, &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{Id: in.RequestID, Path: in.Path, Host: in.Host},
+			},
+			ContextExtensions: map[string]string{
+				ExtensionPhase:        string(in.Phase),
+				ExtensionEndpoint:     in.Endpoint,
+				ExtensionModel:        in.Model,
+				ExtensionUser:         in.User,
+				ExtensionGroups:       strings.Join(in.Groups, ","),
+				ExtensionKeyID:        in.KeyID,
+				ExtensionSubscription: in.Subscription,
+			},
+		},
+	})
+	if err != nil {
+		return Output{}, err
+	}
+	if codes.Code(resp.GetStatus().GetCode()) != codes.OK {
+		message := resp.GetDeniedResponse().GetBody()
+		if message == "" {
+			message = resp.GetStatus().GetMessage()
+		}
+		return Output{Deny: true, Message: message}, nil
+	}
+	var out Output
+	for _, h := range resp.GetOkResponse().GetHeaders() {
+		if out.Headers == nil {
+			out.Headers = map[string]string{}
+		}
+		out.Headers[h.GetHeader().GetKey()] = h.GetHeader().GetValue()
+	}
+	return out, nil
+}
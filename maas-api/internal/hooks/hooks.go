@@ -0,0 +1,224 @@
+// Package hooks lets external extensions take part in authorization decisions. Hooks run in a
+// configured order before the decision (pre) and after an allowed one (post). Each can veto the
+// request or add headers to it, so entitlement systems or fraud checks can be added without
+// changing maas-api.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+// DefaultTimeout bounds a hook call when its configuration sets no timeout.
+const DefaultTimeout = 500 * time.Millisecond
+
+// Phase is when a hook runs.
+type Phase string
+
+const (
+	// PhasePre hooks run once the caller is authenticated, before MaaSAuthPolicies and subscription
+	// selection.
+	PhasePre Phase = "pre"
+	// PhasePost hooks run after maas-api allowed the request, before the decision is returned.
+	// Denied requests skip them.
+	PhasePost Phase = "post"
+)
+
+// FailMode is what happens when a hook cannot be reached, times out or answers malformed.
+type FailMode string
+
+const (
+	// FailClosed denies the request with reason hook_failed.
+	FailClosed FailMode = "closed"
+	// FailOpen skips the hook.
+	FailOpen FailMode = "open"
+)
+
+var (
+	callsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_decision_hook_calls_total",
+		Help: "Decision hook calls by hook, phase and result (continue, deny, error).",
+	}, []string{"hook", "phase", "result"})
+	callDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maas_decision_hook_duration_seconds",
+		Help:    "Decision hook call latency by hook.",
+		Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"hook"})
+)
+
+func init() {
+	prometheus.MustRegister(callsTotal, callDuration)
+}
+
+// Input is what a hook is told about the request.
+type Input struct {
+	Phase    Phase  `json:"phase"`
+	Endpoint string `json:"endpoint"` // ext_authz, batch for POST /v1/models/authorize/batch, or access for GET /v1/models/{name}/access
+	Model    string `json:"model"`    // namespace/name
+	User     string `json:"user"`
+	// Groups are the caller's groups.
+	Groups []string `json:"groups,omitempty"`
+	KeyID  string   `json:"keyId,omitempty"`
+	// Subscription is the subscription the caller asked for in pre hooks, and the selected one in
+	// post hooks.
+	Subscription string `json:"subscription,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Host         string `json:"host,omitempty"`
+	RequestID    string `json:"requestId,omitempty"`
+	// DryRun is set when the decision authorizes no request (batch and access checks), so hooks
+	// can skip side effects such as recording usage.
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// Output is a hook's answer. The zero Output lets the request continue unchanged.
+type Output struct {
+	// Deny vetoes the request with reason hook_denied.
+	Deny bool `json:"deny,omitempty"`
+	// Message is returned to the caller with a denial.
+	Message string `json:"message,omitempty"`
+	// Headers are added to the request forwarded to the model when it is allowed. Only X- headers
+	// are kept, except X-MaaS- ones, which are maas-api's.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Extension is an external system a hook calls.
+type Extension interface {
+	Call(ctx context.Context, in Input) (Output, error)
+}
+
+// Hook is a configured call to an extension.
+type Hook struct {
+	Name      string
+	Phase     Phase
+	Timeout   time.Duration
+	FailMode  FailMode
+	Extension Extension
+}
+
+// Verdict is the combined answer of a phase's hooks.
+type Verdict struct {
+	// Reason is reason.HookDenied or reason.HookFailed when a hook stopped the request, else "".
+	Reason  string
+	Message string
+	// Headers are the headers the hooks add, later hooks overriding earlier ones.
+	Headers map[string]string
+}
+
+// Denied reports whether a hook stopped the request.
+func (v Verdict) Denied() bool {
+	return v.Reason != ""
+}
+
+// Chain runs hooks in order. A nil Chain runs none.
+type Chain struct {
+	hooks  []*Hook
+	conns  []*grpc.ClientConn
+	logger *logger.Logger
+}
+
+// NewChain creates a chain running hooks in the given order.
+func NewChain(log *logger.Logger, hooks ...*Hook) *Chain {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Chain{hooks: hooks, logger: log}
+}
+
+// Names returns the hook names in order.
+func (c *Chain) Names() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, len(c.hooks))
+	for i, h := range c.hooks {
+		names[i] = h.Name
+	}
+	return names
+}
+
+// Run calls the hooks of in.Phase in order, stopping at the first that denies the request or
+// fails closed.
+func (c *Chain) Run(ctx context.Context, in Input) Verdict {
+	var verdict Verdict
+	if c == nil {
+		return verdict
+	}
+	for _, h := range c.hooks {
+		if h.Phase != in.Phase {
+			continue
+		}
+		out, err := c.call(ctx, h, in)
+		switch {
+		case err != nil && h.FailMode == FailOpen:
+			c.logger.Warn("Decision hook failed, skipping it", "hook", h.Name, "phase", in.Phase, "error", err)
+			continue
+		case err != nil:
+			c.logger.Error("Decision hook failed, denying the request", "hook", h.Name, "phase", in.Phase, "error", err)
+			return Verdict{Reason: reason.HookFailed, Message: reason.Message(reason.HookFailed)}
+		case out.Deny:
+			c.logger.Debug("Decision hook denied the request", "hook", h.Name, "phase", in.Phase, "user", in.User, "model", in.Model)
+			message := out.Message
+			if message == "" {
+				message = reason.Message(reason.HookDenied)
+			}
+			return Verdict{Reason: reason.HookDenied, Message: message}
+		}
+		for name, value := range out.Headers {
+			lower := strings.ToLower(name)
+			if !strings.HasPrefix(lower, "x-") || strings.HasPrefix(lower, "x-maas-") {
+				c.logger.Debug("Dropped header set by decision hook", "hook", h.Name, "header", name)
+				continue
+			}
+			if verdict.Headers == nil {
+				verdict.Headers = map[string]string{}
+			}
+			verdict.Headers[name] = value
+		}
+	}
+	return verdict
+}
+
+// call calls one hook within its timeout, in a span.
+func (c *Chain) call(ctx context.Context, h *Hook, in Input) (Output, error) {
+	ctx, span := tracing.Start(ctx, "maas.hook", trace.WithAttributes(
+		attribute.String("maas.hook", h.Name),
+		attribute.String("maas.hook.phase", string(h.Phase)),
+	))
+	defer span.End()
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	out, err := h.Extension.Call(ctx, in)
+	callDuration.WithLabelValues(h.Name).Observe(time.Since(start).Seconds())
+	result := "continue"
+	switch {
+	case err != nil:
+		result = "error"
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("no answer within %s: %w", timeout, err)
+		}
+		tracing.Fail(span, err)
+	case out.Deny:
+		result = "deny"
+	}
+	callsTotal.WithLabelValues(h.Name, string(h.Phase), result).Inc()
+	span.SetAttributes(attribute.String("maas.hook.result", result))
+	return out, err
+}
@@ -0,0 +1,200 @@
+package hooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/hooks"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+type extensionFunc func(ctx context.Context, in hooks.Input) (hooks.Output, error)
+
+func (f extensionFunc) Call(ctx context.Context, in hooks.Input) (hooks.Output, error) {
+	return f(ctx, in)
+}
+
+func TestChain(t *testing.T) {
+	var called []string
+	hook := func(name string, phase hooks.Phase, failMode hooks.FailMode, out hooks.Output, err error) *hooks.Hook {
+		return &hooks.Hook{Name: name, Phase: phase, FailMode: failMode, Timeout: 50 * time.Millisecond,
+			Extension: extensionFunc(func(ctx context.Context, _ hooks.Input) (hooks.Output, error) {
+				called = append(called, name)
+				if err == context.DeadlineExceeded {
+					<-ctx.Done()
+					return hooks.Output{}, ctx.Err()
+				}
+				return out, err
+			})}
+	}
+	in := hooks.Input{Phase: hooks.PhasePre, Model: "llm/granite", User: "alice"}
+
+	t.Run("headers of every hook", func(t *testing.T) {
+		called = nil
+		chain := hooks.NewChain(logger.Development(),
+			hook("entitlements", hooks.PhasePre, hooks.FailClosed, hooks.Output{Headers: map[string]string{
+				"X-Entitlement": "gold", "X-MaaS-Username": "mallory", "Authorization": "Bearer x",
+			}}, nil),
+			hook("fraud", hooks.PhasePost, hooks.FailClosed, hooks.Output{Deny: true}, nil),
+			hook("flaky", hooks.PhasePre, hooks.FailOpen, hooks.Output{}, errors.New("connection refused")),
+			hook("slow", hooks.PhasePre, hooks.FailOpen, hooks.Output{}, context.DeadlineExceeded),
+			hook("tagger", hooks.PhasePre, hooks.FailClosed, hooks.Output{Headers: map[string]string{"X-Entitlement": "platinum"}}, nil),
+		)
+		verdict := chain.Run(context.Background(), in)
+		assert.False(t, verdict.Denied())
+		assert.Equal(t, map[string]string{"X-Entitlement": "platinum"}, verdict.Headers, "only X- headers other than X-MaaS- are kept, later hooks win")
+		assert.Equal(t, []string{"entitlements", "flaky", "slow", "tagger"}, called, "post hooks do not run before the decision")
+	})
+
+	t.Run("veto stops the chain", func(t *testing.T) {
+		called = nil
+		chain := hooks.NewChain(logger.Development(),
+			hook("fraud", hooks.PhasePre, hooks.FailOpen, hooks.Output{Deny: true, Message: "suspicious activity"}, nil),
+			hook("entitlements", hooks.PhasePre, hooks.FailClosed, hooks.Output{}, nil),
+		)
+		verdict := chain.Run(context.Background(), in)
+		assert.Equal(t, hooks.Verdict{Reason: reason.HookDenied, Message: "suspicious activity"}, verdict)
+		assert.Equal(t, []string{"fraud"}, called)
+	})
+
+	t.Run("fail closed", func(t *testing.T) {
+		chain := hooks.NewChain(logger.Development(),
+			hook("slow", hooks.PhasePre, hooks.FailClosed, hooks.Output{}, context.DeadlineExceeded))
+		verdict := chain.Run(context.Background(), in)
+		assert.Equal(t, reason.HookFailed, verdict.Reason)
+	})
+
+	t.Run("nil chain", func(t *testing.T) {
+		var chain *hooks.Chain
+		assert.False(t, chain.Run(context.Background(), in).Denied())
+	})
+}
+
+func TestHTTP(t *testing.T) {
+	ext := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in hooks.Input
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch in.User {
+		case "alice":
+			_ = json.NewEncoder(w).Encode(hooks.Output{Headers: map[string]string{"X-Entitlement": in.Model}})
+		case "bob":
+			_ = json.NewEncoder(w).Encode(hooks.Output{Deny: true, Message: "no entitlement"})
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ext.Close()
+	extension := hooks.HTTP(ext.URL, nil)
+
+	out, err := extension.Call(context.Background(), hooks.Input{User: "alice", Model: "llm/granite"})
+	require.NoError(t, err)
+	assert.Equal(t, hooks.Output{Headers: map[string]string{"X-Entitlement": "llm/granite"}}, out)
+
+	out, err = extension.Call(context.Background(), hooks.Input{User: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, hooks.Output{Deny: true, Message: "no entitlement"}, out)
+
+	_, err = extension.Call(context.Background(), hooks.Input{User: "carol"})
+	assert.ErrorContains(t, err, "502")
+}
+
+// authServer allows alice and denies everyone else.
+type authServer struct {
+	authv3.UnimplementedAuthorizationServer
+}
+
+func (authServer) Check(_ context.Context, req *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	extensions := req.GetAttributes().GetContextExtensions()
+	if extensions[hooks.ExtensionUser] != "alice" {
+		return &authv3.CheckResponse{
+			Status:       &rpcstatus.Status{Code: int32(codes.PermissionDenied)},
+			HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: &authv3.DeniedHttpResponse{Body: "not entitled"}},
+		}, nil
+	}
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{OkResponse: &authv3.OkHttpResponse{Headers: []*corev3.HeaderValueOption{
+			{Header: &corev3.HeaderValue{Key: "X-Entitlement", Value: extensions[hooks.ExtensionModel] + "@" + req.GetAttributes().GetRequest().GetHttp().GetPath()}},
+		}}},
+	}, nil
+}
+
+func TestGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	authv3.RegisterAuthorizationServer(server, authServer{})
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	extension := hooks.GRPC(conn)
+
+	out, err := extension.Call(context.Background(), hooks.Input{User: "alice", Model: "llm/granite", Path: "/llm/granite/v1/chat/completions"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Entitlement": "llm/granite@/llm/granite/v1/chat/completions"}, out.Headers)
+
+	out, err = extension.Call(context.Background(), hooks.Input{User: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, hooks.Output{Deny: true, Message: "not entitled"}, out)
+}
+
+func TestLoad(t *testing.T) {
+	load := func(t *testing.T, content string) (*hooks.Chain, error) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "hooks.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		chain, err := hooks.Load(logger.Development(), path)
+		t.Cleanup(chain.Close)
+		return chain, err
+	}
+
+	chain, err := load(t, `
+hooks:
+- name: entitlements
+  phase: pre
+  url: http://entitlements.example.svc:8080/decide
+  timeout: 200ms
+- name: fraud
+  phase: post
+  grpc: fraud.example.svc:9001
+  failMode: open
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"entitlements", "fraud"}, chain.Names())
+
+	for content, want := range map[string]string{
+		"hooks:\n- phase: pre\n  url: http://a":                                                       "name is required",
+		"hooks:\n- name: a\n  phase: during\n  url: http://a":                                         "phase",
+		"hooks:\n- name: a\n  phase: pre":                                                             "exactly one of url and grpc",
+		"hooks:\n- name: a\n  phase: pre\n  url: http://a\n  grpc: a:1":                               "exactly one of url and grpc",
+		"hooks:\n- name: a\n  phase: pre\n  url: ftp://a":                                             "http or https",
+		"hooks:\n- name: a\n  phase: pre\n  url: http://a\n  timeout: 0s":                             "positive duration",
+		"hooks:\n- name: a\n  phase: pre\n  url: http://a\n  failMode: half":                          "failMode",
+		"hooks:\n- name: a\n  phase: pre\n  url: http://a\n  retries: 3":                              "unknown field",
+		"hooks:\n- name: a\n  phase: pre\n  url: http://a\n- name: a\n  phase: post\n  url: http://b": "duplicate name",
+	} {
+		_, err := load(t, content)
+		assert.ErrorContains(t, err, want, content)
+	}
+}
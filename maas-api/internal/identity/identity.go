@@ -0,0 +1,244 @@
+// Package identity authenticates callers with a JWT from a trusted OIDC issuer and derives their
+// username, groups and tier from configurable claims, so maas-api can be called by clients or
+// gateways that do not resolve the caller's identity and tier themselves.
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+var verificationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_identity_verifications_total",
+	Help: "JWT identity verifications by result (valid, invalid, missing).",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(verificationsTotal)
+}
+
+// clockLeeway is the clock skew tolerated on the exp, nbf and iat claims.
+const clockLeeway = 30 * time.Second
+
+// signingMethods are the JWS algorithms accepted; symmetric algorithms are not, as the keys come
+// from a public JWKS.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Options configures JWT identity extraction. The zero Options disables it.
+type Options struct {
+	// Issuer is the iss tokens must carry, e.g. https://keycloak.example.com/realms/maas.
+	Issuer string
+	// Audience is the aud tokens must include.
+	Audience string
+	// JWKSURL serves the issuer's signing keys. Defaults to the jwks_uri of the issuer's
+	// /.well-known/openid-configuration.
+	JWKSURL string
+	// UsernameClaim, GroupsClaim and TierClaim are the claims the caller's username, groups and
+	// tier are read from, as dot-separated paths into nested claims, e.g. realm_access.roles. A
+	// claim whose name contains dots is matched whole first. An empty TierClaim leaves the tier to
+	// subscription selection.
+	UsernameClaim string
+	GroupsClaim   string
+	TierClaim     string
+}
+
+// Enabled reports whether JWT identity extraction is configured.
+func (o Options) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// Validate checks the options are consistent.
+func (o Options) Validate() error {
+	if !o.Enabled() {
+		if o.Audience != "" || o.JWKSURL != "" || o.TierClaim != "" {
+			return errors.New("IDENTITY_JWT_AUDIENCE, IDENTITY_JWKS_URL and IDENTITY_TIER_CLAIM require IDENTITY_JWT_ISSUER")
+		}
+		return nil
+	}
+	if !isHTTPURL(o.Issuer) {
+		return fmt.Errorf("IDENTITY_JWT_ISSUER %q is not an http(s) URL", o.Issuer)
+	}
+	if o.Audience == "" {
+		return errors.New("IDENTITY_JWT_ISSUER requires IDENTITY_JWT_AUDIENCE")
+	}
+	if o.JWKSURL != "" && !isHTTPURL(o.JWKSURL) {
+		return fmt.Errorf("IDENTITY_JWKS_URL %q is not an http(s) URL", o.JWKSURL)
+	}
+	if o.UsernameClaim == "" {
+		return errors.New("IDENTITY_USERNAME_CLAIM must not be empty")
+	}
+	return nil
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Identity is a caller authenticated by a JWT.
+type Identity struct {
+	Username string
+	Groups   []string
+	// Tier is the subscription the token binds the caller to; empty when the token names none.
+	Tier string
+}
+
+// ContextKey is the gin context key of the caller's Identity.
+const ContextKey = "identity"
+
+// FromContext returns the caller's verified identity, set by Verifier.Require or
+// Verifier.Optional.
+func FromContext(c *gin.Context) (*Identity, bool) {
+	v, exists := c.Get(ContextKey)
+	id, ok := v.(*Identity)
+	return id, exists && ok
+}
+
+// Verifier validates JWTs against the issuer's keys and extracts identities from their claims.
+type Verifier struct {
+	opts   Options
+	keys   *keySet
+	logger *logger.Logger
+}
+
+// New returns a verifier of tokens issued as opts configures.
+func New(log *logger.Logger, opts Options) *Verifier {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Verifier{
+		opts:   opts,
+		keys:   newKeySet(log, opts.Issuer, opts.JWKSURL, &http.Client{Timeout: 10 * time.Second}),
+		logger: log,
+	}
+}
+
+// Verify checks the signature, issuer, audience and lifetime of a JWT and returns the identity its
+// claims carry.
+func (v *Verifier) Verify(ctx context.Context, raw string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.key(ctx, kid)
+	},
+		jwt.WithIssuer(v.opts.Issuer),
+		jwt.WithAudience(v.opts.Audience),
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockLeeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &Identity{}
+	if id.Username = firstString(claim(claims, v.opts.UsernameClaim)); id.Username == "" {
+		return nil, fmt.Errorf("token has no %s claim", v.opts.UsernameClaim)
+	}
+	if v.opts.GroupsClaim != "" {
+		id.Groups = stringValues(claim(claims, v.opts.GroupsClaim))
+	}
+	if v.opts.TierClaim != "" {
+		id.Tier = firstString(claim(claims, v.opts.TierClaim))
+	}
+	return id, nil
+}
+
+// Require authenticates every request with a bearer JWT, answering 401 without a valid one. The
+// caller's identity replaces the identity headers the gateway would set (see
+// token.Handler.ExtractUserInfo), so routes behind it see the same user context.
+func (v *Verifier) Require() gin.HandlerFunc {
+	return v.middleware(true)
+}
+
+// Optional is Require for routes also called without a token, e.g. by Authorino: requests
+// without a bearer JWT pass unchanged, but a token that is present must be valid.
+func (v *Verifier) Optional() gin.HandlerFunc {
+	return v.middleware(false)
+}
+
+func (v *Verifier) middleware(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || !token.LooksLikeJWT(raw) {
+			if !required {
+				c.Next()
+				return
+			}
+			verificationsTotal.WithLabelValues("missing").Inc()
+			apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authentication required")
+			c.Abort()
+			return
+		}
+		id, err := v.Verify(c.Request.Context(), raw)
+		if err != nil {
+			verificationsTotal.WithLabelValues("invalid").Inc()
+			v.logger.Debug("Rejected JWT", "error", err)
+			apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authentication required")
+			c.Abort()
+			return
+		}
+		verificationsTotal.WithLabelValues("valid").Inc()
+		c.Set(ContextKey, id)
+		c.Set("user", &token.UserContext{Username: id.Username, Groups: id.Groups})
+		c.Next()
+	}
+}
+
+// claim returns the value at a dot-separated path into claims, matching a claim named path whole
+// first; nil when it is missing.
+func claim(claims jwt.MapClaims, path string) any {
+	if v, ok := claims[path]; ok {
+		return v
+	}
+	var cur any = map[string]any(claims)
+	for part := range strings.SplitSeq(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if cur, ok = m[part]; !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
+// stringValues returns a string claim as one value, or the strings of a list claim.
+func stringValues(v any) []string {
+	switch v := v.(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func firstString(v any) string {
+	if values := stringValues(v); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
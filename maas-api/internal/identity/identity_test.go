@@ -0,0 +1,261 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// issuer serves an OpenID configuration and the JWKS of its keys, and signs tokens with them.
+type issuer struct {
+	server     *httptest.Server
+	keys       map[string]*rsa.PrivateKey
+	jwksServed atomic.Int32
+	// gate, when set, holds each JWKS response until it is closed.
+	gate chan struct{}
+}
+
+func newIssuer(t *testing.T, kids ...string) *issuer {
+	t.Helper()
+	iss := &issuer{keys: make(map[string]*rsa.PrivateKey)}
+	for _, kid := range kids {
+		iss.addKey(t, kid)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": iss.server.URL, "jwks_uri": iss.server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, _ *http.Request) {
+		iss.jwksServed.Add(1)
+		if iss.gate != nil {
+			<-iss.gate
+		}
+		var set jose.JSONWebKeySet
+		for kid, key := range iss.keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: &key.PublicKey, KeyID: kid, Algorithm: "RS256", Use: "sig"})
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	})
+	iss.server = httptest.NewServer(mux)
+	t.Cleanup(iss.server.Close)
+	return iss
+}
+
+func (iss *issuer) addKey(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	iss.keys[kid] = key
+}
+
+func (iss *issuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = kid
+	signed, err := tok.SignedString(iss.keys[kid])
+	require.NoError(t, err)
+	return signed
+}
+
+func (iss *issuer) claims(extra jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss": iss.server.URL,
+		"aud": "maas-api",
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	return claims
+}
+
+func (iss *issuer) options() Options {
+	return Options{Issuer: iss.server.URL, Audience: "maas-api", UsernameClaim: "sub", GroupsClaim: "groups"}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, Options{}.Validate())
+	assert.NoError(t, Options{Issuer: "https://sso.example.com/realms/maas", Audience: "maas-api", UsernameClaim: "sub"}.Validate())
+
+	for _, tc := range []struct {
+		opts Options
+		err  string
+	}{
+		{Options{Audience: "maas-api"}, "require IDENTITY_JWT_ISSUER"},
+		{Options{Issuer: "sso.example.com", Audience: "maas-api", UsernameClaim: "sub"}, "not an http(s) URL"},
+		{Options{Issuer: "https://sso.example.com", UsernameClaim: "sub"}, "requires IDENTITY_JWT_AUDIENCE"},
+		{Options{Issuer: "https://sso.example.com", Audience: "maas-api", JWKSURL: "keys", UsernameClaim: "sub"}, "IDENTITY_JWKS_URL"},
+		{Options{Issuer: "https://sso.example.com", Audience: "maas-api"}, "IDENTITY_USERNAME_CLAIM"},
+	} {
+		assert.ErrorContains(t, tc.opts.Validate(), tc.err, "%+v", tc.opts)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	iss := newIssuer(t, "k1")
+	opts := iss.options()
+	opts.GroupsClaim = "realm_access.roles"
+	opts.TierClaim = "maas.tier"
+	v := New(nil, opts)
+	ctx := context.Background()
+
+	id, err := v.Verify(ctx, iss.sign(t, "k1", iss.claims(jwt.MapClaims{
+		"realm_access": map[string]any{"roles": []any{"ml-team", "premium-users"}},
+		"maas.tier":    "premium",
+	})))
+	require.NoError(t, err)
+	assert.Equal(t, &Identity{Username: "alice", Groups: []string{"ml-team", "premium-users"}, Tier: "premium"}, id)
+
+	id, err = v.Verify(ctx, iss.sign(t, "k1", iss.claims(jwt.MapClaims{
+		"realm_access": map[string]any{"roles": "ml-team"},
+		"maas":         map[string]any{"tier": []any{"enterprise", "premium"}},
+	})))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ml-team"}, id.Groups, "a single group may be a string")
+	assert.Equal(t, "enterprise", id.Tier, "nested paths are followed, and the first of several tiers is taken")
+
+	for name, claims := range map[string]jwt.MapClaims{
+		"wrong issuer":   {"iss": "https://other.example.com"},
+		"wrong audience": {"aud": "other"},
+		"expired":        {"exp": time.Now().Add(-time.Hour).Unix()},
+		"no expiry":      {"exp": nil},
+		"no username":    {"sub": nil},
+	} {
+		c := iss.claims(nil)
+		for k, val := range claims {
+			if val == nil {
+				delete(c, k)
+			} else {
+				c[k] = val
+			}
+		}
+		_, err := v.Verify(ctx, iss.sign(t, "k1", c))
+		assert.Error(t, err, name)
+	}
+
+	forger := newIssuer(t, "k1")
+	_, err = v.Verify(ctx, forger.sign(t, "k1", iss.claims(nil)))
+	assert.Error(t, err, "tokens signed with another key are rejected")
+
+	hs := jwt.NewWithClaims(jwt.SigningMethodHS256, iss.claims(nil))
+	signed, err := hs.SignedString([]byte("secret"))
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, signed)
+	assert.Error(t, err, "symmetric algorithms are rejected")
+}
+
+func TestKeyRotation(t *testing.T) {
+	iss := newIssuer(t, "k1")
+	v := New(nil, iss.options())
+	ctx := context.Background()
+
+	_, err := v.Verify(ctx, iss.sign(t, "k1", iss.claims(nil)))
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, iss.sign(t, "k1", iss.claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), iss.jwksServed.Load(), "keys are cached")
+
+	// The issuer rotates to k2: its first token triggers a fetch.
+	iss.addKey(t, "k2")
+	v.keys.triedAt = time.Time{}
+	_, err = v.Verify(ctx, iss.sign(t, "k2", iss.claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), iss.jwksServed.Load())
+
+	// Unknown key IDs do not fetch again before the backoff.
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, iss.claims(nil))
+	tok.Header["kid"] = "forged"
+	signed, err := tok.SignedString(iss.keys["k1"])
+	require.NoError(t, err)
+	_, err = v.Verify(ctx, signed)
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), iss.jwksServed.Load())
+}
+
+func TestConcurrentMissesShareOneFetch(t *testing.T) {
+	iss := newIssuer(t, "k1")
+	iss.gate = make(chan struct{})
+	v := New(nil, iss.options())
+	ctx := context.Background()
+	signed := iss.sign(t, "k1", iss.claims(nil))
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := v.Verify(ctx, signed)
+			assert.NoError(t, err)
+		}()
+	}
+	// A caller that gives up returns at once and does not fail the fetch for the others.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := v.keys.key(canceled, "k1")
+	require.ErrorIs(t, err, context.Canceled)
+
+	close(iss.gate)
+	wg.Wait()
+	assert.Equal(t, int32(1), iss.jwksServed.Load())
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	iss := newIssuer(t, "k1")
+	opts := iss.options()
+	opts.TierClaim = "tier"
+	v := New(nil, opts)
+
+	handler := func(c *gin.Context) {
+		user, _ := c.Get("user")
+		id, _ := FromContext(c)
+		c.JSON(http.StatusOK, gin.H{"user": user, "identity": id})
+	}
+	router := gin.New()
+	router.GET("/required", v.Require(), handler)
+	router.GET("/optional", v.Optional(), handler)
+
+	serve := func(path, bearer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	valid := iss.sign(t, "k1", iss.claims(jwt.MapClaims{"groups": []any{"ml-team"}, "tier": "premium"}))
+	w := serve("/required", valid)
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		User     token.UserContext `json:"user"`
+		Identity Identity          `json:"identity"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, token.UserContext{Username: "alice", Groups: []string{"ml-team"}}, body.User)
+	assert.Equal(t, "premium", body.Identity.Tier)
+
+	assert.Equal(t, http.StatusUnauthorized, serve("/required", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("/required", "opaque-key").Code)
+	assert.Equal(t, http.StatusOK, serve("/optional", "").Code, "requests without a JWT pass")
+	assert.Equal(t, http.StatusOK, serve("/optional", valid).Code)
+	expired := iss.sign(t, "k1", iss.claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()}))
+	assert.Equal(t, http.StatusUnauthorized, serve("/optional", expired).Code, "a JWT that is present must be valid")
+}
@@ -0,0 +1,161 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+const (
+	// keysMaxAge is how long fetched signing keys are used before they are fetched again.
+	keysMaxAge = time.Hour
+	// unknownKeyBackoff is the minimum time between fetches triggered by tokens signed with a key
+	// the set does not hold, so that forged key IDs cannot flood the issuer.
+	unknownKeyBackoff = 10 * time.Second
+	// fetchTimeout bounds a fetch of the issuer's keys, which runs apart from the requests waiting
+	// for it.
+	fetchTimeout = 10 * time.Second
+)
+
+// keySet caches the issuer's signing keys, fetching them again when they get old or a token is
+// signed with a key it does not hold, which is how issuers rotate keys. A failed fetch keeps the
+// keys held. Lookups only take a read lock; fetches run outside it, one at a time.
+type keySet struct {
+	issuer string
+	client *http.Client
+	logger *logger.Logger
+	group  singleflight.Group
+
+	mu        sync.RWMutex
+	jwksURL   string
+	keys      jose.JSONWebKeySet
+	fetchedAt time.Time
+	triedAt   time.Time
+}
+
+func newKeySet(log *logger.Logger, issuer, jwksURL string, client *http.Client) *keySet {
+	return &keySet{issuer: strings.TrimSuffix(issuer, "/"), jwksURL: jwksURL, client: client, logger: log}
+}
+
+// key returns the public key with ID kid. Tokens without a kid are accepted while the issuer
+// publishes a single key.
+func (s *keySet) key(ctx context.Context, kid string) (any, error) {
+	s.mu.RLock()
+	k, ok := s.lookup(kid)
+	fresh := time.Since(s.fetchedAt) < keysMaxAge
+	s.mu.RUnlock()
+	if ok && fresh {
+		return k, nil
+	}
+
+	// Concurrent misses share one fetch, on its own context so that a caller giving up does not
+	// fail it for the others.
+	select {
+	case <-s.group.DoChan("fetch", func() (any, error) {
+		s.refresh()
+		return nil, nil
+	}):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if k, ok := s.lookup(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("no signing key %q in the issuer's JWKS", kid)
+}
+
+// refresh fetches the keys unless a fetch was tried within unknownKeyBackoff.
+func (s *keySet) refresh() {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.triedAt) < unknownKeyBackoff {
+		s.mu.Unlock()
+		return
+	}
+	s.triedAt = now
+	jwksURL := s.jwksURL
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	keys, jwksURL, err := s.fetch(ctx, jwksURL)
+	if err != nil {
+		s.logger.Warn("Failed to fetch JWKS", "issuer", s.issuer, "error", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jwksURL = jwksURL
+	s.keys = keys
+	s.fetchedAt = now
+}
+
+// lookup finds kid in the held keys. The caller holds s.mu.
+func (s *keySet) lookup(kid string) (any, bool) {
+	if kid == "" {
+		if len(s.keys.Keys) == 1 {
+			return s.keys.Keys[0].Key, true
+		}
+		return nil, false
+	}
+	for _, k := range s.keys.Key(kid) {
+		if k.Use == "" || k.Use == "sig" {
+			return k.Key, true
+		}
+	}
+	return nil, false
+}
+
+// fetch reads the JWKS at jwksURL, discovering the URL from the issuer's OpenID configuration
+// when it is empty, and returns the keys and the URL.
+func (s *keySet) fetch(ctx context.Context, jwksURL string) (jose.JSONWebKeySet, string, error) {
+	var keys jose.JSONWebKeySet
+	if jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := s.get(ctx, s.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return keys, "", err
+		}
+		if discovery.JWKSURI == "" {
+			return keys, "", errors.New("issuer's OpenID configuration has no jwks_uri")
+		}
+		jwksURL = discovery.JWKSURI
+	}
+	if err := s.get(ctx, jwksURL, &keys); err != nil {
+		return keys, "", err
+	}
+	return keys, jwksURL, nil
+}
+
+func (s *keySet) get(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", url, err)
+	}
+	return nil
+}
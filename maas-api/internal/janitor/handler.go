@@ -0,0 +1,89 @@
+package janitor
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+// AdminChecker reports whether a user may call admin-only endpoints.
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, user *token.UserContext) bool
+}
+
+// Handler exposes the janitor to admins.
+type Handler struct {
+	logger       *logger.Logger
+	janitor      *Janitor
+	adminChecker AdminChecker
+}
+
+// NewHandler creates a handler for the janitor endpoints. They require the user set by
+// ExtractUserInfo to be an admin.
+func NewHandler(log *logger.Logger, janitor *Janitor, adminChecker AdminChecker) *Handler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if janitor == nil {
+		panic("janitor cannot be nil")
+	}
+	if adminChecker == nil {
+		panic("adminChecker cannot be nil")
+	}
+	return &Handler{logger: log, janitor: janitor, adminChecker: adminChecker}
+}
+
+// requireAdmin responds and returns false unless the caller is an admin.
+func (h *Handler) requireAdmin(c *gin.Context) bool {
+	value, _ := c.Get("user")
+	user, _ := value.(*token.UserContext)
+	if user == nil {
+		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return false
+	}
+	if !h.adminChecker.IsAdmin(c.Request.Context(), user) {
+		apierror.Respond(c, http.StatusForbidden, apierror.PermissionDenied, "Admin access required")
+		return false
+	}
+	return true
+}
+
+// Run handles POST /admin/v1/janitor/run. Pass ?dryRun=true to only report what would be removed.
+func (h *Handler) Run(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "dryRun must be true or false")
+		return
+	}
+
+	report, err := h.janitor.Run(c.Request.Context(), dryRun)
+	if err != nil {
+		h.logger.Error("Janitor run failed", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Janitor run failed")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// LastReport handles GET /admin/v1/janitor/report.
+func (h *Handler) LastReport(c *gin.Context) {
+	if !h.requireAdmin(c) {
+		return
+	}
+	report := h.janitor.LastReport()
+	if report == nil {
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "the janitor has not run yet")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,54 @@
+package janitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+type admins []string
+
+func (a admins) IsAdmin(_ context.Context, user *token.UserContext) bool {
+	for _, name := range a {
+		if user.Username == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHandlerRequiresAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	j := New(logger.Development(), api_keys.NewMockStore(), staticLister{}, time.Hour, false)
+	h := NewHandler(logger.Development(), j, admins{"root"})
+
+	for _, tt := range []struct {
+		user string
+		want int
+	}{
+		{user: "alice", want: http.StatusForbidden},
+		{user: "root", want: http.StatusOK},
+	} {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("user", &token.UserContext{Username: tt.user}) })
+		router.POST("/admin/v1/janitor/run", h.Run)
+		router.GET("/admin/v1/janitor/report", h.LastReport)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/v1/janitor/run", nil))
+		assert.Equal(t, tt.want, w.Code, tt.user)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/v1/janitor/report", nil))
+		assert.Equal(t, tt.want, w.Code, tt.user)
+	}
+}
@@ -0,0 +1,322 @@
+// Package janitor prunes control-plane data that is no longer needed so the API key datastore
+// does not grow without bound, and enforces the retention period of each class of audit and usage
+// records, except those under legal hold.
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/retention"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+var (
+	prunedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_janitor_pruned_total",
+		Help: "Records removed or revoked by the janitor, by kind (inactive_keys, stale_subscription_keys, decisions, usage, admin_actions).",
+	}, []string{"kind"})
+	runsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_janitor_runs_total",
+		Help: "Janitor runs by result (success, error, dry_run).",
+	}, []string{"result"})
+	lastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "maas_janitor_last_success_timestamp_seconds",
+		Help: "Unix time of the last janitor run that completed without error.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(prunedTotal, runsTotal, lastSuccess)
+}
+
+// Store is the part of the API key store the janitor prunes.
+type Store interface {
+	PurgeInactive(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error)
+	ActiveKeysBySubscription(ctx context.Context) (map[string]int64, error)
+	RevokeBySubscription(ctx context.Context, subscription string) (int64, error)
+}
+
+// AuditStore is the part of the audit store the janitor prunes.
+type AuditStore interface {
+	Prune(ctx context.Context, p audit.Prune) (int64, error)
+}
+
+// UsageStore is the part of the usage ledger the janitor prunes.
+type UsageStore interface {
+	Prune(ctx context.Context, p usage.Prune) (int64, error)
+}
+
+// StaleSubscription is a subscription name that active keys are bound to but that no longer exists.
+type StaleSubscription struct {
+	Name       string `json:"name"`
+	ActiveKeys int64  `json:"activeKeys"`
+	// Revoked is set once scheduled runs have found the subscription missing for at least an
+	// interval and its keys were revoked (or, in a dry run, would have been).
+	Revoked bool `json:"revoked"`
+}
+
+// Report describes what one janitor run removed, or would remove in a dry run.
+type Report struct {
+	StartedAt          time.Time           `json:"startedAt"`
+	DryRun             bool                `json:"dryRun"`
+	Retention          string              `json:"retention"`
+	PurgedKeys         int64               `json:"purgedKeys"`
+	RevokedKeys        int64               `json:"revokedKeys"`
+	StaleSubscriptions []StaleSubscription `json:"staleSubscriptions"`
+	// DataRetention and Pruned are the retention period of each data class and the records of the
+	// class removed, when retention is enforced.
+	DataRetention map[retention.DataClass]string `json:"dataRetention,omitempty"`
+	Pruned        map[retention.DataClass]int64  `json:"pruned,omitempty"`
+	LegalHolds    *retention.LegalHolds          `json:"legalHolds,omitempty"`
+}
+
+// Janitor deletes revoked and expired API keys once they are past retention and revokes keys bound
+// to subscriptions that have been deleted. With SetRetention, it also prunes audit and usage records.
+type Janitor struct {
+	logger        *logger.Logger
+	store         Store
+	subscriptions subscription.Lister
+	retention     time.Duration
+	dryRun        bool
+	paused        func() bool
+	now           func() time.Time
+
+	dataRetention retention.Policy
+	holds         retention.LegalHolds
+	audit         AuditStore
+	usage         UsageStore
+
+	mu sync.Mutex
+	// interval is the time between scheduled runs, set by Start.
+	interval time.Duration
+	// missing holds when scheduled runs first found each subscription missing. Keys are only
+	// revoked once a subscription has been missing for an interval, so an informer that has not
+	// caught up cannot cause it. Only scheduled runs that change data update it: manual and dry
+	// runs in quick succession must not shorten the wait.
+	missing map[string]time.Time
+	last    *Report
+}
+
+// New creates a janitor. With dryRun set, scheduled runs only report what they would do.
+func New(log *logger.Logger, store Store, subscriptions subscription.Lister, retention time.Duration, dryRun bool) *Janitor {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Janitor{
+		logger:        log,
+		store:         store,
+		subscriptions: subscriptions,
+		retention:     retention,
+		dryRun:        dryRun,
+		now:           time.Now,
+		missing:       map[string]time.Time{},
+	}
+}
+
+// SetPaused skips scheduled runs while paused returns true, e.g. in read-only mode.
+func (j *Janitor) SetPaused(paused func() bool) {
+	j.paused = paused
+}
+
+// SetRetention prunes the records of each data class past its period from the stores set with
+// SetAuditStore and SetUsageStore, except those under legal hold.
+func (j *Janitor) SetRetention(policy retention.Policy, holds retention.LegalHolds) {
+	j.dataRetention = policy
+	j.holds = holds
+}
+
+// SetAuditStore sets the store the decisions and admin actions are pruned from.
+func (j *Janitor) SetAuditStore(store AuditStore) {
+	j.audit = store
+}
+
+// SetUsageStore sets the ledger the usage is pruned from.
+func (j *Janitor) SetUsageStore(store UsageStore) {
+	j.usage = store
+}
+
+// Start runs the janitor every interval until ctx is done.
+func (j *Janitor) Start(ctx context.Context, interval time.Duration) {
+	j.mu.Lock()
+	j.interval = interval
+	j.mu.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if j.paused != nil && j.paused() {
+					j.logger.Debug("Janitor run skipped while paused")
+					continue
+				}
+				if _, err := j.run(ctx, j.dryRun, true); err != nil {
+					j.logger.Error("Janitor run failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Run performs one pass by hand. A dry run counts what would be removed without changing
+// anything. A manual run only revokes the keys of subscriptions that scheduled runs have found
+// missing for at least an interval, and never starts that wait itself.
+func (j *Janitor) Run(ctx context.Context, dryRun bool) (*Report, error) {
+	return j.run(ctx, dryRun, false)
+}
+
+func (j *Janitor) run(ctx context.Context, dryRun, scheduled bool) (*Report, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	report := &Report{
+		StartedAt:          j.now().UTC(),
+		DryRun:             dryRun,
+		Retention:          j.retention.String(),
+		StaleSubscriptions: []StaleSubscription{},
+	}
+	if err := j.pass(ctx, report, scheduled); err != nil {
+		runsTotal.WithLabelValues("error").Inc()
+		return nil, err
+	}
+
+	if dryRun {
+		runsTotal.WithLabelValues("dry_run").Inc()
+	} else {
+		runsTotal.WithLabelValues("success").Inc()
+		lastSuccess.Set(float64(report.StartedAt.Unix()))
+		prunedTotal.WithLabelValues("inactive_keys").Add(float64(report.PurgedKeys))
+		prunedTotal.WithLabelValues("stale_subscription_keys").Add(float64(report.RevokedKeys))
+		for class, n := range report.Pruned {
+			prunedTotal.WithLabelValues(string(class)).Add(float64(n))
+		}
+	}
+	j.last = report
+	j.logger.Info("Janitor run completed",
+		"dryRun", dryRun, "purgedKeys", report.PurgedKeys, "revokedKeys", report.RevokedKeys,
+		"staleSubscriptions", len(report.StaleSubscriptions), "pruned", report.Pruned)
+	return report, nil
+}
+
+func (j *Janitor) pass(ctx context.Context, report *Report, scheduled bool) error {
+	purged, err := j.store.PurgeInactive(ctx, report.StartedAt.Add(-j.retention), report.DryRun)
+	if err != nil {
+		return fmt.Errorf("failed to purge inactive keys: %w", err)
+	}
+	report.PurgedKeys = purged
+
+	if err := j.revokeStale(ctx, report, scheduled); err != nil {
+		return err
+	}
+	return j.prune(ctx, report)
+}
+
+func (j *Janitor) revokeStale(ctx context.Context, report *Report, scheduled bool) error {
+	if j.subscriptions == nil {
+		return nil
+	}
+	items, err := j.subscriptions.List()
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	existing := make(map[string]bool, len(items))
+	for _, u := range items {
+		existing[u.GetName()] = true
+	}
+	counts, err := j.store.ActiveKeysBySubscription(ctx)
+	if err != nil {
+		return err
+	}
+
+	missing := map[string]time.Time{}
+	for name, count := range counts {
+		if existing[name] {
+			continue
+		}
+		since, seen := j.missing[name]
+		if !seen {
+			since = report.StartedAt
+		}
+		missing[name] = since
+		stale := StaleSubscription{Name: name, ActiveKeys: count, Revoked: seen && report.StartedAt.Sub(since) >= j.interval}
+		if stale.Revoked {
+			if report.DryRun {
+				report.RevokedKeys += count
+			} else {
+				revoked, err := j.store.RevokeBySubscription(ctx, name)
+				if err != nil {
+					return fmt.Errorf("failed to revoke keys for subscription %s: %w", name, err)
+				}
+				report.RevokedKeys += revoked
+			}
+		}
+		report.StaleSubscriptions = append(report.StaleSubscriptions, stale)
+	}
+	sort.Slice(report.StaleSubscriptions, func(a, b int) bool {
+		return report.StaleSubscriptions[a].Name < report.StaleSubscriptions[b].Name
+	})
+	if scheduled && !report.DryRun {
+		j.missing = missing
+	}
+	return nil
+}
+
+// prune deletes the audit and usage records past the retention of their class.
+func (j *Janitor) prune(ctx context.Context, report *Report) error {
+	if j.dataRetention == nil || (j.audit == nil && j.usage == nil) {
+		return nil
+	}
+	report.DataRetention = map[retention.DataClass]string{}
+	report.Pruned = map[retention.DataClass]int64{}
+	if !j.holds.Empty() {
+		report.LegalHolds = &j.holds
+	}
+	for _, class := range retention.Classes {
+		period := j.dataRetention[class]
+		report.DataRetention[class] = period.String()
+		if period.IsZero() {
+			continue
+		}
+		before := period.Cutoff(report.StartedAt)
+		var (
+			n   int64
+			err error
+		)
+		switch class {
+		case retention.ClassUsage:
+			if j.usage == nil {
+				continue
+			}
+			n, err = j.usage.Prune(ctx, usage.Prune{Before: before, Organizations: j.holds.Organizations, Models: j.holds.Models, DryRun: report.DryRun})
+		default:
+			if j.audit == nil {
+				continue
+			}
+			n, err = j.audit.Prune(ctx, audit.Prune{Before: before, AdminActions: class == retention.ClassAdminActions,
+				Organizations: j.holds.Organizations, Models: j.holds.Models, DryRun: report.DryRun})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to prune %s: %w", class, err)
+		}
+		report.Pruned[class] = n
+	}
+	return nil
+}
+
+// LastReport returns the report of the most recent run, or nil before the first one.
+func (j *Janitor) LastReport() *Report {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.last
+}
@@ -0,0 +1,260 @@
+package janitor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/retention"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+type staticLister []string
+
+func (s staticLister) List() ([]*unstructured.Unstructured, error) {
+	out := make([]*unstructured.Unstructured, 0, len(s))
+	for _, name := range s {
+		u := &unstructured.Unstructured{}
+		u.SetName(name)
+		out = append(out, u)
+	}
+	return out, nil
+}
+
+func addKey(t *testing.T, store *api_keys.MockStore, id, subscription string) {
+	t.Helper()
+	require.NoError(t, store.AddKey(context.Background(), "alice", id, "hash-"+id, id, "", nil, subscription, nil, nil, false))
+}
+
+func keyStatus(t *testing.T, store *api_keys.MockStore, id string) api_keys.Status {
+	t.Helper()
+	key, err := store.Get(context.Background(), id)
+	if err != nil {
+		return ""
+	}
+	return key.Status
+}
+
+func TestRunPurgesInactiveKeysPastRetention(t *testing.T) {
+	ctx := context.Background()
+	store := api_keys.NewMockStore()
+	addKey(t, store, "active", "free")
+	addKey(t, store, "revoked", "free")
+	require.NoError(t, store.Revoke(ctx, "revoked"))
+
+	j := New(logger.Development(), store, staticLister{"free"}, 24*time.Hour, false)
+
+	report, err := j.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.PurgedKeys, "revoked key is still within retention")
+
+	j.now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+
+	report, err = j.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.PurgedKeys)
+	assert.Equal(t, api_keys.StatusRevoked, keyStatus(t, store, "revoked"), "dry run leaves the key in place")
+
+	report, err = j.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.PurgedKeys)
+	assert.Empty(t, keyStatus(t, store, "revoked"))
+	assert.Equal(t, api_keys.StatusActive, keyStatus(t, store, "active"))
+	assert.Same(t, report, j.LastReport())
+}
+
+// scheduled makes a scheduled run of j an interval after the previous one.
+func scheduled(t *testing.T, j *Janitor, now *time.Time, dryRun bool) *Report {
+	t.Helper()
+	*now = now.Add(j.interval)
+	report, err := j.run(context.Background(), dryRun, true)
+	require.NoError(t, err)
+	return report
+}
+
+func newScheduledJanitor(store Store, subscriptions staticLister) (*Janitor, *time.Time) {
+	now := time.Now()
+	j := New(logger.Development(), store, subscriptions, 24*time.Hour, false)
+	j.interval = time.Hour
+	j.now = func() time.Time { return now }
+	return j, &now
+}
+
+func TestRunRevokesKeysOfSubscriptionMissingForAnInterval(t *testing.T) {
+	ctx := context.Background()
+	store := api_keys.NewMockStore()
+	addKey(t, store, "kept", "free")
+	addKey(t, store, "orphan-1", "deleted")
+	addKey(t, store, "orphan-2", "deleted")
+
+	j, now := newScheduledJanitor(store, staticLister{"free"})
+
+	report := scheduled(t, j, now, false)
+	require.Len(t, report.StaleSubscriptions, 1)
+	assert.Equal(t, StaleSubscription{Name: "deleted", ActiveKeys: 2}, report.StaleSubscriptions[0])
+	assert.Equal(t, int64(0), report.RevokedKeys, "first sighting only reports")
+
+	report, err := j.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.RevokedKeys, "a manual run right after does not revoke")
+
+	*now = now.Add(j.interval)
+	report, err = j.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), report.RevokedKeys)
+	assert.Equal(t, api_keys.StatusActive, keyStatus(t, store, "orphan-1"), "dry run does not revoke")
+
+	report = scheduled(t, j, now, false)
+	assert.Equal(t, int64(2), report.RevokedKeys)
+	assert.True(t, report.StaleSubscriptions[0].Revoked)
+	assert.Equal(t, api_keys.StatusRevoked, keyStatus(t, store, "orphan-1"))
+	assert.Equal(t, api_keys.StatusRevoked, keyStatus(t, store, "orphan-2"))
+	assert.Equal(t, api_keys.StatusActive, keyStatus(t, store, "kept"))
+}
+
+func TestManualAndDryRunsDoNotStartTheWait(t *testing.T) {
+	ctx := context.Background()
+	store := api_keys.NewMockStore()
+	addKey(t, store, "key", "deleted")
+
+	j, now := newScheduledJanitor(store, staticLister{})
+	for range 2 {
+		_, err := j.Run(ctx, true)
+		require.NoError(t, err)
+		_, err = j.Run(ctx, false)
+		require.NoError(t, err)
+		scheduled(t, j, now, true)
+	}
+	report := scheduled(t, j, now, false)
+	assert.Equal(t, int64(0), report.RevokedKeys, "only scheduled runs that change data count")
+	assert.Equal(t, api_keys.StatusActive, keyStatus(t, store, "key"))
+}
+
+func TestRunForgetsSubscriptionThatReturns(t *testing.T) {
+	store := api_keys.NewMockStore()
+	addKey(t, store, "key", "late")
+
+	j, now := newScheduledJanitor(store, staticLister{})
+	scheduled(t, j, now, false)
+
+	j.subscriptions = staticLister{"late"}
+	report := scheduled(t, j, now, false)
+	assert.Empty(t, report.StaleSubscriptions)
+
+	j.subscriptions = staticLister{}
+	report = scheduled(t, j, now, false)
+	assert.Equal(t, int64(0), report.RevokedKeys, "the missing streak restarts")
+	assert.Equal(t, api_keys.StatusActive, keyStatus(t, store, "key"))
+}
+
+func TestStartSkipsRunsWhilePaused(t *testing.T) {
+	j := New(logger.Development(), api_keys.NewMockStore(), staticLister{"free"}, time.Hour, false)
+	var checks atomic.Int32
+	j.SetPaused(func() bool {
+		checks.Add(1)
+		return true
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j.Start(ctx, 5*time.Millisecond)
+
+	require.Eventually(t, func() bool { return checks.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	assert.Nil(t, j.LastReport(), "no run while paused")
+}
+
+func TestRunPrunesDataClassesExceptLegalHolds(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	auditStore := audit.NewMemoryStore()
+	for _, r := range []audit.Record{
+		{Time: now.AddDate(0, 0, -100), Endpoint: "ext_authz", Subscription: "free", Model: "llm/granite"},
+		{Time: now.AddDate(0, 0, -100), Endpoint: "ext_authz", Subscription: "acme-gold", Organization: "acme", Model: "llm/granite"},
+		{Time: now.AddDate(0, 0, -100), Endpoint: "select", Subscription: "free", Model: "llm/held"},
+		{Time: now.AddDate(0, 0, -10), Endpoint: "ext_authz", Subscription: "free", Model: "llm/granite"},
+		{Time: now.AddDate(-6, 0, 0), Endpoint: audit.EndpointAdmin, Subscription: "free", Path: "tier.update"},
+		{Time: now.AddDate(-8, 0, 0), Endpoint: audit.EndpointAdmin, Subscription: "free", Path: "tier.create"},
+	} {
+		require.NoError(t, auditStore.Write(ctx, r))
+	}
+	usageStore := usage.NewMemoryStore()
+	for _, r := range []usage.Record{
+		{Time: now.AddDate(0, -14, 0), User: "alice", Subscription: "maas/free", Model: "llm/granite", TotalTokens: 10},
+		{Time: now.AddDate(0, -14, 0), User: "alice", Subscription: "maas/acme-gold", Organization: "acme", Model: "llm/granite", TotalTokens: 10},
+		{Time: now.AddDate(0, -12, 0), User: "alice", Subscription: "maas/free", Model: "llm/granite", TotalTokens: 10},
+	} {
+		require.NoError(t, usageStore.Add(ctx, r))
+	}
+
+	j := New(logger.Development(), api_keys.NewMockStore(), nil, 24*time.Hour, false)
+	j.now = func() time.Time { return now }
+	j.SetRetention(retention.DefaultPolicy(), retention.LegalHolds{Organizations: []string{"acme"}, Models: []string{"llm/held"}})
+	j.SetAuditStore(auditStore)
+	j.SetUsageStore(usageStore)
+
+	want := map[retention.DataClass]int64{retention.ClassDecisions: 1, retention.ClassUsage: 1, retention.ClassAdminActions: 1}
+	report, err := j.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, want, report.Pruned)
+	assert.Equal(t, map[retention.DataClass]string{"decisions": "90d", "usage": "13mo", "admin_actions": "7y"}, report.DataRetention)
+	records, _, err := auditStore.Query(ctx, audit.Query{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, records, 6, "a dry run removes nothing")
+
+	report, err = j.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, report.Pruned)
+	records, _, err = auditStore.Query(ctx, audit.Query{Limit: 10})
+	require.NoError(t, err)
+	var kept []string
+	for _, r := range records {
+		kept = append(kept, r.Endpoint+" "+r.Subscription+" "+r.Model+" "+r.Path)
+	}
+	assert.ElementsMatch(t, []string{
+		"ext_authz acme-gold llm/granite ",
+		"select free llm/held ",
+		"ext_authz free llm/granite ",
+		"admin free  tier.update",
+	}, kept)
+	rows, err := usageStore.Query(ctx, usage.Query{From: now.AddDate(-1, -6, 0), To: now, GroupBy: []usage.Dimension{usage.DimensionTier}})
+	require.NoError(t, err)
+	assert.Equal(t, []usage.Row{
+		{Tier: "maas/acme-gold", Requests: 1, TotalTokens: 10},
+		{Tier: "maas/free", Requests: 1, TotalTokens: 10},
+	}, rows)
+}
+
+func TestRunKeepsRecordsOfHeldOrganizationAfterItsSubscriptionsChange(t *testing.T) {
+	ctx := context.Background()
+	auditStore := audit.NewMemoryStore()
+	auditor := audit.New(logger.Development(), auditStore)
+	orgs := map[string]string{"gold": "acme"}
+	auditor.SetOrganizations(func() map[string]string { return orgs })
+	runCtx, stop := context.WithCancel(ctx)
+	auditor.Decision("ext_authz", "llm/granite", "gold", "alice", "/v1/chat/completions", "")
+	stop()
+	auditor.Run(runCtx)
+
+	// The subscription leaves the organization, or is deleted, after the decision was recorded.
+	orgs = map[string]string{}
+	j := New(logger.Development(), api_keys.NewMockStore(), nil, 24*time.Hour, false)
+	j.now = func() time.Time { return time.Now().AddDate(1, 0, 0) }
+	j.SetRetention(retention.DefaultPolicy(), retention.LegalHolds{Organizations: []string{"acme"}})
+	j.SetAuditStore(auditStore)
+
+	report, err := j.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), report.Pruned[retention.ClassDecisions])
+	records, _, err := auditStore.Query(ctx, audit.Query{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "acme", records[0].Organization)
+}
@@ -0,0 +1,70 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awskms "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// awsEncryptionContext is bound to every wrapped DEK, so a DEK wrapped for maas-api cannot be
+// unwrapped through another application's KMS permissions.
+var awsEncryptionContext = map[string]*string{"service": aws.String("maas-api")}
+
+// AWSKMS wraps DEKs with an AWS KMS symmetric key. The key ID in each value is the ARN that
+// wrapped it, so pointing the provider at a new key (or alias) still decrypts older values.
+type AWSKMS struct {
+	client kmsiface.KMSAPI
+	keyID  string
+}
+
+var _ KeyProvider = (*AWSKMS)(nil)
+
+// NewAWSKMS creates a provider for keyID (key ID, ARN or alias). Credentials and region come from
+// the standard AWS environment, e.g. AWS_REGION and an IRSA web identity token.
+func NewAWSKMS(keyID string) (*AWSKMS, error) {
+	sess, err := session.NewSessionWithOptions(session.Options{SharedConfigState: session.SharedConfigEnable})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	return NewAWSKMSWithClient(awskms.New(sess), keyID), nil
+}
+
+// NewAWSKMSWithClient creates a provider with an existing KMS client.
+func NewAWSKMSWithClient(client kmsiface.KMSAPI, keyID string) *AWSKMS {
+	return &AWSKMS{client: client, keyID: keyID}
+}
+
+// Name implements KeyProvider.
+func (a *AWSKMS) Name() string {
+	return "awskms"
+}
+
+// Wrap implements KeyProvider.
+func (a *AWSKMS) Wrap(ctx context.Context, dek []byte) (string, []byte, error) {
+	out, err := a.client.EncryptWithContext(ctx, &awskms.EncryptInput{
+		KeyId:             aws.String(a.keyID),
+		Plaintext:         dek,
+		EncryptionContext: awsEncryptionContext,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("aws kms encrypt: %w", err)
+	}
+	return aws.StringValue(out.KeyId), out.CiphertextBlob, nil
+}
+
+// Unwrap implements KeyProvider.
+func (a *AWSKMS) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	out, err := a.client.DecryptWithContext(ctx, &awskms.DecryptInput{
+		KeyId:             aws.String(keyID),
+		CiphertextBlob:    wrapped,
+		EncryptionContext: awsEncryptionContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("aws kms decrypt: %w", err)
+	}
+	return out.Plaintext, nil
+}
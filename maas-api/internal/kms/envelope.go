@@ -0,0 +1,239 @@
+// Package kms encrypts values that maas-api stores with envelope encryption: each value is sealed
+// with a data encryption key (DEK) and the DEK is wrapped by a key held in an external KMS.
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix marks an encrypted value. Values without it, or legacyPrefix, are treated as legacy
+// plaintext.
+const Prefix = "enc:v2:"
+
+// legacyPrefix marks values sealed without additional data. They are still opened, and Rewrap
+// seals them again under Prefix.
+const legacyPrefix = "enc:v1:"
+
+const (
+	dekSize = 32
+	// dekLifetime bounds how long one DEK seals new values, so a compromised DEK exposes little and
+	// the KMS is not called on every write.
+	dekLifetime = time.Hour
+	// maxCachedDEKs bounds the unwrapped DEKs kept in memory for decryption.
+	maxCachedDEKs = 1024
+)
+
+// ErrMalformed is returned for values that carry the prefix but cannot be parsed.
+var ErrMalformed = errors.New("malformed encrypted value")
+
+// KeyProvider wraps and unwraps DEKs with a key encryption key it never reveals.
+type KeyProvider interface {
+	// Name identifies the provider in logs, e.g. "vault".
+	Name() string
+	// Wrap encrypts dek with the provider's current key and returns that key's ID.
+	Wrap(ctx context.Context, dek []byte) (keyID string, wrapped []byte, err error)
+	// Unwrap decrypts a DEK wrapped by the key with keyID. Providers must keep accepting keys that
+	// have been rotated out until every value has been rewrapped.
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// sealed is the serialized form of an encrypted value.
+type sealed struct {
+	KeyID   string `json:"kid"`
+	Wrapped []byte `json:"dek"`
+	Nonce   []byte `json:"iv"`
+	Data    []byte `json:"ct"`
+}
+
+// Envelope encrypts and decrypts strings with DEKs wrapped by a KeyProvider.
+type Envelope struct {
+	provider KeyProvider
+	now      func() time.Time
+
+	mu             sync.Mutex
+	currentWrapped []byte
+	currentKID     string
+	currentDEK     []byte
+	createdAt      time.Time
+	cache          map[string][]byte // wrapped DEK -> DEK
+}
+
+// NewEnvelope creates an Envelope backed by provider.
+func NewEnvelope(provider KeyProvider) *Envelope {
+	if provider == nil {
+		panic("provider cannot be nil")
+	}
+	return &Envelope{
+		provider: provider,
+		now:      time.Now,
+		cache:    map[string][]byte{},
+	}
+}
+
+// Provider returns the name of the backing KeyProvider.
+func (e *Envelope) Provider() string {
+	return e.provider.Name()
+}
+
+// Encrypt seals plaintext, bound to aad and the ID of the wrapping key as additional data: aad
+// names where the value is stored, e.g. the row's ID, so a value copied elsewhere does not open.
+// The empty string is returned unchanged so optional fields stay empty.
+func (e *Envelope) Encrypt(ctx context.Context, plaintext, aad string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	kid, wrapped, dek, err := e.dek(ctx)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	raw, err := json.Marshal(sealed{
+		KeyID:   kid,
+		Wrapped: wrapped,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, []byte(plaintext), additionalData(kid, aad)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode encrypted value: %w", err)
+	}
+	return Prefix + base64.RawStdEncoding.EncodeToString(raw), nil
+}
+
+// Decrypt opens a value produced by Encrypt with the same aad. Values without Prefix are returned
+// unchanged so data written before encryption was enabled stays readable.
+func (e *Envelope) Decrypt(ctx context.Context, value, aad string) (string, error) {
+	encoded, legacy := strings.CutPrefix(value, legacyPrefix)
+	if !legacy {
+		var ok bool
+		if encoded, ok = strings.CutPrefix(value, Prefix); !ok {
+			return value, nil
+		}
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	var s sealed
+	if err := json.Unmarshal(raw, &s); err != nil || len(s.Wrapped) == 0 {
+		return "", ErrMalformed
+	}
+	dek, err := e.unwrap(ctx, s.KeyID, s.Wrapped)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	if len(s.Nonce) != gcm.NonceSize() {
+		return "", ErrMalformed
+	}
+	var ad []byte
+	if !legacy {
+		ad = additionalData(s.KeyID, aad)
+	}
+	plaintext, err := gcm.Open(nil, s.Nonce, s.Data, ad)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap re-encrypts value under the provider's current key, bound to aad. Plaintext values are
+// encrypted, which backfills data written before encryption was enabled.
+func (e *Envelope) Rewrap(ctx context.Context, value, aad string) (string, error) {
+	plaintext, err := e.Decrypt(ctx, value, aad)
+	if err != nil {
+		return "", err
+	}
+	return e.Encrypt(ctx, plaintext, aad)
+}
+
+// IsEncrypted reports whether value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix) || strings.HasPrefix(value, legacyPrefix)
+}
+
+// additionalData authenticates the wrapping key's ID along with aad, so neither can be swapped.
+func additionalData(kid, aad string) []byte {
+	return []byte(kid + "\x00" + aad)
+}
+
+// dek returns the DEK that seals new values, generating and wrapping a new one when it is too old.
+func (e *Envelope) dek(ctx context.Context) (string, []byte, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.currentDEK != nil && e.now().Sub(e.createdAt) < dekLifetime {
+		return e.currentKID, e.currentWrapped, e.currentDEK, nil
+	}
+
+	dek := make([]byte, dekSize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	kid, wrapped, err := e.provider.Wrap(ctx, dek)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to wrap data key with %s: %w", e.provider.Name(), err)
+	}
+	e.currentKID, e.currentWrapped, e.currentDEK, e.createdAt = kid, wrapped, dek, e.now()
+	e.cacheDEK(wrapped, dek)
+	return kid, wrapped, dek, nil
+}
+
+func (e *Envelope) unwrap(ctx context.Context, kid string, wrapped []byte) ([]byte, error) {
+	e.mu.Lock()
+	dek, ok := e.cache[string(wrapped)]
+	e.mu.Unlock()
+	if ok {
+		return dek, nil
+	}
+
+	dek, err := e.provider.Unwrap(ctx, kid, wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with %s: %w", e.provider.Name(), err)
+	}
+	if len(dek) != dekSize {
+		return nil, fmt.Errorf("unwrapped data key has %d bytes, want %d", len(dek), dekSize)
+	}
+	e.mu.Lock()
+	e.cacheDEK(wrapped, dek)
+	e.mu.Unlock()
+	return dek, nil
+}
+
+// cacheDEK must be called with mu held.
+func (e *Envelope) cacheDEK(wrapped, dek []byte) {
+	if len(e.cache) >= maxCachedDEKs {
+		e.cache = map[string][]byte{}
+	}
+	e.cache[string(wrapped)] = dek
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
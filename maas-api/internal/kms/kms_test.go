@@ -0,0 +1,256 @@
+package kms_test
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awskms "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/kms"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func writeKeyFile(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600))
+	return path
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	provider, err := kms.NewLocalKeyFile(writeKeyFile(t, "# primary", "k1:"+newKey(t)))
+	require.NoError(t, err)
+	e := kms.NewEnvelope(provider)
+
+	encrypted, err := e.Encrypt(ctx, "billing pipeline for team-a", "key-1")
+	require.NoError(t, err)
+	assert.True(t, kms.IsEncrypted(encrypted))
+	assert.NotContains(t, encrypted, "billing")
+
+	decrypted, err := e.Decrypt(ctx, encrypted, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "billing pipeline for team-a", decrypted)
+
+	plaintext, err := e.Decrypt(ctx, "written before encryption", "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "written before encryption", plaintext, "legacy plaintext is returned unchanged")
+
+	empty, err := e.Encrypt(ctx, "", "key-1")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = e.Decrypt(ctx, kms.Prefix+"not-base64!", "key-1")
+	require.ErrorIs(t, err, kms.ErrMalformed)
+}
+
+func TestEnvelopeDetectsTampering(t *testing.T) {
+	ctx := context.Background()
+	provider, err := kms.NewLocalKeyFile(writeKeyFile(t, "k1:"+newKey(t)))
+	require.NoError(t, err)
+	e := kms.NewEnvelope(provider)
+
+	encrypted, err := e.Encrypt(ctx, "secret", "key-1")
+	require.NoError(t, err)
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(encrypted, kms.Prefix))
+	require.NoError(t, err)
+	var sealed map[string]any
+	require.NoError(t, json.Unmarshal(raw, &sealed))
+	sealed["ct"] = base64.StdEncoding.EncodeToString([]byte("tampered-ciphertext"))
+	raw, err = json.Marshal(sealed)
+	require.NoError(t, err)
+
+	_, err = e.Decrypt(ctx, kms.Prefix+base64.RawStdEncoding.EncodeToString(raw), "key-1")
+	require.Error(t, err)
+
+	_, err = e.Decrypt(ctx, encrypted, "key-2")
+	require.Error(t, err, "a value copied to another key's row does not open")
+}
+
+func TestEnvelopeRewrapsValuesSealedWithoutAdditionalData(t *testing.T) {
+	ctx := context.Background()
+	provider, err := kms.NewLocalKeyFile(writeKeyFile(t, "k1:"+newKey(t)))
+	require.NoError(t, err)
+	dek := make([]byte, 32)
+	_, err = rand.Read(dek)
+	require.NoError(t, err)
+	kid, wrapped, err := provider.Wrap(ctx, dek)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(dek)
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	nonce := make([]byte, gcm.NonceSize())
+	raw, err := json.Marshal(map[string]any{"kid": kid, "dek": wrapped, "iv": nonce, "ct": gcm.Seal(nil, nonce, []byte("sealed by v1"), nil)})
+	require.NoError(t, err)
+	legacy := "enc:v1:" + base64.RawStdEncoding.EncodeToString(raw)
+	e := kms.NewEnvelope(provider)
+
+	assert.True(t, kms.IsEncrypted(legacy))
+	decrypted, err := e.Decrypt(ctx, legacy, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "sealed by v1", decrypted)
+
+	rewrapped, err := e.Rewrap(ctx, legacy, "key-1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rewrapped, kms.Prefix))
+	_, err = e.Decrypt(ctx, rewrapped, "key-2")
+	require.Error(t, err, "rewrapped values are bound to their key")
+}
+
+func TestLocalKeyFileRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKey, newKeyValue := newKey(t), newKey(t)
+
+	before, err := kms.NewLocalKeyFile(writeKeyFile(t, "k1:"+oldKey))
+	require.NoError(t, err)
+	encrypted, err := kms.NewEnvelope(before).Encrypt(ctx, "rotate me", "key-1")
+	require.NoError(t, err)
+
+	// k2 is added as the new primary; k1 stays so existing values can be read and rewrapped.
+	after, err := kms.NewLocalKeyFile(writeKeyFile(t, "k2:"+newKeyValue, "k1:"+oldKey))
+	require.NoError(t, err)
+	e := kms.NewEnvelope(after)
+	rewrapped, err := e.Rewrap(ctx, encrypted, "key-1")
+	require.NoError(t, err)
+
+	// Once everything is rewrapped, k1 can be removed.
+	onlyNew, err := kms.NewLocalKeyFile(writeKeyFile(t, "k2:"+newKeyValue))
+	require.NoError(t, err)
+	decrypted, err := kms.NewEnvelope(onlyNew).Decrypt(ctx, rewrapped, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "rotate me", decrypted)
+
+	_, err = kms.NewEnvelope(onlyNew).Decrypt(ctx, encrypted, "key-1")
+	require.Error(t, err, "values still wrapped by a removed key cannot be read")
+}
+
+func TestLocalKeyFileInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+	}{
+		{name: "empty", lines: []string{"# no keys"}},
+		{name: "missing id", lines: []string{":" + newKey(t)}},
+		{name: "short key", lines: []string{"k1:" + base64.StdEncoding.EncodeToString([]byte("short"))}},
+		{name: "duplicate id", lines: []string{"k1:" + newKey(t), "k1:" + newKey(t)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := kms.NewLocalKeyFile(writeKeyFile(t, tt.lines...))
+			require.Error(t, err)
+		})
+	}
+}
+
+// fakeTransit is a Vault transit engine that "encrypts" by base64-encoding with a version prefix.
+func fakeTransit(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		data := map[string]string{}
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/maas":
+			data["ciphertext"] = "vault:v1:" + body["plaintext"]
+		case "/v1/transit/decrypt/maas":
+			data["plaintext"] = strings.TrimPrefix(body["ciphertext"], "vault:v1:")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+}
+
+func TestVaultTransit(t *testing.T) {
+	ctx := context.Background()
+	server := fakeTransit(t, "s.token")
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("s.token\n"), 0o600))
+	e := kms.NewEnvelope(kms.NewVaultTransit(server.URL, "", "maas", tokenFile))
+
+	encrypted, err := e.Encrypt(ctx, "via vault", "key-1")
+	require.NoError(t, err)
+	decrypted, err := kms.NewEnvelope(kms.NewVaultTransit(server.URL, "transit", "maas", tokenFile)).Decrypt(ctx, encrypted, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "via vault", decrypted)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("wrong"), 0o600))
+	_, err = kms.NewEnvelope(kms.NewVaultTransit(server.URL, "transit", "maas", tokenFile)).Encrypt(ctx, "denied", "key-1")
+	require.ErrorContains(t, err, "status 403")
+}
+
+// fakeAWSKMS reverses the plaintext and checks the encryption context.
+type fakeAWSKMS struct {
+	kmsiface.KMSAPI
+	t *testing.T
+}
+
+func (f fakeAWSKMS) EncryptWithContext(_ aws.Context, in *awskms.EncryptInput, _ ...request.Option) (*awskms.EncryptOutput, error) {
+	assert.Equal(f.t, "maas-api", aws.StringValue(in.EncryptionContext["service"]))
+	return &awskms.EncryptOutput{KeyId: aws.String("arn:aws:kms:us-east-1:1:key/" + aws.StringValue(in.KeyId)), CiphertextBlob: reverse(in.Plaintext)}, nil
+}
+
+func (f fakeAWSKMS) DecryptWithContext(_ aws.Context, in *awskms.DecryptInput, _ ...request.Option) (*awskms.DecryptOutput, error) {
+	assert.Equal(f.t, "arn:aws:kms:us-east-1:1:key/old", aws.StringValue(in.KeyId))
+	return &awskms.DecryptOutput{Plaintext: reverse(in.CiphertextBlob)}, nil
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
+func TestAWSKMSDecryptsWithWrappingKey(t *testing.T) {
+	ctx := context.Background()
+	encrypted, err := kms.NewEnvelope(kms.NewAWSKMSWithClient(fakeAWSKMS{t: t}, "old")).Encrypt(ctx, "via aws", "key-1")
+	require.NoError(t, err)
+
+	// The provider now points at a new key; the value names the key that wrapped it.
+	decrypted, err := kms.NewEnvelope(kms.NewAWSKMSWithClient(fakeAWSKMS{t: t}, "new")).Decrypt(ctx, encrypted, "key-1")
+	require.NoError(t, err)
+	assert.Equal(t, "via aws", decrypted)
+}
+
+func TestOptionsValidate(t *testing.T) {
+	require.NoError(t, kms.Options{}.Validate())
+	require.NoError(t, kms.Options{Provider: kms.ProviderAWS, KeyID: "alias/maas"}.Validate())
+	require.Error(t, kms.Options{Provider: kms.ProviderLocal}.Validate())
+	require.Error(t, kms.Options{Provider: kms.ProviderAWS}.Validate())
+
+	provider, err := kms.NewProvider(kms.Options{})
+	require.NoError(t, err)
+	assert.Nil(t, provider)
+}
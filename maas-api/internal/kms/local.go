@@ -0,0 +1,100 @@
+package kms
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LocalKeyFile wraps DEKs with AES-256 keys read from a file, typically a mounted Secret.
+// Each line is "<id>:<base64 32-byte key>". The first key wraps new DEKs; the others are only used
+// to unwrap, so a key is rotated by adding a new first line and rewrapping.
+type LocalKeyFile struct {
+	primary string
+	keys    map[string][]byte
+}
+
+var _ KeyProvider = (*LocalKeyFile)(nil)
+
+// NewLocalKeyFile loads the keys in path.
+func NewLocalKeyFile(path string) (*LocalKeyFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key file: %w", err)
+	}
+	defer f.Close()
+
+	p := &LocalKeyFile{keys: map[string][]byte{}}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(text, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key file line %d: expected <id>:<base64 key>", line)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != dekSize {
+			return nil, fmt.Errorf("key file line %d: key %q must be %d base64-encoded bytes", line, id, dekSize)
+		}
+		if _, dup := p.keys[id]; dup {
+			return nil, fmt.Errorf("key file line %d: duplicate key id %q", line, id)
+		}
+		if p.primary == "" {
+			p.primary = id
+		}
+		p.keys[id] = key
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if p.primary == "" {
+		return nil, fmt.Errorf("key file %s has no keys", path)
+	}
+	return p, nil
+}
+
+// Name implements KeyProvider.
+func (p *LocalKeyFile) Name() string {
+	return "local"
+}
+
+// Wrap implements KeyProvider.
+func (p *LocalKeyFile) Wrap(_ context.Context, dek []byte) (string, []byte, error) {
+	gcm, err := newGCM(p.keys[p.primary])
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return p.primary, gcm.Seal(nonce, nonce, dek, []byte(p.primary)), nil
+}
+
+// Unwrap implements KeyProvider.
+func (p *LocalKeyFile) Unwrap(_ context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %q is not in the key file", keyID)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := wrapped[:gcm.NonceSize()], wrapped[gcm.NonceSize():]
+	dek, err := gcm.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap with key %q: %w", keyID, err)
+	}
+	return dek, nil
+}
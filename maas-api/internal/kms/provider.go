@@ -0,0 +1,68 @@
+package kms
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Supported values for Options.Provider.
+const (
+	ProviderNone  = ""
+	ProviderLocal = "local"
+	ProviderVault = "vault"
+	ProviderAWS   = "awskms"
+)
+
+// Options selects and configures a KeyProvider.
+type Options struct {
+	Provider string
+	// KeyFile is the key file of the local provider.
+	KeyFile string
+	// KeyID is the transit key name (vault) or the key ID, ARN or alias (awskms).
+	KeyID string
+	// VaultAddress, VaultMount and VaultTokenFile configure the vault provider.
+	VaultAddress   string
+	VaultMount     string
+	VaultTokenFile string
+}
+
+// Validate checks that the options needed by the selected provider are set.
+func (o Options) Validate() error {
+	switch o.Provider {
+	case ProviderNone:
+		return nil
+	case ProviderLocal:
+		if o.KeyFile == "" {
+			return errors.New("KMS provider local requires a key file")
+		}
+	case ProviderVault:
+		if o.VaultAddress == "" || o.KeyID == "" {
+			return errors.New("KMS provider vault requires a Vault address and a transit key name")
+		}
+	case ProviderAWS:
+		if o.KeyID == "" {
+			return errors.New("KMS provider awskms requires a key ID")
+		}
+	default:
+		return fmt.Errorf("unknown KMS provider %q (expected %s, %s or %s)", o.Provider, ProviderLocal, ProviderVault, ProviderAWS)
+	}
+	return nil
+}
+
+// NewProvider creates the provider selected by opts. It returns nil when encryption is disabled.
+//
+//nolint:ireturn // Returns the KeyProvider interface by design.
+func NewProvider(opts Options) (KeyProvider, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	switch opts.Provider {
+	case ProviderLocal:
+		return NewLocalKeyFile(opts.KeyFile)
+	case ProviderVault:
+		return NewVaultTransit(opts.VaultAddress, opts.VaultMount, opts.KeyID, opts.VaultTokenFile), nil
+	case ProviderAWS:
+		return NewAWSKMS(opts.KeyID)
+	}
+	return nil, nil
+}
@@ -0,0 +1,128 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultTransit wraps DEKs with a key in Vault's transit secrets engine. Vault keeps every version
+// of the key, so rotating it there (vault write -f transit/keys/<name>/rotate) is enough; rewrapping
+// then moves stored values to the latest version.
+type VaultTransit struct {
+	address   string
+	mount     string
+	key       string
+	tokenFile string
+	client    *http.Client
+}
+
+var _ KeyProvider = (*VaultTransit)(nil)
+
+// NewVaultTransit creates a provider for the transit key name under mount (usually "transit").
+// The token is read from tokenFile on every call so a renewed token is picked up; if tokenFile is
+// empty, VAULT_TOKEN is used.
+func NewVaultTransit(address, mount, key, tokenFile string) *VaultTransit {
+	if mount == "" {
+		mount = "transit"
+	}
+	return &VaultTransit{
+		address:   strings.TrimSuffix(address, "/"),
+		mount:     strings.Trim(mount, "/"),
+		key:       key,
+		tokenFile: tokenFile,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// Name implements KeyProvider.
+func (v *VaultTransit) Name() string {
+	return "vault"
+}
+
+// Wrap implements KeyProvider. The wrapped DEK is Vault's "vault:v<N>:..." ciphertext.
+func (v *VaultTransit) Wrap(ctx context.Context, dek []byte) (string, []byte, error) {
+	var out struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	if err := v.call(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dek)}, &out); err != nil {
+		return "", nil, err
+	}
+	return v.key, []byte(out.Ciphertext), nil
+}
+
+// Unwrap implements KeyProvider.
+func (v *VaultTransit) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	if keyID != v.key {
+		return nil, fmt.Errorf("value was wrapped with transit key %q, but %q is configured", keyID, v.key)
+	}
+	var out struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := v.call(ctx, "decrypt", map[string]string{"ciphertext": string(wrapped)}, &out); err != nil {
+		return nil, err
+	}
+	dek, err := base64.StdEncoding.DecodeString(out.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode vault plaintext: %w", err)
+	}
+	return dek, nil
+}
+
+func (v *VaultTransit) call(ctx context.Context, op string, body map[string]string, out any) error {
+	token, err := v.token()
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode vault request: %w", err)
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/%s/%s", v.address, v.mount, op, url.PathEscape(v.key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call vault transit %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault transit %s returned status %d", op, resp.StatusCode)
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return nil
+}
+
+func (v *VaultTransit) token() (string, error) {
+	if v.tokenFile == "" {
+		if token := os.Getenv("VAULT_TOKEN"); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("no vault token: set VAULT_TOKEN or a token file")
+	}
+	raw, err := os.ReadFile(v.tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault token: %w", err)
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
@@ -0,0 +1,189 @@
+// Package metering exports usage data as Prometheus metrics on /metrics: authorization decisions
+// per model and subscription, and prompt and completion tokens when the gateway reports them in
+// response headers. It is the raw material for chargeback and capacity planning.
+//
+// High-volume models can be metered by sampling: with a sample rate of 0.1, one request in ten
+// is recorded and counted ten times. maas_metering_sample_rate reports the rate in effect per
+// model, so consumers can tell extrapolated counters from exact ones.
+package metering
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// Token count headers. A gateway filter or the model server sets them on responses; for streamed
+// responses they may arrive as trailers.
+const (
+	HeaderPromptTokens     = "X-MaaS-Prompt-Tokens"
+	HeaderCompletionTokens = "X-MaaS-Completion-Tokens"
+)
+
+// Decision values of maas_authorization_decisions_total.
+const (
+	DecisionAllowed = "allowed"
+	DecisionDenied  = "denied"
+)
+
+var (
+	decisionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_authorization_decisions_total",
+		Help: "Authorization decisions by endpoint (select, ext_authz), decision (allowed, denied), denial reason, model, subscription and user.",
+	}, []string{"endpoint", "decision", "reason", "model", "subscription", "user"})
+	decisionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maas_authorization_duration_seconds",
+		Help:    "Time taken to reach an authorization decision, by endpoint and decision.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"endpoint", "decision"})
+	tokensTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_tokens_total",
+		Help: "Tokens consumed by model, subscription, user and type (prompt, completion).",
+	}, []string{"model", "subscription", "user", "type"})
+	requestTokens = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "maas_request_tokens",
+		Help:    "Tokens per request by model, subscription and type (prompt, completion).",
+		Buckets: prometheus.ExponentialBuckets(16, 4, 8),
+	}, []string{"model", "subscription", "type"})
+	sampleRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "maas_metering_sample_rate",
+		Help: "Fraction of requests metered per model; counters of models below 1 are extrapolated from a sample.",
+	}, []string{"model"})
+)
+
+func init() {
+	prometheus.MustRegister(decisionsTotal, decisionDuration, tokensTotal, requestTokens, sampleRate)
+}
+
+// SampleRateResolver returns the fraction of a model's ("namespace/name") requests to meter,
+// in (0, 1]. Other values meter every request.
+type SampleRateResolver func(model string) float64
+
+// Meter records usage. A nil Meter records nothing.
+type Meter struct {
+	perUser     bool
+	reasonLabel reason.Label
+
+	sampleRate SampleRateResolver
+	// seen counts requests per model and kind for systematic sampling.
+	seen sync.Map
+}
+
+// New creates a Meter. The user label is only filled in when perUser is set, as it makes the
+// number of series grow with the number of users.
+func New(perUser bool) *Meter {
+	return &Meter{perUser: perUser}
+}
+
+// SetReasonLabel reports denial reasons as label does: by code (the default) or by category.
+func (m *Meter) SetReasonLabel(label reason.Label) {
+	m.reasonLabel = label
+}
+
+// SetSampleRate meters each model at the rate resolve returns for it instead of every request.
+func (m *Meter) SetSampleRate(resolve SampleRateResolver) {
+	m.sampleRate = resolve
+}
+
+// sample reports whether to record this request for model and, if so, how many requests it
+// stands for. Every Nth request is recorded, N being the inverse of the model's rate, so
+// decisions and token counts are each sampled evenly. kind separates the two streams.
+func (m *Meter) sample(model, kind string) (float64, bool) {
+	rate := 1.0
+	if m.sampleRate != nil {
+		if r := m.sampleRate(model); r > 0 && r < 1 {
+			rate = r
+		}
+	}
+	sampleRate.WithLabelValues(model).Set(rate)
+	if rate == 1 {
+		return 1, true
+	}
+	every := uint64(math.Round(1 / rate))
+	counter, _ := m.seen.LoadOrStore(kind+"\x00"+model, new(atomic.Uint64))
+	if (counter.(*atomic.Uint64).Add(1)-1)%every != 0 {
+		return 0, false
+	}
+	return float64(every), true
+}
+
+func (m *Meter) user(user string) string {
+	if !m.perUser {
+		return ""
+	}
+	return user
+}
+
+// Decision records an authorization decision. An empty code means the request was allowed. Codes
+// outside the reason taxonomy are recorded as "unknown".
+func (m *Meter) Decision(endpoint, model, subscription, user, code string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	decision := DecisionAllowed
+	if code != "" {
+		decision = DecisionDenied
+	}
+	weight, ok := m.sample(model, "decision")
+	if !ok {
+		return
+	}
+	decisionsTotal.WithLabelValues(endpoint, decision, m.reasonLabel.Value(code), model, subscription, m.user(user)).Add(weight)
+	decisionDuration.WithLabelValues(endpoint, decision).Observe(elapsed.Seconds())
+}
+
+// Tokens records the prompt and completion tokens of one request.
+func (m *Meter) Tokens(model, subscription, user string, prompt, completion int64) {
+	if m == nil {
+		return
+	}
+	weight, ok := m.sample(model, "tokens")
+	if !ok {
+		return
+	}
+	for _, t := range []struct {
+		kind  string
+		count int64
+	}{{"prompt", prompt}, {"completion", completion}} {
+		if t.count <= 0 {
+			continue
+		}
+		tokensTotal.WithLabelValues(model, subscription, m.user(user), t.kind).Add(float64(t.count) * weight)
+		requestTokens.WithLabelValues(model, subscription, t.kind).Observe(float64(t.count))
+	}
+}
+
+// TokensFromHeaders records the token counts in a response's headers or trailers. It reports
+// whether any were recorded.
+func (m *Meter) TokensFromHeaders(model, subscription, user string, headers ...http.Header) bool {
+	if m == nil {
+		return false
+	}
+	prompt, okPrompt := headerCount(HeaderPromptTokens, headers)
+	completion, okCompletion := headerCount(HeaderCompletionTokens, headers)
+	if !okPrompt && !okCompletion {
+		return false
+	}
+	m.Tokens(model, subscription, user, prompt, completion)
+	return true
+}
+
+func headerCount(name string, headers []http.Header) (int64, bool) {
+	for _, h := range headers {
+		if v := strings.TrimSpace(h.Get(name)); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err == nil && n >= 0 {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
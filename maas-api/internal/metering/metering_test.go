@@ -0,0 +1,147 @@
+package metering_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+func counterValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range f.GetMetric() {
+			got := map[string]string{}
+			for _, l := range m.GetLabel() {
+				got[l.GetName()] = l.GetValue()
+			}
+			for k, v := range labels {
+				if got[k] != v {
+					continue metrics
+				}
+			}
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestDecision(t *testing.T) {
+	metering.New(false).Decision("select", "llm/granite", "premium", "alice", "", time.Millisecond)
+	metering.New(true).Decision("select", "llm/granite", "", "bob", "not_found", time.Millisecond)
+
+	assert.InDelta(t, 1, counterValue(t, "maas_authorization_decisions_total", map[string]string{
+		"endpoint": "select", "decision": "allowed", "reason": "", "model": "llm/granite", "subscription": "premium", "user": "",
+	}), 0, "user label is empty unless per-user metering is on")
+	assert.InDelta(t, 1, counterValue(t, "maas_authorization_decisions_total", map[string]string{
+		"endpoint": "select", "decision": "denied", "reason": "not_found", "model": "llm/granite", "user": "bob",
+	}), 0)
+}
+
+func TestDecisionReasonLabel(t *testing.T) {
+	byCategory := metering.New(false)
+	byCategory.SetReasonLabel(reason.LabelCategory)
+	byCategory.Decision("ext_authz", "llm/reasons", "", "", reason.StaleSignature, time.Millisecond)
+	metering.New(false).Decision("ext_authz", "llm/reasons", "", "", "made_up", time.Millisecond)
+
+	assert.InDelta(t, 1, counterValue(t, "maas_authorization_decisions_total", map[string]string{
+		"endpoint": "ext_authz", "model": "llm/reasons", "reason": "authentication",
+	}), 0)
+	assert.InDelta(t, 1, counterValue(t, "maas_authorization_decisions_total", map[string]string{
+		"endpoint": "ext_authz", "model": "llm/reasons", "reason": "unknown",
+	}), 0, "codes outside the taxonomy must not create series")
+}
+
+func TestTokensFromHeaders(t *testing.T) {
+	m := metering.New(true)
+	headers := http.Header{}
+	headers.Set(metering.HeaderPromptTokens, "120")
+	trailers := http.Header{}
+	trailers.Set(metering.HeaderCompletionTokens, "30")
+
+	assert.True(t, m.TokensFromHeaders("llm/mistral", "free", "carol", headers, trailers))
+	assert.False(t, m.TokensFromHeaders("llm/mistral", "free", "carol", http.Header{}), "no counts reported")
+
+	assert.InDelta(t, 120, counterValue(t, "maas_tokens_total", map[string]string{
+		"model": "llm/mistral", "subscription": "free", "user": "carol", "type": "prompt",
+	}), 0)
+	assert.InDelta(t, 30, counterValue(t, "maas_tokens_total", map[string]string{
+		"model": "llm/mistral", "subscription": "free", "user": "carol", "type": "completion",
+	}), 0)
+}
+
+func TestNilMeterRecordsNothing(t *testing.T) {
+	var m *metering.Meter
+	m.Decision("ext_authz", "llm/x", "", "", "unauthorized", time.Millisecond)
+	m.Tokens("llm/x", "", "", 1, 1)
+	headers := http.Header{}
+	headers.Set(metering.HeaderPromptTokens, "5")
+	assert.False(t, m.TokensFromHeaders("llm/x", "", "", headers))
+	assert.InDelta(t, 0, counterValue(t, "maas_tokens_total", map[string]string{"model": "llm/x"}), 0)
+}
+
+func gaugeValue(t *testing.T, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if v, ok := labels[l.GetName()]; ok && v != l.GetValue() {
+					continue metrics
+				}
+			}
+			return m.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestSampling(t *testing.T) {
+	m := metering.New(false)
+	m.SetSampleRate(func(model string) float64 {
+		if model == "llm/chatty" {
+			return 0.1
+		}
+		return 1
+	})
+
+	for range 25 {
+		m.Decision("select", "llm/chatty", "free", "", "", time.Millisecond)
+		m.Tokens("llm/chatty", "free", "", 100, 0)
+		m.Decision("select", "llm/billing", "free", "", "", time.Millisecond)
+	}
+
+	// Requests 1, 11 and 21 are recorded, each standing for ten.
+	assert.InDelta(t, 30, counterValue(t, "maas_authorization_decisions_total", map[string]string{
+		"endpoint": "select", "decision": "allowed", "model": "llm/chatty", "subscription": "free",
+	}), 0)
+	assert.InDelta(t, 3000, counterValue(t, "maas_tokens_total", map[string]string{
+		"model": "llm/chatty", "subscription": "free", "type": "prompt",
+	}), 0)
+	assert.InDelta(t, 0.1, gaugeValue(t, "maas_metering_sample_rate", map[string]string{"model": "llm/chatty"}), 1e-9)
+
+	assert.InDelta(t, 25, counterValue(t, "maas_authorization_decisions_total", map[string]string{
+		"endpoint": "select", "decision": "allowed", "model": "llm/billing", "subscription": "free",
+	}), 0, "models without sampling meter every request")
+	assert.InDelta(t, 1, gaugeValue(t, "maas_metering_sample_rate", map[string]string{"model": "llm/billing"}), 0)
+}
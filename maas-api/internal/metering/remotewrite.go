@@ -0,0 +1,242 @@
+package metering
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// RemoteWriteMetric is the series pushed by RemoteWriter: maas_tokens_total summed over users,
+// labelled with model, subscription, organization, type and instance.
+const RemoteWriteMetric = "maas_usage_tokens_total"
+
+// Organizations returns the organization ID of each subscription, by subscription name.
+type Organizations func() map[string]string
+
+// RemoteWriter pushes aggregated token usage to a Prometheus remote-write endpoint (Mimir,
+// Thanos receive, Cortex, or Prometheus with the receiver enabled) on a fixed interval, so usage
+// reaches a central store without scraping each replica. Series are cumulative counters; each
+// replica pushes its own, told apart by the instance label, so sum them in queries.
+type RemoteWriter struct {
+	url      string
+	instance string
+	interval time.Duration
+	token    string
+	tenant   string
+	orgs     Organizations
+	now      func() time.Time
+	gatherer prometheus.Gatherer
+	client   *http.Client
+	logger   *logger.Logger
+}
+
+// NewRemoteWriter creates a writer pushing to url every interval. instance identifies this
+// replica, usually the pod name.
+func NewRemoteWriter(log *logger.Logger, url, instance string, interval time.Duration) *RemoteWriter {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &RemoteWriter{
+		url:      url,
+		instance: instance,
+		interval: interval,
+		now:      time.Now,
+		gatherer: prometheus.DefaultGatherer,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		logger:   log,
+	}
+}
+
+// SetBearerToken authenticates pushes with a bearer token.
+func (w *RemoteWriter) SetBearerToken(token string) {
+	w.token = token
+}
+
+// SetTenant sets the X-Scope-OrgID header Mimir and Cortex use to pick the tenant.
+func (w *RemoteWriter) SetTenant(tenant string) {
+	w.tenant = tenant
+}
+
+// SetOrganizations fills the organization label from each subscription's tokenMetadata.
+func (w *RemoteWriter) SetOrganizations(orgs Organizations) {
+	w.orgs = orgs
+}
+
+// SetClock timestamps samples with now instead of the local clock, so a replica with a drifting
+// clock does not push samples the store rejects as out of order or too far in the future.
+func (w *RemoteWriter) SetClock(now func() time.Time) {
+	w.now = now
+}
+
+// Run pushes usage every interval until ctx is done, then pushes once more so the last counts
+// are not lost on shutdown.
+func (w *RemoteWriter) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.Push(ctx); err != nil {
+				w.logger.Warn("Failed to push usage via remote write", "error", err)
+			}
+		case <-ctx.Done():
+			flush, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			if err := w.Push(flush); err != nil {
+				w.logger.Warn("Failed to push final usage via remote write", "error", err)
+			}
+			cancel()
+			return
+		}
+	}
+}
+
+// Push sends the current usage counters once. Nothing is sent before the first tokens are recorded.
+func (w *RemoteWriter) Push(ctx context.Context) error {
+	series, err := w.collect()
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		return nil
+	}
+	body := s2.EncodeSnappy(nil, encodeWriteRequest(series, w.now().UnixMilli()))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "maas-api")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.token)
+	}
+	if w.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", w.tenant)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote write returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// remoteSeries is one pushed series; labels are sorted by name, as remote write requires.
+type remoteSeries struct {
+	labels [][2]string
+	value  float64
+}
+
+// collect sums maas_tokens_total over users into one series per model, subscription and type.
+func (w *RemoteWriter) collect() ([]remoteSeries, error) {
+	families, err := w.gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather usage metrics: %w", err)
+	}
+	var orgs map[string]string
+	if w.orgs != nil {
+		orgs = w.orgs()
+	}
+
+	type key struct{ model, subscription, kind string }
+	sums := map[key]float64{}
+	for _, f := range families {
+		if f.GetName() != "maas_tokens_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			var k key
+			for _, l := range m.GetLabel() {
+				switch l.GetName() {
+				case "model":
+					k.model = l.GetValue()
+				case "subscription":
+					k.subscription = l.GetValue()
+				case "type":
+					k.kind = l.GetValue()
+				}
+			}
+			sums[k] += m.GetCounter().GetValue()
+		}
+	}
+
+	series := make([]remoteSeries, 0, len(sums))
+	for k, v := range sums {
+		series = append(series, remoteSeries{
+			labels: [][2]string{
+				{"__name__", RemoteWriteMetric},
+				{"instance", w.instance},
+				{"model", k.model},
+				{"organization", orgs[k.subscription]},
+				{"subscription", k.subscription},
+				{"type", k.kind},
+			},
+			value: v,
+		})
+	}
+	slices.SortFunc(series, func(a, b remoteSeries) int {
+		for i := range a.labels {
+			if c := strings.Compare(a.labels[i][1], b.labels[i][1]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return series, nil
+}
+
+// encodeWriteRequest encodes a prometheus.WriteRequest protobuf with one sample per series:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; }
+//
+// Labels with empty values are left out, as Prometheus treats them as absent.
+func encodeWriteRequest(series []remoteSeries, timestampMillis int64) []byte {
+	var out []byte
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			if l[1] == "" {
+				continue
+			}
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l[0])
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l[1])
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		var sample []byte
+		sample = protowire.AppendTag(sample, 1, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.value))
+		sample = protowire.AppendTag(sample, 2, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(timestampMillis))
+		ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+		ts = protowire.AppendBytes(ts, sample)
+
+		out = protowire.AppendTag(out, 1, protowire.BytesType)
+		out = protowire.AppendBytes(out, ts)
+	}
+	return out
+}
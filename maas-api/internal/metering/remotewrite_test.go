@@ -0,0 +1,120 @@
+package metering_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+)
+
+type pushedSeries struct {
+	labels map[string]string
+	value  float64
+}
+
+// fields splits a protobuf message into its length-delimited and fixed64 fields by number.
+func fields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	out := map[protowire.Number][][]byte{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		var v []byte
+		switch typ {
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		case protowire.Fixed64Type:
+			var x uint64
+			x, n = protowire.ConsumeFixed64(b)
+			v = protowire.AppendFixed64(nil, x)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		out[num] = append(out[num], v)
+	}
+	return out
+}
+
+func decodeWriteRequest(t *testing.T, compressed []byte) []pushedSeries {
+	t.Helper()
+	raw, err := s2.Decode(nil, compressed)
+	require.NoError(t, err)
+	var series []pushedSeries
+	for _, ts := range fields(t, raw)[1] {
+		tsFields := fields(t, ts)
+		s := pushedSeries{labels: map[string]string{}}
+		for _, l := range tsFields[1] {
+			lf := fields(t, l)
+			s.labels[string(lf[1][0])] = string(lf[2][0])
+		}
+		require.Len(t, tsFields[2], 1)
+		bits, _ := protowire.ConsumeFixed64(fields(t, tsFields[2][0])[1][0])
+		s.value = math.Float64frombits(bits)
+		series = append(series, s)
+	}
+	return series
+}
+
+func TestRemoteWriterPushesAggregatedUsage(t *testing.T) {
+	m := metering.New(true)
+	m.Tokens("rw/granite", "premium", "alice", 100, 20)
+	m.Tokens("rw/granite", "premium", "bob", 50, 5)
+
+	var got []pushedSeries
+	var headers http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		got = decodeWriteRequest(t, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	writer := metering.NewRemoteWriter(logger.Development(), srv.URL, "maas-api-0", 0)
+	writer.SetBearerToken("secret")
+	writer.SetTenant("billing")
+	writer.SetOrganizations(func() map[string]string { return map[string]string{"premium": "acme"} })
+	require.NoError(t, writer.Push(context.Background()))
+
+	assert.Equal(t, "snappy", headers.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+	assert.Equal(t, "Bearer secret", headers.Get("Authorization"))
+	assert.Equal(t, "billing", headers.Get("X-Scope-OrgID"))
+
+	values := map[string]float64{}
+	for _, s := range got {
+		if s.labels["model"] != "rw/granite" {
+			continue
+		}
+		assert.Equal(t, metering.RemoteWriteMetric, s.labels["__name__"])
+		assert.Equal(t, "maas-api-0", s.labels["instance"])
+		assert.Equal(t, "acme", s.labels["organization"])
+		assert.NotContains(t, s.labels, "user", "usage is summed over users")
+		values[s.labels["type"]] = s.value
+	}
+	assert.Equal(t, map[string]float64{"prompt": 150, "completion": 25}, values)
+}
+
+func TestRemoteWriterReportsRejectedPushes(t *testing.T) {
+	metering.New(false).Tokens("rw/llama", "basic", "", 10, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := metering.NewRemoteWriter(nil, srv.URL, "maas-api-0", 0).Push(context.Background())
+	assert.ErrorContains(t, err, "out of order sample")
+}
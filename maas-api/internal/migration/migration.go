@@ -0,0 +1,112 @@
+// Package migration upgrades the data maas-api stores (database schema, key formats) between
+// versions. Every step is checked before any is applied, so an upgrade that cannot complete
+// fails before it changes anything.
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// Step is one upgrade of stored data. Apply must be idempotent: it runs again on the next
+// upgrade, or on restart when maas-api applies migrations at startup.
+type Step interface {
+	// Name identifies the step in plans and logs, e.g. "postgres-schema".
+	Name() string
+	// Plan is the pre-flight check. It reports what Apply would change, and returns an error
+	// when Apply cannot run safely (e.g. a dirty or newer schema).
+	Plan(ctx context.Context) (Plan, error)
+	// Apply performs the upgrade.
+	Apply(ctx context.Context) error
+}
+
+// Plan describes what a step would change.
+type Plan struct {
+	Step string `json:"step"`
+	// Current and Target are the step's version before and after Apply, when it has one.
+	Current string `json:"current,omitempty"`
+	Target  string `json:"target,omitempty"`
+	// Pending describes each change Apply makes, in order. Empty when the step is up to date.
+	Pending []string `json:"pending,omitempty"`
+	// Rollback tells an operator how to undo the pending changes.
+	Rollback []string `json:"rollback,omitempty"`
+}
+
+// UpToDate reports whether the step has nothing to apply.
+func (p Plan) UpToDate() bool {
+	return len(p.Pending) == 0
+}
+
+// ErrPending is returned by Verify when migrations have not been applied.
+var ErrPending = errors.New("migrations pending")
+
+// Runner checks and applies steps in order.
+type Runner struct {
+	logger *logger.Logger
+	steps  []Step
+}
+
+// NewRunner returns a Runner for the given steps. Steps run in the order given.
+func NewRunner(log *logger.Logger, steps ...Step) *Runner {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Runner{logger: log, steps: steps}
+}
+
+// Plan runs the pre-flight check of every step. It returns the plans gathered so far and the
+// first failure.
+func (r *Runner) Plan(ctx context.Context) ([]Plan, error) {
+	plans := make([]Plan, 0, len(r.steps))
+	for _, step := range r.steps {
+		plan, err := step.Plan(ctx)
+		if err != nil {
+			return plans, fmt.Errorf("pre-flight check of %s failed: %w", step.Name(), err)
+		}
+		plan.Step = step.Name()
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// Run checks every step and, if all pass, applies those with pending changes. It returns the
+// plans that were applied or, when dryRun is set, would be.
+func (r *Runner) Run(ctx context.Context, dryRun bool) ([]Plan, error) {
+	plans, err := r.Plan(ctx)
+	if err != nil {
+		return plans, err
+	}
+	if dryRun {
+		return plans, nil
+	}
+	for i, step := range r.steps {
+		if plans[i].UpToDate() {
+			r.logger.Info("Migration step up to date", "step", step.Name(), "version", plans[i].Current)
+			continue
+		}
+		r.logger.Info("Applying migration step", "step", step.Name(), "from", plans[i].Current, "to", plans[i].Target)
+		if err := step.Apply(ctx); err != nil {
+			return plans, fmt.Errorf("migration step %s failed: %w", step.Name(), err)
+		}
+	}
+	return plans, nil
+}
+
+// Verify runs the pre-flight checks and returns ErrPending when any step has changes to apply.
+// maas-api uses it at startup when migrations run as a separate job.
+func (r *Runner) Verify(ctx context.Context) error {
+	plans, err := r.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	for _, plan := range plans {
+		if !plan.UpToDate() {
+			return fmt.Errorf("%w: %s is at %q, this version expects %q; run `maas-api migrate` first",
+				ErrPending, plan.Step, plan.Current, plan.Target)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package migration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/migration"
+)
+
+type fakeStep struct {
+	name     string
+	plan     migration.Plan
+	planErr  error
+	applyErr error
+	applied  bool
+}
+
+func (s *fakeStep) Name() string { return s.name }
+
+func (s *fakeStep) Plan(context.Context) (migration.Plan, error) { return s.plan, s.planErr }
+
+func (s *fakeStep) Apply(context.Context) error {
+	s.applied = true
+	return s.applyErr
+}
+
+func pending(current, target string) migration.Plan {
+	return migration.Plan{Current: current, Target: target, Pending: []string{"change"}, Rollback: []string{"undo"}}
+}
+
+func TestRunnerAppliesPendingSteps(t *testing.T) {
+	current := &fakeStep{name: "current", plan: migration.Plan{Current: "2", Target: "2"}}
+	behind := &fakeStep{name: "behind", plan: pending("1", "2")}
+
+	plans, err := migration.NewRunner(logger.Development(), current, behind).Run(t.Context(), false)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	assert.Equal(t, "current", plans[0].Step)
+	assert.True(t, plans[0].UpToDate())
+	assert.Equal(t, []string{"undo"}, plans[1].Rollback)
+	assert.False(t, current.applied, "up-to-date step applied")
+	assert.True(t, behind.applied)
+}
+
+func TestRunnerDryRun(t *testing.T) {
+	step := &fakeStep{name: "schema", plan: pending("1", "2")}
+
+	plans, err := migration.NewRunner(logger.Development(), step).Run(t.Context(), true)
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.False(t, step.applied)
+}
+
+func TestRunnerPreflightFailureAppliesNothing(t *testing.T) {
+	first := &fakeStep{name: "first", plan: pending("1", "2")}
+	dirty := &fakeStep{name: "dirty", planErr: errors.New("schema is dirty")}
+
+	_, err := migration.NewRunner(logger.Development(), first, dirty).Run(t.Context(), false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pre-flight check of dirty failed")
+	assert.False(t, first.applied, "step applied although a later pre-flight check failed")
+}
+
+func TestRunnerStopsAtFailedStep(t *testing.T) {
+	failing := &fakeStep{name: "failing", plan: pending("1", "2"), applyErr: errors.New("boom")}
+	next := &fakeStep{name: "next", plan: pending("1", "2")}
+
+	_, err := migration.NewRunner(logger.Development(), failing, next).Run(t.Context(), false)
+	require.Error(t, err)
+	assert.False(t, next.applied)
+}
+
+func TestRunnerVerify(t *testing.T) {
+	upToDate := &fakeStep{name: "schema", plan: migration.Plan{Current: "5", Target: "5"}}
+	require.NoError(t, migration.NewRunner(logger.Development(), upToDate).Verify(t.Context()))
+
+	behind := &fakeStep{name: "schema", plan: pending("4", "5")}
+	err := migration.NewRunner(logger.Development(), behind).Verify(t.Context())
+	require.ErrorIs(t, err, migration.ErrPending)
+	assert.False(t, behind.applied)
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// AliasIndex indexes MaaSModelRefs by each of their spec.aliases, across namespaces.
+const AliasIndex = "alias"
+
+// AliasIndexFunc is the cache.IndexFunc for AliasIndex.
+func AliasIndexFunc(obj any) ([]string, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	return Aliases(u), nil
+}
+
+// MaaSModelRefAliasLister is implemented by listers indexed by AliasIndex, so resolving an alias
+// does not scan every MaaSModelRef.
+type MaaSModelRefAliasLister interface {
+	ByAlias(alias string) ([]*unstructured.Unstructured, error)
+}
+
+// Aliases returns the spec.aliases of a MaaSModelRef: other names clients may use for it.
+func Aliases(u *unstructured.Unstructured) []string {
+	aliases, _, _ := unstructured.NestedStringSlice(u.Object, "spec", "aliases")
+	return aliases
+}
+
+// AliasResolver returns a function that resolves a model named by one of its aliases to the
+// namespace and name of its MaaSModelRef, in namespace or, when namespace is empty, in any
+// namespace. ok is false when a MaaSModelRef has the name itself, which takes precedence, and when
+// no single model has the alias.
+func AliasResolver(lister MaaSModelRefLister) func(namespace, name string) (string, string, bool) {
+	return func(namespace, name string) (string, string, bool) {
+		if lister == nil || name == "" {
+			return "", "", false
+		}
+		if namespace != "" {
+			if getter, ok := lister.(MaaSModelRefGetter); ok {
+				if u, err := getter.Get(namespace, name); err == nil && u != nil {
+					return "", "", false
+				}
+			}
+		} else if indexed, ok := lister.(MaaSModelRefNameLister); ok {
+			if items, err := indexed.ByName(name); err == nil && len(items) > 0 {
+				return "", "", false
+			}
+		}
+
+		var candidates []*unstructured.Unstructured
+		if indexed, ok := lister.(MaaSModelRefAliasLister); ok {
+			items, err := indexed.ByAlias(name)
+			if err != nil {
+				return "", "", false
+			}
+			candidates = items
+		} else {
+			items, err := lister.List()
+			if err != nil {
+				return "", "", false
+			}
+			for _, u := range items {
+				if u.GetName() == name && (namespace == "" || u.GetNamespace() == namespace) {
+					return "", "", false
+				}
+				for _, alias := range Aliases(u) {
+					if alias == name {
+						candidates = append(candidates, u)
+						break
+					}
+				}
+			}
+		}
+
+		var match *unstructured.Unstructured
+		for _, u := range candidates {
+			if namespace != "" && u.GetNamespace() != namespace {
+				continue
+			}
+			if match != nil {
+				// Aliases are unique within a namespace, but not across them.
+				return "", "", false
+			}
+			match = u
+		}
+		if match == nil {
+			return "", "", false
+		}
+		return match.GetNamespace(), match.GetName(), true
+	}
+}
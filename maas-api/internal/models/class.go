@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Model classes, from spec.class of a MaaSModelRef. A model without a class serves every path,
+// its requests are not validated and it is metered like a chat model.
+const (
+	ClassChat       = "chat"
+	ClassCompletion = "completion"
+	ClassEmbedding  = "embedding"
+	ClassReranker   = "reranker"
+	ClassAudio      = "audio"
+)
+
+// classPaths are the endpoints of each class, as routed by maas-controller.
+var classPaths = map[string][]string{
+	ClassChat:       {"/v1/chat/completions", "/v1/responses"},
+	ClassCompletion: {"/v1/completions"},
+	ClassEmbedding:  {"/v1/embeddings"},
+	ClassReranker:   {"/v1/rerank", "/v2/rerank", "/rerank"},
+	ClassAudio:      {"/v1/audio/transcriptions", "/v1/audio/translations", "/v1/audio/speech"},
+}
+
+// ClassOf returns the class of a MaaSModelRef, or "" when it has none.
+func ClassOf(u *unstructured.Unstructured) string {
+	class, _, _ := unstructured.NestedString(u.Object, "spec", "class")
+	return class
+}
+
+// ServesPath reports whether a model of class serves path, without its query. Models without a
+// class, or with a class this version does not know, serve every path.
+func ServesPath(class, path string) bool {
+	paths, ok := classPaths[class]
+	if !ok {
+		return true
+	}
+	path, _, _ = strings.Cut(path, "?")
+	return path == "/v1/models" || slices.Contains(paths, path)
+}
+
+// InputOnly reports whether a model of class is billed for input tokens only: embedding and
+// reranker models generate no output tokens worth charging for.
+func InputOnly(class string) bool {
+	return class == ClassEmbedding || class == ClassReranker
+}
+
+// ValidateRequest checks that the JSON body of a request on path has the fields the endpoint of
+// class requires. Requests for models without a class, and endpoints whose body is not JSON such
+// as audio transcriptions, are not checked.
+func ValidateRequest(class, path string, body []byte) error {
+	if _, ok := classPaths[class]; !ok {
+		return nil
+	}
+	path, _, _ = strings.Cut(path, "?")
+	var required []string
+	switch path {
+	case "/v1/chat/completions":
+		required = []string{"messages"}
+	case "/v1/responses", "/v1/embeddings", "/v1/audio/speech":
+		required = []string{"input"}
+	case "/v1/completions":
+		required = []string{"prompt"}
+	case "/v1/rerank", "/v2/rerank", "/rerank":
+		required = []string{"query", "documents"}
+	default:
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return errors.New("request body must be a JSON object")
+	}
+	for _, name := range required {
+		if empty(fields[name]) {
+			return fmt.Errorf("%s requests to a %s model must have a non-empty %s field", path, class, name)
+		}
+	}
+	return nil
+}
+
+// empty reports whether a JSON value is missing, null, "" or [].
+func empty(value json.RawMessage) bool {
+	switch strings.TrimSpace(string(value)) {
+	case "", "null", `""`, "[]":
+		return true
+	}
+	return false
+}
+
+// ClassResolver returns a function that reads a model's ("namespace/name") class from the cached
+// MaaSModelRefs. It returns "" for unknown models.
+func ClassResolver(lister MaaSModelRefLister) func(model string) string {
+	return func(model string) string {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return ""
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return ""
+		}
+		return ClassOf(u)
+	}
+}
@@ -0,0 +1,65 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func TestServesPath(t *testing.T) {
+	assert.True(t, models.ServesPath("", "/v1/anything"), "models without a class serve every path")
+	assert.True(t, models.ServesPath(models.ClassEmbedding, "/v1/embeddings?x=1"))
+	assert.True(t, models.ServesPath(models.ClassEmbedding, "/v1/models"))
+	assert.False(t, models.ServesPath(models.ClassEmbedding, "/v1/chat/completions"))
+	assert.True(t, models.ServesPath(models.ClassReranker, "/v2/rerank"))
+	assert.False(t, models.ServesPath(models.ClassChat, "/v1/embeddings"))
+}
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		class   string
+		path    string
+		body    string
+		wantErr bool
+	}{
+		{name: "chat", class: models.ClassChat, path: "/v1/chat/completions", body: `{"messages": [{"role": "user", "content": "hi"}]}`},
+		{name: "chat without messages", class: models.ClassChat, path: "/v1/chat/completions", body: `{"messages": []}`, wantErr: true},
+		{name: "responses", class: models.ClassChat, path: "/v1/responses", body: `{"input": "hi"}`},
+		{name: "completion", class: models.ClassCompletion, path: "/v1/completions", body: `{"prompt": ["a", "b"]}`},
+		{name: "completion without prompt", class: models.ClassCompletion, path: "/v1/completions", body: `{"prompt": null}`, wantErr: true},
+		{name: "embedding", class: models.ClassEmbedding, path: "/v1/embeddings", body: `{"input": [1, 2, 3]}`},
+		{name: "rerank", class: models.ClassReranker, path: "/rerank", body: `{"query": "q", "documents": ["a"]}`},
+		{name: "rerank without query", class: models.ClassReranker, path: "/v1/rerank", body: `{"documents": ["a"]}`, wantErr: true},
+		{name: "not an object", class: models.ClassEmbedding, path: "/v1/embeddings", body: `["hi"]`, wantErr: true},
+		{name: "transcription is not JSON", class: models.ClassAudio, path: "/v1/audio/transcriptions", body: `--boundary`},
+		{name: "no class", path: "/v1/chat/completions", body: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := models.ValidateRequest(tt.class, tt.path, []byte(tt.body))
+			assert.Equal(t, tt.wantErr, err != nil, "err = %v", err)
+		})
+	}
+}
+
+func TestInputOnly(t *testing.T) {
+	assert.True(t, models.InputOnly(models.ClassEmbedding))
+	assert.True(t, models.InputOnly(models.ClassReranker))
+	assert.False(t, models.InputOnly(models.ClassChat))
+	assert.False(t, models.InputOnly(""))
+}
+
+func TestExamplesForClass(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{"spec": map[string]any{"class": models.ClassEmbedding}}}
+	u.SetNamespace("llm")
+	u.SetName("embed")
+
+	examples := models.ExamplesFor(u, "https://maas.example.com/llm/embed")
+	assert.Len(t, examples.Data, 1)
+	assert.Equal(t, "https://maas.example.com/llm/embed/v1/embeddings", examples.Data[0].URL)
+	assert.JSONEq(t, `{"model": "embed", "input": "Hello!"}`, string(examples.Data[0].Body))
+}
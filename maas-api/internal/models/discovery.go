@@ -154,10 +154,11 @@ func discoveredToModels(discovered []openai.Model, original Model) []Model {
 				Created: created,
 				OwnedBy: original.OwnedBy,
 			},
-			Kind:    original.Kind,
-			URL:     original.URL,
-			Ready:   original.Ready,
-			Details: original.Details,
+			Kind:      original.Kind,
+			URL:       original.URL,
+			Ready:     original.Ready,
+			Details:   original.Details,
+			Resources: original.Resources,
 		})
 	}
 	// Fallback: if backend returned items but all had empty IDs, use original model
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// ErrorResponse is a custom denial body from a MaaSModelRef's spec.errorResponses.
+type ErrorResponse struct {
+	Message string `json:"message"`
+	URL     string `json:"url,omitempty"`
+}
+
+// ErrorResponses are the custom denial bodies of a model.
+type ErrorResponses struct {
+	// Forbidden replaces the body of access denials (reasons unauthorized and access_denied).
+	Forbidden *ErrorResponse
+	// NotFound replaces the body when no subscription of the caller includes the model
+	// (reasons not_found and model_not_in_subscription).
+	NotFound *ErrorResponse
+}
+
+// For returns the custom response for a denial reason, or nil to keep the default body.
+func (e *ErrorResponses) For(code string) *ErrorResponse {
+	if e == nil {
+		return nil
+	}
+	switch code {
+	case reason.Unauthorized, reason.AccessDenied:
+		return e.Forbidden
+	case reason.NotFound, reason.ModelNotInSubscription:
+		return e.NotFound
+	}
+	return nil
+}
+
+// Body renders the response in the OpenAI error format, with the denial reason as its type.
+func (r *ErrorResponse) Body(reason string) string {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			URL     string `json:"url,omitempty"`
+		} `json:"error"`
+	}
+	body.Error.Message, body.Error.Type, body.Error.URL = r.Message, reason, r.URL
+	out, _ := json.Marshal(body)
+	return string(out)
+}
+
+// ErrorResponseResolver returns a function that reads a model's ("namespace/name") custom denial
+// bodies from the cached MaaSModelRefs. It returns nil when the model sets none.
+func ErrorResponseResolver(lister MaaSModelRefLister) func(model string) *ErrorResponses {
+	return func(model string) *ErrorResponses {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return nil
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return nil
+		}
+		forbidden := errorResponse(u, "forbidden")
+		notFound := errorResponse(u, "notFound")
+		if forbidden == nil && notFound == nil {
+			return nil
+		}
+		return &ErrorResponses{Forbidden: forbidden, NotFound: notFound}
+	}
+}
+
+func errorResponse(u *unstructured.Unstructured, kind string) *ErrorResponse {
+	message, _, _ := unstructured.NestedString(u.Object, "spec", "errorResponses", kind, "message")
+	if message == "" {
+		return nil
+	}
+	url, _, _ := unstructured.NestedString(u.Object, "spec", "errorResponses", kind, "url")
+	return &ErrorResponse{Message: message, URL: url}
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TokenPlaceholder stands in for the caller's API key in generated curl snippets.
+const TokenPlaceholder = "$MAAS_API_KEY"
+
+// Example is a ready-to-run request to a model.
+type Example struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Curl        string          `json:"curl"`
+}
+
+// Examples is the response of GET /v1/models/{name}/examples.
+type Examples struct {
+	Object string `json:"object"`
+	// Model is the MaaSModelRef as namespace/name.
+	Model string `json:"model"`
+	// DocsURL is the model's external documentation from spec.documentation.url.
+	DocsURL string    `json:"docsUrl,omitempty"`
+	Data    []Example `json:"data"`
+}
+
+// ExamplesFor builds the examples of a MaaSModelRef against baseURL, the model's endpoint as the
+// caller reaches it. Examples come from spec.documentation.examples; a model without any gets a
+// default request for its kind and class: an MCP initialize call, a request to the endpoint of the
+// model's class, or a chat completion with the model's name.
+func ExamplesFor(u *unstructured.Unstructured, baseURL string) *Examples {
+	docsURL, _, _ := unstructured.NestedString(u.Object, "spec", "documentation", "url")
+	out := &Examples{Object: "list", Model: u.GetNamespace() + "/" + u.GetName(), DocsURL: docsURL}
+
+	items, _, _ := unstructured.NestedSlice(u.Object, "spec", "documentation", "examples")
+	for _, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(fields, "name")
+		description, _, _ := unstructured.NestedString(fields, "description")
+		path, _, _ := unstructured.NestedString(fields, "path")
+		body, _, _ := unstructured.NestedString(fields, "body")
+		if name == "" || path == "" {
+			continue
+		}
+		out.Data = append(out.Data, newExample(name, description, baseURL, path, body))
+	}
+	if len(out.Data) == 0 {
+		out.Data = []Example{defaultExample(u, baseURL)}
+	}
+	return out
+}
+
+func defaultExample(u *unstructured.Unstructured, baseURL string) Example {
+	kind, _, _ := unstructured.NestedString(u.Object, "spec", "modelRef", "kind")
+	if kind == "MCPServer" {
+		return newExample("initialize", "Open an MCP session", baseURL, "/mcp",
+			`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-06-18", "capabilities": {}, "clientInfo": {"name": "curl", "version": "1.0"}}}`)
+	}
+	switch ClassOf(u) {
+	case ClassCompletion:
+		body, _ := json.Marshal(map[string]any{"model": u.GetName(), "prompt": "Hello!"})
+		return newExample("completion", "Complete a prompt", baseURL, "/v1/completions", string(body))
+	case ClassEmbedding:
+		body, _ := json.Marshal(map[string]any{"model": u.GetName(), "input": "Hello!"})
+		return newExample("embedding", "Embed a text", baseURL, "/v1/embeddings", string(body))
+	case ClassReranker:
+		body, _ := json.Marshal(map[string]any{"model": u.GetName(), "query": "What is MaaS?", "documents": []string{"Models as a Service", "A hello world program"}})
+		return newExample("rerank", "Rank documents against a query", baseURL, "/v1/rerank", string(body))
+	case ClassAudio:
+		body, _ := json.Marshal(map[string]any{"model": u.GetName(), "input": "Hello!", "voice": "alloy"})
+		return newExample("speech", "Synthesize speech", baseURL, "/v1/audio/speech", string(body))
+	}
+	body, _ := json.Marshal(map[string]any{
+		"model":    u.GetName(),
+		"messages": []map[string]string{{"role": "user", "content": "Hello!"}},
+	})
+	return newExample("chat", "Send a chat completion", baseURL, "/v1/chat/completions", string(body))
+}
+
+func newExample(name, description, baseURL, path, body string) Example {
+	example := Example{
+		Name:        name,
+		Description: description,
+		Method:      http.MethodGet,
+		URL:         strings.TrimRight(baseURL, "/") + path,
+	}
+	if body != "" && json.Valid([]byte(body)) {
+		example.Method = http.MethodPost
+		example.Body = json.RawMessage(body)
+	}
+	example.Curl = curl(example)
+	return example
+}
+
+// curl renders an example as a shell command, with TokenPlaceholder for the API key.
+func curl(e Example) string {
+	var b strings.Builder
+	// curl sends a POST when -d is given.
+	b.WriteString("curl -sS " + shellQuote(e.URL) + " \\\n")
+	b.WriteString(`  -H "Authorization: Bearer ` + TokenPlaceholder + `"`)
+	if len(e.Body) > 0 {
+		b.WriteString(" \\\n  -H \"Content-Type: application/json\" \\\n  -d " + shellQuote(string(e.Body)))
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
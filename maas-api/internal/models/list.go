@@ -0,0 +1,177 @@
+package models
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Sort keys of ListOptions.
+const (
+	SortByID        = "id"
+	SortByCreated   = "created"
+	SortByNamespace = "namespace"
+)
+
+// ErrInvalidContinue is returned for a continue token that is malformed or was issued for a
+// different sort order.
+var ErrInvalidContinue = errors.New("invalid continue token")
+
+// MaaSModelRefNamespaceLister is implemented by listers that can list one namespace from the
+// informer cache's namespace index instead of scanning List.
+type MaaSModelRefNamespaceLister interface {
+	ByNamespace(namespace string) ([]*unstructured.Unstructured, error)
+}
+
+// ListOptions narrows, orders and pages the MaaSModelRefs of a listing.
+type ListOptions struct {
+	// Namespace keeps only the MaaSModelRefs of one namespace when set.
+	Namespace string
+	// Ready keeps only models that are (true) or are not (false) Ready when set.
+	Ready *bool
+	// Sort is SortByID (the default), SortByCreated or SortByNamespace. SortByID orders by
+	// MaaSModelRef name, which is the model ID unless discovery reports the served model names.
+	// Ties are broken by namespace and name.
+	Sort       string
+	Descending bool
+	// Limit bounds the MaaSModelRefs of a page; 0 lists them all.
+	Limit int
+	// Continue resumes after the last MaaSModelRef of a previous page.
+	Continue string
+}
+
+// continueToken is the position of the last MaaSModelRef of a page, with the order it was in.
+type continueToken struct {
+	Sort       string `json:"s"`
+	Descending bool   `json:"d,omitempty"`
+	Created    int64  `json:"c,omitempty"`
+	Namespace  string `json:"ns"`
+	Name       string `json:"n"`
+}
+
+// ListPage lists a page of the MaaSModelRefs matching opts as API models, leaving out soft-deleted
+// ones. The MaaSModelRefs are filtered and ordered as cached, and only the page is converted. The
+// returned token continues the listing, or is "" on the last page. Because the position is kept
+// rather than an offset, models created or deleted between pages do not shift the ones after them.
+func ListPage(lister MaaSModelRefLister, opts ListOptions) ([]Model, string, error) {
+	if lister == nil {
+		return nil, "", nil
+	}
+	if opts.Sort == "" {
+		opts.Sort = SortByID
+	}
+	var after *continueToken
+	if opts.Continue != "" {
+		t, err := decodeContinue(opts.Continue)
+		if err != nil || t.Sort != opts.Sort || t.Descending != opts.Descending {
+			return nil, "", ErrInvalidContinue
+		}
+		after = t
+	}
+
+	var items []*unstructured.Unstructured
+	var err error
+	if indexed, ok := lister.(MaaSModelRefNamespaceLister); ok && opts.Namespace != "" {
+		items, err = indexed.ByNamespace(opts.Namespace)
+	} else {
+		items, err = lister.List()
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	compare := func(a, b continueToken) int {
+		var c int
+		switch opts.Sort {
+		case SortByCreated:
+			c = cmp.Or(cmp.Compare(a.Created, b.Created), strings.Compare(a.Namespace, b.Namespace), strings.Compare(a.Name, b.Name))
+		case SortByNamespace:
+			c = cmp.Or(strings.Compare(a.Namespace, b.Namespace), strings.Compare(a.Name, b.Name))
+		default:
+			c = cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Namespace, b.Namespace))
+		}
+		if opts.Descending {
+			return -c
+		}
+		return c
+	}
+	position := func(u *unstructured.Unstructured) continueToken {
+		return continueToken{Sort: opts.Sort, Descending: opts.Descending, Created: u.GetCreationTimestamp().Unix(), Namespace: u.GetNamespace(), Name: u.GetName()}
+	}
+
+	matched := make([]*unstructured.Unstructured, 0, len(items))
+	for _, u := range items {
+		if IsSoftDeleted(u) || (opts.Namespace != "" && u.GetNamespace() != opts.Namespace) {
+			continue
+		}
+		if opts.Ready != nil {
+			phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+			if (phase == "Ready") != *opts.Ready {
+				continue
+			}
+		}
+		if after != nil && compare(position(u), *after) <= 0 {
+			continue
+		}
+		matched = append(matched, u)
+	}
+	slices.SortFunc(matched, func(a, b *unstructured.Unstructured) int {
+		return compare(position(a), position(b))
+	})
+
+	next := ""
+	if opts.Limit > 0 && len(matched) > opts.Limit {
+		matched = matched[:opts.Limit]
+		next = encodeContinue(position(matched[len(matched)-1]))
+	}
+	out := make([]Model, 0, len(matched))
+	for _, u := range matched {
+		if m := maasModelRefToModel(u); m != nil {
+			out = append(out, *m)
+		}
+	}
+	return out, next, nil
+}
+
+// SortByPage orders models as their MaaSModelRefs appear in page, the models ListPage returned, so the
+// response follows the order the listing was paged in. Discovery can turn one MaaSModelRef into
+// several models, with IDs and created times of the model server's own, so the models are ordered by
+// their MaaSModelRef (OwnedBy) and then by ID and URL.
+func SortByPage(list, page []Model) {
+	rank := make(map[string]int, len(page))
+	for i, m := range page {
+		rank[m.OwnedBy] = i
+	}
+	slices.SortFunc(list, func(a, b Model) int {
+		return cmp.Or(cmp.Compare(rank[a.OwnedBy], rank[b.OwnedBy]), strings.Compare(a.ID, b.ID), strings.Compare(urlString(a), urlString(b)))
+	})
+}
+
+func urlString(m Model) string {
+	if m.URL == nil {
+		return ""
+	}
+	return m.URL.String()
+}
+
+func encodeContinue(t continueToken) string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeContinue(s string) (*continueToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var t continueToken
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,119 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func listedModel(namespace, name string, created int64, ready bool) *unstructured.Unstructured {
+	u := scopedModel(namespace, name, nil)
+	u.SetCreationTimestamp(metav1.NewTime(time.Unix(created, 0)))
+	if ready {
+		_ = unstructured.SetNestedField(u.Object, "Ready", "status", "phase")
+	}
+	return u
+}
+
+func ownedBy(list []models.Model) []string {
+	out := make([]string, 0, len(list))
+	for _, m := range list {
+		out = append(out, m.OwnedBy)
+	}
+	return out
+}
+
+func TestListPage(t *testing.T) {
+	deleted := listedModel("llm", "retired", 100, true)
+	deleted.SetAnnotations(map[string]string{constant.AnnotationSoftDeleted: "true"})
+	lister := modelRefs{
+		listedModel("llm", "mistral", 300, true),
+		listedModel("team-a", "granite", 200, false),
+		listedModel("llm", "granite", 400, true),
+		listedModel("team-a", "phi", 100, true),
+		deleted,
+	}
+
+	t.Run("defaults list everything by id", func(t *testing.T) {
+		list, next, err := models.ListPage(lister, models.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, next)
+		assert.Equal(t, []string{"llm/granite", "team-a/granite", "llm/mistral", "team-a/phi"}, ownedBy(list))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		ready := false
+		list, _, err := models.ListPage(lister, models.ListOptions{Ready: &ready})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a/granite"}, ownedBy(list))
+
+		list, _, err = models.ListPage(lister, models.ListOptions{Namespace: "llm"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"llm/granite", "llm/mistral"}, ownedBy(list))
+	})
+
+	t.Run("pages follow the order", func(t *testing.T) {
+		opts := models.ListOptions{Sort: models.SortByCreated, Descending: true, Limit: 3}
+		list, next, err := models.ListPage(lister, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"llm/granite", "llm/mistral", "team-a/granite"}, ownedBy(list))
+		require.NotEmpty(t, next)
+
+		opts.Continue = next
+		list, next, err = models.ListPage(lister, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a/phi"}, ownedBy(list))
+		assert.Empty(t, next)
+	})
+
+	t.Run("continue survives changes", func(t *testing.T) {
+		opts := models.ListOptions{Sort: models.SortByNamespace, Limit: 2}
+		list, next, err := models.ListPage(lister, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"llm/granite", "llm/mistral"}, ownedBy(list))
+
+		// A model sorted before the position does not shift the next page.
+		changed := append(modelRefs{listedModel("llm", "bert", 500, true)}, lister[1:]...)
+		opts.Continue = next
+		list, _, err = models.ListPage(changed, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"team-a/granite", "team-a/phi"}, ownedBy(list))
+	})
+
+	t.Run("continue must match the order", func(t *testing.T) {
+		_, next, err := models.ListPage(lister, models.ListOptions{Limit: 1})
+		require.NoError(t, err)
+		_, _, err = models.ListPage(lister, models.ListOptions{Sort: models.SortByCreated, Continue: next})
+		require.ErrorIs(t, err, models.ErrInvalidContinue)
+		_, _, err = models.ListPage(lister, models.ListOptions{Continue: "not a token"})
+		require.ErrorIs(t, err, models.ErrInvalidContinue)
+	})
+}
+
+func TestSortByPage(t *testing.T) {
+	page, _, err := models.ListPage(modelRefs{
+		listedModel("llm", "zephyr", 100, true),
+		listedModel("llm", "alpha", 200, true),
+	}, models.ListOptions{})
+	require.NoError(t, err)
+
+	// Discovery reports served model names, which need not sort like the MaaSModelRef names.
+	list := []models.Model{page[1], page[0], page[0]}
+	list[1].ID = "aaa-served"
+	list[2].ID = "zzz-served"
+	list[0].ID = "bbb-served"
+	models.SortByPage(list, page)
+
+	ids := make([]string, 0, len(list))
+	for _, m := range list {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"aaa-served", "zzz-served", "bbb-served"}, ids)
+}
@@ -2,8 +2,11 @@ package models
 
 import (
 	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/openai/openai-go/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
@@ -23,7 +26,14 @@ type MaaSModelRefLister interface {
 	List() ([]*unstructured.Unstructured, error)
 }
 
+// MaaSModelRefGetter is implemented by listers that can look up one MaaSModelRef by key from the
+// informer cache instead of scanning List. Get returns nil when the model does not exist.
+type MaaSModelRefGetter interface {
+	Get(namespace, name string) (*unstructured.Unstructured, error)
+}
+
 // ListFromMaaSModelRefLister converts cached MaaSModelRef items to API models. Uses status.endpoint and status.phase.
+// Soft-deleted models are left out.
 func ListFromMaaSModelRefLister(lister MaaSModelRefLister) ([]Model, error) {
 	if lister == nil {
 		return nil, nil
@@ -34,6 +44,9 @@ func ListFromMaaSModelRefLister(lister MaaSModelRefLister) ([]Model, error) {
 	}
 	out := make([]Model, 0, len(items))
 	for _, u := range items {
+		if IsSoftDeleted(u) {
+			continue
+		}
 		m := maasModelRefToModel(u)
 		if m != nil {
 			out = append(out, *m)
@@ -47,6 +60,11 @@ func GVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: maasGroup, Version: maasVersion, Resource: maasResource}
 }
 
+// FromMaaSModelRef converts a single MaaSModelRef to its catalog entry. Returns nil for a nil item.
+func FromMaaSModelRef(u *unstructured.Unstructured) *Model {
+	return maasModelRefToModel(u)
+}
+
 // maasModelRefToModel converts a MaaSModelRef unstructured to a Model for the API.
 func maasModelRefToModel(u *unstructured.Unstructured) *Model {
 	if u == nil {
@@ -87,7 +105,23 @@ func maasModelRefToModel(u *unstructured.Unstructured) *Model {
 		created = t.Unix()
 	}
 
+	var resources *Resources
+	if res, found, _ := unstructured.NestedMap(u.Object, "status", "resources"); found {
+		gpuType, _, _ := unstructured.NestedString(res, "gpuType")
+		gpuCount, _, _ := unstructured.NestedInt64(res, "gpuCount")
+		memory, _, _ := unstructured.NestedString(res, "memory")
+		replicas, _, _ := unstructured.NestedInt64(res, "replicas")
+		resources = &Resources{GPUType: gpuType, GPUCount: gpuCount, Memory: memory, Replicas: int32(replicas)}
+	}
+
 	namespace := u.GetNamespace()
+	lineage, _, _ := unstructured.NestedStringSlice(u.Object, "status", "lineage")
+	var parent string
+	if len(lineage) > 0 {
+		parent = lineage[0]
+	}
+	pricingMultiplier, _, _ := unstructured.NestedString(u.Object, "status", "effectivePricingMultiplier")
+
 	// OwnedBy includes both namespace and MaaSModelRef name for dashboard display
 	ownedBy := namespace + "/" + name
 	return &Model{
@@ -97,9 +131,154 @@ func maasModelRefToModel(u *unstructured.Unstructured) *Model {
 			Created: created,
 			OwnedBy: ownedBy,
 		},
-		Kind:    kind,
-		URL:     urlPtr,
-		Ready:   ready,
-		Details: details,
+		Kind:      kind,
+		Class:     ClassOf(u),
+		URL:       urlPtr,
+		Ready:     ready,
+		Details:   details,
+		Aliases:   Aliases(u),
+		Resources: resources,
+
+		Parent:            parent,
+		Lineage:           lineage,
+		PricingMultiplier: pricingMultiplier,
+		Cluster:           OriginCluster(u),
+	}
+}
+
+// IsSoftDeleted reports whether a MaaSModelRef is soft-deleted: unpublished from the catalog and
+// denied, but kept for restore.
+func IsSoftDeleted(u *unstructured.Unstructured) bool {
+	return u.GetAnnotations()[constant.AnnotationSoftDeleted] == "true"
+}
+
+// IsIgnored reports whether a MaaSModelRef is outside MaaS governance, so that it is treated as
+// not found rather than as a model that denies requests.
+func IsIgnored(obj metav1.Object) bool {
+	return obj.GetAnnotations()[constant.AnnotationIgnore] == "true"
+}
+
+// OriginCluster returns the cluster hosting a federated MaaSModelRef, or "" when maas-api does not
+// federate models.
+func OriginCluster(obj metav1.Object) string {
+	return obj.GetAnnotations()[constant.AnnotationOriginCluster]
+}
+
+// SoftDeletedResolver returns a function that reports whether a model ("namespace/name") is
+// soft-deleted in the cached MaaSModelRefs.
+func SoftDeletedResolver(lister MaaSModelRefLister) func(model string) bool {
+	return func(model string) bool {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return false
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return false
+		}
+		return IsSoftDeleted(u)
+	}
+}
+
+// AllowListResolver returns a function that reads a model's ("namespace/name") allow-list
+// annotations from the cached MaaSModelRefs.
+func AllowListResolver(lister MaaSModelRefLister) func(model string) (groups, users []string) {
+	return func(model string) ([]string, []string) {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return nil, nil
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return nil, nil
+		}
+		annotations := u.GetAnnotations()
+		return splitList(annotations[constant.AnnotationAllowedGroups]), splitList(annotations[constant.AnnotationAllowedUsers])
+	}
+}
+
+// ContextWindowResolver returns a function that reads a model's ("namespace/name") context window
+// annotation from the cached MaaSModelRefs. It returns 0 when the annotation is unset or not a
+// positive number of tokens.
+func ContextWindowResolver(lister MaaSModelRefLister) func(model string) int64 {
+	return func(model string) int64 {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return 0
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return 0
+		}
+		window, err := strconv.ParseInt(strings.TrimSpace(u.GetAnnotations()[constant.AnnotationContextWindow]), 10, 64)
+		if err != nil || window <= 0 {
+			return 0
+		}
+		return window
+	}
+}
+
+// SampleRateResolver returns a function that reads a model's ("namespace/name") metering sample
+// rate annotation from the cached MaaSModelRefs. It returns 1 when the annotation is unset or
+// not a number in (0, 1].
+func SampleRateResolver(lister MaaSModelRefLister) func(model string) float64 {
+	return func(model string) float64 {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return 1
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return 1
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(u.GetAnnotations()[constant.AnnotationMeteringSampleRate]), 64)
+		if err != nil || rate <= 0 || rate > 1 {
+			return 1
+		}
+		return rate
+	}
+}
+
+func splitList(value string) []string {
+	var out []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// LineageResolver returns a function that looks up a model's ancestors ("namespace/name", nearest
+// first) from status.lineage of the cached MaaSModelRefs.
+func LineageResolver(lister MaaSModelRefLister) func(model string) []string {
+	return func(model string) []string {
+		if lister == nil {
+			return nil
+		}
+		if getter, ok := lister.(MaaSModelRefGetter); ok {
+			ns, name, _ := strings.Cut(model, "/")
+			u, err := getter.Get(ns, name)
+			if err != nil || u == nil {
+				return nil
+			}
+			lineage, _, _ := unstructured.NestedStringSlice(u.Object, "status", "lineage")
+			return lineage
+		}
+		items, err := lister.List()
+		if err != nil {
+			return nil
+		}
+		for _, u := range items {
+			if u.GetNamespace()+"/"+u.GetName() == model {
+				lineage, _, _ := unstructured.NestedStringSlice(u.Object, "status", "lineage")
+				return lineage
+			}
+		}
+		return nil
 	}
 }
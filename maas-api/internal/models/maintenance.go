@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+)
+
+// InMaintenance reports whether a MaaSModelRef is in maintenance at now, and until when. The
+// maintenance annotation has no planned end, so until is zero for it. maas-controller reports the
+// model's maintenance windows in status.maintenance; a window whose end has passed is ignored even
+// if the controller has not cleared it yet.
+func InMaintenance(u *unstructured.Unstructured, now time.Time) (bool, time.Time) {
+	if u.GetAnnotations()[constant.AnnotationMaintenance] == "true" {
+		return true, time.Time{}
+	}
+	status, found, _ := unstructured.NestedMap(u.Object, "status", "maintenance")
+	if !found {
+		return false, time.Time{}
+	}
+	untilStr, _ := status["until"].(string)
+	if untilStr == "" {
+		return true, time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, untilStr)
+	if err != nil || !now.Before(until) {
+		return false, time.Time{}
+	}
+	return true, until
+}
+
+// MaintenanceResolver returns a function that reports whether a model ("namespace/name") in the
+// cached MaaSModelRefs is in maintenance at now, and how long clients should wait before retrying:
+// until the end of the maintenance window, or defaultRetryAfter when it has no planned end.
+func MaintenanceResolver(lister MaaSModelRefLister, defaultRetryAfter time.Duration) func(model string, now time.Time) (bool, time.Duration) {
+	return func(model string, now time.Time) (bool, time.Duration) {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return false, 0
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return false, 0
+		}
+		inMaintenance, until := InMaintenance(u, now)
+		if !inMaintenance {
+			return false, 0
+		}
+		if until.IsZero() {
+			return true, defaultRetryAfter
+		}
+		return true, until.Sub(now)
+	}
+}
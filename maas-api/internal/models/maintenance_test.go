@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func maintenanceModel(name string, annotated bool, until string) *unstructured.Unstructured {
+	u := tracedModel(name, nil)
+	if annotated {
+		u.SetAnnotations(map[string]string{constant.AnnotationMaintenance: "true"})
+	}
+	if until != "" {
+		_ = unstructured.SetNestedField(u.Object, until, "status", "maintenance", "until")
+	}
+	return u
+}
+
+func TestMaintenanceResolver(t *testing.T) {
+	now := time.Date(2025, 6, 11, 10, 30, 0, 0, time.UTC)
+	resolve := models.MaintenanceResolver(modelRefs{
+		maintenanceModel("annotated", true, ""),
+		maintenanceModel("window", false, "2025-06-11T11:00:00Z"),
+		maintenanceModel("ended", false, "2025-06-11T10:00:00Z"),
+		maintenanceModel("serving", false, ""),
+	}, 5*time.Minute)
+
+	for _, tt := range []struct {
+		model      string
+		want       bool
+		retryAfter time.Duration
+	}{
+		{model: "llm/annotated", want: true, retryAfter: 5 * time.Minute},
+		{model: "llm/window", want: true, retryAfter: 30 * time.Minute},
+		{model: "llm/ended", want: false},
+		{model: "llm/serving", want: false},
+		{model: "llm/missing", want: false},
+	} {
+		got, retryAfter := resolve(tt.model, now)
+		assert.Equal(t, tt.want, got, tt.model)
+		assert.Equal(t, tt.retryAfter, retryAfter, tt.model)
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// maxNotFoundEntries bounds the cache, so a scanner cycling through random names cannot grow it
+// without limit. When it is full of live entries, new misses are simply not cached.
+const maxNotFoundEntries = 10000
+
+// NotFoundCache remembers model references (a bare name or namespace/name) that matched no
+// MaaSModelRef, so repeated lookups of the same unknown name skip the informer cache. Entries
+// expire after a short TTL and are dropped as soon as a matching MaaSModelRef is added.
+// A nil NotFoundCache caches nothing.
+type NotFoundCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// NewNotFoundCache creates a cache whose entries live for ttl. It returns nil when ttl is not positive.
+func NewNotFoundCache(ttl time.Duration) *NotFoundCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &NotFoundCache{ttl: ttl, entries: map[string]time.Time{}}
+}
+
+// Has reports whether ref was recently found not to exist.
+func (c *NotFoundCache) Has(ref string) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.entries[ref]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expires) {
+		delete(c.entries, ref)
+		return false
+	}
+	return true
+}
+
+// Add records that ref matched no model.
+func (c *NotFoundCache) Add(ref string) {
+	if c == nil {
+		return
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxNotFoundEntries {
+		for key, expires := range c.entries {
+			if now.After(expires) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= maxNotFoundEntries {
+			return
+		}
+	}
+	c.entries[ref] = now.Add(c.ttl)
+}
+
+// Forget drops the entries a model named namespace/name would now answer: its bare name and its
+// namespace/name.
+func (c *NotFoundCache) Forget(namespace, name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, name)
+	delete(c.entries, namespace+"/"+name)
+}
+
+// EventHandler forgets entries when a MaaSModelRef is added to the informer cache.
+func (c *NotFoundCache) EventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			if u, ok := obj.(*unstructured.Unstructured); ok {
+				c.Forget(u.GetNamespace(), u.GetName())
+			}
+		},
+	}
+}
+
+// Resolver wraps a NamespaceResolver so names it reports as ErrModelNotFound are answered from
+// the cache until they expire or the model appears.
+func (c *NotFoundCache) Resolver(resolve func(name string) (string, error)) func(name string) (string, error) {
+	if c == nil {
+		return resolve
+	}
+	return func(name string) (string, error) {
+		if c.Has(name) {
+			return "", ErrModelNotFound
+		}
+		ns, err := resolve(name)
+		if errors.Is(err, ErrModelNotFound) && !strings.Contains(name, "/") {
+			c.Add(name)
+		}
+		return ns, err
+	}
+}
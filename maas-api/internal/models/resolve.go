@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+)
+
+// NameIndex indexes MaaSModelRefs by name across namespaces, for resolving bare model names.
+const NameIndex = "name"
+
+// NameIndexFunc is the cache.IndexFunc for NameIndex.
+func NameIndexFunc(obj any) ([]string, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	return []string{u.GetName()}, nil
+}
+
+// MaaSModelRefNameLister is implemented by listers indexed by NameIndex, so resolving a bare
+// name does not scan every MaaSModelRef.
+type MaaSModelRefNameLister interface {
+	ByName(name string) ([]*unstructured.Unstructured, error)
+}
+
+// ErrModelNotFound is returned when no MaaSModelRef has the requested name.
+var ErrModelNotFound = errors.New("model not found")
+
+// AmbiguousModelError is returned when a bare model name matches MaaSModelRefs in several
+// namespaces and their resolution priorities do not single one out.
+type AmbiguousModelError struct {
+	Name       string
+	Namespaces []string
+}
+
+func (e *AmbiguousModelError) Error() string {
+	return fmt.Sprintf("model %q exists in namespaces %s; use a namespace-qualified model (namespace/%s)",
+		e.Name, strings.Join(e.Namespaces, ", "), e.Name)
+}
+
+// NamespaceResolver returns a function that resolves a bare model name to the namespace of its
+// MaaSModelRef. When the name exists in several namespaces, the one with the highest
+// AnnotationResolutionPriority wins; a tie at the top is an AmbiguousModelError rather than
+// whichever the cache happens to return first.
+func NamespaceResolver(lister MaaSModelRefLister) func(name string) (string, error) {
+	return func(name string) (string, error) {
+		if lister == nil {
+			return "", ErrModelNotFound
+		}
+		var candidates []*unstructured.Unstructured
+		if indexed, ok := lister.(MaaSModelRefNameLister); ok {
+			items, err := indexed.ByName(name)
+			if err != nil {
+				return "", err
+			}
+			candidates = items
+		} else {
+			items, err := lister.List()
+			if err != nil {
+				return "", err
+			}
+			for _, u := range items {
+				if u.GetName() == name {
+					candidates = append(candidates, u)
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			return "", ErrModelNotFound
+		}
+
+		best := resolutionPriority(candidates[0])
+		var top []string
+		for _, u := range candidates {
+			switch p := resolutionPriority(u); {
+			case p > best:
+				best, top = p, []string{u.GetNamespace()}
+			case p == best:
+				top = append(top, u.GetNamespace())
+			}
+		}
+		if len(top) > 1 {
+			slices.Sort(top)
+			return "", &AmbiguousModelError{Name: name, Namespaces: top}
+		}
+		return top[0], nil
+	}
+}
+
+func resolutionPriority(u *unstructured.Unstructured) int {
+	p, err := strconv.Atoi(u.GetAnnotations()[constant.AnnotationResolutionPriority])
+	if err != nil {
+		return 0
+	}
+	return p
+}
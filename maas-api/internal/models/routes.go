@@ -0,0 +1,186 @@
+package models
+
+import (
+	"net"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Annotations maas-controller falls back to when spec.routing leaves a field unset.
+const (
+	annotationPathPrefix = "maas.opendatahub.io/path-prefix"
+	annotationHostname   = "maas.opendatahub.io/hostname"
+)
+
+// RouteIndex indexes MaaSModelRefs with a custom route by "host:<hostname>" and
+// "path:/<first prefix segment>" of their path prefix and aliases, so resolving a request does not
+// scan every model.
+const RouteIndex = "route"
+
+// RouteIndexFunc is the cache.IndexFunc for RouteIndex.
+func RouteIndexFunc(obj any) ([]string, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	prefix, hostname, ok := Route(u)
+	if !ok {
+		return nil, nil
+	}
+	if hostname != "" {
+		return []string{"host:" + hostname}, nil
+	}
+	keys := []string{}
+	for _, p := range routePrefixes(u, prefix) {
+		if key := "path:" + firstSegment(p); !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// MaaSModelRefRouteLister is implemented by listers indexed by RouteIndex.
+type MaaSModelRefRouteLister interface {
+	ByRoute(key string) ([]*unstructured.Unstructured, error)
+}
+
+// Route returns the path prefix and dedicated hostname of a model's generated HTTPRoute, from
+// spec.routing or the path-prefix and hostname annotations, as maas-controller resolves them.
+// ok is false for models without a custom route and for kinds whose route KServe generates.
+func Route(u *unstructured.Unstructured) (pathPrefix, hostname string, ok bool) {
+	kind, _, _ := unstructured.NestedString(u.Object, "spec", "modelRef", "kind")
+	if kind != "ExternalModel" && kind != "MCPServer" {
+		return "", "", false
+	}
+	pathPrefix, _, _ = unstructured.NestedString(u.Object, "spec", "routing", "pathPrefix")
+	hostname, _, _ = unstructured.NestedString(u.Object, "spec", "routing", "hostname")
+	if pathPrefix == "" {
+		pathPrefix = u.GetAnnotations()[annotationPathPrefix]
+	}
+	if hostname == "" {
+		hostname = u.GetAnnotations()[annotationHostname]
+	}
+	if pathPrefix == "" && hostname == "" {
+		return "", "", false
+	}
+	if pathPrefix == "" {
+		pathPrefix = "/" + u.GetName()
+		if hostname != "" {
+			pathPrefix = "/"
+		}
+	}
+	if trimmed := strings.TrimRight(pathPrefix, "/"); trimmed != "" {
+		pathPrefix = trimmed
+	} else {
+		pathPrefix = "/"
+	}
+	return pathPrefix, strings.ToLower(hostname), true
+}
+
+// routePrefixes returns the path prefixes a model's generated HTTPRoute serves: pathPrefix, then
+// /<alias> for each of its aliases.
+func routePrefixes(u *unstructured.Unstructured, pathPrefix string) []string {
+	prefixes := []string{pathPrefix}
+	for _, alias := range Aliases(u) {
+		prefixes = append(prefixes, "/"+alias)
+	}
+	return prefixes
+}
+
+// RouteResolver resolves the model ("namespace/name") a request targets from its Host header or
+// path, using the custom routes of the cached MaaSModelRefs.
+type RouteResolver struct {
+	lister MaaSModelRefLister
+}
+
+// NewRouteResolver returns a RouteResolver over lister.
+func NewRouteResolver(lister MaaSModelRefLister) *RouteResolver {
+	return &RouteResolver{lister: lister}
+}
+
+// ByHost returns the model with host as its dedicated hostname whose path prefix, or the prefix of
+// one of its aliases, is the longest match for path.
+func (r *RouteResolver) ByHost(host, path string) (string, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	if host == "" {
+		return "", false
+	}
+	return r.longestMatch("host:"+host, host, path)
+}
+
+// ByPath returns the model for a request on a shared hostname. The /<namespace>/<name>/... path of
+// an existing model takes precedence; otherwise the model whose path prefix is the longest match.
+func (r *RouteResolver) ByPath(path string) (string, bool) {
+	path, _, _ = strings.Cut(path, "?")
+	if getter, ok := r.lister.(MaaSModelRefGetter); ok {
+		segments := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 3)
+		if len(segments) >= 2 && segments[0] != "" && segments[1] != "" {
+			if u, err := getter.Get(segments[0], segments[1]); err == nil && u != nil {
+				return segments[0] + "/" + segments[1], true
+			}
+		}
+	}
+	return r.longestMatch("path:"+firstSegment(path), "", path)
+}
+
+func (r *RouteResolver) longestMatch(key, host, path string) (string, bool) {
+	if r == nil || r.lister == nil {
+		return "", false
+	}
+	path, _, _ = strings.Cut(path, "?")
+	candidates, err := r.candidates(key)
+	if err != nil {
+		return "", false
+	}
+	var best *unstructured.Unstructured
+	bestLen := -1
+	for _, u := range candidates {
+		prefix, hostname, ok := Route(u)
+		if !ok || hostname != host {
+			continue
+		}
+		for _, p := range routePrefixes(u, prefix) {
+			if matchesPrefix(path, p) && len(p) > bestLen {
+				best, bestLen = u, len(p)
+			}
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.GetNamespace() + "/" + best.GetName(), true
+}
+
+func (r *RouteResolver) candidates(key string) ([]*unstructured.Unstructured, error) {
+	if indexed, ok := r.lister.(MaaSModelRefRouteLister); ok {
+		return indexed.ByRoute(key)
+	}
+	items, err := r.lister.List()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*unstructured.Unstructured, 0, len(items))
+	for _, u := range items {
+		if keys, _ := RouteIndexFunc(u); slices.Contains(keys, key) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// matchesPrefix reports whether path is under prefix on a segment boundary, as a Gateway API
+// PathPrefix match does.
+func matchesPrefix(path, prefix string) bool {
+	return prefix == "/" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// firstSegment returns "/<first segment>" of a path, or "/" for the root.
+func firstSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return "/" + segment
+}
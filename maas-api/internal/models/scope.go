@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Scope selects the MaaSModelRefs a maas-api instance serves, so several instances in one cluster,
+// e.g. behind an internal and a partner-facing gateway, each authorize only their own models. The
+// zero Scope selects every MaaSModelRef.
+type Scope struct {
+	// Namespaces the models must be in; empty allows every namespace.
+	Namespaces []string
+	// Selector the model labels must match; nil matches every model.
+	Selector labels.Selector
+	// Shards splits the model namespaces among that many instances, and Shard is the one this
+	// instance serves (see ShardOf). Shards of 0 or 1 disables sharding.
+	Shards int
+	Shard  int
+}
+
+// ShardOf returns the shard of a model namespace among shards: the FNV-1a hash of the namespace
+// modulo shards. maas-controller uses the same function to send each model's authorization
+// requests to the maas-api shard serving it.
+func ShardOf(namespace string, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// Sharded reports whether the scope serves one shard of the model namespaces.
+func (s Scope) Sharded() bool {
+	return s.Shards > 1
+}
+
+// InShard reports whether a model namespace belongs to the scope's shard.
+func (s Scope) InShard(namespace string) bool {
+	return !s.Sharded() || ShardOf(namespace, s.Shards) == s.Shard
+}
+
+// ParseScope parses a comma-separated namespace allow-list and a label selector, e.g.
+// "llm,llm-shared" and "maas.opendatahub.io/gateway=partner". Empty values select everything.
+func ParseScope(namespaces, selector string) (Scope, error) {
+	var scope Scope
+	for ns := range strings.SplitSeq(namespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" && !slices.Contains(scope.Namespaces, ns) {
+			scope.Namespaces = append(scope.Namespaces, ns)
+		}
+	}
+	if strings.TrimSpace(selector) != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			return Scope{}, fmt.Errorf("invalid label selector %q: %w", selector, err)
+		}
+		scope.Selector = parsed
+	}
+	return scope, nil
+}
+
+// All reports whether the scope selects every MaaSModelRef.
+func (s Scope) All() bool {
+	return len(s.Namespaces) == 0 && (s.Selector == nil || s.Selector.Empty()) && !s.Sharded()
+}
+
+// Contains reports whether obj is in scope.
+func (s Scope) Contains(obj metav1.Object) bool {
+	if len(s.Namespaces) > 0 && !slices.Contains(s.Namespaces, obj.GetNamespace()) {
+		return false
+	}
+	if !s.InShard(obj.GetNamespace()) {
+		return false
+	}
+	return s.Selector == nil || s.Selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// Serves reports whether obj is in scope and not ignored (see IsIgnored), that is whether the
+// instance authorizes requests to it.
+func (s Scope) Serves(obj metav1.Object) bool {
+	return s.Contains(obj) && !IsIgnored(obj)
+}
+
+// String describes the scope for logs.
+func (s Scope) String() string {
+	if s.All() {
+		return "all"
+	}
+	var parts []string
+	if len(s.Namespaces) > 0 {
+		parts = append(parts, "namespaces="+strings.Join(s.Namespaces, ","))
+	}
+	if s.Selector != nil && !s.Selector.Empty() {
+		parts = append(parts, "selector="+s.Selector.String())
+	}
+	if s.Sharded() {
+		parts = append(parts, "shard="+strconv.Itoa(s.Shard)+"/"+strconv.Itoa(s.Shards))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ServedResolver returns a function that reports whether a model ("namespace/name") has a
+// MaaSModelRef in lister. With a scoped lister, that is whether this instance serves the model and
+// the model is not ignored.
+func ServedResolver(lister MaaSModelRefLister) func(model string) bool {
+	return func(model string) bool {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return true
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		return err == nil && u != nil
+	}
+}
@@ -0,0 +1,78 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+func scopedModel(namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	u := tracedModel(name, nil)
+	u.SetNamespace(namespace)
+	u.SetLabels(labels)
+	return u
+}
+
+func TestScope(t *testing.T) {
+	partner := scopedModel("llm", "granite", map[string]string{"maas.opendatahub.io/gateway": "partner"})
+	internal := scopedModel("llm", "mistral", nil)
+	shared := scopedModel("llm-shared", "llama", map[string]string{"maas.opendatahub.io/gateway": "partner"})
+	other := scopedModel("sandbox", "phi", map[string]string{"maas.opendatahub.io/gateway": "partner"})
+
+	all, err := models.ParseScope(" , ", "")
+	require.NoError(t, err)
+	assert.True(t, all.All())
+	assert.Equal(t, "all", all.String())
+	assert.True(t, all.Contains(other))
+
+	scope, err := models.ParseScope("llm, llm-shared,llm", "maas.opendatahub.io/gateway=partner")
+	require.NoError(t, err)
+	assert.False(t, scope.All())
+	assert.Equal(t, []string{"llm", "llm-shared"}, scope.Namespaces)
+	assert.Equal(t, "namespaces=llm,llm-shared selector=maas.opendatahub.io/gateway=partner", scope.String())
+	assert.True(t, scope.Contains(partner))
+	assert.True(t, scope.Contains(shared))
+	assert.False(t, scope.Contains(internal), "labels must match")
+	assert.False(t, scope.Contains(other), "namespace must be allowed")
+
+	_, err = models.ParseScope("", "gateway in (partner")
+	assert.ErrorContains(t, err, "invalid label selector")
+}
+
+func TestScopeShard(t *testing.T) {
+	// Pinned, so a change to the hash, which maas-controller must follow, fails here.
+	assert.Equal(t, 2, models.ShardOf("llm", 3))
+	assert.Equal(t, 1, models.ShardOf("llm-shared", 3))
+	assert.Equal(t, 0, models.ShardOf("llm", 1))
+
+	scope := models.Scope{Shards: 3, Shard: 2}
+	assert.False(t, scope.All())
+	assert.Equal(t, "shard=2/3", scope.String())
+	assert.True(t, scope.Contains(scopedModel("llm", "granite", nil)))
+	assert.False(t, scope.Contains(scopedModel("llm-shared", "llama", nil)))
+}
+
+func TestServedResolver(t *testing.T) {
+	served := models.ServedResolver(modelRefs{scopedModel("llm", "granite", nil)})
+	assert.True(t, served("llm/granite"))
+	assert.False(t, served("llm/mistral"))
+}
+
+func TestScopeServesIgnored(t *testing.T) {
+	granite := scopedModel("llm", "granite", nil)
+	ignored := scopedModel("llm", "mistral", nil)
+	ignored.SetAnnotations(map[string]string{"maas.opendatahub.io/ignore": "true"})
+	notIgnored := scopedModel("llm", "llama", nil)
+	notIgnored.SetAnnotations(map[string]string{"maas.opendatahub.io/ignore": "false"})
+
+	var all models.Scope
+	assert.True(t, all.Serves(granite))
+	assert.True(t, all.Serves(notIgnored))
+	assert.True(t, all.Contains(ignored), "ignoring a model does not take it out of scope")
+	assert.False(t, all.Serves(ignored))
+	assert.False(t, models.Scope{Namespaces: []string{"llm-shared"}}.Serves(granite))
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SensitiveSpanAttributes are the span attributes identifying the caller. Spans of a model with
+// spec.tracing.stripSensitiveAttributes leave them out.
+var SensitiveSpanAttributes = []string{
+	"enduser.id",
+	"maas.user",
+	"maas.groups",
+	"maas.subscription",
+	"maas.api_key_id",
+}
+
+// Tracing is a model's spec.tracing: how maas-api traces requests for the model.
+type Tracing struct {
+	// SamplingRatio is the fraction of requests traced, from 0 to 1. It is negative when the
+	// model leaves sampling to the default.
+	SamplingRatio float64
+	// Propagate reports whether the caller's trace context is continued. When false, spans for
+	// the model start a new trace.
+	Propagate bool
+	// StripSensitiveAttributes leaves SensitiveSpanAttributes out of the model's spans.
+	StripSensitiveAttributes bool
+}
+
+// Sampled reports whether a trace is recorded for the model, deciding by the trace ID as
+// OpenTelemetry's ratio-based sampler does, so every service sampling at the same ratio keeps the
+// same traces. defaultSampled applies when the model does not set a sampling percentage.
+func (t *Tracing) Sampled(traceID [16]byte, defaultSampled bool) bool {
+	if t == nil || t.SamplingRatio < 0 {
+		return defaultSampled
+	}
+	if t.SamplingRatio >= 1 {
+		return true
+	}
+	bound := uint64(t.SamplingRatio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < bound
+}
+
+// KeepAttribute reports whether a span attribute may be recorded for the model.
+func (t *Tracing) KeepAttribute(key string) bool {
+	if t == nil || !t.StripSensitiveAttributes {
+		return true
+	}
+	for _, sensitive := range SensitiveSpanAttributes {
+		if key == sensitive {
+			return false
+		}
+	}
+	return true
+}
+
+// TracingResolver returns a function that reads a model's ("namespace/name") tracing policy from
+// the cached MaaSModelRefs. It returns nil when the model sets none, which keeps the defaults.
+func TracingResolver(lister MaaSModelRefLister) func(model string) *Tracing {
+	return func(model string) *Tracing {
+		getter, ok := lister.(MaaSModelRefGetter)
+		if !ok {
+			return nil
+		}
+		ns, name, _ := strings.Cut(model, "/")
+		u, err := getter.Get(ns, name)
+		if err != nil || u == nil {
+			return nil
+		}
+		spec, found, _ := unstructured.NestedMap(u.Object, "spec", "tracing")
+		if !found {
+			return nil
+		}
+		tracing := &Tracing{SamplingRatio: -1, Propagate: true}
+		if pct, _, _ := unstructured.NestedString(spec, "samplingPercentage"); pct != "" {
+			if value, err := strconv.ParseFloat(pct, 64); err == nil && value >= 0 && value <= 100 {
+				tracing.SamplingRatio = value / 100
+			}
+		}
+		if propagate, found, _ := unstructured.NestedBool(spec, "propagate"); found {
+			tracing.Propagate = propagate
+		}
+		tracing.StripSensitiveAttributes, _, _ = unstructured.NestedBool(spec, "stripSensitiveAttributes")
+		return tracing
+	}
+}
@@ -0,0 +1,76 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+type modelRefs []*unstructured.Unstructured
+
+func (m modelRefs) List() ([]*unstructured.Unstructured, error) { return m, nil }
+
+func (m modelRefs) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	for _, u := range m {
+		if u.GetNamespace() == namespace && u.GetName() == name {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func tracedModel(name string, tracing map[string]any) *unstructured.Unstructured {
+	spec := map[string]any{"modelRef": map[string]any{"kind": "LLMInferenceService", "name": name}}
+	if tracing != nil {
+		spec["tracing"] = tracing
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "maas.opendatahub.io/v1alpha1",
+		"kind":       "MaaSModelRef",
+		"metadata":   map[string]any{"name": name, "namespace": "llm"},
+		"spec":       spec,
+	}}
+}
+
+func TestTracingResolver(t *testing.T) {
+	resolve := models.TracingResolver(modelRefs{
+		tracedModel("plain", nil),
+		tracedModel("busy", map[string]any{"samplingPercentage": "0.5", "propagate": false, "stripSensitiveAttributes": true}),
+		tracedModel("private", map[string]any{"stripSensitiveAttributes": true}),
+	})
+
+	assert.Nil(t, resolve("llm/plain"), "no spec.tracing keeps the defaults")
+	assert.Nil(t, resolve("llm/missing"))
+
+	busy := resolve("llm/busy")
+	require.NotNil(t, busy)
+	assert.InDelta(t, 0.005, busy.SamplingRatio, 1e-12)
+	assert.False(t, busy.Propagate)
+	assert.False(t, busy.KeepAttribute("maas.user"))
+	assert.True(t, busy.KeepAttribute("maas.model"))
+
+	private := resolve("llm/private")
+	require.NotNil(t, private)
+	assert.True(t, private.Propagate, "propagate defaults to true")
+	assert.True(t, private.Sampled([16]byte{}, true), "unset sampling keeps the default decision")
+	assert.False(t, private.Sampled([16]byte{}, false))
+}
+
+func TestTracingSampled(t *testing.T) {
+	low := [16]byte{8: 0x00, 9: 0x10}
+	high := [16]byte{8: 0xff, 9: 0xff}
+	half := &models.Tracing{SamplingRatio: 0.5}
+	assert.True(t, half.Sampled(low, false))
+	assert.False(t, half.Sampled(high, true))
+
+	assert.False(t, (&models.Tracing{SamplingRatio: 0}).Sampled(low, true))
+	assert.True(t, (&models.Tracing{SamplingRatio: 1}).Sampled(high, false))
+
+	var unset *models.Tracing
+	assert.True(t, unset.Sampled(high, true))
+	assert.True(t, unset.KeepAttribute("maas.user"))
+}
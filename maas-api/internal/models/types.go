@@ -18,6 +18,15 @@ type Details struct {
 	ContextWindow string `json:"contextWindow,omitempty"`
 }
 
+// Resources is the per-replica compute footprint of a model backend, as reported by the
+// controller in MaaSModelRef status.resources.
+type Resources struct {
+	GPUType  string `json:"gpuType,omitempty"`
+	GPUCount int64  `json:"gpuCount,omitempty"`
+	Memory   string `json:"memory,omitempty"`
+	Replicas int32  `json:"replicas,omitempty"`
+}
+
 // SubscriptionInfo contains metadata about which subscription provides access to a model.
 type SubscriptionInfo struct {
 	Name        string `json:"name"`
@@ -37,11 +46,22 @@ type Model struct {
 	// Kind is the model reference kind (e.g. "llmisvc" from MaaSModelRef spec.modelRef.kind).
 	// Used when validating access; default is "llmisvc" if unset.
 	Kind          string             `json:"kind,omitempty"`
+	Class         string             `json:"class,omitempty"` // spec.class: chat, completion, embedding, reranker or audio
 	URL           *apis.URL          `json:"url,omitempty"`
 	Ready         bool               `json:"ready"`
 	Details       *Details           `json:"modelDetails,omitempty"`
 	Aliases       []string           `json:"aliases,omitempty"`
+	Resources     *Resources         `json:"resources,omitempty"`
 	Subscriptions []SubscriptionInfo `json:"subscriptions,omitempty"` // Subscriptions providing access to this model
+
+	// Parent is the base model (namespace/name) this model is a variant of, e.g. for a fine-tune.
+	Parent string `json:"parent,omitempty"`
+	// Lineage lists the ancestors (namespace/name) from the direct parent up to the base model.
+	Lineage []string `json:"lineage,omitempty"`
+	// PricingMultiplier scales the subscription billing rate for this model (inherited from the parent when unset).
+	PricingMultiplier string `json:"pricingMultiplier,omitempty"`
+	// Cluster is the cluster hosting the model when maas-api federates models from several clusters.
+	Cluster string `json:"cluster,omitempty"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshalling to work around openai.Model's
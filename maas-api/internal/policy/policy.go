@@ -0,0 +1,331 @@
+// Package policy evaluates custom authorization rules written in CEL, for rules that do not map
+// to static tier lists, such as "the free tier only outside business hours" or "contractors may
+// not use models labeled restricted". Rules come from a file, typically a mounted ConfigMap, and
+// from the spec.authorizationRule of each MaaSModelRef. A request is allowed only if every rule
+// that applies evaluates to true, on top of the built-in MaaSAuthPolicy and subscription checks.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// costLimit bounds the work of one evaluation, so a rule cannot stall authorization.
+const costLimit = 100000
+
+// modelRuleName names the spec.authorizationRule of a model in logs and metrics.
+const modelRuleName = "model"
+
+var evaluationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_authorization_rule_evaluations_total",
+	Help: "Authorization rule evaluations by rule (\"model\" for spec.authorizationRule) and result (allow, deny, error).",
+}, []string{"rule", "result"})
+
+func init() {
+	prometheus.MustRegister(evaluationsTotal)
+}
+
+// Input is what rules are evaluated with.
+type Input struct {
+	// Model is the namespace/name of the MaaSModelRef.
+	Model  string
+	User   string
+	Groups []string
+	// Tier is the name of the selected subscription.
+	Tier string
+	Path string
+	Time time.Time
+}
+
+// Verdict is the combined answer of the rules.
+type Verdict struct {
+	// Reason is reason.PolicyDenied or reason.PolicyFailed when a rule stopped the request, else "".
+	Reason  string
+	Message string
+	// Rule is the name of the rule that stopped the request.
+	Rule string
+}
+
+// Denied reports whether a rule stopped the request.
+func (v Verdict) Denied() bool {
+	return v.Reason != ""
+}
+
+// Rule is a CEL expression that must evaluate to true for a request to be allowed.
+type Rule struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	// Message is returned to the caller when the rule denies a request.
+	Message string `json:"message,omitempty"`
+
+	program cel.Program
+}
+
+// file is the rules file, typically a mounted ConfigMap:
+//
+//	rules:
+//	- name: free-tier-off-hours
+//	  expression: 'tier != "free" || time.getDayOfWeek("America/New_York") in [0, 6] || time.getHours("America/New_York") < 9 || time.getHours("America/New_York") >= 17'
+//	  message: The free tier is available outside business hours
+//	- name: restricted-models
+//	  expression: '!("contractors" in groups && model.labels[?"restricted"].orValue("") == "true")'
+type file struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Engine evaluates the rules of the file and of each model. A nil Engine allows every request.
+type Engine struct {
+	env    *cel.Env
+	rules  []*Rule
+	models models.MaaSModelRefLister
+	logger *logger.Logger
+
+	mu sync.Mutex
+	// modelRules caches the compiled spec.authorizationRule of models by expression.
+	modelRules map[string]modelRule
+}
+
+type modelRule struct {
+	program cel.Program
+	err     error
+}
+
+// NewEnv returns the CEL environment rules are compiled in. Its variables are:
+//
+//	user    string        the caller's username
+//	groups  list(string)  the caller's groups
+//	tier    string        the name of the selected subscription
+//	model   map           name, namespace, labels and annotations of the MaaSModelRef
+//	path    string        the request path ("" for access checks)
+//	time    timestamp     when the request is authorized
+//
+// The ext strings library and optional values, e.g. model.labels[?"team"].orValue(""), are
+// available; indexing a missing label or annotation fails the rule.
+func NewEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("user", cel.StringType),
+		cel.Variable("groups", cel.ListType(cel.StringType)),
+		cel.Variable("tier", cel.StringType),
+		cel.Variable("model", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("path", cel.StringType),
+		cel.Variable("time", cel.TimestampType),
+		ext.Strings(),
+		cel.OptionalTypes(),
+	)
+}
+
+// New creates an engine evaluating rules, in order, for every request. Models' own rules are
+// evaluated only once SetModels gives the engine the MaaSModelRefs.
+func New(log *logger.Logger, rules ...Rule) (*Engine, error) {
+	if log == nil {
+		log = logger.Production()
+	}
+	env, err := NewEnv()
+	if err != nil {
+		return nil, err
+	}
+	e := &Engine{env: env, logger: log, modelRules: map[string]modelRule{}}
+	names := map[string]bool{}
+	for i := range rules {
+		r := rules[i]
+		if r.Name == "" {
+			return nil, fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if r.Name == modelRuleName || names[r.Name] {
+			return nil, fmt.Errorf("rule %d: name %q is reserved or already used", i+1, r.Name)
+		}
+		names[r.Name] = true
+		if r.program, err = compile(env, r.Expression); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		e.rules = append(e.rules, &r)
+	}
+	return e, nil
+}
+
+// Load creates an engine with the rules of the file at path.
+func Load(log *logger.Logger, path string) (*Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization rules file: %w", err)
+	}
+	var f file
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse authorization rules file: %w", err)
+	}
+	return New(log, f.Rules...)
+}
+
+// compile checks that expression is a boolean CEL expression and plans it.
+func compile(env *cel.Env, expression string) (cel.Program, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("expression is required")
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must be a bool, not %s", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(costLimit))
+}
+
+// SetModels evaluates the spec.authorizationRule of the cached MaaSModelRefs, and gives rules the
+// labels and annotations of the model.
+func (e *Engine) SetModels(lister models.MaaSModelRefLister) {
+	e.models = lister
+}
+
+// Names returns the names of the file's rules in order.
+func (e *Engine) Names() []string {
+	if e == nil {
+		return nil
+	}
+	names := make([]string, len(e.rules))
+	for i, r := range e.rules {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// Evaluate evaluates the file's rules in order, then the model's own rule, stopping at the first
+// that does not evaluate to true. A rule that fails to evaluate denies the request with
+// reason.PolicyFailed.
+func (e *Engine) Evaluate(in Input) Verdict {
+	if e == nil {
+		return Verdict{}
+	}
+	u := e.model(in.Model)
+	vars := map[string]any{
+		"user":   in.User,
+		"groups": append([]string{}, in.Groups...),
+		"tier":   in.Tier,
+		"model":  modelVars(in.Model, u),
+		"path":   in.Path,
+		"time":   in.Time,
+	}
+	for _, r := range e.rules {
+		if v := e.eval(r.Name, r.program, r.Message, vars, in); v.Denied() {
+			return v
+		}
+	}
+	if u == nil {
+		return Verdict{}
+	}
+	expression := strings.TrimSpace(AuthorizationRule(u))
+	if expression == "" {
+		return Verdict{}
+	}
+	program, err := e.modelProgram(expression)
+	if err != nil {
+		evaluationsTotal.WithLabelValues(modelRuleName, "error").Inc()
+		e.logger.Error("Invalid spec.authorizationRule, denying the request", "model", in.Model, "error", err)
+		return Verdict{Reason: reason.PolicyFailed, Message: reason.Message(reason.PolicyFailed), Rule: modelRuleName}
+	}
+	return e.eval(modelRuleName, program, "", vars, in)
+}
+
+// Warm compiles the spec.authorizationRule of a namespace/name model ahead of its first request.
+// It reports whether the model has a rule, and the compile error of an invalid one.
+func (e *Engine) Warm(ref string) (bool, error) {
+	if e == nil {
+		return false, nil
+	}
+	u := e.model(ref)
+	if u == nil {
+		return false, nil
+	}
+	expression := strings.TrimSpace(AuthorizationRule(u))
+	if expression == "" {
+		return false, nil
+	}
+	_, err := e.modelProgram(expression)
+	return true, err
+}
+
+// eval evaluates one rule.
+func (e *Engine) eval(name string, program cel.Program, message string, vars map[string]any, in Input) Verdict {
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		evaluationsTotal.WithLabelValues(name, "error").Inc()
+		e.logger.Error("Authorization rule failed, denying the request", "rule", name, "model", in.Model, "user", in.User, "error", err)
+		return Verdict{Reason: reason.PolicyFailed, Message: reason.Message(reason.PolicyFailed), Rule: name}
+	}
+	if allowed, ok := out.Value().(bool); ok && allowed {
+		evaluationsTotal.WithLabelValues(name, "allow").Inc()
+		return Verdict{}
+	}
+	evaluationsTotal.WithLabelValues(name, "deny").Inc()
+	e.logger.Debug("Authorization rule denied the request", "rule", name, "model", in.Model, "user", in.User, "tier", in.Tier)
+	if message == "" {
+		message = reason.Message(reason.PolicyDenied)
+	}
+	return Verdict{Reason: reason.PolicyDenied, Message: message, Rule: name}
+}
+
+// model returns the cached MaaSModelRef of a namespace/name model, or nil.
+func (e *Engine) model(ref string) *unstructured.Unstructured {
+	getter, ok := e.models.(models.MaaSModelRefGetter)
+	if !ok {
+		return nil
+	}
+	ns, name, _ := strings.Cut(ref, "/")
+	u, err := getter.Get(ns, name)
+	if err != nil || u == nil {
+		return nil
+	}
+	return u
+}
+
+// modelProgram compiles a model's rule once per distinct expression.
+func (e *Engine) modelProgram(expression string) (cel.Program, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if r, ok := e.modelRules[expression]; ok {
+		return r.program, r.err
+	}
+	program, err := compile(e.env, expression)
+	e.modelRules[expression] = modelRule{program: program, err: err}
+	return program, err
+}
+
+// modelVars returns the model variable of a namespace/name model and its MaaSModelRef, if cached.
+func modelVars(ref string, u *unstructured.Unstructured) map[string]any {
+	ns, name, _ := strings.Cut(ref, "/")
+	labels, annotations := map[string]string{}, map[string]string{}
+	if u != nil {
+		if l := u.GetLabels(); l != nil {
+			labels = l
+		}
+		if a := u.GetAnnotations(); a != nil {
+			annotations = a
+		}
+	}
+	return map[string]any{
+		"name":        name,
+		"namespace":   ns,
+		"labels":      labels,
+		"annotations": annotations,
+	}
+}
+
+// AuthorizationRule returns the spec.authorizationRule of a MaaSModelRef, or "".
+func AuthorizationRule(u *unstructured.Unstructured) string {
+	rule, _, _ := unstructured.NestedString(u.Object, "spec", "authorizationRule")
+	return rule
+}
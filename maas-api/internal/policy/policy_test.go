@@ -0,0 +1,150 @@
+package policy_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+type modelRefs map[string]*unstructured.Unstructured
+
+func (m modelRefs) List() ([]*unstructured.Unstructured, error) {
+	items := make([]*unstructured.Unstructured, 0, len(m))
+	for _, u := range m {
+		items = append(items, u)
+	}
+	return items, nil
+}
+
+func (m modelRefs) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	if u, ok := m[namespace+"/"+name]; ok {
+		return u, nil
+	}
+	return nil, errors.NewNotFound(schema.GroupResource{Resource: "maasmodelrefs"}, name)
+}
+
+func modelRef(namespace, name string, labels map[string]string, rule string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetLabels(labels)
+	if rule != "" {
+		_ = unstructured.SetNestedField(u.Object, rule, "spec", "authorizationRule")
+	}
+	return u
+}
+
+// monday10am is a Monday at 10:00 UTC.
+var monday10am = time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+func TestEvaluate(t *testing.T) {
+	engine, err := policy.New(logger.Development(),
+		policy.Rule{
+			Name:       "free-tier-off-hours",
+			Expression: `tier != "free" || time.getDayOfWeek() in [0, 6] || time.getHours() < 9 || time.getHours() >= 17`,
+			Message:    "The free tier is available outside business hours",
+		},
+		policy.Rule{
+			Name:       "restricted-models",
+			Expression: `!("contractors" in groups && model.labels[?"restricted"].orValue("") == "true")`,
+		},
+	)
+	require.NoError(t, err)
+	engine.SetModels(modelRefs{
+		"llm/granite": modelRef("llm", "granite", nil, ""),
+		"llm/secret":  modelRef("llm", "secret", map[string]string{"restricted": "true"}, ""),
+		"llm/beta":    modelRef("llm", "beta", nil, `user.startsWith("beta-") && path.endsWith("/chat/completions")`),
+		"llm/broken":  modelRef("llm", "broken", nil, `model.labels["missing"] == "x"`),
+		"llm/invalid": modelRef("llm", "invalid", nil, `tier + 1`),
+	})
+
+	in := policy.Input{Model: "llm/granite", User: "alice", Groups: []string{"users"}, Tier: "premium",
+		Path: "/llm/granite/v1/chat/completions", Time: monday10am}
+	assert.False(t, engine.Evaluate(in).Denied())
+
+	free := in
+	free.Tier = "free"
+	assert.Equal(t, policy.Verdict{Reason: reason.PolicyDenied, Message: "The free tier is available outside business hours",
+		Rule: "free-tier-off-hours"}, engine.Evaluate(free))
+	free.Time = monday10am.Add(8 * time.Hour)
+	assert.False(t, engine.Evaluate(free).Denied())
+
+	contractor := in
+	contractor.Model, contractor.Groups = "llm/secret", []string{"users", "contractors"}
+	assert.Equal(t, policy.Verdict{Reason: reason.PolicyDenied, Message: reason.Message(reason.PolicyDenied),
+		Rule: "restricted-models"}, engine.Evaluate(contractor))
+	contractor.Model = "llm/granite"
+	assert.False(t, engine.Evaluate(contractor).Denied())
+
+	beta := in
+	beta.Model = "llm/beta"
+	assert.Equal(t, "model", engine.Evaluate(beta).Rule)
+	beta.User = "beta-bob"
+	assert.False(t, engine.Evaluate(beta).Denied())
+	beta.Path = "/llm/beta/v1/embeddings"
+	assert.Equal(t, reason.PolicyDenied, engine.Evaluate(beta).Reason)
+
+	// Rules fail closed.
+	broken := in
+	broken.Model = "llm/broken"
+	assert.Equal(t, reason.PolicyFailed, engine.Evaluate(broken).Reason)
+	broken.Model = "llm/invalid"
+	assert.Equal(t, reason.PolicyFailed, engine.Evaluate(broken).Reason)
+
+	// Models outside the cache get only the file's rules.
+	unknown := in
+	unknown.Model = "llm/unknown"
+	assert.False(t, engine.Evaluate(unknown).Denied())
+
+	var none *policy.Engine
+	assert.False(t, none.Evaluate(free).Denied())
+}
+
+func TestNewRejectsInvalidRules(t *testing.T) {
+	for name, rule := range map[string]policy.Rule{
+		"no name":       {Expression: "true"},
+		"reserved name": {Name: "model", Expression: "true"},
+		"no expression": {Name: "empty"},
+		"syntax error":  {Name: "syntax", Expression: `tier ==`},
+		"unknown var":   {Name: "unknown", Expression: `namespace == "llm"`},
+		"not a bool":    {Name: "string", Expression: `tier`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := policy.New(logger.Development(), rule)
+			assert.Error(t, err)
+		})
+	}
+	_, err := policy.New(logger.Development(), policy.Rule{Name: "a", Expression: "true"}, policy.Rule{Name: "a", Expression: "true"})
+	assert.Error(t, err)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`rules:
+- name: no-interns
+  expression: '!("interns" in groups)'
+  message: Interns may not use models
+- name: premium-only
+  expression: 'tier == "premium"'
+`), 0o600))
+	engine, err := policy.Load(logger.Development(), path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no-interns", "premium-only"}, engine.Names())
+	v := engine.Evaluate(policy.Input{Model: "llm/granite", Groups: []string{"interns"}, Tier: "premium", Time: monday10am})
+	assert.Equal(t, "Interns may not use models", v.Message)
+
+	require.NoError(t, os.WriteFile(path, []byte("rules:\n- name: typo\n  expresion: 'true'\n"), 0o600))
+	_, err = policy.Load(logger.Development(), path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package prewarm
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// Handler exposes the warmer to callers bearing the pre-warm token, for the gateway or Authorino
+// to call as they start.
+type Handler struct {
+	logger *logger.Logger
+	warmer *Warmer
+	token  string
+}
+
+// NewHandler creates a handler for the pre-warm endpoints, which callers bearing token may use.
+func NewHandler(log *logger.Logger, warmer *Warmer, token string) *Handler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if warmer == nil {
+		panic("warmer cannot be nil")
+	}
+	if token == "" {
+		panic("token cannot be empty")
+	}
+	return &Handler{logger: log, warmer: warmer, token: token}
+}
+
+// authenticated responds and returns false unless the caller bears the pre-warm token.
+func (h *Handler) authenticated(c *gin.Context) bool {
+	bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(h.token)) != 1 {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authentication required")
+		return false
+	}
+	return true
+}
+
+// Prewarm handles POST /internal/v1/authorize/prewarm. It answers 202 with a job to poll, or,
+// with ?wait=true, 200 with the snapshot once the caches are warm. An empty body warms every
+// model for every tier.
+func (h *Handler) Prewarm(c *gin.Context) {
+	if !h.authenticated(c) {
+		return
+	}
+	wait, err := strconv.ParseBool(c.DefaultQuery("wait", "false"))
+	if err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "wait must be true or false")
+		return
+	}
+	var req Request
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if err := req.Validate(); err != nil {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+		return
+	}
+
+	if !wait {
+		job, err := h.warmer.Start(req)
+		if err != nil {
+			apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, err.Error())
+			return
+		}
+		c.Header("Location", "/internal/v1/authorize/prewarm/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+		return
+	}
+	snapshot, err := h.warmer.Warm(req)
+	if err != nil {
+		h.logger.Error("Authorization cache pre-warm failed", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Pre-warm failed")
+		return
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// GetJob handles GET /internal/v1/authorize/prewarm/:id.
+func (h *Handler) GetJob(c *gin.Context) {
+	if !h.authenticated(c) {
+		return
+	}
+	job, ok := h.warmer.Job(c.Param("id"))
+	if !ok {
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "pre-warm job not found")
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
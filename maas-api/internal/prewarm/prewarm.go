@@ -0,0 +1,409 @@
+// Package prewarm warms maas-api's authorization caches for a list of models and tiers, as the
+// gateway or Authorino starts, and returns a compact snapshot of the policies that apply to them.
+// On large catalogs this moves parsing the tiers, resolving model names and compiling the models'
+// authorization rules off the first requests after a deployment.
+package prewarm
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+// Bounds on a request, so one call cannot hold a replica busy for long.
+const (
+	MaxModels = 5000
+	MaxTiers  = 500
+)
+
+// maxJobs is how many pre-warm jobs are remembered for GET by ID; older ones are forgotten.
+const maxJobs = 16
+
+// Job statuses.
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	runsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_prewarm_runs_total",
+		Help: "Authorization cache pre-warm runs, by result (success, error).",
+	}, []string{"result"})
+	runDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "maas_prewarm_duration_seconds",
+		Help:    "Duration of authorization cache pre-warm runs.",
+		Buckets: prometheus.ExponentialBuckets(0.001, 4, 8),
+	})
+)
+
+func init() {
+	prometheus.MustRegister(runsTotal, runDuration)
+}
+
+// Request names what to warm.
+type Request struct {
+	// Models are bare names, aliases or namespace/name. Empty warms every model.
+	Models []string `json:"models,omitempty"`
+	// Tiers are subscription names or namespace/name. Empty includes every tier.
+	Tiers []string `json:"tiers,omitempty"`
+}
+
+// Snapshot is the compact policy of the warmed models.
+type Snapshot struct {
+	GeneratedAt time.Time     `json:"generatedAt"`
+	Models      []ModelPolicy `json:"models"`
+	// Unresolved are requested models that match no single MaaSModelRef.
+	Unresolved []Unresolved `json:"unresolved,omitempty"`
+	// UnknownTiers are requested tiers that match no subscription.
+	UnknownTiers []string `json:"unknownTiers,omitempty"`
+}
+
+// ModelPolicy is what applies to one model.
+type ModelPolicy struct {
+	Model string `json:"model"` // namespace/name
+	// Requested is the name the model was requested by, when not its namespace/name.
+	Requested string `json:"requested,omitempty"`
+	// Tiers are the requested tiers that include the model or one of its ancestors.
+	Tiers []TierPolicy `json:"tiers"`
+	// AuthorizationRule is "valid" or "invalid" when the model has a spec.authorizationRule.
+	AuthorizationRule string `json:"authorizationRule,omitempty"`
+}
+
+// TierPolicy is the limits of a tier on a model, after MaaSRateLimitOverrides.
+type TierPolicy struct {
+	Tier                  string                          `json:"tier"` // namespace/name
+	TokenRateLimits       []subscription.TokenRateLimit   `json:"tokenRateLimits,omitempty"`
+	RequestRateLimits     []subscription.RequestRateLimit `json:"requestRateLimits,omitempty"`
+	TokenBudget           *subscription.TokenBudget       `json:"tokenBudget,omitempty"`
+	MaxConcurrentRequests int64                           `json:"maxConcurrentRequests,omitempty"`
+}
+
+// Unresolved is a requested model that was not warmed.
+type Unresolved struct {
+	Model  string `json:"model"`
+	Reason string `json:"reason"` // model_not_found or model_ambiguous
+}
+
+// Job is an asynchronous pre-warm run.
+type Job struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Snapshot   *Snapshot  `json:"snapshot,omitempty"`
+}
+
+// Warmer warms the caches authorization reads: the tier catalog, model name resolution and the
+// models' compiled authorization rules.
+type Warmer struct {
+	logger   *logger.Logger
+	lister   models.MaaSModelRefLister
+	selector *subscription.Selector
+	resolve  func(name string) (string, error)
+	aliases  func(namespace, name string) (string, string, bool)
+	policy   *policy.Engine
+	now      func() time.Time
+
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	order []string
+}
+
+// New creates a warmer of the models in lister and the tiers of selector.
+func New(log *logger.Logger, lister models.MaaSModelRefLister, selector *subscription.Selector) *Warmer {
+	if log == nil {
+		log = logger.Production()
+	}
+	if selector == nil {
+		panic("selector cannot be nil")
+	}
+	return &Warmer{
+		logger:   log,
+		lister:   lister,
+		selector: selector,
+		resolve:  models.NamespaceResolver(lister),
+		aliases:  models.AliasResolver(lister),
+		now:      time.Now,
+		jobs:     map[string]*Job{},
+	}
+}
+
+// SetModelResolver resolves bare model names with resolve, e.g. one that also fills the
+// not-found cache the authorization path reads.
+func (w *Warmer) SetModelResolver(resolve func(name string) (string, error)) {
+	w.resolve = resolve
+}
+
+// SetPolicy compiles the models' authorization rules in e.
+func (w *Warmer) SetPolicy(e *policy.Engine) {
+	w.policy = e
+}
+
+// SetClock stamps snapshots with now instead of the local clock.
+func (w *Warmer) SetClock(now func() time.Time) {
+	w.now = now
+}
+
+// Validate checks the bounds of a request.
+func (req Request) Validate() error {
+	if len(req.Models) > MaxModels {
+		return fmt.Errorf("at most %d models may be pre-warmed at once", MaxModels)
+	}
+	if len(req.Tiers) > MaxTiers {
+		return fmt.Errorf("at most %d tiers may be pre-warmed at once", MaxTiers)
+	}
+	for _, m := range req.Models {
+		if strings.TrimSpace(m) == "" {
+			return errors.New("model names must not be empty")
+		}
+	}
+	for _, t := range req.Tiers {
+		if strings.TrimSpace(t) == "" {
+			return errors.New("tier names must not be empty")
+		}
+	}
+	return nil
+}
+
+// Warm warms the caches for req and returns the snapshot.
+func (w *Warmer) Warm(req Request) (*Snapshot, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	snapshot, err := w.warm(req)
+	runDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		runsTotal.WithLabelValues("error").Inc()
+		return nil, err
+	}
+	runsTotal.WithLabelValues("success").Inc()
+	w.logger.Info("Authorization caches pre-warmed", "models", len(snapshot.Models), "unresolved", len(snapshot.Unresolved),
+		"duration", time.Since(start).String())
+	return snapshot, nil
+}
+
+func (w *Warmer) warm(req Request) (*Snapshot, error) {
+	snapshot := &Snapshot{GeneratedAt: w.now(), Models: []ModelPolicy{}}
+
+	type target struct{ ref, requested string }
+	var targets []target
+	if len(req.Models) == 0 {
+		refs, err := w.allModels()
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			targets = append(targets, target{ref: ref})
+		}
+	}
+	for _, requested := range req.Models {
+		requested = strings.TrimSpace(requested)
+		ref, code := w.resolveModel(requested)
+		if code != "" {
+			snapshot.Unresolved = append(snapshot.Unresolved, Unresolved{Model: requested, Reason: code})
+			continue
+		}
+		t := target{ref: ref}
+		if ref != requested {
+			t.requested = requested
+		}
+		targets = append(targets, t)
+	}
+
+	matchedTiers := map[string]bool{}
+	for _, t := range targets {
+		tiers, err := w.selector.TiersForModel(t.ref)
+		if err != nil {
+			return nil, err
+		}
+		mp := ModelPolicy{Model: t.ref, Requested: t.requested, Tiers: []TierPolicy{}}
+		for _, tier := range tiers {
+			requested, ok := matchTier(req.Tiers, tier)
+			if !ok {
+				continue
+			}
+			matchedTiers[requested] = true
+			key := tier + "@" + t.ref
+			tokens, requests := w.selector.EffectiveRateLimits(key)
+			mp.Tiers = append(mp.Tiers, TierPolicy{
+				Tier:                  tier,
+				TokenRateLimits:       tokens,
+				RequestRateLimits:     requests,
+				TokenBudget:           w.selector.TokenBudget(key),
+				MaxConcurrentRequests: w.selector.MaxConcurrentRequests(key),
+			})
+		}
+		if hasRule, err := w.policy.Warm(t.ref); hasRule {
+			mp.AuthorizationRule = "valid"
+			if err != nil {
+				mp.AuthorizationRule = "invalid"
+				w.logger.Warn("Invalid spec.authorizationRule found while pre-warming", "model", t.ref, "error", err)
+			}
+		}
+		snapshot.Models = append(snapshot.Models, mp)
+	}
+
+	if len(req.Tiers) > 0 {
+		known, err := w.tierKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, requested := range req.Tiers {
+			if matchedTiers[requested] {
+				continue
+			}
+			if !slices.ContainsFunc(known, func(tier string) bool { return tierMatches(requested, tier) }) {
+				snapshot.UnknownTiers = append(snapshot.UnknownTiers, requested)
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// allModels returns the namespace/name of every model that is not soft-deleted, sorted.
+func (w *Warmer) allModels() ([]string, error) {
+	if w.lister == nil {
+		return nil, nil
+	}
+	items, err := w.lister.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	refs := make([]string, 0, len(items))
+	for _, u := range items {
+		if !models.IsSoftDeleted(u) {
+			refs = append(refs, u.GetNamespace()+"/"+u.GetName())
+		}
+	}
+	slices.Sort(refs)
+	return refs, nil
+}
+
+// resolveModel returns the namespace/name of a requested model, or the reason code it was not
+// resolved.
+func (w *Warmer) resolveModel(requested string) (string, string) {
+	namespace, name, qualified := strings.Cut(requested, "/")
+	if !qualified {
+		name, namespace = requested, ""
+	}
+	if w.aliases != nil {
+		if ns, n, ok := w.aliases(namespace, name); ok {
+			return ns + "/" + n, ""
+		}
+	}
+	if qualified {
+		if getter, ok := w.lister.(models.MaaSModelRefGetter); ok {
+			if u, err := getter.Get(namespace, name); err != nil || u == nil {
+				return "", reason.ModelNotFound
+			}
+		}
+		return requested, ""
+	}
+	ns, err := w.resolve(name)
+	var ambiguous *models.AmbiguousModelError
+	switch {
+	case err == nil:
+		return ns + "/" + name, ""
+	case errors.As(err, &ambiguous):
+		return "", reason.ModelAmbiguous
+	default:
+		return "", reason.ModelNotFound
+	}
+}
+
+// tierKeys returns the namespace/name of every tier.
+func (w *Warmer) tierKeys() ([]string, error) {
+	all, err := w.selector.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(all))
+	for _, sub := range all {
+		keys = append(keys, sub.Namespace+"/"+sub.Name)
+	}
+	return keys, nil
+}
+
+// matchTier returns the requested tier that names tier (namespace/name), or tier itself when
+// every tier is requested.
+func matchTier(requested []string, tier string) (string, bool) {
+	if len(requested) == 0 {
+		return tier, true
+	}
+	for _, r := range requested {
+		if tierMatches(r, tier) {
+			return r, true
+		}
+	}
+	return "", false
+}
+
+// tierMatches reports whether requested, a subscription name or namespace/name, names tier.
+func tierMatches(requested, tier string) bool {
+	requested = strings.TrimSpace(requested)
+	if strings.Contains(requested, "/") {
+		return requested == tier
+	}
+	_, name, _ := strings.Cut(tier, "/")
+	return requested == name
+}
+
+// Start warms the caches for req in the background. The job can be read with Job until
+// maxJobs newer jobs have started.
+func (w *Warmer) Start(req Request) (Job, error) {
+	if err := req.Validate(); err != nil {
+		return Job{}, err
+	}
+	job := &Job{ID: uuid.NewString(), Status: StatusRunning, StartedAt: w.now()}
+	w.mu.Lock()
+	w.jobs[job.ID] = job
+	w.order = append(w.order, job.ID)
+	if len(w.order) > maxJobs {
+		delete(w.jobs, w.order[0])
+		w.order = w.order[1:]
+	}
+	started := *job
+	w.mu.Unlock()
+
+	go func() {
+		snapshot, err := w.Warm(req)
+		finished := w.now()
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		job.FinishedAt = &finished
+		if err != nil {
+			w.logger.Error("Authorization cache pre-warm failed", "job", job.ID, "error", err)
+			job.Status, job.Error = StatusFailed, err.Error()
+			return
+		}
+		job.Status, job.Snapshot = StatusSucceeded, snapshot
+	}()
+	return started, nil
+}
+
+// Job returns a copy of the job with id.
+func (w *Warmer) Job(id string) (Job, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	job, ok := w.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
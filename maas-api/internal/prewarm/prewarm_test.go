@@ -0,0 +1,215 @@
+package prewarm_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/policy"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/prewarm"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+type modelRefs []*unstructured.Unstructured
+
+func (m modelRefs) List() ([]*unstructured.Unstructured, error) { return m, nil }
+
+func (m modelRefs) Get(namespace, name string) (*unstructured.Unstructured, error) {
+	for _, u := range m {
+		if u.GetNamespace() == namespace && u.GetName() == name {
+			return u, nil
+		}
+	}
+	return nil, errors.NewNotFound(schema.GroupResource{Resource: "maasmodelrefs"}, name)
+}
+
+type subscriptions []*unstructured.Unstructured
+
+func (s subscriptions) List() ([]*unstructured.Unstructured, error) { return s, nil }
+
+func modelRef(namespace, name, rule string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{}}
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if rule != "" {
+		_ = unstructured.SetNestedField(u.Object, rule, "spec", "authorizationRule")
+	}
+	return u
+}
+
+func tier(name string, tokenLimit int64, models ...string) *unstructured.Unstructured {
+	sub := &unstructured.Unstructured{Object: map[string]any{}}
+	sub.SetName(name)
+	sub.SetNamespace("models-as-a-service")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{map[string]any{"name": name + "-users"}}, "spec", "owner", "groups")
+	refs := make([]any, 0, len(models))
+	for _, m := range models {
+		ns, n, _ := strings.Cut(m, "/")
+		refs = append(refs, map[string]any{
+			"name":            n,
+			"namespace":       ns,
+			"tokenRateLimits": []any{map[string]any{"limit": tokenLimit, "window": "1m"}},
+		})
+	}
+	_ = unstructured.SetNestedSlice(sub.Object, refs, "spec", "modelRefs")
+	return sub
+}
+
+func newWarmer(t *testing.T) *prewarm.Warmer {
+	t.Helper()
+	log := logger.Development()
+	lister := modelRefs{
+		modelRef("llm", "granite", ""),
+		modelRef("llm", "mistral", `tier == "premium"`),
+		modelRef("llm", "broken", `tier + 1`),
+		modelRef("llm", "shared", ""),
+		modelRef("other", "shared", ""),
+	}
+	selector := subscription.NewSelector(log, subscriptions{
+		tier("free", 1000, "llm/granite"),
+		tier("premium", 50000, "llm/granite", "llm/mistral"),
+	})
+	engine, err := policy.New(log)
+	require.NoError(t, err)
+	engine.SetModels(lister)
+	w := prewarm.New(log, lister, selector)
+	w.SetPolicy(engine)
+	w.SetClock(func() time.Time { return time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC) })
+	return w
+}
+
+func TestWarm(t *testing.T) {
+	w := newWarmer(t)
+
+	snapshot, err := w.Warm(prewarm.Request{
+		Models: []string{"granite", "llm/mistral", "llm/broken", "shared", "llm/missing"},
+		Tiers:  []string{"premium", "models-as-a-service/free", "gold"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC), snapshot.GeneratedAt)
+	require.Len(t, snapshot.Models, 3)
+
+	granite := snapshot.Models[0]
+	assert.Equal(t, "llm/granite", granite.Model)
+	assert.Equal(t, "granite", granite.Requested)
+	assert.Empty(t, granite.AuthorizationRule)
+	require.Len(t, granite.Tiers, 2)
+	assert.Equal(t, "models-as-a-service/free", granite.Tiers[0].Tier)
+	assert.Equal(t, []subscription.TokenRateLimit{{Limit: 1000, Window: "1m"}}, granite.Tiers[0].TokenRateLimits)
+	assert.Equal(t, "models-as-a-service/premium", granite.Tiers[1].Tier)
+
+	mistral := snapshot.Models[1]
+	assert.Empty(t, mistral.Requested)
+	assert.Equal(t, "valid", mistral.AuthorizationRule)
+	require.Len(t, mistral.Tiers, 1)
+	assert.Equal(t, "models-as-a-service/premium", mistral.Tiers[0].Tier)
+
+	broken := snapshot.Models[2]
+	assert.Equal(t, "invalid", broken.AuthorizationRule)
+	assert.Empty(t, broken.Tiers)
+
+	assert.Equal(t, []prewarm.Unresolved{
+		{Model: "shared", Reason: reason.ModelAmbiguous},
+		{Model: "llm/missing", Reason: reason.ModelNotFound},
+	}, snapshot.Unresolved)
+	assert.Equal(t, []string{"gold"}, snapshot.UnknownTiers)
+}
+
+func TestWarmEverything(t *testing.T) {
+	snapshot, err := newWarmer(t).Warm(prewarm.Request{})
+	require.NoError(t, err)
+	names := make([]string, 0, len(snapshot.Models))
+	for _, m := range snapshot.Models {
+		names = append(names, m.Model)
+	}
+	assert.Equal(t, []string{"llm/broken", "llm/granite", "llm/mistral", "llm/shared", "other/shared"}, names)
+	assert.Len(t, snapshot.Models[1].Tiers, 2)
+	assert.Empty(t, snapshot.Unresolved)
+	assert.Empty(t, snapshot.UnknownTiers)
+}
+
+func TestWarmRejectsOversizedRequests(t *testing.T) {
+	_, err := newWarmer(t).Warm(prewarm.Request{Models: make([]string, prewarm.MaxModels+1)})
+	assert.Error(t, err)
+	_, err = newWarmer(t).Warm(prewarm.Request{Tiers: []string{" "}})
+	assert.Error(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	h := prewarm.NewHandler(logger.Development(), newWarmer(t), "prewarm-token")
+	router.POST("/internal/v1/authorize/prewarm", h.Prewarm)
+	router.GET("/internal/v1/authorize/prewarm/:id", h.GetJob)
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer prewarm-token")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		for _, auth := range []string{"", "Bearer wrong"} {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/internal/v1/authorize/prewarm?wait=true", nil)
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code, auth)
+
+			w = httptest.NewRecorder()
+			req = httptest.NewRequest(http.MethodGet, "/internal/v1/authorize/prewarm/unknown", nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		w := do(http.MethodPost, "/internal/v1/authorize/prewarm?wait=true", `{"models":["granite"],"tiers":["free"]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var snapshot prewarm.Snapshot
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+		require.Len(t, snapshot.Models, 1)
+		assert.Len(t, snapshot.Models[0].Tiers, 1)
+	})
+
+	t.Run("async", func(t *testing.T) {
+		w := do(http.MethodPost, "/internal/v1/authorize/prewarm", "")
+		require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		var job prewarm.Job
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		assert.Equal(t, "/internal/v1/authorize/prewarm/"+job.ID, w.Header().Get("Location"))
+
+		assert.Eventually(t, func() bool {
+			w := do(http.MethodGet, "/internal/v1/authorize/prewarm/"+job.ID, "")
+			if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &job) != nil {
+				return false
+			}
+			return job.Status == prewarm.StatusSucceeded
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NotNil(t, job.Snapshot)
+		assert.Len(t, job.Snapshot.Models, 5)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/internal/v1/authorize/prewarm", `{"models":"granite"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/internal/v1/authorize/prewarm?wait=maybe", "").Code)
+		assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/internal/v1/authorize/prewarm/unknown", "").Code)
+	})
+}
@@ -0,0 +1,381 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/redis"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/warmstart"
+)
+
+// Budget is the number of tokens a user may consume under a subscription key per period.
+type Budget struct {
+	Limit  int64
+	Period string // hours or days, e.g. "24h" or "30d"
+}
+
+// BudgetResolver returns the budget under a model-scoped subscription key
+// (namespace/name@modelNamespace/modelName), or false when there is none.
+type BudgetResolver func(subscriptionKey string) (Budget, bool)
+
+var periodPattern = regexp.MustCompile(`^([1-9]\d*)(h|d)$`)
+
+// ParsePeriod parses a budget period of whole hours or days.
+func ParsePeriod(period string) (time.Duration, error) {
+	m := periodPattern.FindStringSubmatch(period)
+	if m == nil {
+		return 0, fmt.Errorf("invalid budget period %q: must be hours or days, e.g. 24h or 30d", period)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid budget period %q: %w", period, err)
+	}
+	if m[2] == "d" {
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.Duration(n) * time.Hour, nil
+}
+
+// Window returns the start and end of the period containing t. Windows are aligned to the Unix
+// epoch rather than to each user's first request, so every replica agrees on them without
+// coordination: a 1d budget resets at midnight UTC.
+func Window(t time.Time, period time.Duration) (time.Time, time.Time) {
+	ms := period.Milliseconds()
+	start := time.UnixMilli(t.UnixMilli() / ms * ms).UTC()
+	return start, start.Add(period)
+}
+
+// DailyCapResolver returns the daily token cap shared by all users of a subscription
+// (namespace/name), or 0 when it has none.
+type DailyCapResolver func(subscription string) int64
+
+// CapReachedFunc is called for every usage report that finds a subscription's daily cap reached,
+// with the time the cap resets.
+type CapReachedFunc func(ctx context.Context, subscription string, resetsAt time.Time)
+
+// dailyCapPeriod is the window of daily caps; like budget windows, it resets at midnight UTC.
+const dailyCapPeriod = 24 * time.Hour
+
+// Store keeps token counters that expire on their own.
+type Store interface {
+	// Add adds tokens to key, keeping it for ttl, and returns the new total.
+	Add(ctx context.Context, key string, tokens int64, ttl time.Duration) (int64, error)
+	// Get returns the total of key, 0 when it does not exist.
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// MemoryStore keeps counters in this replica only. With several replicas each counts only the
+// usage reported to it; use RedisStore there.
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]memoryCounter
+	lastSweep int
+	now       func() time.Time
+}
+
+type memoryCounter struct {
+	total  int64
+	expiry time.Time
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: map[string]memoryCounter{}, now: time.Now}
+}
+
+// Add implements Store.
+func (s *MemoryStore) Add(_ context.Context, key string, tokens int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	// Drop expired counters whenever the map has doubled since the last sweep.
+	if len(s.counters) >= 2*max(s.lastSweep, 512) {
+		for k, c := range s.counters {
+			if !now.Before(c.expiry) {
+				delete(s.counters, k)
+			}
+		}
+		s.lastSweep = len(s.counters)
+	}
+	c := s.counters[key]
+	if !now.Before(c.expiry) {
+		c.total = 0
+	}
+	c.total += tokens
+	c.expiry = now.Add(ttl)
+	s.counters[key] = c
+	return c.total, nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.counters[key]
+	if !ok || !s.now().Before(c.expiry) {
+		return 0, nil
+	}
+	return c.total, nil
+}
+
+var _ warmstart.Part = (*MemoryStore)(nil)
+
+// Entries implements warmstart.Part, returning the live counters.
+func (s *MemoryStore) Entries() []warmstart.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	entries := make([]warmstart.Entry, 0, len(s.counters))
+	for key, c := range s.counters {
+		if now.Before(c.expiry) {
+			entries = append(entries, warmstart.Entry{Key: key, Value: c.total, Expiry: c.expiry})
+		}
+	}
+	return entries
+}
+
+// Merge implements warmstart.Part. Totals are added to the live counters of the same key, since
+// they count usage reported to another replica; the later expiry is kept.
+func (s *MemoryStore) Merge(entries []warmstart.Entry) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	merged := 0
+	for _, e := range entries {
+		if !now.Before(e.Expiry) {
+			continue
+		}
+		c := s.counters[e.Key]
+		if !now.Before(c.expiry) {
+			c = memoryCounter{}
+		}
+		c.total += e.Value
+		if e.Expiry.After(c.expiry) {
+			c.expiry = e.Expiry
+		}
+		s.counters[e.Key] = c
+		merged++
+	}
+	return merged
+}
+
+const redisKeyPrefix = "maas:quota:"
+
+// addScript increments the counter and renews its expiry in one round trip.
+const addScript = `
+local total = redis.call('INCRBY', KEYS[1], ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return total
+`
+
+// RedisStore keeps counters in Redis, so usage reported to any replica counts on all of them.
+type RedisStore struct {
+	client *redis.Client
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a store in the Redis at a redis:// or rediss:// URL.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	client, err := redis.NewClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: client}, nil
+}
+
+// Add implements Store.
+func (s *RedisStore) Add(ctx context.Context, key string, tokens int64, ttl time.Duration) (int64, error) {
+	reply, err := s.client.Do(ctx, "EVAL", addScript, "1", redisKeyPrefix+key,
+		strconv.FormatInt(tokens, 10), strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	if err != nil {
+		return 0, err
+	}
+	total, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected reply %v to quota script", reply)
+	}
+	return total, nil
+}
+
+// Get implements Store.
+func (s *RedisStore) Get(ctx context.Context, key string) (int64, error) {
+	reply, err := s.client.Do(ctx, "GET", redisKeyPrefix+key)
+	if err != nil {
+		return 0, err
+	}
+	switch v := reply.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("redis: unexpected reply %v to GET", reply)
+	}
+}
+
+// Usage is a user's consumption of a budget in the current window.
+type Usage struct {
+	Used     int64
+	Limit    int64
+	ResetsAt time.Time
+}
+
+// Tracker counts the tokens each user consumes under budgeted subscription keys and reports
+// when a budget is spent.
+type Tracker struct {
+	store      Store
+	budgets    BudgetResolver
+	dailyCaps  DailyCapResolver
+	capReached CapReachedFunc
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewTracker creates a tracker keeping counters in store for the budgets resolve returns.
+func NewTracker(log *logger.Logger, store Store, budgets BudgetResolver) *Tracker {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Tracker{store: store, budgets: budgets, logger: log, now: time.Now}
+}
+
+// SetClock places usage in windows by now instead of the local clock.
+func (t *Tracker) SetClock(now func() time.Time) {
+	t.now = now
+}
+
+// SetDailyCaps also counts the tokens of all users of a subscription together against the daily
+// cap caps returns, denying requests once it is reached. reached, if not nil, is told about each
+// report that finds the cap reached.
+func (t *Tracker) SetDailyCaps(caps DailyCapResolver, reached CapReachedFunc) {
+	t.dailyCaps = caps
+	t.capReached = reached
+}
+
+// budget returns the budget under subscriptionKey and its current window.
+func (t *Tracker) budget(subscriptionKey string) (Budget, time.Time, time.Time, bool) {
+	budget, ok := t.budgets(subscriptionKey)
+	if !ok {
+		return Budget{}, time.Time{}, time.Time{}, false
+	}
+	period, err := ParsePeriod(budget.Period)
+	if err != nil {
+		t.logger.Warn("Ignoring token budget", "subscription", subscriptionKey, "error", err)
+		return Budget{}, time.Time{}, time.Time{}, false
+	}
+	start, end := Window(t.now(), period)
+	return budget, start, end, true
+}
+
+// dailyCap returns the daily cap of the subscription of a model-scoped subscriptionKey, and the
+// subscription.
+func (t *Tracker) dailyCap(subscriptionKey string) (int64, string) {
+	if t.dailyCaps == nil {
+		return 0, ""
+	}
+	subscription, _, _ := strings.Cut(subscriptionKey, "@")
+	return t.dailyCaps(subscription), subscription
+}
+
+func counterKey(username, subscriptionKey string, start time.Time) string {
+	return username + ":" + subscriptionKey + ":" + strconv.FormatInt(start.Unix(), 10)
+}
+
+// dailyCapKey cannot collide with counterKey, since subscription has no "@".
+func dailyCapKey(subscription string, start time.Time) string {
+	return "daily:" + subscription + ":" + strconv.FormatInt(start.Unix(), 10)
+}
+
+// recordDailyCap adds tokens to the consumption of all users of the subscription of
+// subscriptionKey. It returns false when the subscription has no daily cap.
+func (t *Tracker) recordDailyCap(ctx context.Context, subscriptionKey string, tokens int64) (Usage, bool, error) {
+	limit, subscription := t.dailyCap(subscriptionKey)
+	if limit <= 0 {
+		return Usage{}, false, nil
+	}
+	start, end := Window(t.now(), dailyCapPeriod)
+	used, err := t.store.Add(ctx, dailyCapKey(subscription, start), tokens, end.Sub(t.now())+time.Minute)
+	if err != nil {
+		return Usage{}, true, fmt.Errorf("failed to record daily token usage: %w", err)
+	}
+	if used >= limit && t.capReached != nil {
+		t.capReached(ctx, subscription, end)
+	}
+	return Usage{Used: used, Limit: limit, ResetsAt: end}, true, nil
+}
+
+// Record adds tokens to the user's consumption under subscriptionKey, and to the consumption of
+// all users when the subscription has a daily cap. It returns false when the subscription has
+// neither a budget for the model nor a daily cap, in which case nothing is counted. The usage
+// returned is the user's budget, or the daily cap when there is no budget.
+func (t *Tracker) Record(ctx context.Context, username, subscriptionKey string, tokens int64) (Usage, bool, error) {
+	capUsage, capped, err := t.recordDailyCap(ctx, subscriptionKey, tokens)
+	if err != nil {
+		return Usage{}, true, err
+	}
+	budget, start, end, ok := t.budget(subscriptionKey)
+	if !ok {
+		return capUsage, capped, nil
+	}
+	// Keep the counter a minute past the window, so replicas whose clocks lag still find it.
+	used, err := t.store.Add(ctx, counterKey(username, subscriptionKey, start), tokens, end.Sub(t.now())+time.Minute)
+	if err != nil {
+		return Usage{}, true, fmt.Errorf("failed to record token usage: %w", err)
+	}
+	return Usage{Used: used, Limit: budget.Limit, ResetsAt: end}, true, nil
+}
+
+// BudgetExhausted returns a message and the time until the budget resets when the user has
+// consumed the budget under subscriptionKey, or the users of the subscription its daily cap, or ""
+// when requests may proceed. Store failures never block a request; they are logged and the
+// request proceeds.
+func (t *Tracker) BudgetExhausted(ctx context.Context, username, subscriptionKey string) (string, time.Duration) {
+	if t == nil {
+		return "", 0
+	}
+	if message, resetsIn := t.dailyCapExhausted(ctx, subscriptionKey); message != "" {
+		return message, resetsIn
+	}
+	budget, start, end, ok := t.budget(subscriptionKey)
+	if !ok {
+		return "", 0
+	}
+	used, err := t.store.Get(ctx, counterKey(username, subscriptionKey, start))
+	if err != nil {
+		t.logger.Warn("Token budget lookup failed, allowing request", "error", err, "subscription", subscriptionKey)
+		return "", 0
+	}
+	if used < budget.Limit {
+		return "", 0
+	}
+	return fmt.Sprintf("token budget of %d tokens per %s is exhausted; it resets at %s",
+		budget.Limit, budget.Period, end.UTC().Format(time.RFC3339)), end.Sub(t.now())
+}
+
+// dailyCapExhausted is BudgetExhausted for the daily cap of the subscription of subscriptionKey.
+func (t *Tracker) dailyCapExhausted(ctx context.Context, subscriptionKey string) (string, time.Duration) {
+	limit, subscription := t.dailyCap(subscriptionKey)
+	if limit <= 0 {
+		return "", 0
+	}
+	start, end := Window(t.now(), dailyCapPeriod)
+	used, err := t.store.Get(ctx, dailyCapKey(subscription, start))
+	if err != nil {
+		t.logger.Warn("Daily token cap lookup failed, allowing request", "error", err, "subscription", subscription)
+		return "", 0
+	}
+	if used < limit {
+		return "", 0
+	}
+	return fmt.Sprintf("daily token cap of %d tokens for subscription %s is reached; it resets at %s",
+		limit, subscription, end.UTC().Format(time.RFC3339)), end.Sub(t.now())
+}
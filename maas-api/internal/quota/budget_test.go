@@ -0,0 +1,216 @@
+package quota_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		period  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{period: "1h", want: time.Hour},
+		{period: "24h", want: 24 * time.Hour},
+		{period: "30d", want: 30 * 24 * time.Hour},
+		{period: "0d", wantErr: true},
+		{period: "30m", wantErr: true},
+		{period: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			got, err := quota.ParsePeriod(tt.period)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func budgets(subscriptionKey string) (quota.Budget, bool) {
+	if subscriptionKey == subKey {
+		return quota.Budget{Limit: 1000, Period: "1d"}, true
+	}
+	return quota.Budget{}, false
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tracker := quota.NewTracker(logger.Development(), quota.NewMemoryStore(), budgets)
+	tracker.SetClock(func() time.Time { return now })
+
+	usage, budgeted, err := tracker.Record(ctx, "alice", subKey, 600)
+	require.NoError(t, err)
+	require.True(t, budgeted)
+	assert.Equal(t, quota.Usage{Used: 600, Limit: 1000, ResetsAt: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)}, usage)
+	message, _ := tracker.BudgetExhausted(ctx, "alice", subKey)
+	assert.Empty(t, message, "600 of 1000 tokens used")
+
+	_, _, err = tracker.Record(ctx, "alice", subKey, 400)
+	require.NoError(t, err)
+	message, retryAfter := tracker.BudgetExhausted(ctx, "alice", subKey)
+	assert.Equal(t, "token budget of 1000 tokens per 1d is exhausted; it resets at 2026-03-11T00:00:00Z", message)
+	assert.Equal(t, 9*time.Hour, retryAfter)
+
+	message, _ = tracker.BudgetExhausted(ctx, "bob", subKey)
+	assert.Empty(t, message, "budgets are per user")
+
+	_, budgeted, err = tracker.Record(ctx, "alice", "models-as-a-service/free@llm/granite", 5000)
+	require.NoError(t, err)
+	assert.False(t, budgeted, "subscription without a budget")
+
+	// The next day is a new window.
+	now = now.Add(10 * time.Hour)
+	message, _ = tracker.BudgetExhausted(ctx, "alice", subKey)
+	assert.Empty(t, message, "budget resets at midnight UTC")
+}
+
+func TestTrackerDailyCap(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tracker := quota.NewTracker(logger.Development(), quota.NewMemoryStore(), budgets)
+	tracker.SetClock(func() time.Time { return now })
+	var reached []time.Time
+	tracker.SetDailyCaps(func(subscription string) int64 {
+		if subscription == "models-as-a-service/premium" {
+			return 1500
+		}
+		return 0
+	}, func(_ context.Context, subscription string, resetsAt time.Time) {
+		assert.Equal(t, "models-as-a-service/premium", subscription)
+		reached = append(reached, resetsAt)
+	})
+
+	// The cap is shared by all users and models of the subscription.
+	_, _, err := tracker.Record(ctx, "alice", subKey, 900)
+	require.NoError(t, err)
+	usage, budgeted, err := tracker.Record(ctx, "bob", "models-as-a-service/premium@llm/mistral", 400)
+	require.NoError(t, err)
+	require.True(t, budgeted, "the daily cap counts models without a budget")
+	assert.Equal(t, quota.Usage{Used: 1300, Limit: 1500, ResetsAt: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)}, usage)
+	assert.Empty(t, reached)
+	message, _ := tracker.BudgetExhausted(ctx, "carol", subKey)
+	assert.Empty(t, message)
+
+	_, _, err = tracker.Record(ctx, "carol", subKey, 200)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)}, reached)
+	message, retryAfter := tracker.BudgetExhausted(ctx, "dave", "models-as-a-service/premium@llm/mistral")
+	assert.Equal(t, "daily token cap of 1500 tokens for subscription models-as-a-service/premium is reached; it resets at 2026-03-11T00:00:00Z", message)
+	assert.Equal(t, 9*time.Hour, retryAfter)
+
+	now = now.Add(10 * time.Hour)
+	message, _ = tracker.BudgetExhausted(ctx, "dave", subKey)
+	assert.Empty(t, message, "the daily cap resets at midnight UTC")
+}
+
+type failingStore struct{}
+
+func (failingStore) Add(context.Context, string, int64, time.Duration) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (failingStore) Get(context.Context, string) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestTrackerStoreFailures(t *testing.T) {
+	tracker := quota.NewTracker(logger.Development(), failingStore{}, budgets)
+
+	_, _, err := tracker.Record(context.Background(), "alice", subKey, 10)
+	require.Error(t, err)
+	message, _ := tracker.BudgetExhausted(context.Background(), "alice", subKey)
+	assert.Empty(t, message, "lookup failures must not block requests")
+}
+
+// fakeRedis implements GET and the quota script's INCRBY on a single connection, ignoring TTLs.
+func fakeRedis(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		totals := map[string]int64{}
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			args := make([]string, n)
+			for i := range args {
+				header, _ := r.ReadString('\n')
+				size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+				arg := make([]byte, size+2)
+				if _, err := io.ReadFull(r, arg); err != nil {
+					return
+				}
+				args[i] = string(arg[:size])
+			}
+			var reply string
+			switch args[0] {
+			case "EVAL":
+				tokens, _ := strconv.ParseInt(args[4], 10, 64)
+				totals[args[3]] += tokens
+				reply = ":" + strconv.FormatInt(totals[args[3]], 10) + "\r\n"
+			case "GET":
+				total, ok := totals[args[1]]
+				reply = "$-1\r\n"
+				if ok {
+					s := strconv.FormatInt(total, 10)
+					reply = "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+				}
+			default:
+				reply = "-ERR unknown command\r\n"
+			}
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	s, err := quota.NewRedisStore("redis://" + fakeRedis(t))
+	require.NoError(t, err)
+
+	total, err := s.Get(ctx, "alice")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	_, err = s.Add(ctx, "alice", 40, time.Hour)
+	require.NoError(t, err)
+	total, err = s.Add(ctx, "alice", 2, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+
+	total, err = s.Get(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), total)
+}
@@ -0,0 +1,165 @@
+package quota
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+// UsageReport is the body of POST /v1/usage, sent by the gateway's quota filter after each
+// response with the usage object of the OpenAI response.
+type UsageReport struct {
+	User            string   `binding:"required" json:"user"`
+	Groups          []string `json:"groups,omitempty"` // the user's groups, for usage reports by team
+	SubscriptionKey string   `binding:"required" json:"subscriptionKey"`
+	Usage           struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+		TotalTokens      int64 `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Ledger keeps the usage history behind chargeback reports.
+type Ledger interface {
+	Add(ctx context.Context, record usage.Record) error
+}
+
+// Handler ingests token usage reports.
+type Handler struct {
+	tracker      *Tracker
+	ledger       Ledger
+	limiter      *RateLimiter
+	class        func(model string) string
+	organization func(subscriptionKey string) string
+	token        string
+	logger       *logger.Logger
+}
+
+// NewHandler creates a handler for POST /v1/usage that accepts reports bearing token.
+func NewHandler(log *logger.Logger, tracker *Tracker, token string) *Handler {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Handler{tracker: tracker, token: token, logger: log}
+}
+
+// SetLedger also records every report in ledger, budgeted or not.
+func (h *Handler) SetLedger(ledger Ledger) {
+	h.ledger = ledger
+}
+
+// SetRateLimiter also counts every report against the token rate limits limiter enforces.
+func (h *Handler) SetRateLimiter(limiter *RateLimiter) {
+	h.limiter = limiter
+}
+
+// SetOrganizationResolver records the organization resolve returns for each report's subscription
+// key in the ledger, so legal holds on an organization keep its usage.
+func (h *Handler) SetOrganizationResolver(resolve func(subscriptionKey string) string) {
+	h.organization = resolve
+}
+
+// SetClassResolver bills models whose class resolve returns as input-only (embedding and
+// reranker, see models.InputOnly) for their prompt tokens alone.
+func (h *Handler) SetClassResolver(resolve func(model string) string) {
+	h.class = resolve
+}
+
+// IngestUsage handles POST /v1/usage. Without a ledger, reports for subscriptions without a
+// budget for the model are accepted and ignored.
+func (h *Handler) IngestUsage(c *gin.Context) {
+	bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(h.token)) != 1 {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authentication required")
+		return
+	}
+
+	var report UsageReport
+	if err := c.ShouldBindJSON(&report); err != nil {
+		invalidReport(c, "invalid request body: "+err.Error())
+		return
+	}
+	u := report.Usage
+	if u.PromptTokens < 0 || u.CompletionTokens < 0 || u.TotalTokens < 0 {
+		invalidReport(c, "token counts must not be negative")
+		return
+	}
+	tokens := u.TotalTokens
+	if tokens == 0 {
+		tokens = u.PromptTokens + u.CompletionTokens
+	}
+	if h.class != nil {
+		if _, model := usage.SplitSubscriptionKey(report.SubscriptionKey); models.InputOnly(h.class(model)) {
+			// A report with only a total has no output tokens to leave out.
+			if u.PromptTokens > 0 || u.CompletionTokens > 0 {
+				tokens = u.PromptTokens
+			}
+			u.CompletionTokens = 0
+		}
+	}
+
+	spent, budgeted, err := h.tracker.Record(c.Request.Context(), report.User, report.SubscriptionKey, tokens)
+	if err != nil {
+		h.logger.Error("Failed to record token usage", "error", err, "subscription", report.SubscriptionKey)
+		apierror.Respond(c, http.StatusServiceUnavailable, apierror.Unavailable, "Failed to record usage")
+		return
+	}
+	if h.limiter != nil {
+		if err := h.limiter.RecordTokens(c.Request.Context(), report.User, report.SubscriptionKey, tokens); err != nil {
+			h.logger.Error("Failed to record token usage against rate limits", "error", err, "subscription", report.SubscriptionKey)
+			apierror.Respond(c, http.StatusServiceUnavailable, apierror.Unavailable, "Failed to record usage")
+			return
+		}
+	}
+	if h.ledger != nil {
+		subscription, model := usage.SplitSubscriptionKey(report.SubscriptionKey)
+		var organization string
+		if h.organization != nil {
+			organization = h.organization(report.SubscriptionKey)
+		}
+		err := h.ledger.Add(c.Request.Context(), usage.Record{
+			Time:             h.tracker.now(),
+			User:             report.User,
+			Groups:           report.Groups,
+			Model:            model,
+			Subscription:     subscription,
+			Organization:     organization,
+			PromptTokens:     u.PromptTokens,
+			CompletionTokens: u.CompletionTokens,
+			TotalTokens:      tokens,
+		})
+		if err != nil {
+			h.logger.Error("Failed to record usage in the ledger", "error", err, "subscription", report.SubscriptionKey)
+			apierror.Respond(c, http.StatusServiceUnavailable, apierror.Unavailable, "Failed to record usage")
+			return
+		}
+	}
+	if !budgeted {
+		if h.ledger != nil {
+			c.JSON(http.StatusOK, gin.H{"status": "recorded"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ignored"})
+		return
+	}
+	h.logger.Debug("Recorded token usage", "subscription", report.SubscriptionKey, "tokens", tokens, "used", spent.Used)
+	c.JSON(http.StatusOK, gin.H{
+		"status":   "recorded",
+		"used":     spent.Used,
+		"limit":    spent.Limit,
+		"resetsAt": spent.ResetsAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func invalidReport(c *gin.Context, message string) {
+	apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, message)
+}
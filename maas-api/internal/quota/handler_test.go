@@ -0,0 +1,183 @@
+package quota_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+const ingestToken = "gateway-token"
+
+func TestIngestUsage(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC)
+	tracker := quota.NewTracker(logger.Development(), quota.NewMemoryStore(), budgets)
+	tracker.SetClock(func() time.Time { return now })
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/v1/usage", quota.NewHandler(logger.Development(), tracker, ingestToken).IngestUsage)
+
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/v1/usage", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		wantStatus int
+		want       map[string]any
+	}{
+		{
+			name:       "missing token",
+			body:       `{"user":"alice","subscriptionKey":"` + subKey + `","usage":{"total_tokens":10}}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "wrong token",
+			token:      "guess",
+			body:       `{"user":"alice","subscriptionKey":"` + subKey + `","usage":{"total_tokens":10}}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing user",
+			token:      ingestToken,
+			body:       `{"subscriptionKey":"` + subKey + `","usage":{"total_tokens":10}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "negative tokens",
+			token:      ingestToken,
+			body:       `{"user":"alice","subscriptionKey":"` + subKey + `","usage":{"total_tokens":-10}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "total tokens",
+			token:      ingestToken,
+			body:       `{"user":"alice","subscriptionKey":"` + subKey + `","usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`,
+			wantStatus: http.StatusOK,
+			want:       map[string]any{"status": "recorded", "used": float64(30), "limit": float64(1000), "resetsAt": "2026-03-11T00:00:00Z"},
+		},
+		{
+			name:       "prompt and completion tokens only",
+			token:      ingestToken,
+			body:       `{"user":"alice","subscriptionKey":"` + subKey + `","usage":{"prompt_tokens":5,"completion_tokens":7}}`,
+			wantStatus: http.StatusOK,
+			want:       map[string]any{"status": "recorded", "used": float64(42), "limit": float64(1000), "resetsAt": "2026-03-11T00:00:00Z"},
+		},
+		{
+			name:       "subscription without a budget",
+			token:      ingestToken,
+			body:       `{"user":"alice","subscriptionKey":"models-as-a-service/free@llm/granite","usage":{"total_tokens":10}}`,
+			wantStatus: http.StatusOK,
+			want:       map[string]any{"status": "ignored"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(tt.token, tt.body)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.want != nil {
+				var got map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIngestUsageLedger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 20, 0, 0, time.UTC)
+	tracker := quota.NewTracker(logger.Development(), quota.NewMemoryStore(), budgets)
+	tracker.SetClock(func() time.Time { return now })
+	ledger := usage.NewMemoryStore()
+	h := quota.NewHandler(logger.Development(), tracker, ingestToken)
+	h.SetLedger(ledger)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/v1/usage", h.IngestUsage)
+
+	for _, body := range []string{
+		`{"user":"alice","groups":["finance"],"subscriptionKey":"` + subKey + `","usage":{"prompt_tokens":10,"completion_tokens":20,"total_tokens":30}}`,
+		`{"user":"alice","groups":["finance"],"subscriptionKey":"models-as-a-service/free@llm/granite","usage":{"prompt_tokens":1,"completion_tokens":2}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/usage", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+ingestToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(t, "recorded", got["status"], "reports without a budget are recorded in the ledger")
+	}
+
+	rows, err := ledger.Query(context.Background(), usage.Query{
+		From:    now.Add(-time.Hour),
+		To:      now.Add(time.Hour),
+		GroupBy: []usage.Dimension{usage.DimensionTeam, usage.DimensionTier, usage.DimensionModel},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []usage.Row{
+		{Team: "finance", Tier: "models-as-a-service/free", Model: "llm/granite", Requests: 1, PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3},
+		{Team: "finance", Tier: "models-as-a-service/premium", Model: "llm/granite", Requests: 1, PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30},
+	}, rows)
+}
+
+func TestIngestUsageInputOnly(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 20, 0, 0, time.UTC)
+	tracker := quota.NewTracker(logger.Development(), quota.NewMemoryStore(), budgets)
+	tracker.SetClock(func() time.Time { return now })
+	ledger := usage.NewMemoryStore()
+	h := quota.NewHandler(logger.Development(), tracker, ingestToken)
+	h.SetLedger(ledger)
+	h.SetClassResolver(func(model string) string {
+		if model == "llm/embed" {
+			return models.ClassEmbedding
+		}
+		return ""
+	})
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/v1/usage", h.IngestUsage)
+
+	for _, body := range []string{
+		`{"user":"alice","subscriptionKey":"models-as-a-service/free@llm/embed","usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+		`{"user":"alice","subscriptionKey":"models-as-a-service/free@llm/granite","usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/usage", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+ingestToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+
+	rows, err := ledger.Query(context.Background(), usage.Query{
+		From:    now.Add(-time.Hour),
+		To:      now.Add(time.Hour),
+		GroupBy: []usage.Dimension{usage.DimensionModel},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []usage.Row{
+		{Model: "llm/embed", Requests: 1, PromptTokens: 10, TotalTokens: 10},
+		{Model: "llm/granite", Requests: 1, PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, rows, "embedding models are billed for input tokens only")
+}
@@ -0,0 +1,177 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// userIDVariable is the TokenRateLimitPolicy counter expression generated by maas-controller.
+	userIDVariable = "auth.identity.userid"
+
+	// countersTTL is how long the counters of the limits namespace are reused. Warnings and the
+	// remaining-token header tolerate that staleness, and each selection would otherwise read
+	// every counter of the gateway.
+	countersTTL = 2 * time.Second
+	// countersTimeout bounds a read of the counters, so selection never waits long on a warning.
+	countersTimeout = 500 * time.Millisecond
+	// countersBackoff is how long lookups fail at once after Limitador did not answer.
+	countersBackoff = 5 * time.Second
+)
+
+// Counter is one user's consumption of a single token rate limit.
+type Counter struct {
+	MaxValue  int64
+	Remaining int64
+	Seconds   int64
+}
+
+// UsageSource returns the live counters for a user's token limits under a model-scoped
+// subscription key (namespace/name@modelNamespace/modelName).
+type UsageSource interface {
+	Counters(ctx context.Context, username, subscriptionKey string) ([]Counter, error)
+}
+
+// LimitadorSource reads counters from the Limitador HTTP API (GET /counters/{namespace}). The
+// counters of the namespace are read by one request at a time and shared for countersTTL.
+type LimitadorSource struct {
+	baseURL   string
+	namespace string
+	client    *http.Client
+	now       func() time.Time
+	group     singleflight.Group
+
+	mu        sync.Mutex
+	counters  []limitadorCounter
+	fetchedAt time.Time
+	failedAt  time.Time
+	err       error
+}
+
+// NewLimitadorSource creates a UsageSource backed by Limitador. namespace is the limits
+// namespace Kuadrant uses for the MaaS gateway, usually "<gateway-namespace>/<gateway-name>".
+func NewLimitadorSource(baseURL, namespace string) *LimitadorSource {
+	return &LimitadorSource{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		namespace: namespace,
+		client:    &http.Client{Timeout: countersTimeout},
+		now:       time.Now,
+	}
+}
+
+// SetClock reads the time from now instead of the local clock.
+func (s *LimitadorSource) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+type limitadorCounter struct {
+	Limit struct {
+		MaxValue   int64    `json:"max_value"`
+		Seconds    int64    `json:"seconds"`
+		Conditions []string `json:"conditions"`
+	} `json:"limit"`
+	SetVariables map[string]string `json:"set_variables"`
+	Remaining    *int64            `json:"remaining"`
+}
+
+// Counters implements UsageSource.
+func (s *LimitadorSource) Counters(ctx context.Context, username, subscriptionKey string) ([]Counter, error) {
+	raw, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []Counter
+	for _, c := range raw {
+		if c.Remaining == nil || c.SetVariables[userIDVariable] != username || !conditionsMention(c.Limit.Conditions, subscriptionKey) {
+			continue
+		}
+		out = append(out, Counter{MaxValue: c.Limit.MaxValue, Remaining: *c.Remaining, Seconds: c.Limit.Seconds})
+	}
+	return out, nil
+}
+
+// snapshot returns the counters of the namespace, read at most countersTTL ago. After a failed
+// read it returns the error until countersBackoff has passed.
+func (s *LimitadorSource) snapshot(ctx context.Context) ([]limitadorCounter, error) {
+	s.mu.Lock()
+	now := s.now()
+	if now.Sub(s.fetchedAt) < countersTTL {
+		counters := s.counters
+		s.mu.Unlock()
+		return counters, nil
+	}
+	if now.Sub(s.failedAt) < countersBackoff {
+		err := s.err
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.mu.Unlock()
+
+	// The read runs on its own context so that a caller giving up does not fail it for the others.
+	select {
+	case res := <-s.group.DoChan("counters", func() (any, error) { return s.fetch() }):
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]limitadorCounter), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *LimitadorSource) fetch() ([]limitadorCounter, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), countersTimeout)
+	defer cancel()
+	counters, err := s.read(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failedAt, s.err = s.now(), err
+		return nil, err
+	}
+	s.counters, s.fetchedAt = counters, s.now()
+	return counters, nil
+}
+
+func (s *LimitadorSource) read(ctx context.Context) ([]limitadorCounter, error) {
+	endpoint := s.baseURL + "/counters/" + url.PathEscape(s.namespace)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build limitador request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query limitador: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("limitador returned status %d", resp.StatusCode)
+	}
+
+	var raw []limitadorCounter
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode limitador counters: %w", err)
+	}
+	return raw, nil
+}
+
+// conditionsMention reports whether any limit condition references the quoted subscription key,
+// matching the selected_subscription_key predicate maas-controller writes into each limit.
+func conditionsMention(conditions []string, subscriptionKey string) bool {
+	quoted := `"` + subscriptionKey + `"`
+	for _, cond := range conditions {
+		if strings.Contains(cond, quoted) {
+			return true
+		}
+	}
+	return false
+}
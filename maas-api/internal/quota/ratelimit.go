@@ -0,0 +1,137 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Rate limit backends, selected with RATE_LIMIT_BACKEND. maas-controller's --rate-limit-backend
+// must name the same one.
+const (
+	// RateLimitBackendLimitador enforces subscription limits in Limitador through the Kuadrant
+	// policies maas-controller generates.
+	RateLimitBackendLimitador = "limitador"
+	// RateLimitBackendEnvoyRLS enforces them in an Envoy global rate limit service configured by
+	// maas-controller.
+	RateLimitBackendEnvoyRLS = "envoy-rls"
+	// RateLimitBackendRedis enforces them in the ext_authz evaluator with a RateLimiter counting
+	// in Redis.
+	RateLimitBackendRedis = "redis"
+)
+
+// ValidateRateLimitBackend checks that backend is limitador, envoy-rls or redis.
+func ValidateRateLimitBackend(backend string) error {
+	if !slices.Contains([]string{RateLimitBackendLimitador, RateLimitBackendEnvoyRLS, RateLimitBackendRedis}, backend) {
+		return fmt.Errorf("invalid rate limit backend %q: must be limitador, envoy-rls or redis", backend)
+	}
+	return nil
+}
+
+// Rate is a rate limit: Limit requests or tokens per Window.
+type Rate struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// RateLimitResolver returns the request and token rate limits under a model-scoped subscription
+// key (namespace/name@modelNamespace/modelName). Both are empty when there are none.
+type RateLimitResolver func(subscriptionKey string) (requests, tokens []Rate)
+
+// RateLimiter enforces the request and token rate limits of subscriptions per user, as the
+// TokenRateLimitPolicies and RateLimitPolicies maas-controller generates do in Limitador. Windows
+// are aligned to the Unix epoch like budget windows. Requests are counted when admitted; tokens
+// when the gateway reports a response's usage, so a request is only denied once an earlier one
+// used up a token limit.
+type RateLimiter struct {
+	store  Store
+	limits RateLimitResolver
+	now    func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter counting in store.
+func NewRateLimiter(store Store, limits RateLimitResolver) *RateLimiter {
+	return &RateLimiter{store: store, limits: limits, now: time.Now}
+}
+
+// SetClock replaces the clock windows are computed from.
+func (l *RateLimiter) SetClock(now func() time.Time) {
+	l.now = now
+}
+
+// rateLimitKey is the counter of a user's kind (requests or tokens) under a subscription key in
+// the window starting at start.
+func rateLimitKey(kind string, window time.Duration, start time.Time, username, subscriptionKey string) string {
+	return "ratelimit:" + kind + ":" + strconv.FormatInt(int64(window/time.Second), 10) + ":" +
+		strconv.FormatInt(start.Unix(), 10) + ":" + username + "|" + subscriptionKey
+}
+
+// Admit counts a request of username under subscriptionKey, unless a request or token limit is
+// already used up; it then returns how long until the exhausted window resets. A denied request
+// is not counted.
+func (l *RateLimiter) Admit(ctx context.Context, username, subscriptionKey string) (time.Duration, error) {
+	requests, tokens := l.limits(subscriptionKey)
+	now := l.now()
+	var retryAfter time.Duration
+	for _, rate := range tokens {
+		if rate.Window <= 0 {
+			continue
+		}
+		start, end := Window(now, rate.Window)
+		used, err := l.store.Get(ctx, rateLimitKey("tokens", rate.Window, start, username, subscriptionKey))
+		if err != nil {
+			return 0, err
+		}
+		if used >= rate.Limit {
+			retryAfter = max(retryAfter, end.Sub(now))
+		}
+	}
+	for _, rate := range requests {
+		if rate.Window <= 0 {
+			continue
+		}
+		start, end := Window(now, rate.Window)
+		used, err := l.store.Get(ctx, rateLimitKey("requests", rate.Window, start, username, subscriptionKey))
+		if err != nil {
+			return 0, err
+		}
+		if used >= rate.Limit {
+			retryAfter = max(retryAfter, end.Sub(now))
+		}
+	}
+	if retryAfter > 0 {
+		return retryAfter, nil
+	}
+	for _, rate := range requests {
+		if rate.Window <= 0 {
+			continue
+		}
+		start, end := Window(now, rate.Window)
+		if _, err := l.store.Add(ctx, rateLimitKey("requests", rate.Window, start, username, subscriptionKey), 1, end.Sub(now)); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+// RecordTokens counts the tokens of a response against the token limits of username under
+// subscriptionKey.
+func (l *RateLimiter) RecordTokens(ctx context.Context, username, subscriptionKey string, tokens int64) error {
+	if tokens <= 0 {
+		return nil
+	}
+	_, rates := l.limits(subscriptionKey)
+	now := l.now()
+	for _, rate := range rates {
+		if rate.Window <= 0 {
+			continue
+		}
+		start, end := Window(now, rate.Window)
+		if _, err := l.store.Add(ctx, rateLimitKey("tokens", rate.Window, start, username, subscriptionKey), tokens, end.Sub(now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package quota_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+)
+
+func rateLimits(subscriptionKey string) ([]quota.Rate, []quota.Rate) {
+	if subscriptionKey != subKey {
+		return nil, nil
+	}
+	return []quota.Rate{{Limit: 2, Window: time.Minute}}, []quota.Rate{{Limit: 1000, Window: time.Hour}}
+}
+
+func TestRateLimiterRequests(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 0, 20, 0, time.UTC)
+	limiter := quota.NewRateLimiter(quota.NewMemoryStore(), rateLimits)
+	limiter.SetClock(func() time.Time { return now })
+
+	for range 2 {
+		retryIn, err := limiter.Admit(ctx, "alice", subKey)
+		require.NoError(t, err)
+		assert.Zero(t, retryIn)
+	}
+	retryIn, err := limiter.Admit(ctx, "alice", subKey)
+	require.NoError(t, err)
+	assert.Equal(t, 40*time.Second, retryIn, "denied until the minute window resets")
+
+	retryIn, err = limiter.Admit(ctx, "bob", subKey)
+	require.NoError(t, err)
+	assert.Zero(t, retryIn, "each user has their own counters")
+	retryIn, err = limiter.Admit(ctx, "alice", "models-as-a-service/free@llm/granite")
+	require.NoError(t, err)
+	assert.Zero(t, retryIn, "subscriptions without limits are not limited")
+
+	now = now.Add(time.Minute)
+	retryIn, err = limiter.Admit(ctx, "alice", subKey)
+	require.NoError(t, err)
+	assert.Zero(t, retryIn)
+}
+
+func TestRateLimiterTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	store := quota.NewMemoryStore()
+	limiter := quota.NewRateLimiter(store, func(subscriptionKey string) ([]quota.Rate, []quota.Rate) {
+		_, tokens := rateLimits(subscriptionKey)
+		return nil, tokens
+	})
+	limiter.SetClock(func() time.Time { return now })
+
+	require.NoError(t, limiter.RecordTokens(ctx, "alice", subKey, 999))
+	retryIn, err := limiter.Admit(ctx, "alice", subKey)
+	require.NoError(t, err)
+	assert.Zero(t, retryIn, "one token left")
+
+	require.NoError(t, limiter.RecordTokens(ctx, "alice", subKey, 400))
+	retryIn, err = limiter.Admit(ctx, "alice", subKey)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, retryIn, "denied until the hour window resets")
+}
+
+func TestValidateRateLimitBackend(t *testing.T) {
+	for _, backend := range []string{"limitador", "envoy-rls", "redis"} {
+		assert.NoError(t, quota.ValidateRateLimitBackend(backend))
+	}
+	assert.Error(t, quota.ValidateRateLimitBackend("memcached"))
+	assert.Error(t, quota.ValidateRateLimitBackend(""))
+}
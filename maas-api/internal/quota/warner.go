@@ -0,0 +1,84 @@
+// Package quota computes soft quota warnings from live rate limit counters, so the gateway
+// can tell callers they are approaching a hard token limit before requests are denied. It also
+// enforces per-user token budgets: the gateway reports the tokens of each response to
+// POST /v1/usage, and requests are denied once a user has consumed the budget of the period.
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// Warner turns usage counters into a warning message once a threshold is crossed.
+type Warner struct {
+	source    UsageSource
+	threshold int
+	logger    *logger.Logger
+}
+
+// NewWarner creates a Warner that warns when any limit is at least thresholdPercent consumed.
+func NewWarner(log *logger.Logger, source UsageSource, thresholdPercent int) *Warner {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Warner{source: source, threshold: thresholdPercent, logger: log}
+}
+
+// QuotaWarning returns a human-readable warning for the most consumed limit at or above the
+// threshold, or "" when the caller is below it. Lookup failures never block a request; they
+// are logged and produce no warning.
+func (w *Warner) QuotaWarning(ctx context.Context, username, subscriptionKey string) string {
+	if w == nil || w.source == nil || w.threshold <= 0 {
+		return ""
+	}
+	counters, err := w.source.Counters(ctx, username, subscriptionKey)
+	if err != nil {
+		w.logger.Debug("Quota usage lookup failed, skipping warning", "error", err, "subscription", subscriptionKey)
+		return ""
+	}
+
+	var worst *Counter
+	worstPercent := int64(-1)
+	for i := range counters {
+		c := counters[i]
+		if c.MaxValue <= 0 {
+			continue
+		}
+		used := max(c.MaxValue-c.Remaining, 0)
+		percent := used * 100 / c.MaxValue
+		if percent > worstPercent {
+			worst, worstPercent = &counters[i], percent
+		}
+	}
+	if worst == nil || worstPercent < int64(w.threshold) {
+		return ""
+	}
+	return fmt.Sprintf("%d%% of %d tokens per %ds used; requests will be rate limited at 100%%",
+		worstPercent, worst.MaxValue, worst.Seconds)
+}
+
+// RemainingTokens returns the tokens the caller may still use before the most consumed of their
+// token limits is reached. Until their first request creates the counters, that is limit, if any.
+// It returns false when the lookup failed. Unlike warnings, it does not depend on the threshold.
+func (w *Warner) RemainingTokens(ctx context.Context, username, subscriptionKey string, limit int64) (int64, bool) {
+	if w == nil || w.source == nil {
+		return 0, false
+	}
+	counters, err := w.source.Counters(ctx, username, subscriptionKey)
+	if err != nil {
+		w.logger.Debug("Quota usage lookup failed, skipping remaining tokens", "error", err, "subscription", subscriptionKey)
+		return 0, false
+	}
+	remaining, found := limit, limit > 0
+	for _, c := range counters {
+		if c.MaxValue <= 0 {
+			continue
+		}
+		if r := max(c.Remaining, 0); !found || r < remaining {
+			remaining, found = r, true
+		}
+	}
+	return remaining, found
+}
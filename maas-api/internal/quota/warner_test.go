@@ -0,0 +1,144 @@
+package quota_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+)
+
+const subKey = "models-as-a-service/premium@llm/granite"
+
+func limitadorServer(t *testing.T, counters []map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/counters/openshift-ingress%2Fmaas-default-gateway", r.URL.EscapedPath())
+		_ = json.NewEncoder(w).Encode(counters)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func counter(user, key string, maxValue, remaining, seconds int64) map[string]any {
+	return map[string]any{
+		"limit": map[string]any{
+			"max_value":  maxValue,
+			"seconds":    seconds,
+			"conditions": []string{`auth.identity.selected_subscription_key == "` + key + `"`},
+		},
+		"set_variables": map[string]string{"auth.identity.userid": user},
+		"remaining":     remaining,
+	}
+}
+
+func TestLimitadorSourceFiltersByUserAndSubscription(t *testing.T) {
+	srv := limitadorServer(t, []map[string]any{
+		counter("alice", subKey, 1000, 100, 60),
+		counter("bob", subKey, 1000, 0, 60),
+		counter("alice", "models-as-a-service/free@llm/granite", 100, 0, 60),
+	})
+
+	src := quota.NewLimitadorSource(srv.URL, "openshift-ingress/maas-default-gateway")
+	got, err := src.Counters(context.Background(), "alice", subKey)
+	require.NoError(t, err)
+	assert.Equal(t, []quota.Counter{{MaxValue: 1000, Remaining: 100, Seconds: 60}}, got)
+}
+
+func TestLimitadorSourceSharesCounters(t *testing.T) {
+	var reads atomic.Int32
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reads.Add(1)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode([]map[string]any{counter("alice", subKey, 1000, 100, 60)})
+	}))
+	t.Cleanup(srv.Close)
+	now := time.Unix(1700000000, 0)
+	src := quota.NewLimitadorSource(srv.URL, "ns/gw")
+	src.SetClock(func() time.Time { return now })
+	ctx := context.Background()
+
+	_, err := src.Counters(ctx, "alice", subKey)
+	require.NoError(t, err)
+	got, err := src.Counters(ctx, "bob", subKey)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	assert.Equal(t, int32(1), reads.Load(), "callers share the namespace's counters")
+
+	now = now.Add(3 * time.Second)
+	status = http.StatusServiceUnavailable
+	_, err = src.Counters(ctx, "alice", subKey)
+	require.Error(t, err)
+	_, err = src.Counters(ctx, "alice", subKey)
+	require.Error(t, err, "lookups fail at once while Limitador is down")
+	assert.Equal(t, int32(2), reads.Load())
+
+	now = now.Add(6 * time.Second)
+	status = http.StatusOK
+	_, err = src.Counters(ctx, "alice", subKey)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), reads.Load())
+}
+
+func TestWarner(t *testing.T) {
+	srv := limitadorServer(t, []map[string]any{
+		counter("alice", subKey, 1000, 150, 60),      // 85% used
+		counter("alice", subKey, 50000, 40000, 3600), // 20% used
+		counter("carol", subKey, 1000, 900, 60),      // 10% used
+	})
+	w := quota.NewWarner(logger.Development(), quota.NewLimitadorSource(srv.URL, "openshift-ingress/maas-default-gateway"), 80)
+
+	assert.Equal(t, "85% of 1000 tokens per 60s used; requests will be rate limited at 100%",
+		w.QuotaWarning(context.Background(), "alice", subKey))
+	assert.Empty(t, w.QuotaWarning(context.Background(), "carol", subKey), "below threshold")
+	assert.Empty(t, w.QuotaWarning(context.Background(), "dave", subKey), "no counters yet")
+}
+
+func TestWarnerIgnoresLookupFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	w := quota.NewWarner(logger.Development(), quota.NewLimitadorSource(srv.URL, "ns/gw"), 80)
+	assert.Empty(t, w.QuotaWarning(context.Background(), "alice", subKey))
+}
+
+func TestWarnerRemainingTokens(t *testing.T) {
+	srv := limitadorServer(t, []map[string]any{
+		counter("alice", subKey, 1000, 150, 60),
+		counter("alice", subKey, 50000, 40000, 3600),
+		counter("bob", subKey, 1000, -20, 60),
+	})
+	// The threshold only applies to warnings.
+	w := quota.NewWarner(logger.Development(), quota.NewLimitadorSource(srv.URL, "openshift-ingress/maas-default-gateway"), 0)
+
+	remaining, ok := w.RemainingTokens(context.Background(), "alice", subKey, 1000)
+	assert.True(t, ok)
+	assert.Equal(t, int64(150), remaining)
+	remaining, ok = w.RemainingTokens(context.Background(), "bob", subKey, 1000)
+	assert.True(t, ok)
+	assert.Zero(t, remaining, "overdrawn limits have nothing left")
+	remaining, ok = w.RemainingTokens(context.Background(), "dave", subKey, 1000)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1000), remaining, "no counters yet, so the whole limit is left")
+	_, ok = w.RemainingTokens(context.Background(), "dave", subKey, 0)
+	assert.False(t, ok, "no counters and no limit")
+
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	w = quota.NewWarner(logger.Development(), quota.NewLimitadorSource(down.URL, "ns/gw"), 0)
+	_, ok = w.RemainingTokens(context.Background(), "alice", subKey, 1000)
+	assert.False(t, ok, "unknown when Limitador is unreachable")
+}
@@ -0,0 +1,122 @@
+// Package readonly implements maas-api's read-only mode, used while the API key database is
+// migrated or maas-api is upgraded. Mutating endpoints answer 503 with Retry-After; authorization
+// callbacks, ext_authz, inference and reads keep working.
+package readonly
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+var (
+	enabledGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "maas_read_only",
+		Help: "1 while maas-api rejects mutations in read-only mode.",
+	})
+	rejectedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "maas_read_only_rejected_total",
+		Help: "Mutations rejected because maas-api was in read-only mode.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(enabledGauge, rejectedTotal)
+}
+
+// Mode is on when configured (READ_ONLY) or while the maas-api namespace carries the
+// maas.opendatahub.io/read-only=true annotation. A nil Mode is never read-only.
+type Mode struct {
+	configured bool
+	retryAfter time.Duration
+	logger     *logger.Logger
+
+	annotated atomic.Bool
+}
+
+// New creates a Mode; configured switches it on regardless of the annotation. Mutations rejected
+// while read-only ask clients to retry after retryAfter, rounded up to whole seconds.
+func New(log *logger.Logger, configured bool, retryAfter time.Duration) *Mode {
+	if log == nil {
+		log = logger.Production()
+	}
+	m := &Mode{configured: configured, retryAfter: max(retryAfter, time.Second), logger: log}
+	m.publish()
+	return m
+}
+
+// Enabled reports whether mutations are rejected.
+func (m *Mode) Enabled() bool {
+	return m != nil && (m.configured || m.annotated.Load())
+}
+
+func (m *Mode) publish() {
+	if m.Enabled() {
+		enabledGauge.Set(1)
+	} else {
+		enabledGauge.Set(0)
+	}
+}
+
+// Middleware rejects the request with 503 Service Unavailable and Retry-After while read-only.
+// Register it only on mutating routes.
+func (m *Mode) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !m.Enabled() {
+			c.Next()
+			return
+		}
+		rejectedTotal.Inc()
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(m.retryAfter.Seconds()))))
+		apierror.Abort(c, http.StatusServiceUnavailable, apierror.ReadOnly, "maas-api is in read-only mode for maintenance; retry later")
+	}
+}
+
+// Watch reads the read-only annotation of namespace every interval until ctx is done. A failed
+// read keeps the last known state.
+func (m *Mode) Watch(ctx context.Context, clientset kubernetes.Interface, namespace string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		ns, err := clientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				m.logger.Warn("Failed to read the read-only annotation", "namespace", namespace, "error", err)
+			}
+		default:
+			m.set(ns.Annotations[constant.AnnotationReadOnly] == "true", namespace)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Mode) set(annotated bool, namespace string) {
+	if m.annotated.Swap(annotated) == annotated {
+		return
+	}
+	m.publish()
+	switch {
+	case m.configured:
+	case annotated:
+		m.logger.Warn("Read-only mode on: mutations are rejected", "annotation", constant.AnnotationReadOnly, "namespace", namespace)
+	default:
+		m.logger.Info("Read-only mode off", "annotation", constant.AnnotationReadOnly, "namespace", namespace)
+	}
+}
@@ -0,0 +1,62 @@
+package readonly_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/readonly"
+)
+
+func serve(mode *readonly.Mode) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/api-keys", mode.Middleware(), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/api-keys", nil))
+	return w
+}
+
+func TestMiddlewareRejectsWhileReadOnly(t *testing.T) {
+	w := serve(readonly.New(logger.Development(), true, 90*time.Second))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), `"type":"read_only"`)
+
+	assert.Equal(t, http.StatusCreated, serve(readonly.New(logger.Development(), false, time.Minute)).Code)
+
+	var off *readonly.Mode
+	assert.False(t, off.Enabled())
+	assert.Equal(t, http.StatusCreated, serve(off).Code)
+}
+
+func TestWatchFollowsNamespaceAnnotation(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "maas-api",
+		Annotations: map[string]string{constant.AnnotationReadOnly: "true"},
+	}}
+	clientset := fake.NewClientset(ns)
+	mode := readonly.New(logger.Development(), false, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go mode.Watch(ctx, clientset, "maas-api", 10*time.Millisecond)
+
+	require.Eventually(t, mode.Enabled, time.Second, 5*time.Millisecond)
+
+	ns.Annotations = nil
+	_, err := clientset.CoreV1().Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return !mode.Enabled() }, time.Second, 5*time.Millisecond)
+}
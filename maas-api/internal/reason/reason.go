@@ -0,0 +1,274 @@
+// Package reason defines the codes maas-api gives for denied requests. The same code is the
+// error of the subscription selection response, the x-ext-auth-reason of ext_authz denials, the
+// reason of batch authorization decisions and of audit records, and the reason label of
+// maas_authorization_decisions_total, so clients and dashboards can rely on it.
+//
+// Codes are stable: once released a code keeps its meaning and is never removed. Each belongs to
+// a category, which metrics can report instead of the code to keep the number of series down.
+package reason
+
+import "slices"
+
+// Codes of denied requests.
+const (
+	// Unauthenticated: the request carries no valid API key.
+	Unauthenticated = "unauthenticated"
+	// SignatureRequired: the user signs requests, and this one is unsigned.
+	SignatureRequired = "signature_required"
+	// InvalidSignature: the request signature does not match.
+	InvalidSignature = "invalid_signature"
+	// StaleSignature: the signature timestamp is outside the allowed skew.
+	StaleSignature = "stale_signature"
+	// ReplayedRequest: the signature nonce was already used.
+	ReplayedRequest = "replayed_request"
+
+	// Unauthorized: no MaaSAuthPolicy or allow-list of the model grants the user access.
+	Unauthorized = "unauthorized"
+	// AccessDenied: the user may not use the requested subscription.
+	AccessDenied = "access_denied"
+	// ModelNotInKeyScope: the API key is scoped to other models.
+	ModelNotInKeyScope = "model_not_in_key_scope"
+	// HostMismatch: the subscription is bound to a listener that does not serve the request host.
+	HostMismatch = "host_mismatch"
+	// HookDenied: a decision hook vetoed the request.
+	HookDenied = "hook_denied"
+	// PolicyDenied: an authorization rule (AUTHORIZATION_RULES_FILE or the model's
+	// spec.authorizationRule) did not evaluate to true.
+	PolicyDenied = "policy_denied"
+	// EndpointNotAllowed: the subscription restricts its requests to other API suffixes, e.g. an
+	// embeddings-only tier sending a chat completion.
+	EndpointNotAllowed = "endpoint_not_allowed"
+
+	// NotFound: the user has no subscription, or the requested one does not exist.
+	NotFound = "not_found"
+	// MultipleSubscriptions: the user has several subscriptions and must pick one.
+	MultipleSubscriptions = "multiple_subscriptions"
+	// ModelNotInSubscription: no subscription of the user includes the model.
+	ModelNotInSubscription = "model_not_in_subscription"
+
+	// QuotaExhausted: the user's token budget for the model is spent.
+	QuotaExhausted = "quota_exhausted"
+	// RateLimited: maas-api throttled the authorization call, or the caller used up a rate limit
+	// of their subscription (RATE_LIMIT_BACKEND=redis).
+	RateLimited = "rate_limited"
+	// TooManyInFlight: too many authorization calls of the client are in flight.
+	TooManyInFlight = "too_many_in_flight"
+	// TooManyConcurrentRequests: the caller has as many requests in progress on the model as
+	// their subscription allows.
+	TooManyConcurrentRequests = "too_many_concurrent_requests"
+
+	// ModelNotFound: the request does not target a MaaS model.
+	ModelNotFound = "model_not_found"
+	// ModelDeleted: the model is soft-deleted.
+	ModelDeleted = "model_deleted"
+	// ModelMaintenance: the model is in maintenance; retry later.
+	ModelMaintenance = "model_maintenance"
+	// ClusterUnavailable: the model is hosted on a federated cluster maas-api cannot currently
+	// reach; retry later.
+	ClusterUnavailable = "cluster_unavailable"
+	// ModelAmbiguous: a bare model name matches models in several namespaces.
+	ModelAmbiguous = "model_ambiguous"
+	// MissingModel: the request body names no model.
+	MissingModel = "missing_model"
+	// BadRequest: the request is malformed.
+	BadRequest = "bad_request"
+	// UnsupportedEndpoint: the model's class does not serve the requested endpoint, e.g. a chat
+	// completion sent to an embedding model.
+	UnsupportedEndpoint = "unsupported_endpoint"
+	// UnknownEndpoint: the path after the model is not one of the API suffixes maas-api allows.
+	UnknownEndpoint = "unknown_endpoint"
+	// TooManyAttachments: the request carries more attachments than the subscription allows.
+	TooManyAttachments = "too_many_attachments"
+	// AttachmentTooLarge: an attachment, or the request body, is larger than the subscription allows.
+	AttachmentTooLarge = "attachment_too_large"
+	// ImageTooLarge: an image has a higher resolution than the subscription allows.
+	ImageTooLarge = "image_too_large"
+	// AttachmentTypeNotAllowed: an attachment has a MIME type the subscription does not allow.
+	AttachmentTypeNotAllowed = "attachment_type_not_allowed"
+	// ContextLengthExceeded: the prompt, plus the completion tokens requested, is longer than the
+	// model's context window.
+	ContextLengthExceeded = "context_length_exceeded"
+
+	// InternalError: maas-api failed to reach a decision.
+	InternalError = "internal_error"
+	// HookFailed: a decision hook that fails closed could not be reached or answered malformed.
+	HookFailed = "hook_failed"
+	// PolicyFailed: an authorization rule failed to compile or evaluate.
+	PolicyFailed = "policy_failed"
+)
+
+// Category groups related codes.
+type Category string
+
+const (
+	CategoryAuthentication Category = "authentication"
+	CategoryAuthorization  Category = "authorization"
+	CategorySubscription   Category = "subscription"
+	CategoryQuota          Category = "quota"
+	CategoryRequest        Category = "request"
+	CategoryInternal       Category = "internal"
+	// CategoryUnknown is reported for codes outside the taxonomy.
+	CategoryUnknown Category = "unknown"
+)
+
+var categories = map[string]Category{
+	Unauthenticated:           CategoryAuthentication,
+	SignatureRequired:         CategoryAuthentication,
+	InvalidSignature:          CategoryAuthentication,
+	StaleSignature:            CategoryAuthentication,
+	ReplayedRequest:           CategoryAuthentication,
+	Unauthorized:              CategoryAuthorization,
+	AccessDenied:              CategoryAuthorization,
+	ModelNotInKeyScope:        CategoryAuthorization,
+	HostMismatch:              CategoryAuthorization,
+	HookDenied:                CategoryAuthorization,
+	PolicyDenied:              CategoryAuthorization,
+	EndpointNotAllowed:        CategoryAuthorization,
+	NotFound:                  CategorySubscription,
+	MultipleSubscriptions:     CategorySubscription,
+	ModelNotInSubscription:    CategorySubscription,
+	QuotaExhausted:            CategoryQuota,
+	RateLimited:               CategoryQuota,
+	TooManyInFlight:           CategoryQuota,
+	TooManyConcurrentRequests: CategoryQuota,
+	ModelNotFound:             CategoryRequest,
+	ModelDeleted:              CategoryRequest,
+	ModelMaintenance:          CategoryRequest,
+	ClusterUnavailable:        CategoryRequest,
+	ModelAmbiguous:            CategoryRequest,
+	MissingModel:              CategoryRequest,
+	BadRequest:                CategoryRequest,
+	UnsupportedEndpoint:       CategoryRequest,
+	UnknownEndpoint:           CategoryRequest,
+	TooManyAttachments:        CategoryRequest,
+	AttachmentTooLarge:        CategoryRequest,
+	ImageTooLarge:             CategoryRequest,
+	AttachmentTypeNotAllowed:  CategoryRequest,
+	ContextLengthExceeded:     CategoryRequest,
+	InternalError:             CategoryInternal,
+	HookFailed:                CategoryInternal,
+	PolicyFailed:              CategoryInternal,
+}
+
+// Codes returns every code, sorted.
+func Codes() []string {
+	codes := make([]string, 0, len(categories))
+	for code := range categories {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	return codes
+}
+
+// CategoryOf returns the category of code, CategoryUnknown for codes outside the taxonomy and ""
+// for "", which stands for an allowed request.
+func CategoryOf(code string) Category {
+	if code == "" {
+		return ""
+	}
+	if c, ok := categories[code]; ok {
+		return c
+	}
+	return CategoryUnknown
+}
+
+// Label is how metrics report a reason.
+type Label string
+
+const (
+	// LabelCode reports the code itself.
+	LabelCode Label = "code"
+	// LabelCategory reports the code's category.
+	LabelCategory Label = "category"
+)
+
+// ParseLabel parses a reason label setting. "" means LabelCode.
+func ParseLabel(s string) (Label, bool) {
+	switch l := Label(s); l {
+	case "", LabelCode:
+		return LabelCode, true
+	case LabelCategory:
+		return l, true
+	}
+	return "", false
+}
+
+// Value returns the metrics label value of code. Codes outside the taxonomy are reported as
+// "unknown" either way, so a misbehaving caller cannot create series at will.
+func (l Label) Value(code string) string {
+	category := CategoryOf(code)
+	if l == LabelCategory || category == CategoryUnknown {
+		return string(category)
+	}
+	return code
+}
+
+var messages = map[string]string{
+	Unauthenticated:           "Authentication required",
+	SignatureRequired:         "Request signature required",
+	InvalidSignature:          "Invalid request signature",
+	StaleSignature:            "Request signature has expired",
+	ReplayedRequest:           "Request has already been used",
+	Unauthorized:              "Access denied",
+	AccessDenied:              "Access denied to the requested subscription",
+	ModelNotInKeyScope:        "API key is not valid for this model",
+	HostMismatch:              "Subscription is not served on this host",
+	HookDenied:                "Access denied",
+	PolicyDenied:              "Access denied by policy",
+	EndpointNotAllowed:        "Subscription does not allow this endpoint",
+	NotFound:                  "No subscription found",
+	MultipleSubscriptions:     "Several subscriptions apply, select one with the X-MaaS-Subscription header",
+	ModelNotInSubscription:    "Subscription does not include this model",
+	QuotaExhausted:            "Token budget exhausted",
+	RateLimited:               "Too many requests",
+	TooManyInFlight:           "Too many requests",
+	TooManyConcurrentRequests: "Too many concurrent requests",
+	ModelNotFound:             "Request does not target a MaaS model",
+	ModelDeleted:              "Model has been deleted",
+	ModelMaintenance:          "Model is under maintenance, retry later",
+	ClusterUnavailable:        "Cluster hosting the model is unavailable, retry later",
+	ModelAmbiguous:            "Model name is ambiguous, qualify it with its namespace",
+	MissingModel:              "Request names no model",
+	BadRequest:                "Bad request",
+	UnsupportedEndpoint:       "Model does not serve this endpoint",
+	UnknownEndpoint:           "Unknown endpoint",
+	TooManyAttachments:        "Request carries too many attachments",
+	AttachmentTooLarge:        "Attachment is too large",
+	ImageTooLarge:             "Image resolution is too high",
+	AttachmentTypeNotAllowed:  "Attachment type is not allowed",
+	ContextLengthExceeded:     "Request exceeds the model's context window",
+	InternalError:             "Internal error",
+	HookFailed:                "Authorization is unavailable",
+	PolicyFailed:              "Authorization is unavailable",
+}
+
+// Message returns the fixed message of code. It names no users, subscriptions or hosts, so it can
+// be shown to anyone, and it never changes for a code, so clients can translate it by code.
+func Message(code string) string {
+	if m, ok := messages[code]; ok {
+		return m
+	}
+	return "Request denied"
+}
+
+// Verbosity is how much a denial tells the caller.
+type Verbosity string
+
+const (
+	// VerbosityMinimal gives the code and its fixed message only.
+	VerbosityMinimal Verbosity = "minimal"
+	// VerbosityDetailed adds the message of the failed check and the tiers that would grant
+	// access, which reveals how tiers are set up. Meant for debugging.
+	VerbosityDetailed Verbosity = "detailed"
+)
+
+// ParseVerbosity parses a verbosity setting. "" means VerbosityMinimal.
+func ParseVerbosity(s string) (Verbosity, bool) {
+	switch v := Verbosity(s); v {
+	case "", VerbosityMinimal:
+		return VerbosityMinimal, true
+	case VerbosityDetailed:
+		return v, true
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package reason_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// TestCodesAreStable fails when a code is renamed or removed. Clients and dashboards match on
+// these strings; add new codes instead.
+func TestCodesAreStable(t *testing.T) {
+	released := []string{
+		"access_denied", "bad_request", "host_mismatch", "internal_error", "invalid_signature",
+		"missing_model", "model_ambiguous", "model_not_found", "model_not_in_key_scope",
+		"model_not_in_subscription", "multiple_subscriptions", "not_found", "quota_exhausted",
+		"rate_limited", "replayed_request", "signature_required", "stale_signature",
+		"too_many_in_flight", "unauthenticated", "unauthorized",
+	}
+	assert.Subset(t, reason.Codes(), released)
+	for _, code := range reason.Codes() {
+		assert.NotEqual(t, reason.CategoryUnknown, reason.CategoryOf(code), code)
+		assert.NotEqual(t, "Request denied", reason.Message(code), "%s has no message", code)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		setting string
+		code    string
+		want    string
+	}{
+		{setting: "", code: reason.QuotaExhausted, want: "quota_exhausted"},
+		{setting: "code", code: reason.QuotaExhausted, want: "quota_exhausted"},
+		{setting: "category", code: reason.QuotaExhausted, want: "quota"},
+		{setting: "category", code: reason.Unauthorized, want: "authorization"},
+		{setting: "code", code: "made_up", want: "unknown"},
+		{setting: "category", code: "", want: ""},
+		{setting: "code", code: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.setting+"/"+tt.code, func(t *testing.T) {
+			label, ok := reason.ParseLabel(tt.setting)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, label.Value(tt.code))
+		})
+	}
+
+	_, ok := reason.ParseLabel("message")
+	assert.False(t, ok)
+}
+
+func TestParseVerbosity(t *testing.T) {
+	for setting, want := range map[string]reason.Verbosity{
+		"":         reason.VerbosityMinimal,
+		"minimal":  reason.VerbosityMinimal,
+		"detailed": reason.VerbosityDetailed,
+	} {
+		got, ok := reason.ParseVerbosity(setting)
+		assert.True(t, ok, setting)
+		assert.Equal(t, want, got, setting)
+	}
+
+	_, ok := reason.ParseVerbosity("verbose")
+	assert.False(t, ok)
+}
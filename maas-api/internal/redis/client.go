@@ -0,0 +1,196 @@
+// Package redis is a minimal Redis client for state that maas-api replicas share, such as the
+// authorization throttle's buckets. It speaks just enough RESP for simple commands, AUTH and
+// SELECT, so maas-api does not need a full client library.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	timeout     = 500 * time.Millisecond
+	idleConns   = 8
+	defaultPort = "6379"
+)
+
+// Client sends commands to one Redis server. Connections are opened lazily and reused.
+type Client struct {
+	target target
+	idle   chan *conn
+}
+
+type target struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      bool
+	host     string
+}
+
+// NewClient creates a client for a redis:// or rediss:// URL, e.g. redis://:password@redis:6379/0.
+func NewClient(rawURL string) (*Client, error) {
+	t, err := parseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{target: t, idle: make(chan *conn, idleConns)}, nil
+}
+
+// ValidateURL checks that rawURL is a usable redis:// or rediss:// URL.
+func ValidateURL(rawURL string) error {
+	_, err := parseURL(rawURL)
+	return err
+}
+
+func parseURL(rawURL string) (target, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return target{}, err
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return target{}, fmt.Errorf("unsupported scheme %q (expected redis or rediss)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return target{}, errors.New("missing host")
+	}
+	t := target{tls: u.Scheme == "rediss", host: u.Hostname()}
+	port := u.Port()
+	if port == "" {
+		port = defaultPort
+	}
+	t.addr = net.JoinHostPort(u.Hostname(), port)
+	if u.User != nil {
+		t.username = u.User.Username()
+		t.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if t.db, err = strconv.Atoi(db); err != nil || t.db < 0 {
+			return target{}, fmt.Errorf("invalid database %q", db)
+		}
+	}
+	return t, nil
+}
+
+// Do sends a command and returns its reply: a string, an int64 or nil. A connection that fails is
+// discarded; error replies from Redis are returned as errors.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	cn, err := c.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := cn.do(ctx, args...)
+	if err != nil {
+		_ = cn.Close()
+		return nil, err
+	}
+	select {
+	case c.idle <- cn:
+	default:
+		_ = cn.Close()
+	}
+	return reply, nil
+}
+
+func (c *Client) conn(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	var nc net.Conn
+	var err error
+	if c.target.tls {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{MinVersion: tls.VersionTLS12, ServerName: c.target.host}}
+		nc, err = tlsDialer.DialContext(ctx, "tcp", c.target.addr)
+	} else {
+		nc, err = dialer.DialContext(ctx, "tcp", c.target.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc)}
+	if c.target.password != "" {
+		args := []string{"AUTH", c.target.password}
+		if c.target.username != "" {
+			args = []string{"AUTH", c.target.username, c.target.password}
+		}
+		if _, err := cn.do(ctx, args...); err != nil {
+			_ = cn.Close()
+			return nil, err
+		}
+	}
+	if c.target.db != 0 {
+		if _, err := cn.do(ctx, "SELECT", strconv.Itoa(c.target.db)); err != nil {
+			_ = cn.Close()
+			return nil, err
+		}
+	}
+	return cn, nil
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *conn) do(ctx context.Context, args ...string) (any, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := c.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c, b.String()); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return readReply(c.r)
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			// $-1 is the nil reply.
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return string(buf[:n]), nil
+	}
+	return nil, fmt.Errorf("redis: unsupported reply %q", line)
+}
@@ -0,0 +1,159 @@
+// Package retention describes how long each class of audit and usage records is kept, and the
+// legal holds that exempt records from pruning. The janitor enforces it.
+package retention
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DataClass is a kind of stored record with its own retention period.
+type DataClass string
+
+const (
+	// ClassDecisions are the authorization decisions in the audit store.
+	ClassDecisions DataClass = "decisions"
+	// ClassUsage is the hourly usage ledger behind the chargeback reports.
+	ClassUsage DataClass = "usage"
+	// ClassAdminActions are the admin actions in the audit store, e.g. tier changes.
+	ClassAdminActions DataClass = "admin_actions"
+)
+
+// Classes lists the data classes in the order the janitor prunes them.
+var Classes = []DataClass{ClassDecisions, ClassUsage, ClassAdminActions}
+
+// Period is a retention period in calendar days, months and years, so "13mo" keeps a record until
+// the same day thirteen months later however long the months are.
+type Period struct {
+	Days   int
+	Months int
+	Years  int
+}
+
+// ParsePeriod parses a period such as "90d", "13mo" or "7y". "off" keeps records forever and
+// parses as the zero Period.
+func ParsePeriod(s string) (Period, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" {
+		return Period{}, nil
+	}
+	for _, unit := range []string{"mo", "d", "y"} {
+		number, ok := strings.CutSuffix(s, unit)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil || n <= 0 {
+			break
+		}
+		switch unit {
+		case "d":
+			return Period{Days: n}, nil
+		case "mo":
+			return Period{Months: n}, nil
+		default:
+			return Period{Years: n}, nil
+		}
+	}
+	return Period{}, fmt.Errorf("invalid retention period %q: must be a positive number of days (90d), months (13mo) or years (7y), or off", s)
+}
+
+// IsZero reports whether the period keeps records forever.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// Cutoff returns the time before which records are past retention.
+func (p Period) Cutoff(now time.Time) time.Time {
+	return now.AddDate(-p.Years, -p.Months, -p.Days)
+}
+
+func (p Period) String() string {
+	var b strings.Builder
+	if p.Years > 0 {
+		fmt.Fprintf(&b, "%dy", p.Years)
+	}
+	if p.Months > 0 {
+		fmt.Fprintf(&b, "%dmo", p.Months)
+	}
+	if p.Days > 0 {
+		fmt.Fprintf(&b, "%dd", p.Days)
+	}
+	if b.Len() == 0 {
+		return "off"
+	}
+	return b.String()
+}
+
+// Policy is the retention period of each data class. Classes with a zero period are never pruned.
+type Policy map[DataClass]Period
+
+// DefaultPolicy follows a typical enterprise schedule: decisions for 90 days, usage for 13
+// months, so a year can be compared with the one before, and admin actions for 7 years.
+func DefaultPolicy() Policy {
+	return Policy{
+		ClassDecisions:    {Days: 90},
+		ClassUsage:        {Months: 13},
+		ClassAdminActions: {Years: 7},
+	}
+}
+
+// ParsePolicy parses comma-separated class=period pairs, e.g. "decisions=30d,usage=off", on top
+// of DefaultPolicy.
+func ParsePolicy(s string) (Policy, error) {
+	policy := DefaultPolicy()
+	for pair := range strings.SplitSeq(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		class := DataClass(strings.TrimSpace(name))
+		if !ok || !slices.Contains(Classes, class) {
+			return nil, fmt.Errorf("invalid retention %q: must be class=period with class decisions, usage or admin_actions", pair)
+		}
+		period, err := ParsePeriod(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention for %s: %w", class, err)
+		}
+		policy[class] = period
+	}
+	return policy, nil
+}
+
+// LegalHolds exempts the records of organizations and models from pruning, whatever their age.
+type LegalHolds struct {
+	// Organizations are tokenMetadata organization IDs. They hold the records of every
+	// MaaSSubscription of the organization.
+	Organizations []string `json:"organizations,omitempty"`
+	// Models are MaaSModelRefs in namespace/name form.
+	Models []string `json:"models,omitempty"`
+}
+
+// ParseLegalHolds parses comma-separated organization IDs and models (namespace/name).
+func ParseLegalHolds(organizations, models string) (LegalHolds, error) {
+	holds := LegalHolds{Organizations: splitList(organizations), Models: splitList(models)}
+	for _, model := range holds.Models {
+		if ns, name, ok := strings.Cut(model, "/"); !ok || ns == "" || name == "" || strings.Contains(name, "/") {
+			return LegalHolds{}, fmt.Errorf("invalid legal hold model %q: must be namespace/name", model)
+		}
+	}
+	return holds, nil
+}
+
+// Empty reports whether nothing is under legal hold.
+func (h LegalHolds) Empty() bool {
+	return len(h.Organizations) == 0 && len(h.Models) == 0
+}
+
+func splitList(s string) []string {
+	var out []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" && !slices.Contains(out, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
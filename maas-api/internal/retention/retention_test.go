@@ -0,0 +1,69 @@
+package retention_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/retention"
+)
+
+func TestParsePeriod(t *testing.T) {
+	for input, want := range map[string]retention.Period{
+		"90d":  {Days: 90},
+		"13mo": {Months: 13},
+		" 7y ": {Years: 7},
+		"off":  {},
+	} {
+		got, err := retention.ParsePeriod(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "90", "0d", "-1y", "13m", "1.5y", "10mod"} {
+		_, err := retention.ParsePeriod(input)
+		assert.Error(t, err, input)
+	}
+
+	now := time.Date(2026, time.March, 31, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC), retention.Period{Months: 13}.Cutoff(now),
+		"months are calendar months")
+	assert.Equal(t, time.Date(2019, time.March, 31, 12, 0, 0, 0, time.UTC), retention.Period{Years: 7}.Cutoff(now))
+	assert.Equal(t, "13mo", retention.Period{Months: 13}.String())
+	assert.Equal(t, "off", retention.Period{}.String())
+}
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := retention.ParsePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, retention.DefaultPolicy(), policy)
+	assert.Equal(t, "90d", policy[retention.ClassDecisions].String())
+	assert.Equal(t, "13mo", policy[retention.ClassUsage].String())
+	assert.Equal(t, "7y", policy[retention.ClassAdminActions].String())
+
+	policy, err = retention.ParsePolicy("decisions=30d, usage=off")
+	require.NoError(t, err)
+	assert.Equal(t, retention.Period{Days: 30}, policy[retention.ClassDecisions])
+	assert.True(t, policy[retention.ClassUsage].IsZero())
+	assert.Equal(t, retention.Period{Years: 7}, policy[retention.ClassAdminActions], "unnamed classes keep their default")
+
+	_, err = retention.ParsePolicy("keys=30d")
+	require.ErrorContains(t, err, "class decisions, usage or admin_actions")
+	_, err = retention.ParsePolicy("usage=forever")
+	require.ErrorContains(t, err, "invalid retention for usage")
+}
+
+func TestParseLegalHolds(t *testing.T) {
+	holds, err := retention.ParseLegalHolds("acme, globex,acme", "llm/granite")
+	require.NoError(t, err)
+	assert.Equal(t, retention.LegalHolds{Organizations: []string{"acme", "globex"}, Models: []string{"llm/granite"}}, holds)
+	assert.False(t, holds.Empty())
+
+	holds, err = retention.ParseLegalHolds("", " ")
+	require.NoError(t, err)
+	assert.True(t, holds.Empty())
+
+	_, err = retention.ParseLegalHolds("", "granite")
+	require.ErrorContains(t, err, "must be namespace/name")
+}
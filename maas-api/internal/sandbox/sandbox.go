@@ -0,0 +1,378 @@
+// Package sandbox is a mock OpenAI-compatible backend for sandbox subscriptions. Requests of a
+// MaaSSubscription with spec.sandbox set are routed here by the gateway instead of to the model,
+// so developers can build against the API without spending GPU time. Responses are canned but
+// shaped like a real model server's, including token usage, so metering and quotas still apply.
+package sandbox
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+const (
+	// DefaultModel is the model name reported when a request does not name one.
+	DefaultModel = "sandbox"
+	// EmbeddingDimensions is the length of the vectors returned by /v1/embeddings.
+	EmbeddingDimensions = 16
+	// Reply is the completion text; responses are cut to max_tokens words.
+	Reply = "This is a sandbox response from Models-as-a-Service. No model was called, " +
+		"so the content is fixed, but the request was authenticated, authorized and metered like any other."
+
+	// tokenIntervalDivisor spaces streamed tokens at latency/tokenIntervalDivisor.
+	tokenIntervalDivisor = 20
+)
+
+// Server answers OpenAI requests with canned responses after a simulated latency.
+type Server struct {
+	logger  *logger.Logger
+	latency time.Duration
+	now     func() time.Time
+}
+
+// NewServer creates a sandbox backend. latency is the time to the first token; streamed tokens
+// follow at a twentieth of it.
+func NewServer(log *logger.Logger, latency time.Duration) *Server {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Server{logger: log, latency: max(latency, 0), now: time.Now}
+}
+
+// Register adds the sandbox routes to r.
+func (s *Server) Register(r *gin.Engine) {
+	r.Use(func(c *gin.Context) {
+		c.Header("X-MaaS-Sandbox", "true")
+	})
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	})
+	r.GET("/v1/models", s.ListModels)
+	r.POST("/v1/chat/completions", s.ChatCompletions)
+	r.POST("/v1/completions", s.Completions)
+	r.POST("/v1/embeddings", s.Embeddings)
+	r.NoRoute(func(c *gin.Context) {
+		s.logger.Debug("Unsupported sandbox request", "method", c.Request.Method, "path", c.Request.URL.Path)
+		apierror.New(apierror.InvalidPath, fmt.Sprintf("%s %s is not supported by the sandbox", c.Request.Method, c.Request.URL.Path)).
+			WithType("not_found_error").
+			Respond(c, http.StatusNotFound)
+	})
+}
+
+// Usage is the token accounting of a response, counted in whitespace-separated words.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func newUsage(prompt, completion int) *Usage {
+	return &Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+}
+
+// ListModels handles GET /v1/models.
+func (s *Server) ListModels(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data": []gin.H{{
+			"id":       DefaultModel,
+			"object":   "model",
+			"created":  s.now().Unix(),
+			"owned_by": "maas-sandbox",
+		}},
+	})
+}
+
+type chatRequest struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+	} `json:"messages"`
+	MaxTokens           int  `json:"max_tokens"`
+	MaxCompletionTokens int  `json:"max_completion_tokens"`
+	Stream              bool `json:"stream"`
+}
+
+// ChatCompletions handles POST /v1/chat/completions, streaming as server-sent events when asked.
+func (s *Server) ChatCompletions(c *gin.Context) {
+	var req chatRequest
+	if !bind(c, &req) {
+		return
+	}
+	if len(req.Messages) == 0 {
+		invalidRequest(c, "messages must not be empty")
+		return
+	}
+	prompt := 0
+	for _, m := range req.Messages {
+		prompt += countTokens(textOf(m.Content))
+	}
+	words := reply(max(req.MaxTokens, req.MaxCompletionTokens))
+	model := modelOf(req.Model)
+	id := "chatcmpl-sandbox-" + s.id()
+
+	if req.Stream {
+		s.stream(c, words, func(word string, first bool) any {
+			delta := gin.H{"content": word}
+			if first {
+				delta["role"] = "assistant"
+			}
+			return gin.H{"id": id, "object": "chat.completion.chunk", "created": s.now().Unix(), "model": model,
+				"choices": []gin.H{{"index": 0, "delta": delta, "finish_reason": nil}}}
+		}, gin.H{"id": id, "object": "chat.completion.chunk", "created": s.now().Unix(), "model": model,
+			"choices": []gin.H{{"index": 0, "delta": gin.H{}, "finish_reason": "stop"}},
+			"usage":   newUsage(prompt, len(words))})
+		return
+	}
+
+	if !s.wait(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "chat.completion",
+		"created": s.now().Unix(),
+		"model":   model,
+		"choices": []gin.H{{
+			"index":         0,
+			"message":       gin.H{"role": "assistant", "content": strings.Join(words, "")},
+			"finish_reason": "stop",
+		}},
+		"usage": newUsage(prompt, len(words)),
+	})
+}
+
+type completionRequest struct {
+	Model     string          `json:"model"`
+	Prompt    json.RawMessage `json:"prompt"`
+	MaxTokens int             `json:"max_tokens"`
+	Stream    bool            `json:"stream"`
+}
+
+// Completions handles POST /v1/completions.
+func (s *Server) Completions(c *gin.Context) {
+	var req completionRequest
+	if !bind(c, &req) {
+		return
+	}
+	prompts, ok := stringOrList(req.Prompt)
+	if !ok {
+		invalidRequest(c, "prompt must be a string or a list of strings")
+		return
+	}
+	prompt := 0
+	for _, p := range prompts {
+		prompt += countTokens(p)
+	}
+	words := reply(req.MaxTokens)
+	model := modelOf(req.Model)
+	id := "cmpl-sandbox-" + s.id()
+
+	if req.Stream {
+		s.stream(c, words, func(word string, _ bool) any {
+			return gin.H{"id": id, "object": "text_completion", "created": s.now().Unix(), "model": model,
+				"choices": []gin.H{{"index": 0, "text": word, "finish_reason": nil}}}
+		}, gin.H{"id": id, "object": "text_completion", "created": s.now().Unix(), "model": model,
+			"choices": []gin.H{{"index": 0, "text": "", "finish_reason": "stop"}},
+			"usage":   newUsage(prompt, len(words))})
+		return
+	}
+
+	if !s.wait(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "text_completion",
+		"created": s.now().Unix(),
+		"model":   model,
+		"choices": []gin.H{{"index": 0, "text": strings.Join(words, ""), "finish_reason": "stop"}},
+		"usage":   newUsage(prompt, len(words)),
+	})
+}
+
+type embeddingRequest struct {
+	Model string          `json:"model"`
+	Input json.RawMessage `json:"input"`
+}
+
+// Embeddings handles POST /v1/embeddings. Vectors are derived from a hash of each input, so the
+// same text always gets the same unit vector.
+func (s *Server) Embeddings(c *gin.Context) {
+	var req embeddingRequest
+	if !bind(c, &req) {
+		return
+	}
+	inputs, ok := stringOrList(req.Input)
+	if !ok || len(inputs) == 0 {
+		invalidRequest(c, "input must be a string or a non-empty list of strings")
+		return
+	}
+	if !s.wait(c) {
+		return
+	}
+	data := make([]gin.H, len(inputs))
+	prompt := 0
+	for i, input := range inputs {
+		prompt += countTokens(input)
+		data[i] = gin.H{"object": "embedding", "index": i, "embedding": Embedding(input)}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"model":  modelOf(req.Model),
+		"data":   data,
+		"usage":  gin.H{"prompt_tokens": prompt, "total_tokens": prompt},
+	})
+}
+
+// Embedding returns the deterministic unit vector of input.
+func Embedding(input string) []float64 {
+	sum := sha256.Sum256([]byte(input))
+	vector := make([]float64, EmbeddingDimensions)
+	var norm float64
+	for i := range vector {
+		// Two bytes of the digest per dimension, mapped to [-1, 1].
+		v := float64(binary.BigEndian.Uint16(sum[2*i:]))/math.MaxUint16*2 - 1
+		vector[i] = v
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+	return vector
+}
+
+// stream writes one server-sent event per word, then last and [DONE], waiting the latency before
+// the first event and latency/tokenIntervalDivisor between the others.
+func (s *Server) stream(c *gin.Context, words []string, chunk func(word string, first bool) any, last any) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	if !s.wait(c) {
+		return
+	}
+	interval := s.latency / tokenIntervalDivisor
+	for i, word := range words {
+		if i > 0 && !sleep(c, interval) {
+			return
+		}
+		writeEvent(c, chunk(word, i == 0))
+	}
+	writeEvent(c, last)
+	_, _ = c.Writer.WriteString("data: [DONE]\n\n")
+	c.Writer.Flush()
+}
+
+func writeEvent(c *gin.Context, event any) {
+	data, _ := json.Marshal(event)
+	_, _ = c.Writer.WriteString("data: " + string(data) + "\n\n")
+	c.Writer.Flush()
+}
+
+// wait simulates the time to the first token. It returns false when the client went away.
+func (s *Server) wait(c *gin.Context) bool {
+	return sleep(c, s.latency)
+}
+
+func sleep(c *gin.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.Request.Context().Done():
+		return false
+	}
+}
+
+func (s *Server) id() string {
+	return fmt.Sprintf("%x", s.now().UnixNano())
+}
+
+// reply splits Reply into words, keeping the separating space on each word after the first so
+// the streamed chunks join back into the text, and cuts it to maxTokens words when positive.
+func reply(maxTokens int) []string {
+	words := strings.Fields(Reply)
+	for i := 1; i < len(words); i++ {
+		words[i] = " " + words[i]
+	}
+	if maxTokens > 0 && maxTokens < len(words) {
+		words = words[:maxTokens]
+	}
+	return words
+}
+
+func countTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+func modelOf(model string) string {
+	if model == "" {
+		return DefaultModel
+	}
+	return model
+}
+
+// textOf returns the text of a message content, which is either a string or a list of parts.
+func textOf(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &parts) != nil {
+		return ""
+	}
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p.Type == "text" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+func stringOrList(raw json.RawMessage) ([]string, bool) {
+	if len(raw) == 0 {
+		return nil, true
+	}
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return []string{one}, true
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list, true
+	}
+	return nil, false
+}
+
+func bind(c *gin.Context, req any) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		invalidRequest(c, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func invalidRequest(c *gin.Context, message string) {
+	apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, message)
+}
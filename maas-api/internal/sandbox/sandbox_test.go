@@ -0,0 +1,183 @@
+package sandbox_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/sandbox"
+)
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	sandbox.NewServer(logger.Development(), 0).Register(r)
+	return r
+}
+
+func do(t *testing.T, r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+type usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+func TestChatCompletions(t *testing.T) {
+	w := do(t, newRouter(), http.MethodPost, "/v1/chat/completions",
+		`{"model": "granite", "max_tokens": 3, "messages": [{"role": "user", "content": "Hello there"}, {"role": "user", "content": [{"type": "text", "text": "again"}]}]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get("X-MaaS-Sandbox"))
+
+	var resp struct {
+		Object  string `json:"object"`
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage usage `json:"usage"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "chat.completion", resp.Object)
+	assert.Equal(t, "granite", resp.Model)
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "assistant", resp.Choices[0].Message.Role)
+	assert.Equal(t, "This is a", resp.Choices[0].Message.Content)
+	assert.Equal(t, usage{PromptTokens: 3, CompletionTokens: 3, TotalTokens: 6}, resp.Usage)
+}
+
+func TestChatCompletionsStream(t *testing.T) {
+	w := do(t, newRouter(), http.MethodPost, "/v1/chat/completions",
+		`{"stream": true, "max_tokens": 4, "messages": [{"role": "user", "content": "Hi"}]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	var content strings.Builder
+	var last struct {
+		Usage *usage `json:"usage"`
+	}
+	done := false
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+		var chunk struct {
+			Model   string `json:"model"`
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *usage `json:"usage"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+		assert.Equal(t, sandbox.DefaultModel, chunk.Model)
+		content.WriteString(chunk.Choices[0].Delta.Content)
+		last.Usage = chunk.Usage
+	}
+	assert.True(t, done, "stream must end with [DONE]")
+	assert.Equal(t, "This is a sandbox", content.String())
+	require.NotNil(t, last.Usage, "the final chunk carries the usage")
+	assert.Equal(t, usage{PromptTokens: 1, CompletionTokens: 4, TotalTokens: 5}, *last.Usage)
+}
+
+func TestCompletions(t *testing.T) {
+	w := do(t, newRouter(), http.MethodPost, "/v1/completions", `{"prompt": ["one two", "three"], "max_tokens": 2}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Choices []struct {
+			Text string `json:"text"`
+		} `json:"choices"`
+		Usage usage `json:"usage"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "This is", resp.Choices[0].Text)
+	assert.Equal(t, usage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5}, resp.Usage)
+}
+
+func TestEmbeddings(t *testing.T) {
+	w := do(t, newRouter(), http.MethodPost, "/v1/embeddings", `{"input": ["hello", "world", "hello"]}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 3)
+	assert.Len(t, resp.Data[0].Embedding, sandbox.EmbeddingDimensions)
+	assert.Equal(t, resp.Data[0].Embedding, resp.Data[2].Embedding, "the same input gets the same vector")
+	assert.NotEqual(t, resp.Data[0].Embedding, resp.Data[1].Embedding)
+
+	var norm float64
+	for _, v := range sandbox.Embedding("hello") {
+		norm += v * v
+	}
+	assert.InDelta(t, 1, math.Sqrt(norm), 1e-9)
+}
+
+func TestInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+		typ    string
+	}{
+		{name: "malformed body", method: http.MethodPost, path: "/v1/chat/completions", body: `{`, code: http.StatusBadRequest, typ: "invalid_request_error"},
+		{name: "no messages", method: http.MethodPost, path: "/v1/chat/completions", body: `{"messages": []}`, code: http.StatusBadRequest, typ: "invalid_request_error"},
+		{name: "bad prompt", method: http.MethodPost, path: "/v1/completions", body: `{"prompt": 1}`, code: http.StatusBadRequest, typ: "invalid_request_error"},
+		{name: "no input", method: http.MethodPost, path: "/v1/embeddings", body: `{}`, code: http.StatusBadRequest, typ: "invalid_request_error"},
+		{name: "unsupported path", method: http.MethodPost, path: "/v1/audio/speech", body: `{}`, code: http.StatusNotFound, typ: "not_found_error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(t, newRouter(), tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.code, w.Code)
+			var resp struct {
+				Error struct {
+					Type string `json:"type"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.typ, resp.Error.Type)
+		})
+	}
+}
+
+func TestListModelsAndHealth(t *testing.T) {
+	r := newRouter()
+	w := do(t, r, http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do(t, r, http.MethodGet, "/v1/models", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"sandbox"`)
+}
@@ -0,0 +1,53 @@
+package signedurl_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+)
+
+func TestMintAndVerify(t *testing.T) {
+	_, err := signedurl.NewSigner([]byte("short"), 0)
+	require.Error(t, err)
+
+	now := time.Unix(1_700_000_000, 0)
+	signer, err := signedurl.NewSigner(bytes.Repeat([]byte("a"), signedurl.MinSecretLength), 0)
+	require.NoError(t, err)
+	signer.SetClock(func() time.Time { return now })
+
+	token, grant, err := signer.Mint(signedurl.Grant{User: "alice", Groups: []string{"team"}, Model: "llm/granite"}, 0)
+	require.NoError(t, err)
+	assert.Contains(t, grant.ID, signedurl.IDPrefix)
+	assert.Equal(t, now.Add(signedurl.DefaultTTL).Unix(), grant.ExpiresAt)
+
+	verified, err := signer.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, grant, *verified)
+
+	other, err := signedurl.NewSigner(bytes.Repeat([]byte("b"), signedurl.MinSecretLength), 0)
+	require.NoError(t, err)
+	_, err = other.Verify(token)
+	require.ErrorIs(t, err, signedurl.ErrInvalid, "tokens only verify with the secret they were signed with")
+	_, err = signer.Verify("x" + token)
+	require.ErrorIs(t, err, signedurl.ErrInvalid)
+
+	now = now.Add(signedurl.DefaultTTL)
+	_, err = signer.Verify(token)
+	require.ErrorIs(t, err, signedurl.ErrExpired)
+
+	_, _, err = signer.Mint(signedurl.Grant{User: "alice", Model: "llm/granite"}, 2*signedurl.DefaultMaxTTL)
+	require.Error(t, err)
+}
+
+func TestFromPathAndRedact(t *testing.T) {
+	path := "/llm/granite/v1/chat/completions?stream=true&maas-signature=abc.def"
+	assert.Equal(t, "abc.def", signedurl.FromPath(path))
+	assert.Empty(t, signedurl.FromPath("/llm/granite/v1/chat/completions"))
+	assert.Equal(t, "/llm/granite/v1/chat/completions?stream=true&maas-signature=REDACTED", signedurl.Redact(path))
+	assert.Equal(t, "/v1/models?limit=1", signedurl.Redact("/v1/models?limit=1"))
+}
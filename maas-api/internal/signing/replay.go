@@ -0,0 +1,133 @@
+package signing
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/redis"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/warmstart"
+)
+
+// ErrCacheFull is returned by MemoryCache when it holds its maximum of unexpired nonces.
+var ErrCacheFull = errors.New("replay cache is full")
+
+// ReplayCache remembers nonces for as long as a request carrying them could still be accepted.
+type ReplayCache interface {
+	// Remember records key for ttl. It returns false when key is already recorded.
+	Remember(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// DefaultMemoryCacheSize is the default number of nonces a MemoryCache holds.
+const DefaultMemoryCacheSize = 1_000_000
+
+// MemoryCache keeps nonces in this replica only. With several replicas, a request replayed
+// against another replica is not detected; use RedisCache there.
+type MemoryCache struct {
+	mu         sync.Mutex
+	expiries   map[string]time.Time
+	maxEntries int
+	now        func() time.Time
+}
+
+var _ ReplayCache = (*MemoryCache)(nil)
+
+// NewMemoryCache creates a cache of at most maxEntries nonces; a non-positive value uses
+// DefaultMemoryCacheSize. When it is full, requests are rejected rather than forgetting nonces
+// early.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryCacheSize
+	}
+	return &MemoryCache{expiries: map[string]time.Time{}, maxEntries: maxEntries, now: time.Now}
+}
+
+// Remember implements ReplayCache.
+func (c *MemoryCache) Remember(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if expiry, ok := c.expiries[key]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	if len(c.expiries) >= c.maxEntries {
+		for k, expiry := range c.expiries {
+			if !now.Before(expiry) {
+				delete(c.expiries, k)
+			}
+		}
+		if len(c.expiries) >= c.maxEntries {
+			return false, ErrCacheFull
+		}
+	}
+	c.expiries[key] = now.Add(ttl)
+	return true, nil
+}
+
+var _ warmstart.Part = (*MemoryCache)(nil)
+
+// Entries implements warmstart.Part, returning the nonces that could still be replayed.
+func (c *MemoryCache) Entries() []warmstart.Entry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	entries := make([]warmstart.Entry, 0, len(c.expiries))
+	for key, expiry := range c.expiries {
+		if now.Before(expiry) {
+			entries = append(entries, warmstart.Entry{Key: key, Expiry: expiry})
+		}
+	}
+	return entries
+}
+
+// Merge implements warmstart.Part. Nonces beyond the cache's maximum are dropped.
+func (c *MemoryCache) Merge(entries []warmstart.Entry) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	merged := 0
+	for _, e := range entries {
+		if !now.Before(e.Expiry) {
+			continue
+		}
+		expiry, ok := c.expiries[e.Key]
+		if !ok && len(c.expiries) >= c.maxEntries {
+			break
+		}
+		if e.Expiry.After(expiry) {
+			c.expiries[e.Key] = e.Expiry
+		}
+		merged++
+	}
+	return merged
+}
+
+const redisKeyPrefix = "maas:nonce:"
+
+// RedisCache keeps nonces in Redis, so every replica sees the nonces the others accepted.
+type RedisCache struct {
+	client *redis.Client
+}
+
+var _ ReplayCache = (*RedisCache)(nil)
+
+// NewRedisCache creates a cache in the Redis at a redis:// or rediss:// URL.
+func NewRedisCache(rawURL string) (*RedisCache, error) {
+	client, err := redis.NewClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisCache{client: client}, nil
+}
+
+// Remember implements ReplayCache with SET NX, which is atomic across replicas.
+func (c *RedisCache) Remember(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	reply, err := c.client.Do(ctx, "SET", redisKeyPrefix+key, "1", "NX", "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10))
+	if err != nil {
+		return false, err
+	}
+	// OK when the key was set, nil when it already existed.
+	return reply == "OK", nil
+}
@@ -0,0 +1,172 @@
+// Package signing verifies signed requests from partner integrations. A partner signs each request
+// with a shared secret, a timestamp and a single-use nonce, so a captured request cannot be
+// replayed against metered models: its nonce has been seen, or its timestamp has gone stale.
+package signing
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// Request headers of a signed request.
+const (
+	HeaderTimestamp = "X-MaaS-Timestamp"
+	HeaderNonce     = "X-MaaS-Nonce"
+	HeaderSignature = "X-MaaS-Signature"
+)
+
+// Denial reasons, returned in x-ext-auth-reason.
+const (
+	ReasonRequired = reason.SignatureRequired
+	ReasonInvalid  = reason.InvalidSignature
+	ReasonStale    = reason.StaleSignature
+	ReasonReplayed = reason.ReplayedRequest
+)
+
+// DefaultMaxSkew is how far a request's timestamp may be from the server's clock.
+const DefaultMaxSkew = 5 * time.Minute
+
+const (
+	minNonceLength = 16
+	maxNonceLength = 128
+	minSecretBytes = 32
+)
+
+// Verifier checks the signatures of the users that have a signing secret. Requests of other users
+// are not checked.
+type Verifier struct {
+	secrets map[string][]byte
+	cache   ReplayCache
+	maxSkew time.Duration
+	now     func() time.Time
+}
+
+// NewVerifier creates a verifier for secrets by username, remembering nonces in cache. A
+// non-positive maxSkew uses DefaultMaxSkew.
+func NewVerifier(secrets map[string][]byte, cache ReplayCache, maxSkew time.Duration) *Verifier {
+	if cache == nil {
+		panic("replay cache cannot be nil")
+	}
+	if maxSkew <= 0 {
+		maxSkew = DefaultMaxSkew
+	}
+	return &Verifier{secrets: secrets, cache: cache, maxSkew: maxSkew, now: time.Now}
+}
+
+// SetClock checks timestamps against now instead of the local clock.
+func (v *Verifier) SetClock(now func() time.Time) {
+	v.now = now
+}
+
+// Users returns how many users must sign their requests.
+func (v *Verifier) Users() int {
+	return len(v.secrets)
+}
+
+// Verify checks the request of username, given its method, path (with the query) and headers by
+// lower-case name as Envoy sends them. It returns a denial reason and message, or empty strings
+// when the request may proceed. The error is set only when the replay cache failed.
+func (v *Verifier) Verify(ctx context.Context, username, method, path string, headers map[string]string) (string, string, error) {
+	secret, ok := v.secrets[username]
+	if !ok {
+		return "", "", nil
+	}
+	timestamp := headers[strings.ToLower(HeaderTimestamp)]
+	nonce := headers[strings.ToLower(HeaderNonce)]
+	signature := headers[strings.ToLower(HeaderSignature)]
+	if timestamp == "" || nonce == "" || signature == "" {
+		return ReasonRequired, fmt.Sprintf("requests must carry %s, %s and %s", HeaderTimestamp, HeaderNonce, HeaderSignature), nil
+	}
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return ReasonInvalid, fmt.Sprintf("%s must be %d to %d characters", HeaderNonce, minNonceLength, maxNonceLength), nil
+	}
+	sent, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(sent, mac(secret, method, path, timestamp, nonce)) {
+		return ReasonInvalid, "signature does not match the request", nil
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ReasonInvalid, HeaderTimestamp + " must be Unix seconds", nil
+	}
+	signedAt := time.Unix(seconds, 0)
+	now := v.now()
+	if signedAt.Before(now.Add(-v.maxSkew)) || signedAt.After(now.Add(v.maxSkew)) {
+		return ReasonStale, fmt.Sprintf("%s is more than %s from the server time", HeaderTimestamp, v.maxSkew), nil
+	}
+
+	// The nonce only needs remembering until the timestamp goes stale; after that the request is
+	// rejected as stale anyway.
+	fresh, err := v.cache.Remember(ctx, username+"\n"+nonce, signedAt.Add(v.maxSkew).Sub(now)+time.Second)
+	if err != nil {
+		return "", "", fmt.Errorf("replay cache: %w", err)
+	}
+	if !fresh {
+		return ReasonReplayed, "nonce has already been used", nil
+	}
+	return "", "", nil
+}
+
+// Sign returns the hex-encoded signature of a request, as a partner computes it for
+// X-MaaS-Signature: HMAC-SHA256 over the method, path, timestamp and nonce, each followed by a
+// newline.
+func Sign(secret []byte, method, path, timestamp, nonce string) string {
+	return hex.EncodeToString(mac(secret, method, path, timestamp, nonce))
+}
+
+func mac(secret []byte, method, path, timestamp, nonce string) []byte {
+	h := hmac.New(sha256.New, secret)
+	for _, part := range []string{strings.ToUpper(method), path, timestamp, nonce} {
+		h.Write([]byte(part))
+		h.Write([]byte{'\n'})
+	}
+	return h.Sum(nil)
+}
+
+// LoadSecrets reads signing secrets from a file, typically a mounted Secret. Each line is
+// "<username>:<base64 secret>" with a secret of at least 32 bytes; usernames may contain colons,
+// so the last one separates the secret. Blank lines and lines starting with # are skipped.
+func LoadSecrets(path string) (map[string][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open signing secrets file: %w", err)
+	}
+	defer f.Close()
+
+	secrets := map[string][]byte{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		i := strings.LastIndex(text, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("signing secrets line %d: expected <username>:<base64 secret>", line)
+		}
+		username := text[:i]
+		secret, err := base64.StdEncoding.DecodeString(text[i+1:])
+		if err != nil || len(secret) < minSecretBytes {
+			return nil, fmt.Errorf("signing secrets line %d: secret of %q must be at least %d base64-encoded bytes", line, username, minSecretBytes)
+		}
+		if _, dup := secrets[username]; dup {
+			return nil, fmt.Errorf("signing secrets line %d: duplicate user %q", line, username)
+		}
+		secrets[username] = secret
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read signing secrets file: %w", err)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,220 @@
+package signing_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signing"
+)
+
+var (
+	secret = []byte("0123456789abcdef0123456789abcdef")
+	now    = time.Unix(1_800_000_000, 0)
+)
+
+func signedHeaders(method, path string, signedAt time.Time, nonce string) map[string]string {
+	ts := strconv.FormatInt(signedAt.Unix(), 10)
+	return map[string]string{
+		"x-maas-timestamp": ts,
+		"x-maas-nonce":     nonce,
+		"x-maas-signature": signing.Sign(secret, method, path, ts, nonce),
+	}
+}
+
+func newVerifier() *signing.Verifier {
+	v := signing.NewVerifier(map[string][]byte{"partner": secret}, signing.NewMemoryCache(0), time.Minute)
+	v.SetClock(func() time.Time { return now })
+	return v
+}
+
+func TestVerify(t *testing.T) {
+	const path = "/llm/granite/v1/chat/completions"
+	tampered := signedHeaders("POST", path, now, "nonce-tampered-0001")
+	tampered["x-maas-signature"] = signing.Sign(secret, "POST", "/llm/llama/v1/chat/completions", tampered["x-maas-timestamp"], "nonce-tampered-0001")
+	otherSecret := signedHeaders("POST", path, now, "nonce-other-secret-1")
+	otherSecret["x-maas-signature"] = signing.Sign([]byte("another secret of at least 32 bytes"), "POST", path, otherSecret["x-maas-timestamp"], "nonce-other-secret-1")
+
+	tests := []struct {
+		name     string
+		username string
+		headers  map[string]string
+		reason   string
+	}{
+		{name: "user without a secret", username: "alice", headers: map[string]string{}},
+		{name: "valid signature", username: "partner", headers: signedHeaders("POST", path, now, "nonce-valid-000001")},
+		{name: "lower-case method", username: "partner", headers: signedHeaders("post", path, now.Add(-30*time.Second), "nonce-valid-000002")},
+		{name: "unsigned", username: "partner", headers: map[string]string{}, reason: signing.ReasonRequired},
+		{name: "short nonce", username: "partner", headers: signedHeaders("POST", path, now, "short"), reason: signing.ReasonInvalid},
+		{name: "signed for another path", username: "partner", headers: tampered, reason: signing.ReasonInvalid},
+		{name: "signed with another secret", username: "partner", headers: otherSecret, reason: signing.ReasonInvalid},
+		{name: "not hex", username: "partner", headers: map[string]string{"x-maas-timestamp": "1", "x-maas-nonce": "nonce-not-hex-0001", "x-maas-signature": "zz"}, reason: signing.ReasonInvalid},
+		{name: "too old", username: "partner", headers: signedHeaders("POST", path, now.Add(-2*time.Minute), "nonce-too-old-0001"), reason: signing.ReasonStale},
+		{name: "too far ahead", username: "partner", headers: signedHeaders("POST", path, now.Add(2*time.Minute), "nonce-too-new-0001"), reason: signing.ReasonStale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, _, err := newVerifier().Verify(context.Background(), tt.username, "POST", path, tt.headers)
+			require.NoError(t, err)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestVerifyRejectsReplays(t *testing.T) {
+	const path = "/llm/granite/v1/chat/completions"
+	v := signing.NewVerifier(map[string][]byte{"partner": secret, "other": secret}, signing.NewMemoryCache(0), time.Minute)
+	v.SetClock(func() time.Time { return now })
+	headers := signedHeaders("POST", path, now, "nonce-replayed-0001")
+
+	reason, _, err := v.Verify(context.Background(), "partner", "POST", path, headers)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+
+	reason, message, err := v.Verify(context.Background(), "partner", "POST", path, headers)
+	require.NoError(t, err)
+	assert.Equal(t, signing.ReasonReplayed, reason)
+	assert.Equal(t, "nonce has already been used", message)
+
+	// Nonces are per user.
+	reason, _, err = v.Verify(context.Background(), "other", "POST", path, headers)
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+}
+
+type failingCache struct{}
+
+func (failingCache) Remember(context.Context, string, time.Duration) (bool, error) {
+	return false, signing.ErrCacheFull
+}
+
+func TestVerifyFailsClosed(t *testing.T) {
+	v := signing.NewVerifier(map[string][]byte{"partner": secret}, failingCache{}, time.Minute)
+	v.SetClock(func() time.Time { return now })
+	_, _, err := v.Verify(context.Background(), "partner", "GET", "/", signedHeaders("GET", "/", now, "nonce-cache-down-01"))
+	require.ErrorIs(t, err, signing.ErrCacheFull)
+}
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := signing.NewMemoryCache(1)
+
+	fresh, err := c.Remember(ctx, "expired", 0)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	// The expired entry is swept to make room.
+	fresh, err = c.Remember(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = c.Remember(ctx, "a", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, fresh)
+
+	_, err = c.Remember(ctx, "b", time.Hour)
+	require.ErrorIs(t, err, signing.ErrCacheFull)
+}
+
+// fakeRedis implements SET key value NX PX ttl on a single connection, ignoring the TTL.
+func fakeRedis(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		keys := map[string]bool{}
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			args := make([]string, n)
+			for i := range args {
+				header, _ := r.ReadString('\n')
+				size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+				arg := make([]byte, size+2)
+				if _, err := io.ReadFull(r, arg); err != nil {
+					return
+				}
+				args[i] = string(arg[:size])
+			}
+			reply := "$-1\r\n"
+			if args[0] == "SET" && !keys[args[1]] {
+				keys[args[1]] = true
+				reply = "+OK\r\n"
+			}
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedisCache(t *testing.T) {
+	c, err := signing.NewRedisCache("redis://" + fakeRedis(t))
+	require.NoError(t, err)
+
+	fresh, err := c.Remember(context.Background(), "partner\nnonce", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = c.Remember(context.Background(), "partner\nnonce", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, fresh)
+}
+
+func TestLoadSecrets(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(secret)
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "usernames with colons",
+			content: fmt.Sprintf("# partners\n\nacme-bot:%s\nsystem:serviceaccount:acme:bot:%s\n", encoded, encoded),
+			want:    []string{"acme-bot", "system:serviceaccount:acme:bot"},
+		},
+		{name: "missing secret", content: "acme-bot\n", wantErr: "line 1"},
+		{name: "short secret", content: "acme-bot:" + base64.StdEncoding.EncodeToString([]byte("short")), wantErr: "at least 32"},
+		{name: "duplicate user", content: fmt.Sprintf("acme-bot:%s\nacme-bot:%s\n", encoded, encoded), wantErr: "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "secrets")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			secrets, err := signing.LoadSecrets(path)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, secrets, len(tt.want))
+			for _, user := range tt.want {
+				assert.Equal(t, secret, secrets[user])
+			}
+		})
+	}
+}
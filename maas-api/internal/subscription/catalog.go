@@ -0,0 +1,149 @@
+package subscription
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+var (
+	catalogReloadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_tier_catalog_reloads_total",
+		Help: "Reloads of the tier catalog after MaaSSubscription changes, by result (success, error).",
+	}, []string{"result"})
+	catalogTiers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "maas_tier_catalog_tiers",
+		Help: "Tiers in the current tier catalog, by state (valid, invalid). Invalid tiers keep their last valid definition, if any.",
+	}, []string{"state"})
+	catalogLastReload = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "maas_tier_catalog_last_reload_timestamp_seconds",
+		Help: "Unix time of the last successful tier catalog reload.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(catalogReloadsTotal, catalogTiers, catalogLastReload)
+}
+
+// Catalog holds the parsed MaaSSubscriptions (tiers) that subscription selection reads, so
+// requests do not parse every MaaSSubscription again. MaaSSubscription events mark it stale and
+// the next read rebuilds it and swaps it in atomically: a request sees either the old catalog or
+// the new one, never a mix, and tier changes apply without a restart.
+//
+// A tier that fails to parse keeps its last valid definition, so a bad edit does not cut off its
+// users. If listing fails, the previous catalog stays in use.
+type Catalog struct {
+	lister Lister
+	logger *logger.Logger
+
+	// generation counts MaaSSubscription events; a snapshot built at an older generation is stale.
+	generation atomic.Uint64
+	current    atomic.Pointer[catalogSnapshot]
+	reloadMu   sync.Mutex
+}
+
+type catalogSnapshot struct {
+	generation    uint64
+	subscriptions []subscription
+}
+
+// NewCatalog creates a catalog of the MaaSSubscriptions in lister. It is built on first use.
+func NewCatalog(log *logger.Logger, lister Lister) *Catalog {
+	if log == nil {
+		log = logger.Production()
+	}
+	c := &Catalog{lister: lister, logger: log}
+	c.generation.Store(1)
+	return c
+}
+
+// subscriptions returns the current tiers. The slice is shared and must not be modified.
+func (c *Catalog) subscriptions() ([]subscription, error) {
+	if snap := c.current.Load(); snap != nil && snap.generation == c.generation.Load() {
+		return snap.subscriptions, nil
+	}
+	return c.reload()
+}
+
+func (c *Catalog) reload() ([]subscription, error) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	// Another request may have reloaded while this one waited.
+	generation := c.generation.Load()
+	previous := c.current.Load()
+	if previous != nil && previous.generation == generation {
+		return previous.subscriptions, nil
+	}
+
+	objects, err := c.lister.List()
+	if err != nil {
+		catalogReloadsTotal.WithLabelValues("error").Inc()
+		if previous != nil {
+			c.logger.Warn("Failed to reload tier catalog, keeping the previous one", "error", err)
+			return previous.subscriptions, nil
+		}
+		return nil, err
+	}
+
+	lastValid := map[string]subscription{}
+	if previous != nil {
+		for _, sub := range previous.subscriptions {
+			lastValid[sub.key()] = sub
+		}
+	}
+	subscriptions := make([]subscription, 0, len(objects))
+	invalid := 0
+	for _, obj := range objects {
+		sub, err := parseSubscription(obj)
+		if err == nil {
+			subscriptions = append(subscriptions, sub)
+			continue
+		}
+		invalid++
+		key := obj.GetNamespace() + "/" + obj.GetName()
+		if last, ok := lastValid[key]; ok {
+			c.logger.Warn("Failed to parse subscription, keeping its last valid definition", "subscription", key, "error", err)
+			subscriptions = append(subscriptions, last)
+			continue
+		}
+		c.logger.Warn("Failed to parse subscription, skipping", "subscription", key, "error", err)
+	}
+
+	c.current.Store(&catalogSnapshot{generation: generation, subscriptions: subscriptions})
+	catalogReloadsTotal.WithLabelValues("success").Inc()
+	catalogTiers.WithLabelValues("valid").Set(float64(len(objects) - invalid))
+	catalogTiers.WithLabelValues("invalid").Set(float64(invalid))
+	catalogLastReload.SetToCurrentTime()
+	if previous != nil {
+		c.logger.Info("Tier catalog reloaded", "tiers", len(subscriptions), "invalid", invalid)
+	}
+	return subscriptions, nil
+}
+
+// Invalidate marks the catalog stale, so the next read rebuilds it.
+func (c *Catalog) Invalidate() {
+	c.generation.Add(1)
+}
+
+// EventHandler invalidates the catalog on any MaaSSubscription change, ignoring periodic
+// resyncs. Register it on the MaaSSubscription informer.
+func (c *Catalog) EventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(any) { c.Invalidate() },
+		UpdateFunc: func(oldObj, newObj any) {
+			oldU, ok1 := oldObj.(*unstructured.Unstructured)
+			newU, ok2 := newObj.(*unstructured.Unstructured)
+			if ok1 && ok2 && oldU.GetResourceVersion() == newU.GetResourceVersion() {
+				return
+			}
+			c.Invalidate()
+		},
+		DeleteFunc: func(any) { c.Invalidate() },
+	}
+}
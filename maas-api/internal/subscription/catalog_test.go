@@ -0,0 +1,77 @@
+package subscription_test
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+func newCatalogSelector(lister *fakeLister) (*subscription.Selector, *subscription.Catalog) {
+	catalog := subscription.NewCatalog(logger.New(false), lister)
+	sel := subscription.NewSelector(logger.New(false), lister)
+	sel.SetCatalog(catalog)
+	return sel, catalog
+}
+
+func TestCatalogReloadsOnChange(t *testing.T) {
+	premium := createSubscription("premium", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	premium.SetResourceVersion("1")
+	lister := &fakeLister{subscriptions: []*unstructured.Unstructured{premium}}
+	sel, catalog := newCatalogSelector(lister)
+
+	if got, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil || got.Name != "premium" {
+		t.Fatalf("Select = %v, %v; want premium", got, err)
+	}
+
+	// Move the tier to another group. The catalog keeps serving the old definition until told.
+	moved := createSubscription("premium", []string{"g2"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	moved.SetResourceVersion("2")
+	lister.subscriptions = []*unstructured.Unstructured{moved}
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil {
+		t.Fatalf("Select before the event: %v, want the previous catalog", err)
+	}
+
+	// A resync with the same resource version does not reload.
+	catalog.EventHandler().OnUpdate(premium, premium)
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil {
+		t.Fatalf("Select after a resync: %v, want the previous catalog", err)
+	}
+
+	catalog.EventHandler().OnUpdate(premium, moved)
+	var noSub *subscription.NoSubscriptionError
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); !errors.As(err, &noSub) {
+		t.Errorf("g1 after reload: got %v, want NoSubscriptionError", err)
+	}
+	if got, err := sel.Select([]string{"g2"}, "bob", "", ""); err != nil || got.Name != "premium" {
+		t.Errorf("g2 after reload: got %v, %v; want premium", got, err)
+	}
+}
+
+func TestCatalogKeepsLastValidDefinition(t *testing.T) {
+	premium := createSubscription("premium", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	lister := &fakeLister{subscriptions: []*unstructured.Unstructured{premium}}
+	sel, catalog := newCatalogSelector(lister)
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+
+	broken := premium.DeepCopy()
+	if err := unstructured.SetNestedField(broken.Object, "next tuesday", "spec", "expiresAt"); err != nil {
+		t.Fatal(err)
+	}
+	brokenNew := createSubscription("fresh", []string{"g1"}, nil, 20, defaultTestTokenRateLimit, "", "")
+	if err := unstructured.SetNestedField(brokenNew.Object, "soon", "spec", "expiresAt"); err != nil {
+		t.Fatal(err)
+	}
+	lister.subscriptions = []*unstructured.Unstructured{broken, brokenNew}
+	catalog.Invalidate()
+
+	got, err := sel.Select([]string{"g1"}, "alice", "", "")
+	if err != nil || got.Name != "premium" {
+		t.Errorf("Select = %v, %v; want premium kept at its last valid definition and fresh skipped", got, err)
+	}
+}
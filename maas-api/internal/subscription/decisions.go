@@ -0,0 +1,135 @@
+package subscription
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+)
+
+// maxDecisionEntries bounds the cache. When it is full of live entries, new decisions are
+// simply not cached.
+const maxDecisionEntries = 10000
+
+// DecisionCache remembers Select results for a short TTL, keyed by user, groups, requested
+// subscription and requested model, so the authorization path does not re-parse every
+// MaaSSubscription on each inference request. Any change to a MaaSSubscription or MaaSModelRef
+// drops every entry, since ownership, included tiers and model lineage can all change the outcome.
+// A nil DecisionCache caches nothing.
+type DecisionCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]decision
+}
+
+type decision struct {
+	resp    *SelectResponse
+	err     error
+	expires time.Time
+}
+
+// NewDecisionCache creates a cache whose entries live for ttl. It returns nil when ttl is not positive.
+func NewDecisionCache(ttl time.Duration) *DecisionCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &DecisionCache{ttl: ttl, entries: map[string]decision{}}
+}
+
+func decisionKey(groups []string, username, requestedSubscription, requestedModel string) string {
+	sorted := make([]string, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, strings.TrimSpace(g))
+	}
+	slices.Sort(sorted)
+	return username + "\x00" + strings.Join(sorted, ",") + "\x00" + requestedSubscription + "\x00" + requestedModel
+}
+
+// get returns a cached decision. Its response is a copy, so callers may set fields such as
+// QuotaWarning on it.
+func (c *DecisionCache) get(key string) (decision, bool) {
+	if c == nil {
+		return decision{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.entries[key]
+	if !ok {
+		return decision{}, false
+	}
+	if !time.Now().Before(d.expires) {
+		delete(c.entries, key)
+		return decision{}, false
+	}
+	if d.resp != nil {
+		resp := *d.resp
+		d.resp = &resp
+	}
+	return d, true
+}
+
+// put caches a decision. Failures other than a denial are not cached. A selected subscription
+// that expires, or whose downgrade grace period ends, before the TTL is only cached until then,
+// measured from clockNow, the time on the clock the selector evaluates expiry with.
+func (c *DecisionCache) put(key string, resp *SelectResponse, err error, clockNow time.Time) {
+	if c == nil || (err != nil && ErrorCode(err) == reason.InternalError) {
+		return
+	}
+	now := time.Now()
+	expires := now.Add(c.ttl)
+	if resp != nil {
+		for _, ends := range []time.Time{resp.ExpiresAt, resp.GraceEndsAt} {
+			if !ends.IsZero() && ends.Sub(clockNow) < expires.Sub(now) {
+				expires = now.Add(ends.Sub(clockNow))
+			}
+		}
+		stored := *resp
+		resp = &stored
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxDecisionEntries {
+		for k, d := range c.entries {
+			if !now.Before(d.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxDecisionEntries {
+			return
+		}
+	}
+	c.entries[key] = decision{resp: resp, err: err, expires: expires}
+}
+
+// Purge drops every cached decision.
+func (c *DecisionCache) Purge() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// EventHandler purges the cache on any change to the watched resources, ignoring periodic
+// resyncs. Register it on the MaaSSubscription, MaaSModelRef and MaaSRateLimitOverride informers.
+func (c *DecisionCache) EventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(any) { c.Purge() },
+		UpdateFunc: func(oldObj, newObj any) {
+			oldU, ok1 := oldObj.(*unstructured.Unstructured)
+			newU, ok2 := newObj.(*unstructured.Unstructured)
+			if ok1 && ok2 && oldU.GetResourceVersion() == newU.GetResourceVersion() {
+				return
+			}
+			c.Purge()
+		},
+		DeleteFunc: func(any) { c.Purge() },
+	}
+}
@@ -0,0 +1,90 @@
+package subscription_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+func TestSelectDecisionCache(t *testing.T) {
+	premium := createSubscription("premium", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	lister := &fakeLister{subscriptions: []*unstructured.Unstructured{premium}}
+	decisions := subscription.NewDecisionCache(time.Minute)
+	sel := subscription.NewSelector(logger.New(false), lister)
+	sel.SetDecisionCache(decisions)
+
+	got, err := sel.Select([]string{"g1"}, "alice", "", "")
+	if err != nil || got.Name != "premium" {
+		t.Fatalf("Select = %v, %v; want premium", got, err)
+	}
+	got.QuotaWarning = "mutated by caller"
+
+	// Removing the subscription without an informer event keeps serving the cached decision.
+	lister.subscriptions = nil
+	got, err = sel.Select([]string{"g1"}, "alice", "", "")
+	if err != nil || got.Name != "premium" {
+		t.Fatalf("cached Select = %v, %v; want premium", got, err)
+	}
+	if got.QuotaWarning != "" {
+		t.Errorf("cached response shares caller mutations: QuotaWarning = %q", got.QuotaWarning)
+	}
+
+	// Group order does not change the key.
+	if _, err := sel.Select([]string{" g1"}, "alice", "", ""); err != nil {
+		t.Errorf("Select with reordered groups missed the cache: %v", err)
+	}
+
+	decisions.EventHandler().OnDelete(premium)
+	_, err = sel.Select([]string{"g1"}, "alice", "", "")
+	var noSub *subscription.NoSubscriptionError
+	if !errors.As(err, &noSub) {
+		t.Fatalf("after invalidation: got %v, want NoSubscriptionError", err)
+	}
+
+	// Denials are cached too, until the next change.
+	lister.subscriptions = []*unstructured.Unstructured{premium}
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); !errors.As(err, &noSub) {
+		t.Errorf("cached denial: got %v, want NoSubscriptionError", err)
+	}
+	decisions.EventHandler().OnAdd(premium, false)
+	if got, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil || got.Name != "premium" {
+		t.Errorf("after add: got %v, %v; want premium", got, err)
+	}
+}
+
+func TestDecisionCacheResyncKeepsEntries(t *testing.T) {
+	premium := createSubscription("premium", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	premium.SetResourceVersion("1")
+	lister := &fakeLister{subscriptions: []*unstructured.Unstructured{premium}}
+	decisions := subscription.NewDecisionCache(time.Minute)
+	sel := subscription.NewSelector(logger.New(false), lister)
+	sel.SetDecisionCache(decisions)
+
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	lister.subscriptions = nil
+
+	decisions.EventHandler().OnUpdate(premium, premium)
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil {
+		t.Errorf("resync dropped the cache: %v", err)
+	}
+
+	changed := premium.DeepCopy()
+	changed.SetResourceVersion("2")
+	decisions.EventHandler().OnUpdate(premium, changed)
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err == nil {
+		t.Error("update did not invalidate the cache")
+	}
+}
+
+func TestDecisionCacheDisabled(t *testing.T) {
+	if subscription.NewDecisionCache(0) != nil {
+		t.Error("NewDecisionCache(0) should return nil")
+	}
+}
@@ -0,0 +1,82 @@
+package subscription
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var graceSelectionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_subscription_downgrade_grace_selections_total",
+	Help: "Selections of a subscription for a caller removed from its owners whose downgrade grace period has not ended, by subscription.",
+}, []string{"subscription"})
+
+func init() {
+	prometheus.MustRegister(graceSelectionsTotal)
+}
+
+// revokedOwner is a user or group the controller recorded as removed from a subscription's
+// owners, with the end of its downgrade grace period.
+type revokedOwner struct {
+	Group       string
+	User        string
+	GraceEndsAt time.Time
+}
+
+// parseRevokedOwners reads status.revokedOwners. Entries with an unparsable end are skipped.
+func parseRevokedOwners(obj *unstructured.Unstructured) []revokedOwner {
+	raw, found, _ := unstructured.NestedSlice(obj.Object, "status", "revokedOwners")
+	if !found {
+		return nil
+	}
+	var revoked []revokedOwner
+	for _, r := range raw {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		endsAt, _ := m["graceEndsAt"].(string)
+		t, err := time.Parse(time.RFC3339, endsAt)
+		if err != nil {
+			continue
+		}
+		group, _ := m["group"].(string)
+		user, _ := m["user"].(string)
+		revoked = append(revoked, revokedOwner{Group: group, User: user, GraceEndsAt: t})
+	}
+	return revoked
+}
+
+// graceEndsAt returns when the caller's downgrade grace period on the subscription ends, or the
+// zero time when the caller was not removed from its owners or the period has ended. When the
+// caller matches several removed owners, the latest end applies.
+func (s *subscription) graceEndsAt(username string, groups []string, now time.Time) time.Time {
+	var ends time.Time
+	for _, r := range s.RevokedOwners {
+		if !now.Before(r.GraceEndsAt) || !r.GraceEndsAt.After(ends) {
+			continue
+		}
+		if (r.User != "" && r.User == username) ||
+			(r.Group != "" && slices.ContainsFunc(groups, func(g string) bool { return strings.TrimSpace(g) == r.Group })) {
+			ends = r.GraceEndsAt
+		}
+	}
+	return ends
+}
+
+// inGrace marks a selection made only through the caller's downgrade grace period.
+func (s *Selector) inGrace(resp *SelectResponse, username string, graceEndsAt time.Time) *SelectResponse {
+	resp.GraceEndsAt = graceEndsAt
+	resp.Warning = fmt.Sprintf("access to subscription %s/%s was removed and ends at %s; move to another subscription",
+		resp.Namespace, resp.Name, graceEndsAt.UTC().Format(time.RFC3339))
+	s.logger.Warn("Selected subscription during downgrade grace period",
+		"username", username,
+		"subscription", resp.Namespace+"/"+resp.Name,
+		"graceEndsAt", graceEndsAt,
+	)
+	return resp
+}
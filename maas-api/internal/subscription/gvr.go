@@ -6,9 +6,16 @@ const (
 	maasGroup    = "maas.opendatahub.io"
 	maasVersion  = "v1alpha1"
 	maasResource = "maassubscriptions"
+
+	overrideResource = "maasratelimitoverrides"
 )
 
 // GVR returns the GroupVersionResource for MaaSSubscription CRs.
 func GVR() schema.GroupVersionResource {
 	return schema.GroupVersionResource{Group: maasGroup, Version: maasVersion, Resource: maasResource}
 }
+
+// OverrideGVR returns the GroupVersionResource for MaaSRateLimitOverride CRs.
+func OverrideGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: maasGroup, Version: maasVersion, Resource: overrideResource}
+}
@@ -1,19 +1,62 @@
 package subscription
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/audit"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/identity"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/metering"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
 )
 
+// QuotaWarner returns a soft quota warning for a user under a model-scoped subscription key
+// (namespace/name@modelNamespace/modelName), or "" when no warning applies.
+type QuotaWarner interface {
+	QuotaWarning(ctx context.Context, username, subscriptionKey string) string
+}
+
+// TokenReporter returns the tokens a user may still use under a model-scoped subscription key
+// before the gateway rate limits them, or false when it cannot tell. limit is the subscription's
+// token limit, all of which is left until the user's first request.
+type TokenReporter interface {
+	RemainingTokens(ctx context.Context, username, subscriptionKey string, limit int64) (int64, bool)
+}
+
+// BudgetChecker reports whether a user has consumed the token budget under a model-scoped
+// subscription key. It returns a message and the time until the budget resets, or "" when
+// requests may proceed.
+type BudgetChecker interface {
+	BudgetExhausted(ctx context.Context, username, subscriptionKey string) (string, time.Duration)
+}
+
+// ConcurrencyCounter reports the requests a user has in progress under a model-scoped
+// subscription key.
+type ConcurrencyCounter interface {
+	InFlight(ctx context.Context, username, subscriptionKey string) (int64, error)
+}
+
 // Handler handles subscription selection requests.
 type Handler struct {
-	selector *Selector
-	logger   *logger.Logger
+	selector    *Selector
+	logger      *logger.Logger
+	quotaWarner QuotaWarner
+	tokens      TokenReporter
+	budgets     BudgetChecker
+	concurrency ConcurrencyCounter
+	meter       *metering.Meter
+	auditor     *audit.Auditor
 }
 
 // NewHandler creates a new subscription handler.
@@ -27,6 +70,39 @@ func NewHandler(log *logger.Logger, selector *Selector) *Handler {
 	}
 }
 
+// SetQuotaWarner enables soft quota warnings in selection responses.
+func (h *Handler) SetQuotaWarner(w QuotaWarner) {
+	h.quotaWarner = w
+}
+
+// SetTokenReporter returns the caller's remaining tokens in selection responses, for the
+// X-MaaS-Remaining-Tokens response header.
+func (h *Handler) SetTokenReporter(r TokenReporter) {
+	h.tokens = r
+}
+
+// SetBudgetChecker fails selection with quota_exhausted once the caller has consumed the token
+// budget of the requested model.
+func (h *Handler) SetBudgetChecker(b BudgetChecker) {
+	h.budgets = b
+}
+
+// SetConcurrencyCounter reports the caller's requests in progress next to each concurrency cap in
+// GET /v1/limits.
+func (h *Handler) SetConcurrencyCounter(c ConcurrencyCounter) {
+	h.concurrency = c
+}
+
+// SetMeter records each selection in the usage metrics.
+func (h *Handler) SetMeter(m *metering.Meter) {
+	h.meter = m
+}
+
+// SetAuditor records each selection in the audit log.
+func (h *Handler) SetAuditor(a *audit.Auditor) {
+	h.auditor = a
+}
+
 // SelectSubscription handles POST /internal/v1/subscriptions/select requests.
 //
 // This endpoint is called by Authorino during AuthPolicy evaluation to determine
@@ -37,7 +113,8 @@ func NewHandler(log *logger.Logger, selector *Selector) *Handler {
 // Selection logic:
 //  1. If requestedSubscription is provided, validate user has access and return it
 //  2. Otherwise, if user belongs to only one subscription, return it
-//  3. If user belongs to multiple subscriptions, require explicit selection via header
+//  3. If user belongs to multiple subscriptions, require explicit selection via header,
+//     or apply the configured tie-break rule when multi-subscription membership is enabled
 //
 // This endpoint is protected by NetworkPolicy and should only be accessible from
 // Authorino pods. No additional authentication is needed as the groups/username
@@ -49,16 +126,30 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 	)
 
 	var req SelectRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	id, verified := identity.FromContext(c)
+	var err error
+	if verified {
+		// The caller's verified JWT supplies the identity, so the body need not name the user.
+		err = json.NewDecoder(c.Request.Body).Decode(&req)
+	} else {
+		err = c.ShouldBindJSON(&req)
+	}
+	if err != nil {
 		h.logger.Warn("Invalid request body",
 			"error", err.Error(),
 		)
 		c.JSON(http.StatusOK, SelectResponse{
-			Error:   "bad_request",
+			Error:   reason.BadRequest,
 			Message: "invalid request body: " + err.Error(),
 		})
 		return
 	}
+	if verified {
+		req.Username, req.Groups = id.Username, id.Groups
+		if id.Tier != "" {
+			req.RequestedSubscription = id.Tier
+		}
+	}
 
 	h.logger.Debug("Processing subscription selection",
 		"username", req.Username,
@@ -67,13 +158,32 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 		"requestedModel", req.RequestedModel,
 	)
 
-	response, err := h.selector.Select(req.Groups, req.Username, req.RequestedSubscription, req.RequestedModel)
+	// Tier evaluation is traced under the requested model's tracing policy.
+	ctx, span := tracing.StartModelSpan(c.Request.Context(), "maas.subscription.select", req.RequestedModel)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("maas.user", req.Username),
+		attribute.StringSlice("maas.groups", req.Groups),
+		attribute.String("maas.requested_subscription", req.RequestedSubscription),
+	)
+
+	start := time.Now()
+	response, err := h.selector.SelectForHost(req.Groups, req.Username, req.RequestedSubscription, req.RequestedModel, req.Host)
 	if err != nil {
+		span.SetAttributes(attribute.String(tracing.ReasonAttribute, ErrorCode(err)))
+		h.meter.Decision("select", req.RequestedModel, "", req.Username, ErrorCode(err), time.Since(start))
+		h.auditor.Decision("select", req.RequestedModel, "", req.Username, "", ErrorCode(err))
+
 		var noSubErr *NoSubscriptionError
 		var notFoundErr *SubscriptionNotFoundError
 		var accessDeniedErr *AccessDeniedError
 		var multipleSubsErr *MultipleSubscriptionsError
 		var modelNotInSubErr *ModelNotInSubscriptionError
+		var hostMismatchErr *HostMismatchError
+		var modelDeletedErr *ModelDeletedError
+		var modelNotServedErr *ModelNotServedError
+		var modelMaintenanceErr *ModelMaintenanceError
+		var clusterUnavailableErr *ModelClusterUnavailableError
 
 		if errors.As(err, &noSubErr) {
 			h.logger.Debug("No subscription found for user",
@@ -81,7 +191,7 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 				"groups", req.Groups,
 			)
 			c.JSON(http.StatusOK, SelectResponse{
-				Error:   "not_found",
+				Error:   reason.NotFound,
 				Message: err.Error(),
 			})
 			return
@@ -92,7 +202,7 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 				"subscription", req.RequestedSubscription,
 			)
 			c.JSON(http.StatusOK, SelectResponse{
-				Error:   "not_found",
+				Error:   reason.NotFound,
 				Message: err.Error(),
 			})
 			return
@@ -104,7 +214,7 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 				"subscription", req.RequestedSubscription,
 			)
 			c.JSON(http.StatusOK, SelectResponse{
-				Error:   "access_denied",
+				Error:   reason.AccessDenied,
 				Message: err.Error(),
 			})
 			return
@@ -116,7 +226,7 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 				"subscriptions", multipleSubsErr.Subscriptions,
 			)
 			c.JSON(http.StatusOK, SelectResponse{
-				Error:   "multiple_subscriptions",
+				Error:   reason.MultipleSubscriptions,
 				Message: err.Error(),
 			})
 			return
@@ -128,7 +238,65 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 				"model", modelNotInSubErr.Model,
 			)
 			c.JSON(http.StatusOK, SelectResponse{
-				Error:   "model_not_in_subscription",
+				Error:   reason.ModelNotInSubscription,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.As(err, &hostMismatchErr) {
+			h.logger.Debug("Subscription not served on request host",
+				"subscription", hostMismatchErr.Subscription,
+				"host", hostMismatchErr.Host,
+			)
+			c.JSON(http.StatusOK, SelectResponse{
+				Error:   reason.HostMismatch,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.As(err, &modelDeletedErr) {
+			h.logger.Debug("Requested model is soft-deleted",
+				"model", modelDeletedErr.Model,
+			)
+			c.JSON(http.StatusOK, SelectResponse{
+				Error:   reason.ModelDeleted,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.As(err, &modelNotServedErr) {
+			h.logger.Debug("Requested model is not served by this instance",
+				"model", modelNotServedErr.Model,
+			)
+			c.JSON(http.StatusOK, SelectResponse{
+				Error:   reason.ModelNotFound,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.As(err, &modelMaintenanceErr) {
+			h.logger.Debug("Requested model is in maintenance",
+				"model", modelMaintenanceErr.Model,
+			)
+			c.JSON(http.StatusOK, SelectResponse{
+				Error:      reason.ModelMaintenance,
+				Message:    err.Error(),
+				RetryAfter: RetryAfterSeconds(modelMaintenanceErr.RetryAfter),
+			})
+			return
+		}
+
+		if errors.As(err, &clusterUnavailableErr) {
+			h.logger.Debug("Cluster hosting the requested model is unavailable",
+				"model", clusterUnavailableErr.Model,
+				"cluster", clusterUnavailableErr.Cluster,
+			)
+			c.JSON(http.StatusOK, SelectResponse{
+				Error:   reason.ClusterUnavailable,
 				Message: err.Error(),
 			})
 			return
@@ -140,52 +308,99 @@ func (h *Handler) SelectSubscription(c *gin.Context) {
 			"username", req.Username,
 		)
 		c.JSON(http.StatusOK, SelectResponse{
-			Error:   "internal_error",
+			Error:   reason.InternalError,
 			Message: "failed to select subscription: " + err.Error(),
 		})
 		return
 	}
 
+	key := response.Namespace + "/" + response.Name + "@" + req.RequestedModel
+	if h.budgets != nil && req.RequestedModel != "" {
+		if message, _ := h.budgets.BudgetExhausted(ctx, req.Username, key); message != "" {
+			span.SetAttributes(attribute.String(tracing.ReasonAttribute, reason.QuotaExhausted))
+			h.meter.Decision("select", req.RequestedModel, response.Name, req.Username, reason.QuotaExhausted, time.Since(start))
+			h.auditor.Decision("select", req.RequestedModel, response.Name, req.Username, "", reason.QuotaExhausted)
+			h.logger.Debug("Token budget exhausted",
+				"username", req.Username,
+				"subscription", response.Name,
+				"model", req.RequestedModel,
+			)
+			c.JSON(http.StatusOK, SelectResponse{
+				Error:   reason.QuotaExhausted,
+				Message: message,
+			})
+			return
+		}
+	}
+
+	if h.quotaWarner != nil && req.RequestedModel != "" {
+		response.QuotaWarning = h.quotaWarner.QuotaWarning(ctx, req.Username, key)
+	}
+	if req.RequestedModel != "" {
+		response.Model = req.RequestedModel
+		if h.tokens != nil {
+			response.RemainingTokens = RemainingTokens(ctx, h.tokens, req.Username, key, response.RateLimits)
+		}
+	}
+	span.SetAttributes(
+		attribute.String("maas.subscription", response.Name),
+		attribute.String("maas.selected_by", response.SelectedBy),
+	)
+
+	h.meter.Decision("select", req.RequestedModel, response.Name, req.Username, "", time.Since(start))
+	h.auditor.Decision("select", req.RequestedModel, response.Name, req.Username, "", "")
 	h.logger.Debug("Subscription selected successfully",
 		"username", req.Username,
 		"subscription", response.Name,
+		"selectedBy", response.SelectedBy,
 		"organizationId", response.OrganizationID,
+		"quotaWarning", response.QuotaWarning != "",
 	)
 	c.JSON(http.StatusOK, response)
 }
 
-// ListSubscriptions handles GET /v1/subscriptions.
-// Returns all subscriptions the authenticated user has access to.
-func (h *Handler) ListSubscriptions(c *gin.Context) {
+// RemainingTokens returns the tokens the user has left under a model-scoped subscription key, or
+// nil when r cannot tell.
+func RemainingTokens(ctx context.Context, r TokenReporter, username, subscriptionKey string, limits *RateLimits) *int64 {
+	var limit int64
+	if limits != nil {
+		limit = limits.TokensPerMinute
+	}
+	if remaining, ok := r.RemainingTokens(ctx, username, subscriptionKey, limit); ok {
+		return &remaining
+	}
+	return nil
+}
+
+// userContext returns the caller set by the ExtractUserInfo middleware, answering 500 when it is missing.
+func (h *Handler) userContext(c *gin.Context) (*token.UserContext, bool) {
 	userContextVal, exists := c.Get("user")
 	if !exists {
 		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Internal server error",
-				"type":    "server_error",
-			}})
-		return
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return nil, false
 	}
 	userContext, ok := userContextVal.(*token.UserContext)
 	if !ok {
 		h.logger.Error("Invalid user context type")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Internal server error",
-				"type":    "server_error",
-			}})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Internal server error")
+		return nil, false
+	}
+	return userContext, true
+}
+
+// ListSubscriptions handles GET /v1/subscriptions.
+// Returns all subscriptions the authenticated user has access to.
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	userContext, ok := h.userContext(c)
+	if !ok {
 		return
 	}
 
 	accessible, err := h.selector.GetAllAccessible(userContext.Groups, userContext.Username)
 	if err != nil {
 		h.logger.Error("Failed to list subscriptions", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Failed to list subscriptions",
-				"type":    "server_error",
-			}})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list subscriptions")
 		return
 	}
 
@@ -197,50 +412,87 @@ func (h *Handler) ListSubscriptions(c *gin.Context) {
 	c.JSON(http.StatusOK, subs)
 }
 
-// ListSubscriptionsForModel handles GET /v1/model/:model-id/subscriptions.
-// Returns subscriptions the user has access to that include the specified model.
-func (h *Handler) ListSubscriptionsForModel(c *gin.Context) {
-	userContextVal, exists := c.Get("user")
-	if !exists {
-		h.logger.Error("User context not found - ExtractUserInfo middleware not called")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Internal server error",
-				"type":    "server_error",
-			}})
+// ListLimits handles GET /v1/limits.
+// Returns the effective rate limits of the models in the subscriptions the authenticated user has
+// access to, with MaaSRateLimitOverrides applied, and their requests in progress under
+// concurrency caps. The optional model query parameter
+// ("namespace/name") keeps only the limits that apply to that model.
+func (h *Handler) ListLimits(c *gin.Context) {
+	userContext, ok := h.userContext(c)
+	if !ok {
 		return
 	}
-	userContext, ok := userContextVal.(*token.UserContext)
+
+	model := c.Query("model")
+	if model != "" && strings.Count(model, "/") != 1 {
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidRequest, "model must be namespace/name")
+		return
+	}
+
+	limits, err := h.selector.Limits(userContext.Groups, userContext.Username, model)
+	if err != nil {
+		h.logger.Error("Failed to list limits", "error", err)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list limits")
+		return
+	}
+	if h.concurrency != nil {
+		for i := range limits {
+			if limits[i].MaxConcurrentRequests == 0 {
+				continue
+			}
+			n, err := h.concurrency.InFlight(c.Request.Context(), userContext.Username, limits[i].Subscription+"@"+limits[i].Model)
+			if err != nil {
+				h.logger.Warn("Failed to count requests in progress", "error", err, "subscription", limits[i].Subscription)
+				continue
+			}
+			limits[i].InFlight = &n
+		}
+	}
+
+	c.JSON(http.StatusOK, LimitList{Object: "list", Data: limits})
+}
+
+// ListSubscriptionsForModel handles GET /v1/model/:model-id/subscriptions.
+// Returns subscriptions the user has access to that include a model with the given name in any
+// namespace. Deprecated: superseded by ListSubscriptionsForModelRef (v2), which takes the namespace.
+func (h *Handler) ListSubscriptionsForModel(c *gin.Context) {
+	userContext, ok := h.userContext(c)
 	if !ok {
-		h.logger.Error("Invalid user context type")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Internal server error",
-				"type":    "server_error",
-			}})
 		return
 	}
 
 	modelID := c.Param("model-id")
 	if modelID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"message": "model-id is required",
-				"type":    "invalid_request_error",
-			}})
+		apierror.Respond(c, http.StatusBadRequest, apierror.InvalidPath, "model-id is required")
 		return
 	}
 
 	subs, err := h.selector.ListAccessibleForModel(userContext.Username, userContext.Groups, modelID)
 	if err != nil {
 		h.logger.Error("Failed to list subscriptions for model", "error", err, "model", modelID)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"message": "Failed to list subscriptions",
-				"type":    "server_error",
-			}})
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list subscriptions")
 		return
 	}
 
 	c.JSON(http.StatusOK, subs)
 }
+
+// ListSubscriptionsForModelRef handles GET /v2/models/:namespace/:name/subscriptions.
+// Unlike the v1 route it matches the model by namespace and name and wraps the result in a list
+// envelope.
+func (h *Handler) ListSubscriptionsForModelRef(c *gin.Context) {
+	userContext, ok := h.userContext(c)
+	if !ok {
+		return
+	}
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	subs, err := h.selector.ListAccessibleForModelRef(userContext.Username, userContext.Groups, namespace, name)
+	if err != nil {
+		h.logger.Error("Failed to list subscriptions for model", "error", err, "model", namespace+"/"+name)
+		apierror.Respond(c, http.StatusInternalServerError, apierror.InternalError, "Failed to list subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, SubscriptionList{Object: "list", Data: subs})
+}
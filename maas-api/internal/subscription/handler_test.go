@@ -2,14 +2,17 @@ package subscription_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/identity"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
@@ -891,6 +894,285 @@ func TestHandler_SelectSubscription_ModelValidation(t *testing.T) {
 	}
 }
 
+// exhaustedBudgets reports the budget of the listed subscription keys as spent.
+type exhaustedBudgets map[string]bool
+
+func (b exhaustedBudgets) BudgetExhausted(_ context.Context, username, subscriptionKey string) (string, time.Duration) {
+	if b[username+" "+subscriptionKey] {
+		return "token budget of 1000 tokens per 1d is exhausted", time.Hour
+	}
+	return "", 0
+}
+
+func TestHandler_SelectSubscription_BudgetExhausted(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+			{ns: "models", name: "small-model"},
+		}, 10, "org-gold", "cc-gold"),
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	handler := subscription.NewHandler(log, subscription.NewSelector(log, lister))
+	handler.SetBudgetChecker(exhaustedBudgets{"alice tenant-a/gold@models/llm": true})
+	router.POST("/subscriptions/select", handler.SelectSubscription)
+
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/llm",
+		"", "quota_exhausted", "alice has spent the budget for models/llm")
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/small-model",
+		"gold", "", "budgets are per model")
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "bob", "", "models/llm",
+		"gold", "", "budgets are per user")
+}
+
+// remainingTokens reports the remaining tokens of the listed users and subscription keys.
+type remainingTokens map[string]int64
+
+func (r remainingTokens) RemainingTokens(_ context.Context, username, subscriptionKey string, limit int64) (int64, bool) {
+	if remaining, ok := r[username+" "+subscriptionKey]; ok {
+		return remaining, true
+	}
+	return limit, limit > 0
+}
+
+func TestHandler_SelectSubscription_RemainingTokens(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+		}, 10, "org-gold", "cc-gold"),
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	handler := subscription.NewHandler(log, subscription.NewSelector(log, lister))
+	handler.SetTokenReporter(remainingTokens{"alice tenant-a/gold@models/llm": 250})
+	router.POST("/subscriptions/select", handler.SelectSubscription)
+
+	tests := []struct {
+		username string
+		want     int64
+	}{
+		{username: "alice", want: 250},
+		{username: "bob", want: 1000}, // the subscription's limit is passed on
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(subscription.SelectRequest{Groups: []string{"premium-users"}, Username: tt.username, RequestedModel: "models/llm"})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/subscriptions/select", bytes.NewBuffer(body)))
+
+		var response subscription.SelectResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if response.Model != "models/llm" {
+			t.Errorf("%s: expected model models/llm, got %q", tt.username, response.Model)
+		}
+		if response.RemainingTokens == nil || *response.RemainingTokens != tt.want {
+			t.Errorf("%s: expected %d remaining tokens, got %v", tt.username, tt.want, response.RemainingTokens)
+		}
+	}
+}
+
+func TestHandler_SelectSubscription_ModelDeleted(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+			{ns: "models", name: "small-model"},
+		}, 10, "org-gold", "cc-gold"),
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	selector := subscription.NewSelector(log, lister)
+	selector.SetSoftDeletedResolver(func(model string) bool { return model == "models/llm" })
+	router.POST("/subscriptions/select", subscription.NewHandler(log, selector).SelectSubscription)
+
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/llm",
+		"", "model_deleted", "soft-deleted model is denied")
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "gold", "models/llm",
+		"", "model_deleted", "soft-deleted model is denied for an explicit subscription")
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/small-model",
+		"gold", "", "other models are unaffected")
+}
+
+func TestHandler_SelectSubscription_ModelMaintenance(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+			{ns: "models", name: "small-model"},
+		}, 10, "org-gold", "cc-gold"),
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	selector := subscription.NewSelector(log, lister)
+	selector.SetMaintenanceResolver(func(model string, _ time.Time) (bool, time.Duration) {
+		return model == "models/llm", 90*time.Second + time.Millisecond
+	})
+	router.POST("/subscriptions/select", subscription.NewHandler(log, selector).SelectSubscription)
+
+	body, _ := json.Marshal(subscription.SelectRequest{Groups: []string{"premium-users"}, Username: "alice", RequestedModel: "models/llm"})
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions/select", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var response subscription.SelectResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Error != "model_maintenance" || response.RetryAfter != 91 {
+		t.Errorf("response = error %q, retryAfter %d; want model_maintenance with retryAfter rounded up to 91", response.Error, response.RetryAfter)
+	}
+
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/small-model",
+		"gold", "", "other models are unaffected")
+}
+
+func TestHandler_SelectSubscription_ModelCluster(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+			{ns: "models", name: "small-model"},
+		}, 10, "org-gold", "cc-gold"),
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	selector := subscription.NewSelector(log, lister)
+	available := true
+	selector.SetClusterResolver(func(model string) (string, bool) {
+		if model == "models/llm" {
+			return "gpu-east", available
+		}
+		return "hub", true
+	})
+	router.POST("/subscriptions/select", subscription.NewHandler(log, selector).SelectSubscription)
+
+	selectLLM := func() subscription.SelectResponse {
+		t.Helper()
+		body, _ := json.Marshal(subscription.SelectRequest{Groups: []string{"premium-users"}, Username: "alice", RequestedModel: "models/llm"})
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions/select", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var response subscription.SelectResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return response
+	}
+
+	if response := selectLLM(); response.Error != "" || response.Cluster != "gpu-east" {
+		t.Errorf("response = error %q, cluster %q; want gold on gpu-east", response.Error, response.Cluster)
+	}
+	available = false
+	if response := selectLLM(); response.Error != "cluster_unavailable" {
+		t.Errorf("response = error %q; want cluster_unavailable despite the cached selection", response.Error)
+	}
+
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/small-model",
+		"gold", "", "models of available clusters are unaffected")
+}
+
+func TestHandler_SelectSubscription_VerifiedIdentity(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+		}, 10, "org-gold", "cc-gold"),
+		createTestSubscriptionWithModels("silver", []string{"free-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+		}, 10, "org-silver", "cc-silver"),
+	}}
+	gin.SetMode(gin.TestMode)
+	log := logger.New(false)
+	handler := subscription.NewHandler(log, subscription.NewSelector(log, lister))
+
+	selectAs := func(id *identity.Identity, body string) subscription.SelectResponse {
+		t.Helper()
+		router := gin.New()
+		router.POST("/subscriptions/select", func(c *gin.Context) {
+			c.Set(identity.ContextKey, id)
+			c.Next()
+		}, handler.SelectSubscription)
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions/select", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var response subscription.SelectResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return response
+	}
+
+	alice := &identity.Identity{Username: "alice", Groups: []string{"premium-users"}, Tier: "gold"}
+	response := selectAs(alice, `{"username":"mallory","groups":["free-users"],"requestedSubscription":"silver","requestedModel":"models/llm"}`)
+	if response.Error != "" || response.Name != "gold" {
+		t.Errorf("response = error %q, name %q; want gold from the verified identity", response.Error, response.Name)
+	}
+	if response := selectAs(alice, `{"requestedModel":"models/llm"}`); response.Error != "" || response.Name != "gold" {
+		t.Errorf("response = error %q, name %q; want gold without a username in the body", response.Error, response.Name)
+	}
+
+	claimsSilver := &identity.Identity{Username: "alice", Groups: []string{"premium-users"}, Tier: "silver"}
+	if response := selectAs(claimsSilver, `{"requestedModel":"models/llm"}`); response.Error != "access_denied" {
+		t.Errorf("response = error %q; want access_denied for a tier the groups do not grant", response.Error)
+	}
+}
+
+func TestHandler_SelectSubscription_ModelNotServed(t *testing.T) {
+	lister := &mockLister{subscriptions: []*unstructured.Unstructured{
+		createTestSubscriptionWithModels("gold", []string{"premium-users"}, []struct{ ns, name string }{
+			{ns: "models", name: "llm"},
+			{ns: "models", name: "small-model"},
+		}, 10, "org-gold", "cc-gold"),
+	}}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	selector := subscription.NewSelector(log, lister)
+	selector.SetServedResolver(func(model string) bool { return model == "models/small-model" })
+	router.POST("/subscriptions/select", subscription.NewHandler(log, selector).SelectSubscription)
+
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/llm",
+		"", "model_not_found", "model outside the scope is denied as not found")
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "gold", "models/llm",
+		"", "model_not_found", "model outside the scope is denied for an explicit subscription")
+	runSelectSubscriptionTest(t, router, []string{"premium-users"}, "alice", "", "models/small-model",
+		"gold", "", "models in scope are unaffected")
+}
+
+func TestHandler_SelectSubscription_HostMismatch(t *testing.T) {
+	gold := createTestSubscription("gold", []string{"premium-users"}, 10, "org-gold", "cc-gold")
+	_ = unstructured.SetNestedMap(gold.Object, map[string]any{
+		"name":      "premium",
+		"hostnames": []any{"premium.models.example.com"},
+	}, "spec", "listener")
+	router := setupTestRouter(&mockLister{subscriptions: []*unstructured.Unstructured{gold}})
+
+	for _, tt := range []struct {
+		host, wantName, wantError string
+	}{
+		{host: "premium.models.example.com", wantName: "gold"},
+		{host: "free.models.example.com", wantError: "host_mismatch"},
+	} {
+		body, _ := json.Marshal(subscription.SelectRequest{Groups: []string{"premium-users"}, Username: "alice", Host: tt.host})
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions/select", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response subscription.SelectResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if response.Name != tt.wantName || response.Error != tt.wantError {
+			t.Errorf("host %s: got name %q error %q, want name %q error %q", tt.host, response.Name, response.Error, tt.wantName, tt.wantError)
+		}
+	}
+}
+
 // TestHandler_SelectSubscription_MultipleSubscriptionsSameModel tests behavior when multiple subscriptions have the same model.
 func TestHandler_SelectSubscription_MultipleSubscriptionsSameModel(t *testing.T) {
 	// Create two subscriptions that both have the same model
@@ -973,6 +1255,7 @@ func setupListTestRouter(lister subscription.Lister, username string, groups []s
 
 	router.GET("/v1/subscriptions", setUser, handler.ListSubscriptions)
 	router.GET("/v1/model/:model-id/subscriptions", setUser, handler.ListSubscriptionsForModel)
+	router.GET("/v2/models/:namespace/:name/subscriptions", setUser, handler.ListSubscriptionsForModelRef)
 	return router
 }
 
@@ -1187,3 +1470,40 @@ func TestListSubscriptionsForModel_NoAccess(t *testing.T) {
 		t.Errorf("expected empty array when user has no access, got %d items", len(result))
 	}
 }
+
+func TestListSubscriptionsForModelRef_MatchesNamespace(t *testing.T) {
+	teamA := createTestSubscriptionWithAnnotations("team-a-sub", []string{"free-users"}, []string{"granite"}, nil)
+	teamB := createTestSubscriptionWithAnnotations("team-b-sub", []string{"free-users"}, []string{"granite"}, nil)
+	for sub, ns := range map[*unstructured.Unstructured]string{teamA: "team-a", teamB: "team-b"} {
+		refs, _, _ := unstructured.NestedSlice(sub.Object, "spec", "modelRefs")
+		refs[0].(map[string]any)["namespace"] = ns
+		if err := unstructured.SetNestedSlice(sub.Object, refs, "spec", "modelRefs"); err != nil {
+			t.Fatalf("failed to set modelRefs: %v", err)
+		}
+	}
+	router := setupListTestRouter(&mockLister{subscriptions: []*unstructured.Unstructured{teamA, teamB}}, "alice", []string{"free-users"})
+
+	// v1 matches by name only and returns both.
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/model/granite/subscriptions", nil))
+	var v1 []subscription.SubscriptionInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &v1); err != nil {
+		t.Fatalf("failed to unmarshal v1 response: %v", err)
+	}
+	if len(v1) != 2 {
+		t.Fatalf("expected v1 to return 2 subscriptions, got %d", len(v1))
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/models/team-b/granite/subscriptions", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var v2 subscription.SubscriptionList
+	if err := json.Unmarshal(w.Body.Bytes(), &v2); err != nil {
+		t.Fatalf("failed to unmarshal v2 response: %v", err)
+	}
+	if v2.Object != "list" || len(v2.Data) != 1 || v2.Data[0].SubscriptionIDHeader != "team-b-sub" {
+		t.Errorf("expected a list with only team-b-sub, got %+v", v2)
+	}
+}
@@ -0,0 +1,66 @@
+package subscription
+
+import (
+	"net"
+	"slices"
+	"strings"
+)
+
+// normalizeHost lowercases a Host header and drops its port.
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// hostMatches reports whether host is one of a listener's hostnames. As in Gateway API, a leading
+// "*." matches exactly one label: *.example.com matches a.example.com but not a.b.example.com.
+func hostMatches(pattern, host string) bool {
+	pattern = strings.ToLower(pattern)
+	suffix, ok := strings.CutPrefix(pattern, "*.")
+	if !ok {
+		return pattern == host
+	}
+	label, ok := strings.CutSuffix(host, "."+suffix)
+	return ok && label != "" && !strings.Contains(label, ".")
+}
+
+// boundTo reports whether sub is bound to a listener serving host.
+func (s *subscription) boundTo(host string) bool {
+	return slices.ContainsFunc(s.Hostnames, func(pattern string) bool { return hostMatches(pattern, host) })
+}
+
+// hostFilter decides which subscriptions requests to a host may use. Subscriptions bound to a
+// gateway listener are only served on its hostnames, and a hostname of a listener only serves the
+// subscriptions bound to it; every other subscription is served on every other host.
+type hostFilter struct {
+	host    string
+	claimed bool // some subscription is bound to a listener serving host
+}
+
+// newHostFilter returns the filter for requests to host. An empty host allows every subscription.
+func newHostFilter(subscriptions []subscription, host string) hostFilter {
+	f := hostFilter{host: normalizeHost(host)}
+	if f.host == "" {
+		return f
+	}
+	for i := range subscriptions {
+		if subscriptions[i].boundTo(f.host) {
+			f.claimed = true
+			break
+		}
+	}
+	return f
+}
+
+// allows reports whether requests to the filter's host may use sub.
+func (f hostFilter) allows(sub *subscription) bool {
+	if f.host == "" {
+		return true
+	}
+	if f.claimed {
+		return sub.boundTo(f.host)
+	}
+	return len(sub.Hostnames) == 0
+}
@@ -0,0 +1,194 @@
+package subscription
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Limit sources reported by GET /v1/limits.
+const (
+	LimitSourceTier     = "tier"
+	LimitSourceOverride = "override"
+)
+
+// override is a parsed MaaSRateLimitOverride: negotiated limits for one organization on one model,
+// replacing those of every subscription of the organization in the override's namespace.
+type override struct {
+	Name              string
+	Namespace         string
+	OrganizationID    string
+	Model             string // namespace/name, as listed in the subscriptions' spec.modelRefs
+	Created           time.Time
+	TokenRateLimits   []TokenRateLimit
+	RequestRateLimits []RequestRateLimit
+}
+
+func overrideKey(namespace, organizationID, model string) string {
+	return namespace + "/" + organizationID + "@" + model
+}
+
+// parseOverride extracts override data from an unstructured MaaSRateLimitOverride.
+func parseOverride(obj *unstructured.Unstructured) (override, error) {
+	spec, found, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil || !found {
+		return override{}, errors.New("spec not found")
+	}
+	o := override{
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Created:   obj.GetCreationTimestamp().Time,
+	}
+	o.OrganizationID, _, _ = unstructured.NestedString(spec, "organizationId")
+	modelNamespace, _, _ := unstructured.NestedString(spec, "model", "namespace")
+	modelName, _, _ := unstructured.NestedString(spec, "model", "name")
+	if o.OrganizationID == "" || modelNamespace == "" || modelName == "" {
+		return override{}, errors.New("spec.organizationId, spec.model.namespace and spec.model.name are required")
+	}
+	o.Model = modelNamespace + "/" + modelName
+	o.TokenRateLimits, o.RequestRateLimits = parseRateLimits(spec)
+	if len(o.TokenRateLimits) == 0 && len(o.RequestRateLimits) == 0 {
+		return override{}, errors.New("spec sets no rate limits")
+	}
+	return o, nil
+}
+
+// loadOverrides returns the overrides in effect, by overrideKey. When several target the same
+// organization and model, the oldest wins, as in maas-controller.
+func (s *Selector) loadOverrides() map[string]override {
+	if s.overrides == nil {
+		return nil
+	}
+	objects, err := s.overrides.List()
+	if err != nil {
+		s.logger.Warn("Failed to list rate limit overrides, using tier limits", "error", err)
+		return nil
+	}
+	effective := make(map[string]override, len(objects))
+	for _, obj := range objects {
+		o, err := parseOverride(obj)
+		if err != nil {
+			s.logger.Warn("Failed to parse rate limit override, skipping",
+				"name", obj.GetName(),
+				"namespace", obj.GetNamespace(),
+				"error", err,
+			)
+			continue
+		}
+		key := overrideKey(o.Namespace, o.OrganizationID, o.Model)
+		if current, ok := effective[key]; ok && !overrideWins(o, current) {
+			continue
+		}
+		effective[key] = o
+	}
+	return effective
+}
+
+// overrideWins reports whether a takes precedence over b for the same organization and model.
+func overrideWins(a, b override) bool {
+	if !a.Created.Equal(b.Created) {
+		return a.Created.Before(b.Created)
+	}
+	return a.Name < b.Name
+}
+
+// applyOverrides replaces the limits of the model refs covered by an override. Each set of limits
+// the override defines replaces the tier's; the others are kept. Subscriptions are updated in
+// place, but their ModelRefs are copied before any change since the catalog shares them.
+func applyOverrides(subs []subscription, overrides map[string]override) {
+	if len(overrides) == 0 {
+		return
+	}
+	for i := range subs {
+		sub := &subs[i]
+		if sub.OrganizationID == "" {
+			continue
+		}
+		copied := false
+		for j, ref := range sub.ModelRefs {
+			o, ok := overrides[overrideKey(sub.Namespace, sub.OrganizationID, ref.Namespace+"/"+ref.Name)]
+			if !ok {
+				continue
+			}
+			if !copied {
+				sub.ModelRefs = slices.Clone(sub.ModelRefs)
+				copied = true
+			}
+			if len(o.TokenRateLimits) > 0 {
+				ref.TokenRateLimits = o.TokenRateLimits
+			}
+			if len(o.RequestRateLimits) > 0 {
+				ref.RequestRateLimits = o.RequestRateLimits
+			}
+			ref.Override = o.Name
+			sub.ModelRefs[j] = ref
+		}
+	}
+}
+
+// Limits returns the effective rate limits of every model in the subscriptions the user has
+// access to, sorted by subscription and model. A non-empty model ("namespace/name") keeps only
+// the limits that apply to it, looking through its lineage like Select.
+func (s *Selector) Limits(groups []string, username, model string) ([]LimitInfo, error) {
+	accessible, err := s.GetAllAccessible(groups, username)
+	if err != nil {
+		return nil, err
+	}
+	limits := []LimitInfo{}
+	for _, sub := range accessible {
+		subKey := sub.Namespace + "/" + sub.Name
+		if model != "" {
+			if ref := refFor(sub.ModelRefs, s.modelChain(model)); ref != nil {
+				limits = append(limits, limitInfo(subKey, model, ref))
+			}
+			continue
+		}
+		for i := range sub.ModelRefs {
+			ref := &sub.ModelRefs[i]
+			limits = append(limits, limitInfo(subKey, ref.Namespace+"/"+ref.Name, ref))
+		}
+	}
+	slices.SortFunc(limits, func(a, b LimitInfo) int {
+		if c := strings.Compare(a.Subscription, b.Subscription); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Model, b.Model)
+	})
+	return limits, nil
+}
+
+// refFor returns the first model ref matching models, in order, like rateLimitsFor.
+func refFor(refs []ModelRefInfo, models []string) *ModelRefInfo {
+	for _, model := range models {
+		for i := range refs {
+			if refs[i].Namespace+"/"+refs[i].Name == model {
+				return &refs[i]
+			}
+		}
+	}
+	return nil
+}
+
+func limitInfo(subscription, model string, ref *ModelRefInfo) LimitInfo {
+	tokenLimits := ref.TokenRateLimits
+	if len(tokenLimits) == 0 {
+		// maas-controller applies 100 tokens per minute to entries without limits.
+		tokenLimits = []TokenRateLimit{{Limit: 100, Window: "1m"}}
+	}
+	info := LimitInfo{
+		Subscription:      subscription,
+		Model:             model,
+		TokenRateLimits:   tokenLimits,
+		RequestRateLimits: ref.RequestRateLimits,
+		Source:            LimitSourceTier,
+		Override:          ref.Override,
+	}
+	if ref.Override != "" {
+		info.Source = LimitSourceOverride
+	}
+	info.MaxConcurrentRequests = ref.MaxConcurrentRequests
+	return info
+}
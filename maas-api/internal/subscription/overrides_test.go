@@ -0,0 +1,211 @@
+package subscription_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+func createTestOverride(name, orgID, model string, tokenLimit int64, created time.Time) *unstructured.Unstructured {
+	ns, modelName, _ := strings.Cut(model, "/")
+	o := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "maas.opendatahub.io/v1alpha1",
+			"kind":       "MaaSRateLimitOverride",
+			"metadata": map[string]any{
+				"name":      name,
+				"namespace": "test-ns",
+			},
+			"spec": map[string]any{
+				"organizationId": orgID,
+				"model":          map[string]any{"namespace": ns, "name": modelName},
+				"tokenRateLimits": []any{
+					map[string]any{"limit": tokenLimit, "window": "1m"},
+				},
+			},
+		},
+	}
+	o.SetCreationTimestamp(metav1.NewTime(created))
+	return o
+}
+
+// createTestSubscriptionForOrg creates a subscription of orgID for the given "namespace/name" models,
+// each with a 1000 tokens per minute tier limit.
+func createTestSubscriptionForOrg(name, group, orgID string, models ...string) *unstructured.Unstructured {
+	var modelRefs []any
+	for _, model := range models {
+		ns, modelName, _ := strings.Cut(model, "/")
+		modelRefs = append(modelRefs, map[string]any{
+			"name":      modelName,
+			"namespace": ns,
+			"tokenRateLimits": []any{
+				map[string]any{"limit": int64(1000), "window": "1m"},
+			},
+			"requestRateLimits": []any{
+				map[string]any{"limit": int64(10), "window": "1s"},
+			},
+		})
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "maas.opendatahub.io/v1alpha1",
+			"kind":       "MaaSSubscription",
+			"metadata": map[string]any{
+				"name":      name,
+				"namespace": "test-ns",
+			},
+			"spec": map[string]any{
+				"owner":         map[string]any{"groups": []any{map[string]any{"name": group}}},
+				"modelRefs":     modelRefs,
+				"tokenMetadata": map[string]any{"organizationId": orgID},
+			},
+		},
+	}
+}
+
+func setupLimitsTestRouter(subscriptions, overrides []*unstructured.Unstructured, groups []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	log := logger.New(false)
+	selector := subscription.NewSelector(log, &mockLister{subscriptions: subscriptions})
+	selector.SetOverrides(&mockLister{subscriptions: overrides})
+	handler := subscription.NewHandler(log, selector)
+
+	router.GET("/v1/limits", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: groups})
+		c.Next()
+	}, handler.ListLimits)
+	return router
+}
+
+func TestListLimits(t *testing.T) {
+	now := time.Now()
+	subscriptions := []*unstructured.Unstructured{
+		createTestSubscriptionForOrg("acme-gold", "acme-users", "acme", "llm/granite", "llm/llama"),
+		createTestSubscriptionForOrg("globex-gold", "globex-users", "globex", "llm/granite"),
+	}
+	overrides := []*unstructured.Unstructured{
+		createTestOverride("acme-granite-2", "acme", "llm/granite", 70000, now),
+		createTestOverride("acme-granite", "acme", "llm/granite", 50000, now.Add(-time.Hour)),
+		createTestOverride("globex-elsewhere", "globex", "other/granite", 90000, now),
+		{Object: map[string]any{"metadata": map[string]any{"name": "broken", "namespace": "test-ns"}, "spec": map[string]any{}}},
+	}
+	router := setupLimitsTestRouter(subscriptions, overrides, []string{"acme-users", "globex-users"})
+
+	list := func(t *testing.T, query string) []subscription.LimitInfo {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/limits"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var result subscription.LimitList
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "list", result.Object)
+		return result.Data
+	}
+
+	t.Run("all models", func(t *testing.T) {
+		limits := list(t, "")
+		require.Len(t, limits, 3)
+		assert.Equal(t, subscription.LimitInfo{
+			Subscription:      "test-ns/acme-gold",
+			Model:             "llm/granite",
+			TokenRateLimits:   []subscription.TokenRateLimit{{Limit: 50000, Window: "1m"}},
+			RequestRateLimits: []subscription.RequestRateLimit{{Limit: 10, Window: "1s"}},
+			Source:            subscription.LimitSourceOverride,
+			Override:          "acme-granite",
+		}, limits[0], "the oldest override replaces the token limits and keeps the tier's request limits")
+		assert.Equal(t, "llm/llama", limits[1].Model)
+		assert.Equal(t, subscription.LimitSourceTier, limits[1].Source)
+		assert.Equal(t, "test-ns/globex-gold", limits[2].Subscription)
+		assert.Equal(t, []subscription.TokenRateLimit{{Limit: 1000, Window: "1m"}}, limits[2].TokenRateLimits, "an override for another model does not apply")
+		assert.Equal(t, subscription.LimitSourceTier, limits[2].Source)
+	})
+
+	t.Run("one model", func(t *testing.T) {
+		limits := list(t, "?model=llm/llama")
+		require.Len(t, limits, 1)
+		assert.Equal(t, "test-ns/acme-gold", limits[0].Subscription)
+	})
+
+	t.Run("invalid model", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/limits?model=granite", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// inFlightCounter reports its count for every key.
+type inFlightCounter map[string]int64
+
+func (c inFlightCounter) InFlight(_ context.Context, username, subscriptionKey string) (int64, error) {
+	return c[username+"|"+subscriptionKey], nil
+}
+
+func TestListLimitsInFlight(t *testing.T) {
+	sub := createTestSubscriptionForOrg("acme-gold", "acme-users", "acme", "llm/granite", "llm/llama")
+	refs, _, _ := unstructured.NestedSlice(sub.Object, "spec", "modelRefs")
+	refs[0].(map[string]any)["maxConcurrentRequests"] = int64(4)
+	require.NoError(t, unstructured.SetNestedSlice(sub.Object, refs, "spec", "modelRefs"))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	log := logger.New(false)
+	handler := subscription.NewHandler(log, subscription.NewSelector(log, &mockLister{subscriptions: []*unstructured.Unstructured{sub}}))
+	handler.SetConcurrencyCounter(inFlightCounter{"alice|test-ns/acme-gold@llm/granite": 3})
+	router.GET("/v1/limits", func(c *gin.Context) {
+		c.Set("user", &token.UserContext{Username: "alice", Groups: []string{"acme-users"}})
+		c.Next()
+	}, handler.ListLimits)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/limits", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result subscription.LimitList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result.Data, 2)
+	assert.Equal(t, int64(4), result.Data[0].MaxConcurrentRequests)
+	require.NotNil(t, result.Data[0].InFlight)
+	assert.Equal(t, int64(3), *result.Data[0].InFlight)
+	assert.Zero(t, result.Data[1].MaxConcurrentRequests)
+	assert.Nil(t, result.Data[1].InFlight, "models without a cap report no count")
+}
+
+func TestEffectiveRateLimits(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subscriptions := []*unstructured.Unstructured{
+		createTestSubscriptionForOrg("acme-sub", "acme-users", "acme", "llm/model-a", "llm/model-b"),
+	}
+	overrides := []*unstructured.Unstructured{
+		createTestOverride("acme-model-a", "acme", "llm/model-a", 5000, created),
+	}
+	selector := subscription.NewSelector(logger.New(false), &mockLister{subscriptions: subscriptions})
+	selector.SetOverrides(&mockLister{subscriptions: overrides})
+
+	tokens, requests := selector.EffectiveRateLimits("test-ns/acme-sub@llm/model-a")
+	assert.Equal(t, []subscription.TokenRateLimit{{Limit: 5000, Window: "1m"}}, tokens)
+	assert.Equal(t, []subscription.RequestRateLimit{{Limit: 10, Window: "1s"}}, requests)
+
+	tokens, _ = selector.EffectiveRateLimits("test-ns/acme-sub@llm/model-b")
+	assert.Equal(t, []subscription.TokenRateLimit{{Limit: 1000, Window: "1m"}}, tokens)
+
+	for _, key := range []string{"test-ns/acme-sub@llm/model-c", "test-ns/missing@llm/model-a", "test-ns/acme-sub"} {
+		tokens, requests = selector.EffectiveRateLimits(key)
+		assert.Nil(t, tokens, key)
+		assert.Nil(t, requests, key)
+	}
+}
@@ -0,0 +1,66 @@
+package subscription
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// QuotaMarker annotates MaaSSubscriptions whose daily token cap is reached with the time it
+// resets, so maas-controller can report them QuotaExceeded.
+type QuotaMarker struct {
+	client dynamic.Interface
+	logger *logger.Logger
+
+	mu     sync.Mutex
+	marked map[string]time.Time // subscription key -> reset time last annotated
+}
+
+// NewQuotaMarker creates a QuotaMarker patching MaaSSubscriptions through client.
+func NewQuotaMarker(log *logger.Logger, client dynamic.Interface) *QuotaMarker {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &QuotaMarker{client: client, logger: log, marked: map[string]time.Time{}}
+}
+
+// CapReached annotates the subscription (namespace/name) with resetsAt, once per reset time. A
+// failed patch is logged and retried on the next call.
+func (m *QuotaMarker) CapReached(ctx context.Context, subscriptionKey string, resetsAt time.Time) {
+	namespace, name, ok := strings.Cut(subscriptionKey, "/")
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	done := m.marked[subscriptionKey].Equal(resetsAt)
+	m.mu.Unlock()
+	if done {
+		return
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{constant.AnnotationQuotaExceededUntil: resetsAt.UTC().Format(time.RFC3339)},
+		},
+	})
+	if err != nil {
+		return
+	}
+	if _, err := m.client.Resource(GVR()).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		m.logger.Warn("Failed to mark subscription as over its daily token cap", "subscription", subscriptionKey, "error", err)
+		return
+	}
+	m.logger.Info("Subscription reached its daily token cap", "subscription", subscriptionKey, "resetsAt", resetsAt.UTC())
+	m.mu.Lock()
+	m.marked[subscriptionKey] = resetsAt
+	m.mu.Unlock()
+}
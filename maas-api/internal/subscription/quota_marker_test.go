@@ -0,0 +1,48 @@
+package subscription_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+)
+
+func TestQuotaMarker(t *testing.T) {
+	sub := &unstructured.Unstructured{}
+	sub.SetAPIVersion("maas.opendatahub.io/v1alpha1")
+	sub.SetKind("MaaSSubscription")
+	sub.SetNamespace("models-as-a-service")
+	sub.SetName("premium")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{subscription.GVR(): "MaaSSubscriptionList"}, sub)
+	patches := 0
+	client.PrependReactor("patch", "maassubscriptions", func(k8stesting.Action) (bool, runtime.Object, error) {
+		patches++
+		return false, nil, nil
+	})
+	marker := subscription.NewQuotaMarker(logger.Development(), client)
+
+	resetsAt := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+	marker.CapReached(context.Background(), "models-as-a-service/premium", resetsAt)
+	marker.CapReached(context.Background(), "models-as-a-service/premium", resetsAt)
+	assert.Equal(t, 1, patches, "a reset time is only annotated once")
+
+	got, err := client.Resource(subscription.GVR()).Namespace("models-as-a-service").Get(context.Background(), "premium", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-11T00:00:00Z", got.GetAnnotations()[constant.AnnotationQuotaExceededUntil])
+
+	marker.CapReached(context.Background(), "models-as-a-service/premium", resetsAt.Add(24*time.Hour))
+	assert.Equal(t, 2, patches)
+}
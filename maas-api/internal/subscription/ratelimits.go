@@ -0,0 +1,78 @@
+package subscription
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var windowPattern = regexp.MustCompile(`^(\d+)(s|m|h|d)$`)
+
+// SustainedPerMinute returns the most restrictive of the limits, normalized to one minute, or 0
+// when there are none. A subscription with 1000/1m and 6000/1h can only sustain 100 per minute.
+func SustainedPerMinute[L TokenRateLimit | RequestRateLimit](limits []L) int64 {
+	var sustained int64 = -1
+	for _, l := range limits {
+		limit := TokenRateLimit(l)
+		seconds := int64(ParseWindow(limit.Window) / time.Second)
+		if seconds <= 0 {
+			continue
+		}
+		perMinute := limit.Limit * 60 / seconds
+		if sustained < 0 || perMinute < sustained {
+			sustained = perMinute
+		}
+	}
+	if sustained < 0 {
+		return 0
+	}
+	return sustained
+}
+
+// ParseWindow parses a rate limit window such as 1m or 24h. It returns 0 for invalid windows.
+func ParseWindow(w string) time.Duration {
+	m := windowPattern.FindStringSubmatch(w)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	unit := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[m[2]]
+	return time.Duration(n) * unit
+}
+
+// rateLimitsFor returns the limits of the first model ref matching models, in order, so a model
+// covered through its lineage gets the limits of the ref that covers it.
+func rateLimitsFor(refs []ModelRefInfo, models []string) *RateLimits {
+	for _, model := range models {
+		for _, ref := range refs {
+			if ref.Namespace+"/"+ref.Name != model {
+				continue
+			}
+			if len(ref.TokenRateLimits) == 0 && len(ref.RequestRateLimits) == 0 {
+				return nil
+			}
+			return &RateLimits{
+				Model:             model,
+				RequestsPerMinute: SustainedPerMinute(ref.RequestRateLimits),
+				TokensPerMinute:   SustainedPerMinute(ref.TokenRateLimits),
+			}
+		}
+	}
+	return nil
+}
+
+// budgetFor returns the token budget of the first model ref matching models, in order, like
+// rateLimitsFor.
+func budgetFor(refs []ModelRefInfo, models []string) *TokenBudget {
+	for _, model := range models {
+		for _, ref := range refs {
+			if ref.Namespace+"/"+ref.Name == model {
+				return ref.TokenBudget
+			}
+		}
+	}
+	return nil
+}
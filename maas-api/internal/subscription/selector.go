@@ -3,14 +3,19 @@ package subscription
 import (
 	"errors"
 	"fmt"
+	"math"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
 )
 
 // Lister provides access to MaaSSubscription resources from an informer cache.
@@ -20,35 +25,177 @@ type Lister interface {
 
 // Selector handles subscription selection logic.
 type Selector struct {
-	lister Lister
-	logger *logger.Logger
+	lister        Lister
+	overrides     Lister
+	logger        *logger.Logger
+	allowMultiple bool
+	tieBreak      string
+	lineage       LineageResolver
+	softDeleted   SoftDeletedResolver
+	maintenance   MaintenanceResolver
+	cluster       ClusterResolver
+	served        ServedResolver
+	decisions     *DecisionCache
+	catalog       *Catalog
+	now           func() time.Time
 }
 
+// LineageResolver returns the ancestors of a model ("namespace/name"), nearest first, or nil
+// for a base model. See MaaSModelRef spec.parentRef.
+type LineageResolver func(model string) []string
+
+// SoftDeletedResolver reports whether a model ("namespace/name") is soft-deleted.
+type SoftDeletedResolver func(model string) bool
+
+// MaintenanceResolver reports whether a model ("namespace/name") is in maintenance at now, and
+// how long clients should wait before retrying.
+type MaintenanceResolver func(model string, now time.Time) (bool, time.Duration)
+
+// ClusterResolver returns the federated cluster hosting a model ("namespace/name") and whether
+// it is available.
+type ClusterResolver func(model string) (cluster string, available bool)
+
+// ServedResolver reports whether this maas-api instance serves a model ("namespace/name").
+type ServedResolver func(model string) bool
+
+// Tie-break strategies for users who match several subscriptions.
+const (
+	// TieBreakPriority picks the highest spec.priority, then the highest token limit, then name.
+	TieBreakPriority = "priority"
+	// TieBreakCheapest picks the lowest billingRate.perToken for the requested model, falling
+	// back to priority order for equal or missing rates.
+	TieBreakCheapest = "cheapest"
+)
+
+// Values of SelectResponse.SelectedBy describing how the subscription was chosen.
+const (
+	SelectedByHeader = "header"
+	SelectedBySingle = "single"
+)
+
 // NewSelector creates a new subscription selector.
 func NewSelector(log *logger.Logger, lister Lister) *Selector {
 	if log == nil {
 		log = logger.Production()
 	}
 	return &Selector{
-		lister: lister,
-		logger: log,
+		lister:   lister,
+		logger:   log,
+		tieBreak: TieBreakPriority,
+		now:      time.Now,
+	}
+}
+
+// SetAllowMultiple controls auto-selection for users who belong to several subscriptions that
+// include the requested model. When enabled the request is allowed through the highest-ranked
+// match instead of failing with MultipleSubscriptionsError; the other matches are reported in
+// SelectResponse.Candidates. Explicit X-MaaS-Subscription selection is unaffected.
+func (s *Selector) SetAllowMultiple(allow bool) {
+	s.allowMultiple = allow
+}
+
+// SetTieBreak sets the rule used to pick among several matching subscriptions when
+// SetAllowMultiple is enabled. Unknown values fall back to TieBreakPriority.
+func (s *Selector) SetTieBreak(strategy string) {
+	if strategy != TieBreakCheapest {
+		strategy = TieBreakPriority
+	}
+	s.tieBreak = strategy
+}
+
+// SetLineageResolver lets subscriptions that list a base model cover its variants (fine-tunes),
+// matching the rate limits maas-controller generates for them.
+func (s *Selector) SetLineageResolver(resolve LineageResolver) {
+	s.lineage = resolve
+}
+
+// SetSoftDeletedResolver denies selection for soft-deleted models with ModelDeletedError.
+func (s *Selector) SetSoftDeletedResolver(resolve SoftDeletedResolver) {
+	s.softDeleted = resolve
+}
+
+// SetMaintenanceResolver denies selection for models in maintenance with ModelMaintenanceError.
+// Maintenance is checked before the decision cache, so windows start and end on time.
+func (s *Selector) SetMaintenanceResolver(resolve MaintenanceResolver) {
+	s.maintenance = resolve
+}
+
+// SetClusterResolver records the cluster hosting the requested model in SelectResponse.Cluster,
+// and denies selection for models whose cluster is unavailable with ModelClusterUnavailableError.
+// Availability is checked before the decision cache, so requests fail over as soon as the
+// cluster is.
+func (s *Selector) SetClusterResolver(resolve ClusterResolver) {
+	s.cluster = resolve
+}
+
+// SetServedResolver denies selection for models outside this instance's model scope with
+// ModelNotServedError.
+func (s *Selector) SetServedResolver(resolve ServedResolver) {
+	s.served = resolve
+}
+
+// SetOverrides applies the MaaSRateLimitOverrides from lister on top of the limits of the
+// subscriptions of their organization. Register the decision cache's EventHandler on its informer.
+func (s *Selector) SetOverrides(lister Lister) {
+	s.overrides = lister
+}
+
+// SetDecisionCache answers repeated Select calls from c until its entries expire or the
+// subscriptions or models change. Register c.EventHandler on both informers.
+func (s *Selector) SetDecisionCache(c *DecisionCache) {
+	s.decisions = c
+}
+
+// SetCatalog makes selection read tiers from catalog instead of parsing every MaaSSubscription
+// on each request. Its lister should be the selector's.
+func (s *Selector) SetCatalog(c *Catalog) {
+	s.catalog = c
+}
+
+// SetClock evaluates subscription expiry against now instead of the local clock, e.g. a clock
+// corrected for skew against the API server that maas-controller also follows.
+func (s *Selector) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// modelChain returns requestedModel followed by its ancestors, or nil when no model is requested.
+func (s *Selector) modelChain(requestedModel string) []string {
+	if requestedModel == "" {
+		return nil
+	}
+	chain := []string{requestedModel}
+	if s.lineage != nil {
+		chain = append(chain, s.lineage(requestedModel)...)
 	}
+	return chain
 }
 
 // subscription represents a parsed MaaSSubscription for selection.
 type subscription struct {
-	Name           string
-	Namespace      string
-	DisplayName    string
-	Description    string
-	Groups         []string
-	Users          []string
-	Priority       int32
-	MaxLimit       int64
-	OrganizationID string
-	CostCenter     string
-	Labels         map[string]string
-	ModelRefs      []ModelRefInfo
+	Name            string
+	Namespace       string
+	DisplayName     string
+	Description     string
+	Groups          []string
+	Users           []string
+	Priority        int32
+	MaxLimit        int64
+	OrganizationID  string
+	CostCenter      string
+	Labels          map[string]string
+	ModelRefs       []ModelRefInfo
+	Includes        []string  // names of subscriptions in the same namespace whose access this one grants, or "*"
+	ExpiresAt       time.Time // zero when the subscription does not expire
+	MaxTokensPerDay int64     // tokens all users may consume together per UTC day; 0 when uncapped
+	Sandbox         bool      // requests are answered by the mock backend instead of the model
+	Hostnames       []string  // hostnames of the gateway listener the subscription is bound to, if any
+	Attachments     *AttachmentPolicy
+	Endpoints       []string // API suffixes requests may use; empty allows all
+	RevokedOwners   []revokedOwner
+}
+
+func (s *subscription) key() string {
+	return s.Namespace + "/" + s.Name
 }
 
 // GetAllAccessible returns all subscriptions the user has access to.
@@ -62,10 +209,13 @@ func (s *Selector) GetAllAccessible(groups []string, username string) ([]*Select
 		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
 	}
 
+	granted := grantedAccess(subscriptions, username, groups)
 	var accessible []*SelectResponse
 	for _, sub := range subscriptions {
-		if userHasAccess(&sub, username, groups) {
-			accessible = append(accessible, toResponse(&sub))
+		if via, ok := granted[sub.key()]; ok {
+			resp := toResponse(&sub)
+			resp.GrantedBy = via
+			accessible = append(accessible, resp)
 		}
 	}
 
@@ -77,13 +227,227 @@ func (s *Selector) GetAllAccessible(groups []string, username string) ([]*Select
 	return accessible, nil
 }
 
+// ListAll returns every subscription regardless of user access, sorted by namespace and name.
+// Intended for admin views; callers must enforce authorization themselves.
+func (s *Selector) ListAll() ([]*SelectResponse, error) {
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	all := make([]*SelectResponse, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		all = append(all, toResponse(&sub))
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Namespace != all[j].Namespace {
+			return all[i].Namespace < all[j].Namespace
+		}
+		return all[i].Name < all[j].Name
+	})
+	return all, nil
+}
+
+// Organizations returns the tokenMetadata organization ID of every subscription that has one,
+// by subscription name, for labelling exported usage.
+func (s *Selector) Organizations() map[string]string {
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		s.logger.Warn("Failed to load subscriptions for organization lookup", "error", err)
+		return nil
+	}
+	orgs := make(map[string]string, len(subscriptions))
+	for _, sub := range subscriptions {
+		if sub.OrganizationID != "" {
+			orgs[sub.Name] = sub.OrganizationID
+		}
+	}
+	return orgs
+}
+
+// TiersForModel returns every subscription (namespace/name) that includes model
+// ("namespace/name") or one of its ancestors, sorted, whoever may use it.
+func (s *Selector) TiersForModel(model string) ([]string, error) {
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	models := s.modelChain(model)
+	tiers := []string{}
+	for _, sub := range subscriptions {
+		if coveredModel(&sub, models) != "" {
+			tiers = append(tiers, sub.key())
+		}
+	}
+	slices.Sort(tiers)
+	return tiers, nil
+}
+
+// TokenBudget returns the token budget under a model-scoped subscription key
+// (namespace/name@modelNamespace/modelName), looking through the model's lineage like Select, or
+// nil when the subscription has none for the model or no longer exists.
+func (s *Selector) TokenBudget(subscriptionKey string) *TokenBudget {
+	subKey, model, ok := strings.Cut(subscriptionKey, "@")
+	if !ok {
+		return nil
+	}
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		s.logger.Warn("Failed to load subscriptions for token budget lookup", "error", err)
+		return nil
+	}
+	for _, sub := range subscriptions {
+		if sub.key() == subKey {
+			return budgetFor(sub.ModelRefs, s.modelChain(model))
+		}
+	}
+	return nil
+}
+
+// MaxConcurrentRequests returns the cap on the requests each user may have in progress under a
+// model-scoped subscription key (namespace/name@modelNamespace/modelName), looking through the
+// model's lineage like Select, or 0 when there is none or the subscription no longer exists.
+func (s *Selector) MaxConcurrentRequests(subscriptionKey string) int64 {
+	subKey, model, ok := strings.Cut(subscriptionKey, "@")
+	if !ok {
+		return 0
+	}
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		s.logger.Warn("Failed to load subscriptions for concurrency limit lookup", "error", err)
+		return 0
+	}
+	for _, sub := range subscriptions {
+		if sub.key() != subKey {
+			continue
+		}
+		if ref := refFor(sub.ModelRefs, s.modelChain(model)); ref != nil {
+			return ref.MaxConcurrentRequests
+		}
+		return 0
+	}
+	return 0
+}
+
+// OrganizationOf returns the tokenMetadata organization ID of the subscription of a model-scoped
+// subscription key (namespace/name@modelNamespace/modelName), or "" when it has none.
+func (s *Selector) OrganizationOf(subscriptionKey string) string {
+	subKey, _, _ := strings.Cut(subscriptionKey, "@")
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		s.logger.Warn("Failed to load subscriptions for organization lookup", "error", err)
+		return ""
+	}
+	for _, sub := range subscriptions {
+		if sub.key() == subKey {
+			return sub.OrganizationID
+		}
+	}
+	return ""
+}
+
+// EffectiveRateLimits returns the token and request rate limits under a model-scoped subscription
+// key (namespace/name@modelNamespace/modelName), after MaaSRateLimitOverrides and with the default
+// token limit maas-controller applies to model refs without one, looking through the model's
+// lineage like Select. Both are nil when the subscription does not cover the model or no longer
+// exists.
+func (s *Selector) EffectiveRateLimits(subscriptionKey string) ([]TokenRateLimit, []RequestRateLimit) {
+	subKey, model, ok := strings.Cut(subscriptionKey, "@")
+	if !ok {
+		return nil, nil
+	}
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		s.logger.Warn("Failed to load subscriptions for rate limit lookup", "error", err)
+		return nil, nil
+	}
+	for _, sub := range subscriptions {
+		if sub.key() != subKey {
+			continue
+		}
+		ref := refFor(sub.ModelRefs, s.modelChain(model))
+		if ref == nil {
+			return nil, nil
+		}
+		info := limitInfo(subKey, model, ref)
+		return info.TokenRateLimits, info.RequestRateLimits
+	}
+	return nil, nil
+}
+
+// MaxTokensPerDay returns the daily token cap of the subscription (namespace/name) shared by all
+// its users, or 0 when it has none or no longer exists.
+func (s *Selector) MaxTokensPerDay(subscriptionKey string) int64 {
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		s.logger.Warn("Failed to load subscriptions for daily token cap lookup", "error", err)
+		return 0
+	}
+	for _, sub := range subscriptions {
+		if sub.key() == subscriptionKey {
+			return sub.MaxTokensPerDay
+		}
+	}
+	return 0
+}
+
 // Select implements the subscription selection logic.
 // Returns the selected subscription or an error if none found.
-// If requestedModel is provided, validates that the selected subscription includes that model.
+// If requestedModel is provided, validates that the selected subscription includes that model
+// and returns the subscription's rate limits for it.
 func (s *Selector) Select(groups []string, username string, requestedSubscription string, requestedModel string) (*SelectResponse, error) {
+	return s.SelectForHost(groups, username, requestedSubscription, requestedModel, "")
+}
+
+// SelectForHost is Select for a request sent to host. Subscriptions bound to a gateway listener
+// are only selected for requests to its hostnames, and requests to those hostnames only select
+// subscriptions bound to them. An empty host skips the check.
+func (s *Selector) SelectForHost(groups []string, username string, requestedSubscription string, requestedModel string, host string) (*SelectResponse, error) {
+	if requestedModel != "" && s.maintenance != nil {
+		if inMaintenance, retryAfter := s.maintenance(requestedModel, s.now()); inMaintenance {
+			return nil, &ModelMaintenanceError{Model: requestedModel, RetryAfter: retryAfter}
+		}
+	}
+	var cluster string
+	if requestedModel != "" && s.cluster != nil {
+		var available bool
+		if cluster, available = s.cluster(requestedModel); !available {
+			return nil, &ModelClusterUnavailableError{Model: requestedModel, Cluster: cluster}
+		}
+	}
+	key := decisionKey(groups, username, requestedSubscription, requestedModel) + "\x00" + normalizeHost(host) + "\x00" + cluster
+	if d, ok := s.decisions.get(key); ok {
+		if d.resp != nil && !d.resp.GraceEndsAt.IsZero() {
+			graceSelectionsTotal.WithLabelValues(d.resp.Namespace + "/" + d.resp.Name).Inc()
+		}
+		return d.resp, d.err
+	}
+	resp, err := s.selectSubscription(groups, username, requestedSubscription, requestedModel, host)
+	if err == nil {
+		resp.RateLimits = rateLimitsFor(resp.ModelRefs, s.modelChain(requestedModel))
+		resp.TokenBudget = budgetFor(resp.ModelRefs, s.modelChain(requestedModel))
+		resp.Cluster = cluster
+	}
+	s.decisions.put(key, resp, err, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if !resp.GraceEndsAt.IsZero() {
+		graceSelectionsTotal.WithLabelValues(resp.Namespace + "/" + resp.Name).Inc()
+	}
+	return resp, nil
+}
+
+func (s *Selector) selectSubscription(groups []string, username string, requestedSubscription string, requestedModel string, host string) (*SelectResponse, error) {
 	if len(groups) == 0 && username == "" {
 		return nil, errors.New("either groups or username must be provided")
 	}
+	if requestedModel != "" && s.served != nil && !s.served(requestedModel) {
+		return nil, &ModelNotServedError{Model: requestedModel}
+	}
+	if requestedModel != "" && s.softDeleted != nil && s.softDeleted(requestedModel) {
+		return nil, &ModelDeletedError{Model: requestedModel}
+	}
 
 	subscriptions, err := s.loadSubscriptions()
 	if err != nil {
@@ -96,6 +460,9 @@ func (s *Selector) Select(groups []string, username string, requestedSubscriptio
 
 	// Sort by priority (desc), then maxLimit (desc)
 	sortSubscriptionsByPriority(subscriptions)
+	models := s.modelChain(requestedModel)
+	granted := grantedAccess(subscriptions, username, groups)
+	hosts := newHostFilter(subscriptions, host)
 
 	// Branch 1: Explicit subscription selection (with validation)
 	// Support both formats: "namespace/name" and bare "name"
@@ -104,14 +471,25 @@ func (s *Selector) Select(groups []string, username string, requestedSubscriptio
 		for _, sub := range subscriptions {
 			qualifiedName := fmt.Sprintf("%s/%s", sub.Namespace, sub.Name)
 			if qualifiedName == requestedSubscription {
-				if !userHasAccess(&sub, username, groups) {
-					return nil, &AccessDeniedError{Subscription: requestedSubscription}
+				via, ok := granted[sub.key()]
+				var graceEndsAt time.Time
+				if !ok {
+					if graceEndsAt = sub.graceEndsAt(username, groups, s.now()); graceEndsAt.IsZero() {
+						return nil, &AccessDeniedError{Subscription: requestedSubscription}
+					}
 				}
 				// Validate subscription includes the requested model
-				if requestedModel != "" && !subscriptionIncludesModel(&sub, requestedModel) {
+				if requestedModel != "" && coveredModel(&sub, models) == "" {
 					return nil, &ModelNotInSubscriptionError{Subscription: requestedSubscription, Model: requestedModel}
 				}
-				return toResponse(&sub), nil
+				if !hosts.allows(&sub) {
+					return nil, &HostMismatchError{Subscription: requestedSubscription, Host: hosts.host}
+				}
+				resp := grantedBy(selectedBy(toResponse(&sub), SelectedByHeader), via)
+				if !graceEndsAt.IsZero() {
+					return s.inGrace(resp, username, graceEndsAt), nil
+				}
+				return resp, nil
 			}
 		}
 
@@ -121,13 +499,24 @@ func (s *Selector) Select(groups []string, username string, requestedSubscriptio
 				if sub.Name != requestedSubscription {
 					continue
 				}
-				if !userHasAccess(&sub, username, groups) {
-					return nil, &AccessDeniedError{Subscription: requestedSubscription}
+				via, ok := granted[sub.key()]
+				var graceEndsAt time.Time
+				if !ok {
+					if graceEndsAt = sub.graceEndsAt(username, groups, s.now()); graceEndsAt.IsZero() {
+						return nil, &AccessDeniedError{Subscription: requestedSubscription}
+					}
 				}
-				if requestedModel != "" && !subscriptionIncludesModel(&sub, requestedModel) {
+				if requestedModel != "" && coveredModel(&sub, models) == "" {
 					return nil, &ModelNotInSubscriptionError{Subscription: requestedSubscription, Model: requestedModel}
 				}
-				return toResponse(&sub), nil
+				if !hosts.allows(&sub) {
+					return nil, &HostMismatchError{Subscription: requestedSubscription, Host: hosts.host}
+				}
+				resp := grantedBy(selectedBy(toResponse(&sub), SelectedByHeader), via)
+				if !graceEndsAt.IsZero() {
+					return s.inGrace(resp, username, graceEndsAt), nil
+				}
+				return resp, nil
 			}
 		}
 
@@ -137,22 +526,50 @@ func (s *Selector) Select(groups []string, username string, requestedSubscriptio
 
 	// Branch 2: Auto-selection
 	var accessibleSubs []subscription
+	elsewhere := false
 	for _, sub := range subscriptions {
-		if userHasAccess(&sub, username, groups) {
+		if _, ok := granted[sub.key()]; ok {
 			// If model is specified, only include subscriptions that contain that model
-			if requestedModel != "" && !subscriptionIncludesModel(&sub, requestedModel) {
+			if requestedModel != "" && coveredModel(&sub, models) == "" {
+				continue
+			}
+			if !hosts.allows(&sub) {
+				elsewhere = true
 				continue
 			}
 			accessibleSubs = append(accessibleSubs, sub)
 		}
 	}
+	accessibleSubs = preferOwned(accessibleSubs, granted)
 
 	if len(accessibleSubs) == 0 {
+		if elsewhere {
+			return nil, &HostMismatchError{Host: hosts.host}
+		}
 		return nil, &NoSubscriptionError{}
 	}
 
 	if len(accessibleSubs) == 1 {
-		return toResponse(&accessibleSubs[0]), nil
+		return grantedBy(selectedBy(toResponse(&accessibleSubs[0]), SelectedBySingle), granted[accessibleSubs[0].key()]), nil
+	}
+
+	// User belongs to multiple subscriptions - allow through the highest-ranked one if enabled,
+	// recording every match so metering can attribute the request.
+	if s.allowMultiple {
+		if s.tieBreak == TieBreakCheapest {
+			sortSubscriptionsByCost(accessibleSubs, models)
+		}
+		selected := grantedBy(selectedBy(toResponse(&accessibleSubs[0]), s.tieBreak), granted[accessibleSubs[0].key()])
+		for _, sub := range accessibleSubs {
+			selected.Candidates = append(selected.Candidates, sub.Namespace+"/"+sub.Name)
+		}
+		s.logger.Debug("Multiple subscriptions matched, applied tie-break",
+			"username", username,
+			"subscription", selected.Name,
+			"tieBreak", s.tieBreak,
+			"candidates", selected.Candidates,
+		)
+		return selected, nil
 	}
 
 	// User has multiple subscriptions - require explicit selection
@@ -179,41 +596,63 @@ func (s *Selector) SelectHighestPriority(groups []string, username string) (*Sel
 		return nil, &NoSubscriptionError{}
 	}
 
+	granted := grantedAccess(subscriptions, username, groups)
 	var accessible []subscription
 	for _, sub := range subscriptions {
-		if userHasAccess(&sub, username, groups) {
+		if _, ok := granted[sub.key()]; ok {
 			accessible = append(accessible, sub)
 		}
 	}
+	accessible = preferOwned(accessible, granted)
 
 	if len(accessible) == 0 {
 		return nil, &NoSubscriptionError{}
 	}
 
 	sortSubscriptionsByPriority(accessible)
-	return toResponse(&accessible[0]), nil
+	return grantedBy(toResponse(&accessible[0]), granted[accessible[0].key()]), nil
 }
 
-// loadSubscriptions fetches and parses MaaSSubscription resources.
+// loadSubscriptions returns the unexpired subscriptions, from the catalog when one is set and
+// otherwise by fetching and parsing the MaaSSubscription resources.
 func (s *Selector) loadSubscriptions() ([]subscription, error) {
-	objects, err := s.lister.List()
-	if err != nil {
-		return nil, err
+	var all []subscription
+	if s.catalog != nil {
+		var err error
+		if all, err = s.catalog.subscriptions(); err != nil {
+			return nil, err
+		}
+	} else {
+		objects, err := s.lister.List()
+		if err != nil {
+			return nil, err
+		}
+		all = make([]subscription, 0, len(objects))
+		for _, obj := range objects {
+			sub, err := parseSubscription(obj)
+			if err != nil {
+				s.logger.Warn("Failed to parse subscription, skipping",
+					"name", obj.GetName(),
+					"namespace", obj.GetNamespace(),
+					"error", err,
+				)
+				continue
+			}
+			all = append(all, sub)
+		}
 	}
 
-	subscriptions := make([]subscription, 0, len(objects))
-	for _, obj := range objects {
-		sub, err := parseSubscription(obj)
-		if err != nil {
-			s.logger.Warn("Failed to parse subscription, skipping",
-				"name", obj.GetName(),
-				"namespace", obj.GetNamespace(),
-				"error", err,
-			)
+	// Copy into a new slice, as callers reorder it and the catalog's is shared.
+	now := s.now()
+	subscriptions := make([]subscription, 0, len(all))
+	for _, sub := range all {
+		// Expired subscriptions grant nothing; maas-controller also drops their rate limits.
+		if !sub.ExpiresAt.IsZero() && !now.Before(sub.ExpiresAt) {
 			continue
 		}
 		subscriptions = append(subscriptions, sub)
 	}
+	applyOverrides(subscriptions, s.loadOverrides())
 
 	return subscriptions, nil
 }
@@ -255,6 +694,38 @@ func parseSubscription(obj *unstructured.Unstructured) (subscription, error) {
 		}
 	}
 
+	if expiresAt, found, _ := unstructured.NestedString(spec, "expiresAt"); found && expiresAt != "" {
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return subscription{}, fmt.Errorf("invalid spec.expiresAt: %w", err)
+		}
+		sub.ExpiresAt = t
+	}
+
+	if maxTokens, found, _ := unstructured.NestedInt64(spec, "maxTokensPerDay"); found && maxTokens > 0 {
+		sub.MaxTokensPerDay = maxTokens
+	}
+
+	if sandbox, found, _ := unstructured.NestedBool(spec, "sandbox"); found {
+		sub.Sandbox = sandbox
+	}
+
+	if hostnames, found, _ := unstructured.NestedStringSlice(spec, "listener", "hostnames"); found {
+		sub.Hostnames = hostnames
+	}
+
+	if attachments, found, _ := unstructured.NestedMap(spec, "attachments"); found {
+		policy, err := parseAttachmentPolicy(attachments)
+		if err != nil {
+			return subscription{}, fmt.Errorf("invalid spec.attachments: %w", err)
+		}
+		sub.Attachments = policy
+	}
+
+	if endpoints, found, _ := unstructured.NestedStringSlice(spec, "endpoints"); found {
+		sub.Endpoints = endpoints
+	}
+
 	// Parse priority
 	if priority, found, _ := unstructured.NestedInt64(spec, "priority"); found {
 		if priority >= 0 && priority <= 2147483647 {
@@ -280,10 +751,48 @@ func parseSubscription(obj *unstructured.Unstructured) (subscription, error) {
 	// Parse tokenMetadata
 	parseTokenMetadata(spec, &sub)
 
+	sub.RevokedOwners = parseRevokedOwners(obj)
+
+	for name := range strings.SplitSeq(obj.GetAnnotations()[constant.AnnotationIncludes], ",") {
+		if name = strings.TrimSpace(name); name != "" && name != sub.Name {
+			sub.Includes = append(sub.Includes, name)
+		}
+	}
+
 	return sub, nil
 }
 
 // parseModelRef extracts a ModelRefInfo from an unstructured model ref map.
+// parseAttachmentPolicy parses spec.attachments. maxSize is a Kubernetes quantity, given as a
+// number of bytes or a string such as "5Mi".
+func parseAttachmentPolicy(spec map[string]any) (*AttachmentPolicy, error) {
+	policy := &AttachmentPolicy{}
+	if n, found, _ := unstructured.NestedInt64(spec, "maxCount"); found {
+		policy.MaxCount = &n
+	}
+	if n, found, _ := unstructured.NestedInt64(spec, "maxImageDimension"); found {
+		policy.MaxImageDimension = &n
+	}
+	switch size := spec["maxSize"].(type) {
+	case nil:
+	case int64:
+		policy.MaxSize = &size
+	case string:
+		q, err := resource.ParseQuantity(size)
+		if err != nil {
+			return nil, fmt.Errorf("maxSize %q: %w", size, err)
+		}
+		n := q.Value()
+		policy.MaxSize = &n
+	default:
+		return nil, fmt.Errorf("maxSize must be a quantity, got %v", size)
+	}
+	if types, found, _ := unstructured.NestedStringSlice(spec, "allowedTypes"); found {
+		policy.AllowedTypes = types
+	}
+	return policy, nil
+}
+
 func parseModelRef(modelMap map[string]any) ModelRefInfo {
 	ref := ModelRefInfo{}
 	if name, ok := modelMap["name"].(string); ok {
@@ -292,7 +801,36 @@ func parseModelRef(modelMap map[string]any) ModelRefInfo {
 	if ns, ok := modelMap["namespace"].(string); ok {
 		ref.Namespace = ns
 	}
-	if limits, found, _ := unstructured.NestedSlice(modelMap, "tokenRateLimits"); found {
+	ref.TokenRateLimits, ref.RequestRateLimits = parseRateLimits(modelMap)
+	if billingRate, found, _ := unstructured.NestedMap(modelMap, "billingRate"); found {
+		br := &BillingRate{}
+		if perToken, ok := billingRate["perToken"].(string); ok {
+			br.PerToken = perToken
+		}
+		ref.BillingRate = br
+	}
+	if budget, found, _ := unstructured.NestedMap(modelMap, "tokenBudget"); found {
+		tb := &TokenBudget{}
+		if limit, ok := budget["limit"].(int64); ok {
+			tb.Limit = limit
+		}
+		if period, ok := budget["period"].(string); ok {
+			tb.Period = period
+		}
+		ref.TokenBudget = tb
+	}
+	if n, ok := modelMap["maxConcurrentRequests"].(int64); ok && n > 0 {
+		ref.MaxConcurrentRequests = n
+	}
+	return ref
+}
+
+// parseRateLimits extracts the tokenRateLimits and requestRateLimits of a model ref or
+// MaaSRateLimitOverride spec.
+func parseRateLimits(m map[string]any) ([]TokenRateLimit, []RequestRateLimit) {
+	var tokenLimits []TokenRateLimit
+	var requestLimits []RequestRateLimit
+	if limits, found, _ := unstructured.NestedSlice(m, "tokenRateLimits"); found {
 		for _, limitRaw := range limits {
 			if limitMap, ok := limitRaw.(map[string]any); ok {
 				trl := TokenRateLimit{}
@@ -302,18 +840,25 @@ func parseModelRef(modelMap map[string]any) ModelRefInfo {
 				if window, ok := limitMap["window"].(string); ok {
 					trl.Window = window
 				}
-				ref.TokenRateLimits = append(ref.TokenRateLimits, trl)
+				tokenLimits = append(tokenLimits, trl)
 			}
 		}
 	}
-	if billingRate, found, _ := unstructured.NestedMap(modelMap, "billingRate"); found {
-		br := &BillingRate{}
-		if perToken, ok := billingRate["perToken"].(string); ok {
-			br.PerToken = perToken
+	if limits, found, _ := unstructured.NestedSlice(m, "requestRateLimits"); found {
+		for _, limitRaw := range limits {
+			if limitMap, ok := limitRaw.(map[string]any); ok {
+				rrl := RequestRateLimit{}
+				if limit, ok := limitMap["limit"].(int64); ok {
+					rrl.Limit = limit
+				}
+				if window, ok := limitMap["window"].(string); ok {
+					rrl.Window = window
+				}
+				requestLimits = append(requestLimits, rrl)
+			}
 		}
-		ref.BillingRate = br
 	}
-	return ref
+	return tokenLimits, requestLimits
 }
 
 // parseTokenMetadata extracts tokenMetadata fields from the spec into the subscription.
@@ -358,29 +903,71 @@ func userHasAccess(sub *subscription, username string, groups []string) bool {
 	return false
 }
 
-// subscriptionIncludesModel checks if the subscription's modelRefs includes the requested model.
-// requestedModel format: "namespace/name".
-func subscriptionIncludesModel(sub *subscription, requestedModel string) bool {
-	if requestedModel == "" {
-		return true // no model specified, so subscription is valid
+// grantedAccess returns the subscriptions the caller may use, keyed by namespace/name. The value
+// is "" for subscriptions the caller owns, and otherwise the owned subscription whose includes
+// (followed transitively, within one namespace) reach it.
+func grantedAccess(subs []subscription, username string, groups []string) map[string]string {
+	granted := map[string]string{}
+	type step struct {
+		sub *subscription
+		via string
 	}
-
-	// Parse the requested model (format: "namespace/name")
-	parts := strings.SplitN(requestedModel, "/", 2)
-	if len(parts) != 2 {
-		return false // invalid format
+	var queue []step
+	for i := range subs {
+		if userHasAccess(&subs[i], username, groups) {
+			granted[subs[i].key()] = ""
+			queue = append(queue, step{&subs[i], subs[i].key()})
+		}
 	}
-	requestedNS := parts[0]
-	requestedName := parts[1]
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for i := range subs {
+			other := &subs[i]
+			if _, seen := granted[other.key()]; seen || other.Namespace != next.sub.Namespace {
+				continue
+			}
+			if slices.Contains(next.sub.Includes, "*") || slices.Contains(next.sub.Includes, other.Name) {
+				granted[other.key()] = next.via
+				queue = append(queue, step{other, next.via})
+			}
+		}
+	}
+	return granted
+}
 
-	// Check if any modelRef in the subscription matches
-	for _, ref := range sub.ModelRefs {
-		if ref.Namespace == requestedNS && ref.Name == requestedName {
-			return true
+// preferOwned returns the subscriptions the caller owns when there are any, so inherited access
+// never turns a single owned match into an ambiguous choice.
+func preferOwned(subs []subscription, granted map[string]string) []subscription {
+	var owned []subscription
+	for _, sub := range subs {
+		if granted[sub.key()] == "" {
+			owned = append(owned, sub)
 		}
 	}
+	if len(owned) > 0 {
+		return owned
+	}
+	return subs
+}
 
-	return false
+// grantedBy records on the response which owned subscription granted access, if it was inherited.
+func grantedBy(resp *SelectResponse, via string) *SelectResponse {
+	resp.GrantedBy = via
+	return resp
+}
+
+// coveredModel returns the first model in models ("namespace/name", the requested model followed
+// by its ancestors) that the subscription's modelRefs include, or "" when none is.
+func coveredModel(sub *subscription, models []string) string {
+	for _, model := range models {
+		for _, ref := range sub.ModelRefs {
+			if ref.Namespace+"/"+ref.Name == model {
+				return model
+			}
+		}
+	}
+	return ""
 }
 
 // hasModel returns true if the subscription includes the given model name.
@@ -406,17 +993,66 @@ func sortSubscriptionsByPriority(subs []subscription) {
 	})
 }
 
+// sortSubscriptionsByCost stably sorts by the per-token billing rate for the requested model
+// (models is the model followed by its ancestors; nil means the subscription's cheapest rate),
+// ascending. Subscriptions without a parseable rate sort last, so priority order is kept among
+// equal or unknown rates.
+func sortSubscriptionsByCost(subs []subscription, models []string) {
+	sort.SliceStable(subs, func(i, j int) bool {
+		return subs[i].perTokenRate(coveredModel(&subs[i], models)) < subs[j].perTokenRate(coveredModel(&subs[j], models))
+	})
+}
+
+// perTokenRate returns the billing rate that applies to requestedModel, or +Inf when unknown.
+func (s subscription) perTokenRate(requestedModel string) float64 {
+	rate := math.Inf(1)
+	for i := range s.ModelRefs {
+		ref := &s.ModelRefs[i]
+		if requestedModel != "" && ref.Namespace+"/"+ref.Name != requestedModel {
+			continue
+		}
+		if ref.BillingRate == nil {
+			continue
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(ref.BillingRate.PerToken), 64); err == nil && v < rate {
+			rate = v
+		}
+	}
+	return rate
+}
+
+// selectedBy records how a subscription was chosen on the response.
+func selectedBy(resp *SelectResponse, how string) *SelectResponse {
+	resp.SelectedBy = how
+	return resp
+}
+
 // ListAccessibleForModel returns subscriptions the user has access to
 // that include the specified model in their modelRefs.
 func (s *Selector) ListAccessibleForModel(username string, groups []string, modelID string) ([]SubscriptionInfo, error) {
+	return s.listAccessible(username, groups, func(sub *subscription) bool { return sub.hasModel(modelID) })
+}
+
+// ListAccessibleForModelRef is ListAccessibleForModel matching the model by namespace and name, so
+// same-named models in other namespaces are not included.
+func (s *Selector) ListAccessibleForModelRef(username string, groups []string, namespace, name string) ([]SubscriptionInfo, error) {
+	return s.listAccessible(username, groups, func(sub *subscription) bool {
+		return slices.ContainsFunc(sub.ModelRefs, func(ref ModelRefInfo) bool {
+			return ref.Namespace == namespace && ref.Name == name
+		})
+	})
+}
+
+func (s *Selector) listAccessible(username string, groups []string, includes func(*subscription) bool) ([]SubscriptionInfo, error) {
 	subscriptions, err := s.loadSubscriptions()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
 	}
 
+	granted := grantedAccess(subscriptions, username, groups)
 	result := []SubscriptionInfo{}
 	for _, sub := range subscriptions {
-		if userHasAccess(&sub, username, groups) && sub.hasModel(modelID) {
+		if _, ok := granted[sub.key()]; ok && includes(&sub) {
 			result = append(result, toSubscriptionInfo(&sub))
 		}
 	}
@@ -451,6 +1087,7 @@ func toSubscriptionInfo(sub *subscription) SubscriptionInfo {
 		OrganizationID:          sub.OrganizationID,
 		CostCenter:              sub.CostCenter,
 		Labels:                  sub.Labels,
+		ExpiresAt:               sub.ExpiresAt,
 	}
 }
 
@@ -476,6 +1113,7 @@ func ResponseToSubscriptionInfo(sub *SelectResponse) SubscriptionInfo {
 		OrganizationID:          sub.OrganizationID,
 		CostCenter:              sub.CostCenter,
 		Labels:                  sub.Labels,
+		ExpiresAt:               sub.ExpiresAt,
 	}
 }
 
@@ -495,6 +1133,46 @@ func toResponse(sub *subscription) *SelectResponse {
 		OrganizationID: sub.OrganizationID,
 		CostCenter:     sub.CostCenter,
 		Labels:         sub.Labels,
+		ExpiresAt:      sub.ExpiresAt,
+		Sandbox:        sub.Sandbox,
+		Attachments:    sub.Attachments,
+		Endpoints:      sub.Endpoints,
+	}
+}
+
+// ErrorCode maps a Select error to the code the select endpoint returns in SelectResponse.Error.
+func ErrorCode(err error) string {
+	var noSubErr *NoSubscriptionError
+	var notFoundErr *SubscriptionNotFoundError
+	var accessDeniedErr *AccessDeniedError
+	var multipleSubsErr *MultipleSubscriptionsError
+	var modelNotInSubErr *ModelNotInSubscriptionError
+	var hostMismatchErr *HostMismatchError
+	var modelDeletedErr *ModelDeletedError
+	var modelNotServedErr *ModelNotServedError
+	var modelMaintenanceErr *ModelMaintenanceError
+	var clusterUnavailableErr *ModelClusterUnavailableError
+	switch {
+	case errors.As(err, &noSubErr), errors.As(err, &notFoundErr):
+		return reason.NotFound
+	case errors.As(err, &accessDeniedErr):
+		return reason.AccessDenied
+	case errors.As(err, &multipleSubsErr):
+		return reason.MultipleSubscriptions
+	case errors.As(err, &modelNotInSubErr):
+		return reason.ModelNotInSubscription
+	case errors.As(err, &hostMismatchErr):
+		return reason.HostMismatch
+	case errors.As(err, &modelDeletedErr):
+		return reason.ModelDeleted
+	case errors.As(err, &modelNotServedErr):
+		return reason.ModelNotFound
+	case errors.As(err, &modelMaintenanceErr):
+		return reason.ModelMaintenance
+	case errors.As(err, &clusterUnavailableErr):
+		return reason.ClusterUnavailable
+	default:
+		return reason.InternalError
 	}
 }
 
@@ -541,3 +1219,61 @@ type ModelNotInSubscriptionError struct {
 func (e *ModelNotInSubscriptionError) Error() string {
 	return fmt.Sprintf("subscription %s does not include model %s", e.Subscription, e.Model)
 }
+
+// HostMismatchError indicates the subscription is not served on the host the request was sent to,
+// because either is bound to a different gateway listener.
+type HostMismatchError struct {
+	Subscription string // empty when auto-selection found subscriptions only on other hosts
+	Host         string
+}
+
+func (e *HostMismatchError) Error() string {
+	if e.Subscription == "" {
+		return fmt.Sprintf("none of the user's subscriptions is served on host %s", e.Host)
+	}
+	return fmt.Sprintf("subscription %s is not served on host %s", e.Subscription, e.Host)
+}
+
+// ModelDeletedError indicates the requested model is soft-deleted.
+type ModelDeletedError struct {
+	Model string
+}
+
+func (e *ModelDeletedError) Error() string {
+	return fmt.Sprintf("model %s has been deleted", e.Model)
+}
+
+// ModelNotServedError indicates the requested model is outside this instance's model scope.
+type ModelNotServedError struct {
+	Model string
+}
+
+func (e *ModelNotServedError) Error() string {
+	return fmt.Sprintf("model %s is not served here", e.Model)
+}
+
+// ModelMaintenanceError indicates the requested model is in maintenance.
+type ModelMaintenanceError struct {
+	Model      string
+	RetryAfter time.Duration // how long clients should wait before retrying
+}
+
+func (e *ModelMaintenanceError) Error() string {
+	return fmt.Sprintf("model %s is under maintenance", e.Model)
+}
+
+// ModelClusterUnavailableError indicates the federated cluster hosting the requested model is
+// unavailable.
+type ModelClusterUnavailableError struct {
+	Model   string
+	Cluster string
+}
+
+func (e *ModelClusterUnavailableError) Error() string {
+	return fmt.Sprintf("cluster %s hosting model %s is unavailable", e.Cluster, e.Model)
+}
+
+// RetryAfterSeconds rounds d up to whole seconds for a Retry-After header, at least 1.
+func RetryAfterSeconds(d time.Duration) int64 {
+	return max(int64(math.Ceil(d.Seconds())), 1)
+}
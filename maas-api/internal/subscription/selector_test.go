@@ -2,7 +2,9 @@ package subscription_test
 
 import (
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
@@ -333,3 +335,469 @@ func TestSelectHighestPriority(t *testing.T) {
 		}
 	})
 }
+
+func TestSelectAllowMultiple(t *testing.T) {
+	log := logger.New(false)
+	subs := []*unstructured.Unstructured{
+		createSubscription("free-sub", []string{"system:authenticated"}, nil, 10, defaultTestTokenRateLimit, "", ""),
+		createSubscription("org-premium", []string{"org-a"}, nil, 50, defaultTestTokenRateLimit, "", ""),
+	}
+
+	t.Run("disabled requires explicit selection", func(t *testing.T) {
+		sel := subscription.NewSelector(log, &fakeLister{subscriptions: subs})
+		_, err := sel.Select([]string{"system:authenticated", "org-a"}, "alice", "", "")
+		var multi *subscription.MultipleSubscriptionsError
+		if !errors.As(err, &multi) {
+			t.Fatalf("expected MultipleSubscriptionsError, got %T %v", err, err)
+		}
+	})
+
+	t.Run("enabled allows through highest ranked match", func(t *testing.T) {
+		sel := subscription.NewSelector(log, &fakeLister{subscriptions: subs})
+		sel.SetAllowMultiple(true)
+		got, err := sel.Select([]string{"system:authenticated", "org-a"}, "alice", "", "")
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if got.Name != "org-premium" {
+			t.Errorf("expected org-premium, got %q", got.Name)
+		}
+		want := []string{"test-ns/org-premium", "test-ns/free-sub"}
+		if !slices.Equal(got.Candidates, want) {
+			t.Errorf("expected candidates %v, got %v", want, got.Candidates)
+		}
+	})
+
+	t.Run("single match records no candidates", func(t *testing.T) {
+		sel := subscription.NewSelector(log, &fakeLister{subscriptions: subs})
+		sel.SetAllowMultiple(true)
+		got, err := sel.Select([]string{"system:authenticated"}, "bob", "", "")
+		if err != nil {
+			t.Fatalf("Select: %v", err)
+		}
+		if got.Name != "free-sub" || len(got.Candidates) != 0 {
+			t.Errorf("expected free-sub without candidates, got %q %v", got.Name, got.Candidates)
+		}
+	})
+}
+
+func TestSelectTieBreak(t *testing.T) {
+	log := logger.New(false)
+	withRate := func(u *unstructured.Unstructured, perToken string) *unstructured.Unstructured {
+		refs, _, _ := unstructured.NestedSlice(u.Object, "spec", "modelRefs")
+		ref, _ := refs[0].(map[string]any)
+		ref["namespace"] = "llm"
+		if perToken != "" {
+			ref["billingRate"] = map[string]any{"perToken": perToken}
+		}
+		_ = unstructured.SetNestedSlice(u.Object, refs, "spec", "modelRefs")
+		return u
+	}
+	subs := []*unstructured.Unstructured{
+		withRate(createSubscription("premium", []string{"g1"}, nil, 50, defaultTestTokenRateLimit, "", ""), "0.002"),
+		withRate(createSubscription("discount", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", ""), "0.0005"),
+		withRate(createSubscription("unpriced", []string{"g1"}, nil, 90, defaultTestTokenRateLimit, "", ""), ""),
+	}
+
+	tests := []struct {
+		name       string
+		tieBreak   string
+		requested  string
+		wantName   string
+		selectedBy string
+	}{
+		{name: "priority", tieBreak: subscription.TieBreakPriority, wantName: "unpriced", selectedBy: "priority"},
+		{name: "cheapest", tieBreak: subscription.TieBreakCheapest, wantName: "discount", selectedBy: "cheapest"},
+		{name: "header overrides rule", tieBreak: subscription.TieBreakCheapest, requested: "premium", wantName: "premium", selectedBy: "header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := subscription.NewSelector(log, &fakeLister{subscriptions: subs})
+			sel.SetAllowMultiple(true)
+			sel.SetTieBreak(tt.tieBreak)
+			got, err := sel.Select([]string{"g1"}, "alice", tt.requested, "llm/test-model")
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			if got.Name != tt.wantName || got.SelectedBy != tt.selectedBy {
+				t.Errorf("got %q selected by %q, want %q by %q", got.Name, got.SelectedBy, tt.wantName, tt.selectedBy)
+			}
+		})
+	}
+}
+
+func TestSelectLineage(t *testing.T) {
+	log := logger.New(false)
+	inLLM := func(u *unstructured.Unstructured, perToken string) *unstructured.Unstructured {
+		refs, _, _ := unstructured.NestedSlice(u.Object, "spec", "modelRefs")
+		ref, _ := refs[0].(map[string]any)
+		ref["namespace"] = "llm"
+		ref["billingRate"] = map[string]any{"perToken": perToken}
+		_ = unstructured.SetNestedSlice(u.Object, refs, "spec", "modelRefs")
+		return u
+	}
+	subs := []*unstructured.Unstructured{
+		inLLM(createSubscription("premium", []string{"g1"}, nil, 50, defaultTestTokenRateLimit, "", ""), "0.002"),
+		inLLM(createSubscription("discount", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", ""), "0.0005"),
+	}
+	lineage := func(model string) []string {
+		if model == "llm/test-model-legal" {
+			return []string{"llm/test-model"}
+		}
+		return nil
+	}
+
+	sel := subscription.NewSelector(log, &fakeLister{subscriptions: subs})
+	_, err := sel.Select([]string{"g1"}, "alice", "premium", "llm/test-model-legal")
+	var notInSub *subscription.ModelNotInSubscriptionError
+	if !errors.As(err, &notInSub) {
+		t.Fatalf("without lineage: got %v, want ModelNotInSubscriptionError", err)
+	}
+
+	sel.SetLineageResolver(lineage)
+	got, err := sel.Select([]string{"g1"}, "alice", "premium", "llm/test-model-legal")
+	if err != nil || got.Name != "premium" {
+		t.Fatalf("variant via parent: got %v, %v; want premium", got, err)
+	}
+
+	sel.SetAllowMultiple(true)
+	sel.SetTieBreak(subscription.TieBreakCheapest)
+	got, err = sel.Select([]string{"g1"}, "alice", "", "llm/test-model-legal")
+	if err != nil || got.Name != "discount" {
+		t.Fatalf("cheapest for variant: got %v, %v; want discount", got, err)
+	}
+}
+
+func TestSelectRateLimits(t *testing.T) {
+	log := logger.New(false)
+	sub := createSubscription("premium", []string{"g1"}, nil, 10, 1000, "", "")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{map[string]any{
+		"name":      "test-model",
+		"namespace": "llm",
+		"tokenRateLimits": []any{
+			map[string]any{"limit": int64(1000), "window": "1m"},
+			map[string]any{"limit": int64(6000), "window": "1h"},
+		},
+		"requestRateLimits": []any{
+			map[string]any{"limit": int64(600), "window": "1h"},
+		},
+	}}, "spec", "modelRefs")
+	sel := subscription.NewSelector(log, &fakeLister{subscriptions: []*unstructured.Unstructured{sub}})
+	sel.SetLineageResolver(func(model string) []string {
+		if model == "llm/test-model-legal" {
+			return []string{"llm/test-model"}
+		}
+		return nil
+	})
+
+	got, err := sel.Select([]string{"g1"}, "alice", "", "llm/test-model")
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	want := subscription.RateLimits{Model: "llm/test-model", RequestsPerMinute: 10, TokensPerMinute: 100}
+	if got.RateLimits == nil || *got.RateLimits != want {
+		t.Errorf("RateLimits = %+v, want %+v", got.RateLimits, want)
+	}
+
+	got, err = sel.Select([]string{"g1"}, "alice", "", "llm/test-model-legal")
+	if err != nil {
+		t.Fatalf("Select variant: %v", err)
+	}
+	if got.RateLimits == nil || *got.RateLimits != want {
+		t.Errorf("variant RateLimits = %+v, want the parent's %+v", got.RateLimits, want)
+	}
+
+	got, err = sel.Select([]string{"g1"}, "alice", "", "")
+	if err != nil {
+		t.Fatalf("Select without model: %v", err)
+	}
+	if got.RateLimits != nil {
+		t.Errorf("RateLimits without a requested model = %+v, want nil", got.RateLimits)
+	}
+}
+
+func TestTokenBudget(t *testing.T) {
+	log := logger.New(false)
+	sub := createSubscription("premium", []string{"g1"}, nil, 10, 1000, "", "")
+	_ = unstructured.SetNestedSlice(sub.Object, []any{
+		map[string]any{
+			"name":        "test-model",
+			"namespace":   "llm",
+			"tokenBudget": map[string]any{"limit": int64(50000), "period": "30d"},
+		},
+		map[string]any{"name": "other-model", "namespace": "llm"},
+	}, "spec", "modelRefs")
+	sel := subscription.NewSelector(log, &fakeLister{subscriptions: []*unstructured.Unstructured{sub}})
+	sel.SetLineageResolver(func(model string) []string {
+		if model == "llm/test-model-legal" {
+			return []string{"llm/test-model"}
+		}
+		return nil
+	})
+	want := subscription.TokenBudget{Limit: 50000, Period: "30d"}
+
+	got, err := sel.Select([]string{"g1"}, "alice", "", "llm/test-model")
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if got.TokenBudget == nil || *got.TokenBudget != want {
+		t.Errorf("Select TokenBudget = %+v, want %+v", got.TokenBudget, want)
+	}
+
+	tests := []struct {
+		key  string
+		want *subscription.TokenBudget
+	}{
+		{"test-ns/premium@llm/test-model", &want},
+		{"test-ns/premium@llm/test-model-legal", &want},
+		{"test-ns/premium@llm/other-model", nil},
+		{"test-ns/missing@llm/test-model", nil},
+		{"test-ns/premium", nil},
+	}
+	for _, tt := range tests {
+		budget := sel.TokenBudget(tt.key)
+		if (budget == nil) != (tt.want == nil) || (budget != nil && *budget != *tt.want) {
+			t.Errorf("TokenBudget(%q) = %+v, want %+v", tt.key, budget, tt.want)
+		}
+	}
+}
+
+func TestTiersForModel(t *testing.T) {
+	log := logger.New(false)
+	other := createSubscription("free", []string{"g2"}, nil, 1, 100, "", "")
+	_ = unstructured.SetNestedSlice(other.Object, []any{map[string]any{"name": "other-model", "namespace": "llm"}}, "spec", "modelRefs")
+	sel := subscription.NewSelector(log, &fakeLister{subscriptions: []*unstructured.Unstructured{
+		createSubscription("premium", []string{"g1"}, nil, 10, 1000, "", ""),
+		createSubscription("basic", []string{"g3"}, nil, 5, 100, "", ""),
+		other,
+	}})
+	sel.SetLineageResolver(func(model string) []string {
+		if model == "/test-model-legal" {
+			return []string{"/test-model"}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		model string
+		want  []string
+	}{
+		{"/test-model", []string{"test-ns/basic", "test-ns/premium"}},
+		{"/test-model-legal", []string{"test-ns/basic", "test-ns/premium"}},
+		{"llm/other-model", []string{"test-ns/free"}},
+		{"llm/missing", []string{}},
+	}
+	for _, tt := range tests {
+		got, err := sel.TiersForModel(tt.model)
+		if err != nil {
+			t.Fatalf("TiersForModel(%q): %v", tt.model, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("TiersForModel(%q) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestSelectSkipsExpiredSubscriptions(t *testing.T) {
+	log := logger.New(false)
+	expired := createSubscription("expired", []string{"g1"}, nil, 50, defaultTestTokenRateLimit, "", "")
+	_ = unstructured.SetNestedField(expired.Object, time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), "spec", "expiresAt")
+	trial := createSubscription("trial", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	_ = unstructured.SetNestedField(trial.Object, expiresAt.Format(time.RFC3339), "spec", "expiresAt")
+
+	sel := subscription.NewSelector(log, &fakeLister{subscriptions: []*unstructured.Unstructured{expired, trial}})
+	got, err := sel.Select([]string{"g1"}, "alice", "", "")
+	if err != nil {
+		t.Fatalf("Select: %v", err)
+	}
+	if got.Name != "trial" || !got.ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Select = %s expiring %v, want trial expiring %v", got.Name, got.ExpiresAt, expiresAt)
+	}
+
+	_, err = sel.Select([]string{"g1"}, "alice", "expired", "")
+	var notFound *subscription.SubscriptionNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("explicit expired subscription: got %v, want SubscriptionNotFoundError", err)
+	}
+}
+
+func TestSelectExpiryFollowsClock(t *testing.T) {
+	trial := createSubscription("trial", []string{"g1"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	_ = unstructured.SetNestedField(trial.Object, time.Now().Add(time.Minute).UTC().Format(time.RFC3339), "spec", "expiresAt")
+
+	sel := subscription.NewSelector(logger.New(false), &fakeLister{subscriptions: []*unstructured.Unstructured{trial}})
+	if _, err := sel.Select([]string{"g1"}, "alice", "", ""); err != nil {
+		t.Fatalf("Select on the local clock: %v", err)
+	}
+
+	// The API server's clock is five minutes ahead: the subscription has already ended there.
+	sel.SetClock(func() time.Time { return time.Now().Add(5 * time.Minute) })
+	_, err := sel.Select([]string{"g1"}, "alice", "", "")
+	var noSub *subscription.NoSubscriptionError
+	if !errors.As(err, &noSub) {
+		t.Errorf("Select on a clock past expiry: got %v, want NoSubscriptionError", err)
+	}
+}
+
+func TestSelectDowngradeGracePeriod(t *testing.T) {
+	premium := createSubscription("premium", []string{"premium-users"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	graceEndsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	_ = unstructured.SetNestedSlice(premium.Object, []any{
+		map[string]any{"group": "former-premium", "revokedAt": time.Now().UTC().Format(time.RFC3339), "graceEndsAt": graceEndsAt.Format(time.RFC3339)},
+		map[string]any{"user": "bob", "revokedAt": time.Now().UTC().Format(time.RFC3339), "graceEndsAt": graceEndsAt.Format(time.RFC3339)},
+	}, "status", "revokedOwners")
+	free := createSubscription("free", []string{"former-premium"}, nil, 0, defaultTestTokenRateLimit, "", "")
+	sel := subscription.NewSelector(logger.New(false), &fakeLister{subscriptions: []*unstructured.Unstructured{premium, free}})
+
+	for _, tc := range []struct {
+		groups   []string
+		username string
+	}{
+		{[]string{"former-premium"}, "alice"},
+		{nil, "bob"},
+	} {
+		got, err := sel.Select(tc.groups, tc.username, "premium", "")
+		if err != nil {
+			t.Fatalf("Select for %s during the grace period: %v", tc.username, err)
+		}
+		if !got.GraceEndsAt.Equal(graceEndsAt) || got.Warning == "" {
+			t.Errorf("Select for %s = grace ending %v with warning %q, want %v and a warning", tc.username, got.GraceEndsAt, got.Warning, graceEndsAt)
+		}
+	}
+
+	got, err := sel.Select([]string{"former-premium"}, "alice", "", "")
+	if err != nil || got.Name != "free" {
+		t.Errorf("auto-selection = %v, %v; want the subscription the caller still owns", got, err)
+	}
+	got, err = sel.Select([]string{"premium-users"}, "carol", "premium", "")
+	if err != nil || !got.GraceEndsAt.IsZero() || got.Warning != "" {
+		t.Errorf("Select for an owner = %+v, %v; want no grace period", got, err)
+	}
+
+	sel.SetClock(func() time.Time { return time.Now().Add(2 * time.Hour) })
+	_, err = sel.Select([]string{"former-premium"}, "alice", "premium", "")
+	var denied *subscription.AccessDeniedError
+	if !errors.As(err, &denied) {
+		t.Errorf("Select after the grace period: got %v, want AccessDeniedError", err)
+	}
+}
+
+func TestSelectIncludedSubscriptions(t *testing.T) {
+	log := logger.New(false)
+	withModel := func(u *unstructured.Unstructured, model string) *unstructured.Unstructured {
+		_ = unstructured.SetNestedSlice(u.Object, []any{map[string]any{"name": model, "namespace": "llm"}}, "spec", "modelRefs")
+		return u
+	}
+	includes := func(u *unstructured.Unstructured, names string) *unstructured.Unstructured {
+		u.SetAnnotations(map[string]string{"maas.opendatahub.io/includes": names})
+		return u
+	}
+	free := withModel(createSubscription("free", []string{"free-users"}, nil, 0, defaultTestTokenRateLimit, "", ""), "small")
+	premium := includes(withModel(createSubscription("premium", []string{"premium-users"}, nil, 10, defaultTestTokenRateLimit, "", ""), "granite"), "free")
+	enterprise := includes(withModel(createSubscription("enterprise", []string{"enterprise-users"}, nil, 20, defaultTestTokenRateLimit, "", ""), "large"), "premium")
+	sel := subscription.NewSelector(log, &fakeLister{subscriptions: []*unstructured.Unstructured{free, premium, enterprise}})
+
+	tests := []struct {
+		name          string
+		groups        []string
+		requested     string
+		model         string
+		wantSub       string
+		wantGrantedBy string
+	}{
+		{name: "owned", groups: []string{"enterprise-users"}, model: "llm/large", wantSub: "enterprise"},
+		{name: "included", groups: []string{"enterprise-users"}, model: "llm/granite", wantSub: "premium", wantGrantedBy: "test-ns/enterprise"},
+		{name: "transitively included", groups: []string{"enterprise-users"}, model: "llm/small", wantSub: "free", wantGrantedBy: "test-ns/enterprise"},
+		{name: "explicitly requested", groups: []string{"enterprise-users"}, requested: "free", wantSub: "free", wantGrantedBy: "test-ns/enterprise"},
+		{name: "owned preferred without model", groups: []string{"enterprise-users"}, wantSub: "enterprise"},
+		{name: "owned preferred over included", groups: []string{"premium-users", "free-users"}, model: "llm/small", wantSub: "free"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sel.Select(tt.groups, "alice", tt.requested, tt.model)
+			if err != nil {
+				t.Fatalf("Select: %v", err)
+			}
+			if got.Name != tt.wantSub || got.GrantedBy != tt.wantGrantedBy {
+				t.Errorf("Select = %s granted by %q, want %s granted by %q", got.Name, got.GrantedBy, tt.wantSub, tt.wantGrantedBy)
+			}
+		})
+	}
+
+	t.Run("inclusion is one-way", func(t *testing.T) {
+		_, err := sel.Select([]string{"free-users"}, "alice", "", "llm/granite")
+		var noSub *subscription.NoSubscriptionError
+		if !errors.As(err, &noSub) {
+			t.Errorf("got %v, want NoSubscriptionError", err)
+		}
+		_, err = sel.Select([]string{"premium-users"}, "alice", "enterprise", "")
+		var denied *subscription.AccessDeniedError
+		if !errors.As(err, &denied) {
+			t.Errorf("got %v, want AccessDeniedError", err)
+		}
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		admin := includes(withModel(createSubscription("admin", []string{"admins"}, nil, 0, defaultTestTokenRateLimit, "", ""), "large"), "*")
+		sel := subscription.NewSelector(log, &fakeLister{subscriptions: []*unstructured.Unstructured{free, premium, enterprise, admin}})
+		accessible, err := sel.GetAllAccessible([]string{"admins"}, "alice")
+		if err != nil {
+			t.Fatalf("GetAllAccessible: %v", err)
+		}
+		if len(accessible) != 4 {
+			t.Errorf("accessible = %d subscriptions, want all 4", len(accessible))
+		}
+	})
+}
+
+func TestSelectForHost(t *testing.T) {
+	premium := createSubscription("premium", []string{"premium-users"}, nil, 10, defaultTestTokenRateLimit, "", "")
+	_ = unstructured.SetNestedMap(premium.Object, map[string]any{
+		"name":      "premium",
+		"hostnames": []any{"premium.models.example.com", "*.premium.example.com"},
+	}, "spec", "listener")
+	free := createSubscription("free", []string{"system:authenticated"}, nil, 0, defaultTestTokenRateLimit, "", "")
+	sel := subscription.NewSelector(logger.New(false), &fakeLister{subscriptions: []*unstructured.Unstructured{premium, free}})
+	sel.SetAllowMultiple(true)
+
+	tests := []struct {
+		name      string
+		groups    []string
+		requested string
+		host      string
+		want      string
+		wantErr   bool
+	}{
+		{name: "no host check", groups: []string{"premium-users", "system:authenticated"}, want: "premium"},
+		{name: "tier hostname", groups: []string{"premium-users", "system:authenticated"}, host: "premium.models.example.com", want: "premium"},
+		{name: "tier hostname with port", groups: []string{"premium-users"}, host: "Premium.Models.Example.com:443", want: "premium"},
+		{name: "wildcard hostname", groups: []string{"premium-users"}, host: "eu.premium.example.com", want: "premium"},
+		{name: "wildcard matches one label", groups: []string{"premium-users"}, host: "a.eu.premium.example.com", wantErr: true},
+		{name: "shared hostname skips tier subscriptions", groups: []string{"premium-users", "system:authenticated"}, host: "models.example.com", want: "free"},
+		{name: "tier subscription on shared hostname", groups: []string{"premium-users"}, host: "models.example.com", wantErr: true},
+		{name: "explicit tier subscription on shared hostname", groups: []string{"premium-users", "system:authenticated"}, requested: "premium", host: "models.example.com", wantErr: true},
+		{name: "other subscription on tier hostname", groups: []string{"system:authenticated"}, host: "premium.models.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sel.SelectForHost(tt.groups, "alice", tt.requested, "", tt.host)
+			if tt.wantErr {
+				var mismatch *subscription.HostMismatchError
+				if !errors.As(err, &mismatch) {
+					t.Fatalf("SelectForHost = %v, %v; want HostMismatchError", got, err)
+				}
+				if code := subscription.ErrorCode(err); code != "host_mismatch" {
+					t.Errorf("ErrorCode = %q, want host_mismatch", code)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectForHost: %v", err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("SelectForHost = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
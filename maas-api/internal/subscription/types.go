@@ -1,11 +1,14 @@
 package subscription
 
+import "time"
+
 // SelectRequest contains the user information for subscription selection.
 type SelectRequest struct {
 	Groups                []string `json:"groups"`                                // User's group memberships (optional if username provided)
 	Username              string   `binding:"required"           json:"username"` // User's username
 	RequestedSubscription string   `json:"requestedSubscription"`                 // Optional explicit subscription name
 	RequestedModel        string   `json:"requestedModel"`                        // Optional model reference (format: namespace/name) to validate subscription includes this model
+	Host                  string   `json:"host"`                                  // Optional request host, checked against the subscriptions' gateway listeners
 }
 
 // ModelRef represents a model reference in a subscription.
@@ -18,19 +21,35 @@ type ModelRef struct {
 // This always returns HTTP 200 with either success or error fields populated.
 type SelectResponse struct {
 	// Success fields (populated when selection succeeds)
-	Name           string            `json:"name,omitempty"`           // Subscription name
-	Namespace      string            `json:"namespace,omitempty"`      // Subscription namespace
-	DisplayName    string            `json:"displayName,omitempty"`    // Human-friendly display name for UI
-	Description    string            `json:"description,omitempty"`    // Subscription description
-	Priority       int32             `json:"priority,omitempty"`       // Subscription priority
-	ModelRefs      []ModelRefInfo    `json:"modelRefs,omitempty"`      // Model references with rate limits
-	OrganizationID string            `json:"organizationId,omitempty"` // Organization ID for billing
-	CostCenter     string            `json:"costCenter,omitempty"`     // Cost center for attribution
-	Labels         map[string]string `json:"labels,omitempty"`         // Additional tracking labels
+	Name            string            `json:"name,omitempty"`            // Subscription name
+	Namespace       string            `json:"namespace,omitempty"`       // Subscription namespace
+	DisplayName     string            `json:"displayName,omitempty"`     // Human-friendly display name for UI
+	Description     string            `json:"description,omitempty"`     // Subscription description
+	Priority        int32             `json:"priority,omitempty"`        // Subscription priority
+	ModelRefs       []ModelRefInfo    `json:"modelRefs,omitempty"`       // Model references with rate limits
+	OrganizationID  string            `json:"organizationId,omitempty"`  // Organization ID for billing
+	CostCenter      string            `json:"costCenter,omitempty"`      // Cost center for attribution
+	Labels          map[string]string `json:"labels,omitempty"`          // Additional tracking labels
+	QuotaWarning    string            `json:"quotaWarning,omitempty"`    // Soft quota warning, set when usage is over the warning threshold
+	Model           string            `json:"model,omitempty"`           // Requested model (namespace/name), for the X-MaaS-Model response header
+	RemainingTokens *int64            `json:"remainingTokens,omitempty"` // Tokens the caller has left before being rate limited, when quota headers are enabled
+	Candidates      []string          `json:"candidates,omitempty"`      // All matching subscriptions (namespace/name) when auto-selected from several
+	SelectedBy      string            `json:"selectedBy,omitempty"`      // How the subscription was chosen: header, single, priority or cheapest
+	GrantedBy       string            `json:"grantedBy,omitempty"`       // Subscription (namespace/name) the caller owns that includes this one, when access is inherited
+	RateLimits      *RateLimits       `json:"rateLimits,omitempty"`      // Limits for the requested model, passed on to Limitador
+	TokenBudget     *TokenBudget      `json:"tokenBudget,omitempty"`     // Per-user token budget for the requested model, enforced by maas-api
+	ExpiresAt       time.Time         `json:"expiresAt,omitzero"`        // When the subscription stops granting access, if ever
+	Sandbox         bool              `json:"sandbox,omitempty"`         // Requests are answered by the sandbox mock backend
+	Attachments     *AttachmentPolicy `json:"attachments,omitempty"`     // Limits on the attachments of multimodal requests
+	Endpoints       []string          `json:"endpoints,omitempty"`       // API suffixes requests under the subscription may use; empty allows all
+	GraceEndsAt     time.Time         `json:"graceEndsAt,omitzero"`      // Set when the caller was removed from the subscription's owners and only reaches it until then
+	Warning         string            `json:"warning,omitempty"`         // Warning for the caller, set during a downgrade grace period
+	Cluster         string            `json:"cluster,omitempty"`         // Federated cluster hosting the requested model
 
 	// Error fields (populated when selection fails)
-	Error   string `json:"error,omitempty"`   // Error code (e.g., "bad_request", "not_found", "access_denied", "multiple_subscriptions")
-	Message string `json:"message,omitempty"` // Human-readable error message
+	Error      string `json:"error,omitempty"`      // Error code (e.g., "bad_request", "not_found", "access_denied", "multiple_subscriptions")
+	Message    string `json:"message,omitempty"`    // Human-readable error message
+	RetryAfter int64  `json:"retryAfter,omitempty"` // Seconds to wait before retrying, for model_maintenance
 }
 
 // SubscriptionInfo represents a subscription in list responses.
@@ -44,14 +63,46 @@ type SubscriptionInfo struct {
 	OrganizationID          string            `json:"organization_id,omitempty"`
 	CostCenter              string            `json:"cost_center,omitempty"`
 	Labels                  map[string]string `json:"labels,omitempty"`
+	ExpiresAt               time.Time         `json:"expires_at,omitzero"`
+}
+
+// SubscriptionList is the v2 list response envelope.
+type SubscriptionList struct {
+	Object string             `json:"object"`
+	Data   []SubscriptionInfo `json:"data"`
 }
 
 // ModelRefInfo represents a model reference with its rate limits.
 type ModelRefInfo struct {
-	Name            string           `json:"name"`
-	Namespace       string           `json:"namespace,omitempty"`
-	TokenRateLimits []TokenRateLimit `json:"token_rate_limits,omitempty"`
-	BillingRate     *BillingRate     `json:"billing_rate,omitempty"`
+	Name              string             `json:"name"`
+	Namespace         string             `json:"namespace,omitempty"`
+	TokenRateLimits   []TokenRateLimit   `json:"token_rate_limits,omitempty"`
+	RequestRateLimits []RequestRateLimit `json:"request_rate_limits,omitempty"`
+	BillingRate       *BillingRate       `json:"billing_rate,omitempty"`
+	TokenBudget       *TokenBudget       `json:"token_budget,omitempty"`
+	Override          string             `json:"override,omitempty"` // MaaSRateLimitOverride that replaced the tier's limits, if any
+	// MaxConcurrentRequests caps the requests each user may have in progress on the model, or 0.
+	MaxConcurrentRequests int64 `json:"max_concurrent_requests,omitempty"`
+}
+
+// LimitInfo is the rate limits a caller gets on a model under one of their subscriptions.
+type LimitInfo struct {
+	Subscription      string             `json:"subscription"` // namespace/name
+	Model             string             `json:"model"`        // namespace/name
+	TokenRateLimits   []TokenRateLimit   `json:"token_rate_limits,omitempty"`
+	RequestRateLimits []RequestRateLimit `json:"request_rate_limits,omitempty"`
+	Source            string             `json:"source"`             // tier, or override when a MaaSRateLimitOverride applies
+	Override          string             `json:"override,omitempty"` // name of the MaaSRateLimitOverride
+	// MaxConcurrentRequests caps the caller's requests in progress on the model, or 0.
+	MaxConcurrentRequests int64 `json:"max_concurrent_requests,omitempty"`
+	// InFlight is the caller's requests in progress on the model, when a concurrency cap is enforced.
+	InFlight *int64 `json:"in_flight,omitempty"`
+}
+
+// LimitList is the GET /v1/limits response envelope.
+type LimitList struct {
+	Object string      `json:"object"`
+	Data   []LimitInfo `json:"data"`
 }
 
 // TokenRateLimit defines a token rate limit.
@@ -60,6 +111,35 @@ type TokenRateLimit struct {
 	Window string `json:"window"`
 }
 
+// RequestRateLimit defines a request rate limit.
+type RequestRateLimit struct {
+	Limit  int64  `json:"limit"`
+	Window string `json:"window"`
+}
+
+// TokenBudget is the number of tokens each user may consume of a model per period.
+type TokenBudget struct {
+	Limit  int64  `json:"limit"`
+	Period string `json:"period"`
+}
+
+// AttachmentPolicy limits the image, audio and file attachments of a request. Unset limits do not
+// apply; an empty AllowedTypes allows any type.
+type AttachmentPolicy struct {
+	MaxCount          *int64   `json:"maxCount,omitempty"`
+	MaxSize           *int64   `json:"maxSize,omitempty"`           // Bytes of an inline attachment, decoded
+	MaxImageDimension *int64   `json:"maxImageDimension,omitempty"` // Pixels of the longest side of an inline image
+	AllowedTypes      []string `json:"allowedTypes,omitempty"`      // MIME types; "image/*" allows every image type
+}
+
+// RateLimits are the subscription's limits for the requested model, normalized to one minute.
+// Authorino exports them as identity metadata so Limitador can key descriptors on them.
+type RateLimits struct {
+	Model             string `json:"model"`                       // Model ref (namespace/name) the limits come from
+	RequestsPerMinute int64  `json:"requestsPerMinute,omitempty"` // Most restrictive request limit per minute, 0 when unlimited
+	TokensPerMinute   int64  `json:"tokensPerMinute,omitempty"`   // Most restrictive token limit per minute, 0 when unlimited
+}
+
 // BillingRate defines billing information.
 type BillingRate struct {
 	PerToken string `json:"per_token"`
@@ -0,0 +1,63 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// sweepInterval is how often idle buckets are dropped.
+const sweepInterval = time.Minute
+
+// Local is an in-process token bucket per caller.
+type Local struct {
+	limit rate.Limit
+	burst int
+	// refill is how long an idle bucket takes to fill up again; after that it can be dropped
+	// without changing any decision.
+	refill time.Duration
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLocal creates a Local limiter allowing perSecond requests per second with bursts of burst.
+func NewLocal(perSecond, burst int) *Local {
+	return &Local{
+		limit:     rate.Limit(perSecond),
+		burst:     burst,
+		refill:    time.Duration(float64(burst) / float64(perSecond) * float64(time.Second)),
+		buckets:   map[string]*bucket{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow implements Limiter.
+func (l *Local) Allow(_ context.Context, caller string) (bool, error) {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		for key, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= l.refill {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.buckets[caller]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[caller] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, 1), nil
+}
@@ -0,0 +1,68 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/redis"
+)
+
+const redisKeyPrefix = "maas:authz:"
+
+// tokenBucketScript refills the caller's bucket from Redis' own clock, so replicas with skewed
+// clocks share one view, takes a token if there is one and returns 1 when it did.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local b = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(b[1]) or burst
+local ts = tonumber(b[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate))
+return allowed
+`
+
+// Redis is a token bucket per caller kept in Redis, so every maas-api replica draws from the
+// same buckets.
+type Redis struct {
+	client    *redis.Client
+	perSecond string
+	burst     string
+}
+
+// NewRedis creates a Redis limiter for a redis:// or rediss:// URL, e.g.
+// redis://:password@redis:6379/0, allowing perSecond requests per second with bursts of burst.
+// Connections are opened lazily.
+func NewRedis(rawURL string, perSecond, burst int) (*Redis, error) {
+	client, err := redis.NewClient(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Redis{
+		client:    client,
+		perSecond: strconv.Itoa(perSecond),
+		burst:     strconv.Itoa(burst),
+	}, nil
+}
+
+// Allow implements Limiter.
+func (r *Redis) Allow(ctx context.Context, caller string) (bool, error) {
+	reply, err := r.client.Do(ctx, "EVAL", tokenBucketScript, "1", redisKeyPrefix+caller, r.perSecond, r.burst)
+	if err != nil {
+		return false, err
+	}
+	allowed, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("redis: unexpected reply %v to token bucket script", reply)
+	}
+	return allowed == 1, nil
+}
@@ -0,0 +1,196 @@
+// Package throttle protects the authorization endpoints from their own callers: per-caller
+// request rate limits and in-flight caps keep a gateway retry storm or a prober from exhausting
+// maas-api and taking down authorization for everyone else.
+package throttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/reason"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/redis"
+)
+
+// Reasons a request is throttled, reported as the error type and in maas_authz_throttled_total.
+const (
+	ReasonRateLimited     = reason.RateLimited
+	ReasonTooManyInFlight = reason.TooManyInFlight
+)
+
+var throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "maas_authz_throttled_total",
+	Help: "Authorization requests rejected by the abuse throttle, by endpoint and reason (rate_limited, too_many_in_flight).",
+}, []string{"endpoint", "reason"})
+
+func init() {
+	prometheus.MustRegister(throttledTotal)
+}
+
+// Limiter decides whether a caller may make one more request now.
+type Limiter interface {
+	Allow(ctx context.Context, caller string) (bool, error)
+}
+
+// Options configures a Throttler. Zero values disable the corresponding check.
+type Options struct {
+	// RatePerSecond is the sustained number of requests per second each caller may make.
+	RatePerSecond int
+	// Burst is how many requests a caller may make at once. Defaults to RatePerSecond.
+	Burst int
+	// MaxInFlightPerCaller caps the requests a single caller may have in progress.
+	MaxInFlightPerCaller int
+	// MaxInFlight caps the requests in progress across all callers.
+	MaxInFlight int
+	// RedisURL (redis:// or rediss://) shares the rate limit buckets between replicas. Without
+	// it each replica limits callers on its own.
+	RedisURL string
+}
+
+// Enabled reports whether any check is configured.
+func (o Options) Enabled() bool {
+	return o.RatePerSecond > 0 || o.MaxInFlightPerCaller > 0 || o.MaxInFlight > 0
+}
+
+// Validate checks the options are consistent.
+func (o Options) Validate() error {
+	if o.RatePerSecond < 0 || o.Burst < 0 || o.MaxInFlightPerCaller < 0 || o.MaxInFlight < 0 {
+		return errors.New("authorization throttle limits must not be negative")
+	}
+	if o.Burst > 0 && o.RatePerSecond == 0 {
+		return errors.New("AUTHZ_RATE_BURST requires AUTHZ_RATE_LIMIT")
+	}
+	if o.RedisURL != "" {
+		if o.RatePerSecond == 0 {
+			return errors.New("AUTHZ_RATE_LIMIT_REDIS_URL requires AUTHZ_RATE_LIMIT")
+		}
+		if err := redis.ValidateURL(o.RedisURL); err != nil {
+			return fmt.Errorf("AUTHZ_RATE_LIMIT_REDIS_URL: %w", err)
+		}
+	}
+	return nil
+}
+
+// Throttler admits or rejects requests by caller.
+type Throttler struct {
+	limiter      Limiter
+	maxPerCaller int
+	maxTotal     int
+	logger       *logger.Logger
+
+	mu       sync.Mutex
+	inFlight map[string]int
+	total    int
+}
+
+// New creates the Throttler configured by opts, with an in-process or Redis-backed rate limiter.
+// It returns nil when opts enables nothing; a nil Throttler admits every request.
+func New(log *logger.Logger, opts Options) (*Throttler, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if !opts.Enabled() {
+		return nil, nil
+	}
+	burst := opts.Burst
+	if burst == 0 {
+		burst = opts.RatePerSecond
+	}
+	var limiter Limiter
+	switch {
+	case opts.RedisURL != "":
+		redis, err := NewRedis(opts.RedisURL, opts.RatePerSecond, burst)
+		if err != nil {
+			return nil, err
+		}
+		limiter = redis
+	case opts.RatePerSecond > 0:
+		limiter = NewLocal(opts.RatePerSecond, burst)
+	}
+	return NewThrottler(log, limiter, opts.MaxInFlightPerCaller, opts.MaxInFlight), nil
+}
+
+// NewThrottler creates a Throttler from a rate limiter (nil for none) and in-flight caps (0 for none).
+func NewThrottler(log *logger.Logger, limiter Limiter, maxInFlightPerCaller, maxInFlight int) *Throttler {
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Throttler{
+		limiter:      limiter,
+		maxPerCaller: maxInFlightPerCaller,
+		maxTotal:     maxInFlight,
+		logger:       log,
+		inFlight:     map[string]int{},
+	}
+}
+
+// Admit checks the caller's in-flight count and rate. When the request is admitted, reason is
+// empty and release must be called once it completes. Rate limiter errors admit the request:
+// an unreachable Redis must not take authorization down with it.
+func (t *Throttler) Admit(ctx context.Context, caller string) (release func(), reason string) {
+	if t == nil {
+		return func() {}, ""
+	}
+	t.mu.Lock()
+	if (t.maxTotal > 0 && t.total >= t.maxTotal) || (t.maxPerCaller > 0 && t.inFlight[caller] >= t.maxPerCaller) {
+		t.mu.Unlock()
+		return nil, ReasonTooManyInFlight
+	}
+	t.inFlight[caller]++
+	t.total++
+	t.mu.Unlock()
+
+	var once sync.Once
+	release = func() {
+		once.Do(func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.total--
+			if t.inFlight[caller]--; t.inFlight[caller] <= 0 {
+				delete(t.inFlight, caller)
+			}
+		})
+	}
+
+	if t.limiter != nil {
+		allowed, err := t.limiter.Allow(ctx, caller)
+		if err != nil {
+			t.logger.Warn("Authorization rate limiter unavailable, admitting request", "error", err)
+		} else if !allowed {
+			release()
+			return nil, ReasonRateLimited
+		}
+	}
+	return release, ""
+}
+
+// Rejected records a throttled request in maas_authz_throttled_total.
+func Rejected(endpoint, reason string) {
+	throttledTotal.WithLabelValues(endpoint, reason).Inc()
+}
+
+// Middleware throttles requests by client IP (see clientip.Resolver.Middleware). Rejected requests
+// get 429 Too Many Requests with Retry-After.
+func (t *Throttler) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		release, reason := t.Admit(c.Request.Context(), c.ClientIP())
+		if reason != "" {
+			Rejected(c.FullPath(), reason)
+			t.logger.Debug("Throttled authorization request", "path", c.FullPath(), "client", c.ClientIP(), "reason", reason)
+			c.Header("Retry-After", "1")
+			apierror.New(apierror.FromReason(reason), "too many authorization requests from this client").
+				WithType(reason).
+				Abort(c, http.StatusTooManyRequests)
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
@@ -0,0 +1,204 @@
+package throttle_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/throttle"
+)
+
+func TestLocalLimitsEachCallerSeparately(t *testing.T) {
+	l := throttle.NewLocal(1, 2)
+	ctx := context.Background()
+
+	for range 2 {
+		allowed, err := l.Allow(ctx, "10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, allowed, "burst should be admitted")
+	}
+	allowed, _ := l.Allow(ctx, "10.0.0.1")
+	assert.False(t, allowed, "caller over its burst should be limited")
+
+	allowed, _ = l.Allow(ctx, "10.0.0.2")
+	assert.True(t, allowed, "other callers keep their own bucket")
+}
+
+func TestAdmitInFlightCaps(t *testing.T) {
+	th := throttle.NewThrottler(logger.Development(), nil, 1, 2)
+	ctx := context.Background()
+
+	releaseA, reason := th.Admit(ctx, "a")
+	require.Empty(t, reason)
+	_, reason = th.Admit(ctx, "a")
+	assert.Equal(t, throttle.ReasonTooManyInFlight, reason, "per-caller cap")
+
+	releaseB, reason := th.Admit(ctx, "b")
+	require.Empty(t, reason)
+	_, reason = th.Admit(ctx, "c")
+	assert.Equal(t, throttle.ReasonTooManyInFlight, reason, "global cap")
+
+	releaseA()
+	releaseA() // releasing twice must not free a second slot
+	_, reason = th.Admit(ctx, "c")
+	assert.Empty(t, reason)
+	_, reason = th.Admit(ctx, "a")
+	assert.Equal(t, throttle.ReasonTooManyInFlight, reason)
+	releaseB()
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestAdmitFailsOpenOnLimiterError(t *testing.T) {
+	th := throttle.NewThrottler(logger.Development(), failingLimiter{}, 0, 0)
+	release, reason := th.Admit(context.Background(), "a")
+	assert.Empty(t, reason)
+	release()
+}
+
+func TestMiddlewareRejectsWith429(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	th := throttle.NewThrottler(logger.Development(), throttle.NewLocal(1, 1), 0, 0)
+	router := gin.New()
+	router.POST("/internal/v1/subscriptions/select", th.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	call := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/internal/v1/subscriptions/select", nil)
+		req.RemoteAddr = "10.0.0.1:4711"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, call().Code)
+	w := call()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.JSONEq(t, `{"error":{"code":"RATE_LIMITED","message":"too many authorization requests from this client","type":"rate_limited"}}`, w.Body.String())
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    throttle.Options
+		wantErr string
+	}{
+		{name: "disabled", opts: throttle.Options{}},
+		{name: "local rate", opts: throttle.Options{RatePerSecond: 50, Burst: 100}},
+		{name: "redis", opts: throttle.Options{RatePerSecond: 50, RedisURL: "rediss://:secret@redis:6380/1"}},
+		{name: "negative", opts: throttle.Options{MaxInFlight: -1}, wantErr: "must not be negative"},
+		{name: "burst without rate", opts: throttle.Options{Burst: 10}, wantErr: "AUTHZ_RATE_BURST requires AUTHZ_RATE_LIMIT"},
+		{name: "bad redis scheme", opts: throttle.Options{RatePerSecond: 1, RedisURL: "http://redis"}, wantErr: "unsupported scheme"},
+		{name: "bad redis database", opts: throttle.Options{RatePerSecond: 1, RedisURL: "redis://redis/x"}, wantErr: "invalid database"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// fakeRedis answers AUTH with OK and EVAL from replies, recording the commands it receives.
+func fakeRedis(t *testing.T, replies ...string) (addr string, commands chan []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	commands = make(chan []string, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			cmd, err := readCommand(r)
+			if err != nil {
+				return
+			}
+			commands <- cmd
+			reply := "+OK\r\n"
+			if cmd[0] == "EVAL" {
+				reply, replies = replies[0], replies[1:]
+			}
+			if _, err := io.WriteString(conn, reply); err != nil {
+				return
+			}
+		}
+	}()
+	return ln.Addr().String(), commands
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func TestRedisLimiter(t *testing.T) {
+	addr, commands := fakeRedis(t, ":1\r\n", ":0\r\n", "-NOSCRIPT boom\r\n")
+	l, err := throttle.NewRedis("redis://:secret@"+addr, 5, 10)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	allowed, err := l.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, []string{"AUTH", "secret"}, <-commands)
+	eval := <-commands
+	assert.Equal(t, "EVAL", eval[0])
+	assert.Equal(t, []string{"1", "maas:authz:10.0.0.1", "5", "10"}, eval[2:])
+
+	allowed, err = l.Allow(ctx, "10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	_, err = l.Allow(ctx, "10.0.0.1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NOSCRIPT boom")
+}
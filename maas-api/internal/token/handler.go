@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/constant"
 	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
 )
@@ -65,12 +66,9 @@ func (h *Handler) ExtractUserInfo() gin.HandlerFunc {
 			h.logger.Error("Missing or empty username header",
 				"header", constant.HeaderUsername,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":         "Exception thrown while generating token",
-				"exceptionCode": "AUTH_FAILURE",
-				"refId":         "001",
-			})
-			c.Abort()
+			apierror.New(apierror.InternalError, "Exception thrown while generating token").
+				WithDetails(map[string]any{"exceptionCode": "AUTH_FAILURE", "refId": "001"}).
+				Abort(c, http.StatusInternalServerError)
 			return
 		}
 
@@ -79,12 +77,9 @@ func (h *Handler) ExtractUserInfo() gin.HandlerFunc {
 				"header", constant.HeaderGroup,
 				"username", username,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":         "Exception thrown while generating token",
-				"exceptionCode": "AUTH_FAILURE",
-				"refId":         "002",
-			})
-			c.Abort()
+			apierror.New(apierror.InternalError, "Exception thrown while generating token").
+				WithDetails(map[string]any{"exceptionCode": "AUTH_FAILURE", "refId": "002"}).
+				Abort(c, http.StatusInternalServerError)
 			return
 		}
 
@@ -97,12 +92,9 @@ func (h *Handler) ExtractUserInfo() gin.HandlerFunc {
 				"header_value", groupHeader,
 				"error", err,
 			)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error":         "Exception thrown while generating token",
-				"exceptionCode": "AUTH_FAILURE",
-				"refId":         "003",
-			})
-			c.Abort()
+			apierror.New(apierror.InternalError, "Exception thrown while generating token").
+				WithDetails(map[string]any{"exceptionCode": "AUTH_FAILURE", "refId": "003"}).
+				Abort(c, http.StatusInternalServerError)
 			return
 		}
 
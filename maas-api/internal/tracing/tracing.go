@@ -0,0 +1,269 @@
+// Package tracing records OpenTelemetry spans for maas-api and carries the request ID and the
+// caller's trace context through the HTTP API, the ext_authz evaluator and outgoing calls, so a
+// slow or denied inference request can be followed from the gateway into the authorization
+// decision.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+)
+
+const (
+	// RequestIDHeader carries the request ID. The gateway sets it on inference requests; maas-api
+	// generates one for calls without it and echoes it on every response.
+	RequestIDHeader = "X-Request-Id"
+
+	// Span attributes recorded by maas-api.
+	ModelAttribute     = "maas.model"
+	RequestIDAttribute = "maas.request_id"
+	ReasonAttribute    = "maas.reason"
+
+	instrumentationName = "github.com/opendatahub-io/models-as-a-service/maas-api"
+	maxRequestIDLength  = 128
+)
+
+// PolicyResolver returns the tracing policy of a model ("namespace/name"), or nil for the defaults.
+type PolicyResolver func(model string) *models.Tracing
+
+// policy is the resolver installed by Setup.
+var policy PolicyResolver = func(string) *models.Tracing { return nil }
+
+// Setup installs the W3C trace context propagator and the model tracing policies and, when
+// endpoint is set, a tracer provider exporting spans to it over OTLP/HTTP. New traces are
+// sampled at samplingPercentage, spans of a model with a sampling percentage of its own at that
+// one. The returned function flushes and stops the exporter.
+func Setup(ctx context.Context, endpoint string, samplingPercentage int, version string, resolve PolicyResolver) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if resolve != nil {
+		policy = resolve
+	}
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "maas-api"),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(Sampler(samplingPercentage)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Sampler samples new traces at samplingPercentage, follows the caller's decision for requests
+// that arrive with a trace context, and samples spans started for a model (with ModelAttribute)
+// at the model's own sampling percentage if it sets one.
+func Sampler(samplingPercentage int) sdktrace.Sampler {
+	return modelSampler{fallback: sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(samplingPercentage) / 100))}
+}
+
+// modelSampler samples spans started with ModelAttribute at the model's sampling percentage,
+// and every other span with fallback.
+type modelSampler struct {
+	fallback sdktrace.Sampler
+}
+
+func (s modelSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, attr := range p.Attributes {
+		if attr.Key != ModelAttribute {
+			continue
+		}
+		if t := policy(attr.Value.AsString()); t != nil && t.SamplingRatio >= 0 {
+			result := sdktrace.SamplingResult{
+				Decision:   sdktrace.Drop,
+				Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+			if t.Sampled(p.TraceID, false) {
+				result.Decision = sdktrace.RecordAndSample
+			}
+			return result
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+func (s modelSampler) Description() string {
+	return "MaaSModelSampler{" + s.fallback.Description() + "}"
+}
+
+// Start starts a span named name as a child of the span in ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// ModelSpan is a span for a request to one model. It records only the attributes the model's
+// tracing policy keeps.
+type ModelSpan struct {
+	trace.Span
+
+	policy *models.Tracing
+}
+
+// StartModelSpan starts a span for a request to model ("namespace/name", or "" when unknown)
+// under the model's tracing policy: sampled at the model's percentage, and in a new trace linked
+// to the caller's when the model does not propagate trace context.
+func StartModelSpan(ctx context.Context, name, model string, opts ...trace.SpanStartOption) (context.Context, *ModelSpan) {
+	var t *models.Tracing
+	if model != "" {
+		t = policy(model)
+		opts = append(opts, trace.WithAttributes(attribute.String(ModelAttribute, model)))
+	}
+	if t != nil && !t.Propagate {
+		opts = append(opts, trace.WithNewRoot(), trace.WithLinks(trace.LinkFromContext(ctx)))
+	}
+	ctx, span := Start(ctx, name, opts...)
+	return ctx, &ModelSpan{Span: span, policy: t}
+}
+
+// SetAttributes records the attributes the model's policy keeps.
+func (s *ModelSpan) SetAttributes(kv ...attribute.KeyValue) {
+	kept := kv[:0:0]
+	for _, attr := range kv {
+		if s.policy.KeepAttribute(string(attr.Key)) {
+			kept = append(kept, attr)
+		}
+	}
+	s.Span.SetAttributes(kept...)
+}
+
+// Extract returns ctx carrying the trace context in headers, as the gateway sends them to
+// ext_authz: lower-case names with one value each.
+func Extract(ctx context.Context, headers map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// Fail marks the span failed with err.
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID reports whether a caller-supplied request ID may be reused. IDs are echoed in
+// headers and logs, so only short printable ones are kept.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	return !strings.ContainsFunc(id, func(r rune) bool { return r < 0x21 || r > 0x7e })
+}
+
+// Middleware assigns each request its ID, reusing a valid X-Request-Id, and echoes it on the
+// response. It continues the caller's trace in a server span for the route and returns the span's
+// trace context in the response headers.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewString()
+			c.Request.Header.Set(RequestIDHeader, id)
+		}
+		c.Header(RequestIDHeader, id)
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String(RequestIDAttribute, id),
+			))
+		defer span.End()
+		propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+		c.Request = c.Request.WithContext(WithRequestID(ctx, id))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// Transport wraps base so outgoing requests carry the trace context and request ID of their
+// context, in a client span.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		))
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if id := RequestID(ctx); id != "" && req.Header.Get(RequestIDHeader) == "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		Fail(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,122 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/models"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/tracing"
+)
+
+const (
+	callerTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent   = "00-" + callerTraceID + "-00f067aa0ba902b7-01"
+)
+
+// record installs the tracing policies and a tracer provider recording every span it samples.
+func record(t *testing.T, policies map[string]*models.Tracing) *tracetest.SpanRecorder {
+	t.Helper()
+	_, err := tracing.Setup(context.Background(), "", 100, "test", func(model string) *models.Tracing { return policies[model] })
+	require.NoError(t, err)
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSampler(tracing.Sampler(100))))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := record(t, nil)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-1", r.Header.Get(tracing.RequestIDHeader), "the request ID is forwarded")
+		assert.Contains(t, r.Header.Get("traceparent"), callerTraceID, "the trace continues to the backend")
+	}))
+	defer backend.Close()
+	client := &http.Client{Transport: tracing.Transport(nil)}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tracing.Middleware())
+	router.GET("/v1/models/:name", func(c *gin.Context) {
+		assert.NotEmpty(t, tracing.RequestID(c.Request.Context()))
+		if c.GetHeader(tracing.RequestIDHeader) == "req-1" {
+			req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, backend.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models/granite", nil)
+	req.Header.Set(tracing.RequestIDHeader, "req-1")
+	req.Header.Set("traceparent", traceparent)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-1", w.Header().Get(tracing.RequestIDHeader))
+	assert.Contains(t, w.Header().Get("traceparent"), callerTraceID)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	clientSpan, server := spans[0], spans[1]
+	assert.Equal(t, "GET /v1/models/:name", server.Name())
+	assert.Equal(t, callerTraceID, server.SpanContext().TraceID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), clientSpan.Parent().SpanID())
+	assert.Contains(t, server.Attributes(), attribute.String(tracing.RequestIDAttribute, "req-1"))
+
+	// Missing or unusable request IDs are replaced.
+	for _, id := range []string{"", "has spaces", strings.Repeat("x", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/models/granite", nil)
+		req.Header.Set(tracing.RequestIDHeader, id)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		got := w.Header().Get(tracing.RequestIDHeader)
+		assert.NotEmpty(t, got)
+		assert.NotEqual(t, id, got)
+	}
+}
+
+func TestStartModelSpan(t *testing.T) {
+	recorder := record(t, map[string]*models.Tracing{
+		"llm/quiet":   {SamplingRatio: 0, Propagate: true},
+		"llm/private": {SamplingRatio: -1, Propagate: false, StripSensitiveAttributes: true},
+	})
+	caller := tracing.Extract(context.Background(), map[string]string{"traceparent": traceparent})
+
+	_, span := tracing.StartModelSpan(caller, "select", "llm/quiet")
+	assert.False(t, span.IsRecording(), "a model sampled at 0% records no spans")
+	span.End()
+
+	_, span = tracing.StartModelSpan(caller, "select", "llm/private")
+	span.SetAttributes(attribute.String("maas.user", "alice"), attribute.String(tracing.ReasonAttribute, "unauthorized"))
+	span.End()
+
+	_, span = tracing.StartModelSpan(caller, "select", "")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	private, unknown := spans[0], spans[1]
+	assert.NotEqual(t, callerTraceID, private.SpanContext().TraceID().String(), "a model that does not propagate starts a new trace")
+	require.Len(t, private.Links(), 1)
+	assert.Equal(t, callerTraceID, private.Links()[0].SpanContext.TraceID().String(), "linked to the caller's trace")
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(tracing.ModelAttribute, "llm/private"),
+		attribute.String(tracing.ReasonAttribute, "unauthorized"),
+	}, private.Attributes(), "the user is stripped")
+	assert.Equal(t, callerTraceID, unknown.SpanContext().TraceID().String())
+	assert.Equal(t, trace.SpanKindInternal, unknown.SpanKind())
+}
@@ -0,0 +1,156 @@
+// Package upstream serves the credentials the gateway sends to external model providers, read from
+// the Secret of an ExternalModel's spec.auth on request so they never appear in a generated
+// HTTPRoute or AuthPolicy.
+package upstream
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/apierror"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+var externalModelGVR = schema.GroupVersionResource{Group: "maas.opendatahub.io", Version: "v1alpha1", Resource: "externalmodels"}
+
+// Defaults of spec.auth, as in maas-controller.
+const (
+	defaultHeader = "x-api-key"
+	defaultKey    = "api-key"
+)
+
+// errNoCredential is returned for a missing ExternalModel or one without spec.auth.
+var errNoCredential = errors.New("no credential")
+
+// Credential is the header and value the gateway sends to an external model's provider.
+type Credential struct {
+	Header string `json:"header"`
+	Value  string `json:"value"`
+}
+
+// Handler serves the credentials of ExternalModels to callers bearing the upstream credentials
+// token, which Authorino presents for the generated AuthPolicies.
+type Handler struct {
+	logger    *logger.Logger
+	dynamic   dynamic.Interface
+	clientset kubernetes.Interface
+	token     string
+}
+
+// NewHandler creates a handler for GET /internal/v1/external-models/{namespace}/{name}/credential,
+// which callers bearing token may use.
+func NewHandler(log *logger.Logger, dynamicClient dynamic.Interface, clientset kubernetes.Interface, token string) *Handler {
+	if log == nil {
+		log = logger.Production()
+	}
+	if dynamicClient == nil || clientset == nil {
+		panic("clients cannot be nil")
+	}
+	if token == "" {
+		panic("token cannot be empty")
+	}
+	return &Handler{logger: log, dynamic: dynamicClient, clientset: clientset, token: token}
+}
+
+// GetCredential handles GET /internal/v1/external-models/{namespace}/{name}/credential. It answers
+// 404 when the ExternalModel does not exist or sets no spec.auth.
+func (h *Handler) GetCredential(c *gin.Context) {
+	bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(h.token)) != 1 {
+		apierror.Respond(c, http.StatusUnauthorized, apierror.Unauthenticated, "Authentication required")
+		return
+	}
+
+	namespace, name := c.Param("namespace"), c.Param("name")
+	credential, err := h.credential(c.Request.Context(), namespace, name)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, credential)
+	case errors.Is(err, errNoCredential):
+		apierror.Respond(c, http.StatusNotFound, apierror.NotFound, "ExternalModel "+namespace+"/"+name+" has no credential")
+	default:
+		h.logger.Error("Failed to read upstream credential", "namespace", namespace, "name", name, "error", err)
+		apierror.Respond(c, http.StatusServiceUnavailable, apierror.Unavailable, "Failed to read the credential")
+	}
+}
+
+// credential reads the ExternalModel and the Secret of its spec.auth, and builds the header the
+// provider expects for the auth type.
+func (h *Handler) credential(ctx context.Context, namespace, name string) (*Credential, error) {
+	extModel, err := h.dynamic.Resource(externalModelGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, errNoCredential
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ExternalModel %s/%s: %w", namespace, name, err)
+	}
+	if _, ok, _ := unstructured.NestedMap(extModel.Object, "spec", "auth"); !ok {
+		return nil, errNoCredential
+	}
+	authType, _, _ := unstructured.NestedString(extModel.Object, "spec", "auth", "type")
+	header, _, _ := unstructured.NestedString(extModel.Object, "spec", "auth", "header")
+	key, _, _ := unstructured.NestedString(extModel.Object, "spec", "auth", "key")
+	secretName, _, _ := unstructured.NestedString(extModel.Object, "spec", "auth", "secretRef", "name")
+	if secretName == "" {
+		secretName, _, _ = unstructured.NestedString(extModel.Object, "spec", "credentialRef", "name")
+	}
+
+	secret, err := h.clientset.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth Secret %s/%s: %w", namespace, secretName, err)
+	}
+	value := func(key string) (string, error) {
+		v := secret.Data[key]
+		if len(v) == 0 {
+			return "", fmt.Errorf("auth Secret %s/%s has no %q key", namespace, secretName, key)
+		}
+		return string(v), nil
+	}
+	if key == "" {
+		key = defaultKey
+	}
+
+	switch authType {
+	case "APIKey":
+		if header == "" {
+			header = defaultHeader
+		}
+		v, err := value(key)
+		if err != nil {
+			return nil, err
+		}
+		return &Credential{Header: header, Value: v}, nil
+	case "Bearer":
+		v, err := value(key)
+		if err != nil {
+			return nil, err
+		}
+		return &Credential{Header: "Authorization", Value: "Bearer " + v}, nil
+	case "Basic":
+		username, err := value(corev1.BasicAuthUsernameKey)
+		if err != nil {
+			return nil, err
+		}
+		password, err := value(corev1.BasicAuthPasswordKey)
+		if err != nil {
+			return nil, err
+		}
+		return &Credential{Header: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))}, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q on ExternalModel %s/%s", authType, namespace, name)
+	}
+}
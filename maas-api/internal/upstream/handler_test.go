@@ -0,0 +1,103 @@
+package upstream_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/upstream"
+)
+
+func externalModel(name string, auth map[string]any) *unstructured.Unstructured {
+	spec := map[string]any{"credentialRef": map[string]any{"name": "openai"}}
+	if auth != nil {
+		spec["auth"] = auth
+	}
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "maas.opendatahub.io/v1alpha1",
+		"kind":       "ExternalModel",
+		"metadata":   map[string]any{"name": name, "namespace": "llm"},
+		"spec":       spec,
+	}}
+}
+
+func secret(name string, data map[string]string) *corev1.Secret {
+	s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "llm"}, Data: map[string][]byte{}}
+	for k, v := range data {
+		s.Data[k] = []byte(v)
+	}
+	return s
+}
+
+func TestGetCredential(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(),
+		externalModel("api-key", map[string]any{"type": "APIKey", "header": "api-key"}),
+		externalModel("bearer", map[string]any{"type": "Bearer", "key": "token"}),
+		externalModel("basic", map[string]any{"type": "Basic", "secretRef": map[string]any{"name": "basic"}}),
+		externalModel("missing-key", map[string]any{"type": "Bearer", "key": "nope"}),
+		externalModel("no-auth", nil),
+	)
+	clientset := fake.NewClientset(
+		secret("openai", map[string]string{"api-key": "sk-1", "token": "tok"}),
+		secret("basic", map[string]string{"username": "maas", "password": "pw"}),
+	)
+	h := upstream.NewHandler(logger.Development(), dynamicClient, clientset, "upstream-token")
+	router := gin.New()
+	router.GET("/internal/v1/external-models/:namespace/:name/credential", h.GetCredential)
+
+	do := func(name, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/internal/v1/external-models/llm/"+name+"/credential", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name       string
+		model      string
+		token      string
+		wantStatus int
+		want       upstream.Credential
+	}{
+		{name: "api key", model: "api-key", token: "upstream-token", wantStatus: http.StatusOK,
+			want: upstream.Credential{Header: "api-key", Value: "sk-1"}},
+		{name: "bearer", model: "bearer", token: "upstream-token", wantStatus: http.StatusOK,
+			want: upstream.Credential{Header: "Authorization", Value: "Bearer tok"}},
+		{name: "basic", model: "basic", token: "upstream-token", wantStatus: http.StatusOK,
+			want: upstream.Credential{Header: "Authorization", Value: "Basic bWFhczpwdw=="}},
+		{name: "unauthenticated", model: "api-key", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", model: "api-key", token: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "no auth", model: "no-auth", token: "upstream-token", wantStatus: http.StatusNotFound},
+		{name: "unknown model", model: "absent", token: "upstream-token", wantStatus: http.StatusNotFound},
+		{name: "missing key", model: "missing-key", token: "upstream-token", wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(tt.model, tt.token)
+			require.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				assert.NotContains(t, w.Body.String(), "sk-1")
+				return
+			}
+			var got upstream.Credential
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Method is how Project extrapolates hourly usage.
+type Method string
+
+const (
+	// MethodLinear fits a least-squares line through the hourly totals and extends it, following
+	// usage that grows or shrinks steadily.
+	MethodLinear Method = "linear"
+	// MethodSeasonal repeats the average of each hour of the day, following usage with a daily
+	// cycle such as office hours.
+	MethodSeasonal Method = "seasonal"
+)
+
+// ParseMethod parses a forecast method. Empty means linear.
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(s); m {
+	case "":
+		return MethodLinear, nil
+	case MethodLinear, MethodSeasonal:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid method %q: must be linear or seasonal", s)
+}
+
+// Project returns the tokens expected from now until end. history holds the total tokens of
+// each whole hour before now's hour, oldest first. The hours ahead are extrapolated from it with
+// method, counting the part of now's hour that is left. Hours are never projected below zero.
+func Project(history []int64, now, end time.Time, method Method) int64 {
+	if len(history) == 0 || !now.Before(end) {
+		return 0
+	}
+	predict := linearTrend(history)
+	if method == MethodSeasonal {
+		predict = dailyCycle(history, now.Truncate(time.Hour))
+	}
+
+	var total float64
+	hour := now.Truncate(time.Hour)
+	for x := len(history); hour.Before(end); x++ {
+		from, to := hour, hour.Add(time.Hour)
+		if from.Before(now) {
+			from = now
+		}
+		if to.After(end) {
+			to = end
+		}
+		total += max(predict(x, hour), 0) * to.Sub(from).Hours()
+		hour = hour.Add(time.Hour)
+	}
+	return int64(math.Round(total))
+}
+
+// linearTrend fits y = a + b*x by least squares, x being the index in history.
+func linearTrend(history []int64) func(x int, hour time.Time) float64 {
+	n := float64(len(history))
+	var sumX, sumY float64
+	for x, y := range history {
+		sumX += float64(x)
+		sumY += float64(y)
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var cov, variance float64
+	for x, y := range history {
+		cov += (float64(x) - meanX) * (float64(y) - meanY)
+		variance += (float64(x) - meanX) * (float64(x) - meanX)
+	}
+	slope := 0.0
+	if variance > 0 {
+		slope = cov / variance
+	}
+	return func(x int, _ time.Time) float64 {
+		return meanY + slope*(float64(x)-meanX)
+	}
+}
+
+// dailyCycle averages history by hour of the day (UTC), historyEnd being the end of its last
+// hour. Hours of the day history does not cover fall back to the overall average.
+func dailyCycle(history []int64, historyEnd time.Time) func(x int, hour time.Time) float64 {
+	var sums, counts [24]float64
+	var total float64
+	for i, y := range history {
+		h := historyEnd.Add(-time.Duration(len(history)-i) * time.Hour).UTC().Hour()
+		sums[h] += float64(y)
+		counts[h]++
+		total += float64(y)
+	}
+	mean := total / float64(len(history))
+	return func(_ int, hour time.Time) float64 {
+		h := hour.UTC().Hour()
+		if counts[h] == 0 {
+			return mean
+		}
+		return sums[h] / counts[h]
+	}
+}
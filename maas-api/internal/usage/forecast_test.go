@@ -0,0 +1,55 @@
+package usage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+func TestProject(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC)
+
+	t.Run("linear trend", func(t *testing.T) {
+		// 10, 20, 30, 40: the next hours are 50 (half of it left), 60 and 70.
+		got := usage.Project([]int64{10, 20, 30, 40}, now, now.Add(150*time.Minute), usage.MethodLinear)
+		assert.Equal(t, int64(25+60+70), got)
+	})
+
+	t.Run("declining trend stops at zero", func(t *testing.T) {
+		got := usage.Project([]int64{30, 20, 10, 0}, now, now.Add(10*time.Hour), usage.MethodLinear)
+		assert.Equal(t, int64(0), got)
+	})
+
+	t.Run("daily cycle", func(t *testing.T) {
+		// Two days of history with 100 tokens at 13:00 and none otherwise.
+		history := make([]int64, 48)
+		for i := range history {
+			if now.Truncate(time.Hour).Add(-time.Duration(48-i)*time.Hour).Hour() == 13 {
+				history[i] = 100
+			}
+		}
+		got := usage.Project(history, now, now.Add(3*time.Hour), usage.MethodSeasonal)
+		assert.Equal(t, int64(100), got)
+		assert.Less(t, usage.Project(history, now, now.Add(3*time.Hour), usage.MethodLinear), int64(100))
+	})
+
+	t.Run("no history or period over", func(t *testing.T) {
+		assert.Equal(t, int64(0), usage.Project(nil, now, now.Add(time.Hour), usage.MethodLinear))
+		assert.Equal(t, int64(0), usage.Project([]int64{10}, now, now, usage.MethodLinear))
+	})
+}
+
+func TestParseMethod(t *testing.T) {
+	m, err := usage.ParseMethod("")
+	require.NoError(t, err)
+	assert.Equal(t, usage.MethodLinear, m)
+	m, err = usage.ParseMethod("seasonal")
+	require.NoError(t, err)
+	assert.Equal(t, usage.MethodSeasonal, m)
+	_, err = usage.ParseMethod("arima")
+	require.Error(t, err)
+}
@@ -0,0 +1,168 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// PostgresStore keeps the ledger in the usage_hourly table (see db/schema), so every replica
+// adds to and reports the same history.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a ledger in db, whose schema the api_keys migrations manage.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Add implements Store.
+func (s *PostgresStore) Add(ctx context.Context, r Record) error {
+	groups := r.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO usage_hourly (hour, username, groups, model, subscription, organization, requests, prompt_tokens, completion_tokens, total_tokens)
+		VALUES ($1, $2, $3, $4, $5, $6, 1, $7, $8, $9)
+		ON CONFLICT (hour, username, model, subscription) DO UPDATE SET
+			groups = EXCLUDED.groups,
+			organization = EXCLUDED.organization,
+			requests = usage_hourly.requests + 1,
+			prompt_tokens = usage_hourly.prompt_tokens + EXCLUDED.prompt_tokens,
+			completion_tokens = usage_hourly.completion_tokens + EXCLUDED.completion_tokens,
+			total_tokens = usage_hourly.total_tokens + EXCLUDED.total_tokens`,
+		IntervalHour.truncate(r.Time), r.User, pq.Array(groups), r.Model, r.Subscription, r.Organization,
+		r.PromptTokens, r.CompletionTokens, r.TotalTokens)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// columns maps each dimension to its usage_hourly expression. team comes from unnesting groups;
+// users without groups count toward the team "".
+var columns = map[Dimension]string{
+	DimensionUser:  "username",
+	DimensionTeam:  "team",
+	DimensionModel: "model",
+	DimensionTier:  "subscription",
+}
+
+// Query implements Store.
+func (s *PostgresStore) Query(ctx context.Context, q Query) ([]Row, error) {
+	query, args, err := buildQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var result []Row
+	for rows.Next() {
+		var row Row
+		var dest []any
+		if q.Interval != IntervalNone {
+			dest = append(dest, &row.Period)
+		}
+		for _, d := range q.GroupBy {
+			dest = append(dest, row.field(d))
+		}
+		dest = append(dest, &row.Requests, &row.PromptTokens, &row.CompletionTokens, &row.TotalTokens)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan usage: %w", err)
+		}
+		row.Period = row.Period.UTC()
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	return result, nil
+}
+
+// buildQuery builds the aggregate for q and its arguments. Only whitelisted identifiers are
+// interpolated; the time range and filters are passed as arguments.
+func buildQuery(q Query) (string, []any, error) {
+	var keys []string
+	if q.Interval != IntervalNone {
+		if _, err := ParseInterval(string(q.Interval)); err != nil {
+			return "", nil, err
+		}
+		keys = append(keys, fmt.Sprintf("date_trunc('%s', hour, 'UTC')", q.Interval))
+	}
+	from := "usage_hourly"
+	for _, d := range q.GroupBy {
+		column, ok := columns[d]
+		if !ok {
+			return "", nil, fmt.Errorf("invalid groupBy %q: must be user, team, model or tier", d)
+		}
+		if d == DimensionTeam {
+			from += " CROSS JOIN LATERAL unnest(CASE WHEN cardinality(groups) = 0 THEN ARRAY[''] ELSE groups END) AS team"
+		}
+		keys = append(keys, column)
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	for _, key := range keys {
+		b.WriteString(key + ", ")
+	}
+	b.WriteString("SUM(requests)::bigint, SUM(prompt_tokens)::bigint, SUM(completion_tokens)::bigint, SUM(total_tokens)::bigint FROM ")
+	b.WriteString(from)
+	b.WriteString(" WHERE hour >= date_trunc('hour', $1::timestamptz) AND hour < $2")
+	args := []any{q.From, q.To}
+	if q.User != "" {
+		args = append(args, q.User)
+		fmt.Fprintf(&b, " AND username = $%d", len(args))
+	}
+	if q.Model != "" {
+		args = append(args, q.Model)
+		fmt.Fprintf(&b, " AND model = $%d", len(args))
+	}
+	if len(keys) == 0 {
+		// Without grouping an empty range would still yield one row, of NULL sums.
+		b.WriteString(" HAVING COUNT(*) > 0")
+	} else {
+		list := strings.Join(keys, ", ")
+		b.WriteString(" GROUP BY " + list + " ORDER BY " + list)
+	}
+	return b.String(), args, nil
+}
+
+// Prune implements Store. Hours recorded before the organization column existed have none; they
+// are kept while any organization is under legal hold, as they may be one of its.
+func (s *PostgresStore) Prune(ctx context.Context, p Prune) (int64, error) {
+	where := `hour < $1 AND NOT (model = ANY($2)) AND
+		(cardinality($3::text[]) = 0 OR (organization IS NOT NULL AND NOT (organization = ANY($3))))`
+	args := []any{p.Before, pq.Array(nonNil(p.Models)), pq.Array(nonNil(p.Organizations))}
+	if p.DryRun {
+		var n int64
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM usage_hourly WHERE "+where, args...).Scan(&n); err != nil {
+			return 0, fmt.Errorf("failed to count prunable usage: %w", err)
+		}
+		return n, nil
+	}
+	res, err := s.db.ExecContext(ctx, "DELETE FROM usage_hourly WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune usage: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// nonNil returns s, or an empty slice for nil, which pq sends as NULL rather than an empty array.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,276 @@
+// Package usage keeps an hourly ledger of the token usage the gateway reports and aggregates it
+// into chargeback reports: token and request counts per user, team, model and tier over any time
+// range.
+package usage
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record is one model response as reported by the gateway.
+type Record struct {
+	Time         time.Time
+	User         string
+	Groups       []string // the user's groups, each a team in reports
+	Model        string   // namespace/name of the MaaSModelRef
+	Subscription string   // namespace/name of the MaaSSubscription, the tier in reports
+	Organization string   // tokenMetadata organization ID of the subscription, for legal holds
+
+	PromptTokens     int64
+	CompletionTokens int64
+	TotalTokens      int64
+}
+
+// Dimension is a column reports can group by.
+type Dimension string
+
+const (
+	DimensionUser  Dimension = "user"
+	DimensionTeam  Dimension = "team"
+	DimensionModel Dimension = "model"
+	DimensionTier  Dimension = "tier"
+)
+
+// ParseDimension parses a groupBy value.
+func ParseDimension(s string) (Dimension, error) {
+	switch d := Dimension(s); d {
+	case DimensionUser, DimensionTeam, DimensionModel, DimensionTier:
+		return d, nil
+	}
+	return "", fmt.Errorf("invalid groupBy %q: must be user, team, model or tier", s)
+}
+
+// Interval splits a report into periods.
+type Interval string
+
+const (
+	IntervalNone  Interval = "" // one period for the whole range
+	IntervalHour  Interval = "hour"
+	IntervalDay   Interval = "day"
+	IntervalMonth Interval = "month"
+)
+
+// ParseInterval parses an interval value.
+func ParseInterval(s string) (Interval, error) {
+	switch i := Interval(s); i {
+	case IntervalNone, IntervalHour, IntervalDay, IntervalMonth:
+		return i, nil
+	}
+	return "", fmt.Errorf("invalid interval %q: must be hour, day or month", s)
+}
+
+// truncate returns the start of the period containing t, in UTC.
+func (i Interval) truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case IntervalHour:
+		return t.Truncate(time.Hour)
+	case IntervalDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+// Query selects and groups usage. The ledger has hourly resolution, so a range that does not
+// start and end on the hour covers the whole hours it touches.
+type Query struct {
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	GroupBy  []Dimension
+	Interval Interval
+	// User and Model, when set, keep only the usage of that user or model.
+	User  string
+	Model string
+}
+
+// Row is the usage of one group in one period. Only the columns the query groups by are set.
+// A user counts toward every team they belong to, so team totals can add up to more than the
+// overall total.
+type Row struct {
+	Period time.Time `json:"period,omitzero"`
+	User   string    `json:"user,omitempty"`
+	Team   string    `json:"team,omitempty"`
+	Model  string    `json:"model,omitempty"`
+	Tier   string    `json:"tier,omitempty"`
+
+	Requests         int64 `json:"requests"`
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
+}
+
+// Get returns the value of column d.
+func (r *Row) Get(d Dimension) string {
+	return *r.field(d)
+}
+
+func (r *Row) field(d Dimension) *string {
+	switch d {
+	case DimensionUser:
+		return &r.User
+	case DimensionTeam:
+		return &r.Team
+	case DimensionModel:
+		return &r.Model
+	default:
+		return &r.Tier
+	}
+}
+
+// Store keeps the usage ledger.
+type Store interface {
+	// Add counts one response in the ledger.
+	Add(ctx context.Context, record Record) error
+	// Query aggregates the ledger, ordered by period and then by the grouped columns.
+	Query(ctx context.Context, q Query) ([]Row, error)
+	// Prune deletes the hours p selects, or only counts them in a dry run, and returns how many.
+	Prune(ctx context.Context, p Prune) (int64, error)
+}
+
+// Prune selects the ledger hours a retention run deletes: those before Before, except the usage of
+// subscriptions and models under legal hold.
+type Prune struct {
+	Before time.Time
+	// Organizations are the organizations whose usage is kept, as recorded with it.
+	Organizations []string
+	// Models are the models (namespace/name) whose usage is kept.
+	Models []string
+	DryRun bool
+}
+
+func (p *Prune) matches(hour time.Time, model, organization string) bool {
+	return hour.Before(p.Before) && !slices.Contains(p.Models, model) && !slices.Contains(p.Organizations, organization)
+}
+
+// MemoryStore keeps the ledger in this replica only and loses it on restart. It is meant for
+// tests and development; use PostgresStore in production.
+type MemoryStore struct {
+	mu    sync.Mutex
+	hours map[hourKey]*hourly
+}
+
+type hourKey struct {
+	hour                      time.Time
+	user, model, subscription string
+}
+
+type hourly struct {
+	groups       []string
+	organization string
+	counts       Row
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty in-memory ledger.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{hours: map[hourKey]*hourly{}}
+}
+
+// Add implements Store.
+func (s *MemoryStore) Add(_ context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := hourKey{hour: IntervalHour.truncate(r.Time), user: r.User, model: r.Model, subscription: r.Subscription}
+	h, ok := s.hours[key]
+	if !ok {
+		h = &hourly{}
+		s.hours[key] = h
+	}
+	h.groups = slices.Clone(r.Groups)
+	h.organization = r.Organization
+	h.counts.Requests++
+	h.counts.PromptTokens += r.PromptTokens
+	h.counts.CompletionTokens += r.CompletionTokens
+	h.counts.TotalTokens += r.TotalTokens
+	return nil
+}
+
+// Query implements Store.
+func (s *MemoryStore) Query(_ context.Context, q Query) ([]Row, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from, to := IntervalHour.truncate(q.From), q.To
+	groupByTeam := slices.Contains(q.GroupBy, DimensionTeam)
+	groups := map[Row]*Row{}
+	for key, h := range s.hours {
+		if key.hour.Before(from) || !key.hour.Before(to) {
+			continue
+		}
+		if (q.User != "" && key.user != q.User) || (q.Model != "" && key.model != q.Model) {
+			continue
+		}
+		teams := []string{""}
+		if groupByTeam && len(h.groups) > 0 {
+			teams = h.groups
+		}
+		values := map[Dimension]string{DimensionUser: key.user, DimensionModel: key.model, DimensionTier: key.subscription}
+		for _, team := range teams {
+			values[DimensionTeam] = team
+			group := Row{Period: q.Interval.truncate(key.hour)}
+			for _, d := range q.GroupBy {
+				*group.field(d) = values[d]
+			}
+			row, ok := groups[group]
+			if !ok {
+				row = &Row{}
+				*row = group
+				groups[group] = row
+			}
+			row.Requests += h.counts.Requests
+			row.PromptTokens += h.counts.PromptTokens
+			row.CompletionTokens += h.counts.CompletionTokens
+			row.TotalTokens += h.counts.TotalTokens
+		}
+	}
+
+	rows := make([]Row, 0, len(groups))
+	for _, row := range groups {
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b Row) int {
+		if c := a.Period.Compare(b.Period); c != 0 {
+			return c
+		}
+		for _, d := range q.GroupBy {
+			if c := cmp.Compare(a.Get(d), b.Get(d)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return rows, nil
+}
+
+// Prune implements Store.
+func (s *MemoryStore) Prune(_ context.Context, p Prune) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for key, h := range s.hours {
+		if !p.matches(key.hour, key.model, h.organization) {
+			continue
+		}
+		n++
+		if !p.DryRun {
+			delete(s.hours, key)
+		}
+	}
+	return n, nil
+}
+
+// SplitSubscriptionKey splits a model-scoped subscription key
+// (namespace/name@modelNamespace/modelName) into the subscription and the model.
+func SplitSubscriptionKey(key string) (string, string) {
+	subscription, model, _ := strings.Cut(key, "@")
+	return subscription, model
+}
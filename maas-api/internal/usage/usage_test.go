@@ -0,0 +1,127 @@
+package usage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/usage"
+)
+
+func ledger(t *testing.T) *usage.MemoryStore {
+	t.Helper()
+	s := usage.NewMemoryStore()
+	day := func(d, h int) time.Time { return time.Date(2026, 9, d, h, 30, 0, 0, time.UTC) }
+	for _, r := range []usage.Record{
+		{Time: day(1, 9), User: "alice", Groups: []string{"data-science", "finance"}, Model: "llm/granite", Subscription: "maas/premium", PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30},
+		{Time: day(1, 9), User: "alice", Groups: []string{"data-science", "finance"}, Model: "llm/granite", Subscription: "maas/premium", PromptTokens: 5, CompletionTokens: 5, TotalTokens: 10},
+		{Time: day(2, 14), User: "bob", Groups: []string{"data-science"}, Model: "llm/llama", Subscription: "maas/free", TotalTokens: 100},
+		{Time: day(30, 23), User: "carol", Model: "llm/granite", Subscription: "maas/free", TotalTokens: 7},
+		{Time: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), User: "alice", Groups: []string{"finance"}, Model: "llm/granite", Subscription: "maas/premium", TotalTokens: 1000},
+	} {
+		require.NoError(t, s.Add(context.Background(), r))
+	}
+	return s
+}
+
+var september = usage.Query{
+	From: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+	To:   time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestMemoryStoreQuery(t *testing.T) {
+	s := ledger(t)
+
+	t.Run("total", func(t *testing.T) {
+		rows, err := s.Query(context.Background(), september)
+		require.NoError(t, err)
+		assert.Equal(t, []usage.Row{{Requests: 4, PromptTokens: 15, CompletionTokens: 25, TotalTokens: 147}}, rows)
+	})
+
+	t.Run("by team", func(t *testing.T) {
+		q := september
+		q.GroupBy = []usage.Dimension{usage.DimensionTeam}
+		rows, err := s.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Equal(t, []usage.Row{
+			{Team: "", Requests: 1, TotalTokens: 7},
+			{Team: "data-science", Requests: 3, PromptTokens: 15, CompletionTokens: 25, TotalTokens: 140},
+			{Team: "finance", Requests: 2, PromptTokens: 15, CompletionTokens: 25, TotalTokens: 40},
+		}, rows, "a user counts toward each of their teams")
+	})
+
+	t.Run("by tier and model per day", func(t *testing.T) {
+		q := september
+		q.GroupBy = []usage.Dimension{usage.DimensionTier, usage.DimensionModel}
+		q.Interval = usage.IntervalDay
+		rows, err := s.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Equal(t, []usage.Row{
+			{Period: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Tier: "maas/premium", Model: "llm/granite", Requests: 2, PromptTokens: 15, CompletionTokens: 25, TotalTokens: 40},
+			{Period: time.Date(2026, 9, 2, 0, 0, 0, 0, time.UTC), Tier: "maas/free", Model: "llm/llama", Requests: 1, TotalTokens: 100},
+			{Period: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC), Tier: "maas/free", Model: "llm/granite", Requests: 1, TotalTokens: 7},
+		}, rows)
+	})
+
+	t.Run("by user per month", func(t *testing.T) {
+		q := usage.Query{
+			From:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			GroupBy:  []usage.Dimension{usage.DimensionUser},
+			Interval: usage.IntervalMonth,
+		}
+		rows, err := s.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Equal(t, []usage.Row{
+			{Period: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), User: "alice", Requests: 2, PromptTokens: 15, CompletionTokens: 25, TotalTokens: 40},
+			{Period: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), User: "bob", Requests: 1, TotalTokens: 100},
+			{Period: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), User: "carol", Requests: 1, TotalTokens: 7},
+			{Period: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), User: "alice", Requests: 1, TotalTokens: 1000},
+		}, rows)
+	})
+
+	t.Run("one user and model", func(t *testing.T) {
+		q := september
+		q.User, q.Model = "alice", "llm/granite"
+		rows, err := s.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Equal(t, []usage.Row{{Requests: 2, PromptTokens: 15, CompletionTokens: 25, TotalTokens: 40}}, rows)
+	})
+
+	t.Run("partial hours are included", func(t *testing.T) {
+		q := usage.Query{From: time.Date(2026, 9, 2, 14, 45, 0, 0, time.UTC), To: time.Date(2026, 9, 2, 14, 50, 0, 0, time.UTC)}
+		rows, err := s.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Equal(t, []usage.Row{{Requests: 1, TotalTokens: 100}}, rows)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		q := usage.Query{From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)}
+		rows, err := s.Query(context.Background(), q)
+		require.NoError(t, err)
+		assert.Empty(t, rows)
+	})
+}
+
+func TestParse(t *testing.T) {
+	d, err := usage.ParseDimension("team")
+	require.NoError(t, err)
+	assert.Equal(t, usage.DimensionTeam, d)
+	_, err = usage.ParseDimension("org")
+	require.Error(t, err)
+
+	i, err := usage.ParseInterval("")
+	require.NoError(t, err)
+	assert.Equal(t, usage.IntervalNone, i)
+	_, err = usage.ParseInterval("week")
+	require.Error(t, err)
+}
+
+func TestSplitSubscriptionKey(t *testing.T) {
+	subscription, model := usage.SplitSubscriptionKey("maas/premium@llm/granite")
+	assert.Equal(t, "maas/premium", subscription)
+	assert.Equal(t, "llm/granite", model)
+}
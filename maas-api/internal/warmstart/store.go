@@ -0,0 +1,94 @@
+package warmstart
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// MemoryStore keeps snapshots in this process only. It is meant for tests; use PostgresStore in
+// production.
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots []Snapshot
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = append(s.snapshots, snapshot)
+	return nil
+}
+
+// Claim implements Store.
+func (s *MemoryStore) Claim(context.Context) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	claimed := s.snapshots
+	s.snapshots = nil
+	return claimed, nil
+}
+
+// PostgresStore keeps snapshots in the warm_snapshots table (see db/schema), where a replica
+// started anywhere in the deployment finds them.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a store in db, whose schema the api_keys migrations manage.
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Save implements Store.
+func (s *PostgresStore) Save(ctx context.Context, snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO warm_snapshots (instance, taken_at, data) VALUES ($1, $2, $3)`,
+		snapshot.Instance, snapshot.TakenAt, data); err != nil {
+		return fmt.Errorf("failed to store warm restart snapshot: %w", err)
+	}
+	return nil
+}
+
+// Claim implements Store. Deleting the rows it returns makes concurrent claims disjoint: a row
+// deleted by one replica is skipped by the others.
+func (s *PostgresStore) Claim(ctx context.Context) ([]Snapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `DELETE FROM warm_snapshots RETURNING data`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim warm restart snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []Snapshot
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan warm restart snapshot: %w", err)
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			// A snapshot this version cannot read is dropped rather than blocking every claim.
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to claim warm restart snapshots: %w", err)
+	}
+	return snapshots, nil
+}
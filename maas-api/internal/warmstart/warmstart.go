@@ -0,0 +1,168 @@
+// Package warmstart carries the state maas-api enforces from memory across restarts: token budget
+// counters and request signing nonces, when they are not kept in Redis, and the clock anchor that
+// keeps budget windows from going back. A stopping replica saves a snapshot of them to the
+// database, and a starting or running replica claims it, so a rolling restart neither resets
+// budgets nor lets a signed request be replayed.
+package warmstart
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+)
+
+// snapshotVersion is the format of Snapshot. Snapshots of other versions are discarded.
+const snapshotVersion = 1
+
+// claimInterval is how often a running replica claims the snapshots of replicas that stopped
+// after it started, as during a rolling restart.
+const claimInterval = 30 * time.Second
+
+// Entry is one expiring value of a Part: a counter total, or 0 for set membership.
+type Entry struct {
+	Key    string    `json:"k"`
+	Value  int64     `json:"v,omitempty"`
+	Expiry time.Time `json:"e"`
+}
+
+// Part is in-memory state that survives a restart through snapshots.
+type Part interface {
+	// Entries returns the entries that have not expired.
+	Entries() []Entry
+	// Merge adds entries taken from another replica, skipping expired ones, and returns how many
+	// were added.
+	Merge(entries []Entry) int
+}
+
+// Snapshot is the state of one replica.
+type Snapshot struct {
+	Version  int                `json:"version"`
+	Instance string             `json:"instance"`
+	TakenAt  time.Time          `json:"takenAt"`
+	Parts    map[string][]Entry `json:"parts"`
+}
+
+// Store keeps snapshots until a replica claims them.
+type Store interface {
+	Save(ctx context.Context, s Snapshot) error
+	// Claim removes and returns every stored snapshot. A snapshot is returned to one caller only,
+	// even when several replicas claim at once.
+	Claim(ctx context.Context) ([]Snapshot, error)
+}
+
+// Manager saves and restores the parts registered with Add. A nil Manager does nothing.
+type Manager struct {
+	logger   *logger.Logger
+	store    Store
+	instance string
+	maxAge   time.Duration
+	parts    map[string]Part
+	now      func() time.Time
+
+	// mu serializes claims and the final save; once saved, nothing more is claimed, since it
+	// could no longer be passed on.
+	mu    sync.Mutex
+	saved bool
+}
+
+// New creates a Manager saving snapshots of instance to store. Snapshots taken more than maxAge
+// before they are claimed are discarded. It returns nil when maxAge is not positive.
+func New(log *logger.Logger, store Store, instance string, maxAge time.Duration) *Manager {
+	if maxAge <= 0 {
+		return nil
+	}
+	if log == nil {
+		log = logger.Production()
+	}
+	return &Manager{logger: log, store: store, instance: instance, maxAge: maxAge, parts: map[string]Part{}, now: time.Now}
+}
+
+// SetClock dates and ages snapshots by now instead of the local clock.
+func (m *Manager) SetClock(now func() time.Time) {
+	if m != nil {
+		m.now = now
+	}
+}
+
+// Add registers a part under name, which must be the same on every replica.
+func (m *Manager) Add(name string, p Part) {
+	if m != nil {
+		m.parts[name] = p
+	}
+}
+
+// Restore claims the stored snapshots and merges the fresh ones into the parts. Call it before
+// serving requests.
+func (m *Manager) Restore(ctx context.Context) error {
+	if m == nil || len(m.parts) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saved {
+		return nil
+	}
+	snapshots, err := m.store.Claim(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to claim warm restart snapshots: %w", err)
+	}
+	for _, s := range snapshots {
+		age := m.now().Sub(s.TakenAt)
+		if s.Version != snapshotVersion || age > m.maxAge {
+			m.logger.Info("Discarding warm restart snapshot", "instance", s.Instance, "takenAt", s.TakenAt, "version", s.Version)
+			continue
+		}
+		restored := make(map[string]int, len(s.Parts))
+		for name, entries := range s.Parts {
+			if p, ok := m.parts[name]; ok {
+				restored[name] = p.Merge(entries)
+			}
+		}
+		m.logger.Info("Restored warm restart snapshot", "instance", s.Instance, "age", age.Round(time.Second), "entries", restored)
+	}
+	return nil
+}
+
+// Run claims snapshots every claimInterval until ctx is done. Snapshots of replicas that stop
+// after this one started would otherwise wait for the next restart.
+func (m *Manager) Run(ctx context.Context) {
+	if m == nil || len(m.parts) == 0 {
+		return
+	}
+	ticker := time.NewTicker(claimInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Restore(ctx); err != nil {
+				m.logger.Warn("Warm restart claim failed", "error", err)
+			}
+		}
+	}
+}
+
+// Save stores a snapshot of the parts. Call it once, when requests have drained at shutdown.
+func (m *Manager) Save(ctx context.Context) error {
+	if m == nil || len(m.parts) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved = true
+	s := Snapshot{Version: snapshotVersion, Instance: m.instance, TakenAt: m.now().UTC(), Parts: make(map[string][]Entry, len(m.parts))}
+	counts := make(map[string]int, len(m.parts))
+	for name, p := range m.parts {
+		s.Parts[name] = p.Entries()
+		counts[name] = len(s.Parts[name])
+	}
+	if err := m.store.Save(ctx, s); err != nil {
+		return fmt.Errorf("failed to save warm restart snapshot: %w", err)
+	}
+	m.logger.Info("Saved warm restart snapshot", "entries", counts)
+	return nil
+}
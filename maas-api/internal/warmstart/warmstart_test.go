@@ -0,0 +1,106 @@
+package warmstart_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/quota"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signing"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/warmstart"
+)
+
+func TestSaveAndRestore(t *testing.T) {
+	ctx := context.Background()
+	store := warmstart.NewMemoryStore()
+
+	// The stopping replica counted 600 tokens for alice and accepted one nonce.
+	oldBudgets, oldNonces := quota.NewMemoryStore(), signing.NewMemoryCache(0)
+	_, err := oldBudgets.Add(ctx, "alice:maas/free@llm/granite:0", 600, time.Hour)
+	require.NoError(t, err)
+	_, err = oldBudgets.Add(ctx, "bob:maas/free@llm/granite:0", 5, time.Nanosecond)
+	require.NoError(t, err)
+	fresh, err := oldNonces.Remember(ctx, "key-1:nonce-1", time.Hour)
+	require.NoError(t, err)
+	require.True(t, fresh)
+
+	old := warmstart.New(logger.Development(), store, "maas-api-old", 10*time.Minute)
+	old.Add("budgets", oldBudgets)
+	old.Add("nonces", oldNonces)
+	require.NoError(t, old.Save(ctx))
+
+	// The replacement already counted 100 tokens for alice before claiming the snapshot.
+	newBudgets, newNonces := quota.NewMemoryStore(), signing.NewMemoryCache(0)
+	_, err = newBudgets.Add(ctx, "alice:maas/free@llm/granite:0", 100, time.Hour)
+	require.NoError(t, err)
+	replacement := warmstart.New(logger.Development(), store, "maas-api-new", 10*time.Minute)
+	replacement.Add("budgets", newBudgets)
+	replacement.Add("nonces", newNonces)
+	require.NoError(t, replacement.Restore(ctx))
+
+	total, err := newBudgets.Get(ctx, "alice:maas/free@llm/granite:0")
+	require.NoError(t, err)
+	assert.Equal(t, int64(700), total, "usage of both replicas counts")
+	total, err = newBudgets.Get(ctx, "bob:maas/free@llm/granite:0")
+	require.NoError(t, err)
+	assert.Zero(t, total, "expired counters are not restored")
+
+	fresh, err = newNonces.Remember(ctx, "key-1:nonce-1", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, fresh, "a nonce accepted before the restart is still a replay")
+
+	claimed, err := store.Claim(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "a snapshot is restored once")
+}
+
+func TestRestoreDiscardsStaleSnapshots(t *testing.T) {
+	ctx := context.Background()
+	store := warmstart.NewMemoryStore()
+	now := time.Now()
+
+	budgets := quota.NewMemoryStore()
+	_, err := budgets.Add(ctx, "alice:maas/free@llm/granite:0", 600, time.Hour)
+	require.NoError(t, err)
+	old := warmstart.New(logger.Development(), store, "maas-api-old", 10*time.Minute)
+	old.SetClock(func() time.Time { return now.Add(-time.Hour) })
+	old.Add("budgets", budgets)
+	require.NoError(t, old.Save(ctx))
+
+	restored := quota.NewMemoryStore()
+	replacement := warmstart.New(logger.Development(), store, "maas-api-new", 10*time.Minute)
+	replacement.SetClock(func() time.Time { return now })
+	replacement.Add("budgets", restored)
+	require.NoError(t, replacement.Restore(ctx))
+
+	total, err := restored.Get(ctx, "alice:maas/free@llm/granite:0")
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestSaveStopsClaims(t *testing.T) {
+	ctx := context.Background()
+	store := warmstart.NewMemoryStore()
+	require.NoError(t, store.Save(ctx, warmstart.Snapshot{Version: 1, Instance: "peer", TakenAt: time.Now()}))
+
+	m := warmstart.New(logger.Development(), store, "maas-api", time.Minute)
+	m.Add("budgets", quota.NewMemoryStore())
+	require.NoError(t, m.Save(ctx))
+	require.NoError(t, m.Restore(ctx))
+
+	claimed, err := store.Claim(ctx)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 2, "a stopping replica leaves its peers' snapshots for the next one")
+}
+
+func TestDisabled(t *testing.T) {
+	m := warmstart.New(logger.Development(), warmstart.NewMemoryStore(), "maas-api", 0)
+	assert.Nil(t, m)
+	m.Add("budgets", quota.NewMemoryStore())
+	require.NoError(t, m.Restore(context.Background()))
+	require.NoError(t, m.Save(context.Background()))
+}
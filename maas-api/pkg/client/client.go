@@ -0,0 +1,338 @@
+// Package client is a Go client for the maas-api REST API, for CLIs, portals and other tools that
+// list models, check access, mint tokens or manage tiers on behalf of a user.
+//
+//	c := client.New("https://maas.apps.example.com/maas-api", token)
+//	c.SetSubscription("premium")
+//	models, err := c.ListModels(ctx)
+//
+// Errors returned by maas-api are *APIError values carrying the HTTP status and the error code
+// of the maas-api error envelope.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CreateTokenRequest mints a token.
+type CreateTokenRequest struct {
+	// Name is generated when empty.
+	Name string
+	// Subscription is the MaaSSubscription the token is bound to; the caller's default when empty.
+	Subscription string
+	// Models (namespace/name) limits the token to these models; empty allows all of the subscription's.
+	Models []string
+	// ExpiresIn defaults to one hour, the maximum.
+	ExpiresIn time.Duration
+}
+
+// APIError is an error response of maas-api.
+type APIError struct {
+	StatusCode int
+	// Code is the machine-readable code, e.g. "PERMISSION_DENIED"; empty for responses without the
+	// maas-api error envelope.
+	Code    string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("maas-api returned %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("maas-api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 from maas-api.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Client calls maas-api with a bearer token: an OpenShift token, an OIDC token or an API key.
+type Client struct {
+	baseURL      string
+	token        string
+	subscription string
+	http         *http.Client
+}
+
+// New creates a client of the maas-api at baseURL, e.g. https://maas.apps.example.com/maas-api.
+func New(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetHTTPClient sends requests with hc instead of a client with a 30s timeout.
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.http = hc
+}
+
+// SetInsecureSkipVerify skips TLS certificate verification, for test clusters with self-signed
+// certificates.
+func (c *Client) SetInsecureSkipVerify(skip bool) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if skip {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in
+	}
+	c.http.Transport = transport
+}
+
+// SetSubscription sends the subscription (tier) in the X-MaaS-Subscription header, so model
+// listings are limited to the subscription's models.
+func (c *Client) SetSubscription(name string) {
+	c.subscription = name
+}
+
+// Subscription returns the subscription set with SetSubscription.
+func (c *Client) Subscription() string {
+	return c.subscription
+}
+
+// ListModels lists the models the caller may use, under the subscription set with SetSubscription
+// when there is one.
+func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
+	var page struct {
+		Data []Model `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/models", nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Data, nil
+}
+
+// ListSubscriptions lists the subscriptions the caller may use.
+func (c *Client) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	if err := c.do(ctx, http.MethodGet, "/v1/subscriptions", nil, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// ListSubscriptionsForModel lists the caller's subscriptions that include the MaaSModelRef
+// namespace/name.
+func (c *Client) ListSubscriptionsForModel(ctx context.Context, namespace, name string) ([]Subscription, error) {
+	var list struct {
+		Data []Subscription `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v2/models/"+url.PathEscape(namespace)+"/"+url.PathEscape(name)+"/subscriptions", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// ListLimits lists the caller's rate limits on each model of each of their subscriptions, with
+// their requests in progress where a concurrency cap applies.
+func (c *Client) ListLimits(ctx context.Context) ([]Limit, error) {
+	var list struct {
+		Data []Limit `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/limits", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// CheckAccess reports whether the caller may reach a model, as the gateway would decide. model is
+// a bare name, an alias or namespace/name; tier, when set, is the subscription to check.
+func (c *Client) CheckAccess(ctx context.Context, model, tier string) (*AccessDecision, error) {
+	query := url.Values{}
+	name := model
+	if namespace, n, ok := strings.Cut(model, "/"); ok {
+		name = n
+		query.Set("namespace", namespace)
+	}
+	if tier != "" {
+		query.Set("tier", tier)
+	}
+	path := "/v1/models/" + url.PathEscape(name) + "/access"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	var decision AccessDecision
+	if err := c.do(ctx, http.MethodGet, path, nil, &decision); err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// CreateToken mints a token for notebooks and CI jobs.
+func (c *Client) CreateToken(ctx context.Context, req CreateTokenRequest) (*CreatedToken, error) {
+	body := map[string]any{}
+	if req.Name != "" {
+		body["name"] = req.Name
+	}
+	if req.Subscription != "" {
+		body["subscription"] = req.Subscription
+	}
+	if len(req.Models) > 0 {
+		body["models"] = req.Models
+	}
+	if req.ExpiresIn > 0 {
+		body["expiresIn"] = req.ExpiresIn.String()
+	}
+	var created CreatedToken
+	if err := c.do(ctx, http.MethodPost, "/v1/tokens", body, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListTokens lists the caller's active tokens, newest first.
+func (c *Client) ListTokens(ctx context.Context) ([]Token, error) {
+	var list struct {
+		Data []Token `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/tokens", nil, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// RevokeToken revokes a token or API key of the caller by ID.
+func (c *Client) RevokeToken(ctx context.Context, id string) (*Token, error) {
+	var revoked Token
+	if err := c.do(ctx, http.MethodDelete, "/v1/tokens/"+url.PathEscape(id), nil, &revoked); err != nil {
+		return nil, err
+	}
+	return &revoked, nil
+}
+
+// ListTiers lists the tiers, highest priority first. Admins only.
+func (c *Client) ListTiers(ctx context.Context) ([]Tier, error) {
+	var page struct {
+		Data []Tier `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/tiers", nil, &page); err != nil {
+		return nil, err
+	}
+	return page.Data, nil
+}
+
+// GetTier returns a tier by name. Admins only.
+func (c *Client) GetTier(ctx context.Context, name string) (*Tier, error) {
+	var tier Tier
+	if err := c.do(ctx, http.MethodGet, "/v1/tiers/"+url.PathEscape(name), nil, &tier); err != nil {
+		return nil, err
+	}
+	return &tier, nil
+}
+
+// CreateTier creates a tier. Admins only.
+func (c *Client) CreateTier(ctx context.Context, tier Tier) (*Tier, error) {
+	var created Tier
+	if err := c.do(ctx, http.MethodPost, "/v1/tiers", tier, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateTier replaces the tier named tier.Name. Admins only.
+func (c *Client) UpdateTier(ctx context.Context, tier Tier) (*Tier, error) {
+	var updated Tier
+	if err := c.do(ctx, http.MethodPut, "/v1/tiers/"+url.PathEscape(tier.Name), tier, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTier deletes a tier by name. Admins only.
+func (c *Client) DeleteTier(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/tiers/"+url.PathEscape(name), nil, nil)
+}
+
+// GetCatalog returns the status of the MaaSModelCatalog: model counts by phase, every model with
+// its covering subscriptions, and the models each subscription covers. Admins only.
+func (c *Client) GetCatalog(ctx context.Context) (map[string]any, error) {
+	var catalog map[string]any
+	if err := c.do(ctx, http.MethodGet, "/v1/catalog", nil, &catalog); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// do sends a request with body encoded as JSON, when not nil, and decodes a 2xx response into
+// into, when not nil.
+func (c *Client) do(ctx context.Context, method, path string, body, into any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.subscription != "" {
+		req.Header.Set("X-MaaS-Subscription", c.subscription)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newAPIError(resp.StatusCode, data)
+	}
+	if into == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// newAPIError reads the maas-api error envelope, falling back to the flat {"error": "..."} some
+// endpoints return and then to the raw body.
+func newAPIError(status int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: status}
+	var nested struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &nested) == nil && nested.Error.Message != "" {
+		apiErr.Code, apiErr.Message = nested.Error.Code, nested.Error.Message
+		return apiErr
+	}
+	var flat struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &flat) == nil && flat.Error != "" {
+		apiErr.Message = flat.Error
+		return apiErr
+	}
+	apiErr.Message = strings.TrimSpace(string(body))
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
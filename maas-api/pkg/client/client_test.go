@@ -0,0 +1,186 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/extauthz"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/handlers"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/pkg/client"
+)
+
+// serve answers every request with body as JSON and records the last request.
+func serve(t *testing.T, status int, body any) (*client.Client, *http.Request) {
+	t.Helper()
+	got := &http.Request{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*got = *r.Clone(context.Background())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(srv.Close)
+	return client.New(srv.URL+"/", "test-token"), got
+}
+
+func TestListModels(t *testing.T) {
+	c, req := serve(t, http.StatusOK, json.RawMessage(`{"object":"list","data":[
+		{"id":"granite","object":"model","created":1700000000,"owned_by":"llm","url":"https://maas.example.com/llm/granite","ready":true,"aliases":["ibm/granite"]}]}`))
+	c.SetSubscription("premium")
+
+	list, err := c.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/models", req.URL.Path)
+	assert.Equal(t, "Bearer test-token", req.Header.Get("Authorization"))
+	assert.Equal(t, "premium", req.Header.Get("X-MaaS-Subscription"))
+	assert.Equal(t, []client.Model{{
+		ID: "granite", Object: "model", Created: 1700000000, OwnedBy: "llm",
+		URL: "https://maas.example.com/llm/granite", Ready: true, Aliases: []string{"ibm/granite"},
+	}}, list)
+}
+
+func TestCheckAccess(t *testing.T) {
+	c, req := serve(t, http.StatusOK, extauthz.AccessResponse{Object: "model.access", Decision: extauthz.Decision{
+		Model: "llm/granite", Reason: "tier_not_allowed", AllowedTiers: []string{"premium"},
+	}})
+
+	decision, err := c.CheckAccess(context.Background(), "llm/granite", "free")
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/models/granite/access", req.URL.Path)
+	assert.Equal(t, "llm", req.URL.Query().Get("namespace"))
+	assert.Equal(t, "free", req.URL.Query().Get("tier"))
+	assert.Equal(t, &client.AccessDecision{Model: "llm/granite", Reason: "tier_not_allowed", AllowedTiers: []string{"premium"}}, decision)
+}
+
+func TestCreateToken(t *testing.T) {
+	expires := "2026-01-01T01:00:00Z"
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/tokens", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(api_keys.CreateAPIKeyResponse{
+			Key: "sk-oai-secret", ID: "key-1", Name: "ci", Subscription: "premium", ExpiresAt: &expires, Ephemeral: true,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	created, err := client.New(srv.URL, "test-token").CreateToken(context.Background(), client.CreateTokenRequest{
+		Name: "ci", Subscription: "premium", Models: []string{"llm/granite"}, ExpiresIn: 30 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "ci", "subscription": "premium", "models": []any{"llm/granite"}, "expiresIn": "30m0s"}, body)
+	assert.Equal(t, &client.CreatedToken{
+		Key: "sk-oai-secret", ID: "key-1", Name: "ci", Subscription: "premium", ExpiresAt: &expires, Ephemeral: true,
+	}, created)
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   any
+		want   client.APIError
+	}{
+		{name: "envelope", status: http.StatusForbidden,
+			body: map[string]any{"error": map[string]any{"code": "PERMISSION_DENIED", "message": "admins only"}},
+			want: client.APIError{StatusCode: http.StatusForbidden, Code: "PERMISSION_DENIED", Message: "admins only"}},
+		{name: "flat", status: http.StatusNotFound, body: map[string]any{"error": "tier not found"},
+			want: client.APIError{StatusCode: http.StatusNotFound, Message: "tier not found"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := serve(t, tt.status, tt.body)
+			_, err := c.GetTier(context.Background(), "gold")
+			var apiErr *client.APIError
+			require.ErrorAs(t, err, &apiErr)
+			assert.Equal(t, tt.want, *apiErr)
+			assert.Equal(t, tt.status == http.StatusNotFound, client.IsNotFound(err))
+		})
+	}
+}
+
+// TestWireTypesMatchServer decodes what the maas-api handlers encode, so the client's types do
+// not drift from the server's.
+func TestWireTypesMatchServer(t *testing.T) {
+	inFlight := int64(2)
+	expires := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		served any
+		decode func(c *client.Client) (any, error)
+	}{
+		{name: "subscriptions", served: []subscription.SubscriptionInfo{{
+			SubscriptionIDHeader: "premium", SubscriptionDescription: "Premium", DisplayName: "Premium", Priority: 10,
+			ModelRefs: []subscription.ModelRefInfo{{
+				Name: "granite", Namespace: "llm",
+				TokenRateLimits:   []subscription.TokenRateLimit{{Limit: 1000, Window: "1m"}},
+				RequestRateLimits: []subscription.RequestRateLimit{{Limit: 10, Window: "1s"}},
+				BillingRate:       &subscription.BillingRate{PerToken: "0.001"},
+				TokenBudget:       &subscription.TokenBudget{Limit: 100000, Period: "30d"},
+				Override:          "vip", MaxConcurrentRequests: 4,
+			}},
+			OrganizationID: "acme", CostCenter: "rnd", Labels: map[string]string{"team": "ml"}, ExpiresAt: expires,
+		}}, decode: func(c *client.Client) (any, error) { return c.ListSubscriptions(context.Background()) }},
+		{name: "limits", served: subscription.LimitList{Object: "list", Data: []subscription.LimitInfo{{
+			Subscription: "models-as-a-service/premium", Model: "llm/granite",
+			TokenRateLimits:   []subscription.TokenRateLimit{{Limit: 1000, Window: "1m"}},
+			RequestRateLimits: []subscription.RequestRateLimit{{Limit: 10, Window: "1s"}},
+			Source:            "override", Override: "vip", MaxConcurrentRequests: 4, InFlight: &inFlight,
+		}}}, decode: func(c *client.Client) (any, error) { return c.ListLimits(context.Background()) }},
+		{name: "tokens", served: api_keys.SearchAPIKeysResponse{Object: "list", Data: []api_keys.ApiKey{{
+			ID: "key-1", Name: "ci", Description: "CI", Username: "alice", Subscription: "premium",
+			Models: []string{"llm/granite"}, Groups: []string{"ml"}, CreationDate: "2025-12-31T23:00:00Z",
+			ExpirationDate: "2026-01-01T00:00:00Z", Status: api_keys.StatusActive, LastUsedAt: "2025-12-31T23:30:00Z", Ephemeral: true,
+		}}}, decode: func(c *client.Client) (any, error) { return c.ListTokens(context.Background()) }},
+		{name: "tier", served: handlers.Tier{
+			Name: "premium", Priority: 10,
+			Owner: handlers.TierOwner{Groups: []string{"ml"}, Users: []string{"alice"}},
+			Models: []handlers.TierModel{{
+				Name: "granite", Namespace: "llm",
+				TokenRateLimits:   []handlers.TierRateLimit{{Limit: 1000, Window: "1m"}},
+				RequestRateLimits: []handlers.TierRateLimit{{Limit: 10, Window: "1s"}},
+				TokenBudget:       &handlers.TierBudget{Limit: 100000, Period: "30d"},
+			}},
+			ExpiresAt: &expires, Includes: []string{"free"},
+			Listener: &handlers.TierListener{Name: "premium", Hostnames: []string{"premium.example.com"}},
+			Phase:    "Active",
+		}, decode: func(c *client.Client) (any, error) { return c.GetTier(context.Background(), "premium") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := serve(t, http.StatusOK, tt.served)
+			got, err := tt.decode(c)
+			require.NoError(t, err)
+
+			want, err := json.Marshal(tt.served)
+			require.NoError(t, err)
+			gotJSON, err := json.Marshal(got)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(dataOf(t, want)), string(gotJSON))
+		})
+	}
+}
+
+// dataOf returns the data of a list envelope, or body itself.
+func dataOf(t *testing.T, body []byte) []byte {
+	t.Helper()
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(body, &envelope) == nil && envelope.Data != nil {
+		return envelope.Data
+	}
+	return body
+}
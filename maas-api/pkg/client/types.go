@@ -0,0 +1,209 @@
+package client
+
+import "time"
+
+// The types below are the JSON of the maas-api responses. They are kept apart from the server's
+// own types so that changes inside maas-api do not change the API of this package.
+
+// Model is a model of GET /v1/models.
+type Model struct {
+	// ID is the canonical model name sent in requests; other names the model server answers to are
+	// in Aliases.
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+	// Kind is the kind of model reference, e.g. "llmisvc".
+	Kind string `json:"kind,omitempty"`
+	// Class is chat, completion, embedding, reranker or audio.
+	Class         string              `json:"class,omitempty"`
+	URL           string              `json:"url,omitempty"`
+	Ready         bool                `json:"ready"`
+	Details       *ModelDetails       `json:"modelDetails,omitempty"`
+	Aliases       []string            `json:"aliases,omitempty"`
+	Resources     *ModelResources     `json:"resources,omitempty"`
+	Subscriptions []ModelSubscription `json:"subscriptions,omitempty"`
+	// Parent is the base model (namespace/name) this model is a variant of.
+	Parent string `json:"parent,omitempty"`
+	// Lineage lists the ancestors (namespace/name) from the direct parent up to the base model.
+	Lineage           []string `json:"lineage,omitempty"`
+	PricingMultiplier string   `json:"pricingMultiplier,omitempty"`
+	// Cluster is the cluster hosting the model when maas-api federates several clusters.
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// ModelDetails is the descriptive metadata of a model.
+type ModelDetails struct {
+	GenAIUseCase  string `json:"genaiUseCase,omitempty"`
+	Description   string `json:"description,omitempty"`
+	DisplayName   string `json:"displayName,omitempty"`
+	ContextWindow string `json:"contextWindow,omitempty"`
+}
+
+// ModelResources is the compute footprint of each replica of a model.
+type ModelResources struct {
+	GPUType  string `json:"gpuType,omitempty"`
+	GPUCount int64  `json:"gpuCount,omitempty"`
+	Memory   string `json:"memory,omitempty"`
+	Replicas int32  `json:"replicas,omitempty"`
+}
+
+// ModelSubscription is a subscription giving access to a model.
+type ModelSubscription struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Subscription is a subscription the caller may use, with the models it includes.
+type Subscription struct {
+	// SubscriptionIDHeader is the name to send in the X-MaaS-Subscription header.
+	SubscriptionIDHeader    string              `json:"subscription_id_header"`
+	SubscriptionDescription string              `json:"subscription_description"`
+	DisplayName             string              `json:"display_name,omitempty"`
+	Priority                int32               `json:"priority"`
+	ModelRefs               []SubscriptionModel `json:"model_refs"`
+	OrganizationID          string              `json:"organization_id,omitempty"`
+	CostCenter              string              `json:"cost_center,omitempty"`
+	Labels                  map[string]string   `json:"labels,omitempty"`
+	ExpiresAt               time.Time           `json:"expires_at,omitzero"`
+}
+
+// SubscriptionModel is a model of a subscription with its per-user limits.
+type SubscriptionModel struct {
+	Name              string       `json:"name"`
+	Namespace         string       `json:"namespace,omitempty"`
+	TokenRateLimits   []RateLimit  `json:"token_rate_limits,omitempty"`
+	RequestRateLimits []RateLimit  `json:"request_rate_limits,omitempty"`
+	BillingRate       *BillingRate `json:"billing_rate,omitempty"`
+	TokenBudget       *TokenBudget `json:"token_budget,omitempty"`
+	// Override is the MaaSRateLimitOverride that replaced the tier's limits, if any.
+	Override              string `json:"override,omitempty"`
+	MaxConcurrentRequests int64  `json:"max_concurrent_requests,omitempty"`
+}
+
+// RateLimit is a limit per window, e.g. 1000 tokens per "1m".
+type RateLimit struct {
+	Limit  int64  `json:"limit"`
+	Window string `json:"window"`
+}
+
+// BillingRate is what a subscription charges for a model.
+type BillingRate struct {
+	PerToken string `json:"per_token"`
+}
+
+// TokenBudget is the number of tokens each user may consume of a model per period.
+type TokenBudget struct {
+	Limit  int64  `json:"limit"`
+	Period string `json:"period"`
+}
+
+// Limit is the rate limits the caller gets on a model under one of their subscriptions.
+type Limit struct {
+	Subscription      string      `json:"subscription"` // namespace/name
+	Model             string      `json:"model"`        // namespace/name
+	TokenRateLimits   []RateLimit `json:"token_rate_limits,omitempty"`
+	RequestRateLimits []RateLimit `json:"request_rate_limits,omitempty"`
+	// Source is tier, or override when a MaaSRateLimitOverride named Override applies.
+	Source                string `json:"source"`
+	Override              string `json:"override,omitempty"`
+	MaxConcurrentRequests int64  `json:"max_concurrent_requests,omitempty"`
+	// InFlight is the caller's requests in progress on the model, when a concurrency cap applies.
+	InFlight *int64 `json:"in_flight,omitempty"`
+}
+
+// AccessDecision is whether the caller may reach a model and, if not, why.
+type AccessDecision struct {
+	Path    string `json:"path,omitempty"`
+	Tier    string `json:"tier,omitempty"`
+	Allowed bool   `json:"allowed"`
+	// Model is the resolved model as namespace/name.
+	Model string `json:"model,omitempty"`
+	// Subscription is the subscription the request would be metered against.
+	Subscription string `json:"subscription,omitempty"`
+	// Reason is the denial reason the gateway would send in x-ext-auth-reason.
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	// AllowedTiers lists the subscriptions that include the model, when maas-api discloses them.
+	AllowedTiers []string `json:"allowedTiers,omitempty"`
+}
+
+// Token is the metadata of a token (ephemeral API key); it never holds the key itself.
+type Token struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Username     string   `json:"username,omitempty"`
+	Subscription string   `json:"subscription,omitempty"`
+	Models       []string `json:"models,omitempty"`
+	Groups       []string `json:"groups,omitempty"`
+	CreationDate string   `json:"creationDate"`
+	// ExpirationDate is empty for permanent keys.
+	ExpirationDate string `json:"expirationDate,omitempty"`
+	// Status is active, expired or revoked.
+	Status     string `json:"status"`
+	LastUsedAt string `json:"lastUsedAt,omitempty"`
+	Ephemeral  bool   `json:"ephemeral"`
+}
+
+// CreatedToken is a minted token, with the key shown only this once.
+type CreatedToken struct {
+	Key          string   `json:"key"`
+	KeyPrefix    string   `json:"keyPrefix"`
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Subscription string   `json:"subscription"`
+	Models       []string `json:"models,omitempty"`
+	CreatedAt    string   `json:"createdAt"`
+	ExpiresAt    *string  `json:"expiresAt,omitempty"` // RFC3339
+	Ephemeral    bool     `json:"ephemeral"`
+}
+
+// Tier is the admin view of a MaaSSubscription.
+type Tier struct {
+	Name      string      `json:"name"`
+	Priority  int32       `json:"priority"`
+	Owner     TierOwner   `json:"owner"`
+	Models    []TierModel `json:"models"`
+	ExpiresAt *time.Time  `json:"expiresAt,omitempty"`
+	// Includes names lower tiers whose models this tier's owners may also use, or "*" for all.
+	Includes []string `json:"includes,omitempty"`
+	// Listener binds the tier to a gateway listener; requests must then use its hostnames.
+	Listener *TierListener `json:"listener,omitempty"`
+	// Phase is reported by maas-controller and ignored on create and update.
+	Phase string `json:"phase,omitempty"`
+}
+
+// TierModel is a model of a tier with its per-user limits.
+type TierModel struct {
+	Name              string          `json:"name"`
+	Namespace         string          `json:"namespace"`
+	TokenRateLimits   []TierRateLimit `json:"tokenRateLimits,omitempty"`
+	RequestRateLimits []TierRateLimit `json:"requestRateLimits,omitempty"`
+	TokenBudget       *TierBudget     `json:"tokenBudget,omitempty"`
+}
+
+// TierOwner lists the groups and users a tier applies to.
+type TierOwner struct {
+	Groups []string `json:"groups,omitempty"`
+	Users  []string `json:"users,omitempty"`
+}
+
+// TierRateLimit is a limit per window, e.g. 1000 tokens per "1m".
+type TierRateLimit struct {
+	Limit  int64  `json:"limit"`
+	Window string `json:"window"`
+}
+
+// TierBudget is the number of tokens each user may consume of a model per period ("24h", "30d").
+type TierBudget struct {
+	Limit  int64  `json:"limit"`
+	Period string `json:"period"`
+}
+
+// TierListener is the gateway listener a tier is bound to.
+type TierListener struct {
+	Name      string   `json:"name"`
+	Hostnames []string `json:"hostnames"`
+}
@@ -0,0 +1,87 @@
+// k6 scenario for the authorization endpoints of a deployed maas-api, through the gateway.
+//
+//   HOST=https://maas.example.com TOKEN=$(oc whoami -t) MODELS=llm/granite,llm/llama \
+//     k6 run --summary-export bench-k6.json test/bench/k6/authorize.js
+//
+// Environment:
+//   HOST         gateway URL in front of maas-api (required)
+//   TOKEN        bearer token or API key of the caller (required)
+//   MODELS       comma-separated namespace/name models; default: the models GET /v1/models lists
+//   ENDPOINT     access or batch (default access)
+//   BATCH_SIZE   entries per batch call (default 20)
+//   VUS          concurrent callers (default 16)
+//   DURATION     how long the scenario runs (default 1m)
+//   API_PREFIX   path of maas-api behind the gateway (default /maas-api)
+import http from 'k6/http';
+import { check, fail } from 'k6';
+import { Counter } from 'k6/metrics';
+
+const host = (__ENV.HOST || '').replace(/\/$/, '');
+const token = __ENV.TOKEN || '';
+const endpoint = __ENV.ENDPOINT || 'access';
+const batchSize = parseInt(__ENV.BATCH_SIZE || '20', 10);
+const api = host + (__ENV.API_PREFIX || '/maas-api');
+
+const denied = new Counter('maas_denied');
+
+export const options = {
+  scenarios: {
+    authorize: {
+      executor: 'constant-vus',
+      vus: parseInt(__ENV.VUS || '16', 10),
+      duration: __ENV.DURATION || '1m',
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    maas_denied: ['count==0'],
+  },
+};
+
+const params = {
+  headers: { Authorization: `Bearer ${token}`, 'Content-Type': 'application/json' },
+  tags: { endpoint },
+};
+
+export function setup() {
+  if (!host || !token) {
+    fail('HOST and TOKEN must be set');
+  }
+  if (endpoint !== 'access' && endpoint !== 'batch') {
+    fail(`unknown ENDPOINT ${endpoint}: want access or batch`);
+  }
+  if (__ENV.MODELS) {
+    return { models: __ENV.MODELS.split(',').map((m) => m.trim()).filter((m) => m) };
+  }
+  const res = http.get(`${api}/v1/models`, params);
+  if (res.status !== 200) {
+    fail(`GET /v1/models: ${res.status} ${res.body}`);
+  }
+  // owned_by is the namespace/name of the MaaSModelRef; the gateway serves it under /namespace/name.
+  const models = res.json('data').map((m) => m.owned_by);
+  if (models.length === 0) {
+    fail('GET /v1/models listed no models; set MODELS');
+  }
+  return { models };
+}
+
+export default function (data) {
+  const models = data.models;
+  const i = (__VU * 7919 + __ITER) % models.length;
+  if (endpoint === 'access') {
+    const [namespace, name] = models[i].split('/');
+    const res = http.get(`${api}/v1/models/${encodeURIComponent(name)}/access?namespace=${encodeURIComponent(namespace)}`, params);
+    if (check(res, { 'status is 200': (r) => r.status === 200 }) && !res.json('allowed')) {
+      denied.add(1);
+    }
+    return;
+  }
+  const requests = [];
+  for (let j = 0; j < batchSize; j++) {
+    requests.push({ path: `/${models[(i + j) % models.length]}/v1/chat/completions` });
+  }
+  const res = http.post(`${api}/v1/models/authorize/batch`, JSON.stringify({ requests }), params);
+  if (check(res, { 'status is 200': (r) => r.status === 200 }) && res.json('data').some((d) => !d.allowed)) {
+    denied.add(1);
+  }
+}
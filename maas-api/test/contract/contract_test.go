@@ -0,0 +1,415 @@
+//go:build contract
+
+// Package contract runs the AuthPolicy maas-controller generates (testdata/authpolicy.yaml) in a
+// real Authorino against maas-api's metadata endpoints, and checks the decisions, injected headers
+// and dynamic metadata of the requests in testdata/cases.yaml. It needs an Authorino binary
+// (AUTHORINO_BIN) and the envtest API server (KUBEBUILDER_ASSETS); make test-contract sets up both.
+package contract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/structpb"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/api_keys"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/config"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/logger"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/signedurl"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/subscription"
+	"github.com/opendatahub-io/models-as-a-service/maas-api/internal/token"
+)
+
+const (
+	// namespace holds the AuthConfig and the service accounts, and is the one Authorino watches.
+	namespace = "contract"
+	// host is the host the AuthConfig serves, in place of the HTTPRoute the AuthPolicy targets.
+	host = "granite.contract.test"
+	// maasAPIURL is the maas-api base URL in the fixture, replaced with the test server's.
+	maasAPIURL = "https://maas-api.maas-system.svc.cluster.local:8443"
+	// audience is the ClusterAudience the fixture was generated with.
+	audience = "contract"
+)
+
+var authConfigGVR = schema.GroupVersionResource{Group: "authorino.kuadrant.io", Version: "v1beta3", Resource: "authconfigs"}
+
+// fixture is testdata/cases.yaml.
+type fixture struct {
+	APIKeys []struct {
+		Name         string   `json:"name"`
+		Username     string   `json:"username"`
+		Groups       []string `json:"groups"`
+		Subscription string   `json:"subscription"`
+		Models       []string `json:"models"`
+		Revoked      bool     `json:"revoked"`
+	} `json:"apiKeys"`
+	SignedURLs []struct {
+		Name         string   `json:"name"`
+		Username     string   `json:"username"`
+		Groups       []string `json:"groups"`
+		Subscription string   `json:"subscription"`
+		Model        string   `json:"model"`
+		Expired      bool     `json:"expired"`
+	} `json:"signedUrls"`
+	ServiceAccounts []struct {
+		Name string `json:"name"`
+	} `json:"serviceAccounts"`
+	Subscriptions []map[string]any `json:"subscriptions"`
+	Cases         []testCase       `json:"cases"`
+}
+
+type testCase struct {
+	Name           string            `json:"name"`
+	Path           string            `json:"path"`
+	Headers        map[string]string `json:"headers"`
+	APIKey         string            `json:"apiKey"`
+	SignedURL      string            `json:"signedUrl"`
+	ServiceAccount string            `json:"serviceAccount"`
+	Expect         struct {
+		Code     codes.Code        `json:"code"`
+		Status   int               `json:"status"`
+		Headers  map[string]string `json:"headers"`
+		Metadata map[string]string `json:"metadata"`
+	} `json:"expect"`
+}
+
+// credentials are the secrets of the fixture's credentials, by name.
+type credentials struct {
+	apiKeys, signedURLs, serviceAccounts map[string]string
+}
+
+type staticLister []*unstructured.Unstructured
+
+func (s staticLister) List() ([]*unstructured.Unstructured, error) { return s, nil }
+
+type noAdmins struct{}
+
+func (noAdmins) IsAdmin(context.Context, *token.UserContext) bool { return false }
+
+func TestAuthPolicyContract(t *testing.T) {
+	authorinoBin := os.Getenv("AUTHORINO_BIN")
+	if authorinoBin == "" {
+		t.Skip("AUTHORINO_BIN is not set; run make test-contract")
+	}
+	var fx fixture
+	readYAML(t, filepath.Join("testdata", "cases.yaml"), &fx)
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("testdata", "crds")},
+		ErrorIfCRDPathMissing: true,
+	}
+	restConfig, err := env.Start()
+	require.NoError(t, err, "start envtest; is KUBEBUILDER_ASSETS set?")
+	t.Cleanup(func() { _ = env.Stop() })
+	kube := kubernetes.NewForConfigOrDie(restConfig)
+	ctx := t.Context()
+	_, err = kube.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	maasAPI, creds := startMaaSAPI(t, &fx)
+	creds.serviceAccounts = serviceAccountTokens(t, kube, &fx)
+	createAuthConfig(t, dynamic.NewForConfigOrDie(restConfig), maasAPI)
+
+	user, err := env.AddUser(envtest.User{Name: "authorino", Groups: []string{"system:masters"}}, nil)
+	require.NoError(t, err)
+	kubeconfig, err := user.KubeConfig()
+	require.NoError(t, err)
+	authz := startAuthorino(t, authorinoBin, kubeconfig)
+
+	for _, tc := range fx.Cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, err := authz.Check(t.Context(), checkRequest(t, tc, creds))
+			require.NoError(t, err)
+			verify(t, tc, resp)
+		})
+	}
+}
+
+// startMaaSAPI serves the endpoints the AuthPolicy calls, backed by the fixture's API keys and
+// subscriptions, and returns its URL and the secrets of the fixture's API keys and signed URLs.
+func startMaaSAPI(t *testing.T, fx *fixture) (string, credentials) {
+	t.Helper()
+	log := logger.Development()
+	ctx := t.Context()
+
+	subscriptions := make(staticLister, 0, len(fx.Subscriptions))
+	for _, obj := range fx.Subscriptions {
+		subscriptions = append(subscriptions, &unstructured.Unstructured{Object: obj})
+	}
+	selector := subscription.NewSelector(log, subscriptions)
+
+	store := api_keys.NewMockStore()
+	creds := credentials{apiKeys: map[string]string{}, signedURLs: map[string]string{}}
+	for _, k := range fx.APIKeys {
+		plaintext, hash, _, err := api_keys.GenerateAPIKey()
+		require.NoError(t, err)
+		require.NoError(t, store.AddKey(ctx, k.Username, k.Name, hash, k.Name, "", k.Groups, k.Subscription, k.Models, nil, false))
+		if k.Revoked {
+			require.NoError(t, store.Revoke(ctx, k.Name))
+		}
+		creds.apiKeys[k.Name] = plaintext
+	}
+	keys := api_keys.NewHandler(log, api_keys.NewServiceWithLogger(store, &config.Config{}, selector, log), noAdmins{})
+
+	signer, err := signedurl.NewSigner([]byte(strings.Repeat("contract", 4)), time.Hour)
+	require.NoError(t, err)
+	for _, u := range fx.SignedURLs {
+		now := time.Now()
+		if u.Expired {
+			now = now.Add(-2 * time.Hour)
+		}
+		signer.SetClock(func() time.Time { return now })
+		tokenValue, _, err := signer.Mint(signedurl.Grant{User: u.Username, Groups: u.Groups, Subscription: u.Subscription, Model: u.Model}, time.Minute)
+		require.NoError(t, err)
+		creds.signedURLs[u.Name] = tokenValue
+	}
+	signer.SetClock(time.Now)
+	keys.SetSignedURLSigner(signer)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/internal/v1/api-keys/validate", keys.ValidateAPIKeyHandler)
+	router.POST("/internal/v1/subscriptions/select", subscription.NewHandler(log, selector).SelectSubscription)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server.URL, creds
+}
+
+// serviceAccountTokens creates the fixture's service accounts and returns a token of each for the
+// fixture's audience.
+func serviceAccountTokens(t *testing.T, kube kubernetes.Interface, fx *fixture) map[string]string {
+	t.Helper()
+	tokens := map[string]string{}
+	for _, sa := range fx.ServiceAccounts {
+		_, err := kube.CoreV1().ServiceAccounts(namespace).Create(t.Context(),
+			&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: sa.Name}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		tr, err := kube.CoreV1().ServiceAccounts(namespace).CreateToken(t.Context(), sa.Name,
+			&authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{Audiences: []string{audience}}}, metav1.CreateOptions{})
+		require.NoError(t, err)
+		tokens[sa.Name] = tr.Status.Token
+	}
+	return tokens
+}
+
+// createAuthConfig creates the AuthConfig Kuadrant would derive from the fixture AuthPolicy: its
+// rules for the test host, calling maasAPI instead of the in-cluster service.
+func createAuthConfig(t *testing.T, client dynamic.Interface, maasAPI string) {
+	t.Helper()
+	var policy map[string]any
+	readYAML(t, filepath.Join("testdata", "authpolicy.yaml"), &policy)
+	rules, found, err := unstructured.NestedMap(policy, "spec", "rules")
+	require.NoError(t, err)
+	require.True(t, found, "authpolicy.yaml has no spec.rules")
+
+	// AuthPolicies call the dynamic metadata of success responses filters.
+	if filters, found, _ := unstructured.NestedMap(rules, "response", "success", "filters"); found {
+		unstructured.RemoveNestedField(rules, "response", "success", "filters")
+		require.NoError(t, unstructured.SetNestedMap(rules, filters, "response", "success", "dynamicMetadata"))
+	}
+	data, err := json.Marshal(rules)
+	require.NoError(t, err)
+	data = []byte(strings.ReplaceAll(string(data), maasAPIURL, maasAPI))
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(data, &spec))
+	spec["hosts"] = []any{host}
+
+	authConfig := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": authConfigGVR.GroupVersion().String(),
+		"kind":       "AuthConfig",
+		"metadata":   map[string]any{"name": "maas-auth-granite", "namespace": namespace},
+		"spec":       spec,
+	}}
+	_, err = client.Resource(authConfigGVR).Namespace(namespace).Create(t.Context(), authConfig, metav1.CreateOptions{})
+	require.NoError(t, err)
+}
+
+// startAuthorino runs Authorino against the envtest API server and returns a client of its
+// ext_authz service once it serves the AuthConfig.
+func startAuthorino(t *testing.T, bin string, kubeconfig []byte) authv3.AuthorizationClient {
+	t.Helper()
+	dir := t.TempDir()
+	kubeconfigPath := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfigPath, kubeconfig, 0o600))
+
+	grpcPort := freePort(t)
+	cmd := exec.CommandContext(t.Context(), bin, "server",
+		"--watch-namespace="+namespace,
+		"--ext-auth-grpc-port="+strconv.Itoa(grpcPort),
+		"--ext-auth-http-port="+strconv.Itoa(freePort(t)),
+		"--oidc-http-port="+strconv.Itoa(freePort(t)),
+		"--metrics-addr=127.0.0.1:"+strconv.Itoa(freePort(t)),
+		"--health-probe-addr=127.0.0.1:"+strconv.Itoa(freePort(t)),
+		"--enable-leader-election=false",
+		"--log-level=debug",
+	)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfigPath)
+	logFile, err := os.Create(filepath.Join(dir, "authorino.log"))
+	require.NoError(t, err)
+	cmd.Stdout, cmd.Stderr = logFile, logFile
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		if t.Failed() {
+			if out, err := os.ReadFile(logFile.Name()); err == nil {
+				t.Logf("Authorino log:\n%s", out)
+			}
+		}
+	})
+
+	conn, err := grpc.NewClient("127.0.0.1:"+strconv.Itoa(grpcPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := authv3.NewAuthorizationClient(conn)
+
+	// Authorino answers NOT_FOUND for hosts without an AuthConfig until it has indexed ours.
+	probe := &authv3.CheckRequest{Attributes: &authv3.AttributeContext{Request: &authv3.AttributeContext_Request{
+		Http: &authv3.AttributeContext_HttpRequest{Host: host, Path: "/", Method: "GET", Headers: map[string]string{}},
+	}}}
+	require.Eventually(t, func() bool {
+		resp, err := client.Check(t.Context(), probe)
+		return err == nil && resp.GetStatus().GetCode() != int32(codes.NotFound)
+	}, time.Minute, 250*time.Millisecond, "Authorino did not serve the AuthConfig")
+	return client
+}
+
+// checkRequest builds the ext_authz request of a case.
+func checkRequest(t *testing.T, tc testCase, creds credentials) *authv3.CheckRequest {
+	t.Helper()
+	headers := map[string]string{}
+	for k, v := range tc.Headers {
+		headers[strings.ToLower(k)] = v
+	}
+	path := tc.Path
+	switch {
+	case tc.APIKey != "":
+		key, ok := creds.apiKeys[tc.APIKey]
+		require.True(t, ok, "unknown API key %q", tc.APIKey)
+		headers["authorization"] = "Bearer " + key
+	case tc.SignedURL != "":
+		tokenValue, ok := creds.signedURLs[tc.SignedURL]
+		require.True(t, ok, "unknown signed URL %q", tc.SignedURL)
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		path += separator + signedurl.QueryParameter + "=" + tokenValue
+	case tc.ServiceAccount != "":
+		tokenValue, ok := creds.serviceAccounts[tc.ServiceAccount]
+		require.True(t, ok, "unknown service account %q", tc.ServiceAccount)
+		headers["authorization"] = "Bearer " + tokenValue
+	}
+	_, query, _ := strings.Cut(path, "?")
+	return &authv3.CheckRequest{Attributes: &authv3.AttributeContext{Request: &authv3.AttributeContext_Request{
+		Http: &authv3.AttributeContext_HttpRequest{
+			Id: tc.Name, Method: "POST", Host: host, Path: path, Query: query, Scheme: "https", Protocol: "HTTP/1.1",
+			Headers: headers,
+		},
+	}}}
+}
+
+// verify checks a response against the expectations of a case.
+func verify(t *testing.T, tc testCase, resp *authv3.CheckResponse) {
+	t.Helper()
+	require.Equal(t, int32(tc.Expect.Code), resp.GetStatus().GetCode(),
+		"got %s: %s", codes.Code(resp.GetStatus().GetCode()), resp.GetStatus().GetMessage())
+
+	headers := map[string]string{}
+	if ok := resp.GetOkResponse(); ok != nil {
+		for _, h := range ok.GetHeaders() {
+			headers[strings.ToLower(h.GetHeader().GetKey())] = h.GetHeader().GetValue()
+		}
+	}
+	if denied := resp.GetDeniedResponse(); denied != nil {
+		if tc.Expect.Status != 0 {
+			assert.Equal(t, tc.Expect.Status, int(denied.GetStatus().GetCode()), "HTTP status")
+		}
+		for _, h := range denied.GetHeaders() {
+			headers[strings.ToLower(h.GetHeader().GetKey())] = h.GetHeader().GetValue()
+		}
+	}
+	for name, want := range tc.Expect.Headers {
+		got, ok := headers[strings.ToLower(name)]
+		if assert.True(t, ok, "header %s missing", name) {
+			assertValue(t, want, got, "header "+name)
+		}
+	}
+	for path, want := range tc.Expect.Metadata {
+		got, ok := lookup(resp.GetDynamicMetadata(), path)
+		if assert.True(t, ok, "metadata %s missing", path) {
+			assertValue(t, want, got, "metadata "+path)
+		}
+	}
+}
+
+// assertValue compares a value with an expected one, which is a regular expression when it starts
+// with ~.
+func assertValue(t *testing.T, want, got, what string) {
+	t.Helper()
+	if pattern, ok := strings.CutPrefix(want, "~"); ok {
+		assert.Regexp(t, regexp.MustCompile(pattern), got, what)
+		return
+	}
+	assert.Equal(t, want, got, what)
+}
+
+// lookup returns the value at a dotted path of dynamic metadata, formatted as a string.
+func lookup(s *structpb.Struct, path string) (string, bool) {
+	var value any = s.AsMap()
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+	if b, err := json.Marshal(value); err == nil && !strings.HasPrefix(string(b), `"`) {
+		return string(b), true
+	}
+	return fmt.Sprint(value), true
+}
+
+func readYAML(t *testing.T, path string, into any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, into), "parse %s", path)
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
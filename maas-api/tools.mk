@@ -11,6 +11,19 @@ GOLANGCI_LINT_VERSION ?= v2.6.2
 $(GOLANGCI_LINT): $(LOCALBIN)
 	$(call go-install-tool,$(GOLANGCI_LINT),github.com/golangci/golangci-lint/v2/cmd/golangci-lint,$(GOLANGCI_LINT_VERSION))
 
+SETUP_ENVTEST ?= $(LOCALBIN)/setup-envtest
+
+SETUP_ENVTEST_VERSION ?= release-0.22
+ENVTEST_K8S_VERSION ?= 1.34.1
+$(SETUP_ENVTEST): $(LOCALBIN)
+	$(call go-install-tool,$(SETUP_ENVTEST),sigs.k8s.io/controller-runtime/tools/setup-envtest,$(SETUP_ENVTEST_VERSION))
+
+AUTHORINO ?= $(LOCALBIN)/authorino
+
+AUTHORINO_VERSION ?= v0.22.0
+$(AUTHORINO): $(LOCALBIN)
+	$(call go-install-tool,$(AUTHORINO),github.com/kuadrant/authorino,$(AUTHORINO_VERSION))
+
 # go-install-tool will 'go install' any package with custom target and name of binary, if it doesn't exist
 # $1 - target path with name of binary (ideally with version)
 # $2 - package url which can be installed
@@ -39,8 +39,128 @@ type ExternalModelSpec struct {
 
 	// CredentialRef references a Kubernetes Secret containing the provider API key.
 	// The Secret must contain a data key "api-key" with the credential value.
+	// When VaultRef is set, the controller creates and maintains this Secret from Vault.
 	// +kubebuilder:validation:Required
 	CredentialRef CredentialReference `json:"credentialRef"`
+
+	// VaultRef sources the provider API key from HashiCorp Vault instead of a hand-managed Secret.
+	// The controller logs in with its service account through Vault's Kubernetes auth method,
+	// reads the path, and keeps the Secret named by CredentialRef in sync, renewing leases.
+	// +optional
+	VaultRef *VaultCredentialReference `json:"vaultRef,omitempty"`
+
+	// CACertificateRef references a Secret in the same namespace whose "ca.crt" key holds the PEM
+	// bundle that verifies the provider's certificate, for providers behind a private CA.
+	// When unset, the gateway trusts the system CA bundle.
+	// +optional
+	CACertificateRef *CredentialReference `json:"caCertificateRef,omitempty"`
+
+	// HealthCheck makes the controller probe the provider directly and report the result in the
+	// EndpointReachable condition of the MaaSModelRefs using this ExternalModel. While the probe
+	// fails, those models are not Ready. When unset, the provider is not probed.
+	// +optional
+	HealthCheck *ExternalModelHealthCheck `json:"healthCheck,omitempty"`
+
+	// Auth makes the gateway send a credential from a Secret to the provider on every request.
+	// The credential replaces whatever the caller sent in its header, and the caller's
+	// Authorization header never reaches the provider. When unset, requests are forwarded with
+	// the caller's headers.
+	// +optional
+	Auth *ExternalModelAuth `json:"auth,omitempty"`
+}
+
+// ExternalModelAuthType is how the credential is presented to the provider.
+// +kubebuilder:validation:Enum=APIKey;Bearer;Basic
+type ExternalModelAuthType string
+
+const (
+	// ExternalModelAuthAPIKey sends the credential as is in Header.
+	ExternalModelAuthAPIKey ExternalModelAuthType = "APIKey"
+	// ExternalModelAuthBearer sends "Authorization: Bearer <credential>".
+	ExternalModelAuthBearer ExternalModelAuthType = "Bearer"
+	// ExternalModelAuthBasic sends "Authorization: Basic <base64(username:password)>" from the
+	// "username" and "password" keys of the Secret, as in a kubernetes.io/basic-auth Secret.
+	ExternalModelAuthBasic ExternalModelAuthType = "Basic"
+)
+
+// ExternalModelAuth configures the credential the gateway injects toward the provider.
+type ExternalModelAuth struct {
+	// Type is how the credential is presented to the provider.
+	// +kubebuilder:validation:Required
+	Type ExternalModelAuthType `json:"type"`
+
+	// SecretRef references the Secret in the same namespace holding the credential.
+	// Defaults to spec.credentialRef, including when it is synced from spec.vaultRef.
+	// +optional
+	SecretRef *CredentialReference `json:"secretRef,omitempty"`
+
+	// Key is the Secret data key holding the credential for types APIKey and Bearer.
+	// +kubebuilder:default="api-key"
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Header is the request header carrying the credential for type APIKey, e.g. "api-key"
+	// for Azure OpenAI. Defaults to "x-api-key". Ignored by the other types.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_-]*$`
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Header string `json:"header,omitempty"`
+}
+
+// ExternalModelHealthCheck configures how the provider endpoint is probed. The probe uses the
+// port and TLS settings of the route, and sends no credentials; list 401 in ExpectedStatusCodes
+// to probe an authenticated path for reachability only.
+type ExternalModelHealthCheck struct {
+	// Path is the request path, e.g. "/v1/models".
+	// +kubebuilder:default="/"
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Method is the HTTP method of the probe.
+	// +kubebuilder:default=GET
+	// +kubebuilder:validation:Enum=GET;HEAD
+	// +optional
+	Method string `json:"method,omitempty"`
+
+	// ExpectedStatusCodes are the response codes that count as reachable. Defaults to 200.
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:Minimum=100
+	// +kubebuilder:validation:items:Maximum=599
+	// +optional
+	ExpectedStatusCodes []int32 `json:"expectedStatusCodes,omitempty"`
+
+	// Interval is the time between probes while the endpoint is reachable. After failures, probes
+	// back off exponentially from Interval up to ten times Interval.
+	// +kubebuilder:default="30s"
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Timeout bounds each probe.
+	// +kubebuilder:default="5s"
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// VaultCredentialReference locates a provider API key in HashiCorp Vault.
+type VaultCredentialReference struct {
+	// Path is the Vault API path to read, without the /v1/ prefix,
+	// e.g. "secret/data/openai" (KV version 2) or "openai/creds/maas" (dynamic secret).
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// Key is the field of the Vault secret holding the API key.
+	// +kubebuilder:default="api-key"
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Role is the Vault Kubernetes auth role the controller logs in with.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Role string `json:"role"`
 }
 
 // ExternalModelStatus defines the observed state of ExternalModel
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaaSConfigSingletonName is the only accepted name for the cluster-scoped MaaSConfig resource.
+const MaaSConfigSingletonName = "default"
+
+// GeneratedResources sets the names and labels of the resources the controller generates for
+// models, so they fit the cluster's naming conventions and cost-attribution labels.
+type GeneratedResources struct {
+	// NameTemplates maps the kind of a generated policy (AuthPolicy, TokenRateLimitPolicy or
+	// RateLimitPolicy) to a Go template of its name, e.g. "{{.Namespace}}-{{.Name}}-auth". The
+	// template sees the model's .Name and .Namespace, the policy's .Kind, and .DefaultName, the
+	// name the controller would otherwise use (e.g. maas-auth-granite). Routes and EnvoyFilters
+	// keep their names, which other components look up.
+	// +optional
+	NameTemplates map[string]string `json:"nameTemplates,omitempty"`
+
+	// Labels are added to every generated route, policy and EnvoyFilter. The controller's own
+	// labels (app.kubernetes.io/managed-by, maas.opendatahub.io/model, ...) take precedence.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MaaSConfigSpec defines cluster-wide settings of the controller.
+type MaaSConfigSpec struct {
+	// GeneratedResources sets the names and labels of generated resources for every model. A
+	// MaaSModelRef's spec.generatedResources overrides it: its name templates replace these per
+	// kind, and its labels are merged over these.
+	// +optional
+	GeneratedResources *GeneratedResources `json:"generatedResources,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="MaaSConfig is a singleton and must be named 'default'"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MaaSConfig is a cluster-scoped singleton holding settings that apply to every model, such as
+// the names and labels of generated resources. Without it the controller uses its defaults.
+type MaaSConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MaaSConfigSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MaaSConfigList contains a list of MaaSConfig
+type MaaSConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaaSConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaaSConfig{}, &MaaSConfigList{})
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaaSModelCatalogSingletonName is the only accepted name for the cluster-scoped MaaSModelCatalog resource.
+const MaaSModelCatalogSingletonName = "default"
+
+// MaaSModelCatalogSpec defines the desired state of MaaSModelCatalog. It is intentionally empty;
+// the resource exists only to carry the aggregated status.
+type MaaSModelCatalogSpec struct{}
+
+// CatalogModel summarizes one MaaSModelRef.
+type CatalogModel struct {
+	// Name of the MaaSModelRef
+	Name string `json:"name"`
+	// Namespace of the MaaSModelRef
+	Namespace string `json:"namespace"`
+	// Kind is the backend kind of spec.modelRef, e.g. LLMInferenceService or ExternalModel
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Phase is the model's status.phase
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Endpoint is the model's status.endpoint
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+	// Tiers lists the MaaSSubscriptions that include the model, as namespace/name
+	// +optional
+	Tiers []string `json:"tiers,omitempty"`
+}
+
+// TierCoverage summarizes the models one MaaSSubscription includes.
+type TierCoverage struct {
+	// Name of the MaaSSubscription
+	Name string `json:"name"`
+	// Namespace of the MaaSSubscription
+	Namespace string `json:"namespace"`
+	// Phase is the subscription's status.phase
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// Models is the number of models the subscription includes that exist
+	Models int32 `json:"models"`
+	// ReadyModels is the number of those models in phase Ready
+	ReadyModels int32 `json:"readyModels"`
+	// MissingModels lists the model references without a MaaSModelRef, as namespace/name
+	// +optional
+	MissingModels []string `json:"missingModels,omitempty"`
+}
+
+// MaaSModelCatalogStatus defines the observed state of MaaSModelCatalog
+type MaaSModelCatalogStatus struct {
+	// Summary counts MaaSModelRefs by phase
+	// +optional
+	Summary ModelPhaseSummary `json:"summary,omitempty"`
+
+	// UncoveredModels is the number of models no MaaSSubscription includes, which no one can use
+	// +optional
+	UncoveredModels int32 `json:"uncoveredModels,omitempty"`
+
+	// Models lists every MaaSModelRef, sorted by namespace and name
+	// +optional
+	Models []CatalogModel `json:"models,omitempty"`
+
+	// Tiers lists every MaaSSubscription with the models it covers, sorted by namespace and name
+	// +optional
+	Tiers []TierCoverage `json:"tiers,omitempty"`
+
+	// LastUpdated is when the controller last changed this status
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="MaaSModelCatalog is a singleton and must be named 'default'"
+//+kubebuilder:printcolumn:name="Models",type="integer",JSONPath=".status.summary.total"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.summary.ready"
+//+kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.summary.failed"
+//+kubebuilder:printcolumn:name="Uncovered",type="integer",JSONPath=".status.uncoveredModels"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MaaSModelCatalog is a cluster-scoped singleton maintained by the controller that lists every
+// model with its phase, endpoint and the subscriptions covering it, so dashboards do not have to
+// join MaaSModelRefs, HTTPRoutes and backends themselves.
+type MaaSModelCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaaSModelCatalogSpec   `json:"spec,omitempty"`
+	Status MaaSModelCatalogStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MaaSModelCatalogList contains a list of MaaSModelCatalog
+type MaaSModelCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaaSModelCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaaSModelCatalog{}, &MaaSModelCatalogList{})
+}
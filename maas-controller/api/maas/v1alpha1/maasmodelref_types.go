@@ -21,14 +21,298 @@ import (
 )
 
 // MaaSModelSpec defines the desired state of MaaSModelRef
+// +kubebuilder:validation:XValidation:rule="!has(self.backends) || self.modelRef.kind == 'LLMInferenceService'",message="backends are only supported for kind LLMInferenceService"
 type MaaSModelSpec struct {
 	// ModelRef references the actual model endpoint
 	ModelRef ModelReference `json:"modelRef"`
+	// Class is the kind of model: chat, completion, embedding, reranker or audio. It selects the
+	// endpoint paths the controller routes to the model, the request schema maas-api validates on
+	// the shared route, and how usage is metered: embedding and reranker models are billed for input
+	// tokens only. When unset every path is routed and requests are not validated, as for models
+	// created before classes existed. Routes KServe owns are not restricted.
+	// +optional
+	// +kubebuilder:validation:Enum=chat;completion;embedding;reranker;audio
+	Class ModelClass `json:"class,omitempty"`
 	// EndpointOverride, when set, overrides the endpoint URL that the controller
 	// would otherwise discover from the backend (e.g. LLMInferenceService status
 	// or Gateway/HTTPRoute).
 	// +optional
 	EndpointOverride string `json:"endpointOverride,omitempty"`
+
+	// ParentRef marks this model as a variant (e.g. a fine-tune) of another MaaSModelRef.
+	// Subscriptions, auth policies and routing defaults that reference the parent also
+	// apply to this model unless they reference this model directly.
+	// +optional
+	ParentRef *ParentModelReference `json:"parentRef,omitempty"`
+
+	// PricingMultiplier scales the billing rate inherited from the parent, as a decimal
+	// string (e.g. "1.5"). When unset the parent's effective multiplier is used, or "1"
+	// for a model without a parent.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	PricingMultiplier string `json:"pricingMultiplier,omitempty"`
+
+	// ErrorResponses replaces the generic bodies of denials for this model, e.g. to point
+	// external customers at an upgrade page.
+	// +optional
+	ErrorResponses *ErrorResponses `json:"errorResponses,omitempty"`
+
+	// Routing overrides where the gateway serves this model. Only kinds whose HTTPRoute the
+	// controller generates (ExternalModel, MCPServer, and LLMInferenceService with Backends) support
+	// it; KServe owns the routes of other LLMInferenceService models.
+	// +optional
+	Routing *ModelRouting `json:"routing,omitempty"`
+
+	// Aliases are other names clients can use for the model, e.g. "llama3" for
+	// "llama3-70b-instruct-v2", so client code keeps working when an alias is moved to a new
+	// model. maas-api resolves them wherever a model is named, before authorization. The
+	// controller also serves each alias at /<alias> on the HTTPRoutes it generates. An alias must
+	// not be the name or alias of another model in the namespace.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	// +kubebuilder:validation:items:MaxLength=63
+	// +kubebuilder:validation:items:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Gateway attaches the model's HTTPRoute to this Gateway instead of the controller's
+	// --gateway-name and --gateway-namespace, so tenants can be served by gateways of their own.
+	// For a Gateway in another namespace, the controller checks that one of its listeners admits
+	// HTTPRoutes from the model's namespace and maintains a ReferenceGrant for the route.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+
+	// Backends splits the model's traffic between several LLMInferenceServices by weight, e.g. to
+	// send 10% of requests to a canary of a new model version. The controller then generates the
+	// model's HTTPRoute, served under Routing like ExternalModel's, instead of using the KServe route
+	// of ModelRef. ModelRef still names the service whose resources the status reports.
+	// Only supported for kind LLMInferenceService.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	Backends []WeightedBackend `json:"backends,omitempty"`
+
+	// Documentation links the model's external docs and example requests, served by maas-api at
+	// GET /v1/models/{name}/examples.
+	// +optional
+	Documentation *ModelDocumentation `json:"documentation,omitempty"`
+
+	// Tracing sets how requests for this model are traced, so a high-volume model need not
+	// carry the cost of tracing every request. The controller applies it to the model's routes
+	// on the gateway, and maas-api to the spans it records for the model.
+	// +optional
+	Tracing *ModelTracing `json:"tracing,omitempty"`
+
+	// RequestTimeout bounds how long the gateway waits for the model to finish a response,
+	// streamed or not, e.g. "10m". Without it the gateway's default applies, which can cut off
+	// long streamed completions. "0s" disables the timeout.
+	// +optional
+	RequestTimeout *metav1.Duration `json:"requestTimeout,omitempty"`
+
+	// StreamIdleTimeout bounds how long a response may go without sending data, e.g. "2m", so a
+	// stream the model stopped feeding is closed while long streams that keep flowing are not.
+	// Without it the gateway's stream idle timeout applies (5 minutes in Envoy). "0s" disables it.
+	// +optional
+	StreamIdleTimeout *metav1.Duration `json:"streamIdleTimeout,omitempty"`
+
+	// AuthorizationRule is a CEL expression every request for this model must satisfy, on top of
+	// MaaSAuthPolicies and subscriptions, e.g. `tier == "premium" || !("contractors" in groups)`.
+	// maas-api evaluates it with the variables user, groups, tier, model, path and time, and
+	// denies the request when it is false or fails to evaluate.
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	AuthorizationRule string `json:"authorizationRule,omitempty"`
+
+	// GeneratedResources overrides the MaaSConfig's names and labels of the resources generated
+	// for this model: its name templates replace the MaaSConfig's per kind, and its labels are
+	// merged over the MaaSConfig's.
+	// +optional
+	GeneratedResources *GeneratedResources `json:"generatedResources,omitempty"`
+
+	// MaintenanceWindows are recurring periods in which the model is in maintenance: maas-api denies
+	// requests to it with reason model_maintenance and a Retry-After until the window ends, while its
+	// routes and policies are kept. Set the maas.opendatahub.io/maintenance annotation to "true" for
+	// unplanned maintenance.
+	// +optional
+	// +kubebuilder:validation:MaxItems=16
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// ModelClass is the kind of model, which determines the endpoints it serves.
+type ModelClass string
+
+const (
+	// ModelClassChat serves /v1/chat/completions and /v1/responses.
+	ModelClassChat ModelClass = "chat"
+	// ModelClassCompletion serves /v1/completions.
+	ModelClassCompletion ModelClass = "completion"
+	// ModelClassEmbedding serves /v1/embeddings.
+	ModelClassEmbedding ModelClass = "embedding"
+	// ModelClassReranker serves /v1/rerank, /v2/rerank and /rerank.
+	ModelClassReranker ModelClass = "reranker"
+	// ModelClassAudio serves /v1/audio/transcriptions, /v1/audio/translations and /v1/audio/speech.
+	ModelClassAudio ModelClass = "audio"
+)
+
+// MaintenanceWindow is a recurring maintenance period.
+type MaintenanceWindow struct {
+	// Schedule is when the window starts, as a cron expression with five fields (minute, hour,
+	// day of month, month, day of week) evaluated in UTC, e.g. "0 2 * * 6" for Saturdays at 02:00.
+	// +kubebuilder:validation:MinLength=9
+	// +kubebuilder:validation:MaxLength=253
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window lasts, e.g. "2h". At most one week.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// MaintenanceStatus reports that a model is in maintenance.
+type MaintenanceStatus struct {
+	// Until is when the current maintenance window ends. Unset when the model is in maintenance
+	// through the maas.opendatahub.io/maintenance annotation, which has no planned end.
+	// +optional
+	Until *metav1.Time `json:"until,omitempty"`
+}
+
+// ModelTracing is the tracing policy of a model.
+type ModelTracing struct {
+	// SamplingPercentage is the percentage of the model's requests that are traced, as a decimal
+	// string from "0" to "100" (e.g. "0.5"). When unset the gateway's default sampling applies.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(100(\.0+)?|[0-9]{1,2}(\.[0-9]+)?)$`
+	SamplingPercentage string `json:"samplingPercentage,omitempty"`
+
+	// Propagate forwards the caller's trace context headers (traceparent, tracestate, B3) to the
+	// model. When false the gateway removes them, and the model's traces start at the gateway.
+	// Defaults to true.
+	// +optional
+	// +kubebuilder:default=true
+	Propagate *bool `json:"propagate,omitempty"`
+
+	// StripSensitiveAttributes leaves caller identity (user, groups, subscription, API key) out of
+	// the spans maas-api records for the model.
+	// +optional
+	StripSensitiveAttributes bool `json:"stripSensitiveAttributes,omitempty"`
+}
+
+// ModelDocumentation points new users of a model at its docs and at requests that work as-is.
+type ModelDocumentation struct {
+	// URL is the model's external documentation, e.g. the provider's model card.
+	// +optional
+	// +kubebuilder:validation:MaxLength=2048
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url,omitempty"`
+
+	// Examples are sample requests. When empty, maas-api offers a chat completion with the model's name.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	Examples []ModelExample `json:"examples,omitempty"`
+}
+
+// ModelExample is a sample request to the model.
+type ModelExample struct {
+	// Name identifies the example, e.g. "chat" or "tool-calling".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Description says what the example shows.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	Description string `json:"description,omitempty"`
+
+	// Path is appended to the model's endpoint, e.g. /v1/chat/completions.
+	// +kubebuilder:validation:Pattern=`^/[A-Za-z0-9._~/-]*$`
+	// +kubebuilder:validation:MaxLength=253
+	Path string `json:"path"`
+
+	// Body is the JSON request body.
+	// +optional
+	// +kubebuilder:validation:MaxLength=16384
+	Body string `json:"body,omitempty"`
+}
+
+// WeightedBackend is an LLMInferenceService receiving a share of a model's traffic.
+type WeightedBackend struct {
+	// Name is the LLMInferenceService name, in the model's namespace.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// Weight is the backend's share of requests relative to the other backends' weights.
+	// A backend with weight 0 receives no traffic and need not be ready.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000000
+	Weight int32 `json:"weight"`
+}
+
+// ModelRouting sets the path prefix and hostname of a model's HTTPRoute. When a field is unset,
+// the maas.opendatahub.io/path-prefix or maas.opendatahub.io/hostname annotation is used.
+type ModelRouting struct {
+	// PathPrefix is matched by the route and stripped before the request reaches the backend.
+	// Defaults to /<model name>, or / when Hostname is set.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^/[A-Za-z0-9._~/-]*$`
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// Hostname dedicates a hostname to the model. The gateway must have a listener that accepts it.
+	// +optional
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// GatewayReference names a Gateway, possibly in another namespace.
+type GatewayReference struct {
+	// Name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the controller's --gateway-namespace.
+	// +optional
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// ErrorResponses holds the custom denial bodies of a model. They are returned in the OpenAI
+// error format, {"error": {"message", "type", "url"}}, with the denial reason as the type.
+type ErrorResponses struct {
+	// Forbidden is returned when the caller is not allowed to use the model
+	// (reasons unauthorized and access_denied).
+	// +optional
+	Forbidden *ErrorResponse `json:"forbidden,omitempty"`
+
+	// NotFound is returned when none of the caller's subscriptions includes the model
+	// (reasons not_found and model_not_in_subscription).
+	// +optional
+	NotFound *ErrorResponse `json:"notFound,omitempty"`
+}
+
+// ErrorResponse is a custom denial body.
+type ErrorResponse struct {
+	// Message is shown to the caller, e.g. "Upgrade your plan to use this model".
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:Pattern=`^[^"'\\]*$`
+	Message string `json:"message"`
+
+	// URL is where the caller can get access.
+	// +optional
+	// +kubebuilder:validation:MaxLength=2048
+	// +kubebuilder:validation:Pattern=`^[^"'\\]*$`
+	URL string `json:"url,omitempty"`
+}
+
+// ParentModelReference references the MaaSModelRef a variant is derived from.
+type ParentModelReference struct {
+	// Name is the name of the parent MaaSModelRef
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the parent MaaSModelRef. Defaults to this model's namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 }
 
 // CredentialReference references a Kubernetes Secret with provider API credentials.
@@ -46,23 +330,50 @@ type ModelReference struct {
 	// Kind determines which backend handles this model reference.
 	// LLMInferenceService: references a KServe LLMInferenceService.
 	// ExternalModel: references an ExternalModel CR containing provider config.
-	// +kubebuilder:validation:Enum=LLMInferenceService;ExternalModel
+	// MCPServer: references a Service serving the Model Context Protocol over streamable HTTP.
+	// +kubebuilder:validation:Enum=LLMInferenceService;ExternalModel;MCPServer
 	Kind string `json:"kind"`
 
 	// Name is the name of the model resource.
 	// For LLMInferenceService, this is the InferenceService name.
 	// For ExternalModel, this is the ExternalModel CR name.
+	// For MCPServer, this is the Service name.
 	// +kubebuilder:validation:MinLength=1
 	// +kubebuilder:validation:MaxLength=253
 	Name string `json:"name"`
 }
 
+// ModelResources describes the per-replica compute footprint of a model backend
+type ModelResources struct {
+	// GPUType is the accelerator product pinned by the pod nodeSelector (e.g. "NVIDIA-A100-SXM4-80GB"),
+	// or the extended resource name (e.g. "nvidia.com/gpu") when no product is pinned
+	// +optional
+	GPUType string `json:"gpuType,omitempty"`
+
+	// GPUCount is the number of GPUs requested per replica
+	// +optional
+	GPUCount int64 `json:"gpuCount,omitempty"`
+
+	// Memory is the memory requested per replica
+	// +optional
+	Memory string `json:"memory,omitempty"`
+
+	// Replicas is the configured replica count of the backend workload
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+}
+
 // MaaSModelStatus defines the observed state of MaaSModelRef
 type MaaSModelStatus struct {
 	// Phase represents the current phase of the model
-	// +kubebuilder:validation:Enum=Pending;Ready;Unhealthy;Failed
+	// +kubebuilder:validation:Enum=Pending;Ready;Unhealthy;Failed;SoftDeleted
 	Phase string `json:"phase,omitempty"`
 
+	// SoftDeletedAt is when the controller first saw the maas.opendatahub.io/soft-deleted
+	// annotation. The model is deleted once the controller's grace period has passed since then.
+	// +optional
+	SoftDeletedAt *metav1.Time `json:"softDeletedAt,omitempty"`
+
 	// Endpoint is the endpoint URL for the model
 	// +optional
 	Endpoint string `json:"endpoint,omitempty"`
@@ -87,6 +398,28 @@ type MaaSModelStatus struct {
 	// +optional
 	HTTPRouteHostnames []string `json:"httpRouteHostnames,omitempty"`
 
+	// Resources summarizes the accelerators and memory requested by the backend workload.
+	// Only populated for kinds whose backend exposes a pod template (LLMInferenceService).
+	// +optional
+	Resources *ModelResources `json:"resources,omitempty"`
+
+	// Lineage lists the ancestors of this model as namespace/name, from the direct parent
+	// up to the base model. Empty for base models.
+	// +optional
+	Lineage []string `json:"lineage,omitempty"`
+
+	// EffectivePricingMultiplier is spec.pricingMultiplier, or the nearest ancestor's when unset.
+	// +optional
+	EffectivePricingMultiplier string `json:"effectivePricingMultiplier,omitempty"`
+
+	// Maintenance is set while the model is in maintenance. maas-api denies requests to it then.
+	// +optional
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"`
+
+	// NextMaintenance is when the next maintenance window of spec.maintenanceWindows starts.
+	// +optional
+	NextMaintenance *metav1.Time `json:"nextMaintenance,omitempty"`
+
 	// Conditions represent the latest available observations of the model's state
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -98,6 +431,7 @@ type MaaSModelStatus struct {
 //+kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".status.endpoint"
 //+kubebuilder:printcolumn:name="HTTPRoute",type="string",JSONPath=".status.httpRouteName"
 //+kubebuilder:printcolumn:name="Gateway",type="string",JSONPath=".status.httpRouteGatewayName"
+//+kubebuilder:printcolumn:name="Parent",type="string",JSONPath=".spec.parentRef.name",priority=1
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // MaaSModelRef is the Schema for the maasmodelrefs API
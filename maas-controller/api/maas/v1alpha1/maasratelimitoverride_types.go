@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaaSRateLimitOverrideSpec defines the limits an organization negotiated for a model.
+// +kubebuilder:validation:XValidation:rule="(has(self.tokenRateLimits) && size(self.tokenRateLimits) > 0) || (has(self.requestRateLimits) && size(self.requestRateLimits) > 0)",message="at least one token or request rate limit must be specified"
+type MaaSRateLimitOverrideSpec struct {
+	// OrganizationID selects the MaaSSubscriptions in this namespace whose
+	// spec.tokenMetadata.organizationId matches.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	OrganizationID string `json:"organizationId"`
+
+	// Model is the model the limits apply to, as listed in the subscriptions' spec.modelRefs.
+	// Variants covered through that entry get the same limits.
+	Model ModelRef `json:"model"`
+
+	// TokenRateLimits replace the subscriptions' token rate limits for the model
+	// +optional
+	TokenRateLimits []TokenRateLimit `json:"tokenRateLimits,omitempty"`
+
+	// RequestRateLimits replace the subscriptions' request rate limits for the model
+	// +optional
+	RequestRateLimits []RequestRateLimit `json:"requestRateLimits,omitempty"`
+}
+
+// MaaSRateLimitOverrideStatus defines the observed state of MaaSRateLimitOverride
+type MaaSRateLimitOverrideStatus struct {
+	// Phase is Active when the override applies to at least one subscription, Unmatched when no
+	// subscription of the organization includes the model, and Superseded when an older override
+	// targets the same organization and model.
+	// +kubebuilder:validation:Enum=Active;Unmatched;Superseded
+	Phase string `json:"phase,omitempty"`
+
+	// Subscriptions are the subscriptions (namespace/name) whose limits the override replaces
+	// +optional
+	Subscriptions []string `json:"subscriptions,omitempty"`
+
+	// Conditions represent the latest available observations of the override's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Organization",type="string",JSONPath=".spec.organizationId"
+//+kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.model.name"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MaaSRateLimitOverride replaces the tier limits of one organization's subscriptions on one model,
+// for limits negotiated outside the tiers. It applies to the MaaSSubscriptions in its namespace.
+type MaaSRateLimitOverride struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaaSRateLimitOverrideSpec   `json:"spec,omitempty"`
+	Status MaaSRateLimitOverrideStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MaaSRateLimitOverrideList contains a list of MaaSRateLimitOverride
+type MaaSRateLimitOverrideList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaaSRateLimitOverride `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaaSRateLimitOverride{}, &MaaSRateLimitOverrideList{})
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaaSStatusSingletonName is the only accepted name for the cluster-scoped MaaSStatus resource.
+const MaaSStatusSingletonName = "default"
+
+// MaaSStatusSpec defines the desired state of MaaSStatus. It is intentionally empty;
+// the resource exists only to carry the aggregated status.
+type MaaSStatusSpec struct{}
+
+// ModelPhaseSummary counts MaaSModelRefs by status.phase.
+type ModelPhaseSummary struct {
+	// Total is the number of MaaSModelRefs in the cluster
+	Total int32 `json:"total"`
+	// Ready is the number of models in phase Ready
+	Ready int32 `json:"ready"`
+	// Pending is the number of models in phase Pending or without a phase yet
+	Pending int32 `json:"pending"`
+	// Unhealthy is the number of models in phase Unhealthy
+	Unhealthy int32 `json:"unhealthy"`
+	// Failed is the number of models in phase Failed
+	Failed int32 `json:"failed"`
+}
+
+// PolicyError identifies a MaaSAuthPolicy or MaaSSubscription that is not Active.
+type PolicyError struct {
+	// Kind is MaaSAuthPolicy or MaaSSubscription
+	Kind string `json:"kind"`
+	// Name of the resource
+	Name string `json:"name"`
+	// Namespace of the resource
+	Namespace string `json:"namespace"`
+	// Phase is the resource's status.phase
+	Phase string `json:"phase,omitempty"`
+	// Message is the Ready condition message
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ComponentHealth reports whether a dependency of the MaaS installation is healthy.
+type ComponentHealth struct {
+	// Name of the component resource
+	Name string `json:"name"`
+	// Namespace of the component resource
+	Namespace string `json:"namespace"`
+	// Healthy is true when the component reports itself ready
+	Healthy bool `json:"healthy"`
+	// Message explains the health state
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// MaaSStatusStatus defines the observed state of MaaSStatus
+type MaaSStatusStatus struct {
+	// Phase summarizes the installation: Healthy when all checks pass, Degraded otherwise
+	// +kubebuilder:validation:Enum=Healthy;Degraded
+	Phase string `json:"phase,omitempty"`
+
+	// Models counts MaaSModelRefs by phase
+	// +optional
+	Models ModelPhaseSummary `json:"models,omitempty"`
+
+	// PolicyErrors lists MaaSAuthPolicies and MaaSSubscriptions that are not Active
+	// +optional
+	PolicyErrors []PolicyError `json:"policyErrors,omitempty"`
+
+	// Gateway reports whether the MaaS Gateway is programmed
+	// +optional
+	Gateway *ComponentHealth `json:"gateway,omitempty"`
+
+	// MaaSAPI reports whether the maas-api Deployment is available
+	// +optional
+	MaaSAPI *ComponentHealth `json:"maasAPI,omitempty"`
+
+	// Authorino reports whether Authorino accepted the AuthConfigs of the MaaS policies.
+	// Set only when the controller runs with --status-check-authorino.
+	// +optional
+	Authorino *ComponentHealth `json:"authorino,omitempty"`
+
+	// Limitador reports whether the Limitador instance is ready.
+	// Set only when the controller runs with --status-check-limitador.
+	// +optional
+	Limitador *ComponentHealth `json:"limitador,omitempty"`
+
+	// ControllerVersion is the version of the running maas-controller
+	// +optional
+	ControllerVersion string `json:"controllerVersion,omitempty"`
+
+	// LastUpdated is when the controller last refreshed this status
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+
+	// Conditions represent the latest available observations of the installation's state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:validation:XValidation:rule="self.metadata.name == 'default'",message="MaaSStatus is a singleton and must be named 'default'"
+//+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+//+kubebuilder:printcolumn:name="Models",type="integer",JSONPath=".status.models.total"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.models.ready"
+//+kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.controllerVersion"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// MaaSStatus is a cluster-scoped singleton maintained by the controller that summarizes
+// the health of the whole MaaS installation.
+type MaaSStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MaaSStatusSpec   `json:"spec,omitempty"`
+	Status MaaSStatusStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// MaaSStatusList contains a list of MaaSStatus
+type MaaSStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MaaSStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MaaSStatus{}, &MaaSStatusList{})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,6 +39,90 @@ type MaaSSubscriptionSpec struct {
 	// +optional
 	// +kubebuilder:default=0
 	Priority int32 `json:"priority,omitempty"`
+
+	// ExpiresAt is when the subscription stops granting access. Once it passes, the subscription
+	// is left out of subscription selection and of the generated TokenRateLimitPolicies, and its
+	// phase becomes Expired. Unset subscriptions do not expire.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// MaxTokensPerDay caps the tokens all users of the subscription may consume together, across
+	// its models, per UTC day. maas-api counts the usage the gateway reports, denies requests once
+	// the cap is reached, and the subscription is marked QuotaExceeded until midnight UTC.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxTokensPerDay *int64 `json:"maxTokensPerDay,omitempty"`
+
+	// Sandbox routes this subscription's requests to a mock backend that returns canned
+	// OpenAI-shaped responses, so developers can integrate without using model capacity.
+	// The controller deploys the backend when started with --sandbox-image.
+	// +optional
+	Sandbox bool `json:"sandbox,omitempty"`
+
+	// Listener binds the subscription to a listener of the MaaS gateway, separating its traffic
+	// from other tiers'. Requests under the subscription must be sent to one of the listener's
+	// hostnames, and requests sent there may only use subscriptions bound to them.
+	// +optional
+	Listener *SubscriptionListener `json:"listener,omitempty"`
+
+	// Attachments limits the images, audio and files requests under the subscription may carry to
+	// multimodal models. maas-api's ext_authz evaluator enforces it on the request body, which the
+	// gateway forwards for the models of subscriptions that set it.
+	// +optional
+	Attachments *AttachmentPolicy `json:"attachments,omitempty"`
+
+	// Endpoints restricts requests under the subscription to these API suffixes, out of those
+	// maas-api allows (e.g., ["/v1/embeddings"] for an embeddings-only tier). GET /v1/models is
+	// always allowed. Unset allows every suffix maas-api allows.
+	// +kubebuilder:validation:items:Pattern=`^/`
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	// DowngradeGracePeriod is how long users and groups removed from spec.owner keep access to the
+	// subscription (e.g., "72h"), so the API keys they bound to it keep working at its limits while
+	// their integrations move to another subscription. maas-api warns on their requests until the
+	// period ends. Unset revokes access immediately.
+	// +optional
+	DowngradeGracePeriod *metav1.Duration `json:"downgradeGracePeriod,omitempty"`
+}
+
+// AttachmentPolicy limits the attachments of a request: the image, audio and file content parts
+// of chat messages and of Responses API input.
+type AttachmentPolicy struct {
+	// MaxCount is the most attachments a request may carry. 0 allows none.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxCount *int32 `json:"maxCount,omitempty"`
+
+	// MaxSize is the largest size of an attachment sent inline as base64 data, once decoded
+	// (e.g., "5Mi"). Attachments referenced by URL are not downloaded, so their size is not checked.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// MaxImageDimension is the longest side, in pixels, of an inline PNG, JPEG or GIF image.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxImageDimension *int32 `json:"maxImageDimension,omitempty"`
+
+	// AllowedTypes are the MIME types attachments may have (e.g., "image/png"). A type ending in
+	// "/*" allows every subtype. When set, attachments whose type cannot be determined are denied.
+	// Empty allows any type.
+	// +optional
+	AllowedTypes []string `json:"allowedTypes,omitempty"`
+}
+
+// SubscriptionListener names a gateway listener and the hostnames it serves.
+type SubscriptionListener struct {
+	// Name is the listener's name on the MaaS gateway. The HTTPRoutes maas-controller generates
+	// for the subscription's models attach to it through their parentRef sectionName.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+
+	// Hostnames are the hostnames the listener serves (e.g., "premium.models.example.com").
+	// A leading "*." matches any single-label subdomain, as in Gateway API.
+	// +kubebuilder:validation:MinItems=1
+	Hostnames []string `json:"hostnames"`
 }
 
 // OwnerSpec defines the owner of the subscription
@@ -71,9 +156,27 @@ type ModelSubscriptionRef struct {
 	// +optional
 	TokenRateLimitRef *string `json:"tokenRateLimitRef,omitempty"`
 
+	// RequestRateLimits defines request-based rate limits for this model. maas-api returns them,
+	// normalized to requests per minute, in the subscription selection so Authorino can pass them
+	// to Limitador.
+	// +optional
+	RequestRateLimits []RequestRateLimit `json:"requestRateLimits,omitempty"`
+
 	// BillingRate defines the cost per token
 	// +optional
 	BillingRate *BillingRate `json:"billingRate,omitempty"`
+
+	// TokenBudget caps the tokens each user may consume of this model per period. maas-api counts
+	// the usage the gateway reports and denies requests once the budget is spent.
+	// +optional
+	TokenBudget *TokenBudget `json:"tokenBudget,omitempty"`
+
+	// MaxConcurrentRequests caps the requests each user may have in progress on this model at
+	// once. LLM requests are long-lived, so rate limits alone do not bound the GPU capacity a
+	// user holds. maas-api's ext_authz evaluator enforces it with ENFORCE_CONCURRENCY_LIMITS.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentRequests *int32 `json:"maxConcurrentRequests,omitempty"`
 }
 
 // TokenRateLimit defines a token rate limit
@@ -86,6 +189,28 @@ type TokenRateLimit struct {
 	Window string `json:"window"`
 }
 
+// RequestRateLimit defines a request rate limit
+type RequestRateLimit struct {
+	// Limit is the maximum number of requests allowed
+	Limit int64 `json:"limit"`
+
+	// Window is the time window (e.g., "1m", "1h", "24h")
+	// +kubebuilder:validation:Pattern=`^(\d+)(s|m|h|d)$`
+	Window string `json:"window"`
+}
+
+// TokenBudget defines a per-user token budget
+type TokenBudget struct {
+	// Limit is the number of tokens each user may consume per period
+	// +kubebuilder:validation:Minimum=1
+	Limit int64 `json:"limit"`
+
+	// Period is the budget window (e.g., "24h", "7d", "30d"). Windows are aligned to the Unix
+	// epoch, so a "1d" budget resets at midnight UTC.
+	// +kubebuilder:validation:Pattern=`^([1-9]\d*)(h|d)$`
+	Period string `json:"period"`
+}
+
 // BillingRate defines billing information
 type BillingRate struct {
 	// PerToken is the cost per token
@@ -110,18 +235,46 @@ type TokenMetadata struct {
 // MaaSSubscriptionStatus defines the observed state of MaaSSubscription
 type MaaSSubscriptionStatus struct {
 	// Phase represents the current phase of the subscription
-	// +kubebuilder:validation:Enum=Pending;Active;Failed
+	// +kubebuilder:validation:Enum=Pending;Active;Failed;Expired
 	Phase string `json:"phase,omitempty"`
 
 	// Conditions represent the latest available observations of the subscription's state
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedOwner is the spec.owner last reconciled, recorded while spec.downgradeGracePeriod
+	// is set so that removed users and groups can be detected.
+	// +optional
+	ObservedOwner *OwnerSpec `json:"observedOwner,omitempty"`
+
+	// RevokedOwners are the users and groups removed from spec.owner whose grace period has not
+	// ended yet. maas-api keeps selecting the subscription for them until GraceEndsAt.
+	// +optional
+	RevokedOwners []RevokedOwner `json:"revokedOwners,omitempty"`
+}
+
+// RevokedOwner is a user or group removed from a subscription's owners.
+type RevokedOwner struct {
+	// Group is the name of the removed group. Exactly one of Group and User is set.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// User is the name of the removed user.
+	// +optional
+	User string `json:"user,omitempty"`
+
+	// RevokedAt is when the controller observed the removal.
+	RevokedAt metav1.Time `json:"revokedAt"`
+
+	// GraceEndsAt is when the user or group loses access to the subscription.
+	GraceEndsAt metav1.Time `json:"graceEndsAt"`
 }
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 //+kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+//+kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".spec.expiresAt"
 //+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // MaaSSubscription is the Schema for the maassubscriptions API
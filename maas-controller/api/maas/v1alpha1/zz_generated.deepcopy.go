@@ -9,6 +9,41 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AttachmentPolicy) DeepCopyInto(out *AttachmentPolicy) {
+	*out = *in
+	if in.MaxCount != nil {
+		in, out := &in.MaxCount, &out.MaxCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxImageDimension != nil {
+		in, out := &in.MaxImageDimension, &out.MaxImageDimension
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AllowedTypes != nil {
+		in, out := &in.AllowedTypes, &out.AllowedTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AttachmentPolicy.
+func (in *AttachmentPolicy) DeepCopy() *AttachmentPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AttachmentPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthPolicyRefStatus) DeepCopyInto(out *AuthPolicyRefStatus) {
 	*out = *in
@@ -39,6 +74,41 @@ func (in *BillingRate) DeepCopy() *BillingRate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogModel) DeepCopyInto(out *CatalogModel) {
+	*out = *in
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogModel.
+func (in *CatalogModel) DeepCopy() *CatalogModel {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentHealth) DeepCopyInto(out *ComponentHealth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentHealth.
+func (in *ComponentHealth) DeepCopy() *ComponentHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CredentialReference) DeepCopyInto(out *CredentialReference) {
 	*out = *in
@@ -54,12 +124,52 @@ func (in *CredentialReference) DeepCopy() *CredentialReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorResponse) DeepCopyInto(out *ErrorResponse) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorResponse.
+func (in *ErrorResponse) DeepCopy() *ErrorResponse {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorResponse)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ErrorResponses) DeepCopyInto(out *ErrorResponses) {
+	*out = *in
+	if in.Forbidden != nil {
+		in, out := &in.Forbidden, &out.Forbidden
+		*out = new(ErrorResponse)
+		**out = **in
+	}
+	if in.NotFound != nil {
+		in, out := &in.NotFound, &out.NotFound
+		*out = new(ErrorResponse)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ErrorResponses.
+func (in *ErrorResponses) DeepCopy() *ErrorResponses {
+	if in == nil {
+		return nil
+	}
+	out := new(ErrorResponses)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalModel) DeepCopyInto(out *ExternalModel) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -81,6 +191,56 @@ func (in *ExternalModel) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalModelAuth) DeepCopyInto(out *ExternalModelAuth) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(CredentialReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalModelAuth.
+func (in *ExternalModelAuth) DeepCopy() *ExternalModelAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalModelAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalModelHealthCheck) DeepCopyInto(out *ExternalModelHealthCheck) {
+	*out = *in
+	if in.ExpectedStatusCodes != nil {
+		in, out := &in.ExpectedStatusCodes, &out.ExpectedStatusCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalModelHealthCheck.
+func (in *ExternalModelHealthCheck) DeepCopy() *ExternalModelHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalModelHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalModelList) DeepCopyInto(out *ExternalModelList) {
 	*out = *in
@@ -117,6 +277,26 @@ func (in *ExternalModelList) DeepCopyObject() runtime.Object {
 func (in *ExternalModelSpec) DeepCopyInto(out *ExternalModelSpec) {
 	*out = *in
 	out.CredentialRef = in.CredentialRef
+	if in.CACertificateRef != nil {
+		in, out := &in.CACertificateRef, &out.CACertificateRef
+		*out = new(CredentialReference)
+		**out = **in
+	}
+	if in.VaultRef != nil {
+		in, out := &in.VaultRef, &out.VaultRef
+		*out = new(VaultCredentialReference)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ExternalModelHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(ExternalModelAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalModelSpec.
@@ -151,6 +331,35 @@ func (in *ExternalModelStatus) DeepCopy() *ExternalModelStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedResources) DeepCopyInto(out *GeneratedResources) {
+	*out = *in
+	if in.NameTemplates != nil {
+		in, out := &in.NameTemplates, &out.NameTemplates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedResources.
+func (in *GeneratedResources) DeepCopy() *GeneratedResources {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupReference) DeepCopyInto(out *GroupReference) {
 	*out = *in
@@ -166,6 +375,21 @@ func (in *GroupReference) DeepCopy() *GroupReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayReference.
+func (in *GatewayReference) DeepCopy() *GatewayReference {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaaSAuthPolicy) DeepCopyInto(out *MaaSAuthPolicy) {
 	*out = *in
@@ -279,26 +503,25 @@ func (in *MaaSAuthPolicyStatus) DeepCopy() *MaaSAuthPolicyStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSModelRef) DeepCopyInto(out *MaaSModelRef) {
+func (in *MaaSConfig) DeepCopyInto(out *MaaSConfig) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	in.Status.DeepCopyInto(&out.Status)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelRef.
-func (in *MaaSModelRef) DeepCopy() *MaaSModelRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSConfig.
+func (in *MaaSConfig) DeepCopy() *MaaSConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSModelRef)
+	out := new(MaaSConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MaaSModelRef) DeepCopyObject() runtime.Object {
+func (in *MaaSConfig) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -306,31 +529,31 @@ func (in *MaaSModelRef) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSModelRefList) DeepCopyInto(out *MaaSModelRefList) {
+func (in *MaaSConfigList) DeepCopyInto(out *MaaSConfigList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MaaSModelRef, len(*in))
+		*out = make([]MaaSConfig, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelRefList.
-func (in *MaaSModelRefList) DeepCopy() *MaaSModelRefList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSConfigList.
+func (in *MaaSConfigList) DeepCopy() *MaaSConfigList {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSModelRefList)
+	out := new(MaaSConfigList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MaaSModelRefList) DeepCopyObject() runtime.Object {
+func (in *MaaSConfigList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -338,69 +561,46 @@ func (in *MaaSModelRefList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSModelSpec) DeepCopyInto(out *MaaSModelSpec) {
-	*out = *in
-	out.ModelRef = in.ModelRef
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelSpec.
-func (in *MaaSModelSpec) DeepCopy() *MaaSModelSpec {
-	if in == nil {
-		return nil
-	}
-	out := new(MaaSModelSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSModelStatus) DeepCopyInto(out *MaaSModelStatus) {
+func (in *MaaSConfigSpec) DeepCopyInto(out *MaaSConfigSpec) {
 	*out = *in
-	if in.HTTPRouteHostnames != nil {
-		in, out := &in.HTTPRouteHostnames, &out.HTTPRouteHostnames
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.GeneratedResources != nil {
+		in, out := &in.GeneratedResources, &out.GeneratedResources
+		*out = new(GeneratedResources)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelStatus.
-func (in *MaaSModelStatus) DeepCopy() *MaaSModelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSConfigSpec.
+func (in *MaaSConfigSpec) DeepCopy() *MaaSConfigSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSModelStatus)
+	out := new(MaaSConfigSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSSubscription) DeepCopyInto(out *MaaSSubscription) {
+func (in *MaaSModelCatalog) DeepCopyInto(out *MaaSModelCatalog) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
+	out.Spec = in.Spec
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscription.
-func (in *MaaSSubscription) DeepCopy() *MaaSSubscription {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelCatalog.
+func (in *MaaSModelCatalog) DeepCopy() *MaaSModelCatalog {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSSubscription)
+	out := new(MaaSModelCatalog)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MaaSSubscription) DeepCopyObject() runtime.Object {
+func (in *MaaSModelCatalog) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -408,31 +608,31 @@ func (in *MaaSSubscription) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSSubscriptionList) DeepCopyInto(out *MaaSSubscriptionList) {
+func (in *MaaSModelCatalogList) DeepCopyInto(out *MaaSModelCatalogList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MaaSSubscription, len(*in))
+		*out = make([]MaaSModelCatalog, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscriptionList.
-func (in *MaaSSubscriptionList) DeepCopy() *MaaSSubscriptionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelCatalogList.
+func (in *MaaSModelCatalogList) DeepCopy() *MaaSModelCatalogList {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSSubscriptionList)
+	out := new(MaaSModelCatalogList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MaaSSubscriptionList) DeepCopyObject() runtime.Object {
+func (in *MaaSModelCatalogList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -440,82 +640,739 @@ func (in *MaaSSubscriptionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSSubscriptionSpec) DeepCopyInto(out *MaaSSubscriptionSpec) {
+func (in *MaaSModelCatalogSpec) DeepCopyInto(out *MaaSModelCatalogSpec) {
 	*out = *in
-	in.Owner.DeepCopyInto(&out.Owner)
-	if in.ModelRefs != nil {
-		in, out := &in.ModelRefs, &out.ModelRefs
-		*out = make([]ModelSubscriptionRef, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.TokenMetadata != nil {
-		in, out := &in.TokenMetadata, &out.TokenMetadata
-		*out = new(TokenMetadata)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscriptionSpec.
-func (in *MaaSSubscriptionSpec) DeepCopy() *MaaSSubscriptionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelCatalogSpec.
+func (in *MaaSModelCatalogSpec) DeepCopy() *MaaSModelCatalogSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSSubscriptionSpec)
+	out := new(MaaSModelCatalogSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaaSSubscriptionStatus) DeepCopyInto(out *MaaSSubscriptionStatus) {
+func (in *MaaSModelCatalogStatus) DeepCopyInto(out *MaaSModelCatalogStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	out.Summary = in.Summary
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]CatalogModel, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Tiers != nil {
+		in, out := &in.Tiers, &out.Tiers
+		*out = make([]TierCoverage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscriptionStatus.
-func (in *MaaSSubscriptionStatus) DeepCopy() *MaaSSubscriptionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelCatalogStatus.
+func (in *MaaSModelCatalogStatus) DeepCopy() *MaaSModelCatalogStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MaaSSubscriptionStatus)
+	out := new(MaaSModelCatalogStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MeteringMetadata) DeepCopyInto(out *MeteringMetadata) {
+func (in *MaaSModelRef) DeepCopyInto(out *MaaSModelRef) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeteringMetadata.
-func (in *MeteringMetadata) DeepCopy() *MeteringMetadata {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelRef.
+func (in *MaaSModelRef) DeepCopy() *MaaSModelRef {
 	if in == nil {
 		return nil
 	}
-	out := new(MeteringMetadata)
+	out := new(MaaSModelRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelRef) DeepCopyInto(out *ModelRef) {
-	*out = *in
-}
-
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSModelRef) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSModelRefList) DeepCopyInto(out *MaaSModelRefList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaaSModelRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelRefList.
+func (in *MaaSModelRefList) DeepCopy() *MaaSModelRefList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSModelRefList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSModelRefList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSModelSpec) DeepCopyInto(out *MaaSModelSpec) {
+	*out = *in
+	out.ModelRef = in.ModelRef
+	if in.ParentRef != nil {
+		in, out := &in.ParentRef, &out.ParentRef
+		*out = new(ParentModelReference)
+		**out = **in
+	}
+	if in.ErrorResponses != nil {
+		in, out := &in.ErrorResponses, &out.ErrorResponses
+		*out = new(ErrorResponses)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = new(ModelRouting)
+		**out = **in
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(GatewayReference)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]WeightedBackend, len(*in))
+		copy(*out, *in)
+	}
+	if in.Documentation != nil {
+		in, out := &in.Documentation, &out.Documentation
+		*out = new(ModelDocumentation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tracing != nil {
+		in, out := &in.Tracing, &out.Tracing
+		*out = new(ModelTracing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestTimeout != nil {
+		in, out := &in.RequestTimeout, &out.RequestTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.StreamIdleTimeout != nil {
+		in, out := &in.StreamIdleTimeout, &out.StreamIdleTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.GeneratedResources != nil {
+		in, out := &in.GeneratedResources, &out.GeneratedResources
+		*out = new(GeneratedResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelSpec.
+func (in *MaaSModelSpec) DeepCopy() *MaaSModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSModelStatus) DeepCopyInto(out *MaaSModelStatus) {
+	*out = *in
+	if in.SoftDeletedAt != nil {
+		in, out := &in.SoftDeletedAt, &out.SoftDeletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.HTTPRouteHostnames != nil {
+		in, out := &in.HTTPRouteHostnames, &out.HTTPRouteHostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(ModelResources)
+		**out = **in
+	}
+	if in.Lineage != nil {
+		in, out := &in.Lineage, &out.Lineage
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NextMaintenance != nil {
+		in, out := &in.NextMaintenance, &out.NextMaintenance
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSModelStatus.
+func (in *MaaSModelStatus) DeepCopy() *MaaSModelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSModelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSRateLimitOverride) DeepCopyInto(out *MaaSRateLimitOverride) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSRateLimitOverride.
+func (in *MaaSRateLimitOverride) DeepCopy() *MaaSRateLimitOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSRateLimitOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSRateLimitOverride) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSRateLimitOverrideList) DeepCopyInto(out *MaaSRateLimitOverrideList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaaSRateLimitOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSRateLimitOverrideList.
+func (in *MaaSRateLimitOverrideList) DeepCopy() *MaaSRateLimitOverrideList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSRateLimitOverrideList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSRateLimitOverrideList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSRateLimitOverrideSpec) DeepCopyInto(out *MaaSRateLimitOverrideSpec) {
+	*out = *in
+	out.Model = in.Model
+	if in.TokenRateLimits != nil {
+		in, out := &in.TokenRateLimits, &out.TokenRateLimits
+		*out = make([]TokenRateLimit, len(*in))
+		copy(*out, *in)
+	}
+	if in.RequestRateLimits != nil {
+		in, out := &in.RequestRateLimits, &out.RequestRateLimits
+		*out = make([]RequestRateLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSRateLimitOverrideSpec.
+func (in *MaaSRateLimitOverrideSpec) DeepCopy() *MaaSRateLimitOverrideSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSRateLimitOverrideSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSRateLimitOverrideStatus) DeepCopyInto(out *MaaSRateLimitOverrideStatus) {
+	*out = *in
+	if in.Subscriptions != nil {
+		in, out := &in.Subscriptions, &out.Subscriptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSRateLimitOverrideStatus.
+func (in *MaaSRateLimitOverrideStatus) DeepCopy() *MaaSRateLimitOverrideStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSRateLimitOverrideStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSStatus) DeepCopyInto(out *MaaSStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSStatus.
+func (in *MaaSStatus) DeepCopy() *MaaSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSStatusList) DeepCopyInto(out *MaaSStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaaSStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSStatusList.
+func (in *MaaSStatusList) DeepCopy() *MaaSStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSStatusSpec) DeepCopyInto(out *MaaSStatusSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSStatusSpec.
+func (in *MaaSStatusSpec) DeepCopy() *MaaSStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSStatusStatus) DeepCopyInto(out *MaaSStatusStatus) {
+	*out = *in
+	out.Models = in.Models
+	if in.PolicyErrors != nil {
+		in, out := &in.PolicyErrors, &out.PolicyErrors
+		*out = make([]PolicyError, len(*in))
+		copy(*out, *in)
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(ComponentHealth)
+		**out = **in
+	}
+	if in.MaaSAPI != nil {
+		in, out := &in.MaaSAPI, &out.MaaSAPI
+		*out = new(ComponentHealth)
+		**out = **in
+	}
+	if in.Authorino != nil {
+		in, out := &in.Authorino, &out.Authorino
+		*out = new(ComponentHealth)
+		**out = **in
+	}
+	if in.Limitador != nil {
+		in, out := &in.Limitador, &out.Limitador
+		*out = new(ComponentHealth)
+		**out = **in
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSStatusStatus.
+func (in *MaaSStatusStatus) DeepCopy() *MaaSStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSSubscription) DeepCopyInto(out *MaaSSubscription) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscription.
+func (in *MaaSSubscription) DeepCopy() *MaaSSubscription {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSSubscription)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSSubscription) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSSubscriptionList) DeepCopyInto(out *MaaSSubscriptionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MaaSSubscription, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscriptionList.
+func (in *MaaSSubscriptionList) DeepCopy() *MaaSSubscriptionList {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSSubscriptionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MaaSSubscriptionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSSubscriptionSpec) DeepCopyInto(out *MaaSSubscriptionSpec) {
+	*out = *in
+	in.Owner.DeepCopyInto(&out.Owner)
+	if in.ModelRefs != nil {
+		in, out := &in.ModelRefs, &out.ModelRefs
+		*out = make([]ModelSubscriptionRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TokenMetadata != nil {
+		in, out := &in.TokenMetadata, &out.TokenMetadata
+		*out = new(TokenMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.MaxTokensPerDay != nil {
+		in, out := &in.MaxTokensPerDay, &out.MaxTokensPerDay
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Listener != nil {
+		in, out := &in.Listener, &out.Listener
+		*out = new(SubscriptionListener)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Attachments != nil {
+		in, out := &in.Attachments, &out.Attachments
+		*out = new(AttachmentPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DowngradeGracePeriod != nil {
+		in, out := &in.DowngradeGracePeriod, &out.DowngradeGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscriptionSpec.
+func (in *MaaSSubscriptionSpec) DeepCopy() *MaaSSubscriptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSSubscriptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaaSSubscriptionStatus) DeepCopyInto(out *MaaSSubscriptionStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ObservedOwner != nil {
+		in, out := &in.ObservedOwner, &out.ObservedOwner
+		*out = new(OwnerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevokedOwners != nil {
+		in, out := &in.RevokedOwners, &out.RevokedOwners
+		*out = make([]RevokedOwner, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaaSSubscriptionStatus.
+func (in *MaaSSubscriptionStatus) DeepCopy() *MaaSSubscriptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaaSSubscriptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceStatus) DeepCopyInto(out *MaintenanceStatus) {
+	*out = *in
+	if in.Until != nil {
+		in, out := &in.Until, &out.Until
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceStatus.
+func (in *MaintenanceStatus) DeepCopy() *MaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MeteringMetadata) DeepCopyInto(out *MeteringMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MeteringMetadata.
+func (in *MeteringMetadata) DeepCopy() *MeteringMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(MeteringMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelDocumentation) DeepCopyInto(out *ModelDocumentation) {
+	*out = *in
+	if in.Examples != nil {
+		in, out := &in.Examples, &out.Examples
+		*out = make([]ModelExample, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelDocumentation.
+func (in *ModelDocumentation) DeepCopy() *ModelDocumentation {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelDocumentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelExample) DeepCopyInto(out *ModelExample) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelExample.
+func (in *ModelExample) DeepCopy() *ModelExample {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelExample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPhaseSummary) DeepCopyInto(out *ModelPhaseSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPhaseSummary.
+func (in *ModelPhaseSummary) DeepCopy() *ModelPhaseSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPhaseSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRef) DeepCopyInto(out *ModelRef) {
+	*out = *in
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRef.
 func (in *ModelRef) DeepCopy() *ModelRef {
 	if in == nil {
@@ -541,6 +1398,36 @@ func (in *ModelReference) DeepCopy() *ModelReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRouting) DeepCopyInto(out *ModelRouting) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRouting.
+func (in *ModelRouting) DeepCopy() *ModelRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelResources) DeepCopyInto(out *ModelResources) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelResources.
+func (in *ModelResources) DeepCopy() *ModelResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelSubscriptionRef) DeepCopyInto(out *ModelSubscriptionRef) {
 	*out = *in
@@ -554,11 +1441,26 @@ func (in *ModelSubscriptionRef) DeepCopyInto(out *ModelSubscriptionRef) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.RequestRateLimits != nil {
+		in, out := &in.RequestRateLimits, &out.RequestRateLimits
+		*out = make([]RequestRateLimit, len(*in))
+		copy(*out, *in)
+	}
 	if in.BillingRate != nil {
 		in, out := &in.BillingRate, &out.BillingRate
 		*out = new(BillingRate)
 		**out = **in
 	}
+	if in.TokenBudget != nil {
+		in, out := &in.TokenBudget, &out.TokenBudget
+		*out = new(TokenBudget)
+		**out = **in
+	}
+	if in.MaxConcurrentRequests != nil {
+		in, out := &in.MaxConcurrentRequests, &out.MaxConcurrentRequests
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSubscriptionRef.
@@ -571,6 +1473,26 @@ func (in *ModelSubscriptionRef) DeepCopy() *ModelSubscriptionRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelTracing) DeepCopyInto(out *ModelTracing) {
+	*out = *in
+	if in.Propagate != nil {
+		in, out := &in.Propagate, &out.Propagate
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelTracing.
+func (in *ModelTracing) DeepCopy() *ModelTracing {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelTracing)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OwnerSpec) DeepCopyInto(out *OwnerSpec) {
 	*out = *in
@@ -596,6 +1518,68 @@ func (in *OwnerSpec) DeepCopy() *OwnerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParentModelReference) DeepCopyInto(out *ParentModelReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParentModelReference.
+func (in *ParentModelReference) DeepCopy() *ParentModelReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ParentModelReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyError) DeepCopyInto(out *PolicyError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyError.
+func (in *PolicyError) DeepCopy() *PolicyError {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestRateLimit) DeepCopyInto(out *RequestRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestRateLimit.
+func (in *RequestRateLimit) DeepCopy() *RequestRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevokedOwner) DeepCopyInto(out *RevokedOwner) {
+	*out = *in
+	in.RevokedAt.DeepCopyInto(&out.RevokedAt)
+	in.GraceEndsAt.DeepCopyInto(&out.GraceEndsAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevokedOwner.
+func (in *RevokedOwner) DeepCopy() *RevokedOwner {
+	if in == nil {
+		return nil
+	}
+	out := new(RevokedOwner)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SubjectSpec) DeepCopyInto(out *SubjectSpec) {
 	*out = *in
@@ -621,6 +1605,61 @@ func (in *SubjectSpec) DeepCopy() *SubjectSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriptionListener) DeepCopyInto(out *SubscriptionListener) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriptionListener.
+func (in *SubscriptionListener) DeepCopy() *SubscriptionListener {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriptionListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TierCoverage) DeepCopyInto(out *TierCoverage) {
+	*out = *in
+	if in.MissingModels != nil {
+		in, out := &in.MissingModels, &out.MissingModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TierCoverage.
+func (in *TierCoverage) DeepCopy() *TierCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(TierCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudget) DeepCopyInto(out *TokenBudget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudget.
+func (in *TokenBudget) DeepCopy() *TokenBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TokenMetadata) DeepCopyInto(out *TokenMetadata) {
 	*out = *in
@@ -657,3 +1696,33 @@ func (in *TokenRateLimit) DeepCopy() *TokenRateLimit {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultCredentialReference) DeepCopyInto(out *VaultCredentialReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultCredentialReference.
+func (in *VaultCredentialReference) DeepCopy() *VaultCredentialReference {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultCredentialReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeightedBackend) DeepCopyInto(out *WeightedBackend) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeightedBackend.
+func (in *WeightedBackend) DeepCopy() *WeightedBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(WeightedBackend)
+	in.DeepCopyInto(out)
+	return out
+}
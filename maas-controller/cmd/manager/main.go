@@ -20,7 +20,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
@@ -41,11 +43,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
 	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/controller/maas"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/fips"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/keycloak"
 	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/vault"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/webhook"
 )
 
 var (
@@ -57,6 +67,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(kservev1alpha1.AddToScheme(scheme))
 	utilruntime.Must(gatewayapiv1.Install(scheme))
+	utilruntime.Must(gatewayapiv1beta1.Install(scheme))
 	utilruntime.Must(maasv1alpha1.AddToScheme(scheme))
 }
 
@@ -140,6 +151,12 @@ func getClusterServiceAccountIssuer(c client.Reader) (string, error) {
 	return issuer, nil
 }
 
+// Set at build time through -ldflags "-X main.version=... -X main.commit=...".
+var (
+	version = "dev"
+	commit  = ""
+)
+
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
@@ -147,8 +164,41 @@ func main() {
 	var gatewayName string
 	var gatewayNamespace string
 	var maasAPINamespace string
+	var maasAPIShards int
 	var maasSubscriptionNamespace string
 	var clusterAudience string
+	var enableQuotaWebhook bool
+	var enableModelWebhook bool
+	var webhookPort int
+	var webhookCertDir string
+	var vaultAddress string
+	var vaultAuthMount string
+	var vaultTokenPath string
+	var fipsRequired bool
+	var sharedRouteName string
+	var sharedRoutePaths string
+	var backendKinds string
+	var sandboxImage string
+	var sandboxLatency time.Duration
+	var softDeleteGracePeriod time.Duration
+	var statusCheckAuthorino bool
+	var statusCheckLimitador bool
+	var kuadrantNamespace string
+	var upstreamCredentialsSecret string
+	var keycloakURL string
+	var keycloakRealm string
+	var keycloakClientID string
+	var keycloakTierClaim string
+	var keycloakAdminClientID string
+	var keycloakAdminSecretFile string
+	var tracingEndpoint string
+	var tracingSamplingPercentage int
+	var rateLimitBackend string
+	var rateLimitService string
+	var enforceContextWindow bool
+	var quotaHeaders bool
+	var gcInterval time.Duration
+	var gcDryRun bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metrics endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -157,15 +207,95 @@ func main() {
 	flag.StringVar(&gatewayName, "gateway-name", "maas-default-gateway", "The name of the Gateway resource to use for model HTTPRoutes.")
 	flag.StringVar(&gatewayNamespace, "gateway-namespace", "openshift-ingress", "The namespace of the Gateway resource.")
 	flag.StringVar(&maasAPINamespace, "maas-api-namespace", "opendatahub", "The namespace where maas-api service is deployed.")
+	flag.IntVar(&maasAPIShards, "maas-api-shards", 0, "Number of maas-api shards (MODEL_SHARDS) the model namespaces are split among. Above 1, AuthPolicies call the Service maas-api-shard-<n> of each model's shard.")
 	flag.StringVar(&maasSubscriptionNamespace, "maas-subscription-namespace", "models-as-a-service", "The namespace to watch for MaaS CRs.")
 	flag.StringVar(&clusterAudience, "cluster-audience", "https://kubernetes.default.svc", "The OIDC audience of the cluster for TokenReview. HyperShift/ROSA clusters use a custom OIDC provider URL.")
 
+	flag.BoolVar(&enableQuotaWebhook, "enable-quota-webhook", false, "Serve the admission webhook that enforces namespace quotas on MaaSModelRefs and MaaSSubscriptions.")
+	flag.BoolVar(&enableModelWebhook, "enable-model-webhook", false, "Serve the admission webhooks that default and validate MaaSModelRefs.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the admission webhook server listens on.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "Directory holding tls.crt and tls.key for the webhook server.")
+
+	flag.StringVar(&vaultAddress, "vault-address", "", "Address of the Vault server ExternalModel spec.vaultRef credentials are read from. Empty disables Vault.")
+	flag.StringVar(&vaultAuthMount, "vault-auth-mount", vault.DefaultAuthMount, "Mount path of Vault's Kubernetes auth method.")
+	flag.StringVar(&vaultTokenPath, "vault-token-path", vault.DefaultTokenPath, "Service account token presented to Vault at login.")
+
+	flag.StringVar(&sharedRouteName, "shared-route-name", "", "Name of the shared OpenAI-style HTTPRoute created in the maas-api namespace. Empty disables the shared route.")
+	flag.StringVar(&sharedRoutePaths, "shared-route-paths", strings.Join(maas.DefaultSharedRoutePaths, ","), "Comma-separated paths matched by the shared route. Must match maas-api's EXT_PROC_PATHS.")
+
+	flag.StringVar(&sandboxImage, "sandbox-image", "", "maas-api image that runs the mock backend for sandbox MaaSSubscriptions, in the maas-api namespace. Empty disables sandboxes.")
+	flag.DurationVar(&sandboxLatency, "sandbox-latency", maas.DefaultSandboxLatency, "Time to first token of sandbox responses.")
+	flag.StringVar(&backendKinds, "backend-kinds", "", "Comma-separated MaaSModelRef spec.modelRef.kind values to reconcile (registered: "+strings.Join(maas.BackendKinds(), ", ")+"). Empty reconciles all.")
+
+	flag.DurationVar(&softDeleteGracePeriod, "soft-delete-grace-period", 7*24*time.Hour, "How long a MaaSModelRef annotated "+maas.SoftDeletedAnnotation+"=true is kept before it is deleted. 0 keeps it until restored.")
+
+	flag.BoolVar(&statusCheckAuthorino, "status-check-authorino", false, "Report in MaaSStatus whether Authorino accepted the AuthConfigs in the Kuadrant namespace.")
+	flag.BoolVar(&statusCheckLimitador, "status-check-limitador", false, "Report in MaaSStatus whether the Limitador resource in the Kuadrant namespace is ready.")
+	flag.StringVar(&kuadrantNamespace, "kuadrant-namespace", "kuadrant-system", "The namespace of Kuadrant's Authorino and Limitador.")
+	flag.StringVar(&upstreamCredentialsSecret, "upstream-credentials-secret", "maas-upstream-credentials", "Secret in the Kuadrant namespace whose token key Authorino presents to maas-api (UPSTREAM_CREDENTIALS_TOKEN) to fetch ExternalModel credentials.")
+
+	flag.StringVar(&keycloakURL, "keycloak-url", "", "Base URL of the Keycloak server MaaSSubscriptions are provisioned in as tiers. Empty disables the Keycloak integration.")
+	flag.StringVar(&keycloakRealm, "keycloak-realm", "", "Keycloak realm of the MaaS OIDC client and the owner groups.")
+	flag.StringVar(&keycloakClientID, "keycloak-client-id", "maas", "Client ID of the MaaS OIDC client that tier roles are created on.")
+	flag.StringVar(&keycloakTierClaim, "keycloak-tier-claim", keycloak.DefaultTierClaim, "Token claim listing the caller's tiers.")
+	flag.StringVar(&keycloakAdminClientID, "keycloak-admin-client-id", "maas-controller", "Client ID of the confidential client maas-controller calls the Keycloak admin API as.")
+	flag.StringVar(&keycloakAdminSecretFile, "keycloak-admin-client-secret-file", "/etc/maas-controller/keycloak/client-secret", "File holding the admin client's secret.")
+
+	flag.StringVar(&tracingEndpoint, "tracing-endpoint", "", "OTLP/HTTP endpoint (e.g. http://otel-collector:4318) reconcile spans are exported to. Empty disables tracing.")
+	flag.IntVar(&tracingSamplingPercentage, "tracing-sampling-percentage", 100, "Percentage of reconciles traced.")
+
+	flag.StringVar(&rateLimitBackend, "rate-limit-backend", maas.RateLimitBackendLimitador, "What enforces subscription rate limits: "+strings.Join(maas.RateLimitBackends, ", ")+". Must match maas-api's RATE_LIMIT_BACKEND.")
+	flag.StringVar(&rateLimitService, "rate-limit-service", "", "host:port of the Envoy rate limit service of the envoy-rls backend (default maas-ratelimit.<maas-api-namespace>.svc.cluster.local:8081). Its "+maas.RLSConfigMapName+" ConfigMap is written to the maas-api namespace.")
+
+	flag.BoolVar(&enforceContextWindow, "enforce-context-window", false, "Forward request bodies of models annotated "+maas.ContextWindowAnnotation+" to maas-api to check prompts against the context window. Must match maas-api's ENFORCE_CONTEXT_WINDOW.")
+
+	flag.BoolVar(&quotaHeaders, "quota-headers", false, "Add X-MaaS-Tier, X-MaaS-Model and X-MaaS-Remaining-Tokens headers to model responses. Pair with maas-api's QUOTA_HEADERS for remaining tokens.")
+
+	flag.DurationVar(&gcInterval, "gc-interval", maas.DefaultGCInterval, "How often generated HTTPRoutes, AuthPolicies and ExternalName Services are swept for ones whose MaaSModelRef is gone or that drifted. 0 disables the sweep.")
+	flag.BoolVar(&gcDryRun, "gc-dry-run", false, "Only report what the sweep finds, in the maas_controller_gc_findings metric and the MaaSStatus OrphanedResources condition.")
+
+	flag.BoolVar(&fipsRequired, "fips-required", false, "Fail startup unless crypto runs in FIPS 140 mode.")
+
 	opts := zap.Options{Development: false}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	cryptoStatus, err := fips.Verify(fipsRequired)
+	if err != nil {
+		setupLog.Error(err, "crypto startup check failed")
+		os.Exit(1)
+	}
+	setupLog.Info("crypto module", "backend", cryptoStatus.Backend, "fipsEnabled", cryptoStatus.Enabled, "fipsRequired", cryptoStatus.Required)
+
+	var enabledKinds []string
+	for k := range strings.SplitSeq(backendKinds, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			enabledKinds = append(enabledKinds, k)
+		}
+	}
+	if err := maas.SetEnabledBackendKinds(enabledKinds); err != nil {
+		setupLog.Error(err, "invalid --backend-kinds")
+		os.Exit(1)
+	}
+
+	if err := maas.ValidateRateLimitBackend(rateLimitBackend); err != nil {
+		setupLog.Error(err, "invalid --rate-limit-backend")
+		os.Exit(1)
+	}
+	if rateLimitService == "" {
+		rateLimitService = "maas-ratelimit." + maasAPINamespace + ".svc.cluster.local:8081"
+	}
+	envoyRLS := maas.EnvoyRLSConfig{Address: rateLimitService, ConfigNamespace: maasAPINamespace}
+	if rateLimitBackend == maas.RateLimitBackendEnvoyRLS {
+		if err := envoyRLS.Validate(); err != nil {
+			setupLog.Error(err, "invalid --rate-limit-service")
+			os.Exit(1)
+		}
+	}
+	setupLog.Info("rate limit backend", "backend", rateLimitBackend)
+
 	// Ensure subscription namespace exists before starting controllers
 	if err := ensureSubscriptionNamespaceExists(context.Background(), maasSubscriptionNamespace); err != nil {
 		setupLog.Error(err, "unable to ensure subscription namespace exists", "namespace", maasSubscriptionNamespace)
@@ -180,14 +310,22 @@ func main() {
 		},
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	metricsOpts := metricsserver.Options{
+		BindAddress:   metricsAddr,
+		ExtraHandlers: map[string]http.Handler{"/v1/buildinfo": fips.NewBuildInfo(version, commit, cryptoStatus).Handler()},
+	}
+	mgrOpts := ctrl.Options{
 		Scheme:                 scheme,
 		Cache:                  cacheOpts,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+		Metrics:                metricsOpts,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "maas-controller.models-as-a-service.opendatahub.io",
-	})
+	}
+	if enableQuotaWebhook || enableModelWebhook {
+		mgrOpts.WebhookServer = ctrlwebhook.NewServer(ctrlwebhook.Options{Port: webhookPort, CertDir: webhookCertDir})
+	}
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -205,34 +343,161 @@ func main() {
 	}
 
 	if err := (&maas.MaaSModelRefReconciler{
-		Client:           mgr.GetClient(),
-		Scheme:           mgr.GetScheme(),
-		GatewayName:      gatewayName,
-		GatewayNamespace: gatewayNamespace,
+		Client:                mgr.GetClient(),
+		APIReader:             mgr.GetAPIReader(),
+		Scheme:                mgr.GetScheme(),
+		GatewayName:           gatewayName,
+		GatewayNamespace:      gatewayNamespace,
+		SoftDeleteGracePeriod: softDeleteGracePeriod,
+		SubscriptionNamespace: maasSubscriptionNamespace,
+		QuotaHeaders:          quotaHeaders,
+		Recorder:              mgr.GetEventRecorderFor("maas-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MaaSModelRef")
 		os.Exit(1)
 	}
 	if err := (&maas.MaaSAuthPolicyReconciler{
-		Client:           mgr.GetClient(),
-		Scheme:           mgr.GetScheme(),
-		MaaSAPINamespace: maasAPINamespace,
-		GatewayName:      gatewayName,
-		ClusterAudience:  clusterAudience,
+		Client:                    mgr.GetClient(),
+		Scheme:                    mgr.GetScheme(),
+		MaaSAPINamespace:          maasAPINamespace,
+		MaaSAPIShards:             maasAPIShards,
+		GatewayName:               gatewayName,
+		ClusterAudience:           clusterAudience,
+		UpstreamCredentialsSecret: upstreamCredentialsSecret,
+		Recorder:                  mgr.GetEventRecorderFor("maas-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MaaSAuthPolicy")
 		os.Exit(1)
 	}
 	if err := (&maas.MaaSSubscriptionReconciler{
+		Client:               mgr.GetClient(),
+		Scheme:               mgr.GetScheme(),
+		RateLimitBackend:     rateLimitBackend,
+		EnvoyRLS:             envoyRLS,
+		EnforceContextWindow: enforceContextWindow,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MaaSSubscription")
+		os.Exit(1)
+	}
+
+	if err := (&maas.MaaSRateLimitOverrideReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "MaaSSubscription")
+		setupLog.Error(err, "unable to create controller", "controller", "MaaSRateLimitOverride")
+		os.Exit(1)
+	}
+
+	if err := (&maas.LLMISvcDiscoveryReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		SubscriptionNamespace: maasSubscriptionNamespace,
+		Recorder:              mgr.GetEventRecorderFor("maas-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LLMISvcDiscovery")
+		os.Exit(1)
+	}
+
+	if err := (&maas.MaaSStatusReconciler{
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		APIReader:         mgr.GetAPIReader(),
+		GatewayName:       gatewayName,
+		GatewayNamespace:  gatewayNamespace,
+		MaaSAPINamespace:  maasAPINamespace,
+		CheckAuthorino:    statusCheckAuthorino,
+		CheckLimitador:    statusCheckLimitador,
+		KuadrantNamespace: kuadrantNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MaaSStatus")
 		os.Exit(1)
 	}
 
+	if gcInterval > 0 {
+		setupLog.Info("sweeping generated gateway resources", "interval", gcInterval, "dryRun", gcDryRun)
+		if err := mgr.Add(&maas.GarbageCollector{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("gc"),
+			Interval: gcInterval,
+			DryRun:   gcDryRun,
+		}); err != nil {
+			setupLog.Error(err, "unable to add garbage collector")
+			os.Exit(1)
+		}
+	}
+
+	if err := (&maas.MaaSModelCatalogReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "MaaSModelCatalog")
+		os.Exit(1)
+	}
+
+	if sharedRouteName != "" {
+		var paths []string
+		for p := range strings.SplitSeq(sharedRoutePaths, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				paths = append(paths, p)
+			}
+		}
+		setupLog.Info("serving shared model route", "name", sharedRouteName, "namespace", maasAPINamespace, "paths", paths)
+		if err := (&maas.SharedRouteReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			RouteName:        sharedRouteName,
+			RouteNamespace:   maasAPINamespace,
+			GatewayName:      gatewayName,
+			GatewayNamespace: gatewayNamespace,
+			Paths:            paths,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "SharedRoute")
+			os.Exit(1)
+		}
+	}
+
+	if sandboxImage != "" {
+		setupLog.Info("serving sandbox subscriptions", "image", sandboxImage, "namespace", maasAPINamespace, "latency", sandboxLatency)
+		if err := (&maas.SandboxReconciler{
+			Client:           mgr.GetClient(),
+			Scheme:           mgr.GetScheme(),
+			Namespace:        maasAPINamespace,
+			Image:            sandboxImage,
+			Latency:          sandboxLatency,
+			GatewayName:      gatewayName,
+			GatewayNamespace: gatewayNamespace,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Sandbox")
+			os.Exit(1)
+		}
+	}
+
+	if keycloakURL != "" {
+		if keycloakRealm == "" {
+			setupLog.Error(nil, "--keycloak-realm is required with --keycloak-url")
+			os.Exit(1)
+		}
+		setupLog.Info("provisioning tiers in Keycloak", "url", keycloakURL, "realm", keycloakRealm, "client", keycloakClientID, "claim", keycloakTierClaim)
+		if err := (&maas.KeycloakTierReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Provisioner: keycloak.NewClient(keycloak.Config{
+				URL:             keycloakURL,
+				Realm:           keycloakRealm,
+				ClientID:        keycloakClientID,
+				TierClaim:       keycloakTierClaim,
+				AdminClientID:   keycloakAdminClientID,
+				AdminSecretFile: keycloakAdminSecretFile,
+			}),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "KeycloakTier")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&externalmodel.Reconciler{
 		Client:           mgr.GetClient(),
+		APIReader:        mgr.GetAPIReader(),
 		Scheme:           mgr.GetScheme(),
 		Log:              ctrl.Log.WithName("controllers").WithName("ExternalModel"),
 		GatewayName:      gatewayName,
@@ -242,6 +507,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	credentialReconciler := &externalmodel.CredentialReconciler{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Log:       ctrl.Log.WithName("controllers").WithName("ExternalModelCredentials"),
+	}
+	if vaultAddress != "" {
+		setupLog.Info("reading ExternalModel credentials from Vault", "address", vaultAddress, "authMount", vaultAuthMount)
+		credentialReconciler.Vault = vault.NewClient(vaultAddress, vaultAuthMount, vaultTokenPath)
+	}
+	if err := credentialReconciler.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ExternalModelCredentials")
+		os.Exit(1)
+	}
+
+	if enableQuotaWebhook {
+		setupLog.Info("serving namespace quota webhook", "path", webhook.QuotaPath, "port", webhookPort)
+		mgr.GetWebhookServer().Register(webhook.QuotaPath, &ctrlwebhook.Admission{Handler: &webhook.QuotaValidator{
+			Client:  mgr.GetClient(),
+			Decoder: admission.NewDecoder(mgr.GetScheme()),
+		}})
+	}
+	if enableModelWebhook {
+		setupLog.Info("serving MaaSModelRef webhooks", "paths", []string{webhook.ModelDefaultPath, webhook.ModelValidatePath}, "port", webhookPort)
+		mgr.GetWebhookServer().Register(webhook.ModelDefaultPath, &ctrlwebhook.Admission{Handler: &webhook.ModelDefaulter{
+			Decoder: admission.NewDecoder(mgr.GetScheme()),
+		}})
+		mgr.GetWebhookServer().Register(webhook.ModelValidatePath, &ctrlwebhook.Admission{Handler: &webhook.ModelValidator{
+			Client:  mgr.GetAPIReader(),
+			Decoder: admission.NewDecoder(mgr.GetScheme()),
+		}})
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -251,8 +549,25 @@ func main() {
 		os.Exit(1)
 	}
 
+	if tracingSamplingPercentage < 0 || tracingSamplingPercentage > 100 {
+		setupLog.Error(fmt.Errorf("%d is not a percentage", tracingSamplingPercentage), "invalid --tracing-sampling-percentage")
+		os.Exit(1)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingEndpoint, tracingSamplingPercentage, version)
+	if err != nil {
+		setupLog.Error(err, "unable to configure tracing")
+		os.Exit(1)
+	}
+	if tracingEndpoint != "" {
+		setupLog.Info("tracing reconciles", "endpoint", tracingEndpoint, "samplingPercentage", tracingSamplingPercentage)
+	}
+
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to flush spans")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
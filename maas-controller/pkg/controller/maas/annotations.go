@@ -33,3 +33,33 @@ func isManaged(obj metav1.Object) bool {
 
 	return val != "false"
 }
+
+// IgnoreAnnotation set to "true" on an LLMInferenceService puts it outside MaaS governance: the
+// discovery controller neither creates a MaaSModelRef for it nor adds it to tiers, even when it is
+// labeled for exposure, and removes the MaaSModelRef it had discovered. maas-api treats
+// MaaSModelRefs carrying the annotation as not found.
+const IgnoreAnnotation = "maas.opendatahub.io/ignore"
+
+// isIgnored reports whether obj carries the ignore annotation.
+func isIgnored(obj metav1.Object) bool {
+	return obj.GetAnnotations()[IgnoreAnnotation] == "true"
+}
+
+// AdoptAnnotation confirms that a pre-existing resource whose name matches a generated one
+// (e.g. a hand-written maas-auth-<model> AuthPolicy) may be taken over by the controller.
+// Resources without the controller's managed-by label and without this annotation are left untouched.
+const AdoptAnnotation = "maas.opendatahub.io/adopt"
+
+const (
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "maas-controller"
+)
+
+// isOwnedOrAdoptable reports whether the controller may write to an existing resource:
+// either it already carries the controller's managed-by label, or an admin confirmed adoption.
+func isOwnedOrAdoptable(obj metav1.Object) bool {
+	if obj.GetLabels()[managedByLabel] == managedByValue {
+		return true
+	}
+	return obj.GetAnnotations()[AdoptAnnotation] == "true"
+}
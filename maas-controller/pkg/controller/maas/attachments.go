@@ -0,0 +1,300 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+const (
+	// extAuthzFilterName is the name of maas-api's ext_authz filter in the gateway's filter chain,
+	// as installed by deployment/components/ext-authz.
+	extAuthzFilterName = "maas.ext_authz"
+
+	// maxInspectedBodyBytes bounds the request body the gateway buffers for maas-api to inspect.
+	// Larger bodies are forwarded truncated, and maas-api denies them under an attachment policy.
+	maxInspectedBodyBytes = 32 << 20
+
+	// inspectedTextBytes is the room left for the text of a request next to its attachments.
+	inspectedTextBytes = 1 << 20
+
+	// ContextWindowAnnotation declares a model's context window in tokens. With
+	// --enforce-context-window, request bodies of annotated models are forwarded to maas-api, which
+	// denies prompts that do not fit.
+	ContextWindowAnnotation = "opendatahub.io/context-window"
+
+	// contextBytesPerToken bounds the bytes per token of a prompt that fills a context window, with
+	// room for JSON escaping and the rest of the request.
+	contextBytesPerToken = 16
+)
+
+// attachmentFilterName is the name of the EnvoyFilter that forwards a model's request bodies to
+// maas-api. It lives in the gateway namespace, so it includes the model namespace.
+func attachmentFilterName(modelNamespace, modelName string) string {
+	return "maas-attachments-" + modelNamespace + "-" + modelName
+}
+
+// inspectedBodyBytes returns how much of a request body the gateway must forward for policy to be
+// checked: enough for its attachments base64-encoded plus their text, or maxInspectedBodyBytes
+// when the policy does not bound the count or size of attachments.
+func inspectedBodyBytes(policy *maasv1alpha1.AttachmentPolicy) int64 {
+	if policy.MaxCount == nil || policy.MaxSize == nil {
+		return maxInspectedBodyBytes
+	}
+	count, size := int64(*policy.MaxCount), max(policy.MaxSize.Value(), 0)
+	if size > 0 && count > maxInspectedBodyBytes/size {
+		return maxInspectedBodyBytes
+	}
+	return min(count*size*4/3+inspectedTextBytes, maxInspectedBodyBytes)
+}
+
+// contextWindowBodyBytes returns how much of a request body the gateway must forward for maas-api
+// to check the prompt against the model's context window, or 0 when the model declares none.
+func contextWindowBodyBytes(model *maasv1alpha1.MaaSModelRef) int64 {
+	window, err := strconv.ParseInt(strings.TrimSpace(model.GetAnnotations()[ContextWindowAnnotation]), 10, 64)
+	if err != nil || window <= 0 {
+		return 0
+	}
+	if window > maxInspectedBodyBytes/contextBytesPerToken {
+		return maxInspectedBodyBytes
+	}
+	return window * contextBytesPerToken
+}
+
+// reconcileAttachmentFilterForModel makes the gateway forward request bodies of a model to
+// maas-api's ext_authz evaluator while any of its subscriptions sets spec.attachments, or, with
+// EnforceContextWindow, while the model declares a context window, so the evaluator can enforce
+// them. The EnvoyFilter sets with_request_body on the model's routes, sized for the most generous
+// of them; it is deleted once none applies.
+func (r *MaaSSubscriptionReconciler) reconcileAttachmentFilterForModel(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.deleteAttachmentFilter(ctx, log, modelNamespace, modelName)
+		}
+		return fmt.Errorf("failed to get MaaSModelRef %s/%s: %w", modelNamespace, modelName, err)
+	}
+	allSubs, err := subscriptionEntriesForModel(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	var bodyBytes int64
+	var subNames []string
+	for _, entry := range allSubs {
+		if entry.sub.Spec.Attachments == nil || !entry.sub.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		subNames = append(subNames, entry.sub.Name)
+		bodyBytes = max(bodyBytes, inspectedBodyBytes(entry.sub.Spec.Attachments))
+	}
+	var contextBytes int64
+	if r.EnforceContextWindow {
+		contextBytes = contextWindowBodyBytes(model)
+	}
+	if len(subNames) == 0 && contextBytes == 0 {
+		return r.deleteAttachmentFilter(ctx, log, modelNamespace, modelName)
+	}
+	bodyBytes = max(bodyBytes, contextBytes)
+
+	routeName, routeNS, err := findHTTPRouteForModel(ctx, r.Client, modelNamespace, modelName)
+	if errors.Is(err, ErrModelNotFound) {
+		return r.deleteAttachmentFilter(ctx, log, modelNamespace, modelName)
+	}
+	if errors.Is(err, ErrHTTPRouteNotFound) {
+		// The HTTPRoute watch reconciles again once the route exists.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve HTTPRoute for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	route, err := getHTTPRoute(ctx, r.Client, routeName, routeNS)
+	if err != nil {
+		return err
+	}
+
+	perRoute := map[string]any{
+		extAuthzFilterName: map[string]any{
+			"@type": "type.googleapis.com/envoy.extensions.filters.http.ext_authz.v3.ExtAuthzPerRoute",
+			"check_settings": map[string]any{
+				"with_request_body": map[string]any{
+					"max_request_bytes": bodyBytes,
+					// Larger bodies reach maas-api truncated, flagged by x-envoy-auth-partial-body,
+					// rather than being rejected for subscriptions without an attachment policy.
+					"allow_partial_message": true,
+					"pack_as_bytes":         true,
+				},
+			},
+		},
+	}
+	configPatches := make([]any, 0, len(route.Spec.Rules))
+	for i := range route.Spec.Rules {
+		configPatches = append(configPatches, map[string]any{
+			"applyTo": "HTTP_ROUTE",
+			"match": map[string]any{
+				"context": "GATEWAY",
+				"routeConfiguration": map[string]any{
+					"vhost": map[string]any{
+						"route": map[string]any{"name": fmt.Sprintf("%s.%s.%d", route.Namespace, route.Name, i)},
+					},
+				},
+			},
+			"patch": map[string]any{
+				"operation": "MERGE",
+				"value":     map[string]any{"typed_per_filter_config": perRoute},
+			},
+		})
+	}
+	gatewayName, gatewayNamespace := modelGateway(model)
+	spec := map[string]any{
+		"workloadSelector": map[string]any{
+			"labels": map[string]any{"gateway.networking.k8s.io/gateway-name": gatewayName},
+		},
+		"configPatches": configPatches,
+	}
+
+	sort.Strings(subNames)
+	naming, err := namingFor(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return err
+	}
+	name := attachmentFilterName(modelNamespace, modelName)
+	labels := naming.withLabels(map[string]string{
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/part-of":           "maas-subscription",
+		"app.kubernetes.io/component":         "attachment-policy",
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": modelNamespace,
+	})
+	annotations := map[string]string{"maas.opendatahub.io/subscriptions": strings.Join(subNames, ",")}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(envoyFilterGVK)
+	err = r.Get(ctx, client.ObjectKey{Namespace: gatewayNamespace, Name: name}, existing)
+	if apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("spec.attachments and context window enforcement require the Istio EnvoyFilter API, which is not installed: %w", err)
+	}
+	if apierrors.IsNotFound(err) {
+		filter := &unstructured.Unstructured{}
+		filter.SetGroupVersionKind(envoyFilterGVK)
+		filter.SetName(name)
+		filter.SetNamespace(gatewayNamespace)
+		filter.SetLabels(labels)
+		filter.SetAnnotations(annotations)
+		filter.Object["spec"] = spec
+		if err := r.Create(ctx, filter); err != nil {
+			return fmt.Errorf("failed to create attachment EnvoyFilter for model %s/%s: %w", modelNamespace, modelName, err)
+		}
+		log.Info("Attachment EnvoyFilter created", "name", name, "namespace", gatewayNamespace, "subscriptions", subNames)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get attachment EnvoyFilter: %w", err)
+	}
+	if !isManaged(existing) {
+		log.Info("Attachment EnvoyFilter opted out, skipping", "name", name)
+		return nil
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info("EnvoyFilter exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it",
+			"name", name, "namespace", gatewayNamespace)
+		return nil
+	}
+
+	snapshot := existing.DeepCopy()
+	mergedLabels := existing.GetLabels()
+	if mergedLabels == nil {
+		mergedLabels = make(map[string]string)
+	}
+	for k, v := range labels {
+		mergedLabels[k] = v
+	}
+	existing.SetLabels(mergedLabels)
+	mergedAnnotations := existing.GetAnnotations()
+	if mergedAnnotations == nil {
+		mergedAnnotations = make(map[string]string)
+	}
+	for k, v := range annotations {
+		mergedAnnotations[k] = v
+	}
+	existing.SetAnnotations(mergedAnnotations)
+	existing.Object["spec"] = spec
+	if equality.Semantic.DeepEqual(snapshot.Object, existing.Object) {
+		return nil
+	}
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update attachment EnvoyFilter for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	log.Info("Attachment EnvoyFilter updated", "name", name, "namespace", gatewayNamespace, "subscriptions", subNames)
+	return nil
+}
+
+// deleteAttachmentFilter deletes the model's attachment EnvoyFilter, wherever its gateway lives.
+func (r *MaaSSubscriptionReconciler) deleteAttachmentFilter(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	filterList := &unstructured.UnstructuredList{}
+	filterList.SetGroupVersionKind(envoyFilterGVK.GroupVersion().WithKind("EnvoyFilterList"))
+	if err := r.List(ctx, filterList, client.MatchingLabels{
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": modelNamespace,
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/component":         "attachment-policy",
+	}); err != nil {
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list attachment EnvoyFilters for cleanup: %w", err)
+	}
+	for i := range filterList.Items {
+		f := &filterList.Items[i]
+		if !isManaged(f) {
+			log.Info("Attachment EnvoyFilter opted out, skipping deletion", "name", f.GetName(), "namespace", f.GetNamespace())
+			continue
+		}
+		log.Info("Deleting attachment EnvoyFilter (no remaining attachment policies)", "name", f.GetName(), "namespace", f.GetNamespace(), "model", modelNamespace+"/"+modelName)
+		if err := r.Delete(ctx, f); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete attachment EnvoyFilter %s/%s: %w", f.GetNamespace(), f.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// modelGateway returns the gateway a model's HTTPRoute attaches to, as recorded in its status,
+// or the default MaaS gateway.
+func modelGateway(model *maasv1alpha1.MaaSModelRef) (string, string) {
+	name, namespace := model.Status.HTTPRouteGatewayName, model.Status.HTTPRouteGatewayNamespace
+	if name == "" {
+		name = defaultGatewayName
+	}
+	if namespace == "" {
+		namespace = defaultGatewayNamespace
+	}
+	return name, namespace
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestInspectedBodyBytes(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy maasv1alpha1.AttachmentPolicy
+		want   int64
+	}{
+		{"unbounded count", maasv1alpha1.AttachmentPolicy{MaxSize: ptr.To(resource.MustParse("1Mi"))}, maxInspectedBodyBytes},
+		{"unbounded size", maasv1alpha1.AttachmentPolicy{MaxCount: ptr.To[int32](2)}, maxInspectedBodyBytes},
+		{"bounded", maasv1alpha1.AttachmentPolicy{MaxCount: ptr.To[int32](3), MaxSize: ptr.To(resource.MustParse("1Mi"))}, 4<<20 + inspectedTextBytes},
+		{"no attachments", maasv1alpha1.AttachmentPolicy{MaxCount: ptr.To[int32](0), MaxSize: ptr.To(resource.MustParse("1Mi"))}, inspectedTextBytes},
+		{"capped", maasv1alpha1.AttachmentPolicy{MaxCount: ptr.To[int32](100), MaxSize: ptr.To(resource.MustParse("50Mi"))}, maxInspectedBodyBytes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inspectedBodyBytes(&tt.policy); got != tt.want {
+				t.Errorf("inspectedBodyBytes = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaaSSubscriptionReconciler_AttachmentFilter(t *testing.T) {
+	const (
+		modelName = "llava"
+		namespace = "default"
+	)
+	ctx := context.Background()
+
+	model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, namespace)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{}, {}}
+	free := newMaaSSubscription("free", namespace, "free-users", modelName, 100)
+	free.Spec.Attachments = &maasv1alpha1.AttachmentPolicy{MaxCount: ptr.To[int32](1), MaxSize: ptr.To(resource.MustParse("3Mi"))}
+	gold := newMaaSSubscription("gold", namespace, "gold-users", modelName, 1000)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, free, gold).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+	reconcile := func(name string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}); err != nil {
+			t.Fatalf("Reconcile %s: %v", name, err)
+		}
+	}
+	key := types.NamespacedName{Name: "maas-attachments-" + namespace + "-" + modelName, Namespace: defaultGatewayNamespace}
+	getFilter := func() (*unstructured.Unstructured, error) {
+		filter := &unstructured.Unstructured{}
+		filter.SetGroupVersionKind(envoyFilterGVK)
+		return filter, c.Get(ctx, key, filter)
+	}
+
+	reconcile("free")
+	filter, err := getFilter()
+	if err != nil {
+		t.Fatalf("attachment EnvoyFilter not created: %v", err)
+	}
+	if got := filter.GetAnnotations()["maas.opendatahub.io/subscriptions"]; got != "free" {
+		t.Errorf("subscriptions annotation = %q, want %q", got, "free")
+	}
+	patches, _, _ := unstructured.NestedSlice(filter.Object, "spec", "configPatches")
+	if len(patches) != 2 {
+		t.Fatalf("configPatches = %d, want one per route rule", len(patches))
+	}
+	routeName, _, _ := unstructured.NestedString(patches[1].(map[string]any), "match", "routeConfiguration", "vhost", "route", "name")
+	if want := namespace + ".maas-model-" + modelName + ".1"; routeName != want {
+		t.Errorf("route name = %q, want %q", routeName, want)
+	}
+	maxBytes, _, _ := unstructured.NestedInt64(patches[0].(map[string]any),
+		"patch", "value", "typed_per_filter_config", extAuthzFilterName, "check_settings", "with_request_body", "max_request_bytes")
+	if want := int64(4<<20 + inspectedTextBytes); maxBytes != want {
+		t.Errorf("max_request_bytes = %d, want %d", maxBytes, want)
+	}
+
+	// Removing the only attachment policy deletes the filter.
+	if err := c.Get(ctx, types.NamespacedName{Name: "free", Namespace: namespace}, free); err != nil {
+		t.Fatalf("Get free: %v", err)
+	}
+	free.Spec.Attachments = nil
+	if err := c.Update(ctx, free); err != nil {
+		t.Fatalf("Update free: %v", err)
+	}
+	reconcile("free")
+	if _, err := getFilter(); !apierrors.IsNotFound(err) {
+		t.Errorf("attachment EnvoyFilter after removing the policy: err = %v, want NotFound", err)
+	}
+}
+
+func TestMaaSSubscriptionReconciler_ContextWindowFilter(t *testing.T) {
+	const (
+		modelName = "granite"
+		namespace = "default"
+	)
+	ctx := context.Background()
+
+	model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+	model.Annotations = map[string]string{ContextWindowAnnotation: "8192"}
+	route := newHTTPRoute("maas-model-"+modelName, namespace)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{}}
+	free := newMaaSSubscription("free", namespace, "free-users", modelName, 100)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, free).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	key := types.NamespacedName{Name: "maas-attachments-" + namespace + "-" + modelName, Namespace: defaultGatewayNamespace}
+	reconcile := func(r *MaaSSubscriptionReconciler) (*unstructured.Unstructured, error) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "free", Namespace: namespace}}); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		filter := &unstructured.Unstructured{}
+		filter.SetGroupVersionKind(envoyFilterGVK)
+		return filter, c.Get(ctx, key, filter)
+	}
+
+	// Without the flag, the annotation stays informational.
+	if _, err := reconcile(&MaaSSubscriptionReconciler{Client: c, Scheme: scheme}); !apierrors.IsNotFound(err) {
+		t.Fatalf("EnvoyFilter without --enforce-context-window: err = %v, want NotFound", err)
+	}
+
+	filter, err := reconcile(&MaaSSubscriptionReconciler{Client: c, Scheme: scheme, EnforceContextWindow: true})
+	if err != nil {
+		t.Fatalf("EnvoyFilter not created for a model with a context window: %v", err)
+	}
+	patches, _, _ := unstructured.NestedSlice(filter.Object, "spec", "configPatches")
+	if len(patches) != 1 {
+		t.Fatalf("configPatches = %d, want one per route rule", len(patches))
+	}
+	maxBytes, _, _ := unstructured.NestedInt64(patches[0].(map[string]any),
+		"patch", "value", "typed_per_filter_config", extAuthzFilterName, "check_settings", "with_request_body", "max_request_bytes")
+	if want := int64(8192 * contextBytesPerToken); maxBytes != want {
+		t.Errorf("max_request_bytes = %d, want %d", maxBytes, want)
+	}
+
+	// Dropping the annotation deletes the filter.
+	if err := c.Get(ctx, types.NamespacedName{Name: modelName, Namespace: namespace}, model); err != nil {
+		t.Fatalf("Get model: %v", err)
+	}
+	model.Annotations = nil
+	if err := c.Update(ctx, model); err != nil {
+		t.Fatalf("Update model: %v", err)
+	}
+	if _, err := reconcile(&MaaSSubscriptionReconciler{Client: c, Scheme: scheme, EnforceContextWindow: true}); !apierrors.IsNotFound(err) {
+		t.Errorf("EnvoyFilter after removing the annotation: err = %v, want NotFound", err)
+	}
+}
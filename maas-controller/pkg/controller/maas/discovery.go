@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+const (
+	// ExposeLabel set to "true" on an LLMInferenceService makes the discovery controller create a
+	// MaaSModelRef of the same name for it.
+	ExposeLabel = "maas.opendatahub.io/expose"
+
+	// TiersAnnotation on an exposed LLMInferenceService lists the MaaSSubscriptions (tiers) that
+	// include its model, comma separated ("free,premium") or as a JSON array (["free","premium"]).
+	TiersAnnotation = "maas.opendatahub.io/tiers"
+
+	// DiscoveryNamespacesAnnotation on a MaaSSubscription lists the namespaces, comma separated or
+	// "*" for all, whose exposed LLMInferenceServices may join it through the tiers annotation. A
+	// subscription without it accepts no discovered models.
+	DiscoveryNamespacesAnnotation = "maas.opendatahub.io/discovery-namespaces"
+
+	// discoveredModelsAnnotation on a MaaSSubscription lists the models (namespace/name) the
+	// discovery controller added to it, so it only ever removes entries it added.
+	discoveredModelsAnnotation = "maas.opendatahub.io/discovered-models"
+)
+
+//+kubebuilder:rbac:groups=serving.kserve.io,resources=llminferenceservices/finalizers,verbs=update
+
+// LLMISvcDiscoveryReconciler creates a MaaSModelRef for every LLMInferenceService labeled
+// maas.opendatahub.io/expose=true and adds it to the MaaSSubscriptions its tiers annotation names.
+// Services annotated maas.opendatahub.io/ignore=true are treated as not exposed. The MaaSModelRef is owned by the LLMInferenceService, so Kubernetes deletes it along with the
+// service; removing the label deletes it too. MaaSModelRefs written by hand are never touched.
+type LLMISvcDiscoveryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// SubscriptionNamespace is where the MaaSSubscriptions named by the tiers annotation live.
+	SubscriptionNamespace string
+
+	// Recorder records events on the LLMInferenceServices; nil records none.
+	Recorder record.EventRecorder
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop
+func (r *LLMISvcDiscoveryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("LLMInferenceService", req.NamespacedName)
+
+	llmisvc := &kservev1alpha1.LLMInferenceService{}
+	if err := r.Get(ctx, req.NamespacedName, llmisvc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// Garbage collection deletes the MaaSModelRef; only the tier entries are left to remove.
+		return ctrl.Result{}, r.syncTiers(ctx, log, nil, req.NamespacedName, nil)
+	}
+
+	ignored := isIgnored(llmisvc)
+	exposed := llmisvc.GetLabels()[ExposeLabel] == "true" && !ignored && llmisvc.GetDeletionTimestamp().IsZero()
+	model := &maasv1alpha1.MaaSModelRef{}
+	err := r.Get(ctx, req.NamespacedName, model)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	if err == nil && !metav1.IsControlledBy(model, llmisvc) {
+		if exposed {
+			log.Info("MaaSModelRef of the same name exists and was not created by discovery, skipping")
+		}
+		return ctrl.Result{}, nil
+	}
+	found := err == nil
+
+	if !exposed {
+		if found {
+			if err := r.deleteDiscoveredModel(ctx, log, model); err != nil {
+				return ctrl.Result{}, err
+			}
+			why := "no longer exposed"
+			if ignored {
+				why = "ignored"
+			}
+			eventf(r.Recorder, llmisvc, corev1.EventTypeNormal, "ModelRemoved", "Deleted MaaSModelRef %s, the service is %s", model.Name, why)
+		}
+		return ctrl.Result{}, r.syncTiers(ctx, log, llmisvc, req.NamespacedName, nil)
+	}
+
+	rawTiers, hasTiers := llmisvc.GetAnnotations()[TiersAnnotation]
+	tiers, tiersErr := parseTiers(rawTiers)
+	if tiersErr != nil {
+		// Keep the tiers the model is in; the MaaSModelRef gets the annotation as written so its
+		// TierAnnotationInvalid condition shows what is wrong.
+		log.Info("Ignoring invalid "+TiersAnnotation+" annotation", "error", tiersErr.Error())
+		eventf(r.Recorder, llmisvc, corev1.EventTypeWarning, "TierAnnotationInvalid", "%s annotation ignored: %v", TiersAnnotation, tiersErr)
+	}
+	if !found {
+		model = &maasv1alpha1.MaaSModelRef{ObjectMeta: metav1.ObjectMeta{Name: llmisvc.Name, Namespace: llmisvc.Namespace}}
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, model, func() error {
+		if model.Labels == nil {
+			model.Labels = map[string]string{}
+		}
+		model.Labels[managedByLabel] = managedByValue
+		if model.Annotations == nil {
+			model.Annotations = map[string]string{}
+		}
+		switch {
+		case tiersErr != nil && hasTiers:
+			model.Annotations[TiersAnnotation] = rawTiers
+		case len(tiers) > 0:
+			model.Annotations[TiersAnnotation] = strings.Join(tiers, ",")
+		default:
+			delete(model.Annotations, TiersAnnotation)
+		}
+		model.Spec.ModelRef = maasv1alpha1.ModelReference{Kind: "LLMInferenceService", Name: llmisvc.Name}
+		return controllerutil.SetControllerReference(llmisvc, model, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply discovered MaaSModelRef: %w", err)
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Info("Discovered MaaSModelRef "+string(op), "tiers", tiers)
+	}
+	if op == controllerutil.OperationResultCreated {
+		eventf(r.Recorder, llmisvc, corev1.EventTypeNormal, "ModelDiscovered", "Created MaaSModelRef %s", model.Name)
+	}
+	if tiersErr != nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, r.syncTiers(ctx, log, llmisvc, req.NamespacedName, tiers)
+}
+
+// deleteDiscoveredModel deletes a MaaSModelRef the controller created. It is soft-deleted first so
+// the model webhook, which only lets soft-deleted models go, admits the deletion.
+func (r *LLMISvcDiscoveryReconciler) deleteDiscoveredModel(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	if !isSoftDeleted(model) {
+		if model.Annotations == nil {
+			model.Annotations = map[string]string{}
+		}
+		model.Annotations[SoftDeletedAnnotation] = "true"
+		if err := r.Update(ctx, model); err != nil {
+			return fmt.Errorf("failed to soft-delete discovered MaaSModelRef: %w", err)
+		}
+	}
+	log.Info("Deleting discovered MaaSModelRef, the LLMInferenceService is no longer exposed")
+	if err := r.Delete(ctx, model); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete discovered MaaSModelRef: %w", err)
+	}
+	return nil
+}
+
+// syncTiers adds the model to the MaaSSubscriptions named in tiers that accept models from its
+// namespace, and removes it from the others the controller added it to. Entries written by hand are
+// left alone, and so is an entry that is a subscription's last model, which the CRD requires.
+// Events go to llmisvc, when it still exists.
+func (r *LLMISvcDiscoveryReconciler) syncTiers(ctx context.Context, log logr.Logger, llmisvc *kservev1alpha1.LLMInferenceService, model types.NamespacedName, tiers []string) error {
+	var subs maasv1alpha1.MaaSSubscriptionList
+	if err := r.List(ctx, &subs, client.InNamespace(r.SubscriptionNamespace)); err != nil {
+		return fmt.Errorf("failed to list MaaSSubscriptions: %w", err)
+	}
+	key := model.String()
+	for i := range subs.Items {
+		sub := &subs.Items[i]
+		discovered := splitList(sub.Annotations[discoveredModelsAnnotation])
+		want := slices.Contains(tiers, sub.Name)
+		if want && !acceptsDiscovered(sub, model.Namespace) {
+			want = false
+			log.Info("MaaSSubscription does not accept discovered models from the namespace", "subscription", sub.Name)
+			if llmisvc != nil {
+				eventf(r.Recorder, llmisvc, corev1.EventTypeWarning, "TierRefused", "MaaSSubscription %s does not list namespace %s in %s", sub.Name, model.Namespace, DiscoveryNamespacesAnnotation)
+			}
+		}
+		added := slices.Contains(discovered, key)
+		idx := slices.IndexFunc(sub.Spec.ModelRefs, func(ref maasv1alpha1.ModelSubscriptionRef) bool {
+			return ref.Name == model.Name && ref.Namespace == model.Namespace
+		})
+		switch {
+		case want && idx < 0:
+			sub.Spec.ModelRefs = append(sub.Spec.ModelRefs, maasv1alpha1.ModelSubscriptionRef{Name: model.Name, Namespace: model.Namespace})
+			if !added {
+				discovered = append(discovered, key)
+			}
+		case !want && added && idx >= 0:
+			if len(sub.Spec.ModelRefs) == 1 {
+				log.Info("Keeping discovered model in MaaSSubscription, it is the subscription's only model", "subscription", sub.Name)
+				continue
+			}
+			sub.Spec.ModelRefs = slices.Delete(sub.Spec.ModelRefs, idx, idx+1)
+			discovered = slices.DeleteFunc(discovered, func(s string) bool { return s == key })
+		case !want && added:
+			discovered = slices.DeleteFunc(discovered, func(s string) bool { return s == key })
+		default:
+			continue
+		}
+		if sub.Annotations == nil {
+			sub.Annotations = map[string]string{}
+		}
+		if len(discovered) > 0 {
+			sub.Annotations[discoveredModelsAnnotation] = strings.Join(discovered, ",")
+		} else {
+			delete(sub.Annotations, discoveredModelsAnnotation)
+		}
+		log.Info("Updating tiers of discovered model", "subscription", sub.Name, "included", want)
+		if err := r.Update(ctx, sub); err != nil {
+			return fmt.Errorf("failed to update MaaSSubscription %s: %w", sub.Name, err)
+		}
+	}
+	for _, tier := range tiers {
+		if !slices.ContainsFunc(subs.Items, func(s maasv1alpha1.MaaSSubscription) bool { return s.Name == tier }) {
+			log.Info("Tier named by "+TiersAnnotation+" has no MaaSSubscription", "tier", tier, "namespace", r.SubscriptionNamespace)
+			if llmisvc != nil {
+				eventf(r.Recorder, llmisvc, corev1.EventTypeWarning, "TierNotFound", "Tier %s named by %s has no MaaSSubscription in %s", tier, TiersAnnotation, r.SubscriptionNamespace)
+			}
+		}
+	}
+	return nil
+}
+
+// acceptsDiscovered reports whether sub opted in to discovered models from namespace.
+func acceptsDiscovered(sub *maasv1alpha1.MaaSSubscription, namespace string) bool {
+	namespaces := splitList(sub.Annotations[DiscoveryNamespacesAnnotation])
+	return slices.Contains(namespaces, "*") || slices.Contains(namespaces, namespace)
+}
+
+// parseTiers reads the tiers annotation, a comma-separated list or a JSON array of names, dropping
+// blanks and duplicates. Malformed JSON and names that are not valid MaaSSubscription names are
+// errors.
+func parseTiers(value string) ([]string, error) {
+	names := splitList(value)
+	if v := strings.TrimSpace(value); strings.HasPrefix(v, "[") {
+		names = nil
+		if err := json.Unmarshal([]byte(v), &names); err != nil {
+			return nil, fmt.Errorf("invalid JSON array: %w", err)
+		}
+	}
+	var tiers []string
+	for _, t := range names {
+		t = strings.TrimSpace(t)
+		if t == "" || slices.Contains(tiers, t) {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(t); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid tier name %q: %s", t, strings.Join(errs, "; "))
+		}
+		tiers = append(tiers, t)
+	}
+	return tiers, nil
+}
+
+// mapSubscriptionToExposed enqueues the exposed LLMInferenceServices whose tiers annotation names
+// the subscription, or whose model the controller added to it, so tiers created or edited after the
+// service are kept in sync.
+func (r *LLMISvcDiscoveryReconciler) mapSubscriptionToExposed(ctx context.Context, obj client.Object) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, key := range splitList(obj.GetAnnotations()[discoveredModelsAnnotation]) {
+		if ns, name, ok := strings.Cut(key, "/"); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ns, Name: name}})
+		}
+	}
+	var services kservev1alpha1.LLMInferenceServiceList
+	if err := r.List(ctx, &services, client.MatchingLabels{ExposeLabel: "true"}); err != nil {
+		logr.FromContextOrDiscard(ctx).Error(err, "failed to list exposed LLMInferenceServices")
+		return requests
+	}
+	for _, svc := range services.Items {
+		if isIgnored(&svc) {
+			continue
+		}
+		if tiers, err := parseTiers(svc.GetAnnotations()[TiersAnnotation]); err == nil && slices.Contains(tiers, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LLMISvcDiscoveryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("llmisvc-discovery").
+		For(&kservev1alpha1.LLMInferenceService{}, builder.WithPredicates(predicate.Or(
+			predicate.LabelChangedPredicate{},
+			predicate.AnnotationChangedPredicate{},
+			predicate.Funcs{UpdateFunc: deletionTimestampSet},
+		))).
+		// Recreate a discovered MaaSModelRef deleted by hand.
+		Owns(&maasv1alpha1.MaaSModelRef{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(event.CreateEvent) bool { return false },
+			UpdateFunc: func(event.UpdateEvent) bool { return false },
+		})).
+		Watches(&maasv1alpha1.MaaSSubscription{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSubscriptionToExposed),
+			builder.WithPredicates(predicate.Funcs{
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return e.ObjectOld.GetAnnotations()[DiscoveryNamespacesAnnotation] != e.ObjectNew.GetAnnotations()[DiscoveryNamespacesAnnotation]
+				},
+			}),
+		).
+		Complete(tracing.Reconciler("llmisvc-discovery", r))
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+const discoverySubNamespace = "models-as-a-service"
+
+func newDiscoveryReconciler(objects ...client.Object) (*LLMISvcDiscoveryReconciler, client.Client) {
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	return &LLMISvcDiscoveryReconciler{Client: c, Scheme: scheme, SubscriptionNamespace: discoverySubNamespace}, c
+}
+
+func newExposedLLMISvc(name, ns, tiers string) *kservev1alpha1.LLMInferenceService {
+	svc := newLLMISvc(name, ns)
+	svc.Labels = map[string]string{ExposeLabel: "true"}
+	if tiers != "" {
+		svc.Annotations = map[string]string{TiersAnnotation: tiers}
+	}
+	return svc
+}
+
+// newTier returns a MaaSSubscription in the subscription namespace that accepts discovered models
+// from any namespace.
+func newTier(name, group, modelName string, limit int64) *maasv1alpha1.MaaSSubscription {
+	sub := newMaaSSubscription(name, discoverySubNamespace, group, modelName, limit)
+	sub.Annotations = map[string]string{DiscoveryNamespacesAnnotation: "*"}
+	return sub
+}
+
+// subscriptionModels returns the namespace/name of the models in a subscription.
+func subscriptionModels(t *testing.T, c client.Client, name string) []string {
+	t.Helper()
+	sub := &maasv1alpha1.MaaSSubscription{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: discoverySubNamespace}, sub); err != nil {
+		t.Fatalf("Get MaaSSubscription %s: %v", name, err)
+	}
+	var models []string
+	for _, ref := range sub.Spec.ModelRefs {
+		models = append(models, ref.Namespace+"/"+ref.Name)
+	}
+	return models
+}
+
+func TestLLMISvcDiscovery_CreatesModelAndTiers(t *testing.T) {
+	ctx := context.Background()
+	svc := newExposedLLMISvc("llama", "llm", "free, premium")
+	r, c := newDiscoveryReconciler(svc,
+		newTier("free", "everyone", "granite", 100),
+		newTier("premium", "paying", "granite", 1000),
+		newTier("internal", "staff", "granite", 1000),
+	)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "llama", Namespace: "llm"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, model); err != nil {
+		t.Fatalf("discovered MaaSModelRef not created: %v", err)
+	}
+	if model.Spec.ModelRef != (maasv1alpha1.ModelReference{Kind: "LLMInferenceService", Name: "llama"}) {
+		t.Errorf("modelRef = %+v, want the LLMInferenceService", model.Spec.ModelRef)
+	}
+	if !metav1.IsControlledBy(model, svc) {
+		t.Error("discovered MaaSModelRef is not owned by the LLMInferenceService")
+	}
+	if got := model.Annotations[TiersAnnotation]; got != "free,premium" {
+		t.Errorf("tiers annotation = %q, want free,premium", got)
+	}
+	for _, tier := range []string{"free", "premium"} {
+		if got := subscriptionModels(t, c, tier); !slices.Contains(got, "llm/llama") {
+			t.Errorf("%s models = %v, want llm/llama added", tier, got)
+		}
+	}
+	if got := subscriptionModels(t, c, "internal"); slices.Contains(got, "llm/llama") {
+		t.Errorf("internal models = %v, want llm/llama left out", got)
+	}
+
+	// Move the model to the internal tier only.
+	svc.Annotations[TiersAnnotation] = "internal"
+	if err := c.Update(ctx, svc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	// newMaaSSubscription puts its model in the subscription's namespace.
+	want := map[string][]string{
+		"free":     {"models-as-a-service/granite"},
+		"premium":  {"models-as-a-service/granite"},
+		"internal": {"models-as-a-service/granite", "llm/llama"},
+	}
+	for tier, models := range want {
+		if got := subscriptionModels(t, c, tier); !slices.Equal(got, models) {
+			t.Errorf("%s models = %v, want %v", tier, got, models)
+		}
+	}
+}
+
+func TestLLMISvcDiscovery_UnexposeDeletesModel(t *testing.T) {
+	ctx := context.Background()
+	svc := newExposedLLMISvc("llama", "llm", "free")
+	r, c := newDiscoveryReconciler(svc, newTier("free", "everyone", "granite", 100))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "llama", Namespace: "llm"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	delete(svc.Labels, ExposeLabel)
+	if err := c.Update(ctx, svc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &maasv1alpha1.MaaSModelRef{}); !apierrors.IsNotFound(err) {
+		t.Errorf("discovered MaaSModelRef still exists after the label was removed: %v", err)
+	}
+	if got := subscriptionModels(t, c, "free"); slices.Contains(got, "llm/llama") {
+		t.Errorf("free models = %v, want llm/llama removed", got)
+	}
+	sub := &maasv1alpha1.MaaSSubscription{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "free", Namespace: discoverySubNamespace}, sub); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, ok := sub.Annotations[discoveredModelsAnnotation]; ok {
+		t.Errorf("discovered-models annotation = %q, want it removed", sub.Annotations[discoveredModelsAnnotation])
+	}
+}
+
+func TestLLMISvcDiscovery_IgnoreAnnotation(t *testing.T) {
+	ctx := context.Background()
+	svc := newExposedLLMISvc("llama", "llm", "free")
+	ignored := newExposedLLMISvc("mistral", "llm", "free")
+	ignored.Annotations[IgnoreAnnotation] = "true"
+	r, c := newDiscoveryReconciler(svc, ignored, newTier("free", "everyone", "granite", 100))
+	for _, name := range []string{"llama", "mistral"} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "llm"}}); err != nil {
+			t.Fatalf("Reconcile %s: %v", name, err)
+		}
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "mistral", Namespace: "llm"}, &maasv1alpha1.MaaSModelRef{}); !apierrors.IsNotFound(err) {
+		t.Errorf("MaaSModelRef created for an ignored service: %v", err)
+	}
+	if got := subscriptionModels(t, c, "free"); !slices.Contains(got, "llm/llama") || slices.Contains(got, "llm/mistral") {
+		t.Errorf("free models = %v, want llm/llama added and llm/mistral left out", got)
+	}
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "llama", Namespace: "llm"}}}
+	if got := r.mapSubscriptionToExposed(ctx, newTier("free", "everyone", "granite", 100)); !slices.Equal(got, want) {
+		t.Errorf("requests for free = %v, want only llm/llama", got)
+	}
+
+	// Ignoring a discovered service removes its model and tier entries.
+	svc.Annotations[IgnoreAnnotation] = "true"
+	if err := c.Update(ctx, svc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "llama", Namespace: "llm"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &maasv1alpha1.MaaSModelRef{}); !apierrors.IsNotFound(err) {
+		t.Errorf("discovered MaaSModelRef still exists after the service was ignored: %v", err)
+	}
+	if got := subscriptionModels(t, c, "free"); slices.Contains(got, "llm/llama") {
+		t.Errorf("free models = %v, want llm/llama removed", got)
+	}
+}
+
+func TestLLMISvcDiscovery_LeavesHandWrittenResources(t *testing.T) {
+	ctx := context.Background()
+	handWritten := newMaaSModelRef("llama", "llm", "LLMInferenceService", "llama")
+	handWritten.Annotations = map[string]string{"team": "a"}
+	free := newTier("free", "everyone", "granite", 100)
+	free.Spec.ModelRefs = append(free.Spec.ModelRefs, maasv1alpha1.ModelSubscriptionRef{Name: "mistral", Namespace: "llm"})
+	r, c := newDiscoveryReconciler(newExposedLLMISvc("llama", "llm", "free"), handWritten,
+		newExposedLLMISvc("mistral", "llm", ""), free)
+
+	for _, name := range []string{"llama", "mistral"} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "llm"}}); err != nil {
+			t.Fatalf("Reconcile %s: %v", name, err)
+		}
+	}
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "llama", Namespace: "llm"}, model); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(model.OwnerReferences) != 0 || model.Annotations[TiersAnnotation] != "" {
+		t.Errorf("hand-written MaaSModelRef was modified: owners %v, annotations %v", model.OwnerReferences, model.Annotations)
+	}
+	if got := subscriptionModels(t, c, "free"); !slices.Contains(got, "llm/mistral") || slices.Contains(got, "llm/llama") {
+		t.Errorf("free models = %v, want the hand-written llm/mistral entry kept and llm/llama not added", got)
+	}
+}
+
+func TestLLMISvcDiscovery_InvalidTiersAnnotationKeepsTiers(t *testing.T) {
+	ctx := context.Background()
+	svc := newExposedLLMISvc("llama", "llm", `["free"]`)
+	r, c := newDiscoveryReconciler(svc, newTier("free", "everyone", "granite", 100))
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "llama", Namespace: "llm"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got := subscriptionModels(t, c, "free"); !slices.Contains(got, "llm/llama") {
+		t.Fatalf("free models = %v, want llm/llama added from the JSON annotation", got)
+	}
+
+	svc.Annotations[TiersAnnotation] = `["free", "premium"`
+	if err := c.Update(ctx, svc); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got := subscriptionModels(t, c, "free"); !slices.Contains(got, "llm/llama") {
+		t.Errorf("free models = %v, want llm/llama kept while the annotation is invalid", got)
+	}
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, model); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got := model.Annotations[TiersAnnotation]; got != `["free", "premium"` {
+		t.Errorf("tiers annotation = %q, want the invalid value copied for the model's condition", got)
+	}
+	events := drainEvents(recorder)
+	if !slices.ContainsFunc(events, func(e string) bool { return strings.HasPrefix(e, "Warning TierAnnotationInvalid") }) {
+		t.Errorf("events = %q, want a TierAnnotationInvalid warning", events)
+	}
+}
+
+func TestLLMISvcDiscovery_TierMustAcceptNamespace(t *testing.T) {
+	ctx := context.Background()
+	team := newTier("team", "ml", "granite", 100)
+	team.Annotations[DiscoveryNamespacesAnnotation] = "llm, research"
+	closed := newMaaSSubscription("closed", discoverySubNamespace, "staff", "granite", 100)
+	r, c := newDiscoveryReconciler(newExposedLLMISvc("llama", "llm", "team,closed"),
+		newExposedLLMISvc("mistral", "sandbox", "team"), team, closed)
+	recorder := record.NewFakeRecorder(10)
+	r.Recorder = recorder
+	for _, key := range []types.NamespacedName{{Name: "llama", Namespace: "llm"}, {Name: "mistral", Namespace: "sandbox"}} {
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile %s: %v", key, err)
+		}
+	}
+	if got := subscriptionModels(t, c, "team"); !slices.Equal(got, []string{"models-as-a-service/granite", "llm/llama"}) {
+		t.Errorf("team models = %v, want llm/llama added and sandbox/mistral refused", got)
+	}
+	if got := subscriptionModels(t, c, "closed"); slices.Contains(got, "llm/llama") {
+		t.Errorf("closed models = %v, want llm/llama refused", got)
+	}
+	refused := slices.DeleteFunc(drainEvents(recorder), func(e string) bool { return !strings.HasPrefix(e, "Warning TierRefused") })
+	if len(refused) != 2 {
+		t.Errorf("TierRefused events = %q, want one for closed and one for team", refused)
+	}
+
+	// Withdrawing the opt-in removes the models discovery added.
+	if err := c.Get(ctx, types.NamespacedName{Name: "team", Namespace: discoverySubNamespace}, team); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	delete(team.Annotations, DiscoveryNamespacesAnnotation)
+	if err := c.Update(ctx, team); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "llama", Namespace: "llm"}}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got := subscriptionModels(t, c, "team"); slices.Contains(got, "llm/llama") {
+		t.Errorf("team models = %v, want llm/llama removed once the subscription no longer accepts llm", got)
+	}
+}
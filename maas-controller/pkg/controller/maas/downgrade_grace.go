@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// reconcileRevokedOwners records the users and groups removed from spec.owner since the last
+// reconcile in status.revokedOwners, and drops those that were added back or whose grace period
+// has ended. It returns how long until the next grace period ends, or 0 when none is running.
+func reconcileRevokedOwners(subscription *maasv1alpha1.MaaSSubscription, now time.Time) time.Duration {
+	status := &subscription.Status
+	if subscription.Spec.DowngradeGracePeriod == nil || subscription.Spec.DowngradeGracePeriod.Duration <= 0 {
+		status.ObservedOwner = nil
+		status.RevokedOwners = nil
+		return 0
+	}
+	owner := subscription.Spec.Owner
+	hasGroup := func(name string) bool {
+		return slices.ContainsFunc(owner.Groups, func(g maasv1alpha1.GroupReference) bool { return g.Name == name })
+	}
+	revoked := func(group, user string) bool {
+		return slices.ContainsFunc(status.RevokedOwners, func(r maasv1alpha1.RevokedOwner) bool { return r.Group == group && r.User == user })
+	}
+
+	graceEndsAt := metav1.NewTime(now.Add(subscription.Spec.DowngradeGracePeriod.Duration))
+	if observed := status.ObservedOwner; observed != nil {
+		for _, g := range observed.Groups {
+			if !hasGroup(g.Name) && !revoked(g.Name, "") {
+				status.RevokedOwners = append(status.RevokedOwners, maasv1alpha1.RevokedOwner{
+					Group: g.Name, RevokedAt: metav1.NewTime(now), GraceEndsAt: graceEndsAt,
+				})
+			}
+		}
+		for _, u := range observed.Users {
+			if !slices.Contains(owner.Users, u) && !revoked("", u) {
+				status.RevokedOwners = append(status.RevokedOwners, maasv1alpha1.RevokedOwner{
+					User: u, RevokedAt: metav1.NewTime(now), GraceEndsAt: graceEndsAt,
+				})
+			}
+		}
+	}
+	status.ObservedOwner = owner.DeepCopy()
+
+	status.RevokedOwners = slices.DeleteFunc(status.RevokedOwners, func(r maasv1alpha1.RevokedOwner) bool {
+		readded := (r.Group != "" && hasGroup(r.Group)) || (r.User != "" && slices.Contains(owner.Users, r.User))
+		return readded || !now.Before(r.GraceEndsAt.Time)
+	})
+	if len(status.RevokedOwners) == 0 {
+		status.RevokedOwners = nil
+	}
+
+	var endsIn time.Duration
+	for _, r := range status.RevokedOwners {
+		endsIn = soonest(endsIn, r.GraceEndsAt.Sub(now))
+	}
+	return endsIn
+}
@@ -0,0 +1,85 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+const (
+	// denialReasonExpr is the reason the AuthPolicy reports in x-ext-auth-reason.
+	denialReasonExpr = `(has(auth.metadata["subscription-info"].error) ? auth.metadata["subscription-info"].error : "unauthorized")`
+	// defaultDenialBodyExpr is the plain-text body of denials without a custom response.
+	defaultDenialBodyExpr = `has(auth.metadata["subscription-info"].message) ? auth.metadata["subscription-info"].message : "Access denied"`
+)
+
+// modelErrorResponses returns the custom denial bodies of the model, or nil when it sets none
+// or does not exist.
+func modelErrorResponses(ctx context.Context, c client.Reader, modelNamespace, modelName string) (*maasv1alpha1.ErrorResponses, error) {
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return model.Spec.ErrorResponses, nil
+}
+
+// denialResponse builds the AuthPolicy's unauthorized response body and content-type. Reasons
+// with a custom response get its JSON body; everything else keeps the plain-text message.
+func denialResponse(custom *maasv1alpha1.ErrorResponses) (body, contentType map[string]any, err error) {
+	bodyExpr := defaultDenialBodyExpr
+	contentTypeExpr := `"text/plain"`
+	for _, c := range []struct {
+		resp    *maasv1alpha1.ErrorResponse
+		reasons string
+	}{
+		{resp: customResponse(custom, false), reasons: `["unauthorized", "access_denied"]`},
+		{resp: customResponse(custom, true), reasons: `["not_found", "model_not_in_subscription"]`},
+	} {
+		if c.resp == nil {
+			continue
+		}
+		jsonExpr, err := errorBodyExpr(c.resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		cond := denialReasonExpr + " in " + c.reasons
+		bodyExpr = cond + " ? " + jsonExpr + " : " + bodyExpr
+		contentTypeExpr = cond + ` ? "application/json" : ` + contentTypeExpr
+	}
+	body = map[string]any{"expression": bodyExpr}
+	if contentTypeExpr == `"text/plain"` {
+		return body, map[string]any{"value": "text/plain"}, nil
+	}
+	return body, map[string]any{"expression": contentTypeExpr}, nil
+}
+
+func customResponse(custom *maasv1alpha1.ErrorResponses, notFound bool) *maasv1alpha1.ErrorResponse {
+	switch {
+	case custom == nil:
+		return nil
+	case notFound:
+		return custom.NotFound
+	default:
+		return custom.Forbidden
+	}
+}
+
+// errorBodyExpr renders resp as a CEL expression producing the OpenAI error format, with the
+// denial reason as the type.
+func errorBodyExpr(resp *maasv1alpha1.ErrorResponse) (string, error) {
+	for _, value := range []string{resp.Message, resp.URL} {
+		if strings.ContainsAny(value, `"'\`) || strings.ContainsFunc(value, unicode.IsControl) {
+			return "", fmt.Errorf("error response %q contains quotes, backslashes or control characters", value)
+		}
+	}
+	expr := `'{"error":{"message":"` + resp.Message + `","type":"' + ` + denialReasonExpr + ` + '"`
+	if resp.URL != "" {
+		expr += `,"url":"` + resp.URL + `"`
+	}
+	return expr + `}}'`, nil
+}
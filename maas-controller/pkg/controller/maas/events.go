@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Conditions that break a MaaSModelRef's Ready condition down into the steps that decide whether
+// the gateway admits requests to it.
+const (
+	// ConditionRouteReconciled is True once the model's HTTPRoute exists and is wired to the gateway.
+	ConditionRouteReconciled = "RouteReconciled"
+	// ConditionPolicyAttached is True once a generated AuthPolicy targets the model's route and
+	// Kuadrant reports it enforced.
+	ConditionPolicyAttached = "PolicyAttached"
+	// ConditionEndpointResolved is True while status.endpoint is set.
+	ConditionEndpointResolved = "EndpointResolved"
+	// ConditionTierAnnotationInvalid is True when the tiers annotation cannot be parsed or names a
+	// tier without a MaaSSubscription. It is only present while the annotation is.
+	ConditionTierAnnotationInvalid = "TierAnnotationInvalid"
+)
+
+// abnormalWhenTrue lists the condition types that report a problem when True; for all others a
+// False status is the problem.
+var abnormalWhenTrue = map[string]bool{
+	ConditionTierAnnotationInvalid:   true,
+	ConditionDegraded:                true,
+	ConditionQuotaExceeded:           true,
+	ConditionPolicyEngineUnavailable: true,
+	ConditionSpecPriorityDuplicate:   true,
+	ConditionExpired:                 true,
+}
+
+// eventf records an event when the reconciler has a recorder; reconcilers built without one (as in
+// tests) record nothing.
+func eventf(recorder record.EventRecorder, obj runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
+
+// recordConditionEvents records an event for each condition whose status or reason changed since
+// before, so "kubectl describe" shows how the object got to its current state. Conditions that
+// report a problem are recorded as warnings.
+func recordConditionEvents(recorder record.EventRecorder, obj runtime.Object, before, after []metav1.Condition) {
+	for _, c := range after {
+		prev := apimeta.FindStatusCondition(before, c.Type)
+		if prev != nil && prev.Status == c.Status && prev.Reason == c.Reason {
+			continue
+		}
+		eventType := corev1.EventTypeNormal
+		if (c.Status == metav1.ConditionTrue) == abnormalWhenTrue[c.Type] {
+			eventType = corev1.EventTypeWarning
+		}
+		if c.Message != "" {
+			eventf(recorder, obj, eventType, c.Reason, "%s is %s: %s", c.Type, c.Status, c.Message)
+		} else {
+			eventf(recorder, obj, eventType, c.Reason, "%s is %s", c.Type, c.Status)
+		}
+	}
+}
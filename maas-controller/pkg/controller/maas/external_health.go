@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+// ConditionEndpointReachable reports the result of an ExternalModel's spec.healthCheck on the
+// MaaSModelRefs that use it.
+const ConditionEndpointReachable = "EndpointReachable"
+
+const (
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckTimeout  = 5 * time.Second
+	// maxHealthCheckBackoff caps the delay between failing probes, in multiples of the interval.
+	maxHealthCheckBackoff = 10
+)
+
+// endpointHealth is the probe state of one model. It is kept in memory only: after a restart
+// every model is probed again on its first reconcile.
+type endpointHealth struct {
+	// target identifies the probe configuration; a changed check is probed at once.
+	target    string
+	next      time.Time
+	failures  int
+	reachable bool
+	message   string
+}
+
+// endpointProber tracks the health of ExternalModel endpoints across reconciles.
+type endpointProber struct {
+	mu    sync.Mutex
+	state map[types.NamespacedName]*endpointHealth
+}
+
+func (p *endpointProber) get(key types.NamespacedName) *endpointHealth {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state == nil {
+		p.state = map[types.NamespacedName]*endpointHealth{}
+	}
+	st, ok := p.state[key]
+	if !ok {
+		st = &endpointHealth{}
+		p.state[key] = st
+	}
+	return st
+}
+
+func (p *endpointProber) forget(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.state, key)
+}
+
+// nextProbe returns when the model is due to be probed again, or zero when it is not probed.
+func (p *endpointProber) nextProbe(key types.NamespacedName) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if st, ok := p.state[key]; ok {
+		return st.next
+	}
+	return time.Time{}
+}
+
+// checkEndpoint probes the model's provider when its ExternalModel has a health check and a probe
+// is due, sets the EndpointReachable condition, and reports whether the endpoint is reachable.
+// Models without a health check are always reachable.
+func (h *externalModelHandler) checkEndpoint(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef, extModel *maasv1alpha1.ExternalModel) (bool, error) {
+	key := types.NamespacedName{Namespace: model.Namespace, Name: model.Name}
+	prober := &h.r.endpointHealth
+	hc := extModel.Spec.HealthCheck
+	if hc == nil {
+		prober.forget(key)
+		apimeta.RemoveStatusCondition(&model.Status.Conditions, ConditionEndpointReachable)
+		return true, nil
+	}
+
+	url, err := externalmodel.HealthCheckURL(extModel, model)
+	if err != nil {
+		return false, fmt.Errorf("invalid health check for ExternalModel %s: %w", extModel.Name, err)
+	}
+	method := hc.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	interval := durationOr(hc.Interval, defaultHealthCheckInterval)
+	target := fmt.Sprintf("%s %s %v %s %s", method, url, hc.ExpectedStatusCodes, interval, durationOr(hc.Timeout, defaultHealthCheckTimeout))
+
+	st := prober.get(key)
+	now := time.Now()
+	if st.target == target && now.Before(st.next) {
+		setEndpointReachable(model, st)
+		return st.reachable, nil
+	}
+
+	probeErr := h.probe(ctx, model, extModel, method, url)
+	if st.target != target {
+		*st = endpointHealth{target: target}
+	}
+	if probeErr == nil {
+		st.failures = 0
+		st.reachable = true
+		st.message = fmt.Sprintf("%s %s succeeded", method, url)
+		st.next = now.Add(interval)
+	} else {
+		st.failures++
+		st.reachable = false
+		st.message = probeErr.Error()
+		backoff := maxHealthCheckBackoff
+		if st.failures <= 4 {
+			backoff = min(backoff, 1<<(st.failures-1))
+		}
+		st.next = now.Add(time.Duration(backoff) * interval)
+		log.Info("ExternalModel endpoint unreachable", "url", url, "failures", st.failures,
+			"nextProbe", st.next.Sub(now).String(), "error", probeErr.Error())
+	}
+	setEndpointReachable(model, st)
+	return st.reachable, nil
+}
+
+// probe sends one health check request and returns why the endpoint is not reachable, or nil.
+func (h *externalModelHandler) probe(ctx context.Context, model *maasv1alpha1.MaaSModelRef, extModel *maasv1alpha1.ExternalModel, method, url string) error {
+	hc := extModel.Spec.HealthCheck
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment, DisableKeepAlives: true}
+	if strings.HasPrefix(url, "https://") && extModel.Spec.CACertificateRef != nil {
+		pool, err := h.caPool(ctx, model.Namespace, extModel.Spec.CACertificateRef.Name)
+		if err != nil {
+			return err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	client := &http.Client{Transport: transport, Timeout: durationOr(hc.Timeout, defaultHealthCheckTimeout)}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "maas-controller-health-check")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	expected := hc.ExpectedStatusCodes
+	if len(expected) == 0 {
+		expected = []int32{http.StatusOK}
+	}
+	if !slices.Contains(expected, int32(resp.StatusCode)) {
+		return fmt.Errorf("%s %s returned %d, expected one of %v", method, url, resp.StatusCode, expected)
+	}
+	return nil
+}
+
+// caPool reads the PEM bundle of an ExternalModel's spec.caCertificateRef.
+func (h *externalModelHandler) caPool(ctx context.Context, namespace, name string) (*x509.CertPool, error) {
+	secret := &corev1.Secret{}
+	if err := h.r.apiReader().Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get CA certificate Secret %s/%s: %w", namespace, name, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(secret.Data[externalmodel.CACertificateKey]) {
+		return nil, fmt.Errorf("CA certificate Secret %s/%s has no valid %q", namespace, name, externalmodel.CACertificateKey)
+	}
+	return pool, nil
+}
+
+// requeueAfter implements healthRequeuer: a probed model is reconciled again when its next probe is due.
+func (h *externalModelHandler) requeueAfter(model *maasv1alpha1.MaaSModelRef) time.Duration {
+	next := h.r.endpointHealth.nextProbe(types.NamespacedName{Namespace: model.Namespace, Name: model.Name})
+	if next.IsZero() {
+		return 0
+	}
+	return max(time.Until(next), time.Second)
+}
+
+func setEndpointReachable(model *maasv1alpha1.MaaSModelRef, st *endpointHealth) {
+	status, reason := metav1.ConditionTrue, "ProbeSucceeded"
+	if !st.reachable {
+		status, reason = metav1.ConditionFalse, "ProbeFailed"
+	}
+	apimeta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+		Type:               ConditionEndpointReachable,
+		Status:             status,
+		Reason:             reason,
+		Message:            st.message,
+		ObservedGeneration: model.GetGeneration(),
+	})
+}
+
+func durationOr(d *metav1.Duration, fallback time.Duration) time.Duration {
+	if d == nil || d.Duration <= 0 {
+		return fallback
+	}
+	return d.Duration
+}
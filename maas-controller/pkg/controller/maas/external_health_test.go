@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+// newProbedExternalModel returns a routed MaaSModelRef and an ExternalModel whose health check
+// probes srv over plain HTTP.
+func newProbedExternalModel(t *testing.T, srv *httptest.Server) (*maasv1alpha1.MaaSModelRef, *maasv1alpha1.ExternalModel) {
+	t.Helper()
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+	model := newExternalModel("local-llm", "default", "openai", host)
+	model.Annotations = map[string]string{externalmodel.AnnTLS: "false", externalmodel.AnnPort: port}
+	model.Status.HTTPRouteName = "maas-model-local-llm"
+	model.Status.HTTPRouteGatewayName = "maas-default-gateway"
+	model.Status.HTTPRouteHostnames = []string{"maas.example.com"}
+
+	extModel := newExternalModelCR("local-llm", "default", "openai", host)
+	extModel.Spec.HealthCheck = &maasv1alpha1.ExternalModelHealthCheck{Path: "/health"}
+	return model, extModel
+}
+
+func TestExternalModel_Status_EndpointReachable(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}))
+	defer srv.Close()
+
+	model, extModel := newProbedExternalModel(t, srv)
+	r, _ := newTestReconciler(model, extModel)
+	handler := &externalModelHandler{r: r}
+
+	_, ready, err := handler.Status(context.Background(), zap.New(zap.UseDevMode(true)), model)
+	if err != nil {
+		t.Fatalf("Status: unexpected error: %v", err)
+	}
+	if !ready {
+		t.Error("Status: ready = false, want true")
+	}
+	if path != "/health" {
+		t.Errorf("probe path = %q, want %q", path, "/health")
+	}
+	if !apimeta.IsStatusConditionTrue(model.Status.Conditions, ConditionEndpointReachable) {
+		t.Errorf("%s condition not True: %+v", ConditionEndpointReachable, model.Status.Conditions)
+	}
+	if d := handler.requeueAfter(model); d <= 0 {
+		t.Errorf("requeueAfter = %v, want the next probe to be scheduled", d)
+	}
+}
+
+func TestExternalModel_Status_EndpointUnreachable(t *testing.T) {
+	probes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		probes++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	model, extModel := newProbedExternalModel(t, srv)
+	r, _ := newTestReconciler(model, extModel)
+	handler := &externalModelHandler{r: r}
+	log := zap.New(zap.UseDevMode(true))
+
+	for range 2 {
+		_, ready, err := handler.Status(context.Background(), log, model)
+		if err != nil {
+			t.Fatalf("Status: unexpected error: %v", err)
+		}
+		if ready {
+			t.Error("Status: ready = true, want false")
+		}
+	}
+	if probes != 1 {
+		t.Errorf("probes = %d, want 1 (second reconcile is within the backoff)", probes)
+	}
+	cond := apimeta.FindStatusCondition(model.Status.Conditions, ConditionEndpointReachable)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "ProbeFailed" {
+		t.Errorf("%s condition = %+v, want False/ProbeFailed", ConditionEndpointReachable, cond)
+	}
+}
+
+func TestExternalModel_Status_HealthCheckRemoved(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	model, extModel := newProbedExternalModel(t, srv)
+	r, c := newTestReconciler(model, extModel)
+	handler := &externalModelHandler{r: r}
+	log := zap.New(zap.UseDevMode(true))
+
+	if _, _, err := handler.Status(context.Background(), log, model); err != nil {
+		t.Fatalf("Status: unexpected error: %v", err)
+	}
+	extModel.Spec.HealthCheck = nil
+	if err := c.Update(context.Background(), extModel); err != nil {
+		t.Fatalf("Update ExternalModel: %v", err)
+	}
+
+	_, ready, err := handler.Status(context.Background(), log, model)
+	if err != nil {
+		t.Fatalf("Status: unexpected error: %v", err)
+	}
+	if !ready {
+		t.Error("Status: ready = false, want true once the health check is removed")
+	}
+	if apimeta.FindStatusCondition(model.Status.Conditions, ConditionEndpointReachable) != nil {
+		t.Errorf("%s condition still set: %+v", ConditionEndpointReachable, model.Status.Conditions)
+	}
+	if d := handler.requeueAfter(model); d != 0 {
+		t.Errorf("requeueAfter = %v, want 0", d)
+	}
+}
@@ -0,0 +1,242 @@
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;delete
+
+// ErrGatewayNotAllowed indicates that no listener of a model's Gateway admits HTTPRoutes from the
+// model's namespace. The route is not attached until the Gateway's allowedRoutes change.
+var ErrGatewayNotAllowed = errors.New("gateway does not admit routes from the model namespace")
+
+// referenceGrantName is the name of the ReferenceGrant that lets the HTTPRoutes of a namespace
+// reference a Gateway. It lives in the gateway namespace, so it includes the route namespace.
+func referenceGrantName(routeNamespace, gatewayName string) string {
+	return "maas-" + routeNamespace + "-" + gatewayName
+}
+
+// reconcileGatewayAccess checks that the model's Gateway admits HTTPRoutes from the model's
+// namespace and, for a Gateway in another namespace, maintains the ReferenceGrant for them. It
+// returns an error wrapping ErrGatewayNotAllowed when no listener admits them.
+func (r *MaaSModelRefReconciler) reconcileGatewayAccess(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	gwName, gwNamespace := r.targetGatewayName(model), r.targetGatewayNamespace(model)
+	if model.Spec.Gateway == nil {
+		// The controller's own gateway is set up by the installation.
+		return r.releaseGatewayAccess(ctx, log, model.Namespace)
+	}
+
+	gateway := &gatewayapiv1.Gateway{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: gwNamespace, Name: gwName}, gateway); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: gateway %s/%s not found", ErrGatewayNotAllowed, gwNamespace, gwName)
+		}
+		return fmt.Errorf("failed to get gateway %s/%s: %w", gwNamespace, gwName, err)
+	}
+	allowed, err := r.gatewayAdmitsRoutes(ctx, gateway, model.Namespace)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: no listener of gateway %s/%s allows HTTPRoutes from namespace %s",
+			ErrGatewayNotAllowed, gwNamespace, gwName, model.Namespace)
+	}
+
+	if gwNamespace != model.Namespace {
+		if err := r.applyReferenceGrant(ctx, log, model.Namespace, gwName, gwNamespace); err != nil {
+			return err
+		}
+	}
+	return r.releaseGatewayAccess(ctx, log, model.Namespace)
+}
+
+// gatewayAdmitsRoutes reports whether a listener of gateway admits HTTPRoutes from namespace,
+// following the listeners' allowedRoutes. A listener without allowedRoutes only admits routes
+// from its own namespace.
+func (r *MaaSModelRefReconciler) gatewayAdmitsRoutes(ctx context.Context, gateway *gatewayapiv1.Gateway, namespace string) (bool, error) {
+	var nsLabels labels.Set
+	for _, listener := range gateway.Spec.Listeners {
+		if !listenerAdmitsHTTPRoutes(listener) {
+			continue
+		}
+		from := gatewayapiv1.NamespacesFromSame
+		var selector *metav1.LabelSelector
+		if ar := listener.AllowedRoutes; ar != nil && ar.Namespaces != nil {
+			if ar.Namespaces.From != nil {
+				from = *ar.Namespaces.From
+			}
+			selector = ar.Namespaces.Selector
+		}
+		switch from {
+		case gatewayapiv1.NamespacesFromAll:
+			return true, nil
+		case gatewayapiv1.NamespacesFromSame:
+			if gateway.Namespace == namespace {
+				return true, nil
+			}
+		case gatewayapiv1.NamespacesFromSelector:
+			if selector == nil {
+				continue
+			}
+			if nsLabels == nil {
+				ns := &corev1.Namespace{}
+				if err := r.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+					return false, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+				}
+				nsLabels = labels.Set(ns.Labels)
+			}
+			sel, err := metav1.LabelSelectorAsSelector(selector)
+			if err != nil {
+				continue
+			}
+			if sel.Matches(nsLabels) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// listenerAdmitsHTTPRoutes reports whether a listener's allowedRoutes kinds include HTTPRoute.
+// Listeners without kinds admit the kinds matching their protocol; HTTPRoute for HTTP and HTTPS.
+func listenerAdmitsHTTPRoutes(listener gatewayapiv1.Listener) bool {
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		return listener.Protocol == gatewayapiv1.HTTPProtocolType || listener.Protocol == gatewayapiv1.HTTPSProtocolType
+	}
+	for _, kind := range listener.AllowedRoutes.Kinds {
+		if kind.Kind == "HTTPRoute" && (kind.Group == nil || *kind.Group == gatewayapiv1.GroupName) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyReferenceGrant creates or updates the ReferenceGrant that lets the HTTPRoutes of
+// routeNamespace reference the Gateway.
+func (r *MaaSModelRefReconciler) applyReferenceGrant(ctx context.Context, log logr.Logger, routeNamespace, gatewayName, gatewayNamespace string) error {
+	gwName := gatewayapiv1.ObjectName(gatewayName)
+	desired := &gatewayapiv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      referenceGrantName(routeNamespace, gatewayName),
+			Namespace: gatewayNamespace,
+			Labels: map[string]string{
+				managedByLabel:                        managedByValue,
+				"app.kubernetes.io/component":         "gateway-access",
+				"maas.opendatahub.io/model-namespace": routeNamespace,
+				"maas.opendatahub.io/gateway":         gatewayName,
+			},
+		},
+		Spec: gatewayapiv1beta1.ReferenceGrantSpec{
+			From: []gatewayapiv1beta1.ReferenceGrantFrom{{
+				Group:     gatewayapiv1.GroupName,
+				Kind:      "HTTPRoute",
+				Namespace: gatewayapiv1.Namespace(routeNamespace),
+			}},
+			To: []gatewayapiv1beta1.ReferenceGrantTo{{
+				Group: gatewayapiv1.GroupName,
+				Kind:  "Gateway",
+				Name:  &gwName,
+			}},
+		},
+	}
+
+	existing := &gatewayapiv1beta1.ReferenceGrant{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create ReferenceGrant %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		log.Info("ReferenceGrant created", "name", desired.Name, "namespace", desired.Namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ReferenceGrant %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	if !isManaged(existing) {
+		log.Info("ReferenceGrant opted out, skipping", "name", existing.Name, "namespace", existing.Namespace)
+		return nil
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info("ReferenceGrant exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it",
+			"name", existing.Name, "namespace", existing.Namespace)
+		return nil
+	}
+	snapshot := existing.DeepCopy()
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		existing.Labels[k] = v
+	}
+	existing.Spec = desired.Spec
+	if equality.Semantic.DeepEqual(snapshot, existing) {
+		return nil
+	}
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update ReferenceGrant %s/%s: %w", existing.Namespace, existing.Name, err)
+	}
+	log.Info("ReferenceGrant updated", "name", existing.Name, "namespace", existing.Namespace)
+	return nil
+}
+
+// releaseGatewayAccess deletes the ReferenceGrants of routeNamespace for Gateways no remaining
+// MaaSModelRef of the namespace targets, e.g. after a model moves to another gateway or is deleted.
+func (r *MaaSModelRefReconciler) releaseGatewayAccess(ctx context.Context, log logr.Logger, routeNamespace string) error {
+	grants := &gatewayapiv1beta1.ReferenceGrantList{}
+	if err := r.List(ctx, grants, client.MatchingLabels{
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/component":         "gateway-access",
+		"maas.opendatahub.io/model-namespace": routeNamespace,
+	}); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list ReferenceGrants of namespace %s: %w", routeNamespace, err)
+	}
+	if len(grants.Items) == 0 {
+		return nil
+	}
+	models := &maasv1alpha1.MaaSModelRefList{}
+	if err := r.List(ctx, models, client.InNamespace(routeNamespace)); err != nil {
+		return fmt.Errorf("failed to list MaaSModelRefs in namespace %s: %w", routeNamespace, err)
+	}
+	inUse := map[client.ObjectKey]bool{}
+	for i := range models.Items {
+		m := &models.Items[i]
+		if m.Spec.Gateway == nil || !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		inUse[client.ObjectKey{Namespace: r.targetGatewayNamespace(m), Name: r.targetGatewayName(m)}] = true
+	}
+	for i := range grants.Items {
+		grant := &grants.Items[i]
+		gateway := client.ObjectKey{Namespace: grant.Namespace, Name: grant.Labels["maas.opendatahub.io/gateway"]}
+		if inUse[gateway] {
+			continue
+		}
+		if !isManaged(grant) {
+			log.Info("ReferenceGrant opted out, skipping deletion", "name", grant.Name, "namespace", grant.Namespace)
+			continue
+		}
+		log.Info("Deleting unused ReferenceGrant", "name", grant.Name, "namespace", grant.Namespace)
+		if err := r.Delete(ctx, grant); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ReferenceGrant %s/%s: %w", grant.Namespace, grant.Name, err)
+		}
+	}
+	return nil
+}
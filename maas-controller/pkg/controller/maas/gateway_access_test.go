@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func newTenantGateway(name, ns string, from gatewayapiv1.FromNamespaces, selector map[string]string) *gatewayapiv1.Gateway {
+	namespaces := &gatewayapiv1.RouteNamespaces{From: &from}
+	if selector != nil {
+		namespaces.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	}
+	return &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec: gatewayapiv1.GatewaySpec{
+			Listeners: []gatewayapiv1.Listener{{
+				Name:          "https",
+				Protocol:      gatewayapiv1.HTTPSProtocolType,
+				AllowedRoutes: &gatewayapiv1.AllowedRoutes{Namespaces: namespaces},
+			}},
+		},
+	}
+}
+
+func TestGatewayAdmitsRoutes(t *testing.T) {
+	tenantNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tenant": "a"}}}
+	tests := []struct {
+		name    string
+		gateway *gatewayapiv1.Gateway
+		want    bool
+	}{
+		{"all", newTenantGateway("gw", "gateways", gatewayapiv1.NamespacesFromAll, nil), true},
+		{"same", newTenantGateway("gw", "gateways", gatewayapiv1.NamespacesFromSame, nil), false},
+		{"matching selector", newTenantGateway("gw", "gateways", gatewayapiv1.NamespacesFromSelector, map[string]string{"tenant": "a"}), true},
+		{"other selector", newTenantGateway("gw", "gateways", gatewayapiv1.NamespacesFromSelector, map[string]string{"tenant": "b"}), false},
+		{"no allowedRoutes", &gatewayapiv1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "gateways"},
+			Spec:       gatewayapiv1.GatewaySpec{Listeners: []gatewayapiv1.Listener{{Name: "http", Protocol: gatewayapiv1.HTTPProtocolType}}},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := newTestReconciler(tenantNS)
+			got, err := r.gatewayAdmitsRoutes(context.Background(), tt.gateway, "team-a")
+			if err != nil {
+				t.Fatalf("gatewayAdmitsRoutes: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("gatewayAdmitsRoutes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileGatewayAccess(t *testing.T) {
+	ctx := context.Background()
+	log := zap.New(zap.UseDevMode(true))
+	grantKey := types.NamespacedName{Name: referenceGrantName("team-a", "tenant-gw"), Namespace: "gateways"}
+
+	model := newMCPServerModel("tools", "team-a", "tools-mcp")
+	model.Spec.Gateway = &maasv1alpha1.GatewayReference{Name: "tenant-gw", Namespace: "gateways"}
+	r, c := newTestReconciler(model, newTenantGateway("tenant-gw", "gateways", gatewayapiv1.NamespacesFromAll, nil))
+
+	if err := r.reconcileGatewayAccess(ctx, log, model); err != nil {
+		t.Fatalf("reconcileGatewayAccess: %v", err)
+	}
+	grant := &gatewayapiv1beta1.ReferenceGrant{}
+	if err := c.Get(ctx, grantKey, grant); err != nil {
+		t.Fatalf("ReferenceGrant not created: %v", err)
+	}
+	if from := grant.Spec.From; len(from) != 1 || from[0].Kind != "HTTPRoute" || from[0].Namespace != "team-a" {
+		t.Errorf("ReferenceGrant from = %+v, want HTTPRoutes of team-a", from)
+	}
+	if to := grant.Spec.To; len(to) != 1 || to[0].Kind != "Gateway" || to[0].Name == nil || *to[0].Name != "tenant-gw" {
+		t.Errorf("ReferenceGrant to = %+v, want Gateway tenant-gw", to)
+	}
+	route := (&mcpServerHandler{r: r}).desiredRoute(model, "tools-mcp", 8080)
+	if ref := route.Spec.ParentRefs[0]; ref.Name != "tenant-gw" || ref.Namespace == nil || *ref.Namespace != "gateways" {
+		t.Errorf("ParentRefs = %+v, want gateways/tenant-gw", route.Spec.ParentRefs)
+	}
+
+	// Moving the model back to the controller's gateway releases the grant.
+	stored := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "tools", Namespace: "team-a"}, stored); err != nil {
+		t.Fatalf("Get MaaSModelRef: %v", err)
+	}
+	stored.Spec.Gateway = nil
+	if err := c.Update(ctx, stored); err != nil {
+		t.Fatalf("Update MaaSModelRef: %v", err)
+	}
+	if err := r.reconcileGatewayAccess(ctx, log, stored); err != nil {
+		t.Fatalf("reconcileGatewayAccess: %v", err)
+	}
+	if err := c.Get(ctx, grantKey, grant); !apierrors.IsNotFound(err) {
+		t.Errorf("ReferenceGrant should be deleted, got err = %v", err)
+	}
+}
+
+func TestReconcileGatewayAccess_NotAllowed(t *testing.T) {
+	model := newMCPServerModel("tools", "team-a", "tools-mcp")
+	model.Spec.Gateway = &maasv1alpha1.GatewayReference{Name: "tenant-gw", Namespace: "gateways"}
+	r, c := newTestReconciler(model, newTenantGateway("tenant-gw", "gateways", gatewayapiv1.NamespacesFromSame, nil))
+
+	err := r.reconcileGatewayAccess(context.Background(), zap.New(zap.UseDevMode(true)), model)
+	if !errors.Is(err, ErrGatewayNotAllowed) {
+		t.Fatalf("reconcileGatewayAccess error = %v, want ErrGatewayNotAllowed", err)
+	}
+	grants := &gatewayapiv1beta1.ReferenceGrantList{}
+	if err := c.List(context.Background(), grants); err != nil {
+		t.Fatalf("List ReferenceGrants: %v", err)
+	}
+	if len(grants.Items) != 0 {
+		t.Errorf("ReferenceGrants = %d, want none for a gateway that does not admit the route", len(grants.Items))
+	}
+
+	model.Spec.Gateway.Name = "missing"
+	if err := r.reconcileGatewayAccess(context.Background(), zap.New(zap.UseDevMode(true)), model); !errors.Is(err, ErrGatewayNotAllowed) {
+		t.Errorf("reconcileGatewayAccess error = %v, want ErrGatewayNotAllowed for a missing gateway", err)
+	}
+}
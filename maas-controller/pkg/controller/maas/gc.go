@@ -0,0 +1,434 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;update;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=kuadrant.io,resources=authpolicies,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasstatuses/status,verbs=get;update;patch
+
+// ConditionOrphanedResources is the MaaSStatus condition that is True while the garbage collector
+// found generated gateway resources it left in place, in dry-run mode or because a repair failed.
+const ConditionOrphanedResources = "OrphanedResources"
+
+// DefaultGCInterval is how often the garbage collector sweeps by default.
+const DefaultGCInterval = 10 * time.Minute
+
+// Reasons a generated resource is reported by the garbage collector.
+const (
+	// gcReasonOwnerNotFound: the MaaSModelRef named by the resource's labels does not exist.
+	gcReasonOwnerNotFound = "OwnerNotFound"
+	// gcReasonNotDesired: the model exists but no longer renders the resource, e.g. a route left
+	// behind when the model changed kind or dropped its backends.
+	gcReasonNotDesired = "NotDesired"
+	// gcReasonSpecDrifted: the resource is still wanted but its spec no longer matches the model.
+	gcReasonSpecDrifted = "SpecDrifted"
+)
+
+// gcMaxListedFindings bounds how many findings the OrphanedResources message names.
+const gcMaxListedFindings = 5
+
+var (
+	gcFindings = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "maas_controller_gc_findings",
+		Help: "Generated gateway resources the last garbage collection sweep found orphaned or drifted and left in place.",
+	}, []string{"kind", "reason"})
+	gcRepairs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "maas_controller_gc_repairs_total",
+		Help: "Generated gateway resources the garbage collector deleted or repaired.",
+	}, []string{"kind", "reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(gcFindings, gcRepairs)
+}
+
+// gcFinding is a generated resource that is orphaned or drifted, with the action that fixes it.
+type gcFinding struct {
+	kind      string
+	namespace string
+	name      string
+	reason    string
+	detail    string
+	repair    func(ctx context.Context) error
+}
+
+func (f gcFinding) String() string {
+	return fmt.Sprintf("%s %s/%s (%s)", f.kind, f.namespace, f.name, f.reason)
+}
+
+// ownerFunc returns the MaaSModelRef a generated resource was rendered for, or nil when it does
+// not exist.
+type ownerFunc func(client.Object) (*maasv1alpha1.MaaSModelRef, error)
+
+// GarbageCollector periodically sweeps the HTTPRoutes, AuthPolicies and ExternalName Services
+// generated for MaaSModelRefs and deletes those whose model is gone or no longer renders them, and
+// repairs ExternalName Services that drifted from their ExternalModel. Reconcilers only act on
+// events, so resources left behind by a controller crash or a CRD migration otherwise keep serving
+// traffic. Resources annotated opendatahub.io/managed=false are never touched.
+//
+// Findings are counted in the maas_controller_gc_findings and maas_controller_gc_repairs_total
+// metrics and summarized in the OrphanedResources condition of the MaaSStatus singleton.
+type GarbageCollector struct {
+	client.Client
+	Log logr.Logger
+
+	// Interval between sweeps. The first sweep runs one interval after startup, once the
+	// reconcilers have caught up.
+	Interval time.Duration
+	// DryRun only reports findings.
+	DryRun bool
+}
+
+// Start runs a sweep every Interval until ctx is done. It runs on the leader only.
+func (g *GarbageCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(g.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			g.sweepAndReport(ctx)
+		}
+	}
+}
+
+// sweepAndReport runs one sweep, repairs its findings unless in dry-run mode and reports what was
+// left in place.
+func (g *GarbageCollector) sweepAndReport(ctx context.Context) {
+	log := g.Log
+	findings, err := g.sweep(ctx)
+	if err != nil {
+		log.Error(err, "garbage collection sweep failed")
+		return
+	}
+	var remaining []gcFinding
+	for _, f := range findings {
+		if g.DryRun {
+			log.Info("Found "+f.kind+" to garbage collect (dry run)", "namespace", f.namespace, "name", f.name, "reason", f.reason, "detail", f.detail)
+			remaining = append(remaining, f)
+			continue
+		}
+		if err := f.repair(ctx); err != nil {
+			log.Error(err, "failed to garbage collect "+f.kind, "namespace", f.namespace, "name", f.name, "reason", f.reason)
+			remaining = append(remaining, f)
+			continue
+		}
+		gcRepairs.WithLabelValues(f.kind, f.reason).Inc()
+		log.Info("Garbage collected "+f.kind, "namespace", f.namespace, "name", f.name, "reason", f.reason, "detail", f.detail)
+	}
+
+	gcFindings.Reset()
+	for _, f := range remaining {
+		gcFindings.WithLabelValues(f.kind, f.reason).Inc()
+	}
+	if err := g.setCondition(ctx, remaining); err != nil {
+		log.Error(err, "failed to report garbage collection findings in MaaSStatus")
+	}
+}
+
+// sweep lists the generated resources and returns those that are orphaned or drifted, ordered by
+// kind, namespace and name.
+func (g *GarbageCollector) sweep(ctx context.Context) ([]gcFinding, error) {
+	models := map[types.NamespacedName]*maasv1alpha1.MaaSModelRef{}
+	owner := func(obj client.Object) (*maasv1alpha1.MaaSModelRef, error) {
+		key := generatedResourceOwner(obj)
+		if m, ok := models[key]; ok {
+			return m, nil
+		}
+		m := &maasv1alpha1.MaaSModelRef{}
+		err := g.Get(ctx, key, m)
+		switch {
+		case apierrors.IsNotFound(err):
+			m = nil
+		case err != nil:
+			return nil, fmt.Errorf("failed to get MaaSModelRef %s: %w", key, err)
+		}
+		models[key] = m
+		return m, nil
+	}
+
+	var findings []gcFinding
+	for _, sweep := range []func(context.Context, ownerFunc) ([]gcFinding, error){
+		g.sweepHTTPRoutes, g.sweepAuthPolicies, g.sweepServices,
+	} {
+		found, err := sweep(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, found...)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.name < b.name
+	})
+	return findings, nil
+}
+
+// generatedSelector selects resources generated for a model by maas-controller or its
+// ExternalModel reconciler.
+func generatedSelector() client.MatchingLabelsSelector {
+	managed, _ := labels.NewRequirement(managedByLabel, selection.In, []string{managedByValue, externalmodel.ManagedBy})
+	model, _ := labels.NewRequirement("maas.opendatahub.io/model", selection.Exists, nil)
+	return client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*managed, *model)}
+}
+
+// generatedResourceOwner returns the MaaSModelRef a generated resource was rendered for.
+func generatedResourceOwner(obj client.Object) types.NamespacedName {
+	l := obj.GetLabels()
+	namespace := l["maas.opendatahub.io/model-namespace"]
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	return types.NamespacedName{Namespace: namespace, Name: l["maas.opendatahub.io/model"]}
+}
+
+// desiredRoute returns the HTTPRoute the model should be served by. ok is false when it cannot be
+// told yet, e.g. while KServe has not created the route, so nothing is collected against it.
+func desiredRoute(ctx context.Context, c client.Reader, model *maasv1alpha1.MaaSModelRef) (key types.NamespacedName, ok bool, err error) {
+	resolver := GetRouteResolver(model.Spec.ModelRef.Kind)
+	if resolver == nil {
+		return key, false, nil
+	}
+	name, namespace, err := resolver.HTTPRouteForModel(ctx, c, model)
+	if errors.Is(err, ErrHTTPRouteNotFound) {
+		return key, false, nil
+	}
+	if err != nil {
+		return key, false, fmt.Errorf("failed to resolve HTTPRoute of MaaSModelRef %s/%s: %w", model.Namespace, model.Name, err)
+	}
+	return types.NamespacedName{Namespace: namespace, Name: name}, true, nil
+}
+
+// deleteFinding returns a finding that deletes obj, provided it is still the object that was found.
+func (g *GarbageCollector) deleteFinding(kind string, obj client.Object, reason, detail string) gcFinding {
+	uid := obj.GetUID()
+	return gcFinding{
+		kind: kind, namespace: obj.GetNamespace(), name: obj.GetName(), reason: reason, detail: detail,
+		repair: func(ctx context.Context) error {
+			return client.IgnoreNotFound(g.Delete(ctx, obj, client.Preconditions{UID: &uid}))
+		},
+	}
+}
+
+// sweepHTTPRoutes finds generated HTTPRoutes whose model is gone or resolves to another route.
+func (g *GarbageCollector) sweepHTTPRoutes(ctx context.Context, owner ownerFunc) ([]gcFinding, error) {
+	routes := &gatewayapiv1.HTTPRouteList{}
+	if err := g.List(ctx, routes, generatedSelector()); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	var findings []gcFinding
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !isManaged(route) || !route.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		model, err := owner(route)
+		if err != nil {
+			return nil, err
+		}
+		if model == nil {
+			findings = append(findings, g.deleteFinding("HTTPRoute", route, gcReasonOwnerNotFound,
+				"MaaSModelRef "+generatedResourceOwner(route).String()+" does not exist"))
+			continue
+		}
+		want, ok, err := desiredRoute(ctx, g.Client, model)
+		if err != nil {
+			return nil, err
+		}
+		if ok && want != client.ObjectKeyFromObject(route) {
+			findings = append(findings, g.deleteFinding("HTTPRoute", route, gcReasonNotDesired,
+				"MaaSModelRef "+model.Namespace+"/"+model.Name+" is served by HTTPRoute "+want.String()))
+		}
+	}
+	return findings, nil
+}
+
+// sweepAuthPolicies finds the AuthPolicies generated from MaaSAuthPolicies whose model is gone,
+// is no longer granted by any MaaSAuthPolicy, or resolves to a route the policy does not target.
+func (g *GarbageCollector) sweepAuthPolicies(ctx context.Context, owner ownerFunc) ([]gcFinding, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(authPolicyGVK.GroupVersion().WithKind("AuthPolicyList"))
+	selector := generatedSelector()
+	partOf, _ := labels.NewRequirement("app.kubernetes.io/part-of", selection.Equals, []string{"maas-auth-policy"})
+	selector.Selector = selector.Add(*partOf)
+	if err := g.List(ctx, list, selector); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list AuthPolicies: %w", err)
+	}
+	var findings []gcFinding
+	for i := range list.Items {
+		ap := &list.Items[i]
+		if !isManaged(ap) || !ap.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		model, err := owner(ap)
+		if err != nil {
+			return nil, err
+		}
+		if model == nil {
+			findings = append(findings, g.deleteFinding("AuthPolicy", ap, gcReasonOwnerNotFound,
+				"MaaSModelRef "+generatedResourceOwner(ap).String()+" does not exist"))
+			continue
+		}
+		policies, err := authPoliciesForModel(ctx, g.Client, model.Namespace, model.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list MaaSAuthPolicies of MaaSModelRef %s/%s: %w", model.Namespace, model.Name, err)
+		}
+		if len(policies) == 0 {
+			findings = append(findings, g.deleteFinding("AuthPolicy", ap, gcReasonNotDesired,
+				"no MaaSAuthPolicy grants MaaSModelRef "+model.Namespace+"/"+model.Name))
+			continue
+		}
+		want, ok, err := desiredRoute(ctx, g.Client, model)
+		if err != nil {
+			return nil, err
+		}
+		target, _, _ := unstructured.NestedString(ap.Object, "spec", "targetRef", "name")
+		if ok && (want.Namespace != ap.GetNamespace() || want.Name != target) {
+			findings = append(findings, g.deleteFinding("AuthPolicy", ap, gcReasonNotDesired,
+				"MaaSModelRef "+model.Namespace+"/"+model.Name+" is served by HTTPRoute "+want.String()+", not "+ap.GetNamespace()+"/"+target))
+		}
+	}
+	return findings, nil
+}
+
+// sweepServices finds the ExternalName Services of external models whose model is gone or is no
+// longer an ExternalModel, and those pointing at another host than the ExternalModel's endpoint.
+// The ExternalModel reconciler does not watch Services, so drift is repaired here in place.
+func (g *GarbageCollector) sweepServices(ctx context.Context, owner ownerFunc) ([]gcFinding, error) {
+	services := &corev1.ServiceList{}
+	if err := g.List(ctx, services, generatedSelector()); err != nil {
+		return nil, fmt.Errorf("failed to list Services: %w", err)
+	}
+	var findings []gcFinding
+	for i := range services.Items {
+		svc := &services.Items[i]
+		if svc.Spec.Type != corev1.ServiceTypeExternalName || !isManaged(svc) || !svc.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		model, err := owner(svc)
+		if err != nil {
+			return nil, err
+		}
+		if model == nil {
+			findings = append(findings, g.deleteFinding("Service", svc, gcReasonOwnerNotFound,
+				"MaaSModelRef "+generatedResourceOwner(svc).String()+" does not exist"))
+			continue
+		}
+		if model.Spec.ModelRef.Kind != "ExternalModel" || svc.Name != externalmodel.ModelBackendServiceName(model.Name) {
+			findings = append(findings, g.deleteFinding("Service", svc, gcReasonNotDesired,
+				"MaaSModelRef "+model.Namespace+"/"+model.Name+" has no ExternalName Service "+svc.Name))
+			continue
+		}
+		ext := &maasv1alpha1.ExternalModel{}
+		err = g.Get(ctx, types.NamespacedName{Namespace: model.Namespace, Name: model.Spec.ModelRef.Name}, ext)
+		if apierrors.IsNotFound(err) {
+			// The MaaSModelRef reports the missing ExternalModel.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ExternalModel %s/%s: %w", model.Namespace, model.Spec.ModelRef.Name, err)
+		}
+		if endpoint := ext.Spec.Endpoint; endpoint != "" && svc.Spec.ExternalName != endpoint {
+			findings = append(findings, gcFinding{
+				kind: "Service", namespace: svc.Namespace, name: svc.Name, reason: gcReasonSpecDrifted,
+				detail: fmt.Sprintf("externalName is %q, ExternalModel %s/%s endpoint is %q", svc.Spec.ExternalName, ext.Namespace, ext.Name, endpoint),
+				repair: func(ctx context.Context) error {
+					// The resourceVersion makes the update fail if the Service changed since the sweep.
+					svc.Spec.ExternalName = endpoint
+					return g.Update(ctx, svc)
+				},
+			})
+		}
+	}
+	return findings, nil
+}
+
+// setCondition records the findings left in place in the OrphanedResources condition of the
+// MaaSStatus singleton. MaaSStatusReconciler creates the singleton; until then nothing is recorded.
+func (g *GarbageCollector) setCondition(ctx context.Context, remaining []gcFinding) error {
+	status := &maasv1alpha1.MaaSStatus{}
+	if err := g.Get(ctx, types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}, status); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	condition := metav1.Condition{
+		Type:               ConditionOrphanedResources,
+		Status:             metav1.ConditionFalse,
+		Reason:             "None",
+		Message:            "no orphaned or drifted generated resources",
+		ObservedGeneration: status.GetGeneration(),
+	}
+	if len(remaining) > 0 {
+		names := make([]string, 0, gcMaxListedFindings)
+		for i, f := range remaining {
+			if i == gcMaxListedFindings {
+				names = append(names, fmt.Sprintf("and %d more", len(remaining)-i))
+				break
+			}
+			names = append(names, f.String())
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "RepairFailed"
+		if g.DryRun {
+			condition.Reason = "DryRun"
+		}
+		condition.Message = fmt.Sprintf("%d generated resources are orphaned or drifted: %s", len(remaining), strings.Join(names, ", "))
+	}
+	if !apimeta.SetStatusCondition(&status.Status.Conditions, condition) {
+		return nil
+	}
+	if err := g.Status().Update(ctx, status); err != nil && !apierrors.IsConflict(err) {
+		return fmt.Errorf("failed to update MaaSStatus: %w", err)
+	}
+	return nil
+}
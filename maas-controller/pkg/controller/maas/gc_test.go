@@ -0,0 +1,184 @@
+package maas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+// newGCTestClient returns a client holding, in namespace llm:
+//   - an HTTPRoute and an AuthPolicy for the deleted model "gone", and an opted-out route for it;
+//   - the LLMInferenceService model "chat" served by its KServe route, with a leftover weighted
+//     route and an AuthPolicy targeting it;
+//   - the external model "ext" whose ExternalName Service points at an old endpoint.
+func newGCTestClient() client.Client {
+	const ns = "llm"
+	generated := func(model string) map[string]string {
+		return map[string]string{managedByLabel: managedByValue, "maas.opendatahub.io/model": model}
+	}
+
+	orphanRoute := newHTTPRoute("maas-model-gone", ns)
+	orphanRoute.Labels = generated("gone")
+	optedOut := newHTTPRoute("maas-model-gone-kept", ns)
+	optedOut.Labels = generated("gone")
+	optedOut.Annotations = map[string]string{ManagedByODHOperator: "false"}
+
+	chat := newMaaSModelRef("chat", ns, "LLMInferenceService", "chat")
+	staleRoute := newHTTPRoute(externalmodel.ModelRouteName("chat"), ns)
+	staleRoute.Labels = generated("chat")
+	policy := newMaaSAuthPolicy("chat-access", ns, "users", maasv1alpha1.ModelRef{Name: "chat", Namespace: ns})
+
+	ext := newExternalModel("ext", ns, "openai", "api.openai.com")
+	extCR := newExternalModelCR("ext", ns, "openai", "api.openai.com")
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalmodel.ModelBackendServiceName("ext"),
+			Namespace: ns,
+			Labels:    map[string]string{managedByLabel: externalmodel.ManagedBy, "maas.opendatahub.io/model": "ext"},
+		},
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName, ExternalName: "old.example.com"},
+	}
+
+	authPolicy := func(name, model, target string) *unstructured.Unstructured {
+		ap := &unstructured.Unstructured{}
+		ap.SetGroupVersionKind(authPolicyGVK)
+		ap.SetName(name)
+		ap.SetNamespace(ns)
+		ap.SetLabels(map[string]string{
+			managedByLabel:                        managedByValue,
+			"app.kubernetes.io/part-of":           "maas-auth-policy",
+			"maas.opendatahub.io/model":           model,
+			"maas.opendatahub.io/model-namespace": ns,
+		})
+		_ = unstructured.SetNestedMap(ap.Object, map[string]any{
+			"group": "gateway.networking.k8s.io", "kind": "HTTPRoute", "name": target,
+		}, "spec", "targetRef")
+		return ap
+	}
+
+	status := &maasv1alpha1.MaaSStatus{ObjectMeta: metav1.ObjectMeta{Name: maasv1alpha1.MaaSStatusSingletonName}}
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(orphanRoute, optedOut, chat, newLLMISvcRoute("chat", ns), staleRoute, policy, ext, extCR, svc, status,
+			authPolicy("maas-auth-gone", "gone", "maas-model-gone"),
+			authPolicy("maas-auth-chat", "chat", staleRoute.Name)).
+		WithStatusSubresource(&maasv1alpha1.MaaSStatus{}).
+		WithIndex(&maasv1alpha1.MaaSModelRef{}, modelRefNameIndex, modelRefNameIndexer).
+		Build()
+}
+
+func orphanedResourcesCondition(t *testing.T, c client.Client) *metav1.Condition {
+	t.Helper()
+	status := &maasv1alpha1.MaaSStatus{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}, status); err != nil {
+		t.Fatalf("get MaaSStatus: %v", err)
+	}
+	cond := apimeta.FindStatusCondition(status.Status.Conditions, ConditionOrphanedResources)
+	if cond == nil {
+		t.Fatalf("MaaSStatus has no %s condition", ConditionOrphanedResources)
+	}
+	return cond
+}
+
+func TestGarbageCollectorSweep(t *testing.T) {
+	g := &GarbageCollector{Client: newGCTestClient()}
+	findings, err := g.sweep(context.Background())
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		"AuthPolicy llm/maas-auth-chat (NotDesired)",
+		"AuthPolicy llm/maas-auth-gone (OwnerNotFound)",
+		"HTTPRoute llm/maas-model-chat (NotDesired)",
+		"HTTPRoute llm/maas-model-gone (OwnerNotFound)",
+		"Service llm/maas-model-ext-backend (SpecDrifted)",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestGarbageCollectorDryRun(t *testing.T) {
+	ctx := context.Background()
+	c := newGCTestClient()
+	g := &GarbageCollector{Client: c, DryRun: true}
+	g.sweepAndReport(ctx)
+
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "llm", Name: "maas-model-gone"}, &gatewayapiv1.HTTPRoute{}); err != nil {
+		t.Errorf("dry run deleted the orphaned HTTPRoute: %v", err)
+	}
+	if got := testutil.ToFloat64(gcFindings.WithLabelValues("HTTPRoute", gcReasonOwnerNotFound)); got != 1 {
+		t.Errorf("findings metric for orphaned HTTPRoutes = %v, want 1", got)
+	}
+	cond := orphanedResourcesCondition(t, c)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != "DryRun" {
+		t.Errorf("condition = %s/%s, want True/DryRun", cond.Status, cond.Reason)
+	}
+	if !strings.HasPrefix(cond.Message, "5 generated resources") {
+		t.Errorf("condition message = %q", cond.Message)
+	}
+}
+
+func TestGarbageCollectorRepairs(t *testing.T) {
+	ctx := context.Background()
+	c := newGCTestClient()
+	g := &GarbageCollector{Client: c}
+	repairedBefore := testutil.ToFloat64(gcRepairs.WithLabelValues("HTTPRoute", gcReasonOwnerNotFound))
+	g.sweepAndReport(ctx)
+
+	for _, name := range []string{"maas-model-gone", "maas-model-chat"} {
+		err := c.Get(ctx, types.NamespacedName{Namespace: "llm", Name: name}, &gatewayapiv1.HTTPRoute{})
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("HTTPRoute %s not deleted: %v", name, err)
+		}
+	}
+	for _, name := range []string{"maas-model-gone-kept", "chat-route"} {
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "llm", Name: name}, &gatewayapiv1.HTTPRoute{}); err != nil {
+			t.Errorf("HTTPRoute %s deleted: %v", name, err)
+		}
+	}
+	for _, name := range []string{"maas-auth-gone", "maas-auth-chat"} {
+		ap := &unstructured.Unstructured{}
+		ap.SetGroupVersionKind(authPolicyGVK)
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "llm", Name: name}, ap); !apierrors.IsNotFound(err) {
+			t.Errorf("AuthPolicy %s not deleted: %v", name, err)
+		}
+	}
+	svc := &corev1.Service{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "llm", Name: externalmodel.ModelBackendServiceName("ext")}, svc); err != nil {
+		t.Fatalf("get Service: %v", err)
+	}
+	if svc.Spec.ExternalName != "api.openai.com" {
+		t.Errorf("externalName = %q, want api.openai.com", svc.Spec.ExternalName)
+	}
+
+	if got := testutil.ToFloat64(gcRepairs.WithLabelValues("HTTPRoute", gcReasonOwnerNotFound)) - repairedBefore; got != 1 {
+		t.Errorf("repairs of orphaned HTTPRoutes = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(gcFindings.WithLabelValues("HTTPRoute", gcReasonOwnerNotFound)); got != 0 {
+		t.Errorf("findings metric after repair = %v, want 0", got)
+	}
+	if cond := orphanedResourcesCondition(t, c); cond.Status != metav1.ConditionFalse {
+		t.Errorf("condition = %s/%s, want False", cond.Status, cond.Reason)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// generatedPolicySkipped reports whether the per-model policy name in namespace must be left
+// alone: it is opted out, or it exists without being managed or adoptable. A policy kind that is
+// not installed is reported as ErrPolicyEngineUnavailable.
+func (r *MaaSSubscriptionReconciler) generatedPolicySkipped(ctx context.Context, log logr.Logger, gvk schema.GroupVersionKind, name, namespace, modelNamespace, modelName string) (bool, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, existing)
+	switch {
+	case err == nil:
+	case apimeta.IsNoMatchError(err):
+		return false, policyEngineError(err, gvk)
+	case apierrors.IsNotFound(err):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check existing %s: %w", gvk.Kind, err)
+	}
+	if !isManaged(existing) {
+		log.Info(gvk.Kind+" opted out, skipping reconciliation", "name", name, "namespace", namespace, "model", modelNamespace+"/"+modelName)
+		return true, nil
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info(gvk.Kind+" exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it",
+			"name", name, "namespace", namespace, "model", modelNamespace+"/"+modelName)
+		return true, nil
+	}
+	return false, nil
+}
+
+// applyGeneratedPolicy creates policy, or merges its labels, annotations and spec into the
+// existing managed policy of the same name. The model's HTTPRoute becomes its controller, so the
+// policy is garbage collected with the route. Policies of the same kind the model had under
+// another name are then deleted.
+func (r *MaaSSubscriptionReconciler) applyGeneratedPolicy(ctx context.Context, log logr.Logger, route *gatewayapiv1.HTTPRoute, policy *unstructured.Unstructured, modelNamespace, modelName string, subNames []string) error {
+	if err := r.upsertGeneratedPolicy(ctx, log, route, policy, modelNamespace, modelName, subNames); err != nil {
+		return err
+	}
+	// A changed name template leaves the policy of the old name behind.
+	deleted, err := deleteRenamedPolicies(ctx, r.Client, policy.GroupVersionKind(), client.MatchingLabels{
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": modelNamespace,
+		"app.kubernetes.io/part-of":           "maas-subscription",
+	}, client.ObjectKeyFromObject(policy))
+	if err != nil {
+		return err
+	}
+	if len(deleted) > 0 {
+		log.Info("Deleted renamed "+policy.GetKind()+"s", "policies", deleted, "model", modelNamespace+"/"+modelName)
+	}
+	return nil
+}
+
+// upsertGeneratedPolicy creates or updates policy for applyGeneratedPolicy.
+func (r *MaaSSubscriptionReconciler) upsertGeneratedPolicy(ctx context.Context, log logr.Logger, route *gatewayapiv1.HTTPRoute, policy *unstructured.Unstructured, modelNamespace, modelName string, subNames []string) error {
+	kind, policyName := policy.GetKind(), policy.GetName()
+	model := modelNamespace + "/" + modelName
+	if err := controllerutil.SetControllerReference(route, policy, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on %s %s/%s: %w", kind, policy.GetNamespace(), policyName, err)
+	}
+	spec, _, _ := unstructured.NestedMap(policy.Object, "spec")
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(policy.GroupVersionKind())
+	err := r.Get(ctx, client.ObjectKeyFromObject(policy), existing)
+	if apierrors.IsNotFound(err) {
+		if err := r.Create(ctx, policy); err != nil {
+			return fmt.Errorf("failed to create %s for model %s: %w", kind, modelName, err)
+		}
+		log.Info(kind+" created", "name", policyName, "model", modelName, "subscriptionCount", len(subNames), "subscriptions", subNames)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get existing %s: %w", kind, err)
+	}
+
+	// Double-check managed status as a safety check for races (the policy could have been
+	// opted-out between the early check and now).
+	if !isManaged(existing) {
+		log.Info(kind+" opted out during reconciliation, skipping update", "name", policyName)
+		return nil
+	}
+	if existing.GetLabels()[managedByLabel] != managedByValue {
+		log.Info("Adopting pre-existing "+kind, "name", policyName, "namespace", existing.GetNamespace(), "model", model)
+	}
+	// Ensure owner reference is set on managed existing policy.
+	if err := controllerutil.SetControllerReference(route, existing, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on existing %s %s/%s: %w", kind, existing.GetNamespace(), existing.GetName(), err)
+	}
+	// Snapshot the existing object before modifications so we can detect no-op updates.
+	snapshot := existing.DeepCopy()
+
+	mergedAnnotations := existing.GetAnnotations()
+	if mergedAnnotations == nil {
+		mergedAnnotations = make(map[string]string)
+	}
+	for k, v := range policy.GetAnnotations() {
+		mergedAnnotations[k] = v
+	}
+	if _, ok := policy.GetAnnotations()[rateLimitOverridesAnnotation]; !ok {
+		delete(mergedAnnotations, rateLimitOverridesAnnotation)
+	}
+	existing.SetAnnotations(mergedAnnotations)
+
+	mergedLabels := existing.GetLabels()
+	if mergedLabels == nil {
+		mergedLabels = make(map[string]string)
+	}
+	for k, v := range policy.GetLabels() {
+		mergedLabels[k] = v
+	}
+	existing.SetLabels(mergedLabels)
+	if err := unstructured.SetNestedMap(existing.Object, spec, "spec"); err != nil {
+		return fmt.Errorf("failed to update spec: %w", err)
+	}
+
+	if equality.Semantic.DeepEqual(snapshot.Object, existing.Object) {
+		log.Info(kind+" unchanged, skipping update", "name", policyName, "model", model, "subscriptionCount", len(subNames))
+		return nil
+	}
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update %s for model %s: %w", kind, model, err)
+	}
+	log.Info(kind+" updated", "name", policyName, "model", model, "subscriptionCount", len(subNames), "subscriptions", subNames)
+	return nil
+}
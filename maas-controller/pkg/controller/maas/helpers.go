@@ -4,13 +4,99 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
 )
 
+// Per-model allow-lists, set on a MaaSModelRef as comma-separated group or user names. They grant
+// access in addition to the subjects of the MaaSAuthPolicies covering the model, so a team can be
+// given one model without a MaaSAuthPolicy of its own.
+const (
+	AnnotationAllowedGroups = "maas.opendatahub.io/allowed-groups"
+	AnnotationAllowedUsers  = "maas.opendatahub.io/allowed-users"
+)
+
+// Models published through maas-api's POST /v1/models carry AnnotationApproval "pending" until an
+// admin removes it. Until then only the MaaSAuthPolicies and MaaSSubscriptions labeled
+// LabelPublished, the private pair maas-api creates for the publisher, govern the model: other
+// policies and subscriptions listing it, those of its ancestors and its allow-lists are ignored.
+const (
+	AnnotationApproval = "maas.opendatahub.io/approval"
+	LabelPublished     = "maas.opendatahub.io/published"
+	approvalPending    = "pending"
+)
+
+// isPendingApproval reports whether a published model still awaits approval.
+func isPendingApproval(model *maasv1alpha1.MaaSModelRef) bool {
+	return model.Annotations[AnnotationApproval] == approvalPending
+}
+
+// isPublishedAccess reports whether a MaaSAuthPolicy or MaaSSubscription is the private access
+// maas-api created for a published model.
+func isPublishedAccess(obj client.Object) bool {
+	return obj.GetLabels()[LabelPublished] == "true"
+}
+
+// modelPendingApproval reports whether the model namespace/name awaits approval. A missing model
+// does not.
+func modelPendingApproval(ctx context.Context, c client.Reader, modelNamespace, modelName string) (bool, error) {
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return isPendingApproval(model), nil
+}
+
+// modelAllowList returns the groups and users listed in the model's allow-list annotations.
+// A missing model, or one pending approval, has none.
+func modelAllowList(ctx context.Context, c client.Reader, modelNamespace, modelName string) (groups, users []string, err error) {
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		return nil, nil, client.IgnoreNotFound(err)
+	}
+	if isPendingApproval(model) {
+		return nil, nil, nil
+	}
+	return splitList(model.Annotations[AnnotationAllowedGroups]), splitList(model.Annotations[AnnotationAllowedUsers]), nil
+}
+
+// modelUpstreamAuth returns the ExternalModel behind a model and the header of its spec.auth
+// credential. It returns empty strings when the model is missing, is not an ExternalModel or sends
+// no credential.
+func modelUpstreamAuth(ctx context.Context, c client.Reader, modelNamespace, modelName string) (extModelName, header string, err error) {
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		return "", "", client.IgnoreNotFound(err)
+	}
+	if model.Spec.ModelRef.Kind != "ExternalModel" {
+		return "", "", nil
+	}
+	extModel := &maasv1alpha1.ExternalModel{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: modelNamespace, Name: model.Spec.ModelRef.Name}, extModel); err != nil {
+		return "", "", client.IgnoreNotFound(err)
+	}
+	if extModel.Spec.Auth == nil {
+		return "", "", nil
+	}
+	return extModel.Name, externalmodel.AuthHeader(extModel.Spec.Auth), nil
+}
+
+// splitList splits a comma-separated annotation value, dropping blanks.
+func splitList(value string) []string {
+	var out []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 // deletionTimestampSet returns true when an object's DeletionTimestamp transitions
 // from nil to non-nil, indicating the object is being deleted. Use with
 // predicate.Funcs{UpdateFunc: deletionTimestampSet} alongside
@@ -30,7 +116,7 @@ func validateCELValue(value, fieldName string) error {
 }
 
 // findAllSubscriptionsForModel returns all MaaSSubscriptions that reference the given model,
-// excluding subscriptions that are being deleted.
+// excluding subscriptions that are being deleted or have expired.
 // Uses the field index for efficient lookup instead of cluster-wide scans.
 func findAllSubscriptionsForModel(ctx context.Context, c client.Reader, modelNamespace, modelName string) ([]maasv1alpha1.MaaSSubscription, error) {
 	var allSubs maasv1alpha1.MaaSSubscriptionList
@@ -39,10 +125,11 @@ func findAllSubscriptionsForModel(ctx context.Context, c client.Reader, modelNam
 	if err := c.List(ctx, &allSubs, client.MatchingFields{"spec.modelRef": modelKey}); err != nil {
 		return nil, fmt.Errorf("failed to list MaaSSubscriptions for model %s: %w", modelKey, err)
 	}
-	// Filter out subscriptions that are being deleted
+	// Filter out subscriptions that are being deleted or have expired
+	now := time.Now()
 	var result []maasv1alpha1.MaaSSubscription
 	for _, s := range allSubs.Items {
-		if !s.GetDeletionTimestamp().IsZero() {
+		if !s.GetDeletionTimestamp().IsZero() || isExpired(&s, now) {
 			continue
 		}
 		result = append(result, s)
@@ -50,6 +137,11 @@ func findAllSubscriptionsForModel(ctx context.Context, c client.Reader, modelNam
 	return result, nil
 }
 
+// isExpired reports whether the subscription's spec.expiresAt has passed at now.
+func isExpired(sub *maasv1alpha1.MaaSSubscription, now time.Time) bool {
+	return sub.Spec.ExpiresAt != nil && !now.Before(sub.Spec.ExpiresAt.Time)
+}
+
 // findAllAuthPoliciesForModel returns all MaaSAuthPolicies that reference the given model,
 // excluding policies that are being deleted.
 func findAllAuthPoliciesForModel(ctx context.Context, c client.Reader, modelNamespace, modelName string) ([]maasv1alpha1.MaaSAuthPolicy, error) {
@@ -72,19 +164,21 @@ func findAllAuthPoliciesForModel(ctx context.Context, c client.Reader, modelName
 	return result, nil
 }
 
-// findAnySubscriptionForModel returns any one non-deleted MaaSSubscription that references the model.
+// findAnySubscriptionForModel returns any one non-deleted MaaSSubscription that covers the model,
+// directly or through one of its ancestors.
 // Used by watch mappers to find a subscription to trigger reconciliation for a model.
 func findAnySubscriptionForModel(ctx context.Context, c client.Reader, modelNamespace, modelName string) *maasv1alpha1.MaaSSubscription {
-	subs, err := findAllSubscriptionsForModel(ctx, c, modelNamespace, modelName)
-	if err != nil || len(subs) == 0 {
+	entries, err := subscriptionEntriesForModel(ctx, c, modelNamespace, modelName)
+	if err != nil || len(entries) == 0 {
 		return nil
 	}
-	return &subs[0]
+	return &entries[0].sub
 }
 
-// findAnyAuthPolicyForModel returns any one non-deleted MaaSAuthPolicy that references the model.
+// findAnyAuthPolicyForModel returns any one non-deleted MaaSAuthPolicy that governs the model,
+// directly or through one of its ancestors.
 func findAnyAuthPolicyForModel(ctx context.Context, c client.Reader, modelNamespace, modelName string) *maasv1alpha1.MaaSAuthPolicy {
-	policies, err := findAllAuthPoliciesForModel(ctx, c, modelNamespace, modelName)
+	policies, err := authPoliciesForModel(ctx, c, modelNamespace, modelName)
 	if err != nil || len(policies) == 0 {
 		return nil
 	}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/keycloak"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+const (
+	keycloakFinalizer = "maas.opendatahub.io/keycloak-cleanup"
+
+	// ConditionKeycloakProvisioned reports whether the subscription's tier was provisioned in
+	// Keycloak. It is only set when the Keycloak integration is enabled.
+	ConditionKeycloakProvisioned = "KeycloakProvisioned"
+)
+
+// TierProvisioner provisions MaaS tiers in an identity provider. *keycloak.Client implements it.
+type TierProvisioner interface {
+	// EnsureTier provisions the tier and returns the owner groups missing from the provider.
+	EnsureTier(ctx context.Context, tier keycloak.Tier) ([]string, error)
+	// DeleteTier removes the tier if it was provisioned for subscription.
+	DeleteTier(ctx context.Context, name, subscription string) error
+}
+
+// KeycloakTierReconciler provisions every MaaSSubscription as a tier in Keycloak, so the IdP's
+// tier claim follows the subscriptions without manual Keycloak work. The tier is granted to the
+// subscription's owner groups; owner users are left to Keycloak administrators.
+type KeycloakTierReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	Provisioner TierProvisioner
+}
+
+// Reconcile provisions or removes the subscription's tier.
+func (r *KeycloakTierReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("MaaSSubscription", req.NamespacedName)
+
+	subscription := &maasv1alpha1.MaaSSubscription{}
+	if err := r.Get(ctx, req.NamespacedName, subscription); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	owner := req.Namespace + "/" + req.Name
+
+	if !subscription.GetDeletionTimestamp().IsZero() {
+		if !controllerutil.ContainsFinalizer(subscription, keycloakFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.Provisioner.DeleteTier(ctx, subscription.Name, owner); err != nil {
+			log.Error(err, "failed to delete Keycloak tier, will retry")
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(subscription, keycloakFinalizer)
+		return ctrl.Result{}, r.Update(ctx, subscription)
+	}
+
+	if !controllerutil.ContainsFinalizer(subscription, keycloakFinalizer) {
+		controllerutil.AddFinalizer(subscription, keycloakFinalizer)
+		if err := r.Update(ctx, subscription); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	tier := keycloak.Tier{Name: subscription.Name, Subscription: owner}
+	for _, g := range subscription.Spec.Owner.Groups {
+		tier.Groups = append(tier.Groups, g.Name)
+	}
+	missing, err := r.Provisioner.EnsureTier(ctx, tier)
+
+	condition := metav1.Condition{
+		Type:               ConditionKeycloakProvisioned,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Provisioned",
+		Message:            fmt.Sprintf("Tier %q is granted to %d group(s)", tier.Name, len(tier.Groups)-len(missing)),
+		ObservedGeneration: subscription.GetGeneration(),
+	}
+	switch {
+	case errors.Is(err, keycloak.ErrNotManaged):
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "Conflict", err.Error()
+	case err != nil:
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "ProvisioningFailed", err.Error()
+	case len(missing) > 0:
+		condition.Reason = "GroupsMissing"
+		condition.Message += "; groups not found in Keycloak: " + strings.Join(missing, ", ")
+	}
+	if statusErr := r.setCondition(ctx, subscription, condition); statusErr != nil {
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil && !errors.Is(err, keycloak.ErrNotManaged) {
+		log.Error(err, "failed to provision Keycloak tier")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// setCondition writes the condition on the latest copy of the subscription, leaving the
+// conditions of MaaSSubscriptionReconciler alone.
+func (r *KeycloakTierReconciler) setCondition(ctx context.Context, subscription *maasv1alpha1.MaaSSubscription, condition metav1.Condition) error {
+	latest := &maasv1alpha1.MaaSSubscription{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(subscription), latest); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !apimeta.SetStatusCondition(&latest.Status.Conditions, condition) {
+		return nil
+	}
+	if err := r.Status().Update(ctx, latest); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KeycloakTierReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("keycloak-tier").
+		For(&maasv1alpha1.MaaSSubscription{}).
+		Complete(tracing.Reconciler("keycloak-tier", r))
+}
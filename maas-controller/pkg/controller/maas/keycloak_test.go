@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/keycloak"
+)
+
+type fakeProvisioner struct {
+	tiers   map[string]keycloak.Tier
+	missing []string
+	err     error
+}
+
+func (f *fakeProvisioner) EnsureTier(_ context.Context, tier keycloak.Tier) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.tiers[tier.Name] = tier
+	return f.missing, nil
+}
+
+func (f *fakeProvisioner) DeleteTier(_ context.Context, name, subscription string) error {
+	if f.tiers[name].Subscription == subscription {
+		delete(f.tiers, name)
+	}
+	return nil
+}
+
+func TestKeycloakTierReconciler(t *testing.T) {
+	ctx := context.Background()
+	sub := &maasv1alpha1.MaaSSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "premium", Namespace: "models-as-a-service"},
+		Spec: maasv1alpha1.MaaSSubscriptionSpec{
+			Owner: maasv1alpha1.OwnerSpec{Groups: []maasv1alpha1.GroupReference{{Name: "premium-users"}, {Name: "ghosts"}}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sub).WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).Build()
+	provisioner := &fakeProvisioner{tiers: map[string]keycloak.Tier{}, missing: []string{"ghosts"}}
+	r := &KeycloakTierReconciler{Client: c, Scheme: scheme, Provisioner: provisioner}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "premium", Namespace: "models-as-a-service"}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	tier, ok := provisioner.tiers["premium"]
+	if !ok || tier.Subscription != "models-as-a-service/premium" || len(tier.Groups) != 2 {
+		t.Fatalf("provisioned tier = %+v, want premium for both owner groups", tier)
+	}
+	got := &maasv1alpha1.MaaSSubscription{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, ConditionKeycloakProvisioned)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "GroupsMissing" {
+		t.Fatalf("KeycloakProvisioned = %+v, want True/GroupsMissing", cond)
+	}
+
+	// A role of the same name that maas-controller did not create is reported, not retried.
+	provisioner.err = fmt.Errorf("%w: premium", keycloak.ErrNotManaged)
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile with unmanaged role: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if cond := apimeta.FindStatusCondition(got.Status.Conditions, ConditionKeycloakProvisioned); cond.Status != metav1.ConditionFalse || cond.Reason != "Conflict" {
+		t.Errorf("KeycloakProvisioned = %s/%s, want False/Conflict", cond.Status, cond.Reason)
+	}
+	provisioner.err = nil
+
+	if err := c.Delete(ctx, got); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile deletion: %v", err)
+	}
+	if _, ok := provisioner.tiers["premium"]; ok {
+		t.Error("tier still provisioned after the subscription was deleted")
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); !apierrors.IsNotFound(err) {
+		t.Errorf("subscription after deletion: err = %v, want NotFound once the finalizer is removed", err)
+	}
+}
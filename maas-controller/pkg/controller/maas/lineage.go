@@ -0,0 +1,252 @@
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// maxModelLineageDepth bounds parentRef chains so a misconfigured chain cannot be walked forever.
+const maxModelLineageDepth = 10
+
+// errModelLineageInvalid is returned when a parentRef chain loops back on itself or is too deep.
+var errModelLineageInvalid = errors.New("invalid model lineage")
+
+// parentKey returns the parent of a model, defaulting the namespace to the model's own.
+func parentKey(model *maasv1alpha1.MaaSModelRef) (types.NamespacedName, bool) {
+	if model.Spec.ParentRef == nil || model.Spec.ParentRef.Name == "" {
+		return types.NamespacedName{}, false
+	}
+	ns := model.Spec.ParentRef.Namespace
+	if ns == "" {
+		ns = model.Namespace
+	}
+	return types.NamespacedName{Namespace: ns, Name: model.Spec.ParentRef.Name}, true
+}
+
+// modelAncestors returns the ancestors of a model, nearest first. A parent that does not exist
+// ends the chain; it is not an error so a child can be created before its parent.
+func modelAncestors(ctx context.Context, c client.Reader, model *maasv1alpha1.MaaSModelRef) ([]maasv1alpha1.MaaSModelRef, error) {
+	var ancestors []maasv1alpha1.MaaSModelRef
+	visited := map[types.NamespacedName]bool{{Namespace: model.Namespace, Name: model.Name}: true}
+	current := model
+	for {
+		key, ok := parentKey(current)
+		if !ok {
+			return ancestors, nil
+		}
+		if visited[key] {
+			return nil, fmt.Errorf("%w: parentRef %s loops back to an earlier model", errModelLineageInvalid, key)
+		}
+		if len(ancestors) == maxModelLineageDepth {
+			return nil, fmt.Errorf("%w: more than %d ancestors", errModelLineageInvalid, maxModelLineageDepth)
+		}
+		visited[key] = true
+
+		parent := &maasv1alpha1.MaaSModelRef{}
+		if err := c.Get(ctx, key, parent); err != nil {
+			if apierrors.IsNotFound(err) {
+				return ancestors, nil
+			}
+			return nil, fmt.Errorf("failed to get parent MaaSModelRef %s: %w", key, err)
+		}
+		ancestors = append(ancestors, *parent)
+		current = parent
+	}
+}
+
+// modelAncestorsByKey is modelAncestors for a model known only by namespace and name. A model
+// that does not exist, or whose chain is invalid, has no ancestors: nothing is inherited until
+// the chain is fixed, and the MaaSModelRef reconciler reports the problem in the model's status.
+func modelAncestorsByKey(ctx context.Context, c client.Reader, modelNamespace, modelName string) ([]maasv1alpha1.MaaSModelRef, error) {
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get MaaSModelRef %s/%s: %w", modelNamespace, modelName, err)
+	}
+	ancestors, err := modelAncestors(ctx, c, model)
+	if errors.Is(err, errModelLineageInvalid) {
+		return nil, nil
+	}
+	return ancestors, err
+}
+
+// modelDescendants returns every MaaSModelRef derived directly or transitively from the given model.
+func modelDescendants(ctx context.Context, c client.Reader, modelNamespace, modelName string) ([]types.NamespacedName, error) {
+	var all maasv1alpha1.MaaSModelRefList
+	if err := c.List(ctx, &all); err != nil {
+		return nil, fmt.Errorf("failed to list MaaSModelRefs: %w", err)
+	}
+	children := map[types.NamespacedName][]types.NamespacedName{}
+	for i := range all.Items {
+		m := &all.Items[i]
+		if parent, ok := parentKey(m); ok {
+			children[parent] = append(children[parent], types.NamespacedName{Namespace: m.Namespace, Name: m.Name})
+		}
+	}
+
+	root := types.NamespacedName{Namespace: modelNamespace, Name: modelName}
+	seen := map[types.NamespacedName]bool{root: true}
+	var result []types.NamespacedName
+	queue := []types.NamespacedName{root}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		for _, child := range children[next] {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			result = append(result, child)
+			queue = append(queue, child)
+		}
+	}
+	return result, nil
+}
+
+// subscriptionModelEntry is the modelRefs entry of a subscription that applies to a model: the
+// model's own entry, or the nearest ancestor's when the subscription does not list the model.
+type subscriptionModelEntry struct {
+	sub  maasv1alpha1.MaaSSubscription
+	mRef maasv1alpha1.ModelSubscriptionRef
+}
+
+// subscriptionEntriesForModel returns the subscriptions that cover a model together with the
+// modelRefs entry that applies, so fine-tunes inherit their base model's tiers and limits. A model
+// pending approval is covered only by its own published subscriptions.
+func subscriptionEntriesForModel(ctx context.Context, c client.Reader, modelNamespace, modelName string) ([]subscriptionModelEntry, error) {
+	pending, err := modelPendingApproval(ctx, c, modelNamespace, modelName)
+	if err != nil {
+		return nil, err
+	}
+	keys := []types.NamespacedName{{Namespace: modelNamespace, Name: modelName}}
+	if !pending {
+		ancestors, err := modelAncestorsByKey(ctx, c, modelNamespace, modelName)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range ancestors {
+			keys = append(keys, types.NamespacedName{Namespace: a.Namespace, Name: a.Name})
+		}
+	}
+
+	var entries []subscriptionModelEntry
+	claimed := map[types.NamespacedName]bool{}
+	for _, key := range keys {
+		subs, err := findAllSubscriptionsForModel(ctx, c, key.Namespace, key.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, sub := range subs {
+			subKey := types.NamespacedName{Namespace: sub.Namespace, Name: sub.Name}
+			if claimed[subKey] || (pending && !isPublishedAccess(&sub)) {
+				continue
+			}
+			for _, mRef := range sub.Spec.ModelRefs {
+				if mRef.Namespace == key.Namespace && mRef.Name == key.Name {
+					entries = append(entries, subscriptionModelEntry{sub: sub, mRef: mRef})
+					claimed[subKey] = true
+					break
+				}
+			}
+		}
+	}
+	return entries, nil
+}
+
+// authPoliciesForModel returns the MaaSAuthPolicies that govern a model: those that reference it
+// directly or, when there are none, those of the nearest ancestor that has any. A model pending
+// approval is governed only by its own published policies.
+func authPoliciesForModel(ctx context.Context, c client.Reader, modelNamespace, modelName string) ([]maasv1alpha1.MaaSAuthPolicy, error) {
+	policies, err := findAllAuthPoliciesForModel(ctx, c, modelNamespace, modelName)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := modelPendingApproval(ctx, c, modelNamespace, modelName)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		var published []maasv1alpha1.MaaSAuthPolicy
+		for _, p := range policies {
+			if isPublishedAccess(&p) {
+				published = append(published, p)
+			}
+		}
+		return published, nil
+	}
+	if len(policies) > 0 {
+		return policies, nil
+	}
+	ancestors, err := modelAncestorsByKey(ctx, c, modelNamespace, modelName)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range ancestors {
+		policies, err := findAllAuthPoliciesForModel(ctx, c, a.Namespace, a.Name)
+		if err != nil || len(policies) > 0 {
+			return policies, err
+		}
+	}
+	return nil, nil
+}
+
+// expandWithDescendants appends the descendants of each model to the list, without duplicates.
+// Models whose descendants cannot be listed are kept on their own.
+func expandWithDescendants(ctx context.Context, c client.Reader, models []types.NamespacedName) []types.NamespacedName {
+	seen := make(map[types.NamespacedName]bool, len(models))
+	var result []types.NamespacedName
+	add := func(key types.NamespacedName) {
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	for _, m := range models {
+		add(m)
+	}
+	for _, m := range models {
+		descendants, err := modelDescendants(ctx, c, m.Namespace, m.Name)
+		if err != nil {
+			continue
+		}
+		for _, d := range descendants {
+			add(d)
+		}
+	}
+	return result
+}
+
+// effectivePricingMultiplier returns the model's pricingMultiplier, else the nearest ancestor's,
+// else "1".
+func effectivePricingMultiplier(model *maasv1alpha1.MaaSModelRef, ancestors []maasv1alpha1.MaaSModelRef) string {
+	if model.Spec.PricingMultiplier != "" {
+		return model.Spec.PricingMultiplier
+	}
+	for _, a := range ancestors {
+		if a.Spec.PricingMultiplier != "" {
+			return a.Spec.PricingMultiplier
+		}
+	}
+	return "1"
+}
+
+// lineageStatus returns status.lineage for a model's ancestors.
+func lineageStatus(ancestors []maasv1alpha1.MaaSModelRef) []string {
+	if len(ancestors) == 0 {
+		return nil
+	}
+	lineage := make([]string, 0, len(ancestors))
+	for _, a := range ancestors {
+		lineage = append(lineage, a.Namespace+"/"+a.Name)
+	}
+	return lineage
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// newVariant returns a MaaSModelRef derived from parent in the same namespace.
+func newVariant(name, ns, parent, pricingMultiplier string) *maasv1alpha1.MaaSModelRef {
+	m := newMaaSModelRef(name, ns, "ExternalModel", name)
+	if parent != "" {
+		m.Spec.ParentRef = &maasv1alpha1.ParentModelReference{Name: parent}
+	}
+	m.Spec.PricingMultiplier = pricingMultiplier
+	return m
+}
+
+func TestMaaSModelRefReconciler_Lineage(t *testing.T) {
+	const testKind = "_test_lineage_kind"
+	backendHandlerFactories[testKind] = func(_ *MaaSModelRefReconciler) BackendHandler {
+		return &fakeHandler{endpoint: "https://model.example.com", ready: true}
+	}
+	defer delete(backendHandlerFactories, testKind)
+
+	withKind := func(m *maasv1alpha1.MaaSModelRef) *maasv1alpha1.MaaSModelRef {
+		m.Spec.ModelRef.Kind = testKind
+		return m
+	}
+	base := withKind(newVariant("granite", "llm", "", "2"))
+	tuned := withKind(newVariant("granite-legal", "llm", "granite", ""))
+	tunedAgain := withKind(newVariant("granite-legal-v2", "llm", "granite-legal", "3"))
+	loopA := withKind(newVariant("loop-a", "llm", "loop-b", ""))
+	loopB := withKind(newVariant("loop-b", "llm", "loop-a", ""))
+
+	r, c := newTestReconciler(base, tuned, tunedAgain, loopA, loopB)
+
+	tests := []struct {
+		name           string
+		wantPhase      string
+		wantLineage    []string
+		wantMultiplier string
+	}{
+		{name: "granite", wantPhase: "Ready", wantMultiplier: "2"},
+		{name: "granite-legal", wantPhase: "Ready", wantLineage: []string{"llm/granite"}, wantMultiplier: "2"},
+		{name: "granite-legal-v2", wantPhase: "Ready", wantLineage: []string{"llm/granite-legal", "llm/granite"}, wantMultiplier: "3"},
+		{name: "loop-a", wantPhase: "Failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: tt.name, Namespace: "llm"}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			got := &maasv1alpha1.MaaSModelRef{}
+			if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got.Status.Phase != tt.wantPhase {
+				t.Errorf("Status.Phase = %q, want %q", got.Status.Phase, tt.wantPhase)
+			}
+			if !slices.Equal(got.Status.Lineage, tt.wantLineage) {
+				t.Errorf("Status.Lineage = %v, want %v", got.Status.Lineage, tt.wantLineage)
+			}
+			if got.Status.EffectivePricingMultiplier != tt.wantMultiplier {
+				t.Errorf("Status.EffectivePricingMultiplier = %q, want %q", got.Status.EffectivePricingMultiplier, tt.wantMultiplier)
+			}
+			if tt.wantPhase == "Failed" {
+				assertReadyCondition(t, got.Status.Conditions, metav1.ConditionFalse, "InvalidParentRef")
+			}
+		})
+	}
+
+	requests := r.mapMaaSModelRefToVariants(context.Background(), base)
+	if len(requests) != 2 {
+		t.Errorf("mapMaaSModelRefToVariants(granite) = %v, want granite-legal and granite-legal-v2", requests)
+	}
+}
+
+// TestMaaSSubscriptionReconciler_VariantInheritsSubscriptions verifies a fine-tune gets the
+// limits of subscriptions that list its parent, unless a subscription lists it directly.
+func TestMaaSSubscriptionReconciler_VariantInheritsSubscriptions(t *testing.T) {
+	const (
+		modelNamespace = "llm"
+		subNS          = "opendatahub"
+	)
+	base := newVariant("granite", modelNamespace, "", "")
+	tuned := newVariant("granite-legal", modelNamespace, "granite", "")
+	baseRoute := &gatewayapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "maas-model-granite", Namespace: modelNamespace}}
+	tunedRoute := &gatewayapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "maas-model-granite-legal", Namespace: modelNamespace}}
+
+	inherited := &maasv1alpha1.MaaSSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: subNS},
+		Spec: maasv1alpha1.MaaSSubscriptionSpec{
+			Owner: maasv1alpha1.OwnerSpec{Groups: []maasv1alpha1.GroupReference{{Name: "team-1"}}},
+			ModelRefs: []maasv1alpha1.ModelSubscriptionRef{
+				{Name: "granite", Namespace: modelNamespace, TokenRateLimits: []maasv1alpha1.TokenRateLimit{{Limit: 100, Window: "1m"}}},
+			},
+		},
+	}
+	overriding := &maasv1alpha1.MaaSSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "legal", Namespace: subNS},
+		Spec: maasv1alpha1.MaaSSubscriptionSpec{
+			Owner: maasv1alpha1.OwnerSpec{Groups: []maasv1alpha1.GroupReference{{Name: "team-2"}}},
+			ModelRefs: []maasv1alpha1.ModelSubscriptionRef{
+				{Name: "granite", Namespace: modelNamespace, TokenRateLimits: []maasv1alpha1.TokenRateLimit{{Limit: 200, Window: "1m"}}},
+				{Name: "granite-legal", Namespace: modelNamespace, TokenRateLimits: []maasv1alpha1.TokenRateLimit{{Limit: 50, Window: "1m"}}},
+			},
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(base, tuned, baseRoute, tunedRoute, inherited, overriding).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+
+	// Reconciling the subscription that only lists the parent must also build the variant's policy.
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "basic", Namespace: subNS}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	trlp := &unstructured.Unstructured{}
+	trlp.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"})
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "maas-trlp-granite-legal", Namespace: modelNamespace}, trlp); err != nil {
+		t.Fatalf("TokenRateLimitPolicy for variant not found: %v", err)
+	}
+	limits, _, _ := unstructured.NestedMap(trlp.Object, "spec", "limits")
+
+	wantLimits := map[string]int64{
+		subNS + "-basic-granite-legal-tokens": 100, // inherited from the parent entry
+		subNS + "-legal-granite-legal-tokens": 50,  // the subscription's own entry wins
+	}
+	if len(limits) != len(wantLimits) {
+		t.Fatalf("limits = %v, want keys %v", getKeys(limits), wantLimits)
+	}
+	for key, wantLimit := range wantLimits {
+		limit, ok := limits[key].(map[string]any)
+		if !ok {
+			t.Fatalf("limit %q missing, got %v", key, getKeys(limits))
+		}
+		rates, _, _ := unstructured.NestedSlice(limit, "rates")
+		if got := rates[0].(map[string]any)["limit"]; got != wantLimit {
+			t.Errorf("%s rate limit = %v, want %d", key, got, wantLimit)
+		}
+		when, _, _ := unstructured.NestedSlice(limit, "when")
+		subName := "basic"
+		if wantLimit == 50 {
+			subName = "legal"
+		}
+		wantPredicate := fmt.Sprintf(`auth.identity.selected_subscription_key == "%s/%s@%s/granite-legal"`, subNS, subName, modelNamespace)
+		if got := when[0].(map[string]any)["predicate"]; got != wantPredicate {
+			t.Errorf("%s predicate = %v, want %s", key, got, wantPredicate)
+		}
+	}
+
+	requests := r.mapMaaSModelRefToMaaSSubscriptions(context.Background(), tuned)
+	if len(requests) != 2 {
+		t.Errorf("mapMaaSModelRefToMaaSSubscriptions(variant) = %v, want both subscriptions", requests)
+	}
+}
+
+// TestPendingModelGovernedByPublishedAccessOnly verifies a model published through maas-api is
+// reachable only through its private pair until an admin removes the approval annotation.
+func TestPendingModelGovernedByPublishedAccessOnly(t *testing.T) {
+	ctx := context.Background()
+	base := newVariant("granite", "llm", "", "")
+	tuned := newVariant("granite-legal", "llm", "granite", "")
+	tuned.Annotations = map[string]string{AnnotationApproval: approvalPending, AnnotationAllowedGroups: "everyone"}
+	published := map[string]string{LabelPublished: "true"}
+
+	basePolicy := newMaaSAuthPolicy("base", "opendatahub", "everyone", maasv1alpha1.ModelRef{Name: "granite", Namespace: "llm"})
+	broadPolicy := newMaaSAuthPolicy("broad", "opendatahub", "everyone", maasv1alpha1.ModelRef{Name: "granite-legal", Namespace: "llm"})
+	privatePolicy := newMaaSAuthPolicy("private", "opendatahub", "", maasv1alpha1.ModelRef{Name: "granite-legal", Namespace: "llm"})
+	privatePolicy.Labels = published
+	baseSub := newMaaSSubscription("base", "opendatahub", "everyone", "granite", 1000)
+	baseSub.Spec.ModelRefs[0].Namespace = "llm"
+	broadSub := newMaaSSubscription("broad", "opendatahub", "everyone", "granite-legal", 1000)
+	broadSub.Spec.ModelRefs[0].Namespace = "llm"
+	privateSub := newMaaSSubscription("private", "opendatahub", "", "granite-legal", 10)
+	privateSub.Spec.ModelRefs[0].Namespace = "llm"
+	privateSub.Labels = published
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(base, tuned, basePolicy, broadPolicy, privatePolicy, baseSub, broadSub, privateSub).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+
+	check := func(wantPolicies, wantSubs []string, wantGroups []string) {
+		t.Helper()
+		policies, err := authPoliciesForModel(ctx, c, "llm", "granite-legal")
+		if err != nil {
+			t.Fatalf("authPoliciesForModel: %v", err)
+		}
+		var gotPolicies []string
+		for _, p := range policies {
+			gotPolicies = append(gotPolicies, p.Name)
+		}
+		entries, err := subscriptionEntriesForModel(ctx, c, "llm", "granite-legal")
+		if err != nil {
+			t.Fatalf("subscriptionEntriesForModel: %v", err)
+		}
+		var gotSubs []string
+		for _, e := range entries {
+			gotSubs = append(gotSubs, e.sub.Name)
+		}
+		groups, _, err := modelAllowList(ctx, c, "llm", "granite-legal")
+		if err != nil {
+			t.Fatalf("modelAllowList: %v", err)
+		}
+		slices.Sort(gotPolicies)
+		slices.Sort(gotSubs)
+		if !slices.Equal(gotPolicies, wantPolicies) || !slices.Equal(gotSubs, wantSubs) || !slices.Equal(groups, wantGroups) {
+			t.Errorf("policies = %v, subscriptions = %v, allowed groups = %v; want %v, %v, %v",
+				gotPolicies, gotSubs, groups, wantPolicies, wantSubs, wantGroups)
+		}
+	}
+
+	check([]string{"private"}, []string{"private"}, nil)
+
+	delete(tuned.Annotations, AnnotationApproval)
+	if err := c.Update(ctx, tuned); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	check([]string{"broad", "private"}, []string{"base", "broad", "private"}, []string{"everyone"})
+}
@@ -21,10 +21,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"sort"
 	"strings"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -33,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,8 +46,13 @@ import (
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
 )
 
+// defaultUpstreamCredentialsSecret is the Secret holding the token Authorino presents to maas-api's
+// upstream credential endpoint when UpstreamCredentialsSecret is unset.
+const defaultUpstreamCredentialsSecret = "maas-upstream-credentials"
+
 // MaaSAuthPolicyReconciler reconciles a MaaSAuthPolicy object
 type MaaSAuthPolicyReconciler struct {
 	client.Client
@@ -53,12 +61,23 @@ type MaaSAuthPolicyReconciler struct {
 	// Used to construct the subscription selector endpoint URL.
 	MaaSAPINamespace string
 
+	// MaaSAPIShards, when above 1, sends each model's authorization requests to the maas-api shard
+	// serving its namespace, Service maas-api-shard-<n>, instead of Service maas-api.
+	MaaSAPIShards int
+
 	// GatewayName is the name of the Gateway used for model HTTPRoutes (configurable via flags).
 	GatewayName string
 
 	// ClusterAudience is the OIDC audience of the cluster (configurable via flags).
 	// Standard clusters use "https://kubernetes.default.svc"; HyperShift/ROSA use a custom OIDC provider URL.
 	ClusterAudience string
+
+	// UpstreamCredentialsSecret is the Secret in the Kuadrant namespace whose "token" key Authorino
+	// presents to maas-api to fetch the credentials of ExternalModels with spec.auth.
+	UpstreamCredentialsSecret string
+
+	// Recorder records events on the MaaSAuthPolicies; nil records none.
+	Recorder record.EventRecorder
 }
 
 func (r *MaaSAuthPolicyReconciler) clusterAudience() string {
@@ -106,6 +125,14 @@ func (r *MaaSAuthPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	statusSnapshot := policy.Status.DeepCopy()
 
 	refs, err := r.reconcileModelAuthPolicies(ctx, log, policy)
+	if errors.Is(err, ErrPolicyEngineUnavailable) {
+		// Kuadrant is not installed: keep routes working and retry later instead of failing.
+		log.Info("Kuadrant AuthPolicy CRD not installed, skipping AuthPolicy generation", "error", err.Error())
+		setPolicyEngineCondition(&policy.Status.Conditions, err, policy.GetGeneration())
+		r.updateStatus(ctx, policy, "Pending", "AuthPolicy generation skipped: Kuadrant is not installed", statusSnapshot)
+		return ctrl.Result{RequeueAfter: policyEngineRecheckInterval}, nil
+	}
+	setPolicyEngineCondition(&policy.Status.Conditions, nil, policy.GetGeneration())
 	if err != nil {
 		log.Error(err, "failed to reconcile model AuthPolicies")
 		r.updateStatus(ctx, policy, "Failed", fmt.Sprintf("Failed to reconcile: %v", err), statusSnapshot)
@@ -129,7 +156,8 @@ func (r *MaaSAuthPolicyReconciler) reconcileModelAuthPolicies(ctx context.Contex
 	// Model-centric approach: for each model referenced by this auth policy,
 	// find ALL auth policies for that model and build a single aggregated AuthPolicy.
 	// Kuadrant only allows one AuthPolicy per HTTPRoute target.
-	for _, ref := range policy.Spec.ModelRefs {
+	// Variants (fine-tunes) of a referenced model inherit its policies, so they are rebuilt too.
+	for _, ref := range r.modelsCoveredBy(ctx, policy) {
 		httpRouteName, httpRouteNS, err := findHTTPRouteForModel(ctx, r.Client, ref.Namespace, ref.Name)
 		if err != nil {
 			if errors.Is(err, ErrModelNotFound) {
@@ -155,8 +183,9 @@ func (r *MaaSAuthPolicyReconciler) reconcileModelAuthPolicies(ctx context.Contex
 			return nil, fmt.Errorf("invalid model name in modelRef %s/%s: %w", ref.Namespace, ref.Name, err)
 		}
 
-		// Find ALL auth policies for this model (not just the current one)
-		allPolicies, err := findAllAuthPoliciesForModel(ctx, r.Client, ref.Namespace, ref.Name)
+		// Find ALL auth policies for this model (not just the current one), falling back to
+		// the nearest ancestor's when none reference the model directly
+		allPolicies, err := authPoliciesForModel(ctx, r.Client, ref.Namespace, ref.Name)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list auth policies for model %s/%s: %w", ref.Namespace, ref.Name, err)
 		}
@@ -183,26 +212,61 @@ func (r *MaaSAuthPolicyReconciler) reconcileModelAuthPolicies(ctx context.Contex
 			}
 		}
 
+		// The model's own allow-list annotations add subjects on top of its policies
+		modelGroups, modelUsers, err := modelAllowList(ctx, r.Client, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read allow-list of model %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		for _, group := range modelGroups {
+			if err := validateCELValue(group, "group name"); err != nil {
+				return nil, fmt.Errorf("invalid %s on model %s/%s: %w", AnnotationAllowedGroups, ref.Namespace, ref.Name, err)
+			}
+		}
+		for _, user := range modelUsers {
+			if err := validateCELValue(user, "username"); err != nil {
+				return nil, fmt.Errorf("invalid %s on model %s/%s: %w", AnnotationAllowedUsers, ref.Namespace, ref.Name, err)
+			}
+		}
+		allowedGroups = append(allowedGroups, modelGroups...)
+		allowedUsers = append(allowedUsers, modelUsers...)
+
+		errorResponses, err := modelErrorResponses(ctx, r.Client, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read error responses of model %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		denialBody, denialContentType, err := denialResponse(errorResponses)
+		if err != nil {
+			return nil, fmt.Errorf("invalid errorResponses on model %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+
+		extModelName, upstreamHeader, err := modelUpstreamAuth(ctx, r.Client, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read upstream auth of model %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+
 		// Deduplicate and sort to ensure stable output across reconciles
 		// (Kubernetes List order is not guaranteed to be deterministic)
 		policyNames = deduplicateAndSort(policyNames)
 		allowedGroups = deduplicateAndSort(allowedGroups)
 		allowedUsers = deduplicateAndSort(allowedUsers)
 
-		// Construct API URLs using configured namespace
-		apiKeyValidationURL := fmt.Sprintf("https://maas-api.%s.svc.cluster.local:8443/internal/v1/api-keys/validate", r.MaaSAPINamespace)
-		subscriptionSelectorURL := fmt.Sprintf("https://maas-api.%s.svc.cluster.local:8443/internal/v1/subscriptions/select", r.MaaSAPINamespace)
+		// Construct API URLs using configured namespace and the model's shard
+		maasAPI := r.maasAPIBaseURL(ref.Namespace)
+		apiKeyValidationURL := maasAPI + "/internal/v1/api-keys/validate"
+		subscriptionSelectorURL := maasAPI + "/internal/v1/subscriptions/select"
 
 		rule := map[string]any{
 			"metadata": map[string]any{
-				// API Key Validation - validates the API key and returns user identity + groups
+				// API Key Validation - validates the API key and returns user identity + groups.
+				// Requests without an Authorization header send their path instead, so maas-api can
+				// validate a signed URL (maas-signature query parameter) as a key scoped to its model.
 				"apiKeyValidation": map[string]any{
 					"http": map[string]any{
 						"url":         apiKeyValidationURL,
 						"contentType": "application/json",
 						"method":      "POST",
 						"body": map[string]any{
-							"expression": `{"key": request.headers.authorization.replace("Bearer ", "")}`,
+							"expression": `"authorization" in request.headers ? {"key": request.headers.authorization.replace("Bearer ", "")} : {"signedUrl": request.path}`,
 						},
 					},
 					"metrics":  false,
@@ -222,18 +286,20 @@ func (r *MaaSAuthPolicyReconciler) reconcileModelAuthPolicies(ctx context.Contex
   "groups": auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.groups : auth.identity.user.groups,
   "username": auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.username : auth.identity.user.username,
   "requestedSubscription": auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.subscription : ("x-maas-subscription" in request.headers ? request.headers["x-maas-subscription"] : ""),
-  "requestedModel": "%s/%s"
+  "requestedModel": "%s/%s",
+  "host": request.host
 }`, ref.Namespace, ref.Name),
 						},
 					},
-					// Cache subscription selection results keyed by username, groups, requested subscription, and model.
-					// Each model has its own cache entry since subscription validation is model-specific.
-					// Key format: "username|groups-hash|requested-subscription|model-namespace/model-name"
+					// Cache subscription selection results keyed by username, groups, requested subscription, model and host.
+					// Each model has its own cache entry since subscription validation is model-specific, and each host
+					// its own since tier listeners only accept the subscriptions bound to them.
+					// Key format: "username|groups-hash|requested-subscription|model-namespace/model-name|host"
 					// Groups are joined with commas to create a stable string representation.
 					"cache": map[string]any{
 						"key": map[string]any{
 							//nolint:lll // CEL expression must be on single line
-							"selector": fmt.Sprintf(`(auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.username : auth.identity.user.username) + "|" + (auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.groups : auth.identity.user.groups).join(",") + "|" + (auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.subscription : ("x-maas-subscription" in request.headers ? request.headers["x-maas-subscription"] : "")) + "|%s/%s"`, ref.Namespace, ref.Name),
+							"selector": fmt.Sprintf(`(auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.username : auth.identity.user.username) + "|" + (auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.groups : auth.identity.user.groups).join(",") + "|" + (auth.metadata.apiKeyValidation.valid == true ? auth.metadata.apiKeyValidation.subscription : ("x-maas-subscription" in request.headers ? request.headers["x-maas-subscription"] : "")) + "|%s/%s|" + request.host`, ref.Namespace, ref.Name),
 						},
 						"ttl": int64(60),
 					},
@@ -258,6 +324,27 @@ func (r *MaaSAuthPolicyReconciler) reconcileModelAuthPolicies(ctx context.Contex
 					"metrics":  false,
 					"priority": int64(0),
 				},
+				// Signed URLs minted with POST /v1/models/{name}/signed-urls - plain authentication,
+				// the signature is verified by the apiKeyValidation metadata like an API key
+				"signed-urls": map[string]any{
+					"plain": map[string]any{
+						"selector": "request.path",
+					},
+					"when": []any{
+						map[string]any{
+							"selector": "request.path",
+							"operator": "matches",
+							"value":    `[?&]maas-signature=`,
+						},
+						map[string]any{
+							"selector": "request.headers.authorization",
+							"operator": "eq",
+							"value":    "",
+						},
+					},
+					"metrics":  false,
+					"priority": int64(0),
+				},
 				// Kubernetes/OpenShift tokens - validated via TokenReview API
 				// Only enabled for /v1/models endpoint (read-only model listing)
 				// Inferencing endpoints require API keys for billing/tracking
@@ -317,6 +404,55 @@ allow {
 			},
 		}
 
+		// API keys minted with a model scope (e.g. through POST /v1/tokens) only work for those models.
+		// Keys without a scope, and K8s tokens, pass.
+		authRules["api-key-model-scope"] = map[string]any{
+			"metrics":  false,
+			"priority": int64(0),
+			"opa": map[string]any{
+				"rego": fmt.Sprintf(`scope := object.get(object.get(input.auth.metadata, "apiKeyValidation", {}), "models", [])
+
+allow { count(scope) == 0 }
+
+allow { scope[_] == "%s/%s" }`, ref.Namespace, ref.Name),
+			},
+		}
+
+		// External models with spec.auth get the provider credential from maas-api on each request,
+		// so it never appears in a generated resource. A request is denied rather than forwarded
+		// with the caller's own headers when the credential cannot be fetched.
+		if upstreamHeader != "" {
+			if err := unstructured.SetNestedField(rule, map[string]any{
+				"http": map[string]any{
+					"url":    r.upstreamCredentialURL(ref.Namespace, extModelName),
+					"method": "GET",
+					"sharedSecretRef": map[string]any{
+						"name": r.upstreamCredentialsSecret(),
+						"key":  "token",
+					},
+					"credentials": map[string]any{
+						"authorizationHeader": map[string]any{"prefix": "Bearer"},
+					},
+				},
+				// One entry per model, so a rotated credential is picked up within a minute
+				"cache": map[string]any{
+					"key": map[string]any{"value": ref.Namespace + "/" + ref.Name},
+					"ttl": int64(60),
+				},
+				"metrics":  false,
+				"priority": int64(0),
+			}, "metadata", "upstreamCredential"); err != nil {
+				return nil, fmt.Errorf("failed to set upstream credential metadata: %w", err)
+			}
+			authRules["upstream-credential"] = map[string]any{
+				"metrics":  false,
+				"priority": int64(0),
+				"opa": map[string]any{
+					"rego": `allow { object.get(object.get(input.auth.metadata, "upstreamCredential", {}), "value", "") != "" }`,
+				},
+			}
+		}
+
 		// Build aggregated authorization rule from ALL auth policies' subjects
 		// Uses OPA to check membership for both API keys and K8s tokens
 		if len(allowedGroups) > 0 || len(allowedUsers) > 0 {
@@ -410,6 +546,71 @@ allow {
 						"metrics":  false,
 						"priority": int64(0),
 					},
+					// "true" for sandbox subscriptions; Envoy then re-matches onto the sandbox route.
+					// Always set, so a client-supplied value is overwritten.
+					SandboxHeader: map[string]any{
+						"plain": map[string]any{
+							"expression": `has(auth.metadata["subscription-info"].sandbox) && auth.metadata["subscription-info"].sandbox == true ? "true" : ""`,
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
+					// Model-scoped subscription key, so the quota EnvoyFilter can report the response's
+					// token usage to maas-api against the right budget.
+					"X-MaaS-Subscription-Key": map[string]any{
+						"plain": map[string]any{
+							//nolint:lll // CEL expression must be on single line
+							"expression": fmt.Sprintf(
+								`has(auth.metadata["subscription-info"].namespace) && has(auth.metadata["subscription-info"].name) ? auth.metadata["subscription-info"].namespace + "/" + auth.metadata["subscription-info"].name + "@%s/%s" : ""`,
+								ref.Namespace, ref.Name,
+							),
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
+					// Soft quota warning from subscription selection (empty below the threshold).
+					// The gateway's quota-warning EnvoyFilter moves it onto the client response.
+					"X-MaaS-Quota-Warning": map[string]any{
+						"plain": map[string]any{
+							"expression": `has(auth.metadata["subscription-info"].quotaWarning) ? auth.metadata["subscription-info"].quotaWarning : ""`,
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
+					// Set while the caller only reaches the subscription through its downgrade grace
+					// period; the same EnvoyFilter returns it to the client.
+					"X-MaaS-Subscription-Warning": map[string]any{
+						"plain": map[string]any{
+							"expression": `has(auth.metadata["subscription-info"].warning) ? auth.metadata["subscription-info"].warning : ""`,
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
+					// Tier, model and remaining tokens for the quota headers. The same EnvoyFilter moves
+					// them into dynamic metadata, which the model's quota-headers route filter sets as
+					// X-MaaS-Tier, X-MaaS-Model and X-MaaS-Remaining-Tokens on the response.
+					quotaHeaderTier: map[string]any{
+						"plain": map[string]any{
+							"expression": `has(auth.metadata["subscription-info"].name) ? auth.metadata["subscription-info"].name : ""`,
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
+					quotaHeaderModel: map[string]any{
+						"plain": map[string]any{
+							"expression": fmt.Sprintf(`"%s/%s"`, ref.Namespace, ref.Name),
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
+					quotaHeaderRemainingTokens: map[string]any{
+						"plain": map[string]any{
+							//nolint:lll // CEL expression must be on single line
+							"expression": `has(auth.metadata["subscription-info"].remainingTokens) ? string(int(auth.metadata["subscription-info"].remainingTokens)) : ""`,
+						},
+						"metrics":  false,
+						"priority": int64(0),
+					},
 				},
 				"filters": map[string]any{
 					"identity": map[string]any{
@@ -435,6 +636,10 @@ allow {
 										ref.Namespace, ref.Name,
 									),
 								},
+								// How the subscription was chosen (header, single, priority, cheapest) for usage attribution
+								"subscription_selected_by": map[string]any{
+									"expression": `has(auth.metadata["subscription-info"].selectedBy) ? auth.metadata["subscription-info"].selectedBy : ""`,
+								},
 								"organizationId": map[string]any{
 									"expression": `has(auth.metadata["subscription-info"].organizationId) ? auth.metadata["subscription-info"].organizationId : ""`,
 								},
@@ -444,6 +649,15 @@ allow {
 								"subscription_labels": map[string]any{
 									"expression": `has(auth.metadata["subscription-info"].labels) ? auth.metadata["subscription-info"].labels : {}`,
 								},
+								// Subscription limits for this model, per minute (0 when unlimited), for Limitador descriptors
+								"rate_limit_rpm": map[string]any{
+									//nolint:lll // CEL expression must be on single line
+									"expression": `has(auth.metadata["subscription-info"].rateLimits) && has(auth.metadata["subscription-info"].rateLimits.requestsPerMinute) ? auth.metadata["subscription-info"].rateLimits.requestsPerMinute : 0`,
+								},
+								"rate_limit_tpm": map[string]any{
+									//nolint:lll // CEL expression must be on single line
+									"expression": `has(auth.metadata["subscription-info"].rateLimits) && has(auth.metadata["subscription-info"].rateLimits.tokensPerMinute) ? auth.metadata["subscription-info"].rateLimits.tokensPerMinute : 0`,
+								},
 								// Error information (for debugging - only populated when selection fails)
 								"subscription_error": map[string]any{
 									"expression": `has(auth.metadata["subscription-info"].error) ? auth.metadata["subscription-info"].error : ""`,
@@ -457,7 +671,8 @@ allow {
 					},
 				},
 			},
-			// Custom denial responses that include subscription error details
+			// Custom denial responses that include subscription error details, or the
+			// model's own error responses for the reasons it customizes
 			"unauthenticated": map[string]any{
 				"code": int64(401),
 				"message": map[string]any{
@@ -466,32 +681,48 @@ allow {
 			},
 			"unauthorized": map[string]any{
 				"code": int64(403),
-				"body": map[string]any{
-					"expression": `has(auth.metadata["subscription-info"].message) ? auth.metadata["subscription-info"].message : "Access denied"`,
-				},
+				"body": denialBody,
 				"headers": map[string]any{
 					"x-ext-auth-reason": map[string]any{
 						"expression": `has(auth.metadata["subscription-info"].error) ? auth.metadata["subscription-info"].error : "unauthorized"`,
 					},
-					"content-type": map[string]any{
-						"value": "text/plain",
-					},
+					"content-type": denialContentType,
 				},
 			},
 		}
 
+		if upstreamHeader != "" {
+			if err := unstructured.SetNestedField(rule, map[string]any{
+				"plain": map[string]any{
+					"selector": "auth.metadata.upstreamCredential.value",
+				},
+				"metrics":  false,
+				"priority": int64(0),
+			}, "response", "success", "headers", upstreamHeader); err != nil {
+				return nil, fmt.Errorf("failed to set upstream credential header: %w", err)
+			}
+		}
+
 		// Build the aggregated AuthPolicy (one per model, covering all MaaSAuthPolicies)
-		authPolicyName := fmt.Sprintf("maas-auth-%s", ref.Name)
+		naming, err := namingFor(ctx, r.Client, ref.Namespace, ref.Name)
+		if err != nil {
+			return nil, err
+		}
+		authPolicyName, err := naming.name(nameTemplateAuthPolicy, fmt.Sprintf("maas-auth-%s", ref.Name))
+		if err != nil {
+			return nil, fmt.Errorf("model %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
 		authPolicy := &unstructured.Unstructured{}
 		authPolicy.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1", Kind: "AuthPolicy"})
 		authPolicy.SetName(authPolicyName)
 		authPolicy.SetNamespace(httpRouteNS)
-		authPolicy.SetLabels(map[string]string{
-			"maas.opendatahub.io/model":    ref.Name,
-			"app.kubernetes.io/managed-by": "maas-controller",
-			"app.kubernetes.io/part-of":    "maas-auth-policy",
-			"app.kubernetes.io/component":  "auth-policy",
-		})
+		authPolicy.SetLabels(naming.withLabels(map[string]string{
+			"maas.opendatahub.io/model":           ref.Name,
+			"maas.opendatahub.io/model-namespace": ref.Namespace,
+			managedByLabel:                        managedByValue,
+			"app.kubernetes.io/part-of":           "maas-auth-policy",
+			"app.kubernetes.io/component":         "auth-policy",
+		}))
 		authPolicy.SetAnnotations(map[string]string{
 			"maas.opendatahub.io/auth-policies": strings.Join(policyNames, ","),
 		})
@@ -514,17 +745,30 @@ allow {
 		existing := &unstructured.Unstructured{}
 		existing.SetGroupVersionKind(authPolicy.GroupVersionKind())
 		err = r.Get(ctx, client.ObjectKeyFromObject(authPolicy), existing)
+		if apimeta.IsNoMatchError(err) {
+			return nil, policyEngineError(err, authPolicyGVK)
+		}
 		if apierrors.IsNotFound(err) {
 			if err := r.Create(ctx, authPolicy); err != nil {
 				return nil, fmt.Errorf("failed to create AuthPolicy for model %s/%s: %w", ref.Namespace, ref.Name, err)
 			}
 			log.Info("AuthPolicy created", "name", authPolicyName, "model", ref.Namespace+"/"+ref.Name, "policies", policyNames)
+			eventf(r.Recorder, policy, corev1.EventTypeNormal, "AuthPolicyCreated", "Created AuthPolicy %s/%s for model %s/%s", httpRouteNS, authPolicyName, ref.Namespace, ref.Name)
 		} else if err != nil {
 			return nil, fmt.Errorf("failed to get existing AuthPolicy: %w", err)
 		} else {
 			if !isManaged(existing) {
 				log.Info("AuthPolicy opted out, skipping", "name", authPolicyName)
+			} else if !isOwnedOrAdoptable(existing) {
+				log.Info("AuthPolicy exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it",
+					"name", authPolicyName, "namespace", httpRouteNS, "model", ref.Namespace+"/"+ref.Name)
+				eventf(r.Recorder, policy, corev1.EventTypeWarning, "AuthPolicyNotAdopted",
+					"AuthPolicy %s/%s for model %s/%s exists and is not managed by maas-controller; annotate it with %s=true to adopt it",
+					httpRouteNS, authPolicyName, ref.Namespace, ref.Name, AdoptAnnotation)
 			} else {
+				if existing.GetLabels()[managedByLabel] != managedByValue {
+					log.Info("Adopting pre-existing AuthPolicy", "name", authPolicyName, "namespace", httpRouteNS, "model", ref.Namespace+"/"+ref.Name)
+				}
 				// Snapshot the existing object before modifications so we can detect
 				// no-op updates.
 				snapshot := existing.DeepCopy()
@@ -557,13 +801,36 @@ allow {
 						return nil, fmt.Errorf("failed to update AuthPolicy for model %s/%s: %w", ref.Namespace, ref.Name, err)
 					}
 					log.Info("AuthPolicy updated", "name", authPolicyName, "model", ref.Namespace+"/"+ref.Name, "policies", policyNames)
+					eventf(r.Recorder, policy, corev1.EventTypeNormal, "AuthPolicyUpdated", "Updated AuthPolicy %s/%s for model %s/%s", httpRouteNS, authPolicyName, ref.Namespace, ref.Name)
 				}
 			}
 		}
+
+		// A changed name template leaves the AuthPolicy of the old name behind.
+		deleted, err := deleteRenamedPolicies(ctx, r.Client, authPolicyGVK, client.MatchingLabels{
+			"maas.opendatahub.io/model":           ref.Name,
+			"maas.opendatahub.io/model-namespace": ref.Namespace,
+			"app.kubernetes.io/part-of":           "maas-auth-policy",
+		}, client.ObjectKeyFromObject(authPolicy))
+		if err != nil {
+			return nil, err
+		}
+		if len(deleted) > 0 {
+			log.Info("Deleted renamed AuthPolicies", "policies", deleted, "model", ref.Namespace+"/"+ref.Name)
+		}
 	}
 	return refs, nil
 }
 
+// modelsCoveredBy returns the models a policy references plus their descendants.
+func (r *MaaSAuthPolicyReconciler) modelsCoveredBy(ctx context.Context, policy *maasv1alpha1.MaaSAuthPolicy) []types.NamespacedName {
+	refs := make([]types.NamespacedName, 0, len(policy.Spec.ModelRefs))
+	for _, ref := range policy.Spec.ModelRefs {
+		refs = append(refs, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+	}
+	return expandWithDescendants(ctx, r.Client, refs)
+}
+
 // deleteModelAuthPolicy deletes the aggregated AuthPolicy for a model in the given namespace.
 func (r *MaaSAuthPolicyReconciler) deleteModelAuthPolicy(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
 	// Always delete the aggregated AuthPolicy so remaining MaaSAuthPolicies rebuild it
@@ -598,12 +865,13 @@ func (r *MaaSAuthPolicyReconciler) deleteModelAuthPolicy(ctx context.Context, lo
 
 func (r *MaaSAuthPolicyReconciler) handleDeletion(ctx context.Context, log logr.Logger, policy *maasv1alpha1.MaaSAuthPolicy) (ctrl.Result, error) {
 	if controllerutil.ContainsFinalizer(policy, maasAuthPolicyFinalizer) {
-		for _, ref := range policy.Spec.ModelRefs {
+		for _, ref := range r.modelsCoveredBy(ctx, policy) {
 			log.Info("Deleting model AuthPolicy so remaining policies can rebuild it", "model", ref.Namespace+"/"+ref.Name)
 			if err := r.deleteModelAuthPolicy(ctx, log, ref.Namespace, ref.Name); err != nil {
 				log.Error(err, "failed to clean up AuthPolicy, will retry", "model", ref.Namespace+"/"+ref.Name)
 				return ctrl.Result{}, err
 			}
+			eventf(r.Recorder, policy, corev1.EventTypeNormal, "AuthPolicyDeleted", "Deleted AuthPolicy of model %s/%s so the remaining MaaSAuthPolicies rebuild it", ref.Namespace, ref.Name)
 		}
 		controllerutil.RemoveFinalizer(policy, maasAuthPolicyFinalizer)
 		if err := r.Update(ctx, policy); err != nil {
@@ -662,9 +930,13 @@ func (r *MaaSAuthPolicyReconciler) updateStatus(ctx context.Context, policy *maa
 
 	status := metav1.ConditionTrue
 	reason := "Reconciled"
-	if phase == "Failed" {
+	switch phase {
+	case "Failed":
 		status = metav1.ConditionFalse
 		reason = "ReconcileFailed"
+	case "Pending":
+		status = metav1.ConditionFalse
+		reason = "PolicyEngineUnavailable"
 	}
 
 	apimeta.SetStatusCondition(&policy.Status.Conditions, metav1.Condition{
@@ -682,15 +954,17 @@ func (r *MaaSAuthPolicyReconciler) updateStatus(ctx context.Context, policy *maa
 	if err := r.Status().Update(ctx, policy); err != nil {
 		log := logr.FromContextOrDiscard(ctx)
 		log.Error(err, "failed to update MaaSAuthPolicy status", "name", policy.Name)
+		return
 	}
+	recordConditionEvents(r.Recorder, policy, statusSnapshot.Conditions, policy.Status.Conditions)
 }
 
 func (r *MaaSAuthPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Watch generated AuthPolicies so we re-reconcile when someone manually edits them.
 	generatedAuthPolicy := &unstructured.Unstructured{}
-	generatedAuthPolicy.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1", Kind: "AuthPolicy"})
+	generatedAuthPolicy.SetGroupVersionKind(authPolicyGVK)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&maasv1alpha1.MaaSAuthPolicy{}, builder.WithPredicates(predicate.Or(
 			predicate.GenerationChangedPredicate{},
 			predicate.Funcs{UpdateFunc: deletionTimestampSet},
@@ -704,11 +978,26 @@ func (r *MaaSAuthPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&maasv1alpha1.MaaSModelRef{}, handler.EnqueueRequestsFromMapFunc(
 			r.mapMaaSModelRefToMaaSAuthPolicies,
 		)).
-		// Watch generated AuthPolicies so manual edits get overwritten by the controller.
-		Watches(generatedAuthPolicy, handler.EnqueueRequestsFromMapFunc(
+		// Watch ExternalModels so adding or changing spec.auth reaches the credential injection.
+		Watches(&maasv1alpha1.ExternalModel{}, handler.EnqueueRequestsFromMapFunc(
+			r.mapExternalModelToMaaSAuthPolicies,
+		), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Watch the MaaSConfig so its spec.generatedResources reaches the generated resources.
+		Watches(&maasv1alpha1.MaaSConfig{}, handler.EnqueueRequestsFromMapFunc(
+			r.mapMaaSConfigToMaaSAuthPolicies,
+		), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	// Watch generated AuthPolicies so manual edits get overwritten by the controller.
+	// Without Kuadrant the kind cannot be watched; reconciles still requeue periodically
+	// and the watch is added on the next controller restart.
+	if kindInstalled(mgr.GetRESTMapper(), authPolicyGVK) {
+		b = b.Watches(generatedAuthPolicy, handler.EnqueueRequestsFromMapFunc(
 			r.mapGeneratedAuthPolicyToParent,
-		)).
-		Complete(r)
+		))
+	} else {
+		mgr.GetLogger().Info("Kuadrant AuthPolicy CRD not installed, generated AuthPolicy watch disabled")
+	}
+	return b.Complete(tracing.Reconciler("maasauthpolicy", r))
 }
 
 // mapGeneratedAuthPolicyToParent maps a generated AuthPolicy back to any
@@ -734,7 +1023,7 @@ func (r *MaaSAuthPolicyReconciler) mapGeneratedAuthPolicyToParent(ctx context.Co
 }
 
 // mapMaaSModelRefToMaaSAuthPolicies returns reconcile requests for all MaaSAuthPolicies
-// that reference the given MaaSModelRef.
+// that reference the given MaaSModelRef or one of its ancestors.
 func (r *MaaSAuthPolicyReconciler) mapMaaSModelRefToMaaSAuthPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
 	model, ok := obj.(*maasv1alpha1.MaaSModelRef)
 	if !ok {
@@ -744,10 +1033,17 @@ func (r *MaaSAuthPolicyReconciler) mapMaaSModelRefToMaaSAuthPolicies(ctx context
 	if err := r.List(ctx, &policies); err != nil {
 		return nil
 	}
+	// Policies of the model's ancestors also govern it, so a new or re-parented variant
+	// gets its AuthPolicy from them.
+	covering := map[string]bool{model.Namespace + "/" + model.Name: true}
+	ancestors, _ := modelAncestors(ctx, r.Client, model)
+	for _, a := range ancestors {
+		covering[a.Namespace+"/"+a.Name] = true
+	}
 	var requests []reconcile.Request
 	for _, p := range policies.Items {
 		for _, ref := range p.Spec.ModelRefs {
-			if ref.Namespace == model.Namespace && ref.Name == model.Name {
+			if covering[ref.Namespace+"/"+ref.Name] {
 				requests = append(requests, reconcile.Request{
 					NamespacedName: types.NamespacedName{Name: p.Name, Namespace: p.Namespace},
 				})
@@ -758,6 +1054,23 @@ func (r *MaaSAuthPolicyReconciler) mapMaaSModelRefToMaaSAuthPolicies(ctx context
 	return requests
 }
 
+// mapExternalModelToMaaSAuthPolicies returns reconcile requests for the MaaSAuthPolicies of the
+// MaaSModelRefs referencing the given ExternalModel.
+func (r *MaaSAuthPolicyReconciler) mapExternalModelToMaaSAuthPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
+	var models maasv1alpha1.MaaSModelRefList
+	if err := r.List(ctx, &models, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range models.Items {
+		m := &models.Items[i]
+		if m.Spec.ModelRef.Kind == "ExternalModel" && m.Spec.ModelRef.Name == obj.GetName() {
+			requests = append(requests, r.mapMaaSModelRefToMaaSAuthPolicies(ctx, m)...)
+		}
+	}
+	return requests
+}
+
 // mapHTTPRouteToMaaSAuthPolicies returns reconcile requests for all MaaSAuthPolicies
 // that reference models in the HTTPRoute's namespace.
 func (r *MaaSAuthPolicyReconciler) mapHTTPRouteToMaaSAuthPolicies(ctx context.Context, obj client.Object) []reconcile.Request {
@@ -800,6 +1113,37 @@ func (r *MaaSAuthPolicyReconciler) mapHTTPRouteToMaaSAuthPolicies(ctx context.Co
 // deduplicateAndSort removes duplicates from a string slice and sorts it.
 // This ensures stable output across reconciles, preventing spurious updates
 // caused by non-deterministic Kubernetes List order.
+// maasAPIBaseURL returns the URL of the maas-api Service serving the models of a namespace.
+func (r *MaaSAuthPolicyReconciler) maasAPIBaseURL(modelNamespace string) string {
+	service := "maas-api"
+	if r.MaaSAPIShards > 1 {
+		service = fmt.Sprintf("maas-api-shard-%d", maasAPIShard(modelNamespace, r.MaaSAPIShards))
+	}
+	return fmt.Sprintf("https://%s.%s.svc.cluster.local:8443", service, r.MaaSAPINamespace)
+}
+
+// upstreamCredentialURL returns the maas-api endpoint serving the credential of an ExternalModel.
+// It is on the metrics listener, which the gateway does not route to, so any replica answers.
+func (r *MaaSAuthPolicyReconciler) upstreamCredentialURL(namespace, name string) string {
+	return fmt.Sprintf("http://maas-api.%s.svc.cluster.local:9090/internal/v1/external-models/%s/%s/credential",
+		r.MaaSAPINamespace, namespace, name)
+}
+
+func (r *MaaSAuthPolicyReconciler) upstreamCredentialsSecret() string {
+	if r.UpstreamCredentialsSecret != "" {
+		return r.UpstreamCredentialsSecret
+	}
+	return defaultUpstreamCredentialsSecret
+}
+
+// maasAPIShard returns the maas-api shard serving a model namespace: the FNV-1a hash of the
+// namespace modulo shards. It must match models.ShardOf in maas-api.
+func maasAPIShard(namespace string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(shards))
+}
+
 func deduplicateAndSort(items []string) []string {
 	if len(items) == 0 {
 		return items
@@ -0,0 +1,201 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+// MaaSModelCatalogReconciler maintains the cluster-scoped MaaSModelCatalog singleton, which lists
+// every model with its phase, endpoint and covering subscriptions for `oc get maasmodelcatalog`
+// and maas-api's GET /v1/catalog.
+type MaaSModelCatalogReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelcatalogs,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelcatalogs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelrefs;maassubscriptions,verbs=get;list;watch
+
+// Reconcile recomputes the MaaSModelCatalog singleton, creating it if it does not exist.
+func (r *MaaSModelCatalogReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("MaaSModelCatalog", req.Name)
+
+	catalog, err := r.ensureSingleton(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired, err := r.computeStatus(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	desired.LastUpdated = catalog.Status.LastUpdated
+	if equality.Semantic.DeepEqual(catalog.Status, desired) {
+		return ctrl.Result{}, nil
+	}
+	now := metav1.Now()
+	desired.LastUpdated = &now
+	catalog.Status = desired
+	if err := r.Status().Update(ctx, catalog); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update MaaSModelCatalog: %w", err)
+	}
+	log.V(1).Info("MaaSModelCatalog updated", "models", desired.Summary.Total, "tiers", len(desired.Tiers), "uncovered", desired.UncoveredModels)
+	return ctrl.Result{}, nil
+}
+
+// ensureSingleton returns the MaaSModelCatalog singleton, creating an empty one when missing.
+func (r *MaaSModelCatalogReconciler) ensureSingleton(ctx context.Context) (*maasv1alpha1.MaaSModelCatalog, error) {
+	key := types.NamespacedName{Name: maasv1alpha1.MaaSModelCatalogSingletonName}
+	catalog := &maasv1alpha1.MaaSModelCatalog{}
+	err := r.Get(ctx, key, catalog)
+	if err == nil {
+		return catalog, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get MaaSModelCatalog: %w", err)
+	}
+	catalog = &maasv1alpha1.MaaSModelCatalog{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   maasv1alpha1.MaaSModelCatalogSingletonName,
+			Labels: map[string]string{managedByLabel: managedByValue},
+		},
+	}
+	if err := r.Create(ctx, catalog); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create MaaSModelCatalog: %w", err)
+	}
+	if err := r.Get(ctx, key, catalog); err != nil {
+		return nil, fmt.Errorf("failed to get MaaSModelCatalog: %w", err)
+	}
+	return catalog, nil
+}
+
+func (r *MaaSModelCatalogReconciler) computeStatus(ctx context.Context) (maasv1alpha1.MaaSModelCatalogStatus, error) {
+	var out maasv1alpha1.MaaSModelCatalogStatus
+
+	models := &maasv1alpha1.MaaSModelRefList{}
+	if err := r.List(ctx, models); err != nil {
+		return out, fmt.Errorf("failed to list MaaSModelRefs: %w", err)
+	}
+	subscriptions := &maasv1alpha1.MaaSSubscriptionList{}
+	if err := r.List(ctx, subscriptions); err != nil {
+		return out, fmt.Errorf("failed to list MaaSSubscriptions: %w", err)
+	}
+
+	byKey := make(map[string]*maasv1alpha1.CatalogModel, len(models.Items))
+	for _, m := range models.Items {
+		countModelPhase(&out.Summary, m.Status.Phase)
+		out.Models = append(out.Models, maasv1alpha1.CatalogModel{
+			Name:      m.Name,
+			Namespace: m.Namespace,
+			Kind:      m.Spec.ModelRef.Kind,
+			Phase:     m.Status.Phase,
+			Endpoint:  m.Status.Endpoint,
+		})
+	}
+	sort.Slice(out.Models, func(i, j int) bool {
+		a, b := out.Models[i], out.Models[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	for i := range out.Models {
+		m := &out.Models[i]
+		byKey[m.Namespace+"/"+m.Name] = m
+	}
+
+	sort.Slice(subscriptions.Items, func(i, j int) bool {
+		a, b := subscriptions.Items[i], subscriptions.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	for _, s := range subscriptions.Items {
+		tier := maasv1alpha1.TierCoverage{Name: s.Name, Namespace: s.Namespace, Phase: s.Status.Phase}
+		seen := make(map[string]bool, len(s.Spec.ModelRefs))
+		for _, ref := range s.Spec.ModelRefs {
+			key := ref.Namespace + "/" + ref.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			m, ok := byKey[key]
+			if !ok {
+				tier.MissingModels = append(tier.MissingModels, key)
+				continue
+			}
+			tier.Models++
+			if m.Phase == "Ready" {
+				tier.ReadyModels++
+			}
+			m.Tiers = append(m.Tiers, s.Namespace+"/"+s.Name)
+		}
+		out.Tiers = append(out.Tiers, tier)
+	}
+	for _, m := range out.Models {
+		if len(m.Tiers) == 0 {
+			out.UncoveredModels++
+		}
+	}
+	return out, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MaaSModelCatalogReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toSingleton := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: maasv1alpha1.MaaSModelCatalogSingletonName}}}
+	})
+
+	// Create the singleton at startup so GET /v1/catalog answers before any model exists.
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if _, err := r.ensureSingleton(ctx); err != nil {
+			mgr.GetLogger().Error(err, "unable to create MaaSModelCatalog singleton; it will be created on the next model or subscription event")
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&maasv1alpha1.MaaSModelCatalog{}).
+		Watches(&maasv1alpha1.MaaSModelRef{}, toSingleton).
+		Watches(&maasv1alpha1.MaaSSubscription{}, toSingleton).
+		Complete(tracing.Reconciler("maasmodelcatalog", r))
+}
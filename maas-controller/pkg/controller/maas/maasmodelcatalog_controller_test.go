@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestMaaSModelCatalogReconciler_Aggregates(t *testing.T) {
+	const namespace = "models-as-a-service"
+
+	granite := newMaaSModelRef("granite", "llm", "LLMInferenceService", "granite")
+	granite.Status.Phase = "Ready"
+	granite.Status.Endpoint = "https://maas.example.com/llm/granite"
+	gpt := newMaaSModelRef("gpt", "llm", "ExternalModel", "gpt")
+	gpt.Status.Phase = "Failed"
+	orphan := newMaaSModelRef("orphan", "other", "ExternalModel", "orphan")
+
+	free := newMaaSSubscription("free", namespace, "everyone", "granite", 100)
+	free.Spec.ModelRefs[0].Namespace = "llm"
+	free.Status.Phase = "Active"
+	premium := newMaaSSubscription("premium", namespace, "paying", "granite", 1000)
+	premium.Spec.ModelRefs = []maasv1alpha1.ModelSubscriptionRef{
+		{Name: "granite", Namespace: "llm"},
+		{Name: "gpt", Namespace: "llm"},
+		{Name: "retired", Namespace: "llm"},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(granite, gpt, orphan, free, premium).
+		WithStatusSubresource(&maasv1alpha1.MaaSModelCatalog{}).
+		Build()
+
+	r := &MaaSModelCatalogReconciler{Client: c, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: maasv1alpha1.MaaSModelCatalogSingletonName}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	got := &maasv1alpha1.MaaSModelCatalog{}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("Get MaaSModelCatalog: %v", err)
+	}
+	wantSummary := maasv1alpha1.ModelPhaseSummary{Total: 3, Ready: 1, Pending: 1, Failed: 1}
+	if got.Status.Summary != wantSummary {
+		t.Errorf("Summary = %+v, want %+v", got.Status.Summary, wantSummary)
+	}
+	if got.Status.UncoveredModels != 1 {
+		t.Errorf("UncoveredModels = %d, want 1", got.Status.UncoveredModels)
+	}
+	wantModels := []maasv1alpha1.CatalogModel{
+		{Name: "gpt", Namespace: "llm", Kind: "ExternalModel", Phase: "Failed", Tiers: []string{namespace + "/premium"}},
+		{Name: "granite", Namespace: "llm", Kind: "LLMInferenceService", Phase: "Ready", Endpoint: "https://maas.example.com/llm/granite",
+			Tiers: []string{namespace + "/free", namespace + "/premium"}},
+		{Name: "orphan", Namespace: "other", Kind: "ExternalModel"},
+	}
+	if !reflect.DeepEqual(got.Status.Models, wantModels) {
+		t.Errorf("Models = %+v, want %+v", got.Status.Models, wantModels)
+	}
+	wantTiers := []maasv1alpha1.TierCoverage{
+		{Name: "free", Namespace: namespace, Phase: "Active", Models: 1, ReadyModels: 1},
+		{Name: "premium", Namespace: namespace, Models: 2, ReadyModels: 1, MissingModels: []string{"llm/retired"}},
+	}
+	if !reflect.DeepEqual(got.Status.Tiers, wantTiers) {
+		t.Errorf("Tiers = %+v, want %+v", got.Status.Tiers, wantTiers)
+	}
+	if got.Status.LastUpdated == nil {
+		t.Fatal("LastUpdated not set")
+	}
+
+	// A second reconcile with nothing changed must not touch the status.
+	lastUpdated := got.Status.LastUpdated.DeepCopy()
+	resourceVersion := got.ResourceVersion
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("Get MaaSModelCatalog: %v", err)
+	}
+	if got.ResourceVersion != resourceVersion || !got.Status.LastUpdated.Equal(lastUpdated) {
+		t.Errorf("status rewritten without changes: resourceVersion %s -> %s", resourceVersion, got.ResourceVersion)
+	}
+}
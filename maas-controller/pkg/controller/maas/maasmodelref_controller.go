@@ -20,9 +20,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-logr/logr"
 	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -31,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/apis"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -43,6 +47,7 @@ import (
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
 )
 
 // Default gateway name and namespace when not set via flags.
@@ -57,9 +62,37 @@ type MaaSModelRefReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 
+	// APIReader reads Secrets without caching them; defaults to Client when nil.
+	APIReader client.Reader
+
 	// GatewayName and GatewayNamespace identify the Gateway used for model HTTPRoutes (configurable via flags).
 	GatewayName      string
 	GatewayNamespace string
+
+	// SoftDeleteGracePeriod is how long a soft-deleted model is kept before it is deleted.
+	// Zero keeps it until it is restored or deleted by hand.
+	SoftDeleteGracePeriod time.Duration
+
+	// SubscriptionNamespace is where the MaaSSubscriptions named by the tiers annotation live; when
+	// empty, TierAnnotationInvalid only checks that the annotation parses.
+	SubscriptionNamespace string
+
+	// QuotaHeaders adds the X-MaaS-Tier, X-MaaS-Model and X-MaaS-Remaining-Tokens headers to the
+	// responses of each model's routes.
+	QuotaHeaders bool
+
+	// Recorder records events on the MaaSModelRefs; nil records none.
+	Recorder record.EventRecorder
+
+	// endpointHealth tracks ExternalModel health checks between reconciles.
+	endpointHealth endpointProber
+}
+
+func (r *MaaSModelRefReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
 }
 
 func (r *MaaSModelRefReconciler) gatewayName() string {
@@ -76,6 +109,24 @@ func (r *MaaSModelRefReconciler) gatewayNamespace() string {
 	return defaultGatewayNamespace
 }
 
+// targetGatewayName returns the Gateway the model's HTTPRoute attaches to: spec.gateway, or the
+// controller's gateway.
+func (r *MaaSModelRefReconciler) targetGatewayName(model *maasv1alpha1.MaaSModelRef) string {
+	if model.Spec.Gateway != nil && model.Spec.Gateway.Name != "" {
+		return model.Spec.Gateway.Name
+	}
+	return r.gatewayName()
+}
+
+// targetGatewayNamespace returns the namespace of targetGatewayName. spec.gateway without a
+// namespace refers to the controller's gateway namespace.
+func (r *MaaSModelRefReconciler) targetGatewayNamespace(model *maasv1alpha1.MaaSModelRef) string {
+	if model.Spec.Gateway != nil && model.Spec.Gateway.Namespace != "" {
+		return model.Spec.Gateway.Namespace
+	}
+	return r.gatewayNamespace()
+}
+
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelrefs,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelrefs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelrefs/finalizers,verbs=update
@@ -83,20 +134,29 @@ func (r *MaaSModelRefReconciler) gatewayNamespace() string {
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
 //+kubebuilder:rbac:groups=kuadrant.io,resources=authpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=serving.kserve.io,resources=llminferenceservices,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=externalmodels,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasconfigs,verbs=get;list;watch
 
 const maasModelFinalizer = "maas.opendatahub.io/model-cleanup"
 
 // Field index for efficiently finding MaaSModelRefs by their modelRef.name
 const modelRefNameIndex = "spec.modelRef.name"
 
-// modelRefNameIndexer returns the modelRef.name for indexing
+// modelRefNameIndexer returns the modelRef.name and the backend names for indexing
 func modelRefNameIndexer(obj client.Object) []string {
 	model, ok := obj.(*maasv1alpha1.MaaSModelRef)
 	if !ok || model.Spec.ModelRef.Name == "" {
 		return nil
 	}
-	return []string{model.Spec.ModelRef.Name}
+	names := []string{model.Spec.ModelRef.Name}
+	for _, backend := range model.Spec.Backends {
+		if !slices.Contains(names, backend.Name) {
+			names = append(names, backend.Name)
+		}
+	}
+	return names
 }
 
 // Reconcile is part of the main kubernetes reconciliation loop
@@ -127,16 +187,61 @@ func (r *MaaSModelRefReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	statusSnapshot := model.Status.DeepCopy()
 
+	if isSoftDeleted(model) {
+		return r.reconcileSoftDeleted(ctx, log, model, statusSnapshot)
+	}
+	model.Status.SoftDeletedAt = nil
+	maintenanceChange := reconcileMaintenance(model, time.Now())
+	r.setTierAnnotationCondition(ctx, model)
+	r.setPolicyAttachedCondition(ctx, log, model)
+
+	ancestors, err := modelAncestors(ctx, r.Client, model)
+	if err != nil {
+		if errors.Is(err, errModelLineageInvalid) {
+			model.Status.Lineage = nil
+			model.Status.EffectivePricingMultiplier = ""
+			setRouteCondition(model, false, "InvalidParentRef", err.Error())
+			r.updateStatusWithReason(ctx, model, "Failed", err.Error(), "InvalidParentRef", statusSnapshot)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to resolve model lineage")
+		return ctrl.Result{}, err
+	}
+	model.Status.Lineage = lineageStatus(ancestors)
+	model.Status.EffectivePricingMultiplier = effectivePricingMultiplier(model, ancestors)
+
 	kind := model.Spec.ModelRef.Kind
 	handler := GetBackendHandler(kind, r)
 	if handler == nil {
 		log.Error(nil, "unknown modelRef kind", "kind", kind)
+		setRouteCondition(model, false, "UnknownKind", fmt.Sprintf("unknown kind: %s", kind))
 		r.updateStatus(ctx, model, "Failed", fmt.Sprintf("unknown kind: %s", kind), statusSnapshot)
 		return ctrl.Result{}, nil
 	}
+	if !BackendKindEnabled(kind) {
+		model.Status.Endpoint = ""
+		setRouteCondition(model, false, "Unsupported", fmt.Sprintf("kind not enabled on this controller: %s", kind))
+		r.updateStatusWithReason(ctx, model, "Failed", fmt.Sprintf("kind not enabled on this controller: %s", kind), "Unsupported", statusSnapshot)
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.reconcileGatewayAccess(ctx, log, model); err != nil {
+		if errors.Is(err, ErrGatewayNotAllowed) {
+			// Gateways are not watched; check again once their listeners may have changed.
+			model.Status.Endpoint = ""
+			setRouteCondition(model, false, "GatewayNotAllowed", err.Error())
+			r.updateStatusWithReason(ctx, model, "Failed", err.Error(), "GatewayNotAllowed", statusSnapshot)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+		log.Error(err, "failed to reconcile gateway access")
+		setRouteCondition(model, false, "ReconcileFailed", fmt.Sprintf("Failed to reconcile gateway access: %v", err))
+		r.updateStatus(ctx, model, "Failed", fmt.Sprintf("Failed to reconcile gateway access: %v", err), statusSnapshot)
+		return ctrl.Result{}, err
+	}
 
 	if err := handler.ReconcileRoute(ctx, log, model); err != nil {
 		if errors.Is(err, ErrKindNotImplemented) {
+			setRouteCondition(model, false, "Unsupported", fmt.Sprintf("kind not implemented: %s", kind))
 			r.updateStatusWithReason(ctx, model, "Failed", fmt.Sprintf("kind not implemented: %s", kind), "Unsupported", statusSnapshot)
 			return ctrl.Result{}, nil
 		}
@@ -144,13 +249,34 @@ func (r *MaaSModelRefReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			// HTTPRoute doesn't exist yet - this is normal during startup.
 			// Set status to Pending (not Failed). The HTTPRoute watch will trigger reconciliation when the route is created.
 			model.Status.Endpoint = ""
+			setRouteCondition(model, false, "HTTPRouteNotFound", "Waiting for HTTPRoute to be created")
 			r.updateStatus(ctx, model, "Pending", "Waiting for HTTPRoute to be created", statusSnapshot)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "failed to reconcile HTTPRoute")
+		setRouteCondition(model, false, "ReconcileFailed", fmt.Sprintf("Failed to reconcile HTTPRoute: %v", err))
 		r.updateStatus(ctx, model, "Failed", fmt.Sprintf("Failed to reconcile HTTPRoute: %v", err), statusSnapshot)
 		return ctrl.Result{}, err
 	}
+	setRouteCondition(model, true, "", "")
+
+	if err := r.reconcileTracing(ctx, log, model); err != nil {
+		log.Error(err, "failed to apply tracing policy")
+		r.updateStatusWithReason(ctx, model, "Failed", fmt.Sprintf("Failed to apply tracing policy: %v", err), "TracingFailed", statusSnapshot)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileTimeouts(ctx, log, model); err != nil {
+		log.Error(err, "failed to apply timeouts")
+		r.updateStatusWithReason(ctx, model, "Failed", fmt.Sprintf("Failed to apply timeouts: %v", err), "TimeoutsFailed", statusSnapshot)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileQuotaHeaders(ctx, log, model); err != nil {
+		log.Error(err, "failed to apply quota headers")
+		r.updateStatusWithReason(ctx, model, "Failed", fmt.Sprintf("Failed to apply quota headers: %v", err), "QuotaHeadersFailed", statusSnapshot)
+		return ctrl.Result{}, err
+	}
 
 	endpoint, ready, err := handler.Status(ctx, log, model)
 	if err != nil {
@@ -171,15 +297,28 @@ func (r *MaaSModelRefReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	} else {
 		model.Status.Endpoint = endpoint
 	}
+	var result ctrl.Result
+	if rq, ok := handler.(healthRequeuer); ok {
+		result.RequeueAfter = rq.requeueAfter(model)
+	}
+	// Reconcile again when the model enters or leaves a maintenance window.
+	if !maintenanceChange.IsZero() {
+		if until := time.Until(maintenanceChange); result.RequeueAfter == 0 || until < result.RequeueAfter {
+			result.RequeueAfter = max(until, time.Second)
+		}
+	}
 	if ready {
 		model.Status.Phase = "Ready"
 		r.updateStatus(ctx, model, "Ready", "Successfully reconciled", statusSnapshot)
+	} else if c := apimeta.FindStatusCondition(model.Status.Conditions, ConditionEndpointReachable); c != nil && c.Status == metav1.ConditionFalse {
+		model.Status.Endpoint = ""
+		r.updateStatusWithReason(ctx, model, "Pending", "Backend endpoint unreachable: "+c.Message, "EndpointUnreachable", statusSnapshot)
 	} else {
 		model.Status.Phase = "Pending"
 		model.Status.Endpoint = ""
 		r.updateStatus(ctx, model, "Pending", "Waiting for backend to become ready", statusSnapshot)
 	}
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 func (r *MaaSModelRefReconciler) handleDeletion(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (ctrl.Result, error) {
@@ -194,6 +333,18 @@ func (r *MaaSModelRefReconciler) handleDeletion(ctx context.Context, log logr.Lo
 			return ctrl.Result{}, err
 		}
 
+		// Clean up generated RateLimitPolicies for this model
+		if err := r.deleteGeneratedPoliciesByLabel(ctx, log, model.Namespace, model.Name, "RateLimitPolicy", "kuadrant.io", "v1"); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if err := r.deleteTracingFilter(ctx, log, model); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.deleteTimeoutsFilter(ctx, log, model); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		// Kind-specific cleanup (e.g. delete HTTPRoute for ExternalModel; no-op for llmisvc)
 		if handler := GetBackendHandler(model.Spec.ModelRef.Kind, r); handler != nil {
 			if err := handler.CleanupOnDelete(ctx, log, model); err != nil {
@@ -202,6 +353,10 @@ func (r *MaaSModelRefReconciler) handleDeletion(ctx context.Context, log logr.Lo
 			}
 		}
 
+		if err := r.releaseGatewayAccess(ctx, log, model.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		// Remove finalizer so the MaaSModelRef can be deleted
 		controllerutil.RemoveFinalizer(model, maasModelFinalizer)
 		if err := r.Update(ctx, model); err != nil {
@@ -276,6 +431,7 @@ func (r *MaaSModelRefReconciler) updateStatusWithReason(ctx context.Context, mod
 		Message:            message,
 		ObservedGeneration: model.GetGeneration(),
 	})
+	setEndpointCondition(model, condReason, message)
 
 	if equality.Semantic.DeepEqual(*statusSnapshot, model.Status) {
 		return
@@ -285,7 +441,9 @@ func (r *MaaSModelRefReconciler) updateStatusWithReason(ctx context.Context, mod
 		log := logr.FromContextOrDiscard(ctx)
 		log.Error(err, "failed to update MaaSModelRef status", "name", model.Name)
 		// Intentionally do not return the error so we do not re-queue on status update conflict/failure.
+		return
 	}
+	recordConditionEvents(r.Recorder, model, statusSnapshot.Conditions, model.Status.Conditions)
 }
 
 // llmisvcReadyChangedPredicate passes Create/Delete events and Update events
@@ -322,10 +480,13 @@ func (r *MaaSModelRefReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("failed to create field index %s: %w", modelRefNameIndex, err)
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&maasv1alpha1.MaaSModelRef{}, builder.WithPredicates(predicate.Or(
 			predicate.GenerationChangedPredicate{},
 			predicate.Funcs{UpdateFunc: deletionTimestampSet},
+			predicate.Funcs{UpdateFunc: softDeleteChanged},
+			predicate.Funcs{UpdateFunc: maintenanceChanged},
+			predicate.Funcs{UpdateFunc: tiersAnnotationChanged},
 		))).
 		// Watch HTTPRoutes so we re-reconcile when KServe creates/updates a route
 		// (fixes race condition where MaaSModelRef is created before HTTPRoute exists).
@@ -338,7 +499,70 @@ func (r *MaaSModelRefReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			handler.EnqueueRequestsFromMapFunc(r.mapLLMISvcToMaaSModelRefs),
 			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, llmisvcReadyChangedPredicate{})),
 		).
-		Complete(r)
+		// Watch ExternalModels so health check and provider changes reach the models using them.
+		Watches(&maasv1alpha1.ExternalModel{},
+			handler.EnqueueRequestsFromMapFunc(r.mapReferencedToMaaSModelRefs("ExternalModel")),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Watch Services so an MCPServer model is routed once its Service exists and follows port changes.
+		Watches(&corev1.Service{},
+			handler.EnqueueRequestsFromMapFunc(r.mapReferencedToMaaSModelRefs(MCPServerKind)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Watch the MaaSConfig so its spec.generatedResources reaches the generated resources.
+		Watches(&maasv1alpha1.MaaSConfig{}, handler.EnqueueRequestsFromMapFunc(
+			r.mapMaaSConfigToMaaSModelRefs,
+		), builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Watch parents so variants pick up lineage and pricing multiplier changes.
+		Watches(&maasv1alpha1.MaaSModelRef{},
+			handler.EnqueueRequestsFromMapFunc(r.mapMaaSModelRefToVariants),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		// Watch tiers being created and deleted so TierAnnotationInvalid follows them.
+		Watches(&maasv1alpha1.MaaSSubscription{},
+			handler.EnqueueRequestsFromMapFunc(r.mapSubscriptionToAnnotatedModels),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: func(event.UpdateEvent) bool { return false }}),
+		)
+
+	// Watch generated AuthPolicies, status included, so PolicyAttached follows Kuadrant.
+	if kindInstalled(mgr.GetRESTMapper(), authPolicyGVK) {
+		generatedAuthPolicy := &unstructured.Unstructured{}
+		generatedAuthPolicy.SetGroupVersionKind(authPolicyGVK)
+		b = b.Watches(generatedAuthPolicy, handler.EnqueueRequestsFromMapFunc(r.mapGeneratedAuthPolicyToMaaSModelRef))
+	}
+	return b.Complete(tracing.Reconciler("maasmodelref", r))
+}
+
+// mapReferencedToMaaSModelRefs returns a MapFunc enqueueing the MaaSModelRefs of the given kind that
+// reference the watched object by name in its namespace.
+func (r *MaaSModelRefReconciler) mapReferencedToMaaSModelRefs(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var models maasv1alpha1.MaaSModelRefList
+		if err := r.List(ctx, &models, client.InNamespace(obj.GetNamespace()), client.MatchingFields{modelRefNameIndex: obj.GetName()}); err != nil {
+			logr.FromContextOrDiscard(ctx).Error(err, "failed to list MaaSModelRefs by modelRef.name index", "kind", kind, "name", obj.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, m := range models.Items {
+			if m.Spec.ModelRef.Kind == kind {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: m.Namespace, Name: m.Name}})
+			}
+		}
+		return requests
+	}
+}
+
+// mapMaaSModelRefToVariants returns reconcile requests for all MaaSModelRefs derived from the given one.
+func (r *MaaSModelRefReconciler) mapMaaSModelRefToVariants(ctx context.Context, obj client.Object) []reconcile.Request {
+	descendants, err := modelDescendants(ctx, r.Client, obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(descendants))
+	for _, d := range descendants {
+		requests = append(requests, reconcile.Request{NamespacedName: d})
+	}
+	return requests
 }
 
 // mapHTTPRouteToMaaSModelRefs returns reconcile requests for all MaaSModelRefs in the HTTPRoute's namespace.
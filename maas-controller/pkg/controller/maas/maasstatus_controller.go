@@ -0,0 +1,416 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+const (
+	// maasAPIDeploymentName is the Deployment whose availability is reported in MaaSStatus.
+	maasAPIDeploymentName = "maas-api"
+	// maasStatusRefreshInterval bounds how stale gateway and maas-api health can get,
+	// since neither is watched.
+	maasStatusRefreshInterval = time.Minute
+	// limitadorName is the Limitador resource Kuadrant creates in its namespace.
+	limitadorName = "limitador"
+)
+
+var (
+	authConfigListGVK = schema.GroupVersionKind{Group: "authorino.kuadrant.io", Version: "v1beta3", Kind: "AuthConfigList"}
+	limitadorGVK      = schema.GroupVersionKind{Group: "limitador.kuadrant.io", Version: "v1alpha1", Kind: "Limitador"}
+)
+
+// MaaSStatusReconciler maintains the cluster-scoped MaaSStatus singleton, which aggregates
+// model, policy and component health into one place for `oc get maasstatus`.
+type MaaSStatusReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// APIReader reads the maas-api Deployment directly so the controller does not cache
+	// every Deployment in the cluster.
+	APIReader client.Reader
+
+	GatewayName      string
+	GatewayNamespace string
+	MaaSAPINamespace string
+
+	// CheckAuthorino and CheckLimitador also report whether Authorino accepted the AuthConfigs and
+	// whether Limitador is ready, both in KuadrantNamespace, so a broken policy chain degrades the
+	// installation.
+	CheckAuthorino    bool
+	CheckLimitador    bool
+	KuadrantNamespace string
+
+	// Version is reported as status.controllerVersion. Defaults to the binary's build info.
+	Version string
+}
+
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasstatuses,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasstatuses/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelrefs;maasauthpolicies;maassubscriptions,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=authorino.kuadrant.io,resources=authconfigs,verbs=list
+//+kubebuilder:rbac:groups=limitador.kuadrant.io,resources=limitadors,verbs=get
+
+// Reconcile recomputes the MaaSStatus singleton, creating it if it does not exist.
+func (r *MaaSStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("MaaSStatus", req.Name)
+
+	status, err := r.ensureSingleton(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	desired, err := r.computeStatus(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Keep lastTransitionTime stable across refreshes.
+	conditions := status.Status.Conditions
+	for _, c := range desired.Conditions {
+		c.ObservedGeneration = status.GetGeneration()
+		apimeta.SetStatusCondition(&conditions, c)
+	}
+	desired.Conditions = conditions
+	desired.LastUpdated = status.Status.LastUpdated
+
+	if equality.Semantic.DeepEqual(status.Status, desired) {
+		return ctrl.Result{RequeueAfter: maasStatusRefreshInterval}, nil
+	}
+	now := metav1.Now()
+	desired.LastUpdated = &now
+	status.Status = desired
+	if err := r.Status().Update(ctx, status); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update MaaSStatus: %w", err)
+	}
+	log.V(1).Info("MaaSStatus updated", "phase", desired.Phase, "models", desired.Models.Total, "policyErrors", len(desired.PolicyErrors))
+	return ctrl.Result{RequeueAfter: maasStatusRefreshInterval}, nil
+}
+
+// ensureSingleton returns the MaaSStatus singleton, creating an empty one when missing.
+func (r *MaaSStatusReconciler) ensureSingleton(ctx context.Context) (*maasv1alpha1.MaaSStatus, error) {
+	status := &maasv1alpha1.MaaSStatus{}
+	err := r.Get(ctx, types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}, status)
+	if err == nil {
+		return status, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get MaaSStatus: %w", err)
+	}
+	status = &maasv1alpha1.MaaSStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   maasv1alpha1.MaaSStatusSingletonName,
+			Labels: map[string]string{managedByLabel: managedByValue},
+		},
+	}
+	if err := r.Create(ctx, status); err != nil && !apierrors.IsAlreadyExists(err) {
+		return nil, fmt.Errorf("failed to create MaaSStatus: %w", err)
+	}
+	if err := r.Get(ctx, types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}, status); err != nil {
+		return nil, fmt.Errorf("failed to get MaaSStatus: %w", err)
+	}
+	return status, nil
+}
+
+func (r *MaaSStatusReconciler) computeStatus(ctx context.Context) (maasv1alpha1.MaaSStatusStatus, error) {
+	out := maasv1alpha1.MaaSStatusStatus{ControllerVersion: r.version()}
+
+	models := &maasv1alpha1.MaaSModelRefList{}
+	if err := r.List(ctx, models); err != nil {
+		return out, fmt.Errorf("failed to list MaaSModelRefs: %w", err)
+	}
+	for _, m := range models.Items {
+		countModelPhase(&out.Models, m.Status.Phase)
+	}
+
+	policies := &maasv1alpha1.MaaSAuthPolicyList{}
+	if err := r.List(ctx, policies); err != nil {
+		return out, fmt.Errorf("failed to list MaaSAuthPolicies: %w", err)
+	}
+	for _, p := range policies.Items {
+		if p.Status.Phase != "Active" {
+			out.PolicyErrors = append(out.PolicyErrors, policyError("MaaSAuthPolicy", &p, p.Status.Phase, p.Status.Conditions))
+		}
+	}
+
+	subscriptions := &maasv1alpha1.MaaSSubscriptionList{}
+	if err := r.List(ctx, subscriptions); err != nil {
+		return out, fmt.Errorf("failed to list MaaSSubscriptions: %w", err)
+	}
+	for _, s := range subscriptions.Items {
+		if s.Status.Phase != "Active" {
+			out.PolicyErrors = append(out.PolicyErrors, policyError("MaaSSubscription", &s, s.Status.Phase, s.Status.Conditions))
+		}
+	}
+	sort.Slice(out.PolicyErrors, func(i, j int) bool {
+		a, b := out.PolicyErrors[i], out.PolicyErrors[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	out.Gateway = r.gatewayHealth(ctx)
+	out.MaaSAPI = r.maasAPIHealth(ctx)
+	if r.CheckAuthorino {
+		out.Authorino = r.authorinoHealth(ctx)
+	}
+	if r.CheckLimitador {
+		out.Limitador = r.limitadorHealth(ctx)
+	}
+
+	healthy := out.Gateway.Healthy && out.MaaSAPI.Healthy && optionalHealthy(out.Authorino) && optionalHealthy(out.Limitador) &&
+		len(out.PolicyErrors) == 0 && out.Models.Failed == 0 && out.Models.Unhealthy == 0
+	ready := metav1.Condition{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Healthy", Message: "all MaaS components are healthy"}
+	out.Phase = "Healthy"
+	if !healthy {
+		out.Phase = "Degraded"
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "Degraded"
+		ready.Message = degradedMessage(out)
+	}
+	out.Conditions = []metav1.Condition{ready}
+	return out, nil
+}
+
+// countModelPhase adds a model in phase to summary. Models without a phase yet count as Pending.
+func countModelPhase(summary *maasv1alpha1.ModelPhaseSummary, phase string) {
+	summary.Total++
+	switch phase {
+	case "Ready":
+		summary.Ready++
+	case "Unhealthy":
+		summary.Unhealthy++
+	case "Failed":
+		summary.Failed++
+	default:
+		summary.Pending++
+	}
+}
+
+func policyError(kind string, obj client.Object, phase string, conditions []metav1.Condition) maasv1alpha1.PolicyError {
+	pe := maasv1alpha1.PolicyError{Kind: kind, Name: obj.GetName(), Namespace: obj.GetNamespace(), Phase: phase}
+	if c := apimeta.FindStatusCondition(conditions, "Ready"); c != nil {
+		pe.Message = c.Message
+	}
+	return pe
+}
+
+func degradedMessage(s maasv1alpha1.MaaSStatusStatus) string {
+	switch {
+	case !s.Gateway.Healthy:
+		return fmt.Sprintf("gateway %s/%s: %s", s.Gateway.Namespace, s.Gateway.Name, s.Gateway.Message)
+	case !s.MaaSAPI.Healthy:
+		return fmt.Sprintf("maas-api %s/%s: %s", s.MaaSAPI.Namespace, s.MaaSAPI.Name, s.MaaSAPI.Message)
+	case !optionalHealthy(s.Authorino):
+		return fmt.Sprintf("authorino in %s: %s", s.Authorino.Namespace, s.Authorino.Message)
+	case !optionalHealthy(s.Limitador):
+		return fmt.Sprintf("limitador %s/%s: %s", s.Limitador.Namespace, s.Limitador.Name, s.Limitador.Message)
+	case len(s.PolicyErrors) > 0:
+		return fmt.Sprintf("%d policies or subscriptions are not Active", len(s.PolicyErrors))
+	default:
+		return fmt.Sprintf("%d models are Failed and %d are Unhealthy", s.Models.Failed, s.Models.Unhealthy)
+	}
+}
+
+func (r *MaaSStatusReconciler) gatewayHealth(ctx context.Context) *maasv1alpha1.ComponentHealth {
+	h := &maasv1alpha1.ComponentHealth{Name: r.GatewayName, Namespace: r.GatewayNamespace}
+	gw := &gatewayapiv1.Gateway{}
+	if err := r.Get(ctx, client.ObjectKey{Name: r.GatewayName, Namespace: r.GatewayNamespace}, gw); err != nil {
+		h.Message = fmt.Sprintf("failed to get Gateway: %v", err)
+		return h
+	}
+	c := apimeta.FindStatusCondition(gw.Status.Conditions, string(gatewayapiv1.GatewayConditionProgrammed))
+	if c == nil {
+		h.Message = "Gateway has no Programmed condition yet"
+		return h
+	}
+	h.Healthy = c.Status == metav1.ConditionTrue
+	h.Message = c.Message
+	if h.Message == "" {
+		h.Message = c.Reason
+	}
+	return h
+}
+
+func (r *MaaSStatusReconciler) maasAPIHealth(ctx context.Context) *maasv1alpha1.ComponentHealth {
+	h := &maasv1alpha1.ComponentHealth{Name: maasAPIDeploymentName, Namespace: r.MaaSAPINamespace}
+	deploy := &appsv1.Deployment{}
+	if err := r.reader().Get(ctx, client.ObjectKey{Name: maasAPIDeploymentName, Namespace: r.MaaSAPINamespace}, deploy); err != nil {
+		h.Message = fmt.Sprintf("failed to get Deployment: %v", err)
+		return h
+	}
+	for _, c := range deploy.Status.Conditions {
+		if c.Type == appsv1.DeploymentAvailable {
+			h.Healthy = c.Status == "True"
+			h.Message = c.Message
+			break
+		}
+	}
+	if h.Message == "" {
+		h.Message = fmt.Sprintf("%d/%d replicas available", deploy.Status.AvailableReplicas, deploy.Status.Replicas)
+	}
+	return h
+}
+
+// optionalHealthy treats a component that is not checked as healthy.
+func optionalHealthy(h *maasv1alpha1.ComponentHealth) bool {
+	return h == nil || h.Healthy
+}
+
+// reader returns the uncached reader for resources the controller does not watch.
+func (r *MaaSStatusReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// authorinoHealth reports whether the AuthConfigs Kuadrant generated from the AuthPolicies are
+// all Ready. With none, the gateway's policies are not enforced at all.
+func (r *MaaSStatusReconciler) authorinoHealth(ctx context.Context) *maasv1alpha1.ComponentHealth {
+	h := &maasv1alpha1.ComponentHealth{Name: "authorino", Namespace: r.KuadrantNamespace}
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(authConfigListGVK)
+	if err := r.reader().List(ctx, list, client.InNamespace(r.KuadrantNamespace)); err != nil {
+		h.Message = fmt.Sprintf("failed to list AuthConfigs: %v", err)
+		return h
+	}
+	if len(list.Items) == 0 {
+		h.Message = "no AuthConfigs found"
+		return h
+	}
+	var notReady []string
+	for _, item := range list.Items {
+		if ready, _ := unstructuredConditionReady(&item); !ready {
+			notReady = append(notReady, item.GetName())
+		}
+	}
+	sort.Strings(notReady)
+	if len(notReady) > 0 {
+		h.Message = fmt.Sprintf("%d of %d AuthConfigs are not Ready: %s", len(notReady), len(list.Items), strings.Join(notReady, ", "))
+		return h
+	}
+	h.Healthy = true
+	h.Message = fmt.Sprintf("%d AuthConfigs are Ready", len(list.Items))
+	return h
+}
+
+// limitadorHealth reports whether the Limitador resource is Ready.
+func (r *MaaSStatusReconciler) limitadorHealth(ctx context.Context) *maasv1alpha1.ComponentHealth {
+	h := &maasv1alpha1.ComponentHealth{Name: limitadorName, Namespace: r.KuadrantNamespace}
+	limitador := &unstructured.Unstructured{}
+	limitador.SetGroupVersionKind(limitadorGVK)
+	if err := r.reader().Get(ctx, client.ObjectKey{Name: limitadorName, Namespace: r.KuadrantNamespace}, limitador); err != nil {
+		h.Message = fmt.Sprintf("failed to get Limitador: %v", err)
+		return h
+	}
+	h.Healthy, h.Message = unstructuredConditionReady(limitador)
+	return h
+}
+
+// unstructuredConditionReady reads the Ready condition of an unstructured object, returning
+// whether it is True and its message or reason.
+func unstructuredConditionReady(obj *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		message, _ := cond["message"].(string)
+		if message == "" {
+			message, _ = cond["reason"].(string)
+		}
+		return cond["status"] == string(metav1.ConditionTrue), message
+	}
+	return false, "no Ready condition yet"
+}
+
+func (r *MaaSStatusReconciler) version() string {
+	if r.Version != "" {
+		return r.Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			return s.Value
+		}
+	}
+	return info.Main.Version
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MaaSStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toSingleton := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}}}
+	})
+
+	// Create the singleton at startup so a fresh install reports status before any model exists.
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if _, err := r.ensureSingleton(ctx); err != nil {
+			mgr.GetLogger().Error(err, "unable to create MaaSStatus singleton; it will be created on the next model or policy event")
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&maasv1alpha1.MaaSStatus{}).
+		Watches(&maasv1alpha1.MaaSModelRef{}, toSingleton).
+		Watches(&maasv1alpha1.MaaSAuthPolicy{}, toSingleton).
+		Watches(&maasv1alpha1.MaaSSubscription{}, toSingleton).
+		Complete(tracing.Reconciler("maasstatus", r))
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestMaaSStatusReconciler_Aggregates(t *testing.T) {
+	const namespace = "models-as-a-service"
+
+	ready := newMaaSModelRef("ready", "llm", "ExternalModel", "ready")
+	ready.Status.Phase = "Ready"
+	failed := newMaaSModelRef("broken", "llm", "ExternalModel", "broken")
+	failed.Status.Phase = "Failed"
+	pending := newMaaSModelRef("new", "llm", "ExternalModel", "new")
+
+	activePolicy := newMaaSAuthPolicy("policy-ok", namespace, "team-a", maasv1alpha1.ModelRef{Name: "ready", Namespace: "llm"})
+	activePolicy.Status.Phase = "Active"
+	failedSub := newMaaSSubscription("sub-bad", namespace, "team-a", "broken", 100)
+	failedSub.Status.Phase = "Failed"
+	failedSub.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionFalse, Reason: "Failed", Message: "model not found"}}
+
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "maas-default-gateway", Namespace: "openshift-ingress"},
+		Status: gatewayapiv1.GatewayStatus{Conditions: []metav1.Condition{
+			{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, Reason: "Programmed"},
+		}},
+	}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: maasAPIDeploymentName, Namespace: "opendatahub"},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Message: "Deployment has minimum availability."},
+		}},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ready, failed, pending, activePolicy, failedSub, gateway, deploy).
+		WithStatusSubresource(&maasv1alpha1.MaaSStatus{}).
+		Build()
+
+	r := &MaaSStatusReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		GatewayName:      "maas-default-gateway",
+		GatewayNamespace: "openshift-ingress",
+		MaaSAPINamespace: "opendatahub",
+		Version:          "v0.0.0-test",
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}}
+	res, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if res.RequeueAfter != maasStatusRefreshInterval {
+		t.Errorf("RequeueAfter = %v, want %v", res.RequeueAfter, maasStatusRefreshInterval)
+	}
+
+	got := &maasv1alpha1.MaaSStatus{}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("Get MaaSStatus: %v", err)
+	}
+	want := maasv1alpha1.ModelPhaseSummary{Total: 3, Ready: 1, Pending: 1, Failed: 1}
+	if got.Status.Models != want {
+		t.Errorf("Models = %+v, want %+v", got.Status.Models, want)
+	}
+	if len(got.Status.PolicyErrors) != 1 || got.Status.PolicyErrors[0].Name != "sub-bad" || got.Status.PolicyErrors[0].Message != "model not found" {
+		t.Errorf("PolicyErrors = %+v, want only sub-bad with its Ready message", got.Status.PolicyErrors)
+	}
+	if got.Status.Gateway == nil || !got.Status.Gateway.Healthy {
+		t.Errorf("Gateway = %+v, want healthy", got.Status.Gateway)
+	}
+	if got.Status.MaaSAPI == nil || !got.Status.MaaSAPI.Healthy {
+		t.Errorf("MaaSAPI = %+v, want healthy", got.Status.MaaSAPI)
+	}
+	if got.Status.Phase != "Degraded" {
+		t.Errorf("Phase = %q, want Degraded", got.Status.Phase)
+	}
+	if got.Status.ControllerVersion != "v0.0.0-test" {
+		t.Errorf("ControllerVersion = %q, want v0.0.0-test", got.Status.ControllerVersion)
+	}
+	if got.Status.LastUpdated == nil {
+		t.Errorf("LastUpdated not set")
+	}
+	if apimeta.IsStatusConditionTrue(got.Status.Conditions, "Ready") {
+		t.Errorf("Ready condition should be False while degraded, got %+v", got.Status.Conditions)
+	}
+}
+
+func TestMaaSStatusReconciler_HealthyWithoutModels(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "gw-ns"},
+		Status: gatewayapiv1.GatewayStatus{Conditions: []metav1.Condition{
+			{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, Reason: "Programmed"},
+		}},
+	}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: maasAPIDeploymentName, Namespace: "maas"},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		}},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(gateway, deploy).
+		WithStatusSubresource(&maasv1alpha1.MaaSStatus{}).
+		Build()
+
+	r := &MaaSStatusReconciler{Client: c, Scheme: scheme, GatewayName: "gw", GatewayNamespace: "gw-ns", MaaSAPINamespace: "maas"}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	got := &maasv1alpha1.MaaSStatus{}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("singleton was not created: %v", err)
+	}
+	if got.Status.Phase != "Healthy" {
+		t.Errorf("Phase = %q, want Healthy (conditions: %+v)", got.Status.Phase, got.Status.Conditions)
+	}
+	first := got.Status.LastUpdated
+
+	// A second reconcile with nothing changed must not rewrite the status.
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("second Reconcile: %v", err)
+	}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("Get MaaSStatus: %v", err)
+	}
+	if !got.Status.LastUpdated.Equal(first) {
+		t.Errorf("LastUpdated changed on a no-op reconcile: %v -> %v", first, got.Status.LastUpdated)
+	}
+}
+
+func newKuadrantObject(apiVersion, kind, name, readyStatus, message string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]any{"name": name, "namespace": "kuadrant-system"},
+		"status": map[string]any{"conditions": []any{
+			map[string]any{"type": "Ready", "status": readyStatus, "reason": "Reconciled", "message": message},
+		}},
+	}}
+}
+
+func TestMaaSStatusReconciler_DependencyChecks(t *testing.T) {
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw", Namespace: "gw-ns"},
+		Status: gatewayapiv1.GatewayStatus{Conditions: []metav1.Condition{
+			{Type: string(gatewayapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, Reason: "Programmed"},
+		}},
+	}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: maasAPIDeploymentName, Namespace: "maas"},
+		Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		}},
+	}
+	const authConfig, limitador = "authorino.kuadrant.io/v1beta3", "limitador.kuadrant.io/v1alpha1"
+
+	tests := []struct {
+		name          string
+		objects       []client.Object
+		wantAuthorino bool
+		wantLimitador bool
+		wantMessage   string
+	}{
+		{
+			name: "healthy",
+			objects: []client.Object{
+				newKuadrantObject(authConfig, "AuthConfig", "a", "True", ""),
+				newKuadrantObject(limitador, "Limitador", "limitador", "True", "Limitador is ready"),
+			},
+			wantAuthorino: true,
+			wantLimitador: true,
+		},
+		{
+			name: "authconfig_not_ready",
+			objects: []client.Object{
+				newKuadrantObject(authConfig, "AuthConfig", "a", "True", ""),
+				newKuadrantObject(authConfig, "AuthConfig", "b", "False", "invalid"),
+				newKuadrantObject(limitador, "Limitador", "limitador", "True", ""),
+			},
+			wantLimitador: true,
+			wantMessage:   "authorino in kuadrant-system: 1 of 2 AuthConfigs are not Ready: b",
+		},
+		{
+			name:          "limitador_missing",
+			objects:       []client.Object{newKuadrantObject(authConfig, "AuthConfig", "a", "True", "")},
+			wantAuthorino: true,
+			wantMessage:   "limitador kuadrant-system/limitador: failed to get Limitador",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(append(tt.objects, gateway.DeepCopy(), deploy.DeepCopy())...).
+				WithStatusSubresource(&maasv1alpha1.MaaSStatus{}).
+				Build()
+			r := &MaaSStatusReconciler{
+				Client: c, Scheme: scheme, GatewayName: "gw", GatewayNamespace: "gw-ns", MaaSAPINamespace: "maas",
+				CheckAuthorino: true, CheckLimitador: true, KuadrantNamespace: "kuadrant-system",
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: maasv1alpha1.MaaSStatusSingletonName}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+
+			got := &maasv1alpha1.MaaSStatus{}
+			if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+				t.Fatalf("Get MaaSStatus: %v", err)
+			}
+			if got.Status.Authorino == nil || got.Status.Authorino.Healthy != tt.wantAuthorino {
+				t.Errorf("Authorino = %+v, want healthy %v", got.Status.Authorino, tt.wantAuthorino)
+			}
+			if got.Status.Limitador == nil || got.Status.Limitador.Healthy != tt.wantLimitador {
+				t.Errorf("Limitador = %+v, want healthy %v", got.Status.Limitador, tt.wantLimitador)
+			}
+			ready := apimeta.FindStatusCondition(got.Status.Conditions, "Ready")
+			if tt.wantMessage == "" {
+				if got.Status.Phase != "Healthy" {
+					t.Errorf("Phase = %q, want Healthy (conditions: %+v)", got.Status.Phase, got.Status.Conditions)
+				}
+				return
+			}
+			if got.Status.Phase != "Degraded" || ready == nil || !strings.HasPrefix(ready.Message, tt.wantMessage) {
+				t.Errorf("Phase = %q, Ready = %+v; want Degraded with message %q", got.Status.Phase, ready, tt.wantMessage)
+			}
+		})
+	}
+}
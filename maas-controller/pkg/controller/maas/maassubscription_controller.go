@@ -20,8 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -44,19 +46,32 @@ import (
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
 )
 
 // MaaSSubscriptionReconciler reconciles a MaaSSubscription object
 type MaaSSubscriptionReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// RateLimitBackend selects what enforces the rate limits of subscriptions: limitador (the
+	// default), envoy-rls or redis. See RateLimitBackends.
+	RateLimitBackend string
+	// EnvoyRLS locates the rate limit service of the envoy-rls backend.
+	EnvoyRLS EnvoyRLSConfig
+	// EnforceContextWindow forwards the request bodies of models annotated with
+	// ContextWindowAnnotation to maas-api, which denies prompts that exceed the window.
+	EnforceContextWindow bool
 }
 
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maassubscriptions,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maassubscriptions/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maassubscriptions/finalizers,verbs=update
 //+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasmodelrefs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasratelimitoverrides,verbs=get;list;watch
 //+kubebuilder:rbac:groups=kuadrant.io,resources=tokenratelimitpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kuadrant.io,resources=ratelimitpolicies,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=networking.istio.io,resources=envoyfilters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/finalizers,verbs=update
 
@@ -71,6 +86,10 @@ const (
 // (API key mint and selector use deterministic tie-break; admins should set distinct priorities).
 const ConditionSpecPriorityDuplicate = "SpecPriorityDuplicate"
 
+// ConditionExpired reports whether spec.expiresAt has passed. It is only set on subscriptions
+// with an expiry.
+const ConditionExpired = "Expired"
+
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *MaaSSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logr.FromContextOrDiscard(ctx).WithValues("MaaSSubscription", req.NamespacedName)
@@ -98,17 +117,57 @@ func (r *MaaSSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Req
 	}
 
 	statusSnapshot := subscription.Status.DeepCopy()
+	now := time.Now()
+	// Reconcile again when a removed owner's grace period ends to drop it from the status.
+	graceEndsIn := reconcileRevokedOwners(subscription, now)
 
 	// Reconcile TokenRateLimitPolicy for each model
 	// IMPORTANT: TokenRateLimitPolicy targets the HTTPRoute for each model
-	if err := r.reconcileTokenRateLimitPolicies(ctx, log, subscription); err != nil {
+	err := r.reconcileTokenRateLimitPolicies(ctx, log, subscription)
+	if errors.Is(err, ErrPolicyEngineUnavailable) {
+		// Kuadrant is not installed: subscriptions still drive selection in maas-api, only rate limiting is skipped.
+		log.Info("Kuadrant TokenRateLimitPolicy CRD not installed, skipping TokenRateLimitPolicy generation", "error", err.Error())
+		setPolicyEngineCondition(&subscription.Status.Conditions, err, subscription.GetGeneration())
+		r.updateStatus(ctx, subscription, "Pending", "TokenRateLimitPolicy generation skipped: Kuadrant is not installed", statusSnapshot)
+		return ctrl.Result{RequeueAfter: soonest(policyEngineRecheckInterval, graceEndsIn)}, nil
+	}
+	setPolicyEngineCondition(&subscription.Status.Conditions, nil, subscription.GetGeneration())
+	if err != nil {
 		log.Error(err, "failed to reconcile TokenRateLimitPolicies")
 		r.updateStatus(ctx, subscription, "Failed", fmt.Sprintf("Failed to reconcile: %v", err), statusSnapshot)
 		return ctrl.Result{}, err
 	}
 
+	requeueIn := soonest(reconcileQuotaExceeded(subscription, now), graceEndsIn)
+
+	if expiresAt := subscription.Spec.ExpiresAt; expiresAt != nil {
+		if isExpired(subscription, now) {
+			setExpiredCondition(&subscription.Status.Conditions, metav1.ConditionTrue, "Expired",
+				fmt.Sprintf("Subscription expired at %s", expiresAt.UTC().Format(time.RFC3339)), subscription.GetGeneration())
+			r.updateStatus(ctx, subscription, "Expired", "Subscription has expired and no longer grants access", statusSnapshot)
+			return ctrl.Result{}, nil
+		}
+		setExpiredCondition(&subscription.Status.Conditions, metav1.ConditionFalse, "NotExpired",
+			fmt.Sprintf("Subscription expires at %s", expiresAt.UTC().Format(time.RFC3339)), subscription.GetGeneration())
+		r.updateStatus(ctx, subscription, "Active", "Successfully reconciled", statusSnapshot)
+		// Reconcile again at expiry to drop the subscription from the TokenRateLimitPolicies.
+		return ctrl.Result{RequeueAfter: soonest(expiresAt.Sub(now), requeueIn)}, nil
+	}
+	apimeta.RemoveStatusCondition(&subscription.Status.Conditions, ConditionExpired)
+
 	r.updateStatus(ctx, subscription, "Active", "Successfully reconciled", statusSnapshot)
-	return ctrl.Result{}, nil
+	// Reconcile again when the daily window resets to clear QuotaExceeded, or a grace period ends.
+	return ctrl.Result{RequeueAfter: requeueIn}, nil
+}
+
+func setExpiredCondition(conditions *[]metav1.Condition, status metav1.ConditionStatus, reason, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               ConditionExpired,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
 }
 
 func (r *MaaSSubscriptionReconciler) reconcileTokenRateLimitPolicies(ctx context.Context, log logr.Logger, subscription *maasv1alpha1.MaaSSubscription) error {
@@ -116,26 +175,34 @@ func (r *MaaSSubscriptionReconciler) reconcileTokenRateLimitPolicies(ctx context
 	// find ALL subscriptions for that model and build a single aggregated TokenRateLimitPolicy.
 	// Kuadrant only allows one TokenRateLimitPolicy per HTTPRoute target.
 
-	// Deduplicate model references to prevent reconciling the same model multiple times
-	seen := make(map[string]struct{}, len(subscription.Spec.ModelRefs))
-	for _, modelRef := range subscription.Spec.ModelRefs {
-		k := modelRef.Namespace + "/" + modelRef.Name
-		if _, ok := seen[k]; ok {
-			continue
+	// Variants (fine-tunes) of a referenced model inherit this subscription, so their
+	// TokenRateLimitPolicies are rebuilt as well. expandWithDescendants also deduplicates.
+	for _, model := range r.modelsCoveredBy(ctx, subscription) {
+		if err := r.reconcileRateLimitsForModel(ctx, log, model.Namespace, model.Name); err != nil {
+			return err
 		}
-		seen[k] = struct{}{}
-		if err := r.reconcileTRLPForModel(ctx, log, modelRef.Namespace, modelRef.Name); err != nil {
+		if err := r.reconcileAttachmentFilterForModel(ctx, log, model.Namespace, model.Name); err != nil {
 			return err
 		}
 	}
-	return nil
+	return r.reconcileRateLimitService(ctx, log)
+}
+
+// modelsCoveredBy returns the models a subscription references plus their descendants.
+func (r *MaaSSubscriptionReconciler) modelsCoveredBy(ctx context.Context, subscription *maasv1alpha1.MaaSSubscription) []types.NamespacedName {
+	refs := make([]types.NamespacedName, 0, len(subscription.Spec.ModelRefs))
+	for _, modelRef := range subscription.Spec.ModelRefs {
+		refs = append(refs, types.NamespacedName{Namespace: modelRef.Namespace, Name: modelRef.Name})
+	}
+	return expandWithDescendants(ctx, r.Client, refs)
 }
 
 // reconcileTRLPForModel builds or updates the aggregated TokenRateLimitPolicy for a specific model.
-// It finds all active subscriptions for the model and creates a single TRLP covering all of them.
+// It finds all active subscriptions for the model, including those inherited from its ancestors,
+// and creates a single TRLP covering all of them.
 func (r *MaaSSubscriptionReconciler) reconcileTRLPForModel(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
 	// Find ALL subscriptions for this model (not just the current one)
-	allSubs, err := findAllSubscriptionsForModel(ctx, r.Client, modelNamespace, modelName)
+	allSubs, err := subscriptionEntriesForModel(ctx, r.Client, modelNamespace, modelName)
 	if err != nil {
 		return fmt.Errorf("failed to list subscriptions for model %s/%s: %w", modelNamespace, modelName, err)
 	}
@@ -161,18 +228,16 @@ func (r *MaaSSubscriptionReconciler) reconcileTRLPForModel(ctx context.Context,
 	}
 
 	// Check if existing TRLP is opted-out before doing any expensive work
-	policyName := fmt.Sprintf("maas-trlp-%s", modelName)
-	existingCheck := &unstructured.Unstructured{}
-	existingCheck.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"})
-	existingCheck.SetName(policyName)
-	existingCheck.SetNamespace(httpRouteNS)
-	if err := r.Get(ctx, client.ObjectKeyFromObject(existingCheck), existingCheck); err == nil {
-		if !isManaged(existingCheck) {
-			log.Info("TokenRateLimitPolicy opted out, skipping reconciliation", "name", policyName, "namespace", httpRouteNS, "model", modelNamespace+"/"+modelName)
-			return nil
-		}
-	} else if !apierrors.IsNotFound(err) {
-		return fmt.Errorf("failed to check existing TokenRateLimitPolicy: %w", err)
+	naming, err := namingFor(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return err
+	}
+	policyName, err := naming.name(nameTemplateTokenRateLimitPolicy, fmt.Sprintf("maas-trlp-%s", modelName))
+	if err != nil {
+		return fmt.Errorf("model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	if skip, err := r.generatedPolicySkipped(ctx, log, tokenRateLimitPolicyGVK, policyName, httpRouteNS, modelNamespace, modelName); err != nil || skip {
+		return err
 	}
 
 	// If no subscriptions remain, delete the TRLP
@@ -190,31 +255,37 @@ func (r *MaaSSubscriptionReconciler) reconcileTRLPForModel(ctx context.Context,
 		return fmt.Errorf("failed to fetch HTTPRoute %s/%s: %w", httpRouteNS, httpRouteName, err)
 	}
 
+	overrides, err := effectiveOverrides(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+
 	limitsMap := map[string]interface{}{}
 	var subNames []string
+	var overrideNames []string
 
 	type subInfo struct {
 		sub   maasv1alpha1.MaaSSubscription
-		mRef  maasv1alpha1.ModelSubscriptionRef
 		rates []interface{}
 	}
 	var subs []subInfo
-	for _, sub := range allSubs {
-		for _, mRef := range sub.Spec.ModelRefs {
-			if mRef.Namespace != modelNamespace || mRef.Name != modelName {
-				continue
-			}
-			var rates []interface{}
-			if len(mRef.TokenRateLimits) > 0 {
-				for _, trl := range mRef.TokenRateLimits {
-					rates = append(rates, map[string]interface{}{"limit": trl.Limit, "window": trl.Window})
-				}
-			} else {
-				rates = append(rates, map[string]interface{}{"limit": int64(100), "window": "1m"})
+	for _, entry := range allSubs {
+		// entry.mRef is the model's own entry or, for a variant, the nearest ancestor's.
+		// A MaaSRateLimitOverride for the subscription's organization replaces the tier's limits.
+		tokenRateLimits := entry.mRef.TokenRateLimits
+		if o := overrideFor(overrides, &entry.sub, entry.mRef); o != nil && len(o.Spec.TokenRateLimits) > 0 {
+			tokenRateLimits = o.Spec.TokenRateLimits
+			overrideNames = append(overrideNames, o.Name)
+		}
+		var rates []interface{}
+		if len(tokenRateLimits) > 0 {
+			for _, trl := range tokenRateLimits {
+				rates = append(rates, map[string]interface{}{"limit": trl.Limit, "window": trl.Window})
 			}
-			subs = append(subs, subInfo{sub: sub, mRef: mRef, rates: rates})
-			break
+		} else {
+			rates = append(rates, map[string]interface{}{"limit": int64(100), "window": "1m"})
 		}
+		subs = append(subs, subInfo{sub: entry.sub, rates: rates})
 	}
 
 	// Trust auth.identity.selected_subscription_key from AuthPolicy.
@@ -233,11 +304,11 @@ func (r *MaaSSubscriptionReconciler) reconcileTRLPForModel(ctx context.Context,
 		// Build subscription reference: namespace/name
 		subRef := fmt.Sprintf("%s/%s", si.sub.Namespace, si.sub.Name)
 		// Build model-scoped reference: subscription@model
-		modelScopedRef := fmt.Sprintf("%s@%s/%s", subRef, si.mRef.Namespace, si.mRef.Name)
+		modelScopedRef := fmt.Sprintf("%s@%s/%s", subRef, modelNamespace, modelName)
 
 		// TRLP limit key must be safe for YAML (no slashes)
 		safeKey := strings.ReplaceAll(subRef, "/", "-")
-		limitsMap[fmt.Sprintf("%s-%s-tokens", safeKey, si.mRef.Name)] = map[string]interface{}{
+		limitsMap[fmt.Sprintf("%s-%s-tokens", safeKey, modelName)] = map[string]interface{}{
 			"rates": si.rates,
 			"when": []interface{}{
 				map[string]interface{}{
@@ -259,21 +330,21 @@ func (r *MaaSSubscriptionReconciler) reconcileTRLPForModel(ctx context.Context,
 	policy.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"})
 	policy.SetName(policyName)
 	policy.SetNamespace(httpRouteNS)
-	policy.SetLabels(map[string]string{
+	policy.SetLabels(naming.withLabels(map[string]string{
 		"maas.opendatahub.io/model":           modelName,
 		"maas.opendatahub.io/model-namespace": modelNamespace,
-		"app.kubernetes.io/managed-by":        "maas-controller",
+		managedByLabel:                        managedByValue,
 		"app.kubernetes.io/part-of":           "maas-subscription",
 		"app.kubernetes.io/component":         "token-rate-limit-policy",
-	})
-	policy.SetAnnotations(map[string]string{
+	}))
+	annotations := map[string]string{
 		"maas.opendatahub.io/subscriptions": strings.Join(subNames, ","),
-	})
-
-	// Set HTTPRoute as owner for garbage collection (TRLP deleted when route is deleted)
-	if err := controllerutil.SetControllerReference(route, policy, r.Scheme); err != nil {
-		return fmt.Errorf("failed to set owner reference on TokenRateLimitPolicy %s/%s: %w", policy.GetNamespace(), policy.GetName(), err)
 	}
+	if len(overrideNames) > 0 {
+		sort.Strings(overrideNames)
+		annotations[rateLimitOverridesAnnotation] = strings.Join(slices.Compact(overrideNames), ",")
+	}
+	policy.SetAnnotations(annotations)
 
 	spec := map[string]interface{}{
 		"targetRef": map[string]interface{}{
@@ -287,63 +358,7 @@ func (r *MaaSSubscriptionReconciler) reconcileTRLPForModel(ctx context.Context,
 		return fmt.Errorf("failed to set spec: %w", err)
 	}
 
-	// Create or update TokenRateLimitPolicy
-	existing := &unstructured.Unstructured{}
-	existing.SetGroupVersionKind(policy.GroupVersionKind())
-	err = r.Get(ctx, client.ObjectKeyFromObject(policy), existing)
-	if apierrors.IsNotFound(err) {
-		if err := r.Create(ctx, policy); err != nil {
-			return fmt.Errorf("failed to create TokenRateLimitPolicy for model %s: %w", modelName, err)
-		}
-		log.Info("TokenRateLimitPolicy created", "name", policyName, "model", modelName, "subscriptionCount", len(subNames), "subscriptions", subNames)
-	} else if err != nil {
-		return fmt.Errorf("failed to get existing TokenRateLimitPolicy: %w", err)
-	} else {
-		// Double-check managed status as a safety check for races (TRLP could have been
-		// opted-out between the early check and now).
-		if !isManaged(existing) {
-			log.Info("TokenRateLimitPolicy opted out during reconciliation, skipping update", "name", policyName)
-		} else {
-			// Ensure owner reference is set on managed existing policy.
-			if err := controllerutil.SetControllerReference(route, existing, r.Scheme); err != nil {
-				return fmt.Errorf("failed to set owner reference on existing TokenRateLimitPolicy %s/%s: %w", existing.GetNamespace(), existing.GetName(), err)
-			}
-			// Snapshot the existing object before modifications so we can detect
-			// no-op updates.
-			snapshot := existing.DeepCopy()
-
-			mergedAnnotations := existing.GetAnnotations()
-			if mergedAnnotations == nil {
-				mergedAnnotations = make(map[string]string)
-			}
-			for k, v := range policy.GetAnnotations() {
-				mergedAnnotations[k] = v
-			}
-			existing.SetAnnotations(mergedAnnotations)
-
-			mergedLabels := existing.GetLabels()
-			if mergedLabels == nil {
-				mergedLabels = make(map[string]string)
-			}
-			for k, v := range policy.GetLabels() {
-				mergedLabels[k] = v
-			}
-			existing.SetLabels(mergedLabels)
-			if err := unstructured.SetNestedMap(existing.Object, spec, "spec"); err != nil {
-				return fmt.Errorf("failed to update spec: %w", err)
-			}
-
-			if equality.Semantic.DeepEqual(snapshot.Object, existing.Object) {
-				log.Info("TokenRateLimitPolicy unchanged, skipping update", "name", policyName, "model", modelNamespace+"/"+modelName, "subscriptionCount", len(subNames))
-			} else {
-				if err := r.Update(ctx, existing); err != nil {
-					return fmt.Errorf("failed to update TokenRateLimitPolicy for model %s/%s: %w", modelNamespace, modelName, err)
-				}
-				log.Info("TokenRateLimitPolicy updated", "name", policyName, "model", modelNamespace+"/"+modelName, "subscriptionCount", len(subNames), "subscriptions", subNames)
-			}
-		}
-	}
-	return nil
+	return r.applyGeneratedPolicy(ctx, log, route, policy, modelNamespace, modelName, subNames)
 }
 
 // deleteModelTRLP deletes the aggregated TokenRateLimitPolicy for a model in the given namespace.
@@ -387,18 +402,24 @@ func (r *MaaSSubscriptionReconciler) handleDeletion(ctx context.Context, log log
 		// For each model referenced by this subscription, rebuild the aggregated TokenRateLimitPolicy
 		// without the deleted subscription's limits. If no other subscriptions reference the model,
 		// the TRLP will be deleted. This ensures zero-downtime rate limiting during subscription removal.
-		seen := make(map[string]struct{}, len(subscription.Spec.ModelRefs))
-		for _, modelRef := range subscription.Spec.ModelRefs {
-			k := modelRef.Namespace + "/" + modelRef.Name
-			if _, ok := seen[k]; ok {
-				continue
-			}
-			seen[k] = struct{}{}
+		for _, modelRef := range r.modelsCoveredBy(ctx, subscription) {
 			log.Info("Rebuilding TokenRateLimitPolicy without deleted subscription", "model", modelRef.Namespace+"/"+modelRef.Name, "subscription", subscription.Name)
-			if err := r.reconcileTRLPForModel(ctx, log, modelRef.Namespace, modelRef.Name); err != nil {
-				log.Error(err, "failed to reconcile TokenRateLimitPolicy during deletion, will retry", "model", modelRef.Namespace+"/"+modelRef.Name)
+			if err := r.reconcileRateLimitsForModel(ctx, log, modelRef.Namespace, modelRef.Name); err != nil {
+				if errors.Is(err, ErrPolicyEngineUnavailable) {
+					// Nothing to clean up when Kuadrant was never installed.
+					continue
+				}
+				log.Error(err, "failed to reconcile rate limits during deletion, will retry", "model", modelRef.Namespace+"/"+modelRef.Name)
 				return ctrl.Result{}, err
 			}
+			if err := r.reconcileAttachmentFilterForModel(ctx, log, modelRef.Namespace, modelRef.Name); err != nil {
+				log.Error(err, "failed to reconcile attachment EnvoyFilter during deletion, will retry", "model", modelRef.Namespace+"/"+modelRef.Name)
+				return ctrl.Result{}, err
+			}
+		}
+		if err := r.reconcileRateLimitService(ctx, log); err != nil {
+			log.Error(err, "failed to reconcile the rate limit service config during deletion, will retry")
+			return ctrl.Result{}, err
 		}
 
 		controllerutil.RemoveFinalizer(subscription, maasSubscriptionFinalizer)
@@ -412,11 +433,14 @@ func (r *MaaSSubscriptionReconciler) handleDeletion(ctx context.Context, log log
 
 func (r *MaaSSubscriptionReconciler) updateStatus(ctx context.Context, subscription *maasv1alpha1.MaaSSubscription, phase, message string, statusSnapshot *maasv1alpha1.MaaSSubscriptionStatus) {
 	// Status-only updates do not bump metadata.generation, so this reconcile may not re-queue.
-	// Merge SpecPriorityDuplicate from the API server so we do not clobber the async duplicate-priority scan.
+	// Merge SpecPriorityDuplicate from the API server so we do not clobber the async duplicate-priority scan,
+	// and KeycloakProvisioned, which KeycloakTierReconciler owns.
 	latest := &maasv1alpha1.MaaSSubscription{}
 	if err := r.Get(ctx, client.ObjectKeyFromObject(subscription), latest); err == nil {
-		if dup := apimeta.FindStatusCondition(latest.Status.Conditions, ConditionSpecPriorityDuplicate); dup != nil {
-			apimeta.SetStatusCondition(&subscription.Status.Conditions, *dup)
+		for _, conditionType := range []string{ConditionSpecPriorityDuplicate, ConditionKeycloakProvisioned} {
+			if cond := apimeta.FindStatusCondition(latest.Status.Conditions, conditionType); cond != nil {
+				apimeta.SetStatusCondition(&subscription.Status.Conditions, *cond)
+			}
 		}
 	}
 
@@ -424,9 +448,16 @@ func (r *MaaSSubscriptionReconciler) updateStatus(ctx context.Context, subscript
 
 	status := metav1.ConditionTrue
 	reason := "Reconciled"
-	if phase == "Failed" {
+	switch phase {
+	case "Failed":
 		status = metav1.ConditionFalse
 		reason = "ReconcileFailed"
+	case "Pending":
+		status = metav1.ConditionFalse
+		reason = "PolicyEngineUnavailable"
+	case "Expired":
+		status = metav1.ConditionFalse
+		reason = "Expired"
 	}
 
 	apimeta.SetStatusCondition(&subscription.Status.Conditions, metav1.Condition{
@@ -572,12 +603,13 @@ func (r *MaaSSubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	// Watch generated TokenRateLimitPolicies so we re-reconcile when someone manually edits them.
 	generatedTRLP := &unstructured.Unstructured{}
-	generatedTRLP.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"})
+	generatedTRLP.SetGroupVersionKind(tokenRateLimitPolicyGVK)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&maasv1alpha1.MaaSSubscription{}, builder.WithPredicates(predicate.Or(
 			predicate.GenerationChangedPredicate{},
 			predicate.Funcs{UpdateFunc: deletionTimestampSet},
+			predicate.Funcs{UpdateFunc: quotaExceededChanged},
 		))).
 		// Full scan of duplicate spec.priority on create, delete, or priority-only spec update.
 		// Does not enqueue reconciles; only patches status conditions on all subscriptions.
@@ -595,11 +627,34 @@ func (r *MaaSSubscriptionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&maasv1alpha1.MaaSModelRef{}, handler.EnqueueRequestsFromMapFunc(
 			r.mapMaaSModelRefToMaaSSubscriptions,
 		)).
-		// Watch generated TokenRateLimitPolicies so manual edits get overwritten by the controller.
-		Watches(generatedTRLP, handler.EnqueueRequestsFromMapFunc(
-			r.mapGeneratedTRLPToParent,
+		// Watch MaaSRateLimitOverrides so their limits reach the TokenRateLimitPolicies.
+		Watches(&maasv1alpha1.MaaSRateLimitOverride{}, handler.EnqueueRequestsFromMapFunc(
+			r.mapOverrideToMaaSSubscriptions,
 		)).
-		Complete(r)
+		// Watch the MaaSConfig so its spec.generatedResources reaches the generated resources.
+		Watches(&maasv1alpha1.MaaSConfig{}, handler.EnqueueRequestsFromMapFunc(
+			r.mapMaaSConfigToMaaSSubscriptions,
+		), builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	// Watch generated TokenRateLimitPolicies so manual edits get overwritten by the controller.
+	// The watch is only registered when Kuadrant is installed; see MaaSAuthPolicyReconciler.SetupWithManager.
+	if kindInstalled(mgr.GetRESTMapper(), tokenRateLimitPolicyGVK) {
+		b = b.Watches(generatedTRLP, handler.EnqueueRequestsFromMapFunc(
+			r.mapGeneratedTRLPToParent,
+		))
+	} else {
+		mgr.GetLogger().Info("Kuadrant TokenRateLimitPolicy CRD not installed, generated TokenRateLimitPolicy watch disabled")
+	}
+	if kindInstalled(mgr.GetRESTMapper(), rateLimitPolicyGVK) {
+		generatedRLP := &unstructured.Unstructured{}
+		generatedRLP.SetGroupVersionKind(rateLimitPolicyGVK)
+		b = b.Watches(generatedRLP, handler.EnqueueRequestsFromMapFunc(
+			r.mapGeneratedTRLPToParent,
+		))
+	} else {
+		mgr.GetLogger().Info("Kuadrant RateLimitPolicy CRD not installed, generated RateLimitPolicy watch disabled")
+	}
+	return b.Complete(tracing.Reconciler("maassubscription", r))
 }
 
 // duplicatePriorityScanHandler runs a full duplicate-priority scan without enqueuing reconciles.
@@ -656,7 +711,7 @@ func (r *MaaSSubscriptionReconciler) mapGeneratedTRLPToParent(ctx context.Contex
 }
 
 // mapMaaSModelRefToMaaSSubscriptions returns reconcile requests for all MaaSSubscriptions
-// that reference the given MaaSModelRef.
+// that reference the given MaaSModelRef or one of its ancestors.
 func (r *MaaSSubscriptionReconciler) mapMaaSModelRefToMaaSSubscriptions(ctx context.Context, obj client.Object) []reconcile.Request {
 	model, ok := obj.(*maasv1alpha1.MaaSModelRef)
 	if !ok {
@@ -668,6 +723,16 @@ func (r *MaaSSubscriptionReconciler) mapMaaSModelRefToMaaSSubscriptions(ctx cont
 	if err := r.List(ctx, &subscriptions, client.MatchingFields{modelRefIndexKey: modelKey}); err != nil {
 		return nil
 	}
+	// Subscriptions of the model's ancestors also cover it, so a new or re-parented
+	// variant gets its TokenRateLimitPolicy from them.
+	ancestors, _ := modelAncestors(ctx, r.Client, model)
+	for _, a := range ancestors {
+		var inherited maasv1alpha1.MaaSSubscriptionList
+		if err := r.List(ctx, &inherited, client.MatchingFields{modelRefIndexKey: a.Namespace + "/" + a.Name}); err != nil {
+			continue
+		}
+		subscriptions.Items = append(subscriptions.Items, inherited.Items...)
+	}
 	// Deduplicate requests (same subscription shouldn't be queued multiple times)
 	seen := make(map[types.NamespacedName]struct{}, len(subscriptions.Items))
 	var requests []reconcile.Request
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
@@ -502,6 +503,215 @@ func TestMaaSSubscriptionReconciler_MultipleSubscriptionsDeletion(t *testing.T)
 	}
 }
 
+// TestMaaSSubscriptionReconciler_Expiry verifies that an expired subscription is reported as
+// Expired and left out of the aggregated TokenRateLimitPolicy, while one that has yet to expire
+// stays Active and is requeued for its expiry.
+func TestMaaSSubscriptionReconciler_Expiry(t *testing.T) {
+	const (
+		modelName      = "expiring-model"
+		modelNamespace = "llm"
+		subNS          = "opendatahub"
+		trlpName       = "maas-trlp-" + modelName
+	)
+
+	model := newMaaSModelRef(modelName, modelNamespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, modelNamespace)
+	active := newMaaSSubscription("active-sub", subNS, "team-a", modelName, 100)
+	active.Spec.ModelRefs[0].Namespace = modelNamespace
+	active.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Hour)}
+	expired := newMaaSSubscription("expired-sub", subNS, "team-b", modelName, 200)
+	expired.Spec.ModelRefs[0].Namespace = modelNamespace
+	expired.Spec.ExpiresAt = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, active, expired).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "active-sub", Namespace: subNS}})
+	if err != nil {
+		t.Fatalf("Reconcile active-sub: %v", err)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("active-sub RequeueAfter = %v, want a requeue at expiry within the hour", result.RequeueAfter)
+	}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "expired-sub", Namespace: subNS}}); err != nil {
+		t.Fatalf("Reconcile expired-sub: %v", err)
+	}
+
+	got := &maasv1alpha1.MaaSSubscription{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "expired-sub", Namespace: subNS}, got); err != nil {
+		t.Fatalf("Get expired-sub: %v", err)
+	}
+	if got.Status.Phase != "Expired" {
+		t.Errorf("expired-sub phase = %q, want Expired", got.Status.Phase)
+	}
+	if !apimeta.IsStatusConditionTrue(got.Status.Conditions, ConditionExpired) {
+		t.Errorf("expired-sub should have condition %s=True, got %+v", ConditionExpired, got.Status.Conditions)
+	}
+
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "active-sub", Namespace: subNS}, got); err != nil {
+		t.Fatalf("Get active-sub: %v", err)
+	}
+	if got.Status.Phase != "Active" {
+		t.Errorf("active-sub phase = %q, want Active", got.Status.Phase)
+	}
+	if cond := apimeta.FindStatusCondition(got.Status.Conditions, ConditionExpired); cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Errorf("active-sub should have condition %s=False, got %+v", ConditionExpired, cond)
+	}
+
+	trlp := &unstructured.Unstructured{}
+	trlp.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"})
+	if err := c.Get(context.Background(), types.NamespacedName{Name: trlpName, Namespace: modelNamespace}, trlp); err != nil {
+		t.Fatalf("Get TokenRateLimitPolicy: %v", err)
+	}
+	limits, _, _ := unstructured.NestedMap(trlp.Object, "spec", "limits")
+	if _, ok := limits[fmt.Sprintf("%s-active-sub-%s-tokens", subNS, modelName)]; !ok {
+		t.Errorf("TRLP should contain limits for active-sub, got keys %v", limits)
+	}
+	if _, ok := limits[fmt.Sprintf("%s-expired-sub-%s-tokens", subNS, modelName)]; ok {
+		t.Errorf("TRLP should not contain limits for expired-sub")
+	}
+}
+
+// TestMaaSSubscriptionReconciler_QuotaExceeded verifies that a subscription whose daily token cap
+// maas-api reported as reached is QuotaExceeded until the reset time and requeued for it, and that
+// one with an elapsed or missing report is within quota.
+func TestMaaSSubscriptionReconciler_QuotaExceeded(t *testing.T) {
+	const (
+		modelName      = "capped-model"
+		modelNamespace = "llm"
+		subNS          = "opendatahub"
+	)
+	maxTokens := int64(1000)
+	model := newMaaSModelRef(modelName, modelNamespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, modelNamespace)
+	exceeded := newMaaSSubscription("exceeded-sub", subNS, "team-a", modelName, 100)
+	exceeded.Spec.ModelRefs[0].Namespace = modelNamespace
+	exceeded.Spec.MaxTokensPerDay = &maxTokens
+	exceeded.Annotations = map[string]string{QuotaExceededUntilAnnotation: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+	reset := newMaaSSubscription("reset-sub", subNS, "team-b", modelName, 200)
+	reset.Spec.ModelRefs[0].Namespace = modelNamespace
+	reset.Spec.MaxTokensPerDay = &maxTokens
+	reset.Annotations = map[string]string{QuotaExceededUntilAnnotation: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, exceeded, reset).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+
+	for name, wantRequeue := range map[string]bool{"exceeded-sub": true, "reset-sub": false} {
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: subNS}})
+		if err != nil {
+			t.Fatalf("Reconcile %s: %v", name, err)
+		}
+		if got := result.RequeueAfter > 0 && result.RequeueAfter <= time.Hour; got != wantRequeue {
+			t.Errorf("%s RequeueAfter = %v, want a requeue at reset: %v", name, result.RequeueAfter, wantRequeue)
+		}
+		got := &maasv1alpha1.MaaSSubscription{}
+		if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: subNS}, got); err != nil {
+			t.Fatalf("Get %s: %v", name, err)
+		}
+		if cond := apimeta.FindStatusCondition(got.Status.Conditions, ConditionQuotaExceeded); cond == nil || (cond.Status == metav1.ConditionTrue) != wantRequeue {
+			t.Errorf("%s condition %s = %+v, want True: %v", name, ConditionQuotaExceeded, cond, wantRequeue)
+		}
+		if got.Status.Phase != "Active" {
+			t.Errorf("%s phase = %q, want Active", name, got.Status.Phase)
+		}
+	}
+}
+
+// TestMaaSSubscriptionReconciler_DowngradeGracePeriod verifies that owners removed from a
+// subscription with a grace period are recorded in the status until the period ends, that the
+// subscription is requeued for it, and that owners added back are dropped.
+func TestMaaSSubscriptionReconciler_DowngradeGracePeriod(t *testing.T) {
+	const (
+		modelName      = "graced-model"
+		modelNamespace = "llm"
+		subNS          = "opendatahub"
+	)
+	model := newMaaSModelRef(modelName, modelNamespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, modelNamespace)
+	sub := newMaaSSubscription("premium", subNS, "team-a", modelName, 100)
+	sub.Spec.ModelRefs[0].Namespace = modelNamespace
+	sub.Spec.Owner.Users = []string{"alice"}
+	sub.Spec.DowngradeGracePeriod = &metav1.Duration{Duration: time.Hour}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, sub).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+	key := types.NamespacedName{Name: "premium", Namespace: subNS}
+	reconcile := func() (ctrl.Result, *maasv1alpha1.MaaSSubscription) {
+		t.Helper()
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+		got := &maasv1alpha1.MaaSSubscription{}
+		if err := c.Get(context.Background(), key, got); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		return result, got
+	}
+	update := func(got *maasv1alpha1.MaaSSubscription, owner maasv1alpha1.OwnerSpec) {
+		t.Helper()
+		got.Spec.Owner = owner
+		if err := c.Update(context.Background(), got); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	_, got := reconcile()
+	if got.Status.ObservedOwner == nil || len(got.Status.RevokedOwners) != 0 {
+		t.Fatalf("first reconcile should only observe the owners, got %+v", got.Status)
+	}
+
+	update(got, maasv1alpha1.OwnerSpec{Groups: []maasv1alpha1.GroupReference{{Name: "team-b"}}})
+	result, got := reconcile()
+	if len(got.Status.RevokedOwners) != 2 {
+		t.Fatalf("RevokedOwners = %+v, want team-a and alice", got.Status.RevokedOwners)
+	}
+	for _, revoked := range got.Status.RevokedOwners {
+		if revoked.Group != "team-a" && revoked.User != "alice" {
+			t.Errorf("unexpected revoked owner %+v", revoked)
+		}
+		if ends := time.Until(revoked.GraceEndsAt.Time); ends <= 0 || ends > time.Hour {
+			t.Errorf("%+v grace should end within the hour", revoked)
+		}
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > time.Hour {
+		t.Errorf("RequeueAfter = %v, want a requeue when the grace period ends", result.RequeueAfter)
+	}
+
+	update(got, maasv1alpha1.OwnerSpec{Groups: []maasv1alpha1.GroupReference{{Name: "team-a"}, {Name: "team-b"}}})
+	_, got = reconcile()
+	if len(got.Status.RevokedOwners) != 1 || got.Status.RevokedOwners[0].User != "alice" {
+		t.Errorf("RevokedOwners = %+v, want only alice once team-a is added back", got.Status.RevokedOwners)
+	}
+
+	if reconcileRevokedOwners(got, time.Now().Add(2*time.Hour)) != 0 || got.Status.RevokedOwners != nil {
+		t.Errorf("RevokedOwners = %+v, want none once the grace period ended", got.Status.RevokedOwners)
+	}
+	got.Spec.DowngradeGracePeriod = nil
+	reconcileRevokedOwners(got, time.Now())
+	if got.Status.ObservedOwner != nil {
+		t.Errorf("ObservedOwner should be cleared without a grace period, got %+v", got.Status.ObservedOwner)
+	}
+}
+
 // TestMaaSSubscriptionReconciler_SimplifiedTRLP verifies the TRLP no longer contains
 // membership checks, header validation, or deny rules. It should trust auth.identity.selected_subscription.
 func TestMaaSSubscriptionReconciler_SimplifiedTRLP(t *testing.T) {
@@ -0,0 +1,255 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// MaintenanceAnnotation set to "true" puts a MaaSModelRef in maintenance until it is removed:
+// maas-api denies requests to the model with reason model_maintenance, while its HTTPRoute and
+// generated policies are kept so traffic resumes as soon as the annotation is removed.
+const MaintenanceAnnotation = "maas.opendatahub.io/maintenance"
+
+// ConditionDegraded is True while a model is in maintenance.
+const ConditionDegraded = "Degraded"
+
+// maxMaintenanceWindow bounds spec.maintenanceWindows[].duration, which also bounds how far back
+// the controller looks for the start of the current window.
+const maxMaintenanceWindow = 7 * 24 * time.Hour
+
+// maintenanceChanged returns true when the maintenance annotation is added or removed. Annotation
+// changes do not bump the generation, so GenerationChangedPredicate alone would miss them.
+func maintenanceChanged(e event.UpdateEvent) bool {
+	return e.ObjectOld.GetAnnotations()[MaintenanceAnnotation] != e.ObjectNew.GetAnnotations()[MaintenanceAnnotation]
+}
+
+// ValidateMaintenanceWindows checks the schedules and durations of spec.maintenanceWindows.
+func ValidateMaintenanceWindows(windows []maasv1alpha1.MaintenanceWindow) error {
+	for i, w := range windows {
+		if _, err := parseSchedule(w.Schedule); err != nil {
+			return fmt.Errorf("spec.maintenanceWindows[%d].schedule: %w", i, err)
+		}
+		if w.Duration.Duration <= 0 || w.Duration.Duration > maxMaintenanceWindow {
+			return fmt.Errorf("spec.maintenanceWindows[%d].duration %s must be positive and at most %s", i, w.Duration.Duration, maxMaintenanceWindow)
+		}
+	}
+	return nil
+}
+
+// reconcileMaintenance sets status.maintenance, status.nextMaintenance and the Degraded condition
+// for now, and returns when the model next enters or leaves maintenance (zero if never). Invalid
+// windows are skipped; the validating webhook rejects them.
+func reconcileMaintenance(model *maasv1alpha1.MaaSModelRef, now time.Time) time.Time {
+	var until, next time.Time
+	active := false
+	for _, w := range model.Spec.MaintenanceWindows {
+		schedule, err := parseSchedule(w.Schedule)
+		if err != nil || w.Duration.Duration <= 0 || w.Duration.Duration > maxMaintenanceWindow {
+			continue
+		}
+		if start, ok := schedule.prev(now, w.Duration.Duration); ok && now.Before(start.Add(w.Duration.Duration)) {
+			active = true
+			if end := start.Add(w.Duration.Duration); end.After(until) {
+				until = end
+			}
+		}
+		if start, ok := schedule.next(now); ok && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	annotated := model.GetAnnotations()[MaintenanceAnnotation] == "true"
+
+	model.Status.NextMaintenance = nil
+	if !next.IsZero() {
+		model.Status.NextMaintenance = &metav1.Time{Time: next}
+	}
+	switch {
+	case annotated:
+		model.Status.Maintenance = &maasv1alpha1.MaintenanceStatus{}
+		apimeta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+			Type: ConditionDegraded, Status: metav1.ConditionTrue, Reason: "Maintenance", ObservedGeneration: model.GetGeneration(),
+			Message: "Model is in maintenance; remove the " + MaintenanceAnnotation + " annotation to resume traffic",
+		})
+	case active:
+		model.Status.Maintenance = &maasv1alpha1.MaintenanceStatus{Until: &metav1.Time{Time: until}}
+		apimeta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+			Type: ConditionDegraded, Status: metav1.ConditionTrue, Reason: "MaintenanceWindow", ObservedGeneration: model.GetGeneration(),
+			Message: "Model is in a maintenance window until " + until.UTC().Format(time.RFC3339),
+		})
+	default:
+		model.Status.Maintenance = nil
+		if len(model.Spec.MaintenanceWindows) > 0 || apimeta.FindStatusCondition(model.Status.Conditions, ConditionDegraded) != nil {
+			apimeta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+				Type: ConditionDegraded, Status: metav1.ConditionFalse, Reason: "NotInMaintenance", ObservedGeneration: model.GetGeneration(),
+				Message: "Model is not in maintenance",
+			})
+		}
+	}
+
+	if active && (next.IsZero() || until.Before(next)) {
+		return until
+	}
+	return next
+}
+
+// schedule is a parsed five-field cron expression. Each field is a bitset of the allowed values.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny record a "*" day of month or day of week. As in cron, a day matches when
+	// both fields match, or either one when neither is "*".
+	domAny, dowAny bool
+}
+
+// parseSchedule parses "minute hour day-of-month month day-of-week". Fields accept "*", values,
+// ranges ("1-5"), steps ("*/15", "0-30/10") and comma-separated lists. Day of week 0 and 7 are
+// Sunday.
+func parseSchedule(expr string) (*schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields: minute hour day-of-month month day-of-week", expr)
+	}
+	var s schedule
+	var err error
+	for i, f := range []struct {
+		bits            *uint64
+		lowest, highest int
+		name            string
+	}{
+		{&s.minute, 0, 59, "minute"},
+		{&s.hour, 0, 23, "hour"},
+		{&s.dom, 1, 31, "day of month"},
+		{&s.month, 1, 12, "month"},
+		{&s.dow, 0, 7, "day of week"},
+	} {
+		if *f.bits, err = parseField(fields[i], f.lowest, f.highest); err != nil {
+			return nil, fmt.Errorf("%s %q: %w", f.name, fields[i], err)
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny, s.dowAny = fields[2] == "*", fields[4] == "*"
+	return &s, nil
+}
+
+func parseField(field string, lowest, highest int) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n < 1 {
+				return 0, errors.New("step must be a positive number")
+			}
+			step = n
+		}
+		lo, hi := lowest, highest
+		if rangePart != "*" {
+			loPart, hiPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(loPart); err != nil {
+				return 0, fmt.Errorf("%q is not a number", loPart)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiPart); err != nil {
+					return 0, fmt.Errorf("%q is not a number", hiPart)
+				}
+			} else if hasStep {
+				hi = highest
+			}
+		}
+		if lo < lowest || hi > highest || lo > hi {
+			return 0, fmt.Errorf("values must be between %d and %d", lowest, highest)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (s *schedule) dayMatches(day time.Time) bool {
+	dom := s.dom&(1<<day.Day()) != 0
+	dow := s.dow&(1<<int(day.Weekday())) != 0
+	if s.month&(1<<int(day.Month())) == 0 {
+		return false
+	}
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// next returns the first start strictly after t, looking up to five years ahead.
+func (s *schedule) next(t time.Time) (time.Time, bool) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	for d := 0; d <= 5*366; d++ {
+		if !s.dayMatches(day) {
+			day = day.AddDate(0, 0, 1)
+			continue
+		}
+		for h := 0; h < 24; h++ {
+			if s.hour&(1<<h) == 0 {
+				continue
+			}
+			for m := 0; m < 60; m++ {
+				if start := day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute); s.minute&(1<<m) != 0 && start.After(t) {
+					return start, true
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}, false
+}
+
+// prev returns the last start at or before t, looking back no further than within.
+func (s *schedule) prev(t time.Time, within time.Duration) (time.Time, bool) {
+	t = t.UTC()
+	earliest := t.Add(-within)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	for !day.Add(24 * time.Hour).Before(earliest) {
+		if s.dayMatches(day) {
+			for h := 23; h >= 0; h-- {
+				if s.hour&(1<<h) == 0 {
+					continue
+				}
+				for m := 59; m >= 0; m-- {
+					if start := day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute); s.minute&(1<<m) != 0 && !start.After(t) {
+						return start, !start.Before(earliest)
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}, false
+}
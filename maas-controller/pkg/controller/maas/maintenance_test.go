@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Wednesday 2025-06-11 10:30 UTC.
+	now := time.Date(2025, 6, 11, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 6, 11, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, 6, 12, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 6", time.Date(2025, 6, 14, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 7", time.Date(2025, 6, 15, 2, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * 1-5", time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * 12 *", time.Date(2025, 12, 1, 9, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := parseSchedule(tt.expr)
+		if err != nil {
+			t.Errorf("parseSchedule(%q): %v", tt.expr, err)
+			continue
+		}
+		if got, ok := s.next(now); !ok || !got.Equal(tt.next) {
+			t.Errorf("next(%q) = %v, %v; want %v", tt.expr, got, ok, tt.next)
+		}
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := parseSchedule(expr); err == nil {
+			t.Errorf("parseSchedule(%q) succeeded, want an error", expr)
+		}
+	}
+
+	s, _ := parseSchedule("0 2 * * 6")
+	if got, ok := s.prev(now, 7*24*time.Hour); !ok || !got.Equal(time.Date(2025, 6, 7, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("prev = %v, %v; want last Saturday 02:00", got, ok)
+	}
+	if _, ok := s.prev(now, 24*time.Hour); ok {
+		t.Error("prev found a start older than within")
+	}
+}
+
+func TestReconcileMaintenance(t *testing.T) {
+	now := time.Date(2025, 6, 11, 10, 30, 0, 0, time.UTC)
+	window := func(schedule string, d time.Duration) []maasv1alpha1.MaintenanceWindow {
+		return []maasv1alpha1.MaintenanceWindow{{Schedule: schedule, Duration: metav1.Duration{Duration: d}}}
+	}
+
+	t.Run("in window", func(t *testing.T) {
+		model := newMaaSModelRef("m", "default", "ExternalModel", "m")
+		model.Spec.MaintenanceWindows = window("0 10 * * *", time.Hour)
+		change := reconcileMaintenance(model, now)
+		wantUntil := time.Date(2025, 6, 11, 11, 0, 0, 0, time.UTC)
+		if model.Status.Maintenance == nil || model.Status.Maintenance.Until == nil || !model.Status.Maintenance.Until.Time.Equal(wantUntil) {
+			t.Fatalf("status.maintenance = %+v, want until %v", model.Status.Maintenance, wantUntil)
+		}
+		if !change.Equal(wantUntil) {
+			t.Errorf("next change = %v, want the end of the window", change)
+		}
+		if c := apimeta.FindStatusCondition(model.Status.Conditions, ConditionDegraded); c == nil || c.Status != metav1.ConditionTrue || c.Reason != "MaintenanceWindow" {
+			t.Errorf("Degraded = %+v, want True/MaintenanceWindow", c)
+		}
+	})
+
+	t.Run("outside window", func(t *testing.T) {
+		model := newMaaSModelRef("m", "default", "ExternalModel", "m")
+		model.Spec.MaintenanceWindows = window("0 2 * * *", time.Hour)
+		change := reconcileMaintenance(model, now)
+		wantNext := time.Date(2025, 6, 12, 2, 0, 0, 0, time.UTC)
+		if model.Status.Maintenance != nil {
+			t.Errorf("status.maintenance = %+v, want nil", model.Status.Maintenance)
+		}
+		if model.Status.NextMaintenance == nil || !model.Status.NextMaintenance.Time.Equal(wantNext) || !change.Equal(wantNext) {
+			t.Errorf("nextMaintenance = %v, next change = %v; want %v", model.Status.NextMaintenance, change, wantNext)
+		}
+		if c := apimeta.FindStatusCondition(model.Status.Conditions, ConditionDegraded); c == nil || c.Status != metav1.ConditionFalse {
+			t.Errorf("Degraded = %+v, want False", c)
+		}
+	})
+
+	t.Run("no windows", func(t *testing.T) {
+		model := newMaaSModelRef("m", "default", "ExternalModel", "m")
+		if change := reconcileMaintenance(model, now); !change.IsZero() {
+			t.Errorf("next change = %v, want none", change)
+		}
+		if len(model.Status.Conditions) != 0 {
+			t.Errorf("conditions = %v, want no Degraded condition on a model without maintenance", model.Status.Conditions)
+		}
+	})
+}
+
+func TestMaaSModelRefReconciler_MaintenanceAnnotation(t *testing.T) {
+	ctx := context.Background()
+	const ns = "default"
+	model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+	model.Annotations = map[string]string{MaintenanceAnnotation: "true"}
+	r, c := newTestReconciler(model, newLLMISvcRoute("llama", ns), newLLMISvc("llama", ns, corev1.ConditionTrue))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status.Phase != "Ready" || got.Status.Maintenance == nil || got.Status.Maintenance.Until != nil {
+		t.Errorf("status = phase %q, maintenance %+v; want Ready in maintenance without an end", got.Status.Phase, got.Status.Maintenance)
+	}
+	if !apimeta.IsStatusConditionTrue(got.Status.Conditions, ConditionDegraded) {
+		t.Error("Degraded should be True while the maintenance annotation is set")
+	}
+
+	got.Annotations = nil
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status.Maintenance != nil || !apimeta.IsStatusConditionFalse(got.Status.Conditions, ConditionDegraded) {
+		t.Errorf("status = maintenance %+v, conditions %v; want out of maintenance and Degraded False", got.Status.Maintenance, got.Status.Conditions)
+	}
+}
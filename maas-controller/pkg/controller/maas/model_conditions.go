@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// setModelCondition sets a condition of the model for its current generation.
+func setModelCondition(model *maasv1alpha1.MaaSModelRef, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&model.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: model.GetGeneration(),
+	})
+}
+
+// setRouteCondition records whether the model's HTTPRoute was reconciled; reason explains a
+// failure and is ignored on success.
+func setRouteCondition(model *maasv1alpha1.MaaSModelRef, reconciled bool, reason, message string) {
+	if reconciled {
+		setModelCondition(model, ConditionRouteReconciled, metav1.ConditionTrue, "Reconciled", "HTTPRoute is attached to the gateway")
+		return
+	}
+	setModelCondition(model, ConditionRouteReconciled, metav1.ConditionFalse, reason, message)
+}
+
+// setEndpointCondition derives EndpointResolved from status.endpoint. reason and message, those of
+// the Ready condition, explain why there is no endpoint.
+func setEndpointCondition(model *maasv1alpha1.MaaSModelRef, reason, message string) {
+	if model.Status.Endpoint != "" {
+		setModelCondition(model, ConditionEndpointResolved, metav1.ConditionTrue, "Resolved", model.Status.Endpoint)
+		return
+	}
+	setModelCondition(model, ConditionEndpointResolved, metav1.ConditionFalse, reason, message)
+}
+
+// setPolicyAttachedCondition records whether an AuthPolicy generated from the model's
+// MaaSAuthPolicies is in place and enforced. Without one the gateway denies every request to the
+// model, which otherwise only shows as 403s at request time.
+func (r *MaaSModelRefReconciler) setPolicyAttachedCondition(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) {
+	policies, err := authPoliciesForModel(ctx, r.Client, model.Namespace, model.Name)
+	if err != nil {
+		log.Error(err, "failed to list MaaSAuthPolicies for PolicyAttached condition")
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionUnknown, "LookupFailed", err.Error())
+		return
+	}
+	if len(policies) == 0 {
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionFalse, "NoMaaSAuthPolicy",
+			"no MaaSAuthPolicy grants access to this model; the gateway denies all requests to it")
+		return
+	}
+	names := make([]string, 0, len(policies))
+	for _, p := range policies {
+		names = append(names, p.Namespace+"/"+p.Name)
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(authPolicyGVK.GroupVersion().WithKind("AuthPolicyList"))
+	err = r.List(ctx, list, client.MatchingLabels{
+		"maas.opendatahub.io/model":           model.Name,
+		"maas.opendatahub.io/model-namespace": model.Namespace,
+		"app.kubernetes.io/part-of":           "maas-auth-policy",
+	})
+	switch {
+	case apimeta.IsNoMatchError(err):
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionFalse, "KuadrantNotInstalled",
+			"Kuadrant is not installed, so the AuthPolicy of MaaSAuthPolicies "+strings.Join(names, ", ")+" cannot be generated")
+		return
+	case err != nil:
+		log.Error(err, "failed to list AuthPolicies for PolicyAttached condition")
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionUnknown, "LookupFailed", err.Error())
+		return
+	case len(list.Items) == 0:
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionFalse, "AuthPolicyNotGenerated",
+			"AuthPolicy of MaaSAuthPolicies "+strings.Join(names, ", ")+" has not been generated yet")
+		return
+	}
+
+	ap := &list.Items[0]
+	key := ap.GetNamespace() + "/" + ap.GetName()
+	accepted, enforced := getAuthPolicyConditionState(ap)
+	switch {
+	case enforced == string(metav1.ConditionTrue):
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionTrue, "Enforced",
+			fmt.Sprintf("AuthPolicy %s from MaaSAuthPolicies %s is enforced", key, strings.Join(names, ", ")))
+	case accepted == string(metav1.ConditionFalse):
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionFalse, "AuthPolicyNotAccepted",
+			fmt.Sprintf("AuthPolicy %s was not accepted by Kuadrant", key))
+	default:
+		setModelCondition(model, ConditionPolicyAttached, metav1.ConditionFalse, "AuthPolicyNotEnforced",
+			fmt.Sprintf("AuthPolicy %s is not enforced yet (accepted: %s, enforced: %s)", key, accepted, enforced))
+	}
+}
+
+// setTierAnnotationCondition validates the tiers annotation, which discovery copies from the
+// LLMInferenceService, so a malformed value shows on the model rather than only as a missing tier.
+// The condition is removed along with the annotation.
+func (r *MaaSModelRefReconciler) setTierAnnotationCondition(ctx context.Context, model *maasv1alpha1.MaaSModelRef) {
+	value, ok := model.Annotations[TiersAnnotation]
+	if !ok {
+		apimeta.RemoveStatusCondition(&model.Status.Conditions, ConditionTierAnnotationInvalid)
+		return
+	}
+	tiers, err := parseTiers(value)
+	if err != nil {
+		setModelCondition(model, ConditionTierAnnotationInvalid, metav1.ConditionTrue, "MalformedAnnotation",
+			fmt.Sprintf("%s annotation %q: %v", TiersAnnotation, value, err))
+		return
+	}
+	if r.SubscriptionNamespace != "" {
+		var missing []string
+		for _, tier := range tiers {
+			sub := &maasv1alpha1.MaaSSubscription{}
+			err := r.Get(ctx, types.NamespacedName{Namespace: r.SubscriptionNamespace, Name: tier}, sub)
+			if apierrors.IsNotFound(err) {
+				missing = append(missing, tier)
+			} else if err != nil {
+				setModelCondition(model, ConditionTierAnnotationInvalid, metav1.ConditionUnknown, "LookupFailed", err.Error())
+				return
+			}
+		}
+		if len(missing) > 0 {
+			setModelCondition(model, ConditionTierAnnotationInvalid, metav1.ConditionTrue, "UnknownTier",
+				fmt.Sprintf("tiers without a MaaSSubscription in %s: %s", r.SubscriptionNamespace, strings.Join(missing, ", ")))
+			return
+		}
+	}
+	setModelCondition(model, ConditionTierAnnotationInvalid, metav1.ConditionFalse, "Valid",
+		"tiers: "+strings.Join(tiers, ", "))
+}
+
+// tiersAnnotationChanged passes updates that change the tiers annotation, which discovery writes.
+func tiersAnnotationChanged(e event.UpdateEvent) bool {
+	return e.ObjectOld.GetAnnotations()[TiersAnnotation] != e.ObjectNew.GetAnnotations()[TiersAnnotation]
+}
+
+// mapGeneratedAuthPolicyToMaaSModelRef enqueues the model a generated AuthPolicy is for, so
+// PolicyAttached follows the AuthPolicy and its Kuadrant status.
+func (r *MaaSModelRefReconciler) mapGeneratedAuthPolicyToMaaSModelRef(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels[managedByLabel] != managedByValue || labels["maas.opendatahub.io/model"] == "" {
+		return nil
+	}
+	namespace := labels["maas.opendatahub.io/model-namespace"]
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: labels["maas.opendatahub.io/model"]}}}
+}
+
+// mapSubscriptionToAnnotatedModels enqueues the models whose tiers annotation names the
+// subscription, so TierAnnotationInvalid follows tiers being created and deleted.
+func (r *MaaSModelRefReconciler) mapSubscriptionToAnnotatedModels(ctx context.Context, obj client.Object) []reconcile.Request {
+	if r.SubscriptionNamespace == "" || obj.GetNamespace() != r.SubscriptionNamespace {
+		return nil
+	}
+	var models maasv1alpha1.MaaSModelRefList
+	if err := r.List(ctx, &models); err != nil {
+		logr.FromContextOrDiscard(ctx).Error(err, "failed to list MaaSModelRefs for tier", "tier", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, m := range models.Items {
+		value, ok := m.Annotations[TiersAnnotation]
+		if !ok {
+			continue
+		}
+		// Malformed values name no tier, so their models are not affected.
+		if tiers, err := parseTiers(value); err == nil && slices.Contains(tiers, obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: m.Namespace, Name: m.Name}})
+		}
+	}
+	return requests
+}
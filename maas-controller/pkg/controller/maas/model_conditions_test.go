@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// newConditionsReconciler is newTestReconciler with a REST mapper for AuthPolicies, the
+// subscription namespace and an event recorder.
+func newConditionsReconciler(objects ...client.Object) (*MaaSModelRefReconciler, client.Client, *record.FakeRecorder) {
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(objects...).
+		WithStatusSubresource(&maasv1alpha1.MaaSModelRef{}).
+		WithIndex(&maasv1alpha1.MaaSModelRef{}, modelRefNameIndex, modelRefNameIndexer).
+		Build()
+	recorder := record.NewFakeRecorder(20)
+	return &MaaSModelRefReconciler{Client: c, Scheme: scheme, SubscriptionNamespace: discoverySubNamespace, Recorder: recorder}, c, recorder
+}
+
+// generatedAuthPolicy is the AuthPolicy the MaaSAuthPolicy controller generates for a model, with
+// the given Kuadrant Enforced status.
+func generatedAuthPolicy(model, ns string, enforced metav1.ConditionStatus) *unstructured.Unstructured {
+	ap := newPreexistingGeneratedPolicy(authPolicyGVK, "maas-auth-"+model, ns, model, nil)
+	labels := ap.GetLabels()
+	labels["maas.opendatahub.io/model-namespace"] = ns
+	labels["app.kubernetes.io/part-of"] = "maas-auth-policy"
+	ap.SetLabels(labels)
+	_ = unstructured.SetNestedSlice(ap.Object, []any{
+		map[string]any{"type": "Accepted", "status": "True"},
+		map[string]any{"type": "Enforced", "status": string(enforced)},
+	}, "status", "conditions")
+	return ap
+}
+
+func assertCondition(t *testing.T, model *maasv1alpha1.MaaSModelRef, conditionType string, wantStatus metav1.ConditionStatus, wantReason string) {
+	t.Helper()
+	c := apimeta.FindStatusCondition(model.Status.Conditions, conditionType)
+	if c == nil {
+		t.Errorf("%s condition not found", conditionType)
+		return
+	}
+	if c.Status != wantStatus || c.Reason != wantReason {
+		t.Errorf("%s = %s/%s (%s), want %s/%s", conditionType, c.Status, c.Reason, c.Message, wantStatus, wantReason)
+	}
+}
+
+// drainEvents returns the events recorded so far.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
+func TestMaaSModelRefReconciler_Conditions(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	model := newMaaSModelRef("granite", ns, "LLMInferenceService", "granite")
+	model.Annotations = map[string]string{TiersAnnotation: "free,gold"}
+	r, c, recorder := newConditionsReconciler(
+		model,
+		newLLMISvcRoute("granite", ns),
+		newLLMISvc("granite", ns, corev1.ConditionTrue),
+		newMaaSSubscription("free", discoverySubNamespace, "everyone", "granite", 100),
+		newMaaSAuthPolicy("everyone", ns, "everyone", maasv1alpha1.ModelRef{Name: "granite", Namespace: ns}),
+		generatedAuthPolicy("granite", ns, metav1.ConditionTrue),
+	)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "granite", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertReadyCondition(t, got.Status.Conditions, metav1.ConditionTrue, "Reconciled")
+	assertCondition(t, got, ConditionRouteReconciled, metav1.ConditionTrue, "Reconciled")
+	assertCondition(t, got, ConditionEndpointResolved, metav1.ConditionTrue, "Resolved")
+	assertCondition(t, got, ConditionPolicyAttached, metav1.ConditionTrue, "Enforced")
+	assertCondition(t, got, ConditionTierAnnotationInvalid, metav1.ConditionTrue, "UnknownTier")
+	if c := apimeta.FindStatusCondition(got.Status.Conditions, ConditionTierAnnotationInvalid); !strings.Contains(c.Message, "gold") {
+		t.Errorf("TierAnnotationInvalid message = %q, want it to name gold", c.Message)
+	}
+
+	events := drainEvents(recorder)
+	if !slices.Contains(events, "Normal Enforced PolicyAttached is True: AuthPolicy llm/maas-auth-granite from MaaSAuthPolicies llm/everyone is enforced") {
+		t.Errorf("events = %q, want PolicyAttached recorded", events)
+	}
+	if !slices.ContainsFunc(events, func(e string) bool { return strings.HasPrefix(e, "Warning UnknownTier TierAnnotationInvalid is True") }) {
+		t.Errorf("events = %q, want a warning for the unknown tier", events)
+	}
+
+	// An unchanged status records no events.
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if events := drainEvents(recorder); len(events) > 0 {
+		t.Errorf("events on unchanged status = %q, want none", events)
+	}
+
+	// The gold tier is created: the annotation becomes valid.
+	if err := c.Create(ctx, newMaaSSubscription("gold", discoverySubNamespace, "paying", "granite", 1000)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	gold := &maasv1alpha1.MaaSSubscription{ObjectMeta: metav1.ObjectMeta{Name: "gold", Namespace: discoverySubNamespace}}
+	if requests := r.mapSubscriptionToAnnotatedModels(ctx, gold); !slices.Contains(requests, req) {
+		t.Fatalf("mapSubscriptionToAnnotatedModels = %v, want %v", requests, req)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertCondition(t, got, ConditionTierAnnotationInvalid, metav1.ConditionFalse, "Valid")
+}
+
+func TestMaaSModelRefReconciler_ConditionsExplainPending(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	model := newMaaSModelRef("granite", ns, "LLMInferenceService", "granite")
+	model.Annotations = map[string]string{TiersAnnotation: `["free",`}
+	// No HTTPRoute, no MaaSAuthPolicy.
+	r, c, recorder := newConditionsReconciler(model, newLLMISvc("granite", ns, corev1.ConditionTrue))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "granite", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status.Phase != "Pending" {
+		t.Errorf("Phase = %q, want Pending", got.Status.Phase)
+	}
+	assertCondition(t, got, ConditionRouteReconciled, metav1.ConditionFalse, "HTTPRouteNotFound")
+	assertCondition(t, got, ConditionEndpointResolved, metav1.ConditionFalse, "BackendNotReady")
+	assertCondition(t, got, ConditionPolicyAttached, metav1.ConditionFalse, "NoMaaSAuthPolicy")
+	assertCondition(t, got, ConditionTierAnnotationInvalid, metav1.ConditionTrue, "MalformedAnnotation")
+
+	events := drainEvents(recorder)
+	for _, want := range []string{"Warning HTTPRouteNotFound", "Warning NoMaaSAuthPolicy", "Warning MalformedAnnotation"} {
+		if !slices.ContainsFunc(events, func(e string) bool { return strings.HasPrefix(e, want) }) {
+			t.Errorf("events = %q, want one starting with %q", events, want)
+		}
+	}
+
+	// Removing the annotation removes the condition.
+	got.Annotations = nil
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if apimeta.FindStatusCondition(got.Status.Conditions, ConditionTierAnnotationInvalid) != nil {
+		t.Error("TierAnnotationInvalid kept after the annotation was removed")
+	}
+}
+
+func TestMaaSModelRefReconciler_PolicyNotEnforced(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	r, c, _ := newConditionsReconciler(
+		newMaaSModelRef("granite", ns, "LLMInferenceService", "granite"),
+		newLLMISvcRoute("granite", ns),
+		newLLMISvc("granite", ns, corev1.ConditionTrue),
+		newMaaSAuthPolicy("everyone", ns, "everyone", maasv1alpha1.ModelRef{Name: "granite", Namespace: ns}),
+		generatedAuthPolicy("granite", ns, metav1.ConditionFalse),
+	)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "granite", Namespace: ns}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertCondition(t, got, ConditionPolicyAttached, metav1.ConditionFalse, "AuthPolicyNotEnforced")
+
+	ap := generatedAuthPolicy("granite", ns, metav1.ConditionFalse)
+	if requests := r.mapGeneratedAuthPolicyToMaaSModelRef(ctx, ap); !slices.Equal(requests, []ctrl.Request{req}) {
+		t.Errorf("mapGeneratedAuthPolicyToMaaSModelRef = %v, want %v", requests, req)
+	}
+}
+
+func TestParseTiers(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{value: "", want: nil},
+		{value: "free, premium,,free", want: []string{"free", "premium"}},
+		{value: `["free", "premium", "free"]`, want: []string{"free", "premium"}},
+		{value: `["free",`, wantErr: true},
+		{value: `[1, 2]`, wantErr: true},
+		{value: "free,Premium Tier", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTiers(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTiers(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseTiers(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// Kinds of generated policies whose names spec.generatedResources.nameTemplates can set.
+const (
+	nameTemplateAuthPolicy           = "AuthPolicy"
+	nameTemplateTokenRateLimitPolicy = "TokenRateLimitPolicy"
+	nameTemplateRateLimitPolicy      = "RateLimitPolicy"
+)
+
+var nameTemplateKinds = []string{nameTemplateAuthPolicy, nameTemplateRateLimitPolicy, nameTemplateTokenRateLimitPolicy}
+
+// nameTemplateData is what a name template sees.
+type nameTemplateData struct {
+	// Name and Namespace are the model's.
+	Name      string
+	Namespace string
+	// Kind is the kind of the generated resource.
+	Kind string
+	// DefaultName is the name the controller uses without a template.
+	DefaultName string
+}
+
+// ValidateGeneratedResources checks the name templates and labels of a MaaSConfig or MaaSModelRef
+// spec.generatedResources.
+func ValidateGeneratedResources(g *maasv1alpha1.GeneratedResources) error {
+	if g == nil {
+		return nil
+	}
+	for kind, text := range g.NameTemplates {
+		if !slices.Contains(nameTemplateKinds, kind) {
+			return fmt.Errorf("spec.generatedResources.nameTemplates: unknown kind %q, must be one of %s", kind, strings.Join(nameTemplateKinds, ", "))
+		}
+		tmpl, err := template.New(kind).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return fmt.Errorf("spec.generatedResources.nameTemplates[%s]: %w", kind, err)
+		}
+		// Render a sample to catch unknown fields and invalid names early.
+		if _, err := renderName(tmpl, nameTemplateData{Name: "model", Namespace: "models", Kind: kind, DefaultName: "maas-model"}); err != nil {
+			return fmt.Errorf("spec.generatedResources.nameTemplates[%s]: %w", kind, err)
+		}
+	}
+	for k, v := range g.Labels {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("spec.generatedResources.labels: invalid key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("spec.generatedResources.labels[%s]: invalid value %q: %s", k, v, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// renderName executes a name template and checks the result is a valid object name.
+func renderName(tmpl *template.Template, data nameTemplateData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(b.String())
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", fmt.Errorf("rendered name %q is invalid: %s", name, strings.Join(errs, "; "))
+	}
+	return name, nil
+}
+
+// generatedNaming is the effective spec.generatedResources of a model: the MaaSConfig's,
+// overridden by the model's own.
+type generatedNaming struct {
+	modelNamespace string
+	modelName      string
+	templates      map[string]string
+	labels         map[string]string
+}
+
+// namingFor reads the MaaSConfig and the model's spec.generatedResources. Without either, names
+// and labels are the controller's defaults.
+func namingFor(ctx context.Context, c client.Reader, modelNamespace, modelName string) (*generatedNaming, error) {
+	n := &generatedNaming{modelNamespace: modelNamespace, modelName: modelName, templates: map[string]string{}, labels: map[string]string{}}
+	cfg := &maasv1alpha1.MaaSConfig{}
+	err := c.Get(ctx, client.ObjectKey{Name: maasv1alpha1.MaaSConfigSingletonName}, cfg)
+	switch {
+	case err == nil:
+		if err := ValidateGeneratedResources(cfg.Spec.GeneratedResources); err != nil {
+			return nil, fmt.Errorf("MaaSConfig %s: %w", cfg.Name, err)
+		}
+		n.merge(cfg.Spec.GeneratedResources)
+	case apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err):
+	default:
+		return nil, fmt.Errorf("failed to get MaaSConfig: %w", err)
+	}
+
+	model := &maasv1alpha1.MaaSModelRef{}
+	err = c.Get(ctx, client.ObjectKey{Namespace: modelNamespace, Name: modelName}, model)
+	switch {
+	case err == nil:
+		if err := ValidateGeneratedResources(model.Spec.GeneratedResources); err != nil {
+			return nil, fmt.Errorf("MaaSModelRef %s/%s: %w", modelNamespace, modelName, err)
+		}
+		n.merge(model.Spec.GeneratedResources)
+	case apierrors.IsNotFound(err):
+	default:
+		return nil, fmt.Errorf("failed to get MaaSModelRef %s/%s: %w", modelNamespace, modelName, err)
+	}
+	return n, nil
+}
+
+// merge applies g over n: its templates replace n's per kind, its labels are merged over n's.
+func (n *generatedNaming) merge(g *maasv1alpha1.GeneratedResources) {
+	if g == nil {
+		return
+	}
+	for kind, text := range g.NameTemplates {
+		n.templates[kind] = text
+	}
+	for k, v := range g.Labels {
+		n.labels[k] = v
+	}
+}
+
+// name returns the name of the model's generated resource of kind, defaultName without a
+// template for the kind.
+func (n *generatedNaming) name(kind, defaultName string) (string, error) {
+	text, ok := n.templates[kind]
+	if !ok {
+		return defaultName, nil
+	}
+	tmpl, err := template.New(kind).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("name template of %s: %w", kind, err)
+	}
+	name, err := renderName(tmpl, nameTemplateData{Name: n.modelName, Namespace: n.modelNamespace, Kind: kind, DefaultName: defaultName})
+	if err != nil {
+		return "", fmt.Errorf("name template of %s: %w", kind, err)
+	}
+	return name, nil
+}
+
+// withLabels returns own plus the configured labels. own wins, so the labels the controller
+// selects its resources by cannot be overridden.
+func (n *generatedNaming) withLabels(own map[string]string) map[string]string {
+	labels := make(map[string]string, len(n.labels)+len(own))
+	for k, v := range n.labels {
+		labels[k] = v
+	}
+	for k, v := range own {
+		labels[k] = v
+	}
+	return labels
+}
+
+// hasLabels reports whether labels include every label of want.
+func hasLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteRenamedPolicies deletes the model's generated policies of gvk other than keep, left
+// behind when a name template changed. selector selects the model's policies of the kind.
+func deleteRenamedPolicies(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, selector client.MatchingLabels, keep client.ObjectKey) ([]string, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, list, selector); err != nil {
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s for renaming: %w", gvk.Kind, err)
+	}
+	var deleted []string
+	for i := range list.Items {
+		p := &list.Items[i]
+		if client.ObjectKeyFromObject(p) == keep || p.GetLabels()[managedByLabel] != managedByValue || !isManaged(p) {
+			continue
+		}
+		if err := c.Delete(ctx, p); err != nil && !apierrors.IsNotFound(err) {
+			return deleted, fmt.Errorf("failed to delete renamed %s %s/%s: %w", gvk.Kind, p.GetNamespace(), p.GetName(), err)
+		}
+		deleted = append(deleted, p.GetNamespace()+"/"+p.GetName())
+	}
+	return deleted, nil
+}
+
+// mapMaaSConfigToMaaSModelRefs enqueues every model when the MaaSConfig changes.
+func (r *MaaSModelRefReconciler) mapMaaSConfigToMaaSModelRefs(ctx context.Context, _ client.Object) []reconcile.Request {
+	var models maasv1alpha1.MaaSModelRefList
+	if err := r.List(ctx, &models); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(models.Items))
+	for _, m := range models.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: m.Namespace, Name: m.Name}})
+	}
+	return requests
+}
+
+// mapMaaSConfigToMaaSAuthPolicies enqueues every MaaSAuthPolicy when the MaaSConfig changes.
+func (r *MaaSAuthPolicyReconciler) mapMaaSConfigToMaaSAuthPolicies(ctx context.Context, _ client.Object) []reconcile.Request {
+	var policies maasv1alpha1.MaaSAuthPolicyList
+	if err := r.List(ctx, &policies); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(policies.Items))
+	for _, p := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: p.Namespace, Name: p.Name}})
+	}
+	return requests
+}
+
+// mapMaaSConfigToMaaSSubscriptions enqueues every MaaSSubscription when the MaaSConfig changes.
+func (r *MaaSSubscriptionReconciler) mapMaaSConfigToMaaSSubscriptions(ctx context.Context, _ client.Object) []reconcile.Request {
+	var subs maasv1alpha1.MaaSSubscriptionList
+	if err := r.List(ctx, &subs); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(subs.Items))
+	for _, s := range subs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: s.Namespace, Name: s.Name}})
+	}
+	return requests
+}
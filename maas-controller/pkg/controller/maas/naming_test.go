@@ -0,0 +1,111 @@
+package maas
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestValidateGeneratedResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		g       *maasv1alpha1.GeneratedResources
+		wantErr bool
+	}{
+		{name: "nil"},
+		{name: "valid", g: &maasv1alpha1.GeneratedResources{
+			NameTemplates: map[string]string{"AuthPolicy": "{{.Namespace}}-{{.Name}}-auth", "RateLimitPolicy": "team-{{.DefaultName}}"},
+			Labels:        map[string]string{"cost-center": "ai", "example.com/team": "platform"},
+		}},
+		{name: "unknown kind", g: &maasv1alpha1.GeneratedResources{NameTemplates: map[string]string{"HTTPRoute": "{{.Name}}"}}, wantErr: true},
+		{name: "unparsable template", g: &maasv1alpha1.GeneratedResources{NameTemplates: map[string]string{"AuthPolicy": "{{.Name"}}, wantErr: true},
+		{name: "unknown field", g: &maasv1alpha1.GeneratedResources{NameTemplates: map[string]string{"AuthPolicy": "{{.Team}}"}}, wantErr: true},
+		{name: "invalid name", g: &maasv1alpha1.GeneratedResources{NameTemplates: map[string]string{"AuthPolicy": "{{.Name}}_AUTH"}}, wantErr: true},
+		{name: "invalid label key", g: &maasv1alpha1.GeneratedResources{Labels: map[string]string{"bad key": "x"}}, wantErr: true},
+		{name: "invalid label value", g: &maasv1alpha1.GeneratedResources{Labels: map[string]string{"team": "a/b"}}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateGeneratedResources(tc.g)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ValidateGeneratedResources() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// TestMaaSAuthPolicyReconciler_GeneratedResources verifies that the AuthPolicy of a model is
+// named by the MaaSConfig's template, carries its labels merged with the model's, and that the
+// AuthPolicy under the old name is deleted.
+func TestMaaSAuthPolicyReconciler_GeneratedResources(t *testing.T) {
+	ctx := context.Background()
+	const (
+		modelName = "llm"
+		namespace = "default"
+	)
+	cfg := &maasv1alpha1.MaaSConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: maasv1alpha1.MaaSConfigSingletonName},
+		Spec: maasv1alpha1.MaaSConfigSpec{GeneratedResources: &maasv1alpha1.GeneratedResources{
+			NameTemplates: map[string]string{nameTemplateAuthPolicy: "{{.Namespace}}-{{.Name}}-auth"},
+			Labels:        map[string]string{"cost-center": "ai-platform", "team": "platform"},
+		}},
+	}
+	model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+	model.Spec.GeneratedResources = &maasv1alpha1.GeneratedResources{
+		Labels: map[string]string{"team": "research", "app.kubernetes.io/managed-by": "someone-else"},
+	}
+	route := newHTTPRoute("maas-model-"+modelName, namespace)
+	maasPolicy := newMaaSAuthPolicy("policy-a", namespace, "team-a", maasv1alpha1.ModelRef{Name: modelName, Namespace: namespace})
+	oldAP := newPreexistingAuthPolicy("maas-auth-"+modelName, namespace, modelName, nil)
+	oldAP.SetLabels(map[string]string{
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": namespace,
+		"app.kubernetes.io/managed-by":        "maas-controller",
+		"app.kubernetes.io/part-of":           "maas-auth-policy",
+	})
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(cfg, model, route, maasPolicy, oldAP).
+		WithStatusSubresource(&maasv1alpha1.MaaSAuthPolicy{}).
+		Build()
+	r := &MaaSAuthPolicyReconciler{Client: c, Scheme: scheme, MaaSAPINamespace: "maas-system"}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "policy-a", Namespace: namespace}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	gvk := schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1", Kind: "AuthPolicy"}
+	got := &unstructured.Unstructured{}
+	got.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, types.NamespacedName{Name: "default-llm-auth", Namespace: namespace}, got); err != nil {
+		t.Fatalf("templated AuthPolicy not created: %v", err)
+	}
+	labels := got.GetLabels()
+	for k, want := range map[string]string{
+		"cost-center":                  "ai-platform",
+		"team":                         "research",
+		"app.kubernetes.io/managed-by": "maas-controller",
+		"maas.opendatahub.io/model":    modelName,
+	} {
+		if labels[k] != want {
+			t.Errorf("label %s = %q, want %q", k, labels[k], want)
+		}
+	}
+
+	old := &unstructured.Unstructured{}
+	old.SetGroupVersionKind(gvk)
+	if err := c.Get(ctx, types.NamespacedName{Name: "maas-auth-" + modelName, Namespace: namespace}, old); !apierrors.IsNotFound(err) {
+		t.Errorf("AuthPolicy under the old name: err = %v, want NotFound", err)
+	}
+}
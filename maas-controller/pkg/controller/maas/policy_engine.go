@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConditionPolicyEngineUnavailable is set True on MaaSAuthPolicy and MaaSSubscription when the
+// Kuadrant policy CRDs are not installed, so policy generation is skipped instead of failing.
+const ConditionPolicyEngineUnavailable = "PolicyEngineUnavailable"
+
+// policyEngineRecheckInterval is how often a skipped resource is requeued to pick up a
+// Kuadrant installation that happened after the controller started.
+const policyEngineRecheckInterval = 2 * time.Minute
+
+// ErrPolicyEngineUnavailable is returned when a Kuadrant policy kind is not served by the API server.
+var ErrPolicyEngineUnavailable = errors.New("kuadrant policy engine is not installed")
+
+var (
+	authPolicyGVK           = schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1", Kind: "AuthPolicy"}
+	tokenRateLimitPolicyGVK = schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"}
+	rateLimitPolicyGVK      = schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1", Kind: "RateLimitPolicy"}
+)
+
+// policyEngineError wraps a no-match error for a Kuadrant kind as ErrPolicyEngineUnavailable.
+// Any other error is returned unchanged.
+func policyEngineError(err error, gvk schema.GroupVersionKind) error {
+	if apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("%w: %s not found", ErrPolicyEngineUnavailable, gvk.GroupKind())
+	}
+	return err
+}
+
+// kindInstalled reports whether the REST mapper can resolve gvk. Errors other than
+// "no match" are treated as installed so a transient discovery failure does not drop a watch.
+func kindInstalled(mapper apimeta.RESTMapper, gvk schema.GroupVersionKind) bool {
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return !apimeta.IsNoMatchError(err)
+}
+
+// setPolicyEngineCondition records whether policy generation ran. The condition is only kept
+// while the engine is missing so clusters with Kuadrant installed see no extra condition.
+func setPolicyEngineCondition(conditions *[]metav1.Condition, unavailable error, generation int64) {
+	if unavailable == nil {
+		apimeta.RemoveStatusCondition(conditions, ConditionPolicyEngineUnavailable)
+		return
+	}
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               ConditionPolicyEngineUnavailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             "KuadrantNotInstalled",
+		Message:            unavailable.Error(),
+		ObservedGeneration: generation,
+	})
+}
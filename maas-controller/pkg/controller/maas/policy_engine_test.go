@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// withoutKuadrant simulates a cluster where the Kuadrant CRDs are not installed:
+// every read of a kuadrant.io object fails with a no-match error.
+var withoutKuadrant = interceptor.Funcs{
+	Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		if gvk.Group == "kuadrant.io" {
+			return &apimeta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+		}
+		return c.Get(ctx, key, obj, opts...)
+	},
+}
+
+func TestKindInstalled(t *testing.T) {
+	mapper := testRESTMapper()
+	if !kindInstalled(mapper, authPolicyGVK) {
+		t.Errorf("kindInstalled(AuthPolicy) = false, want true")
+	}
+	empty := apimeta.NewDefaultRESTMapper(nil)
+	if kindInstalled(empty, tokenRateLimitPolicyGVK) {
+		t.Errorf("kindInstalled(TokenRateLimitPolicy) = true on empty mapper, want false")
+	}
+}
+
+func TestMaaSAuthPolicyReconciler_PolicyEngineUnavailable(t *testing.T) {
+	const (
+		namespace = "default"
+		modelName = "llm"
+	)
+	model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, namespace)
+	maasPolicy := newMaaSAuthPolicy("policy-a", namespace, "team-a", maasv1alpha1.ModelRef{Name: modelName, Namespace: namespace})
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, maasPolicy).
+		WithStatusSubresource(&maasv1alpha1.MaaSAuthPolicy{}).
+		WithInterceptorFuncs(withoutKuadrant).
+		Build()
+
+	r := &MaaSAuthPolicyReconciler{Client: c, Scheme: scheme, MaaSAPINamespace: "maas-system"}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "policy-a", Namespace: namespace}}
+	res, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile: unexpected error without Kuadrant: %v", err)
+	}
+	if res.RequeueAfter != policyEngineRecheckInterval {
+		t.Errorf("RequeueAfter = %v, want %v", res.RequeueAfter, policyEngineRecheckInterval)
+	}
+
+	got := &maasv1alpha1.MaaSAuthPolicy{}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("Get MaaSAuthPolicy: %v", err)
+	}
+	if got.Status.Phase != "Pending" {
+		t.Errorf("Phase = %q, want Pending", got.Status.Phase)
+	}
+	if !apimeta.IsStatusConditionTrue(got.Status.Conditions, ConditionPolicyEngineUnavailable) {
+		t.Errorf("expected %s condition to be True, got %+v", ConditionPolicyEngineUnavailable, got.Status.Conditions)
+	}
+}
+
+func TestMaaSSubscriptionReconciler_PolicyEngineUnavailable(t *testing.T) {
+	const (
+		namespace = "default"
+		modelName = "llm"
+	)
+	model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, namespace)
+	sub := newMaaSSubscription("sub-a", namespace, "team-a", modelName, 100)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, sub).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, modelRefIndexKey, subscriptionModelRefIndexer).
+		WithInterceptorFuncs(withoutKuadrant).
+		Build()
+
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "sub-a", Namespace: namespace}}
+	res, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile: unexpected error without Kuadrant: %v", err)
+	}
+	if res.RequeueAfter != policyEngineRecheckInterval {
+		t.Errorf("RequeueAfter = %v, want %v", res.RequeueAfter, policyEngineRecheckInterval)
+	}
+
+	got := &maasv1alpha1.MaaSSubscription{}
+	if err := c.Get(context.Background(), req.NamespacedName, got); err != nil {
+		t.Fatalf("Get MaaSSubscription: %v", err)
+	}
+	cond := apimeta.FindStatusCondition(got.Status.Conditions, ConditionPolicyEngineUnavailable)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected %s condition to be True, got %+v", ConditionPolicyEngineUnavailable, got.Status.Conditions)
+	}
+
+	// Deleting the subscription must not block on the missing policy engine.
+	if err := c.Delete(context.Background(), got); err != nil {
+		t.Fatalf("Delete MaaSSubscription: %v", err)
+	}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile during deletion: unexpected error without Kuadrant: %v", err)
+	}
+}
@@ -20,6 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,7 +34,7 @@ import (
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
 )
 
-// ErrKindNotImplemented indicates the model kind is recognized but not implemented (e.g. ExternalModel stub).
+// ErrKindNotImplemented indicates the model kind is recognized but not implemented (e.g. a kind registered before its handler).
 var ErrKindNotImplemented = errors.New("model kind not implemented")
 
 // ErrHTTPRouteNotFound indicates the HTTPRoute for a model does not exist yet (normal during startup).
@@ -51,33 +55,84 @@ type BackendHandler interface {
 	// Status returns the endpoint URL and whether the model is ready (phase Ready).
 	Status(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (endpoint string, ready bool, err error)
 	// GetModelEndpoint returns the endpoint URL for the model. Kind-specific: e.g. llmisvc uses gateway/HTTPRoute
-	// hostname + path; ExternalModel (when implemented) would use its own logic and need not follow the same path assumptions.
+	// hostname + path; ExternalModel uses its own logic and need not follow the same path assumptions.
 	GetModelEndpoint(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (string, error)
-	// CleanupOnDelete is called when the MaaSModelRef is deleted (e.g. delete HTTPRoute for ExternalModel).
+	// CleanupOnDelete is called when the MaaSModelRef is deleted (e.g. delete the gateway-namespace CA Secret for ExternalModel).
 	CleanupOnDelete(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error
 }
 
-// backendHandlerFactory creates a BackendHandler that uses the given reconciler for client/scheme and shared helpers.
-type backendHandlerFactory func(*MaaSModelRefReconciler) BackendHandler
+// healthRequeuer is implemented by BackendHandlers that probe their backend: the reconciler
+// requeues the model after the returned delay so the next probe runs (0 means no requeue).
+type healthRequeuer interface {
+	requeueAfter(model *maasv1alpha1.MaaSModelRef) time.Duration
+}
+
+// BackendHandlerFactory creates a BackendHandler that uses the given reconciler for client/scheme and shared helpers.
+type BackendHandlerFactory func(*MaaSModelRefReconciler) BackendHandler
 
-// routeResolverFactory creates a RouteResolver. RouteResolvers are stateless and only need a client.Reader at call time,
+// RouteResolverFactory creates a RouteResolver. RouteResolvers are stateless and only need a client.Reader at call time,
 // so we pass the reader in HTTPRouteForModel; the factory can return a stateless resolver per kind.
-type routeResolverFactory func() RouteResolver
+type RouteResolverFactory func() RouteResolver
 
 var (
-	backendHandlerFactories = map[string]backendHandlerFactory{}
-	routeResolverFactories  = map[string]routeResolverFactory{}
+	backendHandlerFactories = map[string]BackendHandlerFactory{}
+	routeResolverFactories  = map[string]RouteResolverFactory{}
+
+	// enabledBackendKinds limits which kinds the MaaSModelRef reconciler serves; nil enables all registered kinds.
+	enabledBackendKinds map[string]bool
 )
 
 func init() {
-	// CRD enum is LLMInferenceService;ExternalModel (see api/maas/v1alpha1/maasmodelref_types.go). Register both.
-	backendHandlerFactories["LLMInferenceService"] = func(r *MaaSModelRefReconciler) BackendHandler { return &llmisvcHandler{r} }
-	backendHandlerFactories["llmisvc"] = func(r *MaaSModelRefReconciler) BackendHandler { return &llmisvcHandler{r} } // alias for backwards compatibility
-	backendHandlerFactories["ExternalModel"] = func(r *MaaSModelRefReconciler) BackendHandler { return &externalModelHandler{r} }
-
-	routeResolverFactories["LLMInferenceService"] = func() RouteResolver { return &llmisvcRouteResolver{} }
-	routeResolverFactories["llmisvc"] = func() RouteResolver { return &llmisvcRouteResolver{} }
-	routeResolverFactories["ExternalModel"] = func() RouteResolver { return &externalModelRouteResolver{} }
+	// CRD enum is LLMInferenceService;ExternalModel;MCPServer (see api/maas/v1alpha1/maasmodelref_types.go).
+	RegisterBackendKind("LLMInferenceService",
+		func(r *MaaSModelRefReconciler) BackendHandler { return &llmisvcHandler{r} },
+		func() RouteResolver { return &llmisvcRouteResolver{} })
+	RegisterBackendKind("llmisvc", // alias for backwards compatibility
+		func(r *MaaSModelRefReconciler) BackendHandler { return &llmisvcHandler{r} },
+		func() RouteResolver { return &llmisvcRouteResolver{} })
+	RegisterBackendKind("ExternalModel",
+		func(r *MaaSModelRefReconciler) BackendHandler { return &externalModelHandler{r} },
+		func() RouteResolver { return &externalModelRouteResolver{} })
+	RegisterBackendKind(MCPServerKind,
+		func(r *MaaSModelRefReconciler) BackendHandler { return &mcpServerHandler{r} },
+		func() RouteResolver { return &mcpServerRouteResolver{} })
+}
+
+// RegisterBackendKind registers the BackendHandler and RouteResolver for a spec.modelRef.kind, replacing any
+// earlier registration. Call it from an init function: the registry is not safe for use once controllers run.
+// A new kind must also be added to the MaaSModelRef CRD's spec.modelRef.kind enum.
+func RegisterBackendKind(kind string, handler BackendHandlerFactory, resolver RouteResolverFactory) {
+	backendHandlerFactories[kind] = handler
+	routeResolverFactories[kind] = resolver
+}
+
+// BackendKinds returns the registered kinds, sorted.
+func BackendKinds() []string {
+	return slices.Sorted(maps.Keys(backendHandlerFactories))
+}
+
+// SetEnabledBackendKinds limits the MaaSModelRef reconciler to the given kinds (the --backend-kinds flag).
+// MaaSModelRefs of other registered kinds are marked Failed with reason Unsupported. An empty list enables
+// every registered kind.
+func SetEnabledBackendKinds(kinds []string) error {
+	if len(kinds) == 0 {
+		enabledBackendKinds = nil
+		return nil
+	}
+	enabled := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		if backendHandlerFactories[kind] == nil {
+			return fmt.Errorf("unknown backend kind %q (registered: %s)", kind, strings.Join(BackendKinds(), ", "))
+		}
+		enabled[kind] = true
+	}
+	enabledBackendKinds = enabled
+	return nil
+}
+
+// BackendKindEnabled reports whether the MaaSModelRef reconciler serves the given registered kind.
+func BackendKindEnabled(kind string) bool {
+	return enabledBackendKinds == nil || enabledBackendKinds[kind]
 }
 
 // GetBackendHandler returns the BackendHandler for the given kind, or nil if unknown.
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -79,8 +80,8 @@ func (h *externalModelHandler) ReconcileRoute(ctx context.Context, log logr.Logg
 		return fmt.Errorf("failed to get HTTPRoute %s/%s: %w", routeNS, routeName, err)
 	}
 
-	expectedGatewayName := h.r.gatewayName()
-	expectedGatewayNamespace := h.r.gatewayNamespace()
+	expectedGatewayName := h.r.targetGatewayName(model)
+	expectedGatewayNamespace := h.r.targetGatewayNamespace(model)
 	gatewayFound := false
 	gatewayAccepted := false
 	var gatewayName string
@@ -167,7 +168,8 @@ func (h *externalModelHandler) ReconcileRoute(ctx context.Context, log logr.Logg
 }
 
 // Status returns the model endpoint URL and whether the model is ready.
-// ExternalModel is considered ready once the HTTPRoute is validated (no backend readiness probe).
+// ExternalModel is ready once the HTTPRoute is validated and, when the ExternalModel has a
+// spec.healthCheck, the last probe of the provider succeeded (see checkEndpoint).
 func (h *externalModelHandler) Status(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (endpoint string, ready bool, err error) {
 	if model.Status.HTTPRouteName == "" || model.Status.HTTPRouteGatewayName == "" {
 		return "", false, nil
@@ -178,19 +180,31 @@ func (h *externalModelHandler) Status(ctx context.Context, log logr.Logger, mode
 		return "", false, err
 	}
 
-	return endpoint, true, nil
+	// A missing ExternalModel is reported by ReconcileRoute; without it there is no check to run.
+	externalModel := &maasv1alpha1.ExternalModel{}
+	if err := h.r.Get(ctx, types.NamespacedName{Name: model.Spec.ModelRef.Name, Namespace: model.Namespace}, externalModel); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", false, fmt.Errorf("failed to get ExternalModel %s: %w", model.Spec.ModelRef.Name, err)
+		}
+	}
+	reachable, err := h.checkEndpoint(ctx, log, model, externalModel)
+	if err != nil {
+		return "", false, err
+	}
+
+	return endpoint, reachable, nil
 }
 
-// GetModelEndpoint returns the endpoint URL for the ExternalModel.
+// GetModelEndpoint returns the endpoint URL for the ExternalModel, with its path prefix.
 // Follows the same resolution order as llmisvc: HTTPRoute hostnames > gateway listeners > gateway addresses.
 func (h *externalModelHandler) GetModelEndpoint(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (string, error) {
 	if len(model.Status.HTTPRouteHostnames) > 0 {
 		hostname := model.Status.HTTPRouteHostnames[0]
-		return fmt.Sprintf("https://%s/%s", hostname, model.Name), nil
+		return externalmodel.ModelURL(model, hostname), nil
 	}
 
-	gatewayName := h.r.gatewayName()
-	gatewayNS := h.r.gatewayNamespace()
+	gatewayName := h.r.targetGatewayName(model)
+	gatewayNS := h.r.targetGatewayNamespace(model)
 	gateway := &gatewayapiv1.Gateway{}
 	key := client.ObjectKey{Name: gatewayName, Namespace: gatewayNS}
 	if err := h.r.Get(ctx, key, gateway); err != nil {
@@ -199,27 +213,43 @@ func (h *externalModelHandler) GetModelEndpoint(ctx context.Context, log logr.Lo
 
 	for _, listener := range gateway.Spec.Listeners {
 		if listener.Hostname != nil {
-			return fmt.Sprintf("https://%s/%s", string(*listener.Hostname), model.Name), nil
+			return externalmodel.ModelURL(model, string(*listener.Hostname)), nil
 		}
 	}
 
 	for _, addr := range gateway.Status.Addresses {
 		if addr.Type != nil && *addr.Type == gatewayapiv1.HostnameAddressType {
-			return fmt.Sprintf("https://%s/%s", addr.Value, model.Name), nil
+			return externalmodel.ModelURL(model, addr.Value), nil
 		}
 	}
 	if len(gateway.Status.Addresses) > 0 {
 		log.Info("Using IP-based gateway address; TLS hostname verification may fail",
 			"address", gateway.Status.Addresses[0].Value, "model", model.Name)
-		return fmt.Sprintf("https://%s/%s", gateway.Status.Addresses[0].Value, model.Name), nil
+		return externalmodel.ModelURL(model, gateway.Status.Addresses[0].Value), nil
 	}
 
 	return "", fmt.Errorf("unable to determine endpoint: gateway %s/%s has no hostname or addresses", gatewayNS, gatewayName)
 }
 
 // CleanupOnDelete is called when the MaaSModelRef is deleted.
-// ExternalModel: the ExternalModel reconciler handles cleanup of all resources via finalizer.
+// ExternalModel: resources in the model's namespace are garbage collected through their
+// OwnerReferences; the CA certificate copied into the gateway namespace cannot carry one, so it
+// is deleted here.
 func (h *externalModelHandler) CleanupOnDelete(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	h.r.endpointHealth.forget(types.NamespacedName{Namespace: model.Namespace, Name: model.Name})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalmodel.ModelCACertificateSecretName(model.Namespace, model.Name),
+			Namespace: h.r.targetGatewayNamespace(model),
+		},
+	}
+	if err := h.r.Delete(ctx, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete CA certificate Secret %s/%s: %w", secret.Namespace, secret.Name, err)
+	}
+	log.Info("Deleted CA certificate Secret", "name", secret.Name, "namespace", secret.Namespace)
 	return nil
 }
 
@@ -21,11 +21,15 @@ import (
 	"strings"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
 )
 
 func newExternalModel(name, ns, provider, endpoint string) *maasv1alpha1.MaaSModelRef {
@@ -286,6 +290,28 @@ func TestExternalModel_CleanupOnDelete(t *testing.T) {
 	}
 }
 
+func TestExternalModel_CleanupOnDelete_RemovesCACertificate(t *testing.T) {
+	model := newExternalModel("gpt-4o", "default", "openai", "api.openai.com")
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalmodel.ModelCACertificateSecretName("default", "gpt-4o"),
+			Namespace: defaultGatewayNamespace,
+		},
+	}
+
+	r, c := newTestReconciler(model, caSecret)
+	handler := &externalModelHandler{r: r}
+	log := zap.New(zap.UseDevMode(true))
+
+	if err := handler.CleanupOnDelete(context.Background(), log, model); err != nil {
+		t.Fatalf("CleanupOnDelete: unexpected error: %v", err)
+	}
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(caSecret), &corev1.Secret{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("CA certificate Secret should be deleted, got err = %v", err)
+	}
+}
+
 func TestExternalModel_CredentialRef(t *testing.T) {
 	externalModel := newExternalModelCR("gpt-4o", "default", "openai", "api.openai.com")
 	externalModel.Spec.CredentialRef = maasv1alpha1.CredentialReference{
@@ -18,16 +18,21 @@ package maas
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
 	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
 )
 
 // llmisvcHandler implements BackendHandler for kind "llmisvc" (LLMInferenceService).
@@ -36,30 +41,157 @@ type llmisvcHandler struct {
 }
 
 func (h *llmisvcHandler) ReconcileRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	if len(model.Spec.Backends) > 0 {
+		return h.reconcileWeightedRoute(ctx, log, model)
+	}
+	if err := h.deleteWeightedRoute(ctx, log, model); err != nil {
+		return err
+	}
 	return h.validateLLMISvcHTTPRoute(ctx, log, model)
 }
 
-// validateLLMISvcHTTPRoute ensures an HTTPRoute exists for the referenced LLMInferenceService (by labels),
-// populates MaaSModelRef status from the HTTPRoute and gateway ref.
-func (h *llmisvcHandler) validateLLMISvcHTTPRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
-	routeNS := model.Namespace
+// kserveRoute returns the HTTPRoute KServe created for an LLMInferenceService, found by its labels.
+func kserveRoute(ctx context.Context, c client.Reader, namespace, llmisvcName string) (*gatewayapiv1.HTTPRoute, error) {
 	routeList := &gatewayapiv1.HTTPRouteList{}
 	labelSelector := client.MatchingLabels{
-		"app.kubernetes.io/name":      model.Spec.ModelRef.Name,
+		"app.kubernetes.io/name":      llmisvcName,
 		"app.kubernetes.io/component": "llminferenceservice-router",
 		"app.kubernetes.io/part-of":   "llminferenceservice",
 	}
-	if err := h.r.List(ctx, routeList, client.InNamespace(routeNS), labelSelector); err != nil {
-		return fmt.Errorf("failed to list HTTPRoutes for LLMInferenceService %s: %w", model.Spec.ModelRef.Name, err)
+	if err := c.List(ctx, routeList, client.InNamespace(namespace), labelSelector); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes for LLMInferenceService %s: %w", llmisvcName, err)
 	}
 	if len(routeList.Items) == 0 {
-		log.V(1).Info("HTTPRoute not found for LLMInferenceService, will retry when created", "llmisvcName", model.Spec.ModelRef.Name, "namespace", routeNS)
-		return fmt.Errorf("%w: for LLMInferenceService %s in namespace %s", ErrHTTPRouteNotFound, model.Spec.ModelRef.Name, routeNS)
+		return nil, fmt.Errorf("%w: for LLMInferenceService %s in namespace %s", ErrHTTPRouteNotFound, llmisvcName, namespace)
+	}
+	return &routeList.Items[0], nil
+}
+
+// reconcileWeightedRoute generates the maas-model-<name> HTTPRoute splitting the model's traffic
+// between its backends. Each backend is reached through the first backendRef of its KServe route
+// (its workload Service or InferencePool), so the TLS and scheduling KServe sets up there apply.
+func (h *llmisvcHandler) reconcileWeightedRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	backendRefs := make([]gatewayapiv1.HTTPBackendRef, 0, len(model.Spec.Backends))
+	seen := map[string]bool{}
+	var total int64
+	for _, backend := range model.Spec.Backends {
+		if seen[backend.Name] {
+			return fmt.Errorf("backend LLMInferenceService %s is listed more than once", backend.Name)
+		}
+		seen[backend.Name] = true
+		total += int64(backend.Weight)
+
+		route, err := kserveRoute(ctx, h.r, model.Namespace, backend.Name)
+		if err != nil {
+			if errors.Is(err, ErrHTTPRouteNotFound) {
+				log.V(1).Info("HTTPRoute not found for backend LLMInferenceService, will retry when created", "llmisvcName", backend.Name)
+			}
+			return err
+		}
+		if len(route.Spec.Rules) == 0 || len(route.Spec.Rules[0].BackendRefs) == 0 {
+			return fmt.Errorf("HTTPRoute %s/%s of LLMInferenceService %s has no backendRefs", route.Namespace, route.Name, backend.Name)
+		}
+		ref := *route.Spec.Rules[0].BackendRefs[0].DeepCopy()
+		weight := backend.Weight
+		ref.Weight = &weight
+		backendRefs = append(backendRefs, ref)
+	}
+	if total == 0 {
+		return errors.New("every backend has weight 0; at least one must receive traffic")
+	}
+
+	route, err := h.r.applyModelRoute(ctx, log, model, h.desiredWeightedRoute(model, backendRefs))
+	if err != nil {
+		return err
+	}
+	h.r.recordModelRoute(log, model, route)
+	return nil
+}
+
+// desiredWeightedRoute builds the route: one rule matching the model's path prefix (default
+// /<model name>) and aliases, rewritten to / on the weighted backends, or one per endpoint of the
+// model's class, and restricted to its dedicated hostname when it has one.
+func (h *llmisvcHandler) desiredWeightedRoute(model *maasv1alpha1.MaaSModelRef, backendRefs []gatewayapiv1.HTTPBackendRef) *gatewayapiv1.HTTPRoute {
+	gwNamespace := gatewayapiv1.Namespace(h.r.targetGatewayNamespace(model))
+	pathType := gatewayapiv1.PathMatchPathPrefix
+	pathPrefix, hostname := externalmodel.ModelRoute(model)
+	replace := "/"
+
+	route := &gatewayapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalmodel.ModelRouteName(model.Name),
+			Namespace: model.Namespace,
+			Labels: map[string]string{
+				managedByLabel:                managedByValue,
+				"app.kubernetes.io/component": "weighted-model-route",
+				"maas.opendatahub.io/model":   model.Name,
+			},
+		},
+		Spec: gatewayapiv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+				ParentRefs: []gatewayapiv1.ParentReference{{
+					Name:      gatewayapiv1.ObjectName(h.r.targetGatewayName(model)),
+					Namespace: &gwNamespace,
+				}},
+			},
+			Rules: externalmodel.AliasMatches(externalmodel.ClassRules(gatewayapiv1.HTTPRouteRule{
+				Matches: []gatewayapiv1.HTTPRouteMatch{{
+					Path: &gatewayapiv1.HTTPPathMatch{Type: &pathType, Value: &pathPrefix},
+				}},
+				Filters: []gatewayapiv1.HTTPRouteFilter{{
+					Type: gatewayapiv1.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayapiv1.HTTPURLRewriteFilter{
+						Path: &gatewayapiv1.HTTPPathModifier{
+							Type:               gatewayapiv1.PrefixMatchHTTPPathModifier,
+							ReplacePrefixMatch: &replace,
+						},
+					},
+				}},
+				BackendRefs: backendRefs,
+			}, pathPrefix, model.Spec.Class), pathPrefix, model.Spec.Aliases),
+		},
+	}
+	if hostname != "" {
+		route.Spec.Hostnames = []gatewayapiv1.Hostname{gatewayapiv1.Hostname(hostname)}
+	}
+	return route
+}
+
+// deleteWeightedRoute deletes the route generated while the model had backends, so that once they
+// are removed the model is served by its KServe route alone.
+func (h *llmisvcHandler) deleteWeightedRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	route := &gatewayapiv1.HTTPRoute{}
+	key := client.ObjectKey{Name: externalmodel.ModelRouteName(model.Name), Namespace: model.Namespace}
+	if err := h.r.Get(ctx, key, route); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get HTTPRoute %s/%s: %w", key.Namespace, key.Name, err)
+	}
+	if !metav1.IsControlledBy(route, model) {
+		return nil
+	}
+	log.Info("Deleting HTTPRoute of removed backends", "routeName", route.Name)
+	if err := h.r.Delete(ctx, route); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete HTTPRoute %s/%s: %w", route.Namespace, route.Name, err)
+	}
+	return nil
+}
+
+// validateLLMISvcHTTPRoute ensures an HTTPRoute exists for the referenced LLMInferenceService (by labels),
+// populates MaaSModelRef status from the HTTPRoute and gateway ref.
+func (h *llmisvcHandler) validateLLMISvcHTTPRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	routeNS := model.Namespace
+	route, err := kserveRoute(ctx, h.r, routeNS, model.Spec.ModelRef.Name)
+	if err != nil {
+		if errors.Is(err, ErrHTTPRouteNotFound) {
+			log.V(1).Info("HTTPRoute not found for LLMInferenceService, will retry when created", "llmisvcName", model.Spec.ModelRef.Name, "namespace", routeNS)
+		}
+		return err
 	}
-	route := &routeList.Items[0]
 	routeName := route.Name
-	expectedGatewayName := h.r.gatewayName()
-	expectedGatewayNamespace := h.r.gatewayNamespace()
+	expectedGatewayName := h.r.targetGatewayName(model)
+	expectedGatewayNamespace := h.r.targetGatewayNamespace(model)
 	gatewayFound := false
 	var gatewayName string
 	var gatewayNamespace string
@@ -113,13 +245,11 @@ func (h *llmisvcHandler) Status(ctx context.Context, log logr.Logger, model *maa
 		}
 		return "", false, err
 	}
-	for _, c := range llmisvc.Status.Conditions {
-		if c.Type == "Ready" && c.Status == "True" {
-			ready = true
-			break
-		}
+	model.Status.Resources = resourcesFromLLMISvc(llmisvc)
+	if len(model.Spec.Backends) > 0 {
+		return h.weightedStatus(ctx, log, model)
 	}
-	if !ready {
+	if !llmisvcReady(llmisvc) {
 		return "", false, nil
 	}
 	endpoint = h.getEndpointFromLLMISvc(llmisvc)
@@ -132,16 +262,58 @@ func (h *llmisvcHandler) Status(ctx context.Context, log logr.Logger, model *maa
 	return endpoint, true, nil
 }
 
+// weightedStatus reports a model with backends ready once every backend receiving traffic is ready
+// and the gateway has accepted the generated route.
+func (h *llmisvcHandler) weightedStatus(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (string, bool, error) {
+	for _, backend := range model.Spec.Backends {
+		if backend.Weight == 0 {
+			continue
+		}
+		llmisvc := &kservev1alpha1.LLMInferenceService{}
+		if err := h.r.Get(ctx, client.ObjectKey{Name: backend.Name, Namespace: model.Namespace}, llmisvc); err != nil {
+			if apierrors.IsNotFound(err) {
+				return "", false, fmt.Errorf("backend LLMInferenceService %s not found in namespace %s", backend.Name, model.Namespace)
+			}
+			return "", false, err
+		}
+		if !llmisvcReady(llmisvc) {
+			log.V(1).Info("Backend LLMInferenceService not ready", "llmisvcName", backend.Name)
+			return "", false, nil
+		}
+	}
+	if model.Status.HTTPRouteName == "" || model.Status.HTTPRouteGatewayName == "" {
+		return "", false, nil
+	}
+	endpoint, err := h.GetModelEndpoint(ctx, log, model)
+	if err != nil {
+		return "", false, err
+	}
+	return endpoint, true, nil
+}
+
+func llmisvcReady(llmisvc *kservev1alpha1.LLMInferenceService) bool {
+	for _, c := range llmisvc.Status.Conditions {
+		if c.Type == "Ready" && c.Status == "True" {
+			return true
+		}
+	}
+	return false
+}
+
 // GetModelEndpoint returns the model endpoint URL using gateway/HTTPRoute hostname and path.
 // Used when LLMInferenceService status does not expose an endpoint. ExternalModel and other kinds
 // implement their own logic and need not use these path assumptions.
 func (h *llmisvcHandler) GetModelEndpoint(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (string, error) {
+	if len(model.Spec.Backends) > 0 {
+		// The generated route serves the model under its routing prefix, like an ExternalModel.
+		return (&externalModelHandler{h.r}).GetModelEndpoint(ctx, log, model)
+	}
 	if len(model.Status.HTTPRouteHostnames) > 0 {
 		hostname := model.Status.HTTPRouteHostnames[0]
 		return fmt.Sprintf("https://%s/%s", hostname, model.Name), nil
 	}
-	gatewayName := h.r.gatewayName()
-	gatewayNS := h.r.gatewayNamespace()
+	gatewayName := h.r.targetGatewayName(model)
+	gatewayNS := h.r.targetGatewayNamespace(model)
 	gateway := &gatewayapiv1.Gateway{}
 	key := client.ObjectKey{Name: gatewayName, Namespace: gatewayNS}
 	if err := h.r.Get(ctx, key, gateway); err != nil {
@@ -192,27 +364,82 @@ func (h *llmisvcHandler) getEndpointFromLLMISvc(llmisvc *kservev1alpha1.LLMInfer
 }
 
 func (h *llmisvcHandler) CleanupOnDelete(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
-	// llmisvc HTTPRoutes are owned by KServe; we do not delete them.
+	// llmisvc HTTPRoutes are owned by KServe; we do not delete them. The route generated for
+	// backends is garbage collected through its OwnerReference.
 	return nil
 }
 
 // llmisvcRouteResolver resolves the HTTPRoute for a MaaSModelRef that references an LLMInferenceService.
 type llmisvcRouteResolver struct{}
 
+// A model with backends resolves to its generated maas-model-<name> route.
 func (llmisvcRouteResolver) HTTPRouteForModel(ctx context.Context, c client.Reader, model *maasv1alpha1.MaaSModelRef) (routeName, routeNamespace string, err error) {
-	llmisvcNS := model.Namespace
-	routeList := &gatewayapiv1.HTTPRouteList{}
-	labelSelector := client.MatchingLabels{
-		"app.kubernetes.io/name":      model.Spec.ModelRef.Name,
-		"app.kubernetes.io/component": "llminferenceservice-router",
-		"app.kubernetes.io/part-of":   "llminferenceservice",
+	if len(model.Spec.Backends) > 0 {
+		return externalmodel.ModelRouteName(model.Name), model.Namespace, nil
 	}
-	if err := c.List(ctx, routeList, client.InNamespace(llmisvcNS), labelSelector); err != nil {
-		return "", "", fmt.Errorf("failed to list HTTPRoutes for LLMInferenceService %s: %w", model.Spec.ModelRef.Name, err)
+	route, err := kserveRoute(ctx, c, model.Namespace, model.Spec.ModelRef.Name)
+	if err != nil {
+		return "", "", err
 	}
-	if len(routeList.Items) == 0 {
-		return "", "", fmt.Errorf("%w: for LLMInferenceService %s in namespace %s", ErrHTTPRouteNotFound, model.Spec.ModelRef.Name, llmisvcNS)
-	}
-	route := &routeList.Items[0]
 	return route.Name, route.Namespace, nil
 }
+
+// gpuProductLabels are node labels that pin a pod to a specific accelerator model, in lookup order.
+var gpuProductLabels = []string{"nvidia.com/gpu.product", "amd.com/gpu.product", "cloud.google.com/gke-accelerator"}
+
+// resourcesFromLLMISvc summarizes the per-replica GPU and memory requests of the decode workload
+// (head pod plus one worker for multi-node). Returns nil when the spec carries no pod template,
+// e.g. when all resources come from LLMInferenceServiceConfig baseRefs.
+func resourcesFromLLMISvc(llmisvc *kservev1alpha1.LLMInferenceService) *maasv1alpha1.ModelResources {
+	pods := []*corev1.PodSpec{llmisvc.Spec.Template, llmisvc.Spec.Worker}
+	out := &maasv1alpha1.ModelResources{Replicas: 1}
+	if llmisvc.Spec.Replicas != nil {
+		out.Replicas = *llmisvc.Spec.Replicas
+	}
+	memory := resource.Quantity{}
+	found := false
+	for _, pod := range pods {
+		if pod == nil {
+			continue
+		}
+		found = true
+		for _, label := range gpuProductLabels {
+			if v := pod.NodeSelector[label]; v != "" && out.GPUType == "" {
+				out.GPUType = v
+			}
+		}
+		for _, c := range pod.Containers {
+			for name, q := range containerResources(c) {
+				switch {
+				case name == corev1.ResourceMemory:
+					memory.Add(q)
+				case strings.HasSuffix(string(name), "/gpu"):
+					out.GPUCount += q.Value()
+					if out.GPUType == "" {
+						out.GPUType = string(name)
+					}
+				}
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	if !memory.IsZero() {
+		out.Memory = memory.String()
+	}
+	return out
+}
+
+// containerResources returns the container's limits overlaid with its requests. Extended
+// resources such as GPUs may only be set as limits, while memory is usually requested.
+func containerResources(c corev1.Container) corev1.ResourceList {
+	merged := corev1.ResourceList{}
+	for name, q := range c.Resources.Limits {
+		merged[name] = q
+	}
+	for name, q := range c.Resources.Requests {
+		merged[name] = q
+	}
+	return merged
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestResourcesFromLLMISvc(t *testing.T) {
+	container := func(gpu, memory string) corev1.Container {
+		return corev1.Container{Name: "main", Resources: corev1.ResourceRequirements{
+			Limits:   corev1.ResourceList{"nvidia.com/gpu": resource.MustParse(gpu)},
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		}}
+	}
+
+	tests := []struct {
+		name string
+		spec kservev1alpha1.LLMInferenceServiceSpec
+		want *maasv1alpha1.ModelResources
+	}{
+		{
+			name: "no pod template",
+			spec: kservev1alpha1.LLMInferenceServiceSpec{},
+			want: nil,
+		},
+		{
+			name: "single node with pinned product",
+			spec: kservev1alpha1.LLMInferenceServiceSpec{WorkloadSpec: kservev1alpha1.WorkloadSpec{
+				Replicas: ptr.To[int32](3),
+				Template: &corev1.PodSpec{
+					NodeSelector: map[string]string{"nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"},
+					Containers:   []corev1.Container{container("2", "64Gi")},
+				},
+			}},
+			want: &maasv1alpha1.ModelResources{GPUType: "NVIDIA-A100-SXM4-80GB", GPUCount: 2, Memory: "64Gi", Replicas: 3},
+		},
+		{
+			name: "multi node sums head and worker",
+			spec: kservev1alpha1.LLMInferenceServiceSpec{WorkloadSpec: kservev1alpha1.WorkloadSpec{
+				Template: &corev1.PodSpec{Containers: []corev1.Container{container("1", "32Gi")}},
+				Worker:   &corev1.PodSpec{Containers: []corev1.Container{container("4", "32Gi")}},
+			}},
+			want: &maasv1alpha1.ModelResources{GPUType: "nvidia.com/gpu", GPUCount: 5, Memory: "64Gi", Replicas: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resourcesFromLLMISvc(&kservev1alpha1.LLMInferenceService{Spec: tt.spec})
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("resourcesFromLLMISvc() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("resourcesFromLLMISvc() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newWorkloadRoute returns the KServe route of an LLMInferenceService, forwarding to its workload Service.
+func newWorkloadRoute(llmisvcName, ns string) *gatewayapiv1.HTTPRoute {
+	route := newLLMISvcRoute(llmisvcName, ns)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{
+		BackendRefs: []gatewayapiv1.HTTPBackendRef{{
+			BackendRef: gatewayapiv1.BackendRef{
+				BackendObjectReference: gatewayapiv1.BackendObjectReference{
+					Name: gatewayapiv1.ObjectName(llmisvcName + "-kserve-workload-svc"),
+					Port: ptr.To(gatewayapiv1.PortNumber(8000)),
+				},
+			},
+		}},
+	}}
+	return route
+}
+
+func newWeightedModel(name, ns string, backends ...maasv1alpha1.WeightedBackend) *maasv1alpha1.MaaSModelRef {
+	model := newMaaSModelRef(name, ns, "LLMInferenceService", backends[0].Name)
+	model.Spec.Backends = backends
+	return model
+}
+
+func TestLLMISvc_ReconcileRoute_WeightedBackends(t *testing.T) {
+	model := newWeightedModel("llama3", "default",
+		maasv1alpha1.WeightedBackend{Name: "llama3-v1", Weight: 90},
+		maasv1alpha1.WeightedBackend{Name: "llama3-v2", Weight: 10})
+	r, c := newTestReconciler(model, newWorkloadRoute("llama3-v1", "default"), newWorkloadRoute("llama3-v2", "default"))
+	handler := &llmisvcHandler{r: r}
+
+	if err := handler.ReconcileRoute(context.Background(), zap.New(zap.UseDevMode(true)), model); err != nil {
+		t.Fatalf("ReconcileRoute: unexpected error: %v", err)
+	}
+
+	route := &gatewayapiv1.HTTPRoute{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "maas-model-llama3", Namespace: "default"}, route); err != nil {
+		t.Fatalf("Get HTTPRoute: %v", err)
+	}
+	if !metav1.IsControlledBy(route, model) {
+		t.Errorf("HTTPRoute owner references = %+v, want controlled by the MaaSModelRef", route.OwnerReferences)
+	}
+	rule := route.Spec.Rules[0]
+	if got := *rule.Matches[0].Path.Value; got != "/llama3" {
+		t.Errorf("path match = %q, want %q", got, "/llama3")
+	}
+	want := map[string]int32{"llama3-v1-kserve-workload-svc": 90, "llama3-v2-kserve-workload-svc": 10}
+	if len(rule.BackendRefs) != len(want) {
+		t.Fatalf("backendRefs = %+v, want %d", rule.BackendRefs, len(want))
+	}
+	for _, ref := range rule.BackendRefs {
+		if w, ok := want[string(ref.Name)]; !ok || ref.Weight == nil || *ref.Weight != w || *ref.Port != 8000 {
+			t.Errorf("backendRef %s:%v weight %v, want one of %v on port 8000", ref.Name, ref.Port, ref.Weight, want)
+		}
+	}
+	if model.Status.HTTPRouteName != route.Name {
+		t.Errorf("status route = %q, want %q", model.Status.HTTPRouteName, route.Name)
+	}
+
+	name, ns, err := llmisvcRouteResolver{}.HTTPRouteForModel(context.Background(), c, model)
+	if err != nil || name != "maas-model-llama3" || ns != "default" {
+		t.Errorf("HTTPRouteForModel() = %s/%s, %v, want default/maas-model-llama3", ns, name, err)
+	}
+}
+
+func TestLLMISvc_ReconcileRoute_InvalidBackends(t *testing.T) {
+	tests := []struct {
+		name     string
+		backends []maasv1alpha1.WeightedBackend
+	}{
+		{
+			name:     "all weights zero",
+			backends: []maasv1alpha1.WeightedBackend{{Name: "llama3-v1"}, {Name: "llama3-v2"}},
+		},
+		{
+			name:     "duplicate backend",
+			backends: []maasv1alpha1.WeightedBackend{{Name: "llama3-v1", Weight: 1}, {Name: "llama3-v1", Weight: 1}},
+		},
+		{
+			name:     "backend without KServe route",
+			backends: []maasv1alpha1.WeightedBackend{{Name: "llama3-v1", Weight: 1}, {Name: "llama3-v3", Weight: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newWeightedModel("llama3", "default", tt.backends...)
+			r, c := newTestReconciler(model, newWorkloadRoute("llama3-v1", "default"), newWorkloadRoute("llama3-v2", "default"))
+			handler := &llmisvcHandler{r: r}
+
+			if err := handler.ReconcileRoute(context.Background(), zap.New(zap.UseDevMode(true)), model); err == nil {
+				t.Fatal("ReconcileRoute: expected error, got nil")
+			}
+			err := c.Get(context.Background(), types.NamespacedName{Name: "maas-model-llama3", Namespace: "default"}, &gatewayapiv1.HTTPRoute{})
+			if !apierrors.IsNotFound(err) {
+				t.Errorf("Get HTTPRoute: err = %v, want NotFound", err)
+			}
+		})
+	}
+}
+
+func TestLLMISvc_ReconcileRoute_RemovingBackendsDeletesRoute(t *testing.T) {
+	model := newWeightedModel("llama3", "default",
+		maasv1alpha1.WeightedBackend{Name: "llama3-v1", Weight: 90},
+		maasv1alpha1.WeightedBackend{Name: "llama3-v2", Weight: 10})
+	r, c := newTestReconciler(model, newWorkloadRoute("llama3-v1", "default"), newWorkloadRoute("llama3-v2", "default"))
+	handler := &llmisvcHandler{r: r}
+	log := zap.New(zap.UseDevMode(true))
+
+	if err := handler.ReconcileRoute(context.Background(), log, model); err != nil {
+		t.Fatalf("ReconcileRoute with backends: unexpected error: %v", err)
+	}
+	model.Spec.Backends = nil
+	if err := handler.ReconcileRoute(context.Background(), log, model); err != nil {
+		t.Fatalf("ReconcileRoute without backends: unexpected error: %v", err)
+	}
+
+	err := c.Get(context.Background(), types.NamespacedName{Name: "maas-model-llama3", Namespace: "default"}, &gatewayapiv1.HTTPRoute{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Get generated HTTPRoute: err = %v, want NotFound", err)
+	}
+	if model.Status.HTTPRouteName != "llama3-v1-route" {
+		t.Errorf("status route = %q, want the KServe route llama3-v1-route", model.Status.HTTPRouteName)
+	}
+}
+
+func TestLLMISvc_Status_WeightedBackends(t *testing.T) {
+	accepted := func(model *maasv1alpha1.MaaSModelRef) {
+		model.Status.HTTPRouteName = "maas-model-llama3"
+		model.Status.HTTPRouteGatewayName = defaultGatewayName
+		model.Status.HTTPRouteGatewayNamespace = defaultGatewayNamespace
+		model.Status.HTTPRouteHostnames = []string{"maas.example.com"}
+	}
+	tests := []struct {
+		name      string
+		v2Weight  int32
+		v2Ready   corev1.ConditionStatus
+		accepted  bool
+		wantReady bool
+	}{
+		{name: "all backends ready", v2Weight: 10, v2Ready: corev1.ConditionTrue, accepted: true, wantReady: true},
+		{name: "canary not ready", v2Weight: 10, v2Ready: corev1.ConditionFalse, accepted: true, wantReady: false},
+		{name: "canary without traffic need not be ready", v2Weight: 0, v2Ready: corev1.ConditionFalse, accepted: true, wantReady: true},
+		{name: "route not accepted", v2Weight: 10, v2Ready: corev1.ConditionTrue, accepted: false, wantReady: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newWeightedModel("llama3", "default",
+				maasv1alpha1.WeightedBackend{Name: "llama3-v1", Weight: 90},
+				maasv1alpha1.WeightedBackend{Name: "llama3-v2", Weight: tt.v2Weight})
+			if tt.accepted {
+				accepted(model)
+			}
+			r, _ := newTestReconciler(model,
+				newLLMISvc("llama3-v1", "default", corev1.ConditionTrue),
+				newLLMISvc("llama3-v2", "default", tt.v2Ready))
+			handler := &llmisvcHandler{r: r}
+
+			endpoint, ready, err := handler.Status(context.Background(), zap.New(zap.UseDevMode(true)), model)
+			if err != nil {
+				t.Fatalf("Status: unexpected error: %v", err)
+			}
+			if ready != tt.wantReady {
+				t.Errorf("ready = %v, want %v", ready, tt.wantReady)
+			}
+			if ready && endpoint != "https://maas.example.com/llama3" {
+				t.Errorf("endpoint = %q, want %q", endpoint, "https://maas.example.com/llama3")
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+// MCPServerKind is the spec.modelRef.kind of a MaaSModelRef fronting a Model Context Protocol server.
+// spec.modelRef.name is a Service in the model's namespace serving MCP over streamable HTTP.
+const MCPServerKind = "MCPServer"
+
+// mcpServerHandler implements BackendHandler for kind "MCPServer". Unlike llmisvc and ExternalModel,
+// where another component creates the HTTPRoute, it owns the maas-model-<name> route: requests under
+// /<model name> are forwarded to the Service with that prefix stripped, so a server listening on
+// /mcp is reached at <endpoint>/mcp.
+type mcpServerHandler struct {
+	r *MaaSModelRefReconciler
+}
+
+// ReconcileRoute creates or updates the HTTPRoute for the MCP server and records it in the model status.
+func (h *mcpServerHandler) ReconcileRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	svc := &corev1.Service{}
+	if err := h.r.Get(ctx, types.NamespacedName{Name: model.Spec.ModelRef.Name, Namespace: model.Namespace}, svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("Service %s not found in namespace %s", model.Spec.ModelRef.Name, model.Namespace)
+		}
+		return fmt.Errorf("failed to get Service %s: %w", model.Spec.ModelRef.Name, err)
+	}
+	port, err := mcpServerPort(model, svc)
+	if err != nil {
+		return err
+	}
+
+	log.V(1).Info("Reconciling HTTPRoute for MCP server", "service", svc.Name, "port", port)
+	route, err := h.r.applyModelRoute(ctx, log, model, h.desiredRoute(model, svc.Name, port))
+	if err != nil {
+		return err
+	}
+	h.r.recordModelRoute(log, model, route)
+	return nil
+}
+
+// applyModelRoute creates the maas-model-<name> HTTPRoute of a model, or updates it to desired.
+// The model becomes its controller, so it is garbage collected with the model.
+func (r *MaaSModelRefReconciler) applyModelRoute(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef, desired *gatewayapiv1.HTTPRoute) (*gatewayapiv1.HTTPRoute, error) {
+	if err := controllerutil.SetControllerReference(model, desired, r.Scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner on HTTPRoute %s: %w", desired.Name, err)
+	}
+	naming, err := namingFor(ctx, r.Client, model.Namespace, model.Name)
+	if err != nil {
+		return nil, err
+	}
+	desired.Labels = naming.withLabels(desired.Labels)
+	route := &gatewayapiv1.HTTPRoute{}
+	err = r.Get(ctx, client.ObjectKeyFromObject(desired), route)
+	switch {
+	case apierrors.IsNotFound(err):
+		log.Info("Creating HTTPRoute for model", "routeName", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			return nil, fmt.Errorf("failed to create HTTPRoute %s/%s: %w", desired.Namespace, desired.Name, err)
+		}
+		return desired, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get HTTPRoute %s/%s: %w", desired.Namespace, desired.Name, err)
+	case !isOwnedOrAdoptable(route):
+		return nil, fmt.Errorf("HTTPRoute %s/%s exists and is not managed by maas-controller; annotate it with %s=true to adopt it",
+			route.Namespace, route.Name, AdoptAnnotation)
+	case !equality.Semantic.DeepEqual(route.Spec, desired.Spec) || !hasLabels(route.Labels, desired.Labels) || !metav1.IsControlledBy(route, model):
+		route.Spec = desired.Spec
+		if route.Labels == nil {
+			route.Labels = map[string]string{}
+		}
+		for k, v := range desired.Labels {
+			route.Labels[k] = v
+		}
+		route.OwnerReferences = desired.OwnerReferences
+		log.Info("Updating HTTPRoute for model", "routeName", route.Name)
+		if err := r.Update(ctx, route); err != nil {
+			return nil, fmt.Errorf("failed to update HTTPRoute %s/%s: %w", route.Namespace, route.Name, err)
+		}
+	}
+	return route, nil
+}
+
+// recordModelRoute records a route applied by applyModelRoute in the model status. The gateway
+// fields and hostnames stay unset until the gateway has accepted and programmed the route.
+func (r *MaaSModelRefReconciler) recordModelRoute(log logr.Logger, model *maasv1alpha1.MaaSModelRef, route *gatewayapiv1.HTTPRoute) {
+	model.Status.HTTPRouteName = route.Name
+	model.Status.HTTPRouteNamespace = route.Namespace
+	model.Status.HTTPRouteGatewayName = ""
+	model.Status.HTTPRouteGatewayNamespace = ""
+	model.Status.HTTPRouteHostnames = nil
+	if !routeAcceptedByGateway(route, r.targetGatewayName(model), r.targetGatewayNamespace(model)) {
+		log.Info("HTTPRoute not yet accepted and programmed by the gateway", "routeName", route.Name)
+		return
+	}
+	model.Status.HTTPRouteGatewayName = r.targetGatewayName(model)
+	model.Status.HTTPRouteGatewayNamespace = r.targetGatewayNamespace(model)
+	for _, hostname := range route.Spec.Hostnames {
+		model.Status.HTTPRouteHostnames = append(model.Status.HTTPRouteHostnames, string(hostname))
+	}
+}
+
+// desiredRoute builds the route: one rule matching the model's path prefix (default /<model name>)
+// and aliases, rewritten to / on the Service, and restricted to its dedicated hostname when it has one.
+// MCP's streamable HTTP transport keeps server-sent event streams open, so the request timeout is disabled.
+func (h *mcpServerHandler) desiredRoute(model *maasv1alpha1.MaaSModelRef, service string, port int32) *gatewayapiv1.HTTPRoute {
+	gwNamespace := gatewayapiv1.Namespace(h.r.targetGatewayNamespace(model))
+	pathType := gatewayapiv1.PathMatchPathPrefix
+	pathPrefix, hostname := externalmodel.ModelRoute(model)
+	replace := "/"
+	backendPort := gatewayapiv1.PortNumber(port)
+	noTimeout := gatewayapiv1.Duration("0s")
+
+	route := &gatewayapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      externalmodel.ModelRouteName(model.Name),
+			Namespace: model.Namespace,
+			Labels: map[string]string{
+				managedByLabel:                managedByValue,
+				"app.kubernetes.io/component": "mcp-server-route",
+				"maas.opendatahub.io/model":   model.Name,
+			},
+		},
+		Spec: gatewayapiv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+				ParentRefs: []gatewayapiv1.ParentReference{{
+					Name:      gatewayapiv1.ObjectName(h.r.targetGatewayName(model)),
+					Namespace: &gwNamespace,
+				}},
+			},
+			Rules: []gatewayapiv1.HTTPRouteRule{{
+				Matches: []gatewayapiv1.HTTPRouteMatch{{
+					Path: &gatewayapiv1.HTTPPathMatch{Type: &pathType, Value: &pathPrefix},
+				}},
+				Filters: []gatewayapiv1.HTTPRouteFilter{{
+					Type: gatewayapiv1.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayapiv1.HTTPURLRewriteFilter{
+						Path: &gatewayapiv1.HTTPPathModifier{
+							Type:               gatewayapiv1.PrefixMatchHTTPPathModifier,
+							ReplacePrefixMatch: &replace,
+						},
+					},
+				}},
+				BackendRefs: []gatewayapiv1.HTTPBackendRef{{
+					BackendRef: gatewayapiv1.BackendRef{
+						BackendObjectReference: gatewayapiv1.BackendObjectReference{
+							Name: gatewayapiv1.ObjectName(service),
+							Port: &backendPort,
+						},
+					},
+				}},
+				Timeouts: &gatewayapiv1.HTTPRouteTimeouts{Request: &noTimeout},
+			}},
+		},
+	}
+	route.Spec.Rules = externalmodel.AliasMatches(route.Spec.Rules, pathPrefix, model.Spec.Aliases)
+	if hostname != "" {
+		route.Spec.Hostnames = []gatewayapiv1.Hostname{gatewayapiv1.Hostname(hostname)}
+	}
+	return route
+}
+
+// mcpServerPort returns the Service port to route to: the maas.opendatahub.io/port annotation on the
+// model, the Service's only port, or its port named "mcp" or "http", in that order.
+func mcpServerPort(model *maasv1alpha1.MaaSModelRef, svc *corev1.Service) (int32, error) {
+	if portStr, ok := model.GetAnnotations()[externalmodel.AnnPort]; ok {
+		p, err := strconv.ParseInt(portStr, 10, 32)
+		if err != nil || p < 1 || p > 65535 {
+			return 0, fmt.Errorf("invalid port %q in annotation %s", portStr, externalmodel.AnnPort)
+		}
+		return int32(p), nil
+	}
+	if len(svc.Spec.Ports) == 1 {
+		return svc.Spec.Ports[0].Port, nil
+	}
+	for _, name := range []string{"mcp", "http"} {
+		for _, p := range svc.Spec.Ports {
+			if p.Name == name {
+				return p.Port, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("Service %s has %d ports and none is named mcp or http; set the %s annotation",
+		svc.Name, len(svc.Spec.Ports), externalmodel.AnnPort)
+}
+
+// routeAcceptedByGateway reports whether the given gateway has accepted and programmed the route.
+func routeAcceptedByGateway(route *gatewayapiv1.HTTPRoute, gatewayName, gatewayNamespace string) bool {
+	for _, parent := range route.Status.Parents {
+		ns := route.Namespace
+		if parent.ParentRef.Namespace != nil {
+			ns = string(*parent.ParentRef.Namespace)
+		}
+		if string(parent.ParentRef.Name) != gatewayName || ns != gatewayNamespace {
+			continue
+		}
+		accepted, programmed := false, false
+		for _, cond := range parent.Conditions {
+			if cond.Status != metav1.ConditionTrue {
+				continue
+			}
+			switch cond.Type {
+			case string(gatewayapiv1.RouteConditionAccepted):
+				accepted = true
+			case routeConditionProgrammed:
+				programmed = true
+			}
+		}
+		return accepted && programmed
+	}
+	return false
+}
+
+// Status returns the endpoint URL; the MCP server is ready once the gateway has accepted its route.
+func (h *mcpServerHandler) Status(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (endpoint string, ready bool, err error) {
+	if model.Status.HTTPRouteName == "" || model.Status.HTTPRouteGatewayName == "" {
+		return "", false, nil
+	}
+	endpoint, err = h.GetModelEndpoint(ctx, log, model)
+	if err != nil {
+		return "", false, err
+	}
+	return endpoint, true, nil
+}
+
+// GetModelEndpoint returns https://<host>/<model name>, resolved like ExternalModel's endpoint since
+// both route on the model name prefix.
+func (h *mcpServerHandler) GetModelEndpoint(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) (string, error) {
+	return (&externalModelHandler{h.r}).GetModelEndpoint(ctx, log, model)
+}
+
+// CleanupOnDelete is a no-op: the HTTPRoute is garbage collected through its OwnerReference.
+func (h *mcpServerHandler) CleanupOnDelete(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	return nil
+}
+
+// mcpServerRouteResolver returns the maas-model-<name> HTTPRoute created by mcpServerHandler.
+type mcpServerRouteResolver struct{}
+
+func (mcpServerRouteResolver) HTTPRouteForModel(ctx context.Context, c client.Reader, model *maasv1alpha1.MaaSModelRef) (routeName, routeNamespace string, err error) {
+	return externalmodel.ModelRouteName(model.Name), model.Namespace, nil
+}
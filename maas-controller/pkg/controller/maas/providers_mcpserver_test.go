@@ -0,0 +1,224 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+func newMCPServerModel(name, ns, service string) *maasv1alpha1.MaaSModelRef {
+	return &maasv1alpha1.MaaSModelRef{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec: maasv1alpha1.MaaSModelSpec{
+			ModelRef: maasv1alpha1.ModelReference{Kind: MCPServerKind, Name: service},
+		},
+	}
+}
+
+func newMCPService(name, ns string, ports ...corev1.ServicePort) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec:       corev1.ServiceSpec{Ports: ports},
+	}
+}
+
+func TestMCPServer_ReconcileRoute_CreatesRoute(t *testing.T) {
+	model := newMCPServerModel("github-tools", "default", "github-mcp")
+	svc := newMCPService("github-mcp", "default", corev1.ServicePort{Name: "metrics", Port: 9090}, corev1.ServicePort{Name: "mcp", Port: 8080})
+	r, c := newTestReconciler(model, svc)
+	handler := &mcpServerHandler{r: r}
+
+	if err := handler.ReconcileRoute(context.Background(), zap.New(zap.UseDevMode(true)), model); err != nil {
+		t.Fatalf("ReconcileRoute: unexpected error: %v", err)
+	}
+
+	route := &gatewayapiv1.HTTPRoute{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "maas-model-github-tools", Namespace: "default"}, route); err != nil {
+		t.Fatalf("Get HTTPRoute: %v", err)
+	}
+	if !metav1.IsControlledBy(route, model) {
+		t.Errorf("HTTPRoute owner references = %+v, want controlled by the MaaSModelRef", route.OwnerReferences)
+	}
+	if len(route.Spec.ParentRefs) != 1 || string(route.Spec.ParentRefs[0].Name) != defaultGatewayName {
+		t.Errorf("ParentRefs = %+v, want the default gateway", route.Spec.ParentRefs)
+	}
+	rule := route.Spec.Rules[0]
+	if got := *rule.Matches[0].Path.Value; got != "/github-tools" {
+		t.Errorf("path match = %q, want %q", got, "/github-tools")
+	}
+	backend := rule.BackendRefs[0].BackendObjectReference
+	if string(backend.Name) != "github-mcp" || *backend.Port != 8080 {
+		t.Errorf("backend = %s:%d, want github-mcp:8080", backend.Name, *backend.Port)
+	}
+	if model.Status.HTTPRouteName != route.Name || model.Status.HTTPRouteGatewayName != "" {
+		t.Errorf("status route = %q gateway = %q, want route set and gateway unset until accepted",
+			model.Status.HTTPRouteName, model.Status.HTTPRouteGatewayName)
+	}
+}
+
+func TestMCPServer_ReconcileRoute_CustomRouting(t *testing.T) {
+	model := newMCPServerModel("github-tools", "default", "github-mcp")
+	model.Spec.Routing = &maasv1alpha1.ModelRouting{Hostname: "tools.example.com"}
+	svc := newMCPService("github-mcp", "default", corev1.ServicePort{Name: "mcp", Port: 8080})
+	r, c := newTestReconciler(model, svc)
+	handler := &mcpServerHandler{r: r}
+
+	if err := handler.ReconcileRoute(context.Background(), zap.New(zap.UseDevMode(true)), model); err != nil {
+		t.Fatalf("ReconcileRoute: unexpected error: %v", err)
+	}
+
+	route := &gatewayapiv1.HTTPRoute{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "maas-model-github-tools", Namespace: "default"}, route); err != nil {
+		t.Fatalf("Get HTTPRoute: %v", err)
+	}
+	if len(route.Spec.Hostnames) != 1 || route.Spec.Hostnames[0] != "tools.example.com" {
+		t.Errorf("hostnames = %v, want [tools.example.com]", route.Spec.Hostnames)
+	}
+	if got := *route.Spec.Rules[0].Matches[0].Path.Value; got != "/" {
+		t.Errorf("path match = %q, want / on a dedicated hostname", got)
+	}
+}
+
+func TestMCPServer_ReconcileRoute_AcceptedRouteIsReady(t *testing.T) {
+	model := newMCPServerModel("github-tools", "default", "github-mcp")
+	model.Annotations = map[string]string{externalmodel.AnnPort: "3000"}
+	svc := newMCPService("github-mcp", "default", corev1.ServicePort{Name: "http", Port: 80})
+	route := newHTTPRouteWithGateway("maas-model-github-tools", "default", defaultGatewayName, defaultGatewayNamespace)
+	route.Labels = map[string]string{managedByLabel: managedByValue}
+	gwNS := gatewayapiv1.Namespace(defaultGatewayNamespace)
+	route.Status.Parents = []gatewayapiv1.RouteParentStatus{{
+		ParentRef: gatewayapiv1.ParentReference{Name: defaultGatewayName, Namespace: &gwNS},
+		Conditions: []metav1.Condition{
+			{Type: string(gatewayapiv1.RouteConditionAccepted), Status: metav1.ConditionTrue},
+			{Type: routeConditionProgrammed, Status: metav1.ConditionTrue},
+		},
+	}}
+	hostname := gatewayapiv1.Hostname("maas.example.com")
+	gateway := &gatewayapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultGatewayName, Namespace: defaultGatewayNamespace},
+		Spec: gatewayapiv1.GatewaySpec{
+			Listeners: []gatewayapiv1.Listener{{Name: "https", Hostname: &hostname, Port: 443, Protocol: gatewayapiv1.HTTPSProtocolType}},
+		},
+	}
+	r, c := newTestReconciler(model, svc, route, gateway)
+	handler := &mcpServerHandler{r: r}
+	log := zap.New(zap.UseDevMode(true))
+
+	if err := handler.ReconcileRoute(context.Background(), log, model); err != nil {
+		t.Fatalf("ReconcileRoute: unexpected error: %v", err)
+	}
+	endpoint, ready, err := handler.Status(context.Background(), log, model)
+	if err != nil {
+		t.Fatalf("Status: unexpected error: %v", err)
+	}
+	if !ready || endpoint != "https://maas.example.com/github-tools" {
+		t.Errorf("Status = (%q, %v), want (%q, true)", endpoint, ready, "https://maas.example.com/github-tools")
+	}
+
+	updated := &gatewayapiv1.HTTPRoute{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(route), updated); err != nil {
+		t.Fatalf("Get HTTPRoute: %v", err)
+	}
+	if port := *updated.Spec.Rules[0].BackendRefs[0].Port; port != 3000 {
+		t.Errorf("backend port = %d, want the annotated 3000", port)
+	}
+}
+
+func TestMCPServer_ReconcileRoute_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		objects []client.Object
+		wantErr string
+	}{
+		{
+			name:    "service missing",
+			wantErr: "Service github-mcp not found",
+		},
+		{
+			name:    "ambiguous port",
+			objects: []client.Object{newMCPService("github-mcp", "default", corev1.ServicePort{Name: "a", Port: 1}, corev1.ServicePort{Name: "b", Port: 2})},
+			wantErr: "none is named mcp or http",
+		},
+		{
+			name: "unmanaged route",
+			objects: []client.Object{
+				newMCPService("github-mcp", "default", corev1.ServicePort{Port: 8080}),
+				newHTTPRoute("maas-model-github-tools", "default"),
+			},
+			wantErr: "not managed by maas-controller",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newMCPServerModel("github-tools", "default", "github-mcp")
+			r, _ := newTestReconciler(append([]client.Object{model}, tt.objects...)...)
+			err := (&mcpServerHandler{r: r}).ReconcileRoute(context.Background(), zap.New(zap.UseDevMode(true)), model)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReconcileRoute error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaaSModelRefReconciler_DisabledBackendKind(t *testing.T) {
+	if err := SetEnabledBackendKinds([]string{"LLMInferenceService", "ExternalModel"}); err != nil {
+		t.Fatalf("SetEnabledBackendKinds: %v", err)
+	}
+	t.Cleanup(func() { _ = SetEnabledBackendKinds(nil) })
+
+	model := newMCPServerModel("github-tools", "default", "github-mcp")
+	r, c := newTestReconciler(model, newMCPService("github-mcp", "default", corev1.ServicePort{Port: 8080}))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "github-tools", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	updated := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if updated.Status.Phase != "Failed" {
+		t.Errorf("Phase = %q, want Failed", updated.Status.Phase)
+	}
+	assertReadyCondition(t, updated.Status.Conditions, metav1.ConditionFalse, "Unsupported")
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "maas-model-github-tools", Namespace: "default"}, &gatewayapiv1.HTTPRoute{}); err == nil {
+		t.Error("HTTPRoute created for a disabled kind")
+	}
+}
+
+func TestSetEnabledBackendKinds_UnknownKind(t *testing.T) {
+	t.Cleanup(func() { _ = SetEnabledBackendKinds(nil) })
+	if err := SetEnabledBackendKinds([]string{"ExternalModel", "Notebook"}); err == nil {
+		t.Error("SetEnabledBackendKinds accepted an unregistered kind")
+	}
+	if !BackendKindEnabled(MCPServerKind) {
+		t.Error("a rejected kind list must leave every kind enabled")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"fmt"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// QuotaExceededUntilAnnotation is set by maas-api on a MaaSSubscription when its users have
+// consumed spec.maxTokensPerDay, to the RFC 3339 time the daily window resets.
+const QuotaExceededUntilAnnotation = "maas.opendatahub.io/quota-exceeded-until"
+
+// ConditionQuotaExceeded reports whether spec.maxTokensPerDay has been reached in the current
+// UTC day. It is only set on subscriptions with a daily cap.
+const ConditionQuotaExceeded = "QuotaExceeded"
+
+// quotaExceededChanged returns true when maas-api sets or clears the quota annotation, which
+// does not bump the generation.
+func quotaExceededChanged(e event.UpdateEvent) bool {
+	return e.ObjectOld.GetAnnotations()[QuotaExceededUntilAnnotation] != e.ObjectNew.GetAnnotations()[QuotaExceededUntilAnnotation]
+}
+
+// reconcileQuotaExceeded sets the QuotaExceeded condition for now and returns how long until it
+// clears, or 0 when it is not set to True.
+func reconcileQuotaExceeded(subscription *maasv1alpha1.MaaSSubscription, now time.Time) time.Duration {
+	conditions := &subscription.Status.Conditions
+	if subscription.Spec.MaxTokensPerDay == nil {
+		apimeta.RemoveStatusCondition(conditions, ConditionQuotaExceeded)
+		return 0
+	}
+	condition := metav1.Condition{
+		Type:               ConditionQuotaExceeded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinQuota",
+		Message:            fmt.Sprintf("Users consume fewer than %d tokens per day", *subscription.Spec.MaxTokensPerDay),
+		ObservedGeneration: subscription.GetGeneration(),
+	}
+	var resetsIn time.Duration
+	if raw := subscription.GetAnnotations()[QuotaExceededUntilAnnotation]; raw != "" {
+		until, err := time.Parse(time.RFC3339, raw)
+		if err == nil && now.Before(until) {
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "DailyTokensExhausted"
+			condition.Message = fmt.Sprintf("The daily cap of %d tokens is reached; requests are denied until %s",
+				*subscription.Spec.MaxTokensPerDay, until.UTC().Format(time.RFC3339))
+			resetsIn = until.Sub(now)
+		}
+	}
+	apimeta.SetStatusCondition(conditions, condition)
+	return resetsIn
+}
+
+// soonest returns the shorter of two requeue delays, ignoring zero ones.
+func soonest(a, b time.Duration) time.Duration {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,64 @@
+package maas
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// Request headers the AuthPolicy injects with the caller's tier, the model and the caller's
+// remaining tokens. The gateway's quota-warning EnvoyFilter strips them before the request reaches
+// the model server and keeps their values in the quotaHeadersMetadata dynamic metadata.
+const (
+	quotaHeaderTier            = "X-MaaS-Response-Tier"
+	quotaHeaderModel           = "X-MaaS-Response-Model"
+	quotaHeaderRemainingTokens = "X-MaaS-Response-Remaining-Tokens"
+
+	// quotaHeadersMetadata is the dynamic metadata namespace holding the values for the response.
+	quotaHeadersMetadata = "maas.response"
+)
+
+// quotaResponseHeaders maps each response header the quota-headers filter sets to the key of its
+// value in quotaHeadersMetadata.
+var quotaResponseHeaders = []struct{ header, key string }{
+	{"X-MaaS-Tier", "tier"},
+	{"X-MaaS-Model", "model"},
+	{"X-MaaS-Remaining-Tokens", "remaining_tokens"},
+}
+
+// quotaHeadersFilterName is the name of the EnvoyFilter that adds the quota headers to a model's
+// responses. It lives in the gateway namespace, so it includes the model namespace.
+func quotaHeadersFilterName(model *maasv1alpha1.MaaSModelRef) string {
+	return "maas-quota-headers-" + model.Namespace + "-" + model.Name
+}
+
+// reconcileQuotaHeaders adds X-MaaS-Tier, X-MaaS-Model and X-MaaS-Remaining-Tokens to the responses
+// of the model's routes on the gateway through an EnvoyFilter, so callers see their quota
+// consumption without asking maas-api. The filter is deleted when quota headers are disabled.
+// Envoy leaves out a header whose metadata is missing, e.g. remaining tokens when maas-api does
+// not report them.
+func (r *MaaSModelRefReconciler) reconcileQuotaHeaders(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	if !r.QuotaHeaders {
+		return r.deleteModelRouteFilter(ctx, log, "quota headers", r.targetGatewayNamespace(model), quotaHeadersFilterName(model))
+	}
+
+	headers := make([]any, 0, len(quotaResponseHeaders))
+	for _, h := range quotaResponseHeaders {
+		headers = append(headers, map[string]any{
+			"header": map[string]any{
+				"key":   h.header,
+				"value": "%DYNAMIC_METADATA(" + quotaHeadersMetadata + ":" + h.key + ")%",
+			},
+			// The model server cannot set them for the caller.
+			"append_action": "OVERWRITE_IF_EXISTS_OR_ADD",
+		})
+	}
+	return r.reconcileModelRouteFilter(ctx, log, model, modelRouteFilter{
+		what:      "quota headers",
+		field:     "--quota-headers",
+		name:      quotaHeadersFilterName(model),
+		component: "model-quota-headers",
+	}, map[string]any{"response_headers_to_add": headers})
+}
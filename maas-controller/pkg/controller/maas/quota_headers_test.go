@@ -0,0 +1,63 @@
+package maas
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestMaaSModelRefReconciler_QuotaHeaders(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+	route := newLLMISvcRoute("llama", ns)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{}}
+	r, c := newTestReconciler(model, route, newLLMISvc("llama", ns, corev1.ConditionTrue))
+	r.QuotaHeaders = true
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	filter := &unstructured.Unstructured{}
+	filter.SetGroupVersionKind(envoyFilterGVK)
+	key := types.NamespacedName{Name: "maas-quota-headers-llm-m", Namespace: defaultGatewayNamespace}
+	if err := c.Get(ctx, key, filter); err != nil {
+		t.Fatalf("quota headers EnvoyFilter not created: %v", err)
+	}
+	patches, _, _ := unstructured.NestedSlice(filter.Object, "spec", "configPatches")
+	if len(patches) != 1 {
+		t.Fatalf("configPatches = %d, want one per route rule", len(patches))
+	}
+	headers, _, _ := unstructured.NestedSlice(patches[0].(map[string]any), "patch", "value", "response_headers_to_add")
+	got := map[string]string{}
+	for _, h := range headers {
+		name, _, _ := unstructured.NestedString(h.(map[string]any), "header", "key")
+		value, _, _ := unstructured.NestedString(h.(map[string]any), "header", "value")
+		got[name] = value
+	}
+	want := map[string]string{
+		"X-MaaS-Tier":             "%DYNAMIC_METADATA(maas.response:tier)%",
+		"X-MaaS-Model":            "%DYNAMIC_METADATA(maas.response:model)%",
+		"X-MaaS-Remaining-Tokens": "%DYNAMIC_METADATA(maas.response:remaining_tokens)%",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("response header %s = %q, want %q", name, got[name], value)
+		}
+	}
+
+	r.QuotaHeaders = false
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, key, filter); !apierrors.IsNotFound(err) {
+		t.Errorf("quota headers EnvoyFilter after disabling quota headers: err = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,610 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+
+// Rate limit backends, selected with --rate-limit-backend. maas-api's RATE_LIMIT_BACKEND must name
+// the same one.
+const (
+	// RateLimitBackendLimitador enforces subscription limits in Limitador through the Kuadrant
+	// TokenRateLimitPolicies and RateLimitPolicies generated for each model.
+	RateLimitBackendLimitador = "limitador"
+	// RateLimitBackendEnvoyRLS enforces them in an Envoy global rate limit service (such as
+	// envoyproxy/ratelimit): the gateway calls it from each model's routes, and its descriptors
+	// are written to a ConfigMap.
+	RateLimitBackendEnvoyRLS = "envoy-rls"
+	// RateLimitBackendRedis leaves enforcement to maas-api's ext_authz evaluator, which counts in
+	// Redis. Nothing is generated at the gateway.
+	RateLimitBackendRedis = "redis"
+)
+
+// RateLimitBackends lists the backends --rate-limit-backend accepts.
+var RateLimitBackends = []string{RateLimitBackendLimitador, RateLimitBackendEnvoyRLS, RateLimitBackendRedis}
+
+// ValidateRateLimitBackend checks that backend is one of RateLimitBackends. Empty means limitador.
+func ValidateRateLimitBackend(backend string) error {
+	if backend == "" || slices.Contains(RateLimitBackends, backend) {
+		return nil
+	}
+	return fmt.Errorf("unknown rate limit backend %q (expected one of %s)", backend, strings.Join(RateLimitBackends, ", "))
+}
+
+// EnvoyRLSConfig locates the Envoy global rate limit service of the envoy-rls backend.
+type EnvoyRLSConfig struct {
+	// Address is the host:port of the service's gRPC endpoint, a Service in the mesh,
+	// e.g. ratelimit.opendatahub.svc.cluster.local:8081.
+	Address string
+	// ConfigNamespace is the namespace of the ConfigMap the service loads its descriptors from.
+	ConfigNamespace string
+}
+
+// Validate checks that the address is host:port.
+func (c EnvoyRLSConfig) Validate() error {
+	host, port, err := net.SplitHostPort(c.Address)
+	if err != nil || host == "" || port == "" {
+		return fmt.Errorf("invalid rate limit service address %q: must be host:port", c.Address)
+	}
+	if c.ConfigNamespace == "" {
+		return errors.New("the rate limit service config namespace must be set")
+	}
+	return nil
+}
+
+const (
+	// rlsDomain is the rate limit domain of all MaaS descriptors.
+	rlsDomain = "maas"
+	// rlsFilterName is the name of the rate limit filter in the gateway's filter chain.
+	rlsFilterName = "maas.ratelimit"
+	// rlsGatewayFilterName is the EnvoyFilter inserting rlsFilterName into a gateway.
+	rlsGatewayFilterName = "maas-ratelimit"
+	// RLSConfigMapName is the ConfigMap holding the rate limit service's descriptors, under
+	// RLSConfigKey. Mount it as the service's runtime config directory.
+	RLSConfigMapName = "maas-ratelimit-config"
+	// RLSConfigKey is the file of the descriptors in RLSConfigMapName.
+	RLSConfigKey = "maas.yaml"
+
+	// Descriptor keys. subscription and user are read from the X-MaaS-Subscription-Key and
+	// X-MaaS-Username headers maas-api's ext_authz evaluator injects; limit names the window.
+	rlsSubscriptionKey = "subscription"
+	rlsLimitKey        = "limit"
+	rlsUserKey         = "user"
+
+	// rlsChargedTokensMetadata is where the gateway's quota filter (deployment/components/quota)
+	// leaves the tokens of a response not already charged when it was admitted.
+	rlsChargedTokensMetadata = "%DYNAMIC_METADATA(maas.usage:charged_tokens)%"
+)
+
+// rlsRouteFilterName is the name of the EnvoyFilter that attaches a model's routes to the rate
+// limit service. It lives in the gateway namespace, so it includes the model namespace.
+func rlsRouteFilterName(modelNamespace, modelName string) string {
+	return "maas-ratelimit-" + modelNamespace + "-" + modelName
+}
+
+// rateLimitBackend returns the configured backend, limitador when unset.
+func (r *MaaSSubscriptionReconciler) rateLimitBackend() string {
+	if r.RateLimitBackend == "" {
+		return RateLimitBackendLimitador
+	}
+	return r.RateLimitBackend
+}
+
+// reconcileRateLimitsForModel generates the rate limit wiring of a model for the configured
+// backend, and removes what the other backends would have generated so switching backends
+// leaves nothing behind.
+func (r *MaaSSubscriptionReconciler) reconcileRateLimitsForModel(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	switch r.rateLimitBackend() {
+	case RateLimitBackendEnvoyRLS:
+		if err := r.deleteKuadrantLimits(ctx, log, modelNamespace, modelName); err != nil {
+			return err
+		}
+		return r.reconcileRLSRoutesForModel(ctx, log, modelNamespace, modelName)
+	case RateLimitBackendRedis:
+		if err := r.deleteKuadrantLimits(ctx, log, modelNamespace, modelName); err != nil {
+			return err
+		}
+		return r.deleteRLSRouteFilter(ctx, log, modelNamespace, modelName)
+	default:
+		if err := r.reconcileTRLPForModel(ctx, log, modelNamespace, modelName); err != nil {
+			return err
+		}
+		if err := r.reconcileRLPForModel(ctx, log, modelNamespace, modelName); err != nil {
+			return err
+		}
+		return r.deleteRLSRouteFilter(ctx, log, modelNamespace, modelName)
+	}
+}
+
+// reconcileRateLimitService brings the configuration of the envoy-rls backend's rate limit
+// service up to date once the models of a subscription are reconciled. Other backends have none.
+func (r *MaaSSubscriptionReconciler) reconcileRateLimitService(ctx context.Context, log logr.Logger) error {
+	if r.rateLimitBackend() != RateLimitBackendEnvoyRLS {
+		return nil
+	}
+	return r.reconcileRLSConfig(ctx, log)
+}
+
+// deleteKuadrantLimits deletes the TokenRateLimitPolicy and RateLimitPolicy of a model.
+func (r *MaaSSubscriptionReconciler) deleteKuadrantLimits(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	if err := r.deleteModelTRLP(ctx, log, modelNamespace, modelName); err != nil {
+		return err
+	}
+	return r.deleteModelRLP(ctx, log, modelNamespace, modelName)
+}
+
+// rlsLimit is one limit of a subscription on a model, in rate limit service terms.
+type rlsLimit struct {
+	descriptor string // value of the limit descriptor, e.g. requests-1m
+	unit       string
+	multiplier int64
+	limit      int64
+}
+
+var rlsWindowPattern = regexp.MustCompile(`^(\d+)(s|m|h|d)$`)
+
+var rlsUnits = map[string]string{"s": "second", "m": "minute", "h": "hour", "d": "day"}
+
+// newRLSLimit converts a limit of kind (requests or tokens) per window.
+func newRLSLimit(kind string, limit int64, window string) (rlsLimit, bool) {
+	m := rlsWindowPattern.FindStringSubmatch(window)
+	if m == nil {
+		return rlsLimit{}, false
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil || n <= 0 {
+		return rlsLimit{}, false
+	}
+	return rlsLimit{descriptor: kind + "-" + window, unit: rlsUnits[m[2]], multiplier: n, limit: limit}, true
+}
+
+// effectiveRLSLimits returns the request and token limits of a subscription entry, replaced by
+// its organization's MaaSRateLimitOverride and with the default token limit of the
+// TokenRateLimitPolicy when it sets none.
+func effectiveRLSLimits(overrides map[string]*maasv1alpha1.MaaSRateLimitOverride, entry subscriptionModelEntry) []rlsLimit {
+	tokenLimits, requestLimits := entry.mRef.TokenRateLimits, entry.mRef.RequestRateLimits
+	if o := overrideFor(overrides, &entry.sub, entry.mRef); o != nil {
+		if len(o.Spec.TokenRateLimits) > 0 {
+			tokenLimits = o.Spec.TokenRateLimits
+		}
+		if len(o.Spec.RequestRateLimits) > 0 {
+			requestLimits = o.Spec.RequestRateLimits
+		}
+	}
+	if len(tokenLimits) == 0 {
+		tokenLimits = []maasv1alpha1.TokenRateLimit{{Limit: 100, Window: "1m"}}
+	}
+	var limits []rlsLimit
+	for _, l := range requestLimits {
+		if rl, ok := newRLSLimit("requests", l.Limit, l.Window); ok {
+			limits = append(limits, rl)
+		}
+	}
+	for _, l := range tokenLimits {
+		if rl, ok := newRLSLimit("tokens", l.Limit, l.Window); ok {
+			limits = append(limits, rl)
+		}
+	}
+	return limits
+}
+
+// rlsActions returns the rate_limits of a route for one limit descriptor. Token limits charge
+// one token when the request is admitted, which denies it once the window is used up, and the
+// rest of the response's tokens when the stream completes.
+func rlsActions(descriptor string) []any {
+	actions := []any{
+		map[string]any{"request_headers": map[string]any{"header_name": "x-maas-subscription-key", "descriptor_key": rlsSubscriptionKey}},
+		map[string]any{"generic_key": map[string]any{"descriptor_key": rlsLimitKey, "descriptor_value": descriptor}},
+		map[string]any{"request_headers": map[string]any{"header_name": "x-maas-username", "descriptor_key": rlsUserKey}},
+	}
+	rateLimits := []any{map[string]any{"actions": actions}}
+	if strings.HasPrefix(descriptor, "tokens-") {
+		rateLimits = append(rateLimits, map[string]any{
+			"actions":              actions,
+			"apply_on_stream_done": true,
+			"hits_addend":          map[string]any{"format": rlsChargedTokensMetadata},
+		})
+	}
+	return rateLimits
+}
+
+// reconcileRLSRoutesForModel attaches the routes of a model to the rate limit service through an
+// EnvoyFilter with one rate limit per limit window its subscriptions use. The filter is deleted
+// once no subscription covers the model.
+func (r *MaaSSubscriptionReconciler) reconcileRLSRoutesForModel(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	allSubs, err := subscriptionEntriesForModel(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	overrides, err := effectiveOverrides(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	if len(allSubs) == 0 {
+		return r.deleteRLSRouteFilter(ctx, log, modelNamespace, modelName)
+	}
+
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: modelNamespace, Name: modelName}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.deleteRLSRouteFilter(ctx, log, modelNamespace, modelName)
+		}
+		return fmt.Errorf("failed to get MaaSModelRef %s/%s: %w", modelNamespace, modelName, err)
+	}
+	routeName, routeNS, err := findHTTPRouteForModel(ctx, r.Client, modelNamespace, modelName)
+	if errors.Is(err, ErrModelNotFound) {
+		return r.deleteRLSRouteFilter(ctx, log, modelNamespace, modelName)
+	}
+	if errors.Is(err, ErrHTTPRouteNotFound) {
+		// The HTTPRoute watch reconciles again once the route exists.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve HTTPRoute for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	route, err := getHTTPRoute(ctx, r.Client, routeName, routeNS)
+	if err != nil {
+		return err
+	}
+
+	descriptors := map[string]bool{}
+	var subNames []string
+	for _, entry := range allSubs {
+		subNames = append(subNames, entry.sub.Name)
+		for _, l := range effectiveRLSLimits(overrides, entry) {
+			descriptors[l.descriptor] = true
+		}
+	}
+	var rateLimits []any
+	for _, d := range slices.Sorted(maps.Keys(descriptors)) {
+		rateLimits = append(rateLimits, rlsActions(d)...)
+	}
+	configPatches := make([]any, 0, len(route.Spec.Rules))
+	for i := range route.Spec.Rules {
+		configPatches = append(configPatches, map[string]any{
+			"applyTo": "HTTP_ROUTE",
+			"match": map[string]any{
+				"context": "GATEWAY",
+				"routeConfiguration": map[string]any{
+					"vhost": map[string]any{
+						"route": map[string]any{"name": fmt.Sprintf("%s.%s.%d", route.Namespace, route.Name, i)},
+					},
+				},
+			},
+			"patch": map[string]any{
+				"operation": "MERGE",
+				"value":     map[string]any{"route": map[string]any{"rate_limits": rateLimits}},
+			},
+		})
+	}
+	gatewayName, gatewayNamespace := modelGateway(model)
+	if err := r.ensureRLSGatewayFilter(ctx, log, gatewayName, gatewayNamespace); err != nil {
+		return err
+	}
+
+	naming, err := namingFor(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return err
+	}
+	sort.Strings(subNames)
+	return applyEnvoyFilter(ctx, r.Client, log, "rate limit", gatewayNamespace, rlsRouteFilterName(modelNamespace, modelName),
+		naming.withLabels(map[string]string{
+			managedByLabel:                        managedByValue,
+			"app.kubernetes.io/part-of":           "maas-subscription",
+			"app.kubernetes.io/component":         "rate-limit-routes",
+			"maas.opendatahub.io/model":           modelName,
+			"maas.opendatahub.io/model-namespace": modelNamespace,
+		}),
+		map[string]string{"maas.opendatahub.io/subscriptions": strings.Join(subNames, ",")},
+		map[string]any{
+			"workloadSelector": map[string]any{
+				"labels": map[string]any{"gateway.networking.k8s.io/gateway-name": gatewayName},
+			},
+			"configPatches": configPatches,
+		})
+}
+
+// ensureRLSGatewayFilter inserts the rate limit filter into a gateway, after maas-api's ext_authz
+// filter so the descriptors can read the headers it injects. Routes without rate_limits are not
+// limited, so the filter is left in place when no model uses it.
+func (r *MaaSSubscriptionReconciler) ensureRLSGatewayFilter(ctx context.Context, log logr.Logger, gatewayName, gatewayNamespace string) error {
+	host, port, _ := net.SplitHostPort(r.EnvoyRLS.Address) // checked by EnvoyRLSConfig.Validate
+	cluster := fmt.Sprintf("outbound|%s||%s", port, host)
+	spec := map[string]any{
+		"workloadSelector": map[string]any{
+			"labels": map[string]any{"gateway.networking.k8s.io/gateway-name": gatewayName},
+		},
+		"configPatches": []any{map[string]any{
+			"applyTo": "HTTP_FILTER",
+			"match": map[string]any{
+				"context": "GATEWAY",
+				"listener": map[string]any{
+					"filterChain": map[string]any{
+						"filter": map[string]any{
+							"name":      "envoy.filters.network.http_connection_manager",
+							"subFilter": map[string]any{"name": extAuthzFilterName},
+						},
+					},
+				},
+			},
+			"patch": map[string]any{
+				"operation": "INSERT_AFTER",
+				"value": map[string]any{
+					"name": rlsFilterName,
+					"typed_config": map[string]any{
+						"@type":             "type.googleapis.com/envoy.extensions.filters.http.ratelimit.v3.RateLimit",
+						"domain":            rlsDomain,
+						"failure_mode_deny": false,
+						"timeout":           "0.5s",
+						"rate_limit_service": map[string]any{
+							"transport_api_version": "V3",
+							"grpc_service": map[string]any{
+								"envoy_grpc": map[string]any{"cluster_name": cluster},
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+	return applyEnvoyFilter(ctx, r.Client, log, "rate limit", gatewayNamespace, rlsGatewayFilterName,
+		map[string]string{
+			managedByLabel:                managedByValue,
+			"app.kubernetes.io/part-of":   "maas-subscription",
+			"app.kubernetes.io/component": "rate-limit-service",
+		}, nil, spec)
+}
+
+// rlsDescriptor is an entry of the rate limit service's configuration.
+type rlsDescriptor struct {
+	Key         string          `json:"key"`
+	Value       string          `json:"value,omitempty"`
+	RateLimit   *rlsRate        `json:"rate_limit,omitempty"`
+	Descriptors []rlsDescriptor `json:"descriptors,omitempty"`
+}
+
+type rlsRate struct {
+	Unit            string `json:"unit"`
+	UnitMultiplier  int64  `json:"unit_multiplier,omitempty"`
+	RequestsPerUnit int64  `json:"requests_per_unit"`
+}
+
+type rlsConfig struct {
+	Domain      string          `json:"domain"`
+	Descriptors []rlsDescriptor `json:"descriptors"`
+}
+
+// buildRLSConfig returns the rate limit service's descriptors for the limits of each model-scoped
+// subscription key (namespace/name@modelNamespace/modelName). Each user of a subscription gets
+// their own counter, like the counters of the TokenRateLimitPolicy.
+func buildRLSConfig(limits map[string][]rlsLimit) ([]byte, error) {
+	config := rlsConfig{Domain: rlsDomain, Descriptors: []rlsDescriptor{}}
+	for _, key := range slices.Sorted(maps.Keys(limits)) {
+		sub := rlsDescriptor{Key: rlsSubscriptionKey, Value: key}
+		for _, l := range limits[key] {
+			rate := &rlsRate{Unit: l.unit, RequestsPerUnit: l.limit}
+			if l.multiplier > 1 {
+				rate.UnitMultiplier = l.multiplier
+			}
+			sub.Descriptors = append(sub.Descriptors, rlsDescriptor{
+				Key:         rlsLimitKey,
+				Value:       l.descriptor,
+				Descriptors: []rlsDescriptor{{Key: rlsUserKey, RateLimit: rate}},
+			})
+		}
+		config.Descriptors = append(config.Descriptors, sub)
+	}
+	return yaml.Marshal(config)
+}
+
+// reconcileRLSConfig rebuilds the rate limit service's descriptors from every subscription and
+// writes them to RLSConfigMapName. The service reloads its configuration on its own.
+func (r *MaaSSubscriptionReconciler) reconcileRLSConfig(ctx context.Context, log logr.Logger) error {
+	var subs maasv1alpha1.MaaSSubscriptionList
+	if err := r.List(ctx, &subs); err != nil {
+		return fmt.Errorf("failed to list MaaSSubscriptions: %w", err)
+	}
+	overrides, err := effectiveOverrides(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+	seen := map[types.NamespacedName]bool{}
+	limits := map[string][]rlsLimit{}
+	for i := range subs.Items {
+		for _, model := range r.modelsCoveredBy(ctx, &subs.Items[i]) {
+			if seen[model] {
+				continue
+			}
+			seen[model] = true
+			entries, err := subscriptionEntriesForModel(ctx, r.Client, model.Namespace, model.Name)
+			if err != nil {
+				return fmt.Errorf("failed to list subscriptions for model %s: %w", model, err)
+			}
+			for _, entry := range entries {
+				key := fmt.Sprintf("%s/%s@%s", entry.sub.Namespace, entry.sub.Name, model)
+				limits[key] = effectiveRLSLimits(overrides, entry)
+			}
+		}
+	}
+	data, err := buildRLSConfig(limits)
+	if err != nil {
+		return fmt.Errorf("failed to render rate limit service config: %w", err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: r.EnvoyRLS.ConfigNamespace, Name: RLSConfigMapName}, cm)
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      RLSConfigMapName,
+				Namespace: r.EnvoyRLS.ConfigNamespace,
+				Labels: map[string]string{
+					managedByLabel:                managedByValue,
+					"app.kubernetes.io/part-of":   "maas-subscription",
+					"app.kubernetes.io/component": "rate-limit-service",
+				},
+			},
+			Data: map[string]string{RLSConfigKey: string(data)},
+		}
+		if err := r.Create(ctx, cm); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %s/%s: %w", r.EnvoyRLS.ConfigNamespace, RLSConfigMapName, err)
+		}
+		log.Info("Rate limit service config created", "namespace", r.EnvoyRLS.ConfigNamespace, "subscriptionKeys", len(limits))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s/%s: %w", r.EnvoyRLS.ConfigNamespace, RLSConfigMapName, err)
+	}
+	if !isManaged(cm) {
+		log.Info("Rate limit service config opted out, skipping", "namespace", cm.Namespace)
+		return nil
+	}
+	if cm.Data[RLSConfigKey] == string(data) {
+		return nil
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[RLSConfigKey] = string(data)
+	if err := r.Update(ctx, cm); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	log.Info("Rate limit service config updated", "namespace", cm.Namespace, "subscriptionKeys", len(limits))
+	return nil
+}
+
+// deleteRLSRouteFilter deletes the model's rate limit EnvoyFilter, wherever its gateway lives.
+func (r *MaaSSubscriptionReconciler) deleteRLSRouteFilter(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	filterList := &unstructured.UnstructuredList{}
+	filterList.SetGroupVersionKind(envoyFilterGVK.GroupVersion().WithKind("EnvoyFilterList"))
+	if err := r.List(ctx, filterList, client.MatchingLabels{
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": modelNamespace,
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/component":         "rate-limit-routes",
+	}); err != nil {
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list rate limit EnvoyFilters for cleanup: %w", err)
+	}
+	for i := range filterList.Items {
+		f := &filterList.Items[i]
+		if !isManaged(f) {
+			log.Info("Rate limit EnvoyFilter opted out, skipping deletion", "name", f.GetName(), "namespace", f.GetNamespace())
+			continue
+		}
+		log.Info("Deleting rate limit EnvoyFilter", "name", f.GetName(), "namespace", f.GetNamespace(), "model", modelNamespace+"/"+modelName)
+		if err := r.Delete(ctx, f); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete rate limit EnvoyFilter %s/%s: %w", f.GetNamespace(), f.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// applyEnvoyFilter creates or updates a generated EnvoyFilter, leaving alone filters that are
+// opted out or were not created by maas-controller. what names the filter in logs and errors.
+func applyEnvoyFilter(ctx context.Context, c client.Client, log logr.Logger, what, namespace, name string, labels, annotations map[string]string, spec map[string]any) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(envoyFilterGVK)
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, existing)
+	if apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("the %s backend requires the Istio EnvoyFilter API, which is not installed: %w", RateLimitBackendEnvoyRLS, err)
+	}
+	if apierrors.IsNotFound(err) {
+		filter := &unstructured.Unstructured{}
+		filter.SetGroupVersionKind(envoyFilterGVK)
+		filter.SetName(name)
+		filter.SetNamespace(namespace)
+		filter.SetLabels(labels)
+		if len(annotations) > 0 {
+			filter.SetAnnotations(annotations)
+		}
+		filter.Object["spec"] = spec
+		if err := c.Create(ctx, filter); err != nil {
+			return fmt.Errorf("failed to create %s EnvoyFilter %s/%s: %w", what, namespace, name, err)
+		}
+		log.Info("EnvoyFilter created", "kind", what, "name", name, "namespace", namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s EnvoyFilter %s/%s: %w", what, namespace, name, err)
+	}
+	if !isManaged(existing) {
+		log.Info("EnvoyFilter opted out, skipping", "kind", what, "name", name)
+		return nil
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info("EnvoyFilter exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it",
+			"name", name, "namespace", namespace)
+		return nil
+	}
+
+	snapshot := existing.DeepCopy()
+	mergedLabels := existing.GetLabels()
+	if mergedLabels == nil {
+		mergedLabels = make(map[string]string)
+	}
+	for k, v := range labels {
+		mergedLabels[k] = v
+	}
+	existing.SetLabels(mergedLabels)
+	if len(annotations) > 0 {
+		mergedAnnotations := existing.GetAnnotations()
+		if mergedAnnotations == nil {
+			mergedAnnotations = make(map[string]string)
+		}
+		for k, v := range annotations {
+			mergedAnnotations[k] = v
+		}
+		existing.SetAnnotations(mergedAnnotations)
+	}
+	existing.Object["spec"] = spec
+	if equality.Semantic.DeepEqual(snapshot.Object, existing.Object) {
+		return nil
+	}
+	if err := c.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update %s EnvoyFilter %s/%s: %w", what, namespace, name, err)
+	}
+	log.Info("EnvoyFilter updated", "kind", what, "name", name, "namespace", namespace)
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestValidateRateLimitBackend(t *testing.T) {
+	for _, backend := range []string{"", RateLimitBackendLimitador, RateLimitBackendEnvoyRLS, RateLimitBackendRedis} {
+		if err := ValidateRateLimitBackend(backend); err != nil {
+			t.Errorf("ValidateRateLimitBackend(%q) = %v, want nil", backend, err)
+		}
+	}
+	if err := ValidateRateLimitBackend("memcached"); err == nil {
+		t.Error("ValidateRateLimitBackend(memcached) = nil, want error")
+	}
+}
+
+func TestMaaSSubscriptionReconciler_RateLimitBackends(t *testing.T) {
+	const (
+		modelName = "llm"
+		namespace = "default"
+	)
+	ctx := context.Background()
+
+	newReconciler := func(backend string) (*MaaSSubscriptionReconciler, client.Client) {
+		model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+		route := newHTTPRoute("maas-model-"+modelName, namespace)
+		sub := newMaaSSubscription("gold", namespace, "gold-users", modelName, 1000)
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithRESTMapper(testRESTMapper()).
+			WithObjects(model, route, sub).
+			WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+			WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+			Build()
+		return &MaaSSubscriptionReconciler{
+			Client:           c,
+			Scheme:           scheme,
+			RateLimitBackend: backend,
+			EnvoyRLS:         EnvoyRLSConfig{Address: "maas-ratelimit.maas-api.svc.cluster.local:8081", ConfigNamespace: "maas-api"},
+		}, c
+	}
+	reconcile := func(r *MaaSSubscriptionReconciler) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "gold", Namespace: namespace}}); err != nil {
+			t.Fatalf("Reconcile: %v", err)
+		}
+	}
+	getFilter := func(c client.Client, name string) error {
+		filter := &unstructured.Unstructured{}
+		filter.SetGroupVersionKind(envoyFilterGVK)
+		return c.Get(ctx, types.NamespacedName{Name: name, Namespace: defaultGatewayNamespace}, filter)
+	}
+	getTRLP := func(c client.Client) error {
+		trlp := &unstructured.Unstructured{}
+		trlp.SetGroupVersionKind(tokenRateLimitPolicyGVK)
+		return c.Get(ctx, types.NamespacedName{Name: "maas-trlp-" + modelName, Namespace: namespace}, trlp)
+	}
+
+	t.Run("envoy-rls", func(t *testing.T) {
+		r, c := newReconciler(RateLimitBackendEnvoyRLS)
+		reconcile(r)
+
+		if err := getFilter(c, rlsRouteFilterName(namespace, modelName)); err != nil {
+			t.Errorf("route EnvoyFilter not created: %v", err)
+		}
+		if err := getFilter(c, rlsGatewayFilterName); err != nil {
+			t.Errorf("gateway EnvoyFilter not created: %v", err)
+		}
+		if err := getTRLP(c); !apierrors.IsNotFound(err) {
+			t.Errorf("TokenRateLimitPolicy should not exist, got err = %v", err)
+		}
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Name: RLSConfigMapName, Namespace: "maas-api"}, cm); err != nil {
+			t.Fatalf("rate limit service ConfigMap not created: %v", err)
+		}
+		config := cm.Data[RLSConfigKey]
+		for _, want := range []string{"domain: maas", "value: default/gold@default/llm", "requests_per_unit: 1000"} {
+			if !strings.Contains(config, want) {
+				t.Errorf("config missing %q:\n%s", want, config)
+			}
+		}
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		r, c := newReconciler(RateLimitBackendRedis)
+		reconcile(r)
+
+		if err := getTRLP(c); !apierrors.IsNotFound(err) {
+			t.Errorf("TokenRateLimitPolicy should not exist, got err = %v", err)
+		}
+		if err := getFilter(c, rlsRouteFilterName(namespace, modelName)); !apierrors.IsNotFound(err) {
+			t.Errorf("route EnvoyFilter should not exist, got err = %v", err)
+		}
+	})
+
+	t.Run("switch back to limitador", func(t *testing.T) {
+		r, c := newReconciler(RateLimitBackendEnvoyRLS)
+		reconcile(r)
+		r.RateLimitBackend = RateLimitBackendLimitador
+		reconcile(r)
+
+		if err := getTRLP(c); err != nil {
+			t.Errorf("TokenRateLimitPolicy not created: %v", err)
+		}
+		if err := getFilter(c, rlsRouteFilterName(namespace, modelName)); !apierrors.IsNotFound(err) {
+			t.Errorf("route EnvoyFilter should be deleted, got err = %v", err)
+		}
+	})
+}
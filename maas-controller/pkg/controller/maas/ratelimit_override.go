@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasratelimitoverrides,verbs=get;list;watch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=maasratelimitoverrides/status,verbs=get;update;patch
+
+// rateLimitOverridesAnnotation lists the MaaSRateLimitOverrides a generated TokenRateLimitPolicy applies.
+const rateLimitOverridesAnnotation = "maas.opendatahub.io/rate-limit-overrides"
+
+// Phases of a MaaSRateLimitOverride.
+const (
+	OverridePhaseActive     = "Active"
+	OverridePhaseUnmatched  = "Unmatched"
+	OverridePhaseSuperseded = "Superseded"
+)
+
+// overrideKey identifies the organization and model an override targets within its namespace.
+func overrideKey(namespace, organizationID, modelNamespace, modelName string) string {
+	return namespace + "/" + organizationID + "@" + modelNamespace + "/" + modelName
+}
+
+// overrideWins reports whether a takes precedence over b when both target the same organization
+// and model: the oldest wins, then the first by name. maas-api applies the same rule.
+func overrideWins(a, b *maasv1alpha1.MaaSRateLimitOverride) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
+// effectiveOverrides returns the MaaSRateLimitOverrides in effect, by overrideKey. A missing CRD
+// means there are none.
+func effectiveOverrides(ctx context.Context, c client.Reader) (map[string]*maasv1alpha1.MaaSRateLimitOverride, error) {
+	var list maasv1alpha1.MaaSRateLimitOverrideList
+	if err := c.List(ctx, &list); err != nil {
+		if apimeta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list MaaSRateLimitOverrides: %w", err)
+	}
+	effective := make(map[string]*maasv1alpha1.MaaSRateLimitOverride, len(list.Items))
+	for i := range list.Items {
+		o := &list.Items[i]
+		if !o.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		key := overrideKey(o.Namespace, o.Spec.OrganizationID, o.Spec.Model.Namespace, o.Spec.Model.Name)
+		if current, ok := effective[key]; ok && !overrideWins(o, current) {
+			continue
+		}
+		effective[key] = o
+	}
+	return effective, nil
+}
+
+// overrideFor returns the override in effect for a subscription's modelRefs entry, or nil.
+func overrideFor(overrides map[string]*maasv1alpha1.MaaSRateLimitOverride, sub *maasv1alpha1.MaaSSubscription, mRef maasv1alpha1.ModelSubscriptionRef) *maasv1alpha1.MaaSRateLimitOverride {
+	if sub.Spec.TokenMetadata == nil || sub.Spec.TokenMetadata.OrganizationID == "" {
+		return nil
+	}
+	return overrides[overrideKey(sub.Namespace, sub.Spec.TokenMetadata.OrganizationID, mRef.Namespace, mRef.Name)]
+}
+
+// mapOverrideToMaaSSubscriptions returns reconcile requests for the MaaSSubscriptions an override
+// targets, so their TokenRateLimitPolicies pick it up when it is created, changed or deleted.
+func (r *MaaSSubscriptionReconciler) mapOverrideToMaaSSubscriptions(ctx context.Context, obj client.Object) []reconcile.Request {
+	o, ok := obj.(*maasv1alpha1.MaaSRateLimitOverride)
+	if !ok {
+		return nil
+	}
+	subs, err := findAllSubscriptionsForModel(ctx, r.Client, o.Spec.Model.Namespace, o.Spec.Model.Name)
+	if err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, s := range subscriptionsOfOrganization(subs, o.Namespace, o.Spec.OrganizationID) {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: s.Name, Namespace: s.Namespace}})
+	}
+	return requests
+}
+
+// subscriptionsOfOrganization keeps the subscriptions in namespace with the organization ID.
+func subscriptionsOfOrganization(subs []maasv1alpha1.MaaSSubscription, namespace, organizationID string) []maasv1alpha1.MaaSSubscription {
+	var out []maasv1alpha1.MaaSSubscription
+	for _, s := range subs {
+		if s.Namespace == namespace && s.Spec.TokenMetadata != nil && s.Spec.TokenMetadata.OrganizationID == organizationID {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// MaaSRateLimitOverrideReconciler reports which subscriptions each MaaSRateLimitOverride applies
+// to. MaaSSubscriptionReconciler applies the overrides to the TokenRateLimitPolicies.
+type MaaSRateLimitOverrideReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// Reconcile updates the override's status.
+func (r *MaaSRateLimitOverrideReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("MaaSRateLimitOverride", req.NamespacedName)
+
+	o := &maasv1alpha1.MaaSRateLimitOverride{}
+	if err := r.Get(ctx, req.NamespacedName, o); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !o.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+	snapshot := o.Status.DeepCopy()
+
+	overrides, err := effectiveOverrides(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	subs, err := findAllSubscriptionsForModel(ctx, r.Client, o.Spec.Model.Namespace, o.Spec.Model.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	var names []string
+	for _, s := range subscriptionsOfOrganization(subs, o.Namespace, o.Spec.OrganizationID) {
+		names = append(names, s.Namespace+"/"+s.Name)
+	}
+	sort.Strings(names)
+
+	model := o.Spec.Model.Namespace + "/" + o.Spec.Model.Name
+	condition := metav1.Condition{Type: "Ready", ObservedGeneration: o.GetGeneration()}
+	winner := overrides[overrideKey(o.Namespace, o.Spec.OrganizationID, o.Spec.Model.Namespace, o.Spec.Model.Name)]
+	switch {
+	case winner != nil && winner.Name != o.Name:
+		o.Status.Phase, o.Status.Subscriptions = OverridePhaseSuperseded, nil
+		condition.Status, condition.Reason = metav1.ConditionFalse, "Superseded"
+		condition.Message = fmt.Sprintf("MaaSRateLimitOverride %s is older and targets the same organization and model", winner.Name)
+	case len(names) == 0:
+		o.Status.Phase, o.Status.Subscriptions = OverridePhaseUnmatched, nil
+		condition.Status, condition.Reason = metav1.ConditionFalse, "NoSubscriptions"
+		condition.Message = fmt.Sprintf("No MaaSSubscription in namespace %s with organization %q includes model %s", o.Namespace, o.Spec.OrganizationID, model)
+	default:
+		o.Status.Phase, o.Status.Subscriptions = OverridePhaseActive, names
+		condition.Status, condition.Reason = metav1.ConditionTrue, "Applied"
+		condition.Message = fmt.Sprintf("Replaces the limits of %s on model %s", strings.Join(names, ", "), model)
+	}
+	apimeta.SetStatusCondition(&o.Status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(*snapshot, o.Status) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Status().Update(ctx, o); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "failed to update MaaSRateLimitOverride status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// mapToOverridesInNamespace returns reconcile requests for every override in the object's
+// namespace: subscription changes can match or unmatch them, and an override's creation or
+// deletion can supersede its peers.
+func (r *MaaSRateLimitOverrideReconciler) mapToOverridesInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list maasv1alpha1.MaaSRateLimitOverrideList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, o := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: o.Name, Namespace: o.Namespace}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MaaSRateLimitOverrideReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&maasv1alpha1.MaaSRateLimitOverride{}).
+		Watches(&maasv1alpha1.MaaSRateLimitOverride{}, handler.EnqueueRequestsFromMapFunc(r.mapToOverridesInNamespace)).
+		Watches(&maasv1alpha1.MaaSSubscription{}, handler.EnqueueRequestsFromMapFunc(r.mapToOverridesInNamespace)).
+		Complete(tracing.Reconciler("maasratelimitoverride", r))
+}
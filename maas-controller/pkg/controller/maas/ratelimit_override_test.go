@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func newRateLimitOverride(name, ns, org, modelNS, modelName string, limit int64, created time.Time) *maasv1alpha1.MaaSRateLimitOverride {
+	return &maasv1alpha1.MaaSRateLimitOverride{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, CreationTimestamp: metav1.NewTime(created)},
+		Spec: maasv1alpha1.MaaSRateLimitOverrideSpec{
+			OrganizationID:  org,
+			Model:           maasv1alpha1.ModelRef{Name: modelName, Namespace: modelNS},
+			TokenRateLimits: []maasv1alpha1.TokenRateLimit{{Limit: limit, Window: "1m"}},
+		},
+	}
+}
+
+func TestRateLimitOverrides(t *testing.T) {
+	const (
+		modelName      = "granite"
+		modelNamespace = "llm"
+		subNS          = "opendatahub"
+		trlpName       = "maas-trlp-" + modelName
+	)
+	ctx := context.Background()
+	now := time.Now()
+
+	model := newMaaSModelRef(modelName, modelNamespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, modelNamespace)
+	acme := newMaaSSubscription("acme-gold", subNS, "acme", modelName, 1000)
+	acme.Spec.ModelRefs[0].Namespace = modelNamespace
+	acme.Spec.TokenMetadata = &maasv1alpha1.TokenMetadata{OrganizationID: "acme"}
+	other := newMaaSSubscription("globex-gold", subNS, "globex", modelName, 1000)
+	other.Spec.ModelRefs[0].Namespace = modelNamespace
+	other.Spec.TokenMetadata = &maasv1alpha1.TokenMetadata{OrganizationID: "globex"}
+
+	active := newRateLimitOverride("acme-granite", subNS, "acme", modelNamespace, modelName, 50000, now.Add(-time.Hour))
+	superseded := newRateLimitOverride("acme-granite-2", subNS, "acme", modelNamespace, modelName, 70000, now)
+	unmatched := newRateLimitOverride("initech-granite", subNS, "initech", modelNamespace, modelName, 20000, now)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, acme, other, active, superseded, unmatched).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}, &maasv1alpha1.MaaSRateLimitOverride{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+
+	subReconciler := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+	if _, err := subReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme-gold", Namespace: subNS}}); err != nil {
+		t.Fatalf("Reconcile acme-gold: %v", err)
+	}
+
+	trlp := &unstructured.Unstructured{}
+	trlp.SetGroupVersionKind(schema.GroupVersionKind{Group: "kuadrant.io", Version: "v1alpha1", Kind: "TokenRateLimitPolicy"})
+	if err := c.Get(ctx, types.NamespacedName{Name: trlpName, Namespace: modelNamespace}, trlp); err != nil {
+		t.Fatalf("Get TokenRateLimitPolicy: %v", err)
+	}
+	rateLimit := func(sub string) int64 {
+		t.Helper()
+		rates, _, _ := unstructured.NestedSlice(trlp.Object, "spec", "limits", fmt.Sprintf("%s-%s-%s-tokens", subNS, sub, modelName), "rates")
+		if len(rates) != 1 {
+			t.Fatalf("TRLP rates for %s = %v, want one rate", sub, rates)
+		}
+		limit, _, _ := unstructured.NestedInt64(rates[0].(map[string]interface{}), "limit")
+		return limit
+	}
+	if got := rateLimit("acme-gold"); got != 50000 {
+		t.Errorf("acme-gold limit = %d, want the oldest override's 50000", got)
+	}
+	if got := rateLimit("globex-gold"); got != 1000 {
+		t.Errorf("globex-gold limit = %d, want the tier's 1000", got)
+	}
+	if got := trlp.GetAnnotations()[rateLimitOverridesAnnotation]; got != "acme-granite" {
+		t.Errorf("annotation %s = %q, want acme-granite", rateLimitOverridesAnnotation, got)
+	}
+
+	overrideReconciler := &MaaSRateLimitOverrideReconciler{Client: c, Scheme: scheme}
+	for name, want := range map[string]struct {
+		phase         string
+		subscriptions []string
+	}{
+		"acme-granite":    {OverridePhaseActive, []string{subNS + "/acme-gold"}},
+		"acme-granite-2":  {OverridePhaseSuperseded, nil},
+		"initech-granite": {OverridePhaseUnmatched, nil},
+	} {
+		key := types.NamespacedName{Name: name, Namespace: subNS}
+		if _, err := overrideReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile %s: %v", name, err)
+		}
+		got := &maasv1alpha1.MaaSRateLimitOverride{}
+		if err := c.Get(ctx, key, got); err != nil {
+			t.Fatalf("Get %s: %v", name, err)
+		}
+		if got.Status.Phase != want.phase {
+			t.Errorf("%s phase = %q, want %q", name, got.Status.Phase, want.phase)
+		}
+		if fmt.Sprint(got.Status.Subscriptions) != fmt.Sprint(want.subscriptions) {
+			t.Errorf("%s subscriptions = %v, want %v", name, got.Status.Subscriptions, want.subscriptions)
+		}
+		if ready := apimeta.IsStatusConditionTrue(got.Status.Conditions, "Ready"); ready != (want.phase == OverridePhaseActive) {
+			t.Errorf("%s Ready = %v, want it true only when Active", name, ready)
+		}
+	}
+
+	// Deleting the overrides restores the tier limits and drops the annotation.
+	for _, o := range []client.Object{active, superseded, unmatched} {
+		if err := c.Delete(ctx, o); err != nil {
+			t.Fatalf("Delete %s: %v", o.GetName(), err)
+		}
+	}
+	if _, err := subReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "acme-gold", Namespace: subNS}}); err != nil {
+		t.Fatalf("Reconcile acme-gold: %v", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: trlpName, Namespace: modelNamespace}, trlp); err != nil {
+		t.Fatalf("Get TokenRateLimitPolicy: %v", err)
+	}
+	if got := rateLimit("acme-gold"); got != 1000 {
+		t.Errorf("acme-gold limit after deleting the overrides = %d, want 1000", got)
+	}
+	if _, ok := trlp.GetAnnotations()[rateLimitOverridesAnnotation]; ok {
+		t.Errorf("annotation %s should be removed with the last override", rateLimitOverridesAnnotation)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// reconcileRLPForModel builds or updates the aggregated RateLimitPolicy for a model from the
+// requestRateLimits of its subscriptions, the same way reconcileTRLPForModel does for token
+// limits. Subscriptions without request limits get no limit, and the policy is deleted when none
+// of the model's subscriptions has any.
+func (r *MaaSSubscriptionReconciler) reconcileRLPForModel(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	allSubs, err := subscriptionEntriesForModel(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	overrides, err := effectiveOverrides(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+
+	limitsMap := map[string]interface{}{}
+	var subNames, overrideNames []string
+	for _, entry := range allSubs {
+		requestRateLimits := entry.mRef.RequestRateLimits
+		if o := overrideFor(overrides, &entry.sub, entry.mRef); o != nil && len(o.Spec.RequestRateLimits) > 0 {
+			requestRateLimits = o.Spec.RequestRateLimits
+			overrideNames = append(overrideNames, o.Name)
+		}
+		if len(requestRateLimits) == 0 {
+			continue
+		}
+		var rates []interface{}
+		for _, rrl := range requestRateLimits {
+			rates = append(rates, map[string]interface{}{"limit": rrl.Limit, "window": rrl.Window})
+		}
+		subRef := entry.sub.Namespace + "/" + entry.sub.Name
+		subNames = append(subNames, entry.sub.Name)
+		limitsMap[fmt.Sprintf("%s-%s-requests", strings.ReplaceAll(subRef, "/", "-"), modelName)] = map[string]interface{}{
+			"rates": rates,
+			"when": []interface{}{
+				map[string]interface{}{
+					"predicate": fmt.Sprintf(`auth.identity.selected_subscription_key == "%s@%s/%s"`, subRef, modelNamespace, modelName),
+				},
+			},
+			"counters": []interface{}{
+				map[string]interface{}{"expression": "auth.identity.userid"},
+			},
+		}
+	}
+
+	httpRouteName, httpRouteNS, err := findHTTPRouteForModel(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		if errors.Is(err, ErrModelNotFound) || len(limitsMap) == 0 {
+			return r.deleteModelRLP(ctx, log, modelNamespace, modelName)
+		}
+		if errors.Is(err, ErrHTTPRouteNotFound) {
+			// The HTTPRoute watch reconciles again once the route exists.
+			return nil
+		}
+		return fmt.Errorf("failed to resolve HTTPRoute for model %s/%s: %w", modelNamespace, modelName, err)
+	}
+
+	naming, err := namingFor(ctx, r.Client, modelNamespace, modelName)
+	if err != nil {
+		return err
+	}
+	policyName, err := naming.name(nameTemplateRateLimitPolicy, fmt.Sprintf("maas-rlp-%s", modelName))
+	if err != nil {
+		return fmt.Errorf("model %s/%s: %w", modelNamespace, modelName, err)
+	}
+	if skip, err := r.generatedPolicySkipped(ctx, log, rateLimitPolicyGVK, policyName, httpRouteNS, modelNamespace, modelName); err != nil || skip {
+		return err
+	}
+	if len(limitsMap) == 0 {
+		return r.deleteModelRLP(ctx, log, modelNamespace, modelName)
+	}
+
+	route := &gatewayapiv1.HTTPRoute{}
+	if err := r.Get(ctx, types.NamespacedName{Name: httpRouteName, Namespace: httpRouteNS}, route); err != nil {
+		return fmt.Errorf("failed to fetch HTTPRoute %s/%s: %w", httpRouteNS, httpRouteName, err)
+	}
+
+	sort.Strings(subNames)
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(rateLimitPolicyGVK)
+	policy.SetName(policyName)
+	policy.SetNamespace(httpRouteNS)
+	policy.SetLabels(naming.withLabels(map[string]string{
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": modelNamespace,
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/part-of":           "maas-subscription",
+		"app.kubernetes.io/component":         "rate-limit-policy",
+	}))
+	annotations := map[string]string{
+		"maas.opendatahub.io/subscriptions": strings.Join(subNames, ","),
+	}
+	if len(overrideNames) > 0 {
+		sort.Strings(overrideNames)
+		annotations[rateLimitOverridesAnnotation] = strings.Join(slices.Compact(overrideNames), ",")
+	}
+	policy.SetAnnotations(annotations)
+	spec := map[string]interface{}{
+		"targetRef": map[string]interface{}{
+			"group": "gateway.networking.k8s.io",
+			"kind":  "HTTPRoute",
+			"name":  httpRouteName,
+		},
+		"limits": limitsMap,
+	}
+	if err := unstructured.SetNestedMap(policy.Object, spec, "spec"); err != nil {
+		return fmt.Errorf("failed to set spec: %w", err)
+	}
+	return r.applyGeneratedPolicy(ctx, log, route, policy, modelNamespace, modelName, subNames)
+}
+
+// deleteModelRLP deletes the generated RateLimitPolicy of a model, wherever its route lives.
+func (r *MaaSSubscriptionReconciler) deleteModelRLP(ctx context.Context, log logr.Logger, modelNamespace, modelName string) error {
+	policyList := &unstructured.UnstructuredList{}
+	policyList.SetGroupVersionKind(rateLimitPolicyGVK.GroupVersion().WithKind("RateLimitPolicyList"))
+	if err := r.List(ctx, policyList, client.MatchingLabels{
+		"maas.opendatahub.io/model":           modelName,
+		"maas.opendatahub.io/model-namespace": modelNamespace,
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/part-of":           "maas-subscription",
+	}); err != nil {
+		if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list RateLimitPolicy for cleanup: %w", err)
+	}
+	for i := range policyList.Items {
+		p := &policyList.Items[i]
+		if !isManaged(p) {
+			log.Info("RateLimitPolicy opted out, skipping deletion", "name", p.GetName(), "namespace", p.GetNamespace(), "model", modelNamespace+"/"+modelName)
+			continue
+		}
+		log.Info("Deleting RateLimitPolicy (no remaining request limits)", "name", p.GetName(), "namespace", p.GetNamespace(), "model", modelNamespace+"/"+modelName)
+		if err := r.Delete(ctx, p); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete RateLimitPolicy %s/%s: %w", p.GetNamespace(), p.GetName(), err)
+		}
+	}
+	return nil
+}
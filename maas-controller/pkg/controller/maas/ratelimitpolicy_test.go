@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestMaaSSubscriptionReconciler_RateLimitPolicy(t *testing.T) {
+	const (
+		modelName = "granite"
+		namespace = "default"
+		rlpName   = "maas-rlp-" + modelName
+	)
+	ctx := context.Background()
+
+	model := newMaaSModelRef(modelName, namespace, "ExternalModel", modelName)
+	route := newHTTPRoute("maas-model-"+modelName, namespace)
+	gold := newMaaSSubscription("gold", namespace, "gold-users", modelName, 1000)
+	gold.Spec.ModelRefs[0].RequestRateLimits = []maasv1alpha1.RequestRateLimit{{Limit: 10, Window: "1s"}}
+	free := newMaaSSubscription("free", namespace, "free-users", modelName, 100)
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRESTMapper(testRESTMapper()).
+		WithObjects(model, route, gold, free).
+		WithStatusSubresource(&maasv1alpha1.MaaSSubscription{}).
+		WithIndex(&maasv1alpha1.MaaSSubscription{}, "spec.modelRef", subscriptionModelRefIndexer).
+		Build()
+	r := &MaaSSubscriptionReconciler{Client: c, Scheme: scheme}
+	reconcile := func(name string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}); err != nil {
+			t.Fatalf("Reconcile %s: %v", name, err)
+		}
+	}
+	getRLP := func() (*unstructured.Unstructured, error) {
+		rlp := &unstructured.Unstructured{}
+		rlp.SetGroupVersionKind(rateLimitPolicyGVK)
+		return rlp, c.Get(ctx, types.NamespacedName{Name: rlpName, Namespace: namespace}, rlp)
+	}
+
+	reconcile("gold")
+	rlp, err := getRLP()
+	if err != nil {
+		t.Fatalf("Get RateLimitPolicy: %v", err)
+	}
+	limits, _, _ := unstructured.NestedMap(rlp.Object, "spec", "limits")
+	if len(limits) != 1 {
+		t.Fatalf("RLP limits = %v, want only gold's request limit", limits)
+	}
+	rates, _, _ := unstructured.NestedSlice(limits, "default-gold-"+modelName+"-requests", "rates")
+	if len(rates) != 1 {
+		t.Fatalf("gold rates = %v, want one rate", rates)
+	}
+	if limit, _, _ := unstructured.NestedInt64(rates[0].(map[string]interface{}), "limit"); limit != 10 {
+		t.Errorf("gold limit = %d, want 10", limit)
+	}
+	if target, _, _ := unstructured.NestedString(rlp.Object, "spec", "targetRef", "name"); target != route.Name {
+		t.Errorf("RLP targetRef = %q, want %q", target, route.Name)
+	}
+	if got := rlp.GetAnnotations()["maas.opendatahub.io/subscriptions"]; got != "gold" {
+		t.Errorf("subscriptions annotation = %q, want gold", got)
+	}
+
+	// Dropping the last request limit removes the policy.
+	if err := c.Get(ctx, types.NamespacedName{Name: "gold", Namespace: namespace}, gold); err != nil {
+		t.Fatalf("Get gold: %v", err)
+	}
+	gold.Spec.ModelRefs[0].RequestRateLimits = nil
+	if err := c.Update(ctx, gold); err != nil {
+		t.Fatalf("Update gold: %v", err)
+	}
+	reconcile("gold")
+	if _, err := getRLP(); !apierrors.IsNotFound(err) {
+		t.Errorf("Get RateLimitPolicy after dropping request limits: err = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,165 @@
+package maas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// modelRouteFilter describes an EnvoyFilter that patches the Envoy routes of a model's HTTPRoute.
+type modelRouteFilter struct {
+	// what names the filter in logs and errors, e.g. "tracing".
+	what string
+	// field is the MaaSModelRef field the filter applies, for errors.
+	field string
+	// name is the filter's name. It lives in the gateway namespace, so it includes the model
+	// namespace.
+	name string
+	// component is the app.kubernetes.io/component label of the filter.
+	component string
+}
+
+// reconcileModelRouteFilter creates or updates an EnvoyFilter that merges patch into each Envoy
+// route of the model's HTTPRoute on its gateway. Istio names the Envoy route of each HTTPRoute rule
+// <namespace>.<name>.<rule index>, which the filter matches.
+func (r *MaaSModelRefReconciler) reconcileModelRouteFilter(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef, f modelRouteFilter, patch map[string]any) error {
+	resolver := GetRouteResolver(model.Spec.ModelRef.Kind)
+	if resolver == nil {
+		return fmt.Errorf("unknown model kind: %s", model.Spec.ModelRef.Kind)
+	}
+	routeName, routeNS, err := resolver.HTTPRouteForModel(ctx, r.Client, model)
+	if err != nil {
+		return err
+	}
+	route, err := getHTTPRoute(ctx, r.Client, routeName, routeNS)
+	if errors.Is(err, ErrHTTPRouteNotFound) {
+		// The HTTPRoute watch reconciles the model again once the route exists.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	configPatches := make([]any, 0, len(route.Spec.Rules))
+	for i := range route.Spec.Rules {
+		configPatches = append(configPatches, map[string]any{
+			"applyTo": "HTTP_ROUTE",
+			"match": map[string]any{
+				"context": "GATEWAY",
+				"routeConfiguration": map[string]any{
+					"vhost": map[string]any{
+						"route": map[string]any{"name": fmt.Sprintf("%s.%s.%d", route.Namespace, route.Name, i)},
+					},
+				},
+			},
+			"patch": map[string]any{
+				"operation": "MERGE",
+				"value":     runtime.DeepCopyJSON(patch),
+			},
+		})
+	}
+	spec := map[string]any{
+		"workloadSelector": map[string]any{
+			"labels": map[string]any{"gateway.networking.k8s.io/gateway-name": r.targetGatewayName(model)},
+		},
+		"configPatches": configPatches,
+	}
+
+	naming, err := namingFor(ctx, r.Client, model.Namespace, model.Name)
+	if err != nil {
+		return err
+	}
+	namespace := r.targetGatewayNamespace(model)
+	labels := naming.withLabels(map[string]string{
+		managedByLabel:                        managedByValue,
+		"app.kubernetes.io/component":         f.component,
+		"maas.opendatahub.io/model":           model.Name,
+		"maas.opendatahub.io/model-namespace": model.Namespace,
+	})
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(envoyFilterGVK)
+	err = r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: f.name}, existing)
+	if apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("%s requires the Istio EnvoyFilter API, which is not installed: %w", f.field, err)
+	}
+	if apierrors.IsNotFound(err) {
+		filter := &unstructured.Unstructured{}
+		filter.SetGroupVersionKind(envoyFilterGVK)
+		filter.SetName(f.name)
+		filter.SetNamespace(namespace)
+		filter.SetLabels(labels)
+		filter.Object["spec"] = spec
+		if err := r.Create(ctx, filter); err != nil {
+			return fmt.Errorf("failed to create %s EnvoyFilter for model %s/%s: %w", f.what, model.Namespace, model.Name, err)
+		}
+		log.Info("EnvoyFilter created", "kind", f.what, "name", f.name, "namespace", namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s EnvoyFilter: %w", f.what, err)
+	}
+	if !isManaged(existing) {
+		log.Info("EnvoyFilter opted out, skipping", "kind", f.what, "name", f.name)
+		return nil
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info("EnvoyFilter exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it",
+			"name", f.name, "namespace", namespace)
+		return nil
+	}
+
+	snapshot := existing.DeepCopy()
+	mergedLabels := existing.GetLabels()
+	if mergedLabels == nil {
+		mergedLabels = make(map[string]string)
+	}
+	for k, v := range labels {
+		mergedLabels[k] = v
+	}
+	existing.SetLabels(mergedLabels)
+	existing.Object["spec"] = spec
+	if equality.Semantic.DeepEqual(snapshot.Object, existing.Object) {
+		return nil
+	}
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update %s EnvoyFilter for model %s/%s: %w", f.what, model.Namespace, model.Name, err)
+	}
+	log.Info("EnvoyFilter updated", "kind", f.what, "name", f.name, "namespace", namespace)
+	return nil
+}
+
+// deleteModelRouteFilter deletes a model's route EnvoyFilter, if the controller generated it.
+func (r *MaaSModelRefReconciler) deleteModelRouteFilter(ctx context.Context, log logr.Logger, what, namespace, name string) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(envoyFilterGVK)
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, existing)
+	if apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s EnvoyFilter: %w", what, err)
+	}
+	if existing.GetLabels()[managedByLabel] != managedByValue {
+		return nil
+	}
+	if !isManaged(existing) {
+		log.Info("EnvoyFilter opted out, skipping deletion", "kind", what, "name", existing.GetName())
+		return nil
+	}
+	log.Info("Deleting EnvoyFilter", "kind", what, "name", existing.GetName(), "namespace", existing.GetNamespace())
+	if err := r.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s EnvoyFilter %s/%s: %w", what, existing.GetNamespace(), existing.GetName(), err)
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+const (
+	// SandboxName names the mock backend's Deployment, Service and HTTPRoute.
+	SandboxName = "maas-sandbox"
+	// SandboxHeader is set to "true" by the AuthPolicy (or maas-api's ext_authz) on requests of
+	// sandbox subscriptions. Envoy re-matches the route after authorization, and the sandbox
+	// HTTPRoute, which requires the header, then wins over the model's own route.
+	SandboxHeader = "X-MaaS-Sandbox"
+	// DefaultSandboxLatency is the time to first token of sandbox responses.
+	DefaultSandboxLatency = 300 * time.Millisecond
+
+	sandboxPort = 8080
+	// Gateway API caps the matches of one rule.
+	sandboxMatchesPerRule = 64
+)
+
+// SandboxReconciler deploys the mock backend that sandbox MaaSSubscriptions are routed to, and
+// the HTTPRoute that sends their requests to it instead of the model. It runs maas-api's
+// `sandbox` command. Everything is removed when no subscription is a sandbox.
+type SandboxReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Namespace holds the backend and its route, usually the maas-api namespace.
+	Namespace        string
+	Image            string
+	Latency          time.Duration
+	GatewayName      string
+	GatewayNamespace string
+}
+
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile brings the sandbox backend in line with the sandbox subscriptions.
+func (r *SandboxReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("sandbox", r.Namespace+"/"+SandboxName)
+
+	paths, sandboxes, err := r.sandboxPaths(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if sandboxes == 0 {
+		return ctrl.Result{}, r.cleanup(ctx, log)
+	}
+	for _, obj := range []client.Object{r.desiredDeployment(), r.desiredService(), r.desiredRoute(paths)} {
+		if err := r.apply(ctx, log, obj); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// sandboxPaths returns the gateway path prefixes of the models in sandbox subscriptions, taken
+// from their status.endpoint, and the number of sandbox subscriptions. Models on a dedicated
+// hostname are left out: their route's hostname would take precedence over the sandbox route.
+func (r *SandboxReconciler) sandboxPaths(ctx context.Context) ([]string, int, error) {
+	var subs maasv1alpha1.MaaSSubscriptionList
+	if err := r.List(ctx, &subs); err != nil {
+		return nil, 0, fmt.Errorf("failed to list MaaSSubscriptions: %w", err)
+	}
+	var paths []string
+	sandboxes := 0
+	for _, sub := range subs.Items {
+		if !sub.Spec.Sandbox || !sub.DeletionTimestamp.IsZero() {
+			continue
+		}
+		sandboxes++
+		for _, ref := range sub.Spec.ModelRefs {
+			model := &maasv1alpha1.MaaSModelRef{}
+			if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, model); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, 0, fmt.Errorf("failed to get MaaSModelRef %s/%s: %w", ref.Namespace, ref.Name, err)
+			}
+			if model.Spec.Routing != nil && model.Spec.Routing.Hostname != "" {
+				continue
+			}
+			u, err := url.Parse(model.Status.Endpoint)
+			if err != nil || u.Path == "" || u.Path == "/" {
+				continue
+			}
+			paths = append(paths, u.Path)
+		}
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths), sandboxes, nil
+}
+
+func (r *SandboxReconciler) labels() map[string]string {
+	return map[string]string{
+		managedByLabel:                managedByValue,
+		"app.kubernetes.io/name":      SandboxName,
+		"app.kubernetes.io/component": "sandbox",
+	}
+}
+
+func (r *SandboxReconciler) desiredDeployment() *appsv1.Deployment {
+	latency := r.Latency
+	if latency <= 0 {
+		latency = DefaultSandboxLatency
+	}
+	replicas := int32(1)
+	selector := map[string]string{"app.kubernetes.io/name": SandboxName}
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: SandboxName, Namespace: r.Namespace, Labels: r.labels()},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: r.labels()},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "sandbox",
+						Image:   r.Image,
+						Command: []string{"./maas-api", "sandbox"},
+						Args:    []string{fmt.Sprintf("--address=:%d", sandboxPort), "--latency=" + latency.String()},
+						Ports:   []corev1.ContainerPort{{Name: "http", ContainerPort: sandboxPort, Protocol: corev1.ProtocolTCP}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+								Path: "/health", Port: intstr.FromString("http"),
+							}},
+						},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func (r *SandboxReconciler) desiredService() *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: SandboxName, Namespace: r.Namespace, Labels: r.labels()},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app.kubernetes.io/name": SandboxName},
+			Ports: []corev1.ServicePort{{
+				Name: "http", Port: sandboxPort, TargetPort: intstr.FromString("http"), Protocol: corev1.ProtocolTCP,
+			}},
+		},
+	}
+}
+
+// desiredRoute matches each model path prefix together with SandboxHeader and strips the prefix,
+// so /llm/granite/v1/chat/completions reaches the backend as /v1/chat/completions.
+func (r *SandboxReconciler) desiredRoute(paths []string) *gatewayapiv1.HTTPRoute {
+	gwNamespace := gatewayapiv1.Namespace(r.GatewayNamespace)
+	port := gatewayapiv1.PortNumber(sandboxPort)
+	prefix := gatewayapiv1.PathMatchPathPrefix
+	pathExact := gatewayapiv1.PathMatchExact
+	exact := gatewayapiv1.HeaderMatchExact
+	root := "/"
+	var rules []gatewayapiv1.HTTPRouteRule
+	for chunk := range slices.Chunk(paths, sandboxMatchesPerRule) {
+		matches := make([]gatewayapiv1.HTTPRouteMatch, 0, len(chunk))
+		for _, p := range chunk {
+			matches = append(matches, gatewayapiv1.HTTPRouteMatch{
+				Path: &gatewayapiv1.HTTPPathMatch{Type: &prefix, Value: &p},
+				Headers: []gatewayapiv1.HTTPHeaderMatch{{
+					Type: &exact, Name: gatewayapiv1.HTTPHeaderName(SandboxHeader), Value: "true",
+				}},
+			})
+		}
+		rules = append(rules, gatewayapiv1.HTTPRouteRule{
+			Matches: matches,
+			Filters: []gatewayapiv1.HTTPRouteFilter{{
+				Type: gatewayapiv1.HTTPRouteFilterURLRewrite,
+				URLRewrite: &gatewayapiv1.HTTPURLRewriteFilter{Path: &gatewayapiv1.HTTPPathModifier{
+					Type:               gatewayapiv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: &root,
+				}},
+			}},
+			BackendRefs: []gatewayapiv1.HTTPBackendRef{{BackendRef: gatewayapiv1.BackendRef{
+				BackendObjectReference: gatewayapiv1.BackendObjectReference{Name: SandboxName, Port: &port},
+			}}},
+		})
+	}
+	if len(rules) == 0 {
+		// A route needs a rule; without sandboxed models, one that never matches.
+		never := "/.maas-sandbox-no-models"
+		rules = []gatewayapiv1.HTTPRouteRule{{Matches: []gatewayapiv1.HTTPRouteMatch{{
+			Path: &gatewayapiv1.HTTPPathMatch{Type: &pathExact, Value: &never},
+		}}}}
+	}
+	return &gatewayapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: SandboxName, Namespace: r.Namespace, Labels: r.labels()},
+		Spec: gatewayapiv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+				ParentRefs: []gatewayapiv1.ParentReference{{
+					Name:      gatewayapiv1.ObjectName(r.GatewayName),
+					Namespace: &gwNamespace,
+				}},
+			},
+			Rules: rules,
+		},
+	}
+}
+
+// apply creates obj or restores its spec and labels; objects maas-controller does not manage are
+// left alone.
+func (r *SandboxReconciler) apply(ctx context.Context, log logr.Logger, desired client.Object) error {
+	kind := fmt.Sprintf("%T", desired)
+	existing, ok := desired.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("cannot copy %s", kind)
+	}
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		log.Info("Creating sandbox resource", "kind", kind)
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create sandbox %s: %w", kind, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get sandbox %s: %w", kind, err)
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info("Sandbox resource exists but is not managed by maas-controller, skipping; annotate it with "+AdoptAnnotation+"=true to adopt it", "kind", kind)
+		return nil
+	}
+
+	changed := false
+	labels := existing.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range desired.GetLabels() {
+		if labels[k] != v {
+			labels[k], changed = v, true
+		}
+	}
+	existing.SetLabels(labels)
+	switch e := existing.(type) {
+	case *appsv1.Deployment:
+		d := desired.(*appsv1.Deployment)
+		if !equality.Semantic.DeepDerivative(d.Spec, e.Spec) {
+			e.Spec, changed = d.Spec, true
+		}
+	case *corev1.Service:
+		d := desired.(*corev1.Service)
+		if !equality.Semantic.DeepDerivative(d.Spec.Ports, e.Spec.Ports) || !equality.Semantic.DeepEqual(d.Spec.Selector, e.Spec.Selector) {
+			e.Spec.Ports, e.Spec.Selector, changed = d.Spec.Ports, d.Spec.Selector, true
+		}
+	case *gatewayapiv1.HTTPRoute:
+		d := desired.(*gatewayapiv1.HTTPRoute)
+		if !equality.Semantic.DeepEqual(d.Spec, e.Spec) {
+			e.Spec, changed = d.Spec, true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	log.Info("Updating sandbox resource", "kind", kind)
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update sandbox %s: %w", kind, err)
+	}
+	return nil
+}
+
+// cleanup deletes the sandbox resources maas-controller manages.
+func (r *SandboxReconciler) cleanup(ctx context.Context, log logr.Logger) error {
+	key := types.NamespacedName{Name: SandboxName, Namespace: r.Namespace}
+	for _, obj := range []client.Object{&gatewayapiv1.HTTPRoute{}, &corev1.Service{}, &appsv1.Deployment{}} {
+		if err := r.Get(ctx, key, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get sandbox %T: %w", obj, err)
+		}
+		if obj.GetLabels()[managedByLabel] != managedByValue {
+			continue
+		}
+		log.Info("Deleting sandbox resource; no sandbox subscriptions remain", "kind", fmt.Sprintf("%T", obj))
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete sandbox %T: %w", obj, err)
+		}
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SandboxReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toSingleton := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: SandboxName, Namespace: r.Namespace}}}
+	})
+	isSandbox := builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == SandboxName && obj.GetNamespace() == r.Namespace
+	}))
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("sandbox").
+		Watches(&maasv1alpha1.MaaSSubscription{}, toSingleton).
+		Watches(&maasv1alpha1.MaaSModelRef{}, toSingleton).
+		Watches(&appsv1.Deployment{}, toSingleton, isSandbox).
+		Watches(&corev1.Service{}, toSingleton, isSandbox).
+		Watches(&gatewayapiv1.HTTPRoute{}, toSingleton, isSandbox).
+		Complete(tracing.Reconciler("sandbox", r))
+}
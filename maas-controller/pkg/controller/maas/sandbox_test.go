@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func newSandboxReconciler(objs ...client.Object) *SandboxReconciler {
+	return &SandboxReconciler{
+		Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Scheme:           scheme,
+		Namespace:        "opendatahub",
+		Image:            "quay.io/opendatahub/maas-api:latest",
+		GatewayName:      "maas-default-gateway",
+		GatewayNamespace: "openshift-ingress",
+	}
+}
+
+func sandboxSubscription(name string, sandbox bool, models ...string) *maasv1alpha1.MaaSSubscription {
+	sub := &maasv1alpha1.MaaSSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "models-as-a-service"},
+		Spec:       maasv1alpha1.MaaSSubscriptionSpec{Sandbox: sandbox},
+	}
+	for _, m := range models {
+		sub.Spec.ModelRefs = append(sub.Spec.ModelRefs, maasv1alpha1.ModelSubscriptionRef{Name: m, Namespace: "llm"})
+	}
+	return sub
+}
+
+func modelWithEndpoint(name, endpoint string) *maasv1alpha1.MaaSModelRef {
+	return &maasv1alpha1.MaaSModelRef{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "llm"},
+		Spec:       maasv1alpha1.MaaSModelSpec{ModelRef: maasv1alpha1.ModelReference{Kind: "LLMInferenceService", Name: name}},
+		Status:     maasv1alpha1.MaaSModelStatus{Endpoint: endpoint},
+	}
+}
+
+func TestSandboxReconciler_RoutesSandboxModels(t *testing.T) {
+	dedicated := modelWithEndpoint("gpt", "https://gpt.example.com/")
+	dedicated.Spec.Routing = &maasv1alpha1.ModelRouting{Hostname: "gpt.example.com"}
+	r := newSandboxReconciler(
+		sandboxSubscription("sandbox", true, "granite", "llama", "gpt", "missing"),
+		sandboxSubscription("premium", false, "mistral"),
+		modelWithEndpoint("granite", "https://maas.example.com/llm/granite"),
+		modelWithEndpoint("llama", "https://maas.example.com/llm/llama"),
+		modelWithEndpoint("mistral", "https://maas.example.com/llm/mistral"),
+		dedicated,
+	)
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	key := types.NamespacedName{Name: SandboxName, Namespace: "opendatahub"}
+	deploy := &appsv1.Deployment{}
+	if err := r.Get(context.Background(), key, deploy); err != nil {
+		t.Fatalf("Get Deployment: %v", err)
+	}
+	container := deploy.Spec.Template.Spec.Containers[0]
+	if container.Image != r.Image || container.Args[1] != "--latency=300ms" {
+		t.Errorf("container = %s %v, want image %s with the default latency", container.Image, container.Args, r.Image)
+	}
+	if err := r.Get(context.Background(), key, &corev1.Service{}); err != nil {
+		t.Fatalf("Get Service: %v", err)
+	}
+
+	route := &gatewayapiv1.HTTPRoute{}
+	if err := r.Get(context.Background(), key, route); err != nil {
+		t.Fatalf("Get HTTPRoute: %v", err)
+	}
+	if len(route.Spec.Rules) != 1 {
+		t.Fatalf("rules = %d, want 1", len(route.Spec.Rules))
+	}
+	var paths []string
+	for _, m := range route.Spec.Rules[0].Matches {
+		paths = append(paths, *m.Path.Value)
+		if len(m.Headers) != 1 || string(m.Headers[0].Name) != SandboxHeader || m.Headers[0].Value != "true" {
+			t.Errorf("match %s headers = %+v, want %s: true", *m.Path.Value, m.Headers, SandboxHeader)
+		}
+	}
+	if len(paths) != 2 || paths[0] != "/llm/granite" || paths[1] != "/llm/llama" {
+		t.Errorf("paths = %v, want only the sandbox subscription's models on the shared hostname", paths)
+	}
+	if got := route.Spec.Rules[0].BackendRefs[0].Name; got != SandboxName {
+		t.Errorf("backend = %s, want %s", got, SandboxName)
+	}
+}
+
+func TestSandboxReconciler_RemovesBackendWithoutSandboxes(t *testing.T) {
+	r := newSandboxReconciler(
+		sandboxSubscription("sandbox", true, "granite"),
+		modelWithEndpoint("granite", "https://maas.example.com/llm/granite"),
+	)
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	sub := &maasv1alpha1.MaaSSubscription{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: "sandbox", Namespace: "models-as-a-service"}, sub); err != nil {
+		t.Fatalf("Get MaaSSubscription: %v", err)
+	}
+	sub.Spec.Sandbox = false
+	if err := r.Update(context.Background(), sub); err != nil {
+		t.Fatalf("Update MaaSSubscription: %v", err)
+	}
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	key := types.NamespacedName{Name: SandboxName, Namespace: "opendatahub"}
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}, &gatewayapiv1.HTTPRoute{}} {
+		if err := r.Get(context.Background(), key, obj); !apierrors.IsNotFound(err) {
+			t.Errorf("Get %T: err = %v, want NotFound", obj, err)
+		}
+	}
+}
+
+func TestSandboxReconciler_LeavesUnmanagedResources(t *testing.T) {
+	unmanaged := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: SandboxName, Namespace: "opendatahub"}}
+	r := newSandboxReconciler(unmanaged)
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(unmanaged), &corev1.Service{}); err != nil {
+		t.Errorf("unmanaged Service was removed: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+)
+
+// DefaultSharedRoutePaths are the OpenAI-style paths served by the shared route.
+var DefaultSharedRoutePaths = []string{"/v1/chat/completions", "/v1/completions", "/v1/embeddings", "/v1/responses"}
+
+// SharedRouteReconciler maintains the single OpenAI-style HTTPRoute that accepts requests for
+// every model on the same paths. The route has no backends: the maas-api external processor reads
+// the model from the request body and rewrites the path onto that model's own route, so Envoy
+// re-matches and the model's AuthPolicy, TokenRateLimitPolicy and backend apply. A request the
+// processor did not reroute never leaves the gateway.
+type SharedRouteReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	RouteName        string
+	RouteNamespace   string
+	GatewayName      string
+	GatewayNamespace string
+
+	// Paths are matched exactly. Defaults to DefaultSharedRoutePaths and must agree with the
+	// paths maas-api's processor handles (EXT_PROC_PATHS).
+	Paths []string
+}
+
+// Reconcile creates the shared HTTPRoute, or restores it after drift.
+func (r *SharedRouteReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := logr.FromContextOrDiscard(ctx).WithValues("HTTPRoute", r.RouteNamespace+"/"+r.RouteName)
+	return ctrl.Result{}, r.apply(ctx, log)
+}
+
+func (r *SharedRouteReconciler) apply(ctx context.Context, log logr.Logger) error {
+	desired := r.desiredRoute()
+	existing := &gatewayapiv1.HTTPRoute{}
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		log.Info("Creating shared HTTPRoute")
+		if err := r.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed to create shared HTTPRoute: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get shared HTTPRoute: %w", err)
+	}
+	if !isOwnedOrAdoptable(existing) {
+		log.Info("HTTPRoute exists but is not managed by maas-controller, skipping; annotate it with " + AdoptAnnotation + "=true to adopt it")
+		return nil
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) && existing.Labels[managedByLabel] == managedByValue {
+		return nil
+	}
+	existing.Spec = desired.Spec
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		existing.Labels[k] = v
+	}
+	log.Info("Updating shared HTTPRoute")
+	if err := r.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update shared HTTPRoute: %w", err)
+	}
+	return nil
+}
+
+// desiredRoute builds the shared route: one rule, without backends, that exactly matches each
+// shared path.
+func (r *SharedRouteReconciler) desiredRoute() *gatewayapiv1.HTTPRoute {
+	paths := r.Paths
+	if len(paths) == 0 {
+		paths = DefaultSharedRoutePaths
+	}
+	exact := gatewayapiv1.PathMatchExact
+	post := gatewayapiv1.HTTPMethod(http.MethodPost)
+	matches := make([]gatewayapiv1.HTTPRouteMatch, 0, len(paths))
+	for _, p := range paths {
+		matches = append(matches, gatewayapiv1.HTTPRouteMatch{
+			Path:   &gatewayapiv1.HTTPPathMatch{Type: &exact, Value: &p},
+			Method: &post,
+		})
+	}
+	gwNamespace := gatewayapiv1.Namespace(r.GatewayNamespace)
+
+	return &gatewayapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.RouteName,
+			Namespace: r.RouteNamespace,
+			Labels: map[string]string{
+				managedByLabel:                managedByValue,
+				"app.kubernetes.io/component": "shared-route",
+			},
+		},
+		Spec: gatewayapiv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
+				ParentRefs: []gatewayapiv1.ParentReference{{
+					Name:      gatewayapiv1.ObjectName(r.GatewayName),
+					Namespace: &gwNamespace,
+				}},
+			},
+			Rules: []gatewayapiv1.HTTPRouteRule{{Matches: matches}},
+		},
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SharedRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Create the route at startup; the watch below only restores it after it changes or is deleted.
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		if err := r.apply(ctx, mgr.GetLogger().WithName("shared-route")); err != nil {
+			mgr.GetLogger().Error(err, "unable to create shared HTTPRoute; it will be retried when the route changes")
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+
+	isSharedRoute := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == r.RouteName && obj.GetNamespace() == r.RouteNamespace
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("sharedroute").
+		For(&gatewayapiv1.HTTPRoute{}, builder.WithPredicates(isSharedRoute)).
+		Complete(tracing.Reconciler("sharedroute", r))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newSharedRouteReconciler(objs ...*gatewayapiv1.HTTPRoute) *SharedRouteReconciler {
+	b := fake.NewClientBuilder().WithScheme(scheme)
+	for _, o := range objs {
+		b = b.WithObjects(o)
+	}
+	return &SharedRouteReconciler{
+		Client:           b.Build(),
+		Scheme:           scheme,
+		RouteName:        "maas-openai",
+		RouteNamespace:   "opendatahub",
+		GatewayName:      "maas-default-gateway",
+		GatewayNamespace: "openshift-ingress",
+	}
+}
+
+func getSharedRoute(t *testing.T, r *SharedRouteReconciler) *gatewayapiv1.HTTPRoute {
+	t.Helper()
+	route := &gatewayapiv1.HTTPRoute{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: r.RouteName, Namespace: r.RouteNamespace}, route); err != nil {
+		t.Fatalf("Get shared HTTPRoute: %v", err)
+	}
+	return route
+}
+
+func TestSharedRouteReconciler_CreatesRouteWithoutBackends(t *testing.T) {
+	r := newSharedRouteReconciler()
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+
+	route := getSharedRoute(t, r)
+	if route.Labels[managedByLabel] != managedByValue {
+		t.Errorf("labels = %v, want managed-by %s", route.Labels, managedByValue)
+	}
+	if len(route.Spec.ParentRefs) != 1 || route.Spec.ParentRefs[0].Name != "maas-default-gateway" {
+		t.Errorf("parentRefs = %+v, want maas-default-gateway", route.Spec.ParentRefs)
+	}
+	if len(route.Spec.Rules) != 1 {
+		t.Fatalf("rules = %d, want 1", len(route.Spec.Rules))
+	}
+	rule := route.Spec.Rules[0]
+	if len(rule.BackendRefs) != 0 {
+		t.Errorf("backendRefs = %+v, want none: only the processor's rewrite may reach a model", rule.BackendRefs)
+	}
+	if len(rule.Matches) != len(DefaultSharedRoutePaths) {
+		t.Fatalf("matches = %d, want %d", len(rule.Matches), len(DefaultSharedRoutePaths))
+	}
+	for i, m := range rule.Matches {
+		if *m.Path.Type != gatewayapiv1.PathMatchExact || *m.Path.Value != DefaultSharedRoutePaths[i] {
+			t.Errorf("match %d = %s %s, want Exact %s", i, *m.Path.Type, *m.Path.Value, DefaultSharedRoutePaths[i])
+		}
+	}
+}
+
+func TestSharedRouteReconciler_RestoresDrift(t *testing.T) {
+	drifted := newSharedRouteReconciler().desiredRoute()
+	drifted.Spec.Rules[0].Matches = drifted.Spec.Rules[0].Matches[:1]
+	r := newSharedRouteReconciler(drifted)
+	r.Paths = []string{"/v1/chat/completions", "/v1/completions"}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if got := len(getSharedRoute(t, r).Spec.Rules[0].Matches); got != 2 {
+		t.Errorf("matches = %d, want 2", got)
+	}
+}
+
+func TestSharedRouteReconciler_LeavesUnmanagedRoute(t *testing.T) {
+	unmanaged := &gatewayapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "maas-openai", Namespace: "opendatahub"}}
+	r := newSharedRouteReconciler(unmanaged)
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{}); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if route := getSharedRoute(t, r); len(route.Spec.Rules) != 0 {
+		t.Errorf("unmanaged route was modified: %+v", route.Spec)
+	}
+}
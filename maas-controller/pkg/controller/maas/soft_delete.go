@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// SoftDeletedAnnotation set to "true" unpublishes a MaaSModelRef: maas-api leaves it out of the
+// catalog and denies new requests for it, while its HTTPRoute and generated policies are kept so
+// removing the annotation restores the model at once. The model is deleted for good once the
+// controller's soft delete grace period has passed.
+const SoftDeletedAnnotation = "maas.opendatahub.io/soft-deleted"
+
+// isSoftDeleted reports whether model carries the soft delete annotation.
+func isSoftDeleted(model *maasv1alpha1.MaaSModelRef) bool {
+	return model.GetAnnotations()[SoftDeletedAnnotation] == "true"
+}
+
+// softDeleteChanged returns true when the soft delete annotation is added or removed. Annotation
+// changes do not bump the generation, so GenerationChangedPredicate alone would miss them.
+func softDeleteChanged(e event.UpdateEvent) bool {
+	return e.ObjectOld.GetAnnotations()[SoftDeletedAnnotation] != e.ObjectNew.GetAnnotations()[SoftDeletedAnnotation]
+}
+
+// reconcileSoftDeleted marks a soft-deleted model and deletes it once the grace period has passed.
+// Generated resources are left in place for a restore.
+func (r *MaaSModelRefReconciler) reconcileSoftDeleted(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef, statusSnapshot *maasv1alpha1.MaaSModelStatus) (ctrl.Result, error) {
+	now := time.Now()
+	if model.Status.SoftDeletedAt == nil {
+		model.Status.SoftDeletedAt = &metav1.Time{Time: now}
+	}
+	softDeletedAt := model.Status.SoftDeletedAt.Time
+
+	if r.SoftDeleteGracePeriod <= 0 {
+		model.Status.Endpoint = ""
+		r.updateStatusWithReason(ctx, model, "SoftDeleted", "Model is soft-deleted; remove the "+SoftDeletedAnnotation+" annotation to restore it", "SoftDeleted", statusSnapshot)
+		return ctrl.Result{}, nil
+	}
+
+	deleteAt := softDeletedAt.Add(r.SoftDeleteGracePeriod)
+	if !now.Before(deleteAt) {
+		log.Info("Soft delete grace period passed, deleting MaaSModelRef", "softDeletedAt", softDeletedAt.UTC().Format(time.RFC3339))
+		if err := r.Delete(ctx, model); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to delete soft-deleted MaaSModelRef: %w", err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	model.Status.Endpoint = ""
+	r.updateStatusWithReason(ctx, model, "SoftDeleted", fmt.Sprintf("Model is soft-deleted and will be deleted at %s; remove the %s annotation to restore it",
+		deleteAt.UTC().Format(time.RFC3339), SoftDeletedAnnotation), "SoftDeleted", statusSnapshot)
+	// Reconcile again when the grace period ends to delete the model.
+	return ctrl.Result{RequeueAfter: deleteAt.Sub(now)}, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestMaaSModelRefReconciler_SoftDeleteAndRestore(t *testing.T) {
+	ctx := context.Background()
+	const ns = "default"
+	model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+	r, c := newTestReconciler(model, newLLMISvcRoute("llama", ns), newLLMISvc("llama", ns, corev1.ConditionTrue))
+	r.SoftDeleteGracePeriod = 24 * time.Hour
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status.Phase != "Ready" {
+		t.Fatalf("Phase = %q, want Ready", got.Status.Phase)
+	}
+
+	got.Annotations = map[string]string{SoftDeletedAnnotation: "true"}
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile soft-deleted: %v", err)
+	}
+	if result.RequeueAfter <= 23*time.Hour || result.RequeueAfter > 24*time.Hour {
+		t.Errorf("RequeueAfter = %v, want the end of the grace period", result.RequeueAfter)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status.Phase != "SoftDeleted" || got.Status.SoftDeletedAt == nil || got.Status.Endpoint != "" {
+		t.Errorf("status = phase %q, softDeletedAt %v, endpoint %q; want SoftDeleted with a timestamp and no endpoint",
+			got.Status.Phase, got.Status.SoftDeletedAt, got.Status.Endpoint)
+	}
+	assertReadyCondition(t, got.Status.Conditions, metav1.ConditionFalse, "SoftDeleted")
+	if err := c.Get(ctx, types.NamespacedName{Name: "llama-route", Namespace: ns}, &gatewayapiv1.HTTPRoute{}); err != nil {
+		t.Errorf("route of a soft-deleted model should be kept: %v", err)
+	}
+
+	got.Annotations = nil
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile restored: %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Status.Phase != "Ready" || got.Status.SoftDeletedAt != nil {
+		t.Errorf("after restore: phase %q, softDeletedAt %v; want Ready without a timestamp", got.Status.Phase, got.Status.SoftDeletedAt)
+	}
+}
+
+func TestMaaSModelRefReconciler_SoftDeleteGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	const ns = "default"
+	softDeletedAt := metav1.NewTime(time.Now().Add(-48 * time.Hour).Truncate(time.Second))
+
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+		wantDeleted bool
+	}{
+		{name: "deleted_after_grace_period", gracePeriod: 24 * time.Hour, wantDeleted: true},
+		{name: "kept_within_grace_period", gracePeriod: 72 * time.Hour},
+		{name: "kept_without_grace_period", gracePeriod: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+			model.Annotations = map[string]string{SoftDeletedAnnotation: "true"}
+			model.Finalizers = []string{maasModelFinalizer}
+			model.Status.SoftDeletedAt = &softDeletedAt
+			r, c := newTestReconciler(model)
+			r.SoftDeleteGracePeriod = tt.gracePeriod
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+			got := &maasv1alpha1.MaaSModelRef{}
+			if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if deleted := !got.DeletionTimestamp.IsZero(); deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if !got.Status.SoftDeletedAt.Equal(&softDeletedAt) {
+				t.Errorf("SoftDeletedAt = %v, want it kept at %v", got.Status.SoftDeletedAt, softDeletedAt)
+			}
+		})
+	}
+}
+
+func TestSoftDeleteChanged(t *testing.T) {
+	plain := newMaaSModelRef("m", "default", "LLMInferenceService", "llama")
+	softDeleted := plain.DeepCopy()
+	softDeleted.Annotations = map[string]string{SoftDeletedAnnotation: "true"}
+	relabeled := plain.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "a"}
+
+	if !softDeleteChanged(event.UpdateEvent{ObjectOld: plain, ObjectNew: softDeleted}) {
+		t.Error("soft delete not detected")
+	}
+	if !softDeleteChanged(event.UpdateEvent{ObjectOld: softDeleted, ObjectNew: plain}) {
+		t.Error("restore not detected")
+	}
+	if softDeleteChanged(event.UpdateEvent{ObjectOld: plain, ObjectNew: relabeled}) {
+		t.Error("unrelated change detected")
+	}
+}
@@ -0,0 +1,71 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// timeoutsFilterName is the name of the EnvoyFilter that carries a model's timeouts.
+func timeoutsFilterName(model *maasv1alpha1.MaaSModelRef) string {
+	return "maas-timeouts-" + model.Namespace + "-" + model.Name
+}
+
+// ValidateTimeouts checks spec.requestTimeout and spec.streamIdleTimeout.
+func ValidateTimeouts(spec maasv1alpha1.MaaSModelSpec) error {
+	for _, t := range []struct {
+		field string
+		d     *metav1.Duration
+	}{
+		{"spec.requestTimeout", spec.RequestTimeout},
+		{"spec.streamIdleTimeout", spec.StreamIdleTimeout},
+	} {
+		if t.d != nil && t.d.Duration < 0 {
+			return fmt.Errorf("%s %s must not be negative", t.field, t.d.Duration)
+		}
+	}
+	return nil
+}
+
+// envoyDuration formats d as a protobuf JSON duration, e.g. "90s" or "0.5s".
+func envoyDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// reconcileTimeouts applies the model's spec.requestTimeout and spec.streamIdleTimeout to its
+// routes on the gateway through an EnvoyFilter, setting the Envoy route's timeout and idle_timeout.
+// The filter is deleted once the model sets neither. An EnvoyFilter rather than the HTTPRoute's
+// timeouts covers routes KServe owns, and Gateway API has no stream idle timeout.
+func (r *MaaSModelRefReconciler) reconcileTimeouts(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	if model.Spec.RequestTimeout == nil && model.Spec.StreamIdleTimeout == nil {
+		return r.deleteTimeoutsFilter(ctx, log, model)
+	}
+	if err := ValidateTimeouts(model.Spec); err != nil {
+		return err
+	}
+
+	action := map[string]any{}
+	if d := model.Spec.RequestTimeout; d != nil {
+		action["timeout"] = envoyDuration(d.Duration)
+	}
+	if d := model.Spec.StreamIdleTimeout; d != nil {
+		action["idle_timeout"] = envoyDuration(d.Duration)
+	}
+	return r.reconcileModelRouteFilter(ctx, log, model, modelRouteFilter{
+		what:      "timeouts",
+		field:     "spec.requestTimeout and spec.streamIdleTimeout",
+		name:      timeoutsFilterName(model),
+		component: "model-timeouts",
+	}, map[string]any{"route": action})
+}
+
+// deleteTimeoutsFilter deletes the model's timeouts EnvoyFilter, if the controller generated one.
+func (r *MaaSModelRefReconciler) deleteTimeoutsFilter(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	return r.deleteModelRouteFilter(ctx, log, "timeouts", r.targetGatewayNamespace(model), timeoutsFilterName(model))
+}
@@ -0,0 +1,81 @@
+package maas
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestMaaSModelRefReconciler_Timeouts(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+	model.Spec.RequestTimeout = &metav1.Duration{Duration: 10 * time.Minute}
+	model.Spec.StreamIdleTimeout = &metav1.Duration{Duration: 1500 * time.Millisecond}
+	route := newLLMISvcRoute("llama", ns)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{}, {}}
+	r, c := newTestReconciler(model, route, newLLMISvc("llama", ns, corev1.ConditionTrue))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	filter := &unstructured.Unstructured{}
+	filter.SetGroupVersionKind(envoyFilterGVK)
+	key := types.NamespacedName{Name: "maas-timeouts-llm-m", Namespace: defaultGatewayNamespace}
+	if err := c.Get(ctx, key, filter); err != nil {
+		t.Fatalf("timeouts EnvoyFilter not created: %v", err)
+	}
+	patches, _, _ := unstructured.NestedSlice(filter.Object, "spec", "configPatches")
+	if len(patches) != 2 {
+		t.Fatalf("configPatches = %d, want one per route rule", len(patches))
+	}
+	for i, want := range []string{"llm.llama-route.0", "llm.llama-route.1"} {
+		patch := patches[i].(map[string]any)
+		if name, _, _ := unstructured.NestedString(patch, "match", "routeConfiguration", "vhost", "route", "name"); name != want {
+			t.Errorf("patch %d route = %q, want %q", i, name, want)
+		}
+		if got, _, _ := unstructured.NestedString(patch, "patch", "value", "route", "timeout"); got != "600s" {
+			t.Errorf("patch %d timeout = %q, want 600s", i, got)
+		}
+		if got, _, _ := unstructured.NestedString(patch, "patch", "value", "route", "idle_timeout"); got != "1.5s" {
+			t.Errorf("patch %d idle_timeout = %q, want 1.5s", i, got)
+		}
+	}
+
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got.Spec.RequestTimeout, got.Spec.StreamIdleTimeout = nil, nil
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, key, filter); !apierrors.IsNotFound(err) {
+		t.Errorf("timeouts EnvoyFilter after removing the timeouts: err = %v, want NotFound", err)
+	}
+}
+
+func TestValidateTimeouts(t *testing.T) {
+	spec := maasv1alpha1.MaaSModelSpec{RequestTimeout: &metav1.Duration{}}
+	if err := ValidateTimeouts(spec); err != nil {
+		t.Errorf("0s disables the timeout, got %v", err)
+	}
+	spec.StreamIdleTimeout = &metav1.Duration{Duration: -time.Second}
+	if err := ValidateTimeouts(spec); err == nil {
+		t.Error("negative stream idle timeout accepted")
+	}
+}
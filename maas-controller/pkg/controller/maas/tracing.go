@@ -0,0 +1,72 @@
+package maas
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+//+kubebuilder:rbac:groups=networking.istio.io,resources=envoyfilters,verbs=get;list;watch;create;update;patch;delete
+
+var envoyFilterGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "EnvoyFilter"}
+
+// traceContextHeaders are the W3C and B3 trace context headers the gateway removes from the
+// requests of a model that does not propagate the caller's trace context.
+var traceContextHeaders = []any{
+	"traceparent", "tracestate",
+	"b3", "x-b3-traceid", "x-b3-spanid", "x-b3-parentspanid", "x-b3-sampled", "x-b3-flags",
+}
+
+// tracingFilterName is the name of the EnvoyFilter that carries a model's tracing policy. It lives
+// in the gateway namespace, so it includes the model namespace.
+func tracingFilterName(model *maasv1alpha1.MaaSModelRef) string {
+	return "maas-tracing-" + model.Namespace + "-" + model.Name
+}
+
+// gatewayTracing reports whether the model's tracing policy changes anything at the gateway.
+// StripSensitiveAttributes only concerns maas-api.
+func gatewayTracing(tracing *maasv1alpha1.ModelTracing) bool {
+	return tracing != nil && (tracing.SamplingPercentage != "" || (tracing.Propagate != nil && !*tracing.Propagate))
+}
+
+// reconcileTracing applies the model's spec.tracing to its routes on the gateway through an
+// EnvoyFilter, and deletes the filter once the model no longer sets gateway tracing.
+func (r *MaaSModelRefReconciler) reconcileTracing(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	if !gatewayTracing(model.Spec.Tracing) {
+		return r.deleteTracingFilter(ctx, log, model)
+	}
+
+	patch := map[string]any{}
+	if pct := model.Spec.Tracing.SamplingPercentage; pct != "" {
+		value, err := strconv.ParseFloat(pct, 64)
+		if err != nil || value < 0 || value > 100 {
+			return fmt.Errorf("invalid tracing samplingPercentage %q", pct)
+		}
+		patch["tracing"] = map[string]any{
+			"random_sampling": map[string]any{
+				"numerator":   int64(math.Round(value * 10000)),
+				"denominator": "MILLION",
+			},
+		}
+	}
+	if p := model.Spec.Tracing.Propagate; p != nil && !*p {
+		patch["request_headers_to_remove"] = traceContextHeaders
+	}
+	return r.reconcileModelRouteFilter(ctx, log, model, modelRouteFilter{
+		what:      "tracing",
+		field:     "spec.tracing",
+		name:      tracingFilterName(model),
+		component: "model-tracing",
+	}, patch)
+}
+
+// deleteTracingFilter deletes the model's tracing EnvoyFilter, if the controller generated one.
+func (r *MaaSModelRefReconciler) deleteTracingFilter(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef) error {
+	return r.deleteModelRouteFilter(ctx, log, "tracing", r.targetGatewayNamespace(model), tracingFilterName(model))
+}
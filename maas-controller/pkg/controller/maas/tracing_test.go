@@ -0,0 +1,123 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package maas
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestMaaSModelRefReconciler_Tracing(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+	model.Spec.Tracing = &maasv1alpha1.ModelTracing{SamplingPercentage: "0.5", Propagate: ptr.To(false)}
+	route := newLLMISvcRoute("llama", ns)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{}, {}}
+	r, c := newTestReconciler(model, route, newLLMISvc("llama", ns, corev1.ConditionTrue))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	filter := &unstructured.Unstructured{}
+	filter.SetGroupVersionKind(envoyFilterGVK)
+	key := types.NamespacedName{Name: "maas-tracing-llm-m", Namespace: defaultGatewayNamespace}
+	if err := c.Get(ctx, key, filter); err != nil {
+		t.Fatalf("tracing EnvoyFilter not created: %v", err)
+	}
+	if got := filter.GetLabels()[managedByLabel]; got != managedByValue {
+		t.Errorf("managed-by label = %q, want %q", got, managedByValue)
+	}
+	gateway, _, _ := unstructured.NestedString(filter.Object, "spec", "workloadSelector", "labels", "gateway.networking.k8s.io/gateway-name")
+	if gateway != defaultGatewayName {
+		t.Errorf("workloadSelector gateway = %q, want %q", gateway, defaultGatewayName)
+	}
+	patches, _, _ := unstructured.NestedSlice(filter.Object, "spec", "configPatches")
+	if len(patches) != 2 {
+		t.Fatalf("configPatches = %d, want one per route rule", len(patches))
+	}
+	for i, want := range []string{"llm.llama-route.0", "llm.llama-route.1"} {
+		patch := patches[i].(map[string]any)
+		if name, _, _ := unstructured.NestedString(patch, "match", "routeConfiguration", "vhost", "route", "name"); name != want {
+			t.Errorf("patch %d route = %q, want %q", i, name, want)
+		}
+		if n, _, _ := unstructured.NestedInt64(patch, "patch", "value", "tracing", "random_sampling", "numerator"); n != 5000 {
+			t.Errorf("patch %d sampling numerator = %d, want 5000 (0.5%% of a million)", i, n)
+		}
+		if headers, _, _ := unstructured.NestedStringSlice(patch, "patch", "value", "request_headers_to_remove"); len(headers) == 0 || headers[0] != "traceparent" {
+			t.Errorf("patch %d request_headers_to_remove = %v, want the trace context headers", i, headers)
+		}
+	}
+
+	// Only stripping attributes concerns maas-api alone, so the gateway filter goes away.
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got.Spec.Tracing = &maasv1alpha1.ModelTracing{StripSensitiveAttributes: true}
+	if err := c.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	if err := c.Get(ctx, key, filter); !apierrors.IsNotFound(err) {
+		t.Errorf("tracing EnvoyFilter after removing gateway settings: err = %v, want NotFound", err)
+	}
+}
+
+func TestMaaSModelRefReconciler_TracingFilterDeletedWithModel(t *testing.T) {
+	ctx := context.Background()
+	const ns = "llm"
+	model := newMaaSModelRef("m", ns, "LLMInferenceService", "llama")
+	model.Spec.Tracing = &maasv1alpha1.ModelTracing{SamplingPercentage: "100"}
+	route := newLLMISvcRoute("llama", ns)
+	route.Spec.Rules = []gatewayapiv1.HTTPRouteRule{{}}
+	r, c := newTestReconciler(model, route, newLLMISvc("llama", ns, corev1.ConditionTrue))
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "m", Namespace: ns}}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile: %v", err)
+	}
+	got := &maasv1alpha1.MaaSModelRef{}
+	if err := c.Get(ctx, req.NamespacedName, got); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := c.Delete(ctx, got); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile deletion: %v", err)
+	}
+	filter := &unstructured.Unstructured{}
+	filter.SetGroupVersionKind(envoyFilterGVK)
+	err := c.Get(ctx, types.NamespacedName{Name: "maas-tracing-llm-m", Namespace: defaultGatewayNamespace}, filter)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("tracing EnvoyFilter after model deletion: err = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,39 @@
+package fips
+
+import (
+	"crypto/fips140"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	status, err := Verify(false)
+	require.NoError(t, err)
+	assert.Equal(t, "passed", status.SelfCheck)
+	assert.Equal(t, fips140.Enabled(), status.Enabled)
+
+	_, err = Verify(true)
+	if fips140.Enabled() {
+		require.NoError(t, err)
+	} else {
+		require.ErrorContains(t, err, "FIPS mode required")
+	}
+}
+
+func TestBuildInfoHandler(t *testing.T) {
+	status, err := Verify(false)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	NewBuildInfo("v1.2.3", "abc123", status).Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/v1/buildinfo", nil))
+
+	var got BuildInfo
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&got))
+	assert.Equal(t, "v1.2.3", got.Version)
+	assert.Equal(t, "abc123", got.Commit)
+	assert.Equal(t, "passed", got.Crypto.SelfCheck)
+}
@@ -0,0 +1,422 @@
+// Package keycloak keeps Keycloak in sync with the MaaS tiers (MaaSSubscriptions) through the
+// admin REST API. Each tier becomes a client role of the MaaS OIDC client, granted to the tier's
+// owner groups, and a protocol mapper on the client emits the caller's tiers as a token claim.
+package keycloak
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTierClaim is the token claim listing the caller's tiers.
+	DefaultTierClaim = "maas_tiers"
+	// SubscriptionAttribute marks the client roles maas-controller manages with the
+	// "namespace/name" of their MaaSSubscription. Roles without it are never changed or deleted.
+	SubscriptionAttribute = "maas.opendatahub.io/subscription"
+
+	tierMapperName = "maas-tiers"
+)
+
+var (
+	// ErrNotManaged is returned when a client role named after a tier exists but was not created
+	// for that tier.
+	ErrNotManaged = errors.New("keycloak: role exists and is not managed by maas-controller")
+	// ErrClientNotFound is returned when the MaaS OIDC client does not exist in the realm.
+	ErrClientNotFound = errors.New("keycloak: client not found")
+
+	errNotFound = errors.New("keycloak: not found")
+)
+
+// Config configures a Client.
+type Config struct {
+	// URL is Keycloak's base URL, e.g. https://keycloak.example.com.
+	URL string
+	// Realm holds the MaaS client, the groups and the tier roles.
+	Realm string
+	// ClientID is the client ID of the MaaS OIDC client that tier roles are created on.
+	ClientID string
+	// TierClaim is the claim the tier mapper emits. Empty defaults to DefaultTierClaim.
+	TierClaim string
+	// AdminClientID and AdminSecretFile are the credentials of a confidential client in Realm
+	// whose service account has the realm-management roles manage-clients and manage-users.
+	AdminClientID   string
+	AdminSecretFile string
+}
+
+// Tier is the Keycloak side of a MaaS tier.
+type Tier struct {
+	// Name is the client role name, the tier's name in the claim.
+	Name string
+	// Subscription is the "namespace/name" of the MaaSSubscription.
+	Subscription string
+	// Groups are the Keycloak groups granted the tier.
+	Groups []string
+}
+
+type role struct {
+	ID          string              `json:"id,omitempty"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Attributes  map[string][]string `json:"attributes,omitempty"`
+}
+
+func (r role) subscription() string {
+	if values := r.Attributes[SubscriptionAttribute]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+type group struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	SubGroups []group `json:"subGroups,omitempty"`
+}
+
+type protocolMapper struct {
+	ID             string            `json:"id,omitempty"`
+	Name           string            `json:"name"`
+	Protocol       string            `json:"protocol"`
+	ProtocolMapper string            `json:"protocolMapper"`
+	Config         map[string]string `json:"config"`
+}
+
+// Client provisions tiers in Keycloak. It is safe for concurrent use.
+type Client struct {
+	cfg        Config
+	address    string
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	clientUUID  string
+}
+
+// NewClient creates a Client for cfg.
+func NewClient(cfg Config) *Client {
+	if cfg.TierClaim == "" {
+		cfg.TierClaim = DefaultTierClaim
+	}
+	return &Client{
+		cfg:        cfg,
+		address:    strings.TrimRight(cfg.URL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+	}
+}
+
+// EnsureTier creates the tier's client role, grants it to exactly tier.Groups and
+// makes sure the client has the tier mapper. It returns the groups that do not exist in the realm;
+// they are skipped.
+func (c *Client) EnsureTier(ctx context.Context, tier Tier) ([]string, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.ensureMapper(ctx, client); err != nil {
+		return nil, err
+	}
+
+	rolePath := c.adminPath("clients", client, "roles", tier.Name)
+	desired := role{
+		Name:        tier.Name,
+		Description: "MaaS tier of MaaSSubscription " + tier.Subscription,
+		Attributes:  map[string][]string{SubscriptionAttribute: {tier.Subscription}},
+	}
+	var existing role
+	switch err := c.do(ctx, http.MethodGet, rolePath, nil, &existing); {
+	case errors.Is(err, errNotFound):
+		if err := c.do(ctx, http.MethodPost, c.adminPath("clients", client, "roles"), desired, nil); err != nil {
+			return nil, fmt.Errorf("failed to create role %s: %w", tier.Name, err)
+		}
+		if err := c.do(ctx, http.MethodGet, rolePath, nil, &existing); err != nil {
+			return nil, fmt.Errorf("failed to get role %s: %w", tier.Name, err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get role %s: %w", tier.Name, err)
+	case existing.subscription() != tier.Subscription:
+		return nil, fmt.Errorf("%w: %s", ErrNotManaged, tier.Name)
+	}
+
+	return c.syncGroups(ctx, client, existing, tier)
+}
+
+// syncGroups grants the role to the tier's groups and revokes it from any other group.
+func (c *Client) syncGroups(ctx context.Context, client string, r role, tier Tier) ([]string, error) {
+	var granted []group
+	if err := c.do(ctx, http.MethodGet, c.adminPath("clients", client, "roles", r.Name, "groups"), nil, &granted); err != nil {
+		return nil, fmt.Errorf("failed to list groups of role %s: %w", r.Name, err)
+	}
+	grantedByName := make(map[string]string, len(granted))
+	for _, g := range granted {
+		grantedByName[g.Name] = g.ID
+	}
+
+	var missing []string
+	wanted := make(map[string]bool, len(tier.Groups))
+	for _, name := range tier.Groups {
+		wanted[name] = true
+		if _, ok := grantedByName[name]; ok {
+			continue
+		}
+		id, err := c.groupID(ctx, name)
+		if errors.Is(err, errNotFound) {
+			missing = append(missing, name)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := c.do(ctx, http.MethodPost, c.adminPath("groups", id, "role-mappings", "clients", client), []role{r}, nil); err != nil {
+			return nil, fmt.Errorf("failed to grant role %s to group %s: %w", r.Name, name, err)
+		}
+	}
+	for name, id := range grantedByName {
+		if wanted[name] {
+			continue
+		}
+		if err := c.do(ctx, http.MethodDelete, c.adminPath("groups", id, "role-mappings", "clients", client), []role{r}, nil); err != nil {
+			return nil, fmt.Errorf("failed to revoke role %s from group %s: %w", r.Name, name, err)
+		}
+	}
+	return missing, nil
+}
+
+// DeleteTier deletes the tier's client role, which also revokes it from every group. Roles
+// that do not exist or belong to another subscription are left alone.
+func (c *Client) DeleteTier(ctx context.Context, name, subscription string) error {
+	client, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	rolePath := c.adminPath("clients", client, "roles", name)
+	var existing role
+	if err := c.do(ctx, http.MethodGet, rolePath, nil, &existing); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get role %s: %w", name, err)
+	}
+	if existing.subscription() != subscription {
+		return nil
+	}
+	if err := c.do(ctx, http.MethodDelete, rolePath, nil, nil); err != nil && !errors.Is(err, errNotFound) {
+		return fmt.Errorf("failed to delete role %s: %w", name, err)
+	}
+	return nil
+}
+
+// client returns the internal ID of the MaaS client, looked up once.
+func (c *Client) client(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	id := c.clientUUID
+	c.mu.Unlock()
+	if id != "" {
+		return id, nil
+	}
+	var clients []struct {
+		ID       string `json:"id"`
+		ClientID string `json:"clientId"`
+	}
+	query := "?clientId=" + url.QueryEscape(c.cfg.ClientID)
+	if err := c.do(ctx, http.MethodGet, c.adminPath("clients")+query, nil, &clients); err != nil {
+		return "", fmt.Errorf("failed to look up client %s: %w", c.cfg.ClientID, err)
+	}
+	for _, cl := range clients {
+		if cl.ClientID == c.cfg.ClientID {
+			c.mu.Lock()
+			c.clientUUID = cl.ID
+			c.mu.Unlock()
+			return cl.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s in realm %s", ErrClientNotFound, c.cfg.ClientID, c.cfg.Realm)
+}
+
+// ensureMapper adds the mapper emitting the client's roles, i.e. the caller's tiers, as
+// TierClaim, or updates it when its configuration drifted.
+func (c *Client) ensureMapper(ctx context.Context, client string) error {
+	desired := protocolMapper{
+		Name:           tierMapperName,
+		Protocol:       "openid-connect",
+		ProtocolMapper: "oidc-usermodel-client-role-mapper",
+		Config: map[string]string{
+			"usermodel.clientRoleMapping.clientId": c.cfg.ClientID,
+			"claim.name":                           c.cfg.TierClaim,
+			"jsonType.label":                       "String",
+			"multivalued":                          "true",
+			"id.token.claim":                       "true",
+			"access.token.claim":                   "true",
+			"userinfo.token.claim":                 "true",
+		},
+	}
+	mappersPath := c.adminPath("clients", client, "protocol-mappers", "models")
+	var mappers []protocolMapper
+	if err := c.do(ctx, http.MethodGet, mappersPath, nil, &mappers); err != nil {
+		return fmt.Errorf("failed to list protocol mappers: %w", err)
+	}
+	for _, m := range mappers {
+		if m.Name != tierMapperName {
+			continue
+		}
+		if m.ProtocolMapper == desired.ProtocolMapper && configContains(m.Config, desired.Config) {
+			return nil
+		}
+		desired.ID = m.ID
+		if err := c.do(ctx, http.MethodPut, c.adminPath("clients", client, "protocol-mappers", "models", m.ID), desired, nil); err != nil {
+			return fmt.Errorf("failed to update protocol mapper %s: %w", tierMapperName, err)
+		}
+		return nil
+	}
+	if err := c.do(ctx, http.MethodPost, mappersPath, desired, nil); err != nil {
+		return fmt.Errorf("failed to create protocol mapper %s: %w", tierMapperName, err)
+	}
+	return nil
+}
+
+func configContains(have, want map[string]string) bool {
+	for k, v := range want {
+		if have[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// groupID returns the ID of the group named name, searching subgroups too.
+func (c *Client) groupID(ctx context.Context, name string) (string, error) {
+	var groups []group
+	query := "?exact=true&search=" + url.QueryEscape(name)
+	if err := c.do(ctx, http.MethodGet, c.adminPath("groups")+query, nil, &groups); err != nil {
+		return "", fmt.Errorf("failed to look up group %s: %w", name, err)
+	}
+	if id := findGroup(groups, name); id != "" {
+		return id, nil
+	}
+	return "", errNotFound
+}
+
+func findGroup(groups []group, name string) string {
+	for _, g := range groups {
+		if g.Name == name {
+			return g.ID
+		}
+		if id := findGroup(g.SubGroups, name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func (c *Client) adminPath(segments ...string) string {
+	escaped := make([]string, 0, len(segments)+3)
+	escaped = append(escaped, "", "admin", "realms", url.PathEscape(c.cfg.Realm))
+	for _, s := range segments {
+		escaped = append(escaped, url.PathEscape(s))
+	}
+	return strings.Join(escaped, "/")
+}
+
+// accessToken returns a cached admin token, fetching a new one with the client credentials
+// grant once it is within a minute of expiring.
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if c.token != "" && now.Before(c.tokenExpiry) {
+		return c.token, nil
+	}
+
+	secret, err := os.ReadFile(c.cfg.AdminSecretFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read admin client secret: %w", err)
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.cfg.AdminClientID},
+		"client_secret": {strings.TrimSpace(string(secret))},
+	}
+	tokenURL := c.address + "/realms/" + url.PathEscape(c.cfg.Realm) + "/protocol/openid-connect/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get admin token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("failed to get admin token: keycloak returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("failed to decode admin token: %w", err)
+	}
+	c.token = tok.AccessToken
+	c.tokenExpiry = now.Add(time.Duration(tok.ExpiresIn)*time.Second - time.Minute)
+	return c.token, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body any, out any) error {
+	tok, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.address+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode == http.StatusUnauthorized:
+		// The token was revoked or the realm's keys rotated; fetch a new one next time.
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+		fallthrough
+	case resp.StatusCode/100 != 2:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("keycloak returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,171 @@
+package keycloak
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeycloak serves the admin API for one realm with the client "maas" and the groups
+// "premium-users" and "free-users".
+type fakeKeycloak struct {
+	tokens atomic.Int32
+
+	mu      sync.Mutex
+	roles   map[string]role
+	grants  map[string]map[string]bool // role -> group IDs
+	mappers []protocolMapper
+}
+
+var fakeGroups = map[string]string{"g-premium": "premium-users", "g-free": "free-users"}
+
+func (f *fakeKeycloak) handler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		write := func(v any) { _ = json.NewEncoder(w).Encode(v) }
+
+		if r.URL.Path == "/realms/maas/protocol/openid-connect/token" {
+			require.NoError(t, r.ParseForm())
+			if r.PostForm.Get("client_id") != "maas-controller" || r.PostForm.Get("client_secret") != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			f.tokens.Add(1)
+			write(map[string]any{"access_token": "admin-token", "expires_in": 300})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer admin-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path := strings.TrimPrefix(r.URL.Path, "/admin/realms/maas/")
+		parts := strings.Split(path, "/")
+		switch {
+		case path == "clients":
+			write([]map[string]string{{"id": "c-uuid", "clientId": r.URL.Query().Get("clientId")}})
+		case path == "clients/c-uuid/protocol-mappers/models" && r.Method == http.MethodGet:
+			write(f.mappers)
+		case path == "clients/c-uuid/protocol-mappers/models" && r.Method == http.MethodPost:
+			var m protocolMapper
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&m))
+			m.ID = "m-1"
+			f.mappers = append(f.mappers, m)
+			w.WriteHeader(http.StatusCreated)
+		case path == "clients/c-uuid/roles" && r.Method == http.MethodPost:
+			var created role
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			created.ID = "r-" + created.Name
+			f.roles[created.Name] = created
+			w.WriteHeader(http.StatusCreated)
+		case len(parts) == 4 && parts[2] == "roles":
+			existing, ok := f.roles[parts[3]]
+			switch {
+			case !ok:
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == http.MethodDelete:
+				delete(f.roles, parts[3])
+				delete(f.grants, parts[3])
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				write(existing)
+			}
+		case len(parts) == 5 && parts[2] == "roles" && parts[4] == "groups":
+			var groups []group
+			for id := range f.grants[parts[3]] {
+				groups = append(groups, group{ID: id, Name: fakeGroups[id]})
+			}
+			write(groups)
+		case path == "groups":
+			var groups []group
+			for id, name := range fakeGroups {
+				if name == r.URL.Query().Get("search") {
+					groups = append(groups, group{ID: id, Name: name})
+				}
+			}
+			write(groups)
+		case len(parts) == 5 && parts[0] == "groups" && parts[2] == "role-mappings":
+			var roles []role
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&roles))
+			for _, granted := range roles {
+				if f.grants[granted.Name] == nil {
+					f.grants[granted.Name] = map[string]bool{}
+				}
+				if r.Method == http.MethodDelete {
+					delete(f.grants[granted.Name], parts[1])
+				} else {
+					f.grants[granted.Name][parts[1]] = true
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeKeycloak) {
+	t.Helper()
+	f := &fakeKeycloak{roles: map[string]role{}, grants: map[string]map[string]bool{}}
+	server := httptest.NewServer(f.handler(t))
+	t.Cleanup(server.Close)
+
+	secretFile := filepath.Join(t.TempDir(), "client-secret")
+	require.NoError(t, os.WriteFile(secretFile, []byte("s3cret\n"), 0o600))
+	return NewClient(Config{
+		URL:             server.URL,
+		Realm:           "maas",
+		ClientID:        "maas",
+		AdminClientID:   "maas-controller",
+		AdminSecretFile: secretFile,
+	}), f
+}
+
+func TestEnsureTier(t *testing.T) {
+	ctx := context.Background()
+	c, f := newTestClient(t)
+
+	missing, err := c.EnsureTier(ctx, Tier{Name: "premium", Subscription: "models-as-a-service/premium", Groups: []string{"premium-users", "ghosts"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghosts"}, missing)
+	assert.Equal(t, "models-as-a-service/premium", f.roles["premium"].subscription())
+	assert.Equal(t, map[string]bool{"g-premium": true}, f.grants["premium"])
+	require.Len(t, f.mappers, 1)
+	assert.Equal(t, DefaultTierClaim, f.mappers[0].Config["claim.name"])
+	assert.Equal(t, "maas", f.mappers[0].Config["usermodel.clientRoleMapping.clientId"])
+
+	// Owner groups changed: the role moves to the new group, and the mapper is not duplicated.
+	missing, err = c.EnsureTier(ctx, Tier{Name: "premium", Subscription: "models-as-a-service/premium", Groups: []string{"free-users"}})
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+	assert.Equal(t, map[string]bool{"g-free": true}, f.grants["premium"])
+	assert.Len(t, f.mappers, 1)
+	assert.Equal(t, int32(1), f.tokens.Load(), "the admin token is cached")
+
+	require.NoError(t, c.DeleteTier(ctx, "premium", "models-as-a-service/premium"))
+	assert.NotContains(t, f.roles, "premium")
+}
+
+func TestEnsureTier_LeavesUnmanagedRoles(t *testing.T) {
+	ctx := context.Background()
+	c, f := newTestClient(t)
+	f.roles["admin"] = role{ID: "r-admin", Name: "admin"}
+
+	_, err := c.EnsureTier(ctx, Tier{Name: "admin", Subscription: "models-as-a-service/admin"})
+	require.ErrorIs(t, err, ErrNotManaged)
+
+	require.NoError(t, c.DeleteTier(ctx, "admin", "models-as-a-service/admin"))
+	assert.Contains(t, f.roles, "admin", "roles not created for the subscription are never deleted")
+	require.NoError(t, c.DeleteTier(ctx, "missing", "models-as-a-service/missing"))
+}
@@ -0,0 +1,79 @@
+package externalmodel
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// defaultAuthHeader is the header of spec.auth type APIKey when spec.auth.header is unset.
+const defaultAuthHeader = "x-api-key"
+
+// AuthHeader returns the header carrying the credential of an ExternalModel's spec.auth. The
+// gateway's AuthPolicy sets it on each request from maas-api, so the credential itself never
+// appears in a generated resource.
+func AuthHeader(auth *maasv1alpha1.ExternalModelAuth) string {
+	if auth.Type == maasv1alpha1.ExternalModelAuthAPIKey {
+		if auth.Header != "" {
+			return auth.Header
+		}
+		return defaultAuthHeader
+	}
+	return "Authorization"
+}
+
+// checkCredential reads the Secret of an ExternalModel's spec.auth and reports a missing Secret
+// or key, so a model whose credential cannot be injected is not routed.
+func (r *Reconciler) checkCredential(ctx context.Context, extModel *maasv1alpha1.ExternalModel) error {
+	auth := extModel.Spec.Auth
+	secretName := extModel.Spec.CredentialRef.Name
+	if auth.SecretRef != nil {
+		secretName = auth.SecretRef.Name
+	}
+	secret := &corev1.Secret{}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: secretName, Namespace: extModel.Namespace}, secret); err != nil {
+		return fmt.Errorf("failed to get auth Secret %s/%s: %w", extModel.Namespace, secretName, err)
+	}
+	var keys []string
+	switch auth.Type {
+	case maasv1alpha1.ExternalModelAuthAPIKey, maasv1alpha1.ExternalModelAuthBearer:
+		key := auth.Key
+		if key == "" {
+			key = CredentialKey
+		}
+		keys = []string{key}
+	case maasv1alpha1.ExternalModelAuthBasic:
+		keys = []string{corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey}
+	default:
+		return fmt.Errorf("unsupported auth type %q on ExternalModel %s", auth.Type, extModel.Name)
+	}
+	for _, key := range keys {
+		if len(secret.Data[key]) == 0 {
+			return fmt.Errorf("auth Secret %s/%s has no %q key", extModel.Namespace, secretName, key)
+		}
+	}
+	return nil
+}
+
+// externalModelRefs maps an ExternalModel to the MaaSModelRefs referencing it, so changes to
+// spec.auth and the provider reach their routes.
+func (r *Reconciler) externalModelRefs(ctx context.Context, obj client.Object) []reconcile.Request {
+	var models maasv1alpha1.MaaSModelRefList
+	if err := r.List(ctx, &models, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list MaaSModelRefs for ExternalModel", "name", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, m := range models.Items {
+		if m.Spec.ModelRef.Kind == "ExternalModel" && m.Spec.ModelRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: m.Name, Namespace: m.Namespace}})
+		}
+	}
+	return requests
+}
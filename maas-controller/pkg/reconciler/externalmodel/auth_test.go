@@ -0,0 +1,71 @@
+package externalmodel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestCheckCredential(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, maasv1alpha1.AddToScheme(scheme))
+	secret := func(name string, data map[string]string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "llm"}, Data: map[string][]byte{}}
+		for k, v := range data {
+			s.Data[k] = []byte(v)
+		}
+		return s
+	}
+	r := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		secret("openai", map[string]string{"api-key": "sk-1", "token": "tok"}),
+		secret("basic", map[string]string{"username": "maas", "password": "pw"}),
+	).Build()}
+
+	tests := []struct {
+		name       string
+		auth       maasv1alpha1.ExternalModelAuth
+		wantHeader string
+		wantErr    bool
+	}{
+		{name: "api key defaults", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthAPIKey},
+			wantHeader: "x-api-key"},
+		{name: "api key header", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthAPIKey, Header: "api-key"},
+			wantHeader: "api-key"},
+		{name: "bearer with key", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthBearer, Key: "token"},
+			wantHeader: "Authorization"},
+		{name: "basic", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthBasic,
+			SecretRef: &maasv1alpha1.CredentialReference{Name: "basic"}},
+			wantHeader: "Authorization"},
+		{name: "missing key", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthBearer, Key: "nope"}, wantErr: true},
+		{name: "missing basic password", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthBasic}, wantErr: true},
+		{name: "missing secret", auth: maasv1alpha1.ExternalModelAuth{Type: maasv1alpha1.ExternalModelAuthBearer,
+			SecretRef: &maasv1alpha1.CredentialReference{Name: "absent"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extModel := &maasv1alpha1.ExternalModel{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpt4", Namespace: "llm"},
+				Spec: maasv1alpha1.ExternalModelSpec{
+					CredentialRef: maasv1alpha1.CredentialReference{Name: "openai"},
+					Auth:          &tt.auth,
+				},
+			}
+			err := r.checkCredential(context.Background(), extModel)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, AuthHeader(&tt.auth))
+		})
+	}
+}
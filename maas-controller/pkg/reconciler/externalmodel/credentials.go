@@ -0,0 +1,212 @@
+package externalmodel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/vault"
+)
+
+const (
+	// CredentialKey is the Secret data key downstream consumers read the provider API key from.
+	CredentialKey = "api-key"
+
+	// ConditionCredentialsReady reports whether the Vault-sourced credential Secret is in sync.
+	ConditionCredentialsReady = "CredentialsReady"
+)
+
+// VaultReader reads secrets from Vault. It is implemented by *vault.Client.
+type VaultReader interface {
+	Read(ctx context.Context, role, path string) (*vault.Secret, error)
+}
+
+// CredentialReconciler keeps the Secret named by an ExternalModel's spec.credentialRef in sync
+// with the Vault path in spec.vaultRef, so consumers of the Secret do not need Vault access.
+// It requeues each ExternalModel before its Vault lease is due for renewal.
+type CredentialReconciler struct {
+	client.Client
+	// APIReader reads Secrets without caching them; defaults to Client when nil.
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	Log       logr.Logger
+	// Vault is nil when the controller runs without --vault-address.
+	Vault VaultReader
+
+	now func() time.Time
+}
+
+func (r *CredentialReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+func (r *CredentialReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=externalmodels,verbs=get;list;watch
+//+kubebuilder:rbac:groups=maas.opendatahub.io,resources=externalmodels/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
+
+// Reconcile syncs the credential Secret of an ExternalModel with spec.vaultRef.
+func (r *CredentialReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("externalmodel", req.NamespacedName)
+
+	extModel := &maasv1alpha1.ExternalModel{}
+	if err := r.Get(ctx, req.NamespacedName, extModel); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !extModel.GetDeletionTimestamp().IsZero() || extModel.Spec.VaultRef == nil {
+		return ctrl.Result{}, nil
+	}
+	ref := extModel.Spec.VaultRef
+
+	if r.Vault == nil {
+		return ctrl.Result{}, r.setCondition(ctx, extModel, metav1.ConditionFalse, "VaultNotConfigured",
+			"spec.vaultRef is set but the controller was started without --vault-address")
+	}
+
+	secret, err := r.Vault.Read(ctx, ref.Role, ref.Path)
+	if err != nil {
+		log.Error(err, "Failed to read credential from Vault", "path", ref.Path, "role", ref.Role)
+		if statusErr := r.setCondition(ctx, extModel, metav1.ConditionFalse, "VaultReadFailed", err.Error()); statusErr != nil {
+			log.Error(statusErr, "Failed to update ExternalModel status")
+		}
+		return ctrl.Result{}, err
+	}
+	key := ref.Key
+	if key == "" {
+		key = CredentialKey
+	}
+	value, ok := secret.Data[key]
+	if !ok || value == "" {
+		// The field may be added to the Vault secret later; check again on the next refresh.
+		return ctrl.Result{RequeueAfter: vault.DefaultRefresh}, r.setCondition(ctx, extModel, metav1.ConditionFalse, "KeyNotFound",
+			fmt.Sprintf("Vault path %s has no field %q", ref.Path, key))
+	}
+
+	if err := r.applyCredentialSecret(ctx, log, extModel, value); err != nil {
+		if statusErr := r.setCondition(ctx, extModel, metav1.ConditionFalse, "SecretSyncFailed", err.Error()); statusErr != nil {
+			log.Error(statusErr, "Failed to update ExternalModel status")
+		}
+		return ctrl.Result{}, err
+	}
+	if err := r.setCondition(ctx, extModel, metav1.ConditionTrue, "Synced",
+		fmt.Sprintf("Secret %s synced from Vault path %s", extModel.Spec.CredentialRef.Name, ref.Path)); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: secret.RefreshAfter(r.clock())}, nil
+}
+
+// BuildCredentialSecret creates the Secret named by spec.credentialRef holding value under CredentialKey.
+func BuildCredentialSecret(extModel *maasv1alpha1.ExternalModel, value string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      extModel.Spec.CredentialRef.Name,
+			Namespace: extModel.Namespace,
+			Labels:    commonLabels(extModel.Name),
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{CredentialKey: []byte(value)},
+	}
+}
+
+func (r *CredentialReconciler) applyCredentialSecret(ctx context.Context, log logr.Logger, extModel *maasv1alpha1.ExternalModel, value string) error {
+	desired := BuildCredentialSecret(extModel, value)
+	if err := controllerutil.SetControllerReference(extModel, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner on Secret %s: %w", desired.Name, err)
+	}
+
+	existing := &corev1.Secret{}
+	err := r.apiReader().Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		log.Info("Creating credential Secret", "name", desired.Name)
+		return r.Create(ctx, desired)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get Secret %s: %w", desired.Name, err)
+	}
+	if !canManage(existing) {
+		return fmt.Errorf("secret %s exists but is not managed by this reconciler; set annotation %s=true to let Vault manage it",
+			desired.Name, AnnAdopt)
+	}
+	if equality.Semantic.DeepEqual(existing.Data, desired.Data) && existing.Labels["app.kubernetes.io/managed-by"] == ManagedBy {
+		return nil
+	}
+	existing.Data = desired.Data
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		existing.Labels[k] = v
+	}
+	existing.OwnerReferences = desired.OwnerReferences
+	log.Info("Updating credential Secret", "name", desired.Name)
+	return r.Update(ctx, existing)
+}
+
+func (r *CredentialReconciler) setCondition(ctx context.Context, extModel *maasv1alpha1.ExternalModel,
+	status metav1.ConditionStatus, reason, message string,
+) error {
+	statusSnapshot := extModel.Status.DeepCopy()
+	apimeta.SetStatusCondition(&extModel.Status.Conditions, metav1.Condition{
+		Type:               ConditionCredentialsReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: extModel.GetGeneration(),
+	})
+	if equality.Semantic.DeepEqual(*statusSnapshot, extModel.Status) {
+		return nil
+	}
+	return r.Status().Update(ctx, extModel)
+}
+
+// SetupWithManager registers the reconciler to watch ExternalModels that set spec.vaultRef.
+func (r *CredentialReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&maasv1alpha1.ExternalModel{}).
+		WithEventFilter(predicate.And(vaultRefPredicate(), predicate.GenerationChangedPredicate{})).
+		Named("external-model-credentials").
+		Complete(tracing.Reconciler("external-model-credentials", r))
+}
+
+// vaultRefPredicate admits ExternalModels that set spec.vaultRef. Deletes are ignored: the
+// credential Secret is owned by the ExternalModel and garbage collected with it.
+func vaultRefPredicate() predicate.Funcs {
+	hasVaultRef := func(obj client.Object) bool {
+		extModel, ok := obj.(*maasv1alpha1.ExternalModel)
+		return ok && extModel.Spec.VaultRef != nil
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return hasVaultRef(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return hasVaultRef(e.ObjectNew) },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return hasVaultRef(e.Object) },
+	}
+}
@@ -0,0 +1,144 @@
+package externalmodel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/vault"
+)
+
+type fakeVault struct {
+	data map[string]string
+	err  error
+	now  time.Time
+}
+
+func (f *fakeVault) Read(context.Context, string, string) (*vault.Secret, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &vault.Secret{Data: f.data, LeaseDuration: 90 * time.Second, FetchedAt: f.now}, nil
+}
+
+func newCredentialReconciler(t *testing.T, v VaultReader, objects ...client.Object) (*CredentialReconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, maasv1alpha1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&maasv1alpha1.ExternalModel{}).
+		Build()
+	return &CredentialReconciler{Client: c, Scheme: scheme, Log: logr.Discard(), Vault: v}, c
+}
+
+func vaultExternalModel() *maasv1alpha1.ExternalModel {
+	return &maasv1alpha1.ExternalModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt-4o", Namespace: "llm", UID: "uid-1"},
+		Spec: maasv1alpha1.ExternalModelSpec{
+			Provider:      "openai",
+			Endpoint:      "api.openai.com",
+			CredentialRef: maasv1alpha1.CredentialReference{Name: "openai-credentials"},
+			VaultRef:      &maasv1alpha1.VaultCredentialReference{Path: "secret/data/openai", Role: "maas"},
+		},
+	}
+}
+
+func reconcileCredentials(t *testing.T, r *CredentialReconciler) (ctrl.Result, error) {
+	t.Helper()
+	return r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "gpt-4o", Namespace: "llm"}})
+}
+
+func credentialsCondition(t *testing.T, c client.Client) *metav1.Condition {
+	t.Helper()
+	extModel := &maasv1alpha1.ExternalModel{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "gpt-4o", Namespace: "llm"}, extModel))
+	return apimeta.FindStatusCondition(extModel.Status.Conditions, ConditionCredentialsReady)
+}
+
+func TestCredentialReconcilerSyncsSecret(t *testing.T) {
+	now := time.Now()
+	v := &fakeVault{data: map[string]string{"api-key": "sk-from-vault"}, now: now}
+	r, c := newCredentialReconciler(t, v, vaultExternalModel())
+	r.now = func() time.Time { return now }
+
+	result, err := reconcileCredentials(t, r)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter, "requeued two thirds into the lease")
+
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "openai-credentials", Namespace: "llm"}, secret))
+	assert.Equal(t, []byte("sk-from-vault"), secret.Data[CredentialKey])
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "gpt-4o", secret.OwnerReferences[0].Name)
+	assert.Equal(t, metav1.ConditionTrue, credentialsCondition(t, c).Status)
+
+	// A rotated value in Vault is written on the next reconcile.
+	v.data = map[string]string{"api-key": "sk-rotated"}
+	_, err = reconcileCredentials(t, r)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "openai-credentials", Namespace: "llm"}, secret))
+	assert.Equal(t, []byte("sk-rotated"), secret.Data[CredentialKey])
+}
+
+func TestCredentialReconcilerRefusesUnmanagedSecret(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "openai-credentials", Namespace: "llm"},
+		Data:       map[string][]byte{CredentialKey: []byte("hand-written")},
+	}
+	r, c := newCredentialReconciler(t, &fakeVault{data: map[string]string{"api-key": "sk-from-vault"}}, vaultExternalModel(), existing)
+
+	_, err := reconcileCredentials(t, r)
+	require.ErrorContains(t, err, "not managed by this reconciler")
+
+	secret := &corev1.Secret{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "openai-credentials", Namespace: "llm"}, secret))
+	assert.Equal(t, []byte("hand-written"), secret.Data[CredentialKey])
+	assert.Equal(t, "SecretSyncFailed", credentialsCondition(t, c).Reason)
+}
+
+func TestCredentialReconcilerReportsVaultErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		vault      VaultReader
+		wantErr    bool
+		wantReason string
+	}{
+		{name: "vault not configured", vault: nil, wantReason: "VaultNotConfigured"},
+		{name: "read failure", vault: &fakeVault{err: errors.New("permission denied")}, wantErr: true, wantReason: "VaultReadFailed"},
+		{name: "missing field", vault: &fakeVault{data: map[string]string{"token": "x"}}, wantReason: "KeyNotFound"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, c := newCredentialReconciler(t, nil, vaultExternalModel())
+			r.Vault = tt.vault
+
+			_, err := reconcileCredentials(t, r)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			cond := credentialsCondition(t, c)
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionFalse, cond.Status)
+			assert.Equal(t, tt.wantReason, cond.Reason)
+		})
+	}
+}
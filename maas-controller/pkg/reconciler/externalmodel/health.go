@@ -0,0 +1,26 @@
+package externalmodel
+
+import (
+	"net"
+	"strconv"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// HealthCheckURL returns the URL the health check of extModel probes for model: the provider
+// endpoint, on the port and with the TLS setting the model's route uses, and the check's path.
+func HealthCheckURL(extModel *maasv1alpha1.ExternalModel, model *maasv1alpha1.MaaSModelRef) (string, error) {
+	spec, err := specFromExternalModel(extModel, model)
+	if err != nil {
+		return "", err
+	}
+	scheme := "https"
+	if !spec.TLS {
+		scheme = "http"
+	}
+	path := "/"
+	if hc := extModel.Spec.HealthCheck; hc != nil && hc.Path != "" {
+		path = hc.Path
+	}
+	return scheme + "://" + net.JoinHostPort(spec.Endpoint, strconv.Itoa(int(spec.Port))) + path, nil
+}
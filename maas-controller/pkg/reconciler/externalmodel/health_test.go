@@ -0,0 +1,37 @@
+package externalmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestHealthCheckURL(t *testing.T) {
+	extModel := &maasv1alpha1.ExternalModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt-4o"},
+		Spec: maasv1alpha1.ExternalModelSpec{
+			Provider:    "openai",
+			Endpoint:    "api.openai.com",
+			HealthCheck: &maasv1alpha1.ExternalModelHealthCheck{},
+		},
+	}
+	model := &maasv1alpha1.MaaSModelRef{ObjectMeta: metav1.ObjectMeta{Name: "gpt-4o"}}
+
+	url, err := HealthCheckURL(extModel, model)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.openai.com:443/", url)
+
+	extModel.Spec.HealthCheck.Path = "/v1/models"
+	model.Annotations = map[string]string{AnnTLS: "false", AnnPort: "8000"}
+	url, err = HealthCheckURL(extModel, model)
+	require.NoError(t, err)
+	assert.Equal(t, "http://api.openai.com:8000/v1/models", url)
+
+	model.Annotations[AnnPort] = "0"
+	_, err = HealthCheckURL(extModel, model)
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@ package externalmodel
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,19 +12,24 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/tracing"
 )
 
 const (
@@ -36,9 +43,20 @@ const (
 	// AnnTLS controls TLS origination (default "true").
 	AnnTLS = "maas.opendatahub.io/tls"
 
-	// AnnPathPrefix overrides the default path prefix (/external/<provider>/).
+	// AnnPathPrefix overrides the default path prefix (/<model name>) when spec.routing.pathPrefix is unset.
 	AnnPathPrefix = "maas.opendatahub.io/path-prefix"
 
+	// AnnHostname dedicates a hostname to the model when spec.routing.hostname is unset.
+	AnnHostname = "maas.opendatahub.io/hostname"
+
+	// AnnAdopt set to "true" on a pre-existing, manually created resource lets the
+	// reconciler take it over. Same key as the MaaS controller's policy adoption annotation.
+	AnnAdopt = "maas.opendatahub.io/adopt"
+
+	// CACertificateKey is the Secret data key holding the PEM bundle referenced by
+	// ExternalModel spec.caCertificateRef.
+	CACertificateKey = "ca.crt"
+
 	// Default gateway (matches MaaS controller defaults)
 	defaultGatewayName      = "maas-default-gateway"
 	defaultGatewayNamespace = "openshift-ingress"
@@ -49,15 +67,26 @@ const (
 //
 // All resources are created in the model's namespace (same as the MaaSModelRef).
 // OwnerReferences on each resource ensure Kubernetes garbage collection handles
-// cleanup when the MaaSModelRef is deleted — no finalizer needed.
+// cleanup when the MaaSModelRef is deleted — no finalizer needed. The one exception is
+// the CA certificate copied into the gateway namespace for spec.caCertificateRef, which
+// the MaaSModelRef controller deletes through the ExternalModel BackendHandler.
 type Reconciler struct {
 	client.Client
+	// APIReader reads Secrets without caching them; defaults to Client when nil.
+	APIReader        client.Reader
 	Scheme           *runtime.Scheme
 	Log              logr.Logger
 	GatewayName      string
 	GatewayNamespace string
 }
 
+func (r *Reconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
 func (r *Reconciler) gatewayName() string {
 	if r.GatewayName != "" {
 		return r.GatewayName
@@ -89,6 +118,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	if !model.GetDeletionTimestamp().IsZero() {
 		return ctrl.Result{}, nil
 	}
+	// MaaSSubscription events enqueue their models whatever their kind.
+	if model.Spec.ModelRef.Kind != "ExternalModel" {
+		return ctrl.Result{}, nil
+	}
 
 	// Fetch the referenced ExternalModel CR to get provider configuration
 	extModel := &maasv1alpha1.ExternalModel{}
@@ -110,6 +143,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, fmt.Errorf("invalid ExternalModel spec: %w", err)
 	}
 
+	if extModel.Spec.Auth != nil {
+		if err := r.checkCredential(ctx, extModel); err != nil {
+			return ctrl.Result{}, err
+		}
+		spec.AuthHeader = AuthHeader(extModel.Spec.Auth)
+	}
+
 	log.Info("Reconciling ExternalModel",
 		"provider", spec.Provider,
 		"endpoint", spec.Endpoint,
@@ -117,9 +157,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	)
 
 	ns := model.Namespace
-	gwName := r.gatewayName()
-	gwNamespace := r.gatewayNamespace()
-	labels := commonLabels(model.GetName())
+	gwName, gwNamespace := r.gatewayName(), r.gatewayNamespace()
+	if gw := model.Spec.Gateway; gw != nil {
+		// The MaaSModelRef controller checks that the Gateway admits the route.
+		gwName = gw.Name
+		if gw.Namespace != "" {
+			gwNamespace = gw.Namespace
+		}
+	}
+	labels, err := r.generatedLabels(ctx, model)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
 	// 1. ExternalName Service (backend for HTTPRoute)
 	svc := BuildService(spec, model.Name, ns, labels)
@@ -139,8 +188,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, fmt.Errorf("failed to create ServiceEntry: %w", err)
 	}
 
-	// 3. DestinationRule (only if TLS; delete stale DR when TLS is disabled)
+	// 3. DestinationRule (only if TLS; delete stale DR when TLS is disabled), with the
+	// private CA bundle copied into the gateway namespace when one is referenced
 	drName := ModelDestinationRuleName(model.Name)
+	if spec.TLS && extModel.Spec.CACertificateRef != nil {
+		secretName, err := r.syncCACertificate(ctx, log, model, extModel.Spec.CACertificateRef.Name, gwNamespace, labels)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		spec.CACertificateSecret = secretName
+	} else if err := r.deleteCACertificate(ctx, log, model, gwNamespace); err != nil {
+		log.Error(err, "Failed to delete stale CA certificate Secret")
+	}
 	if spec.TLS {
 		dr := BuildDestinationRule(spec, model.Name, ns, labels)
 		if err := r.setUnstructuredOwner(model, dr); err != nil {
@@ -157,7 +216,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		}
 	}
 
-	// 4. HTTPRoute (routes requests to external provider via gateway)
+	// 4. HTTPRoute (routes requests to external provider via gateway), on the listeners of the
+	// tiers that offer the model
+	if spec.Listeners, err = r.listeners(ctx, model); err != nil {
+		return ctrl.Result{}, err
+	}
 	hr := BuildHTTPRoute(spec, model.Name, ns, gwName, gwNamespace, labels)
 	if err := controllerutil.SetControllerReference(model, hr, r.Scheme); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to set owner on HTTPRoute: %w", err)
@@ -176,6 +239,89 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
+// listeners returns the gateway listeners of the MaaSSubscriptions that include the model. It
+// returns nil, attaching the route to the whole gateway, when the model is in no subscription or
+// in one without a listener.
+func (r *Reconciler) listeners(ctx context.Context, model *maasv1alpha1.MaaSModelRef) ([]string, error) {
+	var subs maasv1alpha1.MaaSSubscriptionList
+	if err := r.List(ctx, &subs); err != nil {
+		return nil, fmt.Errorf("failed to list MaaSSubscriptions: %w", err)
+	}
+	var listeners []string
+	for _, sub := range subs.Items {
+		if !sub.DeletionTimestamp.IsZero() || !slices.ContainsFunc(sub.Spec.ModelRefs, func(ref maasv1alpha1.ModelSubscriptionRef) bool {
+			return ref.Name == model.Name && ref.Namespace == model.Namespace
+		}) {
+			continue
+		}
+		if sub.Spec.Listener == nil {
+			return nil, nil
+		}
+		listeners = append(listeners, sub.Spec.Listener.Name)
+	}
+	slices.Sort(listeners)
+	return slices.Compact(listeners), nil
+}
+
+// syncCACertificate copies ca.crt from the referenced Secret in the model's namespace into the
+// gateway namespace and returns the copy's name.
+func (r *Reconciler) syncCACertificate(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef,
+	sourceName, gatewayNamespace string, labels map[string]string,
+) (string, error) {
+	source := &corev1.Secret{}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: sourceName, Namespace: model.Namespace}, source); err != nil {
+		return "", fmt.Errorf("failed to get CA certificate Secret %s/%s: %w", model.Namespace, sourceName, err)
+	}
+	caCert := source.Data[CACertificateKey]
+	if len(caCert) == 0 {
+		return "", fmt.Errorf("CA certificate Secret %s/%s has no %q key", model.Namespace, sourceName, CACertificateKey)
+	}
+
+	desired := BuildCACertificateSecret(caCert, model.Name, model.Namespace, gatewayNamespace, labels)
+	existing := &corev1.Secret{}
+	err := r.apiReader().Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		log.Info("Creating CA certificate Secret", "name", desired.Name, "namespace", desired.Namespace)
+		return desired.Name, r.Create(ctx, desired)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", desired.Namespace, desired.Name, err)
+	}
+	if !canManage(existing) {
+		return "", fmt.Errorf("secret %s/%s exists but is not managed by this reconciler", desired.Namespace, desired.Name)
+	}
+	if !equality.Semantic.DeepEqual(existing.Data, desired.Data) || !equality.Semantic.DeepEqual(existing.Labels, desired.Labels) {
+		existing.Data = desired.Data
+		existing.Labels = desired.Labels
+		log.Info("Updating CA certificate Secret", "name", desired.Name, "namespace", desired.Namespace)
+		if err := r.Update(ctx, existing); err != nil {
+			return "", err
+		}
+	}
+	return desired.Name, nil
+}
+
+// deleteCACertificate removes the gateway-namespace copy left behind when spec.caCertificateRef
+// is removed or TLS is turned off.
+func (r *Reconciler) deleteCACertificate(ctx context.Context, log logr.Logger, model *maasv1alpha1.MaaSModelRef, gatewayNamespace string) error {
+	name := ModelCACertificateSecretName(model.Namespace, model.Name)
+	existing := &corev1.Secret{}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Name: name, Namespace: gatewayNamespace}, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get Secret %s/%s: %w", gatewayNamespace, name, err)
+	}
+	if !canManage(existing) {
+		return nil
+	}
+	log.Info("Deleting CA certificate Secret", "name", name, "namespace", gatewayNamespace)
+	if err := r.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete Secret %s/%s: %w", gatewayNamespace, name, err)
+	}
+	return nil
+}
+
 // setUnstructuredOwner sets the controller OwnerReference on an unstructured resource.
 func (r *Reconciler) setUnstructuredOwner(owner *maasv1alpha1.MaaSModelRef, obj *unstructured.Unstructured) error {
 	isController := true
@@ -210,6 +356,15 @@ func (r *Reconciler) deleteIfExists(ctx context.Context, log logr.Logger, kind,
 	return nil
 }
 
+// canManage reports whether the reconciler may overwrite an existing resource: either it
+// carries this reconciler's managed-by label, or an admin annotated it with AnnAdopt=true.
+func canManage(existing metav1.Object) bool {
+	if existing.GetLabels()["app.kubernetes.io/managed-by"] == ManagedBy {
+		return true
+	}
+	return existing.GetAnnotations()[AnnAdopt] == "true"
+}
+
 // applyService creates or updates a Service.
 func (r *Reconciler) applyService(ctx context.Context, log logr.Logger, desired *corev1.Service) error {
 	existing := &corev1.Service{}
@@ -221,7 +376,11 @@ func (r *Reconciler) applyService(ctx context.Context, log logr.Logger, desired
 	if err != nil {
 		return err
 	}
-	if !equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+	if !canManage(existing) {
+		log.Info("Service exists but is not managed by this reconciler, skipping; annotate it with "+AnnAdopt+"=true to adopt it", "name", desired.Name)
+		return nil
+	}
+	if !equality.Semantic.DeepEqual(existing.Spec, desired.Spec) || !equality.Semantic.DeepEqual(existing.Labels, desired.Labels) {
 		existing.Spec = desired.Spec
 		existing.Labels = desired.Labels
 		existing.OwnerReferences = desired.OwnerReferences
@@ -243,6 +402,11 @@ func (r *Reconciler) applyUnstructured(ctx context.Context, log logr.Logger, des
 	if err != nil {
 		return err
 	}
+	if !canManage(existing) {
+		log.Info("Resource exists but is not managed by this reconciler, skipping; annotate it with "+AnnAdopt+"=true to adopt it",
+			"kind", desired.GetKind(), "name", desired.GetName())
+		return nil
+	}
 	desired.SetResourceVersion(existing.GetResourceVersion())
 	log.Info("Updating resource", "kind", desired.GetKind(), "name", desired.GetName())
 	return r.Update(ctx, desired)
@@ -259,6 +423,10 @@ func (r *Reconciler) applyHTTPRoute(ctx context.Context, log logr.Logger, desire
 	if err != nil {
 		return err
 	}
+	if !canManage(existing) {
+		log.Info("HTTPRoute exists but is not managed by this reconciler, skipping; annotate it with "+AnnAdopt+"=true to adopt it", "name", desired.Name)
+		return nil
+	}
 	existing.Spec = desired.Spec
 	existing.Labels = desired.Labels
 	existing.OwnerReferences = desired.OwnerReferences
@@ -269,17 +437,22 @@ func (r *Reconciler) applyHTTPRoute(ctx context.Context, log logr.Logger, desire
 // specFromExternalModel reads ExternalModelSpec from the ExternalModel CR and
 // optional annotation overrides from the MaaSModelRef.
 // Provider and endpoint come from the ExternalModel CR (PR #586).
-// Port, TLS, path-prefix, and extra-headers are optional annotation overrides on the MaaSModelRef.
+// Port, TLS, path-prefix, hostname, and extra-headers are optional annotation overrides on the MaaSModelRef;
+// spec.routing takes precedence for the path prefix and hostname.
 func specFromExternalModel(extModel *maasv1alpha1.ExternalModel, model *maasv1alpha1.MaaSModelRef) (ExternalModelSpec, error) {
 	ann := model.GetAnnotations()
 	if ann == nil {
 		ann = map[string]string{}
 	}
 
+	pathPrefix, hostname := ModelRoute(model)
 	spec := ExternalModelSpec{
 		Provider:   extModel.Spec.Provider,
 		Endpoint:   extModel.Spec.Endpoint,
-		PathPrefix: ann[AnnPathPrefix],
+		PathPrefix: pathPrefix,
+		Hostname:   hostname,
+		Class:      model.Spec.Class,
+		Aliases:    model.Spec.Aliases,
 		TLS:        true,
 		Port:       443,
 		// TLSInsecureSkipVerify: extModel.Spec.TLSInsecureSkipVerify, // requires issue #627 CRD change
@@ -349,12 +522,71 @@ func externalModelPredicate() predicate.Predicate {
 	}
 }
 
+// generatedLabels returns the labels of the model's resources: the spec.generatedResources
+// labels of the MaaSConfig, then of the model, then the reconciler's own, each winning over the
+// previous. The maas controller validates them and reports invalid ones on the model.
+func (r *Reconciler) generatedLabels(ctx context.Context, model *maasv1alpha1.MaaSModelRef) (map[string]string, error) {
+	labels := map[string]string{}
+	cfg := &maasv1alpha1.MaaSConfig{}
+	err := r.Get(ctx, client.ObjectKey{Name: maasv1alpha1.MaaSConfigSingletonName}, cfg)
+	switch {
+	case err == nil:
+		if g := cfg.Spec.GeneratedResources; g != nil {
+			maps.Copy(labels, g.Labels)
+		}
+	case apierrors.IsNotFound(err) || apimeta.IsNoMatchError(err):
+	default:
+		return nil, fmt.Errorf("failed to get MaaSConfig: %w", err)
+	}
+	if g := model.Spec.GeneratedResources; g != nil {
+		maps.Copy(labels, g.Labels)
+	}
+	maps.Copy(labels, commonLabels(model.GetName()))
+	return labels, nil
+}
+
+// configModels maps the MaaSConfig to every MaaSModelRef of kind ExternalModel, whose resources
+// carry its labels.
+func (r *Reconciler) configModels(ctx context.Context, _ client.Object) []reconcile.Request {
+	models := &maasv1alpha1.MaaSModelRefList{}
+	if err := r.List(ctx, models); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, m := range models.Items {
+		if m.Spec.ModelRef.Kind == "ExternalModel" {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: m.Namespace, Name: m.Name}})
+		}
+	}
+	return requests
+}
+
+// subscriptionModels maps a MaaSSubscription to the MaaSModelRefs it includes.
+func subscriptionModels(_ context.Context, obj client.Object) []reconcile.Request {
+	sub, ok := obj.(*maasv1alpha1.MaaSSubscription)
+	if !ok {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(sub.Spec.ModelRefs))
+	for _, ref := range sub.Spec.ModelRefs {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}})
+	}
+	return requests
+}
+
 // SetupWithManager registers the reconciler to watch MaaSModelRef CRs
-// with kind=ExternalModel only (filtered by predicate).
+// with kind=ExternalModel only (filtered by predicate), the MaaSSubscriptions that include them,
+// and the ExternalModels they reference.
 func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&maasv1alpha1.MaaSModelRef{}).
-		WithEventFilter(externalModelPredicate()).
+		For(&maasv1alpha1.MaaSModelRef{}, builder.WithPredicates(externalModelPredicate())).
+		// A subscription's listener decides which gateway listeners its models' routes attach to.
+		Watches(&maasv1alpha1.MaaSSubscription{}, handler.EnqueueRequestsFromMapFunc(subscriptionModels)).
+		Watches(&maasv1alpha1.ExternalModel{}, handler.EnqueueRequestsFromMapFunc(r.externalModelRefs),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// The MaaSConfig's spec.generatedResources labels every generated resource.
+		Watches(&maasv1alpha1.MaaSConfig{}, handler.EnqueueRequestsFromMapFunc(r.configModels),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Named("external-model-reconciler").
-		Complete(r)
+		Complete(tracing.Reconciler("external-model-reconciler", r))
 }
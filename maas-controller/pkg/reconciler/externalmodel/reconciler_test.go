@@ -0,0 +1,70 @@
+package externalmodel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestCanManage(t *testing.T) {
+	owned := &metav1.ObjectMeta{Labels: commonLabels("my-gpt4")}
+	assert.True(t, canManage(owned))
+
+	manual := &metav1.ObjectMeta{Labels: map[string]string{"app": "hand-written"}}
+	assert.False(t, canManage(manual))
+
+	adopted := &metav1.ObjectMeta{Annotations: map[string]string{AnnAdopt: "true"}}
+	assert.True(t, canManage(adopted))
+
+	notConfirmed := &metav1.ObjectMeta{Annotations: map[string]string{AnnAdopt: "yes"}}
+	assert.False(t, canManage(notConfirmed))
+}
+
+func tierSubscription(name string, listener *maasv1alpha1.SubscriptionListener, models ...string) *maasv1alpha1.MaaSSubscription {
+	sub := &maasv1alpha1.MaaSSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "models-as-a-service"},
+		Spec:       maasv1alpha1.MaaSSubscriptionSpec{Listener: listener},
+	}
+	for _, m := range models {
+		sub.Spec.ModelRefs = append(sub.Spec.ModelRefs, maasv1alpha1.ModelSubscriptionRef{Name: m, Namespace: "llm"})
+	}
+	return sub
+}
+
+func TestListeners(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, maasv1alpha1.AddToScheme(scheme))
+	premium := &maasv1alpha1.SubscriptionListener{Name: "premium", Hostnames: []string{"premium.models.example.com"}}
+	enterprise := &maasv1alpha1.SubscriptionListener{Name: "enterprise", Hostnames: []string{"enterprise.models.example.com"}}
+	objects := []client.Object{
+		tierSubscription("premium", premium, "gpt-4o", "claude"),
+		tierSubscription("enterprise", enterprise, "gpt-4o"),
+		tierSubscription("free", nil, "claude"),
+	}
+	r := &Reconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()}
+
+	tests := []struct {
+		model string
+		want  []string
+	}{
+		{model: "gpt-4o", want: []string{"enterprise", "premium"}},
+		{model: "claude", want: nil},
+		{model: "unsubscribed", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			model := &maasv1alpha1.MaaSModelRef{ObjectMeta: metav1.ObjectMeta{Name: tt.model, Namespace: "llm"}}
+			got, err := r.listeners(context.Background(), model)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
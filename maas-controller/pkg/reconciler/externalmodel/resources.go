@@ -87,6 +87,10 @@ func BuildDestinationRule(spec ExternalModelSpec, modelName, namespace string, l
 	if spec.TLSInsecureSkipVerify {
 		tlsConfig["insecureSkipVerify"] = true
 	}
+	if spec.CACertificateSecret != "" {
+		// Resolved by the gateway proxy in its own namespace.
+		tlsConfig["credentialName"] = spec.CACertificateSecret
+	}
 
 	dr.Object["spec"] = map[string]interface{}{
 		"host": spec.Endpoint,
@@ -97,28 +101,68 @@ func BuildDestinationRule(spec ExternalModelSpec, modelName, namespace string, l
 	return dr
 }
 
+// BuildCACertificateSecret creates the copy of an ExternalModel's CA certificate in the gateway
+// namespace, where the DestinationRule's credentialName is resolved. Owner references cannot cross
+// namespaces, so the MaaSModelRef controller deletes it when the model is deleted.
+func BuildCACertificateSecret(caCert []byte, modelName, modelNamespace, gatewayNamespace string, labels map[string]string) *corev1.Secret {
+	secretLabels := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		secretLabels[k] = v
+	}
+	secretLabels["maas.opendatahub.io/model-namespace"] = modelNamespace
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ModelCACertificateSecretName(modelNamespace, modelName),
+			Namespace: gatewayNamespace,
+			Labels:    secretLabels,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{CACertificateKey: caCert},
+	}
+}
+
+// modelNameMatches matches the X-Gateway-Model-Name header BBR sets to any of names.
+func modelNameMatches(names []string) []gatewayapiv1.HTTPRouteMatch {
+	headerType := gatewayapiv1.HeaderMatchExact
+	matches := make([]gatewayapiv1.HTTPRouteMatch, 0, len(names))
+	for _, name := range names {
+		matches = append(matches, gatewayapiv1.HTTPRouteMatch{
+			Headers: []gatewayapiv1.HTTPHeaderMatch{{
+				Name:  "X-Gateway-Model-Name",
+				Type:  &headerType,
+				Value: name,
+			}},
+		})
+	}
+	return matches
+}
+
 // BuildHTTPRoute creates the maas-model-<name> HTTPRoute in the model's namespace.
 // This route is used by the MaaS auth and subscription controllers to attach
 // AuthPolicy and TokenRateLimitPolicy.
 //
 // It contains two match rules:
-//  1. Path-based match (PathPrefix: /<modelName>) — required for the Kuadrant Wasm plugin
+//  1. Path-based match (PathPrefix: spec.PathPrefix, default /<modelName>) — required for the Kuadrant Wasm plugin
 //     which runs before BBR in the Envoy filter chain. Without a path predicate, auth +
-//     rate limiting are bypassed.
-//  2. Header-based match (X-Gateway-Model-Name: <modelName>) — required for BBR's
+//     rate limiting are bypassed. With spec.Class it becomes one rule per endpoint of the class.
+//     Each of spec.Aliases is matched under /<alias> alongside the prefix.
+//  2. Header-based match (X-Gateway-Model-Name: <modelName> or one of spec.Aliases) — required for BBR's
 //     ClearRouteCache flow. After BBR extracts the model name from the request body,
 //     it sets this header and Envoy re-matches to this route.
 //
 // Both rules route to the backend ExternalName Service in the same namespace and apply
-// a URLRewrite filter to strip the path prefix before forwarding to the external provider.
+// a URLRewrite filter to strip the path prefix before forwarding to the external provider,
+// and set the Host header. The credential of ExternalModel spec.auth is not part of the route:
+// the model's AuthPolicy injects it on each request.
 func BuildHTTPRoute(spec ExternalModelSpec, modelName, namespace, gatewayName, gatewayNamespace string, labels map[string]string) *gatewayapiv1.HTTPRoute {
 	routeName := ModelRouteName(modelName)
 	backendSvcName := ModelBackendServiceName(modelName)
 
-	gwNamespace := gatewayapiv1.Namespace(gatewayNamespace)
 	pathType := gatewayapiv1.PathMatchPathPrefix
-	pathPrefix := "/" + modelName
-	headerType := gatewayapiv1.HeaderMatchExact
+	pathPrefix := spec.PathPrefix
+	if pathPrefix == "" {
+		pathPrefix = "/" + modelName
+	}
 	port := gatewayapiv1.PortNumber(spec.Port)
 	timeout := gatewayapiv1.Duration("300s")
 
@@ -141,11 +185,20 @@ func BuildHTTPRoute(spec ExternalModelSpec, modelName, namespace, gatewayName, g
 		},
 	}
 	for k, v := range spec.ExtraHeaders {
+		if spec.AuthHeader != "" && strings.EqualFold(k, spec.AuthHeader) {
+			continue
+		}
 		headers = append(headers, gatewayapiv1.HTTPHeader{
 			Name:  gatewayapiv1.HTTPHeaderName(k),
 			Value: v,
 		})
 	}
+	// The AuthPolicy's credential overwrites the caller's header of the same name. The caller's
+	// Authorization header carries its MaaS credential, which must not reach the provider either.
+	var remove []string
+	if spec.AuthHeader != "" && !strings.EqualFold(spec.AuthHeader, "Authorization") {
+		remove = []string{"Authorization"}
+	}
 
 	// Filters shared by both rules: rewrite path prefix and set Host header
 	filters := []gatewayapiv1.HTTPRouteFilter{
@@ -161,12 +214,13 @@ func BuildHTTPRoute(spec ExternalModelSpec, modelName, namespace, gatewayName, g
 		{
 			Type: gatewayapiv1.HTTPRouteFilterRequestHeaderModifier,
 			RequestHeaderModifier: &gatewayapiv1.HTTPHeaderFilter{
-				Set: headers,
+				Set:    headers,
+				Remove: remove,
 			},
 		},
 	}
 
-	return &gatewayapiv1.HTTPRoute{
+	route := &gatewayapiv1.HTTPRoute{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      routeName,
 			Namespace: namespace,
@@ -174,16 +228,11 @@ func BuildHTTPRoute(spec ExternalModelSpec, modelName, namespace, gatewayName, g
 		},
 		Spec: gatewayapiv1.HTTPRouteSpec{
 			CommonRouteSpec: gatewayapiv1.CommonRouteSpec{
-				ParentRefs: []gatewayapiv1.ParentReference{
-					{
-						Name:      gatewayapiv1.ObjectName(gatewayName),
-						Namespace: &gwNamespace,
-					},
-				},
+				ParentRefs: parentRefs(gatewayName, gatewayNamespace, spec.Listeners),
 			},
-			Rules: []gatewayapiv1.HTTPRouteRule{
+			Rules: append(AliasMatches(ClassRules(
 				// Rule 1: Path-based match — Kuadrant Wasm plugin needs this
-				{
+				gatewayapiv1.HTTPRouteRule{
 					Matches: []gatewayapiv1.HTTPRouteMatch{
 						{
 							Path: &gatewayapiv1.HTTPPathMatch{
@@ -195,27 +244,43 @@ func BuildHTTPRoute(spec ExternalModelSpec, modelName, namespace, gatewayName, g
 					BackendRefs: backendRefs,
 					Filters:     filters,
 					Timeouts:    &gatewayapiv1.HTTPRouteTimeouts{Request: &timeout},
-				},
+				}, pathPrefix, spec.Class), pathPrefix, spec.Aliases),
 				// Rule 2: Header-based match — BBR ClearRouteCache sets this header
-				{
-					Matches: []gatewayapiv1.HTTPRouteMatch{
-						{
-							Headers: []gatewayapiv1.HTTPHeaderMatch{
-								{
-									Name:  "X-Gateway-Model-Name",
-									Type:  &headerType,
-									Value: modelName,
-								},
-							},
-						},
-					},
+				gatewayapiv1.HTTPRouteRule{
+					Matches:     modelNameMatches(append([]string{modelName}, spec.Aliases...)),
 					BackendRefs: backendRefs,
 					Filters:     filters,
 					Timeouts:    &gatewayapiv1.HTTPRouteTimeouts{Request: &timeout},
 				},
-			},
+			),
 		},
 	}
+	if spec.Hostname != "" {
+		route.Spec.Hostnames = []gatewayapiv1.Hostname{gatewayapiv1.Hostname(spec.Hostname)}
+	}
+	return route
+}
+
+// parentRefs attaches a route to the gateway, or to each of listeners when there are any, so a
+// model only offered by tiers bound to listeners is not reachable through the others.
+func parentRefs(gatewayName, gatewayNamespace string, listeners []string) []gatewayapiv1.ParentReference {
+	gwNamespace := gatewayapiv1.Namespace(gatewayNamespace)
+	if len(listeners) == 0 {
+		return []gatewayapiv1.ParentReference{{
+			Name:      gatewayapiv1.ObjectName(gatewayName),
+			Namespace: &gwNamespace,
+		}}
+	}
+	refs := make([]gatewayapiv1.ParentReference, 0, len(listeners))
+	for _, l := range listeners {
+		section := gatewayapiv1.SectionName(l)
+		refs = append(refs, gatewayapiv1.ParentReference{
+			Name:        gatewayapiv1.ObjectName(gatewayName),
+			Namespace:   &gwNamespace,
+			SectionName: &section,
+		})
+	}
+	return refs
 }
 
 func sanitize(s string) string {
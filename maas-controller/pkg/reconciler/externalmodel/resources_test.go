@@ -1,6 +1,7 @@
 package externalmodel
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -140,6 +141,33 @@ func TestBuildDestinationRuleInsecureSkipVerify(t *testing.T) {
 	assert.Equal(t, true, tlsCfg["insecureSkipVerify"], "insecureSkipVerify must be true when opted in")
 }
 
+func TestBuildDestinationRuleCACertificate(t *testing.T) {
+	spec := ExternalModelSpec{
+		Provider:            "openai",
+		Endpoint:            "llm.internal.example.com",
+		Port:                443,
+		TLS:                 true,
+		CACertificateSecret: ModelCACertificateSecretName("llm", "private-model"),
+	}
+
+	dr := BuildDestinationRule(spec, "private-model", "llm", commonLabels("private-model"))
+
+	drSpec := dr.Object["spec"].(map[string]interface{})
+	tlsCfg := drSpec["trafficPolicy"].(map[string]interface{})["tls"].(map[string]interface{})
+	assert.Equal(t, "maas-model-llm-private-model-ca", tlsCfg["credentialName"])
+}
+
+func TestBuildCACertificateSecret(t *testing.T) {
+	secret := BuildCACertificateSecret([]byte("-----BEGIN CERTIFICATE-----"), "private-model", "llm", "openshift-ingress",
+		commonLabels("private-model"))
+
+	assert.Equal(t, ModelCACertificateSecretName("llm", "private-model"), secret.Name)
+	assert.Equal(t, "openshift-ingress", secret.Namespace)
+	assert.Equal(t, []byte("-----BEGIN CERTIFICATE-----"), secret.Data[CACertificateKey])
+	assert.Equal(t, "llm", secret.Labels["maas.opendatahub.io/model-namespace"])
+	assert.True(t, canManage(&secret.ObjectMeta), "the copy carries the reconciler's labels")
+}
+
 func TestBuildHTTPRoute(t *testing.T) {
 	spec := ExternalModelSpec{
 		Provider:     "openai",
@@ -225,3 +253,53 @@ func TestBuildHTTPRouteWithExtraHeaders(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildHTTPRouteListeners(t *testing.T) {
+	spec := ExternalModelSpec{Provider: "openai", Endpoint: "api.openai.com", Port: 443, TLS: true}
+
+	hr := BuildHTTPRoute(spec, "my-gpt4", "llm", "maas-default-gateway", "openshift-ingress", commonLabels("my-gpt4"))
+	assert.Len(t, hr.Spec.ParentRefs, 1)
+	assert.Nil(t, hr.Spec.ParentRefs[0].SectionName, "without listeners the route attaches to the whole gateway")
+
+	spec.Listeners = []string{"free", "premium"}
+	hr = BuildHTTPRoute(spec, "my-gpt4", "llm", "maas-default-gateway", "openshift-ingress", commonLabels("my-gpt4"))
+	assert.Len(t, hr.Spec.ParentRefs, 2)
+	for i, listener := range spec.Listeners {
+		ref := hr.Spec.ParentRefs[i]
+		assert.Equal(t, "maas-default-gateway", string(ref.Name))
+		assert.Equal(t, "openshift-ingress", string(*ref.Namespace))
+		assert.Equal(t, listener, string(*ref.SectionName))
+	}
+}
+
+func TestBuildHTTPRouteUpstreamAuth(t *testing.T) {
+	spec := ExternalModelSpec{
+		Provider:     "azure",
+		Endpoint:     "example.openai.azure.com",
+		Port:         443,
+		TLS:          true,
+		ExtraHeaders: map[string]string{"API-Key": "from-annotation"},
+		AuthHeader:   "api-key",
+	}
+
+	hr := BuildHTTPRoute(spec, "my-gpt4", "llm", "maas-default-gateway", "openshift-ingress", commonLabels("my-gpt4"))
+	for _, rule := range hr.Spec.Rules {
+		for _, f := range rule.Filters {
+			if f.RequestHeaderModifier == nil {
+				continue
+			}
+			for _, h := range f.RequestHeaderModifier.Set {
+				assert.False(t, strings.EqualFold(string(h.Name), "api-key"), "the AuthPolicy's credential is not overwritten or stored in the route")
+			}
+			assert.Equal(t, []string{"Authorization"}, f.RequestHeaderModifier.Remove, "the caller's MaaS credential is not forwarded")
+		}
+	}
+
+	spec.AuthHeader = "Authorization"
+	hr = BuildHTTPRoute(spec, "my-gpt4", "llm", "maas-default-gateway", "openshift-ingress", commonLabels("my-gpt4"))
+	for _, f := range hr.Spec.Rules[0].Filters {
+		if f.RequestHeaderModifier != nil {
+			assert.Empty(t, f.RequestHeaderModifier.Remove, "the injected Authorization header already replaces the caller's")
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package externalmodel
+
+import (
+	"strings"
+
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+// ModelRoute returns the path prefix and hostname of a model's generated HTTPRoute, from
+// spec.routing, else the path-prefix and hostname annotations. The prefix defaults to
+// /<model name>, or / when the model has a dedicated hostname. The hostname is empty when the
+// model shares the gateway's hostnames.
+func ModelRoute(model *maasv1alpha1.MaaSModelRef) (pathPrefix, hostname string) {
+	ann := model.GetAnnotations()
+	pathPrefix, hostname = ann[AnnPathPrefix], ann[AnnHostname]
+	if r := model.Spec.Routing; r != nil {
+		if r.PathPrefix != "" {
+			pathPrefix = r.PathPrefix
+		}
+		if r.Hostname != "" {
+			hostname = r.Hostname
+		}
+	}
+	if pathPrefix == "" {
+		if hostname != "" {
+			return "/", hostname
+		}
+		pathPrefix = "/" + model.Name
+	}
+	if trimmed := strings.TrimRight(pathPrefix, "/"); trimmed != "" {
+		pathPrefix = trimmed
+	} else {
+		pathPrefix = "/"
+	}
+	return pathPrefix, hostname
+}
+
+// ModelURL is the URL a model is served at through host: its dedicated hostname, when it has one,
+// with its path prefix.
+func ModelURL(model *maasv1alpha1.MaaSModelRef, host string) string {
+	pathPrefix, hostname := ModelRoute(model)
+	if hostname != "" {
+		host = hostname
+	}
+	return "https://" + host + strings.TrimSuffix(pathPrefix, "/")
+}
+
+// classPaths are the endpoints of each model class, relative to the model's path prefix. Every
+// class also serves /v1/models. maas-api keeps the same list to validate shared-route requests.
+var classPaths = map[maasv1alpha1.ModelClass][]string{
+	maasv1alpha1.ModelClassChat:       {"/v1/chat/completions", "/v1/responses"},
+	maasv1alpha1.ModelClassCompletion: {"/v1/completions"},
+	maasv1alpha1.ModelClassEmbedding:  {"/v1/embeddings"},
+	maasv1alpha1.ModelClassReranker:   {"/v1/rerank", "/v2/rerank", "/rerank"},
+	maasv1alpha1.ModelClassAudio:      {"/v1/audio/transcriptions", "/v1/audio/translations", "/v1/audio/speech"},
+}
+
+// ClassPaths returns the endpoints a model of class serves, or nil for an unset or unknown class,
+// whose route serves every path.
+func ClassPaths(class maasv1alpha1.ModelClass) []string {
+	paths, ok := classPaths[class]
+	if !ok {
+		return nil
+	}
+	return append(append([]string(nil), paths...), "/v1/models")
+}
+
+// ClassRules restricts rule, which matches pathPrefix and rewrites it to /, to the endpoints of
+// class: it returns one copy of rule per endpoint, matching pathPrefix followed by the endpoint
+// and rewriting that to the endpoint alone. A rule for a model without a class is returned as is.
+func ClassRules(rule gatewayapiv1.HTTPRouteRule, pathPrefix string, class maasv1alpha1.ModelClass) []gatewayapiv1.HTTPRouteRule {
+	paths := ClassPaths(class)
+	if paths == nil {
+		return []gatewayapiv1.HTTPRouteRule{rule}
+	}
+	pathType := gatewayapiv1.PathMatchPathPrefix
+	rules := make([]gatewayapiv1.HTTPRouteRule, 0, len(paths))
+	for _, path := range paths {
+		r := *rule.DeepCopy()
+		match := strings.TrimSuffix(pathPrefix, "/") + path
+		r.Matches = []gatewayapiv1.HTTPRouteMatch{{Path: &gatewayapiv1.HTTPPathMatch{Type: &pathType, Value: &match}}}
+		for i := range r.Filters {
+			if rewrite := r.Filters[i].URLRewrite; rewrite != nil && rewrite.Path != nil && rewrite.Path.Type == gatewayapiv1.PrefixMatchHTTPPathModifier {
+				rewrite.Path.ReplacePrefixMatch = strPtr(path)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// AliasMatches adds to rules, generated for pathPrefix, a copy of each path match under the
+// /<alias> prefix of every alias, so the rules serve the model's aliases as they serve its prefix.
+// The URL rewrites replace whichever prefix matched, so the backend sees the same paths.
+func AliasMatches(rules []gatewayapiv1.HTTPRouteRule, pathPrefix string, aliases []string) []gatewayapiv1.HTTPRouteRule {
+	if len(aliases) == 0 {
+		return rules
+	}
+	base := strings.TrimSuffix(pathPrefix, "/")
+	for i := range rules {
+		var added []gatewayapiv1.HTTPRouteMatch
+		for _, m := range rules[i].Matches {
+			if m.Path == nil || m.Path.Value == nil || !strings.HasPrefix(*m.Path.Value, base) {
+				continue
+			}
+			for _, alias := range aliases {
+				value := "/" + alias
+				if *m.Path.Value != pathPrefix {
+					value += strings.TrimPrefix(*m.Path.Value, base)
+				}
+				match := *m.DeepCopy()
+				match.Path.Value = &value
+				added = append(added, match)
+			}
+		}
+		rules[i].Matches = append(rules[i].Matches, added...)
+	}
+	return rules
+}
@@ -0,0 +1,116 @@
+package externalmodel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func TestModelRoute(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		routing      *maasv1alpha1.ModelRouting
+		wantPrefix   string
+		wantHostname string
+		wantURL      string
+	}{
+		{name: "default", wantPrefix: "/gpt", wantURL: "https://gw.example.com/gpt"},
+		{name: "annotation", annotations: map[string]string{AnnPathPrefix: "/openai/gpt/"}, wantPrefix: "/openai/gpt", wantURL: "https://gw.example.com/openai/gpt"},
+		{
+			name:        "spec wins over annotation",
+			annotations: map[string]string{AnnPathPrefix: "/old"},
+			routing:     &maasv1alpha1.ModelRouting{PathPrefix: "/new"},
+			wantPrefix:  "/new", wantURL: "https://gw.example.com/new",
+		},
+		{
+			name:       "hostname defaults prefix to root",
+			routing:    &maasv1alpha1.ModelRouting{Hostname: "gpt.example.com"},
+			wantPrefix: "/", wantHostname: "gpt.example.com", wantURL: "https://gpt.example.com",
+		},
+		{
+			name:        "hostname annotation with spec prefix",
+			annotations: map[string]string{AnnHostname: "models.example.com"},
+			routing:     &maasv1alpha1.ModelRouting{PathPrefix: "/gpt"},
+			wantPrefix:  "/gpt", wantHostname: "models.example.com", wantURL: "https://models.example.com/gpt",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &maasv1alpha1.MaaSModelRef{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpt", Namespace: "models", Annotations: tt.annotations},
+				Spec:       maasv1alpha1.MaaSModelSpec{Routing: tt.routing},
+			}
+			prefix, hostname := ModelRoute(model)
+			assert.Equal(t, tt.wantPrefix, prefix)
+			assert.Equal(t, tt.wantHostname, hostname)
+			assert.Equal(t, tt.wantURL, ModelURL(model, "gw.example.com"))
+		})
+	}
+}
+
+func TestBuildHTTPRouteWithRouting(t *testing.T) {
+	spec := ExternalModelSpec{Provider: "openai", Endpoint: "api.openai.com", Port: 443, PathPrefix: "/openai/gpt", Hostname: "gpt.example.com"}
+	route := BuildHTTPRoute(spec, "gpt", "models", "maas-default-gateway", "openshift-ingress", nil)
+
+	assert.Equal(t, []gatewayapiv1.Hostname{"gpt.example.com"}, route.Spec.Hostnames)
+	assert.Equal(t, "/openai/gpt", *route.Spec.Rules[0].Matches[0].Path.Value)
+}
+
+func TestBuildHTTPRouteWithClass(t *testing.T) {
+	spec := ExternalModelSpec{Provider: "openai", Endpoint: "api.openai.com", Port: 443, PathPrefix: "/embed", Class: maasv1alpha1.ModelClassEmbedding}
+	route := BuildHTTPRoute(spec, "embed", "models", "maas-default-gateway", "openshift-ingress", nil)
+
+	var matches, rewrites []string
+	for _, rule := range route.Spec.Rules {
+		if rule.Matches[0].Path == nil {
+			continue
+		}
+		matches = append(matches, *rule.Matches[0].Path.Value)
+		for _, f := range rule.Filters {
+			if f.URLRewrite != nil {
+				rewrites = append(rewrites, *f.URLRewrite.Path.ReplacePrefixMatch)
+			}
+		}
+	}
+	assert.Equal(t, []string{"/embed/v1/embeddings", "/embed/v1/models"}, matches, "only the class's endpoints are routed")
+	assert.Equal(t, []string{"/v1/embeddings", "/v1/models"}, rewrites, "each endpoint keeps its path on the backend")
+	assert.Len(t, route.Spec.Rules, 3, "the header-based rule is kept")
+
+	unclassified := BuildHTTPRoute(ExternalModelSpec{Provider: "openai", Endpoint: "api.openai.com", Port: 443}, "gpt", "models", "maas-default-gateway", "openshift-ingress", nil)
+	assert.Len(t, unclassified.Spec.Rules, 2)
+	assert.Equal(t, "/gpt", *unclassified.Spec.Rules[0].Matches[0].Path.Value)
+}
+
+func TestBuildHTTPRouteWithAliases(t *testing.T) {
+	spec := ExternalModelSpec{Provider: "openai", Endpoint: "api.openai.com", Port: 443, PathPrefix: "/embed", Class: maasv1alpha1.ModelClassEmbedding,
+		Aliases: []string{"embed-latest"}}
+	route := BuildHTTPRoute(spec, "embed", "models", "maas-default-gateway", "openshift-ingress", nil)
+
+	paths := func(rule gatewayapiv1.HTTPRouteRule) []string {
+		var values []string
+		for _, m := range rule.Matches {
+			if m.Path != nil {
+				values = append(values, *m.Path.Value)
+			}
+		}
+		return values
+	}
+	assert.Len(t, route.Spec.Rules, 3, "aliases add matches, not rules")
+	assert.Equal(t, []string{"/embed/v1/embeddings", "/embed-latest/v1/embeddings"}, paths(route.Spec.Rules[0]))
+	assert.Equal(t, []string{"/embed/v1/models", "/embed-latest/v1/models"}, paths(route.Spec.Rules[1]))
+	var names []string
+	for _, m := range route.Spec.Rules[2].Matches {
+		names = append(names, m.Headers[0].Value)
+	}
+	assert.Equal(t, []string{"embed", "embed-latest"}, names, "BBR may set the alias a request body names")
+
+	unclassified := BuildHTTPRoute(ExternalModelSpec{Provider: "openai", Endpoint: "api.openai.com", Port: 443, Aliases: []string{"chat"}},
+		"gpt", "models", "maas-default-gateway", "openshift-ingress", nil)
+	assert.Equal(t, []string{"/gpt", "/chat"}, paths(unclassified.Spec.Rules[0]))
+	assert.Equal(t, "/", *unclassified.Spec.Rules[0].Filters[0].URLRewrite.Path.ReplacePrefixMatch)
+}
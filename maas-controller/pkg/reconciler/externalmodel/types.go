@@ -5,7 +5,7 @@
 //  1. ExternalName Service   - DNS bridge for HTTPRoute backendRef
 //  2. ServiceEntry           - Registers external host in Istio mesh
 //  3. DestinationRule        - TLS origination (HTTP -> HTTPS)
-//  4. HTTPRoute              - Routes requests and sets Host header
+//  4. HTTPRoute              - Routes requests and sets Host and credential headers
 //
 // All resources are created in the model's namespace (same as the MaaSModelRef).
 // OwnerReferences on each resource ensure Kubernetes garbage collection handles
@@ -14,6 +14,8 @@ package externalmodel
 
 import (
 	"strings"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
 )
 
 // ExternalModelSpec holds the configuration for routing to an external model.
@@ -30,10 +32,25 @@ type ExternalModelSpec struct {
 	Port int32
 	// TLS indicates whether TLS origination is needed (default true)
 	TLS bool
-	// PathPrefix is the path prefix to match (default "/external/<provider>/")
+	// PathPrefix is the path prefix to match (default "/<model name>")
 	PathPrefix string
+	// Hostname, when set, restricts the HTTPRoute to this hostname
+	Hostname string
+	// Class, when set, restricts the path-based rule to the endpoints of the model class
+	Class maasv1alpha1.ModelClass
+	// Aliases are the model's other names, matched under /<alias> and in X-Gateway-Model-Name
+	Aliases []string
 	// TLSInsecureSkipVerify disables certificate verification (testing only)
 	TLSInsecureSkipVerify bool
+	// CACertificateSecret is the Secret in the gateway namespace whose ca.crt verifies the
+	// provider's certificate. Empty means the system CA bundle is used.
+	CACertificateSecret string
+	// AuthHeader is the header of the credential the model's AuthPolicy injects from ExternalModel
+	// spec.auth. An empty AuthHeader forwards the caller's headers.
+	AuthHeader string
+	// Listeners are the gateway listeners the HTTPRoute attaches to, from the listeners of the
+	// MaaSSubscriptions that include the model. Empty attaches it to every listener.
+	Listeners []string
 }
 
 // truncateName ensures base + suffix fits within 63 characters.
@@ -73,10 +90,22 @@ func ModelDestinationRuleName(modelName string) string {
 	return truncateName("maas-model-"+sanitize(modelName), "-dr")
 }
 
+// ModelCACertificateSecretName returns the name of the CA certificate Secret copied into the
+// gateway namespace. It includes the model namespace because models from every namespace share
+// the gateway namespace.
+func ModelCACertificateSecretName(modelNamespace, modelName string) string {
+	return truncateName("maas-model-"+sanitize(modelNamespace)+"-"+sanitize(modelName), "-ca")
+}
+
+// ManagedBy is the app.kubernetes.io/managed-by value on every resource this reconciler creates.
+const ManagedBy = "maas-external-model-reconciler"
+
 // commonLabels returns labels applied to all managed resources.
 func commonLabels(modelName string) map[string]string {
 	return map[string]string{
-		"app.kubernetes.io/managed-by":       "maas-external-model-reconciler",
+		"app.kubernetes.io/managed-by":       ManagedBy,
+		"app.kubernetes.io/part-of":          "maas-external-model",
+		"maas.opendatahub.io/model":          modelName,
 		"maas.opendatahub.io/external-model": modelName,
 	}
 }
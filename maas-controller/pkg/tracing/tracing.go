@@ -0,0 +1,87 @@
+// Package tracing exports OpenTelemetry spans for maas-controller's reconcile loops, so a slow
+// or failing reconcile can be found next to maas-api's and the gateway's traces.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const instrumentationName = "github.com/opendatahub-io/models-as-a-service/maas-controller"
+
+// Setup installs a tracer provider exporting spans to endpoint over OTLP/HTTP, sampling
+// samplingPercentage of reconciles. An empty endpoint leaves tracing off. The returned function
+// flushes and stops the exporter.
+func Setup(ctx context.Context, endpoint string, samplingPercentage int, version string) (func(context.Context) error, error) {
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", "maas-controller"),
+		attribute.String("service.version", version),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(float64(samplingPercentage)/100))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Reconciler traces each reconcile of r in a span named after the controller, and adds the
+// trace ID to the reconcile's logger.
+func Reconciler(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracedReconciler{name: name, next: r}
+}
+
+type tracedReconciler struct {
+	name string
+	next reconcile.Reconciler
+}
+
+func (t *tracedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("maas.controller", t.name),
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("k8s.object.name", req.Name),
+	}
+	if id := controller.ReconcileIDFromContext(ctx); id != "" {
+		attrs = append(attrs, attribute.String("maas.reconcile_id", string(id)))
+	}
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, "reconcile "+t.name, trace.WithAttributes(attrs...))
+	defer span.End()
+	if span.SpanContext().IsSampled() {
+		ctx = logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithValues("traceID", span.SpanContext().TraceID().String()))
+	}
+
+	result, err := t.next.Reconcile(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if result.RequeueAfter > 0 {
+		span.SetAttributes(attribute.String("maas.requeue_after", result.RequeueAfter.String()))
+	}
+	return result, err
+}
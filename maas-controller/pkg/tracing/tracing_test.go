@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconciler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	var logged string
+	ctx := logr.NewContext(context.Background(), funcr.New(func(_, args string) { logged = args }, funcr.Options{}))
+	r := Reconciler("maasmodelref", reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+		logr.FromContextOrDiscard(ctx).Info("reconciling")
+		return reconcile.Result{}, errors.New("backend unavailable")
+	}))
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "llm", Name: "granite"}}
+	if _, err := r.Reconcile(ctx, req); err == nil {
+		t.Fatal("Reconcile error was swallowed")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "reconcile maasmodelref" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want Error", span.Status())
+	}
+	want := attribute.String("k8s.object.name", "granite")
+	found := false
+	for _, attr := range span.Attributes() {
+		found = found || attr == want
+	}
+	if !found {
+		t.Errorf("span attributes %v lack %v", span.Attributes(), want)
+	}
+	if traceID := span.SpanContext().TraceID().String(); logged == "" || !strings.Contains(logged, traceID) {
+		t.Errorf("reconcile log %q lacks trace ID %s", logged, traceID)
+	}
+}
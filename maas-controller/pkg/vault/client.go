@@ -0,0 +1,284 @@
+// Package vault reads upstream provider credentials from HashiCorp Vault using the Kubernetes
+// auth method, caching login tokens and leased secrets and renewing them before they expire.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAuthMount is the mount path of the Kubernetes auth method.
+	DefaultAuthMount = "kubernetes"
+	// DefaultTokenPath is the projected service account token presented to Vault at login.
+	DefaultTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec // file path, not a credential
+
+	// DefaultRefresh is how often secrets without a lease (KV) are read again.
+	DefaultRefresh = 5 * time.Minute
+	// minRefresh bounds how often a short-lived lease is renewed.
+	minRefresh = 10 * time.Second
+)
+
+// ErrPermissionDenied is returned when Vault rejects the request with 403.
+var ErrPermissionDenied = errors.New("vault: permission denied")
+
+// Secret is the data read from a Vault path.
+type Secret struct {
+	// Data holds the secret's fields; for KV version 2 the inner "data" object is unwrapped.
+	Data map[string]string
+	// LeaseID is set for dynamic secrets.
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+	// FetchedAt is when the secret was read or its lease last renewed.
+	FetchedAt time.Time
+}
+
+// refreshAt is when the secret should be renewed or read again: two thirds into its lease, or
+// DefaultRefresh for secrets without one.
+func (s *Secret) refreshAt() time.Time {
+	if s.LeaseDuration <= 0 {
+		return s.FetchedAt.Add(DefaultRefresh)
+	}
+	return s.FetchedAt.Add(max(s.LeaseDuration*2/3, minRefresh))
+}
+
+// RefreshAfter returns how long the caller can use the secret before calling Read again.
+func (s *Secret) RefreshAfter(now time.Time) time.Duration {
+	return max(s.refreshAt().Sub(now), minRefresh)
+}
+
+type token struct {
+	value     string
+	fetched   time.Time
+	ttl       time.Duration
+	renewable bool
+}
+
+func (t *token) needsRenewal(now time.Time) bool {
+	return t.ttl > 0 && now.After(t.fetched.Add(t.ttl*2/3))
+}
+
+// Client is a Vault client authenticated with the Kubernetes auth method. It is safe for
+// concurrent use.
+type Client struct {
+	address    string
+	authMount  string
+	tokenPath  string
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu      sync.Mutex
+	tokens  map[string]*token  // by role
+	secrets map[string]*Secret // by role and path
+}
+
+// NewClient creates a Client for the Vault server at address. Empty authMount and tokenPath
+// default to DefaultAuthMount and DefaultTokenPath.
+func NewClient(address, authMount, tokenPath string) *Client {
+	if authMount == "" {
+		authMount = DefaultAuthMount
+	}
+	if tokenPath == "" {
+		tokenPath = DefaultTokenPath
+	}
+	return &Client{
+		address:    strings.TrimRight(address, "/"),
+		authMount:  strings.Trim(authMount, "/"),
+		tokenPath:  tokenPath,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		now:        time.Now,
+		tokens:     make(map[string]*token),
+		secrets:    make(map[string]*Secret),
+	}
+}
+
+// Read returns the secret at path, logging in with role. A cached secret is returned until it
+// is due for refresh; a renewable lease is then renewed, anything else is read again.
+func (c *Client) Read(ctx context.Context, role, path string) (*Secret, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path = strings.Trim(path, "/")
+	cacheKey := role + "\x00" + path
+	now := c.now()
+	if cached, ok := c.secrets[cacheKey]; ok {
+		if now.Before(cached.refreshAt()) {
+			return cached, nil
+		}
+		if cached.Renewable && cached.LeaseID != "" {
+			if renewed, err := c.renewLease(ctx, role, cached); err == nil {
+				c.secrets[cacheKey] = renewed
+				return renewed, nil
+			}
+		}
+		delete(c.secrets, cacheKey)
+	}
+
+	secret, err := c.read(ctx, role, path)
+	if errors.Is(err, ErrPermissionDenied) {
+		// The token may have been revoked; log in again once.
+		delete(c.tokens, role)
+		secret, err = c.read(ctx, role, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.secrets[cacheKey] = secret
+	return secret, nil
+}
+
+func (c *Client) read(ctx context.Context, role, path string) (*Secret, error) {
+	tok, err := c.token(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	var resp response
+	if err := c.do(ctx, http.MethodGet, "/v1/"+path, tok, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return resp.secret(c.now()), nil
+}
+
+func (c *Client) renewLease(ctx context.Context, role string, cached *Secret) (*Secret, error) {
+	tok, err := c.token(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	var resp response
+	body := map[string]any{"lease_id": cached.LeaseID}
+	if err := c.do(ctx, http.MethodPut, "/v1/sys/leases/renew", tok, body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to renew lease: %w", err)
+	}
+	renewed := *cached
+	renewed.LeaseDuration = time.Duration(resp.LeaseDuration) * time.Second
+	renewed.Renewable = resp.Renewable
+	renewed.FetchedAt = c.now()
+	return &renewed, nil
+}
+
+// token returns a valid token for role, renewing or replacing the cached one when it nears expiry.
+// Callers hold c.mu.
+func (c *Client) token(ctx context.Context, role string) (string, error) {
+	now := c.now()
+	if cached, ok := c.tokens[role]; ok {
+		if !cached.needsRenewal(now) {
+			return cached.value, nil
+		}
+		if cached.renewable {
+			var resp response
+			if err := c.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", cached.value, map[string]any{}, &resp); err == nil && resp.Auth != nil {
+				renewed := resp.Auth.token(cached.value, now)
+				c.tokens[role] = renewed
+				return renewed.value, nil
+			}
+		}
+		delete(c.tokens, role)
+	}
+
+	jwt, err := os.ReadFile(c.tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account token: %w", err)
+	}
+	var resp response
+	body := map[string]any{"role": role, "jwt": strings.TrimSpace(string(jwt))}
+	if err := c.do(ctx, http.MethodPost, "/v1/auth/"+c.authMount+"/login", "", body, &resp); err != nil {
+		return "", fmt.Errorf("failed to log in with role %q: %w", role, err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("login with role %q returned no token", role)
+	}
+	tok := resp.Auth.token("", now)
+	c.tokens[role] = tok
+	return tok.value, nil
+}
+
+type auth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+func (a *auth) token(previous string, now time.Time) *token {
+	value := a.ClientToken
+	if value == "" {
+		value = previous
+	}
+	return &token{value: value, fetched: now, ttl: time.Duration(a.LeaseDuration) * time.Second, renewable: a.Renewable}
+}
+
+type response struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int64          `json:"lease_duration"`
+	Renewable     bool           `json:"renewable"`
+	Data          map[string]any `json:"data"`
+	Auth          *auth          `json:"auth"`
+}
+
+func (r *response) secret(now time.Time) *Secret {
+	data := r.Data
+	// KV version 2 nests the fields under data.data next to data.metadata.
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, hasMetadata := data["metadata"]; hasMetadata {
+			data = inner
+		}
+	}
+	fields := make(map[string]string, len(data))
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			fields[k] = s
+		} else if v != nil {
+			fields[k] = fmt.Sprint(v)
+		}
+	}
+	return &Secret{
+		Data:          fields,
+		LeaseID:       r.LeaseID,
+		LeaseDuration: time.Duration(r.LeaseDuration) * time.Second,
+		Renewable:     r.Renewable,
+		FetchedAt:     now,
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path, tok string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.address+path, reader)
+	if err != nil {
+		return err
+	}
+	if tok != "" {
+		req.Header.Set("X-Vault-Token", tok)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusForbidden {
+		return ErrPermissionDenied
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,143 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves Kubernetes auth login, token renewal, a KV v2 secret and a dynamic secret.
+type fakeVault struct {
+	logins, renewals, reads, leaseRenewals atomic.Int32
+	revoked                                atomic.Bool
+}
+
+func (f *fakeVault) handler(t *testing.T) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		write := func(v any) { _ = json.NewEncoder(w).Encode(v) }
+		switch r.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["jwt"] != "sa-token" || body["role"] != "maas" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			f.logins.Add(1)
+			f.revoked.Store(false)
+			write(map[string]any{"auth": map[string]any{"client_token": "s.vault", "lease_duration": 60, "renewable": true}})
+			return
+		case "/v1/auth/token/renew-self":
+			f.renewals.Add(1)
+			write(map[string]any{"auth": map[string]any{"client_token": "s.vault", "lease_duration": 60, "renewable": true}})
+			return
+		}
+		if r.Header.Get("X-Vault-Token") != "s.vault" || f.revoked.Load() {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/openai":
+			f.reads.Add(1)
+			write(map[string]any{"data": map[string]any{
+				"data":     map[string]any{"api-key": "sk-from-vault"},
+				"metadata": map[string]any{"version": 3},
+			}})
+		case "/v1/openai/creds/maas":
+			f.reads.Add(1)
+			write(map[string]any{"lease_id": "openai/creds/maas/abc", "lease_duration": 30, "renewable": true,
+				"data": map[string]any{"api-key": "sk-dynamic"}})
+		case "/v1/sys/leases/renew":
+			f.leaseRenewals.Add(1)
+			write(map[string]any{"lease_id": "openai/creds/maas/abc", "lease_duration": 30, "renewable": true})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func newTestClient(t *testing.T) (*Client, *fakeVault, *time.Time) {
+	t.Helper()
+	f := &fakeVault{}
+	server := httptest.NewServer(f.handler(t))
+	t.Cleanup(server.Close)
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("sa-token\n"), 0o600))
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient(server.URL, "", tokenPath)
+	c.now = func() time.Time { return now }
+	return c, f, &now
+}
+
+func TestReadKVv2(t *testing.T) {
+	c, f, now := newTestClient(t)
+	ctx := context.Background()
+
+	secret, err := c.Read(ctx, "maas", "secret/data/openai")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-from-vault", secret.Data["api-key"])
+	assert.Equal(t, DefaultRefresh, secret.RefreshAfter(*now))
+
+	// Served from cache until the refresh interval elapses.
+	_, err = c.Read(ctx, "maas", "/secret/data/openai")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), f.reads.Load())
+	assert.Equal(t, int32(1), f.logins.Load())
+
+	*now = now.Add(DefaultRefresh + time.Second)
+	_, err = c.Read(ctx, "maas", "secret/data/openai")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), f.reads.Load())
+	assert.Equal(t, int32(1), f.renewals.Load(), "token past two thirds of its TTL is renewed, not replaced")
+	assert.Equal(t, int32(1), f.logins.Load())
+}
+
+func TestReadRenewsLease(t *testing.T) {
+	c, f, now := newTestClient(t)
+	ctx := context.Background()
+
+	secret, err := c.Read(ctx, "maas", "openai/creds/maas")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-dynamic", secret.Data["api-key"])
+	assert.Equal(t, 20*time.Second, secret.RefreshAfter(*now))
+
+	*now = now.Add(25 * time.Second)
+	renewed, err := c.Read(ctx, "maas", "openai/creds/maas")
+	require.NoError(t, err)
+	assert.Equal(t, "sk-dynamic", renewed.Data["api-key"])
+	assert.Equal(t, int32(1), f.reads.Load(), "a renewable lease is renewed instead of issuing new credentials")
+	assert.Equal(t, int32(1), f.leaseRenewals.Load())
+}
+
+func TestReadLogsInAgainWhenTokenRevoked(t *testing.T) {
+	c, f, _ := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := c.Read(ctx, "maas", "secret/data/openai")
+	require.NoError(t, err)
+
+	f.revoked.Store(true)
+	c.secrets = map[string]*Secret{}
+	_, err = c.Read(ctx, "maas", "secret/data/openai")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), f.logins.Load())
+}
+
+func TestReadLoginFailure(t *testing.T) {
+	c, _, _ := newTestClient(t)
+
+	_, err := c.Read(context.Background(), "other-role", "secret/data/openai")
+	require.ErrorContains(t, err, `failed to log in with role "other-role"`)
+}
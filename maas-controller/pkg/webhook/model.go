@@ -0,0 +1,370 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/controller/maas"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+const (
+	// ModelDefaultPath is where the MaaSModelRef defaulting webhook is served.
+	ModelDefaultPath = "/mutate-maas-model"
+	// ModelValidatePath is where the MaaSModelRef validating webhook is served.
+	ModelValidatePath = "/validate-maas-model"
+)
+
+// ModelDefaulter fills in the MaaSModelRef fields the controller would otherwise infer at reconcile
+// time, so the stored object shows what is in effect.
+type ModelDefaulter struct {
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &ModelDefaulter{}
+
+// Handle implements admission.Handler.
+func (d *ModelDefaulter) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := d.Decoder.Decode(req, model); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if model.Namespace == "" {
+		model.Namespace = req.Namespace
+	}
+	DefaultModel(model)
+	raw, err := json.Marshal(model)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, raw)
+}
+
+// DefaultModel sets the parent namespace to the model's own and, for kinds with a generated
+// HTTPRoute, records the route's path prefix in spec.routing.pathPrefix.
+func DefaultModel(model *maasv1alpha1.MaaSModelRef) {
+	if model.Spec.ParentRef != nil && model.Spec.ParentRef.Namespace == "" {
+		model.Spec.ParentRef.Namespace = model.Namespace
+	}
+	if !supportsRouting(model) {
+		return
+	}
+	if model.Annotations[externalmodel.AnnPathPrefix] != "" || (model.Spec.Routing != nil && model.Spec.Routing.PathPrefix != "") {
+		return
+	}
+	if model.Spec.Routing == nil {
+		model.Spec.Routing = &maasv1alpha1.ModelRouting{}
+	}
+	model.Spec.Routing.PathPrefix, _ = externalmodel.ModelRoute(model)
+}
+
+// supportsRouting reports whether the controller generates the HTTPRoute of a model, so
+// spec.routing applies to it: ExternalModel and MCPServer models, and LLMInferenceService models
+// splitting traffic between backends.
+func supportsRouting(model *maasv1alpha1.MaaSModelRef) bool {
+	switch model.Spec.ModelRef.Kind {
+	case "ExternalModel", maas.MCPServerKind:
+		return true
+	case "LLMInferenceService", "llmisvc":
+		return len(model.Spec.Backends) > 0
+	}
+	return false
+}
+
+// ModelValidator rejects MaaSModelRefs that the controller could only mark Failed: an unknown or
+// disabled kind, a missing backend resource, a malformed endpoint, a route or alias another model
+// has, or example requests that are not JSON. It also rejects deleting a model that is not soft-deleted.
+type ModelValidator struct {
+	Client  client.Reader
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &ModelValidator{}
+
+// Handle implements admission.Handler.
+func (v *ModelValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete {
+		return v.validateDelete(req)
+	}
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := v.Decoder.Decode(req, model); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if model.Namespace == "" {
+		model.Namespace = req.Namespace
+	}
+	// Never block the controller from removing its finalizer.
+	if model.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+
+	if err := validateModelSpec(model); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	// Only check what changed, so edits to a model whose backend was deleted (e.g. relabeling)
+	// still go through.
+	var old *maasv1alpha1.MaaSModelRef
+	if req.Operation == admissionv1.Update {
+		old = &maasv1alpha1.MaaSModelRef{}
+		if err := v.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	checks := []func(context.Context, *maasv1alpha1.MaaSModelRef) (string, error){}
+	if old == nil || old.Spec.ModelRef != model.Spec.ModelRef || !slices.Equal(old.Spec.Backends, model.Spec.Backends) {
+		checks = append(checks, v.validateBackend)
+	}
+	if old == nil || routeChanged(old, model) {
+		checks = append(checks, v.validateRoute)
+	}
+	if old == nil || !slices.Equal(old.Spec.Aliases, model.Spec.Aliases) {
+		checks = append(checks, v.validateAliases)
+	}
+	for _, check := range checks {
+		denied, err := check(ctx, model)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if denied != "" {
+			return admission.Denied(denied)
+		}
+	}
+	return admission.Allowed("")
+}
+
+// cascadeDeleters remove models along with their namespace or owner; soft delete cannot apply there.
+var cascadeDeleters = []string{
+	"system:serviceaccount:kube-system:namespace-controller",
+	"system:serviceaccount:kube-system:generic-garbage-collector",
+}
+
+// validateDelete only lets a model be deleted once it is soft-deleted, so a stray kubectl delete
+// cannot take a model and its traffic down at once.
+func (v *ModelValidator) validateDelete(req admission.Request) admission.Response {
+	if slices.Contains(cascadeDeleters, req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+	model := &maasv1alpha1.MaaSModelRef{}
+	if err := v.Decoder.DecodeRaw(req.OldObject, model); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if model.GetAnnotations()[maas.SoftDeletedAnnotation] == "true" {
+		return admission.Allowed("")
+	}
+	return admission.Denied(fmt.Sprintf("MaaSModelRef %s must be soft-deleted before it is deleted: annotate it with %s=true first",
+		model.Name, maas.SoftDeletedAnnotation))
+}
+
+// validateModelSpec checks the fields that need no lookups.
+func validateModelSpec(model *maasv1alpha1.MaaSModelRef) error {
+	kind := model.Spec.ModelRef.Kind
+	if maas.GetRouteResolver(kind) == nil {
+		return fmt.Errorf("spec.modelRef.kind %q is not a registered backend kind (registered: %s)",
+			kind, strings.Join(maas.BackendKinds(), ", "))
+	}
+	if !maas.BackendKindEnabled(kind) {
+		return fmt.Errorf("spec.modelRef.kind %q is not enabled on this controller", kind)
+	}
+	if override := model.Spec.EndpointOverride; override != "" {
+		u, err := url.Parse(override)
+		if err != nil {
+			return fmt.Errorf("spec.endpointOverride %q is not a valid URL: %w", override, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("spec.endpointOverride %q must be an absolute http or https URL", override)
+		}
+	}
+	if err := maas.ValidateMaintenanceWindows(model.Spec.MaintenanceWindows); err != nil {
+		return err
+	}
+	if err := maas.ValidateTimeouts(model.Spec); err != nil {
+		return err
+	}
+	if err := maas.ValidateGeneratedResources(model.Spec.GeneratedResources); err != nil {
+		return err
+	}
+	if prefix, ok := model.Annotations[externalmodel.AnnPathPrefix]; ok && !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("annotation %s %q must start with /", externalmodel.AnnPathPrefix, prefix)
+	}
+	_, hasHostname := model.Annotations[externalmodel.AnnHostname]
+	_, hasPrefix := model.Annotations[externalmodel.AnnPathPrefix]
+	if model.Spec.Routing != nil || hasHostname || hasPrefix {
+		if !supportsRouting(model) {
+			return fmt.Errorf("spec.routing is not supported for kind %s without spec.backends; its HTTPRoute is not generated by maas-controller", kind)
+		}
+		if prefix, hostname := externalmodel.ModelRoute(model); prefix == "/" && hostname == "" {
+			return fmt.Errorf("path prefix / needs a dedicated hostname; without one the model would take over the gateway")
+		}
+	}
+	for i, alias := range model.Spec.Aliases {
+		if alias == model.Name {
+			return fmt.Errorf("spec.aliases: %q is the model's own name", alias)
+		}
+		if slices.Contains(model.Spec.Aliases[:i], alias) {
+			return fmt.Errorf("spec.aliases lists %q more than once", alias)
+		}
+	}
+	if docs := model.Spec.Documentation; docs != nil {
+		names := map[string]bool{}
+		for _, example := range docs.Examples {
+			if names[example.Name] {
+				return fmt.Errorf("spec.documentation.examples has more than one example named %q", example.Name)
+			}
+			names[example.Name] = true
+			if example.Body != "" && !json.Valid([]byte(example.Body)) {
+				return fmt.Errorf("spec.documentation.examples[%s].body is not valid JSON", example.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateRoute denies a model whose hostname and path prefix, or the prefix of one of its
+// aliases, are already served by another model.
+func (v *ModelValidator) validateRoute(ctx context.Context, model *maasv1alpha1.MaaSModelRef) (string, error) {
+	if !supportsRouting(model) {
+		return "", nil
+	}
+	prefixes, hostname := routePrefixes(model)
+	var models maasv1alpha1.MaaSModelRefList
+	if err := v.Client.List(ctx, &models); err != nil {
+		return "", fmt.Errorf("failed to list MaaSModelRefs: %w", err)
+	}
+	for i := range models.Items {
+		other := &models.Items[i]
+		if other.Namespace == model.Namespace && other.Name == model.Name || !supportsRouting(other) {
+			continue
+		}
+		otherPrefixes, otherHostname := routePrefixes(other)
+		if otherHostname != hostname {
+			continue
+		}
+		for _, prefix := range prefixes {
+			if slices.Contains(otherPrefixes, prefix) {
+				return fmt.Sprintf("path prefix %s%s is already used by MaaSModelRef %s/%s", hostname, prefix, other.Namespace, other.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// routePrefixes returns the path prefixes a model's generated HTTPRoute serves, its own and then
+// one per alias, and its dedicated hostname.
+func routePrefixes(model *maasv1alpha1.MaaSModelRef) ([]string, string) {
+	prefix, hostname := externalmodel.ModelRoute(model)
+	prefixes := []string{prefix}
+	for _, alias := range model.Spec.Aliases {
+		prefixes = append(prefixes, "/"+alias)
+	}
+	return prefixes, hostname
+}
+
+// validateAliases denies a model with an alias that is the name or an alias of another model in
+// its namespace, since maas-api could not tell which of them a request names.
+func (v *ModelValidator) validateAliases(ctx context.Context, model *maasv1alpha1.MaaSModelRef) (string, error) {
+	if len(model.Spec.Aliases) == 0 {
+		return "", nil
+	}
+	var models maasv1alpha1.MaaSModelRefList
+	if err := v.Client.List(ctx, &models, client.InNamespace(model.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list MaaSModelRefs: %w", err)
+	}
+	for _, other := range models.Items {
+		if other.Name == model.Name {
+			continue
+		}
+		for _, alias := range model.Spec.Aliases {
+			if alias == other.Name || slices.Contains(other.Spec.Aliases, alias) {
+				return fmt.Sprintf("alias %q is already a name or alias of MaaSModelRef %s/%s", alias, other.Namespace, other.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// validateBackend returns a denial message when the resource spec.modelRef names does not exist or
+// cannot be served. Kinds registered by other packages are not checked.
+func (v *ModelValidator) validateBackend(ctx context.Context, model *maasv1alpha1.MaaSModelRef) (string, error) {
+	key := types.NamespacedName{Name: model.Spec.ModelRef.Name, Namespace: model.Namespace}
+	var backend client.Object
+	switch model.Spec.ModelRef.Kind {
+	case "LLMInferenceService", "llmisvc":
+		backend = &kservev1alpha1.LLMInferenceService{}
+	case "ExternalModel":
+		backend = &maasv1alpha1.ExternalModel{}
+	case maas.MCPServerKind:
+		backend = &corev1.Service{}
+	default:
+		return "", nil
+	}
+	if err := v.Client.Get(ctx, key, backend); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("%s %s not found", model.Spec.ModelRef.Kind, key), nil
+		}
+		return "", fmt.Errorf("failed to get %s %s: %w", model.Spec.ModelRef.Kind, key, err)
+	}
+	if ext, ok := backend.(*maasv1alpha1.ExternalModel); ok {
+		if err := validateEndpointHost(ext.Spec.Endpoint); err != nil {
+			return fmt.Sprintf("ExternalModel %s has an invalid spec.endpoint %q: %v", key, ext.Spec.Endpoint, err), nil
+		}
+	}
+	seen := map[string]bool{}
+	for _, b := range model.Spec.Backends {
+		if seen[b.Name] {
+			return fmt.Sprintf("spec.backends lists LLMInferenceService %s more than once", b.Name), nil
+		}
+		seen[b.Name] = true
+		key := types.NamespacedName{Name: b.Name, Namespace: model.Namespace}
+		if err := v.Client.Get(ctx, key, &kservev1alpha1.LLMInferenceService{}); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Sprintf("spec.backends: LLMInferenceService %s not found", key), nil
+			}
+			return "", fmt.Errorf("failed to get LLMInferenceService %s: %w", key, err)
+		}
+	}
+	return "", nil
+}
+
+func routeChanged(old, model *maasv1alpha1.MaaSModelRef) bool {
+	oldPrefix, oldHostname := externalmodel.ModelRoute(old)
+	prefix, hostname := externalmodel.ModelRoute(model)
+	return oldPrefix != prefix || oldHostname != hostname || old.Spec.ModelRef.Kind != model.Spec.ModelRef.Kind ||
+		supportsRouting(old) != supportsRouting(model) || !slices.Equal(old.Spec.Aliases, model.Spec.Aliases)
+}
+
+// validateEndpointHost checks that an ExternalModel endpoint is a bare host name, optionally with
+// a port, as the generated ServiceEntry and Host header expect.
+func validateEndpointHost(endpoint string) error {
+	if strings.Contains(endpoint, "://") {
+		return fmt.Errorf("must be a host name without a scheme")
+	}
+	u, err := url.Parse("https://" + endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Hostname() == "" || u.Host != endpoint {
+		return fmt.Errorf("must be a host name without a path")
+	}
+	return nil
+}
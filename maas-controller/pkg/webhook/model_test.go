@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	kservev1alpha1 "github.com/kserve/kserve/pkg/apis/serving/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/controller/maas"
+	"github.com/opendatahub-io/models-as-a-service/maas-controller/pkg/reconciler/externalmodel"
+)
+
+func newModelValidator(t *testing.T, objects ...client.Object) *ModelValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(kservev1alpha1.AddToScheme(scheme))
+	utilruntime.Must(maasv1alpha1.AddToScheme(scheme))
+	return &ModelValidator{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Decoder: admission.NewDecoder(scheme),
+	}
+}
+
+func backendModel(name, kind, backend string) *maasv1alpha1.MaaSModelRef {
+	m := modelRef(name, "team-a")
+	m.Spec.ModelRef = maasv1alpha1.ModelReference{Kind: kind, Name: backend}
+	return m
+}
+
+func externalModel(name, endpoint string) *maasv1alpha1.ExternalModel {
+	return &maasv1alpha1.ExternalModel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+		Spec:       maasv1alpha1.ExternalModelSpec{Provider: "openai", Endpoint: endpoint},
+	}
+}
+
+func routedModel(name, namespace, pathPrefix string) *maasv1alpha1.MaaSModelRef {
+	m := modelRef(name, namespace)
+	m.Spec.ModelRef = maasv1alpha1.ModelReference{Kind: "ExternalModel", Name: name}
+	m.Spec.Routing = &maasv1alpha1.ModelRouting{PathPrefix: pathPrefix}
+	return m
+}
+
+func aliasedModel(name string, aliases ...string) *maasv1alpha1.MaaSModelRef {
+	m := backendModel(name, "LLMInferenceService", "llama")
+	m.Spec.Aliases = aliases
+	return m
+}
+
+func TestModelValidator(t *testing.T) {
+	v := newModelValidator(t,
+		&kservev1alpha1.LLMInferenceService{ObjectMeta: metav1.ObjectMeta{Name: "llama", Namespace: "team-a"}},
+		&kservev1alpha1.LLMInferenceService{ObjectMeta: metav1.ObjectMeta{Name: "llama-v2", Namespace: "team-a"}},
+		externalModel("gpt", "api.openai.com"),
+		externalModel("bad-endpoint", "https://api.openai.com/v1"),
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "tools", Namespace: "team-a"}},
+		routedModel("existing", "team-b", "/openai/gpt"),
+		routedModel("mistral", "team-b", "/mistral"),
+		aliasedModel("llama3-70b", "llama3"),
+	)
+	withOverride := backendModel("m", "LLMInferenceService", "llama")
+	withOverride.Spec.EndpointOverride = "llama.example.com/v1"
+	withPrefix := backendModel("m", "ExternalModel", "gpt")
+	withPrefix.Annotations = map[string]string{externalmodel.AnnPathPrefix: "gpt"}
+	llmWithRouting := backendModel("m", "LLMInferenceService", "llama")
+	llmWithRouting.Spec.Routing = &maasv1alpha1.ModelRouting{PathPrefix: "/llama"}
+	canary := backendModel("m", "LLMInferenceService", "llama")
+	canary.Spec.Backends = []maasv1alpha1.WeightedBackend{{Name: "llama", Weight: 90}, {Name: "llama-v2", Weight: 10}}
+	canary.Spec.Routing = &maasv1alpha1.ModelRouting{PathPrefix: "/llm/llama"}
+	missingCanary := canary.DeepCopy()
+	missingCanary.Spec.Backends[1].Name = "llama-v3"
+	rootPrefix := backendModel("m", "ExternalModel", "gpt")
+	rootPrefix.Spec.Routing = &maasv1alpha1.ModelRouting{PathPrefix: "/"}
+	dedicatedHost := backendModel("m", "ExternalModel", "gpt")
+	dedicatedHost.Spec.Routing = &maasv1alpha1.ModelRouting{PathPrefix: "/", Hostname: "gpt.example.com"}
+	conflicting := backendModel("m", "ExternalModel", "gpt")
+	conflicting.Spec.Routing = &maasv1alpha1.ModelRouting{PathPrefix: "/openai/gpt"}
+	withExamples := backendModel("m", "ExternalModel", "gpt")
+	withExamples.Spec.Documentation = &maasv1alpha1.ModelDocumentation{Examples: []maasv1alpha1.ModelExample{
+		{Name: "chat", Path: "/v1/chat/completions", Body: `{"model": "gpt", "messages": []}`},
+		{Name: "models", Path: "/v1/models"},
+	}}
+	invalidBody := backendModel("m", "ExternalModel", "gpt")
+	invalidBody.Spec.Documentation = &maasv1alpha1.ModelDocumentation{Examples: []maasv1alpha1.ModelExample{
+		{Name: "chat", Path: "/v1/chat/completions", Body: `{"model": gpt}`},
+	}}
+	duplicateExample := backendModel("m", "ExternalModel", "gpt")
+	duplicateExample.Spec.Documentation = &maasv1alpha1.ModelDocumentation{Examples: []maasv1alpha1.ModelExample{
+		{Name: "chat", Path: "/v1/chat/completions"}, {Name: "chat", Path: "/v1/completions"},
+	}}
+	maintenance := backendModel("m", "ExternalModel", "gpt")
+	maintenance.Spec.MaintenanceWindows = []maasv1alpha1.MaintenanceWindow{{Schedule: "0 2 * * 6", Duration: metav1.Duration{Duration: 2 * time.Hour}}}
+	badSchedule := maintenance.DeepCopy()
+	badSchedule.Spec.MaintenanceWindows[0].Schedule = "0 2 * *"
+	longWindow := maintenance.DeepCopy()
+	longWindow.Spec.MaintenanceWindows[0].Duration = metav1.Duration{Duration: 8 * 24 * time.Hour}
+
+	badNameTemplate := backendModel("m", "ExternalModel", "gpt")
+	badNameTemplate.Spec.GeneratedResources = &maasv1alpha1.GeneratedResources{NameTemplates: map[string]string{"AuthPolicy": "{{.Team}}-auth"}}
+
+	withAlias := func(aliases ...string) *maasv1alpha1.MaaSModelRef {
+		m := backendModel("m", "ExternalModel", "gpt")
+		m.Spec.Aliases = aliases
+		return m
+	}
+
+	tests := []struct {
+		name    string
+		model   *maasv1alpha1.MaaSModelRef
+		allowed bool
+	}{
+		{name: "llmisvc exists", model: backendModel("m", "LLMInferenceService", "llama"), allowed: true},
+		{name: "llmisvc missing", model: backendModel("m", "LLMInferenceService", "mistral"), allowed: false},
+		{name: "external model", model: backendModel("m", "ExternalModel", "gpt"), allowed: true},
+		{name: "external model missing", model: backendModel("m", "ExternalModel", "claude"), allowed: false},
+		{name: "external endpoint with scheme", model: backendModel("m", "ExternalModel", "bad-endpoint"), allowed: false},
+		{name: "mcp server", model: backendModel("m", maas.MCPServerKind, "tools"), allowed: true},
+		{name: "unknown kind", model: backendModel("m", "InferenceService", "llama"), allowed: false},
+		{name: "relative endpoint override", model: withOverride, allowed: false},
+		{name: "path prefix without slash", model: withPrefix, allowed: false},
+		{name: "routing on llmisvc", model: llmWithRouting, allowed: false},
+		{name: "routing on llmisvc with backends", model: canary, allowed: true},
+		{name: "backend llmisvc missing", model: missingCanary, allowed: false},
+		{name: "root prefix without hostname", model: rootPrefix, allowed: false},
+		{name: "root prefix on hostname", model: dedicatedHost, allowed: true},
+		{name: "prefix used by another model", model: conflicting, allowed: false},
+		{name: "examples", model: withExamples, allowed: true},
+		{name: "example body is not JSON", model: invalidBody, allowed: false},
+		{name: "duplicate example name", model: duplicateExample, allowed: false},
+		{name: "maintenance window", model: maintenance, allowed: true},
+		{name: "maintenance schedule with four fields", model: badSchedule, allowed: false},
+		{name: "maintenance window longer than a week", model: longWindow, allowed: false},
+		{name: "aliases", model: withAlias("gpt-latest", "chat"), allowed: true},
+		{name: "alias is the model's name", model: withAlias("m"), allowed: false},
+		{name: "alias listed twice", model: withAlias("chat", "chat"), allowed: false},
+		{name: "alias of another model", model: withAlias("llama3"), allowed: false},
+		{name: "alias is another model's name", model: withAlias("llama3-70b"), allowed: false},
+		{name: "alias prefix used by another model", model: withAlias("mistral"), allowed: false},
+		{name: "name template with unknown field", model: badNameTemplate, allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), admissionRequest(t, admissionv1.Create, "MaaSModelRef", tt.model, nil))
+			if resp.Allowed != tt.allowed {
+				t.Errorf("Allowed = %v, want %v (%s)", resp.Allowed, tt.allowed, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestModelValidator_UpdateKeepsMissingBackend(t *testing.T) {
+	v := newModelValidator(t)
+	old := backendModel("m", "LLMInferenceService", "deleted")
+	updated := old.DeepCopy()
+	updated.Labels = map[string]string{"team": "a"}
+
+	resp := v.Handle(context.Background(), admissionRequest(t, admissionv1.Update, "MaaSModelRef", updated, old))
+	if !resp.Allowed {
+		t.Errorf("update without a reference change denied: %s", resp.Result.Message)
+	}
+
+	updated.Spec.ModelRef.Name = "also-missing"
+	resp = v.Handle(context.Background(), admissionRequest(t, admissionv1.Update, "MaaSModelRef", updated, old))
+	if resp.Allowed {
+		t.Error("update to a missing backend allowed")
+	}
+}
+
+func TestModelValidator_DisabledKind(t *testing.T) {
+	if err := maas.SetEnabledBackendKinds([]string{"LLMInferenceService"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = maas.SetEnabledBackendKinds(nil) })
+	v := newModelValidator(t, externalModel("gpt", "api.openai.com"))
+
+	resp := v.Handle(context.Background(), admissionRequest(t, admissionv1.Create, "MaaSModelRef", backendModel("m", "ExternalModel", "gpt"), nil))
+	if resp.Allowed {
+		t.Error("model of a disabled kind allowed")
+	}
+}
+
+func TestModelValidator_Delete(t *testing.T) {
+	v := newModelValidator(t)
+	model := backendModel("m", "LLMInferenceService", "llama")
+
+	resp := v.Handle(context.Background(), admissionRequest(t, admissionv1.Delete, "MaaSModelRef", nil, model))
+	if resp.Allowed {
+		t.Error("deleting a model that is not soft-deleted allowed")
+	}
+
+	req := admissionRequest(t, admissionv1.Delete, "MaaSModelRef", nil, model)
+	req.UserInfo.Username = "system:serviceaccount:kube-system:namespace-controller"
+	if resp := v.Handle(context.Background(), req); !resp.Allowed {
+		t.Errorf("namespace deletion denied: %s", resp.Result.Message)
+	}
+
+	model.Annotations = map[string]string{maas.SoftDeletedAnnotation: "true"}
+	resp = v.Handle(context.Background(), admissionRequest(t, admissionv1.Delete, "MaaSModelRef", nil, model))
+	if !resp.Allowed {
+		t.Errorf("deleting a soft-deleted model denied: %s", resp.Result.Message)
+	}
+}
+
+func TestModelDefaulter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(maasv1alpha1.AddToScheme(scheme))
+	d := &ModelDefaulter{Decoder: admission.NewDecoder(scheme)}
+
+	model := backendModel("gpt", "ExternalModel", "gpt")
+	model.Spec.ParentRef = &maasv1alpha1.ParentModelReference{Name: "base"}
+	resp := d.Handle(context.Background(), admissionRequest(t, admissionv1.Create, "MaaSModelRef", model, nil))
+	if !resp.Allowed {
+		t.Fatalf("denied: %s", resp.Result.Message)
+	}
+
+	raw, err := json.Marshal(resp.Patches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(raw)
+	for _, want := range []string{`"/spec/parentRef/namespace"`, `"team-a"`, `"/gpt"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("patches %s do not contain %s", got, want)
+		}
+	}
+}
+
+func TestDefaultModel_KeepsExplicitValues(t *testing.T) {
+	model := backendModel("gpt", "ExternalModel", "gpt")
+	model.Annotations = map[string]string{externalmodel.AnnPathPrefix: "/openai"}
+	model.Spec.ParentRef = &maasv1alpha1.ParentModelReference{Name: "base", Namespace: "shared"}
+	DefaultModel(model)
+	if got := model.Annotations[externalmodel.AnnPathPrefix]; got != "/openai" {
+		t.Errorf("path prefix = %q, want /openai", got)
+	}
+	if got := model.Spec.ParentRef.Namespace; got != "shared" {
+		t.Errorf("parent namespace = %q, want shared", got)
+	}
+
+	llm := backendModel("llama", "LLMInferenceService", "llama")
+	DefaultModel(llm)
+	if llm.Spec.Routing != nil {
+		t.Error("path prefix defaulted on an LLMInferenceService model")
+	}
+
+	hosted := backendModel("tools", maas.MCPServerKind, "tools")
+	hosted.Annotations = map[string]string{externalmodel.AnnHostname: "tools.example.com"}
+	DefaultModel(hosted)
+	if hosted.Spec.Routing == nil || hosted.Spec.Routing.PathPrefix != "/" {
+		t.Errorf("routing = %+v, want path prefix / for a dedicated hostname", hosted.Spec.Routing)
+	}
+}
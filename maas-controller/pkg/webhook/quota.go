@@ -0,0 +1,228 @@
+// Package webhook holds the admission webhooks served by maas-controller.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+const (
+	// QuotaPath is where the quota webhook is served.
+	QuotaPath = "/validate-maas-quota"
+
+	// AnnotationMaxModels on a namespace caps how many MaaSModelRefs it may hold.
+	AnnotationMaxModels = "maas.opendatahub.io/max-models"
+	// AnnotationMaxTokensPerMinute on a namespace caps the token throughput that subscriptions may
+	// commit to its models, summed over every subscription and normalized to one minute.
+	AnnotationMaxTokensPerMinute = "maas.opendatahub.io/max-tokens-per-minute"
+)
+
+// QuotaValidator rejects MaaSModelRefs and MaaSSubscriptions that would take a namespace over the
+// quota declared in its annotations. Namespaces without the annotations are unlimited.
+type QuotaValidator struct {
+	Client  client.Reader
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &QuotaValidator{}
+
+// Handle implements admission.Handler.
+func (v *QuotaValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	switch req.Kind.Kind {
+	case "MaaSModelRef":
+		if req.Operation != admissionv1.Create {
+			return admission.Allowed("")
+		}
+		model := &maasv1alpha1.MaaSModelRef{}
+		if err := v.Decoder.Decode(req, model); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		return v.validateModel(ctx, model)
+	case "MaaSSubscription":
+		sub := &maasv1alpha1.MaaSSubscription{}
+		if err := v.Decoder.Decode(req, sub); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		var old *maasv1alpha1.MaaSSubscription
+		if req.Operation == admissionv1.Update {
+			old = &maasv1alpha1.MaaSSubscription{}
+			if err := v.Decoder.DecodeRaw(req.OldObject, old); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+		}
+		return v.validateSubscription(ctx, sub, old)
+	}
+	return admission.Allowed("")
+}
+
+// validateModel denies a new MaaSModelRef when its namespace already holds max-models.
+func (v *QuotaValidator) validateModel(ctx context.Context, model *maasv1alpha1.MaaSModelRef) admission.Response {
+	quota, err := v.namespaceQuota(ctx, model.Namespace)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if quota.maxModels < 0 {
+		return admission.Allowed("")
+	}
+
+	var existing maasv1alpha1.MaaSModelRefList
+	if err := v.Client.List(ctx, &existing, client.InNamespace(model.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to list MaaSModelRefs: %w", err))
+	}
+	if int64(len(existing.Items)) >= quota.maxModels {
+		return admission.Denied(fmt.Sprintf("namespace %s already has %d MaaSModelRefs; its quota (%s) is %d",
+			model.Namespace, len(existing.Items), AnnotationMaxModels, quota.maxModels))
+	}
+	return admission.Allowed("")
+}
+
+// validateSubscription denies a subscription that would raise the committed throughput of a model
+// namespace above max-tokens-per-minute. An update that does not raise it is always allowed, so
+// lowering a quota never blocks unrelated edits.
+func (v *QuotaValidator) validateSubscription(ctx context.Context, sub, old *maasv1alpha1.MaaSSubscription) admission.Response {
+	requested := committedByNamespace(sub)
+	if len(requested) == 0 {
+		return admission.Allowed("")
+	}
+	previous := map[string]int64{}
+	if old != nil {
+		previous = committedByNamespace(old)
+	}
+
+	var others maasv1alpha1.MaaSSubscriptionList
+	if err := v.Client.List(ctx, &others); err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to list MaaSSubscriptions: %w", err))
+	}
+
+	for namespace, tokens := range requested {
+		if tokens <= previous[namespace] {
+			continue
+		}
+		quota, err := v.namespaceQuota(ctx, namespace)
+		if err != nil {
+			return admission.Errored(http.StatusInternalServerError, err)
+		}
+		if quota.maxTokensPerMinute < 0 {
+			continue
+		}
+		total := tokens
+		for i := range others.Items {
+			other := &others.Items[i]
+			if other.Namespace == sub.Namespace && other.Name == sub.Name {
+				continue
+			}
+			total += committedByNamespace(other)[namespace]
+		}
+		if total > quota.maxTokensPerMinute {
+			return admission.Denied(fmt.Sprintf("subscriptions would commit %d tokens per minute to models in namespace %s; its quota (%s) is %d",
+				total, namespace, AnnotationMaxTokensPerMinute, quota.maxTokensPerMinute))
+		}
+	}
+	return admission.Allowed("")
+}
+
+// quota is a namespace's limits; -1 means unlimited.
+type quota struct {
+	maxModels          int64
+	maxTokensPerMinute int64
+}
+
+func (v *QuotaValidator) namespaceQuota(ctx context.Context, namespace string) (quota, error) {
+	q := quota{maxModels: -1, maxTokensPerMinute: -1}
+	ns := &corev1.Namespace{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return q, nil
+		}
+		return q, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	var err error
+	if q.maxModels, err = quotaValue(ns, AnnotationMaxModels); err != nil {
+		return q, err
+	}
+	if q.maxTokensPerMinute, err = quotaValue(ns, AnnotationMaxTokensPerMinute); err != nil {
+		return q, err
+	}
+	return q, nil
+}
+
+func quotaValue(ns *corev1.Namespace, annotation string) (int64, error) {
+	raw, ok := ns.Annotations[annotation]
+	if !ok {
+		return -1, nil
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || n < 0 {
+		return -1, fmt.Errorf("namespace %s has invalid %s %q: must be a non-negative integer", ns.Name, annotation, raw)
+	}
+	return n, nil
+}
+
+// defaultTokenRateLimits mirrors the limit the subscription controller applies to a model entry
+// without tokenRateLimits.
+var defaultTokenRateLimits = []maasv1alpha1.TokenRateLimit{{Limit: 100, Window: "1m"}}
+
+// committedByNamespace sums, per model namespace, the throughput a subscription commits: the most
+// restrictive of each model's token limits, normalized to one minute.
+func committedByNamespace(sub *maasv1alpha1.MaaSSubscription) map[string]int64 {
+	out := map[string]int64{}
+	for _, ref := range sub.Spec.ModelRefs {
+		limits := ref.TokenRateLimits
+		if len(limits) == 0 {
+			limits = defaultTokenRateLimits
+		}
+		out[ref.Namespace] += sustainedTokensPerMinute(limits)
+	}
+	return out
+}
+
+var windowPattern = regexp.MustCompile(`^(\d+)(s|m|h|d)$`)
+
+// sustainedTokensPerMinute returns the most restrictive of the limits, normalized to one minute.
+// A model with 1000/1m and 6000/1h can only sustain 100 tokens per minute.
+func sustainedTokensPerMinute(limits []maasv1alpha1.TokenRateLimit) int64 {
+	var sustained int64 = -1
+	for _, l := range limits {
+		seconds := int64(parseWindow(l.Window) / time.Second)
+		if seconds <= 0 {
+			continue
+		}
+		perMinute := l.Limit * 60 / seconds
+		if sustained < 0 || perMinute < sustained {
+			sustained = perMinute
+		}
+	}
+	if sustained < 0 {
+		return 0
+	}
+	return sustained
+}
+
+func parseWindow(w string) time.Duration {
+	m := windowPattern.FindStringSubmatch(w)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0
+	}
+	unit := map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}[m[2]]
+	return time.Duration(n) * unit
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	maasv1alpha1 "github.com/opendatahub-io/models-as-a-service/maas-controller/api/maas/v1alpha1"
+)
+
+func newValidator(t *testing.T, objects ...client.Object) *QuotaValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(maasv1alpha1.AddToScheme(scheme))
+	return &QuotaValidator{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Decoder: admission.NewDecoder(scheme),
+	}
+}
+
+func quotaNamespace(name string, annotations map[string]string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+}
+
+func admissionRequest(t *testing.T, op admissionv1.Operation, kind string, obj, old runtime.Object) admission.Request {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: op,
+		Kind:      metav1.GroupVersionKind{Group: "maas.opendatahub.io", Version: "v1alpha1", Kind: kind},
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+	if old != nil {
+		if req.OldObject.Raw, err = json.Marshal(old); err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+	}
+	return req
+}
+
+func modelRef(name, namespace string) *maasv1alpha1.MaaSModelRef {
+	return &maasv1alpha1.MaaSModelRef{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+}
+
+func quotaSubscription(name string, limit int64, window string, models ...string) *maasv1alpha1.MaaSSubscription {
+	sub := &maasv1alpha1.MaaSSubscription{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "models-as-a-service"}}
+	for _, m := range models {
+		sub.Spec.ModelRefs = append(sub.Spec.ModelRefs, maasv1alpha1.ModelSubscriptionRef{
+			Name: m, Namespace: "team-a",
+			TokenRateLimits: []maasv1alpha1.TokenRateLimit{{Limit: limit, Window: window}},
+		})
+	}
+	return sub
+}
+
+func TestQuotaValidator_MaxModels(t *testing.T) {
+	v := newValidator(t,
+		quotaNamespace("team-a", map[string]string{AnnotationMaxModels: "2"}),
+		quotaNamespace("team-b", nil),
+		modelRef("one", "team-a"), modelRef("two", "team-a"), modelRef("one", "team-b"), modelRef("two", "team-b"),
+	)
+
+	tests := []struct {
+		name    string
+		op      admissionv1.Operation
+		model   *maasv1alpha1.MaaSModelRef
+		allowed bool
+	}{
+		{name: "at quota", op: admissionv1.Create, model: modelRef("three", "team-a"), allowed: false},
+		{name: "no quota", op: admissionv1.Create, model: modelRef("three", "team-b"), allowed: true},
+		{name: "update at quota", op: admissionv1.Update, model: modelRef("two", "team-a"), allowed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), admissionRequest(t, tt.op, "MaaSModelRef", tt.model, nil))
+			if resp.Allowed != tt.allowed {
+				t.Errorf("Allowed = %v, want %v (%s)", resp.Allowed, tt.allowed, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestQuotaValidator_MaxTokensPerMinute(t *testing.T) {
+	existing := quotaSubscription("basic", 600, "1h", "one") // 10 tokens/min
+	v := newValidator(t,
+		quotaNamespace("team-a", map[string]string{AnnotationMaxTokensPerMinute: "1000"}),
+		existing,
+	)
+
+	tests := []struct {
+		name    string
+		op      admissionv1.Operation
+		sub     *maasv1alpha1.MaaSSubscription
+		old     *maasv1alpha1.MaaSSubscription
+		allowed bool
+	}{
+		{name: "within quota", op: admissionv1.Create, sub: quotaSubscription("premium", 990, "1m", "one"), allowed: true},
+		{name: "over quota", op: admissionv1.Create, sub: quotaSubscription("premium", 500, "1m", "one", "two"), allowed: false},
+		{name: "default limit counts", op: admissionv1.Create, sub: quotaSubscription("premium", 900, "1m", "one", "two"), allowed: false},
+		{name: "update that raises", op: admissionv1.Update, sub: quotaSubscription("basic", 2000, "1m", "one"), old: existing, allowed: false},
+		{name: "update that keeps", op: admissionv1.Update, sub: quotaSubscription("basic", 10, "1m", "one"), old: existing, allowed: true},
+	}
+	tests[2].sub.Spec.ModelRefs[1].TokenRateLimits = nil // 100/1m applies
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var old runtime.Object
+			if tt.old != nil {
+				old = tt.old
+			}
+			resp := v.Handle(context.Background(), admissionRequest(t, tt.op, "MaaSSubscription", tt.sub, old))
+			if resp.Allowed != tt.allowed {
+				t.Errorf("Allowed = %v, want %v (%s)", resp.Allowed, tt.allowed, resp.Result.Message)
+			}
+		})
+	}
+}